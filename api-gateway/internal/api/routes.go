@@ -10,42 +10,212 @@ import (
 
 type ProxyHandlerFunc func(string) gin.HandlerFunc
 
-func setupRoutes(router *gin.Engine, config *utils.Config, logger *logger.Logger, proxyHandler ProxyHandlerFunc) {
+// setupRoutes регистрирует маршруты шлюза. streamHandler используется для потоковых ответов,
+// которые нельзя буферизовать
+func setupRoutes(router *gin.Engine, config *utils.Config, logger *logger.Logger, proxyHandler, streamHandler ProxyHandlerFunc) {
 	public := router.Group("/api")
 	{
 		public.Any("/register", proxyHandler(config.AuthService.URL))
 		public.Any("/login", proxyHandler(config.AuthService.URL))
 		public.Any("/verify-otp", proxyHandler(config.AuthService.URL))
 		public.Any("/health", proxyHandler(config.AuthService.URL))
+		public.Any("/settings/public", proxyHandler(config.AuthService.URL))
+		public.Any("/scorm/runtime.js", proxyHandler(config.CourseService.URL))
+		public.Any("/badges/issuer", proxyHandler(config.CourseService.URL))
+		public.Any("/badges/jwks", proxyHandler(config.CourseService.URL))
+		public.Any("/badges/images/:id", proxyHandler(config.CourseService.URL))
+		public.Any("/badges/assertions/:id", proxyHandler(config.CourseService.URL))
+		public.Any("/badges/assertions/:id/download", proxyHandler(config.CourseService.URL))
+		public.Any("/certificates/verify/:code", proxyHandler(config.CourseService.URL))
+		public.Any("/certificates/document/:code", proxyHandler(config.CourseService.URL))
+		public.Any("/organizations/:id/logo", proxyHandler(config.AuthService.URL))
+		public.Any("/invitations/:token", proxyHandler(config.AuthService.URL))
+		public.Any("/invitations/:token/register", proxyHandler(config.AuthService.URL))
+		public.Any("/telegram/webhook", proxyHandler(config.CourseService.URL))
+		public.Any("/digest/unsubscribe", proxyHandler(config.AuthService.URL))
+		// Аутентификация WebSocket выполняется в сервисе (токен может прийти в access_token)
+		public.Any("/ws", proxyHandler(config.AuthService.URL))
 	}
+	// Аватары и другие файлы локального медиахранилища
+	router.Any("/media/*path", proxyHandler(config.AuthService.URL))
 
 	api := router.Group("/api")
 	api.Use(middleware.RateLimiterMiddleware())
 	{
 		api.Any("/courses", proxyHandler(config.CourseService.URL))
+		api.Any("/search", proxyHandler(config.CourseService.URL))
+		api.Any("/graphql", proxyHandler(config.CourseService.URL))
 		api.Any("/courses/:id", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/survey", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/survey/*path", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/changes", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/changes/seen", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/tasks", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/tasks/:task_id", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/bookmark", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/tasks/:task_id/bookmark", proxyHandler(config.CourseService.URL))
+		api.Any("/bookmarks", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/tasks/:task_id/note", proxyHandler(config.CourseService.URL))
+		api.Any("/notes", proxyHandler(config.CourseService.URL))
+		api.Any("/surveys/pending", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/threads", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/reviews", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/bans", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/bans/:user_id", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/progress/:user_id/*path", proxyHandler(config.CourseService.URL))
+		api.Any("/threads/:id", proxyHandler(config.CourseService.URL))
+		api.Any("/threads/:id/*path", proxyHandler(config.CourseService.URL))
+		api.Any("/comments/:id/*path", proxyHandler(config.CourseService.URL))
+		api.Any("/reviews/:id/*path", proxyHandler(config.CourseService.URL))
+		api.Any("/moderation/reports", proxyHandler(config.CourseService.URL))
+		api.Any("/moderation/reports/:id", proxyHandler(config.CourseService.URL))
 
 		api.Any("/progress/:user_id", proxyHandler(config.CourseService.URL))
+		api.Any("/progress/:user_id/history", proxyHandler(config.CourseService.URL))
 		api.Any("/progress/:user_id/tasks/:task_id/complete", proxyHandler(config.CourseService.URL))
+		api.Any("/progress/:user_id/tasks/complete", proxyHandler(config.CourseService.URL))
+		api.Any("/events/stream", streamHandler(config.CourseService.URL))
 
 		api.Any("/profile", proxyHandler(config.AuthService.URL))
+		api.Any("/flags", proxyHandler(config.AuthService.URL))
+		api.Any("/users/:username", proxyHandler(config.AuthService.URL))
+		api.Any("/announcements", proxyHandler(config.CourseService.URL))
+		api.Any("/announcements/:id", proxyHandler(config.CourseService.URL))
+		api.Any("/conversations", proxyHandler(config.CourseService.URL))
+		api.Any("/conversations/:id/*path", proxyHandler(config.CourseService.URL))
+		api.Any("/messages/:id/report", proxyHandler(config.CourseService.URL))
+		api.Any("/calendar", proxyHandler(config.CourseService.URL))
+		api.Any("/calendar/feed", proxyHandler(config.CourseService.URL))
+		api.Any("/calendar/feed/:token", proxyHandler(config.CourseService.URL))
+		api.Any("/tasks/:id/schedule", proxyHandler(config.CourseService.URL))
+		api.Any("/tasks/:id/scorm", proxyHandler(config.CourseService.URL))
+		api.Any("/courses/:id/scorm", proxyHandler(config.CourseService.URL))
+		api.Any("/questions/import", proxyHandler(config.CourseService.URL))
+		api.Any("/badges", proxyHandler(config.CourseService.URL))
+		api.Any("/badges/classes", proxyHandler(config.CourseService.URL))
+		api.Any("/badges/classes/:id", proxyHandler(config.CourseService.URL))
+		api.Any("/badges/classes/:id/image", proxyHandler(config.CourseService.URL))
+		api.Any("/badges/classes/:id/assertions", proxyHandler(config.CourseService.URL))
+		api.Any("/badges/assertions/:id/revoke", proxyHandler(config.CourseService.URL))
+		api.Any("/certificates", proxyHandler(config.CourseService.URL))
+		api.Any("/certificates/:code/revoke", proxyHandler(config.CourseService.URL))
+		api.Any("/telegram", proxyHandler(config.CourseService.URL))
+		api.Any("/telegram/link", proxyHandler(config.CourseService.URL))
+		api.Any("/push", proxyHandler(config.CourseService.URL))
+		api.Any("/push/subscriptions", proxyHandler(config.CourseService.URL))
+		api.Any("/push/subscriptions/:id", proxyHandler(config.CourseService.URL))
+		api.Any("/push/test", proxyHandler(config.CourseService.URL))
+		api.Any("/live-sessions", proxyHandler(config.CourseService.URL))
+		api.Any("/live-sessions/:id", proxyHandler(config.CourseService.URL))
+		api.Any("/tickets", proxyHandler(config.CourseService.URL))
+		api.Any("/tickets/:id", proxyHandler(config.CourseService.URL))
+		api.Any("/tickets/:id/*path", proxyHandler(config.CourseService.URL))
+		api.Any("/groups/:group/leaderboard", proxyHandler(config.CourseService.URL))
+		api.Any("/challenges", proxyHandler(config.CourseService.URL))
+		api.Any("/challenges/:id", proxyHandler(config.CourseService.URL))
+		api.Any("/recertifications", proxyHandler(config.CourseService.URL))
+		api.Any("/manager/reports", proxyHandler(config.CourseService.URL))
+		api.Any("/manager/reports/:user_id", proxyHandler(config.CourseService.URL))
+		api.Any("/org", proxyHandler(config.AuthService.URL))
+		api.Any("/org/members", proxyHandler(config.AuthService.URL))
+		api.Any("/org/members/:user_id/status", proxyHandler(config.AuthService.URL))
+		api.Any("/org/admins/:user_id", proxyHandler(config.AuthService.URL))
+		api.Any("/org/branding", proxyHandler(config.AuthService.URL))
+		api.Any("/org/branding/logo", proxyHandler(config.AuthService.URL))
+		api.Any("/org/ip-allowlist", proxyHandler(config.AuthService.URL))
+		api.Any("/org/invitations", proxyHandler(config.AuthService.URL))
+		api.Any("/org/invitations/:id", proxyHandler(config.AuthService.URL))
+
+		// API v2 целиком обслуживает основной сервис; маршруты v1 выше не меняются
+		api.Any("/v2/*path", proxyHandler(config.CourseService.URL))
 
 		account := api.Group("/account")
 		{
 			account.Any("/2fa/enable", proxyHandler(config.AuthService.URL))
+			account.Any("/otp/*path", proxyHandler(config.AuthService.URL))
+			account.Any("/devices", proxyHandler(config.AuthService.URL))
+			account.Any("/devices/:id", proxyHandler(config.AuthService.URL))
+			account.Any("/logins", proxyHandler(config.AuthService.URL))
+			account.Any("/export", proxyHandler(config.AuthService.URL))
+			account.Any("/username", proxyHandler(config.AuthService.URL))
+			account.Any("/avatar", proxyHandler(config.AuthService.URL))
+			account.Any("/privacy", proxyHandler(config.AuthService.URL))
+			account.Any("/digest", proxyHandler(config.AuthService.URL))
+			account.Any("", proxyHandler(config.AuthService.URL))
+			account.Any("/lock", proxyHandler(config.AuthService.URL))
+			account.Any("/email/*path", proxyHandler(config.AuthService.URL))
 		}
 
 		admin := api.Group("/admin")
 		{
 			admin.Any("/reload-templates", proxyHandler(config.AuthService.URL))
+			admin.Any("/broadcast", proxyHandler(config.AuthService.URL))
+			admin.Any("/stats", proxyHandler(config.AuthService.URL))
+
+			admin.Any("/courses/:id/translations", proxyHandler(config.CourseService.URL))
+			admin.Any("/courses/:id/translations/:locale", proxyHandler(config.CourseService.URL))
+			admin.Any("/tasks/:id/translations/:locale", proxyHandler(config.CourseService.URL))
+
+			admin.Any("/messages/reported", proxyHandler(config.CourseService.URL))
+			admin.Any("/messages/:id/moderation", proxyHandler(config.CourseService.URL))
+
+			admin.Any("/chat-channels", proxyHandler(config.CourseService.URL))
+			admin.Any("/chat-channels/:id", proxyHandler(config.CourseService.URL))
+			admin.Any("/chat-channels/:id/test", proxyHandler(config.CourseService.URL))
+
+			admin.Any("/challenges", proxyHandler(config.CourseService.URL))
+			admin.Any("/challenges/:id", proxyHandler(config.CourseService.URL))
+			admin.Any("/courses/:id/recertification", proxyHandler(config.CourseService.URL))
+			admin.Any("/compliance", proxyHandler(config.CourseService.URL))
+			// Выгрузка прогресса передается потоком, поэтому строки CSV отправляются клиенту без буферизации
+			admin.Any("/progress/export", streamHandler(config.CourseService.URL))
 
 			admin.Any("/users", proxyHandler(config.AuthService.URL))
 			admin.Any("/users/:id", proxyHandler(config.AuthService.URL))
 			admin.Any("/users/by-role", proxyHandler(config.AuthService.URL))
 			admin.Any("/users/search", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/by-username/:username", proxyHandler(config.AuthService.URL))
 			admin.Any("/users/:id/status", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/:id/single-session", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/:id/manager", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/:id/organization", proxyHandler(config.AuthService.URL))
+			admin.Any("/courses/:id/organization", proxyHandler(config.CourseService.URL))
+			admin.Any("/courses/archived", proxyHandler(config.CourseService.URL))
+			admin.Any("/courses/:id", proxyHandler(config.CourseService.URL))
+			admin.Any("/courses/:id/archive", proxyHandler(config.CourseService.URL))
+			admin.Any("/courses/:id/restore", proxyHandler(config.CourseService.URL))
+			admin.Any("/organizations", proxyHandler(config.AuthService.URL))
+			admin.Any("/organizations/:id", proxyHandler(config.AuthService.URL))
+			admin.Any("/organizations/:id/admins/:user_id", proxyHandler(config.AuthService.URL))
+			admin.Any("/organizations/:id/branding", proxyHandler(config.AuthService.URL))
+			admin.Any("/organizations/:id/branding/logo", proxyHandler(config.AuthService.URL))
+			admin.Any("/organizations/:id/ip-allowlist", proxyHandler(config.AuthService.URL))
+			admin.Any("/invitations", proxyHandler(config.AuthService.URL))
+			admin.Any("/invitations/:id", proxyHandler(config.AuthService.URL))
+			admin.Any("/registrations", proxyHandler(config.AuthService.URL))
+			admin.Any("/registrations/:user_id/*path", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/bulk", proxyHandler(config.AuthService.URL))
+			admin.Any("/activity", proxyHandler(config.AuthService.URL))
+			admin.Any("/presence", proxyHandler(config.AuthService.URL))
+			admin.Any("/retention", proxyHandler(config.AuthService.URL))
+			admin.Any("/retention/preview", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/access-expiry", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/access-expiry/extend", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/:id/access-expiry", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/:id/force-password-reset", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/:id/email-changes", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/:id/username", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/:id/username-history", proxyHandler(config.AuthService.URL))
+			admin.Any("/users/:id/avatar", proxyHandler(config.AuthService.URL))
 			admin.Any("/users/:id/promote", proxyHandler(config.AuthService.URL))
 			admin.Any("/users/:id/demote", proxyHandler(config.AuthService.URL))
+
+			admin.Any("/settings", proxyHandler(config.AuthService.URL))
+			admin.Any("/settings/:key", proxyHandler(config.AuthService.URL))
+			admin.Any("/maintenance", proxyHandler(config.AuthService.URL))
+
+			admin.Any("/flags", proxyHandler(config.AuthService.URL))
+			admin.Any("/flags/:key", proxyHandler(config.AuthService.URL))
 		}
 	}
 }
@@ -23,6 +23,7 @@ type Server struct {
 
 func NewServer(config *utils.Config, logger *logger.Logger) *Server {
 	router := gin.New()
+	router.Use(middleware.RequestIDMiddleware())
 	router.Use(middleware.LoggerMiddleware(logger))
 	router.Use(gin.Recovery())
 
@@ -42,10 +43,22 @@ func NewServer(config *utils.Config, logger *logger.Logger) *Server {
 }
 
 func (s *Server) setupRoutes() {
-	setupRoutes(s.router, s.config, s.logger, s.proxyRequest)
+	setupRoutes(s.router, s.config, s.logger, s.proxyRequest, s.streamRequest)
 }
 
 func (s *Server) proxyRequest(targetURL string) gin.HandlerFunc {
+	return s.proxy(targetURL, 0)
+}
+
+// streamRequest проксирует потоковые ответы (Server-Sent Events, выгрузки) без буферизации:
+// каждая полученная от сервиса порция сразу отправляется клиенту
+func (s *Server) streamRequest(targetURL string) gin.HandlerFunc {
+	return s.proxy(targetURL, -1)
+}
+
+// proxy возвращает обработчик, проксирующий запрос в targetURL. flushInterval задает
+// период сброса ответа клиенту, отрицательное значение — сброс после каждой записи.
+func (s *Server) proxy(targetURL string, flushInterval time.Duration) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		remote, err := url.Parse(targetURL)
 		if err != nil {
@@ -55,6 +68,7 @@ func (s *Server) proxyRequest(targetURL string) gin.HandlerFunc {
 		}
 
 		proxy := httputil.NewSingleHostReverseProxy(remote)
+		proxy.FlushInterval = flushInterval
 		proxy.Director = func(req *http.Request) {
 			req.URL.Scheme = remote.Scheme
 			req.URL.Host = remote.Host
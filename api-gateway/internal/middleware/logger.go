@@ -15,7 +15,8 @@ func LoggerMiddleware(logger *logger.Logger) gin.HandlerFunc {
 		endTime := time.Now()
 		latency := endTime.Sub(startTime)
 
-		logger.Info("%s %s %s %d %s",
+		logger.Info("%s %s %s %s %d %s",
+			c.GetString("requestID"),
 			c.Request.Method,
 			c.Request.URL.Path,
 			c.ClientIP(),
@@ -23,36 +23,37 @@ func NewRateLimiter(limit int, window time.Duration) *RateLimiter {
 	}
 }
 
+// allow учитывает запрос с адреса ip и сообщает, укладывается ли он в лимит
+func (rl *RateLimiter) allow(ip string, now time.Time) bool {
+	rl.Lock()
+	defer rl.Unlock()
+
+	var requests []time.Time
+	for _, reqTime := range rl.requests[ip] {
+		if now.Sub(reqTime) < rl.window {
+			requests = append(requests, reqTime)
+		}
+	}
+
+	if len(requests) >= rl.limit {
+		rl.requests[ip] = requests
+		return false
+	}
+	rl.requests[ip] = append(requests, now)
+	return true
+}
+
+// RateLimiterMiddleware ограничивает число запросов с одного адреса. Блокировка снимается до
+// проксирования, иначе долгие запросы (потоки событий, выгрузки) останавливали бы все остальные
 func RateLimiterMiddleware() gin.HandlerFunc {
 	limiter := NewRateLimiter(100, time.Minute)
 
 	return func(c *gin.Context) {
-		ip := c.ClientIP()
-
-		limiter.Lock()
-		defer limiter.Unlock()
-
-		now := time.Now()
-		if _, exists := limiter.requests[ip]; !exists {
-			limiter.requests[ip] = []time.Time{now}
-			c.Next()
-			return
-		}
-
-		var requests []time.Time
-		for _, reqTime := range limiter.requests[ip] {
-			if now.Sub(reqTime) < limiter.window {
-				requests = append(requests, reqTime)
-			}
-		}
-
-		if len(requests) >= limiter.limit {
+		if !limiter.allow(c.ClientIP(), time.Now()) {
 			c.JSON(http.StatusTooManyRequests, gin.H{"error": "Too many requests"})
 			c.Abort()
 			return
 		}
-
-		limiter.requests[ip] = append(requests, now)
 		c.Next()
 	}
 }
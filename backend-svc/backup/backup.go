@@ -0,0 +1,215 @@
+// Package backup выгружает данные LMS в переносимый архив и восстанавливает их в новый экземпляр.
+// Архив не зависит от СУБД: строки таблиц хранятся в JSON, поэтому резервную копию MySQL можно
+// восстановить в SQLite и наоборот. Вместе с данными сохраняются объекты медиахранилища
+// (аватары, пакеты SCORM), на которые ссылаются строки.
+//
+// Формат — tar.gz со следующими файлами, в этом порядке:
+//
+//	manifest.json          — Manifest
+//	tables/<table>.jsonl   — первая строка: столбцы и их виды, далее по строке JSON на запись
+//	media/<key>            — объекты локального медиахранилища
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"lmsmodule/backend-svc/storage"
+)
+
+// FormatVersion — версия формата архива; Restore отклоняет архивы других версий
+const FormatVersion = 1
+
+const (
+	manifestName = "manifest.json"
+	tablesDir    = "tables/"
+	mediaDir     = "media/"
+)
+
+// skippedTables не попадают в архив и не очищаются при восстановлении: версия схемы и ее
+// совместимость принадлежат экземпляру, ключи идемпотентности — кэш повторных запросов,
+// а аренды задач — состояние работающих реплик
+var skippedTables = map[string]bool{
+	"schema_migrations": true,
+	"schema_compat":     true,
+	"idempotency_keys":  true,
+	"job_leases":        true,
+	"sqlite_sequence":   true,
+}
+
+// objectReferences — столбцы с префиксами ключей объектов медиахранилища
+var objectReferences = []struct{ table, column string }{
+	{"users", "avatar_key"},
+	{"scorm_packages", "asset_key"},
+}
+
+// Manifest описывает архив
+type Manifest struct {
+	Format        int       `json:"format"`
+	CreatedAt     time.Time `json:"createdAt"`
+	Driver        string    `json:"driver"`
+	SchemaVersion int64     `json:"schemaVersion"`
+	Tables        []string  `json:"tables"`
+	// ObjectPrefixes — префиксы ключей объектов, на которые ссылаются данные. Если объекты
+	// не вошли в архив (хранилище S3), их нужно перенести средствами хранилища.
+	ObjectPrefixes []string `json:"objectPrefixes"`
+	MediaIncluded  bool     `json:"mediaIncluded"`
+}
+
+// Виды столбцов, значения которых в JSON хранятся не в своем естественном виде
+const (
+	kindValue  = ""
+	kindTime   = "time"   // строка RFC 3339
+	kindBinary = "binary" // строка base64
+)
+
+// column — столбец таблицы в заголовке tables/<table>.jsonl
+type column struct {
+	Name string `json:"name"`
+	Kind string `json:"kind,omitempty"`
+}
+
+// Stats — число строк по таблицам и число объектов медиахранилища
+type Stats struct {
+	Rows    map[string]int64
+	Objects int
+}
+
+var (
+	// ErrSchemaMismatch — версия схемы архива не совпадает с версией схемы экземпляра
+	ErrSchemaMismatch = errors.New("backup: schema version mismatch")
+	// ErrNotEmpty — в экземпляре уже есть пользователи, а замена данных не подтверждена
+	ErrNotEmpty = errors.New("backup: target instance already has data")
+)
+
+// dialectOf возвращает диалект хранилища; пустое значение — MySQL
+func dialectOf(store *storage.DBStorage) storage.Dialect {
+	if store.Dialect == storage.DialectSQLite {
+		return storage.DialectSQLite
+	}
+	return storage.DialectMySQL
+}
+
+// listTables возвращает таблицы приложения в алфавитном порядке
+func listTables(ctx context.Context, q interface {
+	QueryContext(context.Context, string, ...interface{}) (*sql.Rows, error)
+}, dialect storage.Dialect) ([]string, error) {
+	query := "SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_type = 'BASE TABLE' ORDER BY table_name"
+	if dialect == storage.DialectSQLite {
+		query = "SELECT name FROM sqlite_master WHERE type = 'table' ORDER BY name"
+	}
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("list tables: %w", err)
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("list tables: %w", err)
+		}
+		if !skippedTables[name] {
+			tables = append(tables, name)
+		}
+	}
+	return tables, rows.Err()
+}
+
+// columnKind определяет вид столбца по типу из схемы
+func columnKind(databaseType string) string {
+	upper := strings.ToUpper(databaseType)
+	switch {
+	case strings.Contains(upper, "BLOB"), strings.Contains(upper, "BINARY"):
+		return kindBinary
+	case strings.Contains(upper, "DATE"), strings.Contains(upper, "TIME"):
+		return kindTime
+	}
+	return kindValue
+}
+
+// encodeValue приводит значение из базы к виду, пригодному для JSON
+func encodeValue(v interface{}, kind string) interface{} {
+	switch typed := v.(type) {
+	case nil:
+		return nil
+	case time.Time:
+		return typed.UTC().Format(time.RFC3339Nano)
+	case []byte:
+		if kind == kindBinary {
+			return base64.StdEncoding.EncodeToString(typed)
+		}
+		return string(typed)
+	case string:
+		if kind == kindBinary {
+			return base64.StdEncoding.EncodeToString([]byte(typed))
+		}
+	}
+	return v
+}
+
+// timeLayouts — форматы времени в архиве: RFC 3339 и текстовый формат MySQL без parseTime
+var timeLayouts = []string{time.RFC3339Nano, "2006-01-02 15:04:05.999999999", "2006-01-02"}
+
+// decodeValue восстанавливает значение для вставки в базу
+func decodeValue(v interface{}, kind string) (interface{}, error) {
+	switch typed := v.(type) {
+	case nil:
+		return nil, nil
+	case json.Number:
+		if n, err := typed.Int64(); err == nil {
+			return n, nil
+		}
+		return typed.Float64()
+	case string:
+		switch kind {
+		case kindBinary:
+			return base64.StdEncoding.DecodeString(typed)
+		case kindTime:
+			for _, layout := range timeLayouts {
+				if t, err := time.Parse(layout, typed); err == nil {
+					return t.UTC(), nil
+				}
+			}
+			return nil, fmt.Errorf("invalid time %q", typed)
+		}
+	}
+	return v, nil
+}
+
+// tarGzip — запись архива: tar поверх gzip
+type tarGzip struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+func (a tarGzip) add(name string, data []byte, modTime time.Time) error {
+	if err := a.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: modTime,
+	}); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	if _, err := a.tw.Write(data); err != nil {
+		return fmt.Errorf("write %s: %w", name, err)
+	}
+	return nil
+}
+
+func (a tarGzip) close() error {
+	if err := a.tw.Close(); err != nil {
+		return err
+	}
+	return a.gz.Close()
+}
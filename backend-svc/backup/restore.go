@@ -0,0 +1,225 @@
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"path"
+	"strings"
+
+	"lmsmodule/backend-svc/media"
+	"lmsmodule/backend-svc/migrate"
+	"lmsmodule/backend-svc/storage"
+)
+
+// Restore загружает архив r в хранилище. Схема экземпляра должна быть той же версии, что и
+// в архиве (миграции применяются заранее). Данные всех таблиц заменяются содержимым архива
+// в одной транзакции; если в экземпляре уже есть пользователи, восстановление выполняется
+// только при replace. Объекты из архива сохраняются в objects после фиксации транзакции.
+func Restore(ctx context.Context, store *storage.DBStorage, objects media.Store, r io.Reader, replace bool) (Manifest, Stats, error) {
+	var manifest Manifest
+	stats := Stats{Rows: map[string]int64{}}
+
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return manifest, stats, fmt.Errorf("read archive: %w", err)
+	}
+	defer gz.Close()
+	tr := tar.NewReader(gz)
+
+	header, err := tr.Next()
+	if err != nil || header.Name != manifestName {
+		return manifest, stats, fmt.Errorf("read archive: %s must be the first entry", manifestName)
+	}
+	if err := json.NewDecoder(tr).Decode(&manifest); err != nil {
+		return manifest, stats, fmt.Errorf("read %s: %w", manifestName, err)
+	}
+	if manifest.Format != FormatVersion {
+		return manifest, stats, fmt.Errorf("unsupported archive format %d", manifest.Format)
+	}
+
+	version, dirty, err := migrate.CurrentVersion(store.DB)
+	if err != nil {
+		return manifest, stats, err
+	}
+	if dirty || version != manifest.SchemaVersion {
+		return manifest, stats, fmt.Errorf("%w: archive has %d, instance has %d", ErrSchemaMismatch, manifest.SchemaVersion, version)
+	}
+	if !replace {
+		var users int
+		if err := store.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM users").Scan(&users); err != nil {
+			return manifest, stats, fmt.Errorf("count users: %w", err)
+		}
+		if users > 0 {
+			return manifest, stats, ErrNotEmpty
+		}
+	}
+
+	dialect := dialectOf(store)
+	tx, err := store.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return manifest, stats, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+	if err := clearTables(ctx, tx, dialect, manifest.Tables); err != nil {
+		return manifest, stats, err
+	}
+
+	archived := make(map[string]bool, len(manifest.Tables))
+	for _, table := range manifest.Tables {
+		archived[table] = true
+	}
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return manifest, stats, fmt.Errorf("read archive: %w", err)
+		}
+
+		switch {
+		case strings.HasPrefix(header.Name, tablesDir):
+			table := strings.TrimSuffix(strings.TrimPrefix(header.Name, tablesDir), ".jsonl")
+			if tx == nil || !archived[table] {
+				return manifest, stats, fmt.Errorf("read archive: unexpected entry %s", header.Name)
+			}
+			rows, err := restoreTable(ctx, tx, table, tr)
+			if err != nil {
+				return manifest, stats, err
+			}
+			stats.Rows[table] = rows
+
+		case strings.HasPrefix(header.Name, mediaDir):
+			// Таблицы в архиве идут раньше объектов
+			if tx != nil {
+				if err := commit(ctx, tx, dialect); err != nil {
+					return manifest, stats, err
+				}
+				tx = nil
+			}
+			if err := restoreObject(ctx, objects, strings.TrimPrefix(header.Name, mediaDir), tr); err != nil {
+				return manifest, stats, err
+			}
+			stats.Objects++
+		}
+	}
+
+	if tx != nil {
+		if err := commit(ctx, tx, dialect); err != nil {
+			return manifest, stats, err
+		}
+	}
+	return manifest, stats, nil
+}
+
+// clearTables отключает проверку внешних ключей до конца транзакции и очищает таблицы
+// экземпляра. Все таблицы очищаются до вставки строк: каскадное удаление в SQLite
+// иначе стерло бы уже восстановленные строки дочерних таблиц.
+func clearTables(ctx context.Context, tx *sql.Tx, dialect storage.Dialect, archived []string) error {
+	disable := "SET FOREIGN_KEY_CHECKS = 0"
+	if dialect == storage.DialectSQLite {
+		disable = "PRAGMA defer_foreign_keys = ON"
+	}
+	if _, err := tx.ExecContext(ctx, disable); err != nil {
+		return fmt.Errorf("disable foreign key checks: %w", err)
+	}
+
+	tables, err := listTables(ctx, tx, dialect)
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		present[table] = true
+	}
+	for _, table := range archived {
+		if !present[table] {
+			return fmt.Errorf("table %s does not exist in the instance schema", table)
+		}
+	}
+	for _, table := range tables {
+		if _, err := tx.ExecContext(ctx, "DELETE FROM `"+table+"`"); err != nil {
+			return fmt.Errorf("clear %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
+// commit возвращает проверку внешних ключей MySQL и фиксирует транзакцию. SQLite проверяет
+// отложенные ограничения при фиксации.
+func commit(ctx context.Context, tx *sql.Tx, dialect storage.Dialect) error {
+	if dialect == storage.DialectMySQL {
+		if _, err := tx.ExecContext(ctx, "SET FOREIGN_KEY_CHECKS = 1"); err != nil {
+			return fmt.Errorf("enable foreign key checks: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit restore: %w", err)
+	}
+	return nil
+}
+
+// restoreTable вставляет строки из tables/<table>.jsonl; возвращает их число
+func restoreTable(ctx context.Context, tx *sql.Tx, table string, r io.Reader) (int64, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+
+	var columns []column
+	if err := dec.Decode(&columns); err != nil {
+		return 0, fmt.Errorf("read columns of %s: %w", table, err)
+	}
+	names := make([]string, len(columns))
+	for i, col := range columns {
+		names[i] = "`" + col.Name + "`"
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?, ", len(columns)), ", ")
+	stmt, err := tx.PrepareContext(ctx, fmt.Sprintf("INSERT INTO `%s` (%s) VALUES (%s)", table, strings.Join(names, ", "), placeholders))
+	if err != nil {
+		return 0, fmt.Errorf("restore %s: %w", table, err)
+	}
+	defer stmt.Close()
+
+	var count int64
+	for {
+		var row []interface{}
+		err := dec.Decode(&row)
+		if errors.Is(err, io.EOF) {
+			return count, nil
+		}
+		if err != nil {
+			return count, fmt.Errorf("read %s row %d: %w", table, count+1, err)
+		}
+		if len(row) != len(columns) {
+			return count, fmt.Errorf("read %s row %d: %d values for %d columns", table, count+1, len(row), len(columns))
+		}
+		for i, v := range row {
+			if row[i], err = decodeValue(v, columns[i].Kind); err != nil {
+				return count, fmt.Errorf("read %s row %d, column %s: %w", table, count+1, columns[i].Name, err)
+			}
+		}
+		if _, err := stmt.ExecContext(ctx, row...); err != nil {
+			return count, fmt.Errorf("restore %s row %d: %w", table, count+1, err)
+		}
+		count++
+	}
+}
+
+// restoreObject сохраняет объект медиахранилища из архива
+func restoreObject(ctx context.Context, objects media.Store, key string, r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("read media %s: %w", key, err)
+	}
+	contentType := mime.TypeByExtension(path.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	return objects.Put(ctx, key, contentType, data)
+}
@@ -0,0 +1,217 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"lmsmodule/backend-svc/migrate"
+	"lmsmodule/backend-svc/storage"
+)
+
+// Write выгружает данные хранилища в архив w. Таблицы читаются в одной транзакции, поэтому
+// архив согласован, даже если сервис продолжает работать. mediaRoot — каталог локального
+// медиахранилища; если он пуст, в архив попадают только ссылки на объекты.
+// Префиксы, для которых в mediaRoot не нашлось файлов, возвращаются в missing.
+func Write(ctx context.Context, store *storage.DBStorage, mediaRoot string, w io.Writer) (manifest Manifest, stats Stats, missing []string, err error) {
+	dialect := dialectOf(store)
+	version, dirty, err := migrate.CurrentVersion(store.DB)
+	if err != nil {
+		return manifest, stats, nil, err
+	}
+	if dirty {
+		return manifest, stats, nil, fmt.Errorf("version %d: %w", version, migrate.ErrDirty)
+	}
+
+	opts := &sql.TxOptions{}
+	if dialect == storage.DialectMySQL {
+		opts = &sql.TxOptions{Isolation: sql.LevelRepeatableRead, ReadOnly: true}
+	}
+	tx, err := store.DB.BeginTx(ctx, opts)
+	if err != nil {
+		return manifest, stats, nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	tables, err := listTables(ctx, tx, dialect)
+	if err != nil {
+		return manifest, stats, nil, err
+	}
+	prefixes, err := objectPrefixes(ctx, tx, tables)
+	if err != nil {
+		return manifest, stats, nil, err
+	}
+
+	manifest = Manifest{
+		Format:         FormatVersion,
+		CreatedAt:      time.Now().UTC(),
+		Driver:         string(dialect),
+		SchemaVersion:  version,
+		Tables:         tables,
+		ObjectPrefixes: prefixes,
+		MediaIncluded:  mediaRoot != "",
+	}
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return manifest, stats, nil, err
+	}
+
+	gz := gzip.NewWriter(w)
+	archive := tarGzip{gz: gz, tw: tar.NewWriter(gz)}
+	if err := archive.add(manifestName, data, manifest.CreatedAt); err != nil {
+		return manifest, stats, nil, err
+	}
+
+	stats.Rows = make(map[string]int64, len(tables))
+	for _, table := range tables {
+		var buf bytes.Buffer
+		rows, err := dumpTable(ctx, tx, table, &buf)
+		if err != nil {
+			return manifest, stats, nil, err
+		}
+		if err := archive.add(tablesDir+table+".jsonl", buf.Bytes(), manifest.CreatedAt); err != nil {
+			return manifest, stats, nil, err
+		}
+		stats.Rows[table] = rows
+	}
+
+	if mediaRoot != "" {
+		for _, prefix := range prefixes {
+			found, err := addMedia(archive, mediaRoot, prefix)
+			if err != nil {
+				return manifest, stats, nil, err
+			}
+			if found == 0 {
+				missing = append(missing, prefix)
+			}
+			stats.Objects += found
+		}
+	}
+
+	return manifest, stats, missing, archive.close()
+}
+
+// objectPrefixes собирает префиксы ключей объектов из столбцов objectReferences
+func objectPrefixes(ctx context.Context, tx *sql.Tx, tables []string) ([]string, error) {
+	present := make(map[string]bool, len(tables))
+	for _, table := range tables {
+		present[table] = true
+	}
+
+	prefixes := []string{}
+	for _, ref := range objectReferences {
+		if !present[ref.table] {
+			continue
+		}
+		rows, err := tx.QueryContext(ctx, fmt.Sprintf("SELECT DISTINCT %s FROM %s WHERE %s <> '' ORDER BY %s",
+			ref.column, ref.table, ref.column, ref.column))
+		if err != nil {
+			return nil, fmt.Errorf("read %s.%s: %w", ref.table, ref.column, err)
+		}
+		for rows.Next() {
+			var prefix string
+			if err := rows.Scan(&prefix); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("read %s.%s: %w", ref.table, ref.column, err)
+			}
+			prefixes = append(prefixes, prefix)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, fmt.Errorf("read %s.%s: %w", ref.table, ref.column, err)
+		}
+	}
+	return prefixes, nil
+}
+
+// dumpTable записывает в w заголовок со столбцами и строки таблицы; возвращает число строк
+func dumpTable(ctx context.Context, tx *sql.Tx, table string, w io.Writer) (int64, error) {
+	rows, err := tx.QueryContext(ctx, "SELECT * FROM `"+table+"`")
+	if err != nil {
+		return 0, fmt.Errorf("dump %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	types, err := rows.ColumnTypes()
+	if err != nil {
+		return 0, fmt.Errorf("dump %s: %w", table, err)
+	}
+	columns := make([]column, len(types))
+	for i, t := range types {
+		columns[i] = column{Name: t.Name(), Kind: columnKind(t.DatabaseTypeName())}
+	}
+
+	enc := json.NewEncoder(w)
+	if err := enc.Encode(columns); err != nil {
+		return 0, err
+	}
+
+	values := make([]interface{}, len(columns))
+	pointers := make([]interface{}, len(columns))
+	for i := range values {
+		pointers[i] = &values[i]
+	}
+	var count int64
+	for rows.Next() {
+		if err := rows.Scan(pointers...); err != nil {
+			return 0, fmt.Errorf("dump %s: %w", table, err)
+		}
+		row := make([]interface{}, len(columns))
+		for i, v := range values {
+			row[i] = encodeValue(v, columns[i].Kind)
+		}
+		if err := enc.Encode(row); err != nil {
+			return 0, err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("dump %s: %w", table, err)
+	}
+	return count, nil
+}
+
+// addMedia добавляет в архив файлы локального хранилища с ключами под prefix; возвращает их число
+func addMedia(archive tarGzip, root, prefix string) (int, error) {
+	dir := filepath.Join(root, filepath.FromSlash(path.Clean("/"+prefix)))
+	count := 0
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) {
+				return nil
+			}
+			return err
+		}
+		if entry.IsDir() || filepath.Ext(file) == ".tmp" {
+			return nil
+		}
+		rel, err := filepath.Rel(root, file)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return err
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		count++
+		return archive.add(mediaDir+filepath.ToSlash(rel), data, info.ModTime())
+	})
+	if err != nil {
+		return count, fmt.Errorf("read media %s: %w", prefix, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,286 @@
+// Package badges выпускает значки Open Badges 3.0: OpenBadgeCredential, защищенный подписью
+// VC-JWT (RS256), и PNG со встроенным удостоверением для публикации в профилях.
+package badges
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+
+	"lmsmodule/backend-svc/models"
+)
+
+// Контексты JSON-LD Verifiable Credentials 2.0 и Open Badges 3.0
+var credentialContext = []string{
+	"https://www.w3.org/ns/credentials/v2",
+	"https://purl.imsglobal.org/spec/ob/v3p0/context-3.0.3.json",
+}
+
+// ErrInvalidCredential — строка не является удостоверением, выпущенным этим сервисом
+var ErrInvalidCredential = errors.New("badges: invalid credential")
+
+// Profile — издатель значков
+type Profile struct {
+	Context []string `json:"@context,omitempty"`
+	ID      string   `json:"id"`
+	Type    []string `json:"type"`
+	Name    string   `json:"name"`
+	URL     string   `json:"url,omitempty"`
+}
+
+type Image struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type Criteria struct {
+	Narrative string `json:"narrative"`
+}
+
+type Achievement struct {
+	ID              string   `json:"id"`
+	Type            []string `json:"type"`
+	AchievementType string   `json:"achievementType"`
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	Criteria        Criteria `json:"criteria"`
+	Image           *Image   `json:"image,omitempty"`
+}
+
+// IdentityObject — получатель, указанный хешем с солью, чтобы email не попадал в открытый значок
+type IdentityObject struct {
+	Type         string `json:"type"`
+	IdentityHash string `json:"identityHash"`
+	IdentityType string `json:"identityType"`
+	Hashed       bool   `json:"hashed"`
+	Salt         string `json:"salt"`
+}
+
+// Recipient — получатель значка: IdentityType "emailAddress" для email или "identifier" для
+// имени пользователя, если email не указан. В удостоверение попадает только хеш Identity.
+type Recipient struct {
+	IdentityType string
+	Identity     string
+}
+
+type AchievementSubject struct {
+	Type        []string         `json:"type"`
+	Identifier  []IdentityObject `json:"identifier"`
+	Achievement Achievement      `json:"achievement"`
+}
+
+// Credential — OpenBadgeCredential
+type Credential struct {
+	Context           []string           `json:"@context"`
+	ID                string             `json:"id"`
+	Type              []string           `json:"type"`
+	Issuer            Profile            `json:"issuer"`
+	ValidFrom         string             `json:"validFrom"`
+	Name              string             `json:"name"`
+	CredentialSubject AchievementSubject `json:"credentialSubject"`
+}
+
+// claims — полезная нагрузка VC-JWT: удостоверение и соответствующие ему зарегистрированные поля JWT
+type claims struct {
+	Credential
+	ISS string `json:"iss"`
+	JTI string `json:"jti"`
+	NBF int64  `json:"nbf"`
+}
+
+// Issuer подписывает удостоверения ключом организации-издателя
+type Issuer struct {
+	name    string
+	url     string
+	baseURL string
+	key     *rsa.PrivateKey
+	keyID   string
+}
+
+// NewIssuer создает издателя. baseURL — внешний адрес API, относительно которого строятся
+// адреса издателя, значков и ключей; url — сайт издателя.
+func NewIssuer(name, url, baseURL string, key *rsa.PrivateKey) *Issuer {
+	baseURL = strings.TrimSuffix(baseURL, "/")
+	if url == "" {
+		url = baseURL
+	}
+	i := &Issuer{name: name, url: url, baseURL: baseURL, key: key}
+	i.keyID = i.JWKSURL() + "#" + thumbprint(&key.PublicKey)
+	return i
+}
+
+// ParsePrivateKey читает закрытый ключ RSA из PEM (PKCS#1 или PKCS#8)
+func ParsePrivateKey(data string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(data))
+	if block == nil {
+		return nil, errors.New("badges: signing key is not PEM")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("badges: parse signing key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("badges: signing key must be an RSA key")
+	}
+	return key, nil
+}
+
+// GenerateKey создает временный ключ подписи
+func GenerateKey() (*rsa.PrivateKey, error) {
+	return rsa.GenerateKey(rand.Reader, 2048)
+}
+
+// Адреса, по которым проверяющие получают издателя, ключи и удостоверения
+func (i *Issuer) ProfileURL() string { return i.baseURL + "/api/badges/issuer" }
+func (i *Issuer) JWKSURL() string    { return i.baseURL + "/api/badges/jwks" }
+func (i *Issuer) CredentialURL(assertionID string) string {
+	return i.baseURL + "/api/badges/assertions/" + assertionID
+}
+func (i *Issuer) ImageURL(class models.BadgeClass) string {
+	return i.baseURL + "/api/badges/images/" + strconv.Itoa(class.ID) + "?v=" + strconv.FormatInt(class.UpdatedAt.Unix(), 10)
+}
+
+// Profile возвращает профиль издателя
+func (i *Issuer) Profile() Profile {
+	return Profile{ID: i.ProfileURL(), Type: []string{"Profile"}, Name: i.name, URL: i.url}
+}
+
+// ProfileDocument возвращает профиль издателя как самостоятельный документ JSON-LD
+func (i *Issuer) ProfileDocument() Profile {
+	profile := i.Profile()
+	profile.Context = credentialContext
+	return profile
+}
+
+// Name возвращает название издателя
+func (i *Issuer) Name() string { return i.name }
+
+// Issue выпускает удостоверение о значке class для получателя recipient и возвращает его в
+// компактной форме JWS
+func (i *Issuer) Issue(assertionID string, class models.BadgeClass, recipient Recipient, issuedAt time.Time) (string, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return "", fmt.Errorf("badges: generate salt: %w", err)
+	}
+	saltHex := hex.EncodeToString(salt)
+	identity := strings.TrimSpace(recipient.Identity)
+	if recipient.IdentityType == "emailAddress" {
+		identity = strings.ToLower(identity)
+	}
+	hash := sha256.Sum256([]byte(identity + saltHex))
+
+	credential := Credential{
+		Context:   credentialContext,
+		ID:        i.CredentialURL(assertionID),
+		Type:      []string{"VerifiableCredential", "OpenBadgeCredential"},
+		Issuer:    i.Profile(),
+		ValidFrom: issuedAt.UTC().Format(time.RFC3339),
+		Name:      class.Name,
+		CredentialSubject: AchievementSubject{
+			Type: []string{"AchievementSubject"},
+			Identifier: []IdentityObject{{
+				Type:         "IdentityObject",
+				IdentityHash: "sha256$" + hex.EncodeToString(hash[:]),
+				IdentityType: recipient.IdentityType,
+				Hashed:       true,
+				Salt:         saltHex,
+			}},
+			Achievement: Achievement{
+				ID:              i.baseURL + "/api/badges/classes/" + strconv.Itoa(class.ID),
+				Type:            []string{"Achievement"},
+				AchievementType: class.AchievementType,
+				Name:            class.Name,
+				Description:     class.Description,
+				Criteria:        Criteria{Narrative: class.Criteria},
+				Image:           &Image{ID: i.ImageURL(class), Type: "Image"},
+			},
+		},
+	}
+	return i.sign(claims{
+		Credential: credential,
+		ISS:        credential.Issuer.ID,
+		JTI:        credential.ID,
+		NBF:        issuedAt.Unix(),
+	})
+}
+
+func (i *Issuer) sign(payload claims) (string, error) {
+	header, err := json.Marshal(map[string]interface{}{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": i.keyID,
+		"jwk": publicJWK(&i.key.PublicKey, ""),
+	})
+	if err != nil {
+		return "", err
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+	input := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(body)
+	digest := sha256.Sum256([]byte(input))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, i.key, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("badges: sign credential: %w", err)
+	}
+	return input + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
+
+// Decode возвращает удостоверение из VC-JWT без проверки подписи: строка берется из хранилища сервиса
+func Decode(token string) (Credential, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Credential{}, ErrInvalidCredential
+	}
+	body, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Credential{}, ErrInvalidCredential
+	}
+	var payload claims
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return Credential{}, ErrInvalidCredential
+	}
+	return payload.Credential, nil
+}
+
+// JWKS возвращает открытый ключ издателя в формате JSON Web Key Set
+func (i *Issuer) JWKS() map[string]interface{} {
+	return map[string]interface{}{"keys": []map[string]string{publicJWK(&i.key.PublicKey, i.keyID)}}
+}
+
+func publicJWK(key *rsa.PublicKey, keyID string) map[string]string {
+	jwk := map[string]string{
+		"kty": "RSA",
+		"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+		"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+	}
+	if keyID != "" {
+		jwk["kid"], jwk["alg"], jwk["use"] = keyID, "RS256", "sig"
+	}
+	return jwk
+}
+
+// thumbprint вычисляет отпечаток ключа по RFC 7638
+func thumbprint(key *rsa.PublicKey) string {
+	jwk := publicJWK(key, "")
+	canonical := `{"e":"` + jwk["e"] + `","kty":"RSA","n":"` + jwk["n"] + `"}`
+	sum := sha256.Sum256([]byte(canonical))
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,135 @@
+package badges
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"math"
+)
+
+// BakedKeyword — ключ чанка iTXt, в котором Open Badges 3.0 хранит удостоверение внутри PNG
+const BakedKeyword = "openbadgecredential"
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// ErrInvalidImage — изображение значка не является PNG
+var ErrInvalidImage = errors.New("badges: image is not a PNG")
+
+// MaxImageSide ограничивает ширину и высоту изображения значка
+const MaxImageSide = 2048
+
+// CheckImage проверяет, что data — PNG допустимого размера
+func CheckImage(data []byte) error {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return ErrInvalidImage
+	}
+	cfg, err := png.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return ErrInvalidImage
+	}
+	if cfg.Width > MaxImageSide || cfg.Height > MaxImageSide {
+		return errors.New("badges: image must not exceed 2048x2048 pixels")
+	}
+	return nil
+}
+
+// Bake встраивает удостоверение в PNG: чанк iTXt с ключом openbadgecredential добавляется
+// перед IEND, а ранее встроенное удостоверение удаляется
+func Bake(img []byte, credential string) ([]byte, error) {
+	if !bytes.HasPrefix(img, pngSignature) {
+		return nil, ErrInvalidImage
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(img)+len(credential)+64))
+	out.Write(pngSignature)
+	for rest := img[len(pngSignature):]; ; {
+		if len(rest) < 12 {
+			return nil, ErrInvalidImage
+		}
+		length := binary.BigEndian.Uint32(rest)
+		if uint64(length)+12 > uint64(len(rest)) {
+			return nil, ErrInvalidImage
+		}
+		chunk := rest[:12+length]
+		kind, data := string(chunk[4:8]), chunk[8:8+length]
+		rest = rest[12+length:]
+
+		if kind == "iTXt" && bytes.HasPrefix(data, []byte(BakedKeyword+"\x00")) {
+			continue
+		}
+		if kind == "IEND" {
+			// Ключ, флаг и метод сжатия (без сжатия), пустые язык и переведенный ключ, текст
+			text := append([]byte(BakedKeyword), 0, 0, 0, 0, 0)
+			writeChunk(out, "iTXt", append(text, credential...))
+			out.Write(chunk)
+			return out.Bytes(), nil
+		}
+		out.Write(chunk)
+	}
+}
+
+func writeChunk(w *bytes.Buffer, kind string, data []byte) {
+	var header [8]byte
+	binary.BigEndian.PutUint32(header[:4], uint32(len(data)))
+	copy(header[4:], kind)
+	w.Write(header[:])
+	w.Write(data)
+	crc := crc32.NewIEEE()
+	crc.Write(header[4:])
+	crc.Write(data)
+	var sum [4]byte
+	binary.BigEndian.PutUint32(sum[:], crc.Sum32())
+	w.Write(sum[:])
+}
+
+// DefaultImage рисует изображение для значка без загруженной картинки: медаль цвета,
+// который определяется номером значка
+func DefaultImage(seed int) []byte {
+	const size = 256
+	hue := float64(seed*137%360) / 360
+	fill, ring := hsl(hue, 0.55, 0.5), hsl(hue, 0.6, 0.35)
+
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	center := float64(size-1) / 2
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			d := math.Hypot(float64(x)-center, float64(y)-center)
+			switch {
+			case d <= 96:
+				img.SetNRGBA(x, y, fill)
+			case d <= 112:
+				img.SetNRGBA(x, y, ring)
+			case d <= 120:
+				img.SetNRGBA(x, y, fill)
+			}
+		}
+	}
+	var buf bytes.Buffer
+	png.Encode(&buf, img) // запись в bytes.Buffer не завершается ошибкой
+	return buf.Bytes()
+}
+
+func hsl(h, s, l float64) color.NRGBA {
+	c := (1 - math.Abs(2*l-1)) * s
+	x := c * (1 - math.Abs(math.Mod(h*6, 2)-1))
+	m := l - c/2
+	var r, g, b float64
+	switch int(h * 6) {
+	case 0:
+		r, g = c, x
+	case 1:
+		r, g = x, c
+	case 2:
+		g, b = c, x
+	case 3:
+		g, b = x, c
+	case 4:
+		r, b = x, c
+	default:
+		r, b = c, x
+	}
+	return color.NRGBA{R: uint8((r + m) * 255), G: uint8((g + m) * 255), B: uint8((b + m) * 255), A: 255}
+}
@@ -0,0 +1,126 @@
+// Package captcha проверяет токены CAPTCHA через siteverify API провайдера
+// (hCaptcha, reCAPTCHA, Cloudflare Turnstile).
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrMissingToken — клиент не передал токен CAPTCHA
+	ErrMissingToken = errors.New("captcha token is required")
+	// ErrInvalidToken — провайдер отклонил токен
+	ErrInvalidToken = errors.New("captcha verification failed")
+	// ErrUnavailable — провайдер не ответил; запрос не может быть проверен
+	ErrUnavailable = errors.New("captcha provider is unavailable")
+)
+
+// Провайдеры CAPTCHA
+const (
+	ProviderNone      = "none"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderReCAPTCHA = "recaptcha"
+	ProviderTurnstile = "turnstile"
+)
+
+// Providers — допустимые значения captcha.provider
+var Providers = []string{ProviderNone, ProviderHCaptcha, ProviderReCAPTCHA, ProviderTurnstile}
+
+// verifyURLs — адреса siteverify; все провайдеры принимают одинаковую форму и отвечают {"success": ...}
+var verifyURLs = map[string]string{
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderReCAPTCHA: "https://www.google.com/recaptcha/api/siteverify",
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+}
+
+// Verifier проверяет токен, полученный клиентом от виджета CAPTCHA
+type Verifier interface {
+	// Provider возвращает имя провайдера; ProviderNone означает, что проверка отключена
+	Provider() string
+	Verify(ctx context.Context, token, remoteIP string) error
+}
+
+// New создает проверяющего для провайдера. Для ProviderNone проверка всегда успешна.
+func New(provider, secret string) (Verifier, error) {
+	if provider == "" || provider == ProviderNone {
+		return disabled{}, nil
+	}
+	verifyURL, ok := verifyURLs[provider]
+	if !ok {
+		return nil, fmt.Errorf("unknown captcha provider %q", provider)
+	}
+	return &SiteVerifier{
+		Name:   provider,
+		URL:    verifyURL,
+		Secret: secret,
+		Client: &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+// Disabled возвращает проверяющего, который принимает любой запрос
+func Disabled() Verifier { return disabled{} }
+
+type disabled struct{}
+
+func (disabled) Provider() string { return ProviderNone }
+
+func (disabled) Verify(context.Context, string, string) error { return nil }
+
+// SiteVerifier проверяет токены через siteverify API
+type SiteVerifier struct {
+	Name   string
+	URL    string
+	Secret string
+	Client *http.Client
+}
+
+// Provider возвращает имя провайдера
+func (v *SiteVerifier) Provider() string { return v.Name }
+
+// Verify отправляет токен провайдеру и возвращает ErrInvalidToken, если он отклонен
+func (v *SiteVerifier) Verify(ctx context.Context, token, remoteIP string) error {
+	if strings.TrimSpace(token) == "" {
+		return ErrMissingToken
+	}
+
+	form := url.Values{"secret": {v.Secret}, "response": {token}}
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.URL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := v.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %v", ErrUnavailable, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%w: unexpected status %d", ErrUnavailable, resp.StatusCode)
+	}
+
+	var result struct {
+		Success    bool     `json:"success"`
+		ErrorCodes []string `json:"error-codes"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("%w: decode response: %v", ErrUnavailable, err)
+	}
+	if !result.Success {
+		if len(result.ErrorCodes) > 0 {
+			return fmt.Errorf("%w: %s", ErrInvalidToken, strings.Join(result.ErrorCodes, ", "))
+		}
+		return ErrInvalidToken
+	}
+	return nil
+}
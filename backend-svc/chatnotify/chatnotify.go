@@ -0,0 +1,120 @@
+// Package chatnotify отправляет сообщения в каналы Slack и Mattermost через входящий webhook
+// или от имени бота с токеном.
+package chatnotify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Провайдеры чатов
+const (
+	ProviderSlack      = "slack"
+	ProviderMattermost = "mattermost"
+)
+
+// SlackAPIURL — адрес Web API Slack для отправки от имени бота
+const SlackAPIURL = "https://slack.com"
+
+// ErrNotConfigured — у канала нет ни webhook, ни токена бота с каналом
+var ErrNotConfigured = errors.New("chatnotify: neither a webhook URL nor a bot token with a channel is set")
+
+// Target — куда доставить сообщение. WebhookURL имеет приоритет; без него сообщение отправляет
+// бот с BotToken в Channel (ID канала). ServerURL — адрес сервера Mattermost; для Slack пустой
+// означает SlackAPIURL.
+type Target struct {
+	Provider   string
+	WebhookURL string
+	BotToken   string
+	ServerURL  string
+	Channel    string
+}
+
+// Sender отправляет сообщения в чаты
+type Sender struct {
+	Client *http.Client
+}
+
+// New создает отправителя с HTTP-клиентом по умолчанию
+func New() *Sender {
+	return &Sender{Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// Send публикует текст в канал. Текст передается как есть; обе системы понимают *жирный*
+// и ссылки в виде голого URL.
+func (s *Sender) Send(ctx context.Context, target Target, text string) error {
+	switch {
+	case target.WebhookURL != "":
+		// Входящие webhook Slack и Mattermost принимают одинаковое тело
+		return s.post(ctx, target.WebhookURL, "", map[string]string{"text": text}, nil)
+	case target.BotToken == "" || target.Channel == "":
+		return ErrNotConfigured
+	case target.Provider == ProviderSlack:
+		base := target.ServerURL
+		if base == "" {
+			base = SlackAPIURL
+		}
+		var result struct {
+			OK    bool   `json:"ok"`
+			Error string `json:"error"`
+		}
+		err := s.post(ctx, strings.TrimSuffix(base, "/")+"/api/chat.postMessage", target.BotToken,
+			map[string]string{"channel": target.Channel, "text": text}, &result)
+		if err == nil && !result.OK {
+			err = fmt.Errorf("chatnotify: slack: %s", result.Error)
+		}
+		return err
+	case target.Provider == ProviderMattermost:
+		if target.ServerURL == "" {
+			return errors.New("chatnotify: mattermost server URL is required for a bot token")
+		}
+		return s.post(ctx, strings.TrimSuffix(target.ServerURL, "/")+"/api/v4/posts", target.BotToken,
+			map[string]string{"channel_id": target.Channel, "message": text}, nil)
+	default:
+		return fmt.Errorf("chatnotify: unknown provider %q", target.Provider)
+	}
+}
+
+func (s *Sender) post(ctx context.Context, endpoint, token string, body, result interface{}) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("chatnotify: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		// Адрес webhook сам по себе секрет, а net/http включает его в текст ошибки
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("chatnotify: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("chatnotify: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	if result != nil {
+		if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+			return fmt.Errorf("chatnotify: decode response: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,525 @@
+// Command lmsctl — утилита администратора LMS для первичной настройки и аварийных операций
+// без прямого доступа к SQL. Использует ту же конфигурацию и слой хранения, что и backend-svc.
+package main
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/go-sql-driver/mysql"
+	"github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/bcrypt"
+	"lmsmodule/backend-svc/backup"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/media"
+	"lmsmodule/backend-svc/migrate"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/seed"
+	"lmsmodule/backend-svc/storage"
+	"lmsmodule/backend-svc/webpush"
+	"os"
+	"sort"
+	"strings"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(args []string) error
+}
+
+var commands = []command{
+	{"create-admin", "create a new administrator account", createAdmin},
+	{"reset-password", "set a new password for a user", resetPassword},
+	{"run-migrations", "apply pending database migrations", runMigrations},
+	{"seed-demo-data", "idempotently add demo courses, users and progress", seedDemoData},
+	{"export-course", "export a course with its tasks as JSON", exportCourse},
+	{"purge-user", "permanently delete a user and their progress", purgeUser},
+	{"backup", "dump application data and media to a portable archive", backupData},
+	{"restore", "load a backup archive into a fresh instance", restoreData},
+	{"generate-vapid-key", "create a key pair for web push notifications", generateVAPIDKey},
+}
+
+var configPath = os.Getenv("CONFIG_FILE")
+
+func main() {
+	flag.StringVar(&configPath, "config", configPath, "path to optional YAML config file")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	name := flag.Arg(0)
+	for _, cmd := range commands {
+		if cmd.name == name {
+			if err := cmd.run(flag.Args()[1:]); err != nil {
+				fmt.Fprintf(os.Stderr, "lmsctl %s: %v\n", name, err)
+				os.Exit(1)
+			}
+			return
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "lmsctl: unknown command %q\n\n", name)
+	usage()
+	os.Exit(2)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage: lmsctl [-config file] <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\nCommands:")
+	for _, cmd := range commands {
+		fmt.Fprintf(os.Stderr, "  %-16s %s\n", cmd.name, cmd.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'lmsctl <command> -h' for command flags.")
+}
+
+// openDB открывает пул соединений по DSN из конфигурации сервиса
+func openDB(multiStatements bool) (*sql.DB, error) {
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Database.DSN == "" {
+		return nil, errors.New("database.dsn is required (DATABASE_DSN)")
+	}
+
+	dsn := cfg.Database.DSN
+	if multiStatements {
+		parsed, err := mysql.ParseDSN(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("parse DSN: %w", err)
+		}
+		parsed.MultiStatements = true
+		dsn = parsed.FormatDSN()
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		return nil, err
+	}
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("connect to database: %w", err)
+	}
+	return db, nil
+}
+
+func openStore() (*storage.DBStorage, func(), error) {
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	store, ok, err := openSQLiteStore()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !ok {
+		db, err := openDB(false)
+		if err != nil {
+			return nil, nil, err
+		}
+		store = &storage.DBStorage{DB: db}
+	}
+	// reset-password подчиняется той же истории паролей, что и смена пароля в профиле
+	store.PasswordHistory = cfg.Security.PasswordHistory
+	return store, func() { store.Close() }, nil
+}
+
+// openSQLiteStore открывает SQLite-хранилище, если сервис настроен на драйвер sqlite.
+// Встроенная схема применяется при открытии, поэтому отдельный шаг миграций не нужен.
+func openSQLiteStore() (*storage.DBStorage, bool, error) {
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return nil, false, err
+	}
+	if cfg.Database.Driver != "sqlite" {
+		return nil, false, nil
+	}
+	store, err := storage.OpenSQLite(cfg.Database.DSN)
+	if err != nil {
+		return nil, true, err
+	}
+	return store, true, nil
+}
+
+// readPassword берет пароль из флага, переменной LMSCTL_PASSWORD или первой строки stdin
+func readPassword(value string) (string, error) {
+	if value != "" {
+		return value, nil
+	}
+	if env := os.Getenv("LMSCTL_PASSWORD"); env != "" {
+		return env, nil
+	}
+
+	fmt.Fprint(os.Stderr, "Password: ")
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("read password: %w", err)
+	}
+	password := strings.TrimRight(line, "\r\n")
+	if password == "" {
+		return "", errors.New("password must not be empty")
+	}
+	return password, nil
+}
+
+func createAdmin(args []string) error {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	username := fs.String("username", "", "login of the new administrator (required)")
+	email := fs.String("email", "", "email address (required)")
+	fullName := fs.String("full-name", "Administrator", "display name")
+	password := fs.String("password", "", "password (defaults to LMSCTL_PASSWORD or stdin)")
+	with2FA := fs.Bool("2fa", true, "require email OTP on login")
+	fs.Parse(args)
+
+	if *username == "" || *email == "" {
+		return errors.New("-username and -email are required")
+	}
+
+	plain, err := readPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(plain), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hash password: %w", err)
+	}
+
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "LMS System", AccountName: *username, SecretSize: 20})
+	if err != nil {
+		return fmt.Errorf("generate 2FA key: %w", err)
+	}
+
+	store, closeStore, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	err = store.CreateUser(models.User{
+		Username:     *username,
+		PasswordHash: string(hash),
+		Email:        *email,
+		FullName:     *fullName,
+		TOTPSecret:   key.Secret(),
+		Is2FAEnabled: *with2FA,
+		IsActive:     true,
+	})
+	if err != nil {
+		return fmt.Errorf("create user: %w", err)
+	}
+
+	user, err := store.GetUserByUsername(*username)
+	if err != nil {
+		return fmt.Errorf("load created user: %w", err)
+	}
+	if err := store.PromoteToAdmin(user.ID); err != nil {
+		return fmt.Errorf("promote user: %w", err)
+	}
+
+	fmt.Printf("Administrator %s created with ID %d\n", user.Username, user.ID)
+	return nil
+}
+
+func resetPassword(args []string) error {
+	fs := flag.NewFlagSet("reset-password", flag.ExitOnError)
+	username := fs.String("username", "", "login of the user (required)")
+	password := fs.String("password", "", "new password (defaults to LMSCTL_PASSWORD or stdin)")
+	fs.Parse(args)
+
+	if *username == "" {
+		return errors.New("-username is required")
+	}
+
+	plain, err := readPassword(*password)
+	if err != nil {
+		return err
+	}
+
+	store, closeStore, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	user, err := store.GetUserByUsername(*username)
+	if err != nil {
+		return err
+	}
+	if err := store.UpdateUserProfile(user.ID, models.UpdateProfileRequest{Password: plain, Version: storage.AnyVersion}); err != nil {
+		return fmt.Errorf("update password: %w", err)
+	}
+
+	fmt.Printf("Password for %s has been reset\n", user.Username)
+	return nil
+}
+
+func runMigrations(args []string) error {
+	fs := flag.NewFlagSet("run-migrations", flag.ExitOnError)
+	dir := fs.String("dir", "migrations", "directory with NNN_name.up.sql files")
+	fs.Parse(args)
+
+	if store, ok, err := openSQLiteStore(); ok || err != nil {
+		if err != nil {
+			return err
+		}
+		store.Close()
+		fmt.Println("SQLite schema is applied from the embedded migrations; -dir is ignored")
+		return nil
+	}
+
+	db, err := openDB(true)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	applied, err := migrate.Up(db, os.DirFS(*dir))
+	for _, m := range applied {
+		fmt.Printf("Applied %03d_%s\n", m.Version, m.Name)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(applied) == 0 {
+		fmt.Println("Schema is up to date")
+	}
+	return nil
+}
+
+func seedDemoData(args []string) error {
+	fs := flag.NewFlagSet("seed-demo-data", flag.ExitOnError)
+	password := fs.String("password", "", "password for demo users (defaults to seed.demo_password)")
+	fs.Parse(args)
+
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return err
+	}
+	if *password == "" {
+		*password = cfg.Seed.DemoPassword
+	}
+
+	store, closeStore, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	if err := seed.Demo(store, *password); err != nil {
+		return err
+	}
+	fmt.Println("Demo data seeded")
+	return nil
+}
+
+func exportCourse(args []string) error {
+	fs := flag.NewFlagSet("export-course", flag.ExitOnError)
+	id := fs.Int("id", 0, "course ID (required)")
+	out := fs.String("out", "", "output file (defaults to stdout)")
+	fs.Parse(args)
+
+	if *id == 0 {
+		return errors.New("-id is required")
+	}
+
+	store, closeStore, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	course, err := store.GetCourseByID(*id)
+	if err != nil {
+		return err
+	}
+
+	export := models.CourseExport{
+		VulnerabilityType: course.VulnerabilityType,
+		Description:       course.Description,
+		Tasks:             course.Tasks,
+	}
+
+	w := os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(export)
+}
+
+func purgeUser(args []string) error {
+	fs := flag.NewFlagSet("purge-user", flag.ExitOnError)
+	username := fs.String("username", "", "login of the user to delete (required)")
+	yes := fs.Bool("yes", false, "confirm irreversible deletion")
+	fs.Parse(args)
+
+	if *username == "" {
+		return errors.New("-username is required")
+	}
+	if !*yes {
+		return errors.New("this permanently deletes the user and their progress; re-run with -yes to confirm")
+	}
+
+	store, closeStore, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	user, err := store.GetUserByUsername(*username)
+	if err != nil {
+		return err
+	}
+	if err := store.DeleteUser(user.ID); err != nil {
+		return err
+	}
+
+	fmt.Printf("User %s (ID %d) purged\n", user.Username, user.ID)
+	return nil
+}
+
+func backupData(args []string) error {
+	fs := flag.NewFlagSet("backup", flag.ExitOnError)
+	out := fs.String("out", "", "archive file to create, e.g. lms-backup.tar.gz (required)")
+	withMedia := fs.Bool("media", true, "include files of the local media storage")
+	fs.Parse(args)
+
+	if *out == "" {
+		return errors.New("-out is required")
+	}
+
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return err
+	}
+	// Объекты S3 не копируются: в архиве остаются только ссылки на них
+	mediaRoot := ""
+	if *withMedia && cfg.Media.Driver == "local" {
+		mediaRoot = cfg.Media.LocalDir
+	}
+
+	store, closeStore, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	manifest, stats, missing, err := backup.Write(context.Background(), store, mediaRoot, f)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(*out)
+		return err
+	}
+
+	printRows(stats.Rows)
+	for _, prefix := range missing {
+		fmt.Printf("Warning: no media files found for %s\n", prefix)
+	}
+	if mediaRoot == "" && len(manifest.ObjectPrefixes) > 0 {
+		fmt.Printf("Media objects are not included; copy the %d prefixes listed in manifest.json with the storage's own tools\n",
+			len(manifest.ObjectPrefixes))
+	}
+	fmt.Printf("Backup of schema version %d written to %s (%d tables, %d media objects)\n",
+		manifest.SchemaVersion, *out, len(manifest.Tables), stats.Objects)
+	return nil
+}
+
+func restoreData(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	in := fs.String("in", "", "archive created by lmsctl backup (required)")
+	force := fs.Bool("force", false, "replace the data of an instance that already has users")
+	fs.Parse(args)
+
+	if *in == "" {
+		return errors.New("-in is required")
+	}
+
+	cfg, err := config.Read(configPath)
+	if err != nil {
+		return err
+	}
+
+	store, closeStore, err := openStore()
+	if err != nil {
+		return err
+	}
+	defer closeStore()
+
+	f, err := os.Open(*in)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	manifest, stats, err := backup.Restore(context.Background(), store, media.NewStore(cfg.Media, cfg.PublicURL), f, *force)
+	if errors.Is(err, backup.ErrNotEmpty) {
+		return fmt.Errorf("%w; re-run with -force to replace it", err)
+	}
+	if errors.Is(err, backup.ErrSchemaMismatch) {
+		return fmt.Errorf("%w; run the migrations of the version the backup was made with", err)
+	}
+	if err != nil {
+		return err
+	}
+
+	printRows(stats.Rows)
+	if !manifest.MediaIncluded && len(manifest.ObjectPrefixes) > 0 {
+		fmt.Printf("The archive has no media objects; copy the %d prefixes listed in its manifest.json to the media storage\n",
+			len(manifest.ObjectPrefixes))
+	}
+	fmt.Printf("Restored backup of %s (%s) with %d media objects\n",
+		manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"), manifest.Driver, stats.Objects)
+	return nil
+}
+
+// printRows печатает число строк по таблицам, пропуская пустые
+func printRows(rows map[string]int64) {
+	tables := make([]string, 0, len(rows))
+	for table := range rows {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+	for _, table := range tables {
+		if rows[table] > 0 {
+			fmt.Printf("  %-28s %d\n", table, rows[table])
+		}
+	}
+}
+
+func generateVAPIDKey(args []string) error {
+	fs := flag.NewFlagSet("generate-vapid-key", flag.ExitOnError)
+	fs.Parse(args)
+
+	privateKey, publicKey, err := webpush.GenerateKey()
+	if err != nil {
+		return err
+	}
+	fmt.Printf("WEBPUSH_VAPID_PRIVATE_KEY=%s\n", privateKey)
+	fmt.Printf("# Public key (served to browsers by GET /api/push): %s\n", publicKey)
+	return nil
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"log"
+	"os"
+
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/migrate"
+	"lmsmodule/backend-svc/storage"
+)
+
+// checkSchema не дает запуститься с базой, схема которой отстает от кода или уже сужена
+// под более новый код. При database.schema_check=false несовместимость только логируется.
+func checkSchema(db *sql.DB, cfg config.DatabaseConfig) {
+	compat, err := migrate.ReadCompat(db, storage.SchemaVersion())
+	if err == nil {
+		err = compat.Err()
+	}
+	switch {
+	case err != nil && cfg.SchemaCheck:
+		log.Fatalf("Schema compatibility check failed: %v", err)
+	case err != nil:
+		log.Printf("WARNING: schema compatibility check failed, starting anyway (DATABASE_SCHEMA_CHECK=false): %v", err)
+	case compat.Ahead():
+		log.Printf("Schema version %d is ahead of this build (%d) with compatible migrations", compat.Current, compat.Required)
+	}
+}
+
+// runCompatCheck — режим -check-compat для конвейера выкладки. Проверяет, что после применения
+// миграций этой сборки уже работающие экземпляры (собранные под текущую версию схемы) продолжат
+// работать, а сама сборка сможет запуститься. Возвращает код выхода: 0 — выкладка без простоя
+// возможна, 1 — нет, 2 — проверку выполнить не удалось.
+func runCompatCheck(cfg *config.Config, migrationsDir string) int {
+	var db *sql.DB
+	var migrations fs.FS
+	var err error
+	if cfg.Database.Driver == "sqlite" {
+		db, err = storage.OpenSQLiteDB(cfg.Database.DSN)
+		migrations = storage.SQLiteMigrations()
+	} else {
+		db, err = sql.Open("mysql", cfg.Database.DSN)
+		if err == nil {
+			err = storage.PingDB(context.Background(), db)
+		}
+		migrations = os.DirFS(migrationsDir)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "check-compat: connect to database: %v\n", err)
+		return 2
+	}
+	defer db.Close()
+
+	compat, err := migrate.ReadCompat(db, storage.SchemaVersion())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "check-compat: %v\n", err)
+		return 2
+	}
+	pending, err := migrate.Pending(migrations, compat.Current)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "check-compat: %v\n", err)
+		return 2
+	}
+
+	fmt.Printf("Build schema version:    %d\n", compat.Required)
+	fmt.Printf("Database schema version: %d\n", compat.Current)
+	fmt.Printf("Minimum app version:     %d\n", compat.MinAppVersion)
+
+	ok := true
+	if compat.Dirty {
+		fmt.Printf("FAIL: schema version %d is dirty, fix the failed migration first\n", compat.Current)
+		ok = false
+	}
+
+	// После миграций схема дойдет до версии сборки, а ограничение — до наибольшего из заданных
+	after := compat
+	for _, m := range pending {
+		kind := "expand"
+		if m.Contract() {
+			kind = fmt.Sprintf("contract, requires app version %d or later", m.MinAppVersion)
+			if m.MinAppVersion > compat.Current {
+				fmt.Printf("FAIL: migration %03d_%s breaks running instances built for schema %d; deploy the code that stops using the removed objects first\n",
+					m.Version, m.Name, compat.Current)
+				ok = false
+			}
+			if m.MinAppVersion > after.MinAppVersion {
+				after.MinAppVersion = m.MinAppVersion
+			}
+		}
+		fmt.Printf("Pending migration %03d_%s (%s)\n", m.Version, m.Name, kind)
+		after.Current = m.Version
+	}
+	after.Dirty = false
+	if err := after.Err(); err != nil {
+		fmt.Printf("FAIL: after migrations: %v\n", err)
+		ok = false
+	}
+
+	if !ok {
+		return 1
+	}
+	fmt.Println("OK: this build can be rolled out without downtime")
+	return 0
+}
@@ -0,0 +1,914 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"gopkg.in/yaml.v2"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Config — полная конфигурация backend-сервиса.
+// Порядок применения: значения по умолчанию, затем YAML-файл (если задан), затем переменные окружения.
+type Config struct {
+	Port            string          `yaml:"port"`
+	ShutdownTimeout time.Duration   `yaml:"shutdown_timeout"`
+	PublicURL       string          `yaml:"public_url"` // внешний адрес API для ссылок в письмах
+	Database        DatabaseConfig  `yaml:"database"`
+	JWT             JWTConfig       `yaml:"jwt"`
+	SMTP            SMTPConfig      `yaml:"smtp"`
+	Redis           RedisConfig     `yaml:"redis"`
+	Lab             LabConfig       `yaml:"lab"`
+	RateLimit       RateLimitConfig `yaml:"rate_limit"`
+	Outbox          OutboxConfig    `yaml:"outbox"`
+	Realtime        RealtimeConfig  `yaml:"realtime"`
+	Search          SearchConfig    `yaml:"search"`
+	GRPC            GRPCConfig      `yaml:"grpc"`
+	CORS            CORSConfig      `yaml:"cors"`
+	Security        SecurityConfig  `yaml:"security"`
+	Captcha         CaptchaConfig   `yaml:"captcha"`
+	OTP             OTPConfig       `yaml:"otp"`
+	Accounts        AccountsConfig  `yaml:"accounts"`
+	Media           MediaConfig     `yaml:"media"`
+	Badges          BadgesConfig    `yaml:"badges"`
+	Telegram        TelegramConfig  `yaml:"telegram"`
+	WebPush         WebPushConfig   `yaml:"webpush"`
+	Digest          DigestConfig    `yaml:"digest"`
+	Cleanup         CleanupConfig   `yaml:"cleanup"`
+	Retention       RetentionConfig `yaml:"retention"`
+	Secrets         SecretsConfig   `yaml:"secrets"`
+	Seed            SeedConfig      `yaml:"seed"`
+}
+
+type DatabaseConfig struct {
+	Driver string `yaml:"driver"`
+	DSN    string `yaml:"dsn"`
+	// ReplicaDSNs — реплики только для чтения (только для mysql)
+	ReplicaDSNs []string `yaml:"replica_dsns"`
+	// ConnectTimeout ограничивает суммарное ожидание базы данных при старте
+	ConnectTimeout      time.Duration `yaml:"connect_timeout"`
+	RetryInitialBackoff time.Duration `yaml:"retry_initial_backoff"`
+	RetryMaxBackoff     time.Duration `yaml:"retry_max_backoff"`
+	// PingInterval — период фоновой проверки соединения
+	PingInterval time.Duration `yaml:"ping_interval"`
+	// QueryTimeout ограничивает время одного обращения к хранилищу
+	QueryTimeout time.Duration `yaml:"query_timeout"`
+	// SlowQueryThreshold — порог, после которого запрос логируется как медленный; 0 отключает лог
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+	// Параметры пула соединений database/sql
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	// SchemaCheck запрещает запуск, если схема базы несовместима с кодом (см. -check-compat)
+	SchemaCheck bool `yaml:"schema_check"`
+	// ReadOnly включает режим только для чтения независимо от runtime-параметра read_only_mode:
+	// на время переключения базы параметр нельзя сохранить, а переменную окружения — можно
+	ReadOnly bool `yaml:"read_only"`
+	// Faults — внедрение сбоев в хранилище для проверки устойчивости на стенде
+	Faults FaultsConfig `yaml:"faults"`
+}
+
+// FaultsConfig — искусственные задержки и ошибки вызовов хранилища. Только для стендов:
+// в продакшене Enabled должен оставаться false.
+type FaultsConfig struct {
+	Enabled bool `yaml:"enabled"`
+	// Methods — методы Storage, вызовы которых затрагиваются (GetCourses, CompleteTask...);
+	// пусто — все методы
+	Methods []string `yaml:"methods"`
+	// Latency добавляется к вызову с вероятностью LatencyProbability
+	Latency            time.Duration `yaml:"latency"`
+	LatencyProbability float64       `yaml:"latency_probability"`
+	// ErrorProbability — вероятность вернуть ошибку вместо вызова хранилища
+	ErrorProbability float64 `yaml:"error_probability"`
+}
+
+type JWTConfig struct {
+	Secret     string        `yaml:"secret"`
+	TempSecret string        `yaml:"temp_secret"`
+	TTL        time.Duration `yaml:"ttl"`
+	TempTTL    time.Duration `yaml:"temp_ttl"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+type RedisConfig struct {
+	Addr     string `yaml:"addr"`
+	Password string `yaml:"password"`
+	DB       int    `yaml:"db"`
+}
+
+type LabConfig struct {
+	Driver string `yaml:"driver"`
+}
+
+type RateLimitConfig struct {
+	RequestsPerMinute int `yaml:"requests_per_minute"`
+	Burst             int `yaml:"burst"`
+}
+
+type OutboxConfig struct {
+	WebhookURLs []string `yaml:"webhook_urls"`
+	// Bus — публикация всех доменных событий в Kafka или NATS для конвейеров данных
+	Bus EventBusConfig `yaml:"bus"`
+}
+
+// EventBusConfig — внешняя шина событий; пустой Driver отключает ее
+type EventBusConfig struct {
+	// Driver — kafka или nats
+	Driver string `yaml:"driver"`
+	// Brokers — брокеры Kafka (host:port) или серверы NATS (nats://host:4222)
+	Brokers []string `yaml:"brokers"`
+	// Topic — топик Kafka или subject NATS; {type} заменяется типом события:
+	// lms.events.{type} → lms.events.task.completed. Без {type} все события идут в один топик.
+	Topic string `yaml:"topic"`
+	// Username и Password — SASL для Kafka, пользователь NATS; Password без Username — токен NATS.
+	// Новый пароль применяется после перезапуска сервиса.
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	// SASLMechanism — механизм SASL для Kafka: plain, scram-sha-256 или scram-sha-512
+	SASLMechanism string `yaml:"sasl_mechanism"`
+	TLS           bool   `yaml:"tls"`
+	// JetStream — публиковать в NATS JetStream и ждать подтверждения сохранения; subject'ы
+	// должны входить в поток
+	JetStream bool `yaml:"jetstream"`
+	// Timeout ограничивает подключение и отправку одного события
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+// RealtimeConfig — связь реплик для WebSocket и потока событий: сообщения и события, отправленные
+// на одной реплике, доходят до клиентов, подключенных к любой другой. Пустой Driver — клиенты
+// получают сообщения только своей реплики (достаточно при одной реплике).
+type RealtimeConfig struct {
+	// Driver — nats
+	Driver string `yaml:"driver"`
+	// Servers — серверы NATS (nats://host:4222)
+	Servers []string `yaml:"servers"`
+	// Subject — префикс subject'ов: <subject>.messages и <subject>.presence
+	Subject string `yaml:"subject"`
+	// Username и Password — пользователь NATS; Password без Username — токен
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	TLS      bool   `yaml:"tls"`
+	// PresenceInterval — период рассылки списка подключенных пользователей; реплика, не
+	// приславшая его три периода подряд, считается остановленной
+	PresenceInterval time.Duration `yaml:"presence_interval"`
+}
+
+// SearchConfig — полнотекстовый поиск по курсам, заданиям и обсуждениям. Индекс хранится
+// у каждой реплики локально и обновляется по событиям outbox, поэтому при нескольких
+// репликах нужен realtime.driver: иначе события получает только реплика диспетчера.
+// Пустой Driver отключает поиск.
+type SearchConfig struct {
+	// Driver — bleve
+	Driver string `yaml:"driver"`
+	// Path — каталог индекса; создается и заполняется при первом запуске
+	Path string `yaml:"path"`
+	// RebuildInterval — период полной переиндексации, которая восстанавливает события,
+	// пропущенные при переполнении очереди индексатора
+	RebuildInterval time.Duration `yaml:"rebuild_interval"`
+}
+
+// GRPCConfig — gRPC API для внутренних сервисов; пустой Port отключает его
+type GRPCConfig struct {
+	Port      string `yaml:"port"`
+	AuthToken string `yaml:"auth_token"`
+}
+
+// CORSConfig — источники, которым разрешены запросы из браузера. "*" разрешает любой
+// источник без передачи cookie; шаблон вида https://*.example.com — поддомены
+type CORSConfig struct {
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+// SecurityConfig — заголовки безопасности ответов
+type SecurityConfig struct {
+	// HSTSMaxAge включает Strict-Transport-Security; 0 — заголовок не отправляется
+	// (включать только при работе за HTTPS)
+	HSTSMaxAge time.Duration `yaml:"hsts_max_age"`
+	// ContentSecurityPolicy — CSP для ответов API; для Swagger UI используется собственная политика
+	ContentSecurityPolicy string `yaml:"content_security_policy"`
+	// LoginAlerts — письмо о входе с нового устройства или из нового места со ссылкой блокировки
+	LoginAlerts bool `yaml:"login_alerts"`
+	// SingleSession — значение по умолчанию для runtime-параметра single_session:
+	// новый вход завершает предыдущие сессии пользователя
+	SingleSession bool `yaml:"single_session"`
+	// PasswordHistory — сколько последних паролей, включая текущий, нельзя использовать повторно; 0 — проверка отключена
+	PasswordHistory int `yaml:"password_history"`
+	// TrustedProxies — адреса и подсети прокси, чьим X-Forwarded-For и заголовкам страны
+	// (CF-IPCountry, X-Country-Code) можно верить при определении адреса и страны клиента
+	// (списки разрешенных адресов, blocked_countries, лимиты запросов); пустой — не доверять никому
+	TrustedProxies []string `yaml:"trusted_proxies"`
+	// BlockUnknownCountry отклоняет запросы без страны от доверенного прокси, когда задан
+	// runtime-параметр blocked_countries; без него такие запросы страной не ограничиваются
+	BlockUnknownCountry bool `yaml:"block_unknown_country"`
+	// RedactPII маскирует адреса почты, имена и токены в логах, сообщениях об ошибках и событиях
+	// для webhook'ов и шины; отключается только в отладочных окружениях
+	RedactPII bool `yaml:"redact_pii"`
+}
+
+// CaptchaConfig — проверка CAPTCHA при регистрации и после неудачных попыток входа.
+// Provider "none" отключает проверку (локальная разработка, тесты)
+type CaptchaConfig struct {
+	Provider string `yaml:"provider"`
+	// SiteKey — публичный ключ виджета, отдается клиентам в /api/settings/public
+	SiteKey string `yaml:"site_key"`
+	Secret  string `yaml:"secret"`
+	// LoginFailureThreshold — после скольких неудачных входов требуется CAPTCHA; 0 — не требовать при входе
+	LoginFailureThreshold int           `yaml:"login_failure_threshold"`
+	LoginFailureWindow    time.Duration `yaml:"login_failure_window"`
+}
+
+// OTPConfig — доставка одноразовых кодов; email доступен всегда
+type OTPConfig struct {
+	// Length и Charset задают формат кода: numeric — цифры, alphanumeric — цифры и заглавные буквы
+	Length  int           `yaml:"length"`
+	Charset string        `yaml:"charset"`
+	TTL     time.Duration `yaml:"ttl"`
+	// MaxAttempts — число попыток ввода кода; после него код сбрасывается и нужен новый вход
+	MaxAttempts int `yaml:"max_attempts"`
+	// TrustedDeviceTTL — сколько устройство, отмеченное «запомнить», пропускает второй фактор; 0 отключает функцию
+	TrustedDeviceTTL time.Duration `yaml:"trusted_device_ttl"`
+	SMS              SMSConfig     `yaml:"sms"`
+}
+
+// SMSConfig — API, совместимый с Twilio Messages; пустой AccountSID отключает SMS
+type SMSConfig struct {
+	BaseURL    string `yaml:"base_url"`
+	AccountSID string `yaml:"account_sid"`
+	AuthToken  string `yaml:"auth_token"`
+	From       string `yaml:"from"`
+}
+
+// SeedConfig управляет наполнением хранилища демонстрационными данными при старте
+type SeedConfig struct {
+	DemoData     bool   `yaml:"demo_data"`
+	DemoPassword string `yaml:"demo_password"`
+}
+
+// AccountsConfig — самообслуживание учетных записей
+type AccountsConfig struct {
+	// DeletionGracePeriod — сколько учетная запись хранится после запроса на удаление;
+	// вход в этот период отменяет удаление
+	DeletionGracePeriod time.Duration `yaml:"deletion_grace_period"`
+	// UsernameChangeInterval — минимальный интервал между сменами имени пользователем
+	UsernameChangeInterval time.Duration `yaml:"username_change_interval"`
+	// UsernameReservation — сколько прежнее имя нельзя занять другому пользователю
+	UsernameReservation time.Duration `yaml:"username_reservation"`
+	// SupportedLocales — локали, которые пользователь может выбрать в профиле
+	SupportedLocales []string `yaml:"supported_locales"`
+	// DefaultLocale и DefaultTimezone применяются к пользователям, не выбравшим свои
+	DefaultLocale   string `yaml:"default_locale"`
+	DefaultTimezone string `yaml:"default_timezone"`
+}
+
+// MediaConfig — хранилище пользовательских файлов (аватаров, пакетов SCORM)
+type MediaConfig struct {
+	// Driver — "local" (каталог на диске, файлы раздает сам сервис по /media) или "s3" (S3, MinIO)
+	Driver   string `yaml:"driver"`
+	LocalDir string `yaml:"local_dir"`
+	// PublicURL — адрес, по которому клиенты получают файлы (например CDN); пусто — {public_url}/media
+	// для local и адрес бакета для s3
+	PublicURL string `yaml:"public_url"`
+	// MaxUploadSize — максимальный размер загружаемого файла в байтах
+	MaxUploadSize int `yaml:"max_upload_size"`
+	// MaxPackageSize — максимальный размер пакета SCORM в байтах, как загружаемого архива,
+	// так и распакованного содержимого
+	MaxPackageSize int      `yaml:"max_package_size"`
+	S3             S3Config `yaml:"s3"`
+}
+
+// S3Config — бакет S3-совместимого хранилища; пустой Endpoint означает AWS S3 в регионе Region
+type S3Config struct {
+	Endpoint        string `yaml:"endpoint"`
+	Region          string `yaml:"region"`
+	Bucket          string `yaml:"bucket"`
+	AccessKeyID     string `yaml:"access_key_id"`
+	SecretAccessKey string `yaml:"secret_access_key"`
+	PathStyle       bool   `yaml:"path_style"` // MinIO обычно требует адресацию {endpoint}/{bucket}
+}
+
+// BadgesConfig — выпуск значков Open Badges 3.0
+type BadgesConfig struct {
+	// IssuerName — название организации-издателя в значках
+	IssuerName string `yaml:"issuer_name"`
+	// IssuerURL — сайт издателя; пусто — public_url
+	IssuerURL string `yaml:"issuer_url"`
+	// SigningKey — закрытый ключ RSA в PEM (PKCS#1 или PKCS#8) для подписи значков.
+	// Пусто — ключ создается при запуске, и выданные значки перестают проверяться после перезапуска.
+	SigningKey string `yaml:"signing_key"`
+}
+
+// WebPushConfig — уведомления Web Push в браузер; пустой VAPIDPrivateKey отключает их
+type WebPushConfig struct {
+	// VAPIDPrivateKey — закрытый ключ P-256 в base64url (lmsctl generate-vapid-key). Смена ключа
+	// делает недействительными все подписки браузеров.
+	VAPIDPrivateKey string `yaml:"vapid_private_key"`
+	// Subject — контакт для push-сервисов: mailto: или https: адрес
+	Subject string `yaml:"subject"`
+	// TTL — сколько push-сервис хранит уведомление, пока браузер недоступен
+	TTL time.Duration `yaml:"ttl"`
+	// DeadlineReminder — за сколько до срока сдачи задания приходит уведомление
+	DeadlineReminder time.Duration `yaml:"deadline_reminder"`
+}
+
+// DigestConfig — еженедельные письма с итогами недели; пользователь может от них отписаться
+type DigestConfig struct {
+	// Enabled включает рассылку; письма уходят по понедельникам в 09:00 часового пояса по умолчанию
+	Enabled bool `yaml:"enabled"`
+}
+
+// TelegramConfig — бот Telegram для уведомлений студентов; пустой BotToken отключает бота
+type TelegramConfig struct {
+	BotToken string `yaml:"bot_token"`
+	// BotUsername — имя бота без @ для ссылок привязки аккаунта t.me/<bot>?start=<код>
+	BotUsername string `yaml:"bot_username"`
+	// WebhookSecret — секрет, который Telegram передает в заголовке X-Telegram-Bot-Api-Secret-Token
+	WebhookSecret string `yaml:"webhook_secret"`
+	// APIURL — адрес Bot API; меняется для собственного сервера telegram-bot-api
+	APIURL string `yaml:"api_url"`
+	// DeadlineReminder — за сколько до срока сдачи задания бот напоминает о нем
+	DeadlineReminder time.Duration `yaml:"deadline_reminder"`
+}
+
+type CleanupConfig struct {
+	Interval       time.Duration `yaml:"interval"`
+	EventRetention time.Duration `yaml:"event_retention"`
+	// IdempotencyRetention — сколько хранятся ответы для повторов с Idempotency-Key
+	IdempotencyRetention time.Duration `yaml:"idempotency_retention"`
+}
+
+// RetentionConfig — политики хранения данных. Срок в днях отсчитывается от времени записи
+// (для учетных записей — от последнего входа или регистрации); 0 — данные этого вида не удаляются.
+type RetentionConfig struct {
+	Interval time.Duration `yaml:"interval"`
+	// DryRun — задача только сообщает, сколько записей затронула бы, ничего не меняя
+	DryRun         bool   `yaml:"dry_run"`
+	AuditLogDays   int    `yaml:"audit_log_days"`
+	AuditLogAction string `yaml:"audit_log_action"`
+	// LoginHistoryDays — история входов (устройства и места) и ссылки блокировки из писем о входе
+	LoginHistoryDays   int    `yaml:"login_history_days"`
+	LoginHistoryAction string `yaml:"login_history_action"`
+	// InactiveAccountDays — учетные записи без входа дольше срока; администраторы не затрагиваются
+	InactiveAccountDays   int    `yaml:"inactive_account_days"`
+	InactiveAccountAction string `yaml:"inactive_account_action"`
+}
+
+// Допустимые действия политик хранения
+var retentionActions = []string{"purge", "anonymize"}
+
+// Допустимые драйверы лабораторных окружений
+var labDrivers = []string{"none", "docker", "kubernetes"}
+
+// Допустимые драйверы базы данных; sqlite предназначен для локальной разработки
+var databaseDrivers = []string{"mysql", "sqlite"}
+
+var mediaDrivers = []string{"local", "s3"}
+
+// Допустимые провайдеры CAPTCHA
+var captchaProviders = []string{"none", "hcaptcha", "recaptcha", "turnstile"}
+
+// Допустимые наборы символов одноразовых кодов
+var otpCharsets = []string{"numeric", "alphanumeric"}
+
+// Default возвращает конфигурацию со значениями по умолчанию (без секретов)
+func Default() *Config {
+	return &Config{
+		Port:            "8081",
+		ShutdownTimeout: 30 * time.Second,
+		PublicURL:       "http://localhost:8081",
+		Database: DatabaseConfig{
+			Driver:              "mysql",
+			ConnectTimeout:      60 * time.Second,
+			RetryInitialBackoff: 500 * time.Millisecond,
+			RetryMaxBackoff:     10 * time.Second,
+			PingInterval:        15 * time.Second,
+			QueryTimeout:        5 * time.Second,
+			SlowQueryThreshold:  500 * time.Millisecond,
+			MaxOpenConns:        20,
+			MaxIdleConns:        10,
+			ConnMaxLifetime:     5 * time.Minute,
+			ConnMaxIdleTime:     time.Minute,
+			SchemaCheck:         true,
+		},
+		JWT: JWTConfig{
+			TTL:     24 * time.Hour,
+			TempTTL: 5 * time.Minute,
+		},
+		SMTP: SMTPConfig{
+			Host: "smtp.mail.ru",
+			Port: "465",
+		},
+		Lab: LabConfig{
+			Driver: "none",
+		},
+		RateLimit: RateLimitConfig{
+			RequestsPerMinute: 600,
+			Burst:             100,
+		},
+		Outbox: OutboxConfig{
+			Bus: EventBusConfig{
+				Topic:   "lms.events.{type}",
+				Timeout: 10 * time.Second,
+			},
+		},
+		Realtime: RealtimeConfig{
+			Subject:          "lms.realtime",
+			PresenceInterval: 10 * time.Second,
+		},
+		Search: SearchConfig{
+			Path:            "data/search.bleve",
+			RebuildInterval: 24 * time.Hour,
+		},
+		Accounts: AccountsConfig{
+			DeletionGracePeriod:    14 * 24 * time.Hour,
+			UsernameChangeInterval: 30 * 24 * time.Hour,
+			UsernameReservation:    90 * 24 * time.Hour,
+			SupportedLocales:       []string{"en", "ru"},
+			DefaultLocale:          "en",
+			DefaultTimezone:        "UTC",
+		},
+		Media: MediaConfig{
+			Driver:         "local",
+			LocalDir:       "data/media",
+			MaxUploadSize:  5 << 20,
+			MaxPackageSize: 200 << 20,
+			S3:             S3Config{Region: "us-east-1"},
+		},
+		Badges: BadgesConfig{
+			IssuerName: "LMS",
+		},
+		Telegram: TelegramConfig{
+			APIURL:           "https://api.telegram.org",
+			DeadlineReminder: 24 * time.Hour,
+		},
+		WebPush: WebPushConfig{
+			TTL:              24 * time.Hour,
+			DeadlineReminder: 24 * time.Hour,
+		},
+		Cleanup: CleanupConfig{
+			Interval:             15 * time.Minute,
+			EventRetention:       7 * 24 * time.Hour,
+			IdempotencyRetention: 24 * time.Hour,
+		},
+		Retention: RetentionConfig{
+			Interval:              24 * time.Hour,
+			AuditLogAction:        "purge",
+			LoginHistoryAction:    "purge",
+			InactiveAccountAction: "anonymize",
+		},
+		Secrets: SecretsConfig{
+			RefreshInterval: 5 * time.Minute,
+		},
+		CORS: CORSConfig{
+			AllowedOrigins: []string{"*"},
+			MaxAge:         10 * time.Minute,
+		},
+		Security: SecurityConfig{
+			ContentSecurityPolicy: "default-src 'none'; frame-ancestors 'none'",
+			LoginAlerts:           true,
+			PasswordHistory:       5,
+			RedactPII:             true,
+		},
+		Captcha: CaptchaConfig{
+			Provider:              "none",
+			LoginFailureThreshold: 3,
+			LoginFailureWindow:    15 * time.Minute,
+		},
+		OTP: OTPConfig{
+			Length:           6,
+			Charset:          "numeric",
+			TTL:              5 * time.Minute,
+			MaxAttempts:      5,
+			TrustedDeviceTTL: 30 * 24 * time.Hour,
+		},
+		Seed: SeedConfig{
+			DemoPassword: "demo-password",
+		},
+	}
+}
+
+// Load собирает конфигурацию из значений по умолчанию, необязательного YAML-файла
+// и переменных окружения, после чего проверяет её
+func Load(path string) (*Config, error) {
+	cfg, err := Read(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Read собирает конфигурацию так же, как Load, но без полной проверки.
+// Используется утилитами (lmsctl), которым нужна только часть настроек.
+func Read(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("config: read %s: %w", path, err)
+		}
+		if err := yaml.UnmarshalStrict(data, cfg); err != nil {
+			return nil, fmt.Errorf("config: parse %s: %w", path, err)
+		}
+	}
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if _, err := cfg.RefreshSecrets(context.Background()); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// ValidationError перечисляет все проблемы конфигурации сразу, а не только первую
+type ValidationError struct {
+	Problems []string
+}
+
+func (e *ValidationError) Error() string {
+	return "config: invalid configuration:\n  - " + strings.Join(e.Problems, "\n  - ")
+}
+
+// Validate проверяет обязательные значения и их формат
+func (c *Config) Validate() error {
+	var problems []string
+	add := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	if _, err := strconv.Atoi(c.Port); err != nil {
+		add("port must be a number, got %q (PORT)", c.Port)
+	}
+	if c.ShutdownTimeout <= 0 {
+		add("shutdown_timeout must be positive (SHUTDOWN_TIMEOUT)")
+	}
+	if !strings.HasPrefix(c.PublicURL, "http://") && !strings.HasPrefix(c.PublicURL, "https://") || strings.HasSuffix(c.PublicURL, "/") {
+		add("public_url must be an http(s) URL without a trailing slash, got %q (PUBLIC_URL)", c.PublicURL)
+	}
+	if !contains(databaseDrivers, c.Database.Driver) {
+		add("database.driver must be one of %s, got %q (DATABASE_DRIVER)", strings.Join(databaseDrivers, ", "), c.Database.Driver)
+	}
+	if c.Database.DSN == "" {
+		add("database.dsn is required (DATABASE_DSN)")
+	}
+	if len(c.Database.ReplicaDSNs) > 0 && c.Database.Driver != "mysql" {
+		add("database.replica_dsns are supported only with the mysql driver (DATABASE_REPLICA_DSNS)")
+	}
+	if c.Database.ConnectTimeout <= 0 {
+		add("database.connect_timeout must be positive (DATABASE_CONNECT_TIMEOUT)")
+	}
+	if c.Database.RetryInitialBackoff <= 0 {
+		add("database.retry_initial_backoff must be positive (DATABASE_RETRY_INITIAL_BACKOFF)")
+	}
+	if c.Database.RetryMaxBackoff < c.Database.RetryInitialBackoff {
+		add("database.retry_max_backoff must not be less than retry_initial_backoff (DATABASE_RETRY_MAX_BACKOFF)")
+	}
+	if c.Database.PingInterval <= 0 {
+		add("database.ping_interval must be positive (DATABASE_PING_INTERVAL)")
+	}
+	if c.Database.QueryTimeout <= 0 {
+		add("database.query_timeout must be positive (DATABASE_QUERY_TIMEOUT)")
+	}
+	if c.Database.SlowQueryThreshold < 0 {
+		add("database.slow_query_threshold must not be negative (DATABASE_SLOW_QUERY_THRESHOLD)")
+	}
+	if c.Database.MaxOpenConns <= 0 {
+		add("database.max_open_conns must be positive (DATABASE_MAX_OPEN_CONNS)")
+	}
+	if c.Database.MaxIdleConns < 0 || c.Database.MaxIdleConns > c.Database.MaxOpenConns {
+		add("database.max_idle_conns must be between 0 and max_open_conns (DATABASE_MAX_IDLE_CONNS)")
+	}
+	if c.Database.Faults.Enabled {
+		faults := c.Database.Faults
+		if faults.Latency < 0 {
+			add("database.faults.latency must not be negative (DATABASE_FAULTS_LATENCY)")
+		}
+		if faults.LatencyProbability < 0 || faults.LatencyProbability > 1 {
+			add("database.faults.latency_probability must be between 0 and 1 (DATABASE_FAULTS_LATENCY_PROBABILITY)")
+		}
+		if faults.ErrorProbability < 0 || faults.ErrorProbability > 1 {
+			add("database.faults.error_probability must be between 0 and 1 (DATABASE_FAULTS_ERROR_PROBABILITY)")
+		}
+	}
+	if c.Database.ConnMaxLifetime < 0 {
+		add("database.conn_max_lifetime must not be negative (DATABASE_CONN_MAX_LIFETIME)")
+	}
+	if c.Database.ConnMaxIdleTime < 0 {
+		add("database.conn_max_idle_time must not be negative (DATABASE_CONN_MAX_IDLE_TIME)")
+	}
+	if c.JWT.Secret == "" {
+		add("jwt.secret is required (JWT_SECRET)")
+	}
+	if c.JWT.TempSecret == "" {
+		add("jwt.temp_secret is required (JWT_TEMP_SECRET)")
+	}
+	if c.JWT.Secret != "" && c.JWT.Secret == c.JWT.TempSecret {
+		add("jwt.secret and jwt.temp_secret must differ")
+	}
+	if c.JWT.TTL <= 0 {
+		add("jwt.ttl must be positive (JWT_TTL)")
+	}
+	if c.JWT.TempTTL <= 0 {
+		add("jwt.temp_ttl must be positive (JWT_TEMP_TTL)")
+	}
+	if c.SMTP.Host == "" {
+		add("smtp.host is required (SMTP_HOST)")
+	}
+	if _, err := strconv.Atoi(c.SMTP.Port); err != nil {
+		add("smtp.port must be a number, got %q (SMTP_PORT)", c.SMTP.Port)
+	}
+	if c.SMTP.Username != "" && c.SMTP.Password == "" {
+		add("smtp.password is required when smtp.username is set (SMTP_PASSWORD)")
+	}
+	if c.Redis.Addr != "" {
+		if _, _, err := net.SplitHostPort(c.Redis.Addr); err != nil {
+			add("redis.addr must be host:port, got %q (REDIS_ADDR)", c.Redis.Addr)
+		}
+	}
+	if !contains(labDrivers, c.Lab.Driver) {
+		add("lab.driver must be one of %s, got %q (LAB_DRIVER)", strings.Join(labDrivers, ", "), c.Lab.Driver)
+	}
+	if c.RateLimit.RequestsPerMinute <= 0 {
+		add("rate_limit.requests_per_minute must be positive (RATE_LIMIT_RPM)")
+	}
+	if c.RateLimit.Burst <= 0 {
+		add("rate_limit.burst must be positive (RATE_LIMIT_BURST)")
+	}
+	for _, url := range c.Outbox.WebhookURLs {
+		if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+			add("outbox.webhook_urls must be http(s) URLs, got %q (OUTBOX_WEBHOOK_URLS)", url)
+		}
+	}
+	switch c.Outbox.Bus.Driver {
+	case "":
+	case "kafka", "nats":
+		if len(c.Outbox.Bus.Brokers) == 0 {
+			add("outbox.bus.brokers is required when outbox.bus.driver is set (OUTBOX_BUS_BROKERS)")
+		}
+		if c.Outbox.Bus.Topic == "" {
+			add("outbox.bus.topic is required when outbox.bus.driver is set (OUTBOX_BUS_TOPIC)")
+		}
+		if c.Outbox.Bus.Timeout <= 0 {
+			add("outbox.bus.timeout must be positive (OUTBOX_BUS_TIMEOUT)")
+		}
+	default:
+		add("outbox.bus.driver must be kafka or nats, got %q (OUTBOX_BUS_DRIVER)", c.Outbox.Bus.Driver)
+	}
+	switch c.Outbox.Bus.SASLMechanism {
+	case "", "plain", "scram-sha-256", "scram-sha-512":
+	default:
+		add("outbox.bus.sasl_mechanism must be plain, scram-sha-256 or scram-sha-512, got %q (OUTBOX_BUS_SASL_MECHANISM)", c.Outbox.Bus.SASLMechanism)
+	}
+	if c.Outbox.Bus.JetStream && c.Outbox.Bus.Driver != "nats" {
+		add("outbox.bus.jetstream requires outbox.bus.driver nats (OUTBOX_BUS_JETSTREAM)")
+	}
+	switch c.Realtime.Driver {
+	case "":
+	case "nats":
+		if len(c.Realtime.Servers) == 0 {
+			add("realtime.servers is required when realtime.driver is set (REALTIME_SERVERS)")
+		}
+		if c.Realtime.Subject == "" {
+			add("realtime.subject is required when realtime.driver is set (REALTIME_SUBJECT)")
+		}
+		if c.Realtime.PresenceInterval <= 0 {
+			add("realtime.presence_interval must be positive (REALTIME_PRESENCE_INTERVAL)")
+		}
+	default:
+		add("realtime.driver must be nats, got %q (REALTIME_DRIVER)", c.Realtime.Driver)
+	}
+	switch c.Search.Driver {
+	case "":
+	case "bleve":
+		if c.Search.Path == "" {
+			add("search.path is required when search.driver is set (SEARCH_PATH)")
+		}
+		if c.Search.RebuildInterval <= 0 {
+			add("search.rebuild_interval must be positive (SEARCH_REBUILD_INTERVAL)")
+		}
+	default:
+		add("search.driver must be bleve, got %q (SEARCH_DRIVER)", c.Search.Driver)
+	}
+	if c.GRPC.Port != "" {
+		if _, err := strconv.Atoi(c.GRPC.Port); err != nil {
+			add("grpc.port must be a number, got %q (GRPC_PORT)", c.GRPC.Port)
+		} else if c.GRPC.Port == c.Port {
+			add("grpc.port must differ from port (GRPC_PORT)")
+		}
+		if len(c.GRPC.AuthToken) < 16 {
+			add("grpc.auth_token must be at least 16 characters when grpc.port is set (GRPC_AUTH_TOKEN)")
+		}
+	}
+	for _, origin := range c.CORS.AllowedOrigins {
+		if origin == "*" {
+			if c.CORS.AllowCredentials {
+				add("cors.allowed_origins must list explicit origins when cors.allow_credentials is enabled (CORS_ALLOWED_ORIGINS)")
+			}
+			continue
+		}
+		if !strings.HasPrefix(origin, "http://") && !strings.HasPrefix(origin, "https://") || strings.HasSuffix(origin, "/") {
+			add("cors.allowed_origins must be scheme://host[:port] without a trailing slash, got %q (CORS_ALLOWED_ORIGINS)", origin)
+		}
+	}
+	if c.CORS.MaxAge < 0 {
+		add("cors.max_age must not be negative (CORS_MAX_AGE)")
+	}
+	if c.Security.HSTSMaxAge < 0 {
+		add("security.hsts_max_age must not be negative (SECURITY_HSTS_MAX_AGE)")
+	}
+	if c.Security.PasswordHistory < 0 || c.Security.PasswordHistory > 24 {
+		add("security.password_history must be between 0 and 24 (SECURITY_PASSWORD_HISTORY)")
+	}
+	for _, proxy := range c.Security.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			add("security.trusted_proxies: %q is not a valid IP address or CIDR range (SECURITY_TRUSTED_PROXIES)", proxy)
+		}
+	}
+	if c.Security.BlockUnknownCountry && len(c.Security.TrustedProxies) == 0 {
+		add("security.block_unknown_country requires security.trusted_proxies: without a trusted proxy no request has a country (SECURITY_BLOCK_UNKNOWN_COUNTRY)")
+	}
+	if !contains(captchaProviders, c.Captcha.Provider) {
+		add("captcha.provider must be one of %s, got %q (CAPTCHA_PROVIDER)", strings.Join(captchaProviders, ", "), c.Captcha.Provider)
+	} else if c.Captcha.Provider != "none" {
+		if c.Captcha.Secret == "" {
+			add("captcha.secret is required when captcha.provider is set (CAPTCHA_SECRET)")
+		}
+		if c.Captcha.SiteKey == "" {
+			add("captcha.site_key is required when captcha.provider is set (CAPTCHA_SITE_KEY)")
+		}
+	}
+	if c.Captcha.LoginFailureThreshold < 0 {
+		add("captcha.login_failure_threshold must not be negative (CAPTCHA_LOGIN_FAILURE_THRESHOLD)")
+	}
+	if c.Captcha.LoginFailureWindow <= 0 {
+		add("captcha.login_failure_window must be positive (CAPTCHA_LOGIN_FAILURE_WINDOW)")
+	}
+	// Верхняя граница длины — размер столбца users.otp_code
+	if c.OTP.Length < 4 || c.OTP.Length > 32 {
+		add("otp.length must be between 4 and 32 (OTP_LENGTH)")
+	}
+	if !contains(otpCharsets, c.OTP.Charset) {
+		add("otp.charset must be one of %s, got %q (OTP_CHARSET)", strings.Join(otpCharsets, ", "), c.OTP.Charset)
+	}
+	if c.OTP.TTL < 30*time.Second || c.OTP.TTL > time.Hour {
+		add("otp.ttl must be between 30s and 1h (OTP_TTL)")
+	}
+	if c.OTP.MaxAttempts <= 0 {
+		add("otp.max_attempts must be positive (OTP_MAX_ATTEMPTS)")
+	}
+	if c.OTP.TrustedDeviceTTL < 0 {
+		add("otp.trusted_device_ttl must not be negative (OTP_TRUSTED_DEVICE_TTL)")
+	}
+	if c.OTP.SMS.AccountSID != "" {
+		if c.OTP.SMS.AuthToken == "" {
+			add("otp.sms.auth_token is required when otp.sms.account_sid is set (OTP_SMS_AUTH_TOKEN)")
+		}
+		if c.OTP.SMS.From == "" {
+			add("otp.sms.from is required when otp.sms.account_sid is set (OTP_SMS_FROM)")
+		}
+		if c.OTP.SMS.BaseURL != "" && !strings.HasPrefix(c.OTP.SMS.BaseURL, "https://") && !strings.HasPrefix(c.OTP.SMS.BaseURL, "http://") {
+			add("otp.sms.base_url must be an http(s) URL, got %q (OTP_SMS_BASE_URL)", c.OTP.SMS.BaseURL)
+		}
+	}
+	if c.Accounts.DeletionGracePeriod <= 0 {
+		add("accounts.deletion_grace_period must be positive (ACCOUNTS_DELETION_GRACE_PERIOD)")
+	}
+	if c.Accounts.UsernameChangeInterval < 0 {
+		add("accounts.username_change_interval must not be negative (ACCOUNTS_USERNAME_CHANGE_INTERVAL)")
+	}
+	if c.Accounts.UsernameReservation < 0 {
+		add("accounts.username_reservation must not be negative (ACCOUNTS_USERNAME_RESERVATION)")
+	}
+	if !contains(c.Accounts.SupportedLocales, c.Accounts.DefaultLocale) {
+		add("accounts.default_locale must be one of accounts.supported_locales %s, got %q (ACCOUNTS_DEFAULT_LOCALE)",
+			strings.Join(c.Accounts.SupportedLocales, ", "), c.Accounts.DefaultLocale)
+	}
+	if _, err := time.LoadLocation(c.Accounts.DefaultTimezone); err != nil || c.Accounts.DefaultTimezone == "" {
+		add("accounts.default_timezone must be an IANA time zone, got %q (ACCOUNTS_DEFAULT_TIMEZONE)", c.Accounts.DefaultTimezone)
+	}
+	if !contains(mediaDrivers, c.Media.Driver) {
+		add("media.driver must be one of %s, got %q (MEDIA_DRIVER)", strings.Join(mediaDrivers, ", "), c.Media.Driver)
+	}
+	if c.Media.Driver == "local" && c.Media.LocalDir == "" {
+		add("media.local_dir is required for the local media driver (MEDIA_LOCAL_DIR)")
+	}
+	if c.Media.Driver == "s3" {
+		if c.Media.S3.Bucket == "" || c.Media.S3.Region == "" {
+			add("media.s3.bucket and media.s3.region are required for the s3 media driver (MEDIA_S3_BUCKET, MEDIA_S3_REGION)")
+		}
+		if c.Media.S3.AccessKeyID == "" || c.Media.S3.SecretAccessKey == "" {
+			add("media.s3.access_key_id and media.s3.secret_access_key are required for the s3 media driver (MEDIA_S3_ACCESS_KEY_ID, MEDIA_S3_SECRET_ACCESS_KEY)")
+		}
+	}
+	if c.Media.PublicURL != "" && !strings.HasPrefix(c.Media.PublicURL, "https://") && !strings.HasPrefix(c.Media.PublicURL, "http://") {
+		add("media.public_url must be an http(s) URL, got %q (MEDIA_PUBLIC_URL)", c.Media.PublicURL)
+	}
+	if c.Media.MaxUploadSize <= 0 {
+		add("media.max_upload_size must be positive (MEDIA_MAX_UPLOAD_SIZE)")
+	}
+	if c.Media.MaxPackageSize <= 0 {
+		add("media.max_package_size must be positive (MEDIA_MAX_PACKAGE_SIZE)")
+	}
+	if c.Badges.IssuerName == "" {
+		add("badges.issuer_name is required (BADGES_ISSUER_NAME)")
+	}
+	if c.Badges.IssuerURL != "" && !strings.HasPrefix(c.Badges.IssuerURL, "https://") && !strings.HasPrefix(c.Badges.IssuerURL, "http://") {
+		add("badges.issuer_url must be an http(s) URL, got %q (BADGES_ISSUER_URL)", c.Badges.IssuerURL)
+	}
+	if c.Telegram.BotToken != "" {
+		if c.Telegram.BotUsername == "" {
+			add("telegram.bot_username is required when telegram.bot_token is set (TELEGRAM_BOT_USERNAME)")
+		}
+		if c.Telegram.WebhookSecret == "" {
+			add("telegram.webhook_secret is required when telegram.bot_token is set (TELEGRAM_WEBHOOK_SECRET)")
+		}
+	}
+	if !strings.HasPrefix(c.Telegram.APIURL, "https://") && !strings.HasPrefix(c.Telegram.APIURL, "http://") {
+		add("telegram.api_url must be an http(s) URL, got %q (TELEGRAM_API_URL)", c.Telegram.APIURL)
+	}
+	if c.Telegram.DeadlineReminder <= 0 {
+		add("telegram.deadline_reminder must be positive (TELEGRAM_DEADLINE_REMINDER)")
+	}
+	if c.WebPush.VAPIDPrivateKey != "" && !strings.HasPrefix(c.WebPush.Subject, "mailto:") && !strings.HasPrefix(c.WebPush.Subject, "https://") {
+		add("webpush.subject must be a mailto: or https:// contact when webpush.vapid_private_key is set, got %q (WEBPUSH_SUBJECT)", c.WebPush.Subject)
+	}
+	if c.WebPush.TTL <= 0 {
+		add("webpush.ttl must be positive (WEBPUSH_TTL)")
+	}
+	if c.WebPush.DeadlineReminder <= 0 {
+		add("webpush.deadline_reminder must be positive (WEBPUSH_DEADLINE_REMINDER)")
+	}
+	if c.Cleanup.Interval <= 0 {
+		add("cleanup.interval must be positive (CLEANUP_INTERVAL)")
+	}
+	if c.Cleanup.EventRetention <= 0 {
+		add("cleanup.event_retention must be positive (CLEANUP_EVENT_RETENTION)")
+	}
+	if c.Cleanup.IdempotencyRetention <= 0 {
+		add("cleanup.idempotency_retention must be positive (CLEANUP_IDEMPOTENCY_RETENTION)")
+	}
+	if c.Retention.Interval <= 0 {
+		add("retention.interval must be positive (RETENTION_INTERVAL)")
+	}
+	for _, policy := range []struct {
+		name, env, action string
+		days              int
+	}{
+		{"audit_log", "AUDIT_LOG", c.Retention.AuditLogAction, c.Retention.AuditLogDays},
+		{"login_history", "LOGIN_HISTORY", c.Retention.LoginHistoryAction, c.Retention.LoginHistoryDays},
+		{"inactive_account", "INACTIVE_ACCOUNT", c.Retention.InactiveAccountAction, c.Retention.InactiveAccountDays},
+	} {
+		if policy.days < 0 {
+			add("retention.%s_days must not be negative (RETENTION_%s_DAYS)", policy.name, policy.env)
+		}
+		if !contains(retentionActions, policy.action) {
+			add("retention.%s_action must be one of %v, got %q (RETENTION_%s_ACTION)", policy.name, retentionActions, policy.action, policy.env)
+		}
+	}
+	if c.Seed.DemoData && len(c.Seed.DemoPassword) < 8 {
+		add("seed.demo_password must be at least 8 characters (SEED_DEMO_PASSWORD)")
+	}
+	if c.Secrets.RefreshInterval <= 0 {
+		add("secrets.refresh_interval must be positive (SECRETS_REFRESH_INTERVAL)")
+	}
+	if c.Secrets.Vault.Addr != "" && c.Secrets.Vault.Path == "" {
+		add("secrets.vault.path is required when secrets.vault.addr is set (VAULT_SECRET_PATH)")
+	}
+	if c.Secrets.Vault.Enabled() && c.Secrets.Vault.Token == "" && c.Secrets.Vault.TokenFile == "" {
+		add("secrets.vault.token or secrets.vault.token_file is required (VAULT_TOKEN, VAULT_TOKEN_FILE)")
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,247 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envParser применяет переменные окружения и накапливает ошибки разбора
+type envParser struct {
+	problems []string
+}
+
+func (p *envParser) str(name string, target *string) {
+	if value := os.Getenv(name); value != "" {
+		*target = value
+	}
+}
+
+func (p *envParser) int(name string, target *int) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		p.problems = append(p.problems, fmt.Sprintf("%s must be an integer, got %q", name, value))
+		return
+	}
+	*target = parsed
+}
+
+func (p *envParser) bool(name string, target *bool) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		p.problems = append(p.problems, fmt.Sprintf("%s must be true or false, got %q", name, value))
+		return
+	}
+	*target = parsed
+}
+
+func (p *envParser) duration(name string, target *time.Duration) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		p.problems = append(p.problems, fmt.Sprintf("%s must be a duration like 30s or 5m, got %q", name, value))
+		return
+	}
+	*target = parsed
+}
+
+func (p *envParser) float(name string, target *float64) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		p.problems = append(p.problems, fmt.Sprintf("%s must be a number, got %q", name, value))
+		return
+	}
+	*target = parsed
+}
+
+func (p *envParser) list(name string, target *[]string) {
+	value := os.Getenv(name)
+	if value == "" {
+		return
+	}
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	*target = items
+}
+
+func (c *Config) applyEnv() error {
+	p := &envParser{}
+
+	p.str("PORT", &c.Port)
+	p.duration("SHUTDOWN_TIMEOUT", &c.ShutdownTimeout)
+	p.str("PUBLIC_URL", &c.PublicURL)
+
+	p.str("DATABASE_DRIVER", &c.Database.Driver)
+	p.str("DATABASE_DSN", &c.Database.DSN)
+	p.list("DATABASE_REPLICA_DSNS", &c.Database.ReplicaDSNs)
+	p.duration("DATABASE_CONNECT_TIMEOUT", &c.Database.ConnectTimeout)
+	p.duration("DATABASE_RETRY_INITIAL_BACKOFF", &c.Database.RetryInitialBackoff)
+	p.duration("DATABASE_RETRY_MAX_BACKOFF", &c.Database.RetryMaxBackoff)
+	p.duration("DATABASE_PING_INTERVAL", &c.Database.PingInterval)
+	p.duration("DATABASE_QUERY_TIMEOUT", &c.Database.QueryTimeout)
+	p.duration("DATABASE_SLOW_QUERY_THRESHOLD", &c.Database.SlowQueryThreshold)
+	p.int("DATABASE_MAX_OPEN_CONNS", &c.Database.MaxOpenConns)
+	p.int("DATABASE_MAX_IDLE_CONNS", &c.Database.MaxIdleConns)
+	p.duration("DATABASE_CONN_MAX_LIFETIME", &c.Database.ConnMaxLifetime)
+	p.duration("DATABASE_CONN_MAX_IDLE_TIME", &c.Database.ConnMaxIdleTime)
+	p.bool("DATABASE_SCHEMA_CHECK", &c.Database.SchemaCheck)
+	p.bool("DATABASE_READ_ONLY", &c.Database.ReadOnly)
+	p.bool("DATABASE_FAULTS_ENABLED", &c.Database.Faults.Enabled)
+	p.list("DATABASE_FAULTS_METHODS", &c.Database.Faults.Methods)
+	p.duration("DATABASE_FAULTS_LATENCY", &c.Database.Faults.Latency)
+	p.float("DATABASE_FAULTS_LATENCY_PROBABILITY", &c.Database.Faults.LatencyProbability)
+	p.float("DATABASE_FAULTS_ERROR_PROBABILITY", &c.Database.Faults.ErrorProbability)
+
+	p.str("JWT_SECRET", &c.JWT.Secret)
+	p.str("JWT_TEMP_SECRET", &c.JWT.TempSecret)
+	p.duration("JWT_TTL", &c.JWT.TTL)
+	p.duration("JWT_TEMP_TTL", &c.JWT.TempTTL)
+
+	p.str("SMTP_HOST", &c.SMTP.Host)
+	p.str("SMTP_PORT", &c.SMTP.Port)
+	p.str("SMTP_USERNAME", &c.SMTP.Username)
+	p.str("SMTP_PASSWORD", &c.SMTP.Password)
+	p.str("SMTP_FROM", &c.SMTP.From)
+
+	p.str("REDIS_ADDR", &c.Redis.Addr)
+	p.str("REDIS_PASSWORD", &c.Redis.Password)
+	p.int("REDIS_DB", &c.Redis.DB)
+
+	p.str("LAB_DRIVER", &c.Lab.Driver)
+
+	p.int("RATE_LIMIT_RPM", &c.RateLimit.RequestsPerMinute)
+	p.int("RATE_LIMIT_BURST", &c.RateLimit.Burst)
+
+	p.list("OUTBOX_WEBHOOK_URLS", &c.Outbox.WebhookURLs)
+	p.str("OUTBOX_BUS_DRIVER", &c.Outbox.Bus.Driver)
+	p.list("OUTBOX_BUS_BROKERS", &c.Outbox.Bus.Brokers)
+	p.str("OUTBOX_BUS_TOPIC", &c.Outbox.Bus.Topic)
+	p.str("OUTBOX_BUS_USERNAME", &c.Outbox.Bus.Username)
+	p.str("OUTBOX_BUS_PASSWORD", &c.Outbox.Bus.Password)
+	p.str("OUTBOX_BUS_SASL_MECHANISM", &c.Outbox.Bus.SASLMechanism)
+	p.bool("OUTBOX_BUS_TLS", &c.Outbox.Bus.TLS)
+	p.bool("OUTBOX_BUS_JETSTREAM", &c.Outbox.Bus.JetStream)
+	p.duration("OUTBOX_BUS_TIMEOUT", &c.Outbox.Bus.Timeout)
+	p.str("REALTIME_DRIVER", &c.Realtime.Driver)
+	p.list("REALTIME_SERVERS", &c.Realtime.Servers)
+	p.str("REALTIME_SUBJECT", &c.Realtime.Subject)
+	p.str("REALTIME_USERNAME", &c.Realtime.Username)
+	p.str("REALTIME_PASSWORD", &c.Realtime.Password)
+	p.bool("REALTIME_TLS", &c.Realtime.TLS)
+	p.duration("REALTIME_PRESENCE_INTERVAL", &c.Realtime.PresenceInterval)
+	p.str("SEARCH_DRIVER", &c.Search.Driver)
+	p.str("SEARCH_PATH", &c.Search.Path)
+	p.duration("SEARCH_REBUILD_INTERVAL", &c.Search.RebuildInterval)
+
+	p.str("GRPC_PORT", &c.GRPC.Port)
+	p.str("GRPC_AUTH_TOKEN", &c.GRPC.AuthToken)
+
+	p.list("CORS_ALLOWED_ORIGINS", &c.CORS.AllowedOrigins)
+	p.bool("CORS_ALLOW_CREDENTIALS", &c.CORS.AllowCredentials)
+	p.duration("CORS_MAX_AGE", &c.CORS.MaxAge)
+
+	p.duration("SECURITY_HSTS_MAX_AGE", &c.Security.HSTSMaxAge)
+	p.str("SECURITY_CSP", &c.Security.ContentSecurityPolicy)
+	p.bool("SECURITY_LOGIN_ALERTS", &c.Security.LoginAlerts)
+	p.bool("SECURITY_SINGLE_SESSION", &c.Security.SingleSession)
+	p.int("SECURITY_PASSWORD_HISTORY", &c.Security.PasswordHistory)
+	p.list("SECURITY_TRUSTED_PROXIES", &c.Security.TrustedProxies)
+	p.bool("SECURITY_BLOCK_UNKNOWN_COUNTRY", &c.Security.BlockUnknownCountry)
+	p.bool("SECURITY_REDACT_PII", &c.Security.RedactPII)
+
+	p.str("CAPTCHA_PROVIDER", &c.Captcha.Provider)
+	p.str("CAPTCHA_SITE_KEY", &c.Captcha.SiteKey)
+	p.str("CAPTCHA_SECRET", &c.Captcha.Secret)
+	p.int("CAPTCHA_LOGIN_FAILURE_THRESHOLD", &c.Captcha.LoginFailureThreshold)
+	p.duration("CAPTCHA_LOGIN_FAILURE_WINDOW", &c.Captcha.LoginFailureWindow)
+
+	p.int("OTP_LENGTH", &c.OTP.Length)
+	p.str("OTP_CHARSET", &c.OTP.Charset)
+	p.duration("OTP_TTL", &c.OTP.TTL)
+	p.int("OTP_MAX_ATTEMPTS", &c.OTP.MaxAttempts)
+	p.duration("OTP_TRUSTED_DEVICE_TTL", &c.OTP.TrustedDeviceTTL)
+	p.str("OTP_SMS_BASE_URL", &c.OTP.SMS.BaseURL)
+	p.str("OTP_SMS_ACCOUNT_SID", &c.OTP.SMS.AccountSID)
+	p.str("OTP_SMS_AUTH_TOKEN", &c.OTP.SMS.AuthToken)
+	p.str("OTP_SMS_FROM", &c.OTP.SMS.From)
+
+	p.duration("ACCOUNTS_DELETION_GRACE_PERIOD", &c.Accounts.DeletionGracePeriod)
+	p.duration("ACCOUNTS_USERNAME_CHANGE_INTERVAL", &c.Accounts.UsernameChangeInterval)
+	p.duration("ACCOUNTS_USERNAME_RESERVATION", &c.Accounts.UsernameReservation)
+	p.list("ACCOUNTS_SUPPORTED_LOCALES", &c.Accounts.SupportedLocales)
+	p.str("ACCOUNTS_DEFAULT_LOCALE", &c.Accounts.DefaultLocale)
+	p.str("ACCOUNTS_DEFAULT_TIMEZONE", &c.Accounts.DefaultTimezone)
+
+	p.str("MEDIA_DRIVER", &c.Media.Driver)
+	p.str("MEDIA_LOCAL_DIR", &c.Media.LocalDir)
+	p.str("MEDIA_PUBLIC_URL", &c.Media.PublicURL)
+	p.int("MEDIA_MAX_UPLOAD_SIZE", &c.Media.MaxUploadSize)
+	p.int("MEDIA_MAX_PACKAGE_SIZE", &c.Media.MaxPackageSize)
+	p.str("MEDIA_S3_ENDPOINT", &c.Media.S3.Endpoint)
+	p.str("MEDIA_S3_REGION", &c.Media.S3.Region)
+	p.str("MEDIA_S3_BUCKET", &c.Media.S3.Bucket)
+	p.str("MEDIA_S3_ACCESS_KEY_ID", &c.Media.S3.AccessKeyID)
+	p.str("MEDIA_S3_SECRET_ACCESS_KEY", &c.Media.S3.SecretAccessKey)
+	p.bool("MEDIA_S3_PATH_STYLE", &c.Media.S3.PathStyle)
+	p.str("BADGES_ISSUER_NAME", &c.Badges.IssuerName)
+	p.str("BADGES_ISSUER_URL", &c.Badges.IssuerURL)
+	p.str("BADGES_SIGNING_KEY", &c.Badges.SigningKey)
+	p.str("TELEGRAM_BOT_TOKEN", &c.Telegram.BotToken)
+	p.str("TELEGRAM_BOT_USERNAME", &c.Telegram.BotUsername)
+	p.str("TELEGRAM_WEBHOOK_SECRET", &c.Telegram.WebhookSecret)
+	p.str("TELEGRAM_API_URL", &c.Telegram.APIURL)
+	p.duration("TELEGRAM_DEADLINE_REMINDER", &c.Telegram.DeadlineReminder)
+	p.str("WEBPUSH_VAPID_PRIVATE_KEY", &c.WebPush.VAPIDPrivateKey)
+	p.str("WEBPUSH_SUBJECT", &c.WebPush.Subject)
+	p.duration("WEBPUSH_TTL", &c.WebPush.TTL)
+	p.duration("WEBPUSH_DEADLINE_REMINDER", &c.WebPush.DeadlineReminder)
+	p.bool("DIGEST_ENABLED", &c.Digest.Enabled)
+
+	p.duration("CLEANUP_INTERVAL", &c.Cleanup.Interval)
+	p.duration("CLEANUP_EVENT_RETENTION", &c.Cleanup.EventRetention)
+	p.duration("CLEANUP_IDEMPOTENCY_RETENTION", &c.Cleanup.IdempotencyRetention)
+
+	p.duration("RETENTION_INTERVAL", &c.Retention.Interval)
+	p.bool("RETENTION_DRY_RUN", &c.Retention.DryRun)
+	p.int("RETENTION_AUDIT_LOG_DAYS", &c.Retention.AuditLogDays)
+	p.str("RETENTION_AUDIT_LOG_ACTION", &c.Retention.AuditLogAction)
+	p.int("RETENTION_LOGIN_HISTORY_DAYS", &c.Retention.LoginHistoryDays)
+	p.str("RETENTION_LOGIN_HISTORY_ACTION", &c.Retention.LoginHistoryAction)
+	p.int("RETENTION_INACTIVE_ACCOUNT_DAYS", &c.Retention.InactiveAccountDays)
+	p.str("RETENTION_INACTIVE_ACCOUNT_ACTION", &c.Retention.InactiveAccountAction)
+
+	p.bool("SEED_DEMO_DATA", &c.Seed.DemoData)
+	p.str("SEED_DEMO_PASSWORD", &c.Seed.DemoPassword)
+
+	p.duration("SECRETS_REFRESH_INTERVAL", &c.Secrets.RefreshInterval)
+	p.str("VAULT_ADDR", &c.Secrets.Vault.Addr)
+	p.str("VAULT_TOKEN", &c.Secrets.Vault.Token)
+	p.str("VAULT_TOKEN_FILE", &c.Secrets.Vault.TokenFile)
+	p.str("VAULT_SECRET_PATH", &c.Secrets.Vault.Path)
+
+	if len(p.problems) > 0 {
+		return &ValidationError{Problems: p.problems}
+	}
+	return nil
+}
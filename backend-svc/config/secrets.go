@@ -0,0 +1,147 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// SecretsConfig описывает внешние источники секретов и период их перечитывания
+type SecretsConfig struct {
+	RefreshInterval time.Duration `yaml:"refresh_interval"`
+	Vault           VaultConfig   `yaml:"vault"`
+}
+
+// VaultConfig — доступ к KV v2 хранилищу HashiCorp Vault
+type VaultConfig struct {
+	Addr      string `yaml:"addr"`
+	Token     string `yaml:"token"`
+	TokenFile string `yaml:"token_file"`
+	// Path — путь к секрету вместе с mount, например "secret/data/lms/backend"
+	Path string `yaml:"path"`
+}
+
+// Enabled сообщает, настроен ли Vault
+func (v VaultConfig) Enabled() bool {
+	return v.Addr != "" && v.Path != ""
+}
+
+// secretField связывает секрет конфигурации с переменной окружения и ключом в Vault.
+// Для каждой переменной NAME поддерживается NAME_FILE с путем к смонтированному файлу.
+type secretField struct {
+	env      string
+	vaultKey string
+	target   func(c *Config) *string
+}
+
+var secretFields = []secretField{
+	{env: "DATABASE_DSN", vaultKey: "database_dsn", target: func(c *Config) *string { return &c.Database.DSN }},
+	{env: "JWT_SECRET", vaultKey: "jwt_secret", target: func(c *Config) *string { return &c.JWT.Secret }},
+	{env: "JWT_TEMP_SECRET", vaultKey: "jwt_temp_secret", target: func(c *Config) *string { return &c.JWT.TempSecret }},
+	{env: "SMTP_PASSWORD", vaultKey: "smtp_password", target: func(c *Config) *string { return &c.SMTP.Password }},
+	{env: "GRPC_AUTH_TOKEN", vaultKey: "grpc_auth_token", target: func(c *Config) *string { return &c.GRPC.AuthToken }},
+	{env: "CAPTCHA_SECRET", vaultKey: "captcha_secret", target: func(c *Config) *string { return &c.Captcha.Secret }},
+	{env: "OTP_SMS_AUTH_TOKEN", vaultKey: "otp_sms_auth_token", target: func(c *Config) *string { return &c.OTP.SMS.AuthToken }},
+	{env: "MEDIA_S3_SECRET_ACCESS_KEY", vaultKey: "media_s3_secret_access_key", target: func(c *Config) *string { return &c.Media.S3.SecretAccessKey }},
+	{env: "BADGES_SIGNING_KEY", vaultKey: "badges_signing_key", target: func(c *Config) *string { return &c.Badges.SigningKey }},
+	{env: "TELEGRAM_BOT_TOKEN", vaultKey: "telegram_bot_token", target: func(c *Config) *string { return &c.Telegram.BotToken }},
+	{env: "TELEGRAM_WEBHOOK_SECRET", vaultKey: "telegram_webhook_secret", target: func(c *Config) *string { return &c.Telegram.WebhookSecret }},
+	{env: "OUTBOX_BUS_PASSWORD", vaultKey: "outbox_bus_password", target: func(c *Config) *string { return &c.Outbox.Bus.Password }},
+	{env: "REALTIME_PASSWORD", vaultKey: "realtime_password", target: func(c *Config) *string { return &c.Realtime.Password }},
+	{env: "WEBPUSH_VAPID_PRIVATE_KEY", vaultKey: "webpush_vapid_private_key", target: func(c *Config) *string { return &c.WebPush.VAPIDPrivateKey }},
+}
+
+// RefreshSecrets перечитывает секреты из файлов (*_FILE) и Vault поверх текущих значений.
+// Возвращает true, если хотя бы одно значение изменилось.
+func (c *Config) RefreshSecrets(ctx context.Context) (bool, error) {
+	changed := false
+	set := func(target *string, value string) {
+		if value != "" && *target != value {
+			*target = value
+			changed = true
+		}
+	}
+
+	for _, field := range secretFields {
+		path := os.Getenv(field.env + "_FILE")
+		if path == "" {
+			continue
+		}
+		value, err := readSecretFile(path)
+		if err != nil {
+			return changed, fmt.Errorf("config: read %s_FILE: %w", field.env, err)
+		}
+		set(field.target(c), value)
+	}
+
+	if c.Secrets.Vault.Enabled() {
+		values, err := c.Secrets.Vault.read(ctx)
+		if err != nil {
+			return changed, fmt.Errorf("config: read vault secret %s: %w", c.Secrets.Vault.Path, err)
+		}
+		for _, field := range secretFields {
+			set(field.target(c), values[field.vaultKey])
+		}
+	}
+
+	return changed, nil
+}
+
+func readSecretFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// read загружает секрет из KV v2 и возвращает его строковые поля
+func (v VaultConfig) read(ctx context.Context) (map[string]string, error) {
+	token := v.Token
+	if v.TokenFile != "" {
+		fileToken, err := readSecretFile(v.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("read token file: %w", err)
+		}
+		token = fileToken
+	}
+
+	url := strings.TrimRight(v.Addr, "/") + "/v1/" + strings.TrimLeft(v.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("X-Vault-Token", token)
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Data struct {
+			Data map[string]interface{} `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+
+	values := make(map[string]string, len(body.Data.Data))
+	for key, value := range body.Data.Data {
+		if str, ok := value.(string); ok {
+			values[key] = str
+		}
+	}
+	return values, nil
+}
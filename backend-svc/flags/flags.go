@@ -0,0 +1,130 @@
+package flags
+
+import (
+	"hash/fnv"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Service вычисляет флаги функциональности для пользователей.
+// Флаги загружаются из хранилища целиком и кэшируются на TTL.
+type Service struct {
+	store storage.Storage
+	ttl   time.Duration
+
+	mu       sync.RWMutex
+	flags    map[string]models.FeatureFlag
+	loadedAt time.Time
+}
+
+// New создает сервис флагов с заданным временем жизни кэша
+func New(store storage.Storage, ttl time.Duration) *Service {
+	return &Service{store: store, ttl: ttl}
+}
+
+// Invalidate сбрасывает кэш; вызывается после изменения флагов через API
+func (s *Service) Invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.loadedAt = time.Time{}
+}
+
+// snapshot возвращает актуальный набор флагов, перечитывая его при истечении TTL.
+// При ошибке хранилища используется последний успешно загруженный набор.
+func (s *Service) snapshot() map[string]models.FeatureFlag {
+	s.mu.RLock()
+	if time.Since(s.loadedAt) < s.ttl {
+		defer s.mu.RUnlock()
+		return s.flags
+	}
+	s.mu.RUnlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if time.Since(s.loadedAt) < s.ttl {
+		return s.flags
+	}
+
+	list, err := s.store.GetFeatureFlags()
+	if err != nil {
+		log.Printf("Feature flags: failed to reload, using cached values: %v", err)
+		return s.flags
+	}
+
+	flags := make(map[string]models.FeatureFlag, len(list))
+	for _, flag := range list {
+		flags[flag.Key] = flag
+	}
+	s.flags = flags
+	s.loadedAt = time.Now()
+	return flags
+}
+
+// List возвращает все флаги
+func (s *Service) List() []models.FeatureFlag {
+	flags := s.snapshot()
+	list := make([]models.FeatureFlag, 0, len(flags))
+	for _, flag := range flags {
+		list = append(list, flag)
+	}
+	return list
+}
+
+// Enabled сообщает, включен ли флаг для пользователя. Флаг включен, если он активен и
+// пользователь либо состоит в одной из целевых групп, либо попадает в процент раскатки.
+// Неизвестный флаг считается выключенным.
+func (s *Service) Enabled(key string, userID int) bool {
+	flag, ok := s.snapshot()[key]
+	if !ok {
+		return false
+	}
+	return s.evaluate(flag, userID)
+}
+
+// EvaluateAll возвращает состояние всех флагов для пользователя
+func (s *Service) EvaluateAll(userID int) map[string]bool {
+	flags := s.snapshot()
+	result := make(map[string]bool, len(flags))
+	for key, flag := range flags {
+		result[key] = s.evaluate(flag, userID)
+	}
+	return result
+}
+
+func (s *Service) evaluate(flag models.FeatureFlag, userID int) bool {
+	if !flag.Enabled {
+		return false
+	}
+
+	if flag.RolloutPercentage >= 100 {
+		return true
+	}
+
+	if len(flag.TargetGroups) > 0 && userID != 0 {
+		groups, err := s.store.GetUserGroups(userID)
+		if err != nil {
+			log.Printf("Feature flags: failed to load groups for user %d: %v", userID, err)
+		}
+		for _, group := range groups {
+			for _, target := range flag.TargetGroups {
+				if group == target {
+					return true
+				}
+			}
+		}
+	}
+
+	return flag.RolloutPercentage > 0 && bucket(flag.Key, userID) < flag.RolloutPercentage
+}
+
+// bucket детерминированно распределяет пользователя по 100 корзинам для конкретного флага,
+// чтобы при увеличении процента раскатки уже включенные пользователи не выпадали
+func bucket(key string, userID int) int {
+	h := fnv.New32a()
+	h.Write([]byte(key + ":" + strconv.Itoa(userID)))
+	return int(h.Sum32() % 100)
+}
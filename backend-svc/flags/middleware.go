@@ -0,0 +1,24 @@
+package flags
+
+import (
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"net/http"
+)
+
+// Require возвращает middleware, скрывающее маршрут (404) от пользователей,
+// для которых флаг выключен. Должно подключаться после JWT-аутентификации.
+func (s *Service) Require(key string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !s.Enabled(key, c.GetInt("userID")) {
+			c.AbortWithStatusJSON(http.StatusNotFound, models.ErrorResponse{Error: "Not found"})
+			return
+		}
+		c.Next()
+	}
+}
+
+// EnabledFor — помощник для обработчиков, ветвящих поведение по флагу
+func (s *Service) EnabledFor(c *gin.Context, key string) bool {
+	return s.Enabled(key, c.GetInt("userID"))
+}
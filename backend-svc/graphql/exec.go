@@ -0,0 +1,363 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// Ограничения, защищающие от слишком дорогих запросов
+const (
+	maxDepth  = 8
+	maxFields = 500
+	// maxSpreads ограничивает развертывания фрагментов: фрагменты, несколько раз
+	// разворачивающие друг друга, размножают повторяющиеся поля экспоненциально
+	maxSpreads = 500
+)
+
+// Object — объектный тип схемы: набор полей с резолверами
+type Object struct {
+	Name   string
+	Fields map[string]*Field
+}
+
+// Field — поле объектного типа. Type задается для полей, возвращающих объект или список
+// объектов; для скалярных полей Type равен nil. Если Resolve не задан, значение берется
+// из одноименного поля исходной структуры (по json-тегу или имени без учета регистра).
+type Field struct {
+	Type    *Object
+	Resolve func(p ResolveParams) (interface{}, error)
+}
+
+// ResolveParams — параметры вызова резолвера
+type ResolveParams struct {
+	Context context.Context
+	Source  interface{}
+	Args    map[string]interface{}
+}
+
+// Schema — схема, поддерживающая только операции чтения (query)
+type Schema struct {
+	Query *Object
+}
+
+// Request — тело GraphQL-запроса по HTTP
+type Request struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// Response — результат выполнения: данные и ошибки полей
+type Response struct {
+	Data   interface{} `json:"data"`
+	Errors []Error     `json:"errors,omitempty"`
+}
+
+// Error — ошибка выполнения с путем к полю, в котором она возникла
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+// Execute разбирает и выполняет запрос. Ошибки разбора и валидации возвращаются
+// в Response без данных, ошибки резолверов — вместе с частичными данными.
+func (s *Schema) Execute(ctx context.Context, req Request) Response {
+	doc, err := parse(req.Query)
+	if err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	op, err := doc.operation(req.OperationName)
+	if err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+	if op.kind != "query" {
+		return Response{Errors: []Error{{Message: fmt.Sprintf("%s operations are not supported", op.kind)}}}
+	}
+
+	vars, err := coerceVariables(op.variables, req.Variables)
+	if err != nil {
+		return Response{Errors: []Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{ctx: ctx, doc: doc, vars: vars}
+	data := e.selectionSet(s.Query, nil, op.selection, nil, 1)
+	return Response{Data: data, Errors: e.errors}
+}
+
+func (d *document) operation(name string) (*operation, error) {
+	if name == "" {
+		if len(d.operations) > 1 {
+			return nil, fmt.Errorf("operationName is required when the document has several operations")
+		}
+		return d.operations[0], nil
+	}
+	for _, op := range d.operations {
+		if op.name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation %q", name)
+}
+
+func coerceVariables(defs []variableDef, given map[string]interface{}) (map[string]interface{}, error) {
+	vars := make(map[string]interface{}, len(defs))
+	for _, def := range defs {
+		v, ok := given[def.name]
+		if !ok || v == nil {
+			if def.defaultValue != nil {
+				v = def.defaultValue
+			} else if strings.HasSuffix(def.typ, "!") {
+				return nil, fmt.Errorf("variable $%s of type %s is required", def.name, def.typ)
+			}
+		}
+		// Числа из JSON приходят как float64; целые приводятся к int, как и литералы запроса
+		if f, isFloat := v.(float64); isFloat && f == float64(int(f)) && strings.HasPrefix(def.typ, "Int") {
+			v = int(f)
+		}
+		vars[def.name] = v
+	}
+	return vars, nil
+}
+
+type executor struct {
+	ctx     context.Context
+	doc     *document
+	vars    map[string]interface{}
+	errors  []Error
+	fields  int
+	spreads int
+}
+
+func (e *executor) fail(path []interface{}, format string, args ...interface{}) {
+	e.errors = append(e.errors, Error{Message: fmt.Sprintf(format, args...), Path: append([]interface{}(nil), path...)})
+}
+
+// selectionSet выполняет набор полей над source и возвращает упорядоченный результат
+func (e *executor) selectionSet(obj *Object, source interface{}, sels []selection, path []interface{}, depth int) *orderedMap {
+	result := &orderedMap{}
+	if depth > maxDepth {
+		e.fail(path, "query is nested deeper than %d levels", maxDepth)
+		return result
+	}
+	e.collect(obj, source, sels, path, depth, result, map[string]bool{})
+	return result
+}
+
+func (e *executor) collect(obj *Object, source interface{}, sels []selection, path []interface{}, depth int, result *orderedMap, visited map[string]bool) {
+	for _, sel := range sels {
+		switch {
+		case sel.fragmentName != "":
+			e.spreads++
+			if e.spreads > maxSpreads {
+				if e.spreads == maxSpreads+1 {
+					e.fail(path, "query spreads fragments more than %d times", maxSpreads)
+				}
+				continue
+			}
+			frag, ok := e.doc.fragments[sel.fragmentName]
+			if !ok {
+				e.fail(path, "unknown fragment %q", sel.fragmentName)
+				continue
+			}
+			if visited[sel.fragmentName] {
+				e.fail(path, "fragment %q spreads itself", sel.fragmentName)
+				continue
+			}
+			if frag.typeCondition == obj.Name {
+				visited[sel.fragmentName] = true
+				e.collect(obj, source, frag.selection, path, depth, result, visited)
+				delete(visited, sel.fragmentName)
+			}
+		case sel.inline != nil:
+			if sel.typeCondition == "" || sel.typeCondition == obj.Name {
+				e.collect(obj, source, sel.inline, path, depth, result, visited)
+			}
+		default:
+			if result.has(sel.field.alias) {
+				continue
+			}
+			result.set(sel.field.alias, e.field(obj, source, sel.field, append(path, sel.field.alias), depth))
+		}
+	}
+}
+
+func (e *executor) field(obj *Object, source interface{}, f *field, path []interface{}, depth int) interface{} {
+	e.fields++
+	if e.fields > maxFields {
+		if e.fields == maxFields+1 {
+			e.fail(path, "query selects more than %d fields", maxFields)
+		}
+		return nil
+	}
+	if f.name == "__typename" {
+		return obj.Name
+	}
+
+	def, ok := obj.Fields[f.name]
+	if !ok {
+		e.fail(path, "cannot query field %q on type %q", f.name, obj.Name)
+		return nil
+	}
+	if def.Type == nil && f.selection != nil {
+		e.fail(path, "field %q of type %q must not have a selection", f.name, obj.Name)
+		return nil
+	}
+	if def.Type != nil && f.selection == nil {
+		e.fail(path, "field %q of type %q must have a selection of subfields", f.name, def.Type.Name)
+		return nil
+	}
+
+	var value interface{}
+	var err error
+	if def.Resolve != nil {
+		args, argErr := e.arguments(f.arguments)
+		if argErr != nil {
+			e.fail(path, "%v", argErr)
+			return nil
+		}
+		value, err = def.Resolve(ResolveParams{Context: e.ctx, Source: source, Args: args})
+	} else {
+		value, err = defaultResolve(source, f.name)
+	}
+	if err != nil {
+		e.fail(path, "%v", err)
+		return nil
+	}
+
+	if def.Type == nil {
+		return value
+	}
+	return e.complete(def.Type, value, f.selection, path, depth)
+}
+
+// complete применяет вложенный набор полей к объекту или к каждому элементу списка
+func (e *executor) complete(obj *Object, value interface{}, sels []selection, path []interface{}, depth int) interface{} {
+	rv := reflect.ValueOf(value)
+	if !rv.IsValid() || ((rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Map) && rv.IsNil()) {
+		return nil
+	}
+	if rv.Kind() == reflect.Slice {
+		items := make([]interface{}, rv.Len())
+		for i := range items {
+			items[i] = e.selectionSet(obj, rv.Index(i).Interface(), sels, append(path, i), depth+1)
+		}
+		return items
+	}
+	return e.selectionSet(obj, value, sels, path, depth+1)
+}
+
+func (e *executor) arguments(args map[string]value) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(args))
+	for name, v := range args {
+		val, err := e.resolveValue(v)
+		if err != nil {
+			return nil, err
+		}
+		resolved[name] = val
+	}
+	return resolved, nil
+}
+
+func (e *executor) resolveValue(v value) (interface{}, error) {
+	switch {
+	case v.variable != "":
+		val, ok := e.vars[v.variable]
+		if !ok {
+			return nil, fmt.Errorf("variable $%s is not defined", v.variable)
+		}
+		return val, nil
+	case v.list != nil:
+		list := make([]interface{}, len(v.list))
+		for i, item := range v.list {
+			val, err := e.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = val
+		}
+		return list, nil
+	case v.object != nil:
+		obj := make(map[string]interface{}, len(v.object))
+		for k, item := range v.object {
+			val, err := e.resolveValue(item)
+			if err != nil {
+				return nil, err
+			}
+			obj[k] = val
+		}
+		return obj, nil
+	}
+	return v.literal, nil
+}
+
+// defaultResolve находит поле структуры по json-тегу или по имени без учета регистра
+func defaultResolve(source interface{}, name string) (interface{}, error) {
+	rv := reflect.Indirect(reflect.ValueOf(source))
+	if rv.Kind() == reflect.Map {
+		if v := rv.MapIndex(reflect.ValueOf(name)); v.IsValid() {
+			return v.Interface(), nil
+		}
+		return nil, nil
+	}
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("cannot resolve field %q on %T", name, source)
+	}
+
+	rt := rv.Type()
+	for i := 0; i < rt.NumField(); i++ {
+		sf := rt.Field(i)
+		tag := strings.Split(sf.Tag.Get("json"), ",")[0]
+		if tag == name || (tag == "" && strings.EqualFold(sf.Name, name)) {
+			return rv.Field(i).Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("cannot resolve field %q on %T", name, source)
+}
+
+// orderedMap сохраняет порядок полей из запроса при сериализации в JSON
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) has(key string) bool {
+	_, ok := m.values[key]
+	return ok
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if m.values == nil {
+		m.values = make(map[string]interface{})
+	}
+	m.keys = append(m.keys, key)
+	m.values[key] = value
+}
+
+// MarshalJSON сериализует поля в порядке их выбора в запросе
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		k, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(k)
+		buf.WriteByte(':')
+		buf.Write(v)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,179 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+type testTask struct {
+	ID    int    `json:"id"`
+	Title string `json:"title"`
+}
+
+type testCourse struct {
+	ID    int        `json:"id"`
+	Name  string     `json:"name"`
+	Tasks []testTask `json:"tasks"`
+}
+
+func testSchema() *Schema {
+	task := &Object{Name: "Task", Fields: map[string]*Field{"id": {}, "title": {}}}
+	course := &Object{Name: "Course", Fields: map[string]*Field{"id": {}, "name": {}, "tasks": {Type: task}}}
+	// Course.self позволяет строить запросы произвольной глубины
+	course.Fields["self"] = &Field{Type: course, Resolve: func(p ResolveParams) (interface{}, error) {
+		return p.Source, nil
+	}}
+	courses := []testCourse{
+		{ID: 1, Name: "SQLi", Tasks: []testTask{{1, "Union"}, {2, "Blind"}}},
+		{ID: 2, Name: "XSS", Tasks: []testTask{{3, "Reflected"}}},
+	}
+	query := &Object{Name: "Query", Fields: map[string]*Field{
+		"courses": {Type: course, Resolve: func(ResolveParams) (interface{}, error) { return courses, nil }},
+		"course": {Type: course, Resolve: func(p ResolveParams) (interface{}, error) {
+			id, ok := p.Args["id"].(int)
+			if !ok {
+				return nil, errors.New("id must be an integer")
+			}
+			for _, c := range courses {
+				if c.ID == id {
+					return c, nil
+				}
+			}
+			return nil, fmt.Errorf("course %d not found", id)
+		}},
+		"echo": {Resolve: func(p ResolveParams) (interface{}, error) { return p.Args["value"], nil }},
+	}}
+	return &Schema{Query: query}
+}
+
+func execute(t *testing.T, req Request) (string, []Error) {
+	t.Helper()
+	resp := testSchema().Execute(context.Background(), req)
+	if resp.Data == nil {
+		return "", resp.Errors
+	}
+	data, err := json.Marshal(resp.Data)
+	if err != nil {
+		t.Fatalf("marshal: %v", err)
+	}
+	return string(data), resp.Errors
+}
+
+func TestExecute(t *testing.T) {
+	tests := []struct {
+		name string
+		req  Request
+		want string
+	}{
+		{"fields keep query order", Request{Query: "{ courses { name id } }"},
+			`{"courses":[{"name":"SQLi","id":1},{"name":"XSS","id":2}]}`},
+		{"aliases and arguments", Request{Query: "{ a: course(id: 2) { name } b: course(id: 1) { name } }"},
+			`{"a":{"name":"XSS"},"b":{"name":"SQLi"}}`},
+		{"nested lists", Request{Query: "{ course(id: 1) { tasks { title } } }"},
+			`{"course":{"tasks":[{"title":"Union"},{"title":"Blind"}]}}`},
+		{"variables from JSON are coerced to int", Request{Query: "query ($id: Int!) { course(id: $id) { id } }",
+			Variables: map[string]interface{}{"id": float64(2)}}, `{"course":{"id":2}}`},
+		{"default variable value", Request{Query: "query ($id: Int = 1) { course(id: $id) { id } }"},
+			`{"course":{"id":1}}`},
+		{"fragments and inline fragments", Request{Query: "{ course(id: 2) { ...C ... on Course { id } } } fragment C on Course { name }"},
+			`{"course":{"name":"XSS","id":2}}`},
+		{"duplicate fields are merged", Request{Query: "{ course(id: 2) { id id ... { id } } }"},
+			`{"course":{"id":2}}`},
+		{"typename", Request{Query: "{ course(id: 1) { __typename } }"}, `{"course":{"__typename":"Course"}}`},
+		{"list and object arguments", Request{Query: `{ echo(value: {a: [1, "x", true, null]}) }`},
+			`{"echo":{"a":[1,"x",true,null]}}`},
+		{"operation by name", Request{Query: "query A { course(id: 1) { id } } query B { course(id: 2) { id } }", OperationName: "B"},
+			`{"course":{"id":2}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, errs := execute(t, tt.req)
+			if len(errs) > 0 {
+				t.Fatalf("errors: %v", errs)
+			}
+			if got != tt.want {
+				t.Errorf("data = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExecuteErrors(t *testing.T) {
+	deep := func(n int) string {
+		return "{ course(id: 1) " + strings.Repeat("{ self ", n) + "{ id }" + strings.Repeat(" }", n) + " }"
+	}
+	// Разные псевдонимы не склеиваются, поэтому каждый считается отдельным полем
+	var wide strings.Builder
+	for i := 0; i <= maxFields; i++ {
+		fmt.Fprintf(&wide, "f%d: id ", i)
+	}
+	// Каждый фрагмент дважды разворачивает следующий: без ограничения 2^20 развертываний
+	var fanOut strings.Builder
+	fanOut.WriteString("{ course(id: 1) { ...F0 } }")
+	for i := 0; i < 20; i++ {
+		fmt.Fprintf(&fanOut, " fragment F%d on Course { ...F%d ...F%d }", i, i+1, i+1)
+	}
+	fanOut.WriteString(" fragment F20 on Course { id }")
+
+	tests := []struct {
+		name string
+		req  Request
+		// partial — данные возвращаются вместе с ошибкой
+		partial bool
+		want    string
+	}{
+		{"syntax error", Request{Query: "{ courses { id }"}, false, "unterminated selection set"},
+		{"mutation", Request{Query: "mutation { courses { id } }"}, false, "mutation operations are not supported"},
+		{"ambiguous operation", Request{Query: "query A { courses { id } } query B { courses { id } }"}, false, "operationName is required"},
+		{"unknown operation", Request{Query: "query A { courses { id } }", OperationName: "B"}, false, "unknown operation"},
+		{"missing required variable", Request{Query: "query ($id: Int!) { course(id: $id) { id } }"}, false, "is required"},
+		{"undefined variable", Request{Query: "{ course(id: $id) { id } }"}, true, "variable $id is not defined"},
+		{"unknown field", Request{Query: "{ courses { secret } }"}, true, `cannot query field "secret"`},
+		{"selection on scalar", Request{Query: "{ courses { id { x } } }"}, true, "must not have a selection"},
+		{"object without selection", Request{Query: "{ courses }"}, true, "must have a selection of subfields"},
+		{"resolver error", Request{Query: "{ course(id: 9) { id } }"}, true, "course 9 not found"},
+		{"unknown fragment", Request{Query: "{ courses { ...Missing } }"}, true, `unknown fragment "Missing"`},
+		{"fragment cycle", Request{Query: "{ course(id: 1) { ...A } } fragment A on Course { ...B } fragment B on Course { ...A }"},
+			true, "spreads itself"},
+		{"too deep", Request{Query: deep(maxDepth)}, true, "nested deeper than"},
+		{"too many fields", Request{Query: "{ course(id: 1) { " + wide.String() + "} }"}, true, "more than 500 fields"},
+		{"fragment fan-out", Request{Query: fanOut.String()}, true, "spreads fragments more than"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := testSchema().Execute(context.Background(), tt.req)
+			if (resp.Data != nil) != tt.partial {
+				t.Errorf("data = %v, want partial = %v", resp.Data, tt.partial)
+			}
+			for _, e := range resp.Errors {
+				if strings.Contains(e.Message, tt.want) {
+					return
+				}
+			}
+			t.Errorf("errors = %v, want one containing %q", resp.Errors, tt.want)
+		})
+	}
+}
+
+func TestExecuteDepthWithinLimit(t *testing.T) {
+	// Корень, course и maxDepth-2 уровня self
+	query := "{ course(id: 1) " + strings.Repeat("{ self ", maxDepth-2) + "{ id }" + strings.Repeat(" }", maxDepth-2) + " }"
+	if _, errs := execute(t, Request{Query: query}); len(errs) > 0 {
+		t.Fatalf("errors: %v", errs)
+	}
+}
+
+func TestErrorPath(t *testing.T) {
+	_, errs := execute(t, Request{Query: "{ courses { tasks { nope } } }"})
+	if len(errs) == 0 {
+		t.Fatal("expected errors")
+	}
+	path, _ := json.Marshal(errs[0].Path)
+	if string(path) != `["courses",0,"tasks",0,"nope"]` {
+		t.Errorf("path = %s", path)
+	}
+}
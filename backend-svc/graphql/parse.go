@@ -0,0 +1,582 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// document — разобранный GraphQL-запрос: операции и именованные фрагменты
+type document struct {
+	operations []*operation
+	fragments  map[string]*fragment
+}
+
+type operation struct {
+	kind      string // поддерживается только "query"
+	name      string
+	variables []variableDef
+	selection []selection
+}
+
+type variableDef struct {
+	name         string
+	typ          string
+	defaultValue interface{}
+}
+
+type fragment struct {
+	typeCondition string
+	selection     []selection
+}
+
+// selection — поле, разворачиваемый фрагмент (...Name) или inline-фрагмент (... on Type)
+type selection struct {
+	field         *field
+	fragmentName  string
+	typeCondition string
+	inline        []selection
+}
+
+type field struct {
+	alias     string
+	name      string
+	arguments map[string]value
+	selection []selection
+}
+
+// value — литерал аргумента; переменные подставляются при выполнении
+type value struct {
+	literal  interface{}
+	variable string
+	list     []value
+	object   map[string]value
+}
+
+// Ограничения разбора: парсер рекурсивный, поэтому вложенность текста ограничивается
+// до выполнения, иначе глубоко вложенный запрос исчерпает стек раньше проверки maxDepth
+const (
+	maxQuerySize = 64 << 10
+	// maxNesting — глубина вложенности наборов полей, фрагментов, списков и объектов в тексте
+	maxNesting = 32
+)
+
+// parse разбирает текст запроса в document
+func parse(source string) (*document, error) {
+	if len(source) > maxQuerySize {
+		return nil, fmt.Errorf("query is larger than %d bytes", maxQuerySize)
+	}
+	p := &parser{lex: lexer{src: source}}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+
+	doc := &document{fragments: make(map[string]*fragment)}
+	for p.tok.kind != tokEOF {
+		switch {
+		case p.tok.is(tokPunct, "{"):
+			sel, err := p.parseSelectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, &operation{kind: "query", selection: sel})
+		case p.tok.is(tokName, "fragment"):
+			name, frag, err := p.parseFragment()
+			if err != nil {
+				return nil, err
+			}
+			doc.fragments[name] = frag
+		case p.tok.kind == tokName:
+			op, err := p.parseOperation()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		default:
+			return nil, p.errorf("unexpected %q", p.tok.text)
+		}
+	}
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("document contains no operations")
+	}
+	return doc, nil
+}
+
+type parser struct {
+	lex lexer
+	tok token
+	// depth — текущая вложенность конструкций, см. maxNesting
+	depth int
+}
+
+// enter учитывает вход во вложенную конструкцию; парная ей leave вызывается через defer
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxNesting {
+		return p.errorf("query is nested deeper than %d levels", maxNesting)
+	}
+	return nil
+}
+
+func (p *parser) leave() {
+	p.depth--
+}
+
+func (p *parser) advance() error {
+	tok, err := p.lex.next()
+	if err != nil {
+		return err
+	}
+	p.tok = tok
+	return nil
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("syntax error at position %d: %s", p.tok.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *parser) expect(kind tokenKind, text string) error {
+	if !p.tok.is(kind, text) {
+		return p.errorf("expected %q, got %q", text, p.tok.text)
+	}
+	return p.advance()
+}
+
+func (p *parser) expectName() (string, error) {
+	if p.tok.kind != tokName {
+		return "", p.errorf("expected name, got %q", p.tok.text)
+	}
+	name := p.tok.text
+	return name, p.advance()
+}
+
+func (p *parser) parseOperation() (*operation, error) {
+	op := &operation{kind: p.tok.text}
+	if err := p.advance(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokName {
+		op.name = p.tok.text
+		if err := p.advance(); err != nil {
+			return nil, err
+		}
+	}
+	if p.tok.is(tokPunct, "(") {
+		vars, err := p.parseVariableDefs()
+		if err != nil {
+			return nil, err
+		}
+		op.variables = vars
+	}
+	sel, err := p.parseSelectionSet()
+	if err != nil {
+		return nil, err
+	}
+	op.selection = sel
+	return op, nil
+}
+
+func (p *parser) parseVariableDefs() ([]variableDef, error) {
+	if err := p.expect(tokPunct, "("); err != nil {
+		return nil, err
+	}
+	var defs []variableDef
+	for !p.tok.is(tokPunct, ")") {
+		if err := p.expect(tokPunct, "$"); err != nil {
+			return nil, err
+		}
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokPunct, ":"); err != nil {
+			return nil, err
+		}
+		typ, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		def := variableDef{name: name, typ: typ}
+		if p.tok.is(tokPunct, "=") {
+			if err := p.advance(); err != nil {
+				return nil, err
+			}
+			v, err := p.parseValue()
+			if err != nil {
+				return nil, err
+			}
+			def.defaultValue = v.literal
+		}
+		defs = append(defs, def)
+	}
+	return defs, p.advance()
+}
+
+// parseType читает ссылку на тип (Int, Int!, [String!]!) как строку; типы переменных
+// проверяются только на обязательность
+func (p *parser) parseType() (string, error) {
+	if err := p.enter(); err != nil {
+		return "", err
+	}
+	defer p.leave()
+
+	var b strings.Builder
+	if p.tok.is(tokPunct, "[") {
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+		inner, err := p.parseType()
+		if err != nil {
+			return "", err
+		}
+		if err := p.expect(tokPunct, "]"); err != nil {
+			return "", err
+		}
+		b.WriteString("[" + inner + "]")
+	} else {
+		name, err := p.expectName()
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(name)
+	}
+	if p.tok.is(tokPunct, "!") {
+		b.WriteString("!")
+		if err := p.advance(); err != nil {
+			return "", err
+		}
+	}
+	return b.String(), nil
+}
+
+func (p *parser) parseFragment() (string, *fragment, error) {
+	if err := p.advance(); err != nil {
+		return "", nil, err
+	}
+	name, err := p.expectName()
+	if err != nil {
+		return "", nil, err
+	}
+	if err := p.expect(tokName, "on"); err != nil {
+		return "", nil, err
+	}
+	typeCondition, err := p.expectName()
+	if err != nil {
+		return "", nil, err
+	}
+	sel, err := p.parseSelectionSet()
+	if err != nil {
+		return "", nil, err
+	}
+	return name, &fragment{typeCondition: typeCondition, selection: sel}, nil
+}
+
+func (p *parser) parseSelectionSet() ([]selection, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer p.leave()
+
+	if err := p.expect(tokPunct, "{"); err != nil {
+		return nil, err
+	}
+	var sels []selection
+	for !p.tok.is(tokPunct, "}") {
+		if p.tok.kind == tokEOF {
+			return nil, p.errorf("unterminated selection set")
+		}
+		sel, err := p.parseSelection()
+		if err != nil {
+			return nil, err
+		}
+		sels = append(sels, sel)
+	}
+	if len(sels) == 0 {
+		return nil, p.errorf("empty selection set")
+	}
+	return sels, p.advance()
+}
+
+func (p *parser) parseSelection() (selection, error) {
+	if p.tok.is(tokPunct, "...") {
+		if err := p.advance(); err != nil {
+			return selection{}, err
+		}
+		if p.tok.is(tokName, "on") || p.tok.is(tokPunct, "{") {
+			var typeCondition string
+			if p.tok.is(tokName, "on") {
+				if err := p.advance(); err != nil {
+					return selection{}, err
+				}
+				name, err := p.expectName()
+				if err != nil {
+					return selection{}, err
+				}
+				typeCondition = name
+			}
+			sel, err := p.parseSelectionSet()
+			return selection{typeCondition: typeCondition, inline: sel}, err
+		}
+		name, err := p.expectName()
+		return selection{fragmentName: name}, err
+	}
+
+	f := &field{}
+	name, err := p.expectName()
+	if err != nil {
+		return selection{}, err
+	}
+	f.name = name
+	if p.tok.is(tokPunct, ":") {
+		if err := p.advance(); err != nil {
+			return selection{}, err
+		}
+		f.alias = name
+		if f.name, err = p.expectName(); err != nil {
+			return selection{}, err
+		}
+	}
+	if f.alias == "" {
+		f.alias = f.name
+	}
+
+	if p.tok.is(tokPunct, "(") {
+		if f.arguments, err = p.parseArguments(); err != nil {
+			return selection{}, err
+		}
+	}
+	if p.tok.is(tokPunct, "{") {
+		if f.selection, err = p.parseSelectionSet(); err != nil {
+			return selection{}, err
+		}
+	}
+	return selection{field: f}, nil
+}
+
+func (p *parser) parseArguments() (map[string]value, error) {
+	if err := p.expect(tokPunct, "("); err != nil {
+		return nil, err
+	}
+	args := make(map[string]value)
+	for !p.tok.is(tokPunct, ")") {
+		name, err := p.expectName()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(tokPunct, ":"); err != nil {
+			return nil, err
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		args[name] = v
+	}
+	return args, p.advance()
+}
+
+func (p *parser) parseValue() (value, error) {
+	if err := p.enter(); err != nil {
+		return value{}, err
+	}
+	defer p.leave()
+
+	tok := p.tok
+	switch {
+	case tok.is(tokPunct, "$"):
+		if err := p.advance(); err != nil {
+			return value{}, err
+		}
+		name, err := p.expectName()
+		return value{variable: name}, err
+	case tok.is(tokPunct, "["):
+		if err := p.advance(); err != nil {
+			return value{}, err
+		}
+		var list []value
+		for !p.tok.is(tokPunct, "]") {
+			v, err := p.parseValue()
+			if err != nil {
+				return value{}, err
+			}
+			list = append(list, v)
+		}
+		return value{list: list}, p.advance()
+	case tok.is(tokPunct, "{"):
+		if err := p.advance(); err != nil {
+			return value{}, err
+		}
+		obj := make(map[string]value)
+		for !p.tok.is(tokPunct, "}") {
+			name, err := p.expectName()
+			if err != nil {
+				return value{}, err
+			}
+			if err := p.expect(tokPunct, ":"); err != nil {
+				return value{}, err
+			}
+			v, err := p.parseValue()
+			if err != nil {
+				return value{}, err
+			}
+			obj[name] = v
+		}
+		return value{object: obj}, p.advance()
+	case tok.kind == tokInt:
+		n, err := strconv.Atoi(tok.text)
+		if err != nil {
+			return value{}, p.errorf("invalid integer %q", tok.text)
+		}
+		return value{literal: n}, p.advance()
+	case tok.kind == tokFloat:
+		f, err := strconv.ParseFloat(tok.text, 64)
+		if err != nil {
+			return value{}, p.errorf("invalid float %q", tok.text)
+		}
+		return value{literal: f}, p.advance()
+	case tok.kind == tokString:
+		return value{literal: tok.text}, p.advance()
+	case tok.kind == tokName:
+		var v interface{}
+		switch tok.text {
+		case "true":
+			v = true
+		case "false":
+			v = false
+		case "null":
+			v = nil
+		default:
+			v = tok.text // значение перечисления
+		}
+		return value{literal: v}, p.advance()
+	}
+	return value{}, p.errorf("unexpected %q in value", tok.text)
+}
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokName
+	tokInt
+	tokFloat
+	tokString
+	tokPunct
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) is(kind tokenKind, text string) bool {
+	return t.kind == kind && t.text == text
+}
+
+type lexer struct {
+	src string
+	pos int
+}
+
+func (l *lexer) next() (token, error) {
+	// Пробелы, запятые и комментарии в GraphQL незначимы
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		if c == '#' {
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+			continue
+		}
+		if c == ',' || c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			l.pos++
+			continue
+		}
+		break
+	}
+	if l.pos >= len(l.src) {
+		return token{kind: tokEOF, pos: l.pos}, nil
+	}
+
+	start := l.pos
+	c := l.src[l.pos]
+	switch {
+	case strings.HasPrefix(l.src[l.pos:], "..."):
+		l.pos += 3
+		return token{kind: tokPunct, text: "...", pos: start}, nil
+	case strings.ContainsRune("{}()[]:!$=@|&", rune(c)):
+		l.pos++
+		return token{kind: tokPunct, text: string(c), pos: start}, nil
+	case c == '"':
+		return l.lexString()
+	case c == '-' || (c >= '0' && c <= '9'):
+		l.pos++
+		kind := tokInt
+		for l.pos < len(l.src) {
+			c := l.src[l.pos]
+			if c == '.' || c == 'e' || c == 'E' || c == '+' || (c == '-' && kind == tokFloat) {
+				kind = tokFloat
+			} else if c < '0' || c > '9' {
+				break
+			}
+			l.pos++
+		}
+		return token{kind: kind, text: l.src[start:l.pos], pos: start}, nil
+	case c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z'):
+		for l.pos < len(l.src) {
+			c := l.src[l.pos]
+			if c != '_' && !(c >= '0' && c <= '9') && !(c >= 'a' && c <= 'z') && !(c >= 'A' && c <= 'Z') {
+				break
+			}
+			l.pos++
+		}
+		return token{kind: tokName, text: l.src[start:l.pos], pos: start}, nil
+	}
+	return token{}, fmt.Errorf("syntax error at position %d: unexpected character %q", start, c)
+}
+
+func (l *lexer) lexString() (token, error) {
+	start := l.pos
+	l.pos++
+	var b strings.Builder
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch c {
+		case '"':
+			l.pos++
+			return token{kind: tokString, text: b.String(), pos: start}, nil
+		case '\n':
+			return token{}, fmt.Errorf("syntax error at position %d: unterminated string", start)
+		case '\\':
+			if l.pos+1 >= len(l.src) {
+				return token{}, fmt.Errorf("syntax error at position %d: unterminated string", start)
+			}
+			l.pos++
+			switch esc := l.src[l.pos]; esc {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			case 'r':
+				b.WriteByte('\r')
+			case 'u':
+				if l.pos+4 >= len(l.src) {
+					return token{}, fmt.Errorf("syntax error at position %d: invalid unicode escape", l.pos)
+				}
+				r, err := strconv.ParseUint(l.src[l.pos+1:l.pos+5], 16, 32)
+				if err != nil {
+					return token{}, fmt.Errorf("syntax error at position %d: invalid unicode escape", l.pos)
+				}
+				b.WriteRune(rune(r))
+				l.pos += 4
+			default:
+				b.WriteByte(esc)
+			}
+		default:
+			b.WriteByte(c)
+		}
+		l.pos++
+	}
+	return token{}, fmt.Errorf("syntax error at position %d: unterminated string", start)
+}
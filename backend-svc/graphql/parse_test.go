@@ -0,0 +1,149 @@
+package graphql
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseValid(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		operations int
+		fragments  int
+	}{
+		{"shorthand query", "{ me { id } }", 1, 0},
+		{"named query with variables", "query Q($id: Int!, $tags: [String!] = [\"a\"]) { course(id: $id) { id } }", 1, 0},
+		{"aliases and arguments", "{ a: course(id: 1) { id } b: course(id: 2) { id } }", 1, 0},
+		{"fragments", "query { me { ...U } } fragment U on User { id username }", 1, 1},
+		{"inline fragments", "{ me { ... on User { id } ... { email } } }", 1, 0},
+		{"several operations", "query A { me { id } } query B { courses { id } }", 2, 0},
+		{"commas and comments", "# comment\n{ me { id, username, # trailing\n email } }", 1, 0},
+		{"object and list arguments", `{ users(filter: {roles: ["admin", "user"], active: true}, limit: 10) { total } }`, 1, 0},
+		{"string escapes", `{ users(search: "a\"b\\c\nA") { total } }`, 1, 0},
+		{"float and negative numbers", "{ a(x: -1, y: 1.5e3) { id } }", 1, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parse(tt.query)
+			if err != nil {
+				t.Fatalf("parse: %v", err)
+			}
+			if len(doc.operations) != tt.operations {
+				t.Errorf("operations = %d, want %d", len(doc.operations), tt.operations)
+			}
+			if len(doc.fragments) != tt.fragments {
+				t.Errorf("fragments = %d, want %d", len(doc.fragments), tt.fragments)
+			}
+		})
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	doc, err := parse(`{ f(s: "a\"b\\c\nA", i: -7, fl: 2.5, b: true, n: null, e: ACTIVE, l: [1, 2], o: {k: "v"}, v: $x) { id } }`)
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	args := doc.operations[0].selection[0].field.arguments
+	if got := args["s"].literal; got != "a\"b\\c\nA" {
+		t.Errorf("s = %q", got)
+	}
+	if got := args["i"].literal; got != -7 {
+		t.Errorf("i = %v", got)
+	}
+	if got := args["fl"].literal; got != 2.5 {
+		t.Errorf("fl = %v", got)
+	}
+	if got := args["b"].literal; got != true {
+		t.Errorf("b = %v", got)
+	}
+	if v, ok := args["n"]; !ok || v.literal != nil {
+		t.Errorf("n = %v", v)
+	}
+	if got := args["e"].literal; got != "ACTIVE" {
+		t.Errorf("e = %v", got)
+	}
+	if got := len(args["l"].list); got != 2 {
+		t.Errorf("len(l) = %d", got)
+	}
+	if got := args["o"].object["k"].literal; got != "v" {
+		t.Errorf("o.k = %v", got)
+	}
+	if got := args["v"].variable; got != "x" {
+		t.Errorf("v = %q", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"empty document", "", "no operations"},
+		{"only comments", "# nothing", "no operations"},
+		{"empty selection", "{ }", "empty selection set"},
+		{"unterminated selection", "{ me { id }", "unterminated selection set"},
+		{"unterminated string", `{ users(search: "abc) { total } }`, "unterminated string"},
+		{"newline in string", "{ users(search: \"a\nb\") { total } }", "unterminated string"},
+		{"trailing backslash", `{ users(search: "\`, "unterminated string"},
+		{"bad unicode escape", `{ users(search: "\uZZZZ") { total } }`, "invalid unicode escape"},
+		{"short unicode escape", `{ users(search: "\u41`, "invalid unicode escape"},
+		{"unexpected character", "{ me { id ^ } }", "unexpected character"},
+		{"missing argument value", "{ course(id: ) { id } }", "in value"},
+		{"missing colon in argument", "{ course(id 1) { id } }", "expected \":\""},
+		{"bad integer", "{ course(id: 99999999999999999999) { id } }", "invalid integer"},
+		{"bad float", "{ course(id: 1.2.3) { id } }", "invalid float"},
+		{"variable without name", "query ($: Int) { me { id } }", "expected name"},
+		{"fragment without type condition", "fragment F { id } { me { ...F } }", "expected \"on\""},
+		{"stray token", "} { me { id } }", "unexpected"},
+		{"unterminated list", "{ f(l: [1, 2", "in value"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(tt.query)
+			if err == nil {
+				t.Fatalf("parse(%q) succeeded, want error containing %q", tt.query, tt.want)
+			}
+			if !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %q, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// Вложенность 5000 укладывается в maxQuerySize, так что срабатывает именно maxNesting
+func TestParseLimits(t *testing.T) {
+	deep := func(open, close string, n int) string {
+		return strings.Repeat(open, n) + strings.Repeat(close, n)
+	}
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{"oversized query", "{ me { id } }" + strings.Repeat(" ", maxQuerySize), "larger than"},
+		{"nested selections", "{" + deep("a {", "}", 5000) + "}", "nested deeper"},
+		{"nested inline fragments", "{ me " + deep("{ ... ", "}", 5000) + "}", "nested deeper"},
+		{"nested lists", "{ f(l: " + deep("[", "]", 5000) + ") { id } }", "nested deeper"},
+		{"nested objects", "{ f(o: " + deep("{a: ", "}", 5000) + ") { id } }", "nested deeper"},
+		{"nested types", "query ($v: " + deep("[", "]", 5000) + ") { me { id } }", "nested deeper"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parse(tt.query)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("error = %v, want it to contain %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseNestingWithinLimit(t *testing.T) {
+	// maxNesting-1 вложенных наборов полей внутри корневого
+	n := maxNesting - 1
+	query := "{" + strings.Repeat("a {", n) + " id" + strings.Repeat("}", n) + "}"
+	if _, err := parse(query); err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+}
@@ -0,0 +1,74 @@
+package grpcapi
+
+import (
+	"lmsmodule/backend-svc/grpcapi/lmspb"
+	"lmsmodule/backend-svc/models"
+	"sort"
+)
+
+// Преобразование моделей хранилища в сообщения из proto/lms.proto
+
+func toUser(u models.UserSummary) *lmspb.User {
+	user := &lmspb.User{
+		Id:            int64(u.ID),
+		Username:      u.Username,
+		Email:         u.Email,
+		FullName:      u.FullName,
+		Is_2FaEnabled: u.Is2FAEnabled,
+		IsAdmin:       u.IsAdmin,
+		IsActive:      u.IsActive,
+	}
+	if !u.LastLogin.IsZero() {
+		user.LastLoginUnix = u.LastLogin.Unix()
+	}
+	return user
+}
+
+func toUsers(users []models.UserSummary) []*lmspb.User {
+	result := make([]*lmspb.User, 0, len(users))
+	for _, u := range users {
+		result = append(result, toUser(u))
+	}
+	return result
+}
+
+func toTask(t models.Task) *lmspb.Task {
+	return &lmspb.Task{
+		Id:          int64(t.ID),
+		CourseId:    int64(t.CourseID),
+		Title:       t.Title,
+		Description: t.Description,
+		Difficulty:  t.Difficulty,
+		Order:       int32(t.Order),
+	}
+}
+
+func toCourse(c models.Course) *lmspb.Course {
+	course := &lmspb.Course{
+		Id:                int64(c.ID),
+		VulnerabilityType: c.VulnerabilityType,
+		TasksCount:        int32(c.TasksCount),
+		Description:       c.Description,
+		Tasks:             make([]*lmspb.Task, 0, len(c.Tasks)),
+	}
+	for _, t := range c.Tasks {
+		course.Tasks = append(course.Tasks, toTask(t))
+	}
+	if !c.UpdatedAt.IsZero() {
+		course.UpdatedAtUnix = c.UpdatedAt.Unix()
+	}
+	return course
+}
+
+func toProgress(p models.UserProgress) *lmspb.UserProgress {
+	progress := &lmspb.UserProgress{UserId: int64(p.UserID)}
+	for taskID, done := range p.Completed {
+		if done {
+			progress.CompletedTaskIds = append(progress.CompletedTaskIds, int64(taskID))
+		}
+	}
+	sort.Slice(progress.CompletedTaskIds, func(i, j int) bool {
+		return progress.CompletedTaskIds[i] < progress.CompletedTaskIds[j]
+	})
+	return progress
+}
@@ -0,0 +1,166 @@
+// Package grpcapi обслуживает gRPC API для внутренних сервисов по контракту proto/lms.proto.
+// Сообщения и заглушки сервисов генерируются в пакет lmspb.
+package grpcapi
+
+//go:generate protoc -I proto --go_out=lmspb --go_opt=paths=source_relative --go-grpc_out=lmspb --go-grpc_opt=paths=source_relative proto/lms.proto
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"expvar"
+	"lmsmodule/backend-svc/grpcapi/lmspb"
+	"lmsmodule/backend-svc/storage"
+	"log"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+var (
+	grpcCalls  = expvar.NewMap("grpc_calls_total")
+	grpcErrors = expvar.NewMap("grpc_errors_total")
+)
+
+// Server реализует сервисы Users, Courses и Progress поверх хранилища
+type Server struct {
+	lmspb.UnimplementedUsersServer
+	lmspb.UnimplementedCoursesServer
+	lmspb.UnimplementedProgressServer
+
+	Store     storage.Storage
+	authToken atomic.Pointer[string]
+}
+
+// NewServer создает реализацию сервисов. Вызовы должны передавать метаданные
+// authorization: Bearer <authToken>.
+func NewServer(store storage.Storage, authToken string) *Server {
+	s := &Server{Store: store}
+	s.SetAuthToken(authToken)
+	return s
+}
+
+// GRPCServer возвращает gRPC-сервер с зарегистрированными сервисами, проверкой токена
+// и счетчиками вызовов
+func (s *Server) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts, grpc.ChainUnaryInterceptor(countCalls, s.authenticate))
+	srv := grpc.NewServer(opts...)
+	lmspb.RegisterUsersServer(srv, s)
+	lmspb.RegisterCoursesServer(srv, s)
+	lmspb.RegisterProgressServer(srv, s)
+	return srv
+}
+
+// SetAuthToken заменяет токен доступа; безопасно вызывать во время работы при ротации секретов
+func (s *Server) SetAuthToken(token string) {
+	s.authToken.Store(&token)
+}
+
+func (s *Server) authorized(ctx context.Context) bool {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) != 1 {
+		return false
+	}
+	expected := *s.authToken.Load()
+	token := strings.TrimPrefix(values[0], "Bearer ")
+	return expected != "" && subtle.ConstantTimeCompare([]byte(token), []byte(expected)) == 1
+}
+
+func (s *Server) authenticate(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	if !s.authorized(ctx) {
+		return nil, status.Error(codes.Unauthenticated, "invalid or missing bearer token")
+	}
+	return handler(ctx, req)
+}
+
+// countCalls считает вызовы и ошибки по методам. Ошибки хранилища без gRPC-статуса
+// записываются в лог, а клиенту возвращается только Internal.
+func countCalls(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	grpcCalls.Add(info.FullMethod, 1)
+	resp, err := handler(ctx, req)
+	if err != nil {
+		grpcErrors.Add(info.FullMethod, 1)
+		if _, ok := status.FromError(err); !ok {
+			log.Printf("gRPC %s failed: %v", info.FullMethod, err)
+			err = status.Error(codes.Internal, "internal error")
+		}
+	}
+	return resp, err
+}
+
+// GetUser возвращает пользователя по ID
+func (s *Server) GetUser(ctx context.Context, req *lmspb.GetUserRequest) (*lmspb.User, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be positive")
+	}
+	user, err := s.Store.GetUserPublicByID(int(req.GetId()))
+	if err != nil {
+		if errors.Is(err, storage.ErrUserNotFound) {
+			return nil, status.Errorf(codes.NotFound, "user %d not found", req.GetId())
+		}
+		return nil, err
+	}
+	return toUser(user), nil
+}
+
+// ListUsers возвращает всех пользователей или найденных по query
+func (s *Server) ListUsers(ctx context.Context, req *lmspb.ListUsersRequest) (*lmspb.ListUsersResponse, error) {
+	if query := req.GetQuery(); query != "" {
+		users, err := s.Store.SearchUsers(query)
+		if err != nil {
+			return nil, err
+		}
+		return &lmspb.ListUsersResponse{Users: toUsers(users)}, nil
+	}
+	users, err := s.Store.ListUserSummaries()
+	if err != nil {
+		return nil, err
+	}
+	return &lmspb.ListUsersResponse{Users: toUsers(users)}, nil
+}
+
+// ListCourses возвращает курсы с заданиями
+func (s *Server) ListCourses(ctx context.Context, req *lmspb.ListCoursesRequest) (*lmspb.ListCoursesResponse, error) {
+	courses, err := s.Store.GetCourses()
+	if err != nil {
+		return nil, err
+	}
+	resp := &lmspb.ListCoursesResponse{Courses: make([]*lmspb.Course, 0, len(courses))}
+	for _, c := range courses {
+		resp.Courses = append(resp.Courses, toCourse(c))
+	}
+	return resp, nil
+}
+
+// GetCourse возвращает курс по ID
+func (s *Server) GetCourse(ctx context.Context, req *lmspb.GetCourseRequest) (*lmspb.Course, error) {
+	if req.GetId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "id must be positive")
+	}
+	course, err := s.Store.GetCourseByID(int(req.GetId()))
+	if err != nil {
+		if errors.Is(err, storage.ErrCourseNotFound) {
+			return nil, status.Errorf(codes.NotFound, "course %d not found", req.GetId())
+		}
+		return nil, err
+	}
+	return toCourse(course), nil
+}
+
+// GetProgress возвращает выполненные пользователем задания
+func (s *Server) GetProgress(ctx context.Context, req *lmspb.GetProgressRequest) (*lmspb.UserProgress, error) {
+	if req.GetUserId() <= 0 {
+		return nil, status.Error(codes.InvalidArgument, "user_id must be positive")
+	}
+	progress, err := s.Store.GetUserProgress(int(req.GetUserId()))
+	if err != nil {
+		return nil, err
+	}
+	progress.UserID = int(req.GetUserId())
+	return toProgress(progress), nil
+}
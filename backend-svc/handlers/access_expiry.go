@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary List access expiries
+// @Description Users whose access ends on a set date, soonest first (admin only). deactivatedAt is set once the account was deactivated because its access expired.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.AccessExpiry
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/access-expiry [get]
+func ListAccessExpiries(c *gin.Context) {
+	expiries, err := Store.ListAccessExpiries()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get access expiries: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, expiries)
+}
+
+// @Summary Set access expiry
+// @Description Set the date after which the user is deactivated automatically, or remove it with "expiresAt": null (admin only). Moving the date into the future reactivates an account that was deactivated because its access expired.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.SetAccessExpiryRequest true "Access expiry"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{id}/access-expiry [put]
+func SetAccessExpiry(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+	var req models.SetAccessExpiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	err = Store.SetAccessExpiry(userID, req.ExpiresAt)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to set access expiry: " + err.Error()})
+		return
+	}
+	forgetSessionState(userID)
+
+	message := "Access expiry updated"
+	if req.ExpiresAt == nil {
+		message = "Access expiry removed"
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, message)})
+}
+
+// @Summary Extend access
+// @Description Extend the access of several users at once, either to the date expiresAt or by days counted from the current expiry (from now if it has passed) (admin only). Accounts deactivated because their access expired are reactivated when the new date is in the future. Users without an access expiry are returned in skipped.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ExtendAccessExpiryRequest true "Users and extension"
+// @Success 200 {object} models.ExtendAccessExpiryResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/access-expiry/extend [post]
+func ExtendAccessExpiry(c *gin.Context) {
+	var req models.ExtendAccessExpiryRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if (req.ExpiresAt == nil) == (req.Days == 0) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Specify either expiresAt or days"})
+		return
+	}
+
+	extended, skipped, err := Store.ExtendAccessExpiry(req.UserIDs, req.ExpiresAt, req.Days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to extend access: " + err.Error()})
+		return
+	}
+	for _, expiry := range extended {
+		forgetSessionState(expiry.UserID)
+	}
+	c.JSON(http.StatusOK, models.ExtendAccessExpiryResponse{Extended: extended, Skipped: skipped})
+}
+
+// DeactivateExpiredAccounts деактивирует пользователей, чей срок доступа истек, и сообщает
+// об этом администраторам одним письмом
+func DeactivateExpiredAccounts() error {
+	deactivated, err := Store.DeactivateExpiredAccounts(time.Now())
+	for _, expiry := range deactivated {
+		forgetSessionState(expiry.UserID)
+		log.Printf("Access of user %d expired at %s; account deactivated", expiry.UserID, expiry.ExpiresAt.Format(time.RFC3339))
+	}
+	if len(deactivated) > 0 {
+		notifyAccessExpired(deactivated)
+	}
+	return err
+}
+
+// notifyAccessExpired отправляет активным администраторам список деактивированных учетных записей
+func notifyAccessExpired(deactivated []models.AccessExpiry) {
+	admins, err := Store.GetUsersByRole(true)
+	if err != nil {
+		log.Printf("Failed to get admins to notify about expired access: %v", err)
+		return
+	}
+	accounts := make([]string, len(deactivated))
+	for i, expiry := range deactivated {
+		accounts[i] = fmt.Sprintf("%s <%s>", expiry.Username, expiry.Email)
+	}
+	for _, admin := range admins {
+		if !admin.IsActive {
+			continue
+		}
+		if err := mail.SendAccessExpired(admin.Email, accounts, UserLocale(admin.Locale)); err != nil {
+			log.Printf("Failed to notify admin %d about expired access: %v", admin.ID, err)
+		}
+	}
+}
@@ -0,0 +1,194 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+var (
+	accountsMu     sync.RWMutex
+	accountsConfig = config.Default().Accounts
+)
+
+// ConfigureAccounts задает параметры самообслуживания учетных записей
+func ConfigureAccounts(cfg config.AccountsConfig) {
+	accountsMu.Lock()
+	defer accountsMu.Unlock()
+	accountsConfig = cfg
+}
+
+func currentAccountsConfig() config.AccountsConfig {
+	accountsMu.RLock()
+	defer accountsMu.RUnlock()
+	return accountsConfig
+}
+
+// @Summary Export my data
+// @Description Download everything the platform stores about the current user as JSON. Clients offer this before account deletion.
+// @Tags Account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.AccountExport
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/export [get]
+func ExportAccountData(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	user, err := Store.GetUserPublicByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
+		return
+	}
+	export := models.AccountExport{
+		ExportedAt: time.Now().UTC(),
+		Profile: models.UserProfile{
+			ID:           user.ID,
+			Username:     user.Username,
+			Email:        user.Email,
+			FullName:     user.FullName,
+			Is2FAEnabled: user.Is2FAEnabled,
+			LastLogin:    user.LastLogin,
+			Version:      user.Version,
+			Avatar:       AvatarURLs(user.AvatarKey),
+			Locale:       UserLocale(user.Locale),
+			Timezone:     UserTimezone(user.Timezone),
+		},
+	}
+
+	if export.Progress, err = Store.GetUserProgress(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to export progress: " + err.Error()})
+		return
+	}
+	if export.OTPPreference, err = Store.GetOTPPreference(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to export OTP settings: " + err.Error()})
+		return
+	}
+	if export.TrustedDevices, err = Store.ListTrustedDevices(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to export trusted devices: " + err.Error()})
+		return
+	}
+	if export.Logins, err = Store.ListLoginContexts(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to export sign-in history: " + err.Error()})
+		return
+	}
+	if export.OTPDeliveries, err = Store.ListOTPDeliveries(userID, otpDeliveryHistoryLimit); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to export OTP deliveries: " + err.Error()})
+		return
+	}
+	if export.TaskNotes, err = Store.ListTaskNotes(userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to export notes: " + err.Error()})
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="lms-account-%d.json"`, userID))
+	c.JSON(http.StatusOK, export)
+}
+
+// @Summary Delete my account
+// @Description Schedule deletion of the current user's account. Requires the password, exportAcknowledged=true (offer GET /account/export first) and, with 2FA enabled, an OTP: the first call without otp sends a code. The account is deactivated at once and purged after the grace period; signing in before then cancels the deletion.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.DeleteAccountRequest true "Re-verification"
+// @Success 200 {object} models.AccountDeletionResponse "Deletion scheduled"
+// @Success 202 {object} models.AccountDeletionResponse "OTP sent, repeat the request with it"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "Invalid password or OTP"
+// @Failure 428 {object} models.ExportRequiredResponse "Offer the data export first"
+// @Failure 429 {object} models.ErrorResponse "Too many invalid OTP attempts"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account [delete]
+func DeleteAccount(c *gin.Context) {
+	userID := c.GetInt("userID")
+
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if !req.ExportAcknowledged {
+		c.JSON(http.StatusPreconditionRequired, models.ExportRequiredResponse{
+			Error:     "Export your data before deleting the account",
+			ExportURL: "/api/account/export",
+		})
+		return
+	}
+
+	user, err := Store.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
+		return
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)) != nil {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Invalid password"})
+		return
+	}
+
+	if user.Is2FAEnabled {
+		if req.OTP == "" {
+			channel, err := issueOTP(c.Request.Context(), user)
+			if err != nil {
+				log.Printf("Failed to issue OTP for user %d: %v", user.ID, err)
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to issue OTP code"})
+				return
+			}
+			c.JSON(http.StatusAccepted, models.AccountDeletionResponse{
+				Message:     Translatef(c, "OTP sent via %s; repeat the request with the code", channel),
+				OTPRequired: true,
+			})
+			return
+		}
+
+		valid, err := Store.VerifyOTPCode(user.ID, req.OTP, currentOTPSettings().MaxAttempts)
+		if errors.Is(err, storage.ErrOTPAttemptsExceeded) {
+			c.JSON(http.StatusTooManyRequests, models.ErrorResponse{Error: "Too many invalid OTP attempts; request a new code"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
+			return
+		}
+		if !valid {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Invalid or expired OTP code"})
+			return
+		}
+		if err := Store.ClearOTPCode(user.ID); err != nil {
+			log.Printf("Failed to clear OTP code for user %d: %v", user.ID, err)
+		}
+	}
+
+	purgeAt := time.Now().Add(currentAccountsConfig().DeletionGracePeriod).UTC()
+	if err := Store.ScheduleAccountDeletion(user.ID, purgeAt); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to schedule account deletion: " + err.Error()})
+		return
+	}
+	forgetSessionState(user.ID)
+	log.Printf("User %d scheduled their account for deletion at %s", user.ID, purgeAt.Format(time.RFC3339))
+
+	if user.Email != "" {
+		go func(email string) {
+			if err := mail.SendAccountDeletionScheduled(email, purgeAt, userLocation(user.Timezone), UserLocale(user.Locale)); err != nil {
+				log.Printf("Failed to send account deletion notice to user %d: %v", user.ID, err)
+			}
+		}(user.Email)
+	}
+
+	c.JSON(http.StatusOK, models.AccountDeletionResponse{
+		Message: Translate(c, "Your account has been deactivated and will be deleted; sign in before the deletion date to cancel"),
+		PurgeAt: &purgeAt,
+	})
+}
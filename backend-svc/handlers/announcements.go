@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/storage"
+)
+
+const (
+	defaultAnnouncementLimit = 20
+	maxAnnouncementLimit     = 100
+)
+
+// requireAnnouncementAuthor отвечает 403, если пользователь не может публиковать объявления
+func requireAnnouncementAuthor(c *gin.Context) (userID int, isAdmin, ok bool) {
+	return requireInstructor(c, "Only administrators and instructors can manage announcements")
+}
+
+// announcementFromRequest проверяет запрос и переносит его поля в объявление
+func announcementFromRequest(c *gin.Context, a *models.Announcement) bool {
+	var req models.AnnouncementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return false
+	}
+	publishAt := time.Now().UTC()
+	if req.PublishAt != nil {
+		publishAt = req.PublishAt.UTC()
+	}
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(publishAt) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "expiresAt must be after publishAt"})
+		return false
+	}
+
+	a.CourseID = req.CourseID
+	a.Title = req.Title
+	a.Body = req.Body
+	a.PublishAt = publishAt
+	a.ExpiresAt = req.ExpiresAt
+	return true
+}
+
+// PublishDueAnnouncements рассылает подписчикам темы broadcast объявления, время публикации
+// которых наступило. Вызывается фоновой задачей и сразу после создания или изменения объявления.
+func PublishDueAnnouncements() error {
+	if Realtime == nil {
+		return nil
+	}
+	due, err := Store.ClaimDueAnnouncements(time.Now())
+	for _, a := range due {
+		// Формат совпадает с объявлениями из /admin/broadcast, а id и courseId позволяют
+		// клиенту показать объявление в ленте и отфильтровать объявления чужих курсов
+		Realtime.Broadcast(realtime.TopicBroadcast, "announcement", gin.H{
+			"id":        a.ID,
+			"courseId":  a.CourseID,
+			"title":     a.Title,
+			"message":   a.Body,
+			"from":      a.AuthorID,
+			"expiresAt": a.ExpiresAt,
+		})
+	}
+	return err
+}
+
+// @Summary Announcements feed
+// @Description Active announcements, newest first. With courseId, returns platform-wide announcements and those of the course; scope=manage returns all announcements including scheduled and expired ones (administrators see all, instructors their own).
+// @Tags Announcements
+// @Produce json
+// @Param courseId query int false "Course ID"
+// @Param scope query string false "manage — include scheduled and expired announcements"
+// @Param limit query int false "Maximum number of announcements (default 20, max 100)"
+// @Success 200 {array} models.Announcement
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /announcements [get]
+func ListAnnouncements(c *gin.Context) {
+	filter := models.AnnouncementFilter{IncludeGlobal: true, Limit: defaultAnnouncementLimit}
+	if raw := c.Query("courseId"); raw != "" {
+		courseID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+			return
+		}
+		filter.CourseID = &courseID
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxAnnouncementLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "limit must be between 1 and 100"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	if c.Query("scope") == "manage" {
+		userID, isAdmin, ok := requireAnnouncementAuthor(c)
+		if !ok {
+			return
+		}
+		if !isAdmin {
+			filter.AuthorID = userID
+		}
+	} else {
+		now := time.Now()
+		filter.ActiveAt = &now
+	}
+
+	announcements, err := Store.ListAnnouncements(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list announcements: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, announcements)
+}
+
+// @Summary Create announcement
+// @Description Creates a platform-wide (no courseId) or course announcement; it appears in the feed and is pushed to connected clients at publishAt (administrators and instructors)
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param request body models.AnnouncementRequest true "Announcement"
+// @Success 201 {object} models.Announcement
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /announcements [post]
+func CreateAnnouncement(c *gin.Context) {
+	userID, _, ok := requireAnnouncementAuthor(c)
+	if !ok {
+		return
+	}
+	announcement := models.Announcement{AuthorID: &userID}
+	if !announcementFromRequest(c, &announcement) {
+		return
+	}
+
+	created, err := Store.CreateAnnouncement(announcement)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create announcement: " + err.Error()})
+		return
+	}
+	log.Printf("User %d created announcement %d", userID, created.ID)
+
+	if err := PublishDueAnnouncements(); err != nil {
+		log.Printf("Failed to publish announcements: %v", err)
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// editableAnnouncement загружает объявление, которое пользователь может изменять:
+// администратор — любое, преподаватель — свое
+func editableAnnouncement(c *gin.Context) (models.Announcement, bool) {
+	userID, isAdmin, ok := requireAnnouncementAuthor(c)
+	if !ok {
+		return models.Announcement{}, false
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid announcement ID"})
+		return models.Announcement{}, false
+	}
+
+	announcement, err := Store.GetAnnouncement(id)
+	if errors.Is(err, storage.ErrAnnouncementNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Announcement not found"})
+		return models.Announcement{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get announcement: " + err.Error()})
+		return models.Announcement{}, false
+	}
+	if !isAdmin && (announcement.AuthorID == nil || *announcement.AuthorID != userID) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Instructors can only change their own announcements"})
+		return models.Announcement{}, false
+	}
+	return announcement, true
+}
+
+// @Summary Update announcement
+// @Description Replaces the course, text and schedule of an announcement; instructors can only change their own. An announcement that was already pushed is not pushed again.
+// @Tags Announcements
+// @Accept json
+// @Produce json
+// @Param id path int true "Announcement ID"
+// @Param request body models.AnnouncementRequest true "Announcement"
+// @Success 200 {object} models.Announcement
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /announcements/{id} [put]
+func UpdateAnnouncement(c *gin.Context) {
+	announcement, ok := editableAnnouncement(c)
+	if !ok || !announcementFromRequest(c, &announcement) {
+		return
+	}
+
+	updated, err := Store.UpdateAnnouncement(announcement)
+	switch {
+	case errors.Is(err, storage.ErrAnnouncementNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Announcement not found"})
+		return
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update announcement: " + err.Error()})
+		return
+	}
+
+	if err := PublishDueAnnouncements(); err != nil {
+		log.Printf("Failed to publish announcements: %v", err)
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// @Summary Delete announcement
+// @Description Instructors can only delete their own announcements
+// @Tags Announcements
+// @Produce json
+// @Param id path int true "Announcement ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /announcements/{id} [delete]
+func DeleteAnnouncement(c *gin.Context) {
+	announcement, ok := editableAnnouncement(c)
+	if !ok {
+		return
+	}
+
+	err := Store.DeleteAnnouncement(announcement.ID)
+	if errors.Is(err, storage.ErrAnnouncementNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Announcement not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete announcement: " + err.Error()})
+		return
+	}
+	log.Printf("User %d deleted announcement %d", c.GetInt("userID"), announcement.ID)
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Announcement deleted")})
+}
@@ -0,0 +1,92 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"lmsmodule/backend-svc/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultActivityLimit = 50
+	maxActivityLimit     = 200
+)
+
+// activityFilter читает фильтр ленты действий из параметров запроса
+func activityFilter(c *gin.Context) (models.AuditFilter, bool) {
+	filter := models.AuditFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("targetType"),
+		Limit:      defaultActivityLimit,
+	}
+	for name, target := range map[string]*int{"actorId": &filter.ActorID, "targetId": &filter.TargetID, "limit": &filter.Limit} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil || value < 1 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "actorId, targetId and limit must be positive integers"})
+			return filter, false
+		}
+		*target = value
+	}
+	if filter.Limit > maxActivityLimit {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "limit must be between 1 and 200"})
+		return filter, false
+	}
+	if raw := c.Query("before"); raw != "" {
+		before, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil || before < 1 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid entry ID"})
+			return filter, false
+		}
+		filter.Before = before
+	}
+	for name, target := range map[string]*time.Time{"from": &filter.From, "to": &filter.To} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "from and to must be RFC 3339 timestamps"})
+			return filter, false
+		}
+		*target = value
+	}
+	return filter, true
+}
+
+// @Summary Admin activity feed
+// @Description Recent actions of administrators, organization admins and instructors, newest first (admin only). Every successful change made through the admin API and the instructor endpoints is recorded with its actor and target; changes to users and courses include the changed fields with their values before and after. Pass before=<oldest entry ID> to page back.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param actorId query int false "Only actions of this user"
+// @Param action query string false "Only this action, e.g. PUT /admin/users/:id/status"
+// @Param targetType query string false "Only actions on this kind of target, e.g. user or course"
+// @Param targetId query int false "Only actions on this target"
+// @Param from query string false "Only actions at or after this time (RFC 3339)"
+// @Param to query string false "Only actions before this time (RFC 3339)"
+// @Param before query int false "Return entries older than this entry ID"
+// @Param limit query int false "Maximum number of entries (default 50, max 200)"
+// @Success 200 {array} models.AuditEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/activity [get]
+func GetActivityFeed(c *gin.Context) {
+	filter, ok := activityFilter(c)
+	if !ok {
+		return
+	}
+	entries, err := Store.ListAuditEntries(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get activity: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, entries)
+}
@@ -9,38 +9,78 @@ import (
 	"golang.org/x/crypto/bcrypt"
 	"lmsmodule/backend-svc/mail"
 	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/settings"
+	"lmsmodule/backend-svc/storage"
+	"log"
 	"net/http"
 	"time"
 )
 
 const (
-	tempJWTSecret = "temp_2fa_secret_here"
+	registrationAcceptedMessage = "Registration received. If the details are valid, you can now sign in; otherwise check your email"
+	// registrationPendingMessage отвечает на регистрацию в режиме одобрения администратором
+	registrationPendingMessage = "Registration received. An administrator will review it and you will get an email with the decision"
 )
 
+// dummyPasswordHash сравнивается с паролем, если пользователь не найден, чтобы время ответа
+// на вход не выдавало существование учетной записи
+var dummyPasswordHash, _ = bcrypt.GenerateFromPassword([]byte("lms-dummy-password"), bcrypt.DefaultCost)
+
 // @Summary Register new user
 // @Tags Authentication
 // @Accept json
 // @Produce json
 // @Param request body models.RegisterRequest true "Registration data"
-// @Success 201 {object} models.RegisterResponse "User created"
+// @Description The response is the same whether or not the username or email is already taken;
+// @Description duplicates are reported to the email owner. Sign in with the chosen credentials to continue.
+// @Description While the registration_approval setting is on, the account stays inactive until an admin approves it.
+// @Success 202 {object} models.RegisterResponse "Registration accepted"
 // @Failure 400 {object} models.ErrorResponse "Invalid request"
-// @Failure 409 {object} models.ErrorResponse "User already exists"
+// @Failure 403 {object} models.ErrorResponse "Registration is closed"
+// @Failure 403 {object} models.CaptchaRequiredResponse "Captcha missing or invalid"
 // @Failure 500 {object} models.ErrorResponse "Server error"
+// @Failure 503 {object} models.ErrorResponse "Captcha provider unavailable"
 // @Router /register [post]
 func RegisterHandler(c *gin.Context) {
+	if !Settings.Bool(settings.KeyRegistrationOpen) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Registration is closed"})
+		return
+	}
+
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data"})
 		return
 	}
 
+	if verifier, siteKey := currentCaptcha(); verifier != nil {
+		if !verifyCaptcha(c, verifier, siteKey, req.CaptchaToken) {
+			return
+		}
+	}
+
+	user, ok := newUserAccount(c, req)
+	if !ok {
+		return
+	}
+	// В режиме одобрения учетная запись создается неактивной и попадает в очередь администраторов
+	if Settings.Bool(settings.KeyRegistrationApproval) {
+		respondRegistration(c, req, registrationPendingMessage, Store.CreatePendingUser(user, c.ClientIP()))
+		return
+	}
+	respondRegistration(c, req, registrationAcceptedMessage, Store.CreateUser(user))
+}
+
+// newUserAccount готовит учетную запись из запроса на регистрацию: хеширует пароль и создает
+// секрет 2FA. При ошибке отвечает на запрос сам.
+func newUserAccount(c *gin.Context, req models.RegisterRequest) (models.User, bool) {
 	hashedPassword, err := bcrypt.GenerateFromPassword(
 		[]byte(req.Password),
 		bcrypt.DefaultCost,
 	)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Password hashing failed"})
-		return
+		return models.User{}, false
 	}
 
 	key, err := totp.Generate(totp.GenerateOpts{
@@ -50,10 +90,10 @@ func RegisterHandler(c *gin.Context) {
 	})
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate 2FA key"})
-		return
+		return models.User{}, false
 	}
 
-	user := models.User{
+	return models.User{
 		Username:     req.Username,
 		PasswordHash: string(hashedPassword),
 		Email:        req.Email,
@@ -61,34 +101,25 @@ func RegisterHandler(c *gin.Context) {
 		TOTPSecret:   key.Secret(),
 		Is2FAEnabled: true,
 		IsActive:     true,
-	}
-
-	err = Store.CreateUser(user)
-	if err != nil {
-		if err.Error() == "username or email already exists" {
-			c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Username or email already exists"})
-		} else {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "User registration failed: " + err.Error()})
-		}
-		return
-	}
-
-	createdUser, err := Store.GetUserByUsername(user.Username)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "User created but failed to retrieve"})
-		return
-	}
+	}, true
+}
 
-	token, err := createJWTToken(createdUser.ID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
+// respondRegistration отвечает на регистрацию сообщением message по результату создания
+// пользователя err. Ответ не должен зависеть от того, заняты ли имя пользователя или email:
+// о дубликате узнает только владелец адреса из письма.
+func respondRegistration(c *gin.Context, req models.RegisterRequest, message string, err error) {
+	switch {
+	case errors.Is(err, storage.ErrDuplicateEmail):
+		go mail.SendAccountExistsEmail(req.Email, RequestLocale(c))
+	case errors.Is(err, storage.ErrDuplicateUsername):
+		go mail.SendUsernameTakenEmail(req.Email, req.Username, RequestLocale(c))
+	case err != nil:
+		log.Printf("User registration failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "User registration failed"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.RegisterResponse{
-		Token:   token,
-		Message: "User created successfully",
-	})
+	c.JSON(http.StatusAccepted, models.RegisterResponse{Message: Translate(c, message)})
 }
 
 // @Summary Login
@@ -96,11 +127,14 @@ func RegisterHandler(c *gin.Context) {
 // @Accept json
 // @Produce json
 // @Param request body models.LoginRequest true "Credentials"
-// @Success 200 {object} models.TempTokenResponse "OTP sent to registered email (if 2FA enabled)"
-// @Success 200 {object} models.LoginResponse "User logged in successfully (if 2FA disabled)"
+// @Success 200 {object} models.TempTokenResponse "OTP sent via the user's delivery channel (if 2FA enabled)"
+// @Success 200 {object} models.LoginResponse "User logged in successfully (if 2FA disabled or the device is trusted)"
 // @Failure 400 {object} models.ErrorResponse "Invalid request"
 // @Failure 401 {object} models.ErrorResponse "Invalid credentials"
+// @Failure 403 {object} models.ErrorResponse "Account is disabled or awaiting approval"
+// @Failure 403 {object} models.CaptchaRequiredResponse "Captcha required after repeated failed logins"
 // @Failure 500 {object} models.ErrorResponse "System error"
+// @Failure 503 {object} models.ErrorResponse "Captcha provider unavailable"
 // @Router /login [post]
 func LoginHandler(c *gin.Context) {
 	var req models.LoginRequest
@@ -109,65 +143,80 @@ func LoginHandler(c *gin.Context) {
 		return
 	}
 
-	user, err := Store.GetUserByUsername(req.Username)
-	if err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Invalid credentials"})
-		return
+	clientIP := c.ClientIP()
+	if verifier, siteKey := currentCaptcha(); verifier != nil && loginFailures.exceeded(req.Username, clientIP) {
+		if !verifyCaptcha(c, verifier, siteKey, req.CaptchaToken) {
+			return
+		}
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Invalid credentials"})
+	user, err := Store.GetUserByUsername(req.Username)
+	if err != nil && !errors.Is(err, storage.ErrUserNotFound) {
+		log.Printf("Login lookup failed: %v", err)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
 		return
 	}
-
-	err = Store.UpdateUserLastLogin(user.ID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
+	passwordHash := dummyPasswordHash
+	if err == nil {
+		passwordHash = []byte(user.PasswordHash)
+	}
+	// Хеш проверяется и для несуществующих пользователей, чтобы ответы не различались по времени
+	if err := bcrypt.CompareHashAndPassword(passwordHash, []byte(req.Password)); err != nil || user.ID == 0 {
+		loginFailures.record(req.Username, clientIP)
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Invalid credentials"})
 		return
 	}
+	loginFailures.reset(req.Username)
 
-	if user.Is2FAEnabled {
-		code, err := totp.GenerateCode(user.TOTPSecret, time.Now())
+	// Учетная запись, ожидающая удаления, восстанавливается входом
+	if !user.IsActive && user.DeletionScheduledAt == nil {
+		pending, err := Store.IsRegistrationPending(user.ID)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to generate OTP"})
+			log.Printf("Pending registration lookup failed: %v", err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
 			return
 		}
-
-		err = Store.SaveOTPCode(user.ID, code)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save OTP code"})
+		if pending {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Account is awaiting approval"})
 			return
 		}
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Account is disabled"})
+		return
+	}
 
-		err = mail.SendOTPEmail(user.Email, code)
-		if err != nil {
-			fmt.Printf("Error sending OTP email: %v\n", err)
-		}
-
-		tempToken, err := createTempToken(user.ID)
-		if err != nil {
+	// Вход разрешен и в режиме только для чтения, но время входа тогда не записывается
+	if !ReadOnlyMode() {
+		if err := Store.UpdateUserLastLogin(user.ID); err != nil {
 			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
 			return
 		}
+	}
 
-		c.JSON(http.StatusOK, models.TempTokenResponse{
-			TempToken: tempToken,
-			Message:   "OTP sent to registered email",
-		})
-	} else {
-		token, err := createJWTToken(user.ID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
+	// На доверенном устройстве второй фактор не запрашивается
+	if user.Is2FAEnabled {
+		if _, trusted := trustedDevice(c, user.ID); !trusted {
+			channel, err := issueOTP(c.Request.Context(), user)
+			if err != nil {
+				log.Printf("Failed to issue OTP for user %d: %v", user.ID, err)
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to issue OTP code"})
+				return
+			}
+
+			tempToken, err := createTempToken(user.ID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
+				return
+			}
+
+			c.JSON(http.StatusOK, models.TempTokenResponse{
+				TempToken: tempToken,
+				Message:   Translatef(c, "OTP sent via %s", channel),
+			})
 			return
 		}
-
-		c.JSON(http.StatusOK, models.LoginResponse{
-			Token:    token,
-			UserID:   user.ID,
-			Username: user.Username,
-			Email:    user.Email,
-		})
 	}
+
+	completeLogin(c, user)
 }
 
 // @Summary Verify OTP
@@ -178,6 +227,7 @@ func LoginHandler(c *gin.Context) {
 // @Success 200 {object} models.LoginResponse "User logged in successfully"
 // @Failure 400 {object} models.ErrorResponse "Invalid request"
 // @Failure 401 {object} models.ErrorResponse "Invalid token or OTP"
+// @Failure 429 {object} models.ErrorResponse "Code invalidated after too many invalid attempts"
 // @Failure 500 {object} models.ErrorResponse "System error"
 // @Router /verify-otp [post]
 func VerifyOTPHandler(c *gin.Context) {
@@ -193,7 +243,11 @@ func VerifyOTPHandler(c *gin.Context) {
 		return
 	}
 
-	valid, err := Store.VerifyOTPCode(userID, req.OTP)
+	valid, err := Store.VerifyOTPCode(userID, req.OTP, currentOTPSettings().MaxAttempts)
+	if errors.Is(err, storage.ErrOTPAttemptsExceeded) {
+		c.JSON(http.StatusTooManyRequests, models.ErrorResponse{Error: "Too many invalid OTP attempts; sign in again to get a new code"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
 		return
@@ -204,9 +258,15 @@ func VerifyOTPHandler(c *gin.Context) {
 		return
 	}
 
-	err = Store.ClearOTPCode(userID)
-	if err != nil {
-		fmt.Printf("Error clearing OTP code: %v\n", err)
+	// В режиме только для чтения код не сбрасывается и остается действительным до истечения срока
+	if !ReadOnlyMode() {
+		if err := Store.ClearOTPCode(userID); err != nil {
+			fmt.Printf("Error clearing OTP code: %v\n", err)
+		}
+	}
+
+	if req.RememberDevice {
+		rememberDevice(c, userID)
 	}
 
 	user, err := Store.GetUserByID(userID)
@@ -214,19 +274,13 @@ func VerifyOTPHandler(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "User not found"})
 		return
 	}
-
-	token, err := createJWTToken(user.ID)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
+	// Учетная запись, ожидающая удаления, восстанавливается входом
+	if !user.IsActive && user.DeletionScheduledAt == nil {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Account is disabled"})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.LoginResponse{
-		Token:    token,
-		UserID:   user.ID,
-		Username: user.Username,
-		Email:    user.Email,
-	})
+	completeLogin(c, user)
 }
 
 // @Summary Enable 2FA
@@ -293,20 +347,77 @@ func ReloadTemplatesHandler(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse{Message: "Templates reloaded successfully"})
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Templates reloaded successfully")})
+}
+
+// completeLogin завершает вход после проверки всех факторов: отменяет запланированное
+// удаление учетной записи, запоминает контекст входа и выдает access-токен
+func completeLogin(c *gin.Context, user models.User) {
+	restored := false
+	if user.DeletionScheduledAt != nil {
+		// Восстановление учетной записи — запись, без которой токен не будет принят
+		if ReadOnlyMode() {
+			c.Header("Retry-After", "300")
+			c.JSON(http.StatusServiceUnavailable, models.MaintenanceResponse{
+				Error:    "Service is in read-only mode",
+				Message:  Settings.Get(settings.KeyMaintenanceText),
+				ReadOnly: true,
+			})
+			return
+		}
+		if err := Store.CancelAccountDeletion(user.ID); err != nil {
+			log.Printf("Failed to cancel account deletion for user %d: %v", user.ID, err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
+			return
+		}
+		forgetSessionState(user.ID)
+		restored = true
+	}
+
+	if !ReadOnlyMode() {
+		recordLogin(c, user)
+	}
+
+	token, err := createSessionToken(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "System error"})
+		return
+	}
+
+	response := loginResponse(user, token)
+	response.AccountRestored = restored
+	c.JSON(http.StatusOK, response)
+}
+
+// loginResponse собирает ответ на успешный вход; флаг смены пароля подсказывает клиенту,
+// что до смены пароля остальные запросы будут отклонены
+func loginResponse(user models.User, token string) models.LoginResponse {
+	mustChange, err := PasswordChangeRequired(user.ID)
+	if err != nil {
+		log.Printf("Failed to check password change requirement for user %d: %v", user.ID, err)
+	}
+	return models.LoginResponse{
+		Token:              token,
+		UserID:             user.ID,
+		Username:           user.Username,
+		Email:              user.Email,
+		MustChangePassword: mustChange,
+	}
 }
 
 func createTempToken(userID int) (string, error) {
+	secret, ttl := currentTempAuth()
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub": userID,
-		"exp": time.Now().Add(5 * time.Minute).Unix(),
+		"exp": time.Now().Add(ttl).Unix(),
 	})
-	return token.SignedString([]byte(tempJWTSecret))
+	return token.SignedString([]byte(secret))
 }
 
 func validateTempToken(tokenString string) (int, error) {
+	secret, _ := currentTempAuth()
 	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-		return []byte(tempJWTSecret), nil
+		return []byte(secret), nil
 	})
 	if err != nil || !token.Valid {
 		return 0, errors.New("invalid token")
@@ -322,10 +433,11 @@ func validateTempToken(tokenString string) (int, error) {
 	return int(userIDFloat), nil
 }
 
-func createJWTToken(userID int) (string, error) {
+func createJWTToken(userID int, sessionID string) (string, error) {
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
 		"sub": userID,
-		"exp": time.Now().Add(24 * time.Hour).Unix(),
+		"sid": sessionID,
+		"exp": time.Now().Add(currentTokenTTL()).Unix(),
 	})
-	return token.SignedString([]byte(JWTSecret))
+	return token.SignedString([]byte(JWTSecret()))
 }
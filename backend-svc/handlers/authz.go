@@ -0,0 +1,151 @@
+package handlers
+
+import (
+	"expvar"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+)
+
+// roleCacheTTL ограничивает, как долго реплика доверяет закэшированным ролям пользователя
+// (администратор, группы). Внутри запроса результат проверки дополнительно запоминается
+// в контексте, поэтому цепочка middleware и обработчик обращаются к таблице users один раз.
+// Изменения ролей через API сбрасывают запись сразу, на других репликах они вступают в силу
+// не позже чем через этот интервал.
+const roleCacheTTL = 5 * time.Second
+
+// Ключи контекста запроса с уже проверенными ролями текущего пользователя
+const (
+	ctxIsAdmin      = "authz.isAdmin"
+	ctxIsInstructor = "authz.isInstructor"
+)
+
+// cachedRoles — роли пользователя; groups == nil — группы еще не загружались
+type cachedRoles struct {
+	isAdmin  bool
+	groups   []string
+	loadedAt time.Time
+}
+
+var (
+	rolesMu    sync.Mutex
+	rolesCache = map[int]cachedRoles{}
+
+	roleCacheHits   = expvar.NewInt("authz_role_cache_hits_total")
+	roleCacheMisses = expvar.NewInt("authz_role_cache_misses_total")
+)
+
+func loadCachedRoles(userID int) (cachedRoles, bool) {
+	rolesMu.Lock()
+	defer rolesMu.Unlock()
+	cached, ok := rolesCache[userID]
+	if !ok || time.Since(cached.loadedAt) >= roleCacheTTL {
+		return cachedRoles{}, false
+	}
+	return cached, true
+}
+
+// forgetRoles сбрасывает закэшированные роли пользователя после изменения его прав
+// администратора, групп или организации
+func forgetRoles(userID int) {
+	rolesMu.Lock()
+	defer rolesMu.Unlock()
+	delete(rolesCache, userID)
+}
+
+// CheckAdminRights проверяет, имеет ли пользователь права администратора
+func CheckAdminRights(userID int) (bool, error) {
+	if cached, ok := loadCachedRoles(userID); ok {
+		roleCacheHits.Add(1)
+		return cached.isAdmin, nil
+	}
+	roleCacheMisses.Add(1)
+	isAdmin, err := Store.IsAdmin(userID)
+	if err != nil {
+		return false, err
+	}
+	rolesMu.Lock()
+	rolesCache[userID] = cachedRoles{isAdmin: isAdmin, loadedAt: time.Now()}
+	rolesMu.Unlock()
+	return isAdmin, nil
+}
+
+// userGroups возвращает группы пользователя с учетом кэша
+func userGroups(userID int) ([]string, error) {
+	cached, ok := loadCachedRoles(userID)
+	if ok && cached.groups != nil {
+		roleCacheHits.Add(1)
+		return cached.groups, nil
+	}
+	roleCacheMisses.Add(1)
+	groups, err := Store.GetUserGroups(userID)
+	if err != nil {
+		return nil, err
+	}
+	if groups == nil {
+		groups = []string{}
+	}
+	// Группы дополняют запись, созданную проверкой прав администратора, и живут столько же;
+	// сброшенная за время запроса запись не восстанавливается
+	if ok {
+		rolesMu.Lock()
+		if current, found := rolesCache[userID]; found && current.loadedAt.Equal(cached.loadedAt) {
+			current.groups = groups
+			rolesCache[userID] = current
+		}
+		rolesMu.Unlock()
+	}
+	return groups, nil
+}
+
+// CheckInstructorRights проверяет, является ли пользователь преподавателем: администратором
+// или участником группы models.InstructorGroup
+func CheckInstructorRights(userID int) (isAdmin, isInstructor bool, err error) {
+	isAdmin, err = CheckAdminRights(userID)
+	if err != nil || isAdmin {
+		return isAdmin, isAdmin, err
+	}
+	groups, err := userGroups(userID)
+	if err != nil {
+		return false, false, err
+	}
+	for _, group := range groups {
+		if group == models.InstructorGroup {
+			return false, true, nil
+		}
+	}
+	return false, false, nil
+}
+
+// RequestAdminRights — CheckAdminRights для текущего пользователя запроса: результат
+// запоминается в контексте и повторно не проверяется
+func RequestAdminRights(c *gin.Context) (bool, error) {
+	if isAdmin, ok := c.Get(ctxIsAdmin); ok {
+		return isAdmin.(bool), nil
+	}
+	isAdmin, err := CheckAdminRights(c.GetInt("userID"))
+	if err != nil {
+		return false, err
+	}
+	c.Set(ctxIsAdmin, isAdmin)
+	return isAdmin, nil
+}
+
+// requestInstructorRights — CheckInstructorRights для текущего пользователя запроса
+func requestInstructorRights(c *gin.Context) (isAdmin, isInstructor bool, err error) {
+	isAdmin, err = RequestAdminRights(c)
+	if err != nil || isAdmin {
+		return isAdmin, isAdmin, err
+	}
+	if isInstructor, ok := c.Get(ctxIsInstructor); ok {
+		return false, isInstructor.(bool), nil
+	}
+	_, isInstructor, err = CheckInstructorRights(c.GetInt("userID"))
+	if err != nil {
+		return false, false, err
+	}
+	c.Set(ctxIsInstructor, isInstructor)
+	return false, isInstructor, nil
+}
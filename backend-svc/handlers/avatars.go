@@ -0,0 +1,218 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/media"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+var (
+	mediaMu         sync.RWMutex
+	mediaStore      media.Store
+	maxAvatarBytes  int64
+	maxPackageBytes int64
+)
+
+// ConfigureMedia задает медиахранилище и ограничения размера загрузок
+func ConfigureMedia(cfg config.MediaConfig, publicURL string) {
+	store := media.NewStore(cfg, publicURL)
+
+	mediaMu.Lock()
+	defer mediaMu.Unlock()
+	mediaStore = store
+	maxAvatarBytes = int64(cfg.MaxUploadSize)
+	maxPackageBytes = int64(cfg.MaxPackageSize)
+}
+
+func currentMedia() (media.Store, int64) {
+	mediaMu.RLock()
+	defer mediaMu.RUnlock()
+	return mediaStore, maxAvatarBytes
+}
+
+func avatarObjectKey(avatarKey string, size int) string {
+	return fmt.Sprintf("%s/%d.png", avatarKey, size)
+}
+
+// AvatarURLs возвращает адреса версий аватара по префиксу ключей; nil, если аватар не загружен
+func AvatarURLs(avatarKey string) *models.AvatarURLs {
+	store, _ := currentMedia()
+	if avatarKey == "" || store == nil {
+		return nil
+	}
+	return &models.AvatarURLs{
+		Small:  store.URL(avatarObjectKey(avatarKey, media.AvatarSizes[0])),
+		Medium: store.URL(avatarObjectKey(avatarKey, media.AvatarSizes[1])),
+		Large:  store.URL(avatarObjectKey(avatarKey, media.AvatarSizes[2])),
+	}
+}
+
+// deleteAvatarFiles удаляет версии аватара из хранилища; ошибки только логируются,
+// так как ссылка на аватар в профиле к этому моменту уже заменена
+func deleteAvatarFiles(c *gin.Context, store media.Store, avatarKey string) {
+	if avatarKey == "" {
+		return
+	}
+	for _, size := range media.AvatarSizes {
+		if err := store.Delete(c.Request.Context(), avatarObjectKey(avatarKey, size)); err != nil {
+			log.Printf("Failed to delete avatar file: %v", err)
+		}
+	}
+}
+
+// readUpload читает файл из поля формы field или, если запрос не multipart, из тела запроса
+func readUpload(c *gin.Context, field string, limit int64) ([]byte, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+1<<20)
+	if c.ContentType() != "multipart/form-data" {
+		return io.ReadAll(io.LimitReader(c.Request.Body, limit+1))
+	}
+
+	file, err := c.FormFile(field)
+	if err != nil {
+		return nil, err
+	}
+	if file.Size > limit {
+		return nil, &http.MaxBytesError{Limit: limit}
+	}
+	f, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, limit+1))
+}
+
+// @Summary Upload my avatar
+// @Description Upload a PNG, JPEG or GIF image as a multipart field "avatar" or as the raw request body. The image is cropped to a square and stored in 48, 128 and 256 px versions; the URLs never change for the same upload and can be cached forever.
+// @Tags Account
+// @Accept multipart/form-data,image/png,image/jpeg,image/gif
+// @Produce json
+// @Security BearerAuth
+// @Param avatar formData file false "Avatar image"
+// @Success 200 {object} models.AvatarURLs
+// @Failure 400 {object} models.ErrorResponse "Not a supported image or too small"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse "File or image dimensions too large"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/avatar [put]
+func UploadAvatar(c *gin.Context) {
+	userID := c.GetInt("userID")
+	store, limit := currentMedia()
+
+	data, err := readUpload(c, "avatar", limit)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || int64(len(data)) > limit {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: fmt.Sprintf("Avatar must not exceed %d bytes", limit)})
+		return
+	}
+	if err != nil || len(data) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Upload an image in the \"avatar\" form field or as the request body"})
+		return
+	}
+
+	versions, err := media.ResizeAvatar(data, media.AvatarSizes)
+	switch {
+	case errors.Is(err, media.ErrImageTooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: "Image dimensions are too large"})
+		return
+	case errors.Is(err, media.ErrImageTooSmall):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Image is too small for an avatar"})
+		return
+	case errors.Is(err, media.ErrUnsupportedImage):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Avatar must be a PNG, JPEG or GIF image"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to process avatar: " + err.Error()})
+		return
+	}
+
+	// Каждая загрузка получает новый префикс: адреса версий неизменны и кэшируются бессрочно
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to store avatar"})
+		return
+	}
+	avatarKey := "avatars/" + strconv.Itoa(userID) + "/" + hex.EncodeToString(suffix)
+
+	for _, size := range media.AvatarSizes {
+		if err := store.Put(c.Request.Context(), avatarObjectKey(avatarKey, size), "image/png", versions[size]); err != nil {
+			log.Printf("Failed to store avatar for user %d: %v", userID, err)
+			deleteAvatarFiles(c, store, avatarKey)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to store avatar"})
+			return
+		}
+	}
+
+	previous, err := Store.SetAvatar(userID, avatarKey)
+	if err != nil {
+		deleteAvatarFiles(c, store, avatarKey)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save avatar: " + err.Error()})
+		return
+	}
+	deleteAvatarFiles(c, store, previous)
+
+	c.JSON(http.StatusOK, AvatarURLs(avatarKey))
+}
+
+// removeAvatar удаляет аватар пользователя и его файлы
+func removeAvatar(c *gin.Context, userID int) {
+	store, _ := currentMedia()
+	previous, err := Store.SetAvatar(userID, "")
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to remove avatar: " + err.Error()})
+		return
+	}
+	deleteAvatarFiles(c, store, previous)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Avatar removed")})
+}
+
+// @Summary Remove my avatar
+// @Tags Account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/avatar [delete]
+func DeleteAvatar(c *gin.Context) {
+	removeAvatar(c, c.GetInt("userID"))
+}
+
+// @Summary Remove a user's avatar
+// @Description Remove an inappropriate avatar (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{id}/avatar [delete]
+func AdminDeleteAvatar(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+	removeAvatar(c, userID)
+}
@@ -0,0 +1,767 @@
+package handlers
+
+import (
+	"crypto/rsa"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"lmsmodule/backend-svc/badges"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/media"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/storage"
+)
+
+// maxBadgeImageBytes ограничивает размер изображения значка
+const maxBadgeImageBytes = 1 << 20
+
+var (
+	badgeMu     sync.RWMutex
+	badgeIssuer *badges.Issuer
+	// ephemeralBadgeKey — ключ, созданный при запуске без badges.signing_key; сохраняется
+	// между перенастройками, чтобы выданные значки проверялись до перезапуска
+	ephemeralBadgeKey *rsa.PrivateKey
+)
+
+// ConfigureBadges задает издателя значков и ключ подписи. Безопасно вызывать повторно при ротации секретов.
+func ConfigureBadges(cfg config.BadgesConfig, publicURL string) error {
+	var key *rsa.PrivateKey
+	if cfg.SigningKey != "" {
+		parsed, err := badges.ParsePrivateKey(cfg.SigningKey)
+		if err != nil {
+			return err
+		}
+		key = parsed
+	}
+
+	badgeMu.Lock()
+	defer badgeMu.Unlock()
+	if key == nil {
+		if ephemeralBadgeKey == nil {
+			generated, err := badges.GenerateKey()
+			if err != nil {
+				return err
+			}
+			ephemeralBadgeKey = generated
+			log.Println("WARNING: badges.signing_key is not set; badges are signed with a temporary key and cannot be verified after a restart")
+		}
+		key = ephemeralBadgeKey
+	}
+	badgeIssuer = badges.NewIssuer(cfg.IssuerName, cfg.IssuerURL, publicURL, key)
+	return nil
+}
+
+func currentIssuer() *badges.Issuer {
+	badgeMu.RLock()
+	defer badgeMu.RUnlock()
+	return badgeIssuer
+}
+
+// badgeClassView дополняет значок адресом изображения и типом достижения
+func badgeClassView(issuer *badges.Issuer, class models.BadgeClass) models.BadgeClass {
+	class.AchievementType = models.AchievementOther
+	if class.CourseID != nil {
+		class.AchievementType = models.AchievementCourse
+	}
+	class.ImageURL = issuer.ImageURL(class)
+	return class
+}
+
+// badgeView дополняет выданный значок ссылками для проверки, скачивания и публикации в LinkedIn
+func badgeView(issuer *badges.Issuer, assertion models.BadgeAssertion) models.Badge {
+	if assertion.BadgeClass != nil {
+		class := badgeClassView(issuer, *assertion.BadgeClass)
+		assertion.BadgeClass = &class
+	}
+	badge := models.Badge{
+		BadgeAssertion: assertion,
+		URL:            issuer.CredentialURL(assertion.ID),
+		DownloadURL:    issuer.CredentialURL(assertion.ID) + "/download",
+	}
+	if assertion.RevokedAt == nil && assertion.BadgeClass != nil {
+		query := url.Values{
+			"startTask":        {"CERTIFICATION_NAME"},
+			"name":             {assertion.BadgeClass.Name},
+			"organizationName": {issuer.Name()},
+			"issueYear":        {strconv.Itoa(assertion.IssuedAt.Year())},
+			"issueMonth":       {strconv.Itoa(int(assertion.IssuedAt.Month()))},
+			"certUrl":          {badge.URL},
+			"certId":           {assertion.ID},
+		}
+		badge.LinkedInURL = "https://www.linkedin.com/profile/add?" + query.Encode()
+	}
+	return badge
+}
+
+// issueBadge подписывает удостоверение о значке class для пользователя и сохраняет его.
+// issuedBy — преподаватель, выдавший значок вручную; nil для автоматической выдачи.
+func issueBadge(class models.BadgeClass, user models.User, issuedBy *int) (models.BadgeAssertion, error) {
+	issuer := currentIssuer()
+	recipient := badges.Recipient{IdentityType: "emailAddress", Identity: user.Email}
+	if user.Email == "" {
+		recipient = badges.Recipient{IdentityType: "identifier", Identity: user.Username}
+	}
+
+	assertion := models.BadgeAssertion{
+		ID:           uuid.NewString(),
+		BadgeClassID: class.ID,
+		UserID:       user.ID,
+		IssuedAt:     time.Now().UTC().Truncate(time.Second),
+		IssuedBy:     issuedBy,
+	}
+	credential, err := issuer.Issue(assertion.ID, badgeClassView(issuer, class), recipient, assertion.IssuedAt)
+	if err != nil {
+		return models.BadgeAssertion{}, err
+	}
+	assertion.Credential = credential
+	saved, err := Store.CreateBadgeAssertion(assertion)
+	if err != nil {
+		return models.BadgeAssertion{}, err
+	}
+	saved.BadgeClass = &class
+	if Realtime != nil {
+		Realtime.Broadcast(realtime.UserTopic(user.ID), "badge.awarded", badgeView(issuer, saved))
+	}
+	return saved, nil
+}
+
+// awardCourseBadges выдает значки за курсы, которые пользователь завершил заданиями taskIDs.
+// Значок, который у пользователя уже есть или был отозван, повторно автоматически не выдается.
+func awardCourseBadges(userID int, taskIDs []int) {
+	if len(taskIDs) == 0 {
+		return
+	}
+	courseIDs, err := completedTaskCourses(userID, taskIDs)
+	if err != nil {
+		log.Printf("Failed to check course badges for user %d: %v", userID, err)
+		return
+	}
+
+	var held map[int]bool
+	for courseID := range courseIDs {
+		class, err := Store.GetCourseBadgeClass(courseID)
+		if errors.Is(err, storage.ErrBadgeClassNotFound) {
+			continue
+		}
+		if err == nil && held == nil {
+			held, err = heldBadgeClasses(userID)
+		}
+		if err == nil && !held[class.ID] {
+			err = awardCourseBadge(userID, courseID, class)
+		}
+		if err != nil && !errors.Is(err, storage.ErrBadgeAlreadyAwarded) {
+			log.Printf("Failed to award badge of course %d to user %d: %v", courseID, userID, err)
+		}
+	}
+}
+
+func heldBadgeClasses(userID int) (map[int]bool, error) {
+	owned, err := Store.ListUserBadges(userID)
+	if err != nil {
+		return nil, err
+	}
+	held := make(map[int]bool, len(owned))
+	for _, assertion := range owned {
+		held[assertion.BadgeClassID] = true
+	}
+	return held, nil
+}
+
+func awardCourseBadge(userID, courseID int, class models.BadgeClass) error {
+	course, err := Store.GetCourseByID(courseID)
+	if err != nil {
+		return err
+	}
+	if completed, err := courseCompleted(userID, course); err != nil || !completed {
+		return err
+	}
+	user, err := Store.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	_, err = issueBadge(class, user, nil)
+	return err
+}
+
+// loadBadgeClass загружает значок из пути запроса
+func loadBadgeClass(c *gin.Context) (models.BadgeClass, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid badge ID"})
+		return models.BadgeClass{}, false
+	}
+	class, err := Store.GetBadgeClass(id)
+	if errors.Is(err, storage.ErrBadgeClassNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Badge not found"})
+		return class, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get badge: " + err.Error()})
+		return class, false
+	}
+	return class, true
+}
+
+// loadBadgeAssertion загружает выданный значок из пути запроса. Отозванный значок не показывается:
+// проверяющий получает 410 Gone.
+func loadBadgeAssertion(c *gin.Context) (models.BadgeAssertion, bool) {
+	assertion, err := Store.GetBadgeAssertion(c.Param("id"))
+	if errors.Is(err, storage.ErrBadgeAssertionNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Badge not found"})
+		return assertion, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get badge: " + err.Error()})
+		return assertion, false
+	}
+	if assertion.RevokedAt != nil {
+		c.JSON(http.StatusGone, models.ErrorResponse{Error: "This badge has been revoked"})
+		return assertion, false
+	}
+	return assertion, true
+}
+
+// badgeImage возвращает загруженное изображение значка или изображение по умолчанию
+func badgeImage(classID int) ([]byte, error) {
+	image, err := Store.GetBadgeClassImage(classID)
+	if err != nil || image != nil {
+		return image, err
+	}
+	return badges.DefaultImage(classID), nil
+}
+
+// @Summary List badges
+// @Description Lists badge classes: course badges are awarded automatically when a student completes every task of the course, other badges are awarded by instructors
+// @Tags Badges
+// @Produce json
+// @Security BearerAuth
+// @Param courseId query int false "Only the badge of this course"
+// @Success 200 {array} models.BadgeClass
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges/classes [get]
+func ListBadgeClasses(c *gin.Context) {
+	issuer := currentIssuer()
+	if courseIDParam := c.Query("courseId"); courseIDParam != "" {
+		courseID, err := strconv.Atoi(courseIDParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+			return
+		}
+		class, err := Store.GetCourseBadgeClass(courseID)
+		if errors.Is(err, storage.ErrBadgeClassNotFound) {
+			c.JSON(http.StatusOK, []models.BadgeClass{})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get badges: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, []models.BadgeClass{badgeClassView(issuer, class)})
+		return
+	}
+
+	classes, err := Store.ListBadgeClasses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get badges: " + err.Error()})
+		return
+	}
+	for i := range classes {
+		classes[i] = badgeClassView(issuer, classes[i])
+	}
+	c.JSON(http.StatusOK, classes)
+}
+
+// @Summary Get badge
+// @Tags Badges
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Badge ID"
+// @Success 200 {object} models.BadgeClass
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /badges/classes/{id} [get]
+func GetBadgeClass(c *gin.Context) {
+	class, ok := loadBadgeClass(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, badgeClassView(currentIssuer(), class))
+}
+
+// @Summary Create badge
+// @Description Creates a badge class (administrators and instructors). A badge with a course ID is awarded automatically to students who complete every task of that course; a course can have one badge. Without a course ID the badge is awarded manually.
+// @Tags Badges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SaveBadgeClassRequest true "Badge"
+// @Success 201 {object} models.BadgeClass
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges/classes [post]
+func CreateBadgeClass(c *gin.Context) {
+	userID, _, ok := requireInstructor(c, "Only administrators and instructors can manage badges")
+	if !ok {
+		return
+	}
+	var req models.SaveBadgeClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	class, err := Store.CreateBadgeClass(models.BadgeClass{
+		CourseID:    req.CourseID,
+		Name:        req.Name,
+		Description: req.Description,
+		Criteria:    req.Criteria,
+		CreatedBy:   &userID,
+	})
+	switch {
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	case errors.Is(err, storage.ErrBadgeClassExists):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "The course already has a badge"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create badge: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, badgeClassView(currentIssuer(), class))
+}
+
+// @Summary Update badge
+// @Description Changes the name, description and criteria of a badge (administrators and instructors). Badges already awarded keep the values they were issued with.
+// @Tags Badges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Badge ID"
+// @Param request body models.SaveBadgeClassRequest true "Badge; courseId is ignored"
+// @Success 200 {object} models.BadgeClass
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges/classes/{id} [put]
+func UpdateBadgeClass(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can manage badges"); !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid badge ID"})
+		return
+	}
+	var req models.SaveBadgeClassRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	class, err := Store.UpdateBadgeClass(models.BadgeClass{ID: id, Name: req.Name, Description: req.Description, Criteria: req.Criteria})
+	if errors.Is(err, storage.ErrBadgeClassNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Badge not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update badge: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, badgeClassView(currentIssuer(), class))
+}
+
+// @Summary Delete badge
+// @Description Deletes a badge class together with every badge awarded from it (administrators and instructors). Deleted badges stop verifying.
+// @Tags Badges
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Badge ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges/classes/{id} [delete]
+func DeleteBadgeClass(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can manage badges"); !ok {
+		return
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid badge ID"})
+		return
+	}
+	err = Store.DeleteBadgeClass(id)
+	if errors.Is(err, storage.ErrBadgeClassNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Badge not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete badge: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Badge deleted")})
+}
+
+// @Summary Upload badge image
+// @Description Uploads the badge image as a multipart field "image" or as the raw request body (administrators and instructors). The image must be a PNG of at most 2048x2048 pixels and 1 MB. Badges without an image get a generated one. The image URL changes with every upload.
+// @Tags Badges
+// @Accept multipart/form-data,image/png
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Badge ID"
+// @Param image formData file false "PNG image"
+// @Success 200 {object} models.BadgeClass
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges/classes/{id}/image [put]
+func UploadBadgeImage(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can manage badges"); !ok {
+		return
+	}
+	class, ok := loadBadgeClass(c)
+	if !ok {
+		return
+	}
+
+	data, err := readUpload(c, "image", maxBadgeImageBytes)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || len(data) > maxBadgeImageBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: fmt.Sprintf("Badge image must not exceed %d bytes", maxBadgeImageBytes)})
+		return
+	}
+	if err != nil || len(data) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Upload an image in the \"image\" form field or as the request body"})
+		return
+	}
+	if err := badges.CheckImage(data); errors.Is(err, badges.ErrInvalidImage) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Badge image must be a PNG"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: "Image dimensions are too large"})
+		return
+	}
+
+	class, err = Store.SetBadgeClassImage(class.ID, data)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save badge image: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, badgeClassView(currentIssuer(), class))
+}
+
+// @Summary Remove badge image
+// @Description Removes the uploaded badge image; the badge gets a generated image (administrators and instructors)
+// @Tags Badges
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Badge ID"
+// @Success 200 {object} models.BadgeClass
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges/classes/{id}/image [delete]
+func DeleteBadgeImage(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can manage badges"); !ok {
+		return
+	}
+	class, ok := loadBadgeClass(c)
+	if !ok {
+		return
+	}
+	class, err := Store.SetBadgeClassImage(class.ID, nil)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save badge image: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, badgeClassView(currentIssuer(), class))
+}
+
+// @Summary Get badge image
+// @Description Returns the badge image (PNG). Public: the URL is embedded in issued credentials.
+// @Tags Badges
+// @Produce png
+// @Param id path int true "Badge ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /badges/images/{id} [get]
+func BadgeImage(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid badge ID"})
+		return
+	}
+	image, err := badgeImage(id)
+	if errors.Is(err, storage.ErrBadgeClassNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Badge not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get badge image: " + err.Error()})
+		return
+	}
+	// Адрес изображения меняется при каждой загрузке
+	c.Header("Cache-Control", media.ImmutableCacheControl)
+	c.Data(http.StatusOK, "image/png", image)
+}
+
+// @Summary List awarded badges
+// @Description Lists the badges awarded from a badge class, including revoked ones (administrators and instructors)
+// @Tags Badges
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Badge ID"
+// @Success 200 {array} models.BadgeAssertion
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges/classes/{id}/assertions [get]
+func ListBadgeClassAssertions(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can manage badges"); !ok {
+		return
+	}
+	class, ok := loadBadgeClass(c)
+	if !ok {
+		return
+	}
+	assertions, err := Store.ListBadgeClassAssertions(class.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get badges: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, assertions)
+}
+
+// @Summary Award badge
+// @Description Awards a badge to a user (administrators and instructors). A revoked badge can be awarded again; it gets a new credential.
+// @Tags Badges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Badge ID"
+// @Param request body models.AwardBadgeRequest true "Recipient"
+// @Success 201 {object} models.Badge
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges/classes/{id}/assertions [post]
+func AwardBadge(c *gin.Context) {
+	userID, _, ok := requireInstructor(c, "Only administrators and instructors can manage badges")
+	if !ok {
+		return
+	}
+	class, ok := loadBadgeClass(c)
+	if !ok {
+		return
+	}
+	var req models.AwardBadgeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	recipient, err := Store.GetUserByID(req.UserID)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
+		return
+	}
+
+	assertion, err := issueBadge(class, recipient, &userID)
+	if errors.Is(err, storage.ErrBadgeAlreadyAwarded) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "The user already has this badge"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to award badge: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, badgeView(currentIssuer(), assertion))
+}
+
+// @Summary Revoke badge
+// @Description Revokes an awarded badge (administrators and instructors). The credential URL then answers 410 Gone, so verifiers see the badge as revoked.
+// @Tags Badges
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path string true "Credential ID"
+// @Param request body models.RevokeBadgeRequest false "Reason"
+// @Success 200 {object} models.BadgeAssertion
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges/assertions/{id}/revoke [post]
+func RevokeBadge(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can manage badges"); !ok {
+		return
+	}
+	var req models.RevokeBadgeRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+			return
+		}
+	}
+
+	assertion, err := Store.RevokeBadgeAssertion(c.Param("id"), req.Reason)
+	switch {
+	case errors.Is(err, storage.ErrBadgeAssertionNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Badge not found"})
+		return
+	case errors.Is(err, storage.ErrBadgeAlreadyRevoked):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Badge is already revoked"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to revoke badge: " + err.Error()})
+		return
+	}
+	assertion.BadgeClass = nil
+	c.JSON(http.StatusOK, assertion)
+}
+
+// @Summary List my badges
+// @Description Lists the badges awarded to the current user, newest first, with links to verify, download and add them to a LinkedIn profile. Revoked badges are included without a LinkedIn link.
+// @Tags Badges
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Badge
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /badges [get]
+func ListMyBadges(c *gin.Context) {
+	owned, err := Store.ListUserBadges(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get badges: " + err.Error()})
+		return
+	}
+	issuer := currentIssuer()
+	result := make([]models.Badge, len(owned))
+	for i, assertion := range owned {
+		result[i] = badgeView(issuer, assertion)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Get badge credential
+// @Description Returns the OpenBadgeCredential (Open Badges 3.0) of an awarded badge. Public: this is the credential ID that verifiers resolve. With format=jwt the credential is returned in its signed VC-JWT form (RS256; the key is published at /badges/jwks). A revoked badge answers 410 Gone.
+// @Tags Badges
+// @Produce json,application/vc+jwt
+// @Param id path string true "Credential ID"
+// @Param format query string false "Response format" Enums(json, jwt)
+// @Success 200 {object} object
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 410 {object} models.ErrorResponse
+// @Router /badges/assertions/{id} [get]
+func GetBadgeCredential(c *gin.Context) {
+	assertion, ok := loadBadgeAssertion(c)
+	if !ok {
+		return
+	}
+	if c.Query("format") == "jwt" {
+		c.Data(http.StatusOK, "application/vc+jwt", []byte(assertion.Credential))
+		return
+	}
+	credential, err := badges.Decode(assertion.Credential)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get badge: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, credential)
+}
+
+// @Summary Download badge
+// @Description Downloads an awarded badge. png (default) is the badge image with the signed credential baked into an iTXt chunk "openbadgecredential", as Open Badges 3.0 baking requires; json is the credential, jwt its signed form. Public, so that badge holders can share the link.
+// @Tags Badges
+// @Produce png,json,application/vc+jwt
+// @Param id path string true "Credential ID"
+// @Param format query string false "File format" Enums(png, json, jwt)
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 410 {object} models.ErrorResponse
+// @Router /badges/assertions/{id}/download [get]
+func DownloadBadge(c *gin.Context) {
+	format := c.DefaultQuery("format", "png")
+	if format != "png" && format != "json" && format != "jwt" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "format must be png, json or jwt"})
+		return
+	}
+	assertion, ok := loadBadgeAssertion(c)
+	if !ok {
+		return
+	}
+
+	filename := "badge-" + assertion.ID + "." + format
+	c.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	switch format {
+	case "jwt":
+		c.Data(http.StatusOK, "application/vc+jwt", []byte(assertion.Credential))
+	case "json":
+		credential, err := badges.Decode(assertion.Credential)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get badge: " + err.Error()})
+			return
+		}
+		c.IndentedJSON(http.StatusOK, credential)
+	default:
+		image, err := badgeImage(assertion.BadgeClassID)
+		if err == nil {
+			image, err = badges.Bake(image, assertion.Credential)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to bake badge: " + err.Error()})
+			return
+		}
+		c.Data(http.StatusOK, "image/png", image)
+	}
+}
+
+// @Summary Get badge issuer
+// @Description Returns the issuer profile referenced by every credential (Open Badges 3.0 Profile). Public.
+// @Tags Badges
+// @Produce json
+// @Success 200 {object} object
+// @Router /badges/issuer [get]
+func GetBadgeIssuer(c *gin.Context) {
+	c.JSON(http.StatusOK, currentIssuer().ProfileDocument())
+}
+
+// @Summary Get badge signing keys
+// @Description Returns the public keys that verify badge credentials as a JSON Web Key Set. Public.
+// @Tags Badges
+// @Produce json
+// @Success 200 {object} object
+// @Router /badges/jwks [get]
+func GetBadgeJWKS(c *gin.Context) {
+	c.JSON(http.StatusOK, currentIssuer().JWKS())
+}
@@ -0,0 +1,248 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/i18n"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/storage"
+)
+
+// @Summary List bookmarks
+// @Description Bookmarked courses and tasks of the current user, newest first. Titles are in the user's language; bookmarks of courses that are no longer available are omitted.
+// @Tags Bookmarks
+// @Produce json
+// @Success 200 {array} models.Bookmark
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /bookmarks [get]
+func ListBookmarks(c *gin.Context) {
+	bookmarks, err := Store.ListBookmarks(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list bookmarks: " + err.Error()})
+		return
+	}
+	courseIDs := make([]int, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		courseIDs = append(courseIDs, b.CourseID)
+	}
+	courses, err := StoreFor(c).GetCoursesByIDs(courseIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list bookmarks: " + err.Error()})
+		return
+	}
+	locale := RequestLocale(c)
+	for id, course := range courses {
+		if err := LocalizeCourse(locale, &course); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list bookmarks: " + err.Error()})
+			return
+		}
+		courses[id] = course
+	}
+
+	result := make([]models.Bookmark, 0, len(bookmarks))
+	for _, b := range bookmarks {
+		course, ok := courses[b.CourseID]
+		if !ok {
+			continue
+		}
+		b.CourseTitle, b.Title, b.Archived = course.VulnerabilityType, course.VulnerabilityType, course.ArchivedAt != nil
+		if b.TaskID != nil {
+			for _, task := range course.Tasks {
+				if task.ID == *b.TaskID {
+					b.Title = task.Title
+				}
+			}
+		}
+		result = append(result, b)
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Bookmark a course
+// @Description Adds the course to the current user's bookmarks or updates the bookmark. With notify the user is notified (Web Push and WebSocket) when the course returns to the catalog from the archive.
+// @Tags Bookmarks
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param request body models.SaveBookmarkRequest false "Bookmark options"
+// @Success 200 {object} models.Bookmark
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/bookmark [put]
+func BookmarkCourse(c *gin.Context) {
+	course, ok := visibleCourse(c)
+	if !ok {
+		return
+	}
+	var req models.SaveBookmarkRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+			return
+		}
+	}
+	saveBookmark(c, course, models.Bookmark{
+		UserID: c.GetInt("userID"), CourseID: course.ID, Notify: req.Notify,
+	})
+}
+
+// @Summary Bookmark a task
+// @Description Adds a task of the course to the current user's bookmarks
+// @Tags Bookmarks
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} models.Bookmark
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/tasks/{task_id}/bookmark [put]
+func BookmarkTask(c *gin.Context) {
+	course, task, ok := visibleCourseTask(c)
+	if !ok {
+		return
+	}
+	saveBookmark(c, course, models.Bookmark{UserID: c.GetInt("userID"), CourseID: course.ID, TaskID: &task.ID})
+}
+
+// saveBookmark сохраняет закладку и отвечает ею с названиями на языке запроса
+func saveBookmark(c *gin.Context, course models.Course, bookmark models.Bookmark) {
+	saved, err := Store.SaveBookmark(bookmark)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err == nil {
+		err = LocalizeCourse(RequestLocale(c), &course)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save bookmark: " + err.Error()})
+		return
+	}
+	saved.CourseTitle, saved.Title, saved.Archived = course.VulnerabilityType, course.VulnerabilityType, course.ArchivedAt != nil
+	for _, task := range course.Tasks {
+		if saved.TaskID != nil && task.ID == *saved.TaskID {
+			saved.Title = task.Title
+		}
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// @Summary Remove a course bookmark
+// @Tags Bookmarks
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/bookmark [delete]
+func DeleteCourseBookmark(c *gin.Context) {
+	deleteBookmark(c, false)
+}
+
+// @Summary Remove a task bookmark
+// @Tags Bookmarks
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/tasks/{task_id}/bookmark [delete]
+func DeleteTaskBookmark(c *gin.Context) {
+	deleteBookmark(c, true)
+}
+
+// deleteBookmark удаляет закладку на курс из пути запроса или, если forTask, на его задание
+func deleteBookmark(c *gin.Context, forTask bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	var taskID *int
+	if forTask {
+		id, err := strconv.Atoi(c.Param("task_id"))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+			return
+		}
+		taskID = &id
+	}
+	err = Store.DeleteBookmark(c.GetInt("userID"), courseID, taskID)
+	if errors.Is(err, storage.ErrBookmarkNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Bookmark not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete bookmark: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Bookmark removed")})
+}
+
+// notifyBookmarkedCourseRestored сообщает пользователям, попросившим об этом в закладке, что
+// курс вернулся в каталог. Вызывается в отдельной горутине после восстановления курса из архива;
+// store ограничен организацией курса, поэтому закладки пользователей других организаций не учитываются.
+func notifyBookmarkedCourseRestored(store storage.Storage, courseID int) {
+	userIDs, err := store.ListBookmarkSubscribers(courseID)
+	if err != nil {
+		log.Printf("Failed to list bookmark subscribers of course %d: %v", courseID, err)
+		return
+	}
+	if len(userIDs) == 0 {
+		return
+	}
+	course, err := store.GetCourseByID(courseID)
+	if err != nil {
+		log.Printf("Failed to get course %d for bookmark notifications: %v", courseID, err)
+		return
+	}
+	users, err := Store.GetUsersByIDs(userIDs)
+	if err != nil {
+		log.Printf("Failed to get bookmark subscribers of course %d: %v", courseID, err)
+		return
+	}
+	client, cfg := currentWebPush()
+	for _, userID := range userIDs {
+		user, ok := users[userID]
+		if !ok || !user.IsActive {
+			continue
+		}
+		localized := course
+		if err := LocalizeCourse(UserLocale(user.Locale), &localized); err != nil {
+			log.Printf("Failed to localize course %d for bookmark notification: %v", courseID, err)
+			continue
+		}
+		if Realtime != nil {
+			Realtime.Broadcast(realtime.UserTopic(userID), "bookmark.course_available", gin.H{
+				"courseId": courseID, "title": localized.VulnerabilityType,
+			})
+		}
+		if client == nil {
+			continue
+		}
+		_, err := sendPush(client, cfg.TTL, userID, models.PushNotification{
+			Title: i18n.Translate(UserLocale(user.Locale), "Bookmarked course is available"),
+			Body:  "🔖 " + localized.VulnerabilityType,
+			Tag:   "bookmark-" + strconv.Itoa(courseID),
+			URL:   pushCoursesPath,
+		})
+		if err != nil {
+			log.Printf("Failed to send bookmark notification to user %d: %v", userID, err)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package handlers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lmsmodule/backend-svc/badges"
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/media"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxLogoBytes ограничивает размер логотипа организации
+const maxLogoBytes = 512 << 10
+
+// defaultCertificateTemplate — сертификат для организаций без собственного шаблона
+const defaultCertificateTemplate = `<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.CourseTitle}} — {{.IssuedTo}}</title></head>
+<body style="font-family: Georgia, serif; text-align: center; padding: 48px;">
+<div style="border: 8px solid {{if .PrimaryColor}}{{.PrimaryColor}}{{else}}#1a4d8f{{end}}; padding: 48px;">
+{{if .LogoURL}}<img src="{{.LogoURL}}" alt="{{.OrganizationName}}" style="max-height: 80px;">{{else}}<h3>{{.OrganizationName}}</h3>{{end}}
+<h1>Certificate of completion</h1>
+<p>This certifies that</p>
+<h2 style="color: {{if .AccentColor}}{{.AccentColor}}{{else}}#333333{{end}};">{{.IssuedTo}}</h2>
+<p>has completed the course</p>
+<h2>{{.CourseTitle}}</h2>
+<p>{{.IssuedAt}}</p>
+{{if .Revoked}}<p><strong>This certificate has been revoked.</strong></p>{{end}}
+<p style="font-size: small;">{{.Code}} · <a href="{{.VerificationURL}}">{{.VerificationURL}}</a></p>
+</div>
+</body></html>`
+
+// certificateDocument — данные, доступные шаблону сертификата
+type certificateDocument struct {
+	IssuedTo, CourseTitle, IssuedAt, Code, VerificationURL string
+	OrganizationName, LogoURL, PrimaryColor, AccentColor   string
+	Revoked                                                bool
+}
+
+// parseCertificateTemplate разбирает шаблон сертификата и пробует заполнить его примером,
+// чтобы ошибки в шаблоне обнаруживались при сохранении, а не при выдаче сертификата
+func parseCertificateTemplate(src string) (*template.Template, error) {
+	tmpl, err := template.New("certificate").Parse(src)
+	if err != nil {
+		return nil, err
+	}
+	sample := certificateDocument{
+		IssuedTo: "Alice Ivanova", CourseTitle: "SQL Injection", IssuedAt: "2025-01-31", Code: "7K3F-9QX2-M4PD",
+		VerificationURL: "https://lms.example.com/api/certificates/verify/7K3F-9QX2-M4PD", OrganizationName: "ACME",
+	}
+	if err := tmpl.Execute(io.Discard, sample); err != nil {
+		return nil, err
+	}
+	return tmpl, nil
+}
+
+var defaultCertificate = template.Must(parseCertificateTemplate(defaultCertificateTemplate))
+
+// brandingView дополняет оформление адресом логотипа
+func brandingView(branding models.OrganizationBranding) models.OrganizationBranding {
+	if branding.HasLogo {
+		branding.LogoURL = currentPublicURL() + "/api/organizations/" + strconv.Itoa(branding.OrganizationID) +
+			"/logo?v=" + strconv.FormatInt(branding.UpdatedAt.Unix(), 10)
+	}
+	return branding
+}
+
+// EmailBranding возвращает оформление писем для адреса email по организации его владельца.
+// Адреса, не принадлежащие пользователям (например, новый адрес при смене email), получают
+// письма без оформления.
+func EmailBranding(email string) mail.Branding {
+	branding, err := Store.GetEmailBranding(email)
+	if err != nil {
+		if !errors.Is(err, storage.ErrUserNotFound) {
+			log.Printf("Failed to get email branding: %v", err)
+		}
+		return mail.Branding{}
+	}
+	branding = brandingView(branding)
+	return mail.Branding{
+		OrganizationName: branding.OrganizationName,
+		LogoURL:          branding.LogoURL,
+		PrimaryColor:     branding.PrimaryColor,
+		AccentColor:      branding.AccentColor,
+		Footer:           branding.EmailFooter,
+	}
+}
+
+// getBranding отвечает оформлением организации orgID
+func getBranding(c *gin.Context, orgID int) {
+	branding, err := Store.GetOrganizationBranding(orgID)
+	if err != nil {
+		respondBrandingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, brandingView(branding))
+}
+
+// saveBranding сохраняет оформление организации orgID из тела запроса
+func saveBranding(c *gin.Context, orgID int) {
+	var req models.SaveBrandingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if req.CertificateTemplate != "" {
+		if _, err := parseCertificateTemplate(req.CertificateTemplate); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid certificate template: " + err.Error()})
+			return
+		}
+	}
+
+	branding, err := Store.SaveOrganizationBranding(models.OrganizationBranding{
+		OrganizationID:      orgID,
+		PrimaryColor:        req.PrimaryColor,
+		AccentColor:         req.AccentColor,
+		EmailFooter:         req.EmailFooter,
+		CertificateTemplate: req.CertificateTemplate,
+	})
+	if err != nil {
+		respondBrandingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, brandingView(branding))
+}
+
+// uploadLogo сохраняет логотип организации orgID
+func uploadLogo(c *gin.Context, orgID int) {
+	data, err := readUpload(c, "logo", maxLogoBytes)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || len(data) > maxLogoBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: fmt.Sprintf("Logo must not exceed %d bytes", maxLogoBytes)})
+		return
+	}
+	if err != nil || len(data) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Upload an image in the \"logo\" form field or as the request body"})
+		return
+	}
+	if err := badges.CheckImage(data); errors.Is(err, badges.ErrInvalidImage) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Logo must be a PNG"})
+		return
+	} else if err != nil {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: "Image dimensions are too large"})
+		return
+	}
+	setLogo(c, orgID, data)
+}
+
+// setLogo сохраняет или удаляет (nil) логотип и отвечает оформлением
+func setLogo(c *gin.Context, orgID int, logo []byte) {
+	branding, err := Store.SetOrganizationLogo(orgID, logo)
+	if err != nil {
+		respondBrandingError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, brandingView(branding))
+}
+
+func respondBrandingError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrOrganizationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Organization not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save branding: " + err.Error()})
+}
+
+// brandingOrganizationID разбирает ID организации из пути административного маршрута
+func brandingOrganizationID(c *gin.Context) (int, bool) {
+	orgID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid organization ID"})
+		return 0, false
+	}
+	return orgID, true
+}
+
+// @Summary My organization's branding
+// @Description Logo, colors, email footer and certificate template of the current user's organization. Empty fields mean the default look.
+// @Tags Organization
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.OrganizationBranding
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/branding [get]
+func GetMyBranding(c *gin.Context) {
+	getBranding(c, c.GetInt("organizationID"))
+}
+
+// @Summary Update my organization's branding
+// @Description Set the colors, email footer and certificate template of the current user's organization (organization admins only). Colors are #rrggbb. The certificate template is a Go html/template with the fields IssuedTo, CourseTitle, IssuedAt, Code, VerificationURL, OrganizationName, LogoURL, PrimaryColor, AccentColor and Revoked; an empty template restores the default certificate.
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SaveBrandingRequest true "Branding"
+// @Success 200 {object} models.OrganizationBranding
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/branding [put]
+func UpdateMyBranding(c *gin.Context) {
+	if orgID, ok := requireOrganizationAdmin(c); ok {
+		saveBranding(c, orgID)
+	}
+}
+
+// @Summary Upload my organization's logo
+// @Description Upload the logo shown in emails and certificates as a multipart field "logo" or as the raw request body (organization admins only). The logo must be a PNG of at most 2048x2048 pixels and 512 KB. The logo URL changes with every upload.
+// @Tags Organization
+// @Accept multipart/form-data,image/png
+// @Produce json
+// @Security BearerAuth
+// @Param logo formData file false "PNG logo"
+// @Success 200 {object} models.OrganizationBranding
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/branding/logo [put]
+func UploadMyLogo(c *gin.Context) {
+	if orgID, ok := requireOrganizationAdmin(c); ok {
+		uploadLogo(c, orgID)
+	}
+}
+
+// @Summary Remove my organization's logo
+// @Description Remove the logo of the current user's organization (organization admins only)
+// @Tags Organization
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.OrganizationBranding
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/branding/logo [delete]
+func DeleteMyLogo(c *gin.Context) {
+	if orgID, ok := requireOrganizationAdmin(c); ok {
+		setLogo(c, orgID, nil)
+	}
+}
+
+// @Summary Get organization branding
+// @Description Branding of any organization (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Success 200 {object} models.OrganizationBranding
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations/{id}/branding [get]
+func GetOrganizationBranding(c *gin.Context) {
+	if orgID, ok := brandingOrganizationID(c); ok {
+		getBranding(c, orgID)
+	}
+}
+
+// @Summary Update organization branding
+// @Description Set the colors, email footer and certificate template of any organization (admin only); see PUT /org/branding
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param request body models.SaveBrandingRequest true "Branding"
+// @Success 200 {object} models.OrganizationBranding
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations/{id}/branding [put]
+func UpdateOrganizationBranding(c *gin.Context) {
+	if orgID, ok := brandingOrganizationID(c); ok {
+		saveBranding(c, orgID)
+	}
+}
+
+// @Summary Upload organization logo
+// @Description Upload the logo of any organization (admin only); see PUT /org/branding/logo
+// @Tags Admin
+// @Accept multipart/form-data,image/png
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param logo formData file false "PNG logo"
+// @Success 200 {object} models.OrganizationBranding
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations/{id}/branding/logo [put]
+func UploadOrganizationLogo(c *gin.Context) {
+	if orgID, ok := brandingOrganizationID(c); ok {
+		uploadLogo(c, orgID)
+	}
+}
+
+// @Summary Remove organization logo
+// @Description Remove the logo of any organization (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Success 200 {object} models.OrganizationBranding
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations/{id}/branding/logo [delete]
+func DeleteOrganizationLogo(c *gin.Context) {
+	if orgID, ok := brandingOrganizationID(c); ok {
+		setLogo(c, orgID, nil)
+	}
+}
+
+// @Summary Get organization logo
+// @Description Returns the organization logo (PNG). Public: the URL is embedded in emails and certificates.
+// @Tags Organization
+// @Produce png
+// @Param id path int true "Organization ID"
+// @Success 200 {file} binary
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Router /organizations/{id}/logo [get]
+func OrganizationLogo(c *gin.Context) {
+	orgID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid organization ID"})
+		return
+	}
+	logo, err := Store.GetOrganizationLogo(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get logo: " + err.Error()})
+		return
+	}
+	if logo == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Logo not found"})
+		return
+	}
+	// Адрес логотипа меняется при каждой загрузке
+	c.Header("Cache-Control", media.ImmutableCacheControl)
+	c.Data(http.StatusOK, "image/png", logo)
+}
+
+// @Summary Printable certificate
+// @Description Renders the certificate as an HTML page for printing, in the branding and certificate template of the recipient's organization. Public like the verification page; revoked certificates are marked as revoked.
+// @Tags Certificates
+// @Produce html
+// @Param code path string true "Certificate code" example(7K3F-9QX2-M4PD)
+// @Success 200 {string} string "HTML certificate"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /certificates/document/{code} [get]
+func CertificateDocument(c *gin.Context) {
+	cert, ok := loadCertificate(c)
+	if !ok {
+		return
+	}
+	cert = certificateView(cert)
+	branding, err := Store.GetUserBranding(cert.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get certificate: " + err.Error()})
+		return
+	}
+	branding = brandingView(branding)
+
+	tmpl := defaultCertificate
+	if branding.CertificateTemplate != "" {
+		// Шаблон проверен при сохранении; ошибка здесь значит, что он сломан иначе, и сертификат
+		// все равно должен открываться
+		if custom, err := parseCertificateTemplate(branding.CertificateTemplate); err == nil {
+			tmpl = custom
+		} else {
+			log.Printf("Invalid certificate template of organization %d: %v", branding.OrganizationID, err)
+		}
+	}
+	var page bytes.Buffer
+	err = tmpl.Execute(&page, certificateDocument{
+		IssuedTo:         cert.IssuedTo,
+		CourseTitle:      cert.CourseTitle,
+		IssuedAt:         cert.IssuedAt.Format(time.DateOnly),
+		Code:             cert.Code,
+		VerificationURL:  cert.VerificationURL,
+		OrganizationName: branding.OrganizationName,
+		LogoURL:          branding.LogoURL,
+		PrimaryColor:     branding.PrimaryColor,
+		AccentColor:      branding.AccentColor,
+		Revoked:          cert.RevokedAt != nil,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to render certificate: " + err.Error()})
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", page.Bytes())
+}
@@ -0,0 +1,66 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// @Summary Bulk update users
+// @Description Change the role, groups and assigned courses of many users at once (admin only). Select users either by userIds or by filter (query, group, isAdmin, isActive, organizationId; at most 1000 users). All changes are applied in one transaction and the response reports, per user, what changed; actions that do not apply to a user (a group or course of another organization, changing your own role) are listed in its errors without failing the operation.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BulkUserRequest true "Users and actions"
+// @Success 200 {object} models.BulkUserResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "A group or course does not exist"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/bulk [post]
+func BulkUpdateUsers(c *gin.Context) {
+	var req models.BulkUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if (len(req.UserIDs) == 0) == (req.Filter == nil) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Specify either userIds or filter"})
+		return
+	}
+	if req.Role == "" && len(req.AddGroups) == 0 && len(req.RemoveGroups) == 0 &&
+		len(req.AssignCourses) == 0 && len(req.UnassignCourses) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "No changes requested"})
+		return
+	}
+
+	results, err := Store.BulkUpdateUsers(req, c.GetInt("userID"))
+	switch {
+	case errors.Is(err, storage.ErrGroupNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Group not found"})
+		return
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	case errors.Is(err, storage.ErrTooManyUsers):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The filter matches too many users, narrow it down"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update users: " + err.Error()})
+		return
+	}
+
+	response := models.BulkUserResponse{Matched: len(results), Results: results}
+	for _, result := range results {
+		if result.Status == models.BulkUserUpdated {
+			response.Updated++
+			// Действие могло изменить группы пользователя
+			forgetRoles(result.UserID)
+		}
+	}
+	c.JSON(http.StatusOK, response)
+}
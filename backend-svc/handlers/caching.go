@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"hash/fnv"
+	"lmsmodule/backend-svc/models"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// checkNotModified выставляет ETag и Last-Modified и проверяет условные заголовки запроса.
+// Возвращает true, если клиентская копия актуальна и ответ 304 уже отправлен.
+func checkNotModified(c *gin.Context, etag string, lastModified time.Time) bool {
+	c.Header("ETag", etag)
+	c.Header("Cache-Control", "no-cache")
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	// If-None-Match имеет приоритет над If-Modified-Since (RFC 9110)
+	if match := c.GetHeader("If-None-Match"); match != "" {
+		for _, candidate := range strings.Split(match, ",") {
+			candidate = strings.TrimSpace(candidate)
+			if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+				c.Status(http.StatusNotModified)
+				return true
+			}
+		}
+		return false
+	}
+
+	if since := c.GetHeader("If-Modified-Since"); since != "" && !lastModified.IsZero() {
+		if t, err := http.ParseTime(since); err == nil && !lastModified.Truncate(time.Second).After(t) {
+			c.Status(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+// userVersionETag — ETag пользователя по его версии; его можно вернуть в If-Match при изменении
+func userVersionETag(version int) string {
+	return `"` + strconv.Itoa(version) + `"`
+}
+
+// requireUserVersion возвращает версию пользователя, которую прочитал клиент: из тела запроса
+// или из заголовка If-Match. Без нее отвечает 428, чтобы параллельные правки не перезаписывали
+// друг друга молча. Возвращает false, если ответ уже отправлен.
+func requireUserVersion(c *gin.Context, bodyVersion int) (int, bool) {
+	if bodyVersion > 0 {
+		return bodyVersion, true
+	}
+	if match := strings.TrimSpace(c.GetHeader("If-Match")); match != "" {
+		version, err := strconv.Atoi(strings.Trim(strings.TrimPrefix(match, "W/"), `"`))
+		if err != nil || version <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "If-Match must be the user ETag returned by a previous response"})
+			return 0, false
+		}
+		return version, true
+	}
+	c.JSON(http.StatusPreconditionRequired, models.ErrorResponse{
+		Error: "Pass the user version you read in the version field or the If-Match header",
+	})
+	return 0, false
+}
+
+// catalogValidators строит ETag и Last-Modified списка курсов: ETag меняется при изменении
+// любого курса, его показателей, а также при добавлении или удалении курсов и заданий.
+// variant различает представления одного каталога (например, разные наборы полей).
+func catalogValidators(courses []models.Course, variant string) (string, time.Time) {
+	var lastModified time.Time
+	var tasks int
+	// Записи на курс и отзывы не меняют updated_at курса, поэтому входят в ETag отдельно
+	stats := fnv.New64a()
+	for _, course := range courses {
+		if course.UpdatedAt.After(lastModified) {
+			lastModified = course.UpdatedAt
+		}
+		tasks += course.TasksCount
+		fmt.Fprintf(stats, "%d:%s;", course.ID, courseStats(course))
+	}
+	if variant != "" {
+		return fmt.Sprintf(`W/"courses-%d-%d-%d-%x-%s"`, len(courses), tasks, lastModified.UnixNano(), stats.Sum64(), variant), lastModified
+	}
+	return fmt.Sprintf(`W/"courses-%d-%d-%d-%x"`, len(courses), tasks, lastModified.UnixNano(), stats.Sum64()), lastModified
+}
+
+// courseValidators строит ETag и Last-Modified карточки курса на языке locale
+func courseValidators(course models.Course, locale string) (string, time.Time) {
+	return fmt.Sprintf(`W/"course-%d-%d-%d-%s-%s"`, course.ID, course.TasksCount, course.UpdatedAt.UnixNano(), courseStats(course), locale), course.UpdatedAt
+}
+
+// courseStats — показатели сводки курса для ETag
+func courseStats(course models.Course) string {
+	rating := "0"
+	if course.AverageRating != nil {
+		rating = strconv.FormatFloat(*course.AverageRating, 'f', 4, 64)
+	}
+	return fmt.Sprintf("%d-%d-%s", course.Enrollments, course.Reviews, rating)
+}
@@ -0,0 +1,566 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+const (
+	// defaultCalendarRange — интервал календаря, если клиент не указал to
+	defaultCalendarRange = 30 * 24 * time.Hour
+	maxCalendarRange     = 366 * 24 * time.Hour
+	// Ссылка на календарь отдает прошедший месяц и год вперед
+	feedPast   = 30 * 24 * time.Hour
+	feedFuture = 365 * 24 * time.Hour
+)
+
+func newCalendarFeedToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate calendar feed token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// calendarCourseIDs возвращает курсы, события которых попадают в календарь пользователя:
+// преподавателям — все (nil), студентам — начатые
+func calendarCourseIDs(userID int) ([]int, error) {
+	_, isInstructor, err := CheckInstructorRights(userID)
+	if err != nil || isInstructor {
+		return nil, err
+	}
+	return Store.GetStartedCourseIDs(userID)
+}
+
+// localizedTaskTitles возвращает переводы названий заданий из расписаний на язык locale
+func localizedTaskTitles(locale string, schedules []models.TaskSchedule) (map[int]string, error) {
+	titles := make(map[int]string, len(schedules))
+	courses := map[int]bool{}
+	for _, schedule := range schedules {
+		titles[schedule.TaskID] = schedule.TaskTitle
+		courses[schedule.CourseID] = true
+	}
+	for courseID := range courses {
+		for _, l := range fallbackLocales(locale) {
+			translations, err := Store.GetTaskTranslations(courseID, l)
+			if err != nil {
+				return nil, err
+			}
+			for taskID, t := range translations {
+				if _, ok := titles[taskID]; ok && t.Title != "" {
+					titles[taskID] = t.Title
+				}
+			}
+		}
+	}
+	return titles, nil
+}
+
+// localizedCourseTitles возвращает названия курсов расписаний на языке locale; курсы
+// загружаются одним запросом
+func localizedCourseTitles(locale string, schedules []models.TaskSchedule) (map[int]string, error) {
+	courseIDs := make([]int, 0, len(schedules))
+	for _, schedule := range schedules {
+		courseIDs = append(courseIDs, schedule.CourseID)
+	}
+	return localizedTitles(locale, courseIDs)
+}
+
+// localizedTitles возвращает названия курсов courseIDs на языке locale
+func localizedTitles(locale string, courseIDs []int) (map[int]string, error) {
+	byID, err := Store.GetCoursesByIDs(courseIDs)
+	if err != nil {
+		return nil, err
+	}
+	courses := make([]models.Course, 0, len(byID))
+	for _, course := range byID {
+		courses = append(courses, course)
+	}
+	if err := LocalizeCourses(locale, courses); err != nil {
+		return nil, err
+	}
+	titles := make(map[int]string, len(courses))
+	for _, course := range courses {
+		titles[course.ID] = course.VulnerabilityType
+	}
+	return titles, nil
+}
+
+// buildCalendar собирает события курсов courseIDs в интервале [from, to) по времени начала
+func buildCalendar(c *gin.Context, courseIDs []int, from, to time.Time) ([]models.CalendarEvent, error) {
+	schedules, err := Store.ListTaskSchedules(courseIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	sessions, err := Store.ListLiveSessions(courseIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	titles, err := localizedTaskTitles(RequestLocale(c), schedules)
+	if err != nil {
+		return nil, err
+	}
+
+	events := []models.CalendarEvent{}
+	for _, schedule := range schedules {
+		taskID := schedule.TaskID
+		if schedule.ReleaseAt != nil && !schedule.ReleaseAt.Before(from) && schedule.ReleaseAt.Before(to) {
+			events = append(events, models.CalendarEvent{
+				ID:       fmt.Sprintf("release-%d", taskID),
+				Type:     models.CalendarEventRelease,
+				Title:    Translatef(c, "Task opens: %s", titles[taskID]),
+				CourseID: schedule.CourseID,
+				TaskID:   &taskID,
+				StartsAt: *schedule.ReleaseAt,
+			})
+		}
+		if schedule.DueAt != nil && !schedule.DueAt.Before(from) && schedule.DueAt.Before(to) {
+			events = append(events, models.CalendarEvent{
+				ID:       fmt.Sprintf("deadline-%d", taskID),
+				Type:     models.CalendarEventDeadline,
+				Title:    Translatef(c, "Deadline: %s", titles[taskID]),
+				CourseID: schedule.CourseID,
+				TaskID:   &taskID,
+				StartsAt: *schedule.DueAt,
+			})
+		}
+	}
+	for _, session := range sessions {
+		sessionID, endsAt := session.ID, session.EndsAt
+		events = append(events, models.CalendarEvent{
+			ID:          fmt.Sprintf("session-%d", sessionID),
+			Type:        models.CalendarEventLiveSession,
+			Title:       session.Title,
+			Description: session.Description,
+			CourseID:    session.CourseID,
+			SessionID:   &sessionID,
+			StartsAt:    session.StartsAt,
+			EndsAt:      &endsAt,
+			URL:         session.JoinURL,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].StartsAt.Before(events[j].StartsAt) })
+	return events, nil
+}
+
+// icalEscape экранирует текстовое значение свойства iCalendar (RFC 5545, 3.3.11)
+func icalEscape(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// icalLine дописывает свойство, перенося строки длиннее 75 байт (RFC 5545, 3.1)
+func icalLine(b *strings.Builder, line string) {
+	for len(line) > 75 {
+		cut := 75
+		for cut > 0 && line[cut]&0xC0 == 0x80 { // не разрываем символ UTF-8
+			cut--
+		}
+		b.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+	}
+	b.WriteString(line + "\r\n")
+}
+
+// renderICal формирует календарь в формате iCalendar; время событий указывается в UTC
+func renderICal(name, timezone string, events []models.CalendarEvent) string {
+	const stamp = "20060102T150405Z"
+	now := time.Now().UTC().Format(stamp)
+	host := "lms"
+	if u, err := url.Parse(currentPublicURL()); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+
+	var b strings.Builder
+	icalLine(&b, "BEGIN:VCALENDAR")
+	icalLine(&b, "VERSION:2.0")
+	icalLine(&b, "PRODID:-//LMS//Calendar//EN")
+	icalLine(&b, "CALSCALE:GREGORIAN")
+	icalLine(&b, "METHOD:PUBLISH")
+	icalLine(&b, "X-WR-CALNAME:"+icalEscape(name))
+	icalLine(&b, "X-WR-TIMEZONE:"+timezone)
+	for _, event := range events {
+		icalLine(&b, "BEGIN:VEVENT")
+		icalLine(&b, "UID:"+event.ID+"@"+host)
+		icalLine(&b, "DTSTAMP:"+now)
+		icalLine(&b, "DTSTART:"+event.StartsAt.UTC().Format(stamp))
+		if event.EndsAt != nil {
+			icalLine(&b, "DTEND:"+event.EndsAt.UTC().Format(stamp))
+		}
+		icalLine(&b, "SUMMARY:"+icalEscape(event.Title))
+		if event.Description != "" {
+			icalLine(&b, "DESCRIPTION:"+icalEscape(event.Description))
+		}
+		if event.URL != "" {
+			icalLine(&b, "URL:"+event.URL)
+		}
+		icalLine(&b, "CATEGORIES:"+strings.ToUpper(event.Type))
+		icalLine(&b, "END:VEVENT")
+	}
+	icalLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+// parseCalendarRange читает интервал календаря из параметров from и to (RFC 3339)
+func parseCalendarRange(c *gin.Context) (from, to time.Time, ok bool) {
+	from = time.Now().UTC()
+	if raw := c.Query("from"); raw != "" {
+		var err error
+		if from, err = time.Parse(time.RFC3339, raw); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "from and to must be RFC 3339 timestamps"})
+			return from, to, false
+		}
+	}
+	to = from.Add(defaultCalendarRange)
+	if raw := c.Query("to"); raw != "" {
+		var err error
+		if to, err = time.Parse(time.RFC3339, raw); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "from and to must be RFC 3339 timestamps"})
+			return from, to, false
+		}
+	}
+	if !to.After(from) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "to must be after from"})
+		return from, to, false
+	}
+	if to.Sub(from) > maxCalendarRange {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The calendar range must not exceed 366 days"})
+		return from, to, false
+	}
+	return from, to, true
+}
+
+// @Summary My calendar
+// @Description Task deadlines, task releases and live sessions between from and to (default: the next 30 days). Students see the courses they have started, instructors all courses; courseId limits the calendar to one course.
+// @Tags Calendar
+// @Produce json
+// @Param from query string false "Start of the range, RFC 3339 (default now)"
+// @Param to query string false "End of the range, RFC 3339 (default from + 30 days, at most 366 days)"
+// @Param courseId query int false "Course ID"
+// @Success 200 {array} models.CalendarEvent
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /calendar [get]
+func GetCalendar(c *gin.Context) {
+	from, to, ok := parseCalendarRange(c)
+	if !ok {
+		return
+	}
+
+	var courseIDs []int
+	if raw := c.Query("courseId"); raw != "" {
+		courseID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+			return
+		}
+		courseIDs = []int{courseID}
+	} else {
+		var err error
+		if courseIDs, err = calendarCourseIDs(c.GetInt("userID")); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to build calendar: " + err.Error()})
+			return
+		}
+	}
+
+	events, err := buildCalendar(c, courseIDs, from, to)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to build calendar: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, events)
+}
+
+// @Summary Create calendar feed link
+// @Description Creates a secret iCalendar URL for Google Calendar, Outlook and other clients; a new link replaces the previous one
+// @Tags Calendar
+// @Produce json
+// @Success 201 {object} models.CalendarFeed
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /calendar/feed [post]
+func CreateCalendarFeed(c *gin.Context) {
+	userID := c.GetInt("userID")
+	token, err := newCalendarFeedToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create calendar link: " + err.Error()})
+		return
+	}
+	if err := Store.SetCalendarFeedToken(userID, hashToken(token)); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create calendar link: " + err.Error()})
+		return
+	}
+	log.Printf("User %d created a calendar feed link", userID)
+	c.JSON(http.StatusCreated, models.CalendarFeed{URL: currentPublicURL() + "/api/calendar/feed/" + token})
+}
+
+// @Summary Revoke calendar feed link
+// @Tags Calendar
+// @Produce json
+// @Success 200 {object} models.SuccessResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /calendar/feed [delete]
+func DeleteCalendarFeed(c *gin.Context) {
+	err := Store.DeleteCalendarFeedToken(c.GetInt("userID"))
+	if errors.Is(err, storage.ErrCalendarFeedNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Calendar link not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to revoke calendar link: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Calendar link revoked")})
+}
+
+// @Summary Calendar feed
+// @Description iCalendar feed of the link owner: the past 30 days and the next year. The secret token in the path replaces authentication, since calendar clients cannot send a bearer token.
+// @Tags Calendar
+// @Produce text/calendar
+// @Param token path string true "Calendar link token"
+// @Success 200 {string} string "iCalendar data"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /calendar/feed/{token} [get]
+func CalendarFeed(c *gin.Context) {
+	userID, err := Store.GetCalendarFeedUser(hashToken(c.Param("token")))
+	if errors.Is(err, storage.ErrCalendarFeedNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Calendar link not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to build calendar: " + err.Error()})
+		return
+	}
+	user, err := Store.GetUserByID(userID)
+	if err != nil || !user.IsActive {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Calendar link not found"})
+		return
+	}
+	// Язык событий берется из профиля владельца ссылки
+	c.Set("userID", userID)
+
+	courseIDs, err := calendarCourseIDs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to build calendar: " + err.Error()})
+		return
+	}
+	now := time.Now().UTC()
+	events, err := buildCalendar(c, courseIDs, now.Add(-feedPast), now.Add(feedFuture))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to build calendar: " + err.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "private, max-age=900")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8",
+		[]byte(renderICal(Translate(c, "LMS deadlines"), UserTimezone(user.Timezone), events)))
+}
+
+// @Summary Set task schedule
+// @Description Sets when a task opens and when it is due; these dates appear in the calendars of course participants (administrators and instructors)
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param request body models.TaskScheduleRequest true "Schedule"
+// @Success 200 {object} models.TaskSchedule
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /tasks/{id}/schedule [put]
+func SetTaskSchedule(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can schedule tasks"); !ok {
+		return
+	}
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+		return
+	}
+	var req models.TaskScheduleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if req.ReleaseAt != nil && req.DueAt != nil && !req.DueAt.After(*req.ReleaseAt) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "dueAt must be after releaseAt"})
+		return
+	}
+
+	schedule, err := StoreFor(c).SetTaskSchedule(models.TaskSchedule{TaskID: taskID, ReleaseAt: req.ReleaseAt, DueAt: req.DueAt})
+	if errors.Is(err, storage.ErrTaskNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Task not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save schedule: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, schedule)
+}
+
+// liveSessionFromRequest проверяет запрос и переносит его поля в занятие
+func liveSessionFromRequest(c *gin.Context, session *models.LiveSession) bool {
+	var req models.LiveSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return false
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "endsAt must be after startsAt"})
+		return false
+	}
+
+	session.CourseID = req.CourseID
+	session.Title = req.Title
+	session.Description = req.Description
+	session.StartsAt = req.StartsAt.UTC()
+	session.EndsAt = req.EndsAt.UTC()
+	session.JoinURL = req.JoinURL
+	return true
+}
+
+// @Summary Create live session
+// @Description Schedules a live session for a course; it appears in the calendars of course participants (administrators and instructors)
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param request body models.LiveSessionRequest true "Live session"
+// @Success 201 {object} models.LiveSession
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /live-sessions [post]
+func CreateLiveSession(c *gin.Context) {
+	userID, _, ok := requireInstructor(c, "Only administrators and instructors can manage live sessions")
+	if !ok {
+		return
+	}
+	session := models.LiveSession{HostID: &userID}
+	if !liveSessionFromRequest(c, &session) {
+		return
+	}
+
+	created, err := Store.CreateLiveSession(session)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create live session: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, created)
+}
+
+// editableLiveSession загружает занятие, которое пользователь может изменять:
+// администратор — любое, преподаватель — свое
+func editableLiveSession(c *gin.Context) (models.LiveSession, bool) {
+	userID, isAdmin, ok := requireInstructor(c, "Only administrators and instructors can manage live sessions")
+	if !ok {
+		return models.LiveSession{}, false
+	}
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid live session ID"})
+		return models.LiveSession{}, false
+	}
+
+	session, err := Store.GetLiveSession(id)
+	if errors.Is(err, storage.ErrLiveSessionNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Live session not found"})
+		return models.LiveSession{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get live session: " + err.Error()})
+		return models.LiveSession{}, false
+	}
+	if !isAdmin && (session.HostID == nil || *session.HostID != userID) {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Instructors can only change their own live sessions"})
+		return models.LiveSession{}, false
+	}
+	return session, true
+}
+
+// @Summary Update live session
+// @Tags Calendar
+// @Accept json
+// @Produce json
+// @Param id path int true "Live session ID"
+// @Param request body models.LiveSessionRequest true "Live session"
+// @Success 200 {object} models.LiveSession
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /live-sessions/{id} [put]
+func UpdateLiveSession(c *gin.Context) {
+	session, ok := editableLiveSession(c)
+	if !ok || !liveSessionFromRequest(c, &session) {
+		return
+	}
+
+	updated, err := Store.UpdateLiveSession(session)
+	switch {
+	case errors.Is(err, storage.ErrLiveSessionNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Live session not found"})
+		return
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update live session: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// @Summary Delete live session
+// @Tags Calendar
+// @Produce json
+// @Param id path int true "Live session ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /live-sessions/{id} [delete]
+func DeleteLiveSession(c *gin.Context) {
+	session, ok := editableLiveSession(c)
+	if !ok {
+		return
+	}
+
+	err := Store.DeleteLiveSession(session.ID)
+	if errors.Is(err, storage.ErrLiveSessionNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Live session not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete live session: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Live session deleted")})
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/captcha"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/models"
+)
+
+var (
+	captchaMu       sync.RWMutex
+	captchaVerifier captcha.Verifier = captcha.Disabled()
+	captchaSiteKey  string
+	loginFailures   = newFailureTracker(3, 15*time.Minute)
+)
+
+// ConfigureCaptcha выбирает провайдера CAPTCHA и пороги для входа.
+// Безопасно вызывать повторно во время работы при ротации секретов.
+func ConfigureCaptcha(cfg config.CaptchaConfig) error {
+	verifier, err := captcha.New(cfg.Provider, cfg.Secret)
+	if err != nil {
+		return err
+	}
+	captchaMu.Lock()
+	captchaVerifier = verifier
+	captchaSiteKey = cfg.SiteKey
+	captchaMu.Unlock()
+	loginFailures.configure(cfg.LoginFailureThreshold, cfg.LoginFailureWindow)
+	return nil
+}
+
+// currentCaptcha возвращает проверяющего и ключ виджета; nil, если CAPTCHA отключена
+func currentCaptcha() (captcha.Verifier, string) {
+	captchaMu.RLock()
+	defer captchaMu.RUnlock()
+	if captchaVerifier.Provider() == captcha.ProviderNone {
+		return nil, ""
+	}
+	return captchaVerifier, captchaSiteKey
+}
+
+// verifyCaptcha проверяет токен и при ошибке отвечает клиенту. Возвращает false, если запрос отклонен.
+func verifyCaptcha(c *gin.Context, verifier captcha.Verifier, siteKey, token string) bool {
+	err := verifier.Verify(c.Request.Context(), token, c.ClientIP())
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, captcha.ErrMissingToken), errors.Is(err, captcha.ErrInvalidToken):
+		message := "Captcha verification required"
+		if errors.Is(err, captcha.ErrInvalidToken) {
+			message = "Captcha verification failed"
+		}
+		c.JSON(http.StatusForbidden, models.CaptchaRequiredResponse{
+			Error:           message,
+			CaptchaRequired: true,
+			Provider:        verifier.Provider(),
+			SiteKey:         siteKey,
+		})
+	default:
+		log.Printf("Captcha verification error: %v", err)
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Captcha verification is temporarily unavailable"})
+	}
+	return false
+}
+
+// failureTracker считает неудачные попытки входа по логину и по IP в скользящем окне
+type failureTracker struct {
+	mu        sync.Mutex
+	threshold int
+	window    time.Duration
+	entries   map[string]*failureEntry
+}
+
+type failureEntry struct {
+	count int
+	first time.Time
+}
+
+func newFailureTracker(threshold int, window time.Duration) *failureTracker {
+	return &failureTracker{threshold: threshold, window: window, entries: map[string]*failureEntry{}}
+}
+
+func (t *failureTracker) configure(threshold int, window time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.threshold = threshold
+	t.window = window
+}
+
+func failureKeys(username, ip string) []string {
+	return []string{"user:" + strings.ToLower(strings.TrimSpace(username)), "ip:" + ip}
+}
+
+// exceeded сообщает, достиг ли логин или IP порога неудачных попыток
+func (t *failureTracker) exceeded(username, ip string) bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.threshold <= 0 {
+		return false
+	}
+	now := time.Now()
+	for _, key := range failureKeys(username, ip) {
+		entry, ok := t.entries[key]
+		if !ok {
+			continue
+		}
+		if now.Sub(entry.first) > t.window {
+			delete(t.entries, key)
+			continue
+		}
+		if entry.count >= t.threshold {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *failureTracker) record(username, ip string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	now := time.Now()
+	for _, key := range failureKeys(username, ip) {
+		entry, ok := t.entries[key]
+		if !ok || now.Sub(entry.first) > t.window {
+			t.entries[key] = &failureEntry{count: 1, first: now}
+			continue
+		}
+		entry.count++
+	}
+	// Периодически удаляем устаревшие записи, чтобы перебор логинов не раздувал карту
+	if len(t.entries) > 10000 {
+		for key, entry := range t.entries {
+			if now.Sub(entry.first) > t.window {
+				delete(t.entries, key)
+			}
+		}
+	}
+}
+
+// reset сбрасывает счетчик логина после успешного входа; счетчик IP остается,
+// чтобы успешный вход в свою учетную запись не открывал перебор чужих
+func (t *failureTracker) reset(username string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.entries, failureKeys(username, "")[0])
+}
@@ -0,0 +1,294 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// certificateAlphabet — алфавит Crockford Base32: без I, L, O и U, которые легко перепутать при
+// наборе кода с бумажного сертификата
+const certificateAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// newCertificateCode создает код проверки вида XXXX-XXXX-XXXX (60 случайных бит)
+func newCertificateCode() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	var code strings.Builder
+	for i, b := range buf {
+		if i > 0 && i%4 == 0 {
+			code.WriteByte('-')
+		}
+		code.WriteByte(certificateAlphabet[b&31])
+	}
+	return code.String(), nil
+}
+
+// normalizeCertificateCode приводит введенный вручную код к каноническому виду: регистр,
+// разделители и похожие символы (O → 0, I и L → 1) не важны
+func normalizeCertificateCode(raw string) (string, bool) {
+	var symbols []byte
+	for _, r := range strings.ToUpper(raw) {
+		switch {
+		case r == '-' || r == ' ':
+			continue
+		case r == 'O':
+			r = '0'
+		case r == 'I' || r == 'L':
+			r = '1'
+		}
+		if r > 127 || !strings.ContainsRune(certificateAlphabet, r) {
+			return "", false
+		}
+		symbols = append(symbols, byte(r))
+	}
+	if len(symbols) != 12 {
+		return "", false
+	}
+	return string(symbols[:4]) + "-" + string(symbols[4:8]) + "-" + string(symbols[8:]), true
+}
+
+func certificateView(cert models.Certificate) models.Certificate {
+	cert.VerificationURL = currentPublicURL() + "/api/certificates/verify/" + cert.Code
+	return cert
+}
+
+// issueCourseCertificates выдает сертификаты за курсы, которые пользователь завершил заданиями
+// taskIDs. Сертификат за курс выдается один раз: отозванный автоматически не перевыпускается.
+func issueCourseCertificates(userID int, taskIDs []int) {
+	if len(taskIDs) == 0 {
+		return
+	}
+	courseIDs, err := completedTaskCourses(userID, taskIDs)
+	if err != nil {
+		log.Printf("Failed to check course certificates for user %d: %v", userID, err)
+		return
+	}
+	for courseID := range courseIDs {
+		if err := issueCourseCertificate(userID, courseID); err != nil && !errors.Is(err, storage.ErrCertificateExists) {
+			log.Printf("Failed to issue certificate of course %d to user %d: %v", courseID, userID, err)
+		}
+	}
+}
+
+func issueCourseCertificate(userID, courseID int) error {
+	// Задания курса другой организации не дают сертификата
+	orgID, _, err := UserOrganization(userID)
+	if err != nil {
+		return err
+	}
+	course, err := storage.InOrganization(Store, orgID).GetCourseByID(courseID)
+	if err != nil {
+		return err
+	}
+	if completed, err := courseCompleted(userID, course); err != nil || !completed {
+		return err
+	}
+	user, err := Store.GetUserByID(userID)
+	if err != nil {
+		return err
+	}
+	code, err := newCertificateCode()
+	if err != nil {
+		return err
+	}
+	issuedTo := user.FullName
+	if issuedTo == "" {
+		issuedTo = user.Username
+	}
+	cert, err := Store.CreateCertificate(models.Certificate{
+		Code:        code,
+		UserID:      userID,
+		CourseID:    &courseID,
+		IssuedTo:    issuedTo,
+		CourseTitle: course.VulnerabilityType,
+		IssuedAt:    time.Now().UTC().Truncate(time.Second),
+	})
+	if err != nil {
+		return err
+	}
+	if Realtime != nil {
+		Realtime.Broadcast(realtime.UserTopic(userID), "certificate.issued", certificateView(cert))
+	}
+	return nil
+}
+
+// loadCertificate загружает сертификат по коду из пути запроса
+func loadCertificate(c *gin.Context) (models.Certificate, bool) {
+	code, ok := normalizeCertificateCode(c.Param("code"))
+	if !ok {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Certificate not found"})
+		return models.Certificate{}, false
+	}
+	cert, err := Store.GetCertificateByCode(code)
+	if errors.Is(err, storage.ErrCertificateNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Certificate not found"})
+		return models.Certificate{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get certificate: " + err.Error()})
+		return models.Certificate{}, false
+	}
+	return cert, true
+}
+
+// certificatePage — страница, которая открывается при сканировании QR-кода сертификата
+var certificatePage = template.Must(template.New("certificate").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body>
+<h1>{{.Title}}</h1>
+<p><strong>{{.Status}}</strong></p>
+<dl>
+<dt>{{.CodeLabel}}</dt><dd>{{.Code}}</dd>
+<dt>{{.IssuedToLabel}}</dt><dd>{{.IssuedTo}}</dd>
+<dt>{{.CourseLabel}}</dt><dd>{{.CourseTitle}}</dd>
+<dt>{{.IssuedAtLabel}}</dt><dd>{{.IssuedAt}}</dd>
+{{if .RevokedAt}}<dt>{{.RevokedAtLabel}}</dt><dd>{{.RevokedAt}}</dd>
+<dt>{{.ReasonLabel}}</dt><dd>{{.Reason}}</dd>{{end}}
+</dl>
+</body></html>`))
+
+type certificatePageData struct {
+	Title, Status                                        string
+	CodeLabel, IssuedToLabel, CourseLabel, IssuedAtLabel string
+	RevokedAtLabel, ReasonLabel                          string
+	Code, IssuedTo, CourseTitle, IssuedAt                string
+	RevokedAt, Reason                                    string
+}
+
+// @Summary Verify certificate
+// @Description Public check of a course certificate by its code, as encoded in the QR code on the certificate. The code is case-insensitive and dashes are optional. Browsers get an HTML page, other clients JSON.
+// @Tags Certificates
+// @Produce json,html
+// @Param code path string true "Certificate code" example(7K3F-9QX2-M4PD)
+// @Success 200 {object} models.CertificateVerification
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /certificates/verify/{code} [get]
+func VerifyCertificate(c *gin.Context) {
+	cert, ok := loadCertificate(c)
+	if !ok {
+		return
+	}
+	result := models.CertificateVerification{
+		Code:             cert.Code,
+		Valid:            cert.RevokedAt == nil,
+		Status:           models.CertificateValid,
+		IssuedTo:         cert.IssuedTo,
+		CourseTitle:      cert.CourseTitle,
+		IssuedAt:         cert.IssuedAt,
+		RevokedAt:        cert.RevokedAt,
+		RevocationReason: cert.RevocationReason,
+	}
+	if !result.Valid {
+		result.Status = models.CertificateRevoked
+	}
+	c.Header("Cache-Control", "no-store")
+
+	if c.NegotiateFormat(gin.MIMEJSON, gin.MIMEHTML) != gin.MIMEHTML {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	page := certificatePageData{
+		Title:          Translate(c, "Certificate verification"),
+		Status:         Translate(c, "The certificate is valid"),
+		CodeLabel:      Translate(c, "Code"),
+		IssuedToLabel:  Translate(c, "Issued to"),
+		CourseLabel:    Translate(c, "Course"),
+		IssuedAtLabel:  Translate(c, "Issued"),
+		RevokedAtLabel: Translate(c, "Revoked"),
+		ReasonLabel:    Translate(c, "Reason"),
+		Code:           result.Code,
+		IssuedTo:       result.IssuedTo,
+		CourseTitle:    result.CourseTitle,
+		IssuedAt:       result.IssuedAt.Format("2006-01-02"),
+		Reason:         result.RevocationReason,
+	}
+	if result.RevokedAt != nil {
+		page.Status = Translate(c, "The certificate has been revoked")
+		page.RevokedAt = result.RevokedAt.Format("2006-01-02")
+	}
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	certificatePage.Execute(c.Writer, page)
+}
+
+// @Summary List my certificates
+// @Description Lists the course certificates issued to the current user, newest first. Each has a public verification URL that is also the QR code payload.
+// @Tags Certificates
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Certificate
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /certificates [get]
+func ListMyCertificates(c *gin.Context) {
+	certificates, err := Store.ListUserCertificates(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get certificates: " + err.Error()})
+		return
+	}
+	for i := range certificates {
+		certificates[i] = certificateView(certificates[i])
+	}
+	c.JSON(http.StatusOK, certificates)
+}
+
+// @Summary Revoke certificate
+// @Description Revokes a course certificate. The public verification then reports it as revoked with the reason. Admin only.
+// @Tags Certificates
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param code path string true "Certificate code"
+// @Param request body models.RevokeCertificateRequest true "Revocation reason"
+// @Success 200 {object} models.Certificate
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Already revoked"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /certificates/{code}/revoke [post]
+func RevokeCertificate(c *gin.Context) {
+	adminID := c.GetInt("userID")
+	isAdmin, err := RequestAdminRights(c)
+	if err != nil || !isAdmin {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only administrators can revoke certificates"})
+		return
+	}
+	var req models.RevokeCertificateRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	cert, ok := loadCertificate(c)
+	if !ok {
+		return
+	}
+
+	cert, err = Store.RevokeCertificate(cert.Code, adminID, strings.TrimSpace(req.Reason))
+	switch {
+	case errors.Is(err, storage.ErrCertificateNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Certificate not found"})
+		return
+	case errors.Is(err, storage.ErrCertificateRevoked):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Certificate is already revoked"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to revoke certificate: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, certificateView(cert))
+}
@@ -0,0 +1,424 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"lmsmodule/backend-svc/chatnotify"
+	"lmsmodule/backend-svc/i18n"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// chatDeadlineReminder — за сколько до срока задания канал получает напоминание
+	chatDeadlineReminder = 24 * time.Hour
+	// chatLeaderboardSize — сколько участников попадает в еженедельную таблицу лидеров
+	chatLeaderboardSize = 10
+)
+
+// chatSender доставляет сообщения в каналы Slack и Mattermost
+var chatSender = chatnotify.New()
+
+func chatChannelView(channel models.ChatChannel) models.ChatChannel {
+	channel.Delivery = "bot"
+	if channel.WebhookURL != "" {
+		channel.Delivery = "webhook"
+	}
+	return channel
+}
+
+// sendChat публикует сообщение в канал группы
+func sendChat(channel models.ChatChannel, text string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	return chatSender.Send(ctx, chatnotify.Target{
+		Provider:   channel.Provider,
+		WebhookURL: channel.WebhookURL,
+		BotToken:   channel.BotToken,
+		ServerURL:  channel.ServerURL,
+		Channel:    channel.Channel,
+	}, text)
+}
+
+// chatTime форматирует время для канала: у группы нет своего пояса, поэтому берется пояс по умолчанию
+func chatTime(t time.Time) string {
+	return t.In(userLocation("")).Format("2006-01-02 15:04 MST")
+}
+
+// announceCoursePublished сообщает о новом курсе в каналы, подписанные на такие события.
+// Вызывается в отдельной горутине: недоступный чат не должен задерживать ответ API.
+func announceCoursePublished(store storage.Storage, courseID int) {
+	channels, err := Store.ListChatChannels()
+	if err != nil {
+		log.Printf("Failed to list chat channels: %v", err)
+		return
+	}
+	for _, channel := range channels {
+		if !channel.NotifyCoursePublished {
+			continue
+		}
+		locale := UserLocale(channel.Locale)
+		course, err := store.GetCourseByID(courseID)
+		if err == nil {
+			err = LocalizeCourse(locale, &course)
+		}
+		if err != nil {
+			log.Printf("Failed to get course %d for chat announcement: %v", courseID, err)
+			return
+		}
+		text := i18n.Sprintf(locale, "📚 New course published: *%s* (%d tasks)", course.VulnerabilityType, len(course.Tasks))
+		if course.Description != "" {
+			text += "\n" + course.Description
+		}
+		if err := sendChat(channel, text); err != nil {
+			log.Printf("Failed to announce course %d in chat channel %d: %v", courseID, channel.ID, err)
+		}
+	}
+}
+
+// SendChatDeadlineReminders напоминает каналам групп о сроках заданий в ближайшие сутки.
+// Каждый срок объявляется в канале один раз; перенос срока дает новое напоминание.
+func SendChatDeadlineReminders() error {
+	channels, err := Store.ListChatChannels()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, channel := range channels {
+		if !channel.NotifyDeadlines {
+			continue
+		}
+		var courseIDs []int
+		if channel.CourseID != nil {
+			courseIDs = []int{*channel.CourseID}
+		}
+		schedules, err := Store.ListTaskSchedules(courseIDs, now, now.Add(chatDeadlineReminder))
+		if err != nil {
+			return err
+		}
+		locale := UserLocale(channel.Locale)
+		titles, err := localizedTaskTitles(locale, schedules)
+		if err != nil {
+			return err
+		}
+		courseTitles, err := localizedCourseTitles(locale, schedules)
+		if err != nil {
+			return err
+		}
+		for _, schedule := range schedules {
+			if schedule.DueAt == nil || schedule.DueAt.Before(now) || !schedule.DueAt.Before(now.Add(chatDeadlineReminder)) {
+				continue
+			}
+			marked, err := Store.MarkChatDeadlineReminder(channel.ID, schedule.TaskID, *schedule.DueAt)
+			if err != nil {
+				return err
+			}
+			if !marked {
+				continue
+			}
+			text := i18n.Sprintf(locale, "⏰ Deadline %s: %s (%s)",
+				chatTime(*schedule.DueAt), titles[schedule.TaskID], courseTitles[schedule.CourseID])
+			if err := sendChat(channel, text); err != nil {
+				log.Printf("Failed to send deadline reminder to chat channel %d: %v", channel.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+// chatLeaderboardSlot возвращает момент последней рассылки таблиц лидеров, не позже now:
+// понедельник 09:00 в часовом поясе по умолчанию
+func chatLeaderboardSlot(now time.Time) time.Time {
+	loc := userLocation("")
+	local := now.In(loc)
+	daysSinceMonday := (int(local.Weekday()) + 6) % 7
+	slot := time.Date(local.Year(), local.Month(), local.Day()-daysSinceMonday, 9, 0, 0, 0, loc)
+	if slot.After(local) {
+		slot = slot.AddDate(0, 0, -7)
+	}
+	return slot
+}
+
+// SendChatLeaderboards публикует в каналы групп таблицу лидеров за прошедшую неделю.
+// Канал, созданный после очередного понедельника, получит первую таблицу в следующий.
+// Неудачная отправка повторяется при следующем запуске задачи.
+func SendChatLeaderboards() error {
+	channels, err := Store.ListChatChannels()
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	slot := chatLeaderboardSlot(now)
+	for _, channel := range channels {
+		if !channel.NotifyLeaderboard {
+			continue
+		}
+		if channel.LastLeaderboardAt != nil && !channel.LastLeaderboardAt.Before(slot) {
+			continue
+		}
+		if channel.LastLeaderboardAt == nil && channel.CreatedAt.After(slot) {
+			continue
+		}
+		entries, err := Store.GroupLeaderboard(channel.Group, slot.AddDate(0, 0, -7), slot, chatLeaderboardSize)
+		if err != nil {
+			return err
+		}
+		if err := sendChat(channel, chatLeaderboardText(channel, entries, slot)); err != nil {
+			log.Printf("Failed to send leaderboard to chat channel %d: %v", channel.ID, err)
+			continue
+		}
+		if err := Store.SetChatLeaderboardSent(channel.ID, now); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func chatLeaderboardText(channel models.ChatChannel, entries []models.LeaderboardEntry, slot time.Time) string {
+	locale := UserLocale(channel.Locale)
+	from := slot.AddDate(0, 0, -7).Format("2006-01-02")
+	to := slot.AddDate(0, 0, -1).Format("2006-01-02")
+	if len(entries) == 0 {
+		return i18n.Sprintf(locale, "🏆 Nobody in %s completed a task between %s and %s.", channel.Group, from, to)
+	}
+	lines := []string{i18n.Sprintf(locale, "🏆 Leaderboard of %s for %s – %s:", channel.Group, from, to)}
+	for i, entry := range entries {
+		lines = append(lines, i18n.Sprintf(locale, "%d. %s — tasks completed: %d", i+1, entry.Username, entry.TasksCompleted))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// chatChannelFromRequest проверяет запрос и собирает из него канал. existing — сохраненный канал
+// при изменении: если не передан ни webhookUrl, ни botToken, его секреты остаются прежними.
+func chatChannelFromRequest(c *gin.Context, existing *models.ChatChannel) (models.ChatChannel, bool) {
+	var req models.SaveChatChannelRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return models.ChatChannel{}, false
+	}
+	if req.Locale != "" && !validLocale(req.Locale) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Unsupported locale; choose one of: " + strings.Join(currentAccountsConfig().SupportedLocales, ", "),
+		})
+		return models.ChatChannel{}, false
+	}
+
+	channel := models.ChatChannel{
+		Group:                 strings.TrimSpace(req.Group),
+		Provider:              req.Provider,
+		ServerURL:             req.ServerURL,
+		Channel:               strings.TrimSpace(req.Channel),
+		CourseID:              req.CourseID,
+		Locale:                req.Locale,
+		NotifyCoursePublished: req.NotifyCoursePublished,
+		NotifyLeaderboard:     req.NotifyLeaderboard,
+		NotifyDeadlines:       req.NotifyDeadlines,
+	}
+	// Активен только один способ доставки: новый секрет заменяет сохраненный
+	switch {
+	case req.WebhookURL != "":
+		channel.WebhookURL = req.WebhookURL
+	case req.BotToken != "":
+		channel.BotToken = req.BotToken
+	case existing != nil:
+		channel.WebhookURL, channel.BotToken = existing.WebhookURL, existing.BotToken
+	}
+	if channel.WebhookURL == "" && (channel.BotToken == "" || channel.Channel == "") {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Either webhookUrl or botToken with channel is required"})
+		return models.ChatChannel{}, false
+	}
+	if channel.WebhookURL == "" && channel.Provider == chatnotify.ProviderMattermost && channel.ServerURL == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "serverUrl is required for a Mattermost bot"})
+		return models.ChatChannel{}, false
+	}
+	return channel, true
+}
+
+// respondChatChannelError отвечает на ошибку сохранения канала
+func respondChatChannelError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrChatChannelNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Chat channel not found"})
+	case errors.Is(err, storage.ErrGroupNotFound):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Group not found"})
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Course not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save chat channel: " + err.Error()})
+	}
+}
+
+func loadChatChannel(c *gin.Context) (models.ChatChannel, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid chat channel ID"})
+		return models.ChatChannel{}, false
+	}
+	channel, err := Store.GetChatChannel(id)
+	if errors.Is(err, storage.ErrChatChannelNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Chat channel not found"})
+		return channel, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get chat channel: " + err.Error()})
+		return channel, false
+	}
+	return channel, true
+}
+
+// @Summary List chat channels
+// @Description Lists the Slack and Mattermost channels of student groups. Webhook URLs and bot tokens are never returned.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.ChatChannel
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/chat-channels [get]
+func ListChatChannels(c *gin.Context) {
+	channels, err := Store.ListChatChannels()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get chat channels: " + err.Error()})
+		return
+	}
+	for i := range channels {
+		channels[i] = chatChannelView(channels[i])
+	}
+	c.JSON(http.StatusOK, channels)
+}
+
+// @Summary Get chat channel
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Chat channel ID"
+// @Success 200 {object} models.ChatChannel
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/chat-channels/{id} [get]
+func GetChatChannel(c *gin.Context) {
+	channel, ok := loadChatChannel(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, chatChannelView(channel))
+}
+
+// @Summary Connect chat channel
+// @Description Connects a Slack or Mattermost channel to a student group. Messages go through an incoming webhook (webhookUrl) or are posted by a bot (botToken and channel ID; Mattermost also needs serverUrl). The channel can receive new course announcements, a weekly leaderboard of the group (Monday 09:00 in the default time zone) and reminders a day before task deadlines, optionally limited to one course.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SaveChatChannelRequest true "Chat channel"
+// @Success 201 {object} models.ChatChannel
+// @Failure 400 {object} models.ErrorResponse "Invalid data, unknown group or course"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/chat-channels [post]
+func CreateChatChannel(c *gin.Context) {
+	channel, ok := chatChannelFromRequest(c, nil)
+	if !ok {
+		return
+	}
+	channel.CreatedBy = c.GetInt("userID")
+	channel, err := Store.CreateChatChannel(channel)
+	if err != nil {
+		respondChatChannelError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, chatChannelView(channel))
+}
+
+// @Summary Update chat channel
+// @Description Replaces the settings of a chat channel. Leave webhookUrl and botToken empty to keep the stored secret; setting one of them switches the delivery method.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Chat channel ID"
+// @Param request body models.SaveChatChannelRequest true "Chat channel"
+// @Success 200 {object} models.ChatChannel
+// @Failure 400 {object} models.ErrorResponse "Invalid data, unknown group or course"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/chat-channels/{id} [put]
+func UpdateChatChannel(c *gin.Context) {
+	existing, ok := loadChatChannel(c)
+	if !ok {
+		return
+	}
+	channel, ok := chatChannelFromRequest(c, &existing)
+	if !ok {
+		return
+	}
+	channel.ID = existing.ID
+	channel, err := Store.UpdateChatChannel(channel)
+	if err != nil {
+		respondChatChannelError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, chatChannelView(channel))
+}
+
+// @Summary Disconnect chat channel
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Chat channel ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/chat-channels/{id} [delete]
+func DeleteChatChannel(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid chat channel ID"})
+		return
+	}
+	err = Store.DeleteChatChannel(id)
+	if errors.Is(err, storage.ErrChatChannelNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Chat channel not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete chat channel: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Chat channel disconnected")})
+}
+
+// @Summary Send test message to chat channel
+// @Description Posts a test message to the channel to check the webhook or bot token.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Chat channel ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 502 {object} models.ErrorResponse "Slack or Mattermost rejected the message"
+// @Router /admin/chat-channels/{id}/test [post]
+func TestChatChannel(c *gin.Context) {
+	channel, ok := loadChatChannel(c)
+	if !ok {
+		return
+	}
+	locale := UserLocale(channel.Locale)
+	text := i18n.Sprintf(locale, "✅ LMS notifications for %s are connected to this channel.", channel.Group)
+	if err := sendChat(channel, text); err != nil {
+		c.JSON(http.StatusBadGateway, models.ErrorResponse{Error: "Failed to deliver test message: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Test message sent")})
+}
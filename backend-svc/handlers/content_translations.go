@@ -0,0 +1,285 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+// LocalizeCourses подставляет в курсы переводы на язык locale. Если перевода на этот язык
+// нет или поле в нем пустое, используется перевод на язык по умолчанию, а затем исходный текст.
+func LocalizeCourses(locale string, courses []models.Course) error {
+	for _, l := range fallbackLocales(locale) {
+		translations, err := Store.GetCourseTranslations(l)
+		if err != nil {
+			return err
+		}
+		for i := range courses {
+			if t, ok := translations[courses[i].ID]; ok {
+				applyCourseTranslation(&courses[i], t)
+			}
+		}
+	}
+	return nil
+}
+
+// LocalizeCourse подставляет переводы в курс и его задания (см. LocalizeCourses)
+func LocalizeCourse(locale string, course *models.Course) error {
+	for _, l := range fallbackLocales(locale) {
+		courseTranslations, err := Store.GetCourseTranslations(l)
+		if err != nil {
+			return err
+		}
+		if t, ok := courseTranslations[course.ID]; ok {
+			applyCourseTranslation(course, t)
+		}
+
+		taskTranslations, err := Store.GetTaskTranslations(course.ID, l)
+		if err != nil {
+			return err
+		}
+		for i := range course.Tasks {
+			if t, ok := taskTranslations[course.Tasks[i].ID]; ok {
+				applyTaskTranslation(&course.Tasks[i], t)
+			}
+		}
+	}
+	return nil
+}
+
+// fallbackLocales возвращает языки, переводы на которые применяются по очереди:
+// сначала язык по умолчанию, затем запрошенный, чтобы он имел приоритет
+func fallbackLocales(locale string) []string {
+	defaultLocale := currentAccountsConfig().DefaultLocale
+	if locale == "" || locale == defaultLocale {
+		return []string{defaultLocale}
+	}
+	return []string{defaultLocale, locale}
+}
+
+func applyCourseTranslation(course *models.Course, t models.CourseTranslation) {
+	if t.VulnerabilityType != "" {
+		course.VulnerabilityType = t.VulnerabilityType
+	}
+	if t.Description != "" {
+		course.Description = t.Description
+	}
+}
+
+func applyTaskTranslation(task *models.Task, t models.TaskTranslation) {
+	if t.Title != "" {
+		task.Title = t.Title
+	}
+	if t.Description != "" {
+		task.Description = t.Description
+	}
+}
+
+// translationLocale проверяет язык из пути запроса
+func translationLocale(c *gin.Context) (string, bool) {
+	locale := c.Param("locale")
+	if !validLocale(locale) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unsupported locale"})
+		return "", false
+	}
+	return locale, true
+}
+
+// @Summary List course translations
+// @Description Returns all translations of the course and its tasks (admin only)
+// @Tags Admin
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.CourseTranslations
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/courses/{id}/translations [get]
+func ListCourseTranslations(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+
+	translations, err := Store.ListCourseTranslations(courseID)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list translations: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, translations)
+}
+
+// @Summary Set course translation
+// @Description Creates or replaces the translation of a course title and description; empty fields fall back to the default language (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param locale path string true "Locale, e.g. ru"
+// @Param request body models.CourseTranslationRequest true "Translation"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/courses/{id}/translations/{locale} [put]
+func SetCourseTranslation(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	locale, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+	var req models.CourseTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if req.VulnerabilityType == "" && req.Description == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Translation must not be empty"})
+		return
+	}
+
+	err = Store.SetCourseTranslation(models.CourseTranslation{
+		CourseID:          courseID,
+		Locale:            locale,
+		VulnerabilityType: req.VulnerabilityType,
+		Description:       req.Description,
+	})
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save translation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Translation saved")})
+}
+
+// @Summary Delete course translation
+// @Tags Admin
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param locale path string true "Locale, e.g. ru"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/courses/{id}/translations/{locale} [delete]
+func DeleteCourseTranslation(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+
+	err = Store.DeleteCourseTranslation(courseID, c.Param("locale"))
+	if errors.Is(err, storage.ErrTranslationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Translation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete translation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Translation deleted")})
+}
+
+// @Summary Set task translation
+// @Description Creates or replaces the translation of a task title and description; empty fields fall back to the default language (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param locale path string true "Locale, e.g. ru"
+// @Param request body models.TaskTranslationRequest true "Translation"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/tasks/{id}/translations/{locale} [put]
+func SetTaskTranslation(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+		return
+	}
+	locale, ok := translationLocale(c)
+	if !ok {
+		return
+	}
+	var req models.TaskTranslationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if req.Title == "" && req.Description == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Translation must not be empty"})
+		return
+	}
+
+	err = Store.SetTaskTranslation(models.TaskTranslation{
+		TaskID:      taskID,
+		Locale:      locale,
+		Title:       req.Title,
+		Description: req.Description,
+	})
+	if errors.Is(err, storage.ErrTaskNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Task not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save translation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Translation saved")})
+}
+
+// @Summary Delete task translation
+// @Tags Admin
+// @Produce json
+// @Param id path int true "Task ID"
+// @Param locale path string true "Locale, e.g. ru"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/tasks/{id}/translations/{locale} [delete]
+func DeleteTaskTranslation(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+		return
+	}
+
+	err = Store.DeleteTaskTranslation(taskID, c.Param("locale"))
+	switch {
+	case errors.Is(err, storage.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Task not found"})
+		return
+	case errors.Is(err, storage.ErrTranslationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Translation not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete translation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Translation deleted")})
+}
@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+// CourseHidden сообщает, что курс в архиве и текущий пользователь его не видит: архивный
+// курс открыт администраторам и тем, кто начал его до архивации
+func CourseHidden(c *gin.Context, course models.Course) (bool, error) {
+	if course.ArchivedAt == nil {
+		return false, nil
+	}
+	isAdmin, err := RequestAdminRights(c)
+	if err != nil || isAdmin {
+		return false, err
+	}
+	enrolled, err := enrolledInCourse(c.GetInt("userID"), course)
+	return !enrolled, err
+}
+
+// @Summary List archived courses
+// @Description Courses hidden from the catalog by archiving, most recently archived first (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Course
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/courses/archived [get]
+func ListArchivedCourses(c *gin.Context) {
+	courses, err := StoreFor(c).ListArchivedCourses()
+	if err == nil {
+		err = LocalizeCourses(RequestLocale(c), courses)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list archived courses: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, courses)
+}
+
+// @Summary Archive a course
+// @Description Hide a course from the catalog and close it to new learners: task completions by users who have not started it, invitations and assignments are rejected. Learners who started the course keep access, progress and certificates (admin only).
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/courses/{id}/archive [post]
+func ArchiveCourse(c *gin.Context) {
+	changeCourseArchive(c, storage.Storage.ArchiveCourse, "Failed to archive course: ", "Course archived")
+}
+
+// @Summary Restore an archived course
+// @Description Return an archived course to the catalog. Users who bookmarked it with notify get a notification (admin only).
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/courses/{id}/restore [post]
+func RestoreCourse(c *gin.Context) {
+	changeCourseArchive(c, func(store storage.Storage, courseID int) error {
+		course, err := store.GetCourseByID(courseID)
+		if err != nil {
+			return err
+		}
+		if err := store.RestoreCourse(courseID); err != nil || course.ArchivedAt == nil {
+			return err
+		}
+		go notifyBookmarkedCourseRestored(store, courseID)
+		return nil
+	}, "Failed to restore course: ", "Course restored")
+}
+
+// @Summary Permanently delete an archived course
+// @Description Delete an archived course with its tasks, progress, attempts and discussions. Issued certificates stay valid and keep the course title. Only archived courses can be purged (admin only).
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/courses/{id} [delete]
+func PurgeCourse(c *gin.Context) {
+	changeCourseArchive(c, storage.Storage.PurgeCourse, "Failed to purge course: ", "Course deleted permanently")
+}
+
+// changeCourseArchive выполняет действие с курсом из пути запроса в хранилище организации
+// пользователя и отвечает message
+func changeCourseArchive(c *gin.Context, action func(store storage.Storage, courseID int) error, failure, message string) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	err = action(StoreFor(c), courseID)
+	switch {
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	case errors.Is(err, storage.ErrCourseNotArchived):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Archive the course before deleting it permanently"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: failure + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, message)})
+}
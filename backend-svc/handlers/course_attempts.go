@@ -0,0 +1,124 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// courseParticipantParams читает ID курса и пользователя из пути запроса
+func courseParticipantParams(c *gin.Context) (courseID, userID int, ok bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return 0, 0, false
+	}
+	userID, err = strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return 0, 0, false
+	}
+	return courseID, userID, true
+}
+
+// @Summary Reset course progress
+// @Description Re-opens the course for the user, for example for an exam retake or recertification (administrators and instructors). Completed tasks and SCORM attempts are moved to an archived attempt instead of being deleted, so the course counts as not started and the user can enroll again. The user's current recertification cycle for the course ends. Certificates already issued are kept.
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param user_id path int true "User ID"
+// @Param request body models.ResetProgressRequest false "Reason"
+// @Success 200 {object} models.CourseAttempt "Archived attempt"
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "The user has no progress in the course"
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/progress/{user_id}/reset [post]
+func ResetCourseProgress(c *gin.Context) {
+	instructorID, _, ok := requireInstructor(c, "Only administrators and instructors can reset course progress")
+	if !ok {
+		return
+	}
+	courseID, userID, ok := courseParticipantParams(c)
+	if !ok {
+		return
+	}
+	var req models.ResetProgressRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+			return
+		}
+	}
+
+	// Пользователи и курсы других организаций в представлении не видны и дают 404
+	store := StoreFor(c)
+	_, err := store.GetUserPublicByID(userID)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
+		return
+	}
+
+	attempt, err := store.ResetCourseProgress(userID, courseID, &instructorID, req.Reason)
+	switch {
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	case errors.Is(err, storage.ErrNoCourseProgress):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "The user has no progress in this course"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to reset course progress: " + err.Error()})
+		return
+	}
+	log.Printf("Instructor %d reset progress of user %d in course %d (attempt %d)", instructorID, userID, courseID, attempt.ID)
+	if Realtime != nil {
+		Realtime.Broadcast(realtime.UserTopic(userID), "course.progress_reset", attempt)
+	}
+	c.JSON(http.StatusOK, attempt)
+}
+
+// @Summary Archived course attempts
+// @Description Earlier attempts of the user in the course whose progress was reset, oldest first. Users see their own attempts; administrators and instructors see anyone's.
+// @Tags Courses
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {array} models.CourseAttempt
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/progress/{user_id}/attempts [get]
+func ListCourseAttempts(c *gin.Context) {
+	courseID, userID, ok := courseParticipantParams(c)
+	if !ok {
+		return
+	}
+	if userID != c.GetInt("userID") {
+		if _, _, ok := requireInstructor(c, "Access denied"); !ok {
+			return
+		}
+	}
+
+	attempts, err := Store.ListCourseAttempts(userID, courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get course attempts: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, attempts)
+}
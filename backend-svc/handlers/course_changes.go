@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+const (
+	defaultCourseChangesLimit = 50
+	maxCourseChangesLimit     = 200
+)
+
+// @Summary Add a task to a course
+// @Description Appends a task to the course (or inserts it at the given order) and records it in the course changelog (administrators and instructors). Tasks of SCORM courses come from the package and cannot be added; archived courses cannot be changed.
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param request body models.SaveTaskRequest true "Task"
+// @Success 201 {object} models.Task
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/tasks [post]
+func AddCourseTask(c *gin.Context) {
+	task, ok := bindCourseTask(c)
+	if !ok {
+		return
+	}
+	store := StoreFor(c)
+	_, err := store.GetScormPackage(task.CourseID)
+	switch {
+	case err == nil:
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Tasks of a SCORM course come from its package"})
+		return
+	case !errors.Is(err, storage.ErrScormPackageNotFound):
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to add task: " + err.Error()})
+		return
+	}
+
+	task, err = store.AddTask(task)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if errors.Is(err, storage.ErrCourseArchived) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Archived courses cannot be changed"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to add task: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, task)
+}
+
+// @Summary Update a course task
+// @Description Changes the task title, description, difficulty or order and records it in the course changelog (administrators and instructors). Tasks of archived courses cannot be changed.
+// @Tags Courses
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param task_id path int true "Task ID"
+// @Param request body models.SaveTaskRequest true "Task"
+// @Success 200 {object} models.Task
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/tasks/{task_id} [put]
+func UpdateCourseTask(c *gin.Context) {
+	task, ok := bindCourseTask(c)
+	if !ok {
+		return
+	}
+	var err error
+	if task.ID, err = strconv.Atoi(c.Param("task_id")); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+		return
+	}
+
+	task, err = StoreFor(c).UpdateTask(task)
+	if errors.Is(err, storage.ErrTaskNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Task not found"})
+		return
+	}
+	if errors.Is(err, storage.ErrCourseArchived) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Archived courses cannot be changed"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update task: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, task)
+}
+
+// bindCourseTask проверяет права преподавателя и разбирает задание курса из запроса
+func bindCourseTask(c *gin.Context) (models.Task, bool) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can manage course tasks"); !ok {
+		return models.Task{}, false
+	}
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return models.Task{}, false
+	}
+	var req models.SaveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return models.Task{}, false
+	}
+	return models.Task{
+		CourseID: courseID, Title: req.Title, Description: req.Description, Difficulty: req.Difficulty, Order: req.Order,
+	}, true
+}
+
+// courseChangesLimit разбирает параметр limit списков изменений
+func courseChangesLimit(c *gin.Context) (int, bool) {
+	raw := c.Query("limit")
+	if raw == "" {
+		return defaultCourseChangesLimit, true
+	}
+	limit, err := strconv.Atoi(raw)
+	if err != nil || limit < 1 || limit > maxCourseChangesLimit {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "limit must be between 1 and 200"})
+		return 0, false
+	}
+	return limit, true
+}
+
+// visibleCourse загружает курс из пути запроса с учетом организации и архива
+func visibleCourse(c *gin.Context) (models.Course, bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return models.Course{}, false
+	}
+	course, err := StoreFor(c).GetCourseByID(courseID)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return course, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return course, false
+	}
+	hidden, err := CourseHidden(c, course)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return course, false
+	}
+	if hidden {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return course, false
+	}
+	return course, true
+}
+
+// @Summary Course changelog
+// @Description Content changes of the course, newest first: tasks added or edited and survey questions updated. seenVersion is the last version the current user has seen; changes with a greater version are new to them.
+// @Tags Courses
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param limit query int false "Maximum number of changes (default 50, max 200)"
+// @Success 200 {object} models.CourseChangelog
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/changes [get]
+func GetCourseChangelog(c *gin.Context) {
+	limit, ok := courseChangesLimit(c)
+	if !ok {
+		return
+	}
+	course, ok := visibleCourse(c)
+	if !ok {
+		return
+	}
+	changelog, err := Store.GetCourseChangelog(course.ID, c.GetInt("userID"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get course changes: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, changelog)
+}
+
+// @Summary Mark course changes as seen
+// @Description Records that the current user has seen the current version of the course, so its changes leave the "what changed" feed. Clients call it when the user opens the course.
+// @Tags Courses
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/changes/seen [post]
+func MarkCourseChangesSeen(c *gin.Context) {
+	course, ok := visibleCourse(c)
+	if !ok {
+		return
+	}
+	if err := Store.MarkCourseChangesSeen(c.GetInt("userID"), course.ID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to mark course changes as seen: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Course changes marked as seen")})
+}
+
+// @Summary What changed in my courses
+// @Description Content changes of the courses the current user has started, made since their last visit of each course (or since they started it), grouped by course, newest first
+// @Tags Courses
+// @Produce json
+// @Param limit query int false "Maximum number of changes across all courses (default 50, max 200)"
+// @Success 200 {object} models.CourseChangesFeed
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/changes [get]
+func GetCourseChangesFeed(c *gin.Context) {
+	limit, ok := courseChangesLimit(c)
+	if !ok {
+		return
+	}
+	changes, err := Store.ListUnseenCourseChanges(c.GetInt("userID"), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get course changes: " + err.Error()})
+		return
+	}
+
+	feed := models.CourseChangesFeed{Courses: []models.CourseChangesDigest{}}
+	courseIDs := []int{}
+	for _, change := range changes {
+		if n := len(feed.Courses); n == 0 || feed.Courses[n-1].CourseID != change.CourseID {
+			feed.Courses = append(feed.Courses, models.CourseChangesDigest{CourseID: change.CourseID})
+			courseIDs = append(courseIDs, change.CourseID)
+		}
+		digest := &feed.Courses[len(feed.Courses)-1]
+		digest.Changes = append(digest.Changes, change)
+	}
+	titles, err := localizedTitles(RequestLocale(c), courseIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get course changes: " + err.Error()})
+		return
+	}
+	for i := range feed.Courses {
+		feed.Courses[i].VulnerabilityType = titles[feed.Courses[i].CourseID]
+	}
+	c.JSON(http.StatusOK, feed)
+}
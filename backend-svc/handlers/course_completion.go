@@ -0,0 +1,49 @@
+package handlers
+
+import "lmsmodule/backend-svc/models"
+
+// onTasksCompleted вызывается после того, как пользователь выполнил задания taskIDs: предлагает
+// опросы, выдает значки и сертификаты курсов, которые он этим завершил, начинает новый цикл
+// повторного прохождения и уведомляет в Telegram и браузер
+func onTasksCompleted(userID int, taskIDs []int) {
+	notifyTasksGraded(userID, taskIDs)
+	pushTasksGraded(userID, taskIDs)
+	offerCourseSurveys(userID, taskIDs)
+	awardCourseBadges(userID, taskIDs)
+	issueCourseCertificates(userID, taskIDs)
+	recordCourseCompletions(userID, taskIDs)
+}
+
+// courseCompleted сообщает, выполнил ли пользователь все задания курса
+func courseCompleted(userID int, course models.Course) (bool, error) {
+	if len(course.Tasks) == 0 {
+		return false, nil
+	}
+	progress, err := Store.GetUserProgress(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, task := range course.Tasks {
+		if !progress.Completed[task.ID] {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// completedTaskCourses возвращает курсы, к которым относятся выполненные пользователем задания taskIDs
+func completedTaskCourses(userID int, taskIDs []int) (map[int]bool, error) {
+	completions, err := Store.GetTaskCompletions(userID)
+	if err != nil {
+		return nil, err
+	}
+	courses := map[int]bool{}
+	for _, completion := range completions {
+		for _, taskID := range taskIDs {
+			if completion.TaskID == taskID {
+				courses[completion.CourseID] = true
+			}
+		}
+	}
+	return courses, nil
+}
@@ -1,26 +1,59 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // GetCourses
 // @Summary Get all courses
 // @Tags Courses
 // @Produce json
+// @Param fields query string false "Comma-separated fields to return: id,vulnerabilityType,tasksCount,averageDifficulty,enrollments,reviews,averageRating,description,updatedAt"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {array} models.Course
+// @Success 304 "Not modified"
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /courses [get]
 func GetCourses(c *gin.Context) {
-	courses, err := Store.GetCourses()
+	fields, ok := parseFields(c, storage.CourseFields)
+	if !ok {
+		return
+	}
+
+	var courses []models.Course
+	var err error
+	if fields != nil {
+		courses, err = StoreFor(c).GetCourseFields(fields)
+	} else {
+		courses, err = StoreFor(c).GetCourses()
+	}
+	if err == nil {
+		err = LocalizeCourses(RequestLocale(c), courses)
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
 	}
 
+	// Разные наборы полей и языки — разные представления, поэтому они входят в ETag
+	c.Header("Vary", "Accept-Language")
+	variant := strings.Join(fields, ",") + ";" + RequestLocale(c)
+	if etag, lastModified := catalogValidators(courses, variant); checkNotModified(c, etag, lastModified) {
+		return
+	}
+
+	if fields != nil {
+		c.JSON(http.StatusOK, courseFieldSet(courses, fields))
+		return
+	}
 	c.JSON(http.StatusOK, courses)
 }
 
@@ -29,7 +62,9 @@ func GetCourses(c *gin.Context) {
 // @Tags Courses
 // @Produce json
 // @Param id path int true "Course ID"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} models.Course
+// @Success 304 "Not modified"
 // @Failure 404 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /courses/{id} [get]
@@ -40,11 +75,28 @@ func GetCourseByID(c *gin.Context) {
 		return
 	}
 
-	course, err := Store.GetCourseByID(id)
+	course, err := StoreFor(c).GetCourseByID(id)
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
 		return
 	}
+	if hidden, err := CourseHidden(c, course); err != nil || hidden {
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err := LocalizeCourse(RequestLocale(c), &course); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
+		return
+	}
+
+	c.Header("Vary", "Accept-Language")
+	if etag, lastModified := courseValidators(course, RequestLocale(c)); checkNotModified(c, etag, lastModified) {
+		return
+	}
 
 	c.JSON(http.StatusOK, course)
 }
@@ -54,6 +106,7 @@ func GetCourseByID(c *gin.Context) {
 // @Tags Progress
 // @Produce json
 // @Param user_id path int true "User ID"
+// @Param fresh query bool false "Read from the primary database instead of a replica"
 // @Success 200 {object} models.UserProgress
 // @Failure 500 {object} models.ErrorResponse
 // @Router /progress/{user_id} [get]
@@ -64,7 +117,13 @@ func GetUserProgress(c *gin.Context) {
 		return
 	}
 
-	progress, err := Store.GetUserProgress(userID)
+	// Реплика может еще не содержать только что выполненное задание; fresh=true читает с основной базы
+	store := Store
+	if c.Query("fresh") == "true" {
+		store = storage.Primary(Store)
+	}
+
+	progress, err := store.GetUserProgress(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: err.Error()})
 		return
@@ -82,6 +141,7 @@ func GetUserProgress(c *gin.Context) {
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "The course is archived and the user has not started it"
 // @Failure 500 {object} models.ErrorResponse
 // @Router /progress/{user_id}/tasks/{task_id}/complete [post]
 func CompleteTask(c *gin.Context) {
@@ -104,15 +164,82 @@ func CompleteTask(c *gin.Context) {
 		return
 	}
 
-	err = Store.CompleteTask(userID, taskID)
+	err = StoreFor(c).CompleteTask(userID, taskID)
 	if err != nil {
 		if err.Error() == "task not found" {
 			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Task not found"})
 			return
 		}
+		if errors.Is(err, storage.ErrCourseArchived) {
+			c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Course is archived"})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to complete task"})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse{Message: "Task completed successfully"})
+	onTasksCompleted(userID, []int{taskID})
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Task completed successfully")})
+}
+
+// CompleteTasks
+// @Summary Complete several tasks
+// @Description Marks up to 100 tasks complete in one transaction (quiz grading, imports) and returns a result per task. Users may complete only their own tasks; admins may complete tasks for any user.
+// @Tags Progress
+// @Accept json
+// @Produce json
+// @Param user_id path int true "User ID"
+// @Param request body models.CompleteTasksRequest true "Task IDs"
+// @Success 200 {object} models.CompleteTasksResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /progress/{user_id}/tasks/complete [post]
+func CompleteTasks(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	if currentUserID := c.GetInt("userID"); userID != currentUserID {
+		isAdmin, err := RequestAdminRights(c)
+		if err != nil || !isAdmin {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Access denied"})
+			return
+		}
+	}
+
+	var req models.CompleteTasksRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	results, err := StoreFor(c).CompleteTasks(userID, req.TaskIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to complete tasks"})
+		return
+	}
+
+	resp := models.CompleteTasksResponse{Results: results}
+	var completed []int
+	for _, result := range results {
+		if result.Status == models.TaskCompletionCompleted {
+			resp.Completed++
+			completed = append(completed, result.TaskID)
+		}
+	}
+	onTasksCompleted(userID, completed)
+	c.JSON(http.StatusOK, resp)
+}
+
+// RefreshCourseSummaries сверяет сводки курсов каталога с заданиями, прогрессом и отзывами
+func RefreshCourseSummaries() error {
+	refreshed, err := Store.RefreshCourseSummaries()
+	if err != nil {
+		return err
+	}
+	log.Printf("Refreshed %d course summaries", refreshed)
+	return nil
 }
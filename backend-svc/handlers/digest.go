@@ -0,0 +1,331 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"lmsmodule/backend-svc/i18n"
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// digestCoursesPath — страница курсов, на которую ведет ссылка из сводки
+const digestCoursesPath = "/my_courses"
+
+// digestRun — данные, общие для всех сводок одной недели
+type digestRun struct {
+	from, to   time.Time
+	completed  map[int]int
+	newCourses []models.Course
+	// courses — курсы с переводом по языку и ID
+	courses map[string]map[int]models.Course
+}
+
+// course возвращает курс с переводом на locale, запрашивая его один раз за рассылку
+func (r *digestRun) course(locale string, courseID int) (models.Course, error) {
+	if course, ok := r.courses[locale][courseID]; ok {
+		return course, nil
+	}
+	course, err := Store.GetCourseByID(courseID)
+	if err != nil {
+		return models.Course{}, err
+	}
+	if err := LocalizeCourse(locale, &course); err != nil {
+		return models.Course{}, err
+	}
+	if r.courses[locale] == nil {
+		r.courses[locale] = map[int]models.Course{}
+	}
+	r.courses[locale][courseID] = course
+	return course, nil
+}
+
+// rank возвращает место пользователя в рейтинге недели и число участников; участники
+// с равным числом выполненных заданий делят место
+func (r *digestRun) rank(userID int) (int, int) {
+	own, ok := r.completed[userID]
+	if !ok {
+		return 0, len(r.completed)
+	}
+	rank := 1
+	for _, completed := range r.completed {
+		if completed > own {
+			rank++
+		}
+	}
+	return rank, len(r.completed)
+}
+
+// SendWeeklyDigests рассылает сводки за прошедшую неделю после очередного понедельника 09:00.
+// Пользователь получает одну сводку в неделю; сводка без новостей не отправляется.
+// Ошибка SMTP прерывает рассылку, оставшиеся письма уходят при следующем запуске задачи.
+func SendWeeklyDigests() error {
+	now := time.Now()
+	slot := chatLeaderboardSlot(now)
+	userIDs, err := Store.ListDigestRecipients(slot)
+	if err != nil || len(userIDs) == 0 {
+		return err
+	}
+
+	run := &digestRun{from: slot.AddDate(0, 0, -7), to: slot, courses: map[string]map[int]models.Course{}}
+	if run.completed, err = Store.CountCompletionsByUser(run.from, run.to); err != nil {
+		return err
+	}
+	if run.newCourses, err = Store.ListCoursesCreatedBetween(run.from, run.to); err != nil {
+		return err
+	}
+
+	users, err := Store.GetUsersByIDs(userIDs)
+	if err != nil {
+		return err
+	}
+	sent := 0
+	for _, userID := range userIDs {
+		user, ok := users[userID]
+		if !ok {
+			continue
+		}
+		digest, err := composeWeeklyDigest(run, user, now)
+		if err != nil {
+			return err
+		}
+		if digest.TasksCompleted == 0 && len(digest.Deadlines) == 0 && len(digest.NewCourses) == 0 {
+			if err := Store.MarkDigestSent(user.ID, "", now); err != nil {
+				return err
+			}
+			continue
+		}
+
+		raw := make([]byte, 32)
+		if _, err := rand.Read(raw); err != nil {
+			return err
+		}
+		token := base64.RawURLEncoding.EncodeToString(raw)
+		digest.UnsubscribeURL = currentPublicURL() + "/api/digest/unsubscribe?token=" + url.QueryEscape(token)
+		if err := mail.SendWeeklyDigest(user.Email, digest); err != nil {
+			return err
+		}
+		if err := Store.MarkDigestSent(user.ID, hashToken(token), now); err != nil {
+			return err
+		}
+		sent++
+	}
+	if sent > 0 {
+		log.Printf("Weekly digests sent: %d", sent)
+	}
+	return nil
+}
+
+// composeWeeklyDigest собирает сводку пользователя: выполненные за неделю задания по курсам,
+// сроки на следующие 7 дней, новые курсы и место в рейтинге недели
+func composeWeeklyDigest(run *digestRun, user models.User, now time.Time) (mail.WeeklyDigest, error) {
+	locale := UserLocale(user.Locale)
+	digest := mail.WeeklyDigest{
+		Username:   user.Username,
+		From:       run.from,
+		To:         run.to,
+		CoursesURL: currentPublicURL() + digestCoursesPath,
+		TimeZone:   userLocation(user.Timezone),
+		Locale:     locale,
+	}
+
+	completions, err := Store.GetTaskCompletions(user.ID)
+	if err != nil {
+		return digest, err
+	}
+	thisWeek, total := map[int]int{}, map[int]int{}
+	courseIDs := []int{}
+	for _, completion := range completions {
+		total[completion.CourseID]++
+		if completion.CompletedAt.Before(run.from) || !completion.CompletedAt.Before(run.to) {
+			continue
+		}
+		if thisWeek[completion.CourseID] == 0 {
+			courseIDs = append(courseIDs, completion.CourseID)
+		}
+		thisWeek[completion.CourseID]++
+		digest.TasksCompleted++
+	}
+	sort.Ints(courseIDs)
+	for _, courseID := range courseIDs {
+		course, err := run.course(locale, courseID)
+		if err != nil {
+			return digest, err
+		}
+		digest.Progress = append(digest.Progress, mail.DigestItem{
+			Title:  course.VulnerabilityType,
+			Detail: i18n.Sprintf(locale, "+%d this week, %d of %d completed", thisWeek[courseID], total[courseID], course.TasksCount),
+		})
+	}
+
+	deadlines, err := upcomingDeadlines(user, now, now.AddDate(0, 0, 7))
+	if err != nil {
+		return digest, err
+	}
+	for _, deadline := range deadlines {
+		digest.Deadlines = append(digest.Deadlines, mail.DigestItem{
+			Title:  deadline.TaskTitle,
+			Detail: formatTelegramTime(*deadline.DueAt, user) + " (" + deadline.CourseTitle + ")",
+		})
+	}
+
+	if len(run.newCourses) > 0 {
+		courses := append([]models.Course(nil), run.newCourses...)
+		if err := LocalizeCourses(locale, courses); err != nil {
+			return digest, err
+		}
+		for _, course := range courses {
+			digest.NewCourses = append(digest.NewCourses, mail.DigestItem{
+				Title:  course.VulnerabilityType,
+				Detail: i18n.Sprintf(locale, "Tasks: %d", course.TasksCount),
+			})
+		}
+	}
+
+	digest.Rank, digest.Participants = run.rank(user.ID)
+	return digest, nil
+}
+
+// @Summary Get weekly digest settings
+// @Description Whether the current user receives the weekly email digest (progress, upcoming deadlines, new courses and leaderboard position)
+// @Tags Account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.DigestSettings
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/digest [get]
+func GetDigestSettings(c *gin.Context) {
+	settings, err := Store.GetDigestSettings(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get digest settings: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// @Summary Update weekly digest settings
+// @Description Turns the weekly email digest on or off for the current user
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateDigestSettingsRequest true "Digest settings"
+// @Success 200 {object} models.DigestSettings
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/digest [put]
+func UpdateDigestSettings(c *gin.Context) {
+	var req models.UpdateDigestSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	userID := c.GetInt("userID")
+	if err := Store.SetDigestEnabled(userID, *req.Enabled); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save digest settings: " + err.Error()})
+		return
+	}
+	settings, err := Store.GetDigestSettings(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get digest settings: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, settings)
+}
+
+// digestUnsubscribePage — страница отписки по ссылке из сводки. Отписка выполняется только
+// POST-запросом, чтобы ее не вызвали почтовые сканеры, открывающие ссылки из писем.
+var digestUnsubscribePage = template.Must(template.New("digest-unsubscribe").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>LMS weekly digest</title></head>
+<body>
+{{if .Done}}<p>{{.Message}}</p>{{else}}
+<p>{{.Prompt}}</p>
+<form method="post" action="/api/digest/unsubscribe">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">{{.Button}}</button>
+</form>{{end}}
+</body></html>`))
+
+type digestUnsubscribePageData struct {
+	Prompt  string
+	Button  string
+	Token   string
+	Done    bool
+	Message string
+}
+
+// @Summary Weekly digest unsubscribe page
+// @Description Confirmation page opened from the unsubscribe link of a weekly digest; the unsubscribe itself is a POST
+// @Tags Account
+// @Produce html
+// @Param token query string true "Unsubscribe token from the email"
+// @Success 200 {string} string "HTML page"
+// @Router /digest/unsubscribe [get]
+func DigestUnsubscribePage(c *gin.Context) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	digestUnsubscribePage.Execute(c.Writer, digestUnsubscribePageData{
+		Prompt: Translate(c, "Stop receiving the weekly LMS digest? You can turn it back on in your account settings."),
+		Button: Translate(c, "Unsubscribe"),
+		Token:  c.Query("token"),
+	})
+}
+
+// @Summary Unsubscribe from weekly digest
+// @Description Turns the weekly digest off using the token from its unsubscribe link. Also accepts one-click unsubscribe from mail clients (RFC 8058) with the token in the query string.
+// @Tags Account
+// @Accept json,x-www-form-urlencoded
+// @Produce json,html
+// @Param request body models.DigestUnsubscribeRequest true "Unsubscribe token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid or expired token"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /digest/unsubscribe [post]
+func DigestUnsubscribe(c *gin.Context) {
+	htmlForm := c.ContentType() == "application/x-www-form-urlencoded"
+	respond := func(status int, message string) {
+		if htmlForm {
+			c.Status(status)
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			digestUnsubscribePage.Execute(c.Writer, digestUnsubscribePageData{Done: true, Message: Translate(c, message)})
+			return
+		}
+		if status == http.StatusOK {
+			c.JSON(status, models.SuccessResponse{Message: Translate(c, message)})
+		} else {
+			c.JSON(status, models.ErrorResponse{Error: message})
+		}
+	}
+
+	var req models.DigestUnsubscribeRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respond(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	userID, err := Store.UnsubscribeDigest(hashToken(req.Token))
+	if errors.Is(err, storage.ErrDigestTokenInvalid) {
+		respond(http.StatusBadRequest, "This link is invalid or expired")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to unsubscribe from weekly digest: %v", err)
+		respond(http.StatusInternalServerError, "Failed to unsubscribe")
+		return
+	}
+
+	log.Printf("User %d unsubscribed from the weekly digest", userID)
+	respond(http.StatusOK, "You have unsubscribed from the weekly digest.")
+}
@@ -0,0 +1,405 @@
+package handlers
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+// discussionViewer — кто смотрит обсуждение курса. Модераторы видят скрытый контент
+// и контент заблокированных пользователей; сам заблокированный пользователь видит
+// свой контент как обычно и не знает о блокировке.
+type discussionViewer struct {
+	userID  int
+	isStaff bool
+	banned  map[int]bool
+}
+
+// newDiscussionViewer загружает права пользователя и блокировки курса
+func newDiscussionViewer(c *gin.Context, courseID int) (discussionViewer, bool) {
+	viewer := discussionViewer{userID: c.GetInt("userID"), banned: map[int]bool{}}
+	var err error
+	_, viewer.isStaff, err = requestInstructorRights(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return viewer, false
+	}
+	bans, err := Store.ListCourseBans(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get discussion: " + err.Error()})
+		return viewer, false
+	}
+	for _, ban := range bans {
+		viewer.banned[ban.UserID] = true
+	}
+	return viewer, true
+}
+
+// sees сообщает, виден ли зрителю контент автора, и нужно ли пометить его как контент
+// заблокированного пользователя
+func (v discussionViewer) sees(authorID *int) (visible, shadowed bool) {
+	if authorID == nil || !v.banned[*authorID] {
+		return true, false
+	}
+	if v.isStaff {
+		return true, true
+	}
+	return *authorID == v.userID, false
+}
+
+// comment подготавливает сообщение для зрителя: текст скрытого сообщения видят только модераторы
+func (v discussionViewer) comment(comment models.DiscussionComment) (models.DiscussionComment, bool) {
+	visible, shadowed := v.sees(comment.AuthorID)
+	comment.Shadowed = shadowed
+	if comment.HiddenAt != nil && !v.isStaff {
+		comment.Body = ""
+		comment.HiddenBy = nil
+	}
+	return comment, visible
+}
+
+// review подготавливает отзыв для зрителя: скрытые отзывы видят только модераторы и автор
+func (v discussionViewer) review(review models.CourseReview) (models.CourseReview, bool) {
+	visible, shadowed := v.sees(review.UserID)
+	review.Shadowed = shadowed
+	if review.HiddenAt != nil && !v.isStaff {
+		review.HiddenBy = nil
+		visible = visible && review.UserID != nil && *review.UserID == v.userID
+	}
+	return review, visible
+}
+
+// requireParticipant пропускает модераторов и пользователей, начавших курс
+func requireParticipant(c *gin.Context, viewer discussionViewer, course models.Course, denied string) bool {
+	if viewer.isStaff {
+		return true
+	}
+	enrolled, err := enrolledInCourse(viewer.userID, course)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get progress: " + err.Error()})
+		return false
+	}
+	if !enrolled {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: denied})
+		return false
+	}
+	return true
+}
+
+// discussionCourseVisible проверяет, что курс обсуждения принадлежит организации пользователя.
+// Обсуждения и отзывы курсов другой организации не существуют: ответ 404 с текстом notFound.
+func discussionCourseVisible(c *gin.Context, courseID int, notFound string) bool {
+	_, err := StoreFor(c).GetCourseByID(courseID)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: notFound})
+		return false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get discussion: " + err.Error()})
+		return false
+	}
+	return true
+}
+
+// threadAccess загружает тему из пути запроса вместе со зрителем; невидимая зрителю
+// тема не существует
+func threadAccess(c *gin.Context) (models.DiscussionThread, discussionViewer, bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid thread ID"})
+		return models.DiscussionThread{}, discussionViewer{}, false
+	}
+	thread, err := Store.GetThread(id)
+	if errors.Is(err, storage.ErrThreadNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Thread not found"})
+		return thread, discussionViewer{}, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get discussion: " + err.Error()})
+		return thread, discussionViewer{}, false
+	}
+	if !discussionCourseVisible(c, thread.CourseID, "Thread not found") {
+		return thread, discussionViewer{}, false
+	}
+	viewer, ok := newDiscussionViewer(c, thread.CourseID)
+	if !ok {
+		return thread, viewer, false
+	}
+	visible, shadowed := viewer.sees(thread.AuthorID)
+	if !visible {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Thread not found"})
+		return thread, viewer, false
+	}
+	thread.Shadowed = shadowed
+	return thread, viewer, true
+}
+
+// @Summary List course threads
+// @Description Discussion threads of the course, most recently active first
+// @Tags Discussions
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {array} models.DiscussionThread
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/threads [get]
+func ListThreads(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	if _, ok := loadCourse(c, courseID); !ok {
+		return
+	}
+	viewer, ok := newDiscussionViewer(c, courseID)
+	if !ok {
+		return
+	}
+	threads, err := Store.ListThreads(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get discussion: " + err.Error()})
+		return
+	}
+
+	visible := []models.DiscussionThread{}
+	for _, thread := range threads {
+		if ok, shadowed := viewer.sees(thread.AuthorID); ok {
+			thread.Shadowed = shadowed
+			visible = append(visible, thread)
+		}
+	}
+	c.JSON(http.StatusOK, visible)
+}
+
+// @Summary Create thread
+// @Description Starts a discussion thread in the course; body becomes its first comment. Available to users who have started the course and to instructors.
+// @Tags Discussions
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param request body models.CreateThreadRequest true "Thread"
+// @Success 201 {object} models.DiscussionThread
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/threads [post]
+func CreateThread(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	var req models.CreateThreadRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	course, ok := loadCourse(c, courseID)
+	if !ok {
+		return
+	}
+	viewer, ok := newDiscussionViewer(c, courseID)
+	if !ok || !requireParticipant(c, viewer, course, "Only course participants can take part in discussions") {
+		return
+	}
+
+	thread, err := Store.CreateThread(models.DiscussionThread{CourseID: courseID, AuthorID: &viewer.userID, Title: req.Title}, req.Body)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create thread: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, thread)
+}
+
+// @Summary Get thread
+// @Description Thread with its comments. Comments hidden by a moderator are returned without text to everyone except instructors and administrators.
+// @Tags Discussions
+// @Produce json
+// @Param id path int true "Thread ID"
+// @Success 200 {object} models.DiscussionThreadDetails
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /threads/{id} [get]
+func GetThread(c *gin.Context) {
+	thread, viewer, ok := threadAccess(c)
+	if !ok {
+		return
+	}
+	comments, err := Store.ListThreadComments(thread.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get discussion: " + err.Error()})
+		return
+	}
+
+	details := models.DiscussionThreadDetails{DiscussionThread: thread, Comments: []models.DiscussionComment{}}
+	for _, comment := range comments {
+		if comment, ok := viewer.comment(comment); ok {
+			details.Comments = append(details.Comments, comment)
+		}
+	}
+	c.JSON(http.StatusOK, details)
+}
+
+// @Summary Comment in thread
+// @Description Adds a comment to the thread. Only instructors and administrators can comment in a locked thread.
+// @Tags Discussions
+// @Accept json
+// @Produce json
+// @Param id path int true "Thread ID"
+// @Param request body models.CreateCommentRequest true "Comment"
+// @Success 201 {object} models.DiscussionComment
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /threads/{id}/comments [post]
+func AddComment(c *gin.Context) {
+	thread, viewer, ok := threadAccess(c)
+	if !ok {
+		return
+	}
+	var req models.CreateCommentRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if thread.LockedAt != nil && !viewer.isStaff {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The thread is locked"})
+		return
+	}
+	course, ok := loadCourse(c, thread.CourseID)
+	if !ok || !requireParticipant(c, viewer, course, "Only course participants can take part in discussions") {
+		return
+	}
+
+	comment, err := Store.AddComment(models.DiscussionComment{ThreadID: thread.ID, AuthorID: &viewer.userID, Body: req.Body})
+	if errors.Is(err, storage.ErrThreadNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Thread not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to add comment: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, comment)
+}
+
+// @Summary List course reviews
+// @Description Reviews of the course with the average rating over visible reviews
+// @Tags Discussions
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.CourseReviews
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/reviews [get]
+func ListCourseReviews(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	if _, ok := loadCourse(c, courseID); !ok {
+		return
+	}
+	viewer, ok := newDiscussionViewer(c, courseID)
+	if !ok {
+		return
+	}
+	reviews, err := Store.ListCourseReviews(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get reviews: " + err.Error()})
+		return
+	}
+
+	result := models.CourseReviews{Reviews: []models.CourseReview{}}
+	sum := 0
+	for _, review := range reviews {
+		review, ok := viewer.review(review)
+		if !ok {
+			continue
+		}
+		result.Reviews = append(result.Reviews, review)
+		if review.HiddenAt == nil && !review.Shadowed {
+			result.Count++
+			sum += review.Rating
+		}
+	}
+	if result.Count > 0 {
+		average := math.Round(float64(sum)/float64(result.Count)*100) / 100
+		result.Average = &average
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Review course
+// @Description Creates or replaces the current user's review of the course. Available to users who have started the course.
+// @Tags Discussions
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param request body models.ReviewRequest true "Review"
+// @Success 200 {object} models.CourseReview
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/reviews [put]
+func SaveReview(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	var req models.ReviewRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	course, ok := loadCourse(c, courseID)
+	if !ok {
+		return
+	}
+	userID := c.GetInt("userID")
+	enrolled, err := enrolledInCourse(userID, course)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get progress: " + err.Error()})
+		return
+	}
+	if !enrolled {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only course participants can review the course"})
+		return
+	}
+
+	review, err := Store.SaveReview(models.CourseReview{CourseID: courseID, UserID: &userID, Rating: req.Rating, Body: req.Body})
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save review: " + err.Error()})
+		return
+	}
+	review.HiddenBy = nil
+	c.JSON(http.StatusOK, review)
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+func TestDiscussionsHiddenAcrossOrganizations(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Store = &storage.MockStorage{}
+
+	const courseID, userID = 2, 2
+	author := userID
+	thread, err := Store.CreateThread(models.DiscussionThread{CourseID: courseID, AuthorID: &author, Title: "Question"}, "How?")
+	if err != nil {
+		t.Fatalf("create thread: %v", err)
+	}
+	if _, err := Store.SaveReview(models.CourseReview{CourseID: courseID, UserID: &author, Rating: 5}); err != nil {
+		t.Fatalf("save review: %v", err)
+	}
+	org, err := Store.CreateOrganization(models.Organization{Slug: "discussions-test", Name: "Other", IsActive: true})
+	if err != nil {
+		t.Fatalf("create organization: %v", err)
+	}
+
+	r := gin.New()
+	r.Use(func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("organizationID", models.DefaultOrganizationID)
+	})
+	r.GET("/courses/:id/threads", ListThreads)
+	r.GET("/courses/:id/reviews", ListCourseReviews)
+	r.GET("/threads/:id", GetThread)
+
+	paths := []string{
+		"/courses/" + strconv.Itoa(courseID) + "/threads",
+		"/courses/" + strconv.Itoa(courseID) + "/reviews",
+		"/threads/" + strconv.Itoa(thread.ID),
+	}
+	get := func(path string) int {
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w.Code
+	}
+
+	for _, path := range paths {
+		if code := get(path); code != http.StatusOK {
+			t.Errorf("GET %s in the course organization: status = %d, want %d", path, code, http.StatusOK)
+		}
+	}
+
+	// Курс переносится в другую организацию: его обсуждения и отзывы не должны быть видны
+	if err := Store.SetCourseOrganization(courseID, org.ID); err != nil {
+		t.Fatalf("move course: %v", err)
+	}
+	defer Store.SetCourseOrganization(courseID, models.DefaultOrganizationID)
+
+	for _, path := range paths {
+		if code := get(path); code != http.StatusNotFound {
+			t.Errorf("GET %s from another organization: status = %d, want %d", path, code, http.StatusNotFound)
+		}
+	}
+}
@@ -0,0 +1,266 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+const (
+	// emailChangeTTL — сколько действует ссылка подтверждения на новом адресе
+	emailChangeTTL = 24 * time.Hour
+	// emailChangeCancelTTL — сколько владелец старого адреса может отменить или откатить смену
+	emailChangeCancelTTL = 7 * 24 * time.Hour
+)
+
+func newEmailChangeToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate email change token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// requestEmailChange сохраняет запрос на смену email и отправляет ссылки на оба адреса.
+// pending — false, если новый адрес совпадает с текущим.
+func requestEmailChange(c *gin.Context, userID int, newEmail string) (pending bool, err error) {
+	user, err := Store.GetUserByID(userID)
+	if err != nil {
+		return false, err
+	}
+	if strings.EqualFold(user.Email, newEmail) {
+		return false, nil
+	}
+
+	confirmToken, err := newEmailChangeToken()
+	if err != nil {
+		return false, err
+	}
+	cancelToken, err := newEmailChangeToken()
+	if err != nil {
+		return false, err
+	}
+
+	now := time.Now()
+	_, err = Store.CreateEmailChange(models.EmailChange{
+		UserID:           user.ID,
+		OldEmail:         user.Email,
+		NewEmail:         newEmail,
+		ConfirmTokenHash: hashToken(confirmToken),
+		CancelTokenHash:  hashToken(cancelToken),
+		RequestedIP:      c.ClientIP(),
+		ExpiresAt:        now.Add(emailChangeTTL),
+		CancelExpiresAt:  now.Add(emailChangeCancelTTL),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	_, baseURL := currentLoginAlerts()
+	go func() {
+		confirmURL := baseURL + "/api/account/email/confirm?token=" + url.QueryEscape(confirmToken)
+		if err := mail.SendEmailChangeConfirmation(newEmail, confirmURL, emailChangeTTL, UserLocale(user.Locale)); err != nil {
+			log.Printf("Failed to send email change confirmation for user %d: %v", user.ID, err)
+		}
+		if user.Email == "" {
+			return
+		}
+		cancelURL := baseURL + "/api/account/email/cancel?token=" + url.QueryEscape(cancelToken)
+		if err := mail.SendEmailChangeNotice(user.Email, newEmail, cancelURL, UserLocale(user.Locale)); err != nil {
+			log.Printf("Failed to send email change notice for user %d: %v", user.ID, err)
+		}
+	}()
+	return true, nil
+}
+
+// emailChangePage — страница подтверждения для ссылок из писем. Изменение выполняется
+// только POST-запросом, чтобы его не вызвали почтовые сканеры, открывающие ссылки.
+var emailChangePage = template.Must(template.New("email-change").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>LMS email change</title></head>
+<body>
+{{if .Done}}<p>{{.Message}}</p>{{else}}
+<p>{{.Prompt}}</p>
+<form method="post" action="{{.Action}}">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">{{.Button}}</button>
+</form>{{end}}
+</body></html>`))
+
+type emailChangePageData struct {
+	Action  string
+	Prompt  string
+	Button  string
+	Token   string
+	Done    bool
+	Message string
+}
+
+// respondEmailChange отвечает HTML-страницей на отправку формы и JSON на остальные запросы
+func respondEmailChange(c *gin.Context, status int, message string) {
+	if c.ContentType() == "application/x-www-form-urlencoded" {
+		c.Status(status)
+		c.Header("Content-Type", "text/html; charset=utf-8")
+		emailChangePage.Execute(c.Writer, emailChangePageData{Done: true, Message: Translate(c, message)})
+		return
+	}
+	if status == http.StatusOK {
+		c.JSON(status, models.SuccessResponse{Message: Translate(c, message)})
+	} else {
+		c.JSON(status, models.ErrorResponse{Error: message})
+	}
+}
+
+// @Summary Confirm email change page
+// @Description Confirmation page opened from the link sent to the new address; the change itself is a POST
+// @Tags Account
+// @Produce html
+// @Param token query string true "Confirmation token from the email"
+// @Success 200 {string} string "HTML page"
+// @Router /account/email/confirm [get]
+func ConfirmEmailChangePage(c *gin.Context) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	emailChangePage.Execute(c.Writer, emailChangePageData{
+		Action: "/api/account/email/confirm",
+		Prompt: Translate(c, "Use this address for your LMS account?"),
+		Button: Translate(c, "Confirm email address"),
+		Token:  c.Query("token"),
+	})
+}
+
+// @Summary Confirm email change
+// @Description Applies the new email using the token sent to it
+// @Tags Account
+// @Accept json,x-www-form-urlencoded
+// @Produce json,html
+// @Param request body models.EmailChangeTokenRequest true "Confirmation token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid, used or expired token"
+// @Failure 409 {object} models.ErrorResponse "Address already in use"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/email/confirm [post]
+func ConfirmEmailChange(c *gin.Context) {
+	var req models.EmailChangeTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondEmailChange(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	change, err := Store.ConfirmEmailChange(hashToken(req.Token))
+	switch {
+	case errors.Is(err, storage.ErrEmailChangeInvalid):
+		respondEmailChange(c, http.StatusBadRequest, "This link is invalid, already used or expired")
+		return
+	case errors.Is(err, storage.ErrDuplicateEmail):
+		respondEmailChange(c, http.StatusConflict, "This address is already used by another account")
+		return
+	case err != nil:
+		log.Printf("Failed to confirm email change: %v", err)
+		respondEmailChange(c, http.StatusInternalServerError, "Failed to confirm the email change")
+		return
+	}
+
+	log.Printf("User %d confirmed email change %d", change.UserID, change.ID)
+	respondEmailChange(c, http.StatusOK, "Your email address has been changed.")
+}
+
+// @Summary Cancel email change page
+// @Description Page opened from the notice sent to the old address; the cancellation itself is a POST
+// @Tags Account
+// @Produce html
+// @Param token query string true "Cancellation token from the email"
+// @Success 200 {string} string "HTML page"
+// @Router /account/email/cancel [get]
+func CancelEmailChangePage(c *gin.Context) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	emailChangePage.Execute(c.Writer, emailChangePageData{
+		Action: "/api/account/email/cancel",
+		Prompt: Translate(c, "Didn't ask to change your LMS email? Cancel the change. If it was already confirmed, "+
+			"your previous address is restored and you will be asked to set a new password."),
+		Button: Translate(c, "Cancel email change"),
+		Token:  c.Query("token"),
+	})
+}
+
+// @Summary Cancel or revert email change
+// @Description Cancels a pending email change using the token sent to the old address; a confirmed change is reverted, trusted devices are revoked and a password change is required
+// @Tags Account
+// @Accept json,x-www-form-urlencoded
+// @Produce json,html
+// @Param request body models.EmailChangeTokenRequest true "Cancellation token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid, used or expired token"
+// @Failure 409 {object} models.ErrorResponse "Old address is now used by another account"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/email/cancel [post]
+func CancelEmailChange(c *gin.Context) {
+	var req models.EmailChangeTokenRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respondEmailChange(c, http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	change, err := Store.CancelEmailChange(hashToken(req.Token))
+	switch {
+	case errors.Is(err, storage.ErrEmailChangeInvalid):
+		respondEmailChange(c, http.StatusBadRequest, "This link is invalid, already used or expired")
+		return
+	case errors.Is(err, storage.ErrDuplicateEmail):
+		respondEmailChange(c, http.StatusConflict, "Your previous address is now used by another account; contact an administrator")
+		return
+	case err != nil:
+		log.Printf("Failed to cancel email change: %v", err)
+		respondEmailChange(c, http.StatusInternalServerError, "Failed to cancel the email change")
+		return
+	}
+
+	if change.ConfirmedAt == nil {
+		respondEmailChange(c, http.StatusOK, "The email change has been cancelled.")
+		return
+	}
+	forgetSessionState(change.UserID)
+	log.Printf("User %d reverted confirmed email change %d", change.UserID, change.ID)
+	respondEmailChange(c, http.StatusOK,
+		"Your previous email address has been restored. Sign in and set a new password; other sessions are blocked until you do.")
+}
+
+// @Summary List email changes of a user
+// @Description Audit trail of email change requests for a user, most recent first (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} models.EmailChange
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{id}/email-changes [get]
+func ListEmailChanges(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	changes, err := Store.ListEmailChanges(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list email changes: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, changes)
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"io"
+	"lmsmodule/backend-svc/models"
+	"net/http"
+	"time"
+)
+
+// eventStreamKeepAlive — период комментариев-пингов, не дающих прокси закрыть простаивающий поток
+const eventStreamKeepAlive = 25 * time.Second
+
+// @Summary Stream my events
+// @Description Server-Sent Events stream of the current user's domain events (task completed, status changes, etc.)
+// @Tags User
+// @Produce text/event-stream
+// @Security BearerAuth
+// @Success 200 {object} models.OutboxEvent
+// @Failure 503 {object} models.ErrorResponse
+// @Router /events/stream [get]
+func StreamEvents(c *gin.Context) {
+	if Events == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Event stream is not available"})
+		return
+	}
+
+	events, unsubscribe := Events.Subscribe(c.GetInt("userID"))
+	defer unsubscribe()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	// Отключает буферизацию ответа в nginx
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventStreamKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case <-c.Request.Context().Done():
+			return false
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				return true
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.EventType, data)
+			return true
+		case <-keepAlive.C:
+			io.WriteString(w, ": keep-alive\n\n")
+			return true
+		}
+	})
+}
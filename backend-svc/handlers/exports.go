@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+)
+
+// exportFlushRows — через сколько строк выгрузка отправляется клиенту (chunked transfer)
+const exportFlushRows = 500
+
+// csvExport пишет CSV прямо в ответ, не собирая его в памяти. Заголовки ответа отправляются
+// с первой строкой: ошибка хранилища до нее превращается в ответ 500, а посреди выгрузки
+// уже не меняет статус — она логируется, и ответ обрывается.
+type csvExport struct {
+	c       *gin.Context
+	name    string
+	header  []string
+	w       *csv.Writer
+	rows    int
+	started bool
+}
+
+func newCSVExport(c *gin.Context, name string, header []string) *csvExport {
+	return &csvExport{c: c, name: name, header: header, w: csv.NewWriter(c.Writer)}
+}
+
+func (e *csvExport) begin() {
+	if e.started {
+		return
+	}
+	e.started = true
+	e.c.Header("Content-Type", "text/csv; charset=utf-8")
+	e.c.Header("Content-Disposition", `attachment; filename="`+e.name+`-`+time.Now().UTC().Format("2006-01-02")+`.csv"`)
+	e.c.Status(http.StatusOK)
+	e.w.Write(e.header)
+}
+
+// write добавляет строку; ошибка означает, что клиент отключился
+func (e *csvExport) write(record []string) error {
+	e.begin()
+	e.w.Write(record)
+	e.rows++
+	if e.rows%exportFlushRows == 0 {
+		e.w.Flush()
+		e.c.Writer.Flush()
+	}
+	return e.w.Error()
+}
+
+// finish дописывает буфер; err — ошибка чтения строк, прервавшая выгрузку
+func (e *csvExport) finish(err error) {
+	if abortExport(e.c, e.started, e.rows, err) {
+		return
+	}
+	e.begin()
+	e.w.Flush()
+}
+
+// jsonArrayExport пишет JSON-массив поэлементно с теми же правилами ошибок, что и csvExport
+type jsonArrayExport struct {
+	c       *gin.Context
+	enc     *json.Encoder
+	rows    int
+	started bool
+}
+
+func newJSONArrayExport(c *gin.Context) *jsonArrayExport {
+	return &jsonArrayExport{c: c, enc: json.NewEncoder(c.Writer)}
+}
+
+func (e *jsonArrayExport) begin() {
+	if e.started {
+		return
+	}
+	e.started = true
+	e.c.Header("Content-Type", "application/json; charset=utf-8")
+	e.c.Status(http.StatusOK)
+	e.c.Writer.WriteString("[")
+}
+
+func (e *jsonArrayExport) write(v interface{}) error {
+	e.begin()
+	if e.rows > 0 {
+		if _, err := e.c.Writer.WriteString(","); err != nil {
+			return err
+		}
+	}
+	e.rows++
+	if err := e.enc.Encode(v); err != nil {
+		return err
+	}
+	if e.rows%exportFlushRows == 0 {
+		e.c.Writer.Flush()
+	}
+	return nil
+}
+
+func (e *jsonArrayExport) finish(err error) {
+	if abortExport(e.c, e.started, e.rows, err) {
+		return
+	}
+	e.begin()
+	e.c.Writer.WriteString("]")
+}
+
+// abortExport обрабатывает ошибку выгрузки; возвращает true, если выгрузка прервана
+func abortExport(c *gin.Context, started bool, rows int, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case !started:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to export data: " + err.Error()})
+	default:
+		log.Printf("Export %s aborted after %d rows: %v", c.Request.URL.Path, rows, err)
+		c.Abort()
+	}
+	return true
+}
+
+// formatExportTime — время в выгрузке CSV; пустая строка для nil
+func formatExportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// @Summary Export learner progress
+// @Description Streams every completed task of every user as CSV (user, course, task, completion time), ordered by user and task. Suitable for hundreds of thousands of rows.
+// @Tags Admin
+// @Produce text/csv
+// @Security BearerAuth
+// @Param courseId query int false "Only this course"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} models.ErrorResponse
+// @Router /admin/progress/export [get]
+func ExportProgress(c *gin.Context) {
+	var filter models.ProgressExportFilter
+	if raw := c.Query("courseId"); raw != "" {
+		courseID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+			return
+		}
+		filter.CourseID = courseID
+	}
+
+	export := newCSVExport(c, "progress", []string{"user_id", "username", "course_id", "course", "task_id", "task", "completed_at"})
+	export.finish(Store.EachProgressRecord(filter, func(record models.ProgressRecord) error {
+		return export.write([]string{strconv.Itoa(record.UserID), record.Username, strconv.Itoa(record.CourseID),
+			record.CourseTitle, strconv.Itoa(record.TaskID), record.TaskTitle, formatExportTime(record.CompletedAt)})
+	}))
+}
+
+// exportUsers выгружает пользователей в CSV или JSON-массив
+func exportUsers(c *gin.Context) {
+	if c.Query("format") != "csv" {
+		export := newJSONArrayExport(c)
+		export.finish(Store.EachUserSummary(func(user models.UserSummary) error {
+			return export.write(adminUserProfile(user))
+		}))
+		return
+	}
+
+	export := newCSVExport(c, "users", []string{"id", "username", "email", "full_name", "is_admin", "is_active", "2fa_enabled", "last_login", "locale", "timezone"})
+	export.finish(Store.EachUserSummary(func(user models.UserSummary) error {
+		lastLogin := ""
+		if !user.LastLogin.IsZero() {
+			lastLogin = user.LastLogin.UTC().Format(time.RFC3339)
+		}
+		return export.write([]string{strconv.Itoa(user.ID), user.Username, user.Email, user.FullName,
+			strconv.FormatBool(user.IsAdmin), strconv.FormatBool(user.IsActive), strconv.FormatBool(user.Is2FAEnabled),
+			lastLogin, UserLocale(user.Locale), UserTimezone(user.Timezone)})
+	}))
+}
@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"net/http"
+	"strings"
+)
+
+// parseFields разбирает параметр ?fields=a,b,c. Возвращает nil, если параметр не задан;
+// при неизвестном поле отвечает 400 и возвращает ok=false.
+func parseFields(c *gin.Context, allowed []string) (fields []string, ok bool) {
+	raw := c.Query("fields")
+	if raw == "" {
+		return nil, true
+	}
+
+	seen := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" || seen[field] {
+			continue
+		}
+		if !containsString(allowed, field) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{
+				Error: "Unknown field " + field + "; allowed: " + strings.Join(allowed, ","),
+			})
+			return nil, false
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	if len(fields) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "fields must list at least one field"})
+		return nil, false
+	}
+	return fields, true
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// userFieldSet оставляет у пользователей только запрошенные поля
+func userFieldSet(users []models.UserSummary, fields []string) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(users))
+	for _, user := range users {
+		item := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			switch field {
+			case "id":
+				item[field] = user.ID
+			case "username":
+				item[field] = user.Username
+			case "email":
+				item[field] = user.Email
+			case "fullName":
+				item[field] = user.FullName
+			case "is2faEnabled":
+				item[field] = user.Is2FAEnabled
+			case "isAdmin":
+				item[field] = user.IsAdmin
+			case "isActive":
+				item[field] = user.IsActive
+			case "lastLogin":
+				item[field] = user.LastLogin
+			case "version":
+				item[field] = user.Version
+			}
+		}
+		result = append(result, item)
+	}
+	return result
+}
+
+// courseFieldSet оставляет у курсов только запрошенные поля
+func courseFieldSet(courses []models.Course, fields []string) []map[string]interface{} {
+	result := make([]map[string]interface{}, 0, len(courses))
+	for _, course := range courses {
+		item := make(map[string]interface{}, len(fields))
+		for _, field := range fields {
+			switch field {
+			case "id":
+				item[field] = course.ID
+			case "vulnerabilityType":
+				item[field] = course.VulnerabilityType
+			case "tasksCount":
+				item[field] = course.TasksCount
+			case "averageDifficulty":
+				item[field] = course.AverageDifficulty
+			case "enrollments":
+				item[field] = course.Enrollments
+			case "reviews":
+				item[field] = course.Reviews
+			case "averageRating":
+				item[field] = course.AverageRating
+			case "description":
+				item[field] = course.Description
+			case "updatedAt":
+				item[field] = course.UpdatedAt
+			}
+		}
+		result = append(result, item)
+	}
+	return result
+}
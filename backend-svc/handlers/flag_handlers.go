@@ -0,0 +1,89 @@
+package handlers
+
+import (
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"net/http"
+	"regexp"
+)
+
+var flagKeyPattern = regexp.MustCompile(`^[a-z0-9_.-]{1,100}$`)
+
+// @Summary Get my feature flags
+// @Description Evaluated feature flags for the current user
+// @Tags User
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]bool
+// @Router /flags [get]
+func GetMyFeatureFlags(c *gin.Context) {
+	c.JSON(http.StatusOK, Flags.EvaluateAll(c.GetInt("userID")))
+}
+
+// @Summary List feature flags
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.FeatureFlag
+// @Router /admin/flags [get]
+func GetFeatureFlags(c *gin.Context) {
+	c.JSON(http.StatusOK, Flags.List())
+}
+
+// @Summary Create or update feature flag
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Flag key"
+// @Param request body models.UpsertFeatureFlagRequest true "Flag targeting"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/flags/{key} [put]
+func UpsertFeatureFlag(c *gin.Context) {
+	key := c.Param("key")
+	if !flagKeyPattern.MatchString(key) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid flag key"})
+		return
+	}
+
+	var req models.UpsertFeatureFlagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	err := Store.UpsertFeatureFlag(models.FeatureFlag{
+		Key:               key,
+		Description:       req.Description,
+		Enabled:           req.Enabled,
+		RolloutPercentage: req.RolloutPercentage,
+		TargetGroups:      req.TargetGroups,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save flag: " + err.Error()})
+		return
+	}
+	Flags.Invalidate()
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Feature flag saved successfully")})
+}
+
+// @Summary Delete feature flag
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Flag key"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/flags/{key} [delete]
+func DeleteFeatureFlag(c *gin.Context) {
+	if err := Store.DeleteFeatureFlag(c.Param("key")); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete flag: " + err.Error()})
+		return
+	}
+	Flags.Invalidate()
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Feature flag deleted successfully")})
+}
@@ -0,0 +1,329 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/graphql"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+	"net/http"
+	"sort"
+	"sync"
+)
+
+const (
+	defaultUsersPageSize = 20
+	maxUsersPageSize     = 100
+)
+
+var errAdminRequired = errors.New("admin access required")
+
+// graphQLViewer — текущий пользователь запроса; прогресс загружается один раз на запрос
+type graphQLViewer struct {
+	userID  int
+	isAdmin bool
+	locale  string
+	// store ограничен организацией пользователя
+	store storage.Storage
+
+	progress progressLoader
+}
+
+func (v *graphQLViewer) completed(taskID int) (bool, error) {
+	progress, err := v.progress.load(v.userID)
+	return progress.Completed[taskID], err
+}
+
+// progressLoader загружает прогресс пользователей пачками, как dataloader: резолвер списка
+// заранее сообщает ID элементов (expect), и первое же обращение к прогрессу любого из них
+// загружает всю пачку одним запросом. Загруженный прогресс живет до конца запроса.
+type progressLoader struct {
+	mu      sync.Mutex
+	pending []int
+	loaded  map[int]models.UserProgress
+}
+
+// expect добавляет пользователей в следующую пачку
+func (l *progressLoader) expect(userIDs ...int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(l.pending, userIDs...)
+}
+
+func (l *progressLoader) load(userID int) (models.UserProgress, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if progress, ok := l.loaded[userID]; ok {
+		return progress, nil
+	}
+	batch, err := Store.GetProgressByUserIDs(append(l.pending, userID))
+	if err != nil {
+		return models.UserProgress{}, err
+	}
+	l.pending = nil
+	if l.loaded == nil {
+		l.loaded = make(map[int]models.UserProgress, len(batch))
+	}
+	for id, progress := range batch {
+		l.loaded[id] = progress
+	}
+	return l.loaded[userID], nil
+}
+
+type graphQLViewerKey struct{}
+
+func viewerFrom(ctx context.Context) *graphQLViewer {
+	return ctx.Value(graphQLViewerKey{}).(*graphQLViewer)
+}
+
+// graphQLProgress — прогресс пользователя в форме, удобной для выборки полей
+type graphQLProgress struct {
+	UserID           int   `json:"userId"`
+	CompletedTaskIDs []int `json:"completedTaskIds"`
+	CompletedCount   int   `json:"completedCount"`
+}
+
+// graphQLUserPage — страница списка пользователей
+type graphQLUserPage struct {
+	Total int                  `json:"total"`
+	Items []models.UserSummary `json:"items"`
+}
+
+var graphQLSchema = buildGraphQLSchema()
+
+func buildGraphQLSchema() *graphql.Schema {
+	task := &graphql.Object{Name: "Task", Fields: map[string]*graphql.Field{
+		"id": {}, "courseId": {}, "title": {}, "description": {}, "difficulty": {}, "order": {},
+		"completed": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return viewerFrom(p.Context).completed(p.Source.(models.Task).ID)
+		}},
+	}}
+
+	course := &graphql.Object{Name: "Course", Fields: map[string]*graphql.Field{
+		"id": {}, "vulnerabilityType": {}, "tasksCount": {}, "description": {}, "updatedAt": {},
+		"tasks": {Type: task},
+	}}
+
+	progress := &graphql.Object{Name: "Progress", Fields: map[string]*graphql.Field{
+		"userId": {}, "completedTaskIds": {}, "completedCount": {},
+	}}
+
+	// Служебные поля видны только администраторам, как и в REST-профиле
+	adminOnly := func(name string) *graphql.Field {
+		return &graphql.Field{Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if !viewerFrom(p.Context).isAdmin {
+				return nil, nil
+			}
+			user := p.Source.(models.UserSummary)
+			switch name {
+			case "isAdmin":
+				return user.IsAdmin, nil
+			case "isActive":
+				return user.IsActive, nil
+			}
+			return user.LastLogin, nil
+		}}
+	}
+
+	avatar := &graphql.Object{Name: "Avatar", Fields: map[string]*graphql.Field{
+		"small": {}, "medium": {}, "large": {},
+	}}
+
+	user := &graphql.Object{Name: "User", Fields: map[string]*graphql.Field{
+		"id": {}, "username": {}, "email": {}, "fullName": {}, "is2faEnabled": {}, "version": {},
+		"avatar": {Type: avatar, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return AvatarURLs(p.Source.(models.UserSummary).AvatarKey), nil
+		}},
+		"locale": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return UserLocale(p.Source.(models.UserSummary).Locale), nil
+		}},
+		"timezone": {Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return UserTimezone(p.Source.(models.UserSummary).Timezone), nil
+		}},
+		"isAdmin":   adminOnly("isAdmin"),
+		"isActive":  adminOnly("isActive"),
+		"lastLogin": adminOnly("lastLogin"),
+		"progress": {Type: progress, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			userID := p.Source.(models.UserSummary).ID
+			up, err := viewerFrom(p.Context).progress.load(userID)
+			if err != nil {
+				return nil, err
+			}
+			result := graphQLProgress{UserID: userID, CompletedTaskIDs: []int{}}
+			for taskID, done := range up.Completed {
+				if done {
+					result.CompletedTaskIDs = append(result.CompletedTaskIDs, taskID)
+				}
+			}
+			sort.Ints(result.CompletedTaskIDs)
+			result.CompletedCount = len(result.CompletedTaskIDs)
+			return result, nil
+		}},
+	}}
+
+	userPage := &graphql.Object{Name: "UserPage", Fields: map[string]*graphql.Field{
+		"total": {},
+		"items": {Type: user},
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: map[string]*graphql.Field{
+		"courses": {Type: course, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			courses, err := viewerFrom(p.Context).store.GetCourses()
+			if err != nil {
+				return nil, err
+			}
+			return courses, LocalizeCourses(viewerFrom(p.Context).locale, courses)
+		}},
+		"course": {Type: course, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := requiredIntArg(p.Args, "id")
+			if err != nil {
+				return nil, err
+			}
+			viewer := viewerFrom(p.Context)
+			course, err := viewer.store.GetCourseByID(id)
+			if err != nil {
+				return nil, err
+			}
+			// Архивный курс видят администраторы и начавшие его слушатели
+			if course.ArchivedAt != nil && !viewer.isAdmin {
+				if enrolled, err := enrolledInCourse(viewer.userID, course); err != nil || !enrolled {
+					if err == nil {
+						err = storage.ErrCourseNotFound
+					}
+					return nil, err
+				}
+			}
+			return course, LocalizeCourse(viewerFrom(p.Context).locale, &course)
+		}},
+		"me": {Type: user, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			return Store.GetUserPublicByID(viewerFrom(p.Context).userID)
+		}},
+		"user": {Type: user, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			id, err := requiredIntArg(p.Args, "id")
+			if err != nil {
+				return nil, err
+			}
+			if viewer := viewerFrom(p.Context); id != viewer.userID && !viewer.isAdmin {
+				return nil, errAdminRequired
+			}
+			return Store.GetUserPublicByID(id)
+		}},
+		"users": {Type: userPage, Resolve: func(p graphql.ResolveParams) (interface{}, error) {
+			if !viewerFrom(p.Context).isAdmin {
+				return nil, errAdminRequired
+			}
+			page, err := resolveUsersPage(p.Args)
+			if err != nil {
+				return nil, err
+			}
+			for _, user := range page.Items {
+				viewerFrom(p.Context).progress.expect(user.ID)
+			}
+			return page, nil
+		}},
+	}}
+
+	return &graphql.Schema{Query: query}
+}
+
+// resolveUsersPage возвращает страницу пользователей (limit, offset) с необязательным поиском (search)
+func resolveUsersPage(args map[string]interface{}) (graphQLUserPage, error) {
+	limit, err := intArg(args, "limit", defaultUsersPageSize)
+	if err != nil {
+		return graphQLUserPage{}, err
+	}
+	offset, err := intArg(args, "offset", 0)
+	if err != nil {
+		return graphQLUserPage{}, err
+	}
+	if limit < 1 || limit > maxUsersPageSize {
+		return graphQLUserPage{}, errors.New("limit must be between 1 and 100")
+	}
+	if offset < 0 {
+		return graphQLUserPage{}, errors.New("offset must not be negative")
+	}
+
+	var users []models.UserSummary
+	if search, _ := args["search"].(string); search != "" {
+		users, err = Store.SearchUsers(search)
+	} else {
+		users, err = Store.ListUserSummaries()
+	}
+	if err != nil {
+		return graphQLUserPage{}, err
+	}
+
+	page := graphQLUserPage{Total: len(users), Items: []models.UserSummary{}}
+	if offset < len(users) {
+		end := offset + limit
+		if end > len(users) {
+			end = len(users)
+		}
+		page.Items = users[offset:end]
+	}
+	return page, nil
+}
+
+// requiredIntArg возвращает обязательный целочисленный аргумент
+func requiredIntArg(args map[string]interface{}, name string) (int, error) {
+	if args[name] == nil {
+		return 0, errors.New("argument \"" + name + "\" is required")
+	}
+	return intArg(args, name, 0)
+}
+
+// intArg возвращает целочисленный аргумент или def, если аргумент не передан
+func intArg(args map[string]interface{}, name string, def int) (int, error) {
+	v, ok := args[name]
+	if !ok || v == nil {
+		return def, nil
+	}
+	n, ok := v.(int)
+	if !ok {
+		return 0, errors.New("argument \"" + name + "\" must be an integer")
+	}
+	return n, nil
+}
+
+// @Summary GraphQL query
+// @Description Read-only GraphQL endpoint: courses with nested tasks, the current user with progress, paginated users (admin). Accepts POST with a JSON body or GET with ?query=.
+// @Tags GraphQL
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body graphql.Request true "GraphQL request"
+// @Success 200 {object} graphql.Response
+// @Failure 400 {object} graphql.Response
+// @Router /graphql [post]
+func GraphQLHandler(c *gin.Context) {
+	var req graphql.Request
+	if c.Request.Method == http.MethodGet {
+		req.Query = c.Query("query")
+		req.OperationName = c.Query("operationName")
+	} else if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, graphql.Response{Errors: []graphql.Error{{Message: "Invalid request data: " + err.Error()}}})
+		return
+	}
+	if req.Query == "" {
+		c.JSON(http.StatusBadRequest, graphql.Response{Errors: []graphql.Error{{Message: "query is required"}}})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	isAdmin, err := RequestAdminRights(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return
+	}
+
+	ctx := context.WithValue(c.Request.Context(), graphQLViewerKey{}, &graphQLViewer{
+		userID: userID, isAdmin: isAdmin, locale: RequestLocale(c), store: StoreFor(c),
+	})
+	resp := graphQLSchema.Execute(ctx, req)
+	if resp.Data == nil {
+		c.JSON(http.StatusBadRequest, resp)
+		return
+	}
+	c.JSON(http.StatusOK, resp)
+}
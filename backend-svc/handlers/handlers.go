@@ -2,22 +2,109 @@ package handlers
 
 import (
 	"database/sql"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/flags"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/outbox"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/settings"
 	"lmsmodule/backend-svc/storage"
 )
 
 // Глобальные переменные
 var (
-	Db        *sql.DB
-	Store     storage.Storage
-	JWTSecret = "your_strong_secret_here"
+	Db       *sql.DB
+	Store    storage.Storage
+	Settings *settings.Service
+	Flags    *flags.Service
+	Events   *outbox.Broker
+	Realtime *realtime.Hub
+	Presence realtime.Presence
+
+	authMu        sync.RWMutex
+	jwtSecret     string
+	tempJWTSecret string
+	tokenTTL      = 24 * time.Hour
+	tempTokenTTL  = 5 * time.Minute
 )
 
+// ConfigureAuth задает секреты и время жизни JWT-токенов из конфигурации сервиса.
+// Безопасно вызывать повторно во время работы при ротации секретов.
+func ConfigureAuth(cfg config.JWTConfig) {
+	authMu.Lock()
+	defer authMu.Unlock()
+
+	jwtSecret = cfg.Secret
+	tempJWTSecret = cfg.TempSecret
+	tokenTTL = cfg.TTL
+	tempTokenTTL = cfg.TempTTL
+}
+
+// JWTSecret возвращает текущий секрет для подписи и проверки access-токенов
+func JWTSecret() string {
+	authMu.RLock()
+	defer authMu.RUnlock()
+	return jwtSecret
+}
+
+func currentTempAuth() (string, time.Duration) {
+	authMu.RLock()
+	defer authMu.RUnlock()
+	return tempJWTSecret, tempTokenTTL
+}
+
+func currentTokenTTL() time.Duration {
+	authMu.RLock()
+	defer authMu.RUnlock()
+	return tokenTTL
+}
+
 // UseStorage устанавливает хранилище для обработчиков
 func UseStorage(s storage.Storage) {
 	Store = s
 }
 
-// CheckAdminRights проверяет, имеет ли пользователь права администратора
-func CheckAdminRights(userID int) (bool, error) {
-	return Store.IsAdmin(userID)
+// UseSettings устанавливает сервис runtime-параметров для обработчиков
+func UseSettings(s *settings.Service) {
+	Settings = s
+}
+
+// UseFlags устанавливает сервис флагов функциональности для обработчиков
+func UseFlags(f *flags.Service) {
+	Flags = f
+}
+
+// UseEvents устанавливает шину событий для потоковой доставки клиентам
+func UseEvents(b *outbox.Broker) {
+	Events = b
+}
+
+// UseRealtime устанавливает WebSocket-хаб для обработчиков
+func UseRealtime(h *realtime.Hub) {
+	Realtime = h
+}
+
+// UsePresence устанавливает источник сведений о подключенных пользователях: хаб или кластер реплик
+func UsePresence(p realtime.Presence) {
+	Presence = p
+}
+
+// requireInstructor отвечает 403 с сообщением denied, если пользователь не преподаватель
+func requireInstructor(c *gin.Context, denied string) (userID int, isAdmin, ok bool) {
+	userID = c.GetInt("userID")
+	isAdmin, isInstructor, err := requestInstructorRights(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return 0, false, false
+	}
+	if !isInstructor {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: denied})
+		return 0, false, false
+	}
+	return userID, isAdmin, true
 }
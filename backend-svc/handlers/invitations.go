@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newInvitationToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate invitation token: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// invitationURL — ссылка-приглашение; открывает предпросмотр, регистрация — POST по ней же + /register
+func invitationURL(token string) string {
+	return currentPublicURL() + "/api/invitations/" + url.PathEscape(token)
+}
+
+// createInvitation создает приглашение в организацию orgID из тела запроса
+func createInvitation(c *gin.Context, req models.CreateInvitationRequest, orgID int) {
+	token, err := newInvitationToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create invitation: " + err.Error()})
+		return
+	}
+
+	courseIDs := []int{}
+	seen := map[int]bool{}
+	for _, id := range req.CourseIDs {
+		if !seen[id] {
+			seen[id] = true
+			courseIDs = append(courseIDs, id)
+		}
+	}
+	createdBy := c.GetInt("userID")
+	invitation, err := Store.CreateInvitation(models.Invitation{
+		OrganizationID: orgID,
+		Group:          req.Group,
+		CourseIDs:      courseIDs,
+		MaxUses:        req.MaxUses,
+		TokenHash:      hashToken(token),
+		ExpiresAt:      time.Now().Add(time.Duration(req.ExpiresInHours) * time.Hour),
+		CreatedBy:      &createdBy,
+	})
+	switch {
+	case errors.Is(err, storage.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Organization not found"})
+		return
+	case errors.Is(err, storage.ErrGroupNotFound):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Group not found in the organization"})
+		return
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Course not found in the organization"})
+		return
+	case errors.Is(err, storage.ErrCourseArchived):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Course is archived"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create invitation: " + err.Error()})
+		return
+	}
+	invitation.URL = invitationURL(token)
+	c.JSON(http.StatusCreated, invitation)
+}
+
+// revokeInvitation отзывает приглашение из пути запроса; orgID = 0 — приглашение любой организации
+func revokeInvitation(c *gin.Context, orgID int) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid invitation ID"})
+		return
+	}
+	err = Store.RevokeInvitation(id, orgID)
+	if errors.Is(err, storage.ErrInvitationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invitation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to revoke invitation: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Invitation revoked")})
+}
+
+// listInvitations отвечает списком приглашений организации orgID (0 — всех)
+func listInvitations(c *gin.Context, orgID int) {
+	invitations, err := Store.ListInvitations(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get invitations: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, invitations)
+}
+
+// loadInvitation находит действующее приглашение по токену из пути; приглашения в отключенную
+// организацию недействительны
+func loadInvitation(c *gin.Context) (models.Invitation, bool) {
+	invitation, err := Store.GetInvitation(hashToken(c.Param("token")))
+	if err == nil {
+		var org models.Organization
+		if org, err = Store.GetOrganization(invitation.OrganizationID); err == nil && !org.IsActive {
+			err = storage.ErrInvitationExpired
+		}
+	}
+	switch {
+	case errors.Is(err, storage.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invitation not found"})
+		return invitation, false
+	case errors.Is(err, storage.ErrInvitationExpired):
+		c.JSON(http.StatusGone, models.ErrorResponse{Error: "Invitation is no longer valid"})
+		return invitation, false
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get invitation: " + err.Error()})
+		return invitation, false
+	}
+	return invitation, true
+}
+
+// @Summary Create invitation
+// @Description Create a sign-up link for an organization (admin only). maxUses 1 makes a single-use link, 0 lets any number of people register until the link expires. Everyone who registers through the link joins the group and gets the courses of the invitation. The link is returned only once.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateInvitationRequest true "Invitation"
+// @Success 201 {object} models.Invitation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/invitations [post]
+func CreateInvitation(c *gin.Context) {
+	var req models.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	orgID := req.OrganizationID
+	if orgID == 0 {
+		orgID = models.DefaultOrganizationID
+	}
+	createInvitation(c, req, orgID)
+}
+
+// @Summary List invitations
+// @Description Invitations of all organizations or of one organization, newest first (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param organizationId query int false "Organization ID"
+// @Success 200 {array} models.Invitation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/invitations [get]
+func ListInvitations(c *gin.Context) {
+	orgID := 0
+	if raw := c.Query("organizationId"); raw != "" {
+		id, err := strconv.Atoi(raw)
+		if err != nil || id <= 0 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid organization ID"})
+			return
+		}
+		orgID = id
+	}
+	listInvitations(c, orgID)
+}
+
+// @Summary Revoke invitation
+// @Description Revoke an invitation link (admin only). Users who already registered through it keep their accounts.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/invitations/{id} [delete]
+func RevokeInvitation(c *gin.Context) {
+	revokeInvitation(c, 0)
+}
+
+// @Summary Create invitation to my organization
+// @Description Create a sign-up link for the current user's organization (organization admins only); see POST /admin/invitations. organizationId is ignored.
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.CreateInvitationRequest true "Invitation"
+// @Success 201 {object} models.Invitation
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/invitations [post]
+func CreateMyInvitation(c *gin.Context) {
+	orgID, ok := requireOrganizationAdmin(c)
+	if !ok {
+		return
+	}
+	var req models.CreateInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	createInvitation(c, req, orgID)
+}
+
+// @Summary List invitations to my organization
+// @Description Invitations of the current user's organization, newest first (organization admins only)
+// @Tags Organization
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Invitation
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/invitations [get]
+func ListMyInvitations(c *gin.Context) {
+	if orgID, ok := requireOrganizationAdmin(c); ok {
+		listInvitations(c, orgID)
+	}
+}
+
+// @Summary Revoke invitation to my organization
+// @Description Revoke an invitation link of the current user's organization (organization admins only)
+// @Tags Organization
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Invitation ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/invitations/{id} [delete]
+func RevokeMyInvitation(c *gin.Context) {
+	if orgID, ok := requireOrganizationAdmin(c); ok {
+		revokeInvitation(c, orgID)
+	}
+}
+
+// @Summary Preview invitation
+// @Description What the invitation link grants: the organization, the group and the assigned courses
+// @Tags Authentication
+// @Produce json
+// @Param token path string true "Token from the invitation link"
+// @Success 200 {object} models.InvitationPreview
+// @Failure 404 {object} models.ErrorResponse "Unknown invitation"
+// @Failure 410 {object} models.ErrorResponse "Invitation revoked, expired or used up"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /invitations/{token} [get]
+func GetInvitationPreview(c *gin.Context) {
+	invitation, ok := loadInvitation(c)
+	if !ok {
+		return
+	}
+	preview := models.InvitationPreview{
+		OrganizationName: invitation.OrganizationName,
+		Group:            invitation.Group,
+		Courses:          []string{},
+		ExpiresAt:        invitation.ExpiresAt,
+	}
+	// Курсы показываются только из организации приглашения
+	store := storage.InOrganization(Store, invitation.OrganizationID)
+	for _, courseID := range invitation.CourseIDs {
+		course, err := store.GetCourseByID(courseID)
+		if errors.Is(err, storage.ErrCourseNotFound) {
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get invitation: " + err.Error()})
+			return
+		}
+		preview.Courses = append(preview.Courses, course.VulnerabilityType)
+	}
+	c.JSON(http.StatusOK, preview)
+}
+
+// @Summary Register with invitation
+// @Description Create an account in the invitation's organization, join its group and get its courses. Works while public registration is closed, does not require a captcha and is not held for approval: the invitation is the approval. As with /register, the response does not reveal whether the username or email is taken.
+// @Tags Authentication
+// @Accept json
+// @Produce json
+// @Param token path string true "Token from the invitation link"
+// @Param request body models.RegisterRequest true "Registration data (captchaToken is ignored)"
+// @Success 202 {object} models.RegisterResponse "Registration accepted"
+// @Failure 400 {object} models.ErrorResponse "Invalid request"
+// @Failure 404 {object} models.ErrorResponse "Unknown invitation"
+// @Failure 410 {object} models.ErrorResponse "Invitation revoked, expired or used up"
+// @Failure 500 {object} models.ErrorResponse "Server error"
+// @Router /invitations/{token}/register [post]
+func RegisterWithInvitation(c *gin.Context) {
+	var req models.RegisterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data"})
+		return
+	}
+	if _, ok := loadInvitation(c); !ok {
+		return
+	}
+	user, ok := newUserAccount(c, req)
+	if !ok {
+		return
+	}
+
+	// Приглашение проверяется еще раз в транзакции регистрации: его могли исчерпать параллельно
+	_, err := Store.RegisterWithInvitation(hashToken(c.Param("token")), user)
+	switch {
+	case errors.Is(err, storage.ErrInvitationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Invitation not found"})
+		return
+	case errors.Is(err, storage.ErrInvitationExpired):
+		c.JSON(http.StatusGone, models.ErrorResponse{Error: "Invitation is no longer valid"})
+		return
+	}
+	respondRegistration(c, req, registrationAcceptedMessage, err)
+}
+
+// @Summary My assigned courses
+// @Description Courses assigned to the current user, e.g. by the invitation they registered with
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.CourseAssignment
+// @Failure 500 {object} models.ErrorResponse
+// @Router /courses/assigned [get]
+func ListMyCourseAssignments(c *gin.Context) {
+	assignments, err := StoreFor(c).ListCourseAssignments(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get assigned courses: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, assignments)
+}
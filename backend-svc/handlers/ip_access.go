@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"lmsmodule/backend-svc/ipaccess"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	// ipAllowlists — кэш разобранных списков разрешенных адресов организаций; проверяется на каждый запрос
+	ipAllowlists   = map[int]ipaccess.List{}
+	ipAllowlistsMu sync.RWMutex
+)
+
+// ReloadIPAllowlists перечитывает списки разрешенных адресов организаций; вызывается периодически,
+// чтобы изменения, сделанные на других репликах, применялись без перезапуска
+func ReloadIPAllowlists() error {
+	lists, err := Store.ListIPAllowlists()
+	if err != nil {
+		return fmt.Errorf("load ip allowlists: %w", err)
+	}
+	parsed := make(map[int]ipaccess.List, len(lists))
+	for _, list := range lists {
+		networks, err := ipaccess.Parse(strings.Join(list.CIDRs, ","))
+		if err != nil {
+			return fmt.Errorf("ip allowlist of organization %d: %w", list.OrganizationID, err)
+		}
+		parsed[list.OrganizationID] = networks
+	}
+
+	ipAllowlistsMu.Lock()
+	ipAllowlists = parsed
+	ipAllowlistsMu.Unlock()
+	return nil
+}
+
+// cacheIPAllowlist сразу применяет сохраненный список на этой реплике
+func cacheIPAllowlist(list models.IPAllowlist) {
+	networks, err := ipaccess.Parse(strings.Join(list.CIDRs, ","))
+	if err != nil {
+		return
+	}
+	ipAllowlistsMu.Lock()
+	defer ipAllowlistsMu.Unlock()
+	if len(networks) == 0 {
+		delete(ipAllowlists, list.OrganizationID)
+		return
+	}
+	ipAllowlists[list.OrganizationID] = networks
+}
+
+// OrganizationIPAllowlist возвращает список разрешенных адресов организации; пустой — без ограничений
+func OrganizationIPAllowlist(orgID int) ipaccess.List {
+	ipAllowlistsMu.RLock()
+	defer ipAllowlistsMu.RUnlock()
+	return ipAllowlists[orgID]
+}
+
+// getIPAllowlist отвечает списком разрешенных адресов организации orgID
+func getIPAllowlist(c *gin.Context, orgID int) {
+	list, err := Store.GetIPAllowlist(orgID)
+	if err != nil {
+		respondIPAllowlistError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// setIPAllowlist сохраняет список разрешенных адресов организации orgID из тела запроса.
+// Список, который закрыл бы доступ самому пользователю из его организации, отклоняется.
+func setIPAllowlist(c *gin.Context, orgID int) {
+	var req models.SetIPAllowlistRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	cidrs, err := ipaccess.Normalize(req.CIDRs)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid IP allowlist: " + err.Error()})
+		return
+	}
+	if orgID == c.GetInt("organizationID") {
+		if networks, _ := ipaccess.Parse(strings.Join(cidrs, ",")); !networks.Allows(c.ClientIP()) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The allowlist must include your current IP address"})
+			return
+		}
+	}
+
+	list, err := Store.SetIPAllowlist(orgID, cidrs, c.GetInt("userID"))
+	if err != nil {
+		respondIPAllowlistError(c, err)
+		return
+	}
+	cacheIPAllowlist(list)
+	c.JSON(http.StatusOK, list)
+}
+
+func respondIPAllowlistError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrOrganizationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Organization not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save IP allowlist: " + err.Error()})
+}
+
+// @Summary My organization's IP allowlist
+// @Description Addresses from which members of the current user's organization may use the API (organization admins only). An empty list means no restriction.
+// @Tags Organization
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.IPAllowlist
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/ip-allowlist [get]
+func GetMyIPAllowlist(c *gin.Context) {
+	if orgID, ok := requireOrganizationAdmin(c); ok {
+		getIPAllowlist(c, orgID)
+	}
+}
+
+// @Summary Update my organization's IP allowlist
+// @Description Replace the addresses from which members of the current user's organization may use the API (organization admins only). Entries are IP addresses or CIDR ranges (at most 100); an empty list removes the restriction. The list must include the caller's current address. Requests from other addresses get 403 and are recorded in the activity feed.
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SetIPAllowlistRequest true "Allowed addresses"
+// @Success 200 {object} models.IPAllowlist
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/ip-allowlist [put]
+func UpdateMyIPAllowlist(c *gin.Context) {
+	if orgID, ok := requireOrganizationAdmin(c); ok {
+		setIPAllowlist(c, orgID)
+	}
+}
+
+// @Summary Organization IP allowlist
+// @Description Addresses from which members of any organization may use the API (admin only); see GET /org/ip-allowlist
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Success 200 {object} models.IPAllowlist
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations/{id}/ip-allowlist [get]
+func GetOrganizationIPAllowlist(c *gin.Context) {
+	if orgID, ok := brandingOrganizationID(c); ok {
+		getIPAllowlist(c, orgID)
+	}
+}
+
+// @Summary Update organization IP allowlist
+// @Description Replace the addresses from which members of any organization may use the API (admin only); see PUT /org/ip-allowlist
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param request body models.SetIPAllowlistRequest true "Allowed addresses"
+// @Success 200 {object} models.IPAllowlist
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations/{id}/ip-allowlist [put]
+func UpdateOrganizationIPAllowlist(c *gin.Context) {
+	if orgID, ok := brandingOrganizationID(c); ok {
+		setIPAllowlist(c, orgID)
+	}
+}
@@ -0,0 +1,299 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"html/template"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/ipaccess"
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+// lockTokenTTL — сколько действует ссылка «это был не я» из письма о новом входе
+const lockTokenTTL = 7 * 24 * time.Hour
+
+var (
+	loginAlertsMu      sync.RWMutex
+	loginAlertsEnabled = true
+	publicURL          = config.Default().PublicURL
+)
+
+var (
+	countryProxiesMu sync.RWMutex
+	// countryProxies — прокси, от которых принимаются заголовки страны; пустой список — ни от каких
+	countryProxies ipaccess.List
+)
+
+// ConfigureCountryHeaders задает прокси, чьим заголовкам CF-IPCountry и X-Country-Code можно верить.
+// Запросы напрямую от клиентов могут подставить любую страну, поэтому без списка заголовки игнорируются.
+func ConfigureCountryHeaders(trustedProxies []string) error {
+	list, err := ipaccess.Parse(strings.Join(trustedProxies, ","))
+	if err != nil {
+		return err
+	}
+	countryProxiesMu.Lock()
+	defer countryProxiesMu.Unlock()
+	countryProxies = list
+	return nil
+}
+
+// ConfigureLoginAlerts включает письма о входе с нового устройства и задает адрес для ссылок в них
+func ConfigureLoginAlerts(enabled bool, baseURL string) {
+	loginAlertsMu.Lock()
+	defer loginAlertsMu.Unlock()
+	loginAlertsEnabled = enabled
+	publicURL = baseURL
+}
+
+func currentLoginAlerts() (bool, string) {
+	loginAlertsMu.RLock()
+	defer loginAlertsMu.RUnlock()
+	return loginAlertsEnabled, publicURL
+}
+
+// currentPublicURL возвращает внешний адрес API для ссылок, которые открываются вне клиента
+func currentPublicURL() string {
+	loginAlertsMu.RLock()
+	defer loginAlertsMu.RUnlock()
+	return publicURL
+}
+
+// describeDevice сводит User-Agent к семейству браузера и ОС: обновление браузера
+// не должно считаться новым устройством
+func describeDevice(userAgent string) string {
+	browser := "Unknown client"
+	for _, candidate := range []struct{ token, name string }{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"okhttp", "Android app"},
+		{"curl/", "curl"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			browser = candidate.name
+			break
+		}
+	}
+
+	os := "unknown OS"
+	for _, candidate := range []struct{ token, name string }{
+		{"Windows", "Windows"},
+		{"iPhone", "iOS"},
+		{"iPad", "iOS"},
+		{"Android", "Android"},
+		{"Mac OS X", "macOS"},
+		{"Linux", "Linux"},
+	} {
+		if strings.Contains(userAgent, candidate.token) {
+			os = candidate.name
+			break
+		}
+	}
+	return browser + " on " + os
+}
+
+// ClientCountry возвращает код страны клиента из заголовка пограничного прокси (Cloudflare и аналоги);
+// пустая строка — заголовка нет, страна неизвестна или запрос пришел не от доверенного прокси
+func ClientCountry(c *gin.Context) string {
+	countryProxiesMu.RLock()
+	proxies := countryProxies
+	countryProxiesMu.RUnlock()
+	// Пустой List.Allows разрешает любой адрес, поэтому пустой список проверяется отдельно
+	if len(proxies) == 0 || !proxies.Allows(c.RemoteIP()) {
+		return ""
+	}
+	for _, header := range []string{"CF-IPCountry", "X-Country-Code"} {
+		if country := strings.ToUpper(strings.TrimSpace(c.GetHeader(header))); len(country) == 2 && country != "XX" {
+			return country
+		}
+	}
+	return ""
+}
+
+// roughLocation возвращает страну клиента, а если она неизвестна — его подсеть (/24 для IPv4, /48 для IPv6)
+func roughLocation(c *gin.Context) string {
+	if country := ClientCountry(c); country != "" {
+		return country
+	}
+
+	ip := net.ParseIP(c.ClientIP())
+	if ip == nil {
+		return "unknown"
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return (&net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}).String()
+	}
+	return (&net.IPNet{IP: ip.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}).String()
+}
+
+// recordLogin запоминает контекст успешного входа и, если он новый, отправляет письмо
+// со ссылкой блокировки. Ошибки только логируются: вход не должен от них зависеть.
+func recordLogin(c *gin.Context, user models.User) {
+	device := describeDevice(c.Request.UserAgent())
+	location := roughLocation(c)
+	sum := sha256.Sum256([]byte(device + "|" + location))
+
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	isNew, err := Store.RecordLoginContext(models.LoginContext{
+		UserID:      user.ID,
+		Fingerprint: hex.EncodeToString(sum[:]),
+		IP:          c.ClientIP(),
+		UserAgent:   userAgent,
+		Location:    location,
+	})
+	if err != nil {
+		log.Printf("Failed to record login context for user %d: %v", user.ID, err)
+		return
+	}
+
+	enabled, baseURL := currentLoginAlerts()
+	if !isNew || !enabled || user.Email == "" {
+		return
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Printf("Failed to generate account lock token: %v", err)
+		return
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+	if err := Store.CreateAccountLockToken(user.ID, hashToken(token), time.Now().Add(lockTokenTTL)); err != nil {
+		log.Printf("Failed to save account lock token for user %d: %v", user.ID, err)
+		return
+	}
+
+	alert := mail.NewLoginAlert{
+		Time:     time.Now(),
+		IP:       c.ClientIP(),
+		Location: location,
+		Device:   device,
+		LockURL:  baseURL + "/api/account/lock?token=" + url.QueryEscape(token),
+		TimeZone: userLocation(user.Timezone),
+		Locale:   UserLocale(user.Locale),
+	}
+	go mail.SendNewLoginAlert(user.Email, alert)
+}
+
+// lockAccountPage — страница подтверждения. Блокировка выполняется только POST-запросом,
+// чтобы ее не вызвали почтовые сканеры, открывающие ссылки из писем.
+var lockAccountPage = template.Must(template.New("lock").Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>Lock LMS account</title></head>
+<body>
+{{if .Done}}<p>{{.Message}}</p>{{else}}
+<p>{{.Prompt}}</p>
+<form method="post" action="/api/account/lock">
+<input type="hidden" name="token" value="{{.Token}}">
+<button type="submit">{{.Button}}</button>
+</form>{{end}}
+</body></html>`))
+
+type lockAccountPageData struct {
+	Prompt  string
+	Button  string
+	Token   string
+	Done    bool
+	Message string
+}
+
+// @Summary Confirm account lock
+// @Description Confirmation page opened from the new sign-in email; the lock itself is a POST
+// @Tags Auth
+// @Produce html
+// @Param token query string true "Lock token from the email"
+// @Success 200 {string} string "HTML page"
+// @Router /account/lock [get]
+func LockAccountPage(c *gin.Context) {
+	c.Status(http.StatusOK)
+	c.Header("Content-Type", "text/html; charset=utf-8")
+	lockAccountPage.Execute(c.Writer, lockAccountPageData{
+		Prompt: Translate(c, "Someone signed in to your LMS account from a new device or location. "+
+			"If it wasn't you, lock the account now. An administrator will need to reactivate it."),
+		Button: Translate(c, "Lock my account"),
+		Token:  c.Query("token"),
+	})
+}
+
+// @Summary Lock account ("this wasn't me")
+// @Description Deactivates the account and revokes trusted devices using the token from a new sign-in email
+// @Tags Auth
+// @Accept json,x-www-form-urlencoded
+// @Produce json,html
+// @Param request body models.LockAccountRequest true "Lock token"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse "Invalid, used or expired token"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/lock [post]
+func LockAccount(c *gin.Context) {
+	htmlForm := c.ContentType() == "application/x-www-form-urlencoded"
+	respond := func(status int, message string) {
+		if htmlForm {
+			c.Status(status)
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			lockAccountPage.Execute(c.Writer, lockAccountPageData{Done: true, Message: Translate(c, message)})
+			return
+		}
+		if status == http.StatusOK {
+			c.JSON(status, models.SuccessResponse{Message: Translate(c, message)})
+		} else {
+			c.JSON(status, models.ErrorResponse{Error: message})
+		}
+	}
+
+	var req models.LockAccountRequest
+	if err := c.ShouldBind(&req); err != nil {
+		respond(http.StatusBadRequest, "Invalid request")
+		return
+	}
+
+	userID, err := Store.LockAccountByToken(hashToken(req.Token))
+	if errors.Is(err, storage.ErrLockTokenInvalid) {
+		respond(http.StatusBadRequest, "This link is invalid, already used or expired")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to lock account: %v", err)
+		respond(http.StatusInternalServerError, "Failed to lock the account")
+		return
+	}
+
+	forgetSessionState(userID)
+	log.Printf("Account %d locked by its owner from a new sign-in alert", userID)
+	respond(http.StatusOK, "Your account has been locked. Contact an administrator to restore access.")
+}
+
+// @Summary List known sign-in locations
+// @Description Devices and rough locations the current user has signed in from, most recent first
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.LoginContext
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/logins [get]
+func ListLoginContexts(c *gin.Context) {
+	logins, err := Store.ListLoginContexts(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list sign-ins"})
+		return
+	}
+	c.JSON(http.StatusOK, logins)
+}
@@ -0,0 +1,160 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// managerDeadlineWindow — насколько вперед панель руководителя показывает сроки
+const managerDeadlineWindow = 14 * 24 * time.Hour
+
+// teamMemberProgress дополняет подчиненного итогами по курсам и срокам на момент now
+func teamMemberProgress(member models.TeamMember, now time.Time) (models.TeamMemberProgress, error) {
+	progress := models.TeamMemberProgress{TeamMember: member}
+	courses, err := Store.GetCourseProgressSummaries(member.UserID)
+	if err != nil {
+		return progress, err
+	}
+	deadlines, err := Store.ListOutstandingDeadlines(member.UserID, now.Add(managerDeadlineWindow))
+	if err != nil {
+		return progress, err
+	}
+
+	progress.Courses, progress.Deadlines = courses, deadlines
+	progress.CoursesStarted = len(courses)
+	for _, course := range courses {
+		if course.TasksTotal > 0 && course.TasksCompleted >= course.TasksTotal {
+			progress.CoursesCompleted++
+		}
+	}
+	for i := range progress.Deadlines {
+		deadline := &progress.Deadlines[i]
+		deadline.Overdue = !deadline.DueAt.After(now)
+		if deadline.Overdue {
+			progress.OverdueTasks++
+			continue
+		}
+		progress.UpcomingDeadlines++
+		if progress.NextDueAt == nil {
+			dueAt := deadline.DueAt
+			progress.NextDueAt = &dueAt
+		}
+	}
+	return progress, nil
+}
+
+// @Summary Set a user's manager
+// @Description Makes managerId the user's manager, or removes the manager with null (admin only). A manager sees the course progress, deadlines and overdue tasks of their direct reports without any admin rights. A user cannot become their own manager through a chain of managers.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.SetManagerRequest true "Manager"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{id}/manager [put]
+func SetUserManager(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+	var req models.SetManagerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	err = Store.SetUserManager(userID, req.ManagerID)
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	case errors.Is(err, storage.ErrManagerCycle):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "A user cannot manage themselves, directly or through their reports"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to set manager: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Manager updated successfully")})
+}
+
+// @Summary My team
+// @Description The current user's direct reports with course totals, overdue tasks and deadlines in the next 14 days. Users who manage nobody get an empty list.
+// @Tags Manager
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TeamMember
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /manager/reports [get]
+func ListManagerReports(c *gin.Context) {
+	members, err := Store.ListManagerReports(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get team: " + err.Error()})
+		return
+	}
+	now := time.Now()
+	for i, member := range members {
+		progress, err := teamMemberProgress(member, now)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get team: " + err.Error()})
+			return
+		}
+		members[i] = progress.TeamMember
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// @Summary Team member progress
+// @Description Progress of a direct report in every course they started, with their overdue tasks and deadlines in the next 14 days. Only the user's manager can see it.
+// @Tags Manager
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "User ID"
+// @Success 200 {object} models.TeamMemberProgress
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse "The user does not report to the current user"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /manager/reports/{user_id} [get]
+func GetTeamMemberProgress(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+	managed, err := Store.IsManagerOf(c.GetInt("userID"), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get team: " + err.Error()})
+		return
+	}
+	if !managed {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "The user does not report to you"})
+		return
+	}
+
+	user, err := Store.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
+		return
+	}
+	progress, err := teamMemberProgress(models.TeamMember{
+		UserID: user.ID, Username: user.Username, FullName: user.FullName, Email: user.Email, IsActive: user.IsActive,
+	}, time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get team: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, progress)
+}
@@ -0,0 +1,463 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/storage"
+)
+
+const (
+	defaultMessagesLimit = 50
+	maxMessagesLimit     = 200
+	reportedMessagesMax  = 100
+)
+
+// enrolledInCourse сообщает, начал ли студент курс. Курсы платформы открыты всем, поэтому
+// записью на курс считается хотя бы одно выполненное задание.
+func enrolledInCourse(userID int, course models.Course) (bool, error) {
+	progress, err := Store.GetUserProgress(userID)
+	if err != nil {
+		return false, err
+	}
+	for _, task := range course.Tasks {
+		if progress.Completed[task.ID] {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// courseTaskRef проверяет, что упомянутое в сообщении задание относится к курсу переписки
+func courseTaskRef(c *gin.Context, course models.Course, taskID *int) bool {
+	if taskID == nil {
+		return true
+	}
+	for _, task := range course.Tasks {
+		if task.ID == *taskID {
+			return true
+		}
+	}
+	c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Task does not belong to the conversation course"})
+	return false
+}
+
+// loadCourse загружает курс переписки вместе с заданиями; курс другой организации не существует
+func loadCourse(c *gin.Context, courseID int) (models.Course, bool) {
+	course, err := StoreFor(c).GetCourseByID(courseID)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return course, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get course: " + err.Error()})
+		return course, false
+	}
+	return course, true
+}
+
+// conversationAccess загружает переписку из пути запроса. Доступ есть у ее участников,
+// а при allowAdmin — и у администраторов для модерации.
+func conversationAccess(c *gin.Context, allowAdmin bool) (conversation models.Conversation, isAdmin, ok bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid conversation ID"})
+		return conversation, false, false
+	}
+	conversation, err = Store.GetConversation(id)
+	if errors.Is(err, storage.ErrConversationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Conversation not found"})
+		return conversation, false, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get conversation: " + err.Error()})
+		return conversation, false, false
+	}
+
+	userID := c.GetInt("userID")
+	if userID == conversation.StudentID || userID == conversation.InstructorID {
+		return conversation, false, true
+	}
+	if allowAdmin {
+		isAdmin, err = RequestAdminRights(c)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+			return conversation, false, false
+		}
+		if isAdmin {
+			return conversation, true, true
+		}
+	}
+	// Чужие переписки неотличимы от несуществующих
+	c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Conversation not found"})
+	return conversation, false, false
+}
+
+// redactHiddenMessages убирает текст скрытых модератором сообщений; администраторы видят его
+func redactHiddenMessages(messages []models.ConversationMessage, isAdmin bool) {
+	if isAdmin {
+		return
+	}
+	for i := range messages {
+		if messages[i].HiddenAt != nil {
+			messages[i].Body = ""
+		}
+	}
+}
+
+// sendConversationMessage сохраняет сообщение и доставляет его собеседнику через WebSocket
+func sendConversationMessage(c *gin.Context, conversation models.Conversation, body string, taskID *int) {
+	senderID := c.GetInt("userID")
+	message, err := Store.AddConversationMessage(models.ConversationMessage{
+		ConversationID: conversation.ID,
+		SenderID:       &senderID,
+		Body:           body,
+		TaskID:         taskID,
+	})
+	if errors.Is(err, storage.ErrConversationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Conversation not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to send message: " + err.Error()})
+		return
+	}
+	// Свои сообщения отправитель прочитал
+	if err := Store.MarkConversationRead(conversation.ID, senderID, message.ID); err != nil {
+		log.Printf("Failed to mark conversation %d read: %v", conversation.ID, err)
+	}
+
+	if Realtime != nil {
+		recipient := conversation.StudentID
+		if senderID == conversation.StudentID {
+			recipient = conversation.InstructorID
+		}
+		Realtime.Broadcast(realtime.UserTopic(recipient), "message.received", message)
+	}
+	c.JSON(http.StatusCreated, message)
+}
+
+// @Summary List my conversations
+// @Description Conversations of the current user with instructors or students, most recent first, with unread counts
+// @Tags Messages
+// @Produce json
+// @Success 200 {object} models.ConversationList
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations [get]
+func ListConversations(c *gin.Context) {
+	conversations, err := Store.ListConversations(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list conversations: " + err.Error()})
+		return
+	}
+	list := models.ConversationList{Conversations: conversations}
+	for _, conversation := range conversations {
+		list.UnreadTotal += conversation.Unread
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// @Summary Message a student or instructor
+// @Description Sends a message about a course to an instructor (students) or to a student who has started the course (instructors). Reuses the existing conversation of the same participants and course.
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Param request body models.StartConversationRequest true "First message"
+// @Success 201 {object} models.ConversationMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations [post]
+func StartConversation(c *gin.Context) {
+	var req models.StartConversationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	userID := c.GetInt("userID")
+
+	recipient, err := Store.GetUserByID(req.UserID)
+	if errors.Is(err, storage.ErrUserNotFound) || (err == nil && !recipient.IsActive) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
+		return
+	}
+	course, ok := loadCourse(c, req.CourseID)
+	if !ok || !courseTaskRef(c, course, req.TaskID) {
+		return
+	}
+
+	_, senderIsInstructor, err := requestInstructorRights(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return
+	}
+	_, recipientIsInstructor, err := CheckInstructorRights(recipient.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return
+	}
+	if senderIsInstructor == recipientIsInstructor {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Messages can only be exchanged between a student and an instructor"})
+		return
+	}
+
+	studentID, instructorID := userID, recipient.ID
+	if senderIsInstructor {
+		studentID, instructorID = recipient.ID, userID
+		enrolled, err := enrolledInCourse(studentID, course)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get progress: " + err.Error()})
+			return
+		}
+		if !enrolled {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "The student has not started this course"})
+			return
+		}
+	}
+
+	conversation, err := Store.OpenConversation(course.ID, studentID, instructorID)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to open conversation: " + err.Error()})
+		return
+	}
+	sendConversationMessage(c, conversation, req.Body, req.TaskID)
+}
+
+// @Summary Conversation messages
+// @Description Messages in chronological order; pass before=<oldest message ID> to page back. Reading the latest page marks the conversation as read. Administrators can read any conversation for moderation.
+// @Tags Messages
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Param before query int false "Return messages older than this message ID"
+// @Param limit query int false "Maximum number of messages (default 50, max 200)"
+// @Success 200 {array} models.ConversationMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/{id}/messages [get]
+func ListConversationMessages(c *gin.Context) {
+	conversation, isAdmin, ok := conversationAccess(c, true)
+	if !ok {
+		return
+	}
+	before, limit := 0, defaultMessagesLimit
+	if raw := c.Query("before"); raw != "" {
+		var err error
+		if before, err = strconv.Atoi(raw); err != nil || before < 1 {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid message ID"})
+			return
+		}
+	}
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > maxMessagesLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "limit must be between 1 and 200"})
+			return
+		}
+	}
+
+	messages, err := Store.ListConversationMessages(conversation.ID, before, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list messages: " + err.Error()})
+		return
+	}
+	// Просмотр модератором не отмечает переписку прочитанной за ее участников
+	if !isAdmin && before == 0 && len(messages) > 0 {
+		if err := Store.MarkConversationRead(conversation.ID, c.GetInt("userID"), messages[len(messages)-1].ID); err != nil {
+			log.Printf("Failed to mark conversation %d read: %v", conversation.ID, err)
+		}
+	}
+	redactHiddenMessages(messages, isAdmin)
+	c.JSON(http.StatusOK, messages)
+}
+
+// @Summary Send message
+// @Description Sends a message to the other participant; taskId must reference a task of the conversation course
+// @Tags Messages
+// @Accept json
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Param request body models.SendMessageRequest true "Message"
+// @Success 201 {object} models.ConversationMessage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/{id}/messages [post]
+func SendConversationMessage(c *gin.Context) {
+	conversation, _, ok := conversationAccess(c, false)
+	if !ok {
+		return
+	}
+	var req models.SendMessageRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if req.TaskID != nil {
+		course, ok := loadCourse(c, conversation.CourseID)
+		if !ok || !courseTaskRef(c, course, req.TaskID) {
+			return
+		}
+	}
+	sendConversationMessage(c, conversation, req.Body, req.TaskID)
+}
+
+// @Summary Export conversation
+// @Description Downloads the conversation with all its messages as JSON (participants and administrators)
+// @Tags Messages
+// @Produce json
+// @Param id path int true "Conversation ID"
+// @Success 200 {object} models.ConversationExport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /conversations/{id}/export [get]
+func ExportConversation(c *gin.Context) {
+	conversation, isAdmin, ok := conversationAccess(c, true)
+	if !ok {
+		return
+	}
+	messages, err := Store.ListConversationMessages(conversation.ID, 0, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list messages: " + err.Error()})
+		return
+	}
+	redactHiddenMessages(messages, isAdmin)
+
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="lms-conversation-%d.json"`, conversation.ID))
+	c.JSON(http.StatusOK, models.ConversationExport{
+		Conversation: conversation,
+		Messages:     messages,
+		ExportedAt:   time.Now().UTC(),
+	})
+}
+
+// @Summary Report message
+// @Description Sends a message from the other participant to the administrators' moderation queue
+// @Tags Messages
+// @Produce json
+// @Param id path int true "Message ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /messages/{id}/report [post]
+func ReportMessage(c *gin.Context) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid message ID"})
+		return
+	}
+	userID := c.GetInt("userID")
+
+	message, err := Store.GetConversationMessage(messageID)
+	if err == nil {
+		var conversation models.Conversation
+		conversation, err = Store.GetConversation(message.ConversationID)
+		if err == nil && userID != conversation.StudentID && userID != conversation.InstructorID {
+			err = storage.ErrMessageNotFound
+		}
+	}
+	if errors.Is(err, storage.ErrMessageNotFound) || errors.Is(err, storage.ErrConversationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Message not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get message: " + err.Error()})
+		return
+	}
+	if message.SenderID != nil && *message.SenderID == userID {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "You cannot report your own message"})
+		return
+	}
+
+	if err := Store.ReportConversationMessage(messageID, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to report message: " + err.Error()})
+		return
+	}
+	log.Printf("User %d reported message %d", userID, messageID)
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Message reported")})
+}
+
+// @Summary Reported messages
+// @Description Moderation queue: messages reported by participants, oldest report first (admin only)
+// @Tags Admin
+// @Produce json
+// @Success 200 {array} models.ConversationMessage
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/messages/reported [get]
+func ListReportedMessages(c *gin.Context) {
+	messages, err := Store.ListReportedMessages(reportedMessagesMax)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list messages: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, messages)
+}
+
+// @Summary Moderate message
+// @Description Hides a message from participants (or shows it again) and resolves its report (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Param id path int true "Message ID"
+// @Param request body models.MessageModerationRequest true "Decision"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /admin/messages/{id}/moderation [put]
+func ModerateMessage(c *gin.Context) {
+	messageID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid message ID"})
+		return
+	}
+	var req models.MessageModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	adminID := c.GetInt("userID")
+	err = Store.ModerateConversationMessage(messageID, adminID, req.Hidden)
+	if errors.Is(err, storage.ErrMessageNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Message not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to moderate message: " + err.Error()})
+		return
+	}
+	log.Printf("Admin %d moderated message %d (hidden=%t)", adminID, messageID, req.Hidden)
+
+	if req.Hidden {
+		c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Message hidden")})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Message restored")})
+}
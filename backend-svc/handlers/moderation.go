@@ -0,0 +1,488 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+const (
+	defaultContentReports = 100
+	maxContentReports     = 500
+)
+
+// reportedContent загружает сообщение или отзыв из пути запроса и возвращает курс и автора.
+// Контент, невидимый пользователю, не существует.
+func reportedContent(c *gin.Context, contentType string) (courseID int, authorID *int, ok bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid content ID"})
+		return 0, nil, false
+	}
+
+	notFound := "Comment not found"
+	var hidden bool
+	switch contentType {
+	case models.ContentComment:
+		var comment models.DiscussionComment
+		comment, err = Store.GetComment(id)
+		if err == nil {
+			var thread models.DiscussionThread
+			thread, err = Store.GetThread(comment.ThreadID)
+			courseID, authorID, hidden = thread.CourseID, comment.AuthorID, comment.HiddenAt != nil
+		}
+	case models.ContentReview:
+		notFound = "Review not found"
+		var review models.CourseReview
+		review, err = Store.GetReview(id)
+		courseID, authorID, hidden = review.CourseID, review.UserID, review.HiddenAt != nil
+	}
+	if errors.Is(err, storage.ErrCommentNotFound) || errors.Is(err, storage.ErrReviewNotFound) || errors.Is(err, storage.ErrThreadNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: notFound})
+		return 0, nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get discussion: " + err.Error()})
+		return 0, nil, false
+	}
+
+	if !discussionCourseVisible(c, courseID, notFound) {
+		return 0, nil, false
+	}
+	viewer, ok := newDiscussionViewer(c, courseID)
+	if !ok {
+		return 0, nil, false
+	}
+	if visible, _ := viewer.sees(authorID); !visible || (hidden && contentType == models.ContentReview && !viewer.isStaff) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: notFound})
+		return 0, nil, false
+	}
+	return courseID, authorID, true
+}
+
+// reportContent принимает жалобу пользователя на сообщение или отзыв
+func reportContent(c *gin.Context, contentType string) {
+	courseID, authorID, ok := reportedContent(c, contentType)
+	if !ok {
+		return
+	}
+	var req models.ReportContentRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+			return
+		}
+	}
+	userID := c.GetInt("userID")
+	if authorID != nil && *authorID == userID {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "You cannot report your own content"})
+		return
+	}
+
+	contentID, _ := strconv.Atoi(c.Param("id"))
+	report, err := Store.ReportContent(models.ContentReport{
+		ContentType: contentType,
+		ContentID:   contentID,
+		CourseID:    courseID,
+		ReporterID:  &userID,
+		Reason:      req.Reason,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to report content: " + err.Error()})
+		return
+	}
+	log.Printf("User %d reported %s %d (report %d)", userID, contentType, contentID, report.ID)
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Content reported")})
+}
+
+// moderateContent скрывает сообщение или отзыв либо возвращает его
+func moderateContent(c *gin.Context, contentType string) {
+	moderatorID, _, ok := requireInstructor(c, "Only administrators and instructors can moderate discussions")
+	if !ok {
+		return
+	}
+	contentID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid content ID"})
+		return
+	}
+	var req models.ContentModerationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	err = Store.SetContentHidden(contentType, contentID, moderatorID, req.Hidden)
+	switch {
+	case errors.Is(err, storage.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Comment not found"})
+		return
+	case errors.Is(err, storage.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Review not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to moderate content: " + err.Error()})
+		return
+	}
+	log.Printf("Moderator %d moderated %s %d (hidden=%t)", moderatorID, contentType, contentID, req.Hidden)
+
+	if req.Hidden {
+		c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Content hidden")})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Content restored")})
+}
+
+// @Summary Report comment
+// @Description Reports a discussion comment to the moderators. Reporting the same comment again does not create a new report.
+// @Tags Discussions
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param request body models.ReportContentRequest false "Reason"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /comments/{id}/report [post]
+func ReportComment(c *gin.Context) {
+	reportContent(c, models.ContentComment)
+}
+
+// @Summary Report review
+// @Description Reports a course review to the moderators. Reporting the same review again does not create a new report.
+// @Tags Discussions
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param request body models.ReportContentRequest false "Reason"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/{id}/report [post]
+func ReportReview(c *gin.Context) {
+	reportContent(c, models.ContentReview)
+}
+
+// @Summary Moderate comment
+// @Description Hides a discussion comment (or shows it again); participants see a hidden comment without its text (administrators and instructors)
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Comment ID"
+// @Param request body models.ContentModerationRequest true "Decision"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /comments/{id}/moderation [put]
+func ModerateComment(c *gin.Context) {
+	moderateContent(c, models.ContentComment)
+}
+
+// @Summary Moderate review
+// @Description Hides a course review (or shows it again); a hidden review is visible only to its author and moderators and does not count towards the average rating (administrators and instructors)
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Review ID"
+// @Param request body models.ContentModerationRequest true "Decision"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /reviews/{id}/moderation [put]
+func ModerateReview(c *gin.Context) {
+	moderateContent(c, models.ContentReview)
+}
+
+// @Summary Lock thread
+// @Description Locks a thread so that only moderators can comment in it, or unlocks it (administrators and instructors)
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Thread ID"
+// @Param request body models.ThreadLockRequest true "Lock"
+// @Success 200 {object} models.DiscussionThread
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /threads/{id}/lock [put]
+func LockThread(c *gin.Context) {
+	moderatorID, _, ok := requireInstructor(c, "Only administrators and instructors can moderate discussions")
+	if !ok {
+		return
+	}
+	threadID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid thread ID"})
+		return
+	}
+	var req models.ThreadLockRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	thread, err := Store.SetThreadLock(threadID, moderatorID, req.Locked)
+	if errors.Is(err, storage.ErrThreadNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Thread not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to moderate content: " + err.Error()})
+		return
+	}
+	log.Printf("Moderator %d set lock of thread %d to %t", moderatorID, threadID, req.Locked)
+	c.JSON(http.StatusOK, thread)
+}
+
+// @Summary List course bans
+// @Description Users shadow-banned in the course (administrators and instructors)
+// @Tags Moderation
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {array} models.CourseBan
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/bans [get]
+func ListCourseBans(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can moderate discussions"); !ok {
+		return
+	}
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	bans, err := Store.ListCourseBans(courseID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list bans: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, bans)
+}
+
+// @Summary Shadow-ban user in course
+// @Description Shadow-bans a user in the course: their threads, comments and reviews stay visible to themselves but are hidden from other participants. Moderators see them marked as shadowed. Instructors and administrators cannot be banned.
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param user_id path int true "User ID"
+// @Param request body models.CourseBanRequest false "Reason"
+// @Success 200 {object} models.CourseBan
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/bans/{user_id} [put]
+func BanFromCourse(c *gin.Context) {
+	moderatorID, _, ok := requireInstructor(c, "Only administrators and instructors can moderate discussions")
+	if !ok {
+		return
+	}
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+	var req models.CourseBanRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+			return
+		}
+	}
+
+	_, err = Store.GetUserByID(userID)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
+		return
+	}
+	_, isStaff, err := requestInstructorRights(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return
+	}
+	if isStaff {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Instructors and administrators cannot be banned"})
+		return
+	}
+
+	ban, err := Store.SetCourseBan(models.CourseBan{CourseID: courseID, UserID: userID, BannedBy: &moderatorID, Reason: req.Reason})
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to ban user: " + err.Error()})
+		return
+	}
+	log.Printf("Moderator %d shadow-banned user %d in course %d", moderatorID, userID, courseID)
+	c.JSON(http.StatusOK, ban)
+}
+
+// @Summary Lift course ban
+// @Description Lifts a user's shadow ban in the course (administrators and instructors)
+// @Tags Moderation
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/bans/{user_id} [delete]
+func UnbanFromCourse(c *gin.Context) {
+	moderatorID, _, ok := requireInstructor(c, "Only administrators and instructors can moderate discussions")
+	if !ok {
+		return
+	}
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	err = Store.DeleteCourseBan(courseID, userID)
+	if errors.Is(err, storage.ErrCourseBanNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "The user is not banned in this course"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to lift ban: " + err.Error()})
+		return
+	}
+	log.Printf("Moderator %d lifted the ban of user %d in course %d", moderatorID, userID, courseID)
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Ban lifted")})
+}
+
+// @Summary Reported content queue
+// @Description Reports on discussion comments and reviews, oldest first (administrators and instructors). Without status, open reports are returned; status=all returns every report.
+// @Tags Moderation
+// @Produce json
+// @Param status query string false "open (default), hidden, dismissed or all"
+// @Param limit query int false "Maximum number of reports (default 100, max 500)"
+// @Success 200 {array} models.ContentReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /moderation/reports [get]
+func ListContentReports(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can moderate discussions"); !ok {
+		return
+	}
+	status := c.DefaultQuery("status", models.ReportOpen)
+	switch status {
+	case models.ReportOpen, models.ReportHidden, models.ReportDismissed:
+	case "all":
+		status = ""
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unknown report status"})
+		return
+	}
+	limit := defaultContentReports
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		limit, err = strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxContentReports {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "limit must be between 1 and 500"})
+			return
+		}
+	}
+
+	reports, err := Store.ListContentReports(status, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list reports: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, reports)
+}
+
+// @Summary Resolve report
+// @Description Resolves a report: hidden hides the reported content, dismissed rejects the report. The decision applies to all open reports on the same content and is recorded with the moderator and time (administrators and instructors).
+// @Tags Moderation
+// @Accept json
+// @Produce json
+// @Param id path int true "Report ID"
+// @Param request body models.ResolveReportRequest true "Decision"
+// @Success 200 {object} models.ContentReport
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /moderation/reports/{id} [put]
+func ResolveContentReport(c *gin.Context) {
+	moderatorID, _, ok := requireInstructor(c, "Only administrators and instructors can moderate discussions")
+	if !ok {
+		return
+	}
+	reportID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid report ID"})
+		return
+	}
+	var req models.ResolveReportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	report, err := Store.ResolveContentReport(reportID, moderatorID, req.Resolution, req.Note)
+	switch {
+	case errors.Is(err, storage.ErrReportNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Report not found"})
+		return
+	case errors.Is(err, storage.ErrReportResolved):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "The report has already been resolved"})
+		return
+	case errors.Is(err, storage.ErrCommentNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Comment not found"})
+		return
+	case errors.Is(err, storage.ErrReviewNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Review not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to resolve report: " + err.Error()})
+		return
+	}
+	log.Printf("Moderator %d resolved report %d as %s", moderatorID, reportID, req.Resolution)
+	c.JSON(http.StatusOK, report)
+}
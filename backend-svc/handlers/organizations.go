@@ -0,0 +1,438 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"strconv"
+
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// StoreFor возвращает хранилище, ограниченное организацией текущего пользователя: курсы,
+// задания, группы и профили других организаций через него не видны
+func StoreFor(c *gin.Context) storage.Storage {
+	return storage.InOrganization(Store, c.GetInt("organizationID"))
+}
+
+// requireOrganizationAdmin проверяет, что текущий пользователь администрирует свою организацию;
+// администраторы платформы администрируют любую
+func requireOrganizationAdmin(c *gin.Context) (orgID int, ok bool) {
+	userID, orgID := c.GetInt("userID"), c.GetInt("organizationID")
+	isAdmin, err := RequestAdminRights(c)
+	if err == nil && !isAdmin {
+		isAdmin, err = Store.IsOrganizationAdmin(orgID, userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return 0, false
+	}
+	if !isAdmin {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Organization admin rights required"})
+		return 0, false
+	}
+	return orgID, true
+}
+
+// organizationFromRequest проверяет запрос на создание или изменение организации
+func organizationFromRequest(c *gin.Context) (models.Organization, bool) {
+	var req models.SaveOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return models.Organization{}, false
+	}
+	if !organizationSlugPattern.MatchString(req.Slug) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Slug may contain only lowercase letters, digits and hyphens"})
+		return models.Organization{}, false
+	}
+	org := models.Organization{Slug: req.Slug, Name: req.Name, IsActive: true}
+	if req.IsActive != nil {
+		org.IsActive = *req.IsActive
+	}
+	return org, true
+}
+
+// respondOrganizationError отвечает на ошибку сохранения организации
+func respondOrganizationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Organization not found"})
+	case errors.Is(err, storage.ErrOrganizationExists):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "An organization with this slug already exists"})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save organization: " + err.Error()})
+	}
+}
+
+// @Summary List organizations
+// @Description All organizations with their user and course counts (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Organization
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations [get]
+func ListOrganizations(c *gin.Context) {
+	orgs, err := Store.ListOrganizations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get organizations: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, orgs)
+}
+
+// @Summary Create an organization
+// @Description Create a tenant organization (admin only). Users, courses and groups of one organization are invisible to the others.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SaveOrganizationRequest true "Organization"
+// @Success 201 {object} models.Organization
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations [post]
+func CreateOrganization(c *gin.Context) {
+	org, ok := organizationFromRequest(c)
+	if !ok {
+		return
+	}
+	org, err := Store.CreateOrganization(org)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	c.JSON(http.StatusCreated, org)
+}
+
+// @Summary Update an organization
+// @Description Rename an organization or disable it (admin only). Members of a disabled organization are rejected on every request.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param request body models.SaveOrganizationRequest true "Organization"
+// @Success 200 {object} models.Organization
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations/{id} [put]
+func UpdateOrganization(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid organization ID"})
+		return
+	}
+	org, ok := organizationFromRequest(c)
+	if !ok {
+		return
+	}
+	if id == models.DefaultOrganizationID && !org.IsActive {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The default organization cannot be disabled"})
+		return
+	}
+	org.ID = id
+	org, err = Store.UpdateOrganization(org)
+	if err != nil {
+		respondOrganizationError(c, err)
+		return
+	}
+	// Активность организации хранится в кешированном состоянии сессий ее участников
+	forgetAllSessionStates()
+	c.JSON(http.StatusOK, org)
+}
+
+// @Summary Move a user to an organization
+// @Description Move a user to another organization (admin only). Group memberships, organization admin rights and manager links that would cross organizations are removed.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.SetOrganizationRequest true "Organization"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{id}/organization [put]
+func SetUserOrganization(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+	var req models.SetOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	err = Store.SetUserOrganization(userID, req.OrganizationID)
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	case errors.Is(err, storage.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Organization not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to move user: " + err.Error()})
+		return
+	}
+	forgetSessionState(userID)
+	// Перенос исключает пользователя из групп прежней организации
+	forgetRoles(userID)
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "User moved to the organization")})
+}
+
+// @Summary Move a course to an organization
+// @Description Move a course with its tasks to another organization (admin only). Learners of other organizations keep their progress but no longer see the course.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param request body models.SetOrganizationRequest true "Organization"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/courses/{id}/organization [put]
+func SetCourseOrganization(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	var req models.SetOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	err = Store.SetCourseOrganization(courseID, req.OrganizationID)
+	switch {
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	case errors.Is(err, storage.ErrOrganizationNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Organization not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to move course: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Course moved to the organization")})
+}
+
+// setOrganizationAdmin выдает или отзывает права администратора организации orgID
+func setOrganizationAdmin(c *gin.Context, orgID int, isAdmin bool) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+	err = Store.SetOrganizationAdmin(orgID, userID, isAdmin)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User is not a member of the organization"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update organization admins: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Organization admins updated")})
+}
+
+// adminOrganizationID разбирает ID организации из пути и проверяет, что она существует
+func adminOrganizationID(c *gin.Context) (int, bool) {
+	orgID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid organization ID"})
+		return 0, false
+	}
+	if _, err := Store.GetOrganization(orgID); err != nil {
+		respondOrganizationError(c, err)
+		return 0, false
+	}
+	return orgID, true
+}
+
+// @Summary Grant organization admin rights
+// @Description Make a member of the organization its administrator (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations/{id}/admins/{user_id} [put]
+func GrantOrganizationAdmin(c *gin.Context) {
+	if orgID, ok := adminOrganizationID(c); ok {
+		setOrganizationAdmin(c, orgID, true)
+	}
+}
+
+// @Summary Revoke organization admin rights
+// @Description Revoke a user's administrator rights in the organization (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Organization ID"
+// @Param user_id path int true "User ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/organizations/{id}/admins/{user_id} [delete]
+func RevokeOrganizationAdmin(c *gin.Context) {
+	if orgID, ok := adminOrganizationID(c); ok {
+		setOrganizationAdmin(c, orgID, false)
+	}
+}
+
+// @Summary My organization
+// @Description The organization the current user belongs to
+// @Tags Organization
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.Organization
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org [get]
+func GetMyOrganization(c *gin.Context) {
+	org, err := Store.GetOrganization(c.GetInt("organizationID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get organization: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, org)
+}
+
+// @Summary Organization members
+// @Description Users of the current user's organization (organization admins only)
+// @Tags Organization
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.OrganizationMember
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/members [get]
+func ListOrganizationMembers(c *gin.Context) {
+	orgID, ok := requireOrganizationAdmin(c)
+	if !ok {
+		return
+	}
+	members, err := Store.ListOrganizationMembers(orgID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get organization members: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, members)
+}
+
+// @Summary Enable or disable an organization member
+// @Description Activate or deactivate a user of the current user's organization (organization admins only)
+// @Tags Organization
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "User ID"
+// @Param request body models.UpdateStatusRequest true "Status"
+// @Param If-Match header string false "User ETag; required unless version is passed in the body"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 428 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/members/{user_id}/status [put]
+func UpdateOrganizationMemberStatus(c *gin.Context) {
+	orgID, ok := requireOrganizationAdmin(c)
+	if !ok {
+		return
+	}
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+	var req models.UpdateStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	member, err := Store.IsOrganizationMember(orgID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get organization members: " + err.Error()})
+		return
+	}
+	if !member {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User is not a member of the organization"})
+		return
+	}
+
+	version, ok := requireUserVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	err = Store.UpdateUserStatus(userID, req.IsActive, version)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "User was changed concurrently, reload it and retry"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update user status: " + err.Error()})
+		return
+	}
+	forgetSessionState(userID)
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "User status updated successfully")})
+}
+
+// @Summary Grant organization admin rights in my organization
+// @Description Make another member of the current user's organization its administrator (organization admins only)
+// @Tags Organization
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "User ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/admins/{user_id} [put]
+func GrantMyOrganizationAdmin(c *gin.Context) {
+	if orgID, ok := requireOrganizationAdmin(c); ok {
+		setOrganizationAdmin(c, orgID, true)
+	}
+}
+
+// @Summary Revoke organization admin rights in my organization
+// @Description Revoke a member's administrator rights in the current user's organization (organization admins only)
+// @Tags Organization
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "User ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /org/admins/{user_id} [delete]
+func RevokeMyOrganizationAdmin(c *gin.Context) {
+	if orgID, ok := requireOrganizationAdmin(c); ok {
+		setOrganizationAdmin(c, orgID, false)
+	}
+}
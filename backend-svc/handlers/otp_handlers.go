@@ -0,0 +1,172 @@
+package handlers
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/otp"
+)
+
+// otpDeliveryHistoryLimit — сколько последних попыток доставки показывается пользователю
+const otpDeliveryHistoryLimit = 20
+
+var (
+	otpMu        sync.RWMutex
+	otpProviders = otp.Providers{models.OTPChannelEmail: otp.EmailProvider{}}
+	otpSettings  = config.Default().OTP
+
+	// e164Phone — номер телефона в международном формате, например +79991234567
+	e164Phone = regexp.MustCompile(`^\+[1-9][0-9]{6,14}$`)
+)
+
+// ConfigureOTP задает каналы доставки одноразовых кодов и лимит попыток из конфигурации.
+// Безопасно вызывать повторно во время работы при ротации секретов.
+func ConfigureOTP(cfg config.OTPConfig) {
+	providers := otp.Providers{models.OTPChannelEmail: otp.EmailProvider{}}
+	if cfg.SMS.AccountSID != "" {
+		providers[models.OTPChannelSMS] = otp.NewSMSProvider(cfg.SMS.BaseURL, cfg.SMS.AccountSID, cfg.SMS.AuthToken, cfg.SMS.From)
+	}
+
+	otpMu.Lock()
+	defer otpMu.Unlock()
+	otpProviders = providers
+	otpSettings = cfg
+}
+
+func currentOTPProviders() otp.Providers {
+	otpMu.RLock()
+	defer otpMu.RUnlock()
+	return otpProviders
+}
+
+func currentOTPSettings() config.OTPConfig {
+	otpMu.RLock()
+	defer otpMu.RUnlock()
+	return otpSettings
+}
+
+// issueOTP генерирует код в формате из конфигурации, сохраняет его и отправляет пользователю.
+// Возвращает канал, по которому ушел код.
+func issueOTP(ctx context.Context, user models.User) (string, error) {
+	settings := currentOTPSettings()
+	code, err := otp.Generate(settings.Length, settings.Charset)
+	if err != nil {
+		return "", err
+	}
+	if err := Store.SaveOTPCode(user.ID, code, settings.TTL); err != nil {
+		return "", err
+	}
+	return deliverOTP(ctx, user, code, settings.TTL), nil
+}
+
+// deliverOTP отправляет код по каналу, выбранному пользователем, и сохраняет статус доставки.
+// Если канал недоступен или у пользователя нет телефона, код уходит на email.
+func deliverOTP(ctx context.Context, user models.User, code string, ttl time.Duration) string {
+	pref, err := Store.GetOTPPreference(user.ID)
+	if err != nil {
+		log.Printf("Failed to load OTP preference for user %d: %v", user.ID, err)
+		pref = models.OTPPreference{Channel: models.OTPChannelEmail}
+	}
+	if pref.Channel == models.OTPChannelSMS && pref.Phone == "" {
+		pref.Channel = models.OTPChannelEmail
+	}
+
+	provider := currentOTPProviders().For(pref.Channel)
+	ctx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	delivery := models.OTPDelivery{UserID: user.ID, Channel: provider.Channel(), Status: models.OTPDeliverySent}
+	if err := provider.Deliver(ctx, otp.Recipient{UserID: user.ID, Email: user.Email, Phone: pref.Phone, Locale: UserLocale(user.Locale)}, code, ttl); err != nil {
+		log.Printf("OTP delivery via %s failed for user %d: %v", provider.Channel(), user.ID, err)
+		delivery.Status = models.OTPDeliveryFailed
+		delivery.Error = err.Error()
+	}
+	if err := Store.RecordOTPDelivery(delivery); err != nil {
+		log.Printf("Failed to record OTP delivery for user %d: %v", user.ID, err)
+	}
+	return provider.Channel()
+}
+
+// @Summary Get OTP delivery channel
+// @Description Get the channel used to deliver one-time login codes to the current user
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.OTPPreference
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/otp/channel [get]
+func GetOTPPreference(c *gin.Context) {
+	pref, err := Store.GetOTPPreference(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get OTP channel"})
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// @Summary Set OTP delivery channel
+// @Description Choose email or SMS for one-time login codes; SMS requires an E.164 phone number
+// @Tags Auth
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateOTPPreferenceRequest true "Channel preference"
+// @Success 200 {object} models.OTPPreference
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/otp/channel [put]
+func UpdateOTPPreference(c *gin.Context) {
+	var req models.UpdateOTPPreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request"})
+		return
+	}
+
+	if req.Channel == models.OTPChannelSMS {
+		if _, ok := currentOTPProviders()[models.OTPChannelSMS]; !ok {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "SMS delivery is not available"})
+			return
+		}
+		if !e164Phone.MatchString(req.Phone) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Phone must be in E.164 format, e.g. +79991234567"})
+			return
+		}
+	} else if req.Phone != "" && !e164Phone.MatchString(req.Phone) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Phone must be in E.164 format, e.g. +79991234567"})
+		return
+	}
+
+	pref := models.OTPPreference{UserID: c.GetInt("userID"), Channel: req.Channel, Phone: req.Phone}
+	if err := Store.SetOTPPreference(pref); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update OTP channel"})
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// @Summary List OTP deliveries
+// @Description Recent delivery attempts of one-time codes to the current user, newest first
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.OTPDelivery
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/otp/deliveries [get]
+func ListOTPDeliveries(c *gin.Context) {
+	deliveries, err := Store.ListOTPDeliveries(c.GetInt("userID"), otpDeliveryHistoryLimit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list OTP deliveries"})
+		return
+	}
+	c.JSON(http.StatusOK, deliveries)
+}
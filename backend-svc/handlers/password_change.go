@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+// PasswordChangeRequired сообщает, должен ли пользователь сменить пароль перед остальными запросами
+func PasswordChangeRequired(userID int) (bool, error) {
+	state, err := loadSessionState(userID)
+	if err != nil {
+		return false, err
+	}
+	return state.MustChangePassword, nil
+}
+
+// @Summary Force a password reset
+// @Description Require the user to change their password (admin only). Until they do, every endpoint except GET/PUT /profile answers 403 with passwordChangeRequired. sendEmail also notifies the user by email.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.ForcePasswordResetRequest false "Notification options"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{id}/force-password-reset [post]
+func ForcePasswordReset(c *gin.Context) {
+	targetUserID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	var req models.ForcePasswordResetRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+			return
+		}
+	}
+
+	err = Store.SetMustChangePassword(targetUserID, true)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to require password change: " + err.Error()})
+		return
+	}
+	forgetSessionState(targetUserID)
+
+	if !req.SendEmail {
+		c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Password change required")})
+		return
+	}
+
+	user, err := Store.GetUserByID(targetUserID)
+	if err != nil {
+		log.Printf("Failed to load user %d for password change notice: %v", targetUserID, err)
+	}
+	if err != nil || user.Email == "" {
+		c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Password change required; notification email was not sent")})
+		return
+	}
+
+	_, baseURL := currentLoginAlerts()
+	go func(email string) {
+		if err := mail.SendPasswordChangeRequired(email, baseURL, UserLocale(user.Locale)); err != nil {
+			log.Printf("Failed to send password change notice to user %d: %v", targetUserID, err)
+		}
+	}(user.Email)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Password change required; notification email sent")})
+}
@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/i18n"
+	"time"
+)
+
+// UserLocale возвращает локаль пользователя или локаль по умолчанию
+func UserLocale(locale string) string {
+	if locale == "" {
+		return currentAccountsConfig().DefaultLocale
+	}
+	return locale
+}
+
+// UserTimezone возвращает часовой пояс пользователя или пояс по умолчанию
+func UserTimezone(timezone string) string {
+	if timezone == "" {
+		return currentAccountsConfig().DefaultTimezone
+	}
+	return timezone
+}
+
+// userLocation возвращает часовой пояс для дат в письмах и документах пользователя.
+// Пояс проверяется при сохранении, поэтому ошибка возможна только при устаревшей базе tzdata.
+func userLocation(timezone string) *time.Location {
+	loc, err := time.LoadLocation(UserTimezone(timezone))
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// validLocale сообщает, поддерживается ли локаль
+func validLocale(locale string) bool {
+	for _, supported := range currentAccountsConfig().SupportedLocales {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// validTimezone сообщает, является ли строка именем часового пояса IANA
+func validTimezone(timezone string) bool {
+	// LoadLocation принимает и "Local", который зависит от сервера, а не от пользователя
+	if timezone == "Local" {
+		return false
+	}
+	_, err := time.LoadLocation(timezone)
+	return err == nil
+}
+
+// RequestLocale возвращает язык ответа на запрос: язык из профиля пользователя,
+// иначе лучший поддерживаемый язык из Accept-Language, иначе язык по умолчанию.
+// Результат запоминается в контексте запроса.
+func RequestLocale(c *gin.Context) string {
+	if locale := c.GetString("locale"); locale != "" {
+		return locale
+	}
+
+	locale := ""
+	if userID := c.GetInt("userID"); userID != 0 {
+		if user, err := Store.GetUserByID(userID); err == nil {
+			locale = user.Locale
+		}
+	}
+	if locale == "" {
+		locale = i18n.Match(c.GetHeader("Accept-Language"), currentAccountsConfig().SupportedLocales)
+	}
+	locale = UserLocale(locale)
+	c.Set("locale", locale)
+	return locale
+}
+
+// Translate переводит сообщение ответа на язык запроса и указывает язык в Content-Language
+func Translate(c *gin.Context, message string) string {
+	locale := RequestLocale(c)
+	c.Header("Content-Language", locale)
+	return i18n.Translate(locale, message)
+}
+
+// Translatef переводит формат сообщения ответа и подставляет в него аргументы
+func Translatef(c *gin.Context, format string, args ...interface{}) string {
+	return fmt.Sprintf(Translate(c, format), args...)
+}
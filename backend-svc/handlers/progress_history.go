@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lmsmodule/backend-svc/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// progressHistoryMaxDays — наибольший период истории прогресса
+const progressHistoryMaxDays = 730
+
+// SnapshotProgress сохраняет сегодняшний прогресс пользователей по курсам. Задача запускается
+// чаще раза в день, поэтому снимок дня отражает последнее состояние.
+func SnapshotProgress() error {
+	snapshots, err := Store.SnapshotProgress(time.Now())
+	if err != nil {
+		return err
+	}
+	log.Printf("Saved %d progress snapshots", snapshots)
+	return nil
+}
+
+// @Summary Progress over time
+// @Description Daily snapshots of the user's completion percentage per course, oldest first, for progress charts. A day without a snapshot means the progress did not change or the user had not started the course yet; after a reset the history drops to zero. Users see their own history; administrators and instructors see anyone's.
+// @Tags Progress
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "User ID"
+// @Param courseId query int false "Only this course"
+// @Param days query int false "Period length in days including today (1-730)" default(90)
+// @Success 200 {array} models.CourseProgressHistory
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /progress/{user_id}/history [get]
+func GetProgressHistory(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+	if userID != c.GetInt("userID") {
+		if _, _, ok := requireInstructor(c, "Access denied"); !ok {
+			return
+		}
+	}
+	courseID := 0
+	if raw := c.Query("courseId"); raw != "" {
+		if courseID, err = strconv.Atoi(raw); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+			return
+		}
+	}
+	days, err := strconv.Atoi(c.DefaultQuery("days", "90"))
+	if err != nil || days < 1 || days > progressHistoryMaxDays {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid days"})
+		return
+	}
+
+	from := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1-days)
+	history, err := Store.GetProgressHistory(userID, courseID, from)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get progress history: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
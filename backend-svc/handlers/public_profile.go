@@ -0,0 +1,235 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+// buildProgressSummary считает значки, сводку по курсам и матрицу навыков по выполненным заданиям.
+// Навык — тип уязвимости; несколько курсов одного типа складываются в одну строку матрицы.
+func buildProgressSummary(courses []models.Course, completions []models.TaskCompletion) ([]models.ProfileBadge, models.ProfileCourseStats, []models.SkillLevel) {
+	type courseProgress struct {
+		completed int
+		lastAt    time.Time
+	}
+	byCourse := map[int]*courseProgress{}
+	for _, completion := range completions {
+		progress, ok := byCourse[completion.CourseID]
+		if !ok {
+			progress = &courseProgress{}
+			byCourse[completion.CourseID] = progress
+		}
+		progress.completed++
+		if completion.CompletedAt.After(progress.lastAt) {
+			progress.lastAt = completion.CompletedAt
+		}
+	}
+
+	badges := []models.ProfileBadge{}
+	stats := models.ProfileCourseStats{CompletedTasks: len(completions)}
+	skills := map[string]*models.SkillLevel{}
+	for _, course := range courses {
+		skill, ok := skills[course.VulnerabilityType]
+		if !ok {
+			skill = &models.SkillLevel{Skill: course.VulnerabilityType}
+			skills[course.VulnerabilityType] = skill
+		}
+		skill.TotalTasks += course.TasksCount
+
+		progress, ok := byCourse[course.ID]
+		if !ok {
+			continue
+		}
+		skill.CompletedTasks += progress.completed
+		stats.StartedCourses++
+		if course.TasksCount > 0 && progress.completed >= course.TasksCount {
+			stats.CompletedCourses++
+			badge := models.ProfileBadge{CourseID: course.ID, Name: course.VulnerabilityType}
+			if !progress.lastAt.IsZero() {
+				earnedAt := progress.lastAt
+				badge.EarnedAt = &earnedAt
+			}
+			badges = append(badges, badge)
+		}
+	}
+
+	matrix := make([]models.SkillLevel, 0, len(skills))
+	for _, skill := range skills {
+		if skill.TotalTasks > 0 {
+			skill.Level = min(100, skill.CompletedTasks*100/skill.TotalTasks)
+		}
+		matrix = append(matrix, *skill)
+	}
+	sort.Slice(matrix, func(i, j int) bool { return matrix[i].Skill < matrix[j].Skill })
+	return badges, stats, matrix
+}
+
+// @Summary Get a public profile
+// @Description Profile of a student as other students see it: display name, avatar, badges for completed courses, course counts and the skill matrix. Fields the owner hid in the privacy settings are null; a hidden profile is reported as not found. The owner and admins see every field. A previous username redirects to the current one.
+// @Tags Users
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "Username"
+// @Success 200 {object} models.PublicProfile
+// @Success 302 "Redirect to the current username"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /users/{username} [get]
+func GetPublicProfile(c *gin.Context) {
+	username := c.Param("username")
+	userID, err := Store.ResolveUsername(username)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Profile not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to look up user: " + err.Error()})
+		return
+	}
+
+	user, err := StoreFor(c).GetUserPublicByID(userID)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Profile not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
+		return
+	}
+	if user.Username != username {
+		c.Redirect(http.StatusFound, "/api/users/"+url.PathEscape(user.Username))
+		return
+	}
+
+	privacy, err := Store.GetProfilePrivacy(user.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get privacy settings: " + err.Error()})
+		return
+	}
+
+	viewerID := c.GetInt("userID")
+	fullAccess := viewerID == user.ID
+	if !fullAccess {
+		if fullAccess, err = RequestAdminRights(c); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+			return
+		}
+	}
+	// Скрытый профиль неотличим от несуществующего, как и деактивированная учетная запись
+	if !fullAccess && (!privacy.ProfileVisible || !user.IsActive) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Profile not found"})
+		return
+	}
+	show := func(allowed bool) bool { return fullAccess || allowed }
+
+	profile := models.PublicProfile{ID: user.ID, Username: user.Username, DisplayName: user.Username}
+	if show(privacy.ShowFullName) && user.FullName != "" {
+		profile.DisplayName = user.FullName
+	}
+	if show(privacy.ShowAvatar) {
+		profile.Avatar = AvatarURLs(user.AvatarKey)
+	}
+	if fullAccess {
+		profile.Privacy = &privacy
+	}
+
+	if show(privacy.ShowBadges) || show(privacy.ShowCourseStats) || show(privacy.ShowSkills) {
+		courses, err := StoreFor(c).GetCourses()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get courses: " + err.Error()})
+			return
+		}
+		completions, err := Store.GetTaskCompletions(user.ID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get progress: " + err.Error()})
+			return
+		}
+
+		badges, stats, skills := buildProgressSummary(courses, completions)
+		if show(privacy.ShowBadges) {
+			profile.Badges = badges
+		}
+		if show(privacy.ShowCourseStats) {
+			profile.CourseStats = &stats
+		}
+		if show(privacy.ShowSkills) {
+			profile.Skills = skills
+		}
+	}
+
+	c.JSON(http.StatusOK, profile)
+}
+
+// @Summary Get my profile privacy settings
+// @Tags Account
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.ProfilePrivacy
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/privacy [get]
+func GetProfilePrivacy(c *gin.Context) {
+	privacy, err := Store.GetProfilePrivacy(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get privacy settings: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, privacy)
+}
+
+// @Summary Update my profile privacy settings
+// @Description Choose which public profile fields other students can see. Omitted fields keep their current value.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.UpdateProfilePrivacyRequest true "Privacy settings"
+// @Success 200 {object} models.ProfilePrivacy
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/privacy [put]
+func UpdateProfilePrivacy(c *gin.Context) {
+	var req models.UpdateProfilePrivacyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	privacy, err := Store.GetProfilePrivacy(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get privacy settings: " + err.Error()})
+		return
+	}
+
+	for _, field := range []struct {
+		value  *bool
+		target *bool
+	}{
+		{req.ProfileVisible, &privacy.ProfileVisible},
+		{req.ShowFullName, &privacy.ShowFullName},
+		{req.ShowAvatar, &privacy.ShowAvatar},
+		{req.ShowBadges, &privacy.ShowBadges},
+		{req.ShowCourseStats, &privacy.ShowCourseStats},
+		{req.ShowSkills, &privacy.ShowSkills},
+	} {
+		if field.value != nil {
+			*field.target = *field.value
+		}
+	}
+
+	if err := Store.SetProfilePrivacy(userID, privacy); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update privacy settings: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, privacy)
+}
@@ -0,0 +1,57 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/quizimport"
+)
+
+// maxQuestionFileBytes ограничивает размер импортируемого банка вопросов
+const maxQuestionFileBytes = 10 << 20
+
+// @Summary Parse a GIFT or Moodle XML question bank
+// @Description Parse quiz questions exported from Moodle in GIFT or Moodle XML format (instructors and admins) and return them in a source-independent form together with a validation report: each malformed item is listed with its position and the reason and does not prevent the rest from being parsed. Supported question types: multiple choice, true/false, short answer, numerical, matching, essay and description; categories are kept. The file is uploaded as the multipart field "file" or as the raw request body; the format is detected from the content unless given. Nothing is stored: the result is meant to be reviewed and then saved into a question bank.
+// @Tags Questions
+// @Accept multipart/form-data,text/plain,application/xml
+// @Produce json
+// @Security BearerAuth
+// @Param file formData file false "GIFT or Moodle XML file"
+// @Param format query string false "File format" Enums(gift, xml)
+// @Success 200 {object} models.QuestionImportResult
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Router /questions/import [post]
+func ImportQuestions(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can import questions"); !ok {
+		return
+	}
+	format := c.Query("format")
+	if format != "" && format != quizimport.FormatGIFT && format != quizimport.FormatXML {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "format must be gift or xml"})
+		return
+	}
+
+	data, err := readUpload(c, "file", maxQuestionFileBytes)
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) || len(data) > maxQuestionFileBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: fmt.Sprintf("Question file must not exceed %d bytes", maxQuestionFileBytes)})
+		return
+	}
+	if err != nil || len(data) == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Upload a question file in the \"file\" form field or as the request body"})
+		return
+	}
+
+	result, err := quizimport.Parse(format, data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "format must be gift or xml"})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
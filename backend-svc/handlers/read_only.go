@@ -0,0 +1,20 @@
+package handlers
+
+import (
+	"sync/atomic"
+
+	"lmsmodule/backend-svc/settings"
+)
+
+// readOnlyForced — режим только для чтения включен конфигурацией (database.read_only)
+var readOnlyForced atomic.Bool
+
+// ForceReadOnly включает режим только для чтения независимо от runtime-параметра
+func ForceReadOnly(forced bool) {
+	readOnlyForced.Store(forced)
+}
+
+// ReadOnlyMode сообщает, отклоняются ли запросы, изменяющие данные
+func ReadOnlyMode() bool {
+	return readOnlyForced.Load() || Settings.Bool(settings.KeyReadOnlyMode)
+}
@@ -0,0 +1,108 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"golang.org/x/net/websocket"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/realtime"
+	"net/http"
+	"strings"
+)
+
+// webSocketOriginAllowed сообщает, можно ли открыть WebSocket со страницы источника origin
+var webSocketOriginAllowed = func(origin string) bool { return false }
+
+// ConfigureWebSocketOrigins задает проверку источника WebSocket-соединений; браузер не применяет
+// к ним CORS, поэтому сервис проверяет Origin по тому же списку сам
+func ConfigureWebSocketOrigins(allowed func(origin string) bool) {
+	webSocketOriginAllowed = allowed
+}
+
+// @Summary Real-time WebSocket channel
+// @Description Upgrades to WebSocket. The client is subscribed to its personal topic and may send {"action":"subscribe"|"unsubscribe","topic":"broadcast"|"leaderboard"|"user.<id>"}. Browsers may pass the token as ?access_token=.
+// @Tags User
+// @Security BearerAuth
+// @Param access_token query string false "JWT for clients that cannot set headers"
+// @Success 101 "Switching Protocols"
+// @Failure 403 "Origin is not in the CORS allowlist"
+// @Failure 503 {object} models.ErrorResponse
+// @Router /ws [get]
+func WebSocketHandler(c *gin.Context) {
+	if Realtime == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Real-time channel is not available"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	isAdmin, err := RequestAdminRights(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return
+	}
+
+	server := websocket.Server{
+		// Без Origin подключается не браузер, и чужая страница не может открыть соединение от его имени
+		Handshake: func(_ *websocket.Config, r *http.Request) error {
+			if origin := r.Header.Get("Origin"); origin != "" && !webSocketOriginAllowed(origin) {
+				return errors.New("origin not allowed")
+			}
+			return nil
+		},
+		Handler: func(conn *websocket.Conn) {
+			Realtime.Serve(conn, userID, isAdmin)
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
+
+// @Summary Broadcast real-time message
+// @Description Sends a message to every client subscribed to the broadcast topic
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.BroadcastRequest true "Message"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /admin/broadcast [post]
+func BroadcastMessage(c *gin.Context) {
+	if Realtime == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Real-time channel is not available"})
+		return
+	}
+
+	var req models.BroadcastRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	req.Message = strings.TrimSpace(req.Message)
+	if req.Message == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Message must not be empty"})
+		return
+	}
+
+	Realtime.Broadcast(realtime.TopicBroadcast, "announcement", gin.H{
+		"message": req.Message,
+		"from":    c.GetInt("userID"),
+	})
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Message broadcast")})
+}
+
+// @Summary Online users
+// @Description Lists users connected to the WebSocket channel on any replica
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PresenceResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /admin/presence [get]
+func GetPresence(c *gin.Context) {
+	if Presence == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Real-time channel is not available"})
+		return
+	}
+	c.JSON(http.StatusOK, models.PresenceResponse{Users: Presence.OnlineUsers(), Instances: Presence.Instances()})
+}
@@ -0,0 +1,318 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// recertificationCoursesPath — страница курсов, на которую ведут письма о повторном прохождении
+const recertificationCoursesPath = "/my_courses"
+
+// defaultRecertificationReminderDays — за сколько дней до истечения напоминать по умолчанию
+const defaultRecertificationReminderDays = 30
+
+// recordCourseCompletions начинает новый цикл сертификации по курсам с повторным прохождением,
+// которые пользователь завершил заданиями taskIDs
+func recordCourseCompletions(userID int, taskIDs []int) {
+	if len(taskIDs) == 0 {
+		return
+	}
+	courseIDs, err := completedTaskCourses(userID, taskIDs)
+	if err != nil {
+		log.Printf("Failed to check recertification of user %d: %v", userID, err)
+		return
+	}
+	for courseID := range courseIDs {
+		course, err := Store.GetCourseByID(courseID)
+		if err != nil {
+			log.Printf("Failed to check recertification of course %d: %v", courseID, err)
+			continue
+		}
+		if completed, err := courseCompleted(userID, course); err != nil || !completed {
+			if err != nil {
+				log.Printf("Failed to check recertification of course %d: %v", courseID, err)
+			}
+			continue
+		}
+		if _, err := Store.RecordCourseCompletion(userID, courseID, time.Now()); err != nil {
+			log.Printf("Failed to record completion of course %d by user %d: %v", courseID, userID, err)
+		}
+	}
+}
+
+// complianceStatus возвращает состояние сертификации на момент now
+func complianceStatus(entry models.ComplianceEntry, now time.Time) string {
+	switch {
+	case entry.CompletedAt == nil:
+		return models.RecertificationNotCompleted
+	case entry.ExpiredAt != nil || !entry.ExpiresAt.After(now):
+		return models.RecertificationDue
+	case entry.RemindAt != nil && !entry.RemindAt.After(now):
+		return models.RecertificationExpiring
+	default:
+		return models.RecertificationCompliant
+	}
+}
+
+// recertificationNotice — письмо о сроке прохождения курса
+type recertificationNotice func(user models.User, course models.Course, completion models.CourseCompletion) error
+
+// notifyRecertification отправляет письмо владельцу цикла на его языке
+func notifyRecertification(completion models.CourseCompletion, send recertificationNotice) error {
+	user, err := Store.GetUserByID(completion.UserID)
+	if err != nil {
+		return err
+	}
+	if user.Email == "" || !user.IsActive {
+		return nil
+	}
+	course, err := Store.GetCourseByID(completion.CourseID)
+	if err != nil {
+		return err
+	}
+	if err := LocalizeCourse(UserLocale(user.Locale), &course); err != nil {
+		return err
+	}
+	return send(user, course, completion)
+}
+
+// ProcessRecertifications напоминает о скором истечении сертификации и сбрасывает прогресс курсов,
+// срок прохождения которых истек. Ошибка письма не мешает сбросу: пользователь увидит курс в
+// списке заданий к выполнению, а напоминание отмечается, только если письмо ушло.
+func ProcessRecertifications() error {
+	now := time.Now()
+	coursesURL := currentPublicURL() + recertificationCoursesPath
+
+	reminders, err := Store.ListRecertificationReminders(now)
+	if err != nil {
+		return err
+	}
+	for _, completion := range reminders {
+		err := notifyRecertification(completion, func(user models.User, course models.Course, completion models.CourseCompletion) error {
+			return mail.SendRecertificationReminder(user.Email, course.VulnerabilityType, completion.ExpiresAt,
+				userLocation(user.Timezone), coursesURL, UserLocale(user.Locale))
+		})
+		if err != nil {
+			log.Printf("Failed to send recertification reminder for course %d to user %d: %v", completion.CourseID, completion.UserID, err)
+			continue
+		}
+		if err := Store.MarkRecertificationReminded(completion.UserID, completion.CourseID, now); err != nil {
+			return err
+		}
+	}
+
+	expired, err := Store.ListExpiredCourseCompletions(now)
+	if err != nil {
+		return err
+	}
+	for _, completion := range expired {
+		if err := Store.ExpireCourseCompletion(completion.UserID, completion.CourseID, now); err != nil {
+			return err
+		}
+		log.Printf("Completion of course %d by user %d expired; progress reset", completion.CourseID, completion.UserID)
+		err := notifyRecertification(completion, func(user models.User, course models.Course, _ models.CourseCompletion) error {
+			return mail.SendRecertificationDue(user.Email, course.VulnerabilityType, coursesURL, UserLocale(user.Locale))
+		})
+		if err != nil {
+			log.Printf("Failed to send recertification notice for course %d to user %d: %v", completion.CourseID, completion.UserID, err)
+		}
+	}
+	return nil
+}
+
+// @Summary Get course recertification
+// @Description Returns how often the course must be completed again.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.CourseRecertification
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "The course does not require recertification"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/courses/{id}/recertification [get]
+func GetCourseRecertification(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	rec, err := Store.GetCourseRecertification(courseID)
+	if errors.Is(err, storage.ErrRecertificationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course does not require recertification"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get recertification: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rec)
+}
+
+// @Summary Require periodic course re-completion
+// @Description Marks the course as requiring re-completion every periodMonths months (for example annual security training). Each completion is valid for the period; reminderDays before it ends the learner gets an email, and when it ends the course progress is reset so the course is due again. Users who already completed the course get a cycle counted from their last completed task, so enabling this on an old course can make their completions due at once. Changing the period recalculates current cycles. Certificates already issued are kept.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Param request body models.SaveRecertificationRequest true "Recertification period"
+// @Success 200 {object} models.CourseRecertification
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/courses/{id}/recertification [put]
+func SetCourseRecertification(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	var req models.SaveRecertificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	rec := models.CourseRecertification{CourseID: courseID, PeriodMonths: req.PeriodMonths, ReminderDays: defaultRecertificationReminderDays}
+	if req.ReminderDays != nil {
+		rec.ReminderDays = *req.ReminderDays
+	}
+	rec, err = Store.SetCourseRecertification(rec)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save recertification: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rec)
+}
+
+// @Summary Stop course recertification
+// @Description The course no longer has to be completed again. Progress that was already reset stays reset.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/courses/{id}/recertification [delete]
+func DeleteCourseRecertification(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	err = Store.DeleteCourseRecertification(courseID)
+	if errors.Is(err, storage.ErrRecertificationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course does not require recertification"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete recertification: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Recertification disabled")})
+}
+
+// @Summary Compliance report
+// @Description Status of every active user in every course that requires periodic re-completion: compliant, expiring (reminder sent, renewal due soon), due (completion expired) or not_completed. Use format=csv to download the report.
+// @Tags Admin
+// @Produce json,text/csv
+// @Security BearerAuth
+// @Param courseId query int false "Only this course"
+// @Param status query string false "Only this status" Enums(compliant, expiring, due, not_completed)
+// @Param format query string false "Response format" Enums(json, csv)
+// @Success 200 {array} models.ComplianceEntry
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/compliance [get]
+func GetComplianceReport(c *gin.Context) {
+	var filter models.ComplianceFilter
+	if raw := c.Query("courseId"); raw != "" {
+		courseID, err := strconv.Atoi(raw)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+			return
+		}
+		filter.CourseID = courseID
+	}
+	status := c.Query("status")
+	switch status {
+	case "", models.RecertificationCompliant, models.RecertificationExpiring, models.RecertificationDue, models.RecertificationNotCompleted:
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid status"})
+		return
+	}
+
+	if c.Query("format") != "csv" {
+		entries, err := complianceEntries(filter, status)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to build compliance report: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, entries)
+		return
+	}
+
+	now := time.Now()
+	export := newCSVExport(c, "compliance", []string{"user_id", "username", "email", "course_id", "course", "status", "completed_at", "expires_at"})
+	export.finish(Store.EachComplianceEntry(filter, func(entry models.ComplianceEntry) error {
+		entry.Status = complianceStatus(entry, now)
+		if status != "" && entry.Status != status {
+			return nil
+		}
+		return export.write([]string{strconv.Itoa(entry.UserID), entry.Username, entry.Email, strconv.Itoa(entry.CourseID),
+			entry.CourseTitle, entry.Status, formatExportTime(entry.CompletedAt), formatExportTime(entry.ExpiresAt)})
+	}))
+}
+
+// @Summary My recertifications
+// @Description Courses the current user must complete periodically, with the status and expiry of the current completion.
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.ComplianceEntry
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /recertifications [get]
+func ListMyRecertifications(c *gin.Context) {
+	entries, err := complianceEntries(models.ComplianceFilter{UserID: c.GetInt("userID")}, "")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get recertifications: " + err.Error()})
+		return
+	}
+	for i := range entries {
+		entries[i].Email = ""
+	}
+	c.JSON(http.StatusOK, entries)
+}
+
+// complianceEntries возвращает отчет с вычисленными состояниями; status — только это состояние
+func complianceEntries(filter models.ComplianceFilter, status string) ([]models.ComplianceEntry, error) {
+	entries, err := Store.ComplianceReport(filter)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	filtered := entries[:0]
+	for _, entry := range entries {
+		entry.Status = complianceStatus(entry, now)
+		if status == "" || entry.Status == status {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered, nil
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pendingRegistrationUserID разбирает ID пользователя из пути
+func pendingRegistrationUserID(c *gin.Context) (int, bool) {
+	userID, err := strconv.Atoi(c.Param("user_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return 0, false
+	}
+	return userID, true
+}
+
+// respondRegistrationDecisionError отвечает на ошибку одобрения или отклонения регистрации
+func respondRegistrationDecisionError(c *gin.Context, err error) {
+	if errors.Is(err, storage.ErrRegistrationNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Pending registration not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to process registration: " + err.Error()})
+}
+
+// @Summary List pending registrations
+// @Description Self-registrations waiting for approval, oldest first (admin only). Registrations are held while the registration_approval setting is on.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.PendingRegistration
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/registrations [get]
+func ListPendingRegistrations(c *gin.Context) {
+	registrations, err := Store.ListPendingRegistrations()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get pending registrations: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, registrations)
+}
+
+// @Summary Approve registration
+// @Description Activate a pending account and email the user that they can sign in (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "User ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/registrations/{user_id}/approve [post]
+func ApproveRegistration(c *gin.Context) {
+	userID, ok := pendingRegistrationUserID(c)
+	if !ok {
+		return
+	}
+	registration, err := Store.ApproveRegistration(userID)
+	if err != nil {
+		respondRegistrationDecisionError(c, err)
+		return
+	}
+	forgetSessionState(userID)
+
+	_, baseURL := currentLoginAlerts()
+	go func() {
+		if err := mail.SendRegistrationApproved(registration.Email, baseURL, UserLocale(registration.Locale)); err != nil {
+			log.Printf("Failed to send registration approval to user %d: %v", userID, err)
+		}
+	}()
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Registration approved")})
+}
+
+// @Summary Reject registration
+// @Description Delete a pending account and email the applicant the optional reason (admin only). The username and email become available again.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param user_id path int true "User ID"
+// @Param request body models.RejectRegistrationRequest false "Reason"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/registrations/{user_id}/reject [post]
+func RejectRegistration(c *gin.Context) {
+	userID, ok := pendingRegistrationUserID(c)
+	if !ok {
+		return
+	}
+	var req models.RejectRegistrationRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+			return
+		}
+	}
+	registration, err := Store.RejectRegistration(userID)
+	if err != nil {
+		respondRegistrationDecisionError(c, err)
+		return
+	}
+	forgetSessionState(userID)
+
+	go func() {
+		if err := mail.SendRegistrationRejected(registration.Email, req.Reason, UserLocale(registration.Locale)); err != nil {
+			log.Printf("Failed to send registration rejection to user %d: %v", userID, err)
+		}
+	}()
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Registration rejected")})
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+var (
+	retentionMu       sync.Mutex
+	retentionPolicies []models.RetentionPolicy
+	retentionDryRun   bool
+	retentionLastRun  *time.Time
+	retentionReports  = []models.RetentionReport{}
+)
+
+// ConfigureRetention задает политики хранения; виды данных с нулевым сроком не очищаются
+func ConfigureRetention(cfg config.RetentionConfig) {
+	policies := []models.RetentionPolicy{}
+	for _, policy := range []models.RetentionPolicy{
+		{Data: models.RetentionAuditLog, Days: cfg.AuditLogDays, Action: cfg.AuditLogAction},
+		{Data: models.RetentionLoginHistory, Days: cfg.LoginHistoryDays, Action: cfg.LoginHistoryAction},
+		{Data: models.RetentionInactiveAccounts, Days: cfg.InactiveAccountDays, Action: cfg.InactiveAccountAction},
+	} {
+		if policy.Days > 0 {
+			policies = append(policies, policy)
+		}
+	}
+
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+	retentionPolicies = policies
+	retentionDryRun = cfg.DryRun
+}
+
+// applyRetention применяет каждую политику и возвращает отчет; ошибка одной политики
+// попадает в ее отчет и не мешает остальным
+func applyRetention(policies []models.RetentionPolicy, now time.Time, dryRun bool) []models.RetentionReport {
+	reports := make([]models.RetentionReport, 0, len(policies))
+	for _, policy := range policies {
+		report := models.RetentionReport{
+			RetentionPolicy: policy,
+			Cutoff:          now.AddDate(0, 0, -policy.Days).UTC(),
+			DryRun:          dryRun,
+		}
+		affected, err := Store.ApplyRetention(policy, report.Cutoff, dryRun)
+		report.Affected = affected
+		if err != nil {
+			report.Error = err.Error()
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// ApplyRetentionPolicies — периодическая задача политик хранения. Итоги логируются, сохраняются
+// для GET /admin/retention, а изменения данных записываются в журнал действий.
+func ApplyRetentionPolicies() error {
+	retentionMu.Lock()
+	policies, dryRun := retentionPolicies, retentionDryRun
+	retentionMu.Unlock()
+	if len(policies) == 0 {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	reports := applyRetention(policies, now, dryRun)
+
+	retentionMu.Lock()
+	retentionLastRun, retentionReports = &now, reports
+	retentionMu.Unlock()
+
+	var failed []string
+	for _, report := range reports {
+		mode := ""
+		if report.DryRun {
+			mode = " (dry run)"
+		}
+		if report.DryRun || report.Affected > 0 {
+			log.Printf("Retention%s: %s %d %s records older than %s", mode, report.Action, report.Affected, report.Data,
+				report.Cutoff.Format(time.RFC3339))
+		}
+		if report.Error != "" {
+			failed = append(failed, report.Data+": "+report.Error)
+		}
+		if report.DryRun || report.Affected == 0 {
+			continue
+		}
+		err := Store.RecordAuditEntry(models.AuditEntry{
+			Action:     "retention " + report.Action,
+			TargetType: report.Data,
+			Changes: []models.AuditChange{
+				{Field: "affected", After: report.Affected},
+				{Field: "cutoff", After: report.Cutoff},
+			},
+			Status: http.StatusOK,
+		})
+		if err != nil {
+			log.Printf("Failed to record retention of %s in the audit log: %v", report.Data, err)
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("retention failed for %v", failed)
+	}
+	return nil
+}
+
+// @Summary Data retention status
+// @Description Configured retention policies (data kind, days, purge or anonymize) and the report of the last run of the retention job (admin only). Kinds of data without a policy are kept indefinitely.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.RetentionStatus
+// @Router /admin/retention [get]
+func GetRetentionStatus(c *gin.Context) {
+	retentionMu.Lock()
+	defer retentionMu.Unlock()
+	c.JSON(http.StatusOK, models.RetentionStatus{
+		DryRun:   retentionDryRun,
+		Policies: retentionPolicies,
+		LastRun:  retentionLastRun,
+		Reports:  retentionReports,
+	})
+}
+
+// @Summary Preview data retention
+// @Description Report how many records each retention policy would purge or anonymize right now, without changing anything (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.RetentionReport
+// @Router /admin/retention/preview [post]
+func PreviewRetention(c *gin.Context) {
+	retentionMu.Lock()
+	policies := retentionPolicies
+	retentionMu.Unlock()
+	c.JSON(http.StatusOK, applyRetention(policies, time.Now(), true))
+}
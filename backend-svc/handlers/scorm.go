@@ -0,0 +1,439 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/media"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/scorm"
+	"lmsmodule/backend-svc/storage"
+)
+
+func currentPackageLimit() int64 {
+	mediaMu.RLock()
+	defer mediaMu.RUnlock()
+	return maxPackageBytes
+}
+
+// scormLaunchURL возвращает адрес запуска учебного объекта: файлы пакета раздает медиахранилище,
+// внешние ресурсы запускаются по своему адресу
+func scormLaunchURL(store media.Store, assetKey, launch string) string {
+	if strings.Contains(launch, "://") {
+		return launch
+	}
+	file, query := launch, ""
+	if i := strings.IndexAny(launch, "?#"); i >= 0 {
+		file, query = launch[:i], launch[i:]
+	}
+	return store.URL(assetKey+"/"+file) + query
+}
+
+// errorDetail возвращает текст ошибки без текста обернутой в нее sentinel
+func errorDetail(err, sentinel error) string {
+	return strings.TrimPrefix(err.Error(), sentinel.Error()+": ")
+}
+
+// uploadField возвращает поле формы, а при загрузке архива телом запроса — параметр строки запроса
+func uploadField(c *gin.Context, name string) string {
+	if value := c.PostForm(name); value != "" {
+		return value
+	}
+	return c.Query(name)
+}
+
+// openScormUpload открывает архив из поля формы "package" или из тела запроса. Тело запроса
+// сохраняется во временный файл: zip читается с произвольных позиций. cleanup освобождает ресурсы.
+func openScormUpload(c *gin.Context, limit int64) (pkg *scorm.Package, cleanup func(), err error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+1<<20)
+	if c.ContentType() == "multipart/form-data" {
+		file, err := c.FormFile("package")
+		if err != nil {
+			return nil, nil, err
+		}
+		if file.Size > limit {
+			return nil, nil, &http.MaxBytesError{Limit: limit}
+		}
+		f, err := file.Open()
+		if err != nil {
+			return nil, nil, err
+		}
+		pkg, err := scorm.Open(f, file.Size)
+		if err != nil {
+			f.Close()
+			return nil, nil, err
+		}
+		return pkg, func() { f.Close() }, nil
+	}
+
+	tmp, err := os.CreateTemp("", "scorm-*.zip")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup = func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}
+	size, err := io.Copy(tmp, io.LimitReader(c.Request.Body, limit+1))
+	if err == nil && size > limit {
+		err = &http.MaxBytesError{Limit: limit}
+	}
+	if err == nil {
+		pkg, err = scorm.Open(tmp, size)
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return pkg, cleanup, nil
+}
+
+// storeScormFiles выкладывает файлы пакета в медиахранилище, подключая к HTML-страницам мост
+// среды выполнения, и возвращает их ключи
+func storeScormFiles(ctx context.Context, store media.Store, pkg *scorm.Package, assetKey string, limit int64) ([]string, error) {
+	var keys []string
+	err := pkg.Files(limit, func(name string, data []byte) error {
+		contentType := mime.TypeByExtension(path.Ext(name))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		if scorm.IsPage(name) {
+			data = scorm.InjectBridge(name, data)
+		}
+		key := assetKey + "/" + name
+		if err := store.Put(ctx, key, contentType, data); err != nil {
+			return err
+		}
+		keys = append(keys, key)
+		return nil
+	})
+	if err != nil {
+		return keys, err
+	}
+	// Мост записывается последним, чтобы файл пакета с тем же именем не мог его подменить
+	key := assetKey + "/" + scorm.BridgeFile
+	if err := store.Put(ctx, key, "application/javascript; charset=utf-8", scorm.BridgeJS); err != nil {
+		return keys, err
+	}
+	return append(keys, key), nil
+}
+
+// deleteScormFiles удаляет файлы несостоявшегося импорта; ошибки только логируются
+func deleteScormFiles(ctx context.Context, store media.Store, keys []string) {
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			log.Printf("Failed to delete SCORM file: %v", err)
+		}
+	}
+}
+
+// @Summary Import a SCORM package
+// @Description Import a SCORM 1.2 or SCORM 2004 package (zip with imsmanifest.xml at the root) as a new course (instructors and admins). The package is uploaded as the multipart field "package" or as the raw request body. Every launchable item of the default organization becomes a task in manifest order; the package files are stored in the media storage, and every HTML page gets the runtime bridge script. Optional fields (form fields or query parameters): title and description of the course, difficulty of its tasks.
+// @Tags SCORM
+// @Accept multipart/form-data,application/zip
+// @Produce json
+// @Security BearerAuth
+// @Param package formData file false "SCORM package"
+// @Param title query string false "Course title; defaults to the organization title"
+// @Param description query string false "Course description"
+// @Param difficulty query string false "Difficulty of the tasks" default(medium)
+// @Success 201 {object} models.ScormPackage
+// @Failure 400 {object} models.ErrorResponse "Not a SCORM package"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 413 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /courses/scorm [post]
+func ImportScormPackage(c *gin.Context) {
+	userID, _, ok := requireInstructor(c, "Only administrators and instructors can import SCORM packages")
+	if !ok {
+		return
+	}
+	store, _ := currentMedia()
+	limit := currentPackageLimit()
+
+	pkg, cleanup, err := openScormUpload(c, limit)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: fmt.Sprintf("SCORM package must not exceed %d bytes", limit)})
+		return
+	case errors.Is(err, scorm.ErrNoManifest):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The package has no imsmanifest.xml"})
+		return
+	case errors.Is(err, scorm.ErrUnsupportedVersion):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Only SCORM 1.2 and SCORM 2004 packages are supported"})
+		return
+	case errors.Is(err, scorm.ErrNoContent):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The package has no launchable items"})
+		return
+	case errors.Is(err, scorm.ErrInvalidPackage):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid SCORM package: " + errorDetail(err, scorm.ErrInvalidPackage)})
+		return
+	case err != nil:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Upload a SCORM package in the \"package\" form field or as the request body"})
+		return
+	}
+	defer cleanup()
+
+	suffix := make([]byte, 8)
+	if _, err := rand.Read(suffix); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to store SCORM package"})
+		return
+	}
+	assetKey := "scorm/" + hex.EncodeToString(suffix)
+	keys, err := storeScormFiles(c.Request.Context(), store, pkg, assetKey, limit)
+	if errors.Is(err, scorm.ErrTooLarge) {
+		deleteScormFiles(c.Request.Context(), store, keys)
+		c.JSON(http.StatusRequestEntityTooLarge, models.ErrorResponse{Error: fmt.Sprintf("SCORM package must not exceed %d bytes", limit)})
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to store SCORM package: %v", err)
+		deleteScormFiles(c.Request.Context(), store, keys)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to store SCORM package"})
+		return
+	}
+
+	title := uploadField(c, "title")
+	if title == "" {
+		title = pkg.Title
+	}
+	if title == "" {
+		title = pkg.Identifier
+	}
+	difficulty := uploadField(c, "difficulty")
+	if difficulty == "" {
+		difficulty = "medium"
+	}
+	course := models.Course{VulnerabilityType: title, Description: uploadField(c, "description")}
+	record := models.ScormPackage{
+		Identifier: pkg.Identifier,
+		Version:    pkg.Version,
+		Title:      title,
+		AssetKey:   assetKey,
+		CreatedBy:  &userID,
+	}
+	for _, item := range pkg.Items {
+		taskTitle := item.Title
+		if taskTitle == "" {
+			taskTitle = item.Identifier
+		}
+		course.Tasks = append(course.Tasks, models.Task{Title: taskTitle, Difficulty: difficulty})
+		record.Items = append(record.Items, models.ScormItem{
+			Identifier: item.Identifier,
+			Title:      taskTitle,
+			ScormType:  item.ScormType,
+			Launch:     item.Launch,
+		})
+	}
+
+	record, err = StoreFor(c).CreateScormCourse(course, record)
+	if err != nil {
+		deleteScormFiles(c.Request.Context(), store, keys)
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to import SCORM package: " + err.Error()})
+		return
+	}
+	go announceCoursePublished(StoreFor(c), record.CourseID)
+	for i := range record.Items {
+		record.Items[i].LaunchURL = scormLaunchURL(store, assetKey, record.Items[i].Launch)
+	}
+	c.JSON(http.StatusCreated, record)
+}
+
+// @Summary Get the SCORM package of a course
+// @Tags SCORM
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.ScormPackage
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "The course was not imported from a SCORM package"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /courses/{id}/scorm [get]
+func GetScormPackage(c *gin.Context) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+
+	pkg, err := Store.GetScormPackage(courseID)
+	if errors.Is(err, storage.ErrScormPackageNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "The course was not imported from a SCORM package"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get SCORM package: " + err.Error()})
+		return
+	}
+	store, _ := currentMedia()
+	for i := range pkg.Items {
+		pkg.Items[i].LaunchURL = scormLaunchURL(store, pkg.AssetKey, pkg.Items[i].Launch)
+	}
+	c.JSON(http.StatusOK, pkg)
+}
+
+// loadScormItem загружает учебный объект задания из пути запроса
+func loadScormItem(c *gin.Context) (models.ScormItem, bool) {
+	taskID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+		return models.ScormItem{}, false
+	}
+	item, err := Store.GetScormItem(taskID)
+	if errors.Is(err, storage.ErrScormItemNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "The task is not SCORM content"})
+		return item, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get SCORM item: " + err.Error()})
+		return item, false
+	}
+	return item, true
+}
+
+// completeScormTask отмечает задание выполненным, когда учебный объект впервые сообщил о завершении
+func completeScormTask(attempt models.ScormAttempt) error {
+	if err := Store.CompleteTask(attempt.UserID, attempt.TaskID); err != nil {
+		return err
+	}
+	onTasksCompleted(attempt.UserID, []int{attempt.TaskID})
+	return nil
+}
+
+// @Summary Launch a SCORM task
+// @Description Returns the launch URL of the learning object and the CMI data for the runtime API: saved values of the current user plus learner details, mode and entry set by the LMS. Launching an asset (content without runtime communication) completes the task.
+// @Tags SCORM
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Success 200 {object} models.ScormLaunch
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "The task is not SCORM content"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/scorm [get]
+func LaunchScormTask(c *gin.Context) {
+	userID := c.GetInt("userID")
+	item, ok := loadScormItem(c)
+	if !ok {
+		return
+	}
+	user, err := Store.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
+		return
+	}
+	attempt, err := Store.GetScormAttempt(userID, item.TaskID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get SCORM data: " + err.Error()})
+		return
+	}
+
+	if item.ScormType == scorm.TypeAsset && attempt.CompletedAt == nil {
+		now := time.Now().UTC()
+		attempt.Status, attempt.CompletedAt = "completed", &now
+		if attempt, err = Store.SaveScormAttempt(attempt); err == nil {
+			err = completeScormTask(attempt)
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save SCORM data: " + err.Error()})
+			return
+		}
+	}
+
+	learnerName := user.FullName
+	if learnerName == "" {
+		learnerName = user.Username
+	}
+	store, _ := currentMedia()
+	c.JSON(http.StatusOK, models.ScormLaunch{
+		TaskID:    item.TaskID,
+		CourseID:  item.CourseID,
+		Version:   item.Version,
+		ScormType: item.ScormType,
+		LaunchURL: scormLaunchURL(store, item.AssetKey, item.Launch),
+		Data:      scorm.InitialData(item.Version, strconv.Itoa(userID), learnerName, attempt.Data),
+		Completed: attempt.CompletedAt != nil,
+	})
+}
+
+// @Summary Commit SCORM runtime data
+// @Description Saves the CMI data of the current user sent by the runtime API on LMSCommit/Commit and LMSFinish/Terminate. The request carries the full CMI state and replaces the saved one. Elements set by the LMS are ignored. When the learning object reports completion (lesson_status completed or passed in SCORM 1.2, completion_status completed or success_status passed in SCORM 2004) the task is completed.
+// @Tags SCORM
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Task ID"
+// @Param request body models.ScormCommitRequest true "CMI data"
+// @Success 200 {object} models.ScormAttempt
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "The task is not SCORM content"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /tasks/{id}/scorm [put]
+func CommitScormData(c *gin.Context) {
+	userID := c.GetInt("userID")
+	item, ok := loadScormItem(c)
+	if !ok {
+		return
+	}
+
+	var req models.ScormCommitRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	data, err := scorm.Sanitize(item.Version, req.Data)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid SCORM data: " + errorDetail(err, scorm.ErrInvalidData)})
+		return
+	}
+
+	attempt := models.ScormAttempt{UserID: userID, TaskID: item.TaskID, Data: data}
+	var completed bool
+	attempt.Status, completed, attempt.Score = scorm.Status(item.Version, data)
+	if completed {
+		now := time.Now().UTC()
+		attempt.CompletedAt = &now
+	}
+	saved, err := Store.SaveScormAttempt(attempt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save SCORM data: " + err.Error()})
+		return
+	}
+	// Сохраненное время завершения совпадает с только что выставленным лишь при первом завершении
+	if completed && saved.CompletedAt.Equal(*attempt.CompletedAt) {
+		if err := completeScormTask(saved); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to complete task"})
+			return
+		}
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// @Summary SCORM runtime API for the course player
+// @Description JavaScript for the course player backed by the launch and commit endpoints. Usage: LmsScorm.load({apiBase: "/api", taskId, token, frame}). It opens the learning object in the given iframe sandboxed without allow-same-origin, so package code cannot reach the player window or the access token. window.API (SCORM 1.2) and window.API_1484_11 (SCORM 2004) are installed inside the learning object by the bridge script added to its pages on import and talk to the player via postMessage.
+// @Tags SCORM
+// @Produce application/javascript
+// @Success 200 {string} string
+// @Router /scorm/runtime.js [get]
+func ScormRuntimeScript(c *gin.Context) {
+	c.Header("Cache-Control", "public, max-age=3600")
+	c.Data(http.StatusOK, "application/javascript; charset=utf-8", scorm.RuntimeJS)
+}
@@ -0,0 +1,113 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/search"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	// searchOverfetch — во сколько раз больше документов запрашивается из индекса: часть
+	// найденного отбрасывается как курсы другой организации и сообщения заблокированных
+	searchOverfetch = 3
+)
+
+// SearchIndex — полнотекстовый индекс; nil, если поиск не настроен
+var SearchIndex *search.Index
+
+// UseSearch устанавливает поисковый индекс для обработчиков
+func UseSearch(idx *search.Index) {
+	SearchIndex = idx
+}
+
+// @Summary Search
+// @Description Full-text search over courses, tasks and discussion comments, tolerant to typos. Results come from a search index updated asynchronously, so content created a moment ago may not be found yet. Comments hidden by moderators are not returned.
+// @Tags Courses
+// @Produce json
+// @Security BearerAuth
+// @Param q query string true "Search query"
+// @Param type query string false "Comma-separated document types: course,task,comment"
+// @Param limit query int false "Maximum number of results (1-50, default 20)"
+// @Success 200 {object} models.SearchResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse
+// @Router /search [get]
+func Search(c *gin.Context) {
+	if SearchIndex == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Search is not enabled"})
+		return
+	}
+	text := strings.TrimSpace(c.Query("q"))
+	if text == "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Search query is required"})
+		return
+	}
+	var types []string
+	if raw := c.Query("type"); raw != "" {
+		for _, kind := range strings.Split(raw, ",") {
+			switch kind = strings.TrimSpace(kind); kind {
+			case models.SearchCourse, models.SearchTask, models.SearchComment:
+				types = append(types, kind)
+			default:
+				c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "type must be course, task or comment"})
+				return
+			}
+		}
+	}
+	limit := defaultSearchLimit
+	if raw := c.Query("limit"); raw != "" {
+		var err error
+		if limit, err = strconv.Atoi(raw); err != nil || limit < 1 || limit > maxSearchLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "limit must be between 1 and 50"})
+			return
+		}
+	}
+
+	hits, err := SearchIndex.Search(text, types, limit*searchOverfetch)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to search: " + err.Error()})
+		return
+	}
+	courses, err := StoreFor(c).GetCourses()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to search: " + err.Error()})
+		return
+	}
+	visible := make(map[int]bool, len(courses))
+	for _, course := range courses {
+		visible[course.ID] = true
+	}
+
+	// Сообщения заблокированных в курсе пользователей видят только модераторы и сами авторы
+	viewers := map[int]discussionViewer{}
+	result := make([]models.SearchHit, 0, limit)
+	for _, hit := range hits {
+		if len(result) == limit {
+			break
+		}
+		if !visible[hit.CourseID] {
+			continue
+		}
+		if hit.Type == models.SearchComment {
+			viewer, loaded := viewers[hit.CourseID]
+			if !loaded {
+				var ok bool
+				if viewer, ok = newDiscussionViewer(c, hit.CourseID); !ok {
+					return
+				}
+				viewers[hit.CourseID] = viewer
+			}
+			if seen, _ := viewer.sees(hit.AuthorID); !seen {
+				continue
+			}
+		}
+		result = append(result, hit)
+	}
+	c.JSON(http.StatusOK, models.SearchResponse{Query: text, Hits: result})
+}
@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/settings"
+	"lmsmodule/backend-svc/storage"
+)
+
+// sessionStateTTL ограничивает, как долго реплика доверяет закэшированному состоянию сессии.
+// Вход на другой реплике вытесняет старые токены здесь не позже чем через этот интервал.
+const sessionStateTTL = 5 * time.Second
+
+type cachedSessionState struct {
+	state    models.SessionState
+	loadedAt time.Time
+}
+
+var (
+	sessionStateMu    sync.Mutex
+	sessionStateCache = map[int]cachedSessionState{}
+)
+
+func rememberSessionState(userID int, state models.SessionState) {
+	sessionStateMu.Lock()
+	defer sessionStateMu.Unlock()
+	sessionStateCache[userID] = cachedSessionState{state: state, loadedAt: time.Now()}
+}
+
+func forgetSessionState(userID int) {
+	sessionStateMu.Lock()
+	defer sessionStateMu.Unlock()
+	delete(sessionStateCache, userID)
+}
+
+// forgetAllSessionStates сбрасывает кеш состояний сессий всех пользователей
+func forgetAllSessionStates() {
+	sessionStateMu.Lock()
+	defer sessionStateMu.Unlock()
+	clear(sessionStateCache)
+}
+
+func loadSessionState(userID int) (models.SessionState, error) {
+	sessionStateMu.Lock()
+	cached, ok := sessionStateCache[userID]
+	sessionStateMu.Unlock()
+	if ok && time.Since(cached.loadedAt) < sessionStateTTL {
+		return cached.state, nil
+	}
+
+	state, err := Store.GetSessionState(userID)
+	if err != nil {
+		return models.SessionState{}, err
+	}
+	rememberSessionState(userID, state)
+	return state, nil
+}
+
+// createSessionToken начинает новую сессию пользователя и выдает для нее access-токен.
+// Идентификатор сессии запоминается при каждом входе, чтобы включение режима
+// единственной сессии сразу действовало и для уже выданных токенов. В режиме только для
+// чтения новая сессия не записывается: токен получает текущую сессию пользователя.
+func createSessionToken(userID int) (string, error) {
+	if ReadOnlyMode() {
+		state, err := loadSessionState(userID)
+		if err != nil {
+			return "", err
+		}
+		return createJWTToken(userID, state.SessionID)
+	}
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("generate session id: %w", err)
+	}
+	sessionID := hex.EncodeToString(raw)
+
+	if err := Store.StartSession(userID, sessionID); err != nil {
+		return "", err
+	}
+	forgetSessionState(userID)
+	return createJWTToken(userID, sessionID)
+}
+
+// SessionActive сообщает, принимается ли токен с указанной сессией. Если для развертывания
+// или для пользователя включен режим единственной сессии, действителен только токен последнего входа.
+func SessionActive(userID int, sessionID string) (bool, error) {
+	state, err := loadSessionState(userID)
+	if err != nil {
+		return false, err
+	}
+	if !state.SingleSession && !Settings.Bool(settings.KeySingleSession) {
+		return true, nil
+	}
+	return sessionID != "" && sessionID == state.SessionID, nil
+}
+
+// AccountActive сообщает, не деактивирована ли учетная запись (администратором, ссылкой
+// «это был не я» или запросом на удаление); токены неактивных учетных записей не принимаются
+func AccountActive(userID int) (bool, error) {
+	state, err := loadSessionState(userID)
+	if err != nil {
+		return false, err
+	}
+	return state.IsActive, nil
+}
+
+// UserOrganization возвращает организацию пользователя и активна ли она; участники отключенной
+// организации не могут работать с системой
+func UserOrganization(userID int) (orgID int, active bool, err error) {
+	state, err := loadSessionState(userID)
+	if err != nil {
+		return 0, false, err
+	}
+	return state.OrganizationID, state.OrganizationActive, nil
+}
+
+// @Summary Set single-session mode for a user
+// @Description Allow the user only one active session: a new sign-in ends the previous one (admin only). The deployment-wide single_session setting applies to everyone regardless of this flag.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.SingleSessionRequest true "Single-session mode"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{id}/single-session [put]
+func SetUserSingleSession(c *gin.Context) {
+	targetUserID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	var req models.SingleSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	err = Store.SetSingleSession(targetUserID, req.Enabled)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update single-session mode: " + err.Error()})
+		return
+	}
+	forgetSessionState(targetUserID)
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Single-session mode updated successfully")})
+}
@@ -0,0 +1,142 @@
+package handlers
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/ipaccess"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/settings"
+	"net/http"
+	"strconv"
+)
+
+// @Summary Get public runtime settings
+// @Description Registration availability, approval mode and maintenance banner for the frontend
+// @Tags Settings
+// @Produce json
+// @Success 200 {object} models.PublicSettingsResponse
+// @Router /settings/public [get]
+func GetPublicSettings(c *gin.Context) {
+	maintenance := Settings.Bool(settings.KeyMaintenanceMode)
+	response := models.PublicSettingsResponse{
+		RegistrationOpen:     Settings.Bool(settings.KeyRegistrationOpen),
+		RegistrationApproval: Settings.Bool(settings.KeyRegistrationApproval),
+		MaintenanceBanner:    Settings.Get(settings.KeyMaintenanceBanner),
+		MaintenanceMode:      maintenance,
+		ReadOnlyMode:         ReadOnlyMode(),
+	}
+	if maintenance || response.ReadOnlyMode {
+		response.MaintenanceMessage = Settings.Get(settings.KeyMaintenanceText)
+	}
+	if verifier, siteKey := currentCaptcha(); verifier != nil {
+		response.CaptchaProvider = verifier.Provider()
+		response.CaptchaSiteKey = siteKey
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// @Summary List runtime settings
+// @Description Get all runtime-tunable settings with current values (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.Setting
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Router /admin/settings [get]
+func GetSettings(c *gin.Context) {
+	c.JSON(http.StatusOK, Settings.All())
+}
+
+// @Summary Update runtime setting
+// @Description Change a runtime setting; takes effect without redeploying (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param key path string true "Setting key"
+// @Param request body models.UpdateSettingRequest true "New value"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/settings/{key} [put]
+func UpdateSetting(c *gin.Context) {
+	var req models.UpdateSettingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	// Список адресов, закрывающий доступ самому администратору, исправить через API было бы уже нельзя
+	key := c.Param("key")
+	if key == settings.KeyIPAllowlist || key == settings.KeyAdminIPAllowlist {
+		if networks, err := ipaccess.Parse(req.Value); err == nil && !networks.Allows(c.ClientIP()) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The allowlist must include your current IP address"})
+			return
+		}
+	}
+
+	err := Settings.Set(key, req.Value, c.GetInt("userID"))
+	if err != nil {
+		if errors.Is(err, settings.ErrUnknownSetting) {
+			c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Unknown setting"})
+			return
+		}
+		if errors.Is(err, settings.ErrInvalidValue) {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update setting: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Setting updated successfully")})
+}
+
+// @Summary Toggle maintenance mode
+// @Description Enable or disable maintenance mode; non-admin requests get 503 while enabled. With readOnly, only requests that change data get 503 and reads keep working (admin only)
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.MaintenanceRequest true "Maintenance state"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/maintenance [post]
+func SetMaintenanceMode(c *gin.Context) {
+	var req models.MaintenanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	adminID := c.GetInt("userID")
+	if req.Message != "" {
+		if err := Settings.Set(settings.KeyMaintenanceText, req.Message, adminID); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update maintenance message: " + err.Error()})
+			return
+		}
+	}
+
+	// Полное обслуживание и режим только для чтения взаимоисключающие; выключение снимает оба
+	if err := Settings.Set(settings.KeyReadOnlyMode, strconv.FormatBool(req.Enabled && req.ReadOnly), adminID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update maintenance mode: " + err.Error()})
+		return
+	}
+	if err := Settings.Set(settings.KeyMaintenanceMode, strconv.FormatBool(req.Enabled && !req.ReadOnly), adminID); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update maintenance mode: " + err.Error()})
+		return
+	}
+
+	if req.Enabled && req.ReadOnly {
+		c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Read-only mode enabled")})
+		return
+	}
+	if req.Enabled {
+		c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Maintenance mode enabled")})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Maintenance mode disabled")})
+}
@@ -0,0 +1,112 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"lmsmodule/backend-svc/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statsCacheTTL — сколько панель администратора видит одну и ту же статистику. Запросы
+// агрегируют всю историю прогресса, поэтому не выполняются на каждое обновление страницы.
+const statsCacheTTL = 5 * time.Minute
+
+// statsMaxDays — наибольший период рядов статистики
+const statsMaxDays = 365
+
+type cachedPlatformStats struct {
+	stats    models.PlatformStats
+	loadedAt time.Time
+}
+
+var (
+	statsMu    sync.Mutex
+	statsCache = map[int]cachedPlatformStats{}
+
+	// activityDay и activitySeen — пользователи, чья активность за текущий день (UTC) уже записана
+	activityMu   sync.Mutex
+	activityDay  string
+	activitySeen = map[int]bool{}
+)
+
+// RecordActivity отмечает пользователя активным сегодня. В хранилище уходит только первый
+// запрос пользователя за день на этой реплике; ошибка записи не мешает обработке запроса.
+// В режиме только для чтения активность не записывается.
+func RecordActivity(userID int) {
+	if ReadOnlyMode() {
+		return
+	}
+	now := time.Now().UTC()
+	day := now.Format("2006-01-02")
+
+	activityMu.Lock()
+	if day != activityDay {
+		activityDay, activitySeen = day, map[int]bool{}
+	}
+	seen := activitySeen[userID]
+	activityMu.Unlock()
+	if seen {
+		return
+	}
+
+	if err := Store.RecordUserActivity(userID, now); err != nil {
+		log.Printf("Failed to record activity of user %d: %v", userID, err)
+		return
+	}
+	activityMu.Lock()
+	if day == activityDay {
+		activitySeen[userID] = true
+	}
+	activityMu.Unlock()
+}
+
+// loadPlatformStats возвращает статистику за последние days дней, включая сегодняшний
+func loadPlatformStats(days int) (models.PlatformStats, error) {
+	statsMu.Lock()
+	cached, ok := statsCache[days]
+	statsMu.Unlock()
+	if ok && time.Since(cached.loadedAt) < statsCacheTTL {
+		return cached.stats, nil
+	}
+
+	to := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, 1)
+	stats, err := Store.GetPlatformStats(to.AddDate(0, 0, -days), to)
+	if err != nil {
+		return models.PlatformStats{}, err
+	}
+	statsMu.Lock()
+	statsCache[days] = cachedPlatformStats{stats: stats, loadedAt: time.Now()}
+	statsMu.Unlock()
+	return stats, nil
+}
+
+// @Summary Get platform statistics
+// @Description KPIs for the admin dashboard: user totals, DAU/WAU/MAU, daily active users, registrations and task completions per day, per-course learners, completions, SCORM scores and review ratings. Days are UTC; results are cached for 5 minutes (see generatedAt). Lab hours are not reported because lab usage is not recorded by this service.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param days query int false "Period length in days including today (1-365)" default(30)
+// @Success 200 {object} models.PlatformStats
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/stats [get]
+func GetPlatformStats(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > statsMaxDays {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid days"})
+		return
+	}
+	stats, err := loadPlatformStats(days)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get statistics: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
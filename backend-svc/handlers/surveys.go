@@ -0,0 +1,454 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/storage"
+)
+
+// surveyOffered сообщает, нужно ли предложить пользователю опрос курса: опрос включен,
+// курс завершен, а ответа еще нет
+func surveyOffered(userID int, survey models.Survey) (bool, error) {
+	if !survey.Active {
+		return false, nil
+	}
+	course, err := Store.GetCourseByID(survey.CourseID)
+	if err != nil {
+		return false, err
+	}
+	completed, err := courseCompleted(userID, course)
+	if err != nil || !completed {
+		return false, err
+	}
+	answered, err := Store.HasAnsweredSurvey(survey.ID, userID)
+	return !answered, err
+}
+
+// offerCourseSurveys отправляет пользователю через WebSocket опросы курсов, которые он
+// завершил выполнением заданий taskIDs
+func offerCourseSurveys(userID int, taskIDs []int) {
+	if Realtime == nil || len(taskIDs) == 0 {
+		return
+	}
+	touched, err := completedTaskCourses(userID, taskIDs)
+	if err != nil {
+		log.Printf("Failed to check course completion for user %d: %v", userID, err)
+		return
+	}
+
+	for courseID := range touched {
+		survey, err := Store.GetCourseSurvey(courseID)
+		if errors.Is(err, storage.ErrSurveyNotFound) {
+			continue
+		}
+		if err == nil {
+			var offered bool
+			offered, err = surveyOffered(userID, survey)
+			if offered {
+				Realtime.Broadcast(realtime.UserTopic(userID), "survey.available", survey)
+			}
+		}
+		if err != nil {
+			log.Printf("Failed to offer survey of course %d to user %d: %v", courseID, userID, err)
+		}
+	}
+}
+
+// loadSurvey загружает опрос курса из пути запроса
+func loadSurvey(c *gin.Context) (models.Survey, bool) {
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return models.Survey{}, false
+	}
+	survey, err := Store.GetCourseSurvey(courseID)
+	if errors.Is(err, storage.ErrSurveyNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Survey not found"})
+		return survey, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get survey: " + err.Error()})
+		return survey, false
+	}
+	return survey, true
+}
+
+// sameSurveyQuestions сообщает, совпадают ли вопросы опроса без учета ID
+func sameSurveyQuestions(a, b []models.SurveyQuestion) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i].Kind != b[i].Kind || a[i].Prompt != b[i].Prompt || a[i].Required != b[i].Required {
+			return false
+		}
+	}
+	return true
+}
+
+// validateSurveyAnswers проверяет ответы по вопросам опроса и отбрасывает пустые текстовые ответы
+func validateSurveyAnswers(survey models.Survey, answers []models.SurveyAnswer) ([]models.SurveyAnswer, string) {
+	questions := map[int]models.SurveyQuestion{}
+	for _, question := range survey.Questions {
+		questions[question.ID] = question
+	}
+
+	answered := map[int]bool{}
+	valid := []models.SurveyAnswer{}
+	for _, answer := range answers {
+		question, ok := questions[answer.QuestionID]
+		if !ok {
+			return nil, "Unknown survey question"
+		}
+		if answered[answer.QuestionID] {
+			return nil, "Duplicate answer to a survey question"
+		}
+		switch question.Kind {
+		case models.SurveyLikert:
+			if answer.Rating == nil || *answer.Rating < 1 || *answer.Rating > models.SurveyScale {
+				return nil, "Rating must be between 1 and 5"
+			}
+			answer.Text = ""
+		case models.SurveyText:
+			if answer.Rating != nil {
+				return nil, "Text questions take a text answer"
+			}
+			answer.Text = strings.TrimSpace(answer.Text)
+			if answer.Text == "" {
+				continue
+			}
+		}
+		answered[answer.QuestionID] = true
+		valid = append(valid, answer)
+	}
+
+	for _, question := range survey.Questions {
+		if question.Required && !answered[question.ID] {
+			return nil, "Answer all required questions"
+		}
+	}
+	return valid, ""
+}
+
+// @Summary Get course survey
+// @Description Post-course feedback survey with the current user's status: courseCompleted tells whether all course tasks are done and the survey can be taken. Disabled surveys are visible to instructors and administrators only.
+// @Tags Surveys
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.CourseSurvey
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/survey [get]
+func GetCourseSurvey(c *gin.Context) {
+	survey, ok := loadSurvey(c)
+	if !ok {
+		return
+	}
+	userID := c.GetInt("userID")
+	_, isStaff, err := requestInstructorRights(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return
+	}
+	if !survey.Active && !isStaff {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Survey not found"})
+		return
+	}
+
+	course, ok := loadCourse(c, survey.CourseID)
+	if !ok {
+		return
+	}
+	completed, err := courseCompleted(userID, course)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get survey: " + err.Error()})
+		return
+	}
+	responded, err := Store.HasAnsweredSurvey(survey.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get survey: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.CourseSurvey{Survey: survey, CourseCompleted: completed, Responded: responded})
+}
+
+// @Summary Save course survey
+// @Description Creates or replaces the course feedback survey (administrators and instructors). Questions of a survey that already has responses cannot be changed; its title, anonymity and active flag can.
+// @Tags Surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param request body models.SaveSurveyRequest true "Survey"
+// @Success 200 {object} models.Survey
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/survey [put]
+func SaveCourseSurvey(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can manage surveys"); !ok {
+		return
+	}
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	var req models.SaveSurveyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	survey := models.Survey{
+		CourseID:  courseID,
+		Title:     req.Title,
+		Anonymous: req.Anonymous,
+		Active:    req.Active == nil || *req.Active,
+		Questions: req.Questions,
+	}
+	store := StoreFor(c)
+	existing, err := store.GetCourseSurvey(courseID)
+	switch {
+	case errors.Is(err, storage.ErrSurveyNotFound):
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get survey: " + err.Error()})
+		return
+	case sameSurveyQuestions(existing.Questions, req.Questions):
+		// Вопросы не менялись — сохраняем их ID, на которые ссылаются ответы
+		survey.Questions = nil
+	case existing.ResponseCount > 0:
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Questions of a survey with responses cannot be changed"})
+		return
+	}
+
+	saved, err := store.SaveCourseSurvey(survey)
+	if errors.Is(err, storage.ErrCourseNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save survey: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, saved)
+}
+
+// @Summary Delete course survey
+// @Description Deletes the course survey together with its responses (administrators and instructors)
+// @Tags Surveys
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/survey [delete]
+func DeleteCourseSurvey(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can manage surveys"); !ok {
+		return
+	}
+	courseID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid course ID"})
+		return
+	}
+	err = Store.DeleteCourseSurvey(courseID)
+	if errors.Is(err, storage.ErrSurveyNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Survey not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete survey: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Survey deleted")})
+}
+
+// @Summary Submit survey response
+// @Description Submits answers to the course survey. Available once all course tasks are completed, once per user. Likert answers take a rating from 1 to 5, text answers a text. Responses to an anonymous survey are stored without the user.
+// @Tags Surveys
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param request body models.SubmitSurveyRequest true "Answers"
+// @Success 201 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/survey/responses [post]
+func SubmitSurveyResponse(c *gin.Context) {
+	survey, ok := loadSurvey(c)
+	if !ok {
+		return
+	}
+	if !survey.Active {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Survey not found"})
+		return
+	}
+	var req models.SubmitSurveyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	course, ok := loadCourse(c, survey.CourseID)
+	if !ok {
+		return
+	}
+	completed, err := courseCompleted(userID, course)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to submit survey: " + err.Error()})
+		return
+	}
+	if !completed {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Complete the course to take its survey"})
+		return
+	}
+	answers, msg := validateSurveyAnswers(survey, req.Answers)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: msg})
+		return
+	}
+
+	err = Store.SubmitSurveyResponse(survey, userID, answers)
+	if errors.Is(err, storage.ErrSurveyAlreadyAnswered) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "You have already answered this survey"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to submit survey: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, models.SuccessResponse{Message: Translate(c, "Thank you for your feedback")})
+}
+
+// @Summary Get survey results
+// @Description Aggregated survey results (administrators and instructors): average and distribution of ratings for Likert questions, the list of answers for text questions. Respondents are shown only for non-anonymous surveys.
+// @Tags Surveys
+// @Produce json
+// @Param id path int true "Course ID"
+// @Success 200 {object} models.SurveyResults
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/survey/results [get]
+func GetSurveyResults(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can view survey results"); !ok {
+		return
+	}
+	survey, ok := loadSurvey(c)
+	if !ok {
+		return
+	}
+	responses, err := Store.ListSurveyResponses(survey.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get survey results: " + err.Error()})
+		return
+	}
+
+	results := models.SurveyResults{
+		SurveyID:      survey.ID,
+		CourseID:      survey.CourseID,
+		Title:         survey.Title,
+		Anonymous:     survey.Anonymous,
+		ResponseCount: len(responses),
+		Questions:     make([]models.SurveyQuestionResult, len(survey.Questions)),
+	}
+	index := map[int]int{}
+	sums := make([]int, len(survey.Questions))
+	for i, question := range survey.Questions {
+		index[question.ID] = i
+		results.Questions[i].SurveyQuestion = question
+		if question.Kind == models.SurveyLikert {
+			results.Questions[i].Distribution = make([]int, models.SurveyScale)
+		} else {
+			results.Questions[i].Answers = []models.SurveyTextAnswer{}
+		}
+	}
+	for _, response := range responses {
+		for _, answer := range response.Answers {
+			i, ok := index[answer.QuestionID]
+			if !ok {
+				continue
+			}
+			result := &results.Questions[i]
+			result.AnswerCount++
+			if answer.Rating != nil && result.Distribution != nil {
+				result.Distribution[*answer.Rating-1]++
+				sums[i] += *answer.Rating
+				continue
+			}
+			text := models.SurveyTextAnswer{Text: answer.Text, CreatedAt: response.CreatedAt}
+			if !survey.Anonymous {
+				text.UserID = response.UserID
+			}
+			result.Answers = append(result.Answers, text)
+		}
+	}
+	for i := range results.Questions {
+		if result := &results.Questions[i]; result.Distribution != nil && result.AnswerCount > 0 {
+			average := math.Round(float64(sums[i])/float64(result.AnswerCount)*100) / 100
+			result.Average = &average
+		}
+	}
+	c.JSON(http.StatusOK, results)
+}
+
+// @Summary List pending surveys
+// @Description Surveys of courses the current user has completed but not yet answered
+// @Tags Surveys
+// @Produce json
+// @Success 200 {array} models.Survey
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /surveys/pending [get]
+func ListPendingSurveys(c *gin.Context) {
+	userID := c.GetInt("userID")
+	courseIDs, err := Store.GetStartedCourseIDs(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list surveys: " + err.Error()})
+		return
+	}
+
+	surveys := []models.Survey{}
+	for _, courseID := range courseIDs {
+		survey, err := Store.GetCourseSurvey(courseID)
+		if errors.Is(err, storage.ErrSurveyNotFound) {
+			continue
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list surveys: " + err.Error()})
+			return
+		}
+		offered, err := surveyOffered(userID, survey)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list surveys: " + err.Error()})
+			return
+		}
+		if offered {
+			surveys = append(surveys, survey)
+		}
+	}
+	c.JSON(http.StatusOK, surveys)
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+// visibleCourseTask загружает задание курса из пути запроса; курс должен быть виден пользователю
+func visibleCourseTask(c *gin.Context) (models.Course, models.Task, bool) {
+	taskID, err := strconv.Atoi(c.Param("task_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+		return models.Course{}, models.Task{}, false
+	}
+	course, ok := visibleCourse(c)
+	if !ok {
+		return course, models.Task{}, false
+	}
+	for _, task := range course.Tasks {
+		if task.ID == taskID {
+			return course, task, true
+		}
+	}
+	c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Task not found"})
+	return course, models.Task{}, false
+}
+
+// localizeNoteTitles подставляет в заметки названия заданий на языке locale
+func localizeNoteTitles(c *gin.Context, notes []models.TaskNote) error {
+	courseIDs := make([]int, 0, len(notes))
+	for _, note := range notes {
+		courseIDs = append(courseIDs, note.CourseID)
+	}
+	courses, err := StoreFor(c).GetCoursesByIDs(courseIDs)
+	if err != nil {
+		return err
+	}
+	titles := map[int]string{}
+	for _, course := range courses {
+		if err := LocalizeCourse(RequestLocale(c), &course); err != nil {
+			return err
+		}
+		for _, task := range course.Tasks {
+			titles[task.ID] = task.Title
+		}
+	}
+	for i := range notes {
+		if title, ok := titles[notes[i].TaskID]; ok {
+			notes[i].TaskTitle = title
+		}
+	}
+	return nil
+}
+
+// @Summary List my task notes
+// @Description Private Markdown notes of the current user on tasks, most recently edited first
+// @Tags Notes
+// @Produce json
+// @Success 200 {array} models.TaskNote
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /notes [get]
+func ListTaskNotes(c *gin.Context) {
+	notes, err := Store.ListTaskNotes(c.GetInt("userID"))
+	if err == nil {
+		err = localizeNoteTitles(c, notes)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list notes: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, notes)
+}
+
+// @Summary Get my note on a task
+// @Tags Notes
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} models.TaskNote
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "No such task or no note yet"
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/tasks/{task_id}/note [get]
+func GetTaskNote(c *gin.Context) {
+	_, task, ok := visibleCourseTask(c)
+	if !ok {
+		return
+	}
+	note, err := Store.GetTaskNote(c.GetInt("userID"), task.ID)
+	if errors.Is(err, storage.ErrNoteNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Note not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get note: " + err.Error()})
+		return
+	}
+	note.TaskTitle = task.Title
+	c.JSON(http.StatusOK, note)
+}
+
+// @Summary Save my note on a task
+// @Description Autosave endpoint: creates or replaces the private Markdown note. Pass the version the edit started from to avoid overwriting a newer text saved from another window; version 0 saves unconditionally.
+// @Tags Notes
+// @Accept json
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param task_id path int true "Task ID"
+// @Param request body models.SaveTaskNoteRequest true "Note"
+// @Success 200 {object} models.TaskNote
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "The note was changed in another window"
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/tasks/{task_id}/note [put]
+func SaveTaskNote(c *gin.Context) {
+	_, task, ok := visibleCourseTask(c)
+	if !ok {
+		return
+	}
+	var req models.SaveTaskNoteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	note, err := Store.SaveTaskNote(c.GetInt("userID"), task.ID, req.Body, req.Version)
+	switch {
+	case errors.Is(err, storage.ErrNoteConflict):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Note was changed in another window, reload it and retry"})
+		return
+	case errors.Is(err, storage.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Task not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save note: " + err.Error()})
+		return
+	}
+	note.TaskTitle = task.Title
+	c.JSON(http.StatusOK, note)
+}
+
+// @Summary Delete my note on a task
+// @Tags Notes
+// @Produce json
+// @Param id path int true "Course ID"
+// @Param task_id path int true "Task ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /courses/{id}/tasks/{task_id}/note [delete]
+func DeleteTaskNote(c *gin.Context) {
+	taskID, err := strconv.Atoi(c.Param("task_id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid task ID"})
+		return
+	}
+	err = Store.DeleteTaskNote(c.GetInt("userID"), taskID)
+	if errors.Is(err, storage.ErrNoteNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Note not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete note: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Note deleted")})
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+
+	"github.com/gin-gonic/gin"
+)
+
+// groupLeaderboardSize — сколько участников показывает таблица лидеров группы
+const groupLeaderboardSize = 50
+
+// challengeStatus возвращает этап соревнования на момент now
+func challengeStatus(challenge models.TeamChallenge, now time.Time) string {
+	switch {
+	case now.Before(challenge.StartsAt):
+		return models.ChallengeStatusUpcoming
+	case now.Before(challenge.EndsAt):
+		return models.ChallengeStatusActive
+	default:
+		return models.ChallengeStatusFinished
+	}
+}
+
+// teamStandings считает очки групп по числу заданий, выполненных каждым участником. При подсчете
+// best_of очки дают только bestOf лучших участников, поэтому большие группы не получают преимущества.
+func teamStandings(challenge models.TeamChallenge, completions map[string][]int) []models.TeamStanding {
+	teams := make([]models.TeamStanding, 0, len(challenge.Groups))
+	for _, group := range challenge.Groups {
+		counts := append([]int(nil), completions[group]...)
+		sort.Sort(sort.Reverse(sort.IntSlice(counts)))
+		team := models.TeamStanding{Group: group, Members: len(counts)}
+		for i, completed := range counts {
+			if completed > 0 {
+				team.Participants++
+			}
+			if challenge.Scoring == models.ChallengeScoringSum || i < challenge.BestOf {
+				team.Score += completed
+			}
+		}
+		teams = append(teams, team)
+	}
+
+	sort.SliceStable(teams, func(i, j int) bool {
+		if teams[i].Score != teams[j].Score {
+			return teams[i].Score > teams[j].Score
+		}
+		return teams[i].Group < teams[j].Group
+	})
+	for i := range teams {
+		if i > 0 && teams[i].Score == teams[i-1].Score {
+			teams[i].Rank = teams[i-1].Rank
+		} else {
+			teams[i].Rank = i + 1
+		}
+	}
+	return teams
+}
+
+// teamChallengeFromRequest проверяет запрос и собирает из него соревнование
+func teamChallengeFromRequest(c *gin.Context) (models.TeamChallenge, bool) {
+	var req models.SaveTeamChallengeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return models.TeamChallenge{}, false
+	}
+	if !req.EndsAt.After(req.StartsAt) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "endsAt must be after startsAt"})
+		return models.TeamChallenge{}, false
+	}
+	if req.Scoring == models.ChallengeScoringBestOf && req.BestOf == 0 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "bestOf is required for best_of scoring"})
+		return models.TeamChallenge{}, false
+	}
+	if req.Scoring == models.ChallengeScoringSum {
+		req.BestOf = 0
+	}
+
+	groups := []string{}
+	seen := map[string]bool{}
+	for _, group := range req.Groups {
+		group = strings.TrimSpace(group)
+		if group != "" && !seen[group] {
+			seen[group] = true
+			groups = append(groups, group)
+		}
+	}
+	if len(groups) < 2 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "At least two different groups are required"})
+		return models.TeamChallenge{}, false
+	}
+
+	return models.TeamChallenge{
+		Title:       strings.TrimSpace(req.Title),
+		Description: req.Description,
+		Scoring:     req.Scoring,
+		BestOf:      req.BestOf,
+		Groups:      groups,
+		StartsAt:    req.StartsAt.UTC(),
+		EndsAt:      req.EndsAt.UTC(),
+	}, true
+}
+
+// respondTeamChallengeError отвечает на ошибку сохранения соревнования
+func respondTeamChallengeError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, storage.ErrTeamChallengeNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Team challenge not found"})
+	case errors.Is(err, storage.ErrGroupNotFound):
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Group not found"})
+	default:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save team challenge: " + err.Error()})
+	}
+}
+
+// @Summary Get group leaderboard
+// @Description Members of the group ranked by tasks completed during the last days. Available to group members and administrators.
+// @Tags Leaderboards
+// @Produce json
+// @Security BearerAuth
+// @Param group path string true "Group name"
+// @Param days query int false "Period in days (1-365)" default(7)
+// @Success 200 {object} models.GroupLeaderboard
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /groups/{group}/leaderboard [get]
+func GetGroupLeaderboard(c *gin.Context) {
+	days, err := strconv.Atoi(c.DefaultQuery("days", "7"))
+	if err != nil || days < 1 || days > 365 {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid days"})
+		return
+	}
+	group := c.Param("group")
+	userID := c.GetInt("userID")
+
+	member, err := StoreFor(c).IsGroupMember(group, userID)
+	if errors.Is(err, storage.ErrGroupNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Group not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get leaderboard: " + err.Error()})
+		return
+	}
+	if !member {
+		if isAdmin, err := RequestAdminRights(c); err != nil || !isAdmin {
+			c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only group members can view this leaderboard"})
+			return
+		}
+	}
+
+	to := time.Now().UTC()
+	from := to.AddDate(0, 0, -days)
+	entries, err := StoreFor(c).GroupLeaderboard(group, from, to, groupLeaderboardSize)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get leaderboard: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.GroupLeaderboard{Group: group, From: from, To: to, Entries: entries})
+}
+
+// @Summary List team challenges
+// @Description Team challenges between groups, latest first, with their current status.
+// @Tags Leaderboards
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TeamChallenge
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /challenges [get]
+func ListTeamChallenges(c *gin.Context) {
+	challenges, err := Store.ListTeamChallenges()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get team challenges: " + err.Error()})
+		return
+	}
+	now := time.Now()
+	for i := range challenges {
+		challenges[i].Status = challengeStatus(challenges[i], now)
+	}
+	c.JSON(http.StatusOK, challenges)
+}
+
+// @Summary Get team challenge standings
+// @Description Groups ranked by score: tasks completed by their members between startsAt and endsAt. With sum scoring every member counts; with best_of only the bestOf top members of each group count. Groups with equal scores share a rank. Standings of a finished challenge no longer change.
+// @Tags Leaderboards
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Team challenge ID"
+// @Success 200 {object} models.TeamChallengeStandings
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /challenges/{id} [get]
+func GetTeamChallengeStandings(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid team challenge ID"})
+		return
+	}
+	challenge, err := Store.GetTeamChallenge(id)
+	if errors.Is(err, storage.ErrTeamChallengeNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Team challenge not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get team challenge: " + err.Error()})
+		return
+	}
+	challenge.Status = challengeStatus(challenge, time.Now())
+
+	completions := map[string][]int{}
+	if challenge.Status != models.ChallengeStatusUpcoming {
+		if completions, err = Store.TeamChallengeCompletions(challenge); err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get team challenge: " + err.Error()})
+			return
+		}
+	}
+	userGroups, err := Store.GetUserGroups(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get team challenge: " + err.Error()})
+		return
+	}
+
+	standings := models.TeamChallengeStandings{Challenge: challenge, Teams: teamStandings(challenge, completions), MyGroups: []string{}}
+	for _, group := range userGroups {
+		for _, participant := range challenge.Groups {
+			if group == participant {
+				standings.MyGroups = append(standings.MyGroups, group)
+			}
+		}
+	}
+	c.JSON(http.StatusOK, standings)
+}
+
+// @Summary Create team challenge
+// @Description Creates a time-boxed competition between at least two groups (for example departments during Security Awareness Month). Scoring is sum (all tasks completed by group members) or best_of (tasks of the bestOf top members of each group).
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SaveTeamChallengeRequest true "Team challenge"
+// @Success 201 {object} models.TeamChallenge
+// @Failure 400 {object} models.ErrorResponse "Invalid data or unknown group"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/challenges [post]
+func CreateTeamChallenge(c *gin.Context) {
+	challenge, ok := teamChallengeFromRequest(c)
+	if !ok {
+		return
+	}
+	challenge.CreatedBy = c.GetInt("userID")
+	challenge, err := Store.CreateTeamChallenge(challenge)
+	if err != nil {
+		respondTeamChallengeError(c, err)
+		return
+	}
+	challenge.Status = challengeStatus(challenge, time.Now())
+	c.JSON(http.StatusCreated, challenge)
+}
+
+// @Summary Update team challenge
+// @Description Replaces the settings and groups of a team challenge. Standings are recomputed from the new interval and scoring.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Team challenge ID"
+// @Param request body models.SaveTeamChallengeRequest true "Team challenge"
+// @Success 200 {object} models.TeamChallenge
+// @Failure 400 {object} models.ErrorResponse "Invalid data or unknown group"
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/challenges/{id} [put]
+func UpdateTeamChallenge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid team challenge ID"})
+		return
+	}
+	challenge, ok := teamChallengeFromRequest(c)
+	if !ok {
+		return
+	}
+	challenge.ID = id
+	challenge, err = Store.UpdateTeamChallenge(challenge)
+	if err != nil {
+		respondTeamChallengeError(c, err)
+		return
+	}
+	challenge.Status = challengeStatus(challenge, time.Now())
+	c.JSON(http.StatusOK, challenge)
+}
+
+// @Summary Delete team challenge
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Team challenge ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/challenges/{id} [delete]
+func DeleteTeamChallenge(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid team challenge ID"})
+		return
+	}
+	err = Store.DeleteTeamChallenge(id)
+	if errors.Is(err, storage.ErrTeamChallengeNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Team challenge not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete team challenge: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Team challenge deleted")})
+}
@@ -0,0 +1,506 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/i18n"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+	"lmsmodule/backend-svc/telegram"
+
+	"github.com/gin-gonic/gin"
+)
+
+// telegramLinkTTL — срок действия ссылки привязки аккаунта
+const telegramLinkTTL = 15 * time.Minute
+
+var (
+	telegramMu   sync.RWMutex
+	telegramBot  *telegram.Client
+	telegramConf config.TelegramConfig
+	telegramHook string
+)
+
+// ConfigureTelegram задает бота Telegram; пустой токен отключает бота. Безопасно вызывать
+// повторно при ротации секретов. Webhook регистрирует RegisterTelegramWebhook.
+func ConfigureTelegram(cfg config.TelegramConfig, publicURL string) {
+	telegramMu.Lock()
+	defer telegramMu.Unlock()
+	telegramConf = cfg
+	telegramBot = nil
+	if cfg.BotToken != "" {
+		telegramBot = telegram.New(cfg.APIURL, cfg.BotToken)
+	}
+	telegramHook = strings.TrimSuffix(publicURL, "/") + "/api/telegram/webhook"
+}
+
+func currentTelegram() (*telegram.Client, config.TelegramConfig) {
+	telegramMu.RLock()
+	defer telegramMu.RUnlock()
+	return telegramBot, telegramConf
+}
+
+// RegisterTelegramWebhook направляет обновления бота на сервис и задает меню команд
+func RegisterTelegramWebhook(ctx context.Context) error {
+	telegramMu.RLock()
+	bot, cfg, hook := telegramBot, telegramConf, telegramHook
+	telegramMu.RUnlock()
+	if bot == nil {
+		return nil
+	}
+	if err := bot.SetWebhook(ctx, hook, cfg.WebhookSecret); err != nil {
+		return err
+	}
+	accounts := currentAccountsConfig()
+	// Меню на языке по умолчанию показывается пользователям Telegram с неподдерживаемым языком
+	if err := bot.SetCommands(ctx, telegramCommands(accounts.DefaultLocale), ""); err != nil {
+		return err
+	}
+	for _, locale := range accounts.SupportedLocales {
+		if err := bot.SetCommands(ctx, telegramCommands(locale), locale); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func telegramCommands(locale string) []telegram.Command {
+	return []telegram.Command{
+		{Command: "progress", Description: i18n.Translate(locale, "My progress")},
+		{Command: "deadline", Description: i18n.Translate(locale, "Next deadline")},
+		{Command: "stop", Description: i18n.Translate(locale, "Unlink the account and stop notifications")},
+		{Command: "help", Description: i18n.Translate(locale, "What the bot can do")},
+	}
+}
+
+// notifyTelegram отправляет пользователю сообщение, если он привязал Telegram. Чат, в котором
+// бот заблокирован, отвязывается.
+func notifyTelegram(userID int, message func(user models.User) string) {
+	bot, _ := currentTelegram()
+	if bot == nil {
+		return
+	}
+	account, err := Store.GetTelegramAccount(userID)
+	if errors.Is(err, storage.ErrTelegramAccountNotFound) {
+		return
+	}
+	user, err2 := Store.GetUserByID(userID)
+	if err == nil {
+		err = err2
+	}
+	if err != nil {
+		log.Printf("Failed to prepare Telegram notification for user %d: %v", userID, err)
+		return
+	}
+	sendTelegram(bot, account, message(user))
+}
+
+func sendTelegram(bot *telegram.Client, account models.TelegramAccount, text string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	err := bot.SendMessage(ctx, account.ChatID, text)
+	if errors.Is(err, telegram.ErrBlocked) && account.UserID != 0 {
+		err = Store.DeleteTelegramAccount(account.UserID)
+		if err == nil || errors.Is(err, storage.ErrTelegramAccountNotFound) {
+			log.Printf("Telegram chat of user %d unlinked: the bot was blocked", account.UserID)
+			return
+		}
+	}
+	if err != nil {
+		log.Printf("Failed to send Telegram message to user %d: %v", account.UserID, err)
+	}
+}
+
+// notifyTasksGraded сообщает в Telegram о засчитанных заданиях
+func notifyTasksGraded(userID int, taskIDs []int) {
+	if len(taskIDs) == 0 {
+		return
+	}
+	if bot, _ := currentTelegram(); bot == nil {
+		return
+	}
+	go notifyTelegram(userID, func(user models.User) string {
+		locale := UserLocale(user.Locale)
+		titles, err := telegramTaskTitles(userID, taskIDs, locale)
+		if err != nil {
+			log.Printf("Failed to get task titles for Telegram notification: %v", err)
+		}
+		var text strings.Builder
+		text.WriteString(i18n.Translate(locale, "Tasks graded:"))
+		for _, taskID := range taskIDs {
+			title := titles[taskID]
+			if title == "" {
+				title = fmt.Sprintf("#%d", taskID)
+			}
+			text.WriteString("\n✅ " + title)
+		}
+		return text.String()
+	})
+}
+
+// telegramTaskTitles возвращает названия заданий taskIDs на языке locale
+func telegramTaskTitles(userID int, taskIDs []int, locale string) (map[int]string, error) {
+	courseIDs, err := completedTaskCourses(userID, taskIDs)
+	if err != nil {
+		return nil, err
+	}
+	titles := map[int]string{}
+	for courseID := range courseIDs {
+		course, err := Store.GetCourseByID(courseID)
+		if err != nil {
+			return titles, err
+		}
+		if err := LocalizeCourse(locale, &course); err != nil {
+			return titles, err
+		}
+		for _, task := range course.Tasks {
+			titles[task.ID] = task.Title
+		}
+	}
+	return titles, nil
+}
+
+// SendTelegramDeadlineReminders напоминает о заданиях со сроком сдачи в ближайшие
+// telegram.deadline_reminder. О каждом сроке напоминание отправляется один раз.
+func SendTelegramDeadlineReminders() error {
+	bot, cfg := currentTelegram()
+	if bot == nil {
+		return nil
+	}
+	accounts, err := Store.ListTelegramAccounts()
+	if err != nil {
+		return err
+	}
+	userIDs := make([]int, len(accounts))
+	for i, account := range accounts {
+		userIDs[i] = account.UserID
+	}
+	users, err := Store.GetUsersByIDs(userIDs)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, account := range accounts {
+		user, ok := users[account.UserID]
+		if !ok {
+			continue
+		}
+		deadlines, err := upcomingDeadlines(user, now, now.Add(cfg.DeadlineReminder))
+		if err != nil {
+			return err
+		}
+		locale := UserLocale(user.Locale)
+		for _, deadline := range deadlines {
+			marked, err := Store.MarkTelegramDeadlineReminder(user.ID, deadline.TaskID, *deadline.DueAt)
+			if err != nil {
+				return err
+			}
+			if marked {
+				sendTelegram(bot, account, i18n.Sprintf(locale, "⏰ Deadline %s: %s (%s)",
+					formatTelegramTime(*deadline.DueAt, user), deadline.TaskTitle, deadline.CourseTitle))
+			}
+		}
+	}
+	return nil
+}
+
+type telegramDeadline struct {
+	models.TaskSchedule
+	CourseTitle string
+}
+
+// upcomingDeadlines возвращает невыполненные задания начатых пользователем курсов со сроком
+// сдачи в интервале [from, to), ближайшие первыми. Названия переведены на язык пользователя.
+func upcomingDeadlines(user models.User, from, to time.Time) ([]telegramDeadline, error) {
+	courseIDs, err := Store.GetStartedCourseIDs(user.ID)
+	if err != nil || len(courseIDs) == 0 {
+		return nil, err
+	}
+	schedules, err := Store.ListTaskSchedules(courseIDs, from, to)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := Store.GetUserProgress(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	locale := UserLocale(user.Locale)
+	titles, err := localizedTaskTitles(locale, schedules)
+	if err != nil {
+		return nil, err
+	}
+	courseTitles, err := localizedCourseTitles(locale, schedules)
+	if err != nil {
+		return nil, err
+	}
+	deadlines := []telegramDeadline{}
+	for _, schedule := range schedules {
+		if schedule.DueAt == nil || schedule.DueAt.Before(from) || !schedule.DueAt.Before(to) || progress.Completed[schedule.TaskID] {
+			continue
+		}
+		schedule.TaskTitle = titles[schedule.TaskID]
+		deadlines = append(deadlines, telegramDeadline{TaskSchedule: schedule, CourseTitle: courseTitles[schedule.CourseID]})
+	}
+	sort.SliceStable(deadlines, func(i, j int) bool { return deadlines[i].DueAt.Before(*deadlines[j].DueAt) })
+	return deadlines, nil
+}
+
+// formatTelegramTime записывает время в часовом поясе пользователя, например "2025-03-01 18:30 MSK"
+func formatTelegramTime(t time.Time, user models.User) string {
+	return t.In(userLocation(user.Timezone)).Format("2006-01-02 15:04 MST")
+}
+
+// @Summary Telegram integration status
+// @Description Whether the Telegram bot is configured and whether the current user has linked a chat
+// @Tags Telegram
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TelegramStatus
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /telegram [get]
+func GetTelegramStatus(c *gin.Context) {
+	bot, _ := currentTelegram()
+	status := models.TelegramStatus{Enabled: bot != nil}
+	account, err := Store.GetTelegramAccount(c.GetInt("userID"))
+	switch {
+	case err == nil:
+		status.Linked, status.Account = true, &account
+	case !errors.Is(err, storage.ErrTelegramAccountNotFound):
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get Telegram account: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// @Summary Create Telegram link
+// @Description Returns a one-time t.me link valid for 15 minutes. Opening it and pressing Start in the bot links the chat to the current user; a chat already linked to another account moves to this one. A new link replaces the previous one.
+// @Tags Telegram
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.TelegramLink
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "The bot is not configured"
+// @Router /telegram/link [post]
+func CreateTelegramLink(c *gin.Context) {
+	bot, cfg := currentTelegram()
+	if bot == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Telegram bot is not configured"})
+		return
+	}
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create Telegram link: " + err.Error()})
+		return
+	}
+	code := base64.RawURLEncoding.EncodeToString(raw)
+	expiresAt := time.Now().Add(telegramLinkTTL).UTC().Truncate(time.Second)
+	if err := Store.SetTelegramLinkCode(c.GetInt("userID"), hashToken(code), expiresAt); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create Telegram link: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.TelegramLink{
+		URL:       "https://t.me/" + strings.TrimPrefix(cfg.BotUsername, "@") + "?start=" + code,
+		ExpiresAt: expiresAt,
+	})
+}
+
+// @Summary Unlink Telegram
+// @Description Unlinks the current user's Telegram chat; the bot stops sending notifications
+// @Tags Telegram
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /telegram [delete]
+func UnlinkTelegram(c *gin.Context) {
+	err := Store.DeleteTelegramAccount(c.GetInt("userID"))
+	if errors.Is(err, storage.ErrTelegramAccountNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Telegram account is not linked"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to unlink Telegram account: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Telegram account unlinked")})
+}
+
+// @Summary Telegram webhook
+// @Description Receives bot updates from Telegram. Requests must carry the configured secret in X-Telegram-Bot-Api-Secret-Token.
+// @Tags Telegram
+// @Accept json
+// @Param X-Telegram-Bot-Api-Secret-Token header string true "Webhook secret"
+// @Success 200
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "The bot is not configured"
+// @Router /telegram/webhook [post]
+func TelegramWebhook(c *gin.Context) {
+	bot, cfg := currentTelegram()
+	if bot == nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Telegram bot is not configured"})
+		return
+	}
+	secret := c.GetHeader("X-Telegram-Bot-Api-Secret-Token")
+	if subtle.ConstantTimeCompare([]byte(secret), []byte(cfg.WebhookSecret)) != 1 {
+		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Invalid webhook secret"})
+		return
+	}
+	var update telegram.Update
+	if err := c.ShouldBindJSON(&update); err != nil {
+		// Telegram повторяет недоставленные обновления; некорректное повторять бессмысленно
+		c.Status(http.StatusOK)
+		return
+	}
+	if msg := update.Message; msg != nil && msg.Chat.Type == "private" && msg.Text != "" {
+		handleTelegramMessage(bot, msg)
+	}
+	c.Status(http.StatusOK)
+}
+
+// handleTelegramMessage выполняет команду из личного чата с ботом
+func handleTelegramMessage(bot *telegram.Client, msg *telegram.Message) {
+	command, arg, _ := strings.Cut(strings.TrimSpace(msg.Text), " ")
+	command, _, _ = strings.Cut(strings.ToLower(command), "@") // /progress@lms_bot в меню команд
+	arg = strings.TrimSpace(arg)
+
+	chat := models.TelegramAccount{ChatID: msg.Chat.ID}
+	if msg.From != nil {
+		chat.Username = msg.From.Username
+	}
+	account, err := Store.GetTelegramAccountByChat(msg.Chat.ID)
+	linked := err == nil
+	if err != nil && !errors.Is(err, storage.ErrTelegramAccountNotFound) {
+		log.Printf("Failed to get Telegram chat %d: %v", msg.Chat.ID, err)
+		return
+	}
+	var user models.User
+	if linked {
+		if user, err = Store.GetUserByID(account.UserID); err != nil {
+			log.Printf("Failed to get user %d for Telegram: %v", account.UserID, err)
+			return
+		}
+	}
+	locale := UserLocale(user.Locale)
+	if !linked && msg.From != nil {
+		locale = UserLocale(i18n.Match(msg.From.LanguageCode, currentAccountsConfig().SupportedLocales))
+	}
+	reply := func(text string) { sendTelegram(bot, chat, text) }
+	chat.UserID = account.UserID
+
+	switch {
+	case command == "/start" && arg != "":
+		account, err := Store.LinkTelegramAccount(hashToken(arg), chat)
+		if errors.Is(err, storage.ErrTelegramLinkCodeInvalid) {
+			reply(i18n.Translate(locale, "This link is invalid or has expired. Open the Telegram settings in your LMS profile to get a new one."))
+			return
+		}
+		if err == nil {
+			user, err = Store.GetUserByID(account.UserID)
+		}
+		if err != nil {
+			log.Printf("Failed to link Telegram chat %d: %v", msg.Chat.ID, err)
+			reply(i18n.Translate(locale, "Something went wrong. Please try again later."))
+			return
+		}
+		chat.UserID = account.UserID
+		locale = UserLocale(user.Locale)
+		reply(i18n.Sprintf(locale, "Your LMS account %s is linked. You will get deadline reminders and grading notifications here.", user.Username) +
+			"\n\n" + telegramHelp(locale))
+	case !linked:
+		reply(i18n.Translate(locale, "This chat is not linked to an LMS account. Open the Telegram settings in your LMS profile and follow the link."))
+	case command == "/progress":
+		reply(telegramProgress(user, locale))
+	case command == "/deadline":
+		reply(telegramNextDeadline(user, locale))
+	case command == "/stop":
+		if err := Store.DeleteTelegramAccount(user.ID); err != nil && !errors.Is(err, storage.ErrTelegramAccountNotFound) {
+			log.Printf("Failed to unlink Telegram chat of user %d: %v", user.ID, err)
+			reply(i18n.Translate(locale, "Something went wrong. Please try again later."))
+			return
+		}
+		reply(i18n.Translate(locale, "The chat is unlinked, notifications are turned off. You can link it again from your LMS profile."))
+	default:
+		reply(telegramHelp(locale))
+	}
+}
+
+func telegramHelp(locale string) string {
+	lines := make([]string, 0, 4)
+	for _, command := range telegramCommands(locale) {
+		lines = append(lines, "/"+command.Command+" — "+command.Description)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// telegramProgress — ответ на /progress: выполненные задания начатых курсов
+func telegramProgress(user models.User, locale string) string {
+	failed := i18n.Translate(locale, "Something went wrong. Please try again later.")
+	courseIDs, err := Store.GetStartedCourseIDs(user.ID)
+	if err != nil {
+		log.Printf("Failed to get started courses of user %d: %v", user.ID, err)
+		return failed
+	}
+	if len(courseIDs) == 0 {
+		return i18n.Translate(locale, "You haven't started any course yet.")
+	}
+	progress, err := Store.GetUserProgress(user.ID)
+	if err != nil {
+		log.Printf("Failed to get progress of user %d: %v", user.ID, err)
+		return failed
+	}
+	lines := []string{i18n.Translate(locale, "Your progress:")}
+	for _, courseID := range courseIDs {
+		course, err := Store.GetCourseByID(courseID)
+		if err == nil {
+			err = LocalizeCourse(locale, &course)
+		}
+		if err != nil {
+			log.Printf("Failed to get course %d: %v", courseID, err)
+			return failed
+		}
+		done := 0
+		for _, task := range course.Tasks {
+			if progress.Completed[task.ID] {
+				done++
+			}
+		}
+		mark := "▫️"
+		if done == len(course.Tasks) && done > 0 {
+			mark = "✅"
+		}
+		lines = append(lines, i18n.Sprintf(locale, "%s %s: %d of %d tasks", mark, course.VulnerabilityType, done, len(course.Tasks)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// telegramNextDeadline — ответ на /deadline: ближайший срок невыполненного задания
+func telegramNextDeadline(user models.User, locale string) string {
+	now := time.Now()
+	deadlines, err := upcomingDeadlines(user, now, now.Add(maxCalendarRange))
+	if err != nil {
+		log.Printf("Failed to get deadlines of user %d: %v", user.ID, err)
+		return i18n.Translate(locale, "Something went wrong. Please try again later.")
+	}
+	if len(deadlines) == 0 {
+		return i18n.Translate(locale, "No upcoming deadlines.")
+	}
+	next := deadlines[0]
+	return i18n.Sprintf(locale, "Next deadline %s: %s (%s)", formatTelegramTime(*next.DueAt, user), next.TaskTitle, next.CourseTitle)
+}
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/storage"
+)
+
+const (
+	defaultTicketLimit = 50
+	maxTicketLimit     = 200
+)
+
+func validTicketStatus(status string) bool {
+	for _, s := range models.TicketStatuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
+
+// notifyTicket отправляет событие обращения пользователю через WebSocket
+func notifyTicket(userID *int, msgType string, data interface{}) {
+	if Realtime == nil || userID == nil {
+		return
+	}
+	Realtime.Broadcast(realtime.UserTopic(*userID), msgType, data)
+}
+
+// ticketAccess загружает обращение из пути запроса. Доступ есть у автора обращения
+// и у преподавателей (isStaff); для остальных обращение не существует.
+func ticketAccess(c *gin.Context) (ticket models.Ticket, isStaff, ok bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid ticket ID"})
+		return ticket, false, false
+	}
+	ticket, err = Store.GetTicket(id)
+	if errors.Is(err, storage.ErrTicketNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Ticket not found"})
+		return ticket, false, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get ticket: " + err.Error()})
+		return ticket, false, false
+	}
+
+	_, isStaff, err = requestInstructorRights(c)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+		return ticket, false, false
+	}
+	if !isStaff && ticket.UserID != c.GetInt("userID") {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Ticket not found"})
+		return ticket, false, false
+	}
+	return ticket, isStaff, true
+}
+
+// @Summary Open support ticket
+// @Description Opens a ticket about a course, task or lab environment. taskId links the failing task (its course is filled in automatically), labSessionId the lab environment where the problem occurred.
+// @Tags Support
+// @Accept json
+// @Produce json
+// @Param request body models.CreateTicketRequest true "Ticket"
+// @Success 201 {object} models.Ticket
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /tickets [post]
+func CreateTicket(c *gin.Context) {
+	var req models.CreateTicketRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	ticket, err := Store.CreateTicket(models.Ticket{
+		UserID:       userID,
+		Subject:      req.Subject,
+		CourseID:     req.CourseID,
+		TaskID:       req.TaskID,
+		LabSessionID: req.LabSessionID,
+	}, req.Body)
+	switch {
+	case errors.Is(err, storage.ErrTaskNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Task not found"})
+		return
+	case errors.Is(err, storage.ErrCourseNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Course not found"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to create ticket: " + err.Error()})
+		return
+	}
+	log.Printf("User %d opened ticket %d", userID, ticket.ID)
+	c.JSON(http.StatusCreated, ticket)
+}
+
+// @Summary List tickets
+// @Description Without scope, returns the current user's tickets. Instructors and administrators can pass scope=all for the whole queue or scope=assigned for tickets assigned to them.
+// @Tags Support
+// @Produce json
+// @Param scope query string false "all or assigned (instructors only)"
+// @Param status query string false "open, in_progress, waiting, resolved or closed"
+// @Param limit query int false "Maximum number of tickets (default 50, max 200)"
+// @Success 200 {array} models.Ticket
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /tickets [get]
+func ListTickets(c *gin.Context) {
+	userID := c.GetInt("userID")
+	filter := models.TicketFilter{Status: c.Query("status"), Limit: defaultTicketLimit}
+	if filter.Status != "" && !validTicketStatus(filter.Status) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unknown ticket status"})
+		return
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > maxTicketLimit {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "limit must be between 1 and 200"})
+			return
+		}
+		filter.Limit = limit
+	}
+
+	switch c.Query("scope") {
+	case "":
+		filter.UserID = userID
+	case "all", "assigned":
+		if _, _, ok := requireInstructor(c, "Only administrators and instructors can view the ticket queue"); !ok {
+			return
+		}
+		if c.Query("scope") == "assigned" {
+			filter.AssigneeID = userID
+		}
+	default:
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "scope must be all or assigned"})
+		return
+	}
+
+	tickets, err := Store.ListTickets(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list tickets: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, tickets)
+}
+
+// @Summary Get ticket
+// @Description Ticket with its conversation; internal notes are returned to instructors and administrators only
+// @Tags Support
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Success 200 {object} models.TicketDetails
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /tickets/{id} [get]
+func GetTicket(c *gin.Context) {
+	ticket, isStaff, ok := ticketAccess(c)
+	if !ok {
+		return
+	}
+	replies, err := Store.ListTicketReplies(ticket.ID, isStaff)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get ticket: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.TicketDetails{Ticket: ticket, Replies: replies})
+}
+
+// @Summary Reply to ticket
+// @Description Adds a message to the ticket. An instructor's reply moves the ticket to waiting; the author's reply reopens a waiting or resolved ticket. Internal notes (instructors only) do not change the status and are hidden from the author.
+// @Tags Support
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param request body models.TicketReplyRequest true "Reply"
+// @Success 201 {object} models.TicketReply
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /tickets/{id}/replies [post]
+func ReplyToTicket(c *gin.Context) {
+	ticket, isStaff, ok := ticketAccess(c)
+	if !ok {
+		return
+	}
+	var req models.TicketReplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if req.Internal && !isStaff {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "Only instructors can add internal notes"})
+		return
+	}
+	if ticket.Status == models.TicketClosed {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The ticket is closed"})
+		return
+	}
+
+	userID := c.GetInt("userID")
+	status := ""
+	switch {
+	case req.Internal:
+	case userID == ticket.UserID:
+		if ticket.Status == models.TicketWaiting || ticket.Status == models.TicketResolved {
+			status = models.TicketOpen
+		}
+	default:
+		status = models.TicketWaiting
+	}
+
+	reply, err := Store.AddTicketReply(models.TicketReply{
+		TicketID: ticket.ID,
+		AuthorID: &userID,
+		Body:     req.Body,
+		Internal: req.Internal,
+	}, status)
+	if errors.Is(err, storage.ErrTicketNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Ticket not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to reply to ticket: " + err.Error()})
+		return
+	}
+
+	switch {
+	case req.Internal:
+	case userID == ticket.UserID:
+		notifyTicket(ticket.AssigneeID, "ticket.reply", reply)
+	default:
+		notifyTicket(&ticket.UserID, "ticket.reply", reply)
+	}
+	c.JSON(http.StatusCreated, reply)
+}
+
+// @Summary Change ticket status
+// @Description Instructors and administrators can set any status; the author can only close the ticket
+// @Tags Support
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param request body models.TicketStatusRequest true "Status"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /tickets/{id}/status [put]
+func SetTicketStatus(c *gin.Context) {
+	ticket, isStaff, ok := ticketAccess(c)
+	if !ok {
+		return
+	}
+	var req models.TicketStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if !validTicketStatus(req.Status) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Unknown ticket status"})
+		return
+	}
+	if !isStaff && req.Status != models.TicketClosed {
+		c.JSON(http.StatusForbidden, models.ErrorResponse{Error: "You can only close your own ticket"})
+		return
+	}
+
+	updated, err := Store.SetTicketStatus(ticket.ID, req.Status)
+	if errors.Is(err, storage.ErrTicketNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Ticket not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update ticket: " + err.Error()})
+		return
+	}
+	if c.GetInt("userID") != ticket.UserID {
+		notifyTicket(&ticket.UserID, "ticket.status", updated)
+	}
+	c.JSON(http.StatusOK, updated)
+}
+
+// @Summary Assign ticket
+// @Description Assigns the ticket to an instructor, or unassigns it with a null assigneeId (instructors and administrators)
+// @Tags Support
+// @Accept json
+// @Produce json
+// @Param id path int true "Ticket ID"
+// @Param request body models.TicketAssigneeRequest true "Assignee"
+// @Success 200 {object} models.Ticket
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Security BearerAuth
+// @Router /tickets/{id}/assignee [put]
+func AssignTicket(c *gin.Context) {
+	if _, _, ok := requireInstructor(c, "Only administrators and instructors can assign tickets"); !ok {
+		return
+	}
+	ticket, _, ok := ticketAccess(c)
+	if !ok {
+		return
+	}
+	var req models.TicketAssigneeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	if req.AssigneeID != nil {
+		_, isInstructor, err := CheckInstructorRights(*req.AssigneeID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
+			return
+		}
+		if !isInstructor {
+			c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Tickets can only be assigned to instructors"})
+			return
+		}
+	}
+
+	updated, err := Store.SetTicketAssignee(ticket.ID, req.AssigneeID)
+	if errors.Is(err, storage.ErrTicketNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Ticket not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update ticket: " + err.Error()})
+		return
+	}
+	if req.AssigneeID != nil && *req.AssigneeID != c.GetInt("userID") {
+		notifyTicket(req.AssigneeID, "ticket.assigned", updated)
+	}
+	c.JSON(http.StatusOK, updated)
+}
@@ -0,0 +1,177 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+// trustedDeviceCookie хранит "<токен>.<подпись>"; подпись привязывает токен к пользователю
+// и секрету JWT, поэтому ротация секрета отзывает все доверенные устройства
+const trustedDeviceCookie = "lms_trusted_device"
+
+func trustedDeviceSignature(userID int, token string) string {
+	mac := hmac.New(sha256.New, []byte(JWTSecret()))
+	mac.Write([]byte("trusted-device:" + strconv.Itoa(userID) + ":" + token))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// hashToken возвращает SHA-256 случайного токена; в базе хранится только хеш
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// secureRequest сообщает, пришел ли запрос по HTTPS (напрямую или через прокси)
+func secureRequest(c *gin.Context) bool {
+	return c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https")
+}
+
+func setTrustedDeviceCookie(c *gin.Context, value string, maxAge int) {
+	c.SetSameSite(http.SameSiteStrictMode)
+	c.SetCookie(trustedDeviceCookie, value, maxAge, "/api", "", secureRequest(c), true)
+}
+
+// rememberDevice сохраняет устройство как доверенное и выдает cookie
+func rememberDevice(c *gin.Context, userID int) {
+	ttl := currentOTPSettings().TrustedDeviceTTL
+	if ttl <= 0 || ReadOnlyMode() {
+		return
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		log.Printf("Failed to generate trusted device token: %v", err)
+		return
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	_, err := Store.CreateTrustedDevice(models.TrustedDevice{
+		UserID:    userID,
+		TokenHash: hashToken(token),
+		UserAgent: userAgent,
+		IP:        c.ClientIP(),
+		ExpiresAt: time.Now().Add(ttl),
+	})
+	if err != nil {
+		log.Printf("Failed to save trusted device for user %d: %v", userID, err)
+		return
+	}
+	setTrustedDeviceCookie(c, token+"."+trustedDeviceSignature(userID, token), int(ttl/time.Second))
+}
+
+// trustedDevice проверяет cookie доверенного устройства для пользователя
+func trustedDevice(c *gin.Context, userID int) (models.TrustedDevice, bool) {
+	if currentOTPSettings().TrustedDeviceTTL <= 0 {
+		return models.TrustedDevice{}, false
+	}
+	value, err := c.Cookie(trustedDeviceCookie)
+	if err != nil {
+		return models.TrustedDevice{}, false
+	}
+	token, signature, ok := strings.Cut(value, ".")
+	if !ok || !hmac.Equal([]byte(signature), []byte(trustedDeviceSignature(userID, token))) {
+		return models.TrustedDevice{}, false
+	}
+
+	device, err := Store.GetTrustedDevice(hashToken(token))
+	if err != nil {
+		if !errors.Is(err, storage.ErrTrustedDeviceNotFound) {
+			log.Printf("Failed to load trusted device: %v", err)
+		}
+		return models.TrustedDevice{}, false
+	}
+	if device.UserID != userID {
+		return models.TrustedDevice{}, false
+	}
+	// В режиме только для чтения время последнего использования не обновляется
+	if !ReadOnlyMode() {
+		if err := Store.TouchTrustedDevice(device.ID); err != nil {
+			log.Printf("Failed to update trusted device %d: %v", device.ID, err)
+		}
+	}
+	return device, true
+}
+
+// @Summary List trusted devices
+// @Description Devices on which the current user skips the second factor, most recently used first
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.TrustedDevice
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/devices [get]
+func ListTrustedDevices(c *gin.Context) {
+	devices, err := Store.ListTrustedDevices(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list trusted devices"})
+		return
+	}
+	c.JSON(http.StatusOK, devices)
+}
+
+// @Summary Revoke trusted device
+// @Description Require the second factor again on one device
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Device ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/devices/{id} [delete]
+func RevokeTrustedDevice(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid device ID"})
+		return
+	}
+
+	err = Store.RevokeTrustedDevice(c.GetInt("userID"), id)
+	if errors.Is(err, storage.ErrTrustedDeviceNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Device not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to revoke device"})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Device revoked")})
+}
+
+// @Summary Revoke all trusted devices
+// @Description Require the second factor again on every device
+// @Tags Auth
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/devices [delete]
+func RevokeTrustedDevices(c *gin.Context) {
+	if _, err := Store.RevokeTrustedDevices(c.GetInt("userID")); err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to revoke devices"})
+		return
+	}
+	setTrustedDeviceCookie(c, "", -1)
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "All devices revoked")})
+}
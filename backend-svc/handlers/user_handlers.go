@@ -1,10 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
 )
 
 // @Summary Get user profile
@@ -26,18 +30,23 @@ func GetUserProfile(c *gin.Context) {
 
 	userID := userIDInterface.(int)
 
-	user, err := Store.GetUserByID(userID)
+	user, err := Store.GetUserPublicByID(userID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get user: " + err.Error()})
 		return
 	}
 
+	c.Header("ETag", userVersionETag(user.Version))
 	c.JSON(http.StatusOK, models.UserProfile{
 		ID:           user.ID,
 		Username:     user.Username,
 		Email:        user.Email,
 		FullName:     user.FullName,
 		Is2FAEnabled: user.Is2FAEnabled,
+		Version:      user.Version,
+		Avatar:       AvatarURLs(user.AvatarKey),
+		Locale:       UserLocale(user.Locale),
+		Timezone:     UserTimezone(user.Timezone),
 	})
 }
 
@@ -55,13 +64,13 @@ func GetUserProfile(c *gin.Context) {
 // @Failure 500 {object} models.ErrorResponse
 // @Router /admin/users/{id} [get]
 func GetUserByID(c *gin.Context) {
-	userID, exists := c.Get("userID")
+	_, exists := c.Get("userID")
 	if !exists {
 		c.JSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
 		return
 	}
 
-	isAdmin, err := Store.IsAdmin(userID.(int))
+	isAdmin, err := RequestAdminRights(c)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
 		return
@@ -78,69 +87,64 @@ func GetUserByID(c *gin.Context) {
 		return
 	}
 
-	user, err := Store.GetUserByID(targetUserID)
+	user, err := Store.GetUserPublicByID(targetUserID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.UserProfile{
-		ID:           user.ID,
-		Username:     user.Username,
-		Email:        user.Email,
-		FullName:     user.FullName,
-		Is2FAEnabled: user.Is2FAEnabled,
-		IsAdmin:      user.IsAdmin,
-		IsActive:     user.IsActive,
-		LastLogin:    user.LastLogin,
-	})
+	c.Header("ETag", userVersionETag(user.Version))
+	c.JSON(http.StatusOK, adminUserProfile(user))
 }
 
 // @Summary Get all users
-// @Description Get a list of all users (admin only)
+// @Description Get a list of all users (admin only). The list is streamed; use format=csv to download it as CSV.
 // @Tags Admin
 // @Accept json
-// @Produce json
+// @Produce json,text/csv
 // @Security BearerAuth
+// @Param fields query string false "Comma-separated fields to return: id,username,email,fullName,is2faEnabled,isAdmin,isActive,lastLogin,version"
+// @Param format query string false "Response format" Enums(json, csv)
 // @Success 200 {array} models.UserProfile
+// @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /admin/users [get]
 func GetAllUsers(c *gin.Context) {
-	users, err := Store.GetAllUsers()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get users: " + err.Error()})
+	fields, ok := parseFields(c, storage.UserFields)
+	if !ok {
 		return
 	}
 
-	var userProfiles []models.UserProfile
-	for _, user := range users {
-		userProfiles = append(userProfiles, models.UserProfile{
-			ID:           user.ID,
-			Username:     user.Username,
-			Email:        user.Email,
-			FullName:     user.FullName,
-			Is2FAEnabled: user.Is2FAEnabled,
-			IsAdmin:      user.IsAdmin,
-			IsActive:     user.IsActive,
-			LastLogin:    user.LastLogin,
-		})
+	if fields != nil {
+		users, err := Store.ListUserSummaryFields(fields)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get users: " + err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, userFieldSet(users, fields))
+		return
 	}
 
-	c.JSON(http.StatusOK, userProfiles)
+	// Полный список читается из базы пачками и отправляется по мере чтения
+	exportUsers(c)
 }
 
 // @Summary Update user profile
-// @Description Update the profile of the current user
+// @Description Update the profile of the current user. A new email is applied only after it is confirmed via the link sent to it (202); the old address receives a link to cancel or revert the change. Locale must be one of the supported locales and timezone an IANA name such as Europe/Moscow.
 // @Tags User
 // @Accept json
 // @Produce json
 // @Security BearerAuth
 // @Param request body models.UpdateProfileRequest true "Profile data to update"
+// @Param If-Match header string false "User ETag; required unless version is passed in the body"
 // @Success 200 {object} models.SuccessResponse
+// @Success 202 {object} models.SuccessResponse "Email change awaits confirmation"
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 428 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /profile [put]
 func UpdateUserProfile(c *gin.Context) {
@@ -157,14 +161,78 @@ func UpdateUserProfile(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
 		return
 	}
+	if req.Locale != "" && !validLocale(req.Locale) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{
+			Error: "Unsupported locale; choose one of: " + strings.Join(currentAccountsConfig().SupportedLocales, ", "),
+		})
+		return
+	}
+	if req.Timezone != "" && !validTimezone(req.Timezone) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Timezone must be an IANA time zone name such as Europe/Moscow"})
+		return
+	}
+
+	// Пока действует требование администратора, профиль меняется только вместе с паролем
+	if req.Password == "" {
+		required, err := PasswordChangeRequired(userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update profile: " + err.Error()})
+			return
+		}
+		if required {
+			c.JSON(http.StatusForbidden, models.PasswordChangeRequiredResponse{Error: "Password change required", PasswordChangeRequired: true})
+			return
+		}
+	}
+
+	version, ok := requireUserVersion(c, req.Version)
+	if !ok {
+		return
+	}
+	req.Version = version
+
+	// Новый email применяется только после подтверждения по ссылке, см. requestEmailChange
+	newEmail := req.Email
+	req.Email = ""
 
 	err := Store.UpdateUserProfile(userID, req)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Profile was changed concurrently, reload it and retry"})
+		return
+	}
+	if errors.Is(err, storage.ErrPasswordReused) {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "The new password must differ from your recent passwords"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update profile: " + err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse{Message: "Profile updated successfully"})
+	// После смены пароля доверенные устройства снова проходят второй фактор
+	if req.Password != "" {
+		forgetSessionState(userID)
+		if _, err := Store.RevokeTrustedDevices(userID); err != nil {
+			log.Printf("Failed to revoke trusted devices for user %d: %v", userID, err)
+		}
+	}
+
+	if newEmail != "" {
+		pending, err := requestEmailChange(c, userID, newEmail)
+		if err != nil {
+			log.Printf("Failed to request email change for user %d: %v", userID, err)
+			c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to request email change"})
+			return
+		}
+		if pending {
+			c.JSON(http.StatusAccepted, models.SuccessResponse{
+				Message: Translate(c, "Profile updated; confirm the new email address using the link sent to it"),
+			})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Profile updated successfully")})
 }
 
 // @Summary Get users by role
@@ -189,21 +257,7 @@ func GetUsersByRole(c *gin.Context) {
 		return
 	}
 
-	var userProfiles []models.UserProfile
-	for _, user := range users {
-		userProfiles = append(userProfiles, models.UserProfile{
-			ID:           user.ID,
-			Username:     user.Username,
-			Email:        user.Email,
-			FullName:     user.FullName,
-			Is2FAEnabled: user.Is2FAEnabled,
-			IsAdmin:      user.IsAdmin,
-			IsActive:     user.IsActive,
-			LastLogin:    user.LastLogin,
-		})
-	}
-
-	c.JSON(http.StatusOK, userProfiles)
+	c.JSON(http.StatusOK, adminUserProfiles(users))
 }
 
 // @Summary Search users
@@ -231,21 +285,7 @@ func SearchUsers(c *gin.Context) {
 		return
 	}
 
-	var userProfiles []models.UserProfile
-	for _, user := range users {
-		userProfiles = append(userProfiles, models.UserProfile{
-			ID:           user.ID,
-			Username:     user.Username,
-			Email:        user.Email,
-			FullName:     user.FullName,
-			Is2FAEnabled: user.Is2FAEnabled,
-			IsAdmin:      user.IsAdmin,
-			IsActive:     user.IsActive,
-			LastLogin:    user.LastLogin,
-		})
-	}
-
-	c.JSON(http.StatusOK, userProfiles)
+	c.JSON(http.StatusOK, adminUserProfiles(users))
 }
 
 // @Summary Update user status
@@ -256,11 +296,14 @@ func SearchUsers(c *gin.Context) {
 // @Security BearerAuth
 // @Param id path int true "User ID"
 // @Param request body models.UpdateStatusRequest true "Status data"
+// @Param If-Match header string false "User ETag; required unless version is passed in the body"
 // @Success 200 {object} models.SuccessResponse
 // @Failure 400 {object} models.ErrorResponse
 // @Failure 401 {object} models.ErrorResponse
 // @Failure 403 {object} models.ErrorResponse
 // @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse
+// @Failure 428 {object} models.ErrorResponse
 // @Failure 500 {object} models.ErrorResponse
 // @Router /admin/users/{id}/status [put]
 func UpdateUserStatus(c *gin.Context) {
@@ -276,13 +319,23 @@ func UpdateUserStatus(c *gin.Context) {
 		return
 	}
 
-	err = Store.UpdateUserStatus(targetUserID, req.IsActive)
+	version, ok := requireUserVersion(c, req.Version)
+	if !ok {
+		return
+	}
+
+	err = Store.UpdateUserStatus(targetUserID, req.IsActive, version)
+	if errors.Is(err, storage.ErrVersionConflict) {
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "User was changed concurrently, reload it and retry"})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to update user status: " + err.Error()})
 		return
 	}
+	forgetSessionState(targetUserID)
 
-	c.JSON(http.StatusOK, models.SuccessResponse{Message: "User status updated successfully"})
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "User status updated successfully")})
 }
 
 // @Summary Promote user to admin
@@ -307,12 +360,13 @@ func PromoteToAdmin(c *gin.Context) {
 	}
 
 	err = Store.PromoteToAdmin(targetUserID)
+	forgetRoles(targetUserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to promote user: " + err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse{Message: "User promoted to admin successfully"})
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "User promoted to admin successfully")})
 }
 
 // @Summary Demote user from admin
@@ -337,10 +391,37 @@ func DemoteFromAdmin(c *gin.Context) {
 	}
 
 	err = Store.DemoteFromAdmin(targetUserID)
+	forgetRoles(targetUserID)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to demote user: " + err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.SuccessResponse{Message: "User demoted from admin successfully"})
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "User demoted from admin successfully")})
+}
+
+// adminUserProfile формирует карточку пользователя для администратора
+func adminUserProfile(user models.UserSummary) models.UserProfile {
+	return models.UserProfile{
+		ID:           user.ID,
+		Username:     user.Username,
+		Email:        user.Email,
+		FullName:     user.FullName,
+		Is2FAEnabled: user.Is2FAEnabled,
+		IsAdmin:      user.IsAdmin,
+		IsActive:     user.IsActive,
+		LastLogin:    user.LastLogin,
+		Version:      user.Version,
+		Avatar:       AvatarURLs(user.AvatarKey),
+		Locale:       UserLocale(user.Locale),
+		Timezone:     UserTimezone(user.Timezone),
+	}
+}
+
+func adminUserProfiles(users []models.UserSummary) []models.UserProfile {
+	var userProfiles []models.UserProfile
+	for _, user := range users {
+		userProfiles = append(userProfiles, adminUserProfile(user))
+	}
+	return userProfiles
 }
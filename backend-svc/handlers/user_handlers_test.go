@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/storage"
+)
+
+func TestUpdateUserStatusRequiresVersion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	Store = &storage.MockStorage{}
+	r := gin.New()
+	r.PUT("/admin/users/:id/status", UpdateUserStatus)
+
+	const userID = 2
+	user, err := Store.GetUserPublicByID(userID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	read := user.Version
+
+	put := func(body, ifMatch string) int {
+		req := httptest.NewRequest(http.MethodPut, "/admin/users/"+strconv.Itoa(userID)+"/status", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if ifMatch != "" {
+			req.Header.Set("If-Match", ifMatch)
+		}
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Оба администратора прочитали версию read; первый успевает сохранить изменение
+	if code := put(`{"isActive": true, "version": `+strconv.Itoa(read)+`}`, ""); code != http.StatusOK {
+		t.Fatalf("first writer: status = %d, want %d", code, http.StatusOK)
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		ifMatch string
+		want    int
+	}{
+		{"stale writer omits the version", `{"isActive": true}`, "", http.StatusPreconditionRequired},
+		{"stale writer sends the old version", `{"isActive": true, "version": ` + strconv.Itoa(read) + `}`, "", http.StatusConflict},
+		{"stale writer sends the old ETag", `{"isActive": true}`, userVersionETag(read), http.StatusConflict},
+		{"malformed If-Match", `{"isActive": true}`, "*", http.StatusBadRequest},
+		{"current ETag", `{"isActive": true}`, `W/` + userVersionETag(read+1), http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if code := put(tt.body, tt.ifMatch); code != tt.want {
+				t.Errorf("status = %d, want %d", code, tt.want)
+			}
+		})
+	}
+
+	user, err = Store.GetUserPublicByID(userID)
+	if err != nil {
+		t.Fatalf("get user: %v", err)
+	}
+	if user.Version != read+2 {
+		t.Errorf("version = %d, want %d: rejected writes must not bump it", user.Version, read+2)
+	}
+}
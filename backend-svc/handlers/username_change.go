@@ -0,0 +1,179 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+)
+
+// renameUser меняет имя пользователя и отвечает клиенту. changedBy — администратор,
+// переименовавший пользователя; для них ограничение частоты смены не действует.
+func renameUser(c *gin.Context, userID int, newUsername string, changedBy *int) {
+	accounts := currentAccountsConfig()
+	minInterval := accounts.UsernameChangeInterval
+	if changedBy != nil {
+		minInterval = 0
+	}
+
+	change, err := Store.ChangeUsername(models.UsernameChange{
+		UserID:        userID,
+		NewUsername:   newUsername,
+		ChangedBy:     changedBy,
+		ReservedUntil: time.Now().Add(accounts.UsernameReservation),
+	}, minInterval)
+	switch {
+	case errors.Is(err, storage.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	case errors.Is(err, storage.ErrDuplicateUsername), errors.Is(err, storage.ErrUsernameReserved):
+		c.JSON(http.StatusConflict, models.ErrorResponse{Error: "Username is not available"})
+		return
+	case errors.Is(err, storage.ErrUsernameChangeTooSoon):
+		if retryAfter := usernameChangeRetryAfter(userID, minInterval); retryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(int(retryAfter.Seconds())))
+		}
+		c.JSON(http.StatusTooManyRequests, models.ErrorResponse{Error: "Username was changed recently; try again later"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to change username: " + err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, change)
+}
+
+// usernameChangeRetryAfter возвращает, через сколько пользователь сможет снова сменить имя
+func usernameChangeRetryAfter(userID int, minInterval time.Duration) time.Duration {
+	history, err := Store.ListUsernameHistory(userID)
+	if err != nil || len(history) == 0 {
+		return 0
+	}
+	return time.Until(history[0].ChangedAt.Add(minInterval)).Round(time.Second)
+}
+
+// @Summary Change my username
+// @Description Rename the current user. The old username stays reserved for this account for a while and links to it resolve to the new one. Changes are rate limited.
+// @Tags Account
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.ChangeUsernameRequest true "New username"
+// @Success 200 {object} models.UsernameChange
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Username is taken or reserved"
+// @Failure 429 {object} models.ErrorResponse "Username was changed too recently"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /account/username [put]
+func ChangeUsername(c *gin.Context) {
+	var req models.ChangeUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	renameUser(c, c.GetInt("userID"), req.Username, nil)
+}
+
+// @Summary Change a user's username
+// @Description Rename a user (admin only). The rate limit for self-service changes does not apply; the old username is reserved as usual.
+// @Tags Admin
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Param request body models.ChangeUsernameRequest true "New username"
+// @Success 200 {object} models.UsernameChange
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 409 {object} models.ErrorResponse "Username is taken or reserved"
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{id}/username [put]
+func AdminChangeUsername(c *gin.Context) {
+	targetUserID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	var req models.ChangeUsernameRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+
+	adminID := c.GetInt("userID")
+	renameUser(c, targetUserID, req.Username, &adminID)
+}
+
+// @Summary Username history of a user
+// @Description Previous usernames of a user, most recent first, with how long each stays reserved (admin only)
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {array} models.UsernameChange
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/{id}/username-history [get]
+func ListUsernameHistory(c *gin.Context) {
+	userID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid user ID"})
+		return
+	}
+
+	history, err := Store.ListUsernameHistory(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to list username history: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, history)
+}
+
+// @Summary Get user by username
+// @Description Look up a user by username (admin only). A previous username of a renamed user redirects to the current one.
+// @Tags Admin
+// @Produce json
+// @Security BearerAuth
+// @Param username path string true "Current or previous username"
+// @Success 200 {object} models.UserProfile
+// @Success 302 "Redirect to the current username"
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 403 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /admin/users/by-username/{username} [get]
+func GetUserByUsername(c *gin.Context) {
+	username := c.Param("username")
+	userID, err := Store.ResolveUsername(username)
+	if errors.Is(err, storage.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to look up user: " + err.Error()})
+		return
+	}
+
+	user, err := Store.GetUserPublicByID(userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "User not found"})
+		return
+	}
+	if user.Username != username {
+		c.Redirect(http.StatusFound, "/api/admin/users/by-username/"+url.PathEscape(user.Username))
+		return
+	}
+	c.JSON(http.StatusOK, adminUserProfile(user))
+}
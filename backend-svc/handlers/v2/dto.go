@@ -0,0 +1,130 @@
+package v2
+
+import (
+	"lmsmodule/backend-svc/handlers"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// DTO API v2. В отличие от v1, списки всегда пагинированы, пустые значения
+// передаются как null, а служебные поля пользователя не смешиваются с профилем.
+
+// Pagination — параметры и итог постраничной выдачи
+type Pagination struct {
+	Page       int `json:"page" example:"1"`
+	PerPage    int `json:"perPage" example:"20"`
+	Total      int `json:"total" example:"42"`
+	TotalPages int `json:"totalPages" example:"3"`
+}
+
+type Course struct {
+	ID                int       `json:"id"`
+	VulnerabilityType string    `json:"vulnerabilityType"`
+	Description       string    `json:"description"`
+	TasksCount        int       `json:"tasksCount"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+type CourseDetail struct {
+	Course
+	Tasks []Task `json:"tasks"`
+}
+
+type Task struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description"`
+	Difficulty  string `json:"difficulty"`
+	Order       int    `json:"order"`
+}
+
+type CoursePage struct {
+	Data       []Course   `json:"data"`
+	Pagination Pagination `json:"pagination"`
+}
+
+type User struct {
+	ID               int     `json:"id"`
+	Username         string  `json:"username"`
+	Email            *string `json:"email"`
+	FullName         *string `json:"fullName"`
+	TwoFactorEnabled bool    `json:"twoFactorEnabled"`
+	Version          int     `json:"version"`
+	// Avatar — адреса версий аватара; null, если аватар не загружен
+	Avatar   *models.AvatarURLs `json:"avatar"`
+	Locale   string             `json:"locale"`
+	Timezone string             `json:"timezone"`
+}
+
+// AdminUser — пользователь с полями, доступными только администраторам
+type AdminUser struct {
+	User
+	IsAdmin   bool       `json:"isAdmin"`
+	IsActive  bool       `json:"isActive"`
+	LastLogin *time.Time `json:"lastLogin"`
+}
+
+type UserPage struct {
+	Data       []AdminUser `json:"data"`
+	Pagination Pagination  `json:"pagination"`
+}
+
+type Progress struct {
+	UserID           int   `json:"userId"`
+	CompletedTaskIDs []int `json:"completedTaskIds"`
+	CompletedCount   int   `json:"completedCount"`
+}
+
+func courseDTO(c models.Course) Course {
+	return Course{
+		ID:                c.ID,
+		VulnerabilityType: c.VulnerabilityType,
+		Description:       c.Description,
+		TasksCount:        c.TasksCount,
+		UpdatedAt:         c.UpdatedAt,
+	}
+}
+
+func courseDetailDTO(c models.Course) CourseDetail {
+	detail := CourseDetail{Course: courseDTO(c), Tasks: []Task{}}
+	for _, t := range c.Tasks {
+		detail.Tasks = append(detail.Tasks, Task{
+			ID:          t.ID,
+			Title:       t.Title,
+			Description: t.Description,
+			Difficulty:  t.Difficulty,
+			Order:       t.Order,
+		})
+	}
+	return detail
+}
+
+func userDTO(u models.UserSummary) User {
+	return User{
+		ID:               u.ID,
+		Username:         u.Username,
+		Email:            optional(u.Email),
+		FullName:         optional(u.FullName),
+		TwoFactorEnabled: u.Is2FAEnabled,
+		Version:          u.Version,
+		Avatar:           handlers.AvatarURLs(u.AvatarKey),
+		Locale:           handlers.UserLocale(u.Locale),
+		Timezone:         handlers.UserTimezone(u.Timezone),
+	}
+}
+
+func adminUserDTO(u models.UserSummary) AdminUser {
+	dto := AdminUser{User: userDTO(u), IsAdmin: u.IsAdmin, IsActive: u.IsActive}
+	if !u.LastLogin.IsZero() {
+		lastLogin := u.LastLogin
+		dto.LastLogin = &lastLogin
+	}
+	return dto
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
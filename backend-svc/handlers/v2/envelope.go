@@ -0,0 +1,112 @@
+package v2
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/handlers"
+	"lmsmodule/backend-svc/redact"
+	"net/http"
+	"strconv"
+)
+
+// APIError — описание ошибки в API v2
+type APIError struct {
+	Code      string `json:"code" example:"not_found"`
+	Message   string `json:"message" example:"Course not found"`
+	RequestID string `json:"requestId,omitempty" example:"4f9c2a7e0b1d4c3a8e6f5d2c1b0a9f8e"`
+}
+
+// ErrorEnvelope — формат всех ошибок API v2
+type ErrorEnvelope struct {
+	Error APIError `json:"error"`
+}
+
+// errorCodes — машиночитаемые коды ошибок по HTTP-статусу
+var errorCodes = map[int]string{
+	http.StatusBadRequest:          "invalid_request",
+	http.StatusUnauthorized:        "unauthorized",
+	http.StatusForbidden:           "forbidden",
+	http.StatusNotFound:            "not_found",
+	http.StatusConflict:            "conflict",
+	http.StatusUnprocessableEntity: "unprocessable",
+	http.StatusTooManyRequests:     "rate_limited",
+	http.StatusServiceUnavailable:  "unavailable",
+}
+
+func errorCode(status int) string {
+	if code, ok := errorCodes[status]; ok {
+		return code
+	}
+	if status >= http.StatusInternalServerError {
+		return "internal_error"
+	}
+	return "error"
+}
+
+// abortWithError отвечает ошибкой в формате v2
+func abortWithError(c *gin.Context, status int, message string) {
+	c.AbortWithStatusJSON(status, ErrorEnvelope{Error: APIError{
+		Code:      errorCode(status),
+		Message:   handlers.Translate(c, redact.String(message)),
+		RequestID: c.GetString("requestID"),
+	}})
+}
+
+// envelopeWriter задерживает тело ответов с ошибкой, чтобы привести его к формату v2
+type envelopeWriter struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (w *envelopeWriter) Write(data []byte) (int, error) {
+	if w.Status() >= http.StatusBadRequest {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *envelopeWriter) WriteString(data string) (int, error) {
+	if w.Status() >= http.StatusBadRequest {
+		return w.body.WriteString(data)
+	}
+	return w.ResponseWriter.WriteString(data)
+}
+
+// Envelope приводит ошибки общих middleware (аутентификация, лимиты, режим обслуживания),
+// отвечающих в формате v1 {"error": "..."}, к конверту v2. Подключается первым в группе /api/v2,
+// поэтому middleware и обработчики v1 остаются без изменений.
+// Сообщение переводится на язык запроса, персональные данные в нем маскируются.
+func Envelope() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		writer := &envelopeWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+
+		if writer.body.Len() == 0 {
+			return
+		}
+		body := writer.body.Bytes()
+
+		var v1 struct {
+			Error                  json.RawMessage `json:"error"`
+			PasswordChangeRequired bool            `json:"passwordChangeRequired"`
+		}
+		var message string
+		if json.Unmarshal(body, &v1) == nil && json.Unmarshal(v1.Error, &message) == nil {
+			code := errorCode(writer.Status())
+			// Клиент должен отличить требование сменить пароль от прочих отказов в доступе
+			if v1.PasswordChangeRequired {
+				code = "password_change_required"
+			}
+			body, _ = json.Marshal(ErrorEnvelope{Error: APIError{
+				Code:      code,
+				Message:   handlers.Translate(c, redact.String(message)),
+				RequestID: c.GetString("requestID"),
+			}})
+			writer.Header().Set("Content-Length", strconv.Itoa(len(body)))
+		}
+		writer.ResponseWriter.Write(body)
+	}
+}
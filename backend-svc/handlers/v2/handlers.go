@@ -0,0 +1,232 @@
+// Package v2 содержит обработчики API v2 (/api/v2): единый конверт ошибок, пагинацию
+// списков и собственные DTO. Обработчики v1 в пакете handlers не изменяются.
+package v2
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"lmsmodule/backend-svc/handlers"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+	"net/http"
+	"sort"
+	"strconv"
+)
+
+const (
+	defaultPerPage = 20
+	maxPerPage     = 100
+)
+
+// pagination читает page и perPage из запроса; при некорректных значениях отвечает 400
+func pagination(c *gin.Context) (Pagination, bool) {
+	p := Pagination{Page: 1, PerPage: defaultPerPage}
+	if raw := c.Query("page"); raw != "" {
+		page, err := strconv.Atoi(raw)
+		if err != nil || page < 1 {
+			abortWithError(c, http.StatusBadRequest, "page must be a positive integer")
+			return p, false
+		}
+		p.Page = page
+	}
+	if raw := c.Query("perPage"); raw != "" {
+		perPage, err := strconv.Atoi(raw)
+		if err != nil || perPage < 1 || perPage > maxPerPage {
+			abortWithError(c, http.StatusBadRequest, "perPage must be between 1 and 100")
+			return p, false
+		}
+		p.PerPage = perPage
+	}
+	return p, true
+}
+
+// window заполняет итог пагинации и возвращает границы среза для total элементов
+func (p *Pagination) window(total int) (int, int) {
+	p.Total = total
+	p.TotalPages = (total + p.PerPage - 1) / p.PerPage
+	start := (p.Page - 1) * p.PerPage
+	if start > total {
+		start = total
+	}
+	end := start + p.PerPage
+	if end > total {
+		end = total
+	}
+	return start, end
+}
+
+// @Summary List courses (v2)
+// @Tags v2
+// @Produce json
+// @Security BearerAuth
+// @Param page query int false "Page number, starting at 1"
+// @Param perPage query int false "Page size, 1-100 (default 20)"
+// @Success 200 {object} v2.CoursePage
+// @Failure 400 {object} v2.ErrorEnvelope
+// @Failure 500 {object} v2.ErrorEnvelope
+// @Router /v2/courses [get]
+func ListCourses(c *gin.Context) {
+	p, ok := pagination(c)
+	if !ok {
+		return
+	}
+
+	courses, err := handlers.StoreFor(c).GetCourses()
+	if err == nil {
+		err = handlers.LocalizeCourses(handlers.RequestLocale(c), courses)
+	}
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	start, end := p.window(len(courses))
+	page := CoursePage{Data: []Course{}, Pagination: p}
+	for _, course := range courses[start:end] {
+		page.Data = append(page.Data, courseDTO(course))
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary Get course with tasks (v2)
+// @Tags v2
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Course ID"
+// @Success 200 {object} v2.CourseDetail
+// @Failure 400 {object} v2.ErrorEnvelope
+// @Failure 404 {object} v2.ErrorEnvelope
+// @Router /v2/courses/{id} [get]
+func GetCourse(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, "Invalid course ID")
+		return
+	}
+
+	course, err := handlers.StoreFor(c).GetCourseByID(id)
+	if err != nil {
+		if errors.Is(err, storage.ErrCourseNotFound) {
+			abortWithError(c, http.StatusNotFound, "Course not found")
+			return
+		}
+		abortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if hidden, err := handlers.CourseHidden(c, course); err != nil || hidden {
+		if err != nil {
+			abortWithError(c, http.StatusInternalServerError, err.Error())
+			return
+		}
+		abortWithError(c, http.StatusNotFound, "Course not found")
+		return
+	}
+	if err := handlers.LocalizeCourse(handlers.RequestLocale(c), &course); err != nil {
+		abortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, courseDetailDTO(course))
+}
+
+// @Summary Get my profile (v2)
+// @Tags v2
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} v2.User
+// @Failure 500 {object} v2.ErrorEnvelope
+// @Router /v2/me [get]
+func GetMe(c *gin.Context) {
+	user, err := handlers.Store.GetUserPublicByID(c.GetInt("userID"))
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, "Failed to get user: "+err.Error())
+		return
+	}
+	c.JSON(http.StatusOK, userDTO(user))
+}
+
+// @Summary Get my progress (v2)
+// @Tags v2
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} v2.Progress
+// @Failure 500 {object} v2.ErrorEnvelope
+// @Router /v2/me/progress [get]
+func GetMyProgress(c *gin.Context) {
+	userID := c.GetInt("userID")
+	progress, err := handlers.Store.GetUserProgress(userID)
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	dto := Progress{UserID: userID, CompletedTaskIDs: []int{}}
+	for taskID, done := range progress.Completed {
+		if done {
+			dto.CompletedTaskIDs = append(dto.CompletedTaskIDs, taskID)
+		}
+	}
+	sort.Ints(dto.CompletedTaskIDs)
+	dto.CompletedCount = len(dto.CompletedTaskIDs)
+	c.JSON(http.StatusOK, dto)
+}
+
+// @Summary List users (v2, admin)
+// @Tags v2
+// @Produce json
+// @Security BearerAuth
+// @Param q query string false "Search by username, email or full name"
+// @Param page query int false "Page number, starting at 1"
+// @Param perPage query int false "Page size, 1-100 (default 20)"
+// @Success 200 {object} v2.UserPage
+// @Failure 400 {object} v2.ErrorEnvelope
+// @Failure 403 {object} v2.ErrorEnvelope
+// @Router /v2/admin/users [get]
+func ListUsers(c *gin.Context) {
+	p, ok := pagination(c)
+	if !ok {
+		return
+	}
+
+	var err error
+	var users []models.UserSummary
+	if q := c.Query("q"); q != "" {
+		users, err = handlers.Store.SearchUsers(q)
+	} else {
+		users, err = handlers.Store.ListUserSummaries()
+	}
+	if err != nil {
+		abortWithError(c, http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	start, end := p.window(len(users))
+	page := UserPage{Data: []AdminUser{}, Pagination: p}
+	for _, user := range users[start:end] {
+		page.Data = append(page.Data, adminUserDTO(user))
+	}
+	c.JSON(http.StatusOK, page)
+}
+
+// @Summary Get user (v2, admin)
+// @Tags v2
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "User ID"
+// @Success 200 {object} v2.AdminUser
+// @Failure 400 {object} v2.ErrorEnvelope
+// @Failure 404 {object} v2.ErrorEnvelope
+// @Router /v2/admin/users/{id} [get]
+func GetUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		abortWithError(c, http.StatusBadRequest, "Invalid user ID")
+		return
+	}
+
+	user, err := handlers.Store.GetUserPublicByID(id)
+	if err != nil {
+		abortWithError(c, http.StatusNotFound, "User not found")
+		return
+	}
+	c.JSON(http.StatusOK, adminUserDTO(user))
+}
@@ -0,0 +1,332 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/i18n"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+	"lmsmodule/backend-svc/webpush"
+
+	"github.com/gin-gonic/gin"
+)
+
+// pushCoursesPath — страница, которую service worker открывает по нажатию на уведомление
+const pushCoursesPath = "/my_courses"
+
+var (
+	webPushMu     sync.RWMutex
+	webPushClient *webpush.Client
+	webPushConf   config.WebPushConfig
+)
+
+// ConfigureWebPush задает ключ VAPID; пустой ключ отключает Web Push. Безопасно вызывать
+// повторно при ротации секретов.
+func ConfigureWebPush(cfg config.WebPushConfig) error {
+	var client *webpush.Client
+	if cfg.VAPIDPrivateKey != "" {
+		created, err := webpush.New(cfg.VAPIDPrivateKey, cfg.Subject)
+		if err != nil {
+			return err
+		}
+		client = created
+	}
+
+	webPushMu.Lock()
+	defer webPushMu.Unlock()
+	webPushClient, webPushConf = client, cfg
+	return nil
+}
+
+func currentWebPush() (*webpush.Client, config.WebPushConfig) {
+	webPushMu.RLock()
+	defer webPushMu.RUnlock()
+	return webPushClient, webPushConf
+}
+
+func pushSubscriptionView(sub models.PushSubscription) models.PushSubscription {
+	if u, err := url.Parse(sub.Endpoint); err == nil {
+		sub.Service = u.Hostname()
+	}
+	return sub
+}
+
+// sendPush доставляет уведомление во все браузеры пользователя и возвращает число доставленных.
+// Подписки, которые push-сервис больше не принимает, удаляются.
+func sendPush(client *webpush.Client, ttl time.Duration, userID int, notification models.PushNotification) (int, error) {
+	subs, err := Store.ListPushSubscriptions(userID)
+	if err != nil {
+		return 0, err
+	}
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return 0, err
+	}
+	delivered := 0
+	for _, sub := range subs {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		err := client.Send(ctx, webpush.Subscription{Endpoint: sub.Endpoint, P256dh: sub.P256dh, Auth: sub.Auth}, payload, ttl)
+		cancel()
+		switch {
+		case errors.Is(err, webpush.ErrGone):
+			if err := Store.DeletePushSubscriptionByEndpoint(sub.Endpoint); err != nil {
+				log.Printf("Failed to delete expired push subscription %d: %v", sub.ID, err)
+			} else {
+				log.Printf("Push subscription %d of user %d expired and was deleted", sub.ID, userID)
+			}
+		case err != nil:
+			log.Printf("Failed to send push notification to subscription %d of user %d: %v", sub.ID, userID, err)
+		default:
+			delivered++
+		}
+	}
+	return delivered, nil
+}
+
+// pushTasksGraded отправляет браузерное уведомление о засчитанных заданиях
+func pushTasksGraded(userID int, taskIDs []int) {
+	client, cfg := currentWebPush()
+	if client == nil || len(taskIDs) == 0 {
+		return
+	}
+	go func() {
+		user, err := Store.GetUserByID(userID)
+		if err != nil {
+			log.Printf("Failed to prepare push notification for user %d: %v", userID, err)
+			return
+		}
+		locale := UserLocale(user.Locale)
+		titles, err := telegramTaskTitles(userID, taskIDs, locale)
+		if err != nil {
+			log.Printf("Failed to get task titles for push notification: %v", err)
+		}
+		names := make([]string, 0, len(taskIDs))
+		for _, taskID := range taskIDs {
+			title := titles[taskID]
+			if title == "" {
+				title = fmt.Sprintf("#%d", taskID)
+			}
+			names = append(names, title)
+		}
+		if _, err := sendPush(client, cfg.TTL, userID, models.PushNotification{
+			Title: i18n.Translate(locale, "Tasks graded"),
+			Body:  "✅ " + strings.Join(names, ", "),
+			Tag:   "graded",
+			URL:   pushCoursesPath,
+		}); err != nil {
+			log.Printf("Failed to send push notification to user %d: %v", userID, err)
+		}
+	}()
+}
+
+// SendPushDeadlineReminders напоминает подписанным пользователям о сроках невыполненных заданий
+// начатых курсов за webpush.deadline_reminder. Перенос срока дает новое напоминание.
+func SendPushDeadlineReminders() error {
+	client, cfg := currentWebPush()
+	if client == nil {
+		return nil
+	}
+	userIDs, err := Store.ListPushSubscriberIDs()
+	if err != nil {
+		return err
+	}
+	users, err := Store.GetUsersByIDs(userIDs)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, userID := range userIDs {
+		user, ok := users[userID]
+		if !ok {
+			continue
+		}
+		deadlines, err := upcomingDeadlines(user, now, now.Add(cfg.DeadlineReminder))
+		if err != nil {
+			return err
+		}
+		locale := UserLocale(user.Locale)
+		for _, deadline := range deadlines {
+			marked, err := Store.MarkPushDeadlineReminder(user.ID, deadline.TaskID, *deadline.DueAt)
+			if err != nil {
+				return err
+			}
+			if !marked {
+				continue
+			}
+			if _, err := sendPush(client, cfg.TTL, user.ID, models.PushNotification{
+				Title: i18n.Sprintf(locale, "Deadline %s", formatTelegramTime(*deadline.DueAt, user)),
+				Body:  deadline.TaskTitle + " (" + deadline.CourseTitle + ")",
+				Tag:   "deadline-" + strconv.Itoa(deadline.TaskID),
+				URL:   pushCoursesPath,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// @Summary Get web push settings
+// @Description Returns the VAPID public key to pass to PushManager.subscribe({applicationServerKey}). Notifications arrive in the service worker push event as JSON models.PushNotification.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.PushConfig
+// @Failure 401 {object} models.ErrorResponse
+// @Router /push [get]
+func GetPushConfig(c *gin.Context) {
+	client, _ := currentWebPush()
+	if client == nil {
+		c.JSON(http.StatusOK, models.PushConfig{})
+		return
+	}
+	c.JSON(http.StatusOK, models.PushConfig{Enabled: true, PublicKey: client.PublicKey()})
+}
+
+// @Summary List web push subscriptions
+// @Description Lists the browsers subscribed to the current user's notifications.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {array} models.PushSubscription
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /push/subscriptions [get]
+func ListPushSubscriptions(c *gin.Context) {
+	subs, err := Store.ListPushSubscriptions(c.GetInt("userID"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to get push subscriptions: " + err.Error()})
+		return
+	}
+	for i := range subs {
+		subs[i] = pushSubscriptionView(subs[i])
+	}
+	c.JSON(http.StatusOK, subs)
+}
+
+// @Summary Subscribe browser to web push
+// @Description Saves the result of PushSubscription.toJSON(). The browser then receives deadline reminders and grading notifications even when the LMS tab is closed. Subscribing again with the same endpoint updates the keys.
+// @Tags Notifications
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Param request body models.SavePushSubscriptionRequest true "Browser push subscription"
+// @Success 201 {object} models.PushSubscription
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Web push is not configured"
+// @Router /push/subscriptions [post]
+func SavePushSubscription(c *gin.Context) {
+	if client, _ := currentWebPush(); client == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Web push is not configured"})
+		return
+	}
+	var req models.SavePushSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid request data: " + err.Error()})
+		return
+	}
+	sub := webpush.Subscription{Endpoint: req.Endpoint, P256dh: req.Keys.P256dh, Auth: req.Keys.Auth}
+	if err := sub.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid push subscription"})
+		return
+	}
+
+	userAgent := c.Request.UserAgent()
+	if len(userAgent) > 255 {
+		userAgent = userAgent[:255]
+	}
+	saved, err := Store.SavePushSubscription(models.PushSubscription{
+		UserID:    c.GetInt("userID"),
+		Endpoint:  sub.Endpoint,
+		P256dh:    sub.P256dh,
+		Auth:      sub.Auth,
+		UserAgent: strings.ToValidUTF8(userAgent, ""),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to save push subscription: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, pushSubscriptionView(saved))
+}
+
+// @Summary Unsubscribe browser from web push
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Param id path int true "Subscription ID"
+// @Success 200 {object} models.SuccessResponse
+// @Failure 400 {object} models.ErrorResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse
+// @Failure 500 {object} models.ErrorResponse
+// @Router /push/subscriptions/{id} [delete]
+func DeletePushSubscription(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, models.ErrorResponse{Error: "Invalid subscription ID"})
+		return
+	}
+	err = Store.DeletePushSubscription(c.GetInt("userID"), id)
+	if errors.Is(err, storage.ErrPushSubscriptionNotFound) {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "Push subscription not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to delete push subscription: " + err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Push subscription deleted")})
+}
+
+// @Summary Send test web push notification
+// @Description Sends a test notification to every browser of the current user.
+// @Tags Notifications
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} models.SuccessResponse
+// @Failure 401 {object} models.ErrorResponse
+// @Failure 404 {object} models.ErrorResponse "No subscription accepted the notification"
+// @Failure 500 {object} models.ErrorResponse
+// @Failure 503 {object} models.ErrorResponse "Web push is not configured"
+// @Router /push/test [post]
+func SendTestPush(c *gin.Context) {
+	client, cfg := currentWebPush()
+	if client == nil {
+		c.JSON(http.StatusServiceUnavailable, models.ErrorResponse{Error: "Web push is not configured"})
+		return
+	}
+	userID := c.GetInt("userID")
+	user, err := Store.GetUserByID(userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to send test notification: " + err.Error()})
+		return
+	}
+	locale := UserLocale(user.Locale)
+	delivered, err := sendPush(client, cfg.TTL, userID, models.PushNotification{
+		Title: i18n.Translate(locale, "LMS notifications"),
+		Body:  i18n.Translate(locale, "Notifications in this browser are turned on."),
+		Tag:   "test",
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to send test notification: " + err.Error()})
+		return
+	}
+	if delivered == 0 {
+		c.JSON(http.StatusNotFound, models.ErrorResponse{Error: "No browser accepted the notification"})
+		return
+	}
+	c.JSON(http.StatusOK, models.SuccessResponse{Message: Translate(c, "Test notification sent")})
+}
@@ -0,0 +1,147 @@
+package health
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check проверяет доступность одной зависимости сервиса
+type Check func(ctx context.Context) error
+
+// CheckResult — результат отдельной проверки в ответе /readyz
+type CheckResult struct {
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"durationMs"`
+}
+
+// Report — структурированный ответ эндпоинтов здоровья
+type Report struct {
+	Status string                 `json:"status"`
+	Time   string                 `json:"time"`
+	Checks map[string]CheckResult `json:"checks,omitempty"`
+}
+
+type namedCheck struct {
+	name  string
+	check Check
+}
+
+// Checker хранит набор проверок готовности и состояние завершения работы
+type Checker struct {
+	Timeout      time.Duration
+	mu           sync.RWMutex
+	checks       []namedCheck
+	shuttingDown atomic.Bool
+}
+
+// NewChecker создает Checker с таймаутом на каждую проверку
+func NewChecker(timeout time.Duration) *Checker {
+	return &Checker{Timeout: timeout}
+}
+
+// Register добавляет проверку готовности
+func (c *Checker) Register(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.checks = append(c.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown переводит сервис в состояние «не готов», чтобы балансировщик
+// перестал направлять новые запросы во время остановки
+func (c *Checker) SetShuttingDown() {
+	c.shuttingDown.Store(true)
+}
+
+// LivenessHandler отвечает 200, пока процесс способен обрабатывать запросы
+func (c *Checker) LivenessHandler(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, Report{
+		Status: "ok",
+		Time:   time.Now().Format(time.RFC3339),
+	})
+}
+
+// ReadinessHandler выполняет все проверки и отвечает 503, если хотя бы одна не прошла
+func (c *Checker) ReadinessHandler(ctx *gin.Context) {
+	report := Report{
+		Status: "ok",
+		Time:   time.Now().Format(time.RFC3339),
+		Checks: make(map[string]CheckResult),
+	}
+
+	if c.shuttingDown.Load() {
+		report.Status = "shutting_down"
+		ctx.JSON(http.StatusServiceUnavailable, report)
+		return
+	}
+
+	c.mu.RLock()
+	checks := append([]namedCheck(nil), c.checks...)
+	c.mu.RUnlock()
+
+	for _, nc := range checks {
+		result := c.run(ctx.Request.Context(), nc.check)
+		if result.Status != "ok" {
+			report.Status = "unavailable"
+		}
+		report.Checks[nc.name] = result
+	}
+
+	status := http.StatusOK
+	if report.Status != "ok" {
+		status = http.StatusServiceUnavailable
+	}
+	ctx.JSON(status, report)
+}
+
+func (c *Checker) run(parent context.Context, check Check) CheckResult {
+	ctx, cancel := context.WithTimeout(parent, c.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check(ctx)
+	result := CheckResult{Status: "ok", DurationMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		result.Status = "error"
+		result.Error = err.Error()
+	}
+	return result
+}
+
+// DBCheck проверяет соединение с базой данных
+func DBCheck(db *sql.DB) Check {
+	return func(ctx context.Context) error {
+		return db.PingContext(ctx)
+	}
+}
+
+// MigrationsCheck проверяет, что миграции golang-migrate применены, не остались в состоянии dirty
+// и схема не отстает от версии required, под которую собран код. Опережающая схема допустима:
+// несовместимые сужающие миграции отсекаются проверкой при запуске.
+func MigrationsCheck(db *sql.DB, required int64) Check {
+	return func(ctx context.Context) error {
+		var version int64
+		var dirty bool
+		err := db.QueryRowContext(ctx, "SELECT version, dirty FROM schema_migrations LIMIT 1").Scan(&version, &dirty)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return errors.New("no migrations applied")
+			}
+			return fmt.Errorf("read schema version: %w", err)
+		}
+		if dirty {
+			return fmt.Errorf("schema version %d is dirty", version)
+		}
+		if version < required {
+			return fmt.Errorf("schema version %d is behind the application (%d)", version, required)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,130 @@
+// Package i18n переводит сообщения API, писем и уведомлений на язык пользователя.
+// Исходные сообщения пишутся в коде на английском и служат ключами каталога,
+// поэтому для английской локали перевод не нужен, а непереведенное сообщение
+// просто остается английским.
+package i18n
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	English = "en"
+	Russian = "ru"
+)
+
+// catalogs — переводы по локали; ключ — исходное сообщение на английском.
+// Ключ, оканчивающийся на ": ", переводит префикс сообщений вида "Failed to ...: <ошибка>".
+var catalogs = map[string]map[string]string{
+	Russian: russian,
+}
+
+// Translate возвращает сообщение на языке locale. У сообщений вида "Префикс: подробности"
+// переводится префикс, а подробности (обычно текст внутренней ошибки) остаются как есть.
+// Неизвестные сообщения и локали возвращаются без изменений.
+func Translate(locale, message string) string {
+	catalog, ok := catalogs[locale]
+	if !ok {
+		return message
+	}
+	if translated, ok := catalog[message]; ok {
+		return translated
+	}
+	if i := strings.Index(message, ": "); i > 0 {
+		if prefix, ok := catalog[message[:i+2]]; ok {
+			return prefix + message[i+2:]
+		}
+	}
+	return message
+}
+
+// Sprintf переводит формат и подставляет в него аргументы
+func Sprintf(locale, format string, args ...interface{}) string {
+	return fmt.Sprintf(Translate(locale, format), args...)
+}
+
+// Match выбирает из supported локаль, лучше всего подходящую заголовку Accept-Language.
+// Региональные варианты сводятся к языку ("ru-RU" → "ru"); "" — совпадений нет.
+func Match(acceptLanguage string, supported []string) string {
+	type candidate struct {
+		tag string
+		q   float64
+	}
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(part, ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if value, ok := strings.CutPrefix(param, "q="); ok {
+				if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+					q = parsed
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, candidate{tag: tag, q: q})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].q > candidates[j].q })
+
+	for _, c := range candidates {
+		language, _, _ := strings.Cut(c.tag, "-")
+		for _, locale := range supported {
+			if locale == c.tag || locale == language {
+				return locale
+			}
+		}
+	}
+	return ""
+}
+
+// FormatDuration записывает длительность словами, например "5 minutes" или "5 минут"
+func FormatDuration(locale string, d time.Duration) string {
+	var n int64
+	var unit string
+	switch {
+	case d >= time.Hour && d%time.Hour == 0:
+		n, unit = int64(d/time.Hour), "hour"
+	case d >= time.Minute && d%time.Minute == 0:
+		n, unit = int64(d/time.Minute), "minute"
+	default:
+		n, unit = int64(d/time.Second), "second"
+	}
+
+	if locale == Russian {
+		forms := russianUnits[unit]
+		return fmt.Sprintf("%d %s", n, forms[russianPluralForm(n)])
+	}
+	if n == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", n, unit)
+}
+
+// russianUnits — формы единиц времени для 1, 2–4 и 5+ (1 минута, 2 минуты, 5 минут)
+var russianUnits = map[string][3]string{
+	"hour":   {"час", "часа", "часов"},
+	"minute": {"минута", "минуты", "минут"},
+	"second": {"секунда", "секунды", "секунд"},
+}
+
+// russianPluralForm возвращает индекс формы множественного числа для русского языка
+func russianPluralForm(n int64) int {
+	switch {
+	case n%10 == 1 && n%100 != 11:
+		return 0
+	case n%10 >= 2 && n%10 <= 4 && (n%100 < 12 || n%100 > 14):
+		return 1
+	default:
+		return 2
+	}
+}
@@ -0,0 +1,684 @@
+package i18n
+
+// russian — каталог переводов на русский язык
+var russian = map[string]string{
+	// Общие ошибки запросов и аутентификации
+	"Invalid request":                                           "Некорректный запрос",
+	"Invalid request data":                                      "Некорректные данные запроса",
+	"Invalid request data: ":                                    "Некорректные данные запроса: ",
+	"Failed to read request body":                               "Не удалось прочитать тело запроса",
+	"System error":                                              "Системная ошибка",
+	"Not found":                                                 "Не найдено",
+	"Too many requests":                                         "Слишком много запросов",
+	"Unauthorized":                                              "Требуется аутентификация",
+	"Access denied":                                             "Доступ запрещен",
+	"Admin access required":                                     "Требуются права администратора",
+	"Error checking admin rights: ":                             "Ошибка проверки прав администратора: ",
+	"Authorization header required":                             "Требуется заголовок Authorization",
+	"Invalid token":                                             "Недействительный токен",
+	"Invalid token: ":                                           "Недействительный токен: ",
+	"Invalid token claims":                                      "Некорректные данные токена",
+	"Invalid user ID in token":                                  "Некорректный ID пользователя в токене",
+	"Token is not valid":                                        "Токен недействителен",
+	"Token has expired":                                         "Срок действия токена истек",
+	"Failed to verify session":                                  "Не удалось проверить сеанс",
+	"Session ended by a newer sign-in":                          "Сеанс завершен более новым входом",
+	"Failed to verify account state":                            "Не удалось проверить состояние учетной записи",
+	"Account is disabled":                                       "Учетная запись отключена",
+	"Password change required":                                  "Требуется сменить пароль",
+	"Service is under maintenance":                              "Ведутся технические работы",
+	"Service is in read-only mode":                              "Изменения временно недоступны: сервис работает только для чтения",
+	"Idempotency-Key is too long":                               "Слишком длинный Idempotency-Key",
+	"%s operations are not supported":                           "Операции %s не поддерживаются",
+	"Unknown field ":                                            "Неизвестное поле ",
+	"fields must list at least one field":                       "В fields должно быть указано хотя бы одно поле",
+	"page must be a positive integer":                           "page должен быть положительным целым числом",
+	"perPage must be between 1 and 100":                         "perPage должен быть от 1 до 100",
+	"query is required":                                         "Параметр query обязателен",
+	"Search query is required":                                  "Требуется поисковый запрос",
+	"Idempotency-Key was already used with a different request": "Idempotency-Key уже использован с другим запросом",
+	"A request with this Idempotency-Key is still in progress":  "Запрос с этим Idempotency-Key еще выполняется",
+
+	// Регистрация, вход и одноразовые коды
+	"Registration is closed":                                         "Регистрация закрыта",
+	"User registration failed":                                       "Не удалось зарегистрировать пользователя",
+	"Password hashing failed":                                        "Не удалось обработать пароль",
+	"Invalid credentials":                                            "Неверное имя пользователя или пароль",
+	"Invalid password":                                               "Неверный пароль",
+	"Invalid OTP code":                                               "Неверный код подтверждения",
+	"Invalid or expired OTP code":                                    "Неверный или просроченный код подтверждения",
+	"Failed to issue OTP code":                                       "Не удалось выдать код подтверждения",
+	"Failed to get OTP channel":                                      "Не удалось получить канал доставки кодов",
+	"Failed to update OTP channel":                                   "Не удалось изменить канал доставки кодов",
+	"Failed to list OTP deliveries":                                  "Не удалось получить историю отправки кодов",
+	"SMS delivery is not available":                                  "Отправка SMS недоступна",
+	"Failed to generate 2FA key":                                     "Не удалось создать ключ 2FA",
+	"Failed to enable 2FA: ":                                         "Не удалось включить 2FA: ",
+	"Captcha verification required":                                  "Требуется пройти проверку captcha",
+	"Captcha verification failed":                                    "Проверка captcha не пройдена",
+	"Captcha verification is temporarily unavailable":                "Проверка captcha временно недоступна",
+	"Phone must be in E.164 format, e.g. +79991234567":               "Телефон должен быть в формате E.164, например +79991234567",
+	"Too many invalid OTP attempts; request a new code":              "Слишком много неверных попыток; запросите новый код",
+	"Too many invalid OTP attempts; sign in again to get a new code": "Слишком много неверных попыток; войдите снова, чтобы получить новый код",
+	"OTP sent via %s":                                                "Код отправлен через %s",
+	"OTP sent via %s; repeat the request with the code":              "Код отправлен через %s; повторите запрос с кодом",
+	"Registration received. If the details are valid, you can now sign in; otherwise check your email": "Заявка на регистрацию принята. Если данные корректны, можно входить; иначе проверьте почту",
+
+	// Пользователи и профиль
+	"User not found":                                                "Пользователь не найден",
+	"Profile not found":                                             "Профиль не найден",
+	"Invalid user ID":                                               "Некорректный ID пользователя",
+	"Invalid user ID format":                                        "Некорректный формат ID пользователя",
+	"Failed to get user: ":                                          "Не удалось получить пользователя: ",
+	"Failed to get users: ":                                         "Не удалось получить пользователей: ",
+	"Failed to look up user: ":                                      "Не удалось найти пользователя: ",
+	"Failed to search users: ":                                      "Не удалось выполнить поиск пользователей: ",
+	"Failed to update profile: ":                                    "Не удалось обновить профиль: ",
+	"Failed to update user status: ":                                "Не удалось изменить статус пользователя: ",
+	"Failed to promote user: ":                                      "Не удалось назначить администратора: ",
+	"Failed to demote user: ":                                       "Не удалось снять права администратора: ",
+	"Profile updated successfully":                                  "Профиль обновлен",
+	"User status updated successfully":                              "Статус пользователя обновлен",
+	"User promoted to admin successfully":                           "Пользователь назначен администратором",
+	"User demoted from admin successfully":                          "Права администратора сняты",
+	"Profile was changed concurrently, reload it and retry":         "Профиль был изменен одновременно с вами, загрузите его заново и повторите",
+	"User was changed concurrently, reload it and retry":            "Пользователь был изменен одновременно с вами, загрузите его заново и повторите",
+	"Unsupported locale; choose one of: ":                           "Язык не поддерживается; выберите один из: ",
+	"Timezone must be an IANA time zone name such as Europe/Moscow": "Часовой пояс должен быть названием IANA, например Europe/Moscow",
+	"The new password must differ from your recent passwords":       "Новый пароль должен отличаться от недавно использованных",
+	"Failed to require password change: ":                           "Не удалось потребовать смену пароля: ",
+	"Password change required; notification email sent":             "Смена пароля потребована; письмо отправлено",
+	"Password change required; notification email was not sent":     "Смена пароля потребована; письмо не отправлено",
+	"Failed to get privacy settings: ":                              "Не удалось получить настройки приватности: ",
+	"Failed to update privacy settings: ":                           "Не удалось изменить настройки приватности: ",
+
+	// Имя пользователя и email
+	"Username is not available":                                                "Имя пользователя недоступно",
+	"Username was changed recently; try again later":                           "Имя пользователя недавно менялось; попробуйте позже",
+	"Failed to change username: ":                                              "Не удалось сменить имя пользователя: ",
+	"Failed to list username history: ":                                        "Не удалось получить историю имен пользователя: ",
+	"Failed to request email change":                                           "Не удалось запросить смену email",
+	"Failed to list email changes: ":                                           "Не удалось получить историю смены email: ",
+	"Profile updated; confirm the new email address using the link sent to it": "Профиль обновлен; подтвердите новый email по ссылке из письма",
+	"Use this address for your LMS account?":                                   "Использовать этот адрес для учетной записи LMS?",
+	"Confirm email address":                                                    "Подтвердить адрес",
+	"Cancel email change":                                                      "Отменить смену email",
+	"Didn't ask to change your LMS email? Cancel the change. If it was already confirmed, " +
+		"your previous address is restored and you will be asked to set a new password.": "Не запрашивали смену email в LMS? Отмените ее. Если смена уже подтверждена, этот адрес будет восстановлен, и вам потребуется задать новый пароль.",
+	"This link is invalid, already used or expired":                                  "Ссылка недействительна, уже использована или устарела",
+	"This address is already used by another account":                                "Этот адрес уже используется другой учетной записью",
+	"Your previous address is now used by another account; contact an administrator": "Прежний адрес уже используется другой учетной записью; обратитесь к администратору",
+	"Failed to confirm the email change":                                             "Не удалось подтвердить смену email",
+	"Failed to cancel the email change":                                              "Не удалось отменить смену email",
+	"Your email address has been changed.":                                           "Адрес электронной почты изменен.",
+	"The email change has been cancelled.":                                           "Смена email отменена.",
+	"Your previous email address has been restored. Sign in and set a new password; other sessions are blocked until you do.": "Прежний адрес восстановлен. Войдите и задайте новый пароль; до этого остальные сеансы заблокированы.",
+
+	// Удаление и блокировка учетной записи
+	"Export your data before deleting the account":                                                      "Перед удалением учетной записи выгрузите свои данные",
+	"Failed to schedule account deletion: ":                                                             "Не удалось запланировать удаление учетной записи: ",
+	"Your account has been deactivated and will be deleted; sign in before the deletion date to cancel": "Учетная запись отключена и будет удалена; чтобы отменить удаление, войдите до указанной даты",
+	"Failed to export progress: ":                                                                       "Не удалось выгрузить прогресс: ",
+	"Failed to export OTP settings: ":                                                                   "Не удалось выгрузить настройки кодов: ",
+	"Failed to export OTP deliveries: ":                                                                 "Не удалось выгрузить историю отправки кодов: ",
+	"Failed to export trusted devices: ":                                                                "Не удалось выгрузить доверенные устройства: ",
+	"Failed to export sign-in history: ":                                                                "Не удалось выгрузить историю входов: ",
+	"Failed to export data: ":                                                                           "Не удалось выгрузить данные: ",
+	"Someone signed in to your LMS account from a new device or location. " +
+		"If it wasn't you, lock the account now. An administrator will need to reactivate it.": "Кто-то вошел в вашу учетную запись LMS с нового устройства или из нового места. " +
+		"Если это были не вы, заблокируйте учетную запись. Восстановить ее сможет администратор.",
+	"Lock my account":            "Заблокировать учетную запись",
+	"Failed to lock the account": "Не удалось заблокировать учетную запись",
+	"Your account has been locked. Contact an administrator to restore access.": "Учетная запись заблокирована. Для восстановления доступа обратитесь к администратору.",
+
+	// Устройства и сеансы
+	"Invalid device ID":                        "Некорректный ID устройства",
+	"Device not found":                         "Устройство не найдено",
+	"Device revoked":                           "Устройство отозвано",
+	"All devices revoked":                      "Все устройства отозваны",
+	"Failed to list trusted devices":           "Не удалось получить доверенные устройства",
+	"Failed to revoke device":                  "Не удалось отозвать устройство",
+	"Failed to revoke devices":                 "Не удалось отозвать устройства",
+	"Failed to list sign-ins":                  "Не удалось получить историю входов",
+	"Failed to update single-session mode: ":   "Не удалось изменить режим одного сеанса: ",
+	"Single-session mode updated successfully": "Режим одного сеанса изменен",
+
+	// Аватары
+	"Avatar must be a PNG, JPEG or GIF image":                             "Аватар должен быть изображением PNG, JPEG или GIF",
+	"Avatar must not exceed %d bytes":                                     "Аватар не должен превышать %d байт",
+	"Image dimensions are too large":                                      "Слишком большие размеры изображения",
+	"Image is too small for an avatar":                                    "Изображение слишком маленькое для аватара",
+	"Failed to process avatar: ":                                          "Не удалось обработать аватар: ",
+	"Failed to save avatar: ":                                             "Не удалось сохранить аватар: ",
+	"Failed to store avatar":                                              "Не удалось сохранить аватар",
+	"Failed to remove avatar: ":                                           "Не удалось удалить аватар: ",
+	"Avatar removed":                                                      "Аватар удален",
+	"Upload an image in the \"avatar\" form field or as the request body": "Загрузите изображение в поле формы \"avatar\" или телом запроса",
+
+	// Курсы и задания
+	"Course not found":               "Курс не найден",
+	"Invalid course ID":              "Некорректный ID курса",
+	"Task not found":                 "Задание не найдено",
+	"Invalid task ID":                "Некорректный ID задания",
+	"Failed to get courses: ":        "Не удалось получить курсы: ",
+	"Failed to get progress: ":       "Не удалось получить прогресс: ",
+	"Failed to get course: ":         "Не удалось получить курс: ",
+	"Translation saved":              "Перевод сохранен",
+	"Translation deleted":            "Перевод удален",
+	"Translation not found":          "Перевод не найден",
+	"Translation must not be empty":  "Перевод не должен быть пустым",
+	"Unsupported locale":             "Язык не поддерживается",
+	"Failed to list translations: ":  "Не удалось получить переводы: ",
+	"Failed to save translation: ":   "Не удалось сохранить перевод: ",
+	"Failed to delete translation: ": "Не удалось удалить перевод: ",
+	"Failed to complete task":        "Не удалось отметить задание выполненным",
+	"Failed to complete tasks":       "Не удалось отметить задания выполненными",
+	"Task completed successfully":    "Задание выполнено",
+
+	// Объявления
+	"Only administrators and instructors can manage announcements": "Управлять объявлениями могут только администраторы и преподаватели",
+	"Instructors can only change their own announcements":          "Преподаватель может изменять только свои объявления",
+	"expiresAt must be after publishAt":                            "expiresAt должен быть позже publishAt",
+	"limit must be between 1 and 100":                              "limit должен быть от 1 до 100",
+	"Invalid announcement ID":                                      "Некорректный ID объявления",
+	"Announcement not found":                                       "Объявление не найдено",
+	"Announcement deleted":                                         "Объявление удалено",
+	"Failed to list announcements: ":                               "Не удалось получить объявления: ",
+	"Failed to get announcement: ":                                 "Не удалось получить объявление: ",
+	"Failed to create announcement: ":                              "Не удалось создать объявление: ",
+	"Failed to update announcement: ":                              "Не удалось изменить объявление: ",
+	"Failed to delete announcement: ":                              "Не удалось удалить объявление: ",
+
+	// Личные сообщения
+	"Messages can only be exchanged between a student and an instructor": "Переписка возможна только между студентом и преподавателем",
+	"The student has not started this course":                            "Студент еще не начал этот курс",
+	"Task does not belong to the conversation course":                    "Задание не относится к курсу переписки",
+	"You cannot report your own message":                                 "Нельзя пожаловаться на свое сообщение",
+	"limit must be between 1 and 200":                                    "limit должен быть от 1 до 200",
+	"Invalid conversation ID":                                            "Некорректный ID переписки",
+	"Invalid message ID":                                                 "Некорректный ID сообщения",
+	"Conversation not found":                                             "Переписка не найдена",
+	"Message not found":                                                  "Сообщение не найдено",
+	"Message reported":                                                   "Жалоба отправлена модераторам",
+	"Message hidden":                                                     "Сообщение скрыто",
+	"Message restored":                                                   "Сообщение восстановлено",
+	"Failed to list conversations: ":                                     "Не удалось получить переписки: ",
+	"Failed to get conversation: ":                                       "Не удалось получить переписку: ",
+	"Failed to open conversation: ":                                      "Не удалось начать переписку: ",
+	"Failed to list messages: ":                                          "Не удалось получить сообщения: ",
+	"Failed to get message: ":                                            "Не удалось получить сообщение: ",
+	"Failed to send message: ":                                           "Не удалось отправить сообщение: ",
+	"Failed to report message: ":                                         "Не удалось отправить жалобу: ",
+	"Failed to moderate message: ":                                       "Не удалось изменить сообщение: ",
+
+	// Календарь
+	"Only administrators and instructors can schedule tasks":       "Назначать сроки заданий могут только администраторы и преподаватели",
+	"Only administrators and instructors can manage live sessions": "Управлять занятиями могут только администраторы и преподаватели",
+	"Instructors can only change their own live sessions":          "Преподаватель может изменять только свои занятия",
+	"The calendar range must not exceed 366 days":                  "Интервал календаря не может превышать 366 дней",
+	"from and to must be RFC 3339 timestamps":                      "from и to должны быть временем в формате RFC 3339",
+	"to must be after from":                                        "to должен быть позже from",
+	"dueAt must be after releaseAt":                                "dueAt должен быть позже releaseAt",
+	"endsAt must be after startsAt":                                "endsAt должен быть позже startsAt",
+	"Invalid live session ID":                                      "Некорректный ID занятия",
+	"Live session not found":                                       "Занятие не найдено",
+	"Live session deleted":                                         "Занятие удалено",
+	"Calendar link not found":                                      "Ссылка на календарь не найдена",
+	"Calendar link revoked":                                        "Ссылка на календарь отозвана",
+	"LMS deadlines":                                                "Сроки LMS",
+	"Task opens: %s":                                               "Открытие задания: %s",
+	"Deadline: %s":                                                 "Срок сдачи: %s",
+	"Failed to build calendar: ":                                   "Не удалось построить календарь: ",
+	"Failed to create calendar link: ":                             "Не удалось создать ссылку на календарь: ",
+	"Failed to revoke calendar link: ":                             "Не удалось отозвать ссылку на календарь: ",
+	"Failed to save schedule: ":                                    "Не удалось сохранить сроки: ",
+	"Failed to create live session: ":                              "Не удалось создать занятие: ",
+	"Failed to get live session: ":                                 "Не удалось получить занятие: ",
+	"Failed to update live session: ":                              "Не удалось изменить занятие: ",
+	"Failed to delete live session: ":                              "Не удалось удалить занятие: ",
+
+	// Обращения в поддержку
+	"Only administrators and instructors can view the ticket queue": "Очередь обращений доступна только администраторам и преподавателям",
+	"Only administrators and instructors can assign tickets":        "Назначать обращения могут только администраторы и преподаватели",
+	"Tickets can only be assigned to instructors":                   "Обращение можно назначить только преподавателю",
+	"Only instructors can add internal notes":                       "Внутренние заметки могут оставлять только преподаватели",
+	"You can only close your own ticket":                            "Вы можете только закрыть свое обращение",
+	"scope must be all or assigned":                                 "scope должен быть all или assigned",
+	"The ticket is closed":                                          "Обращение закрыто",
+	"Unknown ticket status":                                         "Неизвестный статус обращения",
+	"Invalid ticket ID":                                             "Некорректный ID обращения",
+	"Ticket not found":                                              "Обращение не найдено",
+	"Failed to create ticket: ":                                     "Не удалось создать обращение: ",
+	"Failed to list tickets: ":                                      "Не удалось получить обращения: ",
+	"Failed to get ticket: ":                                        "Не удалось получить обращение: ",
+	"Failed to reply to ticket: ":                                   "Не удалось ответить на обращение: ",
+	"Failed to update ticket: ":                                     "Не удалось изменить обращение: ",
+
+	// Опросы
+	"Only administrators and instructors can manage surveys":      "Управлять опросами могут только администраторы и преподаватели",
+	"Only administrators and instructors can view survey results": "Результаты опросов доступны только администраторам и преподавателям",
+	"Questions of a survey with responses cannot be changed":      "Нельзя изменить вопросы опроса, на который уже ответили",
+	"Complete the course to take its survey":                      "Опрос станет доступен после завершения курса",
+	"You have already answered this survey":                       "Вы уже прошли этот опрос",
+	"Unknown survey question":                                     "Неизвестный вопрос опроса",
+	"Duplicate answer to a survey question":                       "Повторный ответ на вопрос опроса",
+	"Rating must be between 1 and 5":                              "Оценка должна быть от 1 до 5",
+	"Text questions take a text answer":                           "На текстовый вопрос нужно ответить текстом",
+	"Answer all required questions":                               "Ответьте на все обязательные вопросы",
+	"Thank you for your feedback":                                 "Спасибо за отзыв",
+	"Survey deleted":                                              "Опрос удален",
+	"Survey not found":                                            "Опрос не найден",
+	"Failed to get survey: ":                                      "Не удалось получить опрос: ",
+	"Failed to save survey: ":                                     "Не удалось сохранить опрос: ",
+	"Failed to delete survey: ":                                   "Не удалось удалить опрос: ",
+	"Failed to submit survey: ":                                   "Не удалось сохранить ответы: ",
+	"Failed to get survey results: ":                              "Не удалось получить результаты опроса: ",
+	"Failed to list surveys: ":                                    "Не удалось получить опросы: ",
+	"Only administrators and instructors can manage course tasks": "Управлять заданиями курса могут только администраторы и преподаватели",
+	"Tasks of a SCORM course come from its package":               "Задания курса SCORM берутся из его пакета",
+	"Failed to add task: ":                                        "Не удалось добавить задание: ",
+	"Failed to update task: ":                                     "Не удалось изменить задание: ",
+	"Failed to get course changes: ":                              "Не удалось получить изменения курса: ",
+	"Failed to mark course changes as seen: ":                     "Не удалось отметить изменения курса просмотренными: ",
+	"Failed to list bookmarks: ":                                  "Не удалось получить закладки: ",
+	"Failed to save bookmark: ":                                   "Не удалось сохранить закладку: ",
+	"Failed to delete bookmark: ":                                 "Не удалось удалить закладку: ",
+	"Bookmark not found":                                          "Закладка не найдена",
+	"Failed to list notes: ":                                      "Не удалось получить заметки: ",
+	"Failed to get note: ":                                        "Не удалось получить заметку: ",
+	"Failed to save note: ":                                       "Не удалось сохранить заметку: ",
+	"Failed to delete note: ":                                     "Не удалось удалить заметку: ",
+	"Failed to export notes: ":                                    "Не удалось выгрузить заметки: ",
+	"Note not found":                                              "Заметка не найдена",
+	"Note deleted":                                                "Заметка удалена",
+	"Note was changed in another window, reload it and retry":     "Заметка изменена в другом окне, обновите ее и повторите",
+	"Bookmark removed":                                            "Закладка удалена",
+	"Bookmarked course is available":                              "Курс из закладок снова доступен",
+	"Course changes marked as seen":                               "Изменения курса отмечены просмотренными",
+
+	// Обсуждения и отзывы
+	"Only course participants can take part in discussions":        "Участвовать в обсуждении могут только участники курса",
+	"Only course participants can review the course":               "Оставить отзыв могут только участники курса",
+	"Only administrators and instructors can moderate discussions": "Модерировать обсуждения могут только администраторы и преподаватели",
+	"Instructors and administrators cannot be banned":              "Нельзя заблокировать преподавателя или администратора",
+	"The user is not banned in this course":                        "Пользователь не заблокирован в этом курсе",
+	"The report has already been resolved":                         "По жалобе уже принято решение",
+	"You cannot report your own content":                           "Нельзя пожаловаться на свое сообщение",
+	"The thread is locked":                                         "Тема закрыта",
+	"Unknown report status":                                        "Неизвестный статус жалобы",
+	"limit must be between 1 and 500":                              "limit должен быть от 1 до 500",
+	"Invalid thread ID":                                            "Некорректный ID темы",
+	"Invalid content ID":                                           "Некорректный ID сообщения",
+	"Invalid report ID":                                            "Некорректный ID жалобы",
+	"Thread not found":                                             "Тема не найдена",
+	"Comment not found":                                            "Сообщение не найдено",
+	"Review not found":                                             "Отзыв не найден",
+	"Report not found":                                             "Жалоба не найдена",
+	"Content reported":                                             "Жалоба отправлена",
+	"Content hidden":                                               "Скрыто",
+	"Content restored":                                             "Восстановлено",
+	"Ban lifted":                                                   "Блокировка снята",
+	"Failed to get discussion: ":                                   "Не удалось получить обсуждение: ",
+	"Failed to create thread: ":                                    "Не удалось создать тему: ",
+	"Failed to add comment: ":                                      "Не удалось отправить сообщение: ",
+	"Failed to get reviews: ":                                      "Не удалось получить отзывы: ",
+	"Failed to save review: ":                                      "Не удалось сохранить отзыв: ",
+	"Failed to report content: ":                                   "Не удалось отправить жалобу: ",
+	"Failed to moderate content: ":                                 "Не удалось выполнить модерацию: ",
+	"Failed to list bans: ":                                        "Не удалось получить блокировки: ",
+	"Failed to ban user: ":                                         "Не удалось заблокировать пользователя: ",
+	"Failed to lift ban: ":                                         "Не удалось снять блокировку: ",
+	"Failed to list reports: ":                                     "Не удалось получить жалобы: ",
+	"Failed to resolve report: ":                                   "Не удалось принять решение по жалобе: ",
+
+	// SCORM
+	"Only administrators and instructors can import SCORM packages":               "Импортировать пакеты SCORM могут только администраторы и преподаватели",
+	"Upload a SCORM package in the \"package\" form field or as the request body": "Загрузите пакет SCORM в поле формы \"package\" или телом запроса",
+	"Only SCORM 1.2 and SCORM 2004 packages are supported":                        "Поддерживаются только пакеты SCORM 1.2 и SCORM 2004",
+	"The course was not imported from a SCORM package":                            "Курс создан не из пакета SCORM",
+	"The package has no imsmanifest.xml":                                          "В пакете нет файла imsmanifest.xml",
+	"The package has no launchable items":                                         "В пакете нет запускаемых учебных объектов",
+	"The task is not SCORM content":                                               "Задание не является учебным объектом SCORM",
+	"Invalid SCORM package: ":                                                     "Некорректный пакет SCORM: ",
+	"Invalid SCORM data: ":                                                        "Некорректные данные SCORM: ",
+	"Failed to store SCORM package":                                               "Не удалось сохранить пакет SCORM",
+	"Failed to import SCORM package: ":                                            "Не удалось импортировать пакет SCORM: ",
+	"Failed to get SCORM package: ":                                               "Не удалось получить пакет SCORM: ",
+	"Failed to get SCORM item: ":                                                  "Не удалось получить учебный объект SCORM: ",
+	"Failed to get SCORM data: ":                                                  "Не удалось получить данные SCORM: ",
+	"Failed to save SCORM data: ":                                                 "Не удалось сохранить данные SCORM: ",
+
+	// Импорт вопросов
+	"Only administrators and instructors can import questions":                 "Импортировать вопросы могут только администраторы и преподаватели",
+	"Upload a question file in the \"file\" form field or as the request body": "Загрузите файл с вопросами в поле формы \"file\" или телом запроса",
+	"format must be gift or xml":                                               "format должен быть gift или xml",
+
+	// Значки
+	"Only administrators and instructors can manage badges": "Управлять значками могут только администраторы и преподаватели",
+	"Invalid badge ID":               "Некорректный ID значка",
+	"Badge not found":                "Значок не найден",
+	"Failed to get badge: ":          "Не удалось получить значок: ",
+	"Failed to get badges: ":         "Не удалось получить значки: ",
+	"This badge has been revoked":    "Значок отозван",
+	"The course already has a badge": "У курса уже есть значок",
+	"Failed to create badge: ":       "Не удалось создать значок: ",
+	"Failed to update badge: ":       "Не удалось изменить значок: ",
+	"Failed to delete badge: ":       "Не удалось удалить значок: ",
+	"Badge deleted":                  "Значок удален",
+	"Upload an image in the \"image\" form field or as the request body": "Загрузите изображение в поле формы \"image\" или телом запроса",
+	"Badge image must be a PNG":       "Изображение значка должно быть в формате PNG",
+	"Failed to save badge image: ":    "Не удалось сохранить изображение значка: ",
+	"Failed to get badge image: ":     "Не удалось получить изображение значка: ",
+	"The user already has this badge": "У пользователя уже есть этот значок",
+	"Failed to award badge: ":         "Не удалось выдать значок: ",
+	"Badge is already revoked":        "Значок уже отозван",
+	"Failed to revoke badge: ":        "Не удалось отозвать значок: ",
+	"format must be png, json or jwt": "format должен быть png, json или jwt",
+	"Failed to bake badge: ":          "Не удалось встроить удостоверение в значок: ",
+
+	// Сертификаты
+	"Certificate not found":                       "Сертификат не найден",
+	"Failed to get certificate: ":                 "Не удалось получить сертификат: ",
+	"Failed to get certificates: ":                "Не удалось получить сертификаты: ",
+	"Only administrators can revoke certificates": "Отзывать сертификаты могут только администраторы",
+	"Certificate is already revoked":              "Сертификат уже отозван",
+	"Failed to revoke certificate: ":              "Не удалось отозвать сертификат: ",
+	"Certificate verification":                    "Проверка сертификата",
+	"The certificate is valid":                    "Сертификат действителен",
+	"The certificate has been revoked":            "Сертификат отозван",
+	"Code":                                        "Код",
+	"Issued to":                                   "Выдан",
+	"Course":                                      "Курс",
+	"Issued":                                      "Дата выдачи",
+	"Revoked":                                     "Дата отзыва",
+	"Reason":                                      "Причина",
+
+	// Telegram
+	"My progress":   "Мой прогресс",
+	"Next deadline": "Ближайший срок",
+	"Unlink the account and stop notifications": "Отвязать аккаунт и отключить уведомления",
+	"What the bot can do":                       "Что умеет бот",
+	"Tasks graded:":                             "Задания засчитаны:",
+	"⏰ Deadline %s: %s (%s)":                    "⏰ Срок сдачи %s: %s (%s)",
+	"Failed to get Telegram account: ":          "Не удалось получить аккаунт Telegram: ",
+	"Telegram bot is not configured":            "Бот Telegram не настроен",
+	"Failed to create Telegram link: ":          "Не удалось создать ссылку для Telegram: ",
+	"Telegram account is not linked":            "Аккаунт Telegram не привязан",
+	"Failed to unlink Telegram account: ":       "Не удалось отвязать аккаунт Telegram: ",
+	"Telegram account unlinked":                 "Аккаунт Telegram отвязан",
+	"Invalid webhook secret":                    "Неверный секрет webhook",
+	"This link is invalid or has expired. Open the Telegram settings in your LMS profile to get a new one.":          "Ссылка недействительна или устарела. Получите новую в настройках Telegram в профиле LMS.",
+	"Something went wrong. Please try again later.":                                                                  "Что-то пошло не так. Попробуйте позже.",
+	"Your LMS account %s is linked. You will get deadline reminders and grading notifications here.":                 "Аккаунт LMS %s привязан. Сюда будут приходить напоминания о сроках и уведомления о проверке заданий.",
+	"This chat is not linked to an LMS account. Open the Telegram settings in your LMS profile and follow the link.": "Этот чат не привязан к аккаунту LMS. Откройте настройки Telegram в профиле LMS и перейдите по ссылке.",
+	"The chat is unlinked, notifications are turned off. You can link it again from your LMS profile.":               "Чат отвязан, уведомления отключены. Привязать его снова можно в профиле LMS.",
+	"You haven't started any course yet.":                                                                            "Вы еще не начали ни одного курса.",
+	"Your progress:":                                                                                                 "Ваш прогресс:",
+	"%s %s: %d of %d tasks":                                                                                          "%s %s: %d из %d заданий",
+	"No upcoming deadlines.":                                                                                         "Ближайших сроков нет.",
+	"Next deadline %s: %s (%s)":                                                                                      "Ближайший срок %s: %s (%s)",
+
+	// Каналы Slack и Mattermost
+	"Invalid chat channel ID": "Некорректный ID канала",
+	"Chat channel not found":  "Канал не найден",
+	"Group not found":         "Группа не найдена",
+	"Either webhookUrl or botToken with channel is required":    "Нужен webhookUrl или botToken вместе с channel",
+	"serverUrl is required for a Mattermost bot":                "Для бота Mattermost нужен serverUrl",
+	"Failed to get chat channels: ":                             "Не удалось получить каналы: ",
+	"Failed to get chat channel: ":                              "Не удалось получить канал: ",
+	"Failed to save chat channel: ":                             "Не удалось сохранить канал: ",
+	"Failed to delete chat channel: ":                           "Не удалось отключить канал: ",
+	"Failed to deliver test message: ":                          "Не удалось доставить тестовое сообщение: ",
+	"Chat channel disconnected":                                 "Канал отключен",
+	"Test message sent":                                         "Тестовое сообщение отправлено",
+	"✅ LMS notifications for %s are connected to this channel.": "✅ Уведомления LMS для группы %s подключены к этому каналу.",
+	"📚 New course published: *%s* (%d tasks)":                   "📚 Опубликован новый курс: *%s* (заданий: %d)",
+	"🏆 Nobody in %s completed a task between %s and %s.":        "🏆 С %[2]s по %[3]s в группе %[1]s никто не выполнил ни одного задания.",
+	"🏆 Leaderboard of %s for %s – %s:":                          "🏆 Таблица лидеров группы %s за %s – %s:",
+	"%d. %s — tasks completed: %d":                              "%d. %s — выполнено заданий: %d",
+
+	// Web Push
+	"Web push is not configured":                   "Web Push не настроен",
+	"Invalid push subscription":                    "Некорректная подписка на уведомления",
+	"Invalid subscription ID":                      "Некорректный ID подписки",
+	"Push subscription not found":                  "Подписка на уведомления не найдена",
+	"Failed to get push subscriptions: ":           "Не удалось получить подписки на уведомления: ",
+	"Failed to save push subscription: ":           "Не удалось сохранить подписку на уведомления: ",
+	"Failed to delete push subscription: ":         "Не удалось удалить подписку на уведомления: ",
+	"Push subscription deleted":                    "Подписка на уведомления удалена",
+	"Failed to send test notification: ":           "Не удалось отправить тестовое уведомление: ",
+	"No browser accepted the notification":         "Ни один браузер не принял уведомление",
+	"Test notification sent":                       "Тестовое уведомление отправлено",
+	"LMS notifications":                            "Уведомления LMS",
+	"Notifications in this browser are turned on.": "Уведомления в этом браузере включены.",
+	"Tasks graded":                                 "Задания засчитаны",
+	"Deadline %s":                                  "Срок сдачи %s",
+
+	// Еженедельная сводка
+	"Failed to get digest settings: ":  "Не удалось получить настройки сводки: ",
+	"Failed to save digest settings: ": "Не удалось сохранить настройки сводки: ",
+	"Stop receiving the weekly LMS digest? You can turn it back on in your account settings.": "Отписаться от еженедельной сводки LMS? Включить ее снова можно в настройках аккаунта.",
+	"Unsubscribe":                                       "Отписаться",
+	"This link is invalid or expired":                   "Ссылка недействительна или устарела",
+	"Failed to unsubscribe":                             "Не удалось отписаться",
+	"You have unsubscribed from the weekly digest.":     "Вы отписались от еженедельной сводки.",
+	"Your LMS week":                                     "Ваша неделя в LMS",
+	"Your LMS week %s":                                  "Ваша неделя в LMS: %s",
+	"Hi %s! Here is your LMS week %s.":                  "Здравствуйте, %s! Итоги вашей недели в LMS: %s.",
+	"Tasks completed: %d":                               "Выполнено заданий: %d",
+	"+%d this week, %d of %d completed":                 "+%d за неделю, выполнено %d из %d",
+	"Your place on the weekly leaderboard: %d of %d":    "Ваше место в рейтинге недели: %d из %d",
+	"Complete a task to get on the weekly leaderboard.": "Выполните задание, чтобы попасть в рейтинг недели.",
+	"Upcoming deadlines":                                "Ближайшие сроки сдачи",
+	"New courses":                                       "Новые курсы",
+	"Tasks: %d":                                         "Заданий: %d",
+	"Continue learning":                                 "Продолжить обучение",
+	"Continue learning: %s":                             "Продолжить обучение: %s",
+	"You receive this email because weekly digests are turned on in your LMS account.": "Вы получили это письмо, потому что в вашем аккаунте LMS включена еженедельная сводка.",
+	"Unsubscribe: %s": "Отписаться: %s",
+
+	// Статистика платформы
+	"Invalid days":               "Некорректное число дней",
+	"Failed to get statistics: ": "Не удалось получить статистику: ",
+
+	// Таблицы лидеров групп и соревнования команд
+	"Failed to get leaderboard: ":                  "Не удалось получить таблицу лидеров: ",
+	"Only group members can view this leaderboard": "Таблицу лидеров видят только участники группы",
+	"Invalid team challenge ID":                    "Некорректный ID соревнования",
+	"Team challenge not found":                     "Соревнование не найдено",
+	"Failed to get team challenges: ":              "Не удалось получить соревнования: ",
+	"Failed to get team challenge: ":               "Не удалось получить соревнование: ",
+	"Failed to save team challenge: ":              "Не удалось сохранить соревнование: ",
+	"Failed to delete team challenge: ":            "Не удалось удалить соревнование: ",
+	"Team challenge deleted":                       "Соревнование удалено",
+	"bestOf is required for best_of scoring":       "Для подсчета best_of нужно указать bestOf",
+	"At least two different groups are required":   "Нужно указать не меньше двух разных групп",
+
+	// Повторное прохождение курсов
+	"Course does not require recertification": "Курс не требует повторного прохождения",
+	"Failed to get recertification: ":         "Не удалось получить настройки повторного прохождения: ",
+	"Failed to save recertification: ":        "Не удалось сохранить настройки повторного прохождения: ",
+	"Failed to delete recertification: ":      "Не удалось отключить повторное прохождение: ",
+	"Recertification disabled":                "Повторное прохождение отключено",
+	"Invalid status":                          "Некорректный статус",
+	"Failed to build compliance report: ":     "Не удалось построить отчет о соответствии: ",
+	"Failed to get recertifications: ":        "Не удалось получить курсы для повторного прохождения: ",
+
+	// Сброс прогресса и повторное прохождение курса
+	"Only administrators and instructors can reset course progress": "Сбрасывать прогресс могут только администраторы и преподаватели",
+	"The user has no progress in this course":                       "У пользователя нет прогресса по этому курсу",
+	"Failed to reset course progress: ":                             "Не удалось сбросить прогресс по курсу: ",
+	"Failed to get course attempts: ":                               "Не удалось получить попытки прохождения курса: ",
+
+	// История прогресса
+	"Failed to get progress history: ": "Не удалось получить историю прогресса: ",
+
+	// Руководители и прогресс команды
+	"A user cannot manage themselves, directly or through their reports": "Пользователь не может быть руководителем самого себя, напрямую или через подчиненных",
+	"Failed to set manager: ":                                           "Не удалось назначить руководителя: ",
+	"Manager updated successfully":                                      "Руководитель обновлен",
+	"Failed to get team: ":                                              "Не удалось получить команду: ",
+	"The user does not report to you":                                   "Пользователь не является вашим подчиненным",
+	"Organization is disabled":                                          "Организация отключена",
+	"Organization admin rights required":                                "Требуются права администратора организации",
+	"Slug may contain only lowercase letters, digits and hyphens":       "Идентификатор может содержать только строчные латинские буквы, цифры и дефисы",
+	"Organization not found":                                            "Организация не найдена",
+	"An organization with this slug already exists":                     "Организация с таким идентификатором уже существует",
+	"Invalid organization ID":                                           "Неверный ID организации",
+	"The default organization cannot be disabled":                       "Организацию по умолчанию нельзя отключить",
+	"User moved to the organization":                                    "Пользователь перенесен в организацию",
+	"Course moved to the organization":                                  "Курс перенесен в организацию",
+	"User is not a member of the organization":                          "Пользователь не состоит в организации",
+	"Organization admins updated":                                       "Администраторы организации обновлены",
+	"Failed to get organizations: ":                                     "Не удалось получить организации: ",
+	"Failed to save organization: ":                                     "Не удалось сохранить организацию: ",
+	"Failed to move user: ":                                             "Не удалось перенести пользователя: ",
+	"Failed to move course: ":                                           "Не удалось перенести курс: ",
+	"Course is archived":                                                "Курс в архиве и закрыт для новых слушателей",
+	"Course archived":                                                   "Курс перенесен в архив",
+	"Course restored":                                                   "Курс возвращен из архива",
+	"Course deleted permanently":                                        "Курс удален безвозвратно",
+	"Archive the course before deleting it permanently":                 "Перед безвозвратным удалением курс нужно перенести в архив",
+	"Failed to list archived courses: ":                                 "Не удалось получить архивные курсы: ",
+	"Failed to archive course: ":                                        "Не удалось перенести курс в архив: ",
+	"Failed to restore course: ":                                        "Не удалось вернуть курс из архива: ",
+	"Failed to purge course: ":                                          "Не удалось удалить курс: ",
+	"Failed to update organization admins: ":                            "Не удалось обновить администраторов организации: ",
+	"Failed to get organization: ":                                      "Не удалось получить организацию: ",
+	"Failed to get organization members: ":                              "Не удалось получить участников организации: ",
+	"Failed to get logo: ":                                              "Не удалось получить логотип: ",
+	"Failed to render certificate: ":                                    "Не удалось сформировать сертификат: ",
+	"Failed to save branding: ":                                         "Не удалось сохранить оформление: ",
+	"Invalid certificate template: ":                                    "Неверный шаблон сертификата: ",
+	"Logo must be a PNG":                                                "Логотип должен быть в формате PNG",
+	"Logo must not exceed %d bytes":                                     "Логотип не должен превышать %d байт",
+	"Logo not found":                                                    "Логотип не найден",
+	"Upload an image in the \"logo\" form field or as the request body": "Загрузите изображение в поле формы \"logo\" или телом запроса",
+	"Invitation revoked":                                                "Приглашение отозвано",
+	"Invitation not found":                                              "Приглашение не найдено",
+	"Invitation is no longer valid":                                     "Приглашение больше не действует",
+	"Invalid invitation ID":                                             "Неверный ID приглашения",
+	"Group not found in the organization":                               "Группа не найдена в организации",
+	"Course not found in the organization":                              "Курс не найден в организации",
+	"Failed to create invitation: ":                                     "Не удалось создать приглашение: ",
+	"Failed to revoke invitation: ":                                     "Не удалось отозвать приглашение: ",
+	"Failed to get invitations: ":                                       "Не удалось получить приглашения: ",
+	"Failed to get invitation: ":                                        "Не удалось получить приглашение: ",
+	"Failed to get assigned courses: ":                                  "Не удалось получить назначенные курсы: ",
+	"Registration received. An administrator will review it and you will get an email with the decision": "Регистрация получена. Администратор рассмотрит ее, и вы получите письмо с решением",
+	"Account is awaiting approval":                                              "Учетная запись ожидает одобрения администратора",
+	"Pending registration not found":                                            "Регистрация, ожидающая одобрения, не найдена",
+	"Registration approved":                                                     "Регистрация одобрена",
+	"Registration rejected":                                                     "Регистрация отклонена",
+	"Failed to get pending registrations: ":                                     "Не удалось получить регистрации, ожидающие одобрения: ",
+	"Failed to process registration: ":                                          "Не удалось обработать регистрацию: ",
+	"Your LMS account has been approved":                                        "Ваша учетная запись LMS одобрена",
+	"An administrator has approved your registration. You can now sign in:\n%s": "Администратор одобрил вашу регистрацию. Теперь вы можете войти:\n%s",
+	"Your LMS registration was declined":                                        "Ваша регистрация в LMS отклонена",
+	"An administrator has declined your LMS registration, so your account was not created.": "Администратор отклонил вашу регистрацию в LMS, поэтому учетная запись не создана.",
+	"Reason: %s":                                            "Причина: %s",
+	"Failed to get access expiries: ":                       "Не удалось получить сроки доступа: ",
+	"Failed to set access expiry: ":                         "Не удалось задать срок доступа: ",
+	"Failed to extend access: ":                             "Не удалось продлить доступ: ",
+	"Access expiry updated":                                 "Срок доступа обновлен",
+	"Access expiry removed":                                 "Срок доступа снят",
+	"Specify either expiresAt or days":                      "Укажите либо expiresAt, либо days",
+	"Specify either userIds or filter":                      "Укажите либо userIds, либо filter",
+	"No changes requested":                                  "Не указано ни одного изменения",
+	"The filter matches too many users, narrow it down":     "Под фильтр подходит слишком много пользователей, уточните его",
+	"Failed to update users: ":                              "Не удалось изменить пользователей: ",
+	"actorId, targetId and limit must be positive integers": "actorId, targetId и limit должны быть положительными целыми числами",
+	"Invalid entry ID":                                      "Некорректный ID записи",
+	"Failed to get activity: ":                              "Не удалось получить ленту действий: ",
+	"Access from your IP address is not allowed":            "Доступ с вашего IP-адреса запрещен",
+	"Access from your country is not allowed":               "Доступ из вашей страны запрещен",
+	"The allowlist must include your current IP address":    "Список разрешенных адресов должен включать ваш текущий IP-адрес",
+	"Invalid IP allowlist: ":                                "Некорректный список разрешенных адресов: ",
+	"Failed to save IP allowlist: ":                         "Не удалось сохранить список разрешенных адресов: ",
+	"LMS accounts deactivated: access expired":              "Учетные записи LMS отключены: срок доступа истек",
+	"The access period of the following LMS accounts has ended, so they were deactivated:\n\n" +
+		"%s\n\n" +
+		"To restore access, extend it in the user administration; the accounts are reactivated automatically.": "Срок доступа следующих учетных записей LMS истек, поэтому они были отключены:\n\n" +
+		"%s\n\n" +
+		"Чтобы вернуть доступ, продлите его в управлении пользователями — учетные записи будут активированы автоматически.",
+	"Course %q is due for renewal": "Подходит срок повторного прохождения курса %q",
+	"Your completion of the course %q is valid until %s. The course must be completed again every period; after that date your progress is reset and the course appears as due.\n\nComplete it again to stay compliant:\n%s": "Прохождение курса %q действительно до %s. Курс нужно периодически проходить заново: после этой даты ваш прогресс будет сброшен, и курс снова появится в списке обязательных.\n\nПройдите его повторно, чтобы не нарушать требования:\n%s",
+	"Course %q must be completed again": "Курс %q нужно пройти повторно",
+	"Your completion of the course %q has expired, so its progress was reset.\n\nComplete the course again to renew it:\n%s": "Срок действия прохождения курса %q истек, поэтому прогресс по нему сброшен.\n\nПройдите курс заново, чтобы продлить его:\n%s",
+
+	// Администрирование
+	"Invalid flag key":                       "Некорректный ключ флага",
+	"Failed to save flag: ":                  "Не удалось сохранить флаг: ",
+	"Failed to delete flag: ":                "Не удалось удалить флаг: ",
+	"Feature flag saved successfully":        "Флаг сохранен",
+	"Feature flag deleted successfully":      "Флаг удален",
+	"Unknown setting":                        "Неизвестный параметр",
+	"Failed to update setting: ":             "Не удалось изменить параметр: ",
+	"Setting updated successfully":           "Параметр изменен",
+	"Failed to update maintenance mode: ":    "Не удалось изменить режим обслуживания: ",
+	"Failed to update maintenance message: ": "Не удалось изменить текст режима обслуживания: ",
+	"Maintenance mode enabled":               "Режим обслуживания включен",
+	"Maintenance mode disabled":              "Режим обслуживания выключен",
+	"Read-only mode enabled":                 "Режим только для чтения включен",
+	"Failed to reload templates: %v":         "Не удалось перезагрузить шаблоны: %v",
+	"Templates reloaded successfully":        "Шаблоны перезагружены",
+	"Message must not be empty":              "Сообщение не должно быть пустым",
+	"Message broadcast":                      "Сообщение разослано",
+	"Real-time channel is not available":     "Канал уведомлений недоступен",
+	"Event stream is not available":          "Поток событий недоступен",
+	"Search is not enabled":                  "Поиск не настроен",
+	"type must be course, task or comment":   "type должен быть course, task или comment",
+	"limit must be between 1 and 50":         "limit должен быть от 1 до 50",
+	"Failed to search: ":                     "Не удалось выполнить поиск: ",
+
+	// Письма
+	"Your Verification Code":                                    "Ваш код подтверждения",
+	"Your verification code is: %s\nThis code is valid for %s.": "Ваш код подтверждения: %s\nКод действует %s.",
+	"Your LMS verification code is: %s. Valid for %s.":          "Код подтверждения LMS: %s. Действует %s.",
+	"Registration attempt with your email":                      "Попытка регистрации с вашим адресом",
+	"Someone tried to create an LMS account with this email address, but an account already exists.\n" +
+		"If it was you, sign in instead. If not, you can ignore this message.": "Кто-то пытался создать учетную запись LMS с этим адресом, но она уже существует.\n" +
+		"Если это были вы, просто войдите. Если нет, проигнорируйте это письмо.",
+	"Your registration was not completed": "Регистрация не завершена",
+	"The username %q is not available, so your LMS account was not created.\n" +
+		"Please register again with a different username.": "Имя пользователя %q недоступно, поэтому учетная запись LMS не создана.\n" +
+		"Зарегистрируйтесь снова с другим именем.",
+	"New sign-in to your LMS account": "Новый вход в вашу учетную запись LMS",
+	"Your account was just signed in to from a new device or location.\n\n" +
+		"Time: %s\nDevice: %s\nLocation: %s\nIP address: %s\n\n" +
+		"If this was you, no action is needed.\n" +
+		"If this wasn't you, lock your account now and contact an administrator:\n%s": "В вашу учетную запись только что вошли с нового устройства или из нового места.\n\n" +
+		"Время: %s\nУстройство: %s\nМесто: %s\nIP-адрес: %s\n\n" +
+		"Если это были вы, ничего делать не нужно.\n" +
+		"Если это были не вы, заблокируйте учетную запись и обратитесь к администратору:\n%s",
+	"Action required: change your LMS password": "Требуется действие: смените пароль LMS",
+	"An administrator has required a password change for your LMS account.\n\n" +
+		"Sign in with your current password and choose a new one; until then the rest of the platform is unavailable:\n" +
+		"%s\n\n" +
+		"If you no longer know your current password, contact an administrator.": "Администратор потребовал сменить пароль вашей учетной записи LMS.\n\n" +
+		"Войдите с текущим паролем и задайте новый; до этого остальные разделы платформы недоступны:\n" +
+		"%s\n\n" +
+		"Если вы не помните текущий пароль, обратитесь к администратору.",
+	"Your LMS account is scheduled for deletion": "Ваша учетная запись LMS будет удалена",
+	"We received a request to delete your LMS account. It has been deactivated and will be " +
+		"permanently deleted on %s together with your course progress.\n\n" +
+		"Changed your mind? Sign in before that date and the deletion will be cancelled.\n" +
+		"If you did not request this, sign in now, change your password and contact an administrator.": "Мы получили запрос на удаление вашей учетной записи LMS. Она отключена и будет " +
+		"окончательно удалена %s вместе с прогрессом по курсам.\n\n" +
+		"Передумали? Войдите до этой даты, и удаление будет отменено.\n" +
+		"Если вы не запрашивали удаление, войдите, смените пароль и обратитесь к администратору.",
+	"Confirm your new LMS email address": "Подтвердите новый адрес для LMS",
+	"Someone asked to use this address for their LMS account.\n\n" +
+		"If it was you, confirm the change within %s:\n%s\n\n" +
+		"If not, ignore this message and the address will not be used.": "Этот адрес указан для учетной записи LMS.\n\n" +
+		"Если это были вы, подтвердите смену по ссылке (она действует %s):\n%s\n\n" +
+		"Если нет, проигнорируйте письмо, и адрес не будет использован.",
+	"Your LMS email address is being changed": "Адрес вашей учетной записи LMS меняется",
+	"A change of your LMS account email to %s was requested. " +
+		"It takes effect once the new address is confirmed.\n\n" +
+		"If this wasn't you, cancel the change; if it was already confirmed, this link restores this address " +
+		"and asks you to set a new password:\n%s": "Запрошена смена email вашей учетной записи LMS на %s. " +
+		"Она вступит в силу после подтверждения нового адреса.\n\n" +
+		"Если это были не вы, отмените смену; если она уже подтверждена, эта ссылка восстановит этот адрес " +
+		"и попросит задать новый пароль:\n%s",
+}
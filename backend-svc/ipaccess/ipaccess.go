@@ -0,0 +1,118 @@
+// Package ipaccess разбирает списки разрешенных адресов и заблокированных стран,
+// которые проверяет middleware ограничения доступа
+package ipaccess
+
+import (
+	"fmt"
+	"net"
+	"strings"
+)
+
+// MaxEntries ограничивает длину одного списка адресов или стран
+const MaxEntries = 100
+
+// List — разобранный список адресов и подсетей; пустой список разрешает любой адрес
+type List []*net.IPNet
+
+// splitEntries делит значение по запятым и пробелам
+func splitEntries(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || r == ' ' || r == '\n' || r == '\t' || r == '\r'
+	})
+}
+
+// ParseEntry разбирает адрес (203.0.113.7) или подсеть в нотации CIDR (203.0.113.0/24)
+func ParseEntry(entry string) (*net.IPNet, error) {
+	if strings.Contains(entry, "/") {
+		_, network, err := net.ParseCIDR(entry)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a valid IP address or CIDR range", entry)
+		}
+		return network, nil
+	}
+	ip := net.ParseIP(entry)
+	if ip == nil {
+		return nil, fmt.Errorf("%q is not a valid IP address or CIDR range", entry)
+	}
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}, nil
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}, nil
+}
+
+// Parse разбирает список адресов и подсетей, разделенных запятыми или пробелами
+func Parse(value string) (List, error) {
+	entries := splitEntries(value)
+	if len(entries) > MaxEntries {
+		return nil, fmt.Errorf("at most %d entries are allowed", MaxEntries)
+	}
+	list := make(List, 0, len(entries))
+	for _, entry := range entries {
+		network, err := ParseEntry(entry)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, network)
+	}
+	return list, nil
+}
+
+// Normalize проверяет записи и приводит их к нотации CIDR
+func Normalize(entries []string) ([]string, error) {
+	list, err := Parse(strings.Join(entries, ","))
+	if err != nil {
+		return nil, err
+	}
+	normalized := make([]string, len(list))
+	for i, network := range list {
+		normalized[i] = network.String()
+	}
+	return normalized, nil
+}
+
+// Allows сообщает, разрешен ли адрес; пустой список разрешает любой адрес,
+// а адрес, который не удалось разобрать, — только пустой список
+func (l List) Allows(ip string) bool {
+	if len(l) == 0 {
+		return true
+	}
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return false
+	}
+	for _, network := range l {
+		if network.Contains(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCountries разбирает список двухбуквенных кодов стран ISO 3166-1 (RU, US)
+func ParseCountries(value string) (map[string]bool, error) {
+	entries := splitEntries(value)
+	if len(entries) > MaxEntries {
+		return nil, fmt.Errorf("at most %d entries are allowed", MaxEntries)
+	}
+	countries := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		code := strings.ToUpper(entry)
+		if len(code) != 2 || code[0] < 'A' || code[0] > 'Z' || code[1] < 'A' || code[1] > 'Z' {
+			return nil, fmt.Errorf("%q is not a two-letter country code", entry)
+		}
+		countries[code] = true
+	}
+	return countries, nil
+}
+
+// Validate проверяет значение runtime-параметра со списком адресов
+func Validate(value string) error {
+	_, err := Parse(value)
+	return err
+}
+
+// ValidateCountries проверяет значение runtime-параметра со списком стран
+func ValidateCountries(value string) error {
+	_, err := ParseCountries(value)
+	return err
+}
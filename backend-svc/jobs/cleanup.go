@@ -0,0 +1,78 @@
+package jobs
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"lmsmodule/backend-svc/storage"
+	"log"
+	"time"
+)
+
+// rowsPurged — количество удаленных/очищенных строк по видам данных
+var rowsPurged = expvar.NewMap("cleanup_rows_purged_total")
+
+// NewCleanupJob создает задачу, очищающую просроченные OTP-коды, доверенные устройства и одноразовые
+// токены, сессии пользователей, не входивших дольше sessionLifetime, уже доставленные события outbox
+// старше eventRetention, ответы для ключей идемпотентности старше idempotencyRetention и учетные записи,
+// срок удаления которых наступил
+func NewCleanupJob(store storage.Storage, interval, sessionLifetime, eventRetention, idempotencyRetention time.Duration) Job {
+	return Job{
+		Name:     "cleanup",
+		Interval: interval,
+		Run: func(ctx context.Context) error {
+			otpCount, err := store.PurgeExpiredOTPCodes()
+			if err != nil {
+				return fmt.Errorf("purge expired otp codes: %w", err)
+			}
+			rowsPurged.Add("otp_codes", otpCount)
+
+			deviceCount, err := store.PurgeTrustedDevices()
+			if err != nil {
+				return fmt.Errorf("purge trusted devices: %w", err)
+			}
+			rowsPurged.Add("trusted_devices", deviceCount)
+
+			tokens, err := store.PurgeExpiredTokens(time.Now())
+			if err != nil {
+				return fmt.Errorf("purge expired tokens: %w", err)
+			}
+			var tokenCount int64
+			for table, n := range tokens {
+				rowsPurged.Add(table, n)
+				tokenCount += n
+			}
+
+			sessionCount, err := store.PurgeStaleSessions(time.Now().Add(-sessionLifetime))
+			if err != nil {
+				return fmt.Errorf("purge stale sessions: %w", err)
+			}
+			rowsPurged.Add("sessions", sessionCount)
+
+			eventCount, err := store.PurgeDispatchedEvents(time.Now().Add(-eventRetention))
+			if err != nil {
+				return fmt.Errorf("purge dispatched events: %w", err)
+			}
+			rowsPurged.Add("outbox_events", eventCount)
+
+			keyCount, err := store.PurgeIdempotencyKeys(time.Now().Add(-idempotencyRetention))
+			if err != nil {
+				return fmt.Errorf("purge idempotency keys: %w", err)
+			}
+			rowsPurged.Add("idempotency_keys", keyCount)
+
+			accountCount, err := store.PurgeDeletedAccounts(time.Now())
+			if err != nil {
+				return fmt.Errorf("purge deleted accounts: %w", err)
+			}
+			rowsPurged.Add("accounts", accountCount)
+
+			if otpCount > 0 || deviceCount > 0 || tokenCount > 0 || sessionCount > 0 || eventCount > 0 || keyCount > 0 || accountCount > 0 {
+				log.Printf("Cleanup: cleared %d expired OTP codes, %d trusted devices, %d expired tokens, %d stale sessions, "+
+					"%d dispatched events, %d idempotency keys, %d deleted accounts",
+					otpCount, deviceCount, tokenCount, sessionCount, eventCount, keyCount, accountCount)
+			}
+			return nil
+		},
+	}
+}
@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"lmsmodule/backend-svc/storage"
+	"time"
+)
+
+// NewDBPingJob создает задачу, периодически проверяющую соединение с базой данных.
+// Результаты публикуются в метриках db_up, db_ping_failures_total и db_reconnects_total.
+func NewDBPingJob(db *sql.DB, interval time.Duration) Job {
+	return Job{
+		Name:     "db-ping",
+		Interval: interval,
+		Local:    true,
+		Run: func(ctx context.Context) error {
+			ctx, cancel := context.WithTimeout(ctx, interval)
+			defer cancel()
+			return storage.PingDB(ctx, db)
+		},
+	}
+}
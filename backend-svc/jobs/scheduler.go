@@ -0,0 +1,130 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"expvar"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+var (
+	jobRuns     = expvar.NewMap("jobs_runs_total")
+	jobFailures = expvar.NewMap("jobs_failures_total")
+	// jobSkips — запуски, пропущенные потому, что аренду задачи держит другая реплика
+	jobSkips = expvar.NewMap("jobs_skipped_total")
+)
+
+// Job — периодическая фоновая задача
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+	// Local — задача обслуживает состояние своего процесса (кэши, соединения) и выполняется на
+	// каждой реплике. Остальные задачи при заданных арендах выполняет одна реплика за интервал.
+	Local bool
+}
+
+// Leases выдает аренду задачи одной реплике на время ttl
+type Leases interface {
+	AcquireJobLease(name, holder string, ttl time.Duration) (bool, error)
+}
+
+// Scheduler запускает зарегистрированные задачи с их интервалами до отмены контекста
+type Scheduler struct {
+	jobs   []Job
+	wg     sync.WaitGroup
+	leases Leases
+	holder string
+}
+
+// NewScheduler создает пустой планировщик
+func NewScheduler() *Scheduler {
+	return &Scheduler{}
+}
+
+// Coordinate включает аренды: перед каждым запуском нелокальной задачи реплика holder берет ее
+// аренду на интервал задачи, поэтому задача выполняется один раз за интервал на всех репликах.
+// Пока держатель жив, он продлевает аренду; после его остановки задачу подхватывает другая
+// реплика не позже чем через интервал. Вызывать до Start.
+func (s *Scheduler) Coordinate(leases Leases, holder string) {
+	s.leases = leases
+	s.holder = holder
+}
+
+// InstanceID возвращает идентификатор реплики для аренд: имя хоста, PID и случайный суффикс,
+// различающий перезапуски
+func InstanceID() string {
+	host, err := os.Hostname()
+	if err != nil {
+		host = "unknown"
+	}
+	suffix := make([]byte, 4)
+	rand.Read(suffix)
+	return fmt.Sprintf("%s-%d-%s", host, os.Getpid(), hex.EncodeToString(suffix))
+}
+
+// Add регистрирует задачу; вызывать до Start
+func (s *Scheduler) Add(job Job) {
+	s.jobs = append(s.jobs, job)
+}
+
+// Start запускает каждую задачу в отдельной горутине
+func (s *Scheduler) Start(ctx context.Context) {
+	for _, job := range s.jobs {
+		s.wg.Add(1)
+		go func(job Job) {
+			defer s.wg.Done()
+			s.loop(ctx, job)
+		}(job)
+		log.Printf("Job %s scheduled every %s", job.Name, job.Interval)
+	}
+}
+
+// Wait блокируется до завершения всех задач после отмены контекста
+func (s *Scheduler) Wait() {
+	s.wg.Wait()
+}
+
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if !s.acquire(job) {
+				continue
+			}
+			jobRuns.Add(job.Name, 1)
+			if err := job.Run(ctx); err != nil {
+				jobFailures.Add(job.Name, 1)
+				log.Printf("Job %s failed: %v", job.Name, err)
+			}
+		}
+	}
+}
+
+// acquire сообщает, должна ли эта реплика выполнить задачу сейчас. Если аренду получить не
+// удалось из-за ошибки, запуск пропускается: без базы задачи все равно не выполнятся.
+func (s *Scheduler) acquire(job Job) bool {
+	if job.Local || s.leases == nil {
+		return true
+	}
+	ok, err := s.leases.AcquireJobLease(job.Name, s.holder, job.Interval)
+	if err != nil {
+		jobFailures.Add(job.Name, 1)
+		log.Printf("Job %s skipped: acquire lease: %v", job.Name, err)
+		return false
+	}
+	if !ok {
+		jobSkips.Add(job.Name, 1)
+	}
+	return ok
+}
@@ -0,0 +1,135 @@
+package mail
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"strings"
+	"sync"
+)
+
+// Branding — оформление писем организации получателя; нулевое значение — оформление по умолчанию
+type Branding struct {
+	OrganizationName string
+	LogoURL          string
+	PrimaryColor     string
+	AccentColor      string
+	// Footer добавляется в конец письма
+	Footer string
+}
+
+// IsZero сообщает, что оформление не задано
+func (b Branding) IsZero() bool {
+	return b.LogoURL == "" && b.PrimaryColor == "" && b.AccentColor == "" && b.Footer == ""
+}
+
+var (
+	brandingMu sync.RWMutex
+	brandingOf func(email string) Branding
+)
+
+// SetBrandingResolver задает, как находить оформление письма по адресу получателя. Без него
+// все письма отправляются без оформления.
+func SetBrandingResolver(resolve func(email string) Branding) {
+	brandingMu.Lock()
+	defer brandingMu.Unlock()
+	brandingOf = resolve
+}
+
+// brandingFor возвращает оформление писем для адреса email
+func brandingFor(email string) Branding {
+	brandingMu.RLock()
+	resolve := brandingOf
+	brandingMu.RUnlock()
+	if resolve == nil {
+		return Branding{}
+	}
+	return resolve(email)
+}
+
+// brandingPartials — шапка с логотипом и подпись, общие для HTML-писем
+const brandingPartials = `{{define "brandHeader"}}{{if or .LogoURL .PrimaryColor}}<div style="padding: 12px 16px; background: {{if .PrimaryColor}}{{.PrimaryColor}}{{else}}#ffffff{{end}};">
+{{if .LogoURL}}<img src="{{.LogoURL}}" alt="{{.OrganizationName}}" style="max-height: 48px;">{{else}}<b style="color: #ffffff;">{{.OrganizationName}}</b>{{end}}
+</div>{{end}}{{end}}
+{{define "brandFooter"}}{{if .Footer}}<p style="font-size: small; color: #777; white-space: pre-line;">{{.Footer}}</p>{{end}}{{end}}`
+
+// brandedPlainTemplate — HTML-версия текстового письма для организаций с оформлением
+var brandedPlainTemplate = htmltemplate.Must(htmltemplate.Must(htmltemplate.New("plain.html").Parse(brandingPartials)).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body style="font-family: sans-serif; color: #222; max-width: 600px;">
+{{template "brandHeader" .Branding}}
+{{range .Paragraphs}}<p style="white-space: pre-line;">{{.}}</p>
+{{end}}<hr{{if .Branding.AccentColor}} style="border-color: {{.Branding.AccentColor}};"{{end}}>
+{{template "brandFooter" .Branding}}
+</body></html>`))
+
+// withFooter добавляет к тексту письма подпись организации
+func withFooter(text string, branding Branding) string {
+	if branding.Footer == "" {
+		return text
+	}
+	return strings.TrimRight(text, "\n") + "\n\n-- \n" + branding.Footer + "\n"
+}
+
+// alternativeParts собирает тело multipart/alternative из текстовой и HTML-частей
+func alternativeParts(text, html []byte) (body []byte, boundary string, err error) {
+	var buf bytes.Buffer
+	parts := multipart.NewWriter(&buf)
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{"text/plain; charset=UTF-8", bytes.ReplaceAll(text, []byte("\n"), []byte("\r\n"))},
+		{"text/html; charset=UTF-8", html},
+	} {
+		w, err := parts.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, "", err
+		}
+		qp := quotedprintable.NewWriter(w)
+		if _, err := qp.Write(part.content); err != nil {
+			return nil, "", err
+		}
+		if err := qp.Close(); err != nil {
+			return nil, "", err
+		}
+	}
+	if err := parts.Close(); err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), parts.Boundary(), nil
+}
+
+// sendBrandedEmail отправляет текстовое письмо вместе с HTML-версией в оформлении организации
+func sendBrandedEmail(email, subject, body string, branding Branding) error {
+	var html bytes.Buffer
+	err := brandedPlainTemplate.Execute(&html, struct {
+		Subject    string
+		Paragraphs []string
+		Branding   Branding
+	}{subject, strings.Split(body, "\n\n"), branding})
+	if err != nil {
+		return err
+	}
+	parts, boundary, err := alternativeParts([]byte(withFooter(body, branding)), html.Bytes())
+	if err != nil {
+		return err
+	}
+
+	conf := currentSMTP()
+	var message bytes.Buffer
+	message.WriteString("From: " + conf.From + "\r\n" +
+		"To: " + email + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"Content-Type: multipart/alternative; boundary=" + boundary + "\r\n" +
+		"\r\n")
+	message.Write(parts)
+	return deliver(email, subject, message.Bytes())
+}
@@ -3,41 +3,40 @@ package mail
 import (
 	"fmt"
 	"html/template"
+	"lmsmodule/backend-svc/config"
 	"os"
 	"path/filepath"
+	"sync"
 )
 
 var (
-	smtpHost     = "smtp.mail.ru"
-	smtpPort     = "465"
-	smtpUsername = "tristan.nik@mail.ru"
-	smtpPassword = "xYCkbEGbm4Vf4WrrMX5H"
-	smtpFrom     = "Cybersecurity Platform <tristan.nik@mail.ru>"
+	smtpMu   sync.RWMutex
+	smtpConf config.SMTPConfig
 
 	emailTemplates map[string]*template.Template
 )
 
 func init() {
-	loadEnvironmentVariables()
 	loadEmailTemplates()
 }
 
-func loadEnvironmentVariables() {
-	if host := os.Getenv("SMTP_HOST"); host != "" {
-		smtpHost = host
-	}
-	if port := os.Getenv("SMTP_PORT"); port != "" {
-		smtpPort = port
-	}
-	if username := os.Getenv("SMTP_USERNAME"); username != "" {
-		smtpUsername = username
-	}
-	if password := os.Getenv("SMTP_PASSWORD"); password != "" {
-		smtpPassword = password
-	}
-	if from := os.Getenv("SMTP_FROM"); from != "" {
-		smtpFrom = from
+// Configure задает параметры SMTP-сервера из конфигурации сервиса.
+// Безопасно вызывать повторно во время работы, например после ротации пароля.
+func Configure(cfg config.SMTPConfig) {
+	if cfg.From == "" {
+		cfg.From = cfg.Username
 	}
+
+	smtpMu.Lock()
+	defer smtpMu.Unlock()
+	smtpConf = cfg
+}
+
+// currentSMTP возвращает снимок текущих параметров SMTP
+func currentSMTP() config.SMTPConfig {
+	smtpMu.RLock()
+	defer smtpMu.RUnlock()
+	return smtpConf
 }
 
 func loadEmailTemplates() {
@@ -0,0 +1,133 @@
+package mail
+
+import (
+	"bytes"
+	htmltemplate "html/template"
+	"lmsmodule/backend-svc/i18n"
+	"mime"
+	"text/template"
+	"time"
+)
+
+// DigestItem — строка раздела сводки: курс, задание или место в рейтинге
+type DigestItem struct {
+	Title  string
+	Detail string
+}
+
+// WeeklyDigest — содержимое еженедельной сводки за неделю [From, To)
+type WeeklyDigest struct {
+	Username string
+	From, To time.Time
+	// Progress — курсы, в которых пользователь выполнял задания за неделю
+	Progress []DigestItem
+	// TasksCompleted — сколько заданий выполнено за неделю
+	TasksCompleted int
+	// Deadlines — сроки сдачи на ближайшую неделю
+	Deadlines []DigestItem
+	// NewCourses — курсы, появившиеся за неделю
+	NewCourses []DigestItem
+	// Rank — место в рейтинге недели среди Participants участников; 0 — пользователь ничего не выполнил
+	Rank, Participants int
+	CoursesURL         string
+	UnsubscribeURL     string
+	// TimeZone — часовой пояс получателя для дат; nil — UTC
+	TimeZone *time.Location
+	Locale   string // язык письма
+}
+
+// digestView добавляет к сводке перевод строк шаблона на язык получателя и оформление его организации
+type digestView struct {
+	WeeklyDigest
+	Branding Branding
+}
+
+// T переводит строку шаблона; args подставляются как в i18n.Sprintf
+func (d digestView) T(format string, args ...interface{}) string {
+	return i18n.Sprintf(d.Locale, format, args...)
+}
+
+// Period записывает неделю сводки датами в часовом поясе получателя
+func (d digestView) Period() string {
+	loc := d.TimeZone
+	if loc == nil {
+		loc = time.UTC
+	}
+	// Неделя заканчивается утром понедельника, поэтому последним днем показывается воскресенье
+	return d.From.In(loc).Format("2006-01-02") + " – " + d.To.AddDate(0, 0, -1).In(loc).Format("2006-01-02")
+}
+
+const digestText = `{{.T "Hi %s! Here is your LMS week %s." .Username .Period}}
+
+{{.T "Tasks completed: %d" .TasksCompleted}}
+{{range .Progress}}- {{.Title}}: {{.Detail}}
+{{end}}{{if .Rank}}{{.T "Your place on the weekly leaderboard: %d of %d" .Rank .Participants}}
+{{else}}{{.T "Complete a task to get on the weekly leaderboard."}}
+{{end}}{{if .Deadlines}}
+{{.T "Upcoming deadlines"}}
+{{range .Deadlines}}- {{.Title}}: {{.Detail}}
+{{end}}{{end}}{{if .NewCourses}}
+{{.T "New courses"}}
+{{range .NewCourses}}- {{.Title}}{{if .Detail}}: {{.Detail}}{{end}}
+{{end}}{{end}}
+{{.T "Continue learning: %s" .CoursesURL}}
+
+{{.T "You receive this email because weekly digests are turned on in your LMS account."}}
+{{.T "Unsubscribe: %s" .UnsubscribeURL}}
+{{with .Branding.Footer}}
+-- 
+{{.}}
+{{end}}`
+
+var digestTextTemplate = template.Must(template.New("digest.txt").Parse(digestText))
+
+var digestHTMLTemplate = htmltemplate.Must(htmltemplate.Must(htmltemplate.New("digest.html").Parse(brandingPartials)).Parse(`<!DOCTYPE html>
+<html><head><meta charset="utf-8"><title>{{.T "Your LMS week"}}</title></head>
+<body style="font-family: sans-serif; color: #222; max-width: 600px;">
+{{template "brandHeader" .Branding}}
+<p>{{.T "Hi %s! Here is your LMS week %s." .Username .Period}}</p>
+<h3>{{.T "Tasks completed: %d" .TasksCompleted}}</h3>
+{{if .Progress}}<ul>{{range .Progress}}<li><b>{{.Title}}</b>: {{.Detail}}</li>{{end}}</ul>{{end}}
+<p>{{if .Rank}}{{.T "Your place on the weekly leaderboard: %d of %d" .Rank .Participants}}{{else}}{{.T "Complete a task to get on the weekly leaderboard."}}{{end}}</p>
+{{if .Deadlines}}<h3>{{.T "Upcoming deadlines"}}</h3>
+<ul>{{range .Deadlines}}<li><b>{{.Title}}</b>: {{.Detail}}</li>{{end}}</ul>{{end}}
+{{if .NewCourses}}<h3>{{.T "New courses"}}</h3>
+<ul>{{range .NewCourses}}<li><b>{{.Title}}</b>{{if .Detail}}: {{.Detail}}{{end}}</li>{{end}}</ul>{{end}}
+<p><a href="{{.CoursesURL}}"{{with .Branding.AccentColor}} style="color: {{.}};"{{end}}>{{.T "Continue learning"}}</a></p>
+<hr>
+<p style="font-size: small; color: #777;">{{.T "You receive this email because weekly digests are turned on in your LMS account."}}
+<a href="{{.UnsubscribeURL}}">{{.T "Unsubscribe"}}</a></p>
+{{template "brandFooter" .Branding}}
+</body></html>`))
+
+// SendWeeklyDigest отправляет сводку письмом из текстовой и HTML-частей. Заголовки List-Unsubscribe
+// позволяют отписаться кнопкой почтового клиента (RFC 8058).
+func SendWeeklyDigest(email string, digest WeeklyDigest) error {
+	view := digestView{digest, brandingFor(email)}
+	var text, html bytes.Buffer
+	if err := digestTextTemplate.Execute(&text, view); err != nil {
+		return err
+	}
+	if err := digestHTMLTemplate.Execute(&html, view); err != nil {
+		return err
+	}
+
+	body, boundary, err := alternativeParts(text.Bytes(), html.Bytes())
+	if err != nil {
+		return err
+	}
+
+	subject := view.T("Your LMS week %s", view.Period())
+	conf := currentSMTP()
+	var message bytes.Buffer
+	message.WriteString("From: " + conf.From + "\r\n" +
+		"To: " + email + "\r\n" +
+		"Subject: " + mime.QEncoding.Encode("UTF-8", subject) + "\r\n" +
+		"MIME-Version: 1.0\r\n" +
+		"List-Unsubscribe: <" + digest.UnsubscribeURL + ">\r\n" +
+		"List-Unsubscribe-Post: List-Unsubscribe=One-Click\r\n" +
+		"Content-Type: multipart/alternative; boundary=" + boundary + "\r\n" +
+		"\r\n")
+	message.Write(body)
+	return deliver(email, subject, message.Bytes())
+}
@@ -4,33 +4,51 @@ import (
 	"bytes"
 	"crypto/tls"
 	"fmt"
+	"lmsmodule/backend-svc/i18n"
+	"mime"
 	"net/smtp"
 	"strings"
 	"time"
 )
 
 type OTPEmailData struct {
-	Code string
+	Code     string
+	ValidFor string // срок действия кода, например "5 minutes"
 }
 
-func SendOTPEmail(email, code string) error {
+// FormatValidity записывает срок действия кода для писем и SMS на языке locale,
+// например "5 minutes" или "90 секунд"
+func FormatValidity(ttl time.Duration, locale string) string {
+	return i18n.FormatDuration(locale, ttl)
+}
+
+// SendOTPEmail отправляет код подтверждения. HTML-шаблон есть только на английском,
+// поэтому для других языков письмо отправляется простым текстом.
+func SendOTPEmail(email, code string, ttl time.Duration, locale string) error {
+	validFor := FormatValidity(ttl, locale)
+	if locale != i18n.English {
+		return sendPlainEmail(email, i18n.Translate(locale, "Your Verification Code"),
+			i18n.Sprintf(locale, "Your verification code is: %s\nThis code is valid for %s.", code, validFor))
+	}
 	template, ok := emailTemplates["otp_email"]
 	if !ok {
 		fmt.Printf("Email template not found, using fallback template\n")
-		return sendOTPEmailFallback(email, code)
+		return sendOTPEmailFallback(email, code, validFor)
 	}
 
 	data := OTPEmailData{
-		Code: code,
+		Code:     code,
+		ValidFor: validFor,
 	}
 
 	var bodyBuffer bytes.Buffer
 	if err := template.Execute(&bodyBuffer, data); err != nil {
 		fmt.Printf("Error executing email template: %v\n", err)
-		return sendOTPEmailFallback(email, code)
+		return sendOTPEmailFallback(email, code, validFor)
 	}
 
-	auth := smtp.PlainAuth("", smtpUsername, smtpPassword, smtpHost)
+	conf := currentSMTP()
+	auth := smtp.PlainAuth("", conf.Username, conf.Password, conf.Host)
 
 	boundary := "boundary-" + strings.ReplaceAll(time.Now().String(), " ", "-")
 	subject := "Your Verification Code"
@@ -44,7 +62,7 @@ func SendOTPEmail(email, code string) error {
 		"--%s\r\n"+
 		"Content-Type: text/plain; charset=UTF-8\r\n"+
 		"\r\n"+
-		"Your verification code is: %s\r\nThis code is valid for 5 minutes.\r\n"+
+		"Your verification code is: %s\r\nThis code is valid for %s.\r\n"+
 		"\r\n"+
 		"--%s\r\n"+
 		"Content-Type: text/html; charset=UTF-8\r\n"+
@@ -52,9 +70,9 @@ func SendOTPEmail(email, code string) error {
 		"%s\r\n"+
 		"\r\n"+
 		"--%s--",
-		smtpFrom, email, subject, boundary, boundary, code, boundary, bodyBuffer.String(), boundary))
+		conf.From, email, subject, boundary, boundary, code, validFor, boundary, bodyBuffer.String(), boundary))
 
-	err := smtp.SendMail(smtpHost+":"+smtpPort, auth, smtpUsername, []string{email}, message)
+	err := smtp.SendMail(conf.Host+":"+conf.Port, auth, conf.Username, []string{email}, message)
 	if err != nil {
 		fmt.Printf("Error sending email: %v\n", err)
 		fmt.Printf("OTP for %s: %s\n", email, code)
@@ -65,33 +83,34 @@ func SendOTPEmail(email, code string) error {
 	return nil
 }
 
-func sendOTPEmailFallback(email, code string) error {
+func sendOTPEmailFallback(email, code, validFor string) error {
+	conf := currentSMTP()
 	tlsConfig := &tls.Config{
-		ServerName: smtpHost,
+		ServerName: conf.Host,
 	}
 
-	conn, err := tls.Dial("tcp", smtpHost+":"+smtpPort, tlsConfig)
+	conn, err := tls.Dial("tcp", conf.Host+":"+conf.Port, tlsConfig)
 	if err != nil {
 		fmt.Printf("SSL connection error: %v\n", err)
 		fmt.Printf("OTP for %s: %s\n", email, code)
 		return err
 	}
 
-	client, err := smtp.NewClient(conn, smtpHost)
+	client, err := smtp.NewClient(conn, conf.Host)
 	if err != nil {
 		fmt.Printf("Error creating SMTP client: %v\n", err)
 		return err
 	}
 	defer client.Close()
 
-	auth := smtp.PlainAuth("", smtpUsername, smtpPassword, smtpHost)
+	auth := smtp.PlainAuth("", conf.Username, conf.Password, conf.Host)
 	if err = client.Auth(auth); err != nil {
 		fmt.Printf("Authentication error: %v\n", err)
 		fmt.Printf("OTP for %s: %s\n", email, code)
 		return err
 	}
 
-	if err = client.Mail(smtpUsername); err != nil {
+	if err = client.Mail(conf.Username); err != nil {
 		return err
 	}
 
@@ -104,7 +123,7 @@ func sendOTPEmailFallback(email, code string) error {
 		return err
 	}
 
-	plainText := fmt.Sprintf("Your verification code is: %s\nThis code is valid for 5 minutes.", code)
+	plainText := fmt.Sprintf("Your verification code is: %s\nThis code is valid for %s.", code, validFor)
 
 	message := []byte(fmt.Sprintf("From: %s\r\n"+
 		"To: %s\r\n"+
@@ -112,7 +131,7 @@ func sendOTPEmailFallback(email, code string) error {
 		"Content-Type: text/plain; charset=UTF-8\r\n"+
 		"\r\n"+
 		"%s",
-		smtpFrom, email, plainText))
+		conf.From, email, plainText))
 
 	_, err = w.Write(message)
 	if err != nil {
@@ -127,3 +146,154 @@ func sendOTPEmailFallback(email, code string) error {
 	fmt.Printf("Fallback email sent successfully to %s\n", email)
 	return nil
 }
+
+// SendAccountExistsEmail сообщает владельцу адреса, что кто-то пытался зарегистрироваться с ним.
+// Ответ на регистрацию одинаков в обоих случаях, поэтому узнать о дубликате можно только из письма.
+func SendAccountExistsEmail(email, locale string) error {
+	return sendPlainEmail(email, i18n.Translate(locale, "Registration attempt with your email"),
+		i18n.Translate(locale, "Someone tried to create an LMS account with this email address, but an account already exists.\n"+
+			"If it was you, sign in instead. If not, you can ignore this message."))
+}
+
+// SendUsernameTakenEmail сообщает, что регистрация не завершена из-за занятого имени пользователя
+func SendUsernameTakenEmail(email, username, locale string) error {
+	return sendPlainEmail(email, i18n.Translate(locale, "Your registration was not completed"),
+		i18n.Sprintf(locale, "The username %q is not available, so your LMS account was not created.\n"+
+			"Please register again with a different username.", username))
+}
+
+// sendPlainEmail отправляет текстовое письмо; участникам организаций с оформлением письмо
+// дополнительно получает HTML-версию с логотипом, цветами и подписью организации
+func sendPlainEmail(email, subject, body string) error {
+	if branding := brandingFor(email); !branding.IsZero() {
+		return sendBrandedEmail(email, subject, body, branding)
+	}
+	conf := currentSMTP()
+
+	message := []byte(fmt.Sprintf("From: %s\r\n"+
+		"To: %s\r\n"+
+		"Subject: %s\r\n"+
+		"Content-Type: text/plain; charset=UTF-8\r\n"+
+		"\r\n"+
+		"%s",
+		conf.From, email, mime.QEncoding.Encode("UTF-8", subject), strings.ReplaceAll(body, "\n", "\r\n")))
+	return deliver(email, subject, message)
+}
+
+// deliver передает готовое письмо SMTP-серверу
+func deliver(email, subject string, message []byte) error {
+	conf := currentSMTP()
+	auth := smtp.PlainAuth("", conf.Username, conf.Password, conf.Host)
+	if err := smtp.SendMail(conf.Host+":"+conf.Port, auth, conf.Username, []string{email}, message); err != nil {
+		fmt.Printf("Error sending email %q to %s: %v\n", subject, email, err)
+		return err
+	}
+	return nil
+}
+
+// NewLoginAlert — сведения о входе с нового устройства или из нового места
+type NewLoginAlert struct {
+	Time     time.Time
+	IP       string
+	Location string
+	Device   string
+	LockURL  string // ссылка «это был не я», блокирующая учетную запись
+	// TimeZone — часовой пояс получателя для времени входа; nil — UTC
+	TimeZone *time.Location
+	Locale   string // язык письма
+}
+
+// formatLocalTime записывает время в часовом поясе получателя с сокращением пояса,
+// например "2025-03-01 18:30 MSK"
+func formatLocalTime(t time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	return t.In(loc).Format("2006-01-02 15:04 MST")
+}
+
+// SendNewLoginAlert предупреждает пользователя о входе с незнакомого устройства или места
+func SendNewLoginAlert(email string, alert NewLoginAlert) error {
+	return sendPlainEmail(email, i18n.Translate(alert.Locale, "New sign-in to your LMS account"),
+		i18n.Sprintf(alert.Locale, "Your account was just signed in to from a new device or location.\n\n"+
+			"Time: %s\nDevice: %s\nLocation: %s\nIP address: %s\n\n"+
+			"If this was you, no action is needed.\n"+
+			"If this wasn't you, lock your account now and contact an administrator:\n%s",
+			formatLocalTime(alert.Time, alert.TimeZone), alert.Device, alert.Location, alert.IP, alert.LockURL))
+}
+
+// SendPasswordChangeRequired сообщает, что администратор потребовал сменить пароль
+func SendPasswordChangeRequired(email, signInURL, locale string) error {
+	return sendPlainEmail(email, i18n.Translate(locale, "Action required: change your LMS password"),
+		i18n.Sprintf(locale, "An administrator has required a password change for your LMS account.\n\n"+
+			"Sign in with your current password and choose a new one; until then the rest of the platform is unavailable:\n"+
+			"%s\n\n"+
+			"If you no longer know your current password, contact an administrator.", signInURL))
+}
+
+// SendAccountDeletionScheduled подтверждает запрос на удаление учетной записи и объясняет, как его отменить
+func SendAccountDeletionScheduled(email string, purgeAt time.Time, loc *time.Location, locale string) error {
+	return sendPlainEmail(email, i18n.Translate(locale, "Your LMS account is scheduled for deletion"),
+		i18n.Sprintf(locale, "We received a request to delete your LMS account. It has been deactivated and will be "+
+			"permanently deleted on %s together with your course progress.\n\n"+
+			"Changed your mind? Sign in before that date and the deletion will be cancelled.\n"+
+			"If you did not request this, sign in now, change your password and contact an administrator.",
+			formatLocalTime(purgeAt, loc)))
+}
+
+// SendEmailChangeConfirmation отправляет на новый адрес ссылку, подтверждающую смену email
+func SendEmailChangeConfirmation(email, confirmURL string, validFor time.Duration, locale string) error {
+	return sendPlainEmail(email, i18n.Translate(locale, "Confirm your new LMS email address"),
+		i18n.Sprintf(locale, "Someone asked to use this address for their LMS account.\n\n"+
+			"If it was you, confirm the change within %s:\n%s\n\n"+
+			"If not, ignore this message and the address will not be used.",
+			FormatValidity(validFor, locale), confirmURL))
+}
+
+// SendEmailChangeNotice предупреждает прежний адрес о запрошенной смене email и дает ссылку для отмены
+func SendEmailChangeNotice(email, newEmail, cancelURL, locale string) error {
+	return sendPlainEmail(email, i18n.Translate(locale, "Your LMS email address is being changed"),
+		i18n.Sprintf(locale, "A change of your LMS account email to %s was requested. "+
+			"It takes effect once the new address is confirmed.\n\n"+
+			"If this wasn't you, cancel the change; if it was already confirmed, this link restores this address "+
+			"and asks you to set a new password:\n%s", newEmail, cancelURL))
+}
+
+// SendRecertificationReminder напоминает, что срок прохождения курса скоро истечет
+func SendRecertificationReminder(email, courseTitle string, expiresAt time.Time, loc *time.Location, coursesURL, locale string) error {
+	return sendPlainEmail(email, i18n.Sprintf(locale, "Course %q is due for renewal", courseTitle),
+		i18n.Sprintf(locale, "Your completion of the course %q is valid until %s. The course must be completed again "+
+			"every period; after that date your progress is reset and the course appears as due.\n\n"+
+			"Complete it again to stay compliant:\n%s", courseTitle, formatLocalTime(expiresAt, loc), coursesURL))
+}
+
+// SendRecertificationDue сообщает, что срок прохождения курса истек и его нужно пройти заново
+func SendRecertificationDue(email, courseTitle, coursesURL, locale string) error {
+	return sendPlainEmail(email, i18n.Sprintf(locale, "Course %q must be completed again", courseTitle),
+		i18n.Sprintf(locale, "Your completion of the course %q has expired, so its progress was reset.\n\n"+
+			"Complete the course again to renew it:\n%s", courseTitle, coursesURL))
+}
+
+// SendRegistrationApproved сообщает, что администратор одобрил регистрацию
+func SendRegistrationApproved(email, signInURL, locale string) error {
+	return sendPlainEmail(email, i18n.Translate(locale, "Your LMS account has been approved"),
+		i18n.Sprintf(locale, "An administrator has approved your registration. You can now sign in:\n%s", signInURL))
+}
+
+// SendRegistrationRejected сообщает, что администратор отклонил регистрацию; reason может быть пустым
+func SendRegistrationRejected(email, reason, locale string) error {
+	body := i18n.Translate(locale, "An administrator has declined your LMS registration, so your account was not created.")
+	if reason != "" {
+		body += "\n\n" + i18n.Sprintf(locale, "Reason: %s", reason)
+	}
+	return sendPlainEmail(email, i18n.Translate(locale, "Your LMS registration was declined"), body)
+}
+
+// SendAccessExpired сообщает администратору, какие учетные записи отключены по истечении срока доступа
+func SendAccessExpired(email string, accounts []string, locale string) error {
+	return sendPlainEmail(email, i18n.Translate(locale, "LMS accounts deactivated: access expired"),
+		i18n.Sprintf(locale, "The access period of the following LMS accounts has ended, so they were deactivated:\n\n"+
+			"%s\n\n"+
+			"To restore access, extend it in the user administration; the accounts are reactivated automatically.",
+			"- "+strings.Join(accounts, "\n- ")))
+}
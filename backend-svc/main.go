@@ -1,23 +1,48 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	_ "fmt"
+	"errors"
+	"expvar"
+	"flag"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	swaggerFiles "github.com/swaggo/files"
 	ginSwagger "github.com/swaggo/gin-swagger"
+	"google.golang.org/grpc"
+	"lmsmodule/backend-svc/config"
 	_ "lmsmodule/backend-svc/docs"
+	"lmsmodule/backend-svc/flags"
+	"lmsmodule/backend-svc/grpcapi"
 	"lmsmodule/backend-svc/handlers"
-	_ "lmsmodule/backend-svc/mail"
+	v2 "lmsmodule/backend-svc/handlers/v2"
+	"lmsmodule/backend-svc/health"
+	"lmsmodule/backend-svc/jobs"
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/media"
 	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/outbox"
+	"lmsmodule/backend-svc/realtime"
+	"lmsmodule/backend-svc/redact"
+	"lmsmodule/backend-svc/search"
+	"lmsmodule/backend-svc/seed"
+	"lmsmodule/backend-svc/settings"
 	"lmsmodule/backend-svc/storage"
 	"log"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
+	_ "time/tzdata" // часовые пояса пользователей не зависят от tzdata в образе
 )
 
 // @title LMS API
@@ -32,94 +57,882 @@ func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	log.Println("Starting LMS API server...")
 
-	dsn := os.Getenv("DATABASE_DSN")
-	if dsn == "" {
-		dsn = "lms_user:Ept@Meny@8NeSpros1l1@tcp(db:3306)/lms_db?parseTime=true"
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "path to optional YAML config file")
+	checkCompat := flag.Bool("check-compat", false, "check that this build and its migrations can be rolled out without downtime, then exit")
+	migrationsDir := flag.String("migrations", "migrations", "directory with MySQL migrations for -check-compat")
+	flag.Parse()
+
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *checkCompat {
+		os.Exit(runCompatCheck(cfg, *migrationsDir))
 	}
 
+	// Логи сервиса и журнал запросов gin проходят через маскирование персональных данных
+	redact.Configure(cfg.Security.RedactPII)
+	log.SetOutput(redact.Writer(os.Stderr))
+	gin.DefaultWriter = redact.Writer(os.Stdout)
+	gin.DefaultErrorWriter = redact.Writer(os.Stderr)
+	if !cfg.Security.RedactPII {
+		log.Println("WARNING: PII redaction is disabled (SECURITY_REDACT_PII=false); do not use this in production")
+	}
+
+	handlers.ConfigureAuth(cfg.JWT)
+	if err := handlers.ConfigureCaptcha(cfg.Captcha); err != nil {
+		log.Fatal("Captcha configuration failed:", err)
+	}
+	handlers.ConfigureOTP(cfg.OTP)
+	handlers.ConfigureLoginAlerts(cfg.Security.LoginAlerts, cfg.PublicURL)
+	if err := handlers.ConfigureCountryHeaders(cfg.Security.TrustedProxies); err != nil {
+		log.Fatal("Trusted proxies configuration failed:", err)
+	}
+	handlers.ConfigureAccounts(cfg.Accounts)
+	handlers.ConfigureRetention(cfg.Retention)
+	handlers.ConfigureMedia(cfg.Media, cfg.PublicURL)
+	handlers.ConfigureTelegram(cfg.Telegram, cfg.PublicURL)
+	if err := handlers.ConfigureBadges(cfg.Badges, cfg.PublicURL); err != nil {
+		log.Fatal("Badges configuration failed:", err)
+	}
+	if err := handlers.ConfigureWebPush(cfg.WebPush); err != nil {
+		log.Fatal("Web push configuration failed:", err)
+	}
+	mail.Configure(cfg.SMTP)
+	mail.SetBrandingResolver(handlers.EmailBranding)
+
 	var useMockData bool = false
+	var db *sql.DB
 
-	db, err := sql.Open("mysql", dsn)
-	if err != nil {
-		log.Printf("Database connection error: %v. Using mock data instead.", err)
-		useMockData = true
+	if cfg.Database.Driver == "sqlite" {
+		sqliteStore, err := storage.OpenSQLite(cfg.Database.DSN)
+		if err != nil {
+			log.Fatalf("SQLite storage error: %v", err)
+		}
+		storage.PublishPoolStats(sqliteStore.DB)
+		sqliteStore.QueryTimeout = cfg.Database.QueryTimeout
+		sqliteStore.SlowQueryThreshold = cfg.Database.SlowQueryThreshold
+		sqliteStore.PasswordHistory = cfg.Security.PasswordHistory
+		db = sqliteStore.DB
+		handlers.Db = db
+		log.Printf("Using SQLite storage at %s", cfg.Database.DSN)
+		handlers.UseStorage(storage.Instrument(sqliteStore))
 	} else {
-		err = db.Ping()
+		db, err = sql.Open("mysql", cfg.Database.DSN)
 		if err != nil {
-			log.Printf("Database ping failed: %v. Using mock data instead.", err)
+			log.Printf("Database connection error: %v. Using mock data instead.", err)
 			useMockData = true
 		} else {
-			db.SetMaxOpenConns(25)
-			db.SetMaxIdleConns(25)
-			db.SetConnMaxLifetime(5 * time.Minute)
-			handlers.Db = db
-			defer db.Close()
-			log.Println("Successfully connected to database")
+			err = storage.WaitForDB(context.Background(), db, storage.RetryPolicy{
+				Timeout:        cfg.Database.ConnectTimeout,
+				InitialBackoff: cfg.Database.RetryInitialBackoff,
+				MaxBackoff:     cfg.Database.RetryMaxBackoff,
+			})
+			if err != nil {
+				log.Printf("Database ping failed: %v. Using mock data instead.", err)
+				useMockData = true
+			} else {
+				db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+				db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+				db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+				db.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
+				storage.PublishPoolStats(db)
+				handlers.Db = db
+				log.Println("Successfully connected to database")
+			}
+		}
+
+		if useMockData {
+			log.Println("Using mock data storage")
+			handlers.UseStorage(&storage.MockStorage{PasswordHistory: cfg.Security.PasswordHistory})
+		} else {
+			log.Println("Using database storage")
+			handlers.UseStorage(storage.Instrument(&storage.DBStorage{
+				DB:                 db,
+				Replicas:           openReplicas(cfg.Database),
+				QueryTimeout:       cfg.Database.QueryTimeout,
+				SlowQueryThreshold: cfg.Database.SlowQueryThreshold,
+				PasswordHistory:    cfg.Security.PasswordHistory,
+			}))
 		}
 	}
 
-	if useMockData {
-		log.Println("Using mock data storage")
-		handlers.UseStorage(&storage.MockStorage{})
-	} else {
-		log.Println("Using database storage")
-		handlers.UseStorage(&storage.DBStorage{DB: db})
+	if !useMockData {
+		checkSchema(db, cfg.Database)
+	}
+
+	if cfg.Seed.DemoData {
+		if err := seed.Demo(handlers.Store, cfg.Seed.DemoPassword); err != nil {
+			log.Printf("Demo data seeding failed: %v", err)
+		} else {
+			log.Println("Demo data seeded")
+		}
+	}
+
+	// Сбои внедряются после заполнения демо-данными, чтобы старт не зависел от них;
+	// метрики остаются внешним декоратором и учитывают внедренные ошибки и задержки
+	if cfg.Database.Faults.Enabled {
+		faulty, err := storage.InjectFaults(storage.Unwrap(handlers.Store), cfg.Database.Faults)
+		if err != nil {
+			log.Fatalf("Storage fault injection error: %v", err)
+		}
+		log.Printf("WARNING: storage fault injection is enabled (latency %s with probability %g, error probability %g)",
+			cfg.Database.Faults.Latency, cfg.Database.Faults.LatencyProbability, cfg.Database.Faults.ErrorProbability)
+		handlers.UseStorage(storage.Instrument(faulty))
+	}
+
+	runtimeSettings := settings.New(handlers.Store, map[string]string{
+		settings.KeyRateLimitRPM:   strconv.Itoa(cfg.RateLimit.RequestsPerMinute),
+		settings.KeyRateLimitBurst: strconv.Itoa(cfg.RateLimit.Burst),
+		settings.KeySingleSession:  strconv.FormatBool(cfg.Security.SingleSession),
+	})
+	if err := runtimeSettings.Reload(); err != nil {
+		log.Printf("Failed to load runtime settings, using defaults: %v", err)
+	}
+	handlers.UseSettings(runtimeSettings)
+	if err := handlers.ReloadIPAllowlists(); err != nil {
+		log.Printf("Failed to load organization IP allowlists: %v", err)
+	}
+	handlers.UseFlags(flags.New(handlers.Store, 30*time.Second))
+
+	// Фоновые задачи живут в собственном контексте, который отменяется только после
+	// того, как HTTP-сервер дождется завершения текущих запросов
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	var background sync.WaitGroup
+
+	// Диспетчер всегда раздает события подключенным клиентам (SSE и WebSocket) и, если заданы, webhook'ам
+	eventBroker := outbox.NewBroker()
+	handlers.UseEvents(eventBroker)
+	realtimeHub := realtime.NewHub()
+	handlers.UseRealtime(realtimeHub)
+	handlers.UsePresence(realtimeHub)
+	publishers := []outbox.Publisher{eventBroker, realtimeHub}
+	sinks := []realtime.EventSink{eventBroker}
+	// Поисковый индекс у каждой реплики свой, поэтому он получает события вместе с клиентами реплики
+	var searchIndex *search.Index
+	if cfg.Search.Driver != "" {
+		var created bool
+		searchIndex, created, err = search.Open(cfg.Search, handlers.Store)
+		if err != nil {
+			log.Fatal("Search index configuration failed:", err)
+		}
+		handlers.UseSearch(searchIndex)
+		publishers = append(publishers, searchIndex)
+		sinks = append(sinks, searchIndex)
+		background.Add(1)
+		go func() {
+			defer background.Done()
+			searchIndex.Run(backgroundCtx)
+		}()
+		if created {
+			background.Add(1)
+			go func() {
+				defer background.Done()
+				if err := searchIndex.Rebuild(backgroundCtx); err != nil {
+					log.Printf("Search: initial indexing failed: %v", err)
+				}
+			}()
+		}
+		log.Printf("Search index opened at %s", cfg.Search.Path)
+	}
+	// Реплики делят одни аренды задач и outbox и обмениваются сообщениями реального времени
+	instanceID := jobs.InstanceID()
+	var realtimeCluster *realtime.Cluster
+	if cfg.Realtime.Driver != "" {
+		realtimeCluster, err = realtime.NewCluster(cfg.Realtime, instanceID, realtimeHub, sinks...)
+		if err != nil {
+			log.Fatal("Real-time cluster configuration failed:", err)
+		}
+		handlers.UsePresence(realtimeCluster)
+		publishers = []outbox.Publisher{realtimeCluster}
+		log.Printf("Real-time messages are shared between replicas through %s subject %s", cfg.Realtime.Driver, cfg.Realtime.Subject)
+	}
+	if len(cfg.Outbox.WebhookURLs) > 0 {
+		publishers = append(publishers, outbox.Redacted(outbox.NewWebhookPublisher(cfg.Outbox.WebhookURLs)))
+	}
+	var eventBus outbox.BusPublisher
+	if cfg.Outbox.Bus.Driver != "" {
+		eventBus, err = outbox.NewBusPublisher(cfg.Outbox.Bus)
+		if err != nil {
+			log.Fatal("Event bus configuration failed:", err)
+		}
+		publishers = append(publishers, outbox.Redacted(eventBus))
+		log.Printf("Publishing domain events to %s topic %s", cfg.Outbox.Bus.Driver, cfg.Outbox.Bus.Topic)
+	}
+	dispatcher := outbox.NewDispatcher(handlers.Store, publishers...)
+	dispatcher.Coordinate(handlers.Store, instanceID)
+	background.Add(1)
+	go func() {
+		defer background.Done()
+		dispatcher.Run(backgroundCtx)
+	}()
+	log.Println("Outbox dispatcher started")
+
+	// gRPC API для внутренних сервисов слушает отдельный порт
+	var grpcServer *grpcapi.Server
+	var grpcSrv *grpc.Server
+	if cfg.GRPC.Port != "" {
+		grpcServer = grpcapi.NewServer(handlers.Store, cfg.GRPC.AuthToken)
+		grpcSrv = grpcServer.GRPCServer()
+		lis, err := net.Listen("tcp", "0.0.0.0:"+cfg.GRPC.Port)
+		if err != nil {
+			log.Fatal("gRPC server failed:", err)
+		}
+		go func() {
+			log.Printf("gRPC server starting on port %s", cfg.GRPC.Port)
+			if err := grpcSrv.Serve(lis); err != nil {
+				log.Fatal("gRPC server failed:", err)
+			}
+		}()
+	}
+
+	scheduler := jobs.NewScheduler()
+	// Общие задачи (рассылки, очистка, напоминания) выполняются одной репликой за интервал
+	scheduler.Coordinate(handlers.Store, instanceID)
+	log.Printf("Background jobs coordinated through leases as %s", instanceID)
+	// Сессия нужна, пока действует токен последнего входа: он выдается не позже чем через
+	// время жизни временного токена второго фактора после отметки о входе
+	scheduler.Add(jobs.NewCleanupJob(handlers.Store, cfg.Cleanup.Interval, cfg.JWT.TTL+cfg.JWT.TempTTL,
+		cfg.Cleanup.EventRetention, cfg.Cleanup.IdempotencyRetention))
+	if !useMockData {
+		scheduler.Add(jobs.NewDBPingJob(db, cfg.Database.PingInterval))
+	}
+	scheduler.Add(jobs.Job{
+		Name:     "announcements",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			return handlers.PublishDueAnnouncements()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "telegram-deadlines",
+		Interval: 5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			return handlers.SendTelegramDeadlineReminders()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "push-deadlines",
+		Interval: 5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			return handlers.SendPushDeadlineReminders()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "chat-deadlines",
+		Interval: 5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			return handlers.SendChatDeadlineReminders()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "chat-leaderboards",
+		Interval: 15 * time.Minute,
+		Run: func(ctx context.Context) error {
+			return handlers.SendChatLeaderboards()
+		},
+	})
+	if cfg.Digest.Enabled {
+		scheduler.Add(jobs.Job{
+			Name:     "weekly-digests",
+			Interval: 15 * time.Minute,
+			Run: func(ctx context.Context) error {
+				return handlers.SendWeeklyDigests()
+			},
+		})
+	}
+	scheduler.Add(jobs.Job{
+		Name:     "progress-snapshots",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			return handlers.SnapshotProgress()
+		},
+	})
+	if searchIndex != nil {
+		scheduler.Add(jobs.Job{
+			Name:     "search-rebuild",
+			Interval: cfg.Search.RebuildInterval,
+			Local:    true,
+			Run: func(ctx context.Context) error {
+				return searchIndex.Rebuild(ctx)
+			},
+		})
+	}
+	scheduler.Add(jobs.Job{
+		Name:     "course-summaries",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			return handlers.RefreshCourseSummaries()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "recertification",
+		Interval: time.Hour,
+		Run: func(ctx context.Context) error {
+			return handlers.ProcessRecertifications()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "access-expiry",
+		Interval: 5 * time.Minute,
+		Run: func(ctx context.Context) error {
+			return handlers.DeactivateExpiredAccounts()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "settings-reload",
+		Interval: 30 * time.Second,
+		Local:    true,
+		Run: func(ctx context.Context) error {
+			return runtimeSettings.Reload()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "retention",
+		Interval: cfg.Retention.Interval,
+		Run: func(ctx context.Context) error {
+			return handlers.ApplyRetentionPolicies()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "ip-allowlist-reload",
+		Interval: 30 * time.Second,
+		Local:    true,
+		Run: func(ctx context.Context) error {
+			return handlers.ReloadIPAllowlists()
+		},
+	})
+	scheduler.Add(jobs.Job{
+		Name:     "secrets-refresh",
+		Interval: cfg.Secrets.RefreshInterval,
+		Local:    true,
+		Run: func(ctx context.Context) error {
+			changed, err := cfg.RefreshSecrets(ctx)
+			if err != nil {
+				return err
+			}
+			if changed {
+				handlers.ConfigureAuth(cfg.JWT)
+				mail.Configure(cfg.SMTP)
+				if err := handlers.ConfigureCaptcha(cfg.Captcha); err != nil {
+					log.Printf("Captcha reconfiguration failed: %v", err)
+				}
+				handlers.ConfigureOTP(cfg.OTP)
+				handlers.ConfigureMedia(cfg.Media, cfg.PublicURL)
+				if err := handlers.ConfigureBadges(cfg.Badges, cfg.PublicURL); err != nil {
+					log.Printf("Badges reconfiguration failed: %v", err)
+				}
+				handlers.ConfigureTelegram(cfg.Telegram, cfg.PublicURL)
+				if err := handlers.RegisterTelegramWebhook(ctx); err != nil {
+					log.Printf("Telegram webhook registration failed: %v", err)
+				}
+				if err := handlers.ConfigureWebPush(cfg.WebPush); err != nil {
+					log.Printf("Web push reconfiguration failed: %v", err)
+				}
+				if grpcServer != nil {
+					grpcServer.SetAuthToken(cfg.GRPC.AuthToken)
+				}
+				log.Println("Secrets reloaded; database DSN changes take effect after restart")
+			}
+			return nil
+		},
+	})
+	scheduler.Start(backgroundCtx)
+	go func() {
+		if err := handlers.RegisterTelegramWebhook(backgroundCtx); err != nil {
+			log.Printf("Telegram webhook registration failed: %v", err)
+		}
+	}()
+
+	checker := health.NewChecker(2 * time.Second)
+	if !useMockData {
+		checker.Register("database", health.DBCheck(db))
+		checker.Register("migrations", health.MigrationsCheck(db, storage.SchemaVersion()))
 	}
 
 	r := gin.Default()
-	r.Use(CORSMiddleware())
+	// По умолчанию gin доверяет X-Forwarded-For от любого клиента; пустой список отключает это,
+	// и адресом клиента считается адрес соединения
+	if err := r.SetTrustedProxies(cfg.Security.TrustedProxies); err != nil {
+		log.Fatalf("Invalid trusted proxies: %v", err)
+	}
+	r.Use(RequestIDMiddleware(), SecurityHeadersMiddleware(cfg.Security))
+	r.GET("/healthz", checker.LivenessHandler)
+	r.GET("/readyz", checker.ReadinessHandler)
+	r.Use(CORSMiddleware(cfg.CORS))
+	r.Use(IPAccessMiddleware(runtimeSettings, handlers.Store, cfg.Security.BlockUnknownCountry))
+	handlers.ForceReadOnly(cfg.Database.ReadOnly)
+	if cfg.Database.ReadOnly {
+		log.Println("WARNING: read-only mode is enabled by configuration (database.read_only)")
+	}
+	r.Use(ReadOnlyMiddleware(runtimeSettings))
+	// Метрики в формате Prometheus забирает сборщик без токена, поэтому доступ ограничен
+	// списком admin_ip_allowlist
+	r.GET("/metrics", AdminIPMiddleware(runtimeSettings, handlers.Store), gin.WrapH(promhttp.Handler()))
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 	log.Println("Swagger documentation available at /swagger/index.html")
 
+	if cfg.Media.Driver == "local" {
+		// Файлы локального медиахранилища раздает сам сервис; ключи неизменны, поэтому кэш бессрочный
+		mediaFiles := r.Group("/media", func(c *gin.Context) {
+			c.Header("Cache-Control", media.ImmutableCacheControl)
+		})
+		mediaFiles.Static("/", cfg.Media.LocalDir)
+	}
+
+	rateLimiter := RateLimitMiddleware(runtimeSettings)
+
+	maintenance := MaintenanceMiddleware(runtimeSettings)
+
+	idempotency := IdempotencyMiddleware(handlers.Store)
+	audit := AuditMiddleware(handlers.Store)
+
 	public := r.Group("/api")
+	public.Use(rateLimiter)
 	{
-		public.POST("/register", handlers.RegisterHandler)
+		public.POST("/register", maintenance, idempotency, handlers.RegisterHandler)
+		public.GET("/invitations/:token", handlers.GetInvitationPreview)
+		public.POST("/invitations/:token/register", maintenance, idempotency, handlers.RegisterWithInvitation)
 		public.POST("/login", handlers.LoginHandler)
 		public.POST("/verify-otp", handlers.VerifyOTPHandler)
 		public.GET("/health", HealthCheckHandler)
+		public.GET("/settings/public", handlers.GetPublicSettings)
+		// Ссылка «это был не я» из письма о входе с нового устройства
+		public.GET("/account/lock", handlers.LockAccountPage)
+		public.POST("/account/lock", handlers.LockAccount)
+		// Ссылки из писем о смене email: подтверждение на новый адрес и отмена на старый
+		public.GET("/account/email/confirm", handlers.ConfirmEmailChangePage)
+		public.POST("/account/email/confirm", handlers.ConfirmEmailChange)
+		public.GET("/account/email/cancel", handlers.CancelEmailChangePage)
+		public.POST("/account/email/cancel", handlers.CancelEmailChange)
+		public.GET("/digest/unsubscribe", handlers.DigestUnsubscribePage)
+		public.POST("/digest/unsubscribe", handlers.DigestUnsubscribe)
+		// Ссылка на календарь для Google Calendar и Outlook: токен в пути заменяет авторизацию
+		public.GET("/calendar/feed/:token", handlers.CalendarFeed)
+		// Среда выполнения SCORM подключается плеером курса тегом <script>
+		public.GET("/scorm/runtime.js", handlers.ScormRuntimeScript)
+		// Проверка значков Open Badges: адреса удостоверений, издателя, ключей и изображений
+		// встроены в выданные значки и открываются без авторизации
+		public.GET("/badges/issuer", handlers.GetBadgeIssuer)
+		public.GET("/badges/jwks", handlers.GetBadgeJWKS)
+		public.GET("/badges/images/:id", handlers.BadgeImage)
+		public.GET("/badges/assertions/:id", handlers.GetBadgeCredential)
+		public.GET("/badges/assertions/:id/download", handlers.DownloadBadge)
+		public.GET("/certificates/verify/:code", handlers.VerifyCertificate)
+		public.GET("/certificates/document/:code", handlers.CertificateDocument)
+		public.GET("/organizations/:id/logo", handlers.OrganizationLogo)
+		public.POST("/telegram/webhook", handlers.TelegramWebhook)
 	}
 
+	passwordChange := PasswordChangeMiddleware()
+
+	// WebSocket-клиенты в браузере не могут передать заголовок Authorization
+	handlers.ConfigureWebSocketOrigins(func(origin string) bool {
+		_, ok := corsOriginAllowed(cfg.CORS.AllowedOrigins, origin)
+		return ok
+	})
+	r.GET("/api/ws", rateLimiter, QueryTokenMiddleware(), JWTAuthMiddleware(), OrganizationIPMiddleware(handlers.Store),
+		passwordChange, maintenance, handlers.WebSocketHandler)
+
 	api := r.Group("/api")
-	api.Use(JWTAuthMiddleware())
+	api.Use(rateLimiter, JWTAuthMiddleware(), OrganizationIPMiddleware(handlers.Store), passwordChange, maintenance)
 	{
 		api.GET("/courses", handlers.GetCourses)
+		api.GET("/search", handlers.Search)
 		api.GET("/courses/:id", handlers.GetCourseByID)
+		api.GET("/courses/assigned", handlers.ListMyCourseAssignments)
+		api.GET("/courses/changes", handlers.GetCourseChangesFeed)
+		api.GET("/courses/:id/changes", handlers.GetCourseChangelog)
+		api.POST("/courses/:id/changes/seen", handlers.MarkCourseChangesSeen)
+		api.POST("/courses/:id/tasks", audit, handlers.AddCourseTask)
+		api.PUT("/courses/:id/tasks/:task_id", audit, handlers.UpdateCourseTask)
+		api.GET("/bookmarks", handlers.ListBookmarks)
+		api.PUT("/courses/:id/bookmark", handlers.BookmarkCourse)
+		api.DELETE("/courses/:id/bookmark", handlers.DeleteCourseBookmark)
+		api.PUT("/courses/:id/tasks/:task_id/bookmark", handlers.BookmarkTask)
+		api.DELETE("/courses/:id/tasks/:task_id/bookmark", handlers.DeleteTaskBookmark)
+		api.GET("/notes", handlers.ListTaskNotes)
+		api.GET("/courses/:id/tasks/:task_id/note", handlers.GetTaskNote)
+		api.PUT("/courses/:id/tasks/:task_id/note", handlers.SaveTaskNote)
+		api.DELETE("/courses/:id/tasks/:task_id/note", handlers.DeleteTaskNote)
+		api.GET("/courses/:id/survey", handlers.GetCourseSurvey)
+		api.PUT("/courses/:id/survey", audit, handlers.SaveCourseSurvey)
+		api.DELETE("/courses/:id/survey", audit, handlers.DeleteCourseSurvey)
+		api.POST("/courses/:id/survey/responses", idempotency, handlers.SubmitSurveyResponse)
+		api.GET("/courses/:id/survey/results", handlers.GetSurveyResults)
+		api.GET("/surveys/pending", handlers.ListPendingSurveys)
+		api.GET("/courses/:id/threads", handlers.ListThreads)
+		api.POST("/courses/:id/threads", idempotency, handlers.CreateThread)
+		api.GET("/threads/:id", handlers.GetThread)
+		api.POST("/threads/:id/comments", idempotency, handlers.AddComment)
+		api.GET("/courses/:id/reviews", handlers.ListCourseReviews)
+		api.PUT("/courses/:id/reviews", handlers.SaveReview)
+		api.POST("/comments/:id/report", handlers.ReportComment)
+		api.POST("/reviews/:id/report", handlers.ReportReview)
+
+		// Модерация обсуждений курсов (преподаватели и администраторы)
+		api.PUT("/comments/:id/moderation", audit, handlers.ModerateComment)
+		api.PUT("/reviews/:id/moderation", audit, handlers.ModerateReview)
+		api.PUT("/threads/:id/lock", audit, handlers.LockThread)
+		api.GET("/courses/:id/bans", handlers.ListCourseBans)
+		api.PUT("/courses/:id/bans/:user_id", audit, handlers.BanFromCourse)
+		api.DELETE("/courses/:id/bans/:user_id", audit, handlers.UnbanFromCourse)
+		api.POST("/courses/:id/progress/:user_id/reset", audit, handlers.ResetCourseProgress)
+		api.GET("/courses/:id/progress/:user_id/attempts", handlers.ListCourseAttempts)
+		api.GET("/moderation/reports", handlers.ListContentReports)
+		api.PUT("/moderation/reports/:id", audit, handlers.ResolveContentReport)
+
+		// Курсы из пакетов SCORM
+		api.POST("/courses/scorm", audit, handlers.ImportScormPackage)
+		api.GET("/courses/:id/scorm", handlers.GetScormPackage)
+		api.GET("/tasks/:id/scorm", handlers.LaunchScormTask)
+		api.PUT("/tasks/:id/scorm", handlers.CommitScormData)
+
+		// Разбор банков вопросов GIFT и Moodle XML
+		api.POST("/questions/import", audit, handlers.ImportQuestions)
+
+		// Значки Open Badges
+		api.GET("/badges", handlers.ListMyBadges)
+		api.GET("/badges/classes", handlers.ListBadgeClasses)
+		api.POST("/badges/classes", audit, handlers.CreateBadgeClass)
+		api.GET("/badges/classes/:id", handlers.GetBadgeClass)
+		api.PUT("/badges/classes/:id", audit, handlers.UpdateBadgeClass)
+		api.DELETE("/badges/classes/:id", audit, handlers.DeleteBadgeClass)
+		api.PUT("/badges/classes/:id/image", audit, handlers.UploadBadgeImage)
+		api.DELETE("/badges/classes/:id/image", audit, handlers.DeleteBadgeImage)
+		api.GET("/badges/classes/:id/assertions", handlers.ListBadgeClassAssertions)
+		api.POST("/badges/classes/:id/assertions", idempotency, audit, handlers.AwardBadge)
+		api.POST("/badges/assertions/:id/revoke", audit, handlers.RevokeBadge)
+		api.GET("/certificates", handlers.ListMyCertificates)
+		api.POST("/certificates/:code/revoke", audit, handlers.RevokeCertificate)
+		api.GET("/telegram", handlers.GetTelegramStatus)
+		api.DELETE("/telegram", handlers.UnlinkTelegram)
+		api.POST("/telegram/link", handlers.CreateTelegramLink)
+		api.GET("/push", handlers.GetPushConfig)
+		api.GET("/push/subscriptions", handlers.ListPushSubscriptions)
+		api.POST("/push/subscriptions", handlers.SavePushSubscription)
+		api.DELETE("/push/subscriptions/:id", handlers.DeletePushSubscription)
+		api.POST("/push/test", handlers.SendTestPush)
+
 		api.GET("/progress/:user_id", handlers.GetUserProgress)
-		api.POST("/progress/:user_id/tasks/:task_id/complete", handlers.CompleteTask)
+		api.GET("/progress/:user_id/history", handlers.GetProgressHistory)
+		api.POST("/progress/:user_id/tasks/:task_id/complete", idempotency, handlers.CompleteTask)
+		api.POST("/progress/:user_id/tasks/complete", idempotency, handlers.CompleteTasks)
 
 		api.GET("/profile", handlers.GetUserProfile)
 		api.PUT("/profile", handlers.UpdateUserProfile)
+		api.GET("/flags", handlers.GetMyFeatureFlags)
+		api.GET("/users/:username", handlers.GetPublicProfile)
+		api.GET("/announcements", handlers.ListAnnouncements)
+		api.POST("/announcements", idempotency, audit, handlers.CreateAnnouncement)
+		api.PUT("/announcements/:id", audit, handlers.UpdateAnnouncement)
+		api.DELETE("/announcements/:id", audit, handlers.DeleteAnnouncement)
+		api.GET("/conversations", handlers.ListConversations)
+		api.POST("/conversations", idempotency, handlers.StartConversation)
+		api.GET("/conversations/:id/messages", handlers.ListConversationMessages)
+		api.POST("/conversations/:id/messages", idempotency, handlers.SendConversationMessage)
+		api.GET("/conversations/:id/export", handlers.ExportConversation)
+		api.POST("/messages/:id/report", handlers.ReportMessage)
+		api.GET("/calendar", handlers.GetCalendar)
+		api.POST("/calendar/feed", handlers.CreateCalendarFeed)
+		api.DELETE("/calendar/feed", handlers.DeleteCalendarFeed)
+		api.PUT("/tasks/:id/schedule", audit, handlers.SetTaskSchedule)
+		api.POST("/live-sessions", idempotency, audit, handlers.CreateLiveSession)
+		api.PUT("/live-sessions/:id", audit, handlers.UpdateLiveSession)
+		api.DELETE("/live-sessions/:id", audit, handlers.DeleteLiveSession)
+		api.GET("/tickets", handlers.ListTickets)
+		api.POST("/tickets", idempotency, handlers.CreateTicket)
+		api.GET("/tickets/:id", handlers.GetTicket)
+		api.POST("/tickets/:id/replies", idempotency, handlers.ReplyToTicket)
+		api.PUT("/tickets/:id/status", handlers.SetTicketStatus)
+		api.PUT("/tickets/:id/assignee", handlers.AssignTicket)
+		api.GET("/groups/:group/leaderboard", handlers.GetGroupLeaderboard)
+		api.GET("/challenges", handlers.ListTeamChallenges)
+		api.GET("/challenges/:id", handlers.GetTeamChallengeStandings)
+		api.GET("/recertifications", handlers.ListMyRecertifications)
+		api.GET("/manager/reports", handlers.ListManagerReports)
+		api.GET("/manager/reports/:user_id", handlers.GetTeamMemberProgress)
+		api.GET("/org", handlers.GetMyOrganization)
+		api.GET("/org/members", handlers.ListOrganizationMembers)
+		api.PUT("/org/members/:user_id/status", audit, handlers.UpdateOrganizationMemberStatus)
+		api.PUT("/org/admins/:user_id", audit, handlers.GrantMyOrganizationAdmin)
+		api.DELETE("/org/admins/:user_id", audit, handlers.RevokeMyOrganizationAdmin)
+		api.GET("/org/branding", handlers.GetMyBranding)
+		api.PUT("/org/branding", audit, handlers.UpdateMyBranding)
+		api.PUT("/org/branding/logo", audit, handlers.UploadMyLogo)
+		api.DELETE("/org/branding/logo", audit, handlers.DeleteMyLogo)
+		api.GET("/org/ip-allowlist", handlers.GetMyIPAllowlist)
+		api.PUT("/org/ip-allowlist", audit, handlers.UpdateMyIPAllowlist)
+		api.GET("/org/invitations", handlers.ListMyInvitations)
+		api.POST("/org/invitations", audit, handlers.CreateMyInvitation)
+		api.DELETE("/org/invitations/:id", audit, handlers.RevokeMyInvitation)
+
+		api.GET("/events/stream", handlers.StreamEvents)
+		api.GET("/graphql", handlers.GraphQLHandler)
+		api.POST("/graphql", handlers.GraphQLHandler)
 
 		account := api.Group("/account")
 		{
 			account.POST("/2fa/enable", handlers.Enable2FAHandler)
+			account.GET("/otp/channel", handlers.GetOTPPreference)
+			account.PUT("/otp/channel", handlers.UpdateOTPPreference)
+			account.GET("/otp/deliveries", handlers.ListOTPDeliveries)
+			account.GET("/devices", handlers.ListTrustedDevices)
+			account.DELETE("/devices", handlers.RevokeTrustedDevices)
+			account.DELETE("/devices/:id", handlers.RevokeTrustedDevice)
+			account.GET("/logins", handlers.ListLoginContexts)
+			account.GET("/export", handlers.ExportAccountData)
+			account.PUT("/username", handlers.ChangeUsername)
+			account.PUT("/avatar", handlers.UploadAvatar)
+			account.DELETE("/avatar", handlers.DeleteAvatar)
+			account.GET("/privacy", handlers.GetProfilePrivacy)
+			account.PUT("/privacy", handlers.UpdateProfilePrivacy)
+			account.GET("/digest", handlers.GetDigestSettings)
+			account.PUT("/digest", handlers.UpdateDigestSettings)
+			account.DELETE("", handlers.DeleteAccount)
 		}
 
 		admin := api.Group("/admin")
-		admin.Use(AdminAuthMiddleware())
+		admin.Use(AdminIPMiddleware(runtimeSettings, handlers.Store), AdminAuthMiddleware(), audit)
 		{
 			// Шаблоны писем
 			admin.POST("/reload-templates", handlers.ReloadTemplatesHandler)
 
+			// Runtime-параметры
+			admin.GET("/settings", handlers.GetSettings)
+			admin.PUT("/settings/:key", handlers.UpdateSetting)
+			admin.POST("/maintenance", handlers.SetMaintenanceMode)
+
+			// Флаги функциональности
+			admin.GET("/flags", handlers.GetFeatureFlags)
+			admin.PUT("/flags/:key", handlers.UpsertFeatureFlag)
+			admin.DELETE("/flags/:key", handlers.DeleteFeatureFlag)
+
+			// Рассылка объявлений в реальном времени
+			admin.POST("/broadcast", handlers.BroadcastMessage)
+			admin.GET("/presence", handlers.GetPresence)
+
+			// Метрики фоновых задач и сервиса; метрики хранилища отдаются на /metrics
+			admin.GET("/metrics", gin.WrapH(expvar.Handler()))
+
+			// Статистика для панели администратора
+			admin.GET("/stats", handlers.GetPlatformStats)
+
+			// Лента действий администраторов и преподавателей
+			admin.GET("/activity", handlers.GetActivityFeed)
+
+			// Политики хранения данных
+			admin.GET("/retention", handlers.GetRetentionStatus)
+			admin.POST("/retention/preview", handlers.PreviewRetention)
+
+			// Переводы курсов и заданий
+			admin.GET("/courses/:id/translations", handlers.ListCourseTranslations)
+			admin.PUT("/courses/:id/translations/:locale", handlers.SetCourseTranslation)
+			admin.DELETE("/courses/:id/translations/:locale", handlers.DeleteCourseTranslation)
+			admin.PUT("/tasks/:id/translations/:locale", handlers.SetTaskTranslation)
+			admin.DELETE("/tasks/:id/translations/:locale", handlers.DeleteTaskTranslation)
+
+			// Модерация личных сообщений
+			admin.GET("/messages/reported", handlers.ListReportedMessages)
+			admin.PUT("/messages/:id/moderation", handlers.ModerateMessage)
+
+			// Каналы Slack и Mattermost групп
+			admin.GET("/chat-channels", handlers.ListChatChannels)
+			admin.POST("/chat-channels", handlers.CreateChatChannel)
+			admin.GET("/chat-channels/:id", handlers.GetChatChannel)
+			admin.PUT("/chat-channels/:id", handlers.UpdateChatChannel)
+			admin.DELETE("/chat-channels/:id", handlers.DeleteChatChannel)
+			admin.POST("/chat-channels/:id/test", handlers.TestChatChannel)
+
+			// Соревнования групп
+			admin.POST("/challenges", handlers.CreateTeamChallenge)
+			admin.PUT("/challenges/:id", handlers.UpdateTeamChallenge)
+			admin.DELETE("/challenges/:id", handlers.DeleteTeamChallenge)
+
+			// Повторное прохождение курсов
+			admin.GET("/courses/:id/recertification", handlers.GetCourseRecertification)
+			admin.PUT("/courses/:id/recertification", handlers.SetCourseRecertification)
+			admin.DELETE("/courses/:id/recertification", handlers.DeleteCourseRecertification)
+			admin.GET("/compliance", handlers.GetComplianceReport)
+
 			// Управление пользователями
 			admin.GET("/users", handlers.GetAllUsers)
+			admin.GET("/progress/export", handlers.ExportProgress)
 			admin.GET("/users/:id", handlers.GetUserByID)
 			admin.GET("/users/by-role", handlers.GetUsersByRole)
 			admin.GET("/users/search", handlers.SearchUsers)
+			admin.GET("/users/by-username/:username", handlers.GetUserByUsername)
 			admin.PUT("/users/:id/status", handlers.UpdateUserStatus)
+			admin.PUT("/users/:id/single-session", handlers.SetUserSingleSession)
+			admin.PUT("/users/:id/manager", handlers.SetUserManager)
+			admin.PUT("/users/:id/organization", handlers.SetUserOrganization)
+			admin.PUT("/courses/:id/organization", handlers.SetCourseOrganization)
+
+			// Архив курсов
+			admin.GET("/courses/archived", handlers.ListArchivedCourses)
+			admin.POST("/courses/:id/archive", handlers.ArchiveCourse)
+			admin.POST("/courses/:id/restore", handlers.RestoreCourse)
+			admin.DELETE("/courses/:id", handlers.PurgeCourse)
+			admin.GET("/organizations", handlers.ListOrganizations)
+			admin.POST("/organizations", handlers.CreateOrganization)
+			admin.PUT("/organizations/:id", handlers.UpdateOrganization)
+			admin.PUT("/organizations/:id/admins/:user_id", handlers.GrantOrganizationAdmin)
+			admin.DELETE("/organizations/:id/admins/:user_id", handlers.RevokeOrganizationAdmin)
+			admin.GET("/organizations/:id/branding", handlers.GetOrganizationBranding)
+			admin.PUT("/organizations/:id/branding", handlers.UpdateOrganizationBranding)
+			admin.PUT("/organizations/:id/branding/logo", handlers.UploadOrganizationLogo)
+			admin.DELETE("/organizations/:id/branding/logo", handlers.DeleteOrganizationLogo)
+			admin.GET("/organizations/:id/ip-allowlist", handlers.GetOrganizationIPAllowlist)
+			admin.PUT("/organizations/:id/ip-allowlist", handlers.UpdateOrganizationIPAllowlist)
+			admin.GET("/invitations", handlers.ListInvitations)
+			admin.POST("/invitations", handlers.CreateInvitation)
+			admin.DELETE("/invitations/:id", handlers.RevokeInvitation)
+			admin.GET("/registrations", handlers.ListPendingRegistrations)
+			admin.POST("/registrations/:user_id/approve", handlers.ApproveRegistration)
+			admin.POST("/registrations/:user_id/reject", handlers.RejectRegistration)
+			admin.POST("/users/bulk", handlers.BulkUpdateUsers)
+			admin.GET("/users/access-expiry", handlers.ListAccessExpiries)
+			admin.POST("/users/access-expiry/extend", handlers.ExtendAccessExpiry)
+			admin.PUT("/users/:id/access-expiry", handlers.SetAccessExpiry)
+			admin.POST("/users/:id/force-password-reset", handlers.ForcePasswordReset)
+			admin.GET("/users/:id/email-changes", handlers.ListEmailChanges)
+			admin.PUT("/users/:id/username", handlers.AdminChangeUsername)
+			admin.GET("/users/:id/username-history", handlers.ListUsernameHistory)
+			admin.DELETE("/users/:id/avatar", handlers.AdminDeleteAvatar)
 			admin.POST("/users/:id/promote", handlers.PromoteToAdmin)
 			admin.POST("/users/:id/demote", handlers.DemoteFromAdmin)
 		}
 	}
 
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8081"
+	// API v2: конверт ошибок, пагинация и DTO v2; маршруты v1 выше не меняются
+	apiV2 := r.Group("/api/v2")
+	apiV2.Use(v2.Envelope(), rateLimiter, JWTAuthMiddleware(), OrganizationIPMiddleware(handlers.Store), passwordChange, maintenance)
+	{
+		apiV2.GET("/courses", v2.ListCourses)
+		apiV2.GET("/courses/:id", v2.GetCourse)
+		apiV2.GET("/me", v2.GetMe)
+		apiV2.GET("/me/progress", v2.GetMyProgress)
+
+		adminV2 := apiV2.Group("/admin")
+		adminV2.Use(AdminIPMiddleware(runtimeSettings, handlers.Store), AdminAuthMiddleware())
+		{
+			adminV2.GET("/users", v2.ListUsers)
+			adminV2.GET("/users/:id", v2.GetUser)
+		}
 	}
 
-	log.Printf("Server starting on port %s", port)
-	if err := r.Run("0.0.0.0:" + port); err != nil {
-		log.Fatal("Server failed:", err)
+	port := cfg.Port
+	shutdownTimeout := cfg.ShutdownTimeout
+
+	srv := &http.Server{
+		Addr:    "0.0.0.0:" + port,
+		Handler: r,
+	}
+	// Открытые SSE-потоки закрываются сразу, иначе Shutdown ждал бы их до таймаута
+	srv.RegisterOnShutdown(eventBroker.Close)
+	if realtimeCluster != nil {
+		srv.RegisterOnShutdown(realtimeCluster.Close)
+	}
+	srv.RegisterOnShutdown(realtimeHub.Close)
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	go func() {
+		log.Printf("Server starting on port %s", port)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal("Server failed:", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	checker.SetShuttingDown()
+	log.Printf("Shutdown signal received, draining in-flight requests (timeout %s)", shutdownTimeout)
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// Shutdown перестает принимать новые соединения и ждет завершения активных обработчиков
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		log.Printf("HTTP server shutdown error: %v", err)
+	}
+	if grpcSrv != nil && !waitWithDeadline(shutdownCtx, grpcSrv.GracefulStop) {
+		log.Println("gRPC calls did not finish before the shutdown deadline")
+		grpcSrv.Stop()
+	}
+
+	stopBackground()
+	if !waitWithDeadline(shutdownCtx, func() {
+		background.Wait()
+		scheduler.Wait()
+	}) {
+		log.Println("Background jobs did not stop before the shutdown deadline")
+	}
+	if searchIndex != nil {
+		if err := searchIndex.Close(); err != nil {
+			log.Printf("Error closing search index: %v", err)
+		}
+	}
+	if eventBus != nil {
+		if err := eventBus.Close(); err != nil {
+			log.Printf("Error closing event bus connection: %v", err)
+		}
+	}
+
+	if dbStore, ok := storage.Unwrap(handlers.Store).(*storage.DBStorage); ok {
+		if err := dbStore.Close(); err != nil {
+			log.Printf("Error closing database pool: %v", err)
+		}
+	}
+
+	log.Println("Server stopped")
+}
+
+// openReplicas открывает пулы реплик для чтения. Недоступная реплика пропускается,
+// чтобы её отказ не мешал старту: чтение тогда идет с оставшихся реплик или основной базы.
+func openReplicas(cfg config.DatabaseConfig) []*sql.DB {
+	var replicas []*sql.DB
+	for i, dsn := range cfg.ReplicaDSNs {
+		replica, err := sql.Open("mysql", dsn)
+		if err == nil {
+			err = replica.Ping()
+		}
+		if err != nil {
+			log.Printf("Read replica #%d unavailable, skipping: %v", i+1, err)
+			if replica != nil {
+				replica.Close()
+			}
+			continue
+		}
+
+		replica.SetMaxOpenConns(cfg.MaxOpenConns)
+		replica.SetMaxIdleConns(cfg.MaxIdleConns)
+		replica.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		replica.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+		replicas = append(replicas, replica)
+	}
+	if len(replicas) > 0 {
+		log.Printf("Using %d read replica(s)", len(replicas))
+	}
+	return replicas
+}
+
+// waitWithDeadline ждет завершения wait, но не дольше дедлайна контекста.
+// Возвращает false, если дедлайн наступил раньше.
+func waitWithDeadline(ctx context.Context, wait func()) bool {
+	done := make(chan struct{})
+	go func() {
+		wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
 	}
 }
 
@@ -136,7 +949,7 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 				return nil, jwt.ErrSignatureInvalid
 			}
-			return []byte(handlers.JWTSecret), nil
+			return []byte(handlers.JWTSecret()), nil
 		})
 
 		if err != nil {
@@ -167,20 +980,74 @@ func JWTAuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		active, err := handlers.AccountActive(int(userID))
+		if errors.Is(err, storage.ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: "User not found"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to verify session"})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Account is disabled"})
+			return
+		}
+
+		sessionID, _ := claims["sid"].(string)
+		active, err = handlers.SessionActive(int(userID), sessionID)
+		if errors.Is(err, storage.ErrUserNotFound) {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: "User not found"})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to verify session"})
+			return
+		}
+		if !active {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Session ended by a newer sign-in"})
+			return
+		}
+
+		orgID, orgActive, err := handlers.UserOrganization(int(userID))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Failed to verify session"})
+			return
+		}
+		if !orgActive {
+			c.AbortWithStatusJSON(http.StatusForbidden, models.ErrorResponse{Error: "Organization is disabled"})
+			return
+		}
+
 		c.Set("userID", int(userID))
+		c.Set("organizationID", orgID)
+		handlers.RecordActivity(int(userID))
+		c.Next()
+	}
+}
+
+// QueryTokenMiddleware подставляет токен из параметра access_token в заголовок Authorization,
+// если заголовок не передан. Используется только для маршрутов, куда браузер не может передать заголовок.
+func QueryTokenMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
 		c.Next()
 	}
 }
 
 func AdminAuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		userID, exists := c.Get("userID")
+		_, exists := c.Get("userID")
 		if !exists {
 			c.AbortWithStatusJSON(http.StatusUnauthorized, models.ErrorResponse{Error: "Unauthorized"})
 			return
 		}
 
-		isAdmin, err := handlers.CheckAdminRights(userID.(int))
+		isAdmin, err := handlers.RequestAdminRights(c)
 		if err != nil {
 			c.AbortWithStatusJSON(http.StatusInternalServerError, models.ErrorResponse{Error: "Error checking admin rights: " + err.Error()})
 			return
@@ -195,18 +1062,114 @@ func AdminAuthMiddleware() gin.HandlerFunc {
 	}
 }
 
-func CORSMiddleware() gin.HandlerFunc {
+// CORSMiddleware разрешает запросы из браузера с настроенных источников. Запросы с других
+// источников обрабатываются без CORS-заголовков (браузер их заблокирует), а preflight
+// для них отклоняется с 403.
+func CORSMiddleware(cfg config.CORSConfig) gin.HandlerFunc {
+	const (
+		allowHeaders  = "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With, X-Request-ID, Idempotency-Key, If-None-Match, If-Match"
+		allowMethods  = "POST, OPTIONS, GET, PUT, DELETE"
+		exposeHeaders = "X-Request-ID, ETag, Retry-After"
+	)
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
 	return func(c *gin.Context) {
-		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
-		c.Writer.Header().Set("Access-Control-Allow-Credentials", "true")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Writer.Header().Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		// Ответ зависит от Origin, поэтому кеши должны различать источники
+		c.Writer.Header().Add("Vary", "Origin")
+		wildcard, allowed := corsOriginAllowed(cfg.AllowedOrigins, origin)
+		if !allowed {
+			if c.Request.Method == http.MethodOptions {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
 			return
 		}
 
+		if wildcard && !cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Origin", "*")
+		} else {
+			c.Header("Access-Control-Allow-Origin", origin)
+		}
+		if cfg.AllowCredentials {
+			c.Header("Access-Control-Allow-Credentials", "true")
+		}
+		c.Header("Access-Control-Expose-Headers", exposeHeaders)
+
+		if c.Request.Method == http.MethodOptions {
+			c.Header("Access-Control-Allow-Headers", allowHeaders)
+			c.Header("Access-Control-Allow-Methods", allowMethods)
+			c.Header("Access-Control-Max-Age", maxAge)
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// corsOriginAllowed проверяет источник по списку: "*", точное совпадение или шаблон
+// поддоменов scheme://*.domain. wildcard сообщает, что источник разрешен через "*".
+func corsOriginAllowed(allowed []string, origin string) (wildcard, ok bool) {
+	for _, pattern := range allowed {
+		switch {
+		case pattern == "*":
+			return true, true
+		case pattern == origin:
+			return false, true
+		case strings.Contains(pattern, "://*."):
+			scheme, domain, _ := strings.Cut(pattern, "://*")
+			if strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, domain) &&
+				len(origin) > len(scheme)+3+len(domain) {
+				return false, true
+			}
+		}
+	}
+	return false, false
+}
+
+// swaggerCSP разрешает Swagger UI загружать собственные скрипты, стили и изображения
+const swaggerCSP = "default-src 'self'; script-src 'self' 'unsafe-inline'; style-src 'self' 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'none'"
+
+// scormCSP разрешает учебным объектам SCORM выполнять собственные скрипты и открываться
+// только в iframe плеера с того же источника. Директива sandbox без allow-same-origin дает
+// содержимому пакета уникальный источник: оно не получает доступа к окну плеера, его хранилищу
+// и токену, даже если страницу открыть напрямую.
+const scormCSP = "sandbox allow-scripts allow-forms allow-popups; default-src 'self' 'unsafe-inline' 'unsafe-eval' data: blob:; frame-ancestors 'self'"
+
+// SecurityHeadersMiddleware добавляет заголовки безопасности ко всем ответам
+func SecurityHeadersMiddleware(cfg config.SecurityConfig) gin.HandlerFunc {
+	hsts := ""
+	if cfg.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", int(cfg.HSTSMaxAge.Seconds()))
+	}
+
+	return func(c *gin.Context) {
+		h := c.Writer.Header()
+		h.Set("X-Content-Type-Options", "nosniff")
+		scormContent := strings.HasPrefix(c.Request.URL.Path, "/media/scorm/")
+		if scormContent {
+			h.Set("X-Frame-Options", "SAMEORIGIN")
+		} else {
+			h.Set("X-Frame-Options", "DENY")
+		}
+		h.Set("Referrer-Policy", "no-referrer")
+		if strings.HasPrefix(c.Request.URL.Path, "/swagger/") {
+			h.Set("Content-Security-Policy", swaggerCSP)
+		} else if scormContent {
+			h.Set("Content-Security-Policy", scormCSP)
+		} else if cfg.ContentSecurityPolicy != "" {
+			h.Set("Content-Security-Policy", cfg.ContentSecurityPolicy)
+		}
+		if hsts != "" {
+			h.Set("Strict-Transport-Security", hsts)
+		}
 		c.Next()
 	}
 }
@@ -0,0 +1,120 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"  // регистрация декодера GIF
+	_ "image/jpeg" // регистрация декодера JPEG
+	"image/png"
+	"sort"
+)
+
+var (
+	// ErrUnsupportedImage — файл не является изображением PNG, JPEG или GIF
+	ErrUnsupportedImage = errors.New("media: unsupported image format")
+	// ErrImageTooLarge — размеры изображения превышают допустимые
+	ErrImageTooLarge = errors.New("media: image dimensions are too large")
+	// ErrImageTooSmall — изображение меньше минимального размера аватара
+	ErrImageTooSmall = errors.New("media: image is too small")
+)
+
+const (
+	// maxImageSide ограничивает размер декодируемого изображения: размеры проверяются
+	// по заголовку до декодирования, чтобы маленький файл не развернулся в гигабайты пикселей
+	maxImageSide = 4096
+	// minImageSide — меньшие изображения при увеличении до стандартных размеров выглядят размыто
+	minImageSide = 32
+)
+
+// AvatarSizes — стороны квадратных версий аватара в пикселях
+var AvatarSizes = []int{48, 128, 256}
+
+// ResizeAvatar проверяет изображение и возвращает квадратные PNG-версии для каждого размера
+// из sizes. Изображение обрезается по центру до квадрата.
+func ResizeAvatar(data []byte, sizes []int) (map[int][]byte, error) {
+	conf, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	switch format {
+	case "png", "jpeg", "gif":
+	default:
+		return nil, ErrUnsupportedImage
+	}
+	if conf.Width > maxImageSide || conf.Height > maxImageSide {
+		return nil, ErrImageTooLarge
+	}
+	if conf.Width < minImageSide || conf.Height < minImageSide {
+		return nil, ErrImageTooSmall
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+
+	// Крупная версия строится из оригинала, остальные — из предыдущей версии: так
+	// исходное изображение обходится один раз
+	ordered := append([]int(nil), sizes...)
+	sort.Sort(sort.Reverse(sort.IntSlice(ordered)))
+
+	result := make(map[int][]byte, len(ordered))
+	current := src
+	for _, size := range ordered {
+		resized := resizeSquare(current, size)
+		var buf bytes.Buffer
+		if err := png.Encode(&buf, resized); err != nil {
+			return nil, fmt.Errorf("media: encode avatar: %w", err)
+		}
+		result[size] = buf.Bytes()
+		current = resized
+	}
+	return result, nil
+}
+
+// resizeSquare обрезает изображение по центру до квадрата и масштабирует его до size×size,
+// усредняя попадающие в каждый пиксель исходные пиксели
+func resizeSquare(src image.Image, size int) *image.NRGBA {
+	bounds := src.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	x0 := bounds.Min.X + (bounds.Dx()-side)/2
+	y0 := bounds.Min.Y + (bounds.Dy()-side)/2
+
+	dst := image.NewNRGBA(image.Rect(0, 0, size, size))
+	for dy := 0; dy < size; dy++ {
+		sy0, sy1 := y0+dy*side/size, y0+(dy+1)*side/size
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for dx := 0; dx < size; dx++ {
+			sx0, sx1 := x0+dx*side/size, x0+(dx+1)*side/size
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			// RGBA возвращает компоненты, умноженные на альфу, поэтому усреднение
+			// не окрашивает края прозрачных областей
+			var r, g, b, a uint64
+			for sy := sy0; sy < sy1; sy++ {
+				for sx := sx0; sx < sx1; sx++ {
+					cr, cg, cb, ca := src.At(sx, sy).RGBA()
+					r, g, b, a = r+uint64(cr), g+uint64(cg), b+uint64(cb), a+uint64(ca)
+				}
+			}
+			if a == 0 {
+				continue
+			}
+			n := uint64((sy1 - sy0) * (sx1 - sx0))
+			dst.SetNRGBA(dx, dy, color.NRGBA{
+				R: uint8(r * 0xff / a),
+				G: uint8(g * 0xff / a),
+				B: uint8(b * 0xff / a),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
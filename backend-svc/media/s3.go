@@ -0,0 +1,154 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// S3Store хранит объекты в бакете S3 или MinIO. Запросы подписываются AWS Signature V4.
+type S3Store struct {
+	Endpoint        string // например https://s3.eu-central-1.amazonaws.com или http://minio:9000
+	Region          string
+	Bucket          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// PathStyle адресует бакет как {Endpoint}/{Bucket}/key (MinIO), иначе {Bucket}.{host}/key
+	PathStyle bool
+	// PublicURL — адрес бакета для клиентов (CDN); пусто — адрес самого бакета
+	PublicURL string
+	Client    *http.Client
+}
+
+// NewS3Store создает хранилище; пустой endpoint означает AWS S3 в указанном регионе
+func NewS3Store(endpoint, region, bucket, accessKeyID, secretAccessKey string, pathStyle bool, publicURL string) *S3Store {
+	if endpoint == "" {
+		endpoint = "https://s3." + region + ".amazonaws.com"
+	}
+	return &S3Store{
+		Endpoint:        strings.TrimSuffix(endpoint, "/"),
+		Region:          region,
+		Bucket:          bucket,
+		AccessKeyID:     accessKeyID,
+		SecretAccessKey: secretAccessKey,
+		PathStyle:       pathStyle,
+		PublicURL:       strings.TrimSuffix(publicURL, "/"),
+		Client:          &http.Client{Timeout: 30 * time.Second},
+	}
+}
+
+// bucketURL возвращает адрес бакета с учетом стиля адресации
+func (s *S3Store) bucketURL() string {
+	if s.PathStyle {
+		return s.Endpoint + "/" + s.Bucket
+	}
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return s.Endpoint + "/" + s.Bucket
+	}
+	u.Host = s.Bucket + "." + u.Host
+	return u.String()
+}
+
+// escapeKey кодирует сегменты ключа так, как этого требует каноническая форма запроса SigV4
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = url.PathEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// Put загружает объект с заголовком Cache-Control, разрешающим бессрочное кэширование
+func (s *S3Store) Put(ctx context.Context, key, contentType string, data []byte) error {
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+	header.Set("Cache-Control", ImmutableCacheControl)
+	return s.do(ctx, http.MethodPut, key, header, data)
+}
+
+// Delete удаляет объект; S3 отвечает успехом и для отсутствующих ключей
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	return s.do(ctx, http.MethodDelete, key, http.Header{}, nil)
+}
+
+// URL возвращает публичный адрес объекта
+func (s *S3Store) URL(key string) string {
+	base := s.PublicURL
+	if base == "" {
+		base = s.bucketURL()
+	}
+	return base + "/" + escapeKey(key)
+}
+
+func (s *S3Store) do(ctx context.Context, method, key string, header http.Header, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, method, s.bucketURL()+"/"+escapeKey(key), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("media: build s3 request: %w", err)
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	s.sign(req, body, time.Now().UTC())
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("media: s3 %s %s: %w", method, key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("media: s3 %s %s: status %d: %s", method, key, resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// sign добавляет к запросу заголовки подписи AWS Signature V4. Подписываются host и
+// заголовки x-amz-*, остальных заголовков S3 в подписи не требует.
+func (s *S3Store) sign(req *http.Request, body []byte, now time.Time) {
+	payloadHash := sha256.Sum256(body)
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", hex.EncodeToString(payloadHash[:]))
+
+	const signedHeaders = "host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.RawQuery,
+		"host:" + req.URL.Host + "\n" +
+			"x-amz-content-sha256:" + hex.EncodeToString(payloadHash[:]) + "\n" +
+			"x-amz-date:" + amzDate + "\n",
+		signedHeaders,
+		hex.EncodeToString(payloadHash[:]),
+	}, "\n")
+
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + hex.EncodeToString(requestHash[:])
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretAccessKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.AccessKeyID, scope, signedHeaders, signature))
+}
@@ -0,0 +1,89 @@
+// Package media хранит пользовательские файлы (аватары, пакеты SCORM) на диске или в S3-совместимом хранилище.
+package media
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"lmsmodule/backend-svc/config"
+)
+
+// ImmutableCacheControl — ключи объектов меняются вместе с содержимым, поэтому файлы можно кэшировать бессрочно
+const ImmutableCacheControl = "public, max-age=31536000, immutable"
+
+// Store сохраняет и удаляет объекты по ключу вида "avatars/42/ab12cd/128.png"
+type Store interface {
+	Put(ctx context.Context, key, contentType string, data []byte) error
+	Delete(ctx context.Context, key string) error
+	// URL возвращает адрес, по которому клиенты получают объект
+	URL(key string) string
+}
+
+// LocalStore хранит объекты в каталоге на диске; сервис раздает их сам по BaseURL
+type LocalStore struct {
+	Dir     string
+	BaseURL string
+}
+
+func (s LocalStore) path(key string) (string, error) {
+	clean := filepath.Clean("/" + key)
+	if clean == "/" || strings.Contains(key, "..") {
+		return "", fmt.Errorf("media: invalid key %q", key)
+	}
+	return filepath.Join(s.Dir, filepath.FromSlash(clean)), nil
+}
+
+// Put записывает объект во временный файл и переименовывает его, чтобы клиенты не увидели недописанный файл
+func (s LocalStore) Put(_ context.Context, key, _ string, data []byte) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("media: create directory: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("media: write %s: %w", key, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("media: write %s: %w", key, err)
+	}
+	return nil
+}
+
+// Delete удаляет объект; отсутствующий объект не считается ошибкой
+func (s LocalStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("media: delete %s: %w", key, err)
+	}
+	os.Remove(filepath.Dir(path)) // удаляется только опустевший каталог версии
+	return nil
+}
+
+// URL возвращает адрес объекта относительно BaseURL
+func (s LocalStore) URL(key string) string {
+	return strings.TrimSuffix(s.BaseURL, "/") + "/" + key
+}
+
+// NewStore создает хранилище по конфигурации; apiPublicURL — внешний адрес сервиса,
+// относительно которого раздаются файлы локального хранилища
+func NewStore(cfg config.MediaConfig, apiPublicURL string) Store {
+	if cfg.Driver == "s3" {
+		s3 := cfg.S3
+		return NewS3Store(s3.Endpoint, s3.Region, s3.Bucket, s3.AccessKeyID, s3.SecretAccessKey, s3.PathStyle, cfg.PublicURL)
+	}
+	baseURL := cfg.PublicURL
+	if baseURL == "" {
+		baseURL = apiPublicURL + "/media"
+	}
+	return LocalStore{Dir: cfg.LocalDir, BaseURL: baseURL}
+}
@@ -0,0 +1,123 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"strconv"
+)
+
+// Совместимость схемы и кода для выкладки blue/green. Миграции делятся на расширяющие (expand:
+// новые таблицы и столбцы, старый код их не замечает) и сужающие (contract: удаление и
+// переименование). Сужающая миграция записывает в schema_compat.min_app_version версию схемы
+// первого кода, который уже не использует удаляемые объекты; код, собранный под более раннюю
+// версию, с такой базой не запускается.
+const (
+	compatTable = "schema_compat"
+	// compatSince — миграция, создавшая schema_compat; у более старых схем ограничений нет
+	compatSince = 53
+)
+
+// minAppVersionPattern находит в тексте сужающей миграции новое значение min_app_version
+var minAppVersionPattern = regexp.MustCompile(`(?i)UPDATE\s+` + compatTable + `\s+SET\s+min_app_version\s*=\s*(\d+)`)
+
+var (
+	// ErrSchemaBehind — в базе не применены миграции, нужные коду
+	ErrSchemaBehind = errors.New("database schema is behind the application")
+	// ErrSchemaContracted — сужающая миграция удалила объекты, которые использует код
+	ErrSchemaContracted = errors.New("database schema is no longer compatible with the application")
+)
+
+// Compat — состояние схемы базы относительно версии, под которую собран код
+type Compat struct {
+	// Required — версия схемы, под которую собран код
+	Required int64
+	// Current — примененная версия схемы
+	Current int64
+	Dirty   bool
+	// MinAppVersion — наименьшая версия схемы кода, совместимого с базой
+	MinAppVersion int64
+}
+
+// ReadCompat читает версию схемы и ограничение совместимости из базы
+func ReadCompat(db *sql.DB, required int64) (Compat, error) {
+	current, dirty, err := CurrentVersion(db)
+	if err != nil {
+		return Compat{}, err
+	}
+	compat := Compat{Required: required, Current: current, Dirty: dirty}
+	if current < compatSince {
+		return compat, nil
+	}
+	err = db.QueryRow("SELECT min_app_version FROM " + compatTable + " WHERE id = 1").Scan(&compat.MinAppVersion)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return compat, fmt.Errorf("read %s: %w", compatTable, err)
+	}
+	return compat, nil
+}
+
+// Err сообщает, может ли код работать с базой: схема не должна отставать от кода, а опережать
+// его — только расширяющими миграциями
+func (c Compat) Err() error {
+	switch {
+	case c.Dirty:
+		return fmt.Errorf("version %d: %w", c.Current, ErrDirty)
+	case c.Current < c.Required:
+		return fmt.Errorf("%w: schema version %d, the application needs %d; apply migrations before deploying it",
+			ErrSchemaBehind, c.Current, c.Required)
+	case c.MinAppVersion > c.Required:
+		return fmt.Errorf("%w: schema version %d requires an application built for version %d or later, this one is built for %d",
+			ErrSchemaContracted, c.Current, c.MinAppVersion, c.Required)
+	}
+	return nil
+}
+
+// Ahead сообщает, что база опережает код совместимыми миграциями (идет выкладка новой версии)
+func (c Compat) Ahead() bool {
+	return c.Err() == nil && c.Current > c.Required
+}
+
+// PendingMigration — миграция, еще не примененная к базе
+type PendingMigration struct {
+	Migration
+	// MinAppVersion — значение min_app_version, которое задает сужающая миграция; 0 — расширяющая
+	MinAppVersion int64
+}
+
+// Contract сообщает, что миграция сужающая
+func (m PendingMigration) Contract() bool {
+	return m.MinAppVersion > 0
+}
+
+// Pending возвращает миграции из fsys новее версии current
+func Pending(fsys fs.FS, current int64) ([]PendingMigration, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	var pending []PendingMigration
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+		body, err := fs.ReadFile(fsys, m.Path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", m.Path, err)
+		}
+		p := PendingMigration{Migration: m}
+		for _, match := range minAppVersionPattern.FindAllSubmatch(body, -1) {
+			version, err := strconv.ParseInt(string(match[1]), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("parse min_app_version in %s: %w", m.Path, err)
+			}
+			if version > p.MinAppVersion {
+				p.MinAppVersion = version
+			}
+		}
+		pending = append(pending, p)
+	}
+	return pending, nil
+}
@@ -0,0 +1,136 @@
+package migrate
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+)
+
+// Таблица версий совместима с golang-migrate, поэтому миграции можно применять
+// как этим пакетом, так и контейнером migrate/migrate из docker-compose
+const schemaTable = "schema_migrations"
+
+var fileNamePattern = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+// Migration — одна up-миграция из каталога migrations/
+type Migration struct {
+	Version int64
+	Name    string
+	Path    string
+}
+
+// ErrDirty возвращается, если предыдущая миграция завершилась с ошибкой
+var ErrDirty = errors.New("database schema is dirty, fix the failed migration and reset the version manually")
+
+// Load читает up-миграции из файловой системы (os.DirFS или встроенной embed.FS)
+// и сортирует их по версии
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("read migrations dir: %w", err)
+	}
+
+	var migrations []Migration
+	for _, entry := range entries {
+		match := fileNamePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("parse version of %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    match[2],
+			Path:    path.Clean(entry.Name()),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// CurrentVersion возвращает примененную версию схемы (0, если миграций не было)
+func CurrentVersion(db *sql.DB) (int64, bool, error) {
+	if _, err := db.Exec("CREATE TABLE IF NOT EXISTS " + schemaTable +
+		" (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)"); err != nil {
+		return 0, false, fmt.Errorf("create %s: %w", schemaTable, err)
+	}
+
+	var version int64
+	var dirty bool
+	err := db.QueryRow("SELECT version, dirty FROM "+schemaTable+" LIMIT 1").Scan(&version, &dirty)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, dirty, nil
+}
+
+// Up применяет все миграции новее текущей версии. Для MySQL соединение должно быть открыто
+// с multiStatements=true, так как файлы миграций содержат несколько выражений.
+// Возвращает список примененных миграций.
+func Up(db *sql.DB, fsys fs.FS) ([]Migration, error) {
+	migrations, err := Load(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	current, dirty, err := CurrentVersion(db)
+	if err != nil {
+		return nil, err
+	}
+	if dirty {
+		return nil, fmt.Errorf("version %d: %w", current, ErrDirty)
+	}
+
+	var applied []Migration
+	for _, m := range migrations {
+		if m.Version <= current {
+			continue
+		}
+
+		body, err := fs.ReadFile(fsys, m.Path)
+		if err != nil {
+			return applied, fmt.Errorf("read %s: %w", m.Path, err)
+		}
+
+		if err := setVersion(db, m.Version, true); err != nil {
+			return applied, err
+		}
+		if _, err := db.Exec(string(body)); err != nil {
+			return applied, fmt.Errorf("apply %d_%s: %w", m.Version, m.Name, err)
+		}
+		if err := setVersion(db, m.Version, false); err != nil {
+			return applied, err
+		}
+
+		applied = append(applied, m)
+	}
+
+	return applied, nil
+}
+
+func setVersion(db *sql.DB, version int64, dirty bool) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	if _, err := tx.Exec("DELETE FROM " + schemaTable); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("reset schema version: %w", err)
+	}
+	if _, err := tx.Exec("INSERT INTO "+schemaTable+" (version, dirty) VALUES (?, ?)", version, dirty); err != nil {
+		_ = tx.Rollback()
+		return fmt.Errorf("set schema version: %w", err)
+	}
+	return tx.Commit()
+}
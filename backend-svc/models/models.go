@@ -1,46 +1,109 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 )
 
+// User — учетная запись. Email, FullName и TOTPSecret пусты, если в базе NULL
 type User struct {
+	ID           int       `json:"id"`
+	Username     string    `json:"username"`
+	PasswordHash string    `json:"-"`
+	Email        string    `json:"email,omitempty"`
+	FullName     string    `json:"fullName,omitempty"`
+	TOTPSecret   string    `json:"-"`
+	Is2FAEnabled bool      `json:"is2faEnabled"`
+	IsAdmin      bool      `json:"isAdmin"`
+	IsActive     bool      `json:"isActive"`
+	LastLogin    time.Time `json:"lastLogin,omitempty"`
+	Version      int       `json:"version"` // увеличивается при каждом изменении профиля, статуса или роли
+	// DeletionScheduledAt — когда учетная запись будет удалена окончательно; nil — удаление не запрошено.
+	// До этого момента вход отменяет удаление.
+	DeletionScheduledAt *time.Time `json:"-"`
+	// AvatarKey — префикс ключей версий аватара в медиахранилище; пусто — аватар не загружен
+	AvatarKey string `json:"-"`
+	// Locale и Timezone — предпочтения пользователя для писем и отображения дат; пусто — значения по умолчанию
+	Locale   string `json:"-"`
+	Timezone string `json:"-"`
+}
+
+// UserSummary — проекция пользователя без секретов для списков и карточек
+type UserSummary struct {
 	ID           int
 	Username     string
-	PasswordHash string
 	Email        string
 	FullName     string
-	TOTPSecret   string
 	Is2FAEnabled bool
 	IsAdmin      bool
 	IsActive     bool
 	LastLogin    time.Time
+	Version      int
+	AvatarKey    string
+	Locale       string
+	Timezone     string
+}
+
+// Summary возвращает проекцию пользователя без секретов
+func (u User) Summary() UserSummary {
+	return UserSummary{
+		ID:           u.ID,
+		Username:     u.Username,
+		Email:        u.Email,
+		FullName:     u.FullName,
+		Is2FAEnabled: u.Is2FAEnabled,
+		IsAdmin:      u.IsAdmin,
+		IsActive:     u.IsActive,
+		LastLogin:    u.LastLogin,
+		Version:      u.Version,
+		AvatarKey:    u.AvatarKey,
+		Locale:       u.Locale,
+		Timezone:     u.Timezone,
+	}
 }
 
 type UserProfile struct {
-	ID           int       `json:"id"`
-	Username     string    `json:"username"`
-	Email        string    `json:"email"`
-	FullName     string    `json:"fullName"`
-	Is2FAEnabled bool      `json:"is2faEnabled"`
-	IsAdmin      bool      `json:"isAdmin,omitempty"`   // Только для админов
-	IsActive     bool      `json:"isActive,omitempty"`  // Только для админов
-	LastLogin    time.Time `json:"lastLogin,omitempty"` // Только для админов
+	ID           int         `json:"id"`
+	Username     string      `json:"username"`
+	Email        string      `json:"email"`
+	FullName     string      `json:"fullName"`
+	Is2FAEnabled bool        `json:"is2faEnabled"`
+	IsAdmin      bool        `json:"isAdmin,omitempty"`                // Только для админов
+	IsActive     bool        `json:"isActive,omitempty"`               // Только для админов
+	LastLogin    time.Time   `json:"lastLogin,omitempty"`              // Только для админов
+	Version      int         `json:"version"`                          // Передается в запросах на изменение
+	Avatar       *AvatarURLs `json:"avatar,omitempty"`                 // nil — аватар не загружен
+	Locale       string      `json:"locale" example:"ru"`              // с учетом значения по умолчанию
+	Timezone     string      `json:"timezone" example:"Europe/Moscow"` // IANA, с учетом значения по умолчанию
+}
+
+// AvatarURLs — адреса квадратных версий аватара (48, 128 и 256 пикселей)
+type AvatarURLs struct {
+	Small  string `json:"small" example:"https://cdn.example.com/avatars/42/9f86d081/48.png"`
+	Medium string `json:"medium" example:"https://cdn.example.com/avatars/42/9f86d081/128.png"`
+	Large  string `json:"large" example:"https://cdn.example.com/avatars/42/9f86d081/256.png"`
 }
 
 type UpdateProfileRequest struct {
-	Email    string `json:"email,omitempty"`
+	// Email меняется только после подтверждения по ссылке, отправленной на новый адрес
+	Email    string `json:"email,omitempty" binding:"omitempty,email"`
 	FullName string `json:"fullName,omitempty"`
 	Password string `json:"password,omitempty"`
+	Locale   string `json:"locale,omitempty" example:"ru"`              // одна из поддерживаемых локалей
+	Timezone string `json:"timezone,omitempty" example:"Europe/Moscow"` // часовой пояс IANA
+	Version  int    `json:"version,omitempty"`                          // Прочитанная версия пользователя; обязательна, если нет If-Match
 }
 
 type UpdateStatusRequest struct {
 	IsActive bool `json:"isActive" binding:"required"`
+	Version  int  `json:"version,omitempty"` // Прочитанная версия пользователя; обязательна, если нет If-Match
 }
 
 type LoginRequest struct {
 	Username string `json:"username" binding:"required"`
 	Password string `json:"password" binding:"required"`
+	// CaptchaToken обязателен после нескольких неудачных попыток входа
+	CaptchaToken string `json:"captchaToken,omitempty"`
 }
 
 type LoginResponse struct {
@@ -48,6 +111,10 @@ type LoginResponse struct {
 	UserID   int    `json:"userId"`
 	Username string `json:"username"`
 	Email    string `json:"email"`
+	// MustChangePassword — до смены пароля остальные запросы отклоняются с 403
+	MustChangePassword bool `json:"mustChangePassword,omitempty"`
+	// AccountRestored — вход отменил запланированное удаление учетной записи
+	AccountRestored bool `json:"accountRestored,omitempty"`
 }
 
 type RegisterRequest struct {
@@ -55,16 +122,261 @@ type RegisterRequest struct {
 	Password string `json:"password" binding:"required" example:"newpassword123"`
 	Email    string `json:"email" binding:"required" example:"user@example.com"`
 	FullName string `json:"fullName" binding:"required" example:"New User"`
+	// CaptchaToken обязателен, если в окружении включен провайдер CAPTCHA
+	CaptchaToken string `json:"captchaToken,omitempty"`
 }
 
+// RegisterResponse одинаков для новой и уже существующей учетной записи; токен выдается только при входе
 type RegisterResponse struct {
-	Token   string `json:"token"`
 	Message string `json:"message"`
 }
 
 type VerifyOTPRequest struct {
 	TempToken string `json:"tempToken" binding:"required"`
 	OTP       string `json:"otp" binding:"required"`
+	// RememberDevice выдает cookie доверенного устройства: на нем второй фактор не запрашивается
+	RememberDevice bool `json:"rememberDevice,omitempty"`
+}
+
+// TrustedDevice — устройство, на котором пользователь пропускает второй фактор.
+// В базе хранится только SHA-256 токена из cookie.
+type TrustedDevice struct {
+	ID         int64     `json:"id"`
+	UserID     int       `json:"userId"`
+	TokenHash  string    `json:"-"`
+	UserAgent  string    `json:"userAgent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// Каналы доставки одноразовых кодов
+const (
+	OTPChannelEmail = "email"
+	OTPChannelSMS   = "sms"
+)
+
+// Статусы доставки одноразового кода
+const (
+	OTPDeliverySent   = "sent"
+	OTPDeliveryFailed = "failed"
+)
+
+// OTPPreference — канал, через который пользователь получает одноразовые коды
+type OTPPreference struct {
+	UserID  int    `json:"-"`
+	Channel string `json:"channel" example:"sms"`
+	Phone   string `json:"phone,omitempty" example:"+79991234567"`
+}
+
+type UpdateOTPPreferenceRequest struct {
+	Channel string `json:"channel" binding:"required,oneof=email sms" example:"sms"`
+	Phone   string `json:"phone,omitempty" example:"+79991234567"` // E.164, обязателен для sms
+}
+
+// OTPDelivery — попытка доставки одноразового кода; сам код не хранится
+type OTPDelivery struct {
+	ID        int64     `json:"id"`
+	UserID    int       `json:"userId"`
+	Channel   string    `json:"channel" example:"email"`
+	Status    string    `json:"status" example:"sent"` // sent, failed
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// LoginContext — устройство и примерное местоположение, с которых пользователь входил.
+// Fingerprint — SHA-256 от семейства браузера, ОС и местоположения.
+type LoginContext struct {
+	UserID      int       `json:"-"`
+	Fingerprint string    `json:"-"`
+	IP          string    `json:"ip"`
+	UserAgent   string    `json:"userAgent"`
+	Location    string    `json:"location"`
+	FirstSeenAt time.Time `json:"firstSeenAt"`
+	LastSeenAt  time.Time `json:"lastSeenAt"`
+}
+
+// LockAccountRequest — подтверждение «это был не я» по ссылке из письма о новом входе
+type LockAccountRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+// EmailChange — запрос на смену email и его аудит. Ссылка подтверждения уходит на новый адрес,
+// ссылка отмены — на старый; отмена после подтверждения возвращает прежний адрес.
+type EmailChange struct {
+	ID               int64      `json:"id"`
+	UserID           int        `json:"userId"`
+	OldEmail         string     `json:"oldEmail"`
+	NewEmail         string     `json:"newEmail"`
+	ConfirmTokenHash string     `json:"-"`
+	CancelTokenHash  string     `json:"-"`
+	RequestedIP      string     `json:"requestedIp"`
+	CreatedAt        time.Time  `json:"createdAt"`
+	ExpiresAt        time.Time  `json:"expiresAt"`
+	CancelExpiresAt  time.Time  `json:"cancelExpiresAt"`
+	ConfirmedAt      *time.Time `json:"confirmedAt,omitempty"`
+	CancelledAt      *time.Time `json:"cancelledAt,omitempty"`
+}
+
+// EmailChangeTokenRequest — токен из ссылки подтверждения или отмены смены email
+type EmailChangeTokenRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+// UsernameChange — запись истории переименований. Прежнее имя до ReservedUntil нельзя
+// занять другому пользователю, а ссылки на него разрешаются в текущую учетную запись.
+type UsernameChange struct {
+	ID            int64     `json:"id"`
+	UserID        int       `json:"userId"`
+	OldUsername   string    `json:"oldUsername"`
+	NewUsername   string    `json:"newUsername"`
+	ChangedBy     *int      `json:"changedBy,omitempty"` // администратор, если переименовал не сам пользователь
+	ChangedAt     time.Time `json:"changedAt"`
+	ReservedUntil time.Time `json:"reservedUntil"`
+}
+
+// ProfilePrivacy — какие поля публичного профиля видят другие студенты.
+// Владелец профиля и администраторы видят все поля независимо от настроек.
+type ProfilePrivacy struct {
+	ProfileVisible  bool `json:"profileVisible"` // false — профиль скрыт целиком
+	ShowFullName    bool `json:"showFullName"`   // false — вместо полного имени показывается username
+	ShowAvatar      bool `json:"showAvatar"`
+	ShowBadges      bool `json:"showBadges"`
+	ShowCourseStats bool `json:"showCourseStats"`
+	ShowSkills      bool `json:"showSkills"`
+}
+
+// DefaultProfilePrivacy — настройки пользователей, которые их не меняли
+func DefaultProfilePrivacy() ProfilePrivacy {
+	return ProfilePrivacy{
+		ProfileVisible:  true,
+		ShowFullName:    true,
+		ShowAvatar:      true,
+		ShowBadges:      true,
+		ShowCourseStats: true,
+		ShowSkills:      true,
+	}
+}
+
+// UpdateProfilePrivacyRequest — изменение настроек приватности; отсутствующие поля не меняются
+type UpdateProfilePrivacyRequest struct {
+	ProfileVisible  *bool `json:"profileVisible,omitempty"`
+	ShowFullName    *bool `json:"showFullName,omitempty"`
+	ShowAvatar      *bool `json:"showAvatar,omitempty"`
+	ShowBadges      *bool `json:"showBadges,omitempty"`
+	ShowCourseStats *bool `json:"showCourseStats,omitempty"`
+	ShowSkills      *bool `json:"showSkills,omitempty"`
+}
+
+// PublicProfile — профиль пользователя, видимый другим студентам. Скрытые настройками
+// приватности поля равны null, а пустые списки передаются как [].
+type PublicProfile struct {
+	ID          int                 `json:"id"`
+	Username    string              `json:"username"`
+	DisplayName string              `json:"displayName"` // полное имя или username, если имя скрыто
+	Avatar      *AvatarURLs         `json:"avatar"`
+	Badges      []ProfileBadge      `json:"badges"`
+	CourseStats *ProfileCourseStats `json:"courseStats"`
+	Skills      []SkillLevel        `json:"skills"`
+	// Privacy возвращается только владельцу профиля и администраторам
+	Privacy *ProfilePrivacy `json:"privacy,omitempty"`
+}
+
+// ProfileBadge — значок за полностью пройденный курс
+type ProfileBadge struct {
+	CourseID int        `json:"courseId"`
+	Name     string     `json:"name"`
+	EarnedAt *time.Time `json:"earnedAt,omitempty"` // когда выполнено последнее задание курса
+}
+
+// ProfileCourseStats — сводка прохождения курсов
+type ProfileCourseStats struct {
+	CompletedCourses int `json:"completedCourses"`
+	StartedCourses   int `json:"startedCourses"` // выполнено хотя бы одно задание
+	CompletedTasks   int `json:"completedTasks"`
+}
+
+// SkillLevel — строка матрицы навыков: прогресс по всем курсам одного типа уязвимости
+type SkillLevel struct {
+	Skill          string `json:"skill" example:"SQL Injection"`
+	CompletedTasks int    `json:"completedTasks"`
+	TotalTasks     int    `json:"totalTasks"`
+	Level          int    `json:"level"` // процент выполненных заданий, 0–100
+}
+
+// TaskCompletion — выполненное пользователем задание
+type TaskCompletion struct {
+	TaskID      int
+	CourseID    int
+	CompletedAt time.Time // нулевое, если время выполнения неизвестно
+}
+
+// ChangeUsernameRequest — новое имя пользователя
+type ChangeUsernameRequest struct {
+	Username string `json:"username" binding:"required,min=3,max=64" example:"new_username"`
+}
+
+// SessionState — состояние учетной записи, проверяемое при каждом запросе: режим единственной
+// сессии, идентификатор текущей сессии, требование сменить пароль и активность учетной записи.
+// SessionID меняется при каждом входе; пустой — пользователь еще не входил.
+type SessionState struct {
+	SingleSession      bool
+	SessionID          string
+	MustChangePassword bool
+	IsActive           bool
+	OrganizationID     int
+	OrganizationActive bool
+}
+
+// ForcePasswordResetRequest — требование сменить пароль; SendEmail дополнительно уведомляет пользователя письмом
+type ForcePasswordResetRequest struct {
+	SendEmail bool `json:"sendEmail" example:"true"`
+}
+
+// PasswordChangeRequiredResponse — запрос отклонен, пока пользователь не сменит пароль через PUT /profile
+type PasswordChangeRequiredResponse struct {
+	Error                  string `json:"error" example:"Password change required"`
+	PasswordChangeRequired bool   `json:"passwordChangeRequired" example:"true"`
+}
+
+// SingleSessionRequest включает или выключает режим единственной сессии для пользователя
+type SingleSessionRequest struct {
+	Enabled bool `json:"enabled" example:"true"`
+}
+
+// DeleteAccountRequest — удаление собственной учетной записи. При включенной 2FA первый запрос
+// без OTP отправляет код, второй — с кодом — планирует удаление.
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+	OTP      string `json:"otp,omitempty" example:"123456"`
+	// ExportAcknowledged подтверждает, что пользователю предложили выгрузить свои данные
+	ExportAcknowledged bool `json:"exportAcknowledged" example:"true"`
+}
+
+// AccountDeletionResponse — результат запроса на удаление: отправлен код 2FA или удаление запланировано
+type AccountDeletionResponse struct {
+	Message     string     `json:"message"`
+	OTPRequired bool       `json:"otpRequired,omitempty"`
+	PurgeAt     *time.Time `json:"purgeAt,omitempty"`
+}
+
+// ExportRequiredResponse — удаление отклонено, пока клиент не предложит выгрузить данные
+type ExportRequiredResponse struct {
+	Error     string `json:"error" example:"Export your data before deleting the account"`
+	ExportURL string `json:"exportUrl" example:"/api/account/export"`
+}
+
+// AccountExport — выгрузка данных пользователя перед удалением учетной записи
+type AccountExport struct {
+	ExportedAt     time.Time       `json:"exportedAt"`
+	Profile        UserProfile     `json:"profile"`
+	Progress       UserProgress    `json:"progress"`
+	OTPPreference  OTPPreference   `json:"otpPreference"`
+	TrustedDevices []TrustedDevice `json:"trustedDevices"`
+	Logins         []LoginContext  `json:"logins"`
+	OTPDeliveries  []OTPDelivery   `json:"otpDeliveries"`
+	TaskNotes      []TaskNote      `json:"taskNotes"`
 }
 
 type Enable2FARequest struct {
@@ -73,6 +385,1306 @@ type Enable2FARequest struct {
 
 type ErrorResponse struct {
 	Error string `json:"error"`
+	// RequestID добавляется RequestIDMiddleware; его стоит указывать в обращении в поддержку
+	RequestID string `json:"requestId,omitempty"`
+}
+
+// BroadcastRequest — объявление, рассылаемое подписчикам темы broadcast по WebSocket
+type BroadcastRequest struct {
+	Message string `json:"message" binding:"required,max=2000" example:"Соревнование начнется через 10 минут"`
+}
+
+// PresenceResponse — пользователи, подключенные к WebSocket на любой из реплик
+type PresenceResponse struct {
+	Users     []int `json:"users"`
+	Instances int   `json:"instances" example:"2"`
+}
+
+// Типы документов поиска
+const (
+	SearchCourse  = "course"
+	SearchTask    = "task"
+	SearchComment = "comment"
+)
+
+// SearchHit — найденный курс, задание или сообщение обсуждения
+type SearchHit struct {
+	Type     string `json:"type" example:"task"`
+	ID       int    `json:"id"`
+	CourseID int    `json:"courseId"`
+	ThreadID int    `json:"threadId,omitempty"`
+	AuthorID *int   `json:"authorId,omitempty"`
+	// Title — название курса, задания или темы обсуждения
+	Title string `json:"title"`
+	// Snippet — фрагмент текста с совпадениями, выделенными тегом <mark>
+	Snippet string  `json:"snippet,omitempty"`
+	Score   float64 `json:"score"`
+}
+
+type SearchResponse struct {
+	Query string      `json:"query"`
+	Hits  []SearchHit `json:"hits"`
+}
+
+// InstructorGroup — группа пользователей, которые, как и администраторы, могут публиковать объявления
+const InstructorGroup = "instructors"
+
+// Announcement — объявление для всей платформы (CourseID == nil) или для одного курса.
+// Объявление видно в ленте с PublishAt до ExpiresAt; NotifiedAt — когда оно было
+// разослано подключенным клиентам.
+type Announcement struct {
+	ID         int        `json:"id"`
+	CourseID   *int       `json:"courseId"`
+	Title      string     `json:"title"`
+	Body       string     `json:"body"`
+	AuthorID   *int       `json:"authorId"`
+	PublishAt  time.Time  `json:"publishAt"`
+	ExpiresAt  *time.Time `json:"expiresAt"`
+	NotifiedAt *time.Time `json:"notifiedAt,omitempty"`
+	CreatedAt  time.Time  `json:"createdAt"`
+	UpdatedAt  time.Time  `json:"updatedAt"`
+}
+
+// AnnouncementRequest — создание или изменение объявления
+type AnnouncementRequest struct {
+	CourseID  *int       `json:"courseId"` // пусто — объявление для всей платформы
+	Title     string     `json:"title" binding:"required,max=200" example:"Плановые работы в субботу"`
+	Body      string     `json:"body" binding:"required,max=10000"`
+	PublishAt *time.Time `json:"publishAt"` // пусто — опубликовать сразу
+	ExpiresAt *time.Time `json:"expiresAt"` // пусто — без срока
+}
+
+// AnnouncementFilter — условия выборки объявлений
+type AnnouncementFilter struct {
+	// CourseID ограничивает выборку объявлениями курса и, если IncludeGlobal, общими объявлениями
+	CourseID      *int
+	IncludeGlobal bool
+	// ActiveAt оставляет только объявления, опубликованные и не истекшие к этому моменту
+	ActiveAt *time.Time
+	// AuthorID оставляет только объявления автора; 0 — любого
+	AuthorID int
+	Limit    int
+}
+
+// Conversation — личная переписка студента с преподавателем по одному курсу
+type Conversation struct {
+	ID            int       `json:"id"`
+	CourseID      int       `json:"courseId"`
+	StudentID     int       `json:"studentId"`
+	InstructorID  int       `json:"instructorId"`
+	LastMessageAt time.Time `json:"lastMessageAt"`
+	Unread        int       `json:"unread"` // непрочитанные сообщения собеседника для текущего пользователя
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+// ConversationMessage — сообщение переписки. TaskID — задание курса, о котором идет речь.
+// Скрытое модератором сообщение показывается участникам без текста.
+type ConversationMessage struct {
+	ID             int        `json:"id"`
+	ConversationID int        `json:"conversationId"`
+	SenderID       *int       `json:"senderId"` // nil — отправитель удален
+	Body           string     `json:"body"`
+	TaskID         *int       `json:"taskId,omitempty"`
+	ReportedAt     *time.Time `json:"reportedAt,omitempty"`
+	ReportedBy     *int       `json:"reportedBy,omitempty"`
+	HiddenAt       *time.Time `json:"hiddenAt,omitempty"`
+	HiddenBy       *int       `json:"hiddenBy,omitempty"`
+	CreatedAt      time.Time  `json:"createdAt"`
+}
+
+// ConversationList — переписки пользователя и общее число непрочитанных сообщений
+type ConversationList struct {
+	Conversations []Conversation `json:"conversations"`
+	UnreadTotal   int            `json:"unreadTotal"`
+}
+
+// StartConversationRequest — первое сообщение студенту или преподавателю курса
+type StartConversationRequest struct {
+	CourseID int    `json:"courseId" binding:"required"`
+	UserID   int    `json:"userId" binding:"required"` // собеседник
+	Body     string `json:"body" binding:"required,max=5000"`
+	TaskID   *int   `json:"taskId"`
+}
+
+// SendMessageRequest — сообщение в существующую переписку
+type SendMessageRequest struct {
+	Body   string `json:"body" binding:"required,max=5000" example:"Не проходит проверка во втором задании"`
+	TaskID *int   `json:"taskId"`
+}
+
+// MessageModerationRequest — решение модератора по сообщению
+type MessageModerationRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// ConversationExport — выгрузка переписки со всеми сообщениями
+type ConversationExport struct {
+	Conversation Conversation          `json:"conversation"`
+	Messages     []ConversationMessage `json:"messages"`
+	ExportedAt   time.Time             `json:"exportedAt"`
+}
+
+// TaskSchedule — срок сдачи задания и время, когда оно становится доступным
+type TaskSchedule struct {
+	TaskID    int        `json:"taskId"`
+	CourseID  int        `json:"courseId"`
+	TaskTitle string     `json:"taskTitle"`
+	ReleaseAt *time.Time `json:"releaseAt"`
+	DueAt     *time.Time `json:"dueAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// TaskScheduleRequest — изменение расписания задания; пустое поле снимает дату
+type TaskScheduleRequest struct {
+	ReleaseAt *time.Time `json:"releaseAt"`
+	DueAt     *time.Time `json:"dueAt"`
+}
+
+// LiveSession — занятие преподавателя с курсом в реальном времени (вебинар, разбор заданий)
+type LiveSession struct {
+	ID          int       `json:"id"`
+	CourseID    int       `json:"courseId"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	StartsAt    time.Time `json:"startsAt"`
+	EndsAt      time.Time `json:"endsAt"`
+	JoinURL     string    `json:"joinUrl,omitempty"`
+	HostID      *int      `json:"hostId"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// LiveSessionRequest — создание или изменение занятия
+type LiveSessionRequest struct {
+	CourseID    int       `json:"courseId" binding:"required"`
+	Title       string    `json:"title" binding:"required,max=200" example:"Разбор заданий по SQL-инъекциям"`
+	Description string    `json:"description" binding:"max=5000"`
+	StartsAt    time.Time `json:"startsAt" binding:"required"`
+	EndsAt      time.Time `json:"endsAt" binding:"required"`
+	JoinURL     string    `json:"joinUrl" binding:"omitempty,url,max=500"`
+}
+
+// Типы событий календаря
+const (
+	CalendarEventDeadline    = "deadline"
+	CalendarEventRelease     = "release"
+	CalendarEventLiveSession = "live_session"
+)
+
+// CalendarEvent — событие календаря: срок сдачи или открытие задания либо занятие.
+// У сроков и открытий EndsAt пустой.
+type CalendarEvent struct {
+	ID          string     `json:"id"` // стабильный идентификатор, например deadline-12
+	Type        string     `json:"type"`
+	Title       string     `json:"title"`
+	Description string     `json:"description,omitempty"`
+	CourseID    int        `json:"courseId"`
+	TaskID      *int       `json:"taskId,omitempty"`
+	SessionID   *int       `json:"sessionId,omitempty"`
+	StartsAt    time.Time  `json:"startsAt"`
+	EndsAt      *time.Time `json:"endsAt,omitempty"`
+	URL         string     `json:"url,omitempty"`
+}
+
+// CalendarFeed — персональная ссылка на календарь в формате iCalendar
+type CalendarFeed struct {
+	URL string `json:"url"`
+}
+
+// Статусы обращений в поддержку
+const (
+	TicketOpen       = "open"        // ждет ответа преподавателя
+	TicketInProgress = "in_progress" // преподаватель разбирается
+	TicketWaiting    = "waiting"     // ждет ответа студента
+	TicketResolved   = "resolved"    // решено; ответ студента откроет обращение снова
+	TicketClosed     = "closed"      // закрыто, ответы не принимаются
+)
+
+// TicketStatuses — допустимые статусы обращения
+var TicketStatuses = []string{TicketOpen, TicketInProgress, TicketWaiting, TicketResolved, TicketClosed}
+
+// Ticket — обращение студента в поддержку по заданию или лабораторной среде
+type Ticket struct {
+	ID           int        `json:"id"`
+	UserID       int        `json:"userId"`
+	Subject      string     `json:"subject"`
+	CourseID     *int       `json:"courseId"`
+	TaskID       *int       `json:"taskId"`
+	LabSessionID string     `json:"labSessionId,omitempty"` // идентификатор лабораторной среды, в которой возникла проблема
+	Status       string     `json:"status"`
+	AssigneeID   *int       `json:"assigneeId"`
+	CreatedAt    time.Time  `json:"createdAt"`
+	UpdatedAt    time.Time  `json:"updatedAt"`
+	ClosedAt     *time.Time `json:"closedAt,omitempty"`
+}
+
+// TicketReply — сообщение в обращении. Internal — заметка преподавателей, скрытая от студента.
+type TicketReply struct {
+	ID        int       `json:"id"`
+	TicketID  int       `json:"ticketId"`
+	AuthorID  *int      `json:"authorId"`
+	Body      string    `json:"body"`
+	Internal  bool      `json:"internal,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// TicketDetails — обращение со всеми сообщениями
+type TicketDetails struct {
+	Ticket
+	Replies []TicketReply `json:"replies"`
+}
+
+// TicketFilter — условия выборки обращений
+type TicketFilter struct {
+	UserID     int    // обращения автора; 0 — любого
+	AssigneeID int    // обращения, назначенные преподавателю; 0 — любые
+	Status     string // пусто — любой статус
+	Limit      int
+}
+
+// CreateTicketRequest — новое обращение. TaskID определяет курс, если CourseID не указан.
+type CreateTicketRequest struct {
+	Subject      string `json:"subject" binding:"required,max=200" example:"Проверка не засчитывает верный ответ"`
+	Body         string `json:"body" binding:"required,max=10000"`
+	CourseID     *int   `json:"courseId"`
+	TaskID       *int   `json:"taskId"`
+	LabSessionID string `json:"labSessionId" binding:"max=100"`
+}
+
+// TicketReplyRequest — ответ в обращении; Internal доступен только преподавателям
+type TicketReplyRequest struct {
+	Body     string `json:"body" binding:"required,max=10000"`
+	Internal bool   `json:"internal"`
+}
+
+// TicketStatusRequest — смена статуса обращения
+type TicketStatusRequest struct {
+	Status string `json:"status" binding:"required" example:"resolved"`
+}
+
+// TicketAssigneeRequest — назначение преподавателя; nil снимает назначение
+type TicketAssigneeRequest struct {
+	AssigneeID *int `json:"assigneeId"`
+}
+
+// Типы вопросов опроса
+const (
+	SurveyLikert = "likert" // оценка по шкале от 1 до SurveyScale
+	SurveyText   = "text"   // свободный ответ
+)
+
+// SurveyScale — число делений шкалы Лайкерта
+const SurveyScale = 5
+
+// Survey — опрос, который предлагается студенту после завершения курса.
+// Ответы анонимного опроса сохраняются без привязки к пользователю.
+type Survey struct {
+	ID            int              `json:"id"`
+	CourseID      int              `json:"courseId"`
+	Title         string           `json:"title"`
+	Anonymous     bool             `json:"anonymous"`
+	Active        bool             `json:"active"`
+	Questions     []SurveyQuestion `json:"questions"`
+	ResponseCount int              `json:"responseCount"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+}
+
+// SurveyQuestion — вопрос опроса
+type SurveyQuestion struct {
+	ID       int    `json:"id"`
+	Kind     string `json:"kind" binding:"required,oneof=likert text" example:"likert"`
+	Prompt   string `json:"prompt" binding:"required,max=500" example:"Насколько полезным был курс?"`
+	Required bool   `json:"required"`
+}
+
+// CourseSurvey — опрос курса глазами студента
+type CourseSurvey struct {
+	Survey
+	CourseCompleted bool `json:"courseCompleted"` // студент выполнил все задания курса и может пройти опрос
+	Responded       bool `json:"responded"`
+}
+
+// SaveSurveyRequest — создание или замена опроса курса. Вопросы опроса, на который
+// уже ответили, изменить нельзя — можно только переименовать или выключить его.
+type SaveSurveyRequest struct {
+	Title     string           `json:"title" binding:"required,max=200" example:"Отзыв о курсе"`
+	Anonymous bool             `json:"anonymous"`
+	Active    *bool            `json:"active"` // по умолчанию true
+	Questions []SurveyQuestion `json:"questions" binding:"required,min=1,max=50,dive"`
+}
+
+// SurveyAnswer — ответ на вопрос: Rating для likert, Text для text
+type SurveyAnswer struct {
+	QuestionID int    `json:"questionId" binding:"required"`
+	Rating     *int   `json:"rating,omitempty"`
+	Text       string `json:"text,omitempty" binding:"max=5000"`
+}
+
+// SubmitSurveyRequest — ответы студента на опрос
+type SubmitSurveyRequest struct {
+	Answers []SurveyAnswer `json:"answers" binding:"required,dive"`
+}
+
+// SurveyResponse — сохраненные ответы одного студента; UserID пуст для анонимного опроса
+type SurveyResponse struct {
+	ID        int
+	UserID    *int
+	CreatedAt time.Time
+	Answers   []SurveyAnswer
+}
+
+// SurveyResults — сводные результаты опроса для преподавателей
+type SurveyResults struct {
+	SurveyID      int                    `json:"surveyId"`
+	CourseID      int                    `json:"courseId"`
+	Title         string                 `json:"title"`
+	Anonymous     bool                   `json:"anonymous"`
+	ResponseCount int                    `json:"responseCount"`
+	Questions     []SurveyQuestionResult `json:"questions"`
+}
+
+// SurveyQuestionResult — итоги по вопросу. Для likert заполнены Average и Distribution
+// (число оценок 1..SurveyScale), для text — Answers.
+type SurveyQuestionResult struct {
+	SurveyQuestion
+	AnswerCount  int                `json:"answerCount"`
+	Average      *float64           `json:"average,omitempty"`
+	Distribution []int              `json:"distribution,omitempty"`
+	Answers      []SurveyTextAnswer `json:"answers,omitempty"`
+}
+
+// SurveyTextAnswer — свободный ответ; UserID не раскрывается в анонимном опросе
+type SurveyTextAnswer struct {
+	UserID    *int      `json:"userId,omitempty"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DiscussionThread — тема обсуждения курса. В закрытую тему могут писать только преподаватели.
+type DiscussionThread struct {
+	ID            int        `json:"id"`
+	CourseID      int        `json:"courseId"`
+	AuthorID      *int       `json:"authorId"`
+	Title         string     `json:"title"`
+	CommentCount  int        `json:"commentCount"`
+	LockedAt      *time.Time `json:"lockedAt,omitempty"`
+	LockedBy      *int       `json:"lockedBy,omitempty"`
+	Shadowed      bool       `json:"shadowed,omitempty"` // автор скрыто заблокирован в курсе; видно только модераторам
+	CreatedAt     time.Time  `json:"createdAt"`
+	LastCommentAt time.Time  `json:"lastCommentAt"`
+}
+
+// DiscussionComment — сообщение в теме. Скрытое модератором сообщение показывается без текста.
+type DiscussionComment struct {
+	ID        int        `json:"id"`
+	ThreadID  int        `json:"threadId"`
+	AuthorID  *int       `json:"authorId"`
+	Body      string     `json:"body"`
+	HiddenAt  *time.Time `json:"hiddenAt,omitempty"`
+	HiddenBy  *int       `json:"hiddenBy,omitempty"`
+	Shadowed  bool       `json:"shadowed,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// DiscussionThreadDetails — тема со всеми сообщениями
+type DiscussionThreadDetails struct {
+	DiscussionThread
+	Comments []DiscussionComment `json:"comments"`
+}
+
+// CreateThreadRequest — новая тема; Body становится ее первым сообщением
+type CreateThreadRequest struct {
+	Title string `json:"title" binding:"required,max=200" example:"Не запускается стенд второго задания"`
+	Body  string `json:"body" binding:"required,max=10000"`
+}
+
+// CreateCommentRequest — сообщение в теме
+type CreateCommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+// ThreadLockRequest — закрытие или открытие темы
+type ThreadLockRequest struct {
+	Locked bool `json:"locked"`
+}
+
+// CourseReview — отзыв студента о курсе, один на пользователя
+type CourseReview struct {
+	ID        int        `json:"id"`
+	CourseID  int        `json:"courseId"`
+	UserID    *int       `json:"userId"`
+	Rating    int        `json:"rating"`
+	Body      string     `json:"body"`
+	HiddenAt  *time.Time `json:"hiddenAt,omitempty"`
+	HiddenBy  *int       `json:"hiddenBy,omitempty"`
+	Shadowed  bool       `json:"shadowed,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+}
+
+// CourseReviews — отзывы о курсе и средняя оценка по видимым отзывам
+type CourseReviews struct {
+	Average *float64       `json:"average"`
+	Count   int            `json:"count"`
+	Reviews []CourseReview `json:"reviews"`
+}
+
+// ReviewRequest — создание или изменение своего отзыва
+type ReviewRequest struct {
+	Rating int    `json:"rating" binding:"required,min=1,max=5" example:"5"`
+	Body   string `json:"body" binding:"max=5000"`
+}
+
+// ContentModerationRequest — решение модератора по сообщению обсуждения или отзыву
+type ContentModerationRequest struct {
+	Hidden bool `json:"hidden"`
+}
+
+// CourseBan — скрытая блокировка пользователя в курсе: его сообщения и отзывы
+// видны только ему самому и модераторам
+type CourseBan struct {
+	CourseID  int       `json:"courseId"`
+	UserID    int       `json:"userId"`
+	BannedBy  *int      `json:"bannedBy"`
+	Reason    string    `json:"reason,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CourseBanRequest — причина блокировки для модераторов
+type CourseBanRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+// Виды контента, на который можно пожаловаться
+const (
+	ContentComment = "comment"
+	ContentReview  = "review"
+)
+
+// Статусы жалоб
+const (
+	ReportOpen      = "open"      // ждет решения модератора
+	ReportHidden    = "hidden"    // контент скрыт
+	ReportDismissed = "dismissed" // жалоба отклонена
+)
+
+// ContentReport — жалоба на сообщение обсуждения или отзыв
+type ContentReport struct {
+	ID          int        `json:"id"`
+	ContentType string     `json:"contentType" example:"comment"`
+	ContentID   int        `json:"contentId"`
+	CourseID    int        `json:"courseId"`
+	ReporterID  *int       `json:"reporterId"`
+	Reason      string     `json:"reason,omitempty"`
+	Status      string     `json:"status"`
+	ResolvedBy  *int       `json:"resolvedBy,omitempty"`
+	ResolvedAt  *time.Time `json:"resolvedAt,omitempty"`
+	Note        string     `json:"note,omitempty"` // комментарий модератора к решению
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// ReportContentRequest — жалоба пользователя
+type ReportContentRequest struct {
+	Reason string `json:"reason" binding:"max=500" example:"Спойлер решения"`
+}
+
+// ResolveReportRequest — решение по жалобе: hidden скрывает контент, dismissed отклоняет жалобу.
+// Решение применяется ко всем открытым жалобам на тот же контент.
+type ResolveReportRequest struct {
+	Resolution string `json:"resolution" binding:"required,oneof=hidden dismissed" example:"hidden"`
+	Note       string `json:"note" binding:"max=500"`
+}
+
+// ScormPackage — пакет SCORM, импортированный как курс. Каждый запускаемый элемент пакета
+// стал заданием курса.
+type ScormPackage struct {
+	ID         int    `json:"id"`
+	CourseID   int    `json:"courseId"`
+	Identifier string `json:"identifier"`
+	Version    string `json:"version" example:"2004"` // "1.2" или "2004"
+	Title      string `json:"title"`
+	// AssetKey — префикс ключей файлов пакета в медиахранилище
+	AssetKey  string      `json:"-"`
+	CreatedBy *int        `json:"createdBy,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+	Items     []ScormItem `json:"items"`
+}
+
+// ScormItem — задание курса, которое запускает учебный объект из пакета SCORM
+type ScormItem struct {
+	TaskID     int    `json:"taskId"`
+	Identifier string `json:"identifier"`
+	Title      string `json:"title"`
+	ScormType  string `json:"scormType" example:"sco"` // "sco" или "asset"
+	// Launch — путь к файлу внутри пакета или абсолютный URL; клиенты получают LaunchURL
+	Launch    string `json:"-"`
+	LaunchURL string `json:"launchUrl"`
+	// CourseID, Version и AssetKey копируются из пакета, чтобы запуск задания не требовал второго запроса
+	CourseID int    `json:"-"`
+	Version  string `json:"-"`
+	AssetKey string `json:"-"`
+}
+
+// ScormAttempt — данные CMI учащегося по одному учебному объекту
+type ScormAttempt struct {
+	UserID      int               `json:"userId"`
+	TaskID      int               `json:"taskId"`
+	Data        map[string]string `json:"data"`
+	Status      string            `json:"status" example:"completed"`
+	Score       *float64          `json:"score,omitempty"`
+	CompletedAt *time.Time        `json:"completedAt,omitempty"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+// ScormLaunch — все, что нужно плееру для запуска задания из пакета SCORM
+type ScormLaunch struct {
+	TaskID    int               `json:"taskId"`
+	CourseID  int               `json:"courseId"`
+	Version   string            `json:"version" example:"1.2"`
+	ScormType string            `json:"scormType"`
+	LaunchURL string            `json:"launchUrl"`
+	Data      map[string]string `json:"data"`
+	Completed bool              `json:"completed"`
+}
+
+// ScormCommitRequest — данные CMI, сохраненные учебным объектом через LMSCommit или Commit
+type ScormCommitRequest struct {
+	Data map[string]string `json:"data" binding:"required"`
+}
+
+// Типы вопросов, которые понимает импорт из GIFT и Moodle XML
+const (
+	QuestionMultichoice = "multichoice"
+	QuestionTrueFalse   = "truefalse"
+	QuestionShortAnswer = "shortanswer"
+	QuestionNumerical   = "numerical"
+	QuestionMatching    = "matching"
+	QuestionEssay       = "essay"
+	QuestionDescription = "description"
+)
+
+// QuizQuestion — вопрос банка вопросов в формате, не зависящем от источника импорта
+type QuizQuestion struct {
+	Type     string `json:"type" example:"multichoice"`
+	Name     string `json:"name,omitempty"`
+	Text     string `json:"text"`
+	Format   string `json:"format,omitempty" example:"html"` // формат текста: plain, html, markdown или moodle
+	Category string `json:"category,omitempty"`
+	// SingleAnswer — в вопросе с выбором ответа верен ровно один вариант
+	SingleAnswer    bool         `json:"singleAnswer,omitempty"`
+	Answers         []QuizAnswer `json:"answers,omitempty"`
+	Pairs           []QuizPair   `json:"pairs,omitempty"`
+	GeneralFeedback string       `json:"generalFeedback,omitempty"`
+}
+
+// QuizAnswer — вариант ответа. Fraction — доля балла от -1 до 1; Tolerance — допустимая
+// погрешность числового ответа.
+type QuizAnswer struct {
+	Text      string   `json:"text"`
+	Fraction  float64  `json:"fraction" example:"1"`
+	Feedback  string   `json:"feedback,omitempty"`
+	Tolerance *float64 `json:"tolerance,omitempty"`
+}
+
+// QuizPair — пара вопроса на сопоставление
+type QuizPair struct {
+	Question string `json:"question"`
+	Answer   string `json:"answer"`
+}
+
+// QuestionImportError — ошибка в одном элементе импортируемого файла
+type QuestionImportError struct {
+	Item  int    `json:"item"`           // порядковый номер элемента в файле, с 1
+	Line  int    `json:"line,omitempty"` // строка, с которой начинается элемент
+	Name  string `json:"name,omitempty"`
+	Error string `json:"error"`
+}
+
+// QuestionImportResult — разобранные вопросы и отчет об отклоненных элементах
+type QuestionImportResult struct {
+	Format    string                `json:"format" example:"gift"`
+	Total     int                   `json:"total"`
+	Questions []QuizQuestion        `json:"questions"`
+	Errors    []QuestionImportError `json:"errors"`
+}
+
+// Типы достижений Open Badges
+const (
+	AchievementCourse = "Course"      // выдается автоматически за завершение курса
+	AchievementOther  = "Achievement" // выдается преподавателем вручную
+)
+
+// BadgeClass — значок Open Badges (Achievement): за завершение курса, если указан CourseID,
+// или за достижение, которое преподаватель отмечает вручную
+type BadgeClass struct {
+	ID              int       `json:"id"`
+	CourseID        *int      `json:"courseId,omitempty"`
+	Name            string    `json:"name" example:"Web Security Basics"`
+	Description     string    `json:"description"`
+	Criteria        string    `json:"criteria" example:"Complete every task of the course"`
+	AchievementType string    `json:"achievementType" example:"Course"`
+	ImageURL        string    `json:"imageUrl"`
+	CreatedBy       *int      `json:"createdBy,omitempty"`
+	CreatedAt       time.Time `json:"createdAt"`
+	UpdatedAt       time.Time `json:"updatedAt"`
+}
+
+// SaveBadgeClassRequest — создание или изменение значка. Курс значка после создания не меняется.
+type SaveBadgeClassRequest struct {
+	CourseID    *int   `json:"courseId"`
+	Name        string `json:"name" binding:"required,max=200" example:"Web Security Basics"`
+	Description string `json:"description" binding:"required,max=2000"`
+	Criteria    string `json:"criteria" binding:"max=2000"`
+}
+
+// BadgeAssertion — выданный значок. Credential — подписанный OpenBadgeCredential (VC-JWT),
+// который не меняется после выдачи.
+type BadgeAssertion struct {
+	ID               string      `json:"id" example:"5f0c7a2e-6a4b-4d0e-9a55-1f1b2d3c4e5f"`
+	BadgeClassID     int         `json:"badgeClassId"`
+	UserID           int         `json:"userId"`
+	Username         string      `json:"username,omitempty"`
+	IssuedAt         time.Time   `json:"issuedAt"`
+	IssuedBy         *int        `json:"issuedBy,omitempty"` // пусто — выдан автоматически
+	RevokedAt        *time.Time  `json:"revokedAt,omitempty"`
+	RevocationReason string      `json:"revocationReason,omitempty"`
+	BadgeClass       *BadgeClass `json:"badgeClass,omitempty"`
+	Credential       string      `json:"-"`
+}
+
+// Badge — значок студента со ссылками для проверки, скачивания и публикации
+type Badge struct {
+	BadgeAssertion
+	URL         string `json:"url"`                   // адрес OpenBadgeCredential для проверки
+	DownloadURL string `json:"downloadUrl"`           // PNG со встроенным OpenBadgeCredential
+	LinkedInURL string `json:"linkedInUrl,omitempty"` // добавление сертификата в профиль LinkedIn
+}
+
+type AwardBadgeRequest struct {
+	UserID int `json:"userId" binding:"required"`
+}
+
+type RevokeBadgeRequest struct {
+	Reason string `json:"reason" binding:"max=255" example:"Awarded by mistake"`
+}
+
+// Статусы сертификата при проверке
+const (
+	CertificateValid   = "valid"
+	CertificateRevoked = "revoked"
+)
+
+// Certificate — сертификат о завершении курса. Имя получателя и название курса сохраняются на
+// момент выдачи: сертификат не меняется при переименовании курса или смене имени в профиле.
+type Certificate struct {
+	ID               int        `json:"-"`
+	Code             string     `json:"code" example:"7K3F-9QX2-M4PD"`
+	UserID           int        `json:"userId"`
+	CourseID         *int       `json:"courseId,omitempty"` // пусто, если курс удален
+	IssuedTo         string     `json:"issuedTo" example:"Alice Ivanova"`
+	CourseTitle      string     `json:"courseTitle" example:"SQL Injection"`
+	IssuedAt         time.Time  `json:"issuedAt"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	RevokedBy        *int       `json:"revokedBy,omitempty"`
+	RevocationReason string     `json:"revocationReason,omitempty"`
+	// VerificationURL — адрес публичной проверки; его же кодирует QR-код на сертификате
+	VerificationURL string `json:"verificationUrl"`
+}
+
+// CertificateVerification — результат публичной проверки сертификата по коду
+type CertificateVerification struct {
+	Code             string     `json:"code" example:"7K3F-9QX2-M4PD"`
+	Valid            bool       `json:"valid"`
+	Status           string     `json:"status" example:"valid"`
+	IssuedTo         string     `json:"issuedTo" example:"Alice Ivanova"`
+	CourseTitle      string     `json:"courseTitle" example:"SQL Injection"`
+	IssuedAt         time.Time  `json:"issuedAt"`
+	RevokedAt        *time.Time `json:"revokedAt,omitempty"`
+	RevocationReason string     `json:"revocationReason,omitempty"`
+}
+
+type RevokeCertificateRequest struct {
+	Reason string `json:"reason" binding:"required,max=255" example:"Academic misconduct"`
+}
+
+// TelegramAccount — чат Telegram, привязанный к пользователю
+type TelegramAccount struct {
+	UserID   int       `json:"-"`
+	ChatID   int64     `json:"-"`
+	Username string    `json:"username,omitempty" example:"alice_i"` // имя в Telegram без @
+	LinkedAt time.Time `json:"linkedAt"`
+}
+
+// TelegramStatus — состояние интеграции с Telegram для текущего пользователя
+type TelegramStatus struct {
+	Enabled bool             `json:"enabled"` // бот настроен на сервере
+	Linked  bool             `json:"linked"`
+	Account *TelegramAccount `json:"account,omitempty"`
+}
+
+// TelegramLink — одноразовая ссылка привязки: пользователь открывает ее и нажимает Start в боте
+type TelegramLink struct {
+	URL       string    `json:"url" example:"https://t.me/lms_bot?start=Xq3..."`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// ChatChannel — канал Slack или Mattermost группы (потока) студентов. Сообщения доставляются
+// через входящий webhook или ботом с токеном; секреты в ответах API не возвращаются.
+type ChatChannel struct {
+	ID         int    `json:"id"`
+	Group      string `json:"group" example:"cohort-2026-spring"`
+	Provider   string `json:"provider" example:"slack"` // slack или mattermost
+	WebhookURL string `json:"-"`
+	BotToken   string `json:"-"`
+	// ServerURL — адрес сервера Mattermost (для бота); для Slack обычно пусто
+	ServerURL string `json:"serverUrl,omitempty" example:"https://chat.example.com"`
+	// Channel — ID канала для бота; для webhook не используется
+	Channel string `json:"channel,omitempty" example:"C0123456789"`
+	// Delivery — webhook или bot, в зависимости от заданных секретов
+	Delivery string `json:"delivery" example:"webhook"`
+	// CourseID ограничивает напоминания о сроках одним курсом; пусто — все курсы
+	CourseID              *int       `json:"courseId,omitempty"`
+	Locale                string     `json:"locale" example:"ru"`
+	NotifyCoursePublished bool       `json:"notifyCoursePublished"`
+	NotifyLeaderboard     bool       `json:"notifyLeaderboard"`
+	NotifyDeadlines       bool       `json:"notifyDeadlines"`
+	LastLeaderboardAt     *time.Time `json:"lastLeaderboardAt,omitempty"`
+	CreatedBy             int        `json:"createdBy"`
+	CreatedAt             time.Time  `json:"createdAt"`
+	UpdatedAt             time.Time  `json:"updatedAt"`
+}
+
+// SaveChatChannelRequest — создание или изменение канала. Нужен webhookUrl или botToken с channel;
+// при изменении пустые webhookUrl и botToken оставляют сохраненные секреты.
+type SaveChatChannelRequest struct {
+	Group                 string `json:"group" binding:"required,max=100" example:"cohort-2026-spring"`
+	Provider              string `json:"provider" binding:"required,oneof=slack mattermost" example:"slack"`
+	WebhookURL            string `json:"webhookUrl" binding:"omitempty,url,max=500"`
+	BotToken              string `json:"botToken" binding:"max=255"`
+	ServerURL             string `json:"serverUrl" binding:"omitempty,url,max=255"`
+	Channel               string `json:"channel" binding:"max=100"`
+	CourseID              *int   `json:"courseId"`
+	Locale                string `json:"locale" example:"ru"` // пусто — язык по умолчанию
+	NotifyCoursePublished bool   `json:"notifyCoursePublished"`
+	NotifyLeaderboard     bool   `json:"notifyLeaderboard"`
+	NotifyDeadlines       bool   `json:"notifyDeadlines"`
+}
+
+// PushSubscription — подписка браузера пользователя на уведомления Web Push. Адрес и ключи
+// подписки в ответах API не возвращаются.
+type PushSubscription struct {
+	ID       int    `json:"id"`
+	UserID   int    `json:"userId"`
+	Endpoint string `json:"-"`
+	P256dh   string `json:"-"`
+	Auth     string `json:"-"`
+	// Service — хост push-сервиса браузера, например fcm.googleapis.com
+	Service   string    `json:"service" example:"fcm.googleapis.com"`
+	UserAgent string    `json:"userAgent"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SavePushSubscriptionRequest — результат PushSubscription.toJSON() в браузере
+type SavePushSubscriptionRequest struct {
+	Endpoint string `json:"endpoint" binding:"required,url,max=500"`
+	Keys     struct {
+		P256dh string `json:"p256dh" binding:"required,max=200"`
+		Auth   string `json:"auth" binding:"required,max=50"`
+	} `json:"keys"`
+}
+
+// PushConfig — параметры подписки на Web Push для браузера
+type PushConfig struct {
+	Enabled bool `json:"enabled"`
+	// PublicKey — открытый ключ VAPID для PushManager.subscribe({applicationServerKey})
+	PublicKey string `json:"publicKey,omitempty"`
+}
+
+// PushNotification — содержимое уведомления, которое получает service worker в событии push
+type PushNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	// Tag объединяет уведомления об одном событии: новое заменяет прежнее с тем же тегом
+	Tag string `json:"tag,omitempty"`
+	URL string `json:"url,omitempty"`
+}
+
+// LeaderboardEntry — место участника группы в таблице лидеров за период
+type LeaderboardEntry struct {
+	UserID         int    `json:"userId"`
+	Username       string `json:"username"`
+	TasksCompleted int    `json:"tasksCompleted"`
+}
+
+// GroupLeaderboard — таблица лидеров группы за последние дни
+type GroupLeaderboard struct {
+	Group   string             `json:"group" example:"security-team"`
+	From    time.Time          `json:"from"`
+	To      time.Time          `json:"to"`
+	Entries []LeaderboardEntry `json:"entries"`
+}
+
+// Способы подсчета очков команды в соревновании
+const (
+	ChallengeScoringSum    = "sum"     // задания, выполненные всеми участниками группы
+	ChallengeScoringBestOf = "best_of" // задания bestOf лучших участников группы
+)
+
+// Этапы соревнования
+const (
+	ChallengeStatusUpcoming = "upcoming"
+	ChallengeStatusActive   = "active"
+	ChallengeStatusFinished = "finished"
+)
+
+// TeamChallenge — соревнование групп (команд): очки дают задания, выполненные
+// участниками групп в интервале [startsAt, endsAt)
+type TeamChallenge struct {
+	ID          int    `json:"id"`
+	Title       string `json:"title" example:"Security Awareness Month"`
+	Description string `json:"description"`
+	Scoring     string `json:"scoring" example:"sum"` // sum или best_of
+	// BestOf — сколько лучших участников группы дают очки при подсчете best_of
+	BestOf   int       `json:"bestOf,omitempty" example:"5"`
+	Groups   []string  `json:"groups" example:"finance,engineering"`
+	StartsAt time.Time `json:"startsAt"`
+	EndsAt   time.Time `json:"endsAt"`
+	// Status — upcoming, active или finished на момент ответа
+	Status    string    `json:"status" example:"active"`
+	CreatedBy int       `json:"createdBy"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SaveTeamChallengeRequest — создание или изменение соревнования
+type SaveTeamChallengeRequest struct {
+	Title       string    `json:"title" binding:"required,max=255" example:"Security Awareness Month"`
+	Description string    `json:"description" binding:"max=5000"`
+	Scoring     string    `json:"scoring" binding:"required,oneof=sum best_of" example:"sum"`
+	BestOf      int       `json:"bestOf" binding:"omitempty,min=1,max=1000" example:"5"` // обязателен для best_of
+	Groups      []string  `json:"groups" binding:"required,min=2,max=50,dive,required,max=100" example:"finance,engineering"`
+	StartsAt    time.Time `json:"startsAt" binding:"required"`
+	EndsAt      time.Time `json:"endsAt" binding:"required"`
+}
+
+// TeamStanding — место группы в соревновании
+type TeamStanding struct {
+	Rank  int    `json:"rank"`
+	Group string `json:"group" example:"finance"`
+	Score int    `json:"score"`
+	// Members — участники группы, Participants — выполнившие хотя бы одно задание за время соревнования
+	Members      int `json:"members"`
+	Participants int `json:"participants"`
+}
+
+// TeamChallengeStandings — соревнование и таблица команд; группы с равными очками делят место
+type TeamChallengeStandings struct {
+	Challenge TeamChallenge  `json:"challenge"`
+	Teams     []TeamStanding `json:"teams"`
+	// MyGroups — группы текущего пользователя среди участвующих
+	MyGroups []string `json:"myGroups"`
+}
+
+// CourseRecertification — требование проходить курс заново через PeriodMonths месяцев после завершения
+type CourseRecertification struct {
+	CourseID     int `json:"courseId"`
+	PeriodMonths int `json:"periodMonths" example:"12"`
+	// ReminderDays — за сколько дней до истечения напомнить о повторном прохождении; 0 — не напоминать
+	ReminderDays int       `json:"reminderDays" example:"30"`
+	UpdatedAt    time.Time `json:"updatedAt"`
+}
+
+// SaveRecertificationRequest — включение или изменение периодического прохождения курса
+type SaveRecertificationRequest struct {
+	PeriodMonths int  `json:"periodMonths" binding:"required,min=1,max=120" example:"12"`
+	ReminderDays *int `json:"reminderDays" binding:"omitempty,min=0,max=365" example:"30"` // по умолчанию 30
+}
+
+// CourseCompletion — текущий цикл сертификации пользователя по курсу
+type CourseCompletion struct {
+	UserID      int
+	CourseID    int
+	CompletedAt time.Time
+	RemindAt    time.Time
+	ExpiresAt   time.Time
+	RemindedAt  *time.Time
+	ExpiredAt   *time.Time
+}
+
+// Состояния сертификации пользователя по курсу
+const (
+	RecertificationCompliant    = "compliant"     // курс пройден, срок не подходит к концу
+	RecertificationExpiring     = "expiring"      // срок скоро истекает, отправлено напоминание
+	RecertificationDue          = "due"           // срок истек, курс нужно пройти заново
+	RecertificationNotCompleted = "not_completed" // курс еще не пройден
+)
+
+// ComplianceFilter ограничивает отчет курсом или пользователем; 0 — без ограничения
+type ComplianceFilter struct {
+	CourseID int
+	UserID   int
+}
+
+// ComplianceEntry — состояние сертификации пользователя по курсу с периодическим прохождением
+type ComplianceEntry struct {
+	UserID      int        `json:"userId"`
+	Username    string     `json:"username"`
+	Email       string     `json:"email,omitempty"`
+	CourseID    int        `json:"courseId"`
+	CourseTitle string     `json:"courseTitle"`
+	Status      string     `json:"status" example:"compliant"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ExpiresAt   *time.Time `json:"expiresAt,omitempty"`
+	RemindAt    *time.Time `json:"-"`
+	ExpiredAt   *time.Time `json:"-"`
+}
+
+// ProgressExportFilter ограничивает выгрузку прогресса курсом; 0 — все курсы
+type ProgressExportFilter struct {
+	CourseID int
+}
+
+// ProgressRecord — выполненное пользователем задание в выгрузке прогресса
+type ProgressRecord struct {
+	UserID      int
+	Username    string
+	CourseID    int
+	CourseTitle string
+	TaskID      int
+	TaskTitle   string
+	CompletedAt *time.Time
+}
+
+// CourseAttempt — архивная попытка прохождения курса, прогресс которой был сброшен
+type CourseAttempt struct {
+	ID             int                 `json:"id"`
+	UserID         int                 `json:"userId"`
+	CourseID       int                 `json:"courseId"`
+	Number         int                 `json:"number" example:"1"`
+	TasksCompleted int                 `json:"tasksCompleted"`
+	TasksTotal     int                 `json:"tasksTotal"`
+	ResetBy        *int                `json:"resetBy"`
+	Reason         string              `json:"reason,omitempty"`
+	ResetAt        time.Time           `json:"resetAt"`
+	Tasks          []CourseAttemptTask `json:"tasks"`
+}
+
+// CourseAttemptTask — задание архивной попытки: когда выполнено и состояние SCORM на момент сброса
+type CourseAttemptTask struct {
+	TaskID      int        `json:"taskId"`
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+	ScormStatus string     `json:"scormStatus,omitempty"`
+	ScormScore  *float64   `json:"scormScore,omitempty"`
+}
+
+// ResetProgressRequest — причина сброса прогресса, например пересдача экзамена
+type ResetProgressRequest struct {
+	Reason string `json:"reason" binding:"max=500" example:"Exam retake"`
+}
+
+// ProgressPoint — снимок прогресса пользователя по курсу за день
+type ProgressPoint struct {
+	Date           string  `json:"date" example:"2025-03-01"`
+	TasksCompleted int     `json:"tasksCompleted"`
+	TasksTotal     int     `json:"tasksTotal"`
+	Percent        float64 `json:"percent" example:"66.7"`
+}
+
+// SetManagerRequest — руководитель пользователя; null снимает руководителя
+type SetManagerRequest struct {
+	ManagerID *int `json:"managerId" example:"2"`
+}
+
+// TeamMember — подчиненный в панели руководителя с итогами по курсам и срокам
+type TeamMember struct {
+	UserID            int        `json:"userId"`
+	Username          string     `json:"username"`
+	FullName          string     `json:"fullName,omitempty"`
+	Email             string     `json:"email"`
+	IsActive          bool       `json:"isActive"`
+	CoursesStarted    int        `json:"coursesStarted"`
+	CoursesCompleted  int        `json:"coursesCompleted"`
+	OverdueTasks      int        `json:"overdueTasks"`
+	UpcomingDeadlines int        `json:"upcomingDeadlines"`
+	NextDueAt         *time.Time `json:"nextDueAt,omitempty"`
+}
+
+// CourseProgressSummary — прогресс пользователя по начатому курсу
+type CourseProgressSummary struct {
+	CourseID       int     `json:"courseId"`
+	CourseTitle    string  `json:"courseTitle"`
+	TasksCompleted int     `json:"tasksCompleted"`
+	TasksTotal     int     `json:"tasksTotal"`
+	Percent        float64 `json:"percent" example:"66.7"`
+}
+
+// TeamDeadline — невыполненное задание со сроком в начатом пользователем курсе
+type TeamDeadline struct {
+	TaskID      int       `json:"taskId"`
+	TaskTitle   string    `json:"taskTitle"`
+	CourseID    int       `json:"courseId"`
+	CourseTitle string    `json:"courseTitle"`
+	DueAt       time.Time `json:"dueAt"`
+	Overdue     bool      `json:"overdue"`
+}
+
+// TeamMemberProgress — прогресс, сроки и просроченные задания подчиненного
+type TeamMemberProgress struct {
+	TeamMember
+	Courses   []CourseProgressSummary `json:"courses"`
+	Deadlines []TeamDeadline          `json:"deadlines"`
+}
+
+// DefaultOrganizationID — организация, в которую попадают данные, созданные без организации
+const DefaultOrganizationID = 1
+
+// Organization — организация-арендатор со своими пользователями, курсами и группами
+type Organization struct {
+	ID        int       `json:"id"`
+	Slug      string    `json:"slug" example:"acme"`
+	Name      string    `json:"name" example:"ACME Corp"`
+	IsActive  bool      `json:"isActive"`
+	CreatedAt time.Time `json:"createdAt"`
+	Users     int       `json:"users"`
+	Courses   int       `json:"courses"`
+}
+
+// SaveOrganizationRequest — создание или изменение организации
+type SaveOrganizationRequest struct {
+	Slug     string `json:"slug" binding:"required,min=2,max=64" example:"acme"`
+	Name     string `json:"name" binding:"required,max=200" example:"ACME Corp"`
+	IsActive *bool  `json:"isActive" example:"true"`
+}
+
+// SetOrganizationRequest — перенос пользователя или курса в другую организацию
+type SetOrganizationRequest struct {
+	OrganizationID int `json:"organizationId" binding:"required,min=1" example:"2"`
+}
+
+// OrganizationMember — пользователь организации для ее администраторов
+type OrganizationMember struct {
+	UserID     int    `json:"userId"`
+	Username   string `json:"username"`
+	FullName   string `json:"fullName,omitempty"`
+	Email      string `json:"email"`
+	IsActive   bool   `json:"isActive"`
+	IsOrgAdmin bool   `json:"isOrgAdmin"`
+}
+
+// OrganizationBranding — оформление писем и сертификатов организации; пустые поля означают
+// оформление по умолчанию
+type OrganizationBranding struct {
+	OrganizationID   int    `json:"organizationId"`
+	OrganizationName string `json:"organizationName"`
+	// LogoURL — адрес загруженного логотипа; меняется при каждой загрузке
+	LogoURL      string `json:"logoUrl,omitempty"`
+	HasLogo      bool   `json:"-"`
+	PrimaryColor string `json:"primaryColor,omitempty" example:"#1a4d8f"`
+	AccentColor  string `json:"accentColor,omitempty" example:"#f2a900"`
+	// EmailFooter добавляется в конец каждого письма участникам организации
+	EmailFooter string `json:"emailFooter,omitempty" example:"ACME Corp, Security Awareness Team"`
+	// CertificateTemplate — HTML-шаблон сертификата (Go html/template); пустой — шаблон по умолчанию
+	CertificateTemplate string    `json:"certificateTemplate,omitempty"`
+	UpdatedAt           time.Time `json:"updatedAt"`
+}
+
+// SaveBrandingRequest — изменение оформления организации; логотип загружается отдельно
+type SaveBrandingRequest struct {
+	PrimaryColor        string `json:"primaryColor" binding:"omitempty,hexcolor,len=7" example:"#1a4d8f"`
+	AccentColor         string `json:"accentColor" binding:"omitempty,hexcolor,len=7" example:"#f2a900"`
+	EmailFooter         string `json:"emailFooter" binding:"max=1000"`
+	CertificateTemplate string `json:"certificateTemplate" binding:"max=20000"`
+}
+
+// IPAllowlist — адреса, с которых пользователи организации могут обращаться к API;
+// пустой список снимает ограничение
+type IPAllowlist struct {
+	OrganizationID int        `json:"organizationId"`
+	CIDRs          []string   `json:"cidrs" example:"203.0.113.0/24"`
+	UpdatedBy      *int       `json:"updatedBy,omitempty"`
+	UpdatedAt      *time.Time `json:"updatedAt,omitempty"`
+}
+
+// SetIPAllowlistRequest — новый список разрешенных адресов организации: адреса или подсети CIDR
+type SetIPAllowlistRequest struct {
+	CIDRs []string `json:"cidrs" binding:"max=100" example:"203.0.113.0/24"`
+}
+
+// Invitation — ссылка для регистрации в организации. MaxUses = 1 — одноразовая ссылка,
+// 0 — без ограничения числа регистраций до истечения срока.
+type Invitation struct {
+	ID               int    `json:"id"`
+	OrganizationID   int    `json:"organizationId"`
+	OrganizationName string `json:"organizationName"`
+	// Group — группа, в которую попадают зарегистрированные по ссылке; пустая — без группы
+	Group     string `json:"group,omitempty" example:"cohort-2025-q1"`
+	CourseIDs []int  `json:"courseIds"`
+	MaxUses   int    `json:"maxUses" example:"1"`
+	Uses      int    `json:"uses"`
+	// URL возвращается только при создании: в базе хранится лишь хеш токена
+	URL       string     `json:"url,omitempty"`
+	TokenHash string     `json:"-"`
+	ExpiresAt time.Time  `json:"expiresAt"`
+	CreatedBy *int       `json:"createdBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+	RevokedAt *time.Time `json:"revokedAt,omitempty"`
+}
+
+// CreateInvitationRequest — новая ссылка-приглашение
+type CreateInvitationRequest struct {
+	// OrganizationID учитывается только в административном API; по умолчанию — организация по умолчанию
+	OrganizationID int `json:"organizationId,omitempty"`
+	// MaxUses: 1 — одноразовая ссылка, 0 — без ограничения
+	MaxUses        int    `json:"maxUses" binding:"min=0,max=10000" example:"30"`
+	ExpiresInHours int    `json:"expiresInHours" binding:"required,min=1,max=2160" example:"168"`
+	Group          string `json:"group,omitempty" binding:"max=100" example:"cohort-2025-q1"`
+	CourseIDs      []int  `json:"courseIds,omitempty" binding:"max=50,dive,min=1"`
+}
+
+// InvitationPreview — что увидит приглашенный до регистрации
+type InvitationPreview struct {
+	OrganizationName string    `json:"organizationName"`
+	Group            string    `json:"group,omitempty"`
+	Courses          []string  `json:"courses"`
+	ExpiresAt        time.Time `json:"expiresAt"`
+}
+
+// CourseAssignment — курс, назначенный пользователю
+type CourseAssignment struct {
+	CourseID    int       `json:"courseId"`
+	CourseTitle string    `json:"courseTitle"`
+	AssignedAt  time.Time `json:"assignedAt"`
+	// InvitationID — приглашение, по которому назначен курс
+	InvitationID *int `json:"invitationId,omitempty"`
+}
+
+// PendingRegistration — самостоятельная регистрация, ожидающая одобрения администратора
+type PendingRegistration struct {
+	UserID      int       `json:"userId"`
+	Username    string    `json:"username"`
+	Email       string    `json:"email"`
+	FullName    string    `json:"fullName"`
+	Locale      string    `json:"-"`
+	RequestedIP string    `json:"requestedIp"`
+	RequestedAt time.Time `json:"requestedAt"`
+}
+
+// RejectRegistrationRequest — необязательная причина отказа, которая попадет в письмо заявителю
+type RejectRegistrationRequest struct {
+	Reason string `json:"reason" binding:"max=1000" example:"Please register with your corporate email address"`
+}
+
+// AccessExpiry — срок доступа учетной записи; после него фоновая задача деактивирует пользователя
+type AccessExpiry struct {
+	UserID        int        `json:"userId"`
+	Username      string     `json:"username"`
+	Email         string     `json:"email"`
+	FullName      string     `json:"fullName"`
+	IsActive      bool       `json:"isActive"`
+	ExpiresAt     time.Time  `json:"expiresAt"`
+	DeactivatedAt *time.Time `json:"deactivatedAt,omitempty"`
+}
+
+// SetAccessExpiryRequest задает срок доступа пользователя; null снимает ограничение
+type SetAccessExpiryRequest struct {
+	ExpiresAt *time.Time `json:"expiresAt" example:"2026-12-31T23:59:59Z"`
+}
+
+// ExtendAccessExpiryRequest продлевает доступ нескольких пользователей: либо до даты expiresAt,
+// либо на days дней от текущего срока (от текущего момента, если срок уже прошел)
+type ExtendAccessExpiryRequest struct {
+	UserIDs   []int      `json:"userIds" binding:"required,min=1,max=1000,dive,min=1"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" example:"2027-06-30T23:59:59Z"`
+	Days      int        `json:"days,omitempty" binding:"min=0,max=3650" example:"90"`
+}
+
+// ExtendAccessExpiryResponse — результат продления: новые сроки и пользователи без срока доступа,
+// которые были пропущены
+type ExtendAccessExpiryResponse struct {
+	Extended []AccessExpiry `json:"extended"`
+	Skipped  []int          `json:"skipped"`
+}
+
+// CourseProgressHistory — прогресс пользователя по курсу во времени, по возрастанию дат
+type CourseProgressHistory struct {
+	CourseID    int             `json:"courseId"`
+	CourseTitle string          `json:"courseTitle"`
+	Points      []ProgressPoint `json:"points"`
+}
+
+// DigestSettings — подписка пользователя на еженедельную сводку по email
+type DigestSettings struct {
+	Enabled bool `json:"enabled"`
+	// LastDigestAt — когда сводка в последний раз собиралась для пользователя
+	LastDigestAt *time.Time `json:"lastDigestAt,omitempty"`
+}
+
+// UpdateDigestSettingsRequest — включение или отключение еженедельной сводки
+type UpdateDigestSettingsRequest struct {
+	Enabled *bool `json:"enabled" binding:"required"`
+}
+
+// DigestUnsubscribeRequest — токен из ссылки отписки в письме со сводкой
+type DigestUnsubscribeRequest struct {
+	Token string `json:"token" form:"token" binding:"required"`
+}
+
+// PlatformStats — показатели платформы для панели администратора. Дни считаются в UTC.
+type PlatformStats struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	// From и To — первый и последний день периода (YYYY-MM-DD)
+	From  string    `json:"from"`
+	To    string    `json:"to"`
+	Users UserStats `json:"users"`
+	// DailyActive, Registrations и Completions — значения по дням периода, включая нулевые
+	DailyActive   []DailyCount  `json:"dailyActive"`
+	Registrations []DailyCount  `json:"registrations"`
+	Completions   []DailyCount  `json:"completions"`
+	Courses       []CourseStats `json:"courses"`
+	// AverageScore — средний балл попыток SCORM по всем курсам; nil — оценок нет
+	AverageScore *float64 `json:"averageScore"`
+}
+
+// UserStats — число пользователей и активных за последние 1, 7 и 30 дней
+type UserStats struct {
+	Total       int `json:"total"`
+	Active      int `json:"active"` // учетные записи, которые не деактивированы
+	NewInPeriod int `json:"newInPeriod"`
+	DAU         int `json:"dau"`
+	WAU         int `json:"wau"`
+	MAU         int `json:"mau"`
+}
+
+// DailyCount — значение показателя за день
+type DailyCount struct {
+	Date  string `json:"date" example:"2025-03-01"`
+	Count int    `json:"count"`
+}
+
+// CourseStats — показатели курса
+type CourseStats struct {
+	CourseID int    `json:"courseId"`
+	Title    string `json:"title"`
+	Tasks    int    `json:"tasks"`
+	// Learners — пользователи, выполнившие хотя бы одно задание; Finished — выполнившие все
+	Learners int `json:"learners"`
+	Finished int `json:"finished"`
+	// CompletionsInPeriod — выполненные за период задания
+	CompletionsInPeriod int `json:"completionsInPeriod"`
+	// AverageScore — средний балл попыток SCORM; nil — оценок нет
+	AverageScore *float64 `json:"averageScore"`
+	// AverageRating — средняя оценка в видимых отзывах; nil — отзывов нет
+	AverageRating *float64 `json:"averageRating"`
+	Reviews       int      `json:"reviews"`
+}
+
+// CaptchaRequiredResponse — запрос отклонен, клиент должен показать виджет CAPTCHA и повторить его с captchaToken
+type CaptchaRequiredResponse struct {
+	Error           string `json:"error" example:"Captcha verification required"`
+	CaptchaRequired bool   `json:"captchaRequired" example:"true"`
+	Provider        string `json:"provider" example:"turnstile"`
+	SiteKey         string `json:"siteKey"`
 }
 
 type SuccessResponse struct {
@@ -89,11 +1701,18 @@ type Enable2FAResponse struct {
 }
 
 type Course struct {
-	ID                int    `json:"id"`
-	VulnerabilityType string `json:"vulnerabilityType"`
-	TasksCount        int    `json:"tasksCount"`
-	Description       string `json:"description"`
-	Tasks             []Task `json:"tasks"`
+	ID                int       `json:"id"`
+	VulnerabilityType string    `json:"vulnerabilityType"`
+	TasksCount        int       `json:"tasksCount"`
+	AverageDifficulty *float64  `json:"averageDifficulty,omitempty"` // средняя сложность заданий: easy = 1, medium = 2, hard = 3
+	Enrollments       int       `json:"enrollments"`                 // пользователи, выполнившие хотя бы одно задание
+	Reviews           int       `json:"reviews"`                     // видимые отзывы
+	AverageRating     *float64  `json:"averageRating,omitempty"`     // средняя оценка видимых отзывов
+	Description       string    `json:"description"`
+	Tasks             []Task    `json:"tasks"`
+	UpdatedAt         time.Time `json:"updatedAt"` // время последнего изменения курса или его заданий
+	// ArchivedAt — курс в архиве: скрыт из каталога и закрыт для новых слушателей
+	ArchivedAt *time.Time `json:"archivedAt,omitempty"`
 }
 
 type Task struct {
@@ -105,7 +1724,391 @@ type Task struct {
 	Order       int    `json:"order"`      // порядковый номер задания в курсе
 }
 
+// CourseTranslation — перевод названия и описания курса на один язык.
+// Пустое поле означает, что для него показывается исходный текст.
+type CourseTranslation struct {
+	CourseID          int       `json:"courseId"`
+	Locale            string    `json:"locale"`
+	VulnerabilityType string    `json:"vulnerabilityType"`
+	Description       string    `json:"description"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+// SaveTaskRequest — новое задание курса или новые значения его полей
+type SaveTaskRequest struct {
+	Title       string `json:"title" binding:"required,max=255" example:"Blind SQL injection"`
+	Description string `json:"description" binding:"max=5000"`
+	Difficulty  string `json:"difficulty" binding:"required,oneof=easy medium hard" example:"medium"`
+	// Order — порядковый номер в курсе; при добавлении 0 означает «в конец», при изменении — «не менять»
+	Order int `json:"order" binding:"min=0"`
+}
+
+// Виды записей истории содержимого курса
+const (
+	CourseChangeTaskAdded     = "task_added"
+	CourseChangeTaskUpdated   = "task_updated"
+	CourseChangeSurveyUpdated = "survey_updated"
+)
+
+// CourseChange — запись истории содержимого курса. Version растет на единицу с каждым
+// изменением курса; Title — название задания или опроса на момент изменения.
+type CourseChange struct {
+	ID        int       `json:"id"`
+	CourseID  int       `json:"courseId"`
+	Version   int       `json:"version"`
+	Kind      string    `json:"kind"`
+	TaskID    *int      `json:"taskId,omitempty"`
+	Title     string    `json:"title"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// CourseChangelog — история содержимого курса, новые записи первыми. SeenVersion — последняя
+// версия, которую видел пользователь; записи с большей версией для него новые.
+type CourseChangelog struct {
+	CourseID    int            `json:"courseId"`
+	Version     int            `json:"version"`
+	SeenVersion int            `json:"seenVersion"`
+	Changes     []CourseChange `json:"changes"`
+}
+
+// CourseChangesFeed — изменения начатых пользователем курсов с его последнего посещения
+type CourseChangesFeed struct {
+	Courses []CourseChangesDigest `json:"courses"`
+}
+
+// CourseChangesDigest — новые для пользователя изменения одного курса, новые первыми
+type CourseChangesDigest struct {
+	CourseID          int            `json:"courseId"`
+	VulnerabilityType string         `json:"vulnerabilityType"`
+	Changes           []CourseChange `json:"changes"`
+}
+
+// Bookmark — закладка пользователя на курс (TaskID пуст) или на задание курса. Notify —
+// сообщить, когда курс из закладки вернется в каталог из архива; у закладок на задания не задается.
+// Title и CourseTitle — названия задания и курса на языке пользователя.
+type Bookmark struct {
+	UserID      int       `json:"-"`
+	CourseID    int       `json:"courseId"`
+	TaskID      *int      `json:"taskId,omitempty"`
+	Title       string    `json:"title"`
+	CourseTitle string    `json:"courseTitle"`
+	Notify      bool      `json:"notify"`
+	Archived    bool      `json:"archived,omitempty"` // курс сейчас в архиве
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// SaveBookmarkRequest — параметры закладки на курс
+type SaveBookmarkRequest struct {
+	Notify bool `json:"notify"`
+}
+
+// TaskNote — личная заметка пользователя к заданию в Markdown; видна только автору.
+// Version растет с каждым сохранением.
+type TaskNote struct {
+	TaskID    int       `json:"taskId"`
+	CourseID  int       `json:"courseId"`
+	TaskTitle string    `json:"taskTitle"`
+	Body      string    `json:"body"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// SaveTaskNoteRequest — автосохранение заметки. Version — версия, с которой клиент начал
+// правку; 0 — сохранить без проверки.
+type SaveTaskNoteRequest struct {
+	Body    string `json:"body" binding:"max=65536" example:"Payload: ' OR 1=1 --"`
+	Version int    `json:"version" binding:"min=0"`
+}
+
+// TaskTranslation — перевод названия и описания задания на один язык
+type TaskTranslation struct {
+	TaskID      int       `json:"taskId"`
+	Locale      string    `json:"locale"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// CourseTranslations — все переводы курса и его заданий
+type CourseTranslations struct {
+	CourseID int                 `json:"courseId"`
+	Course   []CourseTranslation `json:"course"`
+	Tasks    []TaskTranslation   `json:"tasks"`
+}
+
+// CourseTranslationRequest — перевод курса на язык из пути запроса
+type CourseTranslationRequest struct {
+	VulnerabilityType string `json:"vulnerabilityType" binding:"max=100" example:"SQL-инъекции"`
+	Description       string `json:"description"`
+}
+
+// TaskTranslationRequest — перевод задания на язык из пути запроса
+type TaskTranslationRequest struct {
+	Title       string `json:"title" binding:"max=255"`
+	Description string `json:"description"`
+}
+
 type UserProgress struct {
 	UserID    int          `json:"userId"`
 	Completed map[int]bool `json:"completed"` // ключ - ID задания
 }
+
+// Результаты отметки задания в пакетном запросе
+const (
+	TaskCompletionCompleted        = "completed"
+	TaskCompletionAlreadyCompleted = "already_completed"
+	TaskCompletionNotFound         = "not_found"
+	// TaskCompletionCourseArchived — курс в архиве, а пользователь его не начинал
+	TaskCompletionCourseArchived = "course_archived"
+)
+
+// CompleteTasksRequest — пакетная отметка заданий (проверка тестов, импорт)
+type CompleteTasksRequest struct {
+	TaskIDs []int `json:"taskIds" binding:"required,min=1,max=100,dive,gt=0" example:"1,2,3"`
+}
+
+// TaskCompletionResult — результат отметки одного задания
+type TaskCompletionResult struct {
+	TaskID int    `json:"taskId"`
+	Status string `json:"status" example:"completed"` // completed, already_completed, not_found
+}
+
+type CompleteTasksResponse struct {
+	Results   []TaskCompletionResult `json:"results"`
+	Completed int                    `json:"completed"` // сколько заданий отмечено этим запросом
+}
+
+// Типы доменных событий, записываемых в outbox
+const (
+	EventUserRegistered    = "user.registered"
+	EventUserStatusChanged = "user.status_changed"
+	EventUserRoleChanged   = "user.role_changed"
+	EventTaskCompleted     = "task.completed"
+	EventUserDeleted       = "user.deleted"
+	EventUserEmailChanged  = "user.email_changed"
+	EventUserRenamed       = "user.renamed"
+	// EventUserAnonymized — персональные данные неактивной учетной записи удалены по политике хранения
+	EventUserAnonymized = "user.anonymized"
+	// EventRecertificationDue — сертификация пользователя по курсу истекла, прогресс курса сброшен
+	EventRecertificationDue = "course.recertification_due"
+	// EventCourseProgressReset — прогресс пользователя по курсу перенесен в архив попыток
+	EventCourseProgressReset = "course.progress_reset"
+	// EventCourseCreated — в каталог добавлен курс с заданиями; AggregateID — 0, событие
+	// не относится к конкретному пользователю
+	EventCourseCreated = "course.created"
+	// EventCommentCreated — в обсуждении курса опубликовано сообщение; AggregateID — автор
+	EventCommentCreated = "comment.created"
+	// EventCommentModerated — модератор скрыл или вернул сообщение обсуждения; AggregateID — 0
+	EventCommentModerated = "comment.moderated"
+	// EventCourseContentChanged — в историю содержимого курса добавлена запись; AggregateID — 0
+	EventCourseContentChanged = "course.content_changed"
+)
+
+// OutboxEvent — доменное событие, ожидающее публикации диспетчером
+type OutboxEvent struct {
+	ID          int64           `json:"id"`
+	EventType   string          `json:"eventType"`
+	AggregateID int             `json:"aggregateId"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+// Setting — runtime-параметр, изменяемый администратором без передеплоя
+type Setting struct {
+	Key       string    `json:"key"`
+	Value     string    `json:"value"`
+	UpdatedBy int       `json:"updatedBy,omitempty"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type UpdateSettingRequest struct {
+	Value string `json:"value" example:"true"`
+}
+
+type PublicSettingsResponse struct {
+	RegistrationOpen bool `json:"registrationOpen"`
+	// RegistrationApproval — новые учетные записи активируются только после одобрения администратором
+	RegistrationApproval bool   `json:"registrationApproval"`
+	MaintenanceBanner    string `json:"maintenanceBanner,omitempty"`
+	MaintenanceMode      bool   `json:"maintenanceMode"`
+	MaintenanceMessage   string `json:"maintenanceMessage,omitempty"`
+	// ReadOnlyMode — изменения данных временно недоступны, чтение работает
+	ReadOnlyMode    bool   `json:"readOnlyMode"`
+	CaptchaProvider string `json:"captchaProvider,omitempty"` // пусто, если CAPTCHA отключена
+	CaptchaSiteKey  string `json:"captchaSiteKey,omitempty"`
+}
+
+type MaintenanceRequest struct {
+	Enabled bool `json:"enabled"`
+	// ReadOnly вместе с Enabled включает режим только для чтения вместо полного обслуживания
+	ReadOnly bool   `json:"readOnly,omitempty"`
+	Message  string `json:"message,omitempty" example:"Upgrading the database, back at 18:00 UTC"`
+}
+
+type MaintenanceResponse struct {
+	Error       string `json:"error" example:"Service is under maintenance"`
+	Message     string `json:"message"`
+	Maintenance bool   `json:"maintenance" example:"true"`
+	// ReadOnly — отклонен изменяющий запрос в режиме только для чтения
+	ReadOnly bool `json:"readOnly,omitempty"`
+}
+
+// FeatureFlag — флаг функциональности с глобальным, процентным и групповым таргетингом
+type FeatureFlag struct {
+	Key               string    `json:"key" example:"quiz_engine_v2"`
+	Description       string    `json:"description"`
+	Enabled           bool      `json:"enabled"`
+	RolloutPercentage int       `json:"rolloutPercentage" example:"0"`
+	TargetGroups      []string  `json:"targetGroups"`
+	UpdatedAt         time.Time `json:"updatedAt"`
+}
+
+type UpsertFeatureFlagRequest struct {
+	Description       string   `json:"description"`
+	Enabled           bool     `json:"enabled"`
+	RolloutPercentage int      `json:"rolloutPercentage" binding:"min=0,max=100"`
+	TargetGroups      []string `json:"targetGroups"`
+}
+
+// CourseExport — курс с заданиями в переносимом формате для lmsctl export-course
+type CourseExport struct {
+	VulnerabilityType string `json:"vulnerabilityType"`
+	Description       string `json:"description"`
+	Tasks             []Task `json:"tasks"`
+}
+
+// IdempotentResponse — сохраненный ответ на запрос с заголовком Idempotency-Key.
+// Scope включает метод, путь и пользователя, чтобы ключи разных клиентов не пересекались.
+type IdempotentResponse struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	StatusCode  int
+	Body        []byte
+	CreatedAt   time.Time
+}
+
+// BulkUserFilter выбирает пользователей для массовой операции; пустые поля не ограничивают выборку
+type BulkUserFilter struct {
+	Query          string `json:"query,omitempty" binding:"max=100" example:"contractor"`
+	Group          string `json:"group,omitempty" binding:"max=100" example:"beta-testers"`
+	IsAdmin        *bool  `json:"isAdmin,omitempty"`
+	IsActive       *bool  `json:"isActive,omitempty" example:"true"`
+	OrganizationID int    `json:"organizationId,omitempty" binding:"min=0"`
+}
+
+// BulkUserRequest — массовое изменение пользователей: выборка по списку ID или по фильтру
+// и действия, применяемые к каждому в одной транзакции
+type BulkUserRequest struct {
+	UserIDs         []int           `json:"userIds,omitempty" binding:"max=1000,dive,min=1"`
+	Filter          *BulkUserFilter `json:"filter,omitempty"`
+	Role            string          `json:"role,omitempty" binding:"omitempty,oneof=admin user" example:"user"`
+	AddGroups       []string        `json:"addGroups,omitempty" binding:"max=50,dive,min=1,max=100"`
+	RemoveGroups    []string        `json:"removeGroups,omitempty" binding:"max=50,dive,min=1,max=100"`
+	AssignCourses   []int           `json:"assignCourses,omitempty" binding:"max=100,dive,min=1"`
+	UnassignCourses []int           `json:"unassignCourses,omitempty" binding:"max=100,dive,min=1"`
+}
+
+// Результаты массовой операции для отдельного пользователя
+const (
+	BulkUserUpdated   = "updated"
+	BulkUserUnchanged = "unchanged"
+	BulkUserNotFound  = "not_found"
+)
+
+// BulkUserResult — что массовая операция изменила у пользователя; Errors перечисляет действия,
+// которые к нему неприменимы (например, группа другой организации)
+type BulkUserResult struct {
+	UserID            int      `json:"userId"`
+	Username          string   `json:"username,omitempty"`
+	Status            string   `json:"status" example:"updated"`
+	RoleChanged       bool     `json:"roleChanged,omitempty"`
+	GroupsAdded       []string `json:"groupsAdded,omitempty"`
+	GroupsRemoved     []string `json:"groupsRemoved,omitempty"`
+	CoursesAssigned   []int    `json:"coursesAssigned,omitempty"`
+	CoursesUnassigned []int    `json:"coursesUnassigned,omitempty"`
+	Errors            []string `json:"errors,omitempty"`
+}
+
+// BulkUserResponse — отчет о массовой операции
+type BulkUserResponse struct {
+	Matched int              `json:"matched"`
+	Updated int              `json:"updated"`
+	Results []BulkUserResult `json:"results"`
+}
+
+// AuditChange — изменение одного поля цели действия
+type AuditChange struct {
+	Field  string      `json:"field" example:"isActive"`
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+// AuditEntry — запись журнала действий администраторов и преподавателей
+type AuditEntry struct {
+	ID            int64         `json:"id"`
+	ActorID       *int          `json:"actorId"`
+	ActorUsername string        `json:"actorUsername"`
+	Action        string        `json:"action" example:"PUT /admin/users/:id/status"`
+	TargetType    string        `json:"targetType,omitempty" example:"user"`
+	TargetID      *int          `json:"targetId,omitempty"`
+	Changes       []AuditChange `json:"changes,omitempty"`
+	Status        int           `json:"status"`
+	IP            string        `json:"ip"`
+	RequestID     string        `json:"requestId"`
+	CreatedAt     time.Time     `json:"createdAt"`
+}
+
+// AuditFilter — отбор записей журнала действий; нулевые поля не ограничивают выборку.
+// Before — ID записи, старше которой вернуть следующую страницу.
+type AuditFilter struct {
+	ActorID    int
+	Action     string
+	TargetType string
+	TargetID   int
+	From       time.Time
+	To         time.Time
+	Before     int64
+	Limit      int
+}
+
+// Виды данных, для которых задаются политики хранения
+const (
+	RetentionAuditLog         = "audit_log"
+	RetentionLoginHistory     = "login_history"
+	RetentionInactiveAccounts = "inactive_accounts"
+)
+
+// Действия политики хранения с устаревшими данными
+const (
+	RetentionPurge     = "purge"
+	RetentionAnonymize = "anonymize"
+)
+
+// RetentionPolicy — сколько дней хранятся данные одного вида и что с ними происходит после
+type RetentionPolicy struct {
+	Data   string `json:"data" example:"audit_log"`
+	Days   int    `json:"days" example:"365"`
+	Action string `json:"action" example:"purge"`
+}
+
+// RetentionReport — итог применения политики хранения: сколько записей старше Cutoff
+// удалено или обезличено (при пробном запуске — было бы)
+type RetentionReport struct {
+	RetentionPolicy
+	Cutoff   time.Time `json:"cutoff"`
+	Affected int64     `json:"affected"`
+	DryRun   bool      `json:"dryRun"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// RetentionStatus — действующие политики хранения и итоги последнего запуска
+type RetentionStatus struct {
+	// DryRun — задача только составляет отчет и ничего не меняет
+	DryRun   bool              `json:"dryRun"`
+	Policies []RetentionPolicy `json:"policies"`
+	LastRun  *time.Time        `json:"lastRun,omitempty"`
+	Reports  []RetentionReport `json:"reports"`
+}
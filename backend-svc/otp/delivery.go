@@ -0,0 +1,127 @@
+// Package otp доставляет одноразовые коды пользователям по выбранному каналу.
+package otp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"lmsmodule/backend-svc/i18n"
+	"lmsmodule/backend-svc/mail"
+	"lmsmodule/backend-svc/models"
+)
+
+// ErrNoRecipient — у пользователя не указан адрес для выбранного канала
+var ErrNoRecipient = errors.New("otp: recipient address is empty")
+
+// Recipient — адреса пользователя для доставки кода
+type Recipient struct {
+	UserID int
+	Email  string
+	Phone  string
+	Locale string // язык сообщения с кодом
+}
+
+// DeliveryProvider отправляет одноразовый код по одному каналу
+type DeliveryProvider interface {
+	Channel() string
+	// Deliver отправляет код; ttl сообщается получателю как срок действия
+	Deliver(ctx context.Context, to Recipient, code string, ttl time.Duration) error
+}
+
+// EmailProvider отправляет код письмом через SMTP-настройки пакета mail
+type EmailProvider struct{}
+
+// Channel возвращает models.OTPChannelEmail
+func (EmailProvider) Channel() string { return models.OTPChannelEmail }
+
+// Deliver отправляет письмо с кодом
+func (EmailProvider) Deliver(_ context.Context, to Recipient, code string, ttl time.Duration) error {
+	if to.Email == "" {
+		return ErrNoRecipient
+	}
+	return mail.SendOTPEmail(to.Email, code, ttl, to.Locale)
+}
+
+// SMSProvider отправляет код через API, совместимый с Twilio Messages
+// (POST {BaseURL}/2010-04-01/Accounts/{AccountSID}/Messages.json)
+type SMSProvider struct {
+	BaseURL    string
+	AccountSID string
+	AuthToken  string
+	From       string
+	Client     *http.Client
+}
+
+// NewSMSProvider создает SMS-провайдера; пустой baseURL означает api.twilio.com
+func NewSMSProvider(baseURL, accountSID, authToken, from string) *SMSProvider {
+	if baseURL == "" {
+		baseURL = "https://api.twilio.com"
+	}
+	return &SMSProvider{
+		BaseURL:    strings.TrimRight(baseURL, "/"),
+		AccountSID: accountSID,
+		AuthToken:  authToken,
+		From:       from,
+		Client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Channel возвращает models.OTPChannelSMS
+func (p *SMSProvider) Channel() string { return models.OTPChannelSMS }
+
+// Deliver отправляет SMS с кодом
+func (p *SMSProvider) Deliver(ctx context.Context, to Recipient, code string, ttl time.Duration) error {
+	if to.Phone == "" {
+		return ErrNoRecipient
+	}
+
+	form := url.Values{
+		"To":   {to.Phone},
+		"From": {p.From},
+		"Body": {i18n.Sprintf(to.Locale, "Your LMS verification code is: %s. Valid for %s.", code, mail.FormatValidity(ttl, to.Locale))},
+	}
+	endpoint := fmt.Sprintf("%s/2010-04-01/Accounts/%s/Messages.json", p.BaseURL, url.PathEscape(p.AccountSID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(p.AccountSID, p.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := p.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("otp: send sms: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		// Twilio возвращает {"code": ..., "message": ...}; показываем сообщение, если оно есть
+		var apiErr struct {
+			Message string `json:"message"`
+		}
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 4096))
+		if json.Unmarshal(body, &apiErr) == nil && apiErr.Message != "" {
+			return fmt.Errorf("otp: sms provider returned %d: %s", resp.StatusCode, apiErr.Message)
+		}
+		return fmt.Errorf("otp: sms provider returned %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Providers выбирает провайдера по каналу
+type Providers map[string]DeliveryProvider
+
+// For возвращает провайдера для канала; если канал не настроен, используется email
+func (p Providers) For(channel string) DeliveryProvider {
+	if provider, ok := p[channel]; ok {
+		return provider
+	}
+	return p[models.OTPChannelEmail]
+}
@@ -0,0 +1,42 @@
+package otp
+
+import (
+	"crypto/rand"
+	"fmt"
+	"math/big"
+)
+
+// Наборы символов одноразовых кодов
+const (
+	CharsetNumeric = "numeric"
+	// CharsetAlphanumeric — цифры и заглавные латинские буквы без похожих символов (0/O, 1/I/L)
+	CharsetAlphanumeric = "alphanumeric"
+)
+
+var charsetSymbols = map[string]string{
+	CharsetNumeric:      "0123456789",
+	CharsetAlphanumeric: "23456789ABCDEFGHJKMNPQRSTUVWXYZ",
+}
+
+// Generate возвращает случайный код заданной длины из набора charset, используя crypto/rand.
+// Символы выбираются равновероятно, без смещения по модулю.
+func Generate(length int, charset string) (string, error) {
+	symbols, ok := charsetSymbols[charset]
+	if !ok {
+		return "", fmt.Errorf("otp: unknown charset %q", charset)
+	}
+	if length <= 0 {
+		return "", fmt.Errorf("otp: length must be positive, got %d", length)
+	}
+
+	max := big.NewInt(int64(len(symbols)))
+	code := make([]byte, length)
+	for i := range code {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", fmt.Errorf("otp: generate code: %w", err)
+		}
+		code[i] = symbols[n.Int64()]
+	}
+	return string(code), nil
+}
@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"context"
+	"expvar"
+	"lmsmodule/backend-svc/models"
+	"sync"
+)
+
+// subscriberBuffer — сколько событий может ждать медленный подписчик, прежде чем новые будут отброшены
+const subscriberBuffer = 16
+
+var (
+	brokerSubscribers = expvar.NewInt("events_subscribers")
+	brokerDropped     = expvar.NewInt("events_dropped_total")
+)
+
+// Broker — внутрипроцессная шина событий: раздает опубликованные диспетчером события
+// подписчикам пользователя, которого они касаются (AggregateID). Используется для
+// потоковой доставки событий клиентам (SSE) вместо периодического опроса API.
+type Broker struct {
+	mu     sync.Mutex
+	subs   map[int]map[chan models.OutboxEvent]struct{}
+	closed bool
+}
+
+// NewBroker создает пустую шину событий
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[int]map[chan models.OutboxEvent]struct{})}
+}
+
+// Subscribe подписывает на события пользователя userID. Канал закрывается вызовом
+// возвращаемой функции отписки или при остановке шины.
+func (b *Broker) Subscribe(userID int) (<-chan models.OutboxEvent, func()) {
+	ch := make(chan models.OutboxEvent, subscriberBuffer)
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		close(ch)
+		return ch, func() {}
+	}
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[chan models.OutboxEvent]struct{})
+	}
+	b.subs[userID][ch] = struct{}{}
+	brokerSubscribers.Add(1)
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() { b.unsubscribe(userID, ch) })
+	}
+}
+
+func (b *Broker) unsubscribe(userID int, ch chan models.OutboxEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if _, ok := b.subs[userID][ch]; !ok {
+		return
+	}
+	delete(b.subs[userID], ch)
+	if len(b.subs[userID]) == 0 {
+		delete(b.subs, userID)
+	}
+	close(ch)
+	brokerSubscribers.Add(-1)
+}
+
+// Publish раздает событие подписчикам. Публикация никогда не блокирует диспетчер:
+// если буфер подписчика заполнен, событие для него отбрасывается.
+func (b *Broker) Publish(ctx context.Context, event models.OutboxEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for ch := range b.subs[event.AggregateID] {
+		select {
+		case ch <- event:
+		default:
+			brokerDropped.Add(1)
+		}
+	}
+	return nil
+}
+
+// Close закрывает все подписки, чтобы открытые потоки завершились до остановки сервера
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+	b.closed = true
+	for userID, chans := range b.subs {
+		for ch := range chans {
+			close(ch)
+			brokerSubscribers.Add(-1)
+		}
+		delete(b.subs, userID)
+	}
+}
@@ -0,0 +1,47 @@
+package outbox
+
+import (
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/models"
+	"strings"
+)
+
+var (
+	busPublished = expvar.NewInt("events_bus_published_total")
+	busFailed    = expvar.NewInt("events_bus_failed_total")
+)
+
+// BusPublisher публикует события во внешнюю шину и держит соединение с ней до Close
+type BusPublisher interface {
+	Publisher
+	Close() error
+}
+
+// NewBusPublisher создает издателя для шины из конфигурации: Kafka или NATS
+func NewBusPublisher(cfg config.EventBusConfig) (BusPublisher, error) {
+	switch cfg.Driver {
+	case "kafka":
+		return NewKafkaPublisher(cfg)
+	case "nats":
+		return NewNATSPublisher(cfg)
+	default:
+		return nil, fmt.Errorf("unknown event bus driver %q", cfg.Driver)
+	}
+}
+
+// busTopic подставляет тип события в шаблон топика: lms.events.{type} → lms.events.task.completed
+func busTopic(template string, event models.OutboxEvent) string {
+	return strings.ReplaceAll(template, "{type}", event.EventType)
+}
+
+// busMessage — тело сообщения в шине; совпадает с телом webhook'а
+func busMessage(event models.OutboxEvent) ([]byte, error) {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return nil, fmt.Errorf("marshal event: %w", err)
+	}
+	return body, nil
+}
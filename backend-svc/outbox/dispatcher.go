@@ -0,0 +1,124 @@
+package outbox
+
+import (
+	"context"
+	"lmsmodule/backend-svc/storage"
+	"log"
+	"time"
+)
+
+const (
+	defaultInterval  = 5 * time.Second
+	defaultBatchSize = 100
+	maxRetryDelay    = 10 * time.Minute
+	// dispatcherLease — имя аренды, с которой outbox разбирает одна реплика
+	dispatcherLease = "outbox-dispatcher"
+)
+
+// Leases выдает аренду одной реплике на время ttl
+type Leases interface {
+	AcquireJobLease(name, holder string, ttl time.Duration) (bool, error)
+}
+
+// Dispatcher периодически читает неопубликованные события из outbox и передает их издателям.
+// Событие отмечается доставленным только после успешной публикации всеми издателями,
+// поэтому гарантируется доставка «как минимум один раз»: получатели должны быть идемпотентны
+// по X-Event-ID.
+type Dispatcher struct {
+	Store      storage.Storage
+	Publishers []Publisher
+	Interval   time.Duration
+	BatchSize  int
+
+	leases Leases
+	holder string
+}
+
+// NewDispatcher создает диспетчер с интервалом опроса и размером пачки по умолчанию
+func NewDispatcher(store storage.Storage, publishers ...Publisher) *Dispatcher {
+	return &Dispatcher{
+		Store:      store,
+		Publishers: publishers,
+		Interval:   defaultInterval,
+		BatchSize:  defaultBatchSize,
+	}
+}
+
+// Coordinate включает аренду: outbox разбирает только реплика, держащая аренду, иначе каждая
+// реплика публиковала бы одни и те же события. Остальные реплики подхватывают разбор, если
+// держатель не продлевал аренду три интервала. Вызывать до Run.
+func (d *Dispatcher) Coordinate(leases Leases, holder string) {
+	d.leases = leases
+	d.holder = holder
+}
+
+// Run опрашивает outbox до отмены контекста
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchBatch(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatchBatch(ctx context.Context) {
+	if d.leases != nil {
+		held, err := d.leases.AcquireJobLease(dispatcherLease, d.holder, 3*d.Interval)
+		if err != nil {
+			log.Printf("Outbox: failed to acquire dispatcher lease: %v", err)
+			return
+		}
+		if !held {
+			return
+		}
+	}
+
+	events, err := d.Store.FetchPendingEvents(d.BatchSize)
+	if err != nil {
+		log.Printf("Outbox: failed to fetch pending events: %v", err)
+		return
+	}
+
+	for _, event := range events {
+		if ctx.Err() != nil {
+			return
+		}
+
+		var publishErr error
+		for _, publisher := range d.Publishers {
+			if publishErr = publisher.Publish(ctx, event); publishErr != nil {
+				break
+			}
+		}
+
+		if publishErr != nil {
+			retryAt := time.Now().Add(retryDelay(event.Attempts + 1))
+			log.Printf("Outbox: failed to publish event %d (%s), attempt %d: %v",
+				event.ID, event.EventType, event.Attempts+1, publishErr)
+			if err := d.Store.MarkEventFailed(event.ID, publishErr.Error(), retryAt); err != nil {
+				log.Printf("Outbox: failed to record failure for event %d: %v", event.ID, err)
+			}
+			continue
+		}
+
+		if err := d.Store.MarkEventDispatched(event.ID); err != nil {
+			log.Printf("Outbox: failed to mark event %d as dispatched: %v", event.ID, err)
+		}
+	}
+}
+
+// retryDelay возвращает квадратичную задержку перед следующей попыткой с верхней границей
+func retryDelay(attempt int) time.Duration {
+	delay := time.Duration(attempt*attempt) * defaultInterval
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,95 @@
+package outbox
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/models"
+	"strconv"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+// KafkaPublisher отправляет события в топики Kafka. Ключ сообщения — AggregateID, поэтому
+// события одного пользователя попадают в одну партицию и читаются по порядку.
+type KafkaPublisher struct {
+	writer *kafka.Writer
+	topic  string
+}
+
+// NewKafkaPublisher создает издателя; соединение с брокерами устанавливается при первой отправке
+func NewKafkaPublisher(cfg config.EventBusConfig) (*KafkaPublisher, error) {
+	transport := &kafka.Transport{DialTimeout: cfg.Timeout}
+	if cfg.TLS {
+		transport.TLS = &tls.Config{MinVersion: tls.VersionTLS12}
+	}
+	if cfg.Username != "" {
+		mechanism, err := kafkaSASL(cfg)
+		if err != nil {
+			return nil, err
+		}
+		transport.SASL = mechanism
+	}
+
+	return &KafkaPublisher{
+		writer: &kafka.Writer{
+			Addr:     kafka.TCP(cfg.Brokers...),
+			Balancer: &kafka.Hash{},
+			// Подтверждение от всех реплик: событие помечается доставленным только после него
+			RequiredAcks: kafka.RequireAll,
+			// Диспетчер отправляет события по одному и ждет ответа, поэтому пачка не копится
+			BatchSize:    1,
+			WriteTimeout: cfg.Timeout,
+			ReadTimeout:  cfg.Timeout,
+			Transport:    transport,
+		},
+		topic: cfg.Topic,
+	}, nil
+}
+
+func kafkaSASL(cfg config.EventBusConfig) (sasl.Mechanism, error) {
+	switch cfg.SASLMechanism {
+	case "", "plain":
+		return plain.Mechanism{Username: cfg.Username, Password: cfg.Password}, nil
+	case "scram-sha-256":
+		return scram.Mechanism(scram.SHA256, cfg.Username, cfg.Password)
+	case "scram-sha-512":
+		return scram.Mechanism(scram.SHA512, cfg.Username, cfg.Password)
+	default:
+		return nil, fmt.Errorf("unsupported SASL mechanism %q", cfg.SASLMechanism)
+	}
+}
+
+// Publish отправляет событие и ждет подтверждения брокера
+func (p *KafkaPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	body, err := busMessage(event)
+	if err != nil {
+		return err
+	}
+	topic := busTopic(p.topic, event)
+	err = p.writer.WriteMessages(ctx, kafka.Message{
+		Topic: topic,
+		Key:   []byte(strconv.Itoa(event.AggregateID)),
+		Value: body,
+		Headers: []kafka.Header{
+			{Key: "X-Event-Type", Value: []byte(event.EventType)},
+			{Key: "X-Event-ID", Value: []byte(strconv.FormatInt(event.ID, 10))},
+		},
+		Time: event.CreatedAt,
+	})
+	if err != nil {
+		busFailed.Add(1)
+		return fmt.Errorf("publish to kafka topic %s: %w", topic, err)
+	}
+	busPublished.Add(1)
+	return nil
+}
+
+// Close закрывает соединения с брокерами
+func (p *KafkaPublisher) Close() error {
+	return p.writer.Close()
+}
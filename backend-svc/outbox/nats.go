@@ -0,0 +1,87 @@
+package outbox
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/models"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// NATSPublisher отправляет события в subject'ы NATS. С JetStream публикация ждет подтверждения
+// сохранения в поток, а Nats-Msg-Id отсекает повторы одного события.
+type NATSPublisher struct {
+	conn    *nats.Conn
+	js      nats.JetStreamContext
+	subject string
+	timeout time.Duration
+}
+
+// NewNATSPublisher подключается к серверам NATS. Недоступный при старте сервер не мешает
+// запуску: клиент переподключается в фоне, а события ждут в outbox.
+func NewNATSPublisher(cfg config.EventBusConfig) (*NATSPublisher, error) {
+	opts := []nats.Option{
+		nats.Name("lms-backend"),
+		nats.Timeout(cfg.Timeout),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	}
+	if cfg.Username != "" {
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	} else if cfg.Password != "" {
+		opts = append(opts, nats.Token(cfg.Password))
+	}
+	if cfg.TLS {
+		opts = append(opts, nats.Secure(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+
+	conn, err := nats.Connect(strings.Join(cfg.Brokers, ","), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats: %w", err)
+	}
+	p := &NATSPublisher{conn: conn, subject: cfg.Topic, timeout: cfg.Timeout}
+	if cfg.JetStream {
+		if p.js, err = conn.JetStream(); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("open jetstream context: %w", err)
+		}
+	}
+	return p, nil
+}
+
+// Publish отправляет событие. Без JetStream доставка подтверждается сбросом буфера на сервер.
+func (p *NATSPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	body, err := busMessage(event)
+	if err != nil {
+		return err
+	}
+	msg := nats.NewMsg(busTopic(p.subject, event))
+	msg.Data = body
+	msg.Header.Set("X-Event-Type", event.EventType)
+	msg.Header.Set("X-Event-ID", strconv.FormatInt(event.ID, 10))
+	msg.Header.Set(nats.MsgIdHdr, "lms-"+strconv.FormatInt(event.ID, 10))
+
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+	if p.js != nil {
+		_, err = p.js.PublishMsg(msg, nats.Context(ctx))
+	} else if err = p.conn.PublishMsg(msg); err == nil {
+		err = p.conn.FlushWithContext(ctx)
+	}
+	if err != nil {
+		busFailed.Add(1)
+		return fmt.Errorf("publish to nats subject %s: %w", msg.Subject, err)
+	}
+	busPublished.Add(1)
+	return nil
+}
+
+// Close отправляет буферизованные сообщения и закрывает соединение
+func (p *NATSPublisher) Close() error {
+	return p.conn.Drain()
+}
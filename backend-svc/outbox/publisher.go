@@ -0,0 +1,60 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"net/http"
+	"time"
+)
+
+// Publisher доставляет доменное событие во внешнюю систему (webhook, очередь и т.п.)
+type Publisher interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// WebhookPublisher отправляет события POST-запросом на каждый из настроенных URL
+type WebhookPublisher struct {
+	URLs   []string
+	Client *http.Client
+}
+
+// NewWebhookPublisher создает WebhookPublisher с HTTP-клиентом по умолчанию
+func NewWebhookPublisher(urls []string) *WebhookPublisher {
+	return &WebhookPublisher{
+		URLs:   urls,
+		Client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Publish отправляет событие на все webhook'и; ошибка любого из них приводит к повторной доставке
+func (p *WebhookPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshal event: %w", err)
+	}
+
+	for _, url := range p.URLs {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return fmt.Errorf("build request for %s: %w", url, err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("X-Event-Type", event.EventType)
+		req.Header.Set("X-Event-ID", fmt.Sprint(event.ID))
+
+		resp, err := p.Client.Do(req)
+		if err != nil {
+			return fmt.Errorf("post to %s: %w", url, err)
+		}
+		resp.Body.Close()
+
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return fmt.Errorf("post to %s: unexpected status %d", url, resp.StatusCode)
+		}
+	}
+
+	return nil
+}
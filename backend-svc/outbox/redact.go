@@ -0,0 +1,25 @@
+package outbox
+
+import (
+	"context"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/redact"
+)
+
+// redactingPublisher маскирует персональные данные в событиях перед передачей во внешнюю систему
+type redactingPublisher struct {
+	next Publisher
+}
+
+// Redacted оборачивает издателя для внешних получателей (webhook'и, шина): имена, адреса почты
+// и токены в полезной нагрузке событий маскируются (см. redact.JSON). Подключенным клиентам
+// (SSE, WebSocket) события передаются без изменений.
+func Redacted(next Publisher) Publisher {
+	return redactingPublisher{next: next}
+}
+
+// Publish маскирует копию события и передает ее дальше
+func (p redactingPublisher) Publish(ctx context.Context, event models.OutboxEvent) error {
+	event.Payload = redact.JSON(event.Payload)
+	return p.next.Publish(ctx, event)
+}
@@ -0,0 +1,356 @@
+package quizimport
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"lmsmodule/backend-svc/models"
+)
+
+// ParseGIFT разбирает файл GIFT. Вопросы разделяются пустыми строками, строки "//" —
+// комментарии, "$CATEGORY: путь" задает категорию следующих вопросов.
+func ParseGIFT(data []byte) models.QuestionImportResult {
+	r := newResult(FormatGIFT)
+	text := strings.TrimPrefix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\ufeff")
+
+	var category string
+	var block []string
+	start := 0
+	flush := func() {
+		if len(block) == 0 {
+			return
+		}
+		item := strings.TrimSpace(strings.Join(block, "\n"))
+		block = nil
+		if rest, ok := strings.CutPrefix(item, "$CATEGORY:"); ok {
+			category = strings.TrimSpace(rest)
+			return
+		}
+		r.Total++
+		q, err := parseGIFTQuestion(item)
+		if err != nil {
+			r.fail(start, q.Name, "%s", err.Error())
+			return
+		}
+		q.Category = category
+		r.add(start, q)
+	}
+	for i, line := range strings.Split(text, "\n") {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "":
+			flush()
+		case strings.HasPrefix(trimmed, "//"):
+		default:
+			if len(block) == 0 {
+				start = i + 1
+			}
+			block = append(block, line)
+		}
+	}
+	flush()
+	return r.QuestionImportResult
+}
+
+// indexUnescaped ищет sep, не экранированный обратной косой чертой
+func indexUnescaped(s, sep string) int {
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if strings.HasPrefix(s[i:], sep) {
+			return i
+		}
+	}
+	return -1
+}
+
+// lastIndexUnescaped ищет последний неэкранированный символ c
+func lastIndexUnescaped(s string, c byte) int {
+	last := -1
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' {
+			i++
+			continue
+		}
+		if s[i] == c {
+			last = i
+		}
+	}
+	return last
+}
+
+// unescape убирает экранирование специальных символов GIFT; \n означает перевод строки
+func unescape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' && i+1 < len(s) {
+			i++
+			if s[i] == 'n' {
+				b.WriteByte('\n')
+			} else {
+				b.WriteByte(s[i])
+			}
+			continue
+		}
+		b.WriteByte(s[i])
+	}
+	return strings.TrimSpace(b.String())
+}
+
+func parseGIFTQuestion(item string) (models.QuizQuestion, error) {
+	var q models.QuizQuestion
+	if strings.HasPrefix(item, "::") {
+		end := indexUnescaped(item[2:], "::")
+		if end < 0 {
+			return q, errors.New("the question title is not closed with \"::\"")
+		}
+		q.Name = unescape(item[2 : 2+end])
+		item = strings.TrimSpace(item[4+end:])
+	}
+	if strings.HasPrefix(item, "[") {
+		if end := strings.IndexByte(item, ']'); end > 0 {
+			q.Format = item[1:end]
+			item = strings.TrimSpace(item[end+1:])
+		}
+	}
+
+	open := indexUnescaped(item, "{")
+	if open < 0 {
+		if indexUnescaped(item, "}") >= 0 {
+			return q, errors.New("unexpected \"}\" without \"{\"")
+		}
+		q.Type, q.Text = models.QuestionDescription, unescape(item)
+		return q, nil
+	}
+	closing := lastIndexUnescaped(item, '}')
+	if closing < open {
+		return q, errors.New("the answer block is not closed with \"}\"")
+	}
+	if indexUnescaped(item[open+1:closing], "{") >= 0 {
+		return q, errors.New("only one answer block is allowed per question")
+	}
+
+	before, after := strings.TrimSpace(item[:open]), strings.TrimSpace(item[closing+1:])
+	q.Text = unescape(before)
+	if after != "" {
+		// Формат «пропущенное слово»: ответы стоят посреди текста вопроса
+		q.Text = unescape(before + " _____ " + after)
+	}
+	if q.Name == "" {
+		q.Name = q.Text
+		if len([]rune(q.Name)) > 40 {
+			q.Name = string([]rune(q.Name)[:40])
+		}
+	}
+
+	body := strings.TrimSpace(item[open+1 : closing])
+	if i := indexUnescaped(body, "####"); i >= 0 {
+		q.GeneralFeedback = unescape(body[i+4:])
+		body = strings.TrimSpace(body[:i])
+	}
+
+	var err error
+	switch {
+	case body == "":
+		q.Type = models.QuestionEssay
+	case strings.HasPrefix(body, "#"):
+		q.Type = models.QuestionNumerical
+		q.Answers, err = parseGIFTNumerical(strings.TrimSpace(body[1:]))
+	case isTrueFalse(body):
+		q.Type = models.QuestionTrueFalse
+		q.Answers = parseGIFTTrueFalse(body)
+	default:
+		err = parseGIFTChoices(&q, body)
+	}
+	return q, err
+}
+
+func isTrueFalse(body string) bool {
+	word, _, _ := strings.Cut(body, "#")
+	switch strings.ToUpper(strings.TrimSpace(word)) {
+	case "T", "TRUE", "F", "FALSE":
+		return true
+	}
+	return false
+}
+
+// parseGIFTTrueFalse разбирает {T}, {FALSE#отзыв на неверный#отзыв на верный}
+func parseGIFTTrueFalse(body string) []models.QuizAnswer {
+	parts := strings.Split(body, "#")
+	truth := strings.HasPrefix(strings.ToUpper(strings.TrimSpace(parts[0])), "T")
+	answers := []models.QuizAnswer{{Text: "true"}, {Text: "false"}}
+	correct, wrong := &answers[1], &answers[0]
+	if truth {
+		correct, wrong = &answers[0], &answers[1]
+	}
+	correct.Fraction = 1
+	// Первый отзыв показывается за неверный ответ, второй — за верный
+	if len(parts) > 1 {
+		wrong.Feedback = unescape(parts[1])
+	}
+	if len(parts) > 2 {
+		correct.Feedback = unescape(parts[2])
+	}
+	return answers
+}
+
+// splitGIFTAnswers делит тело на ответы по неэкранированным "=" и "~"
+func splitGIFTAnswers(body string) (markers []byte, answers []string, err error) {
+	start := -1
+	for i := 0; i < len(body); i++ {
+		switch body[i] {
+		case '\\':
+			i++
+		case '=', '~':
+			// "=" внутри текста ответа, например "2+2=4", не начинает новый ответ
+			if body[i] == '=' && start >= 0 && !startsAnswer(body, i) {
+				continue
+			}
+			if start >= 0 {
+				answers = append(answers, body[start:i])
+			} else if strings.TrimSpace(body[:i]) != "" {
+				return nil, nil, fmt.Errorf("unexpected text %q before the first answer", strings.TrimSpace(body[:i]))
+			}
+			markers = append(markers, body[i])
+			start = i + 1
+		}
+	}
+	if start < 0 {
+		return nil, nil, errors.New("answers must start with \"=\" or \"~\"")
+	}
+	return markers, append(answers, body[start:]), nil
+}
+
+// startsAnswer отличает маркер ответа "=" от "=" внутри текста ответа: маркеру
+// предшествует пробел или перевод строки
+func startsAnswer(body string, i int) bool {
+	return i == 0 || body[i-1] == ' ' || body[i-1] == '\n' || body[i-1] == '\t'
+}
+
+// splitWeight отделяет вес вида %50% от текста ответа
+func splitWeight(answer string) (text string, fraction float64, weighted bool, err error) {
+	answer = strings.TrimSpace(answer)
+	if !strings.HasPrefix(answer, "%") {
+		return answer, 0, false, nil
+	}
+	end := strings.IndexByte(answer[1:], '%')
+	if end < 0 {
+		return "", 0, false, fmt.Errorf("the weight of answer %q is not closed with \"%%\"", answer)
+	}
+	weight, err := strconv.ParseFloat(answer[1:1+end], 64)
+	if err != nil {
+		return "", 0, false, fmt.Errorf("invalid weight %q", answer[1:1+end])
+	}
+	return strings.TrimSpace(answer[end+2:]), weight / 100, true, nil
+}
+
+// splitFeedback отделяет отзыв "#..." от текста ответа
+func splitFeedback(answer string) (text, feedback string) {
+	if i := indexUnescaped(answer, "#"); i >= 0 {
+		return answer[:i], unescape(answer[i+1:])
+	}
+	return answer, ""
+}
+
+func parseGIFTChoices(q *models.QuizQuestion, body string) error {
+	markers, parts, err := splitGIFTAnswers(body)
+	if err != nil {
+		return err
+	}
+
+	matching, wrong := true, false
+	for i, part := range parts {
+		if markers[i] == '~' {
+			wrong = true
+		}
+		if markers[i] != '=' || indexUnescaped(part, "->") < 0 {
+			matching = false
+		}
+	}
+	if matching {
+		q.Type = models.QuestionMatching
+		for _, part := range parts {
+			part, _ = splitFeedback(part)
+			arrow := indexUnescaped(part, "->")
+			q.Pairs = append(q.Pairs, models.QuizPair{Question: unescape(part[:arrow]), Answer: unescape(part[arrow+2:])})
+		}
+		return nil
+	}
+
+	q.Type = models.QuestionShortAnswer
+	if wrong {
+		q.Type = models.QuestionMultichoice
+		q.SingleAnswer = true
+	}
+	for i, part := range parts {
+		part, feedback := splitFeedback(part)
+		text, fraction, weighted, err := splitWeight(part)
+		if err != nil {
+			return err
+		}
+		if !weighted && markers[i] == '=' {
+			fraction = 1
+		}
+		// Веса у вариантов "~" означают вопрос с несколькими верными ответами
+		if weighted && markers[i] == '~' && fraction > 0 {
+			q.SingleAnswer = false
+		}
+		q.Answers = append(q.Answers, models.QuizAnswer{Text: unescape(text), Fraction: fraction, Feedback: feedback})
+	}
+	return nil
+}
+
+// parseGIFTNumerical разбирает {#3.14:0.01}, {#3.1..3.2} и {#=3.14:0.01 =%50%3:0.5}
+func parseGIFTNumerical(body string) ([]models.QuizAnswer, error) {
+	var parts []string
+	if strings.HasPrefix(body, "=") {
+		_, split, err := splitGIFTAnswers(body)
+		if err != nil {
+			return nil, err
+		}
+		parts = split
+	} else {
+		parts = []string{body}
+	}
+
+	answers := make([]models.QuizAnswer, 0, len(parts))
+	for _, part := range parts {
+		part, feedback := splitFeedback(part)
+		text, fraction, weighted, err := splitWeight(part)
+		if err != nil {
+			return nil, err
+		}
+		if !weighted {
+			fraction = 1
+		}
+		answer := models.QuizAnswer{Fraction: fraction, Feedback: feedback}
+		if low, high, ok := strings.Cut(text, ".."); ok {
+			lo, err1 := strconv.ParseFloat(strings.TrimSpace(low), 64)
+			hi, err2 := strconv.ParseFloat(strings.TrimSpace(high), 64)
+			if err1 != nil || err2 != nil || hi < lo {
+				return nil, fmt.Errorf("invalid numeric range %q", text)
+			}
+			tolerance := (hi - lo) / 2
+			answer.Text, answer.Tolerance = strconv.FormatFloat((lo+hi)/2, 'g', -1, 64), &tolerance
+		} else if value, tol, ok := strings.Cut(text, ":"); ok {
+			tolerance, err := strconv.ParseFloat(strings.TrimSpace(tol), 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid tolerance %q", tol)
+			}
+			answer.Text, answer.Tolerance = strings.TrimSpace(value), &tolerance
+		} else {
+			answer.Text = strings.TrimSpace(text)
+		}
+		answers = append(answers, answer)
+	}
+	return answers, nil
+}
+
+func isNumber(s string) bool {
+	_, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	return err == nil
+}
@@ -0,0 +1,304 @@
+package quizimport
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"lmsmodule/backend-svc/models"
+)
+
+// parseOne разбирает файл из одного вопроса и возвращает вопрос или текст ошибки
+func parseOne(t *testing.T, text string) (models.QuizQuestion, string) {
+	t.Helper()
+	r := ParseGIFT([]byte(text))
+	if r.Total != 1 {
+		t.Fatalf("total = %d, want 1", r.Total)
+	}
+	if len(r.Errors) > 0 {
+		return models.QuizQuestion{}, r.Errors[0].Error
+	}
+	return r.Questions[0], ""
+}
+
+func TestParseGIFTValid(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		check  func(t *testing.T, q models.QuizQuestion)
+		qtype  string
+		answer []models.QuizAnswer
+	}{
+		{"multiple choice", "::Sum:: What is 2+2? {=4 ~3 ~5}", func(t *testing.T, q models.QuizQuestion) {
+			if q.Name != "Sum" || q.Text != "What is 2+2?" || !q.SingleAnswer {
+				t.Errorf("question = %+v", q)
+			}
+		}, models.QuestionMultichoice, []models.QuizAnswer{{Text: "4", Fraction: 1}, {Text: "3"}, {Text: "5"}}},
+		{"true", "Grass is green {T}", nil, models.QuestionTrueFalse,
+			[]models.QuizAnswer{{Text: "true", Fraction: 1}, {Text: "false"}}},
+		{"false with feedback", "The sun is cold {FALSE#It is hot#Right}", nil, models.QuestionTrueFalse,
+			[]models.QuizAnswer{{Text: "true", Feedback: "It is hot"}, {Text: "false", Fraction: 1, Feedback: "Right"}}},
+		{"short answer", "Who wrote it? {=Pushkin =A. S. Pushkin}", nil, models.QuestionShortAnswer,
+			[]models.QuizAnswer{{Text: "Pushkin", Fraction: 1}, {Text: "A. S. Pushkin", Fraction: 1}}},
+		{"equals sign inside an answer", "Pick the identity {=2+2=4 ~2+2=5}", nil, models.QuestionMultichoice,
+			[]models.QuizAnswer{{Text: "2+2=4", Fraction: 1}, {Text: "2+2=5"}}},
+		{"answer feedback", "Port of HTTPS? {=443#Correct ~80#That is HTTP}", nil, models.QuestionMultichoice,
+			[]models.QuizAnswer{{Text: "443", Fraction: 1, Feedback: "Correct"}, {Text: "80", Feedback: "That is HTTP"}}},
+		{"weighted multiple answers", "Pick primes {~%50%2 ~%50%3 ~%-100%4}", func(t *testing.T, q models.QuizQuestion) {
+			if q.SingleAnswer {
+				t.Error("singleAnswer = true, want false")
+			}
+		}, models.QuestionMultichoice, []models.QuizAnswer{{Text: "2", Fraction: 0.5}, {Text: "3", Fraction: 0.5}, {Text: "4", Fraction: -1}}},
+		{"missing word", "Moscow is the {=capital ~village} of Russia", func(t *testing.T, q models.QuizQuestion) {
+			if q.Text != "Moscow is the _____ of Russia" {
+				t.Errorf("text = %q", q.Text)
+			}
+		}, models.QuestionMultichoice, []models.QuizAnswer{{Text: "capital", Fraction: 1}, {Text: "village"}}},
+		{"general feedback", "Q {=a ~b ####Read chapter 2}", func(t *testing.T, q models.QuizQuestion) {
+			if q.GeneralFeedback != "Read chapter 2" {
+				t.Errorf("generalFeedback = %q", q.GeneralFeedback)
+			}
+		}, models.QuestionMultichoice, []models.QuizAnswer{{Text: "a", Fraction: 1}, {Text: "b"}}},
+		{"matching", "Match {=cat -> meow =dog -> woof =cow -> moo}", func(t *testing.T, q models.QuizQuestion) {
+			want := []models.QuizPair{{Question: "cat", Answer: "meow"}, {Question: "dog", Answer: "woof"}, {Question: "cow", Answer: "moo"}}
+			if fmt.Sprint(q.Pairs) != fmt.Sprint(want) {
+				t.Errorf("pairs = %v, want %v", q.Pairs, want)
+			}
+		}, models.QuestionMatching, nil},
+		{"essay", "Describe SQL injection {}", nil, models.QuestionEssay, nil},
+		{"description", "Read the following section carefully.", nil, models.QuestionDescription, nil},
+		{"text format", "[html]<b>Bold</b> question {T}", func(t *testing.T, q models.QuizQuestion) {
+			if q.Format != "html" || q.Text != "<b>Bold</b> question" {
+				t.Errorf("format = %q, text = %q", q.Format, q.Text)
+			}
+		}, models.QuestionTrueFalse, []models.QuizAnswer{{Text: "true", Fraction: 1}, {Text: "false"}}},
+		{"multiline question", "First line\nsecond line {\n=a\n~b\n}", func(t *testing.T, q models.QuizQuestion) {
+			if q.Text != "First line\nsecond line" {
+				t.Errorf("text = %q", q.Text)
+			}
+		}, models.QuestionMultichoice, []models.QuizAnswer{{Text: "a", Fraction: 1}, {Text: "b"}}},
+		{"name defaults to the first 40 characters", strings.Repeat("я", 50) + " {T}", func(t *testing.T, q models.QuizQuestion) {
+			if q.Name != strings.Repeat("я", 40) {
+				t.Errorf("name = %q", q.Name)
+			}
+		}, models.QuestionTrueFalse, []models.QuizAnswer{{Text: "true", Fraction: 1}, {Text: "false"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, errText := parseOne(t, tt.input)
+			if errText != "" {
+				t.Fatalf("error: %s", errText)
+			}
+			if q.Type != tt.qtype {
+				t.Errorf("type = %q, want %q", q.Type, tt.qtype)
+			}
+			if tt.answer != nil && fmt.Sprint(q.Answers) != fmt.Sprint(tt.answer) {
+				t.Errorf("answers = %+v, want %+v", q.Answers, tt.answer)
+			}
+			if tt.check != nil {
+				tt.check(t, q)
+			}
+		})
+	}
+}
+
+func TestParseGIFTNumerical(t *testing.T) {
+	tests := []struct {
+		name      string
+		input     string
+		text      []string
+		fraction  []float64
+		tolerance []float64
+	}{
+		{"exact", "Pi? {#3.14}", []string{"3.14"}, []float64{1}, []float64{-1}},
+		{"tolerance", "Pi? {#3.14:0.01}", []string{"3.14"}, []float64{1}, []float64{0.01}},
+		{"range", "Between? {#1..3}", []string{"2"}, []float64{1}, []float64{1}},
+		{"several answers", "Pi? {#=3.14:0.01 =%50%3:0.5}", []string{"3.14", "3"}, []float64{1, 0.5}, []float64{0.01, 0.5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, errText := parseOne(t, tt.input)
+			if errText != "" {
+				t.Fatalf("error: %s", errText)
+			}
+			if q.Type != models.QuestionNumerical || len(q.Answers) != len(tt.text) {
+				t.Fatalf("question = %+v", q)
+			}
+			for i, a := range q.Answers {
+				tolerance := -1.0
+				if a.Tolerance != nil {
+					tolerance = *a.Tolerance
+				}
+				if a.Text != tt.text[i] || a.Fraction != tt.fraction[i] || tolerance != tt.tolerance[i] {
+					t.Errorf("answer %d = %q %v ±%v, want %q %v ±%v", i, a.Text, a.Fraction, tolerance, tt.text[i], tt.fraction[i], tt.tolerance[i])
+				}
+			}
+		})
+	}
+}
+
+func TestParseGIFTEscapes(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		qname   string
+		text    string
+		answers []string
+	}{
+		{"escaped equals in text", `Is 1 \= 1? {T}`, "", "Is 1 = 1?", []string{"true", "false"}},
+		{"escaped braces in text", `Braces \{ and \} are special {=yes ~no}`, "", "Braces { and } are special", []string{"yes", "no"}},
+		{"escaped markers in answers", `Which? {=a\~b ~c\=d ~e\#f}`, "", "Which?", []string{"a~b", "c=d", "e#f"}},
+		{"escaped colons in title", `::a\:\:b:: Question {T}`, "a::b", "Question", []string{"true", "false"}},
+		{"escaped newline", `Line one\nline two {T}`, "", "Line one\nline two", []string{"true", "false"}},
+		{"escaped backslash", `Path C:\\temp {=ok ~no}`, "", `Path C:\temp`, []string{"ok", "no"}},
+		{"escaped closing brace in answer", `Which? {=\} ~\{}`, "", "Which?", []string{"}", "{"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, errText := parseOne(t, tt.input)
+			if errText != "" {
+				t.Fatalf("error: %s", errText)
+			}
+			if tt.qname != "" && q.Name != tt.qname {
+				t.Errorf("name = %q, want %q", q.Name, tt.qname)
+			}
+			if q.Text != tt.text {
+				t.Errorf("text = %q, want %q", q.Text, tt.text)
+			}
+			var answers []string
+			for _, a := range q.Answers {
+				answers = append(answers, a.Text)
+			}
+			if strings.Join(answers, "|") != strings.Join(tt.answers, "|") {
+				t.Errorf("answers = %q, want %q", answers, tt.answers)
+			}
+		})
+	}
+}
+
+func TestParseGIFTErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"unclosed title", "::Title Question {T}", `not closed with "::"`},
+		{"unclosed answer block", "Question {=a ~b", `not closed with "}"`},
+		{"closing brace without opening", "Question } here", `unexpected "}"`},
+		{"two answer blocks", "Question {=a ~b} and {=c ~d}", "only one answer block"},
+		{"text before the first answer", "Question {maybe =a ~b}", "unexpected text"},
+		{"unclosed weight", "Question {=%50 a ~b}", `not closed with "%"`},
+		{"invalid weight", "Question {~%abc%a =b}", "invalid weight"},
+		{"weight out of range", "Question {=%150%a ~b}", "outside -100%..100%"},
+		{"no correct choice", "Question {~a ~b}", "must be worth 100%"},
+		{"weights do not add up", "Question {~%50%a ~%20%b ~c}", "add up to 70%"},
+		{"single choice", "Question {~a}", "at least two answers"},
+		{"empty question text", "::Title:: {T}", "question text is empty"},
+		{"one matching pair", "Match {=a -> 1}", "at least two pairs"},
+		{"matching pair without answer", "Match {=a -> 1 =b -> }", "has no answer"},
+		{"not a number", "Value? {#abc}", "is not a number"},
+		{"inverted range", "Value? {#3..1}", "invalid numeric range"},
+		{"invalid tolerance", "Value? {#1:x}", "invalid tolerance"},
+		{"negative tolerance", "Value? {#1:-1}", "negative tolerance"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, errText := parseOne(t, tt.input)
+			if !strings.Contains(errText, tt.want) {
+				t.Errorf("error = %q, want it to contain %q", errText, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGIFTEmptyAnswers(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty accepted answer", "Question {=}", "accepted answer is empty"},
+		{"blank accepted answer", "Question {=a = }", "accepted answer is empty"},
+		{"empty choice", "Question {=a ~}", "multiple choice answer is empty"},
+		{"empty correct choice", "Question {= ~b}", "multiple choice answer is empty"},
+		{"empty numeric answer", "Question {#}", "is not a number"},
+		{"only general feedback", "Question {####feedback}", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, errText := parseOne(t, tt.input)
+			if tt.want == "" {
+				if errText != "" || q.Type != models.QuestionEssay {
+					t.Errorf("question = %+v, error = %q, want an essay", q, errText)
+				}
+				return
+			}
+			if !strings.Contains(errText, tt.want) {
+				t.Errorf("error = %q, want it to contain %q", errText, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseGIFTFile(t *testing.T) {
+	input := "\ufeff// Банк вопросов\r\n" +
+		"$CATEGORY: web/sqli\r\n" +
+		"\r\n" +
+		"::One:: First {T}\r\n" +
+		"\r\n" +
+		"// комментарий между вопросами\r\n" +
+		"::Two:: Broken {=a ~b\r\n" +
+		"\r\n" +
+		"\r\n" +
+		"::Three::\r\n" +
+		"Third {=x ~y}\r\n"
+	r := ParseGIFT([]byte(input))
+	if r.Format != FormatGIFT || r.Total != 3 {
+		t.Fatalf("format = %q, total = %d", r.Format, r.Total)
+	}
+	if len(r.Questions) != 2 || r.Questions[0].Name != "One" || r.Questions[1].Name != "Three" {
+		t.Fatalf("questions = %+v", r.Questions)
+	}
+	for _, q := range r.Questions {
+		if q.Category != "web/sqli" {
+			t.Errorf("category of %q = %q", q.Name, q.Category)
+		}
+	}
+	if len(r.Errors) != 1 {
+		t.Fatalf("errors = %+v", r.Errors)
+	}
+	if e := r.Errors[0]; e.Item != 2 || e.Line != 7 || e.Name != "Two" {
+		t.Errorf("error = %+v, want item 2 at line 7", e)
+	}
+}
+
+func TestParseGIFTEmpty(t *testing.T) {
+	for _, input := range []string{"", "\n\n", "// only a comment", "$CATEGORY: empty"} {
+		r := ParseGIFT([]byte(input))
+		if r.Total != 0 || len(r.Questions) != 0 || len(r.Errors) != 0 {
+			t.Errorf("ParseGIFT(%q) = %+v", input, r)
+		}
+		if r.Questions == nil || r.Errors == nil {
+			t.Errorf("ParseGIFT(%q) returned nil slices", input)
+		}
+	}
+}
+
+func TestParseGIFTLargeInput(t *testing.T) {
+	const n = 5000
+	var b strings.Builder
+	for i := 0; i < n; i++ {
+		fmt.Fprintf(&b, "::Q%d:: Question %d {=right ~wrong}\n\n", i, i)
+	}
+	r := ParseGIFT([]byte(b.String()))
+	if r.Total != n || len(r.Questions) != n || len(r.Errors) != 0 {
+		t.Fatalf("total = %d, questions = %d, errors = %d", r.Total, len(r.Questions), len(r.Errors))
+	}
+
+	// Очень длинный вопрос разбирается целиком, а название обрезается
+	long := strings.Repeat("x", 1<<20)
+	q, errText := parseOne(t, long+" {T}")
+	if errText != "" || len(q.Text) != len(long) || len(q.Name) != 40 {
+		t.Errorf("text length = %d, name length = %d, error = %q", len(q.Text), len(q.Name), errText)
+	}
+}
@@ -0,0 +1,131 @@
+package quizimport
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	"lmsmodule/backend-svc/models"
+)
+
+type xmlText struct {
+	Format string `xml:"format,attr"`
+	Text   string `xml:"text"`
+}
+
+type xmlAnswer struct {
+	Fraction  string  `xml:"fraction,attr"`
+	Text      string  `xml:"text"`
+	Feedback  xmlText `xml:"feedback"`
+	Tolerance string  `xml:"tolerance"`
+}
+
+type xmlSubquestion struct {
+	Text   string  `xml:"text"`
+	Answer xmlText `xml:"answer"`
+}
+
+type xmlQuestion struct {
+	Type            string           `xml:"type,attr"`
+	Name            xmlText          `xml:"name"`
+	QuestionText    xmlText          `xml:"questiontext"`
+	GeneralFeedback xmlText          `xml:"generalfeedback"`
+	Category        xmlText          `xml:"category"`
+	Single          string           `xml:"single"`
+	Answers         []xmlAnswer      `xml:"answer"`
+	Subquestions    []xmlSubquestion `xml:"subquestion"`
+}
+
+// ParseMoodleXML разбирает экспорт банка вопросов Moodle (элемент quiz с элементами question).
+// Вопрос типа category задает категорию следующих вопросов.
+func ParseMoodleXML(data []byte) models.QuestionImportResult {
+	r := newResult(FormatXML)
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	// Экспорт Moodle всегда в UTF-8, но декларация может указывать другую кодировку
+	decoder.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+
+	var category string
+	for {
+		line, _ := decoder.InputPos()
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			r.Total++
+			r.fail(line, "", "malformed XML: %v", err)
+			break
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "question" {
+			continue
+		}
+
+		line, _ = decoder.InputPos()
+		var xq xmlQuestion
+		if err := decoder.DecodeElement(&xq, &start); err != nil {
+			r.Total++
+			r.fail(line, "", "malformed XML: %v", err)
+			break
+		}
+		if xq.Type == "category" {
+			category = strings.TrimSpace(xq.Category.Text)
+			continue
+		}
+
+		r.Total++
+		q, err := convertXMLQuestion(xq)
+		if err != nil {
+			r.fail(line, q.Name, "%s", err.Error())
+			continue
+		}
+		q.Category = category
+		r.add(line, q)
+	}
+	return r.QuestionImportResult
+}
+
+func convertXMLQuestion(xq xmlQuestion) (models.QuizQuestion, error) {
+	q := models.QuizQuestion{
+		Type:            xq.Type,
+		Name:            strings.TrimSpace(xq.Name.Text),
+		Text:            strings.TrimSpace(xq.QuestionText.Text),
+		Format:          xq.QuestionText.Format,
+		GeneralFeedback: strings.TrimSpace(xq.GeneralFeedback.Text),
+	}
+	if q.Type == models.QuestionMultichoice {
+		// В Moodle по умолчанию вопрос с одним верным ответом
+		q.SingleAnswer = strings.TrimSpace(xq.Single) != "false" && strings.TrimSpace(xq.Single) != "0"
+	}
+
+	for _, xa := range xq.Answers {
+		answer := models.QuizAnswer{Text: strings.TrimSpace(xa.Text), Feedback: strings.TrimSpace(xa.Feedback.Text)}
+		if xa.Fraction != "" {
+			fraction, err := strconv.ParseFloat(strings.TrimSpace(xa.Fraction), 64)
+			if err != nil {
+				return q, errors.New("invalid answer fraction " + strconv.Quote(xa.Fraction))
+			}
+			answer.Fraction = fraction / 100
+		}
+		if q.Type == models.QuestionNumerical && strings.TrimSpace(xa.Tolerance) != "" {
+			tolerance, err := strconv.ParseFloat(strings.TrimSpace(xa.Tolerance), 64)
+			if err != nil {
+				return q, errors.New("invalid tolerance " + strconv.Quote(xa.Tolerance))
+			}
+			answer.Tolerance = &tolerance
+		}
+		q.Answers = append(q.Answers, answer)
+	}
+
+	if q.Type == models.QuestionMatching {
+		q.Answers = nil
+		for _, sub := range xq.Subquestions {
+			// Подвопрос без текста — лишний вариант ответа, который ни с чем не сопоставляется
+			q.Pairs = append(q.Pairs, models.QuizPair{Question: strings.TrimSpace(sub.Text), Answer: strings.TrimSpace(sub.Answer.Text)})
+		}
+	}
+	return q, nil
+}
@@ -0,0 +1,154 @@
+// Package quizimport разбирает банки вопросов в форматах GIFT и Moodle XML. Каждый элемент
+// файла проверяется отдельно: некорректные вопросы попадают в отчет и не мешают разбору остальных.
+package quizimport
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+
+	"lmsmodule/backend-svc/models"
+)
+
+// Форматы файлов
+const (
+	FormatGIFT = "gift"
+	FormatXML  = "xml"
+)
+
+// ErrUnknownFormat — формат не указан и не распознан по содержимому
+var ErrUnknownFormat = errors.New("quizimport: unknown format")
+
+// Parse разбирает файл формата format; пустой format определяется по содержимому
+func Parse(format string, data []byte) (models.QuestionImportResult, error) {
+	if format == "" {
+		format = DetectFormat(data)
+	}
+	switch format {
+	case FormatGIFT:
+		return ParseGIFT(data), nil
+	case FormatXML:
+		return ParseMoodleXML(data), nil
+	}
+	return models.QuestionImportResult{}, ErrUnknownFormat
+}
+
+// DetectFormat считает файл Moodle XML, если он начинается с XML-декларации или элемента quiz
+func DetectFormat(data []byte) string {
+	trimmed := bytes.TrimSpace(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")))
+	if bytes.HasPrefix(trimmed, []byte("<?xml")) || bytes.HasPrefix(trimmed, []byte("<quiz")) {
+		return FormatXML
+	}
+	return FormatGIFT
+}
+
+// result накапливает вопросы и ошибки по мере разбора
+type result struct {
+	models.QuestionImportResult
+}
+
+func newResult(format string) *result {
+	return &result{models.QuestionImportResult{
+		Format:    format,
+		Questions: []models.QuizQuestion{},
+		Errors:    []models.QuestionImportError{},
+	}}
+}
+
+func (r *result) fail(line int, name, format string, args ...interface{}) {
+	r.Errors = append(r.Errors, models.QuestionImportError{Item: r.Total, Line: line, Name: name, Error: fmt.Sprintf(format, args...)})
+}
+
+// add проверяет вопрос и добавляет его в результат или в отчет
+func (r *result) add(line int, q models.QuizQuestion) {
+	if err := validate(q); err != nil {
+		r.fail(line, q.Name, "%s", err.Error())
+		return
+	}
+	r.Questions = append(r.Questions, q)
+}
+
+const fractionEpsilon = 0.001
+
+// validate проверяет вопрос по тем же правилам, что и редактор вопросов Moodle
+func validate(q models.QuizQuestion) error {
+	if strings.TrimSpace(q.Text) == "" {
+		return errors.New("question text is empty")
+	}
+	for _, a := range q.Answers {
+		if a.Fraction < -1-fractionEpsilon || a.Fraction > 1+fractionEpsilon {
+			return fmt.Errorf("answer %q has a grade outside -100%%..100%%", a.Text)
+		}
+	}
+
+	var best, positive float64
+	var correct int
+	for _, a := range q.Answers {
+		best = math.Max(best, a.Fraction)
+		if a.Fraction > 0 {
+			positive += a.Fraction
+			correct++
+		}
+	}
+	switch q.Type {
+	case models.QuestionMultichoice:
+		if len(q.Answers) < 2 {
+			return errors.New("a multiple choice question needs at least two answers")
+		}
+		for _, a := range q.Answers {
+			if strings.TrimSpace(a.Text) == "" {
+				return errors.New("a multiple choice answer is empty")
+			}
+		}
+		if q.SingleAnswer && math.Abs(best-1) > fractionEpsilon {
+			return errors.New("one of the answers must be worth 100%")
+		}
+		if !q.SingleAnswer && math.Abs(positive-1) > fractionEpsilon {
+			return fmt.Errorf("the positive grades of the answers add up to %.4g%% instead of 100%%", positive*100)
+		}
+	case models.QuestionTrueFalse:
+		if len(q.Answers) != 2 || correct != 1 {
+			return errors.New("a true/false question needs exactly one correct answer")
+		}
+	case models.QuestionShortAnswer:
+		if correct == 0 || math.Abs(best-1) > fractionEpsilon {
+			return errors.New("one of the accepted answers must be worth 100%")
+		}
+		for _, a := range q.Answers {
+			if strings.TrimSpace(a.Text) == "" {
+				return errors.New("an accepted answer is empty")
+			}
+		}
+	case models.QuestionNumerical:
+		if correct == 0 || math.Abs(best-1) > fractionEpsilon {
+			return errors.New("one of the numeric answers must be worth 100%")
+		}
+		for _, a := range q.Answers {
+			if a.Text != "*" && !isNumber(a.Text) {
+				return fmt.Errorf("answer %q is not a number", a.Text)
+			}
+			if a.Tolerance != nil && *a.Tolerance < 0 {
+				return fmt.Errorf("answer %q has a negative tolerance", a.Text)
+			}
+		}
+	case models.QuestionMatching:
+		var pairs int
+		for _, p := range q.Pairs {
+			if strings.TrimSpace(p.Answer) == "" {
+				return errors.New("a matching pair has no answer")
+			}
+			if strings.TrimSpace(p.Question) != "" {
+				pairs++
+			}
+		}
+		if pairs < 2 {
+			return errors.New("a matching question needs at least two pairs")
+		}
+	case models.QuestionEssay, models.QuestionDescription:
+	default:
+		return fmt.Errorf("unsupported question type %q", q.Type)
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+package realtime
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"expvar"
+	"fmt"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/models"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+var (
+	clusterRelayed  = expvar.NewInt("realtime_cluster_relayed_total")
+	clusterReceived = expvar.NewInt("realtime_cluster_received_total")
+	clusterFailed   = expvar.NewInt("realtime_cluster_failed_total")
+	clusterPeers    = expvar.NewInt("realtime_cluster_instances")
+)
+
+// EventSink получает доменные события для клиентов своей реплики (поток SSE)
+type EventSink interface {
+	Publish(ctx context.Context, event models.OutboxEvent) error
+}
+
+// envelope — сообщение между репликами: сообщение хаба или доменное событие
+type envelope struct {
+	Origin  string              `json:"origin"`
+	Message *relayedMessage     `json:"message,omitempty"`
+	Event   *models.OutboxEvent `json:"event,omitempty"`
+}
+
+// relayedMessage — Message с данными, уже сериализованными на реплике-отправителе
+type relayedMessage struct {
+	Topic  string          `json:"topic"`
+	Type   string          `json:"type"`
+	Data   json.RawMessage `json:"data,omitempty"`
+	SentAt time.Time       `json:"sentAt"`
+}
+
+// heartbeat — список пользователей, подключенных к реплике
+type heartbeat struct {
+	Origin string `json:"origin"`
+	Users  []int  `json:"users"`
+	// Leaving — реплика останавливается, ее пользователей можно сразу считать отключенными
+	Leaving bool `json:"leaving,omitempty"`
+}
+
+// peer — присутствие на другой реплике
+type peer struct {
+	users     map[int]bool
+	expiresAt time.Time
+}
+
+// Cluster связывает реплики через NATS: сообщения хаба и доменные события, опубликованные
+// на одной реплике, доставляются клиентам всех реплик, а списки подключенных пользователей
+// дают общее присутствие. Реализует outbox.Publisher вместо локальных хаба и шины событий.
+type Cluster struct {
+	id       string
+	hub      *Hub
+	sinks    []EventSink
+	conn     *nats.Conn
+	messages string
+	presence string
+	interval time.Duration
+
+	mu    sync.Mutex
+	peers map[string]peer
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewCluster подключается к NATS и подписывается на сообщения остальных реплик. id отличает
+// реплику от других; sinks получают доменные события вместе с хабом. Недоступный при старте
+// сервер не мешает запуску: клиент переподключается в фоне.
+func NewCluster(cfg config.RealtimeConfig, id string, hub *Hub, sinks ...EventSink) (*Cluster, error) {
+	opts := []nats.Option{
+		nats.Name("lms-backend-realtime"),
+		nats.RetryOnFailedConnect(true),
+		nats.MaxReconnects(-1),
+	}
+	if cfg.Username != "" {
+		opts = append(opts, nats.UserInfo(cfg.Username, cfg.Password))
+	} else if cfg.Password != "" {
+		opts = append(opts, nats.Token(cfg.Password))
+	}
+	if cfg.TLS {
+		opts = append(opts, nats.Secure(&tls.Config{MinVersion: tls.VersionTLS12}))
+	}
+	conn, err := nats.Connect(strings.Join(cfg.Servers, ","), opts...)
+	if err != nil {
+		return nil, fmt.Errorf("connect to nats: %w", err)
+	}
+
+	c := &Cluster{
+		id:       id,
+		hub:      hub,
+		sinks:    sinks,
+		conn:     conn,
+		messages: cfg.Subject + ".messages",
+		presence: cfg.Subject + ".presence",
+		interval: cfg.PresenceInterval,
+		peers:    make(map[string]peer),
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if _, err := conn.Subscribe(c.messages, c.receiveMessage); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("subscribe to %s: %w", c.messages, err)
+	}
+	if _, err := conn.Subscribe(c.presence, c.receiveHeartbeat); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("subscribe to %s: %w", c.presence, err)
+	}
+
+	hub.relay = c.relayMessage
+	go c.heartbeatLoop()
+	return c, nil
+}
+
+// send публикует конверт для остальных реплик; ошибка только логируется: клиенты
+// своей реплики сообщение уже получили
+func (c *Cluster) send(subject string, v interface{}) {
+	body, err := json.Marshal(v)
+	if err == nil {
+		err = c.conn.Publish(subject, body)
+	}
+	if err != nil {
+		clusterFailed.Add(1)
+		log.Printf("Realtime: failed to relay to %s: %v", subject, err)
+		return
+	}
+	clusterRelayed.Add(1)
+}
+
+// relayMessage пересылает сообщение хаба остальным репликам
+func (c *Cluster) relayMessage(msg Message) {
+	data, err := json.Marshal(msg.Data)
+	if err != nil {
+		clusterFailed.Add(1)
+		log.Printf("Realtime: failed to encode %s message: %v", msg.Type, err)
+		return
+	}
+	c.send(c.messages, envelope{Origin: c.id, Message: &relayedMessage{
+		Topic: msg.Topic, Type: msg.Type, Data: data, SentAt: msg.SentAt,
+	}})
+}
+
+// Publish реализует outbox.Publisher: событие доставляется клиентам этой реплики
+// и пересылается остальным
+func (c *Cluster) Publish(ctx context.Context, event models.OutboxEvent) error {
+	c.deliverEvent(ctx, event)
+	c.send(c.messages, envelope{Origin: c.id, Event: &event})
+	return nil
+}
+
+func (c *Cluster) deliverEvent(ctx context.Context, event models.OutboxEvent) {
+	c.hub.Publish(ctx, event)
+	for _, sink := range c.sinks {
+		sink.Publish(ctx, event)
+	}
+}
+
+func (c *Cluster) receiveMessage(m *nats.Msg) {
+	var env envelope
+	if err := json.Unmarshal(m.Data, &env); err != nil {
+		clusterFailed.Add(1)
+		return
+	}
+	if env.Origin == c.id {
+		return
+	}
+	clusterReceived.Add(1)
+	switch {
+	case env.Message != nil:
+		msg := env.Message
+		var data interface{}
+		if len(msg.Data) > 0 {
+			data = msg.Data
+		}
+		c.hub.deliver(Message{Topic: msg.Topic, Type: msg.Type, Data: data, SentAt: msg.SentAt})
+	case env.Event != nil:
+		c.deliverEvent(context.Background(), *env.Event)
+	}
+}
+
+func (c *Cluster) receiveHeartbeat(m *nats.Msg) {
+	var hb heartbeat
+	if err := json.Unmarshal(m.Data, &hb); err != nil || hb.Origin == c.id {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if hb.Leaving {
+		delete(c.peers, hb.Origin)
+	} else {
+		users := make(map[int]bool, len(hb.Users))
+		for _, userID := range hb.Users {
+			users[userID] = true
+		}
+		c.peers[hb.Origin] = peer{users: users, expiresAt: time.Now().Add(3 * c.interval)}
+	}
+	clusterPeers.Set(int64(len(c.peers)))
+}
+
+// heartbeatLoop рассылает список подключенных пользователей каждые interval и сразу
+// после того, как пользователь подключился или отключился
+func (c *Cluster) heartbeatLoop() {
+	defer close(c.done)
+	ticker := time.NewTicker(c.interval)
+	defer ticker.Stop()
+
+	c.send(c.presence, heartbeat{Origin: c.id, Users: c.hub.OnlineUsers()})
+	for {
+		select {
+		case <-c.stop:
+			c.send(c.presence, heartbeat{Origin: c.id, Users: []int{}, Leaving: true})
+			return
+		case <-ticker.C:
+		case <-c.hub.presence:
+		}
+		c.send(c.presence, heartbeat{Origin: c.id, Users: c.hub.OnlineUsers()})
+	}
+}
+
+// OnlineUsers возвращает пользователей, подключенных к любой из живых реплик
+func (c *Cluster) OnlineUsers() []int {
+	online := make(map[int]bool)
+	for _, userID := range c.hub.OnlineUsers() {
+		online[userID] = true
+	}
+
+	now := time.Now()
+	c.mu.Lock()
+	for id, p := range c.peers {
+		if now.After(p.expiresAt) {
+			delete(c.peers, id)
+			continue
+		}
+		for userID := range p.users {
+			online[userID] = true
+		}
+	}
+	clusterPeers.Set(int64(len(c.peers)))
+	c.mu.Unlock()
+
+	users := make([]int, 0, len(online))
+	for userID := range online {
+		users = append(users, userID)
+	}
+	sort.Ints(users)
+	return users
+}
+
+// Instances возвращает число живых реплик, включая эту
+func (c *Cluster) Instances() int {
+	c.OnlineUsers() // удаляет молчащие реплики
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.peers) + 1
+}
+
+// Close сообщает остальным репликам об остановке и закрывает соединение. Сообщения хаба,
+// отправленные после этого, доставляются только клиентам этой реплики.
+func (c *Cluster) Close() {
+	c.once.Do(func() {
+		close(c.stop)
+		<-c.done
+		if err := c.conn.Drain(); err != nil {
+			log.Printf("Realtime: failed to drain NATS connection: %v", err)
+		}
+	})
+}
@@ -0,0 +1,311 @@
+package realtime
+
+import (
+	"context"
+	"expvar"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// Темы, на которые может подписаться клиент
+const (
+	// TopicBroadcast — объявления администраторов и преподавателей для всех пользователей
+	TopicBroadcast = "broadcast"
+	// TopicLeaderboard — обновления таблицы лидеров во время соревнований
+	TopicLeaderboard = "leaderboard"
+	// userTopicPrefix — личная тема пользователя с его доменными событиями (user.<id>)
+	userTopicPrefix = "user."
+)
+
+const (
+	// sendBuffer — сколько сообщений может ждать медленный клиент, прежде чем соединение будет закрыто
+	sendBuffer   = 32
+	writeTimeout = 10 * time.Second
+	// maxCommandSize ограничивает размер команды клиента
+	maxCommandSize = 4096
+)
+
+var (
+	hubConnections = expvar.NewInt("realtime_connections")
+	hubMessages    = expvar.NewInt("realtime_messages_total")
+	hubSlowClients = expvar.NewInt("realtime_slow_clients_total")
+)
+
+// UserTopic возвращает личную тему пользователя
+func UserTopic(userID int) string {
+	return fmt.Sprintf("%s%d", userTopicPrefix, userID)
+}
+
+// Message — сообщение, отправляемое клиенту по WebSocket
+type Message struct {
+	Topic  string      `json:"topic"`
+	Type   string      `json:"type"`
+	Data   interface{} `json:"data,omitempty"`
+	SentAt time.Time   `json:"sentAt"`
+}
+
+// command — команда клиента: подписка или отписка от темы
+type command struct {
+	Action string `json:"action"`
+	Topic  string `json:"topic"`
+}
+
+// Hub хранит WebSocket-соединения и их подписки и рассылает сообщения по темам
+type Hub struct {
+	mu      sync.Mutex
+	topics  map[string]map[*client]struct{}
+	clients map[*client]struct{}
+	// users — число соединений каждого подключенного пользователя
+	users  map[int]int
+	closed bool
+
+	// relay пересылает сообщения другим репликам (Cluster); nil — реплика одна
+	relay func(Message)
+	// presence получает сигнал, когда пользователь подключился впервые или отключился полностью
+	presence chan struct{}
+}
+
+type client struct {
+	conn    *websocket.Conn
+	userID  int
+	isAdmin bool
+	send    chan Message
+	once    sync.Once
+}
+
+// NewHub создает пустой хаб
+func NewHub() *Hub {
+	return &Hub{
+		topics:   make(map[string]map[*client]struct{}),
+		clients:  make(map[*client]struct{}),
+		users:    make(map[int]int),
+		presence: make(chan struct{}, 1),
+	}
+}
+
+// Broadcast отправляет сообщение всем подписчикам темы на этой и, если хаб подключен
+// к Cluster, на остальных репликах
+func (h *Hub) Broadcast(topic, msgType string, data interface{}) {
+	msg := Message{Topic: topic, Type: msgType, Data: data, SentAt: time.Now().UTC()}
+	h.deliver(msg)
+	if h.relay != nil {
+		h.relay(msg)
+	}
+}
+
+// deliver отправляет сообщение подписчикам темы на этой реплике. Клиент, не успевающий
+// читать сообщения, отключается, чтобы не задерживать остальных.
+func (h *Hub) deliver(msg Message) {
+	topic := msg.Topic
+	h.mu.Lock()
+	var slow []*client
+	for c := range h.topics[topic] {
+		select {
+		case c.send <- msg:
+			hubMessages.Add(1)
+		default:
+			slow = append(slow, c)
+		}
+	}
+	h.mu.Unlock()
+
+	for _, c := range slow {
+		hubSlowClients.Add(1)
+		h.remove(c)
+	}
+}
+
+// Publish реализует outbox.Publisher: доменные события доставляются в личную тему
+// пользователя, которого они касаются. Событие получают только клиенты этой реплики;
+// между репликами события пересылает Cluster.
+func (h *Hub) Publish(ctx context.Context, event models.OutboxEvent) error {
+	h.deliver(Message{Topic: UserTopic(event.AggregateID), Type: event.EventType, Data: event, SentAt: time.Now().UTC()})
+	return nil
+}
+
+// OnlineUsers возвращает пользователей, подключенных к этой реплике
+func (h *Hub) OnlineUsers() []int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	users := make([]int, 0, len(h.users))
+	for userID := range h.users {
+		users = append(users, userID)
+	}
+	sort.Ints(users)
+	return users
+}
+
+// Instances возвращает число реплик, чьи подключения учтены в OnlineUsers
+func (h *Hub) Instances() int {
+	return 1
+}
+
+// Presence — сведения о подключенных пользователях: одной реплики (Hub) или всех (Cluster)
+type Presence interface {
+	OnlineUsers() []int
+	Instances() int
+}
+
+// notifyPresence сообщает об изменении списка подключенных пользователей, не дожидаясь получателя
+func (h *Hub) notifyPresence() {
+	select {
+	case h.presence <- struct{}{}:
+	default:
+	}
+}
+
+// Serve обслуживает соединение до его закрытия: читает команды подписки и пишет
+// сообщения из подписанных тем. Клиент сразу подписан на свою личную тему.
+func (h *Hub) Serve(conn *websocket.Conn, userID int, isAdmin bool) {
+	c := &client{conn: conn, userID: userID, isAdmin: isAdmin, send: make(chan Message, sendBuffer)}
+	conn.MaxPayloadBytes = maxCommandSize
+
+	h.mu.Lock()
+	if h.closed {
+		h.mu.Unlock()
+		conn.Close()
+		return
+	}
+	h.clients[c] = struct{}{}
+	h.subscribeLocked(c, UserTopic(userID))
+	h.users[userID]++
+	first := h.users[userID] == 1
+	h.mu.Unlock()
+	hubConnections.Add(1)
+	if first {
+		h.notifyPresence()
+	}
+
+	go h.writeLoop(c)
+	defer h.remove(c)
+
+	for {
+		var cmd command
+		if err := websocket.JSON.Receive(conn, &cmd); err != nil {
+			return
+		}
+		h.handleCommand(c, cmd)
+	}
+}
+
+func (h *Hub) handleCommand(c *client, cmd command) {
+	topic := strings.TrimSpace(cmd.Topic)
+	switch cmd.Action {
+	case "subscribe":
+		if !c.canSubscribe(topic) {
+			h.reply(c, topic, "error", "subscription to this topic is not allowed")
+			return
+		}
+		h.mu.Lock()
+		h.subscribeLocked(c, topic)
+		h.mu.Unlock()
+		h.reply(c, topic, "subscribed", nil)
+	case "unsubscribe":
+		h.mu.Lock()
+		h.unsubscribeLocked(c, topic)
+		h.mu.Unlock()
+		h.reply(c, topic, "unsubscribed", nil)
+	default:
+		h.reply(c, topic, "error", "unknown action")
+	}
+}
+
+// canSubscribe проверяет право клиента на тему: общие темы доступны всем,
+// личные — только владельцу и администраторам
+func (c *client) canSubscribe(topic string) bool {
+	switch topic {
+	case TopicBroadcast, TopicLeaderboard:
+		return true
+	}
+	if strings.HasPrefix(topic, userTopicPrefix) {
+		return topic == UserTopic(c.userID) || c.isAdmin
+	}
+	return false
+}
+
+func (h *Hub) reply(c *client, topic, msgType string, data interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	// Канал отправки закрывается под той же блокировкой при отключении клиента
+	if _, ok := h.clients[c]; !ok {
+		return
+	}
+	select {
+	case c.send <- Message{Topic: topic, Type: msgType, Data: data, SentAt: time.Now().UTC()}:
+	default:
+	}
+}
+
+func (h *Hub) writeLoop(c *client) {
+	for msg := range c.send {
+		c.conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+		if err := websocket.JSON.Send(c.conn, msg); err != nil {
+			h.remove(c)
+			return
+		}
+	}
+}
+
+func (h *Hub) subscribeLocked(c *client, topic string) {
+	if h.topics[topic] == nil {
+		h.topics[topic] = make(map[*client]struct{})
+	}
+	h.topics[topic][c] = struct{}{}
+}
+
+func (h *Hub) unsubscribeLocked(c *client, topic string) {
+	delete(h.topics[topic], c)
+	if len(h.topics[topic]) == 0 {
+		delete(h.topics, topic)
+	}
+}
+
+// remove отключает клиента и удаляет все его подписки
+func (h *Hub) remove(c *client) {
+	c.once.Do(func() {
+		h.mu.Lock()
+		for topic := range h.topics {
+			h.unsubscribeLocked(c, topic)
+		}
+		delete(h.clients, c)
+		close(c.send)
+		h.users[c.userID]--
+		last := h.users[c.userID] == 0
+		if last {
+			delete(h.users, c.userID)
+		}
+		h.mu.Unlock()
+
+		c.conn.Close()
+		hubConnections.Add(-1)
+		if last {
+			h.notifyPresence()
+		}
+	})
+}
+
+// Close отключает всех клиентов; после этого новые соединения не принимаются
+func (h *Hub) Close() {
+	h.mu.Lock()
+	h.closed = true
+	clients := make([]*client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.Unlock()
+
+	for _, c := range clients {
+		h.remove(c)
+	}
+	if len(clients) > 0 {
+		log.Printf("Realtime: closed %d WebSocket connections", len(clients))
+	}
+}
@@ -0,0 +1,150 @@
+// Package redact маскирует персональные данные и секреты (адреса почты, имена, токены)
+// в логах, сообщениях об ошибках и событиях, уходящих во внешние системы.
+// Маскирование включено по умолчанию; в отладочных окружениях его отключает Configure(false).
+package redact
+
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"sync/atomic"
+)
+
+// Mask заменяет секреты целиком
+const Mask = "[REDACTED]"
+
+var disabled atomic.Bool
+
+// Configure включает или отключает маскирование
+func Configure(enabled bool) {
+	disabled.Store(!enabled)
+}
+
+// Enabled сообщает, включено ли маскирование
+func Enabled() bool {
+	return !disabled.Load()
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+	// JWT и заголовок Authorization
+	jwtPattern    = regexp.MustCompile(`eyJ[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]+\.[A-Za-z0-9_\-]*`)
+	bearerPattern = regexp.MustCompile(`(?i)\b(bearer|basic)\s+[A-Za-z0-9._~+/=\-]+`)
+	// Параметры запроса и пары key=value с секретами
+	secretParamPattern = regexp.MustCompile(`(?i)\b((?:access_|refresh_)?token|code|password|secret|api_key|key)=([^&\s"']+)`)
+	// Длинные случайные строки: токены приглашений, ссылок из писем, календарей. Идентификаторы
+	// запросов (32 шестнадцатеричных символа) короче и остаются в логах.
+	longTokenPattern = regexp.MustCompile(`\b[A-Za-z0-9_\-]{40,}\b`)
+)
+
+// Email маскирует адрес, оставляя первую букву и домен: a***@example.com
+func Email(email string) string {
+	if !Enabled() || email == "" {
+		return email
+	}
+	at := strings.LastIndex(email, "@")
+	if at <= 0 {
+		return Name(email)
+	}
+	return email[:1] + "***" + email[at:]
+}
+
+// Name маскирует имя или логин, оставляя первую букву: a***
+func Name(name string) string {
+	if !Enabled() || name == "" {
+		return name
+	}
+	first := []rune(name)[0]
+	return string(first) + "***"
+}
+
+// String маскирует в произвольном тексте адреса почты, токены и секреты в параметрах
+func String(s string) string {
+	if !Enabled() || s == "" {
+		return s
+	}
+	s = jwtPattern.ReplaceAllString(s, Mask)
+	s = bearerPattern.ReplaceAllString(s, "$1 "+Mask)
+	s = secretParamPattern.ReplaceAllString(s, "$1="+Mask)
+	s = longTokenPattern.ReplaceAllString(s, Mask)
+	return emailPattern.ReplaceAllStringFunc(s, Email)
+}
+
+// Поля событий, значения которых маскируются по смыслу, а не по виду
+var (
+	nameFields = map[string]bool{
+		"username": true, "fullname": true, "name": true, "firstname": true, "lastname": true,
+		"actorusername": true, "displayname": true,
+	}
+	secretFields = map[string]bool{
+		"token": true, "password": true, "secret": true, "code": true, "otp": true,
+		"accesstoken": true, "refreshtoken": true,
+	}
+)
+
+// value маскирует значение JSON; key — имя поля, в котором оно лежит
+func value(key string, v interface{}) interface{} {
+	switch typed := v.(type) {
+	case map[string]interface{}:
+		for k, nested := range typed {
+			typed[k] = value(k, nested)
+		}
+		return typed
+	case []interface{}:
+		for i, nested := range typed {
+			typed[i] = value(key, nested)
+		}
+		return typed
+	case string:
+		field := strings.ToLower(key)
+		switch {
+		case secretFields[field]:
+			return Mask
+		case strings.Contains(field, "email"):
+			return Email(typed)
+		case nameFields[field]:
+			return Name(typed)
+		}
+		return String(typed)
+	}
+	return v
+}
+
+// JSON маскирует персональные данные в документе JSON: значения полей с именами и адресами,
+// секреты, а в остальных строках — то же, что String. Документ, который не удалось разобрать,
+// маскируется как текст.
+func JSON(data []byte) []byte {
+	if !Enabled() || len(data) == 0 {
+		return data
+	}
+	var doc interface{}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return []byte(String(string(data)))
+	}
+	masked, err := json.Marshal(value("", doc))
+	if err != nil {
+		return []byte(String(string(data)))
+	}
+	return masked
+}
+
+// writer маскирует каждую запись перед передачей в нижележащий поток
+type writer struct {
+	out io.Writer
+}
+
+// Writer оборачивает поток логов: каждая запись (строка лога) маскируется функцией String
+func Writer(out io.Writer) io.Writer {
+	return writer{out: out}
+}
+
+func (w writer) Write(p []byte) (int, error) {
+	if !Enabled() {
+		return w.out.Write(p)
+	}
+	if _, err := io.WriteString(w.out, String(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
@@ -0,0 +1,52 @@
+// Package requestid хранит идентификатор запроса (X-Request-ID) в контексте, чтобы он
+// попадал в логи и ответы с ошибкой и пользователь мог сослаться на него в обращении.
+package requestid
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// Header — заголовок, в котором идентификатор принимается от шлюза и возвращается клиенту
+const Header = "X-Request-ID"
+
+// maxLength ограничивает длину идентификатора, принятого от клиента
+const maxLength = 64
+
+type contextKey struct{}
+
+// New генерирует случайный идентификатор запроса
+func New() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(b[:])
+}
+
+// Valid проверяет идентификатор, полученный извне: допускаются только буквы, цифры,
+// '-', '_' и '.', чтобы значение можно было безопасно писать в логи
+func Valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		c := id[i]
+		if !(c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '-' || c == '_' || c == '.') {
+			return false
+		}
+	}
+	return true
+}
+
+// WithContext возвращает контекст с идентификатором запроса
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, contextKey{}, id)
+}
+
+// FromContext возвращает идентификатор запроса или пустую строку
+func FromContext(ctx context.Context) string {
+	id, _ := ctx.Value(contextKey{}).(string)
+	return id
+}
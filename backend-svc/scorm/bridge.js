@@ -0,0 +1,167 @@
+/*
+ * Мост среды выполнения SCORM внутри учебного объекта. При импорте пакета сценарий подключается
+ * в начало каждой HTML-страницы и устанавливает window.API (SCORM 1.2) и window.API_1484_11
+ * (SCORM 2004) в окне самого учебного объекта.
+ *
+ * Учебный объект открывается в изолированном iframe без allow-same-origin: у него уникальный
+ * источник, поэтому он не видит окно плеера, его localStorage и токен доступа. Данные CMI мост
+ * получает из имени окна, которое задает плеер (см. runtime.js), хранит их у себя, чтобы вызовы
+ * API оставались синхронными, и передает плееру через postMessage. В API сервиса обращается
+ * только плеер.
+ */
+(function (global) {
+  "use strict";
+
+  var prefix = "lms-scorm:";
+
+  if (global.API || global.API_1484_11 || global.name.indexOf(prefix) !== 0) {
+    return;
+  }
+
+  var launch;
+  try {
+    launch = JSON.parse(global.name.slice(prefix.length));
+  } catch (err) {
+    return;
+  }
+
+  var messages = {
+    "0": "No error",
+    "101": "General exception",
+    "103": "Already initialized",
+    "104": "Content instance terminated",
+    "201": "Invalid argument error",
+    "301": "Not initialized",
+    "391": "General commit failure",
+    "401": "Undefined data model element",
+    "403": "Element is read only",
+    "404": "Element is read only"
+  };
+
+  var readOnly = [
+    "cmi.core.student_id", "cmi.core.student_name", "cmi.core.credit", "cmi.core.entry",
+    "cmi.core.total_time", "cmi.core.lesson_mode", "cmi.launch_data", "cmi.comments_from_lms",
+    "cmi.learner_id", "cmi.learner_name", "cmi.credit", "cmi.entry", "cmi.mode",
+    "cmi.completion_threshold", "cmi.scaled_passing_score", "cmi.time_limit_action", "cmi.max_time_allowed"
+  ];
+
+  var data = launch.data || {};
+  var scorm12 = launch.version === "1.2";
+  var state = "new";
+  var lastError = "0";
+
+  // post передает плееру текущие данные; commit — запрос сохранить их в сервисе. Имя окна
+  // обновляется, чтобы данные пережили переход учебного объекта на другую страницу пакета.
+  function post(commit, final) {
+    global.name = prefix + JSON.stringify({ version: launch.version, data: data });
+    global.parent.postMessage({ type: "lms-scorm", data: data, commit: commit, final: final }, "*");
+  }
+
+  function fail(code) {
+    lastError = code;
+    return "false";
+  }
+
+  function count(key) {
+    var keyPrefix = key.slice(0, -"_count".length);
+    var seen = {};
+    Object.keys(data).forEach(function (name) {
+      if (name.indexOf(keyPrefix) === 0) {
+        seen[name.slice(keyPrefix.length).split(".")[0]] = true;
+      }
+    });
+    return String(Object.keys(seen).length);
+  }
+
+  function initialize() {
+    if (state === "running") {
+      return fail(scorm12 ? "101" : "103");
+    }
+    if (state === "terminated") {
+      return fail(scorm12 ? "101" : "104");
+    }
+    state = "running";
+    lastError = "0";
+    return "true";
+  }
+
+  function terminate() {
+    if (state !== "running") {
+      return fail("301");
+    }
+    state = "terminated";
+    lastError = "0";
+    post(true, true);
+    return "true";
+  }
+
+  function getValue(key) {
+    if (state !== "running") {
+      lastError = "301";
+      return "";
+    }
+    lastError = "0";
+    if (/\._count$/.test(key)) {
+      return count(key);
+    }
+    if (!Object.prototype.hasOwnProperty.call(data, key)) {
+      lastError = scorm12 ? "0" : "403";
+      return "";
+    }
+    return data[key];
+  }
+
+  function setValue(key, value) {
+    if (state !== "running") {
+      return fail("301");
+    }
+    if (typeof key !== "string" || !/^(cmi|adl)\./.test(key)) {
+      return fail("401");
+    }
+    if (readOnly.indexOf(key) >= 0) {
+      return fail(scorm12 ? "403" : "404");
+    }
+    data[key] = String(value);
+    lastError = "0";
+    post(false, false);
+    return "true";
+  }
+
+  function commit() {
+    if (state !== "running") {
+      return fail("301");
+    }
+    lastError = "0";
+    post(true, false);
+    return "true";
+  }
+
+  function getLastError() {
+    return lastError;
+  }
+
+  function getErrorString(code) {
+    return messages[String(code)] || "";
+  }
+
+  global.API = {
+    LMSInitialize: initialize,
+    LMSFinish: terminate,
+    LMSGetValue: getValue,
+    LMSSetValue: setValue,
+    LMSCommit: commit,
+    LMSGetLastError: getLastError,
+    LMSGetErrorString: getErrorString,
+    LMSGetDiagnostic: getErrorString
+  };
+  global.API_1484_11 = {
+    Initialize: initialize,
+    Terminate: terminate,
+    GetValue: getValue,
+    SetValue: setValue,
+    Commit: commit,
+    GetLastError: getLastError,
+    GetErrorString: getErrorString,
+    GetDiagnostic: getErrorString
+  };
+})(window);
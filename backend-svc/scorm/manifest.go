@@ -0,0 +1,297 @@
+// Package scorm читает пакеты SCORM 1.2 и SCORM 2004: манифест imsmanifest.xml, файлы пакета
+// и данные CMI, которые учебный объект сохраняет через программный интерфейс среды выполнения.
+package scorm
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+)
+
+// Поддерживаемые версии SCORM
+const (
+	Version12   = "1.2"
+	Version2004 = "2004"
+)
+
+// Типы ресурсов: SCO общается со средой выполнения, asset — статическое содержимое
+const (
+	TypeSCO   = "sco"
+	TypeAsset = "asset"
+)
+
+const manifestName = "imsmanifest.xml"
+
+var (
+	// ErrInvalidPackage — файл не является zip-архивом или содержит недопустимые пути
+	ErrInvalidPackage = errors.New("scorm: invalid package")
+	// ErrNoManifest — в корне архива нет imsmanifest.xml
+	ErrNoManifest = errors.New("scorm: imsmanifest.xml not found")
+	// ErrUnsupportedVersion — манифест не относится к SCORM 1.2 или SCORM 2004
+	ErrUnsupportedVersion = errors.New("scorm: unsupported version")
+	// ErrNoContent — в структуре курса нет ни одного запускаемого элемента
+	ErrNoContent = errors.New("scorm: no launchable items")
+	// ErrTooLarge — распакованное содержимое превышает допустимый размер
+	ErrTooLarge = errors.New("scorm: package too large")
+)
+
+// Item — запускаемый элемент структуры курса; каждый становится заданием
+type Item struct {
+	Identifier string
+	Title      string
+	// Launch — путь к файлу внутри пакета с параметрами запуска или абсолютный URL внешнего ресурса
+	Launch    string
+	ScormType string
+}
+
+// Package — открытый пакет SCORM
+type Package struct {
+	Identifier string
+	Version    string
+	Title      string
+	Items      []Item
+
+	files []*zip.File
+}
+
+type manifest struct {
+	Identifier    string        `xml:"identifier,attr"`
+	Attrs         []xml.Attr    `xml:",any,attr"`
+	SchemaVersion string        `xml:"metadata>schemaversion"`
+	Organizations organizations `xml:"organizations"`
+	Resources     resources     `xml:"resources"`
+}
+
+type organizations struct {
+	Default       string         `xml:"default,attr"`
+	Organizations []organization `xml:"organization"`
+}
+
+type organization struct {
+	Identifier string         `xml:"identifier,attr"`
+	Title      string         `xml:"title"`
+	Items      []manifestItem `xml:"item"`
+}
+
+type manifestItem struct {
+	Identifier    string         `xml:"identifier,attr"`
+	IdentifierRef string         `xml:"identifierref,attr"`
+	IsVisible     string         `xml:"isvisible,attr"`
+	Parameters    string         `xml:"parameters,attr"`
+	Title         string         `xml:"title"`
+	Items         []manifestItem `xml:"item"`
+}
+
+type resources struct {
+	Base      string     `xml:"base,attr"`
+	Resources []resource `xml:"resource"`
+}
+
+type resource struct {
+	Identifier string `xml:"identifier,attr"`
+	Href       string `xml:"href,attr"`
+	Base       string `xml:"base,attr"`
+	// В SCORM 1.2 атрибут называется adlcp:scormtype, в SCORM 2004 — adlcp:scormType
+	ScormType12   string `xml:"scormtype,attr"`
+	ScormType2004 string `xml:"scormType,attr"`
+}
+
+// Open читает манифест пакета. Пути всех файлов архива проверяются заранее, чтобы пакет
+// нельзя было распаковать за пределы своего каталога.
+func Open(r io.ReaderAt, size int64) (*Package, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+
+	var manifestFile *zip.File
+	files := make([]*zip.File, 0, len(archive.File))
+	for _, f := range archive.File {
+		if strings.HasSuffix(f.Name, "/") {
+			continue
+		}
+		if !validPath(f.Name) {
+			return nil, fmt.Errorf("%w: file name %q", ErrInvalidPackage, f.Name)
+		}
+		if f.Name == manifestName {
+			manifestFile = f
+		}
+		files = append(files, f)
+	}
+	if manifestFile == nil {
+		return nil, ErrNoManifest
+	}
+
+	rc, err := manifestFile.Open()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+	}
+	defer rc.Close()
+	var m manifest
+	if err := xml.NewDecoder(io.LimitReader(rc, 16<<20)).Decode(&m); err != nil {
+		return nil, fmt.Errorf("%w: parse manifest: %v", ErrInvalidPackage, err)
+	}
+
+	pkg := &Package{Identifier: m.Identifier, Version: detectVersion(m), files: files}
+	if pkg.Version == "" {
+		return nil, ErrUnsupportedVersion
+	}
+	org := m.Organizations.defaultOrganization()
+	if org == nil {
+		return nil, ErrNoContent
+	}
+	pkg.Title = strings.TrimSpace(org.Title)
+
+	byID := make(map[string]resource, len(m.Resources.Resources))
+	for _, res := range m.Resources.Resources {
+		byID[res.Identifier] = res
+	}
+	var walk func(items []manifestItem) error
+	walk = func(items []manifestItem) error {
+		for _, it := range items {
+			if it.IsVisible != "false" && it.IdentifierRef != "" {
+				res, ok := byID[it.IdentifierRef]
+				if ok && res.Href != "" {
+					launch, err := launchPath(m.Resources.Base, res, it.Parameters)
+					if err != nil {
+						return err
+					}
+					scormType := strings.ToLower(res.ScormType12 + res.ScormType2004)
+					if scormType != TypeAsset {
+						scormType = TypeSCO
+					}
+					pkg.Items = append(pkg.Items, Item{
+						Identifier: it.Identifier,
+						Title:      strings.TrimSpace(it.Title),
+						Launch:     launch,
+						ScormType:  scormType,
+					})
+				}
+			}
+			if err := walk(it.Items); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if err := walk(org.Items); err != nil {
+		return nil, err
+	}
+	if len(pkg.Items) == 0 {
+		return nil, ErrNoContent
+	}
+	return pkg, nil
+}
+
+// Files передает содержимое каждого файла пакета в fn. Суммарный объем распакованных данных
+// ограничен limit байтами независимо от размеров, записанных в заголовках архива.
+func (p *Package) Files(limit int64, fn func(name string, data []byte) error) error {
+	var total int64
+	for _, f := range p.files {
+		rc, err := f.Open()
+		if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPackage, err)
+		}
+		data, err := io.ReadAll(io.LimitReader(rc, limit-total+1))
+		rc.Close()
+		if err != nil {
+			return fmt.Errorf("%w: read %s: %v", ErrInvalidPackage, f.Name, err)
+		}
+		total += int64(len(data))
+		if total > limit {
+			return ErrTooLarge
+		}
+		if err := fn(f.Name, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o organizations) defaultOrganization() *organization {
+	for i := range o.Organizations {
+		if o.Organizations[i].Identifier == o.Default {
+			return &o.Organizations[i]
+		}
+	}
+	if len(o.Organizations) > 0 {
+		return &o.Organizations[0]
+	}
+	return nil
+}
+
+// detectVersion определяет версию по schemaversion, а если ее нет — по пространствам имен ADL
+func detectVersion(m manifest) string {
+	schema := strings.TrimSpace(m.SchemaVersion)
+	switch {
+	case schema == "1.2":
+		return Version12
+	case strings.Contains(schema, "2004") || strings.Contains(schema, "1.3"):
+		return Version2004
+	}
+	for _, attr := range m.Attrs {
+		switch {
+		case strings.Contains(attr.Value, "adlcp_rootv1p2"):
+			return Version12
+		case strings.Contains(attr.Value, "adlcp_v1p3"):
+			return Version2004
+		}
+	}
+	return ""
+}
+
+// launchPath собирает путь запуска из xml:base ресурсов, href и параметров элемента
+func launchPath(base string, res resource, parameters string) (string, error) {
+	href := res.Href
+	if u, err := url.Parse(href); err == nil && u.Scheme != "" {
+		if u.Scheme != "http" && u.Scheme != "https" {
+			return "", fmt.Errorf("%w: launch URL %q", ErrInvalidPackage, href)
+		}
+		return appendParameters(href, parameters), nil
+	}
+
+	file, query := href, ""
+	if i := strings.IndexAny(href, "?#"); i >= 0 {
+		file, query = href[:i], href[i:]
+	}
+	full := path.Join(base, res.Base, file)
+	if !validPath(full) {
+		return "", fmt.Errorf("%w: launch path %q", ErrInvalidPackage, href)
+	}
+	return appendParameters(full+query, parameters), nil
+}
+
+// appendParameters дописывает параметры запуска по правилам IMS: "?" заменяется на "&",
+// если в адресе уже есть строка запроса
+func appendParameters(launch, parameters string) string {
+	parameters = strings.TrimSpace(parameters)
+	switch {
+	case parameters == "":
+		return launch
+	case parameters[0] == '#':
+		return launch + parameters
+	case parameters[0] == '?':
+		parameters = parameters[1:]
+	}
+	if strings.Contains(launch, "?") {
+		return launch + "&" + parameters
+	}
+	return launch + "?" + parameters
+}
+
+func validPath(name string) bool {
+	if name == "" || strings.HasPrefix(name, "/") || strings.Contains(name, "\\") {
+		return false
+	}
+	for _, part := range strings.Split(name, "/") {
+		if part == ".." {
+			return false
+		}
+	}
+	return true
+}
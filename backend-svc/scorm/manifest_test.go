@@ -0,0 +1,230 @@
+package scorm
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// buildZip собирает архив из пар имя — содержимое
+func buildZip(t *testing.T, files map[string]string) *bytes.Reader {
+	t.Helper()
+	var buf bytes.Buffer
+	w := zip.NewWriter(&buf)
+	for name, content := range files {
+		f, err := w.Create(name)
+		if err != nil {
+			t.Fatalf("create %s: %v", name, err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatalf("write %s: %v", name, err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("close zip: %v", err)
+	}
+	return bytes.NewReader(buf.Bytes())
+}
+
+func open(t *testing.T, files map[string]string) (*Package, error) {
+	t.Helper()
+	r := buildZip(t, files)
+	return Open(r, r.Size())
+}
+
+const manifest12 = `<?xml version="1.0"?>
+<manifest identifier="course-12" xmlns="http://www.imsproject.org/xsd/imscp_rootv1p1p2"
+    xmlns:adlcp="http://www.adlnet.org/xsd/adlcp_rootv1p2">
+  <metadata><schema>ADL SCORM</schema><schemaversion>1.2</schemaversion></metadata>
+  <organizations default="org1">
+    <organization identifier="org1">
+      <title> SQL injection </title>
+      <item identifier="i1" identifierref="r1"><title>Intro</title></item>
+      <item identifier="i2" identifierref="r2" parameters="?lang=ru"><title>Glossary</title></item>
+    </organization>
+  </organizations>
+  <resources>
+    <resource identifier="r1" type="webcontent" adlcp:scormtype="sco" href="intro/index.html"/>
+    <resource identifier="r2" type="webcontent" adlcp:scormtype="asset" href="glossary.html"/>
+  </resources>
+</manifest>`
+
+const manifest2004 = `<?xml version="1.0"?>
+<manifest identifier="course-2004" xmlns="http://www.imsglobal.org/xsd/imscp_v1p1"
+    xmlns:adlcp="http://www.adlnet.org/xsd/adlcp_v1p3">
+  <metadata><schema>ADL SCORM</schema><schemaversion>2004 4th Edition</schemaversion></metadata>
+  <organizations default="main">
+    <organization identifier="other"><title>Other</title>
+      <item identifier="x" identifierref="r1"><title>Wrong organization</title></item>
+    </organization>
+    <organization identifier="main">
+      <title>XSS</title>
+      <item identifier="m1"><title>Module 1</title>
+        <item identifier="l1" identifierref="r1"><title>Lesson 1</title>
+          <item identifier="l1a" identifierref="r2"><title>Lesson 1a</title></item>
+        </item>
+        <item identifier="hidden" identifierref="r1" isvisible="false"><title>Hidden</title></item>
+      </item>
+      <item identifier="m2"><title>Module 2</title>
+        <item identifier="l2" identifierref="r3"><title>Lesson 2</title></item>
+      </item>
+    </organization>
+  </organizations>
+  <resources xml:base="content/">
+    <resource identifier="r1" adlcp:scormType="sco" href="lesson1.html?start=1" xml:base="m1/"/>
+    <resource identifier="r2" adlcp:scormType="sco" href="lesson1a.html"/>
+    <resource identifier="r3" adlcp:scormType="sco" href="https://example.com/lesson2"/>
+  </resources>
+</manifest>`
+
+func TestOpen(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest string
+		version  string
+		title    string
+		items    []Item
+	}{
+		{"SCORM 1.2", manifest12, Version12, "SQL injection", []Item{
+			{Identifier: "i1", Title: "Intro", Launch: "intro/index.html", ScormType: TypeSCO},
+			{Identifier: "i2", Title: "Glossary", Launch: "glossary.html?lang=ru", ScormType: TypeAsset},
+		}},
+		{"SCORM 2004 with nested items", manifest2004, Version2004, "XSS", []Item{
+			{Identifier: "l1", Title: "Lesson 1", Launch: "content/m1/lesson1.html?start=1", ScormType: TypeSCO},
+			{Identifier: "l1a", Title: "Lesson 1a", Launch: "content/lesson1a.html", ScormType: TypeSCO},
+			{Identifier: "l2", Title: "Lesson 2", Launch: "https://example.com/lesson2", ScormType: TypeSCO},
+		}},
+		{"version from namespace", strings.Replace(manifest12, "<schemaversion>1.2</schemaversion>", "", 1), Version12, "SQL injection", nil},
+		{"nested manifest is ignored", strings.Replace(manifest12, "</resources>",
+			`</resources><manifest identifier="sub"><organizations><organization identifier="s"><item identifier="s1" identifierref="r1"/></organization></organizations></manifest>`, 1),
+			Version12, "SQL injection", []Item{
+				{Identifier: "i1", Title: "Intro", Launch: "intro/index.html", ScormType: TypeSCO},
+				{Identifier: "i2", Title: "Glossary", Launch: "glossary.html?lang=ru", ScormType: TypeAsset},
+			}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pkg, err := open(t, map[string]string{manifestName: tt.manifest, "intro/index.html": "<html></html>"})
+			if err != nil {
+				t.Fatalf("open: %v", err)
+			}
+			if pkg.Version != tt.version || pkg.Title != tt.title {
+				t.Errorf("version = %q, title = %q", pkg.Version, pkg.Title)
+			}
+			if tt.items == nil {
+				return
+			}
+			if len(pkg.Items) != len(tt.items) {
+				t.Fatalf("items = %+v, want %+v", pkg.Items, tt.items)
+			}
+			for i := range tt.items {
+				if pkg.Items[i] != tt.items[i] {
+					t.Errorf("item %d = %+v, want %+v", i, pkg.Items[i], tt.items[i])
+				}
+			}
+		})
+	}
+}
+
+func TestOpenErrors(t *testing.T) {
+	resource := func(href string) string {
+		return strings.Replace(manifest12, `href="intro/index.html"`, `href="`+href+`"`, 1)
+	}
+	tests := []struct {
+		name  string
+		files map[string]string
+		want  error
+	}{
+		{"no manifest", map[string]string{"index.html": ""}, ErrNoManifest},
+		{"manifest in a subdirectory", map[string]string{"course/" + manifestName: manifest12}, ErrNoManifest},
+		{"malformed XML", map[string]string{manifestName: "<manifest><organizations>"}, ErrInvalidPackage},
+		{"not XML", map[string]string{manifestName: "not a manifest"}, ErrInvalidPackage},
+		{"unknown version", map[string]string{manifestName: `<manifest identifier="m"><organizations><organization/></organizations></manifest>`}, ErrUnsupportedVersion},
+		{"no organizations", map[string]string{manifestName: `<manifest identifier="m"><metadata><schemaversion>1.2</schemaversion></metadata><organizations/></manifest>`}, ErrNoContent},
+		{"no launchable items", map[string]string{manifestName: strings.ReplaceAll(manifest12, `identifierref="r`, `identifierref="missing`)}, ErrNoContent},
+		{"path traversal in file name", map[string]string{manifestName: manifest12, "../evil.html": ""}, ErrInvalidPackage},
+		{"absolute file name", map[string]string{manifestName: manifest12, "/etc/passwd": ""}, ErrInvalidPackage},
+		{"backslash in file name", map[string]string{manifestName: manifest12, `..\evil.html`: ""}, ErrInvalidPackage},
+		{"path traversal in launch path", map[string]string{manifestName: resource("../../index.html")}, ErrInvalidPackage},
+		{"javascript launch URL", map[string]string{manifestName: resource("javascript:alert(1)")}, ErrInvalidPackage},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := open(t, tt.files)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestOpenNotZip(t *testing.T) {
+	r := bytes.NewReader([]byte("PK not really a zip"))
+	if _, err := Open(r, r.Size()); !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidPackage)
+	}
+}
+
+func TestOpenOversizedManifest(t *testing.T) {
+	// Манифест больше 16 МБ обрезается при чтении и не разбирается
+	padding := "<!--" + strings.Repeat("x", 16<<20) + "-->"
+	_, err := open(t, map[string]string{manifestName: strings.Replace(manifest12, "<metadata>", padding+"<metadata>", 1)})
+	if !errors.Is(err, ErrInvalidPackage) {
+		t.Errorf("error = %v, want %v", err, ErrInvalidPackage)
+	}
+}
+
+func TestFiles(t *testing.T) {
+	files := map[string]string{
+		manifestName:       manifest12,
+		"intro/index.html": strings.Repeat("a", 100),
+		"glossary.html":    strings.Repeat("b", 100),
+	}
+	pkg, err := open(t, files)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	total := int64(len(manifest12) + 200)
+
+	got := map[string]string{}
+	if err := pkg.Files(total, func(name string, data []byte) error {
+		got[name] = string(data)
+		return nil
+	}); err != nil {
+		t.Fatalf("files: %v", err)
+	}
+	for name, content := range files {
+		if got[name] != content {
+			t.Errorf("content of %s differs", name)
+		}
+	}
+
+	if err := pkg.Files(total-1, func(string, []byte) error { return nil }); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("error = %v, want %v", err, ErrTooLarge)
+	}
+	stop := errors.New("stop")
+	if err := pkg.Files(total, func(string, []byte) error { return stop }); !errors.Is(err, stop) {
+		t.Errorf("error = %v, want %v", err, stop)
+	}
+}
+
+func TestAppendParameters(t *testing.T) {
+	tests := []struct {
+		launch, parameters, want string
+	}{
+		{"a.html", "", "a.html"},
+		{"a.html", "  ", "a.html"},
+		{"a.html", "x=1", "a.html?x=1"},
+		{"a.html", "?x=1", "a.html?x=1"},
+		{"a.html?y=2", "?x=1", "a.html?y=2&x=1"},
+		{"a.html?y=2", "x=1", "a.html?y=2&x=1"},
+		{"a.html", "#page2", "a.html#page2"},
+	}
+	for _, tt := range tests {
+		if got := appendParameters(tt.launch, tt.parameters); got != tt.want {
+			t.Errorf("appendParameters(%q, %q) = %q, want %q", tt.launch, tt.parameters, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package scorm
+
+import (
+	_ "embed"
+	"errors"
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// RuntimeJS — среда выполнения для плеера курса: открывает учебный объект в изолированном iframe
+// и сохраняет присланные мостом данные CMI через API сервиса
+//
+//go:embed runtime.js
+var RuntimeJS []byte
+
+// BridgeJS — мост внутри учебного объекта: объекты window.API (SCORM 1.2) и window.API_1484_11
+// (SCORM 2004), которые передают данные CMI плееру через postMessage
+//
+//go:embed bridge.js
+var BridgeJS []byte
+
+// BridgeFile — путь моста относительно корня пакета в медиахранилище
+const BridgeFile = "_lms/scorm-bridge.js"
+
+var (
+	headTag    = regexp.MustCompile(`(?i)<head(\s[^>]*)?>`)
+	htmlTag    = regexp.MustCompile(`(?i)<html(\s[^>]*)?>`)
+	doctypeTag = regexp.MustCompile(`(?i)^\s*<!doctype[^>]*>`)
+)
+
+// IsPage сообщает, является ли файл пакета HTML-страницей, к которой подключается мост
+func IsPage(name string) bool {
+	switch strings.ToLower(path.Ext(name)) {
+	case ".html", ".htm", ".xhtml":
+		return true
+	}
+	return false
+}
+
+// InjectBridge подключает мост к странице name пакета. Сценарий вставляется первым в <head>,
+// чтобы API было установлено до сценариев учебного объекта; без <head> — после <html> или
+// объявления DOCTYPE.
+func InjectBridge(name string, page []byte) []byte {
+	src := strings.Repeat("../", strings.Count(name, "/")) + BridgeFile
+	tag := []byte(`<script src="` + src + `"></script>`)
+
+	at := 0
+	for _, re := range []*regexp.Regexp{headTag, htmlTag, doctypeTag} {
+		if loc := re.FindIndex(page); loc != nil {
+			at = loc[1]
+			break
+		}
+	}
+	out := make([]byte, 0, len(page)+len(tag))
+	out = append(out, page[:at]...)
+	out = append(out, tag...)
+	return append(out, page[at:]...)
+}
+
+// Ограничения на данные CMI одного учебного объекта
+const (
+	MaxDataKeys   = 2000
+	MaxKeyLength  = 255
+	MaxValueBytes = 64000
+)
+
+// ErrInvalidData — данные CMI нарушают ограничения
+var ErrInvalidData = errors.New("scorm: invalid runtime data")
+
+// readOnly — элементы модели данных, которые задает система обучения, а не учебный объект
+var readOnly = map[string]map[string]bool{
+	Version12: {
+		"cmi.core.student_id": true, "cmi.core.student_name": true, "cmi.core.credit": true,
+		"cmi.core.entry": true, "cmi.core.total_time": true, "cmi.core.lesson_mode": true,
+		"cmi.launch_data": true, "cmi.comments_from_lms": true,
+	},
+	Version2004: {
+		"cmi.learner_id": true, "cmi.learner_name": true, "cmi.credit": true, "cmi.entry": true,
+		"cmi.mode": true, "cmi.total_time": true, "cmi.launch_data": true, "cmi.completion_threshold": true,
+		"cmi.scaled_passing_score": true, "cmi.time_limit_action": true, "cmi.max_time_allowed": true,
+	},
+}
+
+// keys — имена элементов модели данных, которые отличаются в версиях SCORM
+type keys struct {
+	learnerID, learnerName, credit, entry, mode, exit, status, score string
+}
+
+var versionKeys = map[string]keys{
+	Version12: {
+		learnerID: "cmi.core.student_id", learnerName: "cmi.core.student_name", credit: "cmi.core.credit",
+		entry: "cmi.core.entry", mode: "cmi.core.lesson_mode", exit: "cmi.core.exit",
+		status: "cmi.core.lesson_status", score: "cmi.core.score.raw",
+	},
+	Version2004: {
+		learnerID: "cmi.learner_id", learnerName: "cmi.learner_name", credit: "cmi.credit",
+		entry: "cmi.entry", mode: "cmi.mode", exit: "cmi.exit",
+		status: "cmi.completion_status", score: "cmi.score.raw",
+	},
+}
+
+// Sanitize проверяет данные, присланные учебным объектом, и отбрасывает элементы только для чтения
+func Sanitize(version string, data map[string]string) (map[string]string, error) {
+	if len(data) > MaxDataKeys {
+		return nil, fmt.Errorf("%w: more than %d elements", ErrInvalidData, MaxDataKeys)
+	}
+	clean := make(map[string]string, len(data))
+	for key, value := range data {
+		if !strings.HasPrefix(key, "cmi.") && !strings.HasPrefix(key, "adl.") {
+			return nil, fmt.Errorf("%w: unknown element %q", ErrInvalidData, key)
+		}
+		if len(key) > MaxKeyLength || len(value) > MaxValueBytes {
+			return nil, fmt.Errorf("%w: element %q is too long", ErrInvalidData, key)
+		}
+		if readOnly[version][key] {
+			continue
+		}
+		clean[key] = value
+	}
+	return clean, nil
+}
+
+// InitialData дополняет сохраненные данные элементами, которые задает система обучения:
+// сведениями об учащемся, режимом и признаком продолжения прерванной попытки
+func InitialData(version, learnerID, learnerName string, saved map[string]string) map[string]string {
+	k := versionKeys[version]
+	data := make(map[string]string, len(saved)+6)
+	for key, value := range saved {
+		data[key] = value
+	}
+	data[k.learnerID] = learnerID
+	data[k.learnerName] = learnerName
+	data[k.credit] = "credit"
+	data[k.mode] = "normal"
+	switch {
+	case len(saved) == 0:
+		data[k.entry] = "ab-initio"
+	case saved[k.exit] == "suspend":
+		data[k.entry] = "resume"
+	default:
+		data[k.entry] = ""
+	}
+	if _, ok := data[k.status]; !ok {
+		if version == Version12 {
+			data[k.status] = "not attempted"
+		} else {
+			data[k.status] = "unknown"
+		}
+	}
+	return data
+}
+
+// Status возвращает состояние попытки, признак завершения и балл. SCORM 1.2 считает объект
+// пройденным при lesson_status completed или passed, SCORM 2004 — при completion_status
+// completed либо success_status passed.
+func Status(version string, data map[string]string) (status string, completed bool, score *float64) {
+	k := versionKeys[version]
+	status = data[k.status]
+	if version == Version12 {
+		completed = status == "completed" || status == "passed"
+	} else {
+		success := data["cmi.success_status"]
+		completed = (status == "completed" && success != "failed") || success == "passed"
+		if success == "passed" || success == "failed" {
+			status = success
+		}
+	}
+	if raw, err := strconv.ParseFloat(data[k.score], 64); err == nil {
+		score = &raw
+	}
+	return status, completed, score
+}
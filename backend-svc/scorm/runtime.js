@@ -0,0 +1,91 @@
+/*
+ * Среда выполнения SCORM для плеера курса. Плеер загружает сценарий с /api/scorm/runtime.js и вызывает
+ *
+ *   LmsScorm.load({ apiBase: "/api", taskId: 42, token: accessToken, frame: iframeElement });
+ *
+ * load получает сохраненные данные CMI и открывает учебный объект в iframe frame. Iframe изолирован
+ * (sandbox без allow-same-origin): код пакета выполняется с уникальным источником и не получает
+ * доступа к окну плеера и токену. Программный интерфейс SCORM устанавливает в окне учебного объекта
+ * мост (bridge.js), подключенный к страницам пакета при импорте. Мост передает данные плееру через
+ * postMessage, а плеер сохраняет их через API сервиса.
+ */
+(function (global) {
+  "use strict";
+
+  var sandbox = "allow-scripts allow-forms allow-popups";
+  var namePrefix = "lms-scorm:";
+
+  function attach(options, frame, launch) {
+    var url = options.apiBase.replace(/\/$/, "") + "/tasks/" + options.taskId + "/scorm";
+    var data = launch.data || {};
+    var dirty = false;
+
+    function save(keepalive) {
+      dirty = false;
+      return fetch(url, {
+        method: "PUT",
+        keepalive: keepalive,
+        headers: { "Authorization": "Bearer " + options.token, "Content-Type": "application/json" },
+        body: JSON.stringify({ data: data })
+      }).then(function (response) {
+        if (!response.ok) {
+          throw new Error("SCORM commit failed with status " + response.status);
+        }
+        return response.json();
+      }).then(function (attempt) {
+        if (options.onCommit) {
+          options.onCommit(attempt);
+        }
+      }, function (err) {
+        dirty = true;
+        if (options.onError) {
+          options.onError(err);
+        }
+      });
+    }
+
+    // Сообщения принимаются только от окна учебного объекта; его источник уникален, поэтому
+    // отправитель проверяется по окну, а не по origin
+    function receive(event) {
+      var message = event.data;
+      if (event.source !== frame.contentWindow || !message || message.type !== "lms-scorm") {
+        return;
+      }
+      if (message.data && typeof message.data === "object") {
+        data = message.data;
+        dirty = true;
+      }
+      if (message.commit && dirty) {
+        save(!!message.final);
+      }
+    }
+
+    global.addEventListener("message", receive);
+    global.addEventListener("pagehide", function () {
+      if (dirty) {
+        save(true);
+      }
+    });
+
+    frame.setAttribute("sandbox", sandbox);
+    frame.name = namePrefix + JSON.stringify({ version: launch.version, data: data });
+    frame.src = launch.launchUrl;
+  }
+
+  global.LmsScorm = {
+    load: function (options) {
+      var url = options.apiBase.replace(/\/$/, "") + "/tasks/" + options.taskId + "/scorm";
+      return fetch(url, {
+        headers: { "Authorization": "Bearer " + options.token }
+      }).then(function (response) {
+        if (!response.ok) {
+          throw new Error("SCORM launch failed with status " + response.status);
+        }
+        return response.json();
+      }).then(function (launch) {
+        attach(options, options.frame, launch);
+        return launch;
+      });
+    }
+  };
+})(window);
@@ -0,0 +1,36 @@
+package scorm
+
+import "testing"
+
+func TestInjectBridge(t *testing.T) {
+	tests := []struct {
+		name, page, want string
+	}{
+		{"index.html", `<html><head><title>x</title></head></html>`,
+			`<html><head><script src="_lms/scorm-bridge.js"></script><title>x</title></head></html>`},
+		{"a/b/index.html", `<!DOCTYPE html><HTML lang="ru"><HEAD data-x="1"><script>API.LMSInitialize("")</script>`,
+			`<!DOCTYPE html><HTML lang="ru"><HEAD data-x="1"><script src="../../_lms/scorm-bridge.js"></script><script>API.LMSInitialize("")</script>`},
+		{"page.htm", `<html lang="en"><header></header>`,
+			`<html lang="en"><script src="_lms/scorm-bridge.js"></script><header></header>`},
+		{"page.html", "  <!doctype html>\n<p>text",
+			"  <!doctype html><script src=\"_lms/scorm-bridge.js\"></script>\n<p>text"},
+		{"page.html", `<p>fragment`, `<script src="_lms/scorm-bridge.js"></script><p>fragment`},
+	}
+	for _, tt := range tests {
+		if got := string(InjectBridge(tt.name, []byte(tt.page))); got != tt.want {
+			t.Errorf("InjectBridge(%q, %q) = %q, want %q", tt.name, tt.page, got, tt.want)
+		}
+	}
+}
+
+func TestIsPage(t *testing.T) {
+	tests := map[string]bool{
+		"index.html": true, "a/B.HTM": true, "c.xhtml": true,
+		"app.js": false, "style.css": false, "html": false, "page.html.zip": false,
+	}
+	for name, want := range tests {
+		if got := IsPage(name); got != want {
+			t.Errorf("IsPage(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
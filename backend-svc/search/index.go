@@ -0,0 +1,298 @@
+// Package search — полнотекстовый поиск по курсам, заданиям и обсуждениям. Индекс Bleve
+// хранится на диске реплики и обновляется фоновым индексатором по событиям outbox, поэтому
+// поисковые запросы не обращаются к базе данных.
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"expvar"
+	"fmt"
+	"lmsmodule/backend-svc/config"
+	"lmsmodule/backend-svc/models"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/blevesearch/bleve/v2"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/keyword"
+	"github.com/blevesearch/bleve/v2/analysis/analyzer/standard"
+	"github.com/blevesearch/bleve/v2/mapping"
+	"github.com/blevesearch/bleve/v2/search/highlight/highlighter/html"
+	"github.com/blevesearch/bleve/v2/search/query"
+)
+
+// queueSize — сколько событий может ждать индексатора; при переполнении события
+// отбрасываются, и индекс догоняет базу при следующей полной переиндексации
+const queueSize = 1024
+
+var (
+	indexedEvents  = expvar.NewInt("search_events_indexed_total")
+	droppedEvents  = expvar.NewInt("search_events_dropped_total")
+	failedEvents   = expvar.NewInt("search_events_failed_total")
+	searchRequests = expvar.NewInt("search_queries_total")
+)
+
+// Source — данные, которые попадают в индекс; реализуется storage.Storage
+type Source interface {
+	GetCourses() ([]models.Course, error)
+	GetCourseByID(id int) (models.Course, error)
+	ListThreads(courseID int) ([]models.DiscussionThread, error)
+	GetThread(id int) (models.DiscussionThread, error)
+	ListThreadComments(threadID int) ([]models.DiscussionComment, error)
+	GetComment(id int) (models.DiscussionComment, error)
+}
+
+// Index — поисковый индекс и его индексатор. Реализует outbox.Publisher: события ставятся
+// в очередь и применяются в Run, не задерживая доставку остальным получателям.
+type Index struct {
+	index  bleve.Index
+	source Source
+	queue  chan models.OutboxEvent
+}
+
+// Open открывает индекс по cfg.Path или создает пустой. created сообщает, что индекс
+// создан заново и его нужно заполнить вызовом Rebuild.
+func Open(cfg config.SearchConfig, source Source) (idx *Index, created bool, err error) {
+	index, err := bleve.Open(cfg.Path)
+	if errors.Is(err, bleve.ErrorIndexPathDoesNotExist) {
+		index, err = bleve.New(cfg.Path, indexMapping())
+		created = true
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("open search index %s: %w", cfg.Path, err)
+	}
+	return &Index{index: index, source: source, queue: make(chan models.OutboxEvent, queueSize)}, created, nil
+}
+
+// indexMapping описывает поля документов: title и body ищутся по словам, остальные
+// хранятся для ответа и фильтрации
+func indexMapping() mapping.IndexMapping {
+	text := bleve.NewTextFieldMapping()
+	text.Analyzer = standard.Name
+	text.Store = true
+	text.IncludeTermVectors = true
+
+	kind := bleve.NewTextFieldMapping()
+	kind.Analyzer = keyword.Name
+
+	number := bleve.NewNumericFieldMapping()
+
+	doc := bleve.NewDocumentMapping()
+	doc.AddFieldMappingsAt("type", kind)
+	doc.AddFieldMappingsAt("title", text)
+	doc.AddFieldMappingsAt("body", text)
+	doc.AddFieldMappingsAt("courseId", number)
+	doc.AddFieldMappingsAt("threadId", number)
+	doc.AddFieldMappingsAt("authorId", number)
+
+	m := bleve.NewIndexMapping()
+	m.DefaultMapping = doc
+	m.DefaultAnalyzer = standard.Name
+	return m
+}
+
+func docID(kind string, id int) string {
+	return kind + ":" + strconv.Itoa(id)
+}
+
+// Publish реализует outbox.Publisher и realtime.EventSink
+func (x *Index) Publish(ctx context.Context, event models.OutboxEvent) error {
+	switch event.EventType {
+	case models.EventCourseCreated, models.EventCourseContentChanged, models.EventCommentCreated, models.EventCommentModerated:
+	default:
+		return nil
+	}
+	select {
+	case x.queue <- event:
+	default:
+		droppedEvents.Add(1)
+	}
+	return nil
+}
+
+// Run применяет события из очереди до отмены ctx
+func (x *Index) Run(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case event := <-x.queue:
+			if err := x.apply(event); err != nil {
+				failedEvents.Add(1)
+				log.Printf("Search: failed to index %s event %d: %v", event.EventType, event.ID, err)
+				continue
+			}
+			indexedEvents.Add(1)
+		}
+	}
+}
+
+func (x *Index) apply(event models.OutboxEvent) error {
+	var payload struct {
+		CourseID  int `json:"courseId"`
+		CommentID int `json:"commentId"`
+	}
+	if err := json.Unmarshal(event.Payload, &payload); err != nil {
+		return fmt.Errorf("decode payload: %w", err)
+	}
+	batch := x.index.NewBatch()
+	switch event.EventType {
+	case models.EventCourseCreated, models.EventCourseContentChanged:
+		course, err := x.source.GetCourseByID(payload.CourseID)
+		if err != nil {
+			return err
+		}
+		addCourse(batch, course)
+	default:
+		comment, err := x.source.GetComment(payload.CommentID)
+		if err != nil {
+			return err
+		}
+		thread, err := x.source.GetThread(comment.ThreadID)
+		if err != nil {
+			return err
+		}
+		addComment(batch, thread, comment)
+	}
+	return x.index.Batch(batch)
+}
+
+// Rebuild заново индексирует все курсы, задания и видимые сообщения обсуждений
+func (x *Index) Rebuild(ctx context.Context) error {
+	courses, err := x.source.GetCourses()
+	if err != nil {
+		return fmt.Errorf("list courses: %w", err)
+	}
+	documents := 0
+	for _, summary := range courses {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		course, err := x.source.GetCourseByID(summary.ID)
+		if err != nil {
+			return fmt.Errorf("get course %d: %w", summary.ID, err)
+		}
+		batch := x.index.NewBatch()
+		addCourse(batch, course)
+		threads, err := x.source.ListThreads(course.ID)
+		if err != nil {
+			return fmt.Errorf("list threads of course %d: %w", course.ID, err)
+		}
+		for _, thread := range threads {
+			comments, err := x.source.ListThreadComments(thread.ID)
+			if err != nil {
+				return fmt.Errorf("list comments of thread %d: %w", thread.ID, err)
+			}
+			for _, comment := range comments {
+				addComment(batch, thread, comment)
+			}
+		}
+		documents += batch.Size()
+		if err := x.index.Batch(batch); err != nil {
+			return fmt.Errorf("index course %d: %w", course.ID, err)
+		}
+	}
+	log.Printf("Search: indexed %d courses (%d documents)", len(courses), documents)
+	return nil
+}
+
+func addCourse(batch *bleve.Batch, course models.Course) {
+	batch.Index(docID(models.SearchCourse, course.ID), map[string]interface{}{
+		"type":     models.SearchCourse,
+		"courseId": course.ID,
+		"title":    course.VulnerabilityType,
+		"body":     course.Description,
+	})
+	for _, task := range course.Tasks {
+		batch.Index(docID(models.SearchTask, task.ID), map[string]interface{}{
+			"type":     models.SearchTask,
+			"courseId": course.ID,
+			"title":    task.Title,
+			"body":     task.Description,
+		})
+	}
+}
+
+// addComment индексирует сообщение или, если модератор его скрыл, удаляет из индекса
+func addComment(batch *bleve.Batch, thread models.DiscussionThread, comment models.DiscussionComment) {
+	id := docID(models.SearchComment, comment.ID)
+	if comment.HiddenAt != nil {
+		batch.Delete(id)
+		return
+	}
+	doc := map[string]interface{}{
+		"type":     models.SearchComment,
+		"courseId": thread.CourseID,
+		"threadId": thread.ID,
+		"title":    thread.Title,
+		"body":     comment.Body,
+	}
+	if comment.AuthorID != nil {
+		doc["authorId"] = *comment.AuthorID
+	}
+	batch.Index(id, doc)
+}
+
+// Search ищет документы типов types (пусто — всех) с учетом опечаток: точные совпадения
+// и совпадения в названии ранжируются выше нечетких и совпадений в тексте
+func (x *Index) Search(text string, types []string, limit int) ([]models.SearchHit, error) {
+	searchRequests.Add(1)
+	match := func(field string, fuzziness int, boost float64) query.Query {
+		q := bleve.NewMatchQuery(text)
+		q.SetField(field)
+		q.SetFuzziness(fuzziness)
+		q.SetBoost(boost)
+		return q
+	}
+	var q query.Query = bleve.NewDisjunctionQuery(
+		match("title", 0, 4), match("body", 0, 2), match("title", 1, 1.5), match("body", 1, 0.5))
+	if len(types) > 0 {
+		kinds := bleve.NewDisjunctionQuery()
+		for _, kind := range types {
+			term := bleve.NewTermQuery(kind)
+			term.SetField("type")
+			kinds.AddQuery(term)
+		}
+		q = bleve.NewConjunctionQuery(q, kinds)
+	}
+
+	req := bleve.NewSearchRequestOptions(q, limit, 0, false)
+	req.Fields = []string{"type", "courseId", "threadId", "authorId", "title"}
+	req.Highlight = bleve.NewHighlightWithStyle(html.Name)
+	req.Highlight.Fields = []string{"body"}
+	res, err := x.index.Search(req)
+	if err != nil {
+		return nil, fmt.Errorf("search: %w", err)
+	}
+
+	hits := make([]models.SearchHit, 0, len(res.Hits))
+	for _, doc := range res.Hits {
+		kind, id, _ := strings.Cut(doc.ID, ":")
+		hit := models.SearchHit{Type: kind, Score: doc.Score}
+		hit.ID, _ = strconv.Atoi(id)
+		hit.CourseID = intField(doc.Fields, "courseId")
+		hit.ThreadID = intField(doc.Fields, "threadId")
+		if authorID := intField(doc.Fields, "authorId"); authorID != 0 {
+			hit.AuthorID = &authorID
+		}
+		hit.Title, _ = doc.Fields["title"].(string)
+		if fragments := doc.Fragments["body"]; len(fragments) > 0 {
+			hit.Snippet = fragments[0]
+		}
+		hits = append(hits, hit)
+	}
+	return hits, nil
+}
+
+// intField читает числовое поле документа; Bleve хранит числа как float64
+func intField(fields map[string]interface{}, name string) int {
+	v, _ := fields[name].(float64)
+	return int(v)
+}
+
+// Close закрывает индекс; вызывается после остановки Run
+func (x *Index) Close() error {
+	return x.index.Close()
+}
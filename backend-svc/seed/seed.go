@@ -0,0 +1,210 @@
+package seed
+
+import (
+	"fmt"
+	"github.com/pquerna/otp/totp"
+	"golang.org/x/crypto/bcrypt"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+	"log"
+)
+
+// demoCourses — демонстрационный каталог курсов
+var demoCourses = []models.Course{
+	{
+		VulnerabilityType: "SQL Injection",
+		Description:       "Learn how unsanitized input reaches SQL queries and how to prevent it with parameterized queries.",
+		Tasks: []models.Task{
+			{Title: "Basics of SQL Injection", Description: "Bypass a login form using a classic tautology payload.", Difficulty: "easy"},
+			{Title: "UNION-based extraction", Description: "Extract data from another table via UNION SELECT.", Difficulty: "medium"},
+			{Title: "Blind SQL Injection", Description: "Infer data using boolean and time-based techniques.", Difficulty: "hard"},
+		},
+	},
+	{
+		VulnerabilityType: "XSS",
+		Description:       "Cross-site scripting: reflected, stored and DOM-based attacks and output encoding.",
+		Tasks: []models.Task{
+			{Title: "Reflected XSS", Description: "Inject a script through a search parameter.", Difficulty: "easy"},
+			{Title: "Stored XSS", Description: "Persist a payload in a comment that runs for every visitor.", Difficulty: "medium"},
+			{Title: "DOM-based XSS", Description: "Abuse an unsafe innerHTML sink fed from location.hash.", Difficulty: "hard"},
+		},
+	},
+	{
+		VulnerabilityType: "CSRF",
+		Description:       "Cross-site request forgery and defenses: SameSite cookies and anti-CSRF tokens.",
+		Tasks: []models.Task{
+			{Title: "Forging a state-changing request", Description: "Change a victim's email with an auto-submitting form.", Difficulty: "medium"},
+			{Title: "Bypassing weak token checks", Description: "Exploit a token that is validated only when present.", Difficulty: "hard"},
+		},
+	},
+	{
+		VulnerabilityType: "SSRF",
+		Description:       "Server-side request forgery against internal services and cloud metadata endpoints.",
+		Tasks: []models.Task{
+			{Title: "Reaching internal services", Description: "Use a URL preview feature to scan the internal network.", Difficulty: "medium"},
+			{Title: "Cloud metadata theft", Description: "Retrieve instance credentials from the metadata service.", Difficulty: "hard"},
+		},
+	},
+	{
+		VulnerabilityType: "Broken Access Control",
+		Description:       "IDOR, missing function-level authorization and privilege escalation.",
+		Tasks: []models.Task{
+			{Title: "Insecure direct object reference", Description: "Read another user's invoice by changing its ID.", Difficulty: "easy"},
+			{Title: "Vertical privilege escalation", Description: "Call an admin-only endpoint as a regular user.", Difficulty: "medium"},
+		},
+	},
+}
+
+// demoUser — демонстрационная учетная запись и выполненные ею задания
+type demoUser struct {
+	Username  string
+	Email     string
+	FullName  string
+	IsAdmin   bool
+	Completed map[string][]string // тип уязвимости курса -> названия выполненных заданий
+}
+
+var demoUsers = []demoUser{
+	{
+		Username: "demo-admin",
+		Email:    "demo-admin@lms.local",
+		FullName: "Demo Administrator",
+		IsAdmin:  true,
+	},
+	{
+		Username: "alice",
+		Email:    "alice@lms.local",
+		FullName: "Alice Ivanova",
+		Completed: map[string][]string{
+			"SQL Injection": {"Basics of SQL Injection", "UNION-based extraction", "Blind SQL Injection"},
+			"XSS":           {"Reflected XSS"},
+		},
+	},
+	{
+		Username: "bob",
+		Email:    "bob@lms.local",
+		FullName: "Bob Petrov",
+		Completed: map[string][]string{
+			"SQL Injection":         {"Basics of SQL Injection"},
+			"Broken Access Control": {"Insecure direct object reference", "Vertical privilege escalation"},
+		},
+	},
+	{
+		Username: "carol",
+		Email:    "carol@lms.local",
+		FullName: "Carol Smirnova",
+	},
+}
+
+// Demo идемпотентно наполняет хранилище демонстрационными курсами, пользователями и прогрессом.
+// Существующие курсы (по типу уязвимости) и пользователи (по логину) не изменяются,
+// поэтому повторный запуск безопасен. Все демо-пользователи получают пароль password без 2FA.
+func Demo(store storage.Storage, password string) error {
+	courses, err := seedCourses(store)
+	if err != nil {
+		return err
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("hash demo password: %w", err)
+	}
+
+	for _, du := range demoUsers {
+		user, err := store.GetUserByUsername(du.Username)
+		if err != nil {
+			if err := createDemoUser(store, du, string(hash)); err != nil {
+				return err
+			}
+			if user, err = store.GetUserByUsername(du.Username); err != nil {
+				return fmt.Errorf("load demo user %s: %w", du.Username, err)
+			}
+			log.Printf("Seed: created user %s", du.Username)
+		}
+
+		for courseType, titles := range du.Completed {
+			course, ok := courses[courseType]
+			if !ok {
+				continue
+			}
+			for _, task := range course.Tasks {
+				if !containsString(titles, task.Title) {
+					continue
+				}
+				if err := store.CompleteTask(user.ID, task.ID); err != nil {
+					return fmt.Errorf("complete task %q for %s: %w", task.Title, du.Username, err)
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// seedCourses создает отсутствующие демо-курсы и возвращает все демо-курсы с заданиями
+func seedCourses(store storage.Storage) (map[string]models.Course, error) {
+	existing, err := store.GetCourses()
+	if err != nil {
+		return nil, fmt.Errorf("list courses: %w", err)
+	}
+
+	ids := make(map[string]int, len(existing))
+	for _, course := range existing {
+		ids[course.VulnerabilityType] = course.ID
+	}
+
+	courses := make(map[string]models.Course, len(demoCourses))
+	for _, demo := range demoCourses {
+		id, ok := ids[demo.VulnerabilityType]
+		if !ok {
+			if id, err = store.CreateCourse(demo); err != nil {
+				return nil, fmt.Errorf("create course %q: %w", demo.VulnerabilityType, err)
+			}
+			log.Printf("Seed: created course %s", demo.VulnerabilityType)
+		}
+
+		course, err := store.GetCourseByID(id)
+		if err != nil {
+			return nil, fmt.Errorf("load course %q: %w", demo.VulnerabilityType, err)
+		}
+		courses[demo.VulnerabilityType] = course
+	}
+	return courses, nil
+}
+
+func createDemoUser(store storage.Storage, du demoUser, passwordHash string) error {
+	key, err := totp.Generate(totp.GenerateOpts{Issuer: "LMS System", AccountName: du.Username, SecretSize: 20})
+	if err != nil {
+		return fmt.Errorf("generate 2FA key: %w", err)
+	}
+
+	err = store.CreateUser(models.User{
+		Username:     du.Username,
+		PasswordHash: passwordHash,
+		Email:        du.Email,
+		FullName:     du.FullName,
+		TOTPSecret:   key.Secret(),
+		IsActive:     true,
+	})
+	if err != nil {
+		return fmt.Errorf("create demo user %s: %w", du.Username, err)
+	}
+
+	if du.IsAdmin {
+		user, err := store.GetUserByUsername(du.Username)
+		if err != nil {
+			return err
+		}
+		return store.PromoteToAdmin(user.ID)
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/ipaccess"
+	"lmsmodule/backend-svc/models"
+	"lmsmodule/backend-svc/storage"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Ключи runtime-параметров
+const (
+	KeyRegistrationOpen     = "registration_open"
+	KeyRegistrationApproval = "registration_approval"
+	KeyMaintenanceBanner    = "maintenance_banner"
+	KeyRateLimitRPM         = "rate_limit_rpm"
+	KeyRateLimitBurst       = "rate_limit_burst"
+	KeyLabQuotaPerUser      = "lab_quota_per_user"
+	KeyMaintenanceMode      = "maintenance_mode"
+	KeyMaintenanceText      = "maintenance_message"
+	KeyReadOnlyMode         = "read_only_mode"
+	KeySingleSession        = "single_session"
+	KeyIPAllowlist          = "ip_allowlist"
+	KeyAdminIPAllowlist     = "admin_ip_allowlist"
+	KeyBlockedCountries     = "blocked_countries"
+)
+
+var (
+	// ErrUnknownSetting возвращается при попытке изменить неизвестный параметр
+	ErrUnknownSetting = errors.New("unknown setting")
+	// ErrInvalidValue возвращается, если значение не прошло проверку
+	ErrInvalidValue = errors.New("invalid setting value")
+)
+
+// Definition описывает допустимый runtime-параметр
+type Definition struct {
+	Key         string
+	Default     string
+	Description string
+	Validate    func(value string) error
+}
+
+func validateBool(value string) error {
+	if _, err := strconv.ParseBool(value); err != nil {
+		return fmt.Errorf("must be true or false")
+	}
+	return nil
+}
+
+func validatePositiveInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n <= 0 {
+		return fmt.Errorf("must be a positive integer")
+	}
+	return nil
+}
+
+func validateNonNegativeInt(value string) error {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return fmt.Errorf("must be a non-negative integer")
+	}
+	return nil
+}
+
+// Service хранит кэш runtime-параметров и синхронизирует его с хранилищем
+type Service struct {
+	store       storage.Storage
+	definitions map[string]Definition
+
+	mu     sync.RWMutex
+	values map[string]models.Setting
+}
+
+// New создает сервис параметров; defaults переопределяют значения по умолчанию
+// из статической конфигурации (например, лимиты запросов)
+func New(store storage.Storage, defaults map[string]string) *Service {
+	definitions := []Definition{
+		{Key: KeyRegistrationOpen, Default: "true", Description: "Allow self-registration", Validate: validateBool},
+		{Key: KeyRegistrationApproval, Default: "false", Description: "Hold self-registrations until an admin approves them", Validate: validateBool},
+		{Key: KeyMaintenanceBanner, Default: "", Description: "Banner text shown to all users", Validate: func(string) error { return nil }},
+		{Key: KeyRateLimitRPM, Default: "600", Description: "Requests per minute per client IP", Validate: validatePositiveInt},
+		{Key: KeyRateLimitBurst, Default: "100", Description: "Request burst size per client IP", Validate: validatePositiveInt},
+		{Key: KeyLabQuotaPerUser, Default: "2", Description: "Concurrent lab environments per user (0 disables labs)", Validate: validateNonNegativeInt},
+		{Key: KeyMaintenanceMode, Default: "false", Description: "Reject non-admin requests with 503", Validate: validateBool},
+		{Key: KeyMaintenanceText, Default: "The platform is undergoing maintenance. Please try again later.", Description: "Message returned while in maintenance mode", Validate: func(string) error { return nil }},
+		{Key: KeyReadOnlyMode, Default: "false", Description: "Reject requests that change data with 503 while reads keep working", Validate: validateBool},
+		{Key: KeySingleSession, Default: "false", Description: "Allow one active session per user; a new sign-in ends the previous one", Validate: validateBool},
+		{Key: KeyIPAllowlist, Default: "", Description: "IP addresses and CIDR ranges allowed to use the API, comma-separated (empty allows any)", Validate: ipaccess.Validate},
+		{Key: KeyAdminIPAllowlist, Default: "", Description: "IP addresses and CIDR ranges allowed to use the admin API, comma-separated (empty allows any)", Validate: ipaccess.Validate},
+		{Key: KeyBlockedCountries, Default: "", Description: "Two-letter codes of countries denied access, comma-separated; the country comes from the CF-IPCountry or X-Country-Code header set by a trusted proxy (security.trusted_proxies)", Validate: ipaccess.ValidateCountries},
+	}
+
+	s := &Service{
+		store:       store,
+		definitions: make(map[string]Definition, len(definitions)),
+		values:      make(map[string]models.Setting),
+	}
+	for _, def := range definitions {
+		if value, ok := defaults[def.Key]; ok {
+			def.Default = value
+		}
+		s.definitions[def.Key] = def
+	}
+	return s
+}
+
+// Register добавляет определение параметра; используется другими подсистемами
+func (s *Service) Register(def Definition) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.definitions[def.Key] = def
+}
+
+// Reload перечитывает параметры из хранилища; вызывается периодически,
+// чтобы изменения, сделанные на других репликах, применялись без передеплоя
+func (s *Service) Reload() error {
+	stored, err := s.store.GetSettings()
+	if err != nil {
+		return fmt.Errorf("load settings: %w", err)
+	}
+
+	values := make(map[string]models.Setting, len(stored))
+	for _, setting := range stored {
+		values[setting.Key] = setting
+	}
+
+	s.mu.Lock()
+	s.values = values
+	s.mu.Unlock()
+	return nil
+}
+
+// Get возвращает значение параметра или его значение по умолчанию
+func (s *Service) Get(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if setting, ok := s.values[key]; ok {
+		return setting.Value
+	}
+	return s.definitions[key].Default
+}
+
+// Bool возвращает логическое значение параметра
+func (s *Service) Bool(key string) bool {
+	value, err := strconv.ParseBool(s.Get(key))
+	if err != nil {
+		value, _ = strconv.ParseBool(s.definitions[key].Default)
+	}
+	return value
+}
+
+// Int возвращает целочисленное значение параметра
+func (s *Service) Int(key string) int {
+	value, err := strconv.Atoi(s.Get(key))
+	if err != nil {
+		value, _ = strconv.Atoi(s.definitions[key].Default)
+	}
+	return value
+}
+
+// Set проверяет и сохраняет новое значение параметра, сразу обновляя локальный кэш
+func (s *Service) Set(key, value string, updatedBy int) error {
+	s.mu.RLock()
+	def, ok := s.definitions[key]
+	s.mu.RUnlock()
+	if !ok {
+		return ErrUnknownSetting
+	}
+
+	if err := def.Validate(value); err != nil {
+		return fmt.Errorf("%w for %s: %v", ErrInvalidValue, key, err)
+	}
+
+	setting := models.Setting{Key: key, Value: value, UpdatedBy: updatedBy}
+	if err := s.store.UpsertSetting(setting); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.values[key] = setting
+	s.mu.Unlock()
+	return nil
+}
+
+// All возвращает все известные параметры с текущими значениями, отсортированные по ключу
+func (s *Service) All() []models.Setting {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	all := make([]models.Setting, 0, len(s.definitions))
+	for key, def := range s.definitions {
+		setting, ok := s.values[key]
+		if !ok {
+			setting = models.Setting{Key: key, Value: def.Default}
+		}
+		all = append(all, setting)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].Key < all[j].Key })
+	return all
+}
@@ -0,0 +1,213 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+const accessExpiryColumns = "u.id, u.username, u.email, u.full_name, u.is_active, e.expires_at, e.deactivated_at"
+
+const accessExpiryFrom = " FROM user_access_expiry e JOIN users u ON u.id = e.user_id"
+
+func scanAccessExpiry(row rowScanner) (models.AccessExpiry, error) {
+	var expiry models.AccessExpiry
+	var deactivatedAt sql.NullTime
+	err := row.Scan(&expiry.UserID, &expiry.Username, &expiry.Email, &expiry.FullName, &expiry.IsActive,
+		&expiry.ExpiresAt, &deactivatedAt)
+	if deactivatedAt.Valid {
+		expiry.DeactivatedAt = &deactivatedAt.Time
+	}
+	return expiry, err
+}
+
+func queryAccessExpiries(ctx context.Context, q rowsQueryer, where string, args ...interface{}) ([]models.AccessExpiry, error) {
+	rows, err := q.QueryContext(ctx, "SELECT "+accessExpiryColumns+accessExpiryFrom+where+" ORDER BY e.expires_at, u.id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("query access expiries: %w", err)
+	}
+	defer rows.Close()
+
+	expiries := []models.AccessExpiry{}
+	for rows.Next() {
+		expiry, err := scanAccessExpiry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan access expiry: %w", err)
+		}
+		expiries = append(expiries, expiry)
+	}
+	return expiries, rows.Err()
+}
+
+// ListAccessExpiries возвращает пользователей со сроком доступа, ближайшие сроки первыми
+func (s *DBStorage) ListAccessExpiries() ([]models.AccessExpiry, error) {
+	ctx, done := s.startQuery("ListAccessExpiries")
+	defer done()
+
+	return queryAccessExpiries(ctx, s.reader(), "")
+}
+
+// setStatusInTx меняет активность пользователя так же, как UpdateUserStatus, но в чужой транзакции
+func setStatusInTx(ctx context.Context, tx *sql.Tx, userID int, isActive bool) error {
+	if err := bumpUserVersion(ctx, tx, userID, AnyVersion); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET is_active = ? WHERE id = ?", isActive, userID); err != nil {
+		return err
+	}
+	return insertOutboxEvent(ctx, tx, models.EventUserStatusChanged, userID, map[string]interface{}{
+		"userId":   userID,
+		"isActive": isActive,
+	})
+}
+
+// saveAccessExpiry записывает новый срок доступа; если учетная запись была отключена по сроку,
+// а новый срок еще не наступил, она снова активируется
+func (s *DBStorage) saveAccessExpiry(ctx context.Context, tx *sql.Tx, userID int, expiresAt, now time.Time) error {
+	var deactivatedAt sql.NullTime
+	err := tx.QueryRowContext(ctx, "SELECT deactivated_at FROM user_access_expiry WHERE user_id = ?", userID).Scan(&deactivatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+	reactivate := deactivatedAt.Valid && expiresAt.After(now)
+
+	query := "INSERT INTO user_access_expiry (user_id, expires_at, deactivated_at, updated_at) VALUES (?, ?, ?, ?)" +
+		s.onConflictUpdate([]string{"user_id"}, "expires_at", "deactivated_at", "updated_at")
+	var keep interface{}
+	if deactivatedAt.Valid && !reactivate {
+		keep = deactivatedAt.Time
+	}
+	if _, err := tx.ExecContext(ctx, query, userID, expiresAt.UTC(), keep, now.UTC()); err != nil {
+		return err
+	}
+	if reactivate {
+		return setStatusInTx(ctx, tx, userID, true)
+	}
+	return nil
+}
+
+// SetAccessExpiry задает или снимает срок доступа пользователя
+func (s *DBStorage) SetAccessExpiry(userID int, expiresAt *time.Time) error {
+	ctx, done := s.startQuery("SetAccessExpiry")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		exists, err := rowExists(ctx, tx, "users", userID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return ErrUserNotFound
+		}
+		if expiresAt == nil {
+			_, err := tx.ExecContext(ctx, "DELETE FROM user_access_expiry WHERE user_id = ?", userID)
+			return err
+		}
+		return s.saveAccessExpiry(ctx, tx, userID, *expiresAt, time.Now())
+	})
+	if err != nil && !errors.Is(err, ErrUserNotFound) {
+		err = fmt.Errorf("set access expiry: %w", err)
+	}
+	return err
+}
+
+// ExtendAccessExpiry продлевает срок доступа нескольких пользователей в одной транзакции
+func (s *DBStorage) ExtendAccessExpiry(userIDs []int, until *time.Time, days int) ([]models.AccessExpiry, []int, error) {
+	ctx, done := s.startQuery("ExtendAccessExpiry")
+	defer done()
+
+	var extended []models.AccessExpiry
+	var skipped []int
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now()
+		extended, skipped = []models.AccessExpiry{}, []int{}
+		seen := make(map[int]bool, len(userIDs))
+		for _, userID := range userIDs {
+			if seen[userID] {
+				continue
+			}
+			seen[userID] = true
+
+			var current time.Time
+			err := tx.QueryRowContext(ctx, "SELECT expires_at FROM user_access_expiry WHERE user_id = ?", userID).Scan(&current)
+			if errors.Is(err, sql.ErrNoRows) {
+				skipped = append(skipped, userID)
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			if err := s.saveAccessExpiry(ctx, tx, userID, extendedExpiry(current, now, until, days), now); err != nil {
+				return err
+			}
+			expiry, err := scanAccessExpiry(tx.QueryRowContext(ctx,
+				"SELECT "+accessExpiryColumns+accessExpiryFrom+" WHERE e.user_id = ?", userID))
+			if err != nil {
+				return err
+			}
+			extended = append(extended, expiry)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("extend access expiry: %w", err)
+	}
+	return extended, skipped, nil
+}
+
+// extendedExpiry вычисляет новый срок доступа: until, если он задан, иначе days дней от текущего
+// срока или от now, если срок уже прошел
+func extendedExpiry(current, now time.Time, until *time.Time, days int) time.Time {
+	if until != nil {
+		return *until
+	}
+	if current.Before(now) {
+		current = now
+	}
+	return current.AddDate(0, 0, days)
+}
+
+// DeactivateExpiredAccounts деактивирует пользователей с наступившим сроком доступа. Отметка
+// deactivated_at ставится условным UPDATE, поэтому при нескольких репликах каждого пользователя
+// деактивирует и возвращает ровно одна из них.
+func (s *DBStorage) DeactivateExpiredAccounts(now time.Time) ([]models.AccessExpiry, error) {
+	ctx, done := s.startQuery("DeactivateExpiredAccounts")
+	defer done()
+
+	due, err := queryAccessExpiries(ctx, s.DB,
+		" WHERE e.expires_at <= ? AND e.deactivated_at IS NULL AND u.is_active = ?", now.UTC(), true)
+	if err != nil {
+		return nil, err
+	}
+
+	deactivated := []models.AccessExpiry{}
+	for _, expiry := range due {
+		var claimed bool
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			res, err := tx.ExecContext(ctx,
+				"UPDATE user_access_expiry SET deactivated_at = ? WHERE user_id = ? AND deactivated_at IS NULL AND expires_at <= ?",
+				now.UTC(), expiry.UserID, now.UTC())
+			if err != nil {
+				return err
+			}
+			affected, err := res.RowsAffected()
+			if err != nil || affected == 0 {
+				return err
+			}
+			claimed = true
+			return setStatusInTx(ctx, tx, expiry.UserID, false)
+		})
+		if err != nil {
+			return deactivated, fmt.Errorf("deactivate user %d: %w", expiry.UserID, err)
+		}
+		if claimed {
+			at := now.UTC()
+			expiry.IsActive, expiry.DeactivatedAt = false, &at
+			deactivated = append(deactivated, expiry)
+		}
+	}
+	return deactivated, nil
+}
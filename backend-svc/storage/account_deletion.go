@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ScheduleAccountDeletion деактивирует учетную запись и планирует ее окончательное удаление на purgeAt
+func (s *DBStorage) ScheduleAccountDeletion(userID int, purgeAt time.Time) error {
+	ctx, done := s.startQuery("ScheduleAccountDeletion")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpUserVersion(ctx, tx, userID, AnyVersion); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE users SET is_active = ?, deletion_scheduled_at = ? WHERE id = ?", false, purgeAt.UTC(), userID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE trusted_devices SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL", time.Now().UTC(), userID); err != nil {
+			return err
+		}
+
+		return insertOutboxEvent(ctx, tx, models.EventUserStatusChanged, userID, map[string]interface{}{
+			"userId":   userID,
+			"isActive": false,
+			"reason":   "deletion_scheduled",
+			"purgeAt":  purgeAt.UTC(),
+		})
+	})
+}
+
+// CancelAccountDeletion отменяет запланированное удаление и снова активирует учетную запись.
+// Для учетных записей без запланированного удаления ничего не делает.
+func (s *DBStorage) CancelAccountDeletion(userID int) error {
+	ctx, done := s.startQuery("CancelAccountDeletion")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			"UPDATE users SET is_active = ?, deletion_scheduled_at = NULL, version = version + 1 "+
+				"WHERE id = ? AND deletion_scheduled_at IS NOT NULL", true, userID)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+			return err
+		}
+
+		return insertOutboxEvent(ctx, tx, models.EventUserStatusChanged, userID, map[string]interface{}{
+			"userId":   userID,
+			"isActive": true,
+			"reason":   "deletion_cancelled",
+		})
+	})
+}
+
+// PurgeDeletedAccounts окончательно удаляет учетные записи, срок удаления которых наступил до now.
+// Связанные данные удаляются каскадно.
+func (s *DBStorage) PurgeDeletedAccounts(now time.Time) (int64, error) {
+	ctx, done := s.startQuery("PurgeDeletedAccounts")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT id FROM users WHERE deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", now.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("select accounts to purge: %w", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	var purged int64
+	for _, id := range ids {
+		deleted := false
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			// Повторная проверка срока: пользователь мог войти и отменить удаление после выборки
+			res, err := tx.ExecContext(ctx,
+				"DELETE FROM users WHERE id = ? AND deletion_scheduled_at IS NOT NULL AND deletion_scheduled_at <= ?", id, now.UTC())
+			if err != nil {
+				return err
+			}
+			affected, err := res.RowsAffected()
+			if err != nil || affected == 0 {
+				return err
+			}
+			deleted = true
+			return insertOutboxEvent(ctx, tx, models.EventUserDeleted, id, map[string]interface{}{
+				"userId": id,
+				"reason": "self_service",
+			})
+		})
+		if err != nil {
+			return purged, fmt.Errorf("purge account %d: %w", id, err)
+		}
+		if deleted {
+			purged++
+		}
+	}
+	return purged, nil
+}
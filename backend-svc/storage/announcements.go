@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+	"time"
+)
+
+// ErrAnnouncementNotFound — объявления нет
+var ErrAnnouncementNotFound = errors.New("announcement not found")
+
+const announcementColumns = "id, course_id, title, body, author_id, publish_at, expires_at, notified_at, created_at, updated_at"
+
+// CreateAnnouncement сохраняет объявление
+func (s *DBStorage) CreateAnnouncement(a models.Announcement) (models.Announcement, error) {
+	ctx, done := s.startQuery("CreateAnnouncement")
+	defer done()
+
+	now := time.Now().UTC()
+	a.CreatedAt, a.UpdatedAt = now, now
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if a.CourseID != nil {
+			if err := courseExists(ctx, tx, *a.CourseID); err != nil {
+				return err
+			}
+		}
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO announcements (course_id, title, body, author_id, publish_at, expires_at, created_at, updated_at) "+
+				"VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			a.CourseID, a.Title, a.Body, a.AuthorID, a.PublishAt.UTC(), utcOrNil(a.ExpiresAt), now, now)
+		if err != nil {
+			return fmt.Errorf("insert announcement: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get announcement id: %w", err)
+		}
+		a.ID = int(id)
+		return nil
+	})
+	return a, err
+}
+
+// GetAnnouncement возвращает объявление по ID
+func (s *DBStorage) GetAnnouncement(id int) (models.Announcement, error) {
+	ctx, done := s.startQuery("GetAnnouncement")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+announcementColumns+" FROM announcements WHERE id = ?")
+	if err != nil {
+		return models.Announcement{}, err
+	}
+	a, err := scanAnnouncement(stmt.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Announcement{}, ErrAnnouncementNotFound
+	}
+	return a, err
+}
+
+// UpdateAnnouncement меняет курс, текст и сроки объявления
+func (s *DBStorage) UpdateAnnouncement(a models.Announcement) (models.Announcement, error) {
+	ctx, done := s.startQuery("UpdateAnnouncement")
+	defer done()
+
+	var updated models.Announcement
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if a.CourseID != nil {
+			if err := courseExists(ctx, tx, *a.CourseID); err != nil {
+				return err
+			}
+		}
+		res, err := tx.ExecContext(ctx,
+			"UPDATE announcements SET course_id = ?, title = ?, body = ?, publish_at = ?, expires_at = ?, updated_at = ? "+
+				"WHERE id = ?",
+			a.CourseID, a.Title, a.Body, a.PublishAt.UTC(), utcOrNil(a.ExpiresAt), time.Now().UTC(), a.ID)
+		if err != nil {
+			return fmt.Errorf("update announcement: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrAnnouncementNotFound
+		}
+		updated, err = scanAnnouncement(tx.QueryRowContext(ctx, "SELECT "+announcementColumns+" FROM announcements WHERE id = ?", a.ID))
+		return err
+	})
+	return updated, err
+}
+
+// DeleteAnnouncement удаляет объявление
+func (s *DBStorage) DeleteAnnouncement(id int) error {
+	ctx, done := s.startQuery("DeleteAnnouncement")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM announcements WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete announcement: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrAnnouncementNotFound
+	}
+	return nil
+}
+
+// ListAnnouncements возвращает объявления по фильтру, новые первыми
+func (s *DBStorage) ListAnnouncements(filter models.AnnouncementFilter) ([]models.Announcement, error) {
+	ctx, done := s.startQuery("ListAnnouncements")
+	defer done()
+
+	var conditions []string
+	var args []interface{}
+	if filter.CourseID != nil {
+		if filter.IncludeGlobal {
+			conditions = append(conditions, "(course_id = ? OR course_id IS NULL)")
+		} else {
+			conditions = append(conditions, "course_id = ?")
+		}
+		args = append(args, *filter.CourseID)
+	}
+	if filter.ActiveAt != nil {
+		at := filter.ActiveAt.UTC()
+		conditions = append(conditions, "publish_at <= ? AND (expires_at IS NULL OR expires_at > ?)")
+		args = append(args, at, at)
+	}
+	if filter.AuthorID != 0 {
+		conditions = append(conditions, "author_id = ?")
+		args = append(args, filter.AuthorID)
+	}
+
+	query := "SELECT " + announcementColumns + " FROM announcements"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY publish_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query announcements: %w", err)
+	}
+	defer rows.Close()
+
+	announcements := []models.Announcement{}
+	for rows.Next() {
+		a, err := scanAnnouncement(rows)
+		if err != nil {
+			return nil, err
+		}
+		announcements = append(announcements, a)
+	}
+	return announcements, rows.Err()
+}
+
+// ClaimDueAnnouncements отмечает разосланными опубликованные к now и не истекшие объявления.
+// Условие notified_at IS NULL в UPDATE гарантирует, что объявление заберет только один экземпляр.
+func (s *DBStorage) ClaimDueAnnouncements(now time.Time) ([]models.Announcement, error) {
+	ctx, done := s.startQuery("ClaimDueAnnouncements")
+	defer done()
+
+	now = now.UTC()
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT "+announcementColumns+" FROM announcements "+
+			"WHERE notified_at IS NULL AND publish_at <= ? AND (expires_at IS NULL OR expires_at > ?) ORDER BY publish_at",
+		now, now)
+	if err != nil {
+		return nil, fmt.Errorf("query due announcements: %w", err)
+	}
+	var due []models.Announcement
+	for rows.Next() {
+		a, err := scanAnnouncement(rows)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		due = append(due, a)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	claimed := []models.Announcement{}
+	for _, a := range due {
+		res, err := s.DB.ExecContext(ctx,
+			"UPDATE announcements SET notified_at = ? WHERE id = ? AND notified_at IS NULL", now, a.ID)
+		if err != nil {
+			return claimed, fmt.Errorf("claim announcement %d: %w", a.ID, err)
+		}
+		if n, _ := res.RowsAffected(); n == 1 {
+			a.NotifiedAt = &now
+			claimed = append(claimed, a)
+		}
+	}
+	return claimed, nil
+}
+
+func scanAnnouncement(row rowScanner) (models.Announcement, error) {
+	var a models.Announcement
+	var courseID, authorID sql.NullInt64
+	var expiresAt, notifiedAt sql.NullTime
+	err := row.Scan(&a.ID, &courseID, &a.Title, &a.Body, &authorID, &a.PublishAt, &expiresAt, &notifiedAt,
+		&a.CreatedAt, &a.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return a, err
+		}
+		return a, fmt.Errorf("scan announcement: %w", err)
+	}
+	if courseID.Valid {
+		id := int(courseID.Int64)
+		a.CourseID = &id
+	}
+	if authorID.Valid {
+		id := int(authorID.Int64)
+		a.AuthorID = &id
+	}
+	if expiresAt.Valid {
+		a.ExpiresAt = &expiresAt.Time
+	}
+	if notifiedAt.Valid {
+		a.NotifiedAt = &notifiedAt.Time
+	}
+	return a, nil
+}
+
+// utcOrNil приводит необязательное время к UTC для записи в базу
+func utcOrNil(t *time.Time) interface{} {
+	if t == nil {
+		return nil
+	}
+	return t.UTC()
+}
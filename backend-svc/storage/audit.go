@@ -0,0 +1,217 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+	"time"
+)
+
+// Типы целей журнала действий, для которых снимается состояние до и после изменения
+const (
+	AuditTargetUser   = "user"
+	AuditTargetCourse = "course"
+)
+
+// RecordAuditEntry добавляет запись в журнал действий; имя исполнителя сохраняется на момент действия
+func (s *DBStorage) RecordAuditEntry(entry models.AuditEntry) error {
+	ctx, done := s.startQuery("RecordAuditEntry")
+	defer done()
+
+	var changes interface{}
+	if len(entry.Changes) > 0 {
+		data, err := json.Marshal(entry.Changes)
+		if err != nil {
+			return fmt.Errorf("marshal audit changes: %w", err)
+		}
+		changes = string(data)
+	}
+	_, err := s.DB.ExecContext(ctx, `
+		INSERT INTO audit_log (actor_id, actor_username, action, target_type, target_id, changes, status, ip, request_id, created_at)
+		SELECT ?, COALESCE((SELECT username FROM users WHERE id = ?), ''), ?, ?, ?, ?, ?, ?, ?, ?`,
+		entry.ActorID, entry.ActorID, entry.Action, entry.TargetType, entry.TargetID, changes,
+		entry.Status, entry.IP, entry.RequestID, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("insert audit entry: %w", err)
+	}
+	return nil
+}
+
+// ListAuditEntries возвращает записи журнала действий, новые первыми
+func (s *DBStorage) ListAuditEntries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	ctx, done := s.startQuery("ListAuditEntries")
+	defer done()
+
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.ActorID != 0 {
+		conditions = append(conditions, "actor_id = ?")
+		args = append(args, filter.ActorID)
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, filter.Action)
+	}
+	if filter.TargetType != "" {
+		conditions = append(conditions, "target_type = ?")
+		args = append(args, filter.TargetType)
+	}
+	if filter.TargetID != 0 {
+		conditions = append(conditions, "target_id = ?")
+		args = append(args, filter.TargetID)
+	}
+	if !filter.From.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.From.UTC())
+	}
+	if !filter.To.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.To.UTC())
+	}
+	if filter.Before != 0 {
+		conditions = append(conditions, "id < ?")
+		args = append(args, filter.Before)
+	}
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT id, actor_id, actor_username, action, target_type, target_id, changes, status, ip, request_id, created_at
+		FROM audit_log WHERE `+strings.Join(conditions, " AND ")+` ORDER BY id DESC LIMIT ?`,
+		append(args, filter.Limit)...)
+	if err != nil {
+		return nil, fmt.Errorf("query audit log: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.AuditEntry{}
+	for rows.Next() {
+		var entry models.AuditEntry
+		var actorID, targetID sql.NullInt64
+		var changes sql.NullString
+		if err := rows.Scan(&entry.ID, &actorID, &entry.ActorUsername, &entry.Action, &entry.TargetType, &targetID,
+			&changes, &entry.Status, &entry.IP, &entry.RequestID, &entry.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan audit entry: %w", err)
+		}
+		if actorID.Valid {
+			id := int(actorID.Int64)
+			entry.ActorID = &id
+		}
+		if targetID.Valid {
+			id := int(targetID.Int64)
+			entry.TargetID = &id
+		}
+		if changes.Valid && changes.String != "" {
+			if err := json.Unmarshal([]byte(changes.String), &entry.Changes); err != nil {
+				return nil, fmt.Errorf("unmarshal audit changes %d: %w", entry.ID, err)
+			}
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// AuditSnapshot возвращает отслеживаемые поля пользователя или курса для сравнения до и после
+// изменения; nil — цели нет или ее тип не отслеживается
+func (s *DBStorage) AuditSnapshot(targetType string, targetID int) (map[string]interface{}, error) {
+	ctx, done := s.startQuery("AuditSnapshot")
+	defer done()
+
+	var snapshot map[string]interface{}
+	var err error
+	switch targetType {
+	case AuditTargetUser:
+		snapshot, err = userAuditSnapshot(ctx, s.DB, targetID)
+	case AuditTargetCourse:
+		snapshot, err = courseAuditSnapshot(ctx, s.DB, targetID)
+	default:
+		return nil, nil
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("audit snapshot of %s %d: %w", targetType, targetID, err)
+	}
+	return snapshot, nil
+}
+
+func userAuditSnapshot(ctx context.Context, q queryer, userID int) (map[string]interface{}, error) {
+	var username, email, fullName string
+	var isAdmin, isActive, twoFactor, mustChangePassword, singleSession bool
+	var orgID int
+	var managerID sql.NullInt64
+	var accessExpiresAt sql.NullTime
+	err := q.QueryRowContext(ctx, `
+		SELECT u.username, u.email, u.full_name, u.is_admin, u.is_active, u.is_2fa_enabled, u.must_change_password,
+			u.single_session, u.organization_id,
+			(SELECT m.manager_id FROM user_managers m WHERE m.user_id = u.id),
+			(SELECT e.expires_at FROM user_access_expiry e WHERE e.user_id = u.id)
+		FROM users u WHERE u.id = ?`, userID).Scan(&username, &email, &fullName, &isAdmin, &isActive, &twoFactor,
+		&mustChangePassword, &singleSession, &orgID, &managerID, &accessExpiresAt)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := map[string]interface{}{
+		"username":           username,
+		"email":              email,
+		"fullName":           fullName,
+		"isAdmin":            isAdmin,
+		"isActive":           isActive,
+		"is2faEnabled":       twoFactor,
+		"mustChangePassword": mustChangePassword,
+		"singleSession":      singleSession,
+		"organizationId":     orgID,
+		"managerId":          nil,
+		"accessExpiresAt":    nil,
+	}
+	if managerID.Valid {
+		snapshot["managerId"] = managerID.Int64
+	}
+	if accessExpiresAt.Valid {
+		snapshot["accessExpiresAt"] = accessExpiresAt.Time.UTC()
+	}
+	return snapshot, nil
+}
+
+func courseAuditSnapshot(ctx context.Context, q rowsQueryer, courseID int) (map[string]interface{}, error) {
+	var title, description string
+	var orgID int
+	var periodMonths, reminderDays sql.NullInt64
+	err := q.QueryRowContext(ctx, `
+		SELECT c.vulnerability_type, c.description, c.organization_id, r.period_months, r.reminder_days
+		FROM courses c LEFT JOIN course_recertification r ON r.course_id = c.id
+		WHERE c.id = ?`, courseID).Scan(&title, &description, &orgID, &periodMonths, &reminderDays)
+	if err != nil {
+		return nil, err
+	}
+	snapshot := map[string]interface{}{
+		"vulnerabilityType":           title,
+		"description":                 description,
+		"organizationId":              orgID,
+		"recertificationMonths":       nil,
+		"recertificationReminderDays": nil,
+	}
+	if periodMonths.Valid {
+		snapshot["recertificationMonths"] = periodMonths.Int64
+		snapshot["recertificationReminderDays"] = reminderDays.Int64
+	}
+
+	rows, err := q.QueryContext(ctx,
+		"SELECT locale, vulnerability_type, description FROM course_translations WHERE course_id = ?", courseID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var locale, translatedTitle, translatedDescription string
+		if err := rows.Scan(&locale, &translatedTitle, &translatedDescription); err != nil {
+			return nil, err
+		}
+		snapshot["translations."+locale+".vulnerabilityType"] = translatedTitle
+		snapshot["translations."+locale+".description"] = translatedDescription
+	}
+	return snapshot, rows.Err()
+}
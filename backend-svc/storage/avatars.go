@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// SetAvatar запоминает префикс ключей нового аватара и возвращает прежний, чтобы вызывающий
+// удалил старые файлы из медиахранилища. Пустой avatarKey удаляет аватар.
+func (s *DBStorage) SetAvatar(userID int, avatarKey string) (string, error) {
+	ctx, done := s.startQuery("SetAvatar")
+	defer done()
+
+	var previous string
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, "SELECT avatar_key FROM users WHERE id = ?", userID).Scan(&previous)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := bumpUserVersion(ctx, tx, userID, AnyVersion); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx, "UPDATE users SET avatar_key = ? WHERE id = ?", avatarKey, userID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return "", err
+		}
+		return "", fmt.Errorf("set avatar: %w", err)
+	}
+	return previous, nil
+}
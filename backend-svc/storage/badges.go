@@ -0,0 +1,341 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrBadgeClassNotFound — значка нет
+	ErrBadgeClassNotFound = errors.New("badge class not found")
+	// ErrBadgeClassExists — у курса уже есть значок
+	ErrBadgeClassExists = errors.New("course already has a badge")
+	// ErrBadgeAssertionNotFound — выданного значка нет
+	ErrBadgeAssertionNotFound = errors.New("badge assertion not found")
+	// ErrBadgeAlreadyAwarded — у получателя уже есть действующий значок этого типа
+	ErrBadgeAlreadyAwarded = errors.New("badge already awarded")
+	// ErrBadgeAlreadyRevoked — значок уже отозван
+	ErrBadgeAlreadyRevoked = errors.New("badge already revoked")
+)
+
+const (
+	badgeClassColumns     = "id, course_id, name, description, criteria, created_by, created_at, updated_at"
+	badgeAssertionColumns = "a.id, a.badge_class_id, a.user_id, a.issued_at, a.issued_by, a.revoked_at, a.revocation_reason, a.credential"
+)
+
+// CreateBadgeClass создает значок; у курса может быть только один значок
+func (s *DBStorage) CreateBadgeClass(class models.BadgeClass) (models.BadgeClass, error) {
+	ctx, done := s.startQuery("CreateBadgeClass")
+	defer done()
+
+	class.CreatedAt = time.Now().UTC()
+	class.UpdatedAt = class.CreatedAt
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if class.CourseID != nil {
+			if err := courseExists(ctx, tx, *class.CourseID); err != nil {
+				return err
+			}
+			if _, err := scanBadgeClass(tx.QueryRowContext(ctx,
+				"SELECT "+badgeClassColumns+" FROM badge_classes WHERE course_id = ?", *class.CourseID)); err == nil {
+				return ErrBadgeClassExists
+			} else if !errors.Is(err, ErrBadgeClassNotFound) {
+				return err
+			}
+		}
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO badge_classes (course_id, name, description, criteria, created_by, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			class.CourseID, class.Name, class.Description, class.Criteria, class.CreatedBy, class.CreatedAt, class.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("insert badge class: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get badge class id: %w", err)
+		}
+		class.ID = int(id)
+		return nil
+	})
+	return class, err
+}
+
+// UpdateBadgeClass меняет описание значка; уже выданные удостоверения остаются прежними
+func (s *DBStorage) UpdateBadgeClass(class models.BadgeClass) (models.BadgeClass, error) {
+	ctx, done := s.startQuery("UpdateBadgeClass")
+	defer done()
+
+	var updated models.BadgeClass
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx,
+			"UPDATE badge_classes SET name = ?, description = ?, criteria = ?, updated_at = ? WHERE id = ?",
+			class.Name, class.Description, class.Criteria, time.Now().UTC(), class.ID)
+		if err != nil {
+			return fmt.Errorf("update badge class: %w", err)
+		}
+		updated, err = scanBadgeClass(tx.QueryRowContext(ctx, "SELECT "+badgeClassColumns+" FROM badge_classes WHERE id = ?", class.ID))
+		return err
+	})
+	return updated, err
+}
+
+// DeleteBadgeClass удаляет значок; выданные удостоверения удаляются каскадом
+func (s *DBStorage) DeleteBadgeClass(id int) error {
+	ctx, done := s.startQuery("DeleteBadgeClass")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM badge_classes WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete badge class: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return ErrBadgeClassNotFound
+	}
+	return nil
+}
+
+// GetBadgeClass возвращает значок по ID
+func (s *DBStorage) GetBadgeClass(id int) (models.BadgeClass, error) {
+	ctx, done := s.startQuery("GetBadgeClass")
+	defer done()
+
+	return scanBadgeClass(s.DB.QueryRowContext(ctx, "SELECT "+badgeClassColumns+" FROM badge_classes WHERE id = ?", id))
+}
+
+// GetCourseBadgeClass возвращает значок за завершение курса
+func (s *DBStorage) GetCourseBadgeClass(courseID int) (models.BadgeClass, error) {
+	ctx, done := s.startQuery("GetCourseBadgeClass")
+	defer done()
+
+	return scanBadgeClass(s.DB.QueryRowContext(ctx, "SELECT "+badgeClassColumns+" FROM badge_classes WHERE course_id = ?", courseID))
+}
+
+// ListBadgeClasses возвращает все значки в порядке создания
+func (s *DBStorage) ListBadgeClasses() ([]models.BadgeClass, error) {
+	ctx, done := s.startQuery("ListBadgeClasses")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx, "SELECT "+badgeClassColumns+" FROM badge_classes ORDER BY id")
+	if err != nil {
+		return nil, fmt.Errorf("query badge classes: %w", err)
+	}
+	defer rows.Close()
+
+	classes := []models.BadgeClass{}
+	for rows.Next() {
+		class, err := scanBadgeClass(rows)
+		if err != nil {
+			return nil, err
+		}
+		classes = append(classes, class)
+	}
+	return classes, rows.Err()
+}
+
+// GetBadgeClassImage возвращает загруженное изображение значка
+func (s *DBStorage) GetBadgeClassImage(id int) ([]byte, error) {
+	ctx, done := s.startQuery("GetBadgeClassImage")
+	defer done()
+
+	var image []byte
+	err := s.DB.QueryRowContext(ctx, "SELECT image FROM badge_classes WHERE id = ?", id).Scan(&image)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrBadgeClassNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get badge image: %w", err)
+	}
+	return image, nil
+}
+
+// SetBadgeClassImage сохраняет изображение значка. Изображения небольшие и нужны сервису
+// целиком для встраивания удостоверения, поэтому хранятся в базе, а не в медиахранилище.
+func (s *DBStorage) SetBadgeClassImage(id int, image []byte) (models.BadgeClass, error) {
+	ctx, done := s.startQuery("SetBadgeClassImage")
+	defer done()
+
+	var updated models.BadgeClass
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, "UPDATE badge_classes SET image = ?, updated_at = ? WHERE id = ?", image, time.Now().UTC(), id)
+		if err != nil {
+			return fmt.Errorf("update badge image: %w", err)
+		}
+		updated, err = scanBadgeClass(tx.QueryRowContext(ctx, "SELECT "+badgeClassColumns+" FROM badge_classes WHERE id = ?", id))
+		return err
+	})
+	return updated, err
+}
+
+// CreateBadgeAssertion сохраняет выданный значок, заменяя отозванный значок того же типа
+func (s *DBStorage) CreateBadgeAssertion(assertion models.BadgeAssertion) (models.BadgeAssertion, error) {
+	ctx, done := s.startQuery("CreateBadgeAssertion")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var revokedAt sql.NullTime
+		err := tx.QueryRowContext(ctx,
+			"SELECT revoked_at FROM badge_assertions WHERE badge_class_id = ? AND user_id = ?",
+			assertion.BadgeClassID, assertion.UserID).Scan(&revokedAt)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+		case err != nil:
+			return fmt.Errorf("get badge assertion: %w", err)
+		case !revokedAt.Valid:
+			return ErrBadgeAlreadyAwarded
+		default:
+			if _, err := tx.ExecContext(ctx,
+				"DELETE FROM badge_assertions WHERE badge_class_id = ? AND user_id = ?",
+				assertion.BadgeClassID, assertion.UserID); err != nil {
+				return fmt.Errorf("delete revoked badge assertion: %w", err)
+			}
+		}
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO badge_assertions (id, badge_class_id, user_id, issued_at, issued_by, credential) VALUES (?, ?, ?, ?, ?, ?)",
+			assertion.ID, assertion.BadgeClassID, assertion.UserID, assertion.IssuedAt, assertion.IssuedBy, assertion.Credential)
+		if err != nil {
+			return fmt.Errorf("insert badge assertion: %w", err)
+		}
+		return nil
+	})
+	return assertion, err
+}
+
+// GetBadgeAssertion возвращает выданный значок со значком-классом
+func (s *DBStorage) GetBadgeAssertion(id string) (models.BadgeAssertion, error) {
+	ctx, done := s.startQuery("GetBadgeAssertion")
+	defer done()
+
+	return getBadgeAssertion(ctx, s.DB, id)
+}
+
+// ListUserBadges возвращает значки пользователя со значками-классами
+func (s *DBStorage) ListUserBadges(userID int) ([]models.BadgeAssertion, error) {
+	ctx, done := s.startQuery("ListUserBadges")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT "+badgeAssertionColumns+", c.id, c.course_id, c.name, c.description, c.criteria, c.created_by, c.created_at, c.updated_at "+
+			"FROM badge_assertions a JOIN badge_classes c ON c.id = a.badge_class_id WHERE a.user_id = ? ORDER BY a.issued_at DESC, a.id",
+		userID)
+	if err != nil {
+		return nil, fmt.Errorf("query user badges: %w", err)
+	}
+	defer rows.Close()
+
+	badges := []models.BadgeAssertion{}
+	for rows.Next() {
+		assertion, err := scanBadgeAssertionWithClass(rows)
+		if err != nil {
+			return nil, err
+		}
+		badges = append(badges, assertion)
+	}
+	return badges, rows.Err()
+}
+
+// ListBadgeClassAssertions возвращает выданные значки одного типа, новые первыми
+func (s *DBStorage) ListBadgeClassAssertions(classID int) ([]models.BadgeAssertion, error) {
+	ctx, done := s.startQuery("ListBadgeClassAssertions")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT "+badgeAssertionColumns+", u.username FROM badge_assertions a JOIN users u ON u.id = a.user_id "+
+			"WHERE a.badge_class_id = ? ORDER BY a.issued_at DESC, a.id", classID)
+	if err != nil {
+		return nil, fmt.Errorf("query badge assertions: %w", err)
+	}
+	defer rows.Close()
+
+	assertions := []models.BadgeAssertion{}
+	for rows.Next() {
+		var assertion models.BadgeAssertion
+		var issuedBy sql.NullInt64
+		var revokedAt sql.NullTime
+		if err := rows.Scan(&assertion.ID, &assertion.BadgeClassID, &assertion.UserID, &assertion.IssuedAt, &issuedBy,
+			&revokedAt, &assertion.RevocationReason, &assertion.Credential, &assertion.Username); err != nil {
+			return nil, fmt.Errorf("scan badge assertion: %w", err)
+		}
+		fillBadgeAssertion(&assertion, issuedBy, revokedAt)
+		assertions = append(assertions, assertion)
+	}
+	return assertions, rows.Err()
+}
+
+// RevokeBadgeAssertion отзывает значок
+func (s *DBStorage) RevokeBadgeAssertion(id, reason string) (models.BadgeAssertion, error) {
+	ctx, done := s.startQuery("RevokeBadgeAssertion")
+	defer done()
+
+	var assertion models.BadgeAssertion
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		assertion, err = getBadgeAssertion(ctx, tx, id)
+		if err != nil {
+			return err
+		}
+		if assertion.RevokedAt != nil {
+			return ErrBadgeAlreadyRevoked
+		}
+		now := time.Now().UTC()
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE badge_assertions SET revoked_at = ?, revocation_reason = ? WHERE id = ?", now, reason, id); err != nil {
+			return fmt.Errorf("revoke badge assertion: %w", err)
+		}
+		assertion.RevokedAt, assertion.RevocationReason = &now, reason
+		return nil
+	})
+	return assertion, err
+}
+
+func getBadgeAssertion(ctx context.Context, q queryer, id string) (models.BadgeAssertion, error) {
+	assertion, err := scanBadgeAssertionWithClass(q.QueryRowContext(ctx,
+		"SELECT "+badgeAssertionColumns+", c.id, c.course_id, c.name, c.description, c.criteria, c.created_by, c.created_at, c.updated_at "+
+			"FROM badge_assertions a JOIN badge_classes c ON c.id = a.badge_class_id WHERE a.id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.BadgeAssertion{}, ErrBadgeAssertionNotFound
+	}
+	return assertion, err
+}
+
+func scanBadgeClass(row rowScanner) (models.BadgeClass, error) {
+	var class models.BadgeClass
+	var courseID, createdBy sql.NullInt64
+	err := row.Scan(&class.ID, &courseID, &class.Name, &class.Description, &class.Criteria, &createdBy, &class.CreatedAt, &class.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return class, ErrBadgeClassNotFound
+	}
+	if err != nil {
+		return class, fmt.Errorf("scan badge class: %w", err)
+	}
+	class.CourseID, class.CreatedBy = intOrNil(courseID), intOrNil(createdBy)
+	return class, nil
+}
+
+func scanBadgeAssertionWithClass(row rowScanner) (models.BadgeAssertion, error) {
+	var assertion models.BadgeAssertion
+	var class models.BadgeClass
+	var issuedBy, courseID, createdBy sql.NullInt64
+	var revokedAt sql.NullTime
+	err := row.Scan(&assertion.ID, &assertion.BadgeClassID, &assertion.UserID, &assertion.IssuedAt, &issuedBy,
+		&revokedAt, &assertion.RevocationReason, &assertion.Credential,
+		&class.ID, &courseID, &class.Name, &class.Description, &class.Criteria, &createdBy, &class.CreatedAt, &class.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return assertion, err
+	}
+	if err != nil {
+		return assertion, fmt.Errorf("scan badge assertion: %w", err)
+	}
+	fillBadgeAssertion(&assertion, issuedBy, revokedAt)
+	class.CourseID, class.CreatedBy = intOrNil(courseID), intOrNil(createdBy)
+	assertion.BadgeClass = &class
+	return assertion, nil
+}
+
+func fillBadgeAssertion(assertion *models.BadgeAssertion, issuedBy sql.NullInt64, revokedAt sql.NullTime) {
+	assertion.IssuedBy = intOrNil(issuedBy)
+	if revokedAt.Valid {
+		assertion.RevokedAt = &revokedAt.Time
+	}
+}
@@ -0,0 +1,160 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+)
+
+// batchLookupSize ограничивает число ID в одном условии IN (...): длинные списки
+// загружаются несколькими запросами
+const batchLookupSize = 500
+
+// eachIDBatch вызывает fn для частей ids без повторов; in — условие вида "(?, ?, ?)"
+func eachIDBatch(ids []int, fn func(in string, args []interface{}) error) error {
+	ids = uniqueInts(ids)
+	for start := 0; start < len(ids); start += batchLookupSize {
+		part := ids[start:min(start+batchLookupSize, len(ids))]
+		args := make([]interface{}, len(part))
+		for i, id := range part {
+			args[i] = id
+		}
+		in := "(" + strings.TrimSuffix(strings.Repeat("?, ", len(part)), ", ") + ")"
+		if err := fn(in, args); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetCoursesByIDs загружает курсы с заданиями и показателями сводки двумя запросами на
+// каждые batchLookupSize курсов. Курсов, которых нет или которые не видны в организации
+// представления, в результате нет.
+func (s *DBStorage) GetCoursesByIDs(ids []int) (map[int]models.Course, error) {
+	ctx, done := s.startQuery("GetCoursesByIDs")
+	defer done()
+
+	courses := make(map[int]models.Course, len(ids))
+	err := eachIDBatch(ids, func(in string, args []interface{}) error {
+		orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+		rows, err := s.reader().QueryContext(ctx, `
+			SELECT c.id, c.vulnerability_type, c.description, c.updated_at,
+				COALESCE(cs.enrollments, 0), COALESCE(cs.reviews, 0), cs.avg_difficulty, cs.avg_rating, c.archived_at
+			FROM courses c
+			LEFT JOIN course_summary cs ON cs.course_id = c.id
+			WHERE c.id IN `+in+orgAnd, append(args, orgArgs...)...)
+		if err != nil {
+			return fmt.Errorf("query courses: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var course models.Course
+			var avgDifficulty, avgRating sql.NullFloat64
+			var archivedAt sql.NullTime
+			if err := rows.Scan(&course.ID, &course.VulnerabilityType, &course.Description, &course.UpdatedAt,
+				&course.Enrollments, &course.Reviews, &avgDifficulty, &avgRating, &archivedAt); err != nil {
+				return fmt.Errorf("scan course: %w", err)
+			}
+			course.AverageDifficulty, course.AverageRating = floatOrNil(avgDifficulty), floatOrNil(avgRating)
+			if archivedAt.Valid {
+				course.ArchivedAt = &archivedAt.Time
+			}
+			courses[course.ID] = course
+		}
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("iterate courses: %w", err)
+		}
+		return s.attachTasks(ctx, in, args, courses)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return courses, nil
+}
+
+// attachTasks дописывает задания к уже загруженным курсам из части ID
+func (s *DBStorage) attachTasks(ctx context.Context, in string, args []interface{}, courses map[int]models.Course) error {
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT id, course_id, title, description, difficulty, task_order
+		FROM tasks
+		WHERE course_id IN `+in+`
+		ORDER BY course_id, task_order`, args...)
+	if err != nil {
+		return fmt.Errorf("query tasks: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var task models.Task
+		if err := rows.Scan(&task.ID, &task.CourseID, &task.Title, &task.Description, &task.Difficulty, &task.Order); err != nil {
+			return fmt.Errorf("scan task: %w", err)
+		}
+		course, ok := courses[task.CourseID]
+		if !ok {
+			continue
+		}
+		course.Tasks = append(course.Tasks, task)
+		course.TasksCount = len(course.Tasks)
+		courses[task.CourseID] = course
+	}
+	return rows.Err()
+}
+
+// GetUsersByIDs загружает пользователей по ID; отсутствующих в результате нет
+func (s *DBStorage) GetUsersByIDs(ids []int) (map[int]models.User, error) {
+	ctx, done := s.startQuery("GetUsersByIDs")
+	defer done()
+
+	users := make(map[int]models.User, len(ids))
+	err := eachIDBatch(ids, func(in string, args []interface{}) error {
+		rows, err := s.DB.QueryContext(ctx, "SELECT "+userColumns+" FROM users WHERE id IN "+in, args...)
+		if err != nil {
+			return fmt.Errorf("query users: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			user, err := scanUser(rows)
+			if err != nil {
+				return err
+			}
+			users[user.ID] = user
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// GetProgressByUserIDs загружает прогресс нескольких пользователей; у каждого
+// запрошенного пользователя есть запись, пустая, если он ничего не выполнил
+func (s *DBStorage) GetProgressByUserIDs(userIDs []int) (map[int]models.UserProgress, error) {
+	ctx, done := s.startQuery("GetProgressByUserIDs")
+	defer done()
+
+	progress := make(map[int]models.UserProgress, len(userIDs))
+	for _, userID := range userIDs {
+		progress[userID] = models.UserProgress{UserID: userID, Completed: map[int]bool{}}
+	}
+	err := eachIDBatch(userIDs, func(in string, args []interface{}) error {
+		rows, err := s.reader().QueryContext(ctx, "SELECT user_id, task_id FROM user_progress WHERE user_id IN "+in, args...)
+		if err != nil {
+			return fmt.Errorf("query progress: %w", err)
+		}
+		defer rows.Close()
+		for rows.Next() {
+			var userID, taskID int
+			if err := rows.Scan(&userID, &taskID); err != nil {
+				return fmt.Errorf("scan progress: %w", err)
+			}
+			progress[userID].Completed[taskID] = true
+		}
+		return rows.Err()
+	})
+	if err != nil {
+		return nil, err
+	}
+	return progress, nil
+}
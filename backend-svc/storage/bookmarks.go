@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"sort"
+)
+
+// ErrBookmarkNotFound — такой закладки у пользователя нет
+var ErrBookmarkNotFound = errors.New("bookmark not found")
+
+// bookmarkTaskID — значение bookmarks.task_id: 0 для закладки на курс
+func bookmarkTaskID(taskID *int) int {
+	if taskID == nil {
+		return 0
+	}
+	return *taskID
+}
+
+// SaveBookmark создает закладку или меняет ее notify
+func (s *DBStorage) SaveBookmark(b models.Bookmark) (models.Bookmark, error) {
+	ctx, done := s.startQuery("SaveBookmark")
+	defer done()
+
+	if b.TaskID != nil {
+		b.Notify = false
+	}
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := courseExists(ctx, tx, b.CourseID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO bookmarks (user_id, course_id, task_id, notify) VALUES (?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"user_id", "course_id", "task_id"}, "notify"),
+			b.UserID, b.CourseID, bookmarkTaskID(b.TaskID), b.Notify)
+		if err != nil {
+			return fmt.Errorf("save bookmark: %w", err)
+		}
+		err = tx.QueryRowContext(ctx,
+			"SELECT created_at FROM bookmarks WHERE user_id = ? AND course_id = ? AND task_id = ?",
+			b.UserID, b.CourseID, bookmarkTaskID(b.TaskID)).Scan(&b.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("get bookmark: %w", err)
+		}
+		return nil
+	})
+	return b, err
+}
+
+// DeleteBookmark удаляет закладку на курс (taskID пуст) или на задание
+func (s *DBStorage) DeleteBookmark(userID, courseID int, taskID *int) error {
+	ctx, done := s.startQuery("DeleteBookmark")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM bookmarks WHERE user_id = ? AND course_id = ? AND task_id = ?",
+		userID, courseID, bookmarkTaskID(taskID))
+	if err != nil {
+		return fmt.Errorf("delete bookmark: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrBookmarkNotFound
+	}
+	return nil
+}
+
+// ListBookmarks возвращает закладки пользователя, новые первыми. Названия не заполняются.
+func (s *DBStorage) ListBookmarks(userID int) ([]models.Bookmark, error) {
+	ctx, done := s.startQuery("ListBookmarks")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT course_id, task_id, notify, created_at FROM bookmarks WHERE user_id = ? "+
+			"ORDER BY created_at DESC, course_id, task_id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("query bookmarks: %w", err)
+	}
+	defer rows.Close()
+
+	bookmarks := []models.Bookmark{}
+	for rows.Next() {
+		b := models.Bookmark{UserID: userID}
+		var taskID int
+		if err := rows.Scan(&b.CourseID, &taskID, &b.Notify, &b.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan bookmark: %w", err)
+		}
+		if taskID != 0 {
+			b.TaskID = &taskID
+		}
+		bookmarks = append(bookmarks, b)
+	}
+	return bookmarks, rows.Err()
+}
+
+// ListBookmarkSubscribers возвращает пользователей, которые просили сообщить о возвращении курса в каталог
+func (s *DBStorage) ListBookmarkSubscribers(courseID int) ([]int, error) {
+	ctx, done := s.startQuery("ListBookmarkSubscribers")
+	defer done()
+
+	orgAnd, orgArgs := s.orgFilter("AND", "u.organization_id")
+	set, err := queryIntSet(ctx, s.reader(),
+		"SELECT b.user_id FROM bookmarks b JOIN users u ON u.id = b.user_id WHERE b.course_id = ? AND b.task_id = 0 AND b.notify = ?"+orgAnd,
+		append([]any{courseID, true}, orgArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("query bookmark subscribers: %w", err)
+	}
+	userIDs := make([]int, 0, len(set))
+	for id := range set {
+		userIDs = append(userIDs, id)
+	}
+	sort.Ints(userIDs)
+	return userIDs, nil
+}
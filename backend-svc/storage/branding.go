@@ -0,0 +1,151 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+const brandingColumns = `o.id, o.name, b.logo IS NOT NULL, COALESCE(b.primary_color, ''), COALESCE(b.accent_color, ''),
+	COALESCE(b.email_footer, ''), COALESCE(b.certificate_template, ''), b.updated_at`
+
+// brandingFrom — организации с необязательным оформлением
+const brandingFrom = " FROM organizations o LEFT JOIN organization_branding b ON b.organization_id = o.id"
+
+func scanBranding(row rowScanner) (models.OrganizationBranding, error) {
+	var branding models.OrganizationBranding
+	var updatedAt sql.NullTime
+	err := row.Scan(&branding.OrganizationID, &branding.OrganizationName, &branding.HasLogo, &branding.PrimaryColor,
+		&branding.AccentColor, &branding.EmailFooter, &branding.CertificateTemplate, &updatedAt)
+	if err != nil {
+		return branding, err
+	}
+	if updatedAt.Valid {
+		branding.UpdatedAt = updatedAt.Time
+	}
+	return branding, nil
+}
+
+// GetOrganizationBranding возвращает оформление организации; без сохраненного оформления
+// все поля, кроме организации, пустые
+func (s *DBStorage) GetOrganizationBranding(orgID int) (models.OrganizationBranding, error) {
+	ctx, done := s.startQuery("GetOrganizationBranding")
+	defer done()
+
+	branding, err := scanBranding(s.DB.QueryRowContext(ctx, "SELECT "+brandingColumns+brandingFrom+" WHERE o.id = ?", orgID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return branding, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return branding, fmt.Errorf("get organization branding: %w", err)
+	}
+	return branding, nil
+}
+
+// GetUserBranding возвращает оформление организации пользователя
+func (s *DBStorage) GetUserBranding(userID int) (models.OrganizationBranding, error) {
+	ctx, done := s.startQuery("GetUserBranding")
+	defer done()
+
+	branding, err := scanBranding(s.DB.QueryRowContext(ctx,
+		"SELECT "+brandingColumns+brandingFrom+" JOIN users u ON u.organization_id = o.id WHERE u.id = ?", userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return branding, ErrUserNotFound
+	}
+	if err != nil {
+		return branding, fmt.Errorf("get user branding: %w", err)
+	}
+	return branding, nil
+}
+
+// GetEmailBranding возвращает оформление организации пользователя с адресом email;
+// ErrUserNotFound, если адрес никому не принадлежит
+func (s *DBStorage) GetEmailBranding(email string) (models.OrganizationBranding, error) {
+	ctx, done := s.startQuery("GetEmailBranding")
+	defer done()
+
+	branding, err := scanBranding(s.DB.QueryRowContext(ctx,
+		"SELECT "+brandingColumns+brandingFrom+" JOIN users u ON u.organization_id = o.id WHERE u.email = ?", email))
+	if errors.Is(err, sql.ErrNoRows) {
+		return branding, ErrUserNotFound
+	}
+	if err != nil {
+		return branding, fmt.Errorf("get email branding: %w", err)
+	}
+	return branding, nil
+}
+
+// SaveOrganizationBranding сохраняет цвета, подпись писем и шаблон сертификата, не меняя логотип
+func (s *DBStorage) SaveOrganizationBranding(branding models.OrganizationBranding) (models.OrganizationBranding, error) {
+	ctx, done := s.startQuery("SaveOrganizationBranding")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if found, err := rowExists(ctx, tx, "organizations", branding.OrganizationID); err != nil || !found {
+			if err == nil {
+				err = ErrOrganizationNotFound
+			}
+			return err
+		}
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO organization_branding (organization_id, primary_color, accent_color, email_footer, certificate_template, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)`+
+			s.onConflictUpdate([]string{"organization_id"}, "primary_color", "accent_color", "email_footer", "certificate_template", "updated_at"),
+			branding.OrganizationID, branding.PrimaryColor, branding.AccentColor, branding.EmailFooter,
+			branding.CertificateTemplate, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("save organization branding: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return branding, err
+	}
+	return s.GetOrganizationBranding(branding.OrganizationID)
+}
+
+// GetOrganizationLogo возвращает логотип организации; nil — логотип не загружен
+func (s *DBStorage) GetOrganizationLogo(orgID int) ([]byte, error) {
+	ctx, done := s.startQuery("GetOrganizationLogo")
+	defer done()
+
+	var logo []byte
+	err := s.DB.QueryRowContext(ctx, "SELECT logo FROM organization_branding WHERE organization_id = ?", orgID).Scan(&logo)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get organization logo: %w", err)
+	}
+	return logo, nil
+}
+
+// SetOrganizationLogo сохраняет логотип организации (nil удаляет его). Как и изображения значков,
+// логотипы небольшие и хранятся в базе.
+func (s *DBStorage) SetOrganizationLogo(orgID int, logo []byte) (models.OrganizationBranding, error) {
+	ctx, done := s.startQuery("SetOrganizationLogo")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if found, err := rowExists(ctx, tx, "organizations", orgID); err != nil || !found {
+			if err == nil {
+				err = ErrOrganizationNotFound
+			}
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO organization_branding (organization_id, logo, updated_at) VALUES (?, ?, ?)"+
+				s.onConflictUpdate([]string{"organization_id"}, "logo", "updated_at"),
+			orgID, logo, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("save organization logo: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.OrganizationBranding{}, err
+	}
+	return s.GetOrganizationBranding(orgID)
+}
@@ -0,0 +1,311 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+	"time"
+)
+
+// MaxBulkUsers — сколько пользователей может затронуть одна массовая операция
+const MaxBulkUsers = 1000
+
+// ErrTooManyUsers — фильтр массовой операции выбрал больше MaxBulkUsers пользователей
+var ErrTooManyUsers = errors.New("too many users selected")
+
+// bulkGroup — группа, участвующая в массовой операции
+type bulkGroup struct {
+	id    int
+	orgID int
+	name  string
+}
+
+// bulkGroups находит группы по названиям; ErrGroupNotFound, если какой-то нет
+func bulkGroups(ctx context.Context, q queryer, names []string) ([]bulkGroup, error) {
+	groups := make([]bulkGroup, 0, len(names))
+	seen := make(map[string]bool, len(names))
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		group := bulkGroup{name: name}
+		err := q.QueryRowContext(ctx, "SELECT id, organization_id FROM user_groups WHERE name = ?", name).Scan(&group.id, &group.orgID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, group)
+	}
+	return groups, nil
+}
+
+// bulkCourseOrganizations возвращает организации курсов; ErrCourseNotFound, если какого-то курса нет
+func bulkCourseOrganizations(ctx context.Context, q queryer, courseIDs ...[]int) (map[int]int, error) {
+	orgs := map[int]int{}
+	for _, ids := range courseIDs {
+		for _, courseID := range ids {
+			if _, ok := orgs[courseID]; ok {
+				continue
+			}
+			var orgID int
+			err := q.QueryRowContext(ctx, "SELECT organization_id FROM courses WHERE id = ?", courseID).Scan(&orgID)
+			if errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("%w: %d", ErrCourseNotFound, courseID)
+			}
+			if err != nil {
+				return nil, err
+			}
+			orgs[courseID] = orgID
+		}
+	}
+	return orgs, nil
+}
+
+// uniqueIDs убирает повторы, сохраняя порядок
+func uniqueIDs(ids []int) []int {
+	unique := make([]int, 0, len(ids))
+	seen := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// bulkUserIDs возвращает ID выбранных пользователей: список из запроса или найденных по фильтру
+func (s *DBStorage) bulkUserIDs(ctx context.Context, tx *sql.Tx, req models.BulkUserRequest) ([]int, error) {
+	if req.Filter == nil {
+		return uniqueIDs(req.UserIDs), nil
+	}
+
+	filter := req.Filter
+	conditions := []string{"1 = 1"}
+	var args []interface{}
+	if filter.Query != "" {
+		pattern := "%" + filter.Query + "%"
+		conditions = append(conditions, "(u.username LIKE ? OR u.email LIKE ? OR u.full_name LIKE ?)")
+		args = append(args, pattern, pattern, pattern)
+	}
+	if filter.Group != "" {
+		if err := groupExists(ctx, tx, filter.Group, 0); err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, "EXISTS (SELECT 1 FROM group_members gm JOIN user_groups g ON g.id = gm.group_id "+
+			"WHERE gm.user_id = u.id AND g.name = ?)")
+		args = append(args, filter.Group)
+	}
+	if filter.IsAdmin != nil {
+		conditions = append(conditions, "u.is_admin = ?")
+		args = append(args, *filter.IsAdmin)
+	}
+	if filter.IsActive != nil {
+		conditions = append(conditions, "u.is_active = ?")
+		args = append(args, *filter.IsActive)
+	}
+	if filter.OrganizationID != 0 {
+		conditions = append(conditions, "u.organization_id = ?")
+		args = append(args, filter.OrganizationID)
+	}
+	orgAnd, orgArgs := s.orgFilter("AND", "u.organization_id")
+
+	rows, err := tx.QueryContext(ctx, "SELECT u.id FROM users u WHERE "+strings.Join(conditions, " AND ")+orgAnd+
+		" ORDER BY u.id LIMIT ?", append(append(args, orgArgs...), MaxBulkUsers+1)...)
+	if err != nil {
+		return nil, fmt.Errorf("select users: %w", err)
+	}
+	defer rows.Close()
+
+	var userIDs []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(userIDs) > MaxBulkUsers {
+		return nil, ErrTooManyUsers
+	}
+	return userIDs, nil
+}
+
+// BulkUpdateUsers применяет массовую операцию в одной транзакции: либо изменения получают все
+// выбранные пользователи, либо никто. Неприменимые к пользователю действия не прерывают операцию
+// и попадают в его отчет.
+func (s *DBStorage) BulkUpdateUsers(req models.BulkUserRequest, actorID int) ([]models.BulkUserResult, error) {
+	ctx, done := s.startQuery("BulkUpdateUsers")
+	defer done()
+
+	var results []models.BulkUserResult
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		addGroups, err := bulkGroups(ctx, tx, req.AddGroups)
+		if err != nil {
+			return err
+		}
+		removeGroups, err := bulkGroups(ctx, tx, req.RemoveGroups)
+		if err != nil {
+			return err
+		}
+		courseOrgs, err := bulkCourseOrganizations(ctx, tx, req.AssignCourses, req.UnassignCourses)
+		if err != nil {
+			return err
+		}
+		// Архивные курсы закрыты для новых слушателей, поэтому не назначаются
+		archived := map[int]bool{}
+		if assign := uniqueIDs(req.AssignCourses); len(assign) > 0 {
+			args := make([]interface{}, len(assign))
+			for i, id := range assign {
+				args[i] = id
+			}
+			archived, err = queryIntSet(ctx, tx, "SELECT id FROM courses WHERE archived_at IS NOT NULL AND id IN ("+
+				strings.TrimSuffix(strings.Repeat("?,", len(assign)), ",")+")", args...)
+			if err != nil {
+				return fmt.Errorf("check archived courses: %w", err)
+			}
+		}
+		userIDs, err := s.bulkUserIDs(ctx, tx, req)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		orgAnd, orgArgs := s.orgFilter("AND", "organization_id")
+		results = make([]models.BulkUserResult, 0, len(userIDs))
+		for _, userID := range userIDs {
+			result := models.BulkUserResult{UserID: userID}
+			var isAdmin bool
+			var orgID int
+			err := tx.QueryRowContext(ctx, "SELECT username, is_admin, organization_id FROM users WHERE id = ?"+orgAnd,
+				append([]interface{}{userID}, orgArgs...)...).Scan(&result.Username, &isAdmin, &orgID)
+			if errors.Is(err, sql.ErrNoRows) {
+				result.Status = models.BulkUserNotFound
+				results = append(results, result)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("get user %d: %w", userID, err)
+			}
+
+			if req.Role != "" && (req.Role == "admin") != isAdmin {
+				if userID == actorID {
+					result.Errors = append(result.Errors, "cannot change your own role")
+				} else if err := setAdminInTx(ctx, tx, userID, !isAdmin); err != nil {
+					return fmt.Errorf("change role of user %d: %w", userID, err)
+				} else {
+					result.RoleChanged = true
+				}
+			}
+
+			for _, group := range addGroups {
+				if group.orgID != orgID {
+					result.Errors = append(result.Errors, fmt.Sprintf("group %q belongs to another organization", group.name))
+					continue
+				}
+				added, err := insertIfMissing(ctx, tx,
+					"SELECT 1 FROM group_members WHERE group_id = ? AND user_id = ?",
+					"INSERT INTO group_members (group_id, user_id) VALUES (?, ?)", group.id, userID)
+				if err != nil {
+					return fmt.Errorf("add user %d to group %s: %w", userID, group.name, err)
+				}
+				if added {
+					result.GroupsAdded = append(result.GroupsAdded, group.name)
+				}
+			}
+			for _, group := range removeGroups {
+				removed, err := deleteRows(ctx, tx, "DELETE FROM group_members WHERE group_id = ? AND user_id = ?", group.id, userID)
+				if err != nil {
+					return fmt.Errorf("remove user %d from group %s: %w", userID, group.name, err)
+				}
+				if removed {
+					result.GroupsRemoved = append(result.GroupsRemoved, group.name)
+				}
+			}
+
+			for _, courseID := range uniqueIDs(req.AssignCourses) {
+				if courseOrgs[courseID] != orgID {
+					result.Errors = append(result.Errors, fmt.Sprintf("course %d belongs to another organization", courseID))
+					continue
+				}
+				if archived[courseID] {
+					result.Errors = append(result.Errors, fmt.Sprintf("course %d is archived", courseID))
+					continue
+				}
+				assigned, err := insertIfMissing(ctx, tx,
+					"SELECT 1 FROM course_assignments WHERE user_id = ? AND course_id = ?",
+					"INSERT INTO course_assignments (user_id, course_id, assigned_at) VALUES (?, ?, ?)", userID, courseID, now)
+				if err != nil {
+					return fmt.Errorf("assign course %d to user %d: %w", courseID, userID, err)
+				}
+				if assigned {
+					result.CoursesAssigned = append(result.CoursesAssigned, courseID)
+				}
+			}
+			for _, courseID := range uniqueIDs(req.UnassignCourses) {
+				removed, err := deleteRows(ctx, tx, "DELETE FROM course_assignments WHERE user_id = ? AND course_id = ?", userID, courseID)
+				if err != nil {
+					return fmt.Errorf("unassign course %d from user %d: %w", courseID, userID, err)
+				}
+				if removed {
+					result.CoursesUnassigned = append(result.CoursesUnassigned, courseID)
+				}
+			}
+
+			result.Status = bulkResultStatus(result)
+			results = append(results, result)
+		}
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, ErrGroupNotFound) || errors.Is(err, ErrCourseNotFound) || errors.Is(err, ErrTooManyUsers) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("bulk update users: %w", err)
+	}
+	return results, nil
+}
+
+// insertIfMissing выполняет insert, если exists (с первыми двумя аргументами) ничего не нашел
+func insertIfMissing(ctx context.Context, tx *sql.Tx, exists, insert string, args ...interface{}) (bool, error) {
+	var found int
+	err := tx.QueryRowContext(ctx, exists, args[0], args[1]).Scan(&found)
+	if err == nil {
+		return false, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+	if _, err := tx.ExecContext(ctx, insert, args...); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// deleteRows выполняет DELETE и сообщает, удалил ли он что-нибудь
+func deleteRows(ctx context.Context, tx *sql.Tx, query string, args ...interface{}) (bool, error) {
+	res, err := tx.ExecContext(ctx, query, args...)
+	if err != nil {
+		return false, err
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, err
+}
+
+// bulkResultStatus возвращает итог массовой операции для пользователя
+func bulkResultStatus(result models.BulkUserResult) string {
+	if result.RoleChanged || len(result.GroupsAdded) > 0 || len(result.GroupsRemoved) > 0 ||
+		len(result.CoursesAssigned) > 0 || len(result.CoursesUnassigned) > 0 {
+		return models.BulkUserUpdated
+	}
+	return models.BulkUserUnchanged
+}
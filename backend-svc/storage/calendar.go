@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrLiveSessionNotFound — занятия нет
+	ErrLiveSessionNotFound = errors.New("live session not found")
+	// ErrCalendarFeedNotFound — ссылки на календарь с таким токеном нет
+	ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+)
+
+const liveSessionColumns = "id, course_id, title, description, starts_at, ends_at, join_url, host_id, created_at, updated_at"
+
+// courseFilter возвращает условие «column входит в courseIDs» с аргументами; nil — без ограничения
+func courseFilter(column string, courseIDs []int) (string, []interface{}) {
+	if courseIDs == nil {
+		return "", nil
+	}
+	placeholders := make([]string, len(courseIDs))
+	args := make([]interface{}, len(courseIDs))
+	for i, id := range courseIDs {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	return fmt.Sprintf(" AND %s IN (%s)", column, strings.Join(placeholders, ", ")), args
+}
+
+// SetTaskSchedule сохраняет сроки задания
+func (s *DBStorage) SetTaskSchedule(schedule models.TaskSchedule) (models.TaskSchedule, error) {
+	ctx, done := s.startQuery("SetTaskSchedule")
+	defer done()
+
+	var saved models.TaskSchedule
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := s.taskCourseInOrganization(ctx, tx, schedule.TaskID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO task_schedules (task_id, release_at, due_at, updated_at) VALUES (?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"task_id"}, "release_at", "due_at", "updated_at"),
+			schedule.TaskID, utcOrNil(schedule.ReleaseAt), utcOrNil(schedule.DueAt), time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("save task schedule: %w", err)
+		}
+		saved, err = scanTaskSchedule(tx.QueryRowContext(ctx,
+			"SELECT ts.task_id, t.course_id, t.title, ts.release_at, ts.due_at, ts.updated_at "+
+				"FROM task_schedules ts JOIN tasks t ON t.id = ts.task_id WHERE ts.task_id = ?", schedule.TaskID))
+		return err
+	})
+	return saved, err
+}
+
+// ListTaskSchedules возвращает расписания заданий с датами в интервале
+func (s *DBStorage) ListTaskSchedules(courseIDs []int, from, to time.Time) ([]models.TaskSchedule, error) {
+	ctx, done := s.startQuery("ListTaskSchedules")
+	defer done()
+
+	schedules := []models.TaskSchedule{}
+	if courseIDs != nil && len(courseIDs) == 0 {
+		return schedules, nil
+	}
+	from, to = from.UTC(), to.UTC()
+	condition, courseArgs := courseFilter("t.course_id", courseIDs)
+	args := append([]interface{}{from, to, from, to}, courseArgs...)
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT ts.task_id, t.course_id, t.title, ts.release_at, ts.due_at, ts.updated_at "+
+			"FROM task_schedules ts JOIN tasks t ON t.id = ts.task_id "+
+			"WHERE ((ts.release_at >= ? AND ts.release_at < ?) OR (ts.due_at >= ? AND ts.due_at < ?))"+condition+
+			" ORDER BY t.course_id, t.task_order", args...)
+	if err != nil {
+		return nil, fmt.Errorf("query task schedules: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		schedule, err := scanTaskSchedule(rows)
+		if err != nil {
+			return nil, err
+		}
+		schedules = append(schedules, schedule)
+	}
+	return schedules, rows.Err()
+}
+
+// CreateLiveSession сохраняет занятие
+func (s *DBStorage) CreateLiveSession(session models.LiveSession) (models.LiveSession, error) {
+	ctx, done := s.startQuery("CreateLiveSession")
+	defer done()
+
+	now := time.Now().UTC()
+	session.CreatedAt, session.UpdatedAt = now, now
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := courseExists(ctx, tx, session.CourseID); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO live_sessions (course_id, title, description, starts_at, ends_at, join_url, host_id, created_at, updated_at) "+
+				"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			session.CourseID, session.Title, session.Description, session.StartsAt.UTC(), session.EndsAt.UTC(),
+			session.JoinURL, session.HostID, now, now)
+		if err != nil {
+			return fmt.Errorf("insert live session: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get live session id: %w", err)
+		}
+		session.ID = int(id)
+		return nil
+	})
+	return session, err
+}
+
+// GetLiveSession возвращает занятие по ID
+func (s *DBStorage) GetLiveSession(id int) (models.LiveSession, error) {
+	ctx, done := s.startQuery("GetLiveSession")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+liveSessionColumns+" FROM live_sessions WHERE id = ?")
+	if err != nil {
+		return models.LiveSession{}, err
+	}
+	session, err := scanLiveSession(stmt.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.LiveSession{}, ErrLiveSessionNotFound
+	}
+	return session, err
+}
+
+// UpdateLiveSession меняет курс, описание и время занятия
+func (s *DBStorage) UpdateLiveSession(session models.LiveSession) (models.LiveSession, error) {
+	ctx, done := s.startQuery("UpdateLiveSession")
+	defer done()
+
+	var updated models.LiveSession
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := courseExists(ctx, tx, session.CourseID); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx,
+			"UPDATE live_sessions SET course_id = ?, title = ?, description = ?, starts_at = ?, ends_at = ?, join_url = ?, "+
+				"updated_at = ? WHERE id = ?",
+			session.CourseID, session.Title, session.Description, session.StartsAt.UTC(), session.EndsAt.UTC(),
+			session.JoinURL, time.Now().UTC(), session.ID)
+		if err != nil {
+			return fmt.Errorf("update live session: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrLiveSessionNotFound
+		}
+		updated, err = scanLiveSession(tx.QueryRowContext(ctx, "SELECT "+liveSessionColumns+" FROM live_sessions WHERE id = ?", session.ID))
+		return err
+	})
+	return updated, err
+}
+
+// DeleteLiveSession удаляет занятие
+func (s *DBStorage) DeleteLiveSession(id int) error {
+	ctx, done := s.startQuery("DeleteLiveSession")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM live_sessions WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete live session: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrLiveSessionNotFound
+	}
+	return nil
+}
+
+// ListLiveSessions возвращает занятия, пересекающиеся с интервалом
+func (s *DBStorage) ListLiveSessions(courseIDs []int, from, to time.Time) ([]models.LiveSession, error) {
+	ctx, done := s.startQuery("ListLiveSessions")
+	defer done()
+
+	sessions := []models.LiveSession{}
+	if courseIDs != nil && len(courseIDs) == 0 {
+		return sessions, nil
+	}
+	condition, courseArgs := courseFilter("course_id", courseIDs)
+	args := append([]interface{}{to.UTC(), from.UTC()}, courseArgs...)
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT "+liveSessionColumns+" FROM live_sessions WHERE starts_at < ? AND ends_at > ?"+condition+
+			" ORDER BY starts_at, id", args...)
+	if err != nil {
+		return nil, fmt.Errorf("query live sessions: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		session, err := scanLiveSession(rows)
+		if err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, session)
+	}
+	return sessions, rows.Err()
+}
+
+// GetStartedCourseIDs возвращает курсы, в которых пользователь выполнил хотя бы одно задание
+func (s *DBStorage) GetStartedCourseIDs(userID int) ([]int, error) {
+	ctx, done := s.startQuery("GetStartedCourseIDs")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.reader(),
+		"SELECT DISTINCT t.course_id FROM user_progress p JOIN tasks t ON t.id = p.task_id WHERE p.user_id = ? ORDER BY t.course_id")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query started courses: %w", err)
+	}
+	defer rows.Close()
+
+	courseIDs := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan course id: %w", err)
+		}
+		courseIDs = append(courseIDs, id)
+	}
+	return courseIDs, rows.Err()
+}
+
+// SetCalendarFeedToken заменяет токен ссылки на календарь пользователя
+func (s *DBStorage) SetCalendarFeedToken(userID int, tokenHash string) error {
+	ctx, done := s.startQuery("SetCalendarFeedToken")
+	defer done()
+
+	_, err := s.DB.ExecContext(ctx,
+		"INSERT INTO calendar_feed_tokens (user_id, token_hash, created_at) VALUES (?, ?, ?)"+
+			s.onConflictUpdate([]string{"user_id"}, "token_hash", "created_at"),
+		userID, tokenHash, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("save calendar feed token: %w", err)
+	}
+	return nil
+}
+
+// DeleteCalendarFeedToken отзывает ссылку на календарь пользователя
+func (s *DBStorage) DeleteCalendarFeedToken(userID int) error {
+	ctx, done := s.startQuery("DeleteCalendarFeedToken")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM calendar_feed_tokens WHERE user_id = ?", userID)
+	if err != nil {
+		return fmt.Errorf("delete calendar feed token: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCalendarFeedNotFound
+	}
+	return nil
+}
+
+// GetCalendarFeedUser возвращает владельца токена ссылки на календарь
+func (s *DBStorage) GetCalendarFeedUser(tokenHash string) (int, error) {
+	ctx, done := s.startQuery("GetCalendarFeedUser")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT user_id FROM calendar_feed_tokens WHERE token_hash = ?")
+	if err != nil {
+		return 0, err
+	}
+	var userID int
+	err = stmt.QueryRowContext(ctx, tokenHash).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrCalendarFeedNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get calendar feed user: %w", err)
+	}
+	return userID, nil
+}
+
+func scanTaskSchedule(row rowScanner) (models.TaskSchedule, error) {
+	var schedule models.TaskSchedule
+	var releaseAt, dueAt sql.NullTime
+	err := row.Scan(&schedule.TaskID, &schedule.CourseID, &schedule.TaskTitle, &releaseAt, &dueAt, &schedule.UpdatedAt)
+	if err != nil {
+		return schedule, fmt.Errorf("scan task schedule: %w", err)
+	}
+	if releaseAt.Valid {
+		schedule.ReleaseAt = &releaseAt.Time
+	}
+	if dueAt.Valid {
+		schedule.DueAt = &dueAt.Time
+	}
+	return schedule, nil
+}
+
+func scanLiveSession(row rowScanner) (models.LiveSession, error) {
+	var session models.LiveSession
+	var hostID sql.NullInt64
+	err := row.Scan(&session.ID, &session.CourseID, &session.Title, &session.Description, &session.StartsAt, &session.EndsAt,
+		&session.JoinURL, &hostID, &session.CreatedAt, &session.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return session, err
+		}
+		return session, fmt.Errorf("scan live session: %w", err)
+	}
+	session.HostID = intOrNil(hostID)
+	return session, nil
+}
@@ -0,0 +1,137 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrCertificateNotFound — сертификата с таким кодом нет
+	ErrCertificateNotFound = errors.New("certificate not found")
+	// ErrCertificateExists — у пользователя уже есть сертификат курса
+	ErrCertificateExists = errors.New("certificate already issued")
+	// ErrCertificateRevoked — сертификат уже отозван
+	ErrCertificateRevoked = errors.New("certificate already revoked")
+)
+
+const certificateColumns = "id, code, user_id, course_id, issued_to, course_title, issued_at, revoked_at, revoked_by, revocation_reason"
+
+// CreateCertificate сохраняет сертификат; повторно за тот же курс сертификат не выдается
+func (s *DBStorage) CreateCertificate(cert models.Certificate) (models.Certificate, error) {
+	ctx, done := s.startQuery("CreateCertificate")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var id int
+		err := tx.QueryRowContext(ctx,
+			"SELECT id FROM certificates WHERE user_id = ? AND course_id = ?", cert.UserID, cert.CourseID).Scan(&id)
+		if err == nil {
+			return ErrCertificateExists
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("get certificate: %w", err)
+		}
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO certificates (code, user_id, course_id, issued_to, course_title, issued_at) VALUES (?, ?, ?, ?, ?, ?)",
+			cert.Code, cert.UserID, cert.CourseID, cert.IssuedTo, cert.CourseTitle, cert.IssuedAt)
+		if err != nil {
+			return fmt.Errorf("insert certificate: %w", err)
+		}
+		newID, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get certificate id: %w", err)
+		}
+		cert.ID = int(newID)
+		return nil
+	})
+	return cert, err
+}
+
+// GetCertificateByCode возвращает сертификат по коду проверки
+func (s *DBStorage) GetCertificateByCode(code string) (models.Certificate, error) {
+	ctx, done := s.startQuery("GetCertificateByCode")
+	defer done()
+
+	return getCertificateByCode(ctx, s.DB, code)
+}
+
+// ListUserCertificates возвращает сертификаты пользователя
+func (s *DBStorage) ListUserCertificates(userID int) ([]models.Certificate, error) {
+	ctx, done := s.startQuery("ListUserCertificates")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT "+certificateColumns+" FROM certificates WHERE user_id = ? ORDER BY issued_at DESC, id DESC", userID)
+	if err != nil {
+		return nil, fmt.Errorf("query certificates: %w", err)
+	}
+	defer rows.Close()
+
+	certificates := []models.Certificate{}
+	for rows.Next() {
+		cert, err := scanCertificate(rows)
+		if err != nil {
+			return nil, err
+		}
+		certificates = append(certificates, cert)
+	}
+	return certificates, rows.Err()
+}
+
+// RevokeCertificate отзывает сертификат
+func (s *DBStorage) RevokeCertificate(code string, adminID int, reason string) (models.Certificate, error) {
+	ctx, done := s.startQuery("RevokeCertificate")
+	defer done()
+
+	var cert models.Certificate
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		cert, err = getCertificateByCode(ctx, tx, code)
+		if err != nil {
+			return err
+		}
+		if cert.RevokedAt != nil {
+			return ErrCertificateRevoked
+		}
+		now := time.Now().UTC()
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE certificates SET revoked_at = ?, revoked_by = ?, revocation_reason = ? WHERE id = ?",
+			now, adminID, reason, cert.ID); err != nil {
+			return fmt.Errorf("revoke certificate: %w", err)
+		}
+		cert.RevokedAt, cert.RevokedBy, cert.RevocationReason = &now, &adminID, reason
+		return nil
+	})
+	return cert, err
+}
+
+func getCertificateByCode(ctx context.Context, q queryer, code string) (models.Certificate, error) {
+	cert, err := scanCertificate(q.QueryRowContext(ctx, "SELECT "+certificateColumns+" FROM certificates WHERE code = ?", code))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Certificate{}, ErrCertificateNotFound
+	}
+	return cert, err
+}
+
+func scanCertificate(row rowScanner) (models.Certificate, error) {
+	var cert models.Certificate
+	var courseID, revokedBy sql.NullInt64
+	var revokedAt sql.NullTime
+	err := row.Scan(&cert.ID, &cert.Code, &cert.UserID, &courseID, &cert.IssuedTo, &cert.CourseTitle,
+		&cert.IssuedAt, &revokedAt, &revokedBy, &cert.RevocationReason)
+	if errors.Is(err, sql.ErrNoRows) {
+		return cert, err
+	}
+	if err != nil {
+		return cert, fmt.Errorf("scan certificate: %w", err)
+	}
+	cert.CourseID, cert.RevokedBy = intOrNil(courseID), intOrNil(revokedBy)
+	if revokedAt.Valid {
+		cert.RevokedAt = &revokedAt.Time
+	}
+	return cert, nil
+}
@@ -0,0 +1,200 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrChatChannelNotFound — канала Slack или Mattermost нет
+var ErrChatChannelNotFound = errors.New("chat channel not found")
+
+const chatChannelColumns = "id, group_name, provider, webhook_url, bot_token, server_url, channel, course_id, locale, " +
+	"notify_course_published, notify_leaderboard, notify_deadlines, last_leaderboard_at, created_by, created_at, updated_at"
+
+// CreateChatChannel сохраняет канал группы
+func (s *DBStorage) CreateChatChannel(channel models.ChatChannel) (models.ChatChannel, error) {
+	ctx, done := s.startQuery("CreateChatChannel")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := groupExists(ctx, tx, channel.Group, 0); err != nil {
+			return err
+		}
+		if channel.CourseID != nil {
+			if err := courseExists(ctx, tx, *channel.CourseID); err != nil {
+				return err
+			}
+		}
+		now := time.Now().UTC().Truncate(time.Second)
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO chat_channels (group_name, provider, webhook_url, bot_token, server_url, channel, course_id, locale, "+
+				"notify_course_published, notify_leaderboard, notify_deadlines, created_by, created_at, updated_at) "+
+				"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			channel.Group, channel.Provider, channel.WebhookURL, channel.BotToken, channel.ServerURL, channel.Channel,
+			channel.CourseID, channel.Locale, channel.NotifyCoursePublished, channel.NotifyLeaderboard, channel.NotifyDeadlines,
+			channel.CreatedBy, now, now)
+		if err != nil {
+			return fmt.Errorf("insert chat channel: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get chat channel id: %w", err)
+		}
+		channel, err = getChatChannel(ctx, tx, int(id))
+		return err
+	})
+	return channel, err
+}
+
+// UpdateChatChannel изменяет канал
+func (s *DBStorage) UpdateChatChannel(channel models.ChatChannel) (models.ChatChannel, error) {
+	ctx, done := s.startQuery("UpdateChatChannel")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := getChatChannel(ctx, tx, channel.ID); err != nil {
+			return err
+		}
+		if err := groupExists(ctx, tx, channel.Group, 0); err != nil {
+			return err
+		}
+		if channel.CourseID != nil {
+			if err := courseExists(ctx, tx, *channel.CourseID); err != nil {
+				return err
+			}
+		}
+		_, err := tx.ExecContext(ctx,
+			"UPDATE chat_channels SET group_name = ?, provider = ?, webhook_url = ?, bot_token = ?, server_url = ?, channel = ?, "+
+				"course_id = ?, locale = ?, notify_course_published = ?, notify_leaderboard = ?, notify_deadlines = ?, updated_at = ? "+
+				"WHERE id = ?",
+			channel.Group, channel.Provider, channel.WebhookURL, channel.BotToken, channel.ServerURL, channel.Channel,
+			channel.CourseID, channel.Locale, channel.NotifyCoursePublished, channel.NotifyLeaderboard, channel.NotifyDeadlines,
+			time.Now().UTC().Truncate(time.Second), channel.ID)
+		if err != nil {
+			return fmt.Errorf("update chat channel: %w", err)
+		}
+		channel, err = getChatChannel(ctx, tx, channel.ID)
+		return err
+	})
+	return channel, err
+}
+
+// DeleteChatChannel удаляет канал
+func (s *DBStorage) DeleteChatChannel(id int) error {
+	ctx, done := s.startQuery("DeleteChatChannel")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM chat_channels WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete chat channel: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrChatChannelNotFound
+	}
+	return nil
+}
+
+// GetChatChannel возвращает канал
+func (s *DBStorage) GetChatChannel(id int) (models.ChatChannel, error) {
+	ctx, done := s.startQuery("GetChatChannel")
+	defer done()
+
+	return getChatChannel(ctx, s.DB, id)
+}
+
+// ListChatChannels возвращает все каналы
+func (s *DBStorage) ListChatChannels() ([]models.ChatChannel, error) {
+	ctx, done := s.startQuery("ListChatChannels")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx, "SELECT "+chatChannelColumns+" FROM chat_channels ORDER BY group_name, id")
+	if err != nil {
+		return nil, fmt.Errorf("query chat channels: %w", err)
+	}
+	defer rows.Close()
+
+	channels := []models.ChatChannel{}
+	for rows.Next() {
+		channel, err := scanChatChannel(rows)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, channel)
+	}
+	return channels, rows.Err()
+}
+
+// SetChatLeaderboardSent запоминает время отправки таблицы лидеров
+func (s *DBStorage) SetChatLeaderboardSent(id int, at time.Time) error {
+	ctx, done := s.startQuery("SetChatLeaderboardSent")
+	defer done()
+
+	if _, err := s.DB.ExecContext(ctx, "UPDATE chat_channels SET last_leaderboard_at = ? WHERE id = ?", at.UTC(), id); err != nil {
+		return fmt.Errorf("save leaderboard time: %w", err)
+	}
+	return nil
+}
+
+// MarkChatDeadlineReminder отмечает напоминание о сроке задания в канале
+func (s *DBStorage) MarkChatDeadlineReminder(channelID, taskID int, dueAt time.Time) (bool, error) {
+	ctx, done := s.startQuery("MarkChatDeadlineReminder")
+	defer done()
+
+	marked := false
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var exists int
+		err := tx.QueryRowContext(ctx,
+			"SELECT 1 FROM chat_deadline_reminders WHERE channel_id = ? AND task_id = ? AND due_at = ?",
+			channelID, taskID, dueAt.UTC()).Scan(&exists)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("get chat reminder: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO chat_deadline_reminders (channel_id, task_id, due_at, sent_at) VALUES (?, ?, ?, ?)",
+			channelID, taskID, dueAt.UTC(), time.Now().UTC()); err != nil {
+			return fmt.Errorf("save chat reminder: %w", err)
+		}
+		marked = true
+		return nil
+	})
+	return marked, err
+}
+
+func getChatChannel(ctx context.Context, q queryer, id int) (models.ChatChannel, error) {
+	channel, err := scanChatChannel(q.QueryRowContext(ctx, "SELECT "+chatChannelColumns+" FROM chat_channels WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ChatChannel{}, ErrChatChannelNotFound
+	}
+	return channel, err
+}
+
+func scanChatChannel(row rowScanner) (models.ChatChannel, error) {
+	var channel models.ChatChannel
+	var courseID, createdBy sql.NullInt64
+	var lastLeaderboardAt sql.NullTime
+	err := row.Scan(&channel.ID, &channel.Group, &channel.Provider, &channel.WebhookURL, &channel.BotToken,
+		&channel.ServerURL, &channel.Channel, &courseID, &channel.Locale, &channel.NotifyCoursePublished,
+		&channel.NotifyLeaderboard, &channel.NotifyDeadlines, &lastLeaderboardAt, &createdBy,
+		&channel.CreatedAt, &channel.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return channel, err
+	}
+	if err != nil {
+		return channel, fmt.Errorf("scan chat channel: %w", err)
+	}
+	channel.CourseID = intOrNil(courseID)
+	if createdBy.Valid {
+		channel.CreatedBy = int(createdBy.Int64)
+	}
+	if lastLeaderboardAt.Valid {
+		channel.LastLeaderboardAt = &lastLeaderboardAt.Time
+	}
+	return channel, nil
+}
@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"expvar"
+	"fmt"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Метрики доступности базы данных
+var (
+	dbUp              = expvar.NewInt("db_up")
+	dbConnectAttempts = expvar.NewInt("db_connect_attempts_total")
+	dbPingFailures    = expvar.NewInt("db_ping_failures_total")
+	dbReconnects      = expvar.NewInt("db_reconnects_total")
+)
+
+// RetryPolicy задает ожидание базы данных при старте: экспоненциальная задержка между
+// попытками от InitialBackoff до MaxBackoff, но не дольше Timeout в сумме
+type RetryPolicy struct {
+	Timeout        time.Duration
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// WaitForDB пингует базу данных, пока она не ответит или не истечет policy.Timeout
+func WaitForDB(ctx context.Context, db *sql.DB, policy RetryPolicy) error {
+	ctx, cancel := context.WithTimeout(ctx, policy.Timeout)
+	defer cancel()
+
+	backoff := policy.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		dbConnectAttempts.Add(1)
+		err := db.PingContext(ctx)
+		if err == nil {
+			markDBUp(true)
+			return nil
+		}
+		// Ожидание при старте не считается потерей соединения, поэтому dbWasDown не меняется
+		dbUp.Set(0)
+
+		if ctx.Err() != nil {
+			return fmt.Errorf("database not reachable after %d attempts in %s: %w", attempt, policy.Timeout, err)
+		}
+		log.Printf("Database not ready (attempt %d): %v, retrying in %s", attempt, err, backoff)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database not reachable after %d attempts in %s: %w", attempt, policy.Timeout, err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if backoff > policy.MaxBackoff {
+			backoff = policy.MaxBackoff
+		}
+	}
+}
+
+// PingDB проверяет соединение с базой данных и обновляет метрики доступности.
+// Восстановление после неудачного пинга учитывается как переподключение.
+func PingDB(ctx context.Context, db *sql.DB) error {
+	if err := db.PingContext(ctx); err != nil {
+		dbPingFailures.Add(1)
+		markDBUp(false)
+		return err
+	}
+	markDBUp(true)
+	return nil
+}
+
+// poolStatsDB — пул, статистика которого публикуется как метрика db_pool
+var poolStatsDB atomic.Pointer[sql.DB]
+
+func init() {
+	expvar.Publish("db_pool", expvar.Func(func() interface{} {
+		db := poolStatsDB.Load()
+		if db == nil {
+			return nil
+		}
+		return db.Stats()
+	}))
+}
+
+// PublishPoolStats включает публикацию sql.DBStats пула db в метрике db_pool
+func PublishPoolStats(db *sql.DB) {
+	poolStatsDB.Store(db)
+}
+
+var (
+	dbStateMu sync.Mutex
+	dbWasDown bool
+)
+
+func markDBUp(up bool) {
+	dbStateMu.Lock()
+	defer dbStateMu.Unlock()
+
+	if up {
+		if dbWasDown {
+			dbReconnects.Add(1)
+			log.Println("Database connection restored")
+		}
+		dbWasDown = false
+		dbUp.Set(1)
+		return
+	}
+	dbWasDown = true
+	dbUp.Set(0)
+}
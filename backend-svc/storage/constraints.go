@@ -0,0 +1,55 @@
+package storage
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// Ошибки нарушения уникальности, переведенные из ошибок драйвера базы данных
+var (
+	ErrDuplicateUsername = errors.New("username already exists")
+	ErrDuplicateEmail    = errors.New("email already exists")
+)
+
+const (
+	mysqlErrDuplicateEntry     = 1062
+	sqliteConstraintUnique     = 2067
+	sqliteConstraintPrimaryKey = 1555
+)
+
+// translateUserConstraint превращает нарушение уникального индекса users.username или
+// users.email в ErrDuplicateUsername/ErrDuplicateEmail; остальные ошибки возвращаются как есть
+func translateUserConstraint(err error) error {
+	var message string
+
+	var mysqlErr *mysql.MySQLError
+	var sqliteErr interface{ Code() int }
+	switch {
+	case errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry:
+		// MySQL 8: "for key 'users.username'", MySQL 5.7: "for key 'username'"
+		message = mysqlErr.Message
+	case errors.As(err, &sqliteErr) && sqliteErr.Code() == sqliteConstraintUnique:
+		// "UNIQUE constraint failed: users.username"
+		message = err.Error()
+	default:
+		return err
+	}
+
+	switch {
+	case strings.Contains(message, "username'") || strings.Contains(message, "users.username"):
+		return ErrDuplicateUsername
+	case strings.Contains(message, "email'") || strings.Contains(message, "users.email"):
+		return ErrDuplicateEmail
+	}
+	return err
+}
+
+// isUniqueViolation сообщает, нарушает ли ошибка уникальный индекс или первичный ключ
+func isUniqueViolation(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	var sqliteErr interface{ Code() int }
+	return (errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDuplicateEntry) ||
+		(errors.As(err, &sqliteErr) && (sqliteErr.Code() == sqliteConstraintUnique || sqliteErr.Code() == sqliteConstraintPrimaryKey))
+}
@@ -0,0 +1,254 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrTranslationNotFound — перевода на этот язык нет
+var ErrTranslationNotFound = errors.New("translation not found")
+
+// GetCourseTranslations возвращает переводы курсов на язык locale по ID курса
+func (s *DBStorage) GetCourseTranslations(locale string) (map[int]models.CourseTranslation, error) {
+	ctx, done := s.startQuery("GetCourseTranslations")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.reader(),
+		"SELECT course_id, locale, vulnerability_type, description, updated_at FROM course_translations WHERE locale = ?")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, locale)
+	if err != nil {
+		return nil, fmt.Errorf("query course translations: %w", err)
+	}
+	defer rows.Close()
+
+	translations := make(map[int]models.CourseTranslation)
+	for rows.Next() {
+		t, err := scanCourseTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations[t.CourseID] = t
+	}
+	return translations, rows.Err()
+}
+
+// GetTaskTranslations возвращает переводы заданий курса на язык locale по ID задания
+func (s *DBStorage) GetTaskTranslations(courseID int, locale string) (map[int]models.TaskTranslation, error) {
+	ctx, done := s.startQuery("GetTaskTranslations")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.reader(),
+		"SELECT tt.task_id, tt.locale, tt.title, tt.description, tt.updated_at FROM task_translations tt "+
+			"JOIN tasks t ON t.id = tt.task_id WHERE t.course_id = ? AND tt.locale = ?")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, courseID, locale)
+	if err != nil {
+		return nil, fmt.Errorf("query task translations: %w", err)
+	}
+	defer rows.Close()
+
+	translations := make(map[int]models.TaskTranslation)
+	for rows.Next() {
+		t, err := scanTaskTranslation(rows)
+		if err != nil {
+			return nil, err
+		}
+		translations[t.TaskID] = t
+	}
+	return translations, rows.Err()
+}
+
+// ListCourseTranslations возвращает все переводы курса и его заданий
+func (s *DBStorage) ListCourseTranslations(courseID int) (models.CourseTranslations, error) {
+	ctx, done := s.startQuery("ListCourseTranslations")
+	defer done()
+
+	result := models.CourseTranslations{
+		CourseID: courseID,
+		Course:   []models.CourseTranslation{},
+		Tasks:    []models.TaskTranslation{},
+	}
+	if err := courseExists(ctx, s.DB, courseID); err != nil {
+		return result, err
+	}
+
+	courseRows, err := s.DB.QueryContext(ctx,
+		"SELECT course_id, locale, vulnerability_type, description, updated_at FROM course_translations "+
+			"WHERE course_id = ? ORDER BY locale", courseID)
+	if err != nil {
+		return result, fmt.Errorf("query course translations: %w", err)
+	}
+	defer courseRows.Close()
+	for courseRows.Next() {
+		t, err := scanCourseTranslation(courseRows)
+		if err != nil {
+			return result, err
+		}
+		result.Course = append(result.Course, t)
+	}
+	if err := courseRows.Err(); err != nil {
+		return result, err
+	}
+
+	taskRows, err := s.DB.QueryContext(ctx,
+		"SELECT tt.task_id, tt.locale, tt.title, tt.description, tt.updated_at FROM task_translations tt "+
+			"JOIN tasks t ON t.id = tt.task_id WHERE t.course_id = ? ORDER BY t.task_order, tt.locale", courseID)
+	if err != nil {
+		return result, fmt.Errorf("query task translations: %w", err)
+	}
+	defer taskRows.Close()
+	for taskRows.Next() {
+		t, err := scanTaskTranslation(taskRows)
+		if err != nil {
+			return result, err
+		}
+		result.Tasks = append(result.Tasks, t)
+	}
+	return result, taskRows.Err()
+}
+
+// SetCourseTranslation создает или заменяет перевод курса
+func (s *DBStorage) SetCourseTranslation(t models.CourseTranslation) error {
+	ctx, done := s.startQuery("SetCourseTranslation")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := courseExists(ctx, tx, t.CourseID); err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO course_translations (course_id, locale, vulnerability_type, description, updated_at) "+
+				"VALUES (?, ?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"course_id", "locale"}, "vulnerability_type", "description", "updated_at"),
+			t.CourseID, t.Locale, t.VulnerabilityType, t.Description, now)
+		if err != nil {
+			return fmt.Errorf("save course translation: %w", err)
+		}
+		return touchCourse(ctx, tx, t.CourseID, now)
+	})
+}
+
+// DeleteCourseTranslation удаляет перевод курса
+func (s *DBStorage) DeleteCourseTranslation(courseID int, locale string) error {
+	ctx, done := s.startQuery("DeleteCourseTranslation")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "DELETE FROM course_translations WHERE course_id = ? AND locale = ?", courseID, locale)
+		if err != nil {
+			return fmt.Errorf("delete course translation: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrTranslationNotFound
+		}
+		return touchCourse(ctx, tx, courseID, time.Now().UTC())
+	})
+}
+
+// SetTaskTranslation создает или заменяет перевод задания
+func (s *DBStorage) SetTaskTranslation(t models.TaskTranslation) error {
+	ctx, done := s.startQuery("SetTaskTranslation")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		courseID, err := taskCourseID(ctx, tx, t.TaskID)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO task_translations (task_id, locale, title, description, updated_at) VALUES (?, ?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"task_id", "locale"}, "title", "description", "updated_at"),
+			t.TaskID, t.Locale, t.Title, t.Description, now)
+		if err != nil {
+			return fmt.Errorf("save task translation: %w", err)
+		}
+		return touchCourse(ctx, tx, courseID, now)
+	})
+}
+
+// DeleteTaskTranslation удаляет перевод задания
+func (s *DBStorage) DeleteTaskTranslation(taskID int, locale string) error {
+	ctx, done := s.startQuery("DeleteTaskTranslation")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		courseID, err := taskCourseID(ctx, tx, taskID)
+		if err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "DELETE FROM task_translations WHERE task_id = ? AND locale = ?", taskID, locale)
+		if err != nil {
+			return fmt.Errorf("delete task translation: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrTranslationNotFound
+		}
+		return touchCourse(ctx, tx, courseID, time.Now().UTC())
+	})
+}
+
+// queryer — общее подмножество *sql.DB и *sql.Tx для чтения одной строки
+type queryer interface {
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// courseExists возвращает ErrCourseNotFound, если курса нет
+func courseExists(ctx context.Context, q queryer, courseID int) error {
+	var id int
+	err := q.QueryRowContext(ctx, "SELECT id FROM courses WHERE id = ?", courseID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCourseNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("check course: %w", err)
+	}
+	return nil
+}
+
+// taskCourseID возвращает курс задания или ErrTaskNotFound
+func taskCourseID(ctx context.Context, tx *sql.Tx, taskID int) (int, error) {
+	var courseID int
+	err := tx.QueryRowContext(ctx, "SELECT course_id FROM tasks WHERE id = ?", taskID).Scan(&courseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrTaskNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get task course: %w", err)
+	}
+	return courseID, nil
+}
+
+// touchCourse сдвигает courses.updated_at, по которому строятся ETag и Last-Modified каталога
+func touchCourse(ctx context.Context, tx *sql.Tx, courseID int, at time.Time) error {
+	if _, err := tx.ExecContext(ctx, "UPDATE courses SET updated_at = ? WHERE id = ?", at, courseID); err != nil {
+		return fmt.Errorf("touch course: %w", err)
+	}
+	return nil
+}
+
+func scanCourseTranslation(row rowScanner) (models.CourseTranslation, error) {
+	var t models.CourseTranslation
+	if err := row.Scan(&t.CourseID, &t.Locale, &t.VulnerabilityType, &t.Description, &t.UpdatedAt); err != nil {
+		return t, fmt.Errorf("scan course translation: %w", err)
+	}
+	return t, nil
+}
+
+func scanTaskTranslation(row rowScanner) (models.TaskTranslation, error) {
+	var t models.TaskTranslation
+	if err := row.Scan(&t.TaskID, &t.Locale, &t.Title, &t.Description, &t.UpdatedAt); err != nil {
+		return t, fmt.Errorf("scan task translation: %w", err)
+	}
+	return t, nil
+}
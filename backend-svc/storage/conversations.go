@@ -0,0 +1,310 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrConversationNotFound — переписки нет
+	ErrConversationNotFound = errors.New("conversation not found")
+	// ErrMessageNotFound — сообщения нет
+	ErrMessageNotFound = errors.New("message not found")
+)
+
+const (
+	conversationColumns = "id, course_id, student_id, instructor_id, last_message_at, created_at"
+	messageColumns      = "id, conversation_id, sender_id, body, task_id, reported_at, reported_by, hidden_at, hidden_by, created_at"
+)
+
+// OpenConversation возвращает переписку участников по курсу, создавая ее при необходимости
+func (s *DBStorage) OpenConversation(courseID, studentID, instructorID int) (models.Conversation, error) {
+	ctx, done := s.startQuery("OpenConversation")
+	defer done()
+
+	var conversation models.Conversation
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := courseExists(ctx, tx, courseID); err != nil {
+			return err
+		}
+		// Повторная вставка тех же участников ничего не меняет, поэтому две одновременные
+		// первые реплики попадают в одну переписку
+		now := time.Now().UTC()
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO conversations (course_id, student_id, instructor_id, last_message_at, created_at) VALUES (?, ?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"course_id", "student_id", "instructor_id"}, "course_id"),
+			courseID, studentID, instructorID, now, now)
+		if err != nil {
+			return fmt.Errorf("insert conversation: %w", err)
+		}
+		conversation, err = scanConversation(tx.QueryRowContext(ctx,
+			"SELECT "+conversationColumns+" FROM conversations WHERE course_id = ? AND student_id = ? AND instructor_id = ?",
+			courseID, studentID, instructorID))
+		return err
+	})
+	return conversation, err
+}
+
+// GetConversation возвращает переписку по ID
+func (s *DBStorage) GetConversation(id int) (models.Conversation, error) {
+	ctx, done := s.startQuery("GetConversation")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+conversationColumns+" FROM conversations WHERE id = ?")
+	if err != nil {
+		return models.Conversation{}, err
+	}
+	conversation, err := scanConversation(stmt.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Conversation{}, ErrConversationNotFound
+	}
+	return conversation, err
+}
+
+// ListConversations возвращает переписки пользователя с числом непрочитанных сообщений
+func (s *DBStorage) ListConversations(userID int) ([]models.Conversation, error) {
+	ctx, done := s.startQuery("ListConversations")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.reader(),
+		"SELECT c.id, c.course_id, c.student_id, c.instructor_id, c.last_message_at, c.created_at, "+
+			"(SELECT COUNT(*) FROM conversation_messages m WHERE m.conversation_id = c.id AND m.hidden_at IS NULL "+
+			"AND (m.sender_id IS NULL OR m.sender_id <> ?) AND m.id > COALESCE("+
+			"(SELECT r.last_read_message_id FROM conversation_reads r WHERE r.conversation_id = c.id AND r.user_id = ?), 0)) "+
+			"FROM conversations c WHERE c.student_id = ? OR c.instructor_id = ? "+
+			"ORDER BY c.last_message_at DESC, c.id DESC")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, userID, userID, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query conversations: %w", err)
+	}
+	defer rows.Close()
+
+	conversations := []models.Conversation{}
+	for rows.Next() {
+		var c models.Conversation
+		if err := rows.Scan(&c.ID, &c.CourseID, &c.StudentID, &c.InstructorID, &c.LastMessageAt, &c.CreatedAt, &c.Unread); err != nil {
+			return nil, fmt.Errorf("scan conversation: %w", err)
+		}
+		conversations = append(conversations, c)
+	}
+	return conversations, rows.Err()
+}
+
+// AddConversationMessage сохраняет сообщение и сдвигает время последнего сообщения переписки
+func (s *DBStorage) AddConversationMessage(m models.ConversationMessage) (models.ConversationMessage, error) {
+	ctx, done := s.startQuery("AddConversationMessage")
+	defer done()
+
+	m.CreatedAt = time.Now().UTC()
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "UPDATE conversations SET last_message_at = ? WHERE id = ?", m.CreatedAt, m.ConversationID)
+		if err != nil {
+			return fmt.Errorf("touch conversation: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrConversationNotFound
+		}
+		res, err = tx.ExecContext(ctx,
+			"INSERT INTO conversation_messages (conversation_id, sender_id, body, task_id, created_at) VALUES (?, ?, ?, ?, ?)",
+			m.ConversationID, m.SenderID, m.Body, m.TaskID, m.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("insert message: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get message id: %w", err)
+		}
+		m.ID = int(id)
+		return nil
+	})
+	return m, err
+}
+
+// GetConversationMessage возвращает сообщение по ID
+func (s *DBStorage) GetConversationMessage(id int) (models.ConversationMessage, error) {
+	ctx, done := s.startQuery("GetConversationMessage")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+messageColumns+" FROM conversation_messages WHERE id = ?")
+	if err != nil {
+		return models.ConversationMessage{}, err
+	}
+	m, err := scanConversationMessage(stmt.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ConversationMessage{}, ErrMessageNotFound
+	}
+	return m, err
+}
+
+// ListConversationMessages возвращает страницу сообщений переписки в хронологическом порядке
+func (s *DBStorage) ListConversationMessages(conversationID, beforeID, limit int) ([]models.ConversationMessage, error) {
+	ctx, done := s.startQuery("ListConversationMessages")
+	defer done()
+
+	query := "SELECT " + messageColumns + " FROM conversation_messages WHERE conversation_id = ?"
+	args := []interface{}{conversationID}
+	if beforeID > 0 {
+		query += " AND id < ?"
+		args = append(args, beforeID)
+	}
+	query += " ORDER BY id DESC"
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []models.ConversationMessage{}
+	for rows.Next() {
+		m, err := scanConversationMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	return messages, nil
+}
+
+// MarkConversationRead отмечает прочитанными сообщения переписки до messageID включительно
+func (s *DBStorage) MarkConversationRead(conversationID, userID, messageID int) error {
+	ctx, done := s.startQuery("MarkConversationRead")
+	defer done()
+
+	_, err := s.DB.ExecContext(ctx,
+		"INSERT INTO conversation_reads (conversation_id, user_id, last_read_message_id) VALUES (?, ?, ?)"+
+			s.onConflictUpdate([]string{"conversation_id", "user_id"}, "last_read_message_id"),
+		conversationID, userID, messageID)
+	if err != nil {
+		return fmt.Errorf("mark conversation read: %w", err)
+	}
+	return nil
+}
+
+// ReportConversationMessage отправляет сообщение на модерацию
+func (s *DBStorage) ReportConversationMessage(messageID, reporterID int) error {
+	ctx, done := s.startQuery("ReportConversationMessage")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx,
+		"UPDATE conversation_messages SET reported_at = ?, reported_by = ? WHERE id = ?",
+		time.Now().UTC(), reporterID, messageID)
+	if err != nil {
+		return fmt.Errorf("report message: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrMessageNotFound
+	}
+	return nil
+}
+
+// ModerateConversationMessage скрывает или возвращает сообщение и снимает с него жалобу
+func (s *DBStorage) ModerateConversationMessage(messageID, moderatorID int, hidden bool) error {
+	ctx, done := s.startQuery("ModerateConversationMessage")
+	defer done()
+
+	var hiddenAt, hiddenBy interface{}
+	if hidden {
+		hiddenAt, hiddenBy = time.Now().UTC(), moderatorID
+	}
+	res, err := s.DB.ExecContext(ctx,
+		"UPDATE conversation_messages SET hidden_at = ?, hidden_by = ?, reported_at = NULL, reported_by = NULL WHERE id = ?",
+		hiddenAt, hiddenBy, messageID)
+	if err != nil {
+		return fmt.Errorf("moderate message: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		// MySQL не считает строку, значения которой не изменились, поэтому проверяем, есть ли сообщение
+		var found int
+		err := s.DB.QueryRowContext(ctx, "SELECT 1 FROM conversation_messages WHERE id = ?", messageID).Scan(&found)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrMessageNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("check message: %w", err)
+		}
+	}
+	return nil
+}
+
+// ListReportedMessages возвращает сообщения с нерассмотренными жалобами
+func (s *DBStorage) ListReportedMessages(limit int) ([]models.ConversationMessage, error) {
+	ctx, done := s.startQuery("ListReportedMessages")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT "+messageColumns+" FROM conversation_messages WHERE reported_at IS NOT NULL ORDER BY reported_at LIMIT ?", limit)
+	if err != nil {
+		return nil, fmt.Errorf("query reported messages: %w", err)
+	}
+	defer rows.Close()
+
+	messages := []models.ConversationMessage{}
+	for rows.Next() {
+		m, err := scanConversationMessage(rows)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func scanConversation(row rowScanner) (models.Conversation, error) {
+	var c models.Conversation
+	err := row.Scan(&c.ID, &c.CourseID, &c.StudentID, &c.InstructorID, &c.LastMessageAt, &c.CreatedAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return c, fmt.Errorf("scan conversation: %w", err)
+	}
+	return c, err
+}
+
+func scanConversationMessage(row rowScanner) (models.ConversationMessage, error) {
+	var m models.ConversationMessage
+	var senderID, taskID, reportedBy, hiddenBy sql.NullInt64
+	var reportedAt, hiddenAt sql.NullTime
+	err := row.Scan(&m.ID, &m.ConversationID, &senderID, &m.Body, &taskID, &reportedAt, &reportedBy, &hiddenAt, &hiddenBy,
+		&m.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return m, err
+		}
+		return m, fmt.Errorf("scan message: %w", err)
+	}
+	m.SenderID = intOrNil(senderID)
+	m.TaskID = intOrNil(taskID)
+	m.ReportedBy = intOrNil(reportedBy)
+	m.HiddenBy = intOrNil(hiddenBy)
+	if reportedAt.Valid {
+		m.ReportedAt = &reportedAt.Time
+	}
+	if hiddenAt.Valid {
+		m.HiddenAt = &hiddenAt.Time
+	}
+	return m, nil
+}
+
+// intOrNil переводит необязательный ID из базы в указатель
+func intOrNil(v sql.NullInt64) *int {
+	if !v.Valid {
+		return nil
+	}
+	id := int(v.Int64)
+	return &id
+}
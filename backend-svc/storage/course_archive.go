@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// courseStartedCondition — пользователь (первый параметр) выполнил хотя бы одно задание курса c
+const courseStartedCondition = `EXISTS (SELECT 1 FROM user_progress p JOIN tasks pt ON pt.id = p.task_id
+	WHERE p.user_id = ? AND pt.course_id = c.id)`
+
+// ArchiveCourse переносит курс в архив. Прогресс, попытки и сертификаты слушателей
+// сохраняются; повторная архивация ничего не меняет.
+func (s *DBStorage) ArchiveCourse(courseID int) error {
+	ctx, done := s.startQuery("ArchiveCourse")
+	defer done()
+
+	now := time.Now().UTC()
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.courseInOrganization(ctx, tx, courseID); err != nil {
+			return err
+		}
+		// updated_at меняется, чтобы клиенты с кэшем каталога получили новый ETag
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE courses SET archived_at = ?, updated_at = ? WHERE id = ? AND archived_at IS NULL",
+			now, now, courseID); err != nil {
+			return fmt.Errorf("archive course: %w", err)
+		}
+		return nil
+	})
+}
+
+// RestoreCourse возвращает курс из архива в каталог
+func (s *DBStorage) RestoreCourse(courseID int) error {
+	ctx, done := s.startQuery("RestoreCourse")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.courseInOrganization(ctx, tx, courseID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE courses SET archived_at = NULL, updated_at = ? WHERE id = ? AND archived_at IS NOT NULL",
+			time.Now().UTC(), courseID); err != nil {
+			return fmt.Errorf("restore course: %w", err)
+		}
+		return nil
+	})
+}
+
+// PurgeCourse безвозвратно удаляет архивный курс вместе с заданиями, прогрессом и
+// обсуждениями (каскадные ключи). Выданные сертификаты остаются без ссылки на курс.
+func (s *DBStorage) PurgeCourse(courseID int) error {
+	ctx, done := s.startQuery("PurgeCourse")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		var archivedAt sql.NullTime
+		orgAnd, orgArgs := s.orgFilter("AND", "organization_id")
+		err := tx.QueryRowContext(ctx, "SELECT archived_at FROM courses WHERE id = ?"+orgAnd,
+			append([]any{courseID}, orgArgs...)...).Scan(&archivedAt)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCourseNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("check course: %w", err)
+		}
+		if !archivedAt.Valid {
+			return ErrCourseNotArchived
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM courses WHERE id = ?", courseID); err != nil {
+			return fmt.Errorf("purge course: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListArchivedCourses возвращает архивные курсы со сводкой, недавно архивированные первыми
+func (s *DBStorage) ListArchivedCourses() ([]models.Course, error) {
+	ctx, done := s.startQuery("ListArchivedCourses")
+	defer done()
+
+	orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT c.id, c.vulnerability_type, COALESCE(cs.tasks_count, 0), COALESCE(cs.enrollments, 0),
+			c.description, c.updated_at, c.archived_at
+		FROM courses c
+		LEFT JOIN course_summary cs ON cs.course_id = c.id
+		WHERE c.archived_at IS NOT NULL`+orgAnd+`
+		ORDER BY c.archived_at DESC, c.id`, orgArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("query archived courses: %w", err)
+	}
+	defer rows.Close()
+
+	courses := []models.Course{}
+	for rows.Next() {
+		var course models.Course
+		var archivedAt time.Time
+		if err := rows.Scan(&course.ID, &course.VulnerabilityType, &course.TasksCount, &course.Enrollments,
+			&course.Description, &course.UpdatedAt, &archivedAt); err != nil {
+			return nil, fmt.Errorf("scan archived course: %w", err)
+		}
+		course.ArchivedAt = &archivedAt
+		courses = append(courses, course)
+	}
+	return courses, rows.Err()
+}
@@ -0,0 +1,179 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrNoCourseProgress — у пользователя нет прогресса по курсу, сбрасывать нечего
+var ErrNoCourseProgress = errors.New("no course progress")
+
+// recertificationResetReason — причина сброса прогресса по истечении цикла сертификации
+const recertificationResetReason = "Recertification period expired"
+
+// archiveCourseProgress переносит выполненные задания и попытки SCORM пользователя по курсу
+// в новую архивную попытку и удаляет их из текущего прогресса
+func archiveCourseProgress(ctx context.Context, tx *sql.Tx, userID, courseID int, resetBy *int, reason string, at time.Time) (models.CourseAttempt, error) {
+	attempt := models.CourseAttempt{
+		UserID:   userID,
+		CourseID: courseID,
+		ResetBy:  resetBy,
+		Reason:   reason,
+		ResetAt:  at.UTC().Truncate(time.Second),
+	}
+	var scormAttempts int
+	err := tx.QueryRowContext(ctx, `
+		SELECT COUNT(*), COUNT(up.task_id), COUNT(sa.task_id)
+		FROM tasks t
+		LEFT JOIN user_progress up ON up.task_id = t.id AND up.user_id = ?
+		LEFT JOIN scorm_attempts sa ON sa.task_id = t.id AND sa.user_id = ?
+		WHERE t.course_id = ?`, userID, userID, courseID).
+		Scan(&attempt.TasksTotal, &attempt.TasksCompleted, &scormAttempts)
+	if err != nil {
+		return attempt, fmt.Errorf("count course progress: %w", err)
+	}
+	if attempt.TasksCompleted == 0 && scormAttempts == 0 {
+		return attempt, ErrNoCourseProgress
+	}
+
+	res, err := tx.ExecContext(ctx,
+		"INSERT INTO course_attempts (user_id, course_id, tasks_completed, tasks_total, reset_by, reason, reset_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		userID, courseID, attempt.TasksCompleted, attempt.TasksTotal, resetBy, reason, attempt.ResetAt)
+	if err != nil {
+		return attempt, fmt.Errorf("insert course attempt: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return attempt, fmt.Errorf("get course attempt id: %w", err)
+	}
+	attempt.ID = int(id)
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO course_attempt_tasks (attempt_id, task_id, completed_at, scorm_status, scorm_score, scorm_data)
+		SELECT ?, t.id, up.completed_at, sa.status, sa.score, sa.cmi_data
+		FROM tasks t
+		LEFT JOIN user_progress up ON up.task_id = t.id AND up.user_id = ?
+		LEFT JOIN scorm_attempts sa ON sa.task_id = t.id AND sa.user_id = ?
+		WHERE t.course_id = ? AND (up.task_id IS NOT NULL OR sa.task_id IS NOT NULL)`,
+		attempt.ID, userID, userID, courseID); err != nil {
+		return attempt, fmt.Errorf("archive course progress: %w", err)
+	}
+	for _, table := range []string{"user_progress", "scorm_attempts"} {
+		if _, err := tx.ExecContext(ctx,
+			"DELETE FROM "+table+" WHERE user_id = ? AND task_id IN (SELECT id FROM tasks WHERE course_id = ?)",
+			userID, courseID); err != nil {
+			return attempt, fmt.Errorf("reset %s: %w", table, err)
+		}
+	}
+	if err := tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM course_attempts WHERE user_id = ? AND course_id = ?", userID, courseID).
+		Scan(&attempt.Number); err != nil {
+		return attempt, fmt.Errorf("count course attempts: %w", err)
+	}
+	if attempt.Tasks, err = courseAttemptTasks(ctx, tx, attempt.ID); err != nil {
+		return attempt, err
+	}
+	if err := refreshCourseSummary(ctx, tx, courseID); err != nil {
+		return attempt, err
+	}
+	return attempt, insertOutboxEvent(ctx, tx, models.EventCourseProgressReset, userID, map[string]int{
+		"userId":    userID,
+		"courseId":  courseID,
+		"attemptId": attempt.ID,
+	})
+}
+
+// courseAttemptTasks возвращает задания архивной попытки
+func courseAttemptTasks(ctx context.Context, q rowsQueryer, attemptID int) ([]models.CourseAttemptTask, error) {
+	rows, err := q.QueryContext(ctx,
+		"SELECT task_id, completed_at, scorm_status, scorm_score FROM course_attempt_tasks WHERE attempt_id = ? ORDER BY task_id",
+		attemptID)
+	if err != nil {
+		return nil, fmt.Errorf("query course attempt tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.CourseAttemptTask{}
+	for rows.Next() {
+		var task models.CourseAttemptTask
+		var completedAt sql.NullTime
+		var scormStatus sql.NullString
+		var scormScore sql.NullFloat64
+		if err := rows.Scan(&task.TaskID, &completedAt, &scormStatus, &scormScore); err != nil {
+			return nil, fmt.Errorf("scan course attempt task: %w", err)
+		}
+		task.CompletedAt = timeOrNil(completedAt)
+		task.ScormStatus = scormStatus.String
+		if scormScore.Valid {
+			task.ScormScore = &scormScore.Float64
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, rows.Err()
+}
+
+// ResetCourseProgress переносит прогресс пользователя по курсу в архив попыток, чтобы он прошел
+// курс заново. Текущий цикл сертификации по курсу при этом истекает.
+func (s *DBStorage) ResetCourseProgress(userID, courseID int, resetBy *int, reason string) (models.CourseAttempt, error) {
+	ctx, done := s.startQuery("ResetCourseProgress")
+	defer done()
+
+	var attempt models.CourseAttempt
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.courseInOrganization(ctx, tx, courseID); err != nil {
+			return err
+		}
+		var err error
+		attempt, err = archiveCourseProgress(ctx, tx, userID, courseID, resetBy, reason, time.Now())
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE course_completions SET expired_at = ? WHERE user_id = ? AND course_id = ? AND expired_at IS NULL",
+			attempt.ResetAt, userID, courseID); err != nil {
+			return fmt.Errorf("expire course completion: %w", err)
+		}
+		return nil
+	})
+	return attempt, err
+}
+
+// ListCourseAttempts возвращает архивные попытки пользователя по курсу, начиная с первой
+func (s *DBStorage) ListCourseAttempts(userID, courseID int) ([]models.CourseAttempt, error) {
+	ctx, done := s.startQuery("ListCourseAttempts")
+	defer done()
+
+	db := s.reader()
+	rows, err := db.QueryContext(ctx, `
+		SELECT id, tasks_completed, tasks_total, reset_by, reason, reset_at
+		FROM course_attempts WHERE user_id = ? AND course_id = ? ORDER BY reset_at, id`, userID, courseID)
+	if err != nil {
+		return nil, fmt.Errorf("query course attempts: %w", err)
+	}
+	attempts := []models.CourseAttempt{}
+	for rows.Next() {
+		attempt := models.CourseAttempt{UserID: userID, CourseID: courseID, Number: len(attempts) + 1}
+		var resetBy sql.NullInt64
+		if err := rows.Scan(&attempt.ID, &attempt.TasksCompleted, &attempt.TasksTotal, &resetBy, &attempt.Reason, &attempt.ResetAt); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan course attempt: %w", err)
+		}
+		attempt.ResetBy = intOrNil(resetBy)
+		attempts = append(attempts, attempt)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("read course attempts: %w", err)
+	}
+
+	for i := range attempts {
+		if attempts[i].Tasks, err = courseAttemptTasks(ctx, db, attempts[i].ID); err != nil {
+			return nil, err
+		}
+	}
+	return attempts, nil
+}
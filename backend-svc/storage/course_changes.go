@@ -0,0 +1,268 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// courseStartedAt — время, когда пользователь (первый аргумент) выполнил первое задание курса
+// (второй аргумент); NULL, если курс не начат
+const courseStartedAt = `(SELECT MIN(p.completed_at) FROM user_progress p JOIN tasks t ON t.id = p.task_id
+	WHERE p.user_id = ? AND t.course_id = ?)`
+
+// recordCourseChange добавляет запись в историю содержимого курса под следующей версией,
+// сдвигает courses.updated_at и публикует событие для поискового индекса
+func recordCourseChange(ctx context.Context, tx *sql.Tx, change models.CourseChange) error {
+	_, err := tx.ExecContext(ctx,
+		"UPDATE courses SET content_version = content_version + 1, updated_at = ? WHERE id = ?",
+		time.Now().UTC(), change.CourseID)
+	if err != nil {
+		return fmt.Errorf("bump course version: %w", err)
+	}
+	var version int
+	if err := tx.QueryRowContext(ctx, "SELECT content_version FROM courses WHERE id = ?", change.CourseID).Scan(&version); err != nil {
+		return fmt.Errorf("get course version: %w", err)
+	}
+	// created_at заполняет база: так оно сравнимо с user_progress.completed_at
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO course_changes (course_id, version, kind, task_id, title) VALUES (?, ?, ?, ?, ?)",
+		change.CourseID, version, change.Kind, change.TaskID, change.Title)
+	if err != nil {
+		return fmt.Errorf("insert course change: %w", err)
+	}
+	return insertOutboxEvent(ctx, tx, models.EventCourseContentChanged, 0, map[string]int{
+		"courseId": change.CourseID, "version": version,
+	})
+}
+
+// AddTask добавляет задание в курс; Order 0 ставит его в конец
+func (s *DBStorage) AddTask(task models.Task) (models.Task, error) {
+	ctx, done := s.startQuery("AddTask")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		orgAnd, orgArgs := s.orgFilter("AND", "organization_id")
+		var archived bool
+		err := tx.QueryRowContext(ctx, "SELECT archived_at IS NOT NULL FROM courses WHERE id = ?"+orgAnd,
+			append([]any{task.CourseID}, orgArgs...)...).Scan(&archived)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCourseNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("check course: %w", err)
+		}
+		if archived {
+			return ErrCourseArchived
+		}
+		if task.Order == 0 {
+			err := tx.QueryRowContext(ctx,
+				"SELECT COALESCE(MAX(task_order), 0) + 1 FROM tasks WHERE course_id = ?", task.CourseID).Scan(&task.Order)
+			if err != nil {
+				return fmt.Errorf("get next task order: %w", err)
+			}
+		}
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO tasks (course_id, title, description, difficulty, task_order) VALUES (?, ?, ?, ?, ?)",
+			task.CourseID, task.Title, task.Description, task.Difficulty, task.Order)
+		if err != nil {
+			return fmt.Errorf("insert task: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get task id: %w", err)
+		}
+		task.ID = int(id)
+		if err := refreshCourseSummary(ctx, tx, task.CourseID); err != nil {
+			return err
+		}
+		return recordCourseChange(ctx, tx, models.CourseChange{
+			CourseID: task.CourseID, Kind: models.CourseChangeTaskAdded, TaskID: &task.ID, Title: task.Title,
+		})
+	})
+	if err != nil {
+		return models.Task{}, err
+	}
+	return task, nil
+}
+
+// UpdateTask меняет задание курса task.CourseID; Order 0 сохраняет прежний порядок.
+// Возвращает ErrTaskNotFound, если задания нет или оно относится к другому курсу.
+func (s *DBStorage) UpdateTask(task models.Task) (models.Task, error) {
+	ctx, done := s.startQuery("UpdateTask")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+		var order int
+		var archived bool
+		err := tx.QueryRowContext(ctx,
+			"SELECT t.task_order, c.archived_at IS NOT NULL FROM tasks t JOIN courses c ON c.id = t.course_id "+
+				"WHERE t.id = ? AND t.course_id = ?"+orgAnd,
+			append([]any{task.ID, task.CourseID}, orgArgs...)...).Scan(&order, &archived)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTaskNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("get task: %w", err)
+		}
+		if archived {
+			return ErrCourseArchived
+		}
+		if task.Order == 0 {
+			task.Order = order
+		}
+		_, err = tx.ExecContext(ctx,
+			"UPDATE tasks SET title = ?, description = ?, difficulty = ?, task_order = ? WHERE id = ?",
+			task.Title, task.Description, task.Difficulty, task.Order, task.ID)
+		if err != nil {
+			return fmt.Errorf("update task: %w", err)
+		}
+		if err := refreshCourseSummary(ctx, tx, task.CourseID); err != nil {
+			return err
+		}
+		return recordCourseChange(ctx, tx, models.CourseChange{
+			CourseID: task.CourseID, Kind: models.CourseChangeTaskUpdated, TaskID: &task.ID, Title: task.Title,
+		})
+	})
+	if err != nil {
+		return models.Task{}, err
+	}
+	return task, nil
+}
+
+// seenCourseVersion возвращает последнюю версию курса, которую видел пользователь. Без отметки
+// о посещении видимыми считаются изменения до начала курса, а для неначатого курса — все.
+func seenCourseVersion(ctx context.Context, q queryer, userID, courseID, version int) (int, error) {
+	var seen int
+	err := q.QueryRowContext(ctx,
+		"SELECT seen_version FROM course_visits WHERE user_id = ? AND course_id = ?", userID, courseID).Scan(&seen)
+	if err == nil {
+		return seen, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("get course visit: %w", err)
+	}
+	var before sql.NullInt64
+	var started int
+	err = q.QueryRowContext(ctx, `
+		SELECT (SELECT MAX(cc.version) FROM course_changes cc WHERE cc.course_id = ? AND cc.created_at < `+courseStartedAt+`),
+			CASE WHEN `+courseStartedAt+` IS NULL THEN 0 ELSE 1 END`,
+		courseID, userID, courseID, userID, courseID).Scan(&before, &started)
+	if err != nil {
+		return 0, fmt.Errorf("get course start: %w", err)
+	}
+	if started == 0 {
+		return version, nil
+	}
+	return int(before.Int64), nil
+}
+
+// GetCourseChangelog возвращает не больше limit последних записей истории курса и версию,
+// которую видел пользователь
+func (s *DBStorage) GetCourseChangelog(courseID, userID, limit int) (models.CourseChangelog, error) {
+	ctx, done := s.startQuery("GetCourseChangelog")
+	defer done()
+
+	log := models.CourseChangelog{CourseID: courseID, Changes: []models.CourseChange{}}
+	err := s.reader().QueryRowContext(ctx, "SELECT content_version FROM courses WHERE id = ?", courseID).Scan(&log.Version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return log, ErrCourseNotFound
+	}
+	if err != nil {
+		return log, fmt.Errorf("get course version: %w", err)
+	}
+	if log.SeenVersion, err = seenCourseVersion(ctx, s.reader(), userID, courseID, log.Version); err != nil {
+		return log, err
+	}
+
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT id, course_id, version, kind, task_id, title, created_at FROM course_changes "+
+			"WHERE course_id = ? ORDER BY version DESC LIMIT ?", courseID, limit)
+	if err != nil {
+		return log, fmt.Errorf("query course changes: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		change, err := scanCourseChange(rows)
+		if err != nil {
+			return log, err
+		}
+		log.Changes = append(log.Changes, change)
+	}
+	return log, rows.Err()
+}
+
+// ListUnseenCourseChanges возвращает не больше limit изменений начатых пользователем курсов,
+// которых он еще не видел (см. seenCourseVersion), по курсам и от новых к старым
+func (s *DBStorage) ListUnseenCourseChanges(userID, limit int) ([]models.CourseChange, error) {
+	ctx, done := s.startQuery("ListUnseenCourseChanges")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT cc.id, cc.course_id, cc.version, cc.kind, cc.task_id, cc.title, cc.created_at
+		FROM course_changes cc
+		JOIN (SELECT t.course_id, MIN(p.completed_at) AS started_at
+			FROM user_progress p JOIN tasks t ON t.id = p.task_id
+			WHERE p.user_id = ? GROUP BY t.course_id) st ON st.course_id = cc.course_id
+		LEFT JOIN course_visits v ON v.user_id = ? AND v.course_id = cc.course_id
+		WHERE (v.seen_version IS NULL AND cc.created_at >= st.started_at) OR cc.version > v.seen_version
+		ORDER BY cc.course_id, cc.version DESC
+		LIMIT ?`, userID, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query unseen course changes: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.CourseChange{}
+	for rows.Next() {
+		change, err := scanCourseChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// MarkCourseChangesSeen отмечает, что пользователь видел текущую версию курса
+func (s *DBStorage) MarkCourseChangesSeen(userID, courseID int) error {
+	ctx, done := s.startQuery("MarkCourseChangesSeen")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		var version int
+		err := tx.QueryRowContext(ctx, "SELECT content_version FROM courses WHERE id = ?", courseID).Scan(&version)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrCourseNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("get course version: %w", err)
+		}
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO course_visits (user_id, course_id, seen_version, visited_at) VALUES (?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"user_id", "course_id"}, "seen_version", "visited_at"),
+			userID, courseID, version, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("save course visit: %w", err)
+		}
+		return nil
+	})
+}
+
+func scanCourseChange(row rowScanner) (models.CourseChange, error) {
+	var change models.CourseChange
+	var taskID sql.NullInt64
+	if err := row.Scan(&change.ID, &change.CourseID, &change.Version, &change.Kind, &taskID, &change.Title,
+		&change.CreatedAt); err != nil {
+		return change, fmt.Errorf("scan course change: %w", err)
+	}
+	if taskID.Valid {
+		id := int(taskID.Int64)
+		change.TaskID = &id
+	}
+	return change, nil
+}
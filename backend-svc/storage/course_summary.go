@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// courseSummarySelect вычисляет строку course_summary по курсам c; совпадает с заполнением
+// таблицы в миграции 055
+const courseSummarySelect = `
+	SELECT c.id,
+		(SELECT COUNT(*) FROM tasks t WHERE t.course_id = c.id),
+		(SELECT AVG(CASE t.difficulty WHEN 'easy' THEN 1 WHEN 'medium' THEN 2 ELSE 3 END) FROM tasks t WHERE t.course_id = c.id),
+		(SELECT COUNT(DISTINCT p.user_id) FROM user_progress p JOIN tasks t ON t.id = p.task_id WHERE t.course_id = c.id),
+		(SELECT COUNT(*) FROM course_reviews r WHERE r.course_id = c.id AND r.hidden_at IS NULL),
+		(SELECT AVG(r.rating) FROM course_reviews r WHERE r.course_id = c.id AND r.hidden_at IS NULL),
+		?
+	FROM courses c
+	WHERE c.id = ?`
+
+// refreshCourseSummary пересчитывает сводку курса. Вызывается в транзакции, которая меняет
+// задания, прогресс или видимые отзывы курса, поэтому каталог не видит расхождений со сводкой.
+// Строка заменяется целиком (DELETE + INSERT ... SELECT): так запрос одинаков для MySQL и SQLite.
+func refreshCourseSummary(ctx context.Context, q execQueryer, courseID int) error {
+	if _, err := q.ExecContext(ctx, "DELETE FROM course_summary WHERE course_id = ?", courseID); err != nil {
+		return fmt.Errorf("delete course summary: %w", err)
+	}
+	_, err := q.ExecContext(ctx,
+		"INSERT INTO course_summary (course_id, tasks_count, avg_difficulty, enrollments, reviews, avg_rating, updated_at)"+
+			courseSummarySelect,
+		time.Now().UTC(), courseID)
+	if err != nil {
+		return fmt.Errorf("refresh course summary: %w", err)
+	}
+	return nil
+}
+
+// refreshTaskCourseSummaries пересчитывает сводки курсов, которым принадлежат задания
+func refreshTaskCourseSummaries(ctx context.Context, tx *sql.Tx, taskIDs []int) error {
+	if len(taskIDs) == 0 {
+		return nil
+	}
+	args := make([]interface{}, len(taskIDs))
+	for i, id := range taskIDs {
+		args[i] = id
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(taskIDs)), ",")
+	courseIDs, err := queryIntSet(ctx, tx, "SELECT DISTINCT course_id FROM tasks WHERE id IN ("+placeholders+")", args...)
+	if err != nil {
+		return fmt.Errorf("get courses of tasks: %w", err)
+	}
+	for courseID := range courseIDs {
+		if err := refreshCourseSummary(ctx, tx, courseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RefreshCourseSummaries пересчитывает сводки всех курсов и возвращает их число. Сверка
+// исправляет сводки после каскадных удалений (удаление пользователей, восстановление из архива),
+// которые обходят пересчет при записи. Каждый курс пересчитывается отдельной транзакцией,
+// чтобы не держать блокировки на весь каталог.
+func (s *DBStorage) RefreshCourseSummaries() (int, error) {
+	ctx, done := s.startQuery("RefreshCourseSummaries")
+	defer done()
+
+	courseIDs, err := queryIntSet(ctx, s.DB, "SELECT id FROM courses")
+	if err != nil {
+		return 0, fmt.Errorf("list courses: %w", err)
+	}
+	refreshed := 0
+	for courseID := range courseIDs {
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			return refreshCourseSummary(ctx, tx, courseID)
+		})
+		if err != nil {
+			return refreshed, err
+		}
+		refreshed++
+	}
+	return refreshed, nil
+}
+
+// floatOrNil переводит NULL в nil
+func floatOrNil(v sql.NullFloat64) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Float64
+}
@@ -1,34 +1,89 @@
 package storage
 
 import (
+	"context"
 	"crypto/subtle"
 	"database/sql"
 	"errors"
 	"fmt"
 	"golang.org/x/crypto/bcrypt"
 	"lmsmodule/backend-svc/models"
+	"strings"
 	"time"
 )
 
 var (
+	// ErrUserNotFound не должен попадать в ответы публичных эндпоинтов входа и регистрации,
+	// иначе по нему можно перебором выяснить, какие учетные записи существуют
+	ErrUserNotFound   = errors.New("user not found")
 	ErrCourseNotFound = errors.New("course not found")
 	ErrTaskNotFound   = errors.New("task not found")
+	// ErrCourseArchived — курс в архиве и закрыт для новых слушателей
+	ErrCourseArchived = errors.New("course is archived")
+	// ErrCourseNotArchived — безвозвратно удалить можно только архивный курс
+	ErrCourseNotArchived = errors.New("course is not archived")
+	// ErrVersionConflict возвращается, если пользователь изменился после того, как клиент его прочитал
+	ErrVersionConflict = errors.New("user was modified concurrently")
+	// ErrVersionRequired возвращается, если клиент не передал версию пользователя, которую он прочитал
+	ErrVersionRequired = errors.New("expected user version is required")
+	// ErrOTPAttemptsExceeded возвращается, когда код сброшен после слишком большого числа неверных попыток
+	ErrOTPAttemptsExceeded = errors.New("too many invalid otp attempts")
 )
 
+// inTx выполняет fn в транзакции: фиксирует её при успехе и откатывает при ошибке.
+// Транзакции, прерванные из-за взаимной блокировки или ожидания блокировки, повторяются
+// целиком, поэтому fn не должна иметь побочных эффектов вне транзакции.
+func (s *DBStorage) inTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	for attempt := 1; ; attempt++ {
+		err := s.runTx(ctx, fn)
+		if err == nil || !isRetryableTxError(err) || attempt >= maxTxAttempts {
+			return err
+		}
+
+		txRetries.Add(1)
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(txRetryBackoff(attempt)):
+		}
+	}
+}
+
+func (s *DBStorage) runTx(ctx context.Context, fn func(tx *sql.Tx) error) error {
+	tx, err := s.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+
+	if err := fn(tx); err != nil {
+		if rollbackErr := tx.Rollback(); rollbackErr != nil {
+			return fmt.Errorf("original error: %w, rollback error: %v", err, rollbackErr)
+		}
+		return err
+	}
+
+	return tx.Commit()
+}
+
 func (s *DBStorage) GetCourses() ([]models.Course, error) {
-	stmt, err := s.DB.Prepare(`
-		SELECT c.id, c.vulnerability_type, 
-			   COUNT(t.id) as tasks_count, c.description
+	ctx, done := s.startQuery("GetCourses")
+	defer done()
+
+	// Архивные курсы в каталог не попадают
+	orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+	stmt, err := s.prepared(ctx, s.reader(), `
+		SELECT c.id, c.vulnerability_type, COALESCE(cs.tasks_count, 0), cs.avg_difficulty,
+			COALESCE(cs.enrollments, 0), COALESCE(cs.reviews, 0), cs.avg_rating, c.description, c.updated_at
 		FROM courses c
-		LEFT JOIN tasks t ON c.id = t.course_id
-		GROUP BY c.id
+		LEFT JOIN course_summary cs ON cs.course_id = c.id
+		WHERE c.archived_at IS NULL`+orgAnd+`
+		ORDER BY c.id
 	`)
 	if err != nil {
 		return nil, fmt.Errorf("prepare statement: %w", err)
 	}
-	defer stmt.Close()
 
-	rows, err := stmt.Query()
+	rows, err := stmt.QueryContext(ctx, orgArgs...)
 	if err != nil {
 		return nil, fmt.Errorf("execute query: %w", err)
 	}
@@ -37,14 +92,21 @@ func (s *DBStorage) GetCourses() ([]models.Course, error) {
 	var courses []models.Course
 	for rows.Next() {
 		var course models.Course
+		var avgDifficulty, avgRating sql.NullFloat64
 		if err := rows.Scan(
 			&course.ID,
 			&course.VulnerabilityType,
 			&course.TasksCount,
+			&avgDifficulty,
+			&course.Enrollments,
+			&course.Reviews,
+			&avgRating,
 			&course.Description,
+			&course.UpdatedAt,
 		); err != nil {
 			return nil, fmt.Errorf("scan row: %w", err)
 		}
+		course.AverageDifficulty, course.AverageRating = floatOrNil(avgDifficulty), floatOrNil(avgRating)
 		courses = append(courses, course)
 	}
 
@@ -55,74 +117,99 @@ func (s *DBStorage) GetCourses() ([]models.Course, error) {
 	return courses, nil
 }
 
+// GetCourseByID читает строку курса и его задания двумя параллельными запросами: оба только
+// читают, поэтому транзакция не нужна. Число заданий берется из прочитанного списка, чтобы
+// TasksCount совпадал с Tasks, даже если задание добавили между запросами.
 func (s *DBStorage) GetCourseByID(id int) (models.Course, error) {
-	tx, err := s.DB.Begin()
-	if err != nil {
-		return models.Course{}, fmt.Errorf("begin transaction: %w", err)
-	}
+	ctx, done := s.startQuery("GetCourseByID")
+	defer done()
 
-	var txErr error
-	defer func() {
-		if txErr != nil {
-			_ = tx.Rollback()
-		} else {
-			_ = tx.Commit()
-		}
+	type tasksResult struct {
+		tasks []models.Task
+		err   error
+	}
+	tasksDone := make(chan tasksResult, 1)
+	go func() {
+		tasks, err := s.courseTasks(ctx, id)
+		tasksDone <- tasksResult{tasks, err}
 	}()
 
-	courseStmt, err := tx.Prepare(`
-		SELECT c.id, c.vulnerability_type, 
-			   COUNT(t.id) as tasks_count, c.description
+	course, err := s.courseRow(ctx, id)
+	// Ждем задания и при ошибке, чтобы запрос не пережил done
+	result := <-tasksDone
+	if err != nil {
+		return models.Course{}, err
+	}
+	if result.err != nil {
+		return models.Course{}, result.err
+	}
+	course.Tasks = result.tasks
+	course.TasksCount = len(result.tasks)
+	return course, nil
+}
+
+// courseRow читает курс без заданий; ErrCourseNotFound — курса нет в организации представления
+func (s *DBStorage) courseRow(ctx context.Context, id int) (models.Course, error) {
+	orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+	stmt, err := s.prepared(ctx, s.DB, `
+		SELECT c.id, c.vulnerability_type, c.description, c.updated_at,
+			COALESCE(cs.enrollments, 0), COALESCE(cs.reviews, 0), cs.avg_difficulty, cs.avg_rating, c.archived_at
 		FROM courses c
-		LEFT JOIN tasks t ON c.id = t.course_id
-		WHERE c.id = ?
-		GROUP BY c.id
-	`)
+		LEFT JOIN course_summary cs ON cs.course_id = c.id
+		WHERE c.id = ?`+orgAnd)
 	if err != nil {
-		txErr = err
 		return models.Course{}, fmt.Errorf("prepare course statement: %w", err)
 	}
-	defer courseStmt.Close()
 
 	var course models.Course
-	err = courseStmt.QueryRow(id).Scan(
+	var avgDifficulty, avgRating sql.NullFloat64
+	var archivedAt sql.NullTime
+	err = stmt.QueryRowContext(ctx, append([]any{id}, orgArgs...)...).Scan(
 		&course.ID,
 		&course.VulnerabilityType,
-		&course.TasksCount,
 		&course.Description,
+		&course.UpdatedAt,
+		&course.Enrollments,
+		&course.Reviews,
+		&avgDifficulty,
+		&avgRating,
+		&archivedAt,
 	)
-
+	course.AverageDifficulty, course.AverageRating = floatOrNil(avgDifficulty), floatOrNil(avgRating)
+	if archivedAt.Valid {
+		course.ArchivedAt = &archivedAt.Time
+	}
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Course{}, ErrCourseNotFound
+	}
 	if err != nil {
-		txErr = err
-		if errors.Is(err, sql.ErrNoRows) {
-			return models.Course{}, ErrCourseNotFound
-		}
 		return models.Course{}, fmt.Errorf("query course: %w", err)
 	}
+	return course, nil
+}
 
-	tasksStmt, err := tx.Prepare(`
+// courseTasks читает задания курса в порядке прохождения
+func (s *DBStorage) courseTasks(ctx context.Context, courseID int) ([]models.Task, error) {
+	stmt, err := s.prepared(ctx, s.DB, `
 		SELECT id, course_id, title, description, difficulty, task_order
 		FROM tasks
 		WHERE course_id = ?
 		ORDER BY task_order
 	`)
 	if err != nil {
-		txErr = err
-		return models.Course{}, fmt.Errorf("prepare tasks statement: %w", err)
+		return nil, fmt.Errorf("prepare tasks statement: %w", err)
 	}
-	defer tasksStmt.Close()
 
-	tasksRows, err := tasksStmt.Query(id)
+	rows, err := stmt.QueryContext(ctx, courseID)
 	if err != nil {
-		txErr = err
-		return models.Course{}, fmt.Errorf("query tasks: %w", err)
+		return nil, fmt.Errorf("query tasks: %w", err)
 	}
-	defer tasksRows.Close()
+	defer rows.Close()
 
 	var tasks []models.Task
-	for tasksRows.Next() {
+	for rows.Next() {
 		var task models.Task
-		if err := tasksRows.Scan(
+		if err := rows.Scan(
 			&task.ID,
 			&task.CourseID,
 			&task.Title,
@@ -130,29 +217,27 @@ func (s *DBStorage) GetCourseByID(id int) (models.Course, error) {
 			&task.Difficulty,
 			&task.Order,
 		); err != nil {
-			txErr = err
-			return models.Course{}, fmt.Errorf("scan task: %w", err)
+			return nil, fmt.Errorf("scan task: %w", err)
 		}
 		tasks = append(tasks, task)
 	}
 
-	if err := tasksRows.Err(); err != nil {
-		txErr = err
-		return models.Course{}, fmt.Errorf("iterate tasks: %w", err)
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate tasks: %w", err)
 	}
-
-	course.Tasks = tasks
-	return course, nil
+	return tasks, nil
 }
 
 func (s *DBStorage) GetUserProgress(userID int) (models.UserProgress, error) {
-	stmt, err := s.DB.Prepare("SELECT task_id FROM user_progress WHERE user_id = ?")
+	ctx, done := s.startQuery("GetUserProgress")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.reader(), "SELECT task_id FROM user_progress WHERE user_id = ?")
 	if err != nil {
 		return models.UserProgress{}, fmt.Errorf("prepare statement: %w", err)
 	}
-	defer stmt.Close()
 
-	rows, err := stmt.Query(userID)
+	rows, err := stmt.QueryContext(ctx, userID)
 	if err != nil {
 		return models.UserProgress{}, fmt.Errorf("execute query: %w", err)
 	}
@@ -178,95 +263,315 @@ func (s *DBStorage) GetUserProgress(userID int) (models.UserProgress, error) {
 }
 
 func (s *DBStorage) CompleteTask(userID, taskID int) error {
-	var exists bool
-	err := s.DB.QueryRow("SELECT EXISTS(SELECT 1 FROM tasks WHERE id = ?)", taskID).Scan(&exists)
+	ctx, done := s.startQuery("CompleteTask")
+	defer done()
+
+	// Задания курсов другой организации для пользователя не существуют. Архивный курс
+	// принимает отметки только от тех, кто начал его до архивации.
+	orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+	var closed bool
+	err := s.DB.QueryRowContext(ctx,
+		"SELECT c.archived_at IS NOT NULL AND NOT "+courseStartedCondition+
+			" FROM tasks t JOIN courses c ON c.id = t.course_id WHERE t.id = ?"+orgAnd,
+		append([]any{userID, taskID}, orgArgs...)...).Scan(&closed)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrTaskNotFound
+	}
 	if err != nil {
 		return fmt.Errorf("check task existence: %w", err)
 	}
+	if closed {
+		return ErrCourseArchived
+	}
 
-	if !exists {
-		return ErrTaskNotFound
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx,
+			"INSERT INTO user_progress (user_id, task_id) VALUES (?, ?)"+
+				s.onConflictUpdate([]string{"user_id", "task_id"}, "task_id"))
+		if err != nil {
+			return fmt.Errorf("prepare statement: %w", err)
+		}
+		defer stmt.Close()
+
+		if _, err := stmt.ExecContext(ctx, userID, taskID); err != nil {
+			return fmt.Errorf("execute statement: %w", err)
+		}
+		if err := refreshTaskCourseSummaries(ctx, tx, []int{taskID}); err != nil {
+			return err
+		}
+
+		return insertOutboxEvent(ctx, tx, models.EventTaskCompleted, userID, map[string]int{
+			"userId": userID,
+			"taskId": taskID,
+		})
+	})
+}
+
+// CompleteTasks отмечает задания выполненными в одной транзакции: либо записываются все
+// новые отметки вместе с событиями outbox, либо ни одной. Несуществующие и уже выполненные
+// задания не считаются ошибкой и возвращаются с соответствующим статусом.
+func (s *DBStorage) CompleteTasks(userID int, taskIDs []int) ([]models.TaskCompletionResult, error) {
+	ctx, done := s.startQuery("CompleteTasks")
+	defer done()
+
+	taskIDs = uniqueInts(taskIDs)
+	if len(taskIDs) == 0 {
+		return []models.TaskCompletionResult{}, nil
+	}
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(taskIDs)), ",")
+
+	var results []models.TaskCompletionResult
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		results = make([]models.TaskCompletionResult, 0, len(taskIDs))
+
+		args := make([]interface{}, len(taskIDs))
+		for i, id := range taskIDs {
+			args[i] = id
+		}
+		orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+		existing, err := queryIntSet(ctx, tx,
+			"SELECT t.id FROM tasks t JOIN courses c ON c.id = t.course_id WHERE t.id IN ("+placeholders+")"+orgAnd,
+			append(args, orgArgs...)...)
+		if err != nil {
+			return fmt.Errorf("check tasks: %w", err)
+		}
+		completed, err := queryIntSet(ctx, tx,
+			"SELECT task_id FROM user_progress WHERE user_id = ? AND task_id IN ("+placeholders+")",
+			append([]interface{}{userID}, args...)...)
+		if err != nil {
+			return fmt.Errorf("check progress: %w", err)
+		}
+		closed, err := queryIntSet(ctx, tx,
+			"SELECT t.id FROM tasks t JOIN courses c ON c.id = t.course_id WHERE t.id IN ("+placeholders+")"+
+				" AND c.archived_at IS NOT NULL AND NOT "+courseStartedCondition,
+			append(args, userID)...)
+		if err != nil {
+			return fmt.Errorf("check archived courses: %w", err)
+		}
+
+		// completed прочитан из снимка транзакции: параллельный пакет мог успеть отметить те же
+		// задания. Upsert, как в CompleteTask, не падает на дубликате, а MySQL не считает
+		// неизмененную строку, так что 0 затронутых строк значит, что задание уже выполнено.
+		// В SQLite параллельная запись не доходит до конфликта: транзакция с устаревшим
+		// снимком не получает блокировку на запись.
+		stmt, err := tx.PrepareContext(ctx,
+			"INSERT INTO user_progress (user_id, task_id) VALUES (?, ?)"+
+				s.onConflictUpdate([]string{"user_id", "task_id"}, "task_id"))
+		if err != nil {
+			return fmt.Errorf("prepare statement: %w", err)
+		}
+		defer stmt.Close()
+
+		var inserted []int
+		for _, taskID := range taskIDs {
+			switch {
+			case !existing[taskID]:
+				results = append(results, models.TaskCompletionResult{TaskID: taskID, Status: models.TaskCompletionNotFound})
+			case completed[taskID]:
+				results = append(results, models.TaskCompletionResult{TaskID: taskID, Status: models.TaskCompletionAlreadyCompleted})
+			case closed[taskID]:
+				results = append(results, models.TaskCompletionResult{TaskID: taskID, Status: models.TaskCompletionCourseArchived})
+			default:
+				res, err := stmt.ExecContext(ctx, userID, taskID)
+				if err != nil {
+					return fmt.Errorf("insert progress for task %d: %w", taskID, err)
+				}
+				if n, err := res.RowsAffected(); err != nil {
+					return fmt.Errorf("insert progress for task %d: %w", taskID, err)
+				} else if n == 0 {
+					results = append(results, models.TaskCompletionResult{TaskID: taskID, Status: models.TaskCompletionAlreadyCompleted})
+					continue
+				}
+				err = insertOutboxEvent(ctx, tx, models.EventTaskCompleted, userID, map[string]int{
+					"userId": userID,
+					"taskId": taskID,
+				})
+				if err != nil {
+					return err
+				}
+				results = append(results, models.TaskCompletionResult{TaskID: taskID, Status: models.TaskCompletionCompleted})
+				inserted = append(inserted, taskID)
+			}
+		}
+		return refreshTaskCourseSummaries(ctx, tx, inserted)
+	})
+	if err != nil {
+		return nil, err
 	}
+	return results, nil
+}
 
-	stmt, err := s.DB.Prepare(
-		"INSERT INTO user_progress (user_id, task_id) VALUES (?, ?) " +
-			"ON DUPLICATE KEY UPDATE task_id = VALUES(task_id)")
+// queryIntSet выполняет запрос, возвращающий один целочисленный столбец, и собирает значения в множество
+func queryIntSet(ctx context.Context, q rowsQueryer, query string, args ...interface{}) (map[int]bool, error) {
+	rows, err := q.QueryContext(ctx, query, args...)
 	if err != nil {
-		return fmt.Errorf("prepare statement: %w", err)
+		return nil, err
 	}
-	defer stmt.Close()
+	defer rows.Close()
 
-	if _, err := stmt.Exec(userID, taskID); err != nil {
-		return fmt.Errorf("execute statement: %w", err)
+	set := make(map[int]bool)
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		set[id] = true
 	}
+	return set, rows.Err()
+}
 
-	return nil
+// uniqueInts удаляет повторы, сохраняя порядок первых вхождений
+func uniqueInts(ids []int) []int {
+	seen := make(map[int]bool, len(ids))
+	unique := make([]int, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
 }
 
-// CreateUser создает нового пользователя в базе данных
-func (s *DBStorage) CreateUser(user models.User) error {
-	// Проверяем, не существует ли уже пользователь с таким именем/email
-	checkStmt, err := s.DB.Prepare("SELECT EXISTS(SELECT 1 FROM users WHERE username = ? OR email = ?)")
-	if err != nil {
+// CreateCourse создает курс вместе с заданиями и возвращает ID курса
+func (s *DBStorage) CreateCourse(course models.Course) (int, error) {
+	ctx, done := s.startQuery("CreateCourse")
+	defer done()
+
+	var courseID int
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		courseID, _, err = insertCourse(ctx, tx, course, s.organization())
 		return err
+	})
+	return courseID, err
+}
+
+// insertCourse добавляет курс с заданиями в организацию orgID и возвращает ID курса и ID заданий по порядку
+func insertCourse(ctx context.Context, tx *sql.Tx, course models.Course, orgID int) (int, []int, error) {
+	// updated_at задается явно: по нему строятся ETag и Last-Modified каталога
+	now := time.Now().UTC()
+	res, err := tx.ExecContext(ctx,
+		"INSERT INTO courses (vulnerability_type, description, updated_at, created_at, organization_id) VALUES (?, ?, ?, ?, ?)",
+		course.VulnerabilityType, course.Description, now, now, orgID)
+	if err != nil {
+		return 0, nil, fmt.Errorf("insert course: %w", err)
 	}
-	defer checkStmt.Close()
 
-	var exists bool
-	err = checkStmt.QueryRow(user.Username, user.Email).Scan(&exists)
+	id, err := res.LastInsertId()
 	if err != nil {
-		return err
+		return 0, nil, fmt.Errorf("get course id: %w", err)
 	}
+	courseID := int(id)
 
-	if exists {
-		return errors.New("username or email already exists")
+	stmt, err := tx.PrepareContext(ctx,
+		"INSERT INTO tasks (course_id, title, description, difficulty, task_order) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, nil, fmt.Errorf("prepare task statement: %w", err)
 	}
+	defer stmt.Close()
 
-	insertStmt, err := s.DB.Prepare(
-		"INSERT INTO users (username, password_hash, email, full_name, totp_secret, is_2fa_enabled, is_active) " +
-			"VALUES (?, ?, ?, ?, ?, ?, ?)")
+	taskIDs := make([]int, 0, len(course.Tasks))
+	for i, task := range course.Tasks {
+		order := task.Order
+		if order == 0 {
+			order = i + 1
+		}
+		res, err := stmt.ExecContext(ctx, courseID, task.Title, task.Description, task.Difficulty, order)
+		if err != nil {
+			return 0, nil, fmt.Errorf("insert task %q: %w", task.Title, err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return 0, nil, fmt.Errorf("get task id: %w", err)
+		}
+		taskIDs = append(taskIDs, int(id))
+	}
+	if err := refreshCourseSummary(ctx, tx, courseID); err != nil {
+		return 0, nil, err
+	}
+	err = insertOutboxEvent(ctx, tx, models.EventCourseCreated, 0, map[string]int{"courseId": courseID})
 	if err != nil {
+		return 0, nil, err
+	}
+	return courseID, taskIDs, nil
+}
+
+// CreateUser создает нового пользователя в базе данных
+func (s *DBStorage) CreateUser(user models.User) error {
+	ctx, done := s.startQuery("CreateUser")
+	defer done()
+
+	// Уникальность username и email обеспечивают индексы таблицы users: предварительная
+	// проверка SELECT EXISTS не защищала от параллельной регистрации
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		_, err := insertUser(ctx, tx, user, s.organization(), true)
 		return err
+	})
+}
+
+// insertUser добавляет пользователя в организацию orgID и возвращает его ID; неактивный
+// пользователь не может войти, пока учетную запись не активируют
+func insertUser(ctx context.Context, tx *sql.Tx, user models.User, orgID int, active bool) (int, error) {
+	// Недавно освобожденные при переименовании имена зарезервированы за прежним владельцем
+	var reserved int
+	if err := tx.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM username_history WHERE old_username = ? AND reserved_until > ?",
+		user.Username, time.Now().UTC()).Scan(&reserved); err != nil {
+		return 0, err
+	}
+	if reserved > 0 {
+		return 0, ErrDuplicateUsername
+	}
+
+	insertStmt, err := tx.PrepareContext(ctx,
+		"INSERT INTO users (username, password_hash, email, full_name, totp_secret, is_2fa_enabled, is_active, organization_id) "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return 0, err
 	}
 	defer insertStmt.Close()
 
-	_, err = insertStmt.Exec(
+	res, err := insertStmt.ExecContext(ctx,
 		user.Username,
 		user.PasswordHash,
 		user.Email,
 		user.FullName,
 		user.TOTPSecret,
 		user.Is2FAEnabled,
-		true, // is_active
+		active,
+		orgID,
 	)
+	if err != nil {
+		return 0, translateUserConstraint(err)
+	}
 
-	return err
+	userID, err := res.LastInsertId()
+	if err != nil {
+		return 0, err
+	}
+
+	err = insertOutboxEvent(ctx, tx, models.EventUserRegistered, int(userID), map[string]interface{}{
+		"userId":   userID,
+		"username": user.Username,
+	})
+	return int(userID), err
 }
 
 // GetUserByUsername возвращает пользователя по имени пользователя из базы данных
 func (s *DBStorage) GetUserByUsername(username string) (models.User, error) {
-	stmt, err := s.DB.Prepare(
-		"SELECT id, username, password_hash, email, full_name, totp_secret, is_2fa_enabled " +
-			"FROM users WHERE username = ?")
+	ctx, done := s.startQuery("GetUserByUsername")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+userColumns+" FROM users WHERE username = ?")
 	if err != nil {
 		return models.User{}, err
 	}
-	defer stmt.Close()
-
-	var user models.User
-	err = stmt.QueryRow(username).Scan(
-		&user.ID,
-		&user.Username,
-		&user.PasswordHash,
-		&user.Email,
-		&user.FullName,
-		&user.TOTPSecret,
-		&user.Is2FAEnabled,
-	)
 
+	user, err := scanUser(stmt.QueryRowContext(ctx, username))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return models.User{}, errors.New("user not found")
+			return models.User{}, ErrUserNotFound
 		}
 		return models.User{}, err
 	}
@@ -276,28 +581,18 @@ func (s *DBStorage) GetUserByUsername(username string) (models.User, error) {
 
 // GetUserByID возвращает пользователя по ID из базы данных
 func (s *DBStorage) GetUserByID(id int) (models.User, error) {
-	stmt, err := s.DB.Prepare(
-		"SELECT id, username, password_hash, email, full_name, totp_secret, is_2fa_enabled " +
-			"FROM users WHERE id = ?")
+	ctx, done := s.startQuery("GetUserByID")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+userColumns+" FROM users WHERE id = ?")
 	if err != nil {
 		return models.User{}, err
 	}
-	defer stmt.Close()
-
-	var user models.User
-	err = stmt.QueryRow(id).Scan(
-		&user.ID,
-		&user.Username,
-		&user.PasswordHash,
-		&user.Email,
-		&user.FullName,
-		&user.TOTPSecret,
-		&user.Is2FAEnabled,
-	)
 
+	user, err := scanUser(stmt.QueryRowContext(ctx, id))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return models.User{}, errors.New("user not found")
+			return models.User{}, ErrUserNotFound
 		}
 		return models.User{}, err
 	}
@@ -305,30 +600,55 @@ func (s *DBStorage) GetUserByID(id int) (models.User, error) {
 	return user, nil
 }
 
-func (s *DBStorage) SaveOTPCode(userID int, code string) error {
-	expiresAt := time.Now().Add(5 * time.Minute)
+func (s *DBStorage) SaveOTPCode(userID int, code string, ttl time.Duration) error {
+	ctx, done := s.startQuery("SaveOTPCode")
+	defer done()
+
+	expiresAt := time.Now().UTC().Add(ttl)
 
-	stmt, err := s.DB.Prepare("UPDATE users SET otp_code = ?, otp_expires_at = ? WHERE id = ?")
+	stmt, err := s.prepared(ctx, s.DB, "UPDATE users SET otp_code = ?, otp_expires_at = ?, otp_attempts = 0 WHERE id = ?")
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(code, expiresAt, userID)
+	_, err = stmt.ExecContext(ctx, code, expiresAt, userID)
 	return err
 }
 
-func (s *DBStorage) VerifyOTPCode(userID int, code string) (bool, error) {
-	var storedCode string
-	var expiresAt time.Time
+// VerifyOTPCode проверяет код и учитывает попытку. Каждая проверка увеличивает счетчик атомарно,
+// поэтому параллельные запросы не дают больше maxAttempts попыток на один код. После последней
+// неудачной попытки код удаляется и возвращается ErrOTPAttemptsExceeded.
+func (s *DBStorage) VerifyOTPCode(userID int, code string, maxAttempts int) (bool, error) {
+	ctx, done := s.startQuery("VerifyOTPCode")
+	defer done()
 
-	stmt, err := s.DB.Prepare("SELECT otp_code, otp_expires_at FROM users WHERE id = ?")
+	claim, err := s.prepared(ctx, s.DB,
+		"UPDATE users SET otp_attempts = otp_attempts + 1 WHERE id = ? AND otp_code IS NOT NULL AND otp_attempts < ?")
 	if err != nil {
 		return false, err
 	}
-	defer stmt.Close()
+	res, err := claim.ExecContext(ctx, userID, maxAttempts)
+	if err != nil {
+		return false, err
+	}
+	if affected, err := res.RowsAffected(); err != nil {
+		return false, err
+	} else if affected == 0 {
+		// Кода нет: он не выдавался, уже использован или сброшен после исчерпания попыток
+		return false, nil
+	}
 
-	err = stmt.QueryRow(userID).Scan(&storedCode, &expiresAt)
+	// После ClearOTPCode оба столбца равны NULL
+	var storedCode sql.NullString
+	var expiresAt sql.NullTime
+	var attempts int
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT otp_code, otp_expires_at, otp_attempts FROM users WHERE id = ?")
+	if err != nil {
+		return false, err
+	}
+
+	err = stmt.QueryRowContext(ctx, userID).Scan(&storedCode, &expiresAt, &attempts)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -337,119 +657,118 @@ func (s *DBStorage) VerifyOTPCode(userID int, code string) (bool, error) {
 		return false, err
 	}
 
-	if storedCode == "" {
+	if storedCode.String == "" || !expiresAt.Valid {
 		return false, nil
 	}
 
-	if time.Now().After(expiresAt) {
+	if time.Now().After(expiresAt.Time) {
 		return false, nil
 	}
 
-	return subtle.ConstantTimeCompare([]byte(code), []byte(storedCode)) == 1, nil
+	if subtle.ConstantTimeCompare([]byte(code), []byte(storedCode.String)) == 1 {
+		return true, nil
+	}
+	if attempts >= maxAttempts {
+		clear, err := s.prepared(ctx, s.DB,
+			"UPDATE users SET otp_code = NULL, otp_expires_at = NULL, otp_attempts = 0 WHERE id = ? AND otp_code = ?")
+		if err != nil {
+			return false, err
+		}
+		if _, err := clear.ExecContext(ctx, userID, storedCode.String); err != nil {
+			return false, err
+		}
+		return false, ErrOTPAttemptsExceeded
+	}
+	return false, nil
 }
 
 func (s *DBStorage) ClearOTPCode(userID int) error {
-	stmt, err := s.DB.Prepare("UPDATE users SET otp_code = NULL, otp_expires_at = NULL WHERE id = ?")
+	ctx, done := s.startQuery("ClearOTPCode")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "UPDATE users SET otp_code = NULL, otp_expires_at = NULL, otp_attempts = 0 WHERE id = ?")
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(userID)
+	_, err = stmt.ExecContext(ctx, userID)
 	return err
 }
 
-// GetUsersByRole возвращает список пользователей с определенной ролью (admin или не admin)
-func (s *DBStorage) GetUsersByRole(isAdmin bool) ([]models.User, error) {
-	stmt, err := s.DB.Prepare(`
-		SELECT id, username, password_hash, email, full_name, totp_secret, 
-			   is_2fa_enabled, is_admin, is_active, last_login 
-		FROM users
-		WHERE is_admin = ?
-	`)
-	if err != nil {
-		return nil, err
-	}
-	defer stmt.Close()
+// PurgeExpiredOTPCodes очищает OTP-коды с истекшим сроком действия и возвращает число затронутых строк
+func (s *DBStorage) PurgeExpiredOTPCodes() (int64, error) {
+	ctx, done := s.startQuery("PurgeExpiredOTPCodes")
+	defer done()
 
-	rows, err := stmt.Query(isAdmin)
+	res, err := s.DB.ExecContext(ctx,
+		"UPDATE users SET otp_code = NULL, otp_expires_at = NULL "+
+			"WHERE otp_expires_at IS NOT NULL AND otp_expires_at < ?", time.Now().UTC())
 	if err != nil {
-		return nil, err
+		return 0, err
 	}
-	defer rows.Close()
-
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		var lastLogin sql.NullTime
-
-		err := rows.Scan(
-			&user.ID,
-			&user.Username,
-			&user.PasswordHash,
-			&user.Email,
-			&user.FullName,
-			&user.TOTPSecret,
-			&user.Is2FAEnabled,
-			&user.IsAdmin,
-			&user.IsActive,
-			&lastLogin,
-		)
-		if err != nil {
-			return nil, err
-		}
+	return res.RowsAffected()
+}
 
-		if lastLogin.Valid {
-			user.LastLogin = lastLogin.Time
-		}
+// GetUsersByRole возвращает список пользователей с определенной ролью (admin или не admin)
+func (s *DBStorage) GetUsersByRole(isAdmin bool) ([]models.UserSummary, error) {
+	ctx, done := s.startQuery("GetUsersByRole")
+	defer done()
 
-		users = append(users, user)
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+userSummaryColumns+" FROM users WHERE is_admin = ?")
+	if err != nil {
+		return nil, err
 	}
 
-	if err = rows.Err(); err != nil {
+	rows, err := stmt.QueryContext(ctx, isAdmin)
+	if err != nil {
 		return nil, err
 	}
-
-	return users, nil
+	return scanUserSummaries(rows)
 }
 
 // UpdateUserLastLogin обновляет время последнего входа пользователя в базе данных
 func (s *DBStorage) UpdateUserLastLogin(userID int) error {
-	stmt, err := s.DB.Prepare("UPDATE users SET last_login = NOW() WHERE id = ?")
+	ctx, done := s.startQuery("UpdateUserLastLogin")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "UPDATE users SET last_login = ? WHERE id = ?")
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(userID)
+	_, err = stmt.ExecContext(ctx, time.Now().UTC(), userID)
 	return err
 }
 
 // Enable2FA включает двухфакторную аутентификацию для пользователя в базе данных
 func (s *DBStorage) Enable2FA(userID int) error {
-	stmt, err := s.DB.Prepare("UPDATE users SET is_2fa_enabled = TRUE WHERE id = ?")
+	ctx, done := s.startQuery("Enable2FA")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "UPDATE users SET is_2fa_enabled = TRUE WHERE id = ?")
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(userID)
+	_, err = stmt.ExecContext(ctx, userID)
 	return err
 }
 
 // IsAdmin проверяет, является ли пользователь администратором, в базе данных
 func (s *DBStorage) IsAdmin(userID int) (bool, error) {
-	stmt, err := s.DB.Prepare("SELECT is_admin FROM users WHERE id = ?")
+	ctx, done := s.startQuery("IsAdmin")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT is_admin FROM users WHERE id = ?")
 	if err != nil {
 		return false, err
 	}
-	defer stmt.Close()
 
 	var isAdmin bool
-	err = stmt.QueryRow(userID).Scan(&isAdmin)
+	err = stmt.QueryRowContext(ctx, userID).Scan(&isAdmin)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return false, errors.New("user not found")
+			return false, ErrUserNotFound
 		}
 		return false, err
 	}
@@ -457,210 +776,250 @@ func (s *DBStorage) IsAdmin(userID int) (bool, error) {
 	return isAdmin, nil
 }
 
-// GetAllUsers возвращает список всех пользователей из базы данных
-func (s *DBStorage) GetAllUsers() ([]models.User, error) {
-	stmt, err := s.DB.Prepare(`
-		SELECT id, username, password_hash, email, full_name, totp_secret, 
-			   is_2fa_enabled, is_admin, is_active, last_login 
-		FROM users
-	`)
+// ListUserSummaries возвращает всех пользователей без секретов
+func (s *DBStorage) ListUserSummaries() ([]models.UserSummary, error) {
+	ctx, done := s.startQuery("ListUserSummaries")
+	defer done()
+
+	orgWhere, orgArgs := s.orgFilter("WHERE", "organization_id")
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+userSummaryColumns+" FROM users"+orgWhere)
 	if err != nil {
 		return nil, err
 	}
-	defer stmt.Close()
 
-	rows, err := stmt.Query()
+	rows, err := stmt.QueryContext(ctx, orgArgs...)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
-
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		var lastLogin sql.NullTime
-
-		err := rows.Scan(
-			&user.ID,
-			&user.Username,
-			&user.PasswordHash,
-			&user.Email,
-			&user.FullName,
-			&user.TOTPSecret,
-			&user.Is2FAEnabled,
-			&user.IsAdmin,
-			&user.IsActive,
-			&lastLogin,
-		)
-		if err != nil {
-			return nil, err
-		}
+	return scanUserSummaries(rows)
+}
 
-		if lastLogin.Valid {
-			user.LastLogin = lastLogin.Time
-		}
+// GetUserPublicByID возвращает пользователя по ID без секретов
+func (s *DBStorage) GetUserPublicByID(id int) (models.UserSummary, error) {
+	ctx, done := s.startQuery("GetUserPublicByID")
+	defer done()
 
-		users = append(users, user)
+	orgAnd, orgArgs := s.orgFilter("AND", "organization_id")
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+userSummaryColumns+" FROM users WHERE id = ?"+orgAnd)
+	if err != nil {
+		return models.UserSummary{}, err
 	}
 
-	if err = rows.Err(); err != nil {
-		return nil, err
+	user, err := scanUserSummary(stmt.QueryRowContext(ctx, append([]any{id}, orgArgs...)...))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.UserSummary{}, ErrUserNotFound
+		}
+		return models.UserSummary{}, err
 	}
 
-	return users, nil
+	return user, nil
 }
 
-// UpdateUserProfile обновляет профиль пользователя в базе данных
+// UpdateUserProfile обновляет профиль пользователя в базе данных, если его версия совпадает с data.Version
 func (s *DBStorage) UpdateUserProfile(userID int, data models.UpdateProfileRequest) error {
-	tx, err := s.DB.Begin()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err != nil {
-			rollbackErr := tx.Rollback()
-			if rollbackErr != nil {
-				err = fmt.Errorf("original error: %w, rollback error: %v", err, rollbackErr)
+	ctx, done := s.startQuery("UpdateUserProfile")
+	defer done()
+
+	// Хеш считаем до транзакции, чтобы не держать блокировки и не пересчитывать его при повторе
+	var passwordHash string
+	if data.Password != "" {
+		if s.PasswordHistory > 0 {
+			if err := s.checkPasswordHistory(ctx, userID, data.Password); err != nil {
+				return err
 			}
 		}
-	}()
-
-	if data.Email != "" {
-		stmt, err := tx.Prepare("UPDATE users SET email = ? WHERE id = ?")
+		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(data.Password), bcrypt.DefaultCost)
 		if err != nil {
 			return err
 		}
-		defer stmt.Close()
+		passwordHash = string(hashedPassword)
+	}
 
-		_, err = stmt.Exec(data.Email, userID)
-		if err != nil {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpUserVersion(ctx, tx, userID, data.Version); err != nil {
 			return err
 		}
-	}
 
-	if data.FullName != "" {
-		stmt, err := tx.Prepare("UPDATE users SET full_name = ? WHERE id = ?")
-		if err != nil {
-			return err
+		if data.Email != "" {
+			if _, err := tx.ExecContext(ctx, "UPDATE users SET email = ? WHERE id = ?", data.Email, userID); err != nil {
+				return err
+			}
 		}
-		defer stmt.Close()
 
-		_, err = stmt.Exec(data.FullName, userID)
-		if err != nil {
-			return err
+		if data.FullName != "" {
+			if _, err := tx.ExecContext(ctx, "UPDATE users SET full_name = ? WHERE id = ?", data.FullName, userID); err != nil {
+				return err
+			}
 		}
-	}
 
-	if data.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(data.Password), bcrypt.DefaultCost)
-		if err != nil {
-			return err
+		if data.Locale != "" {
+			if _, err := tx.ExecContext(ctx, "UPDATE users SET locale = ? WHERE id = ?", data.Locale, userID); err != nil {
+				return err
+			}
 		}
 
-		stmt, err := tx.Prepare("UPDATE users SET password_hash = ? WHERE id = ?")
-		if err != nil {
-			return err
+		if data.Timezone != "" {
+			if _, err := tx.ExecContext(ctx, "UPDATE users SET timezone = ? WHERE id = ?", data.Timezone, userID); err != nil {
+				return err
+			}
 		}
-		defer stmt.Close()
 
-		_, err = stmt.Exec(string(hashedPassword), userID)
-		if err != nil {
-			return err
+		if passwordHash != "" {
+			if s.PasswordHistory > 0 {
+				if err := s.recordPasswordHistory(ctx, tx, userID); err != nil {
+					return err
+				}
+			}
+			if _, err := tx.ExecContext(ctx,
+				"UPDATE users SET password_hash = ?, must_change_password = ? WHERE id = ?", passwordHash, false, userID); err != nil {
+				return err
+			}
 		}
-	}
 
-	return tx.Commit()
+		return nil
+	})
 }
 
 // SearchUsers ищет пользователей по имени пользователя, email или полному имени
-func (s *DBStorage) SearchUsers(query string) ([]models.User, error) {
+func (s *DBStorage) SearchUsers(query string) ([]models.UserSummary, error) {
+	ctx, done := s.startQuery("SearchUsers")
+	defer done()
+
 	// Добавляем % для поиска подстроки
 	searchQuery := "%" + query + "%"
 
-	stmt, err := s.DB.Prepare(`
-		SELECT id, username, password_hash, email, full_name, totp_secret, 
-			   is_2fa_enabled, is_admin, is_active, last_login 
-		FROM users
-		WHERE username LIKE ? OR email LIKE ? OR full_name LIKE ?
-	`)
+	orgAnd, orgArgs := s.orgFilter("AND", "organization_id")
+	stmt, err := s.prepared(ctx, s.reader(),
+		"SELECT "+userSummaryColumns+" FROM users WHERE (username LIKE ? OR email LIKE ? OR full_name LIKE ?)"+orgAnd)
 	if err != nil {
 		return nil, err
 	}
-	defer stmt.Close()
 
-	rows, err := stmt.Query(searchQuery, searchQuery, searchQuery)
+	rows, err := stmt.QueryContext(ctx, append([]any{searchQuery, searchQuery, searchQuery}, orgArgs...)...)
 	if err != nil {
 		return nil, err
 	}
-	defer rows.Close()
+	return scanUserSummaries(rows)
+}
 
-	var users []models.User
-	for rows.Next() {
-		var user models.User
-		var lastLogin sql.NullTime
-
-		err := rows.Scan(
-			&user.ID,
-			&user.Username,
-			&user.PasswordHash,
-			&user.Email,
-			&user.FullName,
-			&user.TOTPSecret,
-			&user.Is2FAEnabled,
-			&user.IsAdmin,
-			&user.IsActive,
-			&lastLogin,
-		)
+// UpdateUserStatus обновляет статус пользователя (активен/неактивен).
+// version — прочитанная клиентом версия, AnyVersion отключает проверку оптимистичной блокировки.
+func (s *DBStorage) UpdateUserStatus(userID int, isActive bool, version int) error {
+	ctx, done := s.startQuery("UpdateUserStatus")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpUserVersion(ctx, tx, userID, version); err != nil {
+			return err
+		}
+
+		stmt, err := tx.PrepareContext(ctx, "UPDATE users SET is_active = ? WHERE id = ?")
 		if err != nil {
-			return nil, err
+			return err
 		}
+		defer stmt.Close()
 
-		if lastLogin.Valid {
-			user.LastLogin = lastLogin.Time
+		if _, err := stmt.ExecContext(ctx, isActive, userID); err != nil {
+			return err
 		}
 
-		users = append(users, user)
-	}
+		return insertOutboxEvent(ctx, tx, models.EventUserStatusChanged, userID, map[string]interface{}{
+			"userId":   userID,
+			"isActive": isActive,
+		})
+	})
+}
 
-	if err = rows.Err(); err != nil {
-		return nil, err
-	}
+// PromoteToAdmin повышает пользователя до администратора
+func (s *DBStorage) PromoteToAdmin(userID int) error {
+	ctx, done := s.startQuery("PromoteToAdmin")
+	defer done()
 
-	return users, nil
+	return s.setAdminFlag(ctx, userID, true)
 }
 
-// UpdateUserStatus обновляет статус пользователя (активен/неактивен)
-func (s *DBStorage) UpdateUserStatus(userID int, isActive bool) error {
-	stmt, err := s.DB.Prepare("UPDATE users SET is_active = ? WHERE id = ?")
-	if err != nil {
+// DemoteFromAdmin понижает пользователя с роли администратора
+func (s *DBStorage) DemoteFromAdmin(userID int) error {
+	ctx, done := s.startQuery("DemoteFromAdmin")
+	defer done()
+
+	return s.setAdminFlag(ctx, userID, false)
+}
+
+// setAdminFlag меняет роль администратора и записывает событие о смене роли
+func (s *DBStorage) setAdminFlag(ctx context.Context, userID int, isAdmin bool) error {
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		return setAdminInTx(ctx, tx, userID, isAdmin)
+	})
+}
+
+// setAdminInTx меняет роль пользователя в транзакции tx и публикует событие
+func setAdminInTx(ctx context.Context, tx *sql.Tx, userID int, isAdmin bool) error {
+	if err := bumpUserVersion(ctx, tx, userID, AnyVersion); err != nil {
 		return err
 	}
-	defer stmt.Close()
-
-	_, err = stmt.Exec(isActive, userID)
-	return err
+	if _, err := tx.ExecContext(ctx, "UPDATE users SET is_admin = ? WHERE id = ?", isAdmin, userID); err != nil {
+		return err
+	}
+	return insertOutboxEvent(ctx, tx, models.EventUserRoleChanged, userID, map[string]interface{}{
+		"userId":  userID,
+		"isAdmin": isAdmin,
+	})
 }
 
-// PromoteToAdmin повышает пользователя до администратора
-func (s *DBStorage) PromoteToAdmin(userID int) error {
-	stmt, err := s.DB.Prepare("UPDATE users SET is_admin = TRUE WHERE id = ?")
+// AnyVersion отключает проверку оптимистичной блокировки. Предназначена только для внутренних
+// изменений, которые не опираются на прочитанную клиентом версию (сессии, сброс пароля из lmsctl)
+const AnyVersion = -1
+
+// bumpUserVersion увеличивает версию пользователя в транзакции tx. Обновление выполняется только
+// при совпадении текущей версии с expected, иначе — ErrVersionConflict; без expected (0) —
+// ErrVersionRequired. AnyVersion увеличивает версию без проверки.
+func bumpUserVersion(ctx context.Context, tx *sql.Tx, userID, expected int) error {
+	if expected != AnyVersion && expected <= 0 {
+		return ErrVersionRequired
+	}
+	res, err := tx.ExecContext(ctx,
+		"UPDATE users SET version = version + 1 WHERE id = ? AND (? = ? OR version = ?)",
+		userID, expected, AnyVersion, expected)
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(userID)
-	return err
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected > 0 {
+		return nil
+	}
+
+	var exists bool
+	if err := tx.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM users WHERE id = ?)", userID).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return ErrUserNotFound
+	}
+	return ErrVersionConflict
 }
 
-// DemoteFromAdmin понижает пользователя с роли администратора
-func (s *DBStorage) DemoteFromAdmin(userID int) error {
-	stmt, err := s.DB.Prepare("UPDATE users SET is_admin = FALSE WHERE id = ?")
+// DeleteUser безвозвратно удаляет пользователя; прогресс удаляется каскадно
+func (s *DBStorage) DeleteUser(userID int) error {
+	ctx, done := s.startQuery("DeleteUser")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM users WHERE id = ?", userID)
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
 
-	_, err = stmt.Exec(userID)
-	return err
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrUserNotFound
+	}
+	return nil
 }
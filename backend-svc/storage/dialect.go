@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Dialect — SQL-диалект базы данных, с которой работает DBStorage
+type Dialect string
+
+const (
+	DialectMySQL  Dialect = "mysql"
+	DialectSQLite Dialect = "sqlite"
+)
+
+// onConflictUpdate возвращает диалектно-зависимое окончание INSERT, обновляющее columns
+// при конфликте по уникальному ключу keys
+func (s *DBStorage) onConflictUpdate(keys []string, columns ...string) string {
+	assignments := make([]string, len(columns))
+	if s.Dialect == DialectSQLite {
+		for i, col := range columns {
+			assignments[i] = fmt.Sprintf("%s = excluded.%s", col, col)
+		}
+		return fmt.Sprintf(" ON CONFLICT(%s) DO UPDATE SET %s", strings.Join(keys, ", "), strings.Join(assignments, ", "))
+	}
+
+	for i, col := range columns {
+		assignments[i] = fmt.Sprintf("%s = VALUES(%s)", col, col)
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(assignments, ", ")
+}
@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrDigestTokenInvalid — токен ссылки отписки от сводки неизвестен или устарел
+var ErrDigestTokenInvalid = errors.New("digest unsubscribe token is invalid")
+
+// digestTokenRetention — сколько действуют ссылки отписки из отправленных писем
+const digestTokenRetention = 90 * 24 * time.Hour
+
+// GetDigestSettings возвращает подписку на сводку
+func (s *DBStorage) GetDigestSettings(userID int) (models.DigestSettings, error) {
+	ctx, done := s.startQuery("GetDigestSettings")
+	defer done()
+
+	var optedOut bool
+	var lastDigestAt sql.NullTime
+	err := s.DB.QueryRowContext(ctx,
+		"SELECT opted_out, last_digest_at FROM digest_settings WHERE user_id = ?", userID).Scan(&optedOut, &lastDigestAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DigestSettings{Enabled: true}, nil
+	}
+	if err != nil {
+		return models.DigestSettings{}, fmt.Errorf("get digest settings: %w", err)
+	}
+	settings := models.DigestSettings{Enabled: !optedOut}
+	if lastDigestAt.Valid {
+		settings.LastDigestAt = &lastDigestAt.Time
+	}
+	return settings, nil
+}
+
+// SetDigestEnabled включает или отключает сводку
+func (s *DBStorage) SetDigestEnabled(userID int, enabled bool) error {
+	ctx, done := s.startQuery("SetDigestEnabled")
+	defer done()
+
+	if _, err := s.DB.ExecContext(ctx,
+		"INSERT INTO digest_settings (user_id, opted_out) VALUES (?, ?)"+s.onConflictUpdate([]string{"user_id"}, "opted_out"),
+		userID, !enabled); err != nil {
+		return fmt.Errorf("save digest settings: %w", err)
+	}
+	return nil
+}
+
+// UnsubscribeDigest отключает сводку по токену из письма
+func (s *DBStorage) UnsubscribeDigest(tokenHash string) (int, error) {
+	ctx, done := s.startQuery("UnsubscribeDigest")
+	defer done()
+
+	var userID int
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx,
+			"SELECT user_id FROM digest_unsubscribe_tokens WHERE token_hash = ? AND created_at >= ?",
+			tokenHash, time.Now().UTC().Add(-digestTokenRetention)).Scan(&userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrDigestTokenInvalid
+		}
+		if err != nil {
+			return fmt.Errorf("get digest unsubscribe token: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO digest_settings (user_id, opted_out) VALUES (?, ?)"+s.onConflictUpdate([]string{"user_id"}, "opted_out"),
+			userID, true); err != nil {
+			return fmt.Errorf("save digest settings: %w", err)
+		}
+		return nil
+	})
+	return userID, err
+}
+
+// ListDigestRecipients возвращает получателей сводки за неделю, начавшуюся в slot
+func (s *DBStorage) ListDigestRecipients(slot time.Time) ([]int, error) {
+	ctx, done := s.startQuery("ListDigestRecipients")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT u.id
+		FROM users u
+		LEFT JOIN digest_settings d ON d.user_id = u.id
+		WHERE u.is_active = ? AND u.deletion_scheduled_at IS NULL AND u.email <> ''
+			AND u.created_at < ?
+			AND (d.user_id IS NULL OR (d.opted_out = ? AND (d.last_digest_at IS NULL OR d.last_digest_at < ?)))
+		ORDER BY u.id
+	`, true, slot.UTC(), false, slot.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query digest recipients: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan digest recipient: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// MarkDigestSent запоминает отправку сводки и удаляет устаревшие токены отписки пользователя
+func (s *DBStorage) MarkDigestSent(userID int, tokenHash string, at time.Time) error {
+	ctx, done := s.startQuery("MarkDigestSent")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO digest_settings (user_id, last_digest_at) VALUES (?, ?)"+s.onConflictUpdate([]string{"user_id"}, "last_digest_at"),
+			userID, at.UTC()); err != nil {
+			return fmt.Errorf("save digest settings: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"DELETE FROM digest_unsubscribe_tokens WHERE user_id = ? AND created_at < ?",
+			userID, at.UTC().Add(-digestTokenRetention)); err != nil {
+			return fmt.Errorf("purge digest unsubscribe tokens: %w", err)
+		}
+		if tokenHash == "" {
+			return nil
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO digest_unsubscribe_tokens (token_hash, user_id, created_at) VALUES (?, ?, ?)",
+			tokenHash, userID, at.UTC()); err != nil {
+			return fmt.Errorf("save digest unsubscribe token: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListCoursesCreatedBetween возвращает курсы, созданные в интервале; у курсов из миграций
+// время создания неизвестно, и они в выборку не попадают
+func (s *DBStorage) ListCoursesCreatedBetween(from, to time.Time) ([]models.Course, error) {
+	ctx, done := s.startQuery("ListCoursesCreatedBetween")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT c.id, c.vulnerability_type, COUNT(t.id), c.description, c.updated_at
+		FROM courses c
+		LEFT JOIN tasks t ON t.course_id = c.id
+		WHERE c.created_at >= ? AND c.created_at < ?
+		GROUP BY c.id, c.vulnerability_type, c.description, c.updated_at, c.created_at
+		ORDER BY c.created_at, c.id
+	`, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query new courses: %w", err)
+	}
+	defer rows.Close()
+
+	courses := []models.Course{}
+	for rows.Next() {
+		var course models.Course
+		if err := rows.Scan(&course.ID, &course.VulnerabilityType, &course.TasksCount, &course.Description, &course.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan course: %w", err)
+		}
+		courses = append(courses, course)
+	}
+	return courses, rows.Err()
+}
+
+// CountCompletionsByUser считает выполненные за интервал задания по пользователям
+func (s *DBStorage) CountCompletionsByUser(from, to time.Time) (map[int]int, error) {
+	ctx, done := s.startQuery("CountCompletionsByUser")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT user_id, COUNT(*)
+		FROM user_progress
+		WHERE completed_at >= ? AND completed_at < ?
+		GROUP BY user_id
+	`, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("query completions: %w", err)
+	}
+	defer rows.Close()
+
+	counts := map[int]int{}
+	for rows.Next() {
+		var id, completed int
+		if err := rows.Scan(&id, &completed); err != nil {
+			return nil, fmt.Errorf("scan completions: %w", err)
+		}
+		counts[id] = completed
+	}
+	return counts, rows.Err()
+}
@@ -0,0 +1,334 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrThreadNotFound — темы обсуждения нет
+	ErrThreadNotFound = errors.New("thread not found")
+	// ErrCommentNotFound — сообщения обсуждения нет
+	ErrCommentNotFound = errors.New("comment not found")
+	// ErrReviewNotFound — отзыва нет
+	ErrReviewNotFound = errors.New("review not found")
+)
+
+const threadColumns = "t.id, t.course_id, t.author_id, t.title, " +
+	"(SELECT COUNT(*) FROM discussion_comments c WHERE c.thread_id = t.id), " +
+	"t.locked_at, t.locked_by, t.created_at, t.last_comment_at"
+
+const commentColumns = "id, thread_id, author_id, body, hidden_at, hidden_by, created_at"
+
+const reviewColumns = "id, course_id, user_id, rating, body, hidden_at, hidden_by, created_at, updated_at"
+
+// CreateThread создает тему обсуждения с первым сообщением
+func (s *DBStorage) CreateThread(thread models.DiscussionThread, body string) (models.DiscussionThread, error) {
+	ctx, done := s.startQuery("CreateThread")
+	defer done()
+
+	now := time.Now().UTC()
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := courseExists(ctx, tx, thread.CourseID); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO discussion_threads (course_id, author_id, title, created_at, last_comment_at) VALUES (?, ?, ?, ?, ?)",
+			thread.CourseID, thread.AuthorID, thread.Title, now, now)
+		if err != nil {
+			return fmt.Errorf("insert thread: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get thread id: %w", err)
+		}
+		thread.ID = int(id)
+
+		res, err = tx.ExecContext(ctx,
+			"INSERT INTO discussion_comments (thread_id, author_id, body, created_at) VALUES (?, ?, ?, ?)",
+			thread.ID, thread.AuthorID, body, now)
+		if err != nil {
+			return fmt.Errorf("insert comment: %w", err)
+		}
+		commentID, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get comment id: %w", err)
+		}
+		return insertCommentEvent(ctx, tx, int(commentID), thread.ID, thread.CourseID, thread.AuthorID)
+	})
+	thread.CommentCount = 1
+	thread.CreatedAt, thread.LastCommentAt = now, now
+	return thread, err
+}
+
+// ListThreads возвращает темы курса, сначала с недавними сообщениями
+func (s *DBStorage) ListThreads(courseID int) ([]models.DiscussionThread, error) {
+	ctx, done := s.startQuery("ListThreads")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT "+threadColumns+" FROM discussion_threads t WHERE t.course_id = ? ORDER BY t.last_comment_at DESC, t.id DESC", courseID)
+	if err != nil {
+		return nil, fmt.Errorf("query threads: %w", err)
+	}
+	defer rows.Close()
+
+	threads := []models.DiscussionThread{}
+	for rows.Next() {
+		thread, err := scanThread(rows)
+		if err != nil {
+			return nil, err
+		}
+		threads = append(threads, thread)
+	}
+	return threads, rows.Err()
+}
+
+// GetThread возвращает тему обсуждения по ID
+func (s *DBStorage) GetThread(id int) (models.DiscussionThread, error) {
+	ctx, done := s.startQuery("GetThread")
+	defer done()
+
+	thread, err := scanThread(s.DB.QueryRowContext(ctx, "SELECT "+threadColumns+" FROM discussion_threads t WHERE t.id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DiscussionThread{}, ErrThreadNotFound
+	}
+	return thread, err
+}
+
+// SetThreadLock закрывает или открывает тему
+func (s *DBStorage) SetThreadLock(threadID, moderatorID int, locked bool) (models.DiscussionThread, error) {
+	ctx, done := s.startQuery("SetThreadLock")
+	defer done()
+
+	var res sql.Result
+	var err error
+	if locked {
+		res, err = s.DB.ExecContext(ctx,
+			"UPDATE discussion_threads SET locked_at = ?, locked_by = ? WHERE id = ?", time.Now().UTC(), moderatorID, threadID)
+	} else {
+		res, err = s.DB.ExecContext(ctx,
+			"UPDATE discussion_threads SET locked_at = NULL, locked_by = NULL WHERE id = ?", threadID)
+	}
+	if err != nil {
+		return models.DiscussionThread{}, fmt.Errorf("lock thread: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		found, err := rowExists(ctx, s.DB, "discussion_threads", threadID)
+		if err != nil {
+			return models.DiscussionThread{}, err
+		}
+		if !found {
+			return models.DiscussionThread{}, ErrThreadNotFound
+		}
+	}
+	return s.GetThread(threadID)
+}
+
+// ListThreadComments возвращает сообщения темы по порядку
+func (s *DBStorage) ListThreadComments(threadID int) ([]models.DiscussionComment, error) {
+	ctx, done := s.startQuery("ListThreadComments")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx, "SELECT "+commentColumns+" FROM discussion_comments WHERE thread_id = ? ORDER BY id", threadID)
+	if err != nil {
+		return nil, fmt.Errorf("query comments: %w", err)
+	}
+	defer rows.Close()
+
+	comments := []models.DiscussionComment{}
+	for rows.Next() {
+		comment, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(comments, comment)
+	}
+	return comments, rows.Err()
+}
+
+// AddComment сохраняет сообщение в теме и поднимает тему в списке
+func (s *DBStorage) AddComment(comment models.DiscussionComment) (models.DiscussionComment, error) {
+	ctx, done := s.startQuery("AddComment")
+	defer done()
+
+	now := time.Now().UTC()
+	comment.CreatedAt = now
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var courseID int
+		err := tx.QueryRowContext(ctx, "SELECT course_id FROM discussion_threads WHERE id = ?", comment.ThreadID).Scan(&courseID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrThreadNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("get thread: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE discussion_threads SET last_comment_at = ? WHERE id = ?", now, comment.ThreadID); err != nil {
+			return fmt.Errorf("touch thread: %w", err)
+		}
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO discussion_comments (thread_id, author_id, body, created_at) VALUES (?, ?, ?, ?)",
+			comment.ThreadID, comment.AuthorID, comment.Body, now)
+		if err != nil {
+			return fmt.Errorf("insert comment: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get comment id: %w", err)
+		}
+		comment.ID = int(id)
+		return insertCommentEvent(ctx, tx, comment.ID, comment.ThreadID, courseID, comment.AuthorID)
+	})
+	return comment, err
+}
+
+// insertCommentEvent записывает событие о новом сообщении обсуждения
+func insertCommentEvent(ctx context.Context, tx *sql.Tx, commentID, threadID, courseID int, authorID *int) error {
+	aggregateID := 0
+	if authorID != nil {
+		aggregateID = *authorID
+	}
+	return insertOutboxEvent(ctx, tx, models.EventCommentCreated, aggregateID, map[string]int{
+		"commentId": commentID,
+		"threadId":  threadID,
+		"courseId":  courseID,
+	})
+}
+
+// GetComment возвращает сообщение обсуждения по ID
+func (s *DBStorage) GetComment(id int) (models.DiscussionComment, error) {
+	ctx, done := s.startQuery("GetComment")
+	defer done()
+
+	comment, err := scanComment(s.DB.QueryRowContext(ctx, "SELECT "+commentColumns+" FROM discussion_comments WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DiscussionComment{}, ErrCommentNotFound
+	}
+	return comment, err
+}
+
+// SaveReview создает или заменяет отзыв пользователя о курсе. Измененный отзыв остается скрытым,
+// если его скрыл модератор.
+func (s *DBStorage) SaveReview(review models.CourseReview) (models.CourseReview, error) {
+	ctx, done := s.startQuery("SaveReview")
+	defer done()
+
+	now := time.Now().UTC()
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := courseExists(ctx, tx, review.CourseID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO course_reviews (course_id, user_id, rating, body, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"course_id", "user_id"}, "rating", "body", "updated_at"),
+			review.CourseID, review.UserID, review.Rating, review.Body, now, now)
+		if err != nil {
+			return fmt.Errorf("save review: %w", err)
+		}
+		if err := refreshCourseSummary(ctx, tx, review.CourseID); err != nil {
+			return err
+		}
+		review, err = scanReview(tx.QueryRowContext(ctx,
+			"SELECT "+reviewColumns+" FROM course_reviews WHERE course_id = ? AND user_id = ?", review.CourseID, review.UserID))
+		return err
+	})
+	return review, err
+}
+
+// ListCourseReviews возвращает отзывы о курсе, сначала новые
+func (s *DBStorage) ListCourseReviews(courseID int) ([]models.CourseReview, error) {
+	ctx, done := s.startQuery("ListCourseReviews")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT "+reviewColumns+" FROM course_reviews WHERE course_id = ? ORDER BY updated_at DESC, id DESC", courseID)
+	if err != nil {
+		return nil, fmt.Errorf("query reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews := []models.CourseReview{}
+	for rows.Next() {
+		review, err := scanReview(rows)
+		if err != nil {
+			return nil, err
+		}
+		reviews = append(reviews, review)
+	}
+	return reviews, rows.Err()
+}
+
+// GetReview возвращает отзыв по ID
+func (s *DBStorage) GetReview(id int) (models.CourseReview, error) {
+	ctx, done := s.startQuery("GetReview")
+	defer done()
+
+	review, err := scanReview(s.DB.QueryRowContext(ctx, "SELECT "+reviewColumns+" FROM course_reviews WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.CourseReview{}, ErrReviewNotFound
+	}
+	return review, err
+}
+
+func scanThread(row rowScanner) (models.DiscussionThread, error) {
+	var thread models.DiscussionThread
+	var authorID, lockedBy sql.NullInt64
+	var lockedAt sql.NullTime
+	err := row.Scan(&thread.ID, &thread.CourseID, &authorID, &thread.Title, &thread.CommentCount,
+		&lockedAt, &lockedBy, &thread.CreatedAt, &thread.LastCommentAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return thread, err
+		}
+		return thread, fmt.Errorf("scan thread: %w", err)
+	}
+	thread.AuthorID = intOrNil(authorID)
+	thread.LockedBy = intOrNil(lockedBy)
+	if lockedAt.Valid {
+		thread.LockedAt = &lockedAt.Time
+	}
+	return thread, nil
+}
+
+func scanComment(row rowScanner) (models.DiscussionComment, error) {
+	var comment models.DiscussionComment
+	var authorID, hiddenBy sql.NullInt64
+	var hiddenAt sql.NullTime
+	err := row.Scan(&comment.ID, &comment.ThreadID, &authorID, &comment.Body, &hiddenAt, &hiddenBy, &comment.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return comment, err
+		}
+		return comment, fmt.Errorf("scan comment: %w", err)
+	}
+	comment.AuthorID = intOrNil(authorID)
+	comment.HiddenBy = intOrNil(hiddenBy)
+	if hiddenAt.Valid {
+		comment.HiddenAt = &hiddenAt.Time
+	}
+	return comment, nil
+}
+
+func scanReview(row rowScanner) (models.CourseReview, error) {
+	var review models.CourseReview
+	var userID, hiddenBy sql.NullInt64
+	var hiddenAt sql.NullTime
+	err := row.Scan(&review.ID, &review.CourseID, &userID, &review.Rating, &review.Body, &hiddenAt, &hiddenBy,
+		&review.CreatedAt, &review.UpdatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return review, err
+		}
+		return review, fmt.Errorf("scan review: %w", err)
+	}
+	review.UserID = intOrNil(userID)
+	review.HiddenBy = intOrNil(hiddenBy)
+	if hiddenAt.Valid {
+		review.HiddenAt = &hiddenAt.Time
+	}
+	return review, nil
+}
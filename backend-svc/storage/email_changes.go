@@ -0,0 +1,205 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrEmailChangeInvalid возвращается для неизвестных, использованных и просроченных ссылок смены email
+var ErrEmailChangeInvalid = errors.New("email change link is invalid or expired")
+
+const emailChangeColumns = "id, user_id, old_email, new_email, confirm_token_hash, cancel_token_hash, requested_ip, " +
+	"created_at, expires_at, cancel_expires_at, confirmed_at, cancelled_at"
+
+func scanEmailChange(row rowScanner) (models.EmailChange, error) {
+	var change models.EmailChange
+	var confirmedAt, cancelledAt sql.NullTime
+	err := row.Scan(&change.ID, &change.UserID, &change.OldEmail, &change.NewEmail, &change.ConfirmTokenHash,
+		&change.CancelTokenHash, &change.RequestedIP, &change.CreatedAt, &change.ExpiresAt, &change.CancelExpiresAt,
+		&confirmedAt, &cancelledAt)
+	if err != nil {
+		return models.EmailChange{}, err
+	}
+	if confirmedAt.Valid {
+		change.ConfirmedAt = &confirmedAt.Time
+	}
+	if cancelledAt.Valid {
+		change.CancelledAt = &cancelledAt.Time
+	}
+	return change, nil
+}
+
+// CreateEmailChange сохраняет запрос на смену email, отменяя предыдущие неподтвержденные запросы пользователя
+func (s *DBStorage) CreateEmailChange(change models.EmailChange) (int64, error) {
+	ctx, done := s.startQuery("CreateEmailChange")
+	defer done()
+
+	var id int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE email_changes SET cancelled_at = ? WHERE user_id = ? AND confirmed_at IS NULL AND cancelled_at IS NULL",
+			now, change.UserID); err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO email_changes (user_id, old_email, new_email, confirm_token_hash, cancel_token_hash, "+
+				"requested_ip, created_at, expires_at, cancel_expires_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			change.UserID, change.OldEmail, change.NewEmail, change.ConfirmTokenHash, change.CancelTokenHash,
+			change.RequestedIP, now, change.ExpiresAt.UTC(), change.CancelExpiresAt.UTC())
+		if err != nil {
+			return err
+		}
+		id, err = res.LastInsertId()
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("create email change: %w", err)
+	}
+	return id, nil
+}
+
+// lockEmailChange выбирает запрос смены email по хешу токена внутри транзакции
+func lockEmailChange(ctx context.Context, tx *sql.Tx, column, tokenHash string) (models.EmailChange, error) {
+	change, err := scanEmailChange(tx.QueryRowContext(ctx,
+		"SELECT "+emailChangeColumns+" FROM email_changes WHERE "+column+" = ?", tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.EmailChange{}, ErrEmailChangeInvalid
+	}
+	return change, err
+}
+
+// ConfirmEmailChange применяет новый адрес по токену из письма на этот адрес.
+// Возвращает ErrDuplicateEmail, если адрес успел занять другой пользователь.
+func (s *DBStorage) ConfirmEmailChange(tokenHash string) (models.EmailChange, error) {
+	ctx, done := s.startQuery("ConfirmEmailChange")
+	defer done()
+
+	var change models.EmailChange
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		change, err = lockEmailChange(ctx, tx, "confirm_token_hash", tokenHash)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if change.ConfirmedAt != nil || change.CancelledAt != nil || !change.ExpiresAt.After(now) {
+			return ErrEmailChangeInvalid
+		}
+
+		if err := bumpUserVersion(ctx, tx, change.UserID, AnyVersion); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET email = ? WHERE id = ?", change.NewEmail, change.UserID); err != nil {
+			return translateUserConstraint(err)
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE email_changes SET confirmed_at = ? WHERE id = ?", now, change.ID); err != nil {
+			return err
+		}
+		change.ConfirmedAt = &now
+
+		return insertOutboxEvent(ctx, tx, models.EventUserEmailChanged, change.UserID, map[string]interface{}{
+			"userId":   change.UserID,
+			"oldEmail": change.OldEmail,
+			"newEmail": change.NewEmail,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrEmailChangeInvalid) || errors.Is(err, ErrDuplicateEmail) {
+			return models.EmailChange{}, err
+		}
+		return models.EmailChange{}, fmt.Errorf("confirm email change: %w", err)
+	}
+	return change, nil
+}
+
+// CancelEmailChange отменяет смену email по токену из письма на старый адрес. Если смена уже
+// подтверждена, возвращает прежний адрес, отзывает доверенные устройства и требует сменить пароль:
+// запрос, скорее всего, сделал кто-то, получивший доступ к сессии.
+func (s *DBStorage) CancelEmailChange(tokenHash string) (models.EmailChange, error) {
+	ctx, done := s.startQuery("CancelEmailChange")
+	defer done()
+
+	var change models.EmailChange
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		change, err = lockEmailChange(ctx, tx, "cancel_token_hash", tokenHash)
+		if err != nil {
+			return err
+		}
+		now := time.Now().UTC()
+		if change.CancelledAt != nil || !change.CancelExpiresAt.After(now) {
+			return ErrEmailChangeInvalid
+		}
+
+		if _, err := tx.ExecContext(ctx, "UPDATE email_changes SET cancelled_at = ? WHERE id = ?", now, change.ID); err != nil {
+			return err
+		}
+		change.CancelledAt = &now
+		if change.ConfirmedAt == nil {
+			return nil
+		}
+
+		if err := bumpUserVersion(ctx, tx, change.UserID, AnyVersion); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE users SET email = ?, must_change_password = ? WHERE id = ?", change.OldEmail, true, change.UserID); err != nil {
+			return translateUserConstraint(err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE trusted_devices SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL", now, change.UserID); err != nil {
+			return err
+		}
+
+		return insertOutboxEvent(ctx, tx, models.EventUserEmailChanged, change.UserID, map[string]interface{}{
+			"userId":   change.UserID,
+			"oldEmail": change.NewEmail,
+			"newEmail": change.OldEmail,
+			"reason":   "reverted_by_owner",
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrEmailChangeInvalid) || errors.Is(err, ErrDuplicateEmail) {
+			return models.EmailChange{}, err
+		}
+		return models.EmailChange{}, fmt.Errorf("cancel email change: %w", err)
+	}
+	return change, nil
+}
+
+// ListEmailChanges возвращает историю смены email пользователя, последние первыми
+func (s *DBStorage) ListEmailChanges(userID int) ([]models.EmailChange, error) {
+	ctx, done := s.startQuery("ListEmailChanges")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT "+emailChangeColumns+" FROM email_changes WHERE user_id = ? ORDER BY created_at DESC, id DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	changes := []models.EmailChange{}
+	for rows.Next() {
+		change, err := scanEmailChange(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		changes = append(changes, change)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return changes, nil
+}
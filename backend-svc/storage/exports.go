@@ -0,0 +1,114 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+
+	"lmsmodule/backend-svc/models"
+)
+
+// exportBatchSize — строк в одном запросе потоковой выгрузки. Следующая пачка продолжается
+// после ключа последней строки, поэтому выгрузка любого размера не держит в памяти больше
+// одной пачки, а каждый запрос укладывается в QueryTimeout.
+const exportBatchSize = 1000
+
+// EachUserSummary передает fn пользователей организации представления в порядке ID
+func (s *DBStorage) EachUserSummary(fn func(models.UserSummary) error) error {
+	afterID := 0
+	for {
+		batch, err := s.userSummaryBatch(afterID)
+		if err != nil {
+			return err
+		}
+		for _, user := range batch {
+			if err := fn(user); err != nil {
+				return err
+			}
+		}
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		afterID = batch[len(batch)-1].ID
+	}
+}
+
+func (s *DBStorage) userSummaryBatch(afterID int) ([]models.UserSummary, error) {
+	ctx, done := s.startQuery("EachUserSummary")
+	defer done()
+
+	orgAnd, orgArgs := s.orgFilter("AND", "organization_id")
+	stmt, err := s.prepared(ctx, s.reader(), "SELECT "+userSummaryColumns+" FROM users WHERE id > ?"+orgAnd+" ORDER BY id LIMIT ?")
+	if err != nil {
+		return nil, err
+	}
+	args := append([]any{afterID}, orgArgs...)
+	rows, err := stmt.QueryContext(ctx, append(args, exportBatchSize)...)
+	if err != nil {
+		return nil, err
+	}
+	return scanUserSummaries(rows)
+}
+
+// EachProgressRecord передает fn выполненные задания пользователей организации представления
+func (s *DBStorage) EachProgressRecord(filter models.ProgressExportFilter, fn func(models.ProgressRecord) error) error {
+	var after *models.ProgressRecord
+	for {
+		batch, err := s.progressBatch(filter, after)
+		if err != nil {
+			return err
+		}
+		for _, record := range batch {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		after = &batch[len(batch)-1]
+	}
+}
+
+func (s *DBStorage) progressBatch(filter models.ProgressExportFilter, after *models.ProgressRecord) ([]models.ProgressRecord, error) {
+	ctx, done := s.startQuery("EachProgressRecord")
+	defer done()
+
+	query := `
+		SELECT p.user_id, u.username, t.course_id, c.vulnerability_type, p.task_id, t.title, p.completed_at
+		FROM user_progress p
+		JOIN users u ON u.id = p.user_id
+		JOIN tasks t ON t.id = p.task_id
+		JOIN courses c ON c.id = t.course_id
+		WHERE 1 = 1`
+	var args []any
+	if filter.CourseID != 0 {
+		query += " AND t.course_id = ?"
+		args = append(args, filter.CourseID)
+	}
+	if after != nil {
+		query += " AND (p.user_id > ? OR (p.user_id = ? AND p.task_id > ?))"
+		args = append(args, after.UserID, after.UserID, after.TaskID)
+	}
+	orgAnd, orgArgs := s.orgFilter("AND", "u.organization_id")
+	query += orgAnd + " ORDER BY p.user_id, p.task_id LIMIT ?"
+	args = append(append(args, orgArgs...), exportBatchSize)
+
+	rows, err := s.reader().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query progress: %w", err)
+	}
+	defer rows.Close()
+
+	var records []models.ProgressRecord
+	for rows.Next() {
+		var record models.ProgressRecord
+		var completedAt sql.NullTime
+		if err := rows.Scan(&record.UserID, &record.Username, &record.CourseID, &record.CourseTitle,
+			&record.TaskID, &record.TaskTitle, &completedAt); err != nil {
+			return nil, fmt.Errorf("scan progress record: %w", err)
+		}
+		record.CompletedAt = timeOrNil(completedAt)
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
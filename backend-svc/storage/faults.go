@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"errors"
+	"expvar"
+	"fmt"
+	"lmsmodule/backend-svc/config"
+	"math/rand"
+	"time"
+)
+
+// ErrInjectedFault возвращается вызовами хранилища, в которые FaultyStorage внедрил ошибку
+var ErrInjectedFault = errors.New("injected storage fault")
+
+var injectedFaults = expvar.NewMap("db_faults_injected_total")
+
+// FaultyStorage — декоратор Storage, который задерживает вызовы и возвращает ошибки с
+// заданной вероятностью, чтобы проверять устойчивость обработчиков и повторов на стенде.
+// Методы сгенерированы в faults_gen.go командой go generate по интерфейсу Storage.
+type FaultyStorage struct {
+	next Storage
+	cfg  config.FaultsConfig
+	// methods — затрагиваемые методы; nil — все
+	methods map[string]bool
+}
+
+// InjectFaults оборачивает хранилище декоратором сбоев по cfg. Неизвестное имя метода в
+// cfg.Methods — ошибка: опечатка в конфигурации иначе молча отключила бы сбои.
+func InjectFaults(store Storage, cfg config.FaultsConfig) (*FaultyStorage, error) {
+	faulty := &FaultyStorage{next: store, cfg: cfg}
+	if len(cfg.Methods) > 0 {
+		faulty.methods = make(map[string]bool, len(cfg.Methods))
+		for _, method := range cfg.Methods {
+			if !storageMethods[method] {
+				return nil, fmt.Errorf("unknown storage method %q in database.faults.methods", method)
+			}
+			faulty.methods[method] = true
+		}
+	}
+	return faulty, nil
+}
+
+func (s *FaultyStorage) affects(method string) bool {
+	return s.methods == nil || s.methods[method]
+}
+
+// delay выдерживает задержку с вероятностью LatencyProbability
+func (s *FaultyStorage) delay(method string) {
+	if !s.affects(method) || s.cfg.Latency <= 0 || rand.Float64() >= s.cfg.LatencyProbability {
+		return
+	}
+	injectedFaults.Add("latency", 1)
+	time.Sleep(s.cfg.Latency)
+}
+
+// inject применяет задержку и с вероятностью ErrorProbability возвращает ErrInjectedFault
+func (s *FaultyStorage) inject(method string) error {
+	s.delay(method)
+	if !s.affects(method) || rand.Float64() >= s.cfg.ErrorProbability {
+		return nil
+	}
+	injectedFaults.Add("error", 1)
+	return fmt.Errorf("%s: %w", method, ErrInjectedFault)
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+	"time"
+)
+
+// GetFeatureFlags возвращает все флаги функциональности
+func (s *DBStorage) GetFeatureFlags() ([]models.FeatureFlag, error) {
+	ctx, done := s.startQuery("GetFeatureFlags")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT flag_key, description, enabled, rollout_percentage, target_groups, updated_at FROM feature_flags")
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var flags []models.FeatureFlag
+	for rows.Next() {
+		var flag models.FeatureFlag
+		var targetGroups sql.NullString
+		if err := rows.Scan(
+			&flag.Key,
+			&flag.Description,
+			&flag.Enabled,
+			&flag.RolloutPercentage,
+			&targetGroups,
+			&flag.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		flag.TargetGroups = splitList(targetGroups.String)
+		flags = append(flags, flag)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return flags, nil
+}
+
+// UpsertFeatureFlag создает или обновляет флаг функциональности
+func (s *DBStorage) UpsertFeatureFlag(flag models.FeatureFlag) error {
+	ctx, done := s.startQuery("UpsertFeatureFlag")
+	defer done()
+
+	_, err := s.DB.ExecContext(ctx,
+		"INSERT INTO feature_flags (flag_key, description, enabled, rollout_percentage, target_groups, updated_at) "+
+			"VALUES (?, ?, ?, ?, ?, ?)"+
+			s.onConflictUpdate([]string{"flag_key"},
+				"description", "enabled", "rollout_percentage", "target_groups", "updated_at"),
+		flag.Key, flag.Description, flag.Enabled, flag.RolloutPercentage, strings.Join(flag.TargetGroups, ","),
+		time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("upsert feature flag %s: %w", flag.Key, err)
+	}
+	return nil
+}
+
+// DeleteFeatureFlag удаляет флаг функциональности
+func (s *DBStorage) DeleteFeatureFlag(key string) error {
+	ctx, done := s.startQuery("DeleteFeatureFlag")
+	defer done()
+
+	_, err := s.DB.ExecContext(ctx, "DELETE FROM feature_flags WHERE flag_key = ?", key)
+	return err
+}
+
+// splitList разбирает список значений, сохраненный через запятую
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
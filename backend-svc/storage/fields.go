@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+)
+
+// UserFields — поля пользователя, доступные для выборки (?fields=), в порядке столбцов
+var UserFields = []string{"id", "username", "email", "fullName", "is2faEnabled", "isAdmin", "isActive", "lastLogin", "version"}
+
+// CourseFields — поля курса, доступные для выборки в списке (?fields=)
+var CourseFields = []string{"id", "vulnerabilityType", "tasksCount", "averageDifficulty", "enrollments", "reviews", "averageRating", "description", "updatedAt"}
+
+// userFieldColumns сопоставляет поля пользователя со столбцами таблицы users
+var userFieldColumns = map[string]string{
+	"id":           "id",
+	"username":     "username",
+	"email":        "email",
+	"fullName":     "full_name",
+	"is2faEnabled": "is_2fa_enabled",
+	"isAdmin":      "is_admin",
+	"isActive":     "is_active",
+	"lastLogin":    "last_login",
+	"version":      "version",
+}
+
+// courseFieldColumns сопоставляет поля курса с выражениями выборки
+var courseFieldColumns = map[string]string{
+	"id":                "c.id",
+	"vulnerabilityType": "c.vulnerability_type",
+	"tasksCount":        "COALESCE(cs.tasks_count, 0)",
+	"averageDifficulty": "cs.avg_difficulty",
+	"enrollments":       "COALESCE(cs.enrollments, 0)",
+	"reviews":           "COALESCE(cs.reviews, 0)",
+	"averageRating":     "cs.avg_rating",
+	"description":       "c.description",
+	"updatedAt":         "c.updated_at",
+}
+
+// ListUserSummaryFields возвращает пользователей, выбирая из базы только столбцы запрошенных полей.
+// Остальные поля UserSummary остаются нулевыми; id выбирается всегда.
+func (s *DBStorage) ListUserSummaryFields(fields []string) ([]models.UserSummary, error) {
+	ctx, done := s.startQuery("ListUserSummaryFields")
+	defer done()
+
+	fields = withField(fields, "id")
+	columns, err := projection(fields, userFieldColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	orgWhere, orgArgs := s.orgFilter("WHERE", "organization_id")
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+columns+" FROM users"+orgWhere+" ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, orgArgs...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []models.UserSummary
+	for rows.Next() {
+		var user models.UserSummary
+		var email, fullName sql.NullString
+		var lastLogin sql.NullTime
+
+		dest := make([]interface{}, len(fields))
+		for i, field := range fields {
+			switch field {
+			case "id":
+				dest[i] = &user.ID
+			case "username":
+				dest[i] = &user.Username
+			case "email":
+				dest[i] = &email
+			case "fullName":
+				dest[i] = &fullName
+			case "is2faEnabled":
+				dest[i] = &user.Is2FAEnabled
+			case "isAdmin":
+				dest[i] = &user.IsAdmin
+			case "isActive":
+				dest[i] = &user.IsActive
+			case "lastLogin":
+				dest[i] = &lastLogin
+			case "version":
+				dest[i] = &user.Version
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, err
+		}
+
+		user.Email = email.String
+		user.FullName = fullName.String
+		if lastLogin.Valid {
+			user.LastLogin = lastLogin.Time
+		}
+		users = append(users, user)
+	}
+	return users, rows.Err()
+}
+
+// GetCourseFields возвращает каталог курсов, выбирая только запрошенные поля. Соединение
+// со сводкой course_summary выполняется, только если запрошены ее показатели; id и updatedAt
+// выбираются всегда, так как по ним строятся ETag и Last-Modified.
+func (s *DBStorage) GetCourseFields(fields []string) ([]models.Course, error) {
+	ctx, done := s.startQuery("GetCourseFields")
+	defer done()
+
+	fields = withField(withField(fields, "id"), "updatedAt")
+	columns, err := projection(fields, courseFieldColumns)
+	if err != nil {
+		return nil, err
+	}
+
+	orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+	query := "SELECT " + columns + " FROM courses c"
+	if strings.Contains(columns, "cs.") {
+		query += " LEFT JOIN course_summary cs ON cs.course_id = c.id"
+	}
+	query += " WHERE c.archived_at IS NULL" + orgAnd + " ORDER BY c.id"
+
+	stmt, err := s.prepared(ctx, s.reader(), query)
+	if err != nil {
+		return nil, fmt.Errorf("prepare statement: %w", err)
+	}
+	rows, err := stmt.QueryContext(ctx, orgArgs...)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var courses []models.Course
+	for rows.Next() {
+		var course models.Course
+		dest := make([]interface{}, len(fields))
+		for i, field := range fields {
+			switch field {
+			case "id":
+				dest[i] = &course.ID
+			case "vulnerabilityType":
+				dest[i] = &course.VulnerabilityType
+			case "tasksCount":
+				dest[i] = &course.TasksCount
+			case "averageDifficulty":
+				dest[i] = &course.AverageDifficulty
+			case "enrollments":
+				dest[i] = &course.Enrollments
+			case "reviews":
+				dest[i] = &course.Reviews
+			case "averageRating":
+				dest[i] = &course.AverageRating
+			case "description":
+				dest[i] = &course.Description
+			case "updatedAt":
+				dest[i] = &course.UpdatedAt
+			}
+		}
+		if err := rows.Scan(dest...); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		courses = append(courses, course)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return courses, nil
+}
+
+// projection строит список столбцов для полей; неизвестное поле — ошибка, чтобы
+// в запрос не попадало ничего, кроме столбцов из белого списка
+func projection(fields []string, columns map[string]string) (string, error) {
+	selected := make([]string, len(fields))
+	for i, field := range fields {
+		column, ok := columns[field]
+		if !ok {
+			return "", fmt.Errorf("unknown field %q", field)
+		}
+		selected[i] = column
+	}
+	return strings.Join(selected, ", "), nil
+}
+
+// withField добавляет поле в начало списка, если его там нет
+func withField(fields []string, field string) []string {
+	for _, f := range fields {
+		if f == field {
+			return fields
+		}
+	}
+	return append([]string{field}, fields...)
+}
@@ -0,0 +1,120 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrGroupNotFound — группы с таким названием нет
+var ErrGroupNotFound = errors.New("group not found")
+
+// GetUserGroups возвращает названия групп, в которых состоит пользователь
+func (s *DBStorage) GetUserGroups(userID int) ([]string, error) {
+	ctx, done := s.startQuery("GetUserGroups")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx, `
+		SELECT g.name
+		FROM user_groups g
+		JOIN group_members m ON m.group_id = g.id
+		WHERE m.user_id = ?
+		ORDER BY g.name
+	`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var groups []string
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		groups = append(groups, name)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return groups, nil
+}
+
+// GroupLeaderboard возвращает участников группы с наибольшим числом заданий, выполненных в интервале
+func (s *DBStorage) GroupLeaderboard(group string, from, to time.Time, limit int) ([]models.LeaderboardEntry, error) {
+	ctx, done := s.startQuery("GroupLeaderboard")
+	defer done()
+
+	if err := groupExists(ctx, s.reader(), group, s.OrganizationID); err != nil {
+		return nil, err
+	}
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT u.id, u.username, COUNT(*) AS completed
+		FROM user_groups g
+		JOIN group_members m ON m.group_id = g.id
+		JOIN users u ON u.id = m.user_id
+		JOIN user_progress p ON p.user_id = u.id
+		WHERE g.name = ? AND p.completed_at >= ? AND p.completed_at < ?
+		GROUP BY u.id, u.username
+		ORDER BY completed DESC, u.username
+		LIMIT ?
+	`, group, from.UTC(), to.UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("query group leaderboard: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.LeaderboardEntry{}
+	for rows.Next() {
+		var entry models.LeaderboardEntry
+		if err := rows.Scan(&entry.UserID, &entry.Username, &entry.TasksCompleted); err != nil {
+			return nil, fmt.Errorf("scan leaderboard entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+// groupExists возвращает ErrGroupNotFound, если группы нет; ненулевой orgID требует, чтобы
+// группа принадлежала этой организации
+func groupExists(ctx context.Context, q queryer, group string, orgID int) error {
+	var id int
+	err := q.QueryRowContext(ctx, "SELECT id FROM user_groups WHERE name = ? AND (? = 0 OR organization_id = ?)",
+		group, orgID, orgID).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrGroupNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("check group: %w", err)
+	}
+	return nil
+}
+
+// IsGroupMember сообщает, состоит ли пользователь в группе
+func (s *DBStorage) IsGroupMember(group string, userID int) (bool, error) {
+	ctx, done := s.startQuery("IsGroupMember")
+	defer done()
+
+	if err := groupExists(ctx, s.reader(), group, s.OrganizationID); err != nil {
+		return false, err
+	}
+	var member int
+	err := s.reader().QueryRowContext(ctx, `
+		SELECT 1
+		FROM user_groups g
+		JOIN group_members m ON m.group_id = g.id
+		WHERE g.name = ? AND m.user_id = ?
+	`, group, userID).Scan(&member)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check group member: %w", err)
+	}
+	return true, nil
+}
@@ -0,0 +1,70 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrIdempotencyKeyNotFound возвращается, если ответ для ключа идемпотентности еще не сохранен
+var ErrIdempotencyKeyNotFound = errors.New("idempotency key not found")
+
+// GetIdempotentResponse возвращает сохраненный ответ для ключа key в области scope
+func (s *DBStorage) GetIdempotentResponse(scope, key string) (models.IdempotentResponse, error) {
+	ctx, done := s.startQuery("GetIdempotentResponse")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT scope, idem_key, request_hash, status_code, response_body, created_at "+
+			"FROM idempotency_keys WHERE scope = ? AND idem_key = ?")
+	if err != nil {
+		return models.IdempotentResponse{}, err
+	}
+
+	var resp models.IdempotentResponse
+	err = stmt.QueryRowContext(ctx, scope, key).Scan(
+		&resp.Scope,
+		&resp.Key,
+		&resp.RequestHash,
+		&resp.StatusCode,
+		&resp.Body,
+		&resp.CreatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.IdempotentResponse{}, ErrIdempotencyKeyNotFound
+		}
+		return models.IdempotentResponse{}, err
+	}
+
+	return resp, nil
+}
+
+// SaveIdempotentResponse сохраняет ответ на запрос с ключом идемпотентности
+func (s *DBStorage) SaveIdempotentResponse(resp models.IdempotentResponse) error {
+	ctx, done := s.startQuery("SaveIdempotentResponse")
+	defer done()
+
+	_, err := s.DB.ExecContext(ctx,
+		"INSERT INTO idempotency_keys (scope, idem_key, request_hash, status_code, response_body, created_at) "+
+			"VALUES (?, ?, ?, ?, ?, ?)",
+		resp.Scope, resp.Key, resp.RequestHash, resp.StatusCode, resp.Body, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("save idempotent response: %w", err)
+	}
+	return nil
+}
+
+// PurgeIdempotencyKeys удаляет ответы, сохраненные раньше before
+func (s *DBStorage) PurgeIdempotencyKeys(before time.Time) (int64, error) {
+	ctx, done := s.startQuery("PurgeIdempotencyKeys")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE created_at < ?", before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,72 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+//go:generate go run ./internal/instrumentgen
+
+// Метрики вызовов хранилища в разрезе методов Storage; регистрируются в реестре Prometheus
+// по умолчанию и отдаются на /metrics
+var (
+	methodCalls = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_method_calls_total",
+		Help: "Calls of storage methods.",
+	}, []string{"method"})
+	methodErrors = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_method_errors_total",
+		Help: "Storage method calls that returned an error, including not found errors.",
+	}, []string{"method"})
+	methodRows = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_method_rows_total",
+		Help: "Rows returned by storage methods that return lists.",
+	}, []string{"method"})
+	methodDurations = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_method_duration_seconds",
+		Help:    "Duration of storage method calls.",
+		Buckets: []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5},
+	}, []string{"method"})
+)
+
+// InstrumentedStorage — декоратор Storage, который считает вызовы, ошибки, длительность и
+// число возвращенных строк каждого метода. Методы сгенерированы в instrumented_gen.go
+// командой go generate по интерфейсу Storage.
+type InstrumentedStorage struct {
+	next Storage
+}
+
+// Instrument оборачивает хранилище метриками вызовов
+func Instrument(store Storage) *InstrumentedStorage {
+	return &InstrumentedStorage{next: store}
+}
+
+// Unwrap возвращает хранилище без декораторов метрик и сбоев
+func Unwrap(store Storage) Storage {
+	for {
+		switch decorated := store.(type) {
+		case *InstrumentedStorage:
+			store = decorated.next
+		case *FaultyStorage:
+			store = decorated.next
+		default:
+			return store
+		}
+	}
+}
+
+// observeCall фиксирует завершенный вызов метода. rows < 0 — метод не возвращает строк.
+// Ошибками считаются любые ошибки, включая «не найдено»: рост их доли после релиза тоже
+// заслуживает внимания.
+func observeCall(method string, started time.Time, rows int, err error) {
+	methodCalls.WithLabelValues(method).Inc()
+	if err != nil {
+		methodErrors.WithLabelValues(method).Inc()
+	}
+	if rows >= 0 {
+		methodRows.WithLabelValues(method).Add(float64(rows))
+	}
+	methodDurations.WithLabelValues(method).Observe(time.Since(started).Seconds())
+}
@@ -0,0 +1,1983 @@
+// Code generated by instrumentgen from storage.go; DO NOT EDIT.
+
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+func (s *InstrumentedStorage) GetCourses() ([]models.Course, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCourses()
+	observeCall("GetCourses", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCourseFields(fields []string) ([]models.Course, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCourseFields(fields)
+	observeCall("GetCourseFields", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCourseByID(id int) (models.Course, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCourseByID(id)
+	observeCall("GetCourseByID", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetUserProgress(userID int) (models.UserProgress, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetUserProgress(userID)
+	observeCall("GetUserProgress", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCoursesByIDs(ids []int) (map[int]models.Course, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCoursesByIDs(ids)
+	observeCall("GetCoursesByIDs", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetUsersByIDs(ids []int) (map[int]models.User, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetUsersByIDs(ids)
+	observeCall("GetUsersByIDs", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetProgressByUserIDs(userIDs []int) (map[int]models.UserProgress, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetProgressByUserIDs(userIDs)
+	observeCall("GetProgressByUserIDs", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CompleteTask(userID int, taskID int) error {
+	started := time.Now()
+	r0 := s.next.CompleteTask(userID, taskID)
+	observeCall("CompleteTask", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) CompleteTasks(userID int, taskIDs []int) ([]models.TaskCompletionResult, error) {
+	started := time.Now()
+	r0, r1 := s.next.CompleteTasks(userID, taskIDs)
+	observeCall("CompleteTasks", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateCourse(course models.Course) (int, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateCourse(course)
+	observeCall("CreateCourse", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) RefreshCourseSummaries() (int, error) {
+	started := time.Now()
+	r0, r1 := s.next.RefreshCourseSummaries()
+	observeCall("RefreshCourseSummaries", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ArchiveCourse(courseID int) error {
+	started := time.Now()
+	r0 := s.next.ArchiveCourse(courseID)
+	observeCall("ArchiveCourse", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) RestoreCourse(courseID int) error {
+	started := time.Now()
+	r0 := s.next.RestoreCourse(courseID)
+	observeCall("RestoreCourse", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) PurgeCourse(courseID int) error {
+	started := time.Now()
+	r0 := s.next.PurgeCourse(courseID)
+	observeCall("PurgeCourse", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListArchivedCourses() ([]models.Course, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListArchivedCourses()
+	observeCall("ListArchivedCourses", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) AddTask(task models.Task) (models.Task, error) {
+	started := time.Now()
+	r0, r1 := s.next.AddTask(task)
+	observeCall("AddTask", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpdateTask(task models.Task) (models.Task, error) {
+	started := time.Now()
+	r0, r1 := s.next.UpdateTask(task)
+	observeCall("UpdateTask", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCourseChangelog(courseID int, userID int, limit int) (models.CourseChangelog, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCourseChangelog(courseID, userID, limit)
+	observeCall("GetCourseChangelog", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListUnseenCourseChanges(userID int, limit int) ([]models.CourseChange, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListUnseenCourseChanges(userID, limit)
+	observeCall("ListUnseenCourseChanges", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) MarkCourseChangesSeen(userID int, courseID int) error {
+	started := time.Now()
+	r0 := s.next.MarkCourseChangesSeen(userID, courseID)
+	observeCall("MarkCourseChangesSeen", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) SaveBookmark(bookmark models.Bookmark) (models.Bookmark, error) {
+	started := time.Now()
+	r0, r1 := s.next.SaveBookmark(bookmark)
+	observeCall("SaveBookmark", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteBookmark(userID int, courseID int, taskID *int) error {
+	started := time.Now()
+	r0 := s.next.DeleteBookmark(userID, courseID, taskID)
+	observeCall("DeleteBookmark", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListBookmarks(userID int) ([]models.Bookmark, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListBookmarks(userID)
+	observeCall("ListBookmarks", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListBookmarkSubscribers(courseID int) ([]int, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListBookmarkSubscribers(courseID)
+	observeCall("ListBookmarkSubscribers", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetTaskNote(userID int, taskID int) (models.TaskNote, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetTaskNote(userID, taskID)
+	observeCall("GetTaskNote", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SaveTaskNote(userID int, taskID int, body string, expectedVersion int) (models.TaskNote, error) {
+	started := time.Now()
+	r0, r1 := s.next.SaveTaskNote(userID, taskID, body, expectedVersion)
+	observeCall("SaveTaskNote", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteTaskNote(userID int, taskID int) error {
+	started := time.Now()
+	r0 := s.next.DeleteTaskNote(userID, taskID)
+	observeCall("DeleteTaskNote", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListTaskNotes(userID int) ([]models.TaskNote, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListTaskNotes(userID)
+	observeCall("ListTaskNotes", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCourseTranslations(locale string) (map[int]models.CourseTranslation, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCourseTranslations(locale)
+	observeCall("GetCourseTranslations", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetTaskTranslations(courseID int, locale string) (map[int]models.TaskTranslation, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetTaskTranslations(courseID, locale)
+	observeCall("GetTaskTranslations", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListCourseTranslations(courseID int) (models.CourseTranslations, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListCourseTranslations(courseID)
+	observeCall("ListCourseTranslations", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetCourseTranslation(translation models.CourseTranslation) error {
+	started := time.Now()
+	r0 := s.next.SetCourseTranslation(translation)
+	observeCall("SetCourseTranslation", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) DeleteCourseTranslation(courseID int, locale string) error {
+	started := time.Now()
+	r0 := s.next.DeleteCourseTranslation(courseID, locale)
+	observeCall("DeleteCourseTranslation", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) SetTaskTranslation(translation models.TaskTranslation) error {
+	started := time.Now()
+	r0 := s.next.SetTaskTranslation(translation)
+	observeCall("SetTaskTranslation", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) DeleteTaskTranslation(taskID int, locale string) error {
+	started := time.Now()
+	r0 := s.next.DeleteTaskTranslation(taskID, locale)
+	observeCall("DeleteTaskTranslation", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) CreateUser(user models.User) error {
+	started := time.Now()
+	r0 := s.next.CreateUser(user)
+	observeCall("CreateUser", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetUserByUsername(username string) (models.User, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetUserByUsername(username)
+	observeCall("GetUserByUsername", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetUserByID(id int) (models.User, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetUserByID(id)
+	observeCall("GetUserByID", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpdateUserLastLogin(userID int) error {
+	started := time.Now()
+	r0 := s.next.UpdateUserLastLogin(userID)
+	observeCall("UpdateUserLastLogin", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) Enable2FA(userID int) error {
+	started := time.Now()
+	r0 := s.next.Enable2FA(userID)
+	observeCall("Enable2FA", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) IsAdmin(userID int) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.IsAdmin(userID)
+	observeCall("IsAdmin", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetUserPublicByID(id int) (models.UserSummary, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetUserPublicByID(id)
+	observeCall("GetUserPublicByID", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListUserSummaries() ([]models.UserSummary, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListUserSummaries()
+	observeCall("ListUserSummaries", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListUserSummaryFields(fields []string) ([]models.UserSummary, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListUserSummaryFields(fields)
+	observeCall("ListUserSummaryFields", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) EachUserSummary(fn func(models.UserSummary) error) error {
+	rows := 0
+	counted := func(row models.UserSummary) error {
+		rows++
+		return fn(row)
+	}
+	started := time.Now()
+	r0 := s.next.EachUserSummary(counted)
+	observeCall("EachUserSummary", started, rows, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) EachProgressRecord(filter models.ProgressExportFilter, fn func(models.ProgressRecord) error) error {
+	rows := 0
+	counted := func(row models.ProgressRecord) error {
+		rows++
+		return fn(row)
+	}
+	started := time.Now()
+	r0 := s.next.EachProgressRecord(filter, counted)
+	observeCall("EachProgressRecord", started, rows, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) UpdateUserProfile(userID int, data models.UpdateProfileRequest) error {
+	started := time.Now()
+	r0 := s.next.UpdateUserProfile(userID, data)
+	observeCall("UpdateUserProfile", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetUsersByRole(isAdmin bool) ([]models.UserSummary, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetUsersByRole(isAdmin)
+	observeCall("GetUsersByRole", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SearchUsers(query string) ([]models.UserSummary, error) {
+	started := time.Now()
+	r0, r1 := s.next.SearchUsers(query)
+	observeCall("SearchUsers", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpdateUserStatus(userID int, isActive bool, version int) error {
+	started := time.Now()
+	r0 := s.next.UpdateUserStatus(userID, isActive, version)
+	observeCall("UpdateUserStatus", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) PromoteToAdmin(userID int) error {
+	started := time.Now()
+	r0 := s.next.PromoteToAdmin(userID)
+	observeCall("PromoteToAdmin", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) DemoteFromAdmin(userID int) error {
+	started := time.Now()
+	r0 := s.next.DemoteFromAdmin(userID)
+	observeCall("DemoteFromAdmin", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) DeleteUser(userID int) error {
+	started := time.Now()
+	r0 := s.next.DeleteUser(userID)
+	observeCall("DeleteUser", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) SaveOTPCode(userID int, code string, ttl time.Duration) error {
+	started := time.Now()
+	r0 := s.next.SaveOTPCode(userID, code, ttl)
+	observeCall("SaveOTPCode", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) VerifyOTPCode(userID int, code string, maxAttempts int) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.VerifyOTPCode(userID, code, maxAttempts)
+	observeCall("VerifyOTPCode", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ClearOTPCode(userID int) error {
+	started := time.Now()
+	r0 := s.next.ClearOTPCode(userID)
+	observeCall("ClearOTPCode", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) PurgeExpiredOTPCodes() (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.PurgeExpiredOTPCodes()
+	observeCall("PurgeExpiredOTPCodes", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetOTPPreference(userID int) (models.OTPPreference, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetOTPPreference(userID)
+	observeCall("GetOTPPreference", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetOTPPreference(pref models.OTPPreference) error {
+	started := time.Now()
+	r0 := s.next.SetOTPPreference(pref)
+	observeCall("SetOTPPreference", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) RecordOTPDelivery(delivery models.OTPDelivery) error {
+	started := time.Now()
+	r0 := s.next.RecordOTPDelivery(delivery)
+	observeCall("RecordOTPDelivery", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListOTPDeliveries(userID int, limit int) ([]models.OTPDelivery, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListOTPDeliveries(userID, limit)
+	observeCall("ListOTPDeliveries", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateTrustedDevice(device models.TrustedDevice) (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateTrustedDevice(device)
+	observeCall("CreateTrustedDevice", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetTrustedDevice(tokenHash string) (models.TrustedDevice, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetTrustedDevice(tokenHash)
+	observeCall("GetTrustedDevice", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) TouchTrustedDevice(id int64) error {
+	started := time.Now()
+	r0 := s.next.TouchTrustedDevice(id)
+	observeCall("TouchTrustedDevice", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListTrustedDevices(userID int) ([]models.TrustedDevice, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListTrustedDevices(userID)
+	observeCall("ListTrustedDevices", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) RevokeTrustedDevice(userID int, id int64) error {
+	started := time.Now()
+	r0 := s.next.RevokeTrustedDevice(userID, id)
+	observeCall("RevokeTrustedDevice", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) RevokeTrustedDevices(userID int) (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.RevokeTrustedDevices(userID)
+	observeCall("RevokeTrustedDevices", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) PurgeTrustedDevices() (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.PurgeTrustedDevices()
+	observeCall("PurgeTrustedDevices", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) PurgeExpiredTokens(now time.Time) (map[string]int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.PurgeExpiredTokens(now)
+	observeCall("PurgeExpiredTokens", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) RecordLoginContext(login models.LoginContext) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.RecordLoginContext(login)
+	observeCall("RecordLoginContext", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListLoginContexts(userID int) ([]models.LoginContext, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListLoginContexts(userID)
+	observeCall("ListLoginContexts", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateAccountLockToken(userID int, tokenHash string, expiresAt time.Time) error {
+	started := time.Now()
+	r0 := s.next.CreateAccountLockToken(userID, tokenHash, expiresAt)
+	observeCall("CreateAccountLockToken", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) LockAccountByToken(tokenHash string) (int, error) {
+	started := time.Now()
+	r0, r1 := s.next.LockAccountByToken(tokenHash)
+	observeCall("LockAccountByToken", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetSessionState(userID int) (models.SessionState, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetSessionState(userID)
+	observeCall("GetSessionState", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) StartSession(userID int, sessionID string) error {
+	started := time.Now()
+	r0 := s.next.StartSession(userID, sessionID)
+	observeCall("StartSession", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) PurgeStaleSessions(before time.Time) (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.PurgeStaleSessions(before)
+	observeCall("PurgeStaleSessions", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetSingleSession(userID int, enabled bool) error {
+	started := time.Now()
+	r0 := s.next.SetSingleSession(userID, enabled)
+	observeCall("SetSingleSession", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) SetMustChangePassword(userID int, required bool) error {
+	started := time.Now()
+	r0 := s.next.SetMustChangePassword(userID, required)
+	observeCall("SetMustChangePassword", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ScheduleAccountDeletion(userID int, purgeAt time.Time) error {
+	started := time.Now()
+	r0 := s.next.ScheduleAccountDeletion(userID, purgeAt)
+	observeCall("ScheduleAccountDeletion", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) CancelAccountDeletion(userID int) error {
+	started := time.Now()
+	r0 := s.next.CancelAccountDeletion(userID)
+	observeCall("CancelAccountDeletion", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) PurgeDeletedAccounts(now time.Time) (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.PurgeDeletedAccounts(now)
+	observeCall("PurgeDeletedAccounts", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateEmailChange(change models.EmailChange) (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateEmailChange(change)
+	observeCall("CreateEmailChange", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ConfirmEmailChange(tokenHash string) (models.EmailChange, error) {
+	started := time.Now()
+	r0, r1 := s.next.ConfirmEmailChange(tokenHash)
+	observeCall("ConfirmEmailChange", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CancelEmailChange(tokenHash string) (models.EmailChange, error) {
+	started := time.Now()
+	r0, r1 := s.next.CancelEmailChange(tokenHash)
+	observeCall("CancelEmailChange", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListEmailChanges(userID int) ([]models.EmailChange, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListEmailChanges(userID)
+	observeCall("ListEmailChanges", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ChangeUsername(change models.UsernameChange, minInterval time.Duration) (models.UsernameChange, error) {
+	started := time.Now()
+	r0, r1 := s.next.ChangeUsername(change, minInterval)
+	observeCall("ChangeUsername", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListUsernameHistory(userID int) ([]models.UsernameChange, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListUsernameHistory(userID)
+	observeCall("ListUsernameHistory", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ResolveUsername(username string) (int, error) {
+	started := time.Now()
+	r0, r1 := s.next.ResolveUsername(username)
+	observeCall("ResolveUsername", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetProfilePrivacy(userID int) (models.ProfilePrivacy, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetProfilePrivacy(userID)
+	observeCall("GetProfilePrivacy", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetProfilePrivacy(userID int, privacy models.ProfilePrivacy) error {
+	started := time.Now()
+	r0 := s.next.SetProfilePrivacy(userID, privacy)
+	observeCall("SetProfilePrivacy", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetTaskCompletions(userID int) ([]models.TaskCompletion, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetTaskCompletions(userID)
+	observeCall("GetTaskCompletions", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetAvatar(userID int, avatarKey string) (string, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetAvatar(userID, avatarKey)
+	observeCall("SetAvatar", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateAnnouncement(announcement models.Announcement) (models.Announcement, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateAnnouncement(announcement)
+	observeCall("CreateAnnouncement", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetAnnouncement(id int) (models.Announcement, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetAnnouncement(id)
+	observeCall("GetAnnouncement", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpdateAnnouncement(announcement models.Announcement) (models.Announcement, error) {
+	started := time.Now()
+	r0, r1 := s.next.UpdateAnnouncement(announcement)
+	observeCall("UpdateAnnouncement", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteAnnouncement(id int) error {
+	started := time.Now()
+	r0 := s.next.DeleteAnnouncement(id)
+	observeCall("DeleteAnnouncement", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListAnnouncements(filter models.AnnouncementFilter) ([]models.Announcement, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListAnnouncements(filter)
+	observeCall("ListAnnouncements", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ClaimDueAnnouncements(now time.Time) ([]models.Announcement, error) {
+	started := time.Now()
+	r0, r1 := s.next.ClaimDueAnnouncements(now)
+	observeCall("ClaimDueAnnouncements", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) OpenConversation(courseID int, studentID int, instructorID int) (models.Conversation, error) {
+	started := time.Now()
+	r0, r1 := s.next.OpenConversation(courseID, studentID, instructorID)
+	observeCall("OpenConversation", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetConversation(id int) (models.Conversation, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetConversation(id)
+	observeCall("GetConversation", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListConversations(userID int) ([]models.Conversation, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListConversations(userID)
+	observeCall("ListConversations", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) AddConversationMessage(message models.ConversationMessage) (models.ConversationMessage, error) {
+	started := time.Now()
+	r0, r1 := s.next.AddConversationMessage(message)
+	observeCall("AddConversationMessage", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetConversationMessage(id int) (models.ConversationMessage, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetConversationMessage(id)
+	observeCall("GetConversationMessage", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListConversationMessages(conversationID int, beforeID int, limit int) ([]models.ConversationMessage, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListConversationMessages(conversationID, beforeID, limit)
+	observeCall("ListConversationMessages", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) MarkConversationRead(conversationID int, userID int, messageID int) error {
+	started := time.Now()
+	r0 := s.next.MarkConversationRead(conversationID, userID, messageID)
+	observeCall("MarkConversationRead", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ReportConversationMessage(messageID int, reporterID int) error {
+	started := time.Now()
+	r0 := s.next.ReportConversationMessage(messageID, reporterID)
+	observeCall("ReportConversationMessage", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ModerateConversationMessage(messageID int, moderatorID int, hidden bool) error {
+	started := time.Now()
+	r0 := s.next.ModerateConversationMessage(messageID, moderatorID, hidden)
+	observeCall("ModerateConversationMessage", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListReportedMessages(limit int) ([]models.ConversationMessage, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListReportedMessages(limit)
+	observeCall("ListReportedMessages", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetTaskSchedule(schedule models.TaskSchedule) (models.TaskSchedule, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetTaskSchedule(schedule)
+	observeCall("SetTaskSchedule", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListTaskSchedules(courseIDs []int, from time.Time, to time.Time) ([]models.TaskSchedule, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListTaskSchedules(courseIDs, from, to)
+	observeCall("ListTaskSchedules", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateLiveSession(session models.LiveSession) (models.LiveSession, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateLiveSession(session)
+	observeCall("CreateLiveSession", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetLiveSession(id int) (models.LiveSession, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetLiveSession(id)
+	observeCall("GetLiveSession", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpdateLiveSession(session models.LiveSession) (models.LiveSession, error) {
+	started := time.Now()
+	r0, r1 := s.next.UpdateLiveSession(session)
+	observeCall("UpdateLiveSession", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteLiveSession(id int) error {
+	started := time.Now()
+	r0 := s.next.DeleteLiveSession(id)
+	observeCall("DeleteLiveSession", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListLiveSessions(courseIDs []int, from time.Time, to time.Time) ([]models.LiveSession, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListLiveSessions(courseIDs, from, to)
+	observeCall("ListLiveSessions", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetStartedCourseIDs(userID int) ([]int, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetStartedCourseIDs(userID)
+	observeCall("GetStartedCourseIDs", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetCalendarFeedToken(userID int, tokenHash string) error {
+	started := time.Now()
+	r0 := s.next.SetCalendarFeedToken(userID, tokenHash)
+	observeCall("SetCalendarFeedToken", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) DeleteCalendarFeedToken(userID int) error {
+	started := time.Now()
+	r0 := s.next.DeleteCalendarFeedToken(userID)
+	observeCall("DeleteCalendarFeedToken", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetCalendarFeedUser(tokenHash string) (int, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCalendarFeedUser(tokenHash)
+	observeCall("GetCalendarFeedUser", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateTicket(ticket models.Ticket, body string) (models.Ticket, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateTicket(ticket, body)
+	observeCall("CreateTicket", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetTicket(id int) (models.Ticket, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetTicket(id)
+	observeCall("GetTicket", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListTickets(filter models.TicketFilter) ([]models.Ticket, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListTickets(filter)
+	observeCall("ListTickets", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) AddTicketReply(reply models.TicketReply, status string) (models.TicketReply, error) {
+	started := time.Now()
+	r0, r1 := s.next.AddTicketReply(reply, status)
+	observeCall("AddTicketReply", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListTicketReplies(ticketID int, includeInternal bool) ([]models.TicketReply, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListTicketReplies(ticketID, includeInternal)
+	observeCall("ListTicketReplies", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetTicketStatus(ticketID int, status string) (models.Ticket, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetTicketStatus(ticketID, status)
+	observeCall("SetTicketStatus", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetTicketAssignee(ticketID int, assigneeID *int) (models.Ticket, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetTicketAssignee(ticketID, assigneeID)
+	observeCall("SetTicketAssignee", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SaveCourseSurvey(survey models.Survey) (models.Survey, error) {
+	started := time.Now()
+	r0, r1 := s.next.SaveCourseSurvey(survey)
+	observeCall("SaveCourseSurvey", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCourseSurvey(courseID int) (models.Survey, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCourseSurvey(courseID)
+	observeCall("GetCourseSurvey", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteCourseSurvey(courseID int) error {
+	started := time.Now()
+	r0 := s.next.DeleteCourseSurvey(courseID)
+	observeCall("DeleteCourseSurvey", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) SubmitSurveyResponse(survey models.Survey, userID int, answers []models.SurveyAnswer) error {
+	started := time.Now()
+	r0 := s.next.SubmitSurveyResponse(survey, userID, answers)
+	observeCall("SubmitSurveyResponse", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) HasAnsweredSurvey(surveyID int, userID int) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.HasAnsweredSurvey(surveyID, userID)
+	observeCall("HasAnsweredSurvey", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListSurveyResponses(surveyID int) ([]models.SurveyResponse, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListSurveyResponses(surveyID)
+	observeCall("ListSurveyResponses", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateThread(thread models.DiscussionThread, body string) (models.DiscussionThread, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateThread(thread, body)
+	observeCall("CreateThread", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListThreads(courseID int) ([]models.DiscussionThread, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListThreads(courseID)
+	observeCall("ListThreads", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetThread(id int) (models.DiscussionThread, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetThread(id)
+	observeCall("GetThread", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetThreadLock(threadID int, moderatorID int, locked bool) (models.DiscussionThread, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetThreadLock(threadID, moderatorID, locked)
+	observeCall("SetThreadLock", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListThreadComments(threadID int) ([]models.DiscussionComment, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListThreadComments(threadID)
+	observeCall("ListThreadComments", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) AddComment(comment models.DiscussionComment) (models.DiscussionComment, error) {
+	started := time.Now()
+	r0, r1 := s.next.AddComment(comment)
+	observeCall("AddComment", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetComment(id int) (models.DiscussionComment, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetComment(id)
+	observeCall("GetComment", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SaveReview(review models.CourseReview) (models.CourseReview, error) {
+	started := time.Now()
+	r0, r1 := s.next.SaveReview(review)
+	observeCall("SaveReview", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListCourseReviews(courseID int) ([]models.CourseReview, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListCourseReviews(courseID)
+	observeCall("ListCourseReviews", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetReview(id int) (models.CourseReview, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetReview(id)
+	observeCall("GetReview", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetContentHidden(contentType string, contentID int, moderatorID int, hidden bool) error {
+	started := time.Now()
+	r0 := s.next.SetContentHidden(contentType, contentID, moderatorID, hidden)
+	observeCall("SetContentHidden", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) SetCourseBan(ban models.CourseBan) (models.CourseBan, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetCourseBan(ban)
+	observeCall("SetCourseBan", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteCourseBan(courseID int, userID int) error {
+	started := time.Now()
+	r0 := s.next.DeleteCourseBan(courseID, userID)
+	observeCall("DeleteCourseBan", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListCourseBans(courseID int) ([]models.CourseBan, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListCourseBans(courseID)
+	observeCall("ListCourseBans", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ReportContent(report models.ContentReport) (models.ContentReport, error) {
+	started := time.Now()
+	r0, r1 := s.next.ReportContent(report)
+	observeCall("ReportContent", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListContentReports(status string, limit int) ([]models.ContentReport, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListContentReports(status, limit)
+	observeCall("ListContentReports", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ResolveContentReport(reportID int, moderatorID int, status string, note string) (models.ContentReport, error) {
+	started := time.Now()
+	r0, r1 := s.next.ResolveContentReport(reportID, moderatorID, status, note)
+	observeCall("ResolveContentReport", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateScormCourse(course models.Course, pkg models.ScormPackage) (models.ScormPackage, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateScormCourse(course, pkg)
+	observeCall("CreateScormCourse", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetScormPackage(courseID int) (models.ScormPackage, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetScormPackage(courseID)
+	observeCall("GetScormPackage", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetScormItem(taskID int) (models.ScormItem, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetScormItem(taskID)
+	observeCall("GetScormItem", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetScormAttempt(userID int, taskID int) (models.ScormAttempt, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetScormAttempt(userID, taskID)
+	observeCall("GetScormAttempt", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SaveScormAttempt(attempt models.ScormAttempt) (models.ScormAttempt, error) {
+	started := time.Now()
+	r0, r1 := s.next.SaveScormAttempt(attempt)
+	observeCall("SaveScormAttempt", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateBadgeClass(class models.BadgeClass) (models.BadgeClass, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateBadgeClass(class)
+	observeCall("CreateBadgeClass", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpdateBadgeClass(class models.BadgeClass) (models.BadgeClass, error) {
+	started := time.Now()
+	r0, r1 := s.next.UpdateBadgeClass(class)
+	observeCall("UpdateBadgeClass", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteBadgeClass(id int) error {
+	started := time.Now()
+	r0 := s.next.DeleteBadgeClass(id)
+	observeCall("DeleteBadgeClass", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetBadgeClass(id int) (models.BadgeClass, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetBadgeClass(id)
+	observeCall("GetBadgeClass", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCourseBadgeClass(courseID int) (models.BadgeClass, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCourseBadgeClass(courseID)
+	observeCall("GetCourseBadgeClass", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListBadgeClasses() ([]models.BadgeClass, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListBadgeClasses()
+	observeCall("ListBadgeClasses", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetBadgeClassImage(id int) ([]byte, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetBadgeClassImage(id)
+	observeCall("GetBadgeClassImage", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetBadgeClassImage(id int, image []byte) (models.BadgeClass, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetBadgeClassImage(id, image)
+	observeCall("SetBadgeClassImage", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateBadgeAssertion(assertion models.BadgeAssertion) (models.BadgeAssertion, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateBadgeAssertion(assertion)
+	observeCall("CreateBadgeAssertion", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetBadgeAssertion(id string) (models.BadgeAssertion, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetBadgeAssertion(id)
+	observeCall("GetBadgeAssertion", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListUserBadges(userID int) ([]models.BadgeAssertion, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListUserBadges(userID)
+	observeCall("ListUserBadges", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListBadgeClassAssertions(classID int) ([]models.BadgeAssertion, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListBadgeClassAssertions(classID)
+	observeCall("ListBadgeClassAssertions", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) RevokeBadgeAssertion(id string, reason string) (models.BadgeAssertion, error) {
+	started := time.Now()
+	r0, r1 := s.next.RevokeBadgeAssertion(id, reason)
+	observeCall("RevokeBadgeAssertion", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateCertificate(cert models.Certificate) (models.Certificate, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateCertificate(cert)
+	observeCall("CreateCertificate", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCertificateByCode(code string) (models.Certificate, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCertificateByCode(code)
+	observeCall("GetCertificateByCode", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListUserCertificates(userID int) ([]models.Certificate, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListUserCertificates(userID)
+	observeCall("ListUserCertificates", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) RevokeCertificate(code string, adminID int, reason string) (models.Certificate, error) {
+	started := time.Now()
+	r0, r1 := s.next.RevokeCertificate(code, adminID, reason)
+	observeCall("RevokeCertificate", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetTelegramLinkCode(userID int, codeHash string, expiresAt time.Time) error {
+	started := time.Now()
+	r0 := s.next.SetTelegramLinkCode(userID, codeHash, expiresAt)
+	observeCall("SetTelegramLinkCode", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) LinkTelegramAccount(codeHash string, account models.TelegramAccount) (models.TelegramAccount, error) {
+	started := time.Now()
+	r0, r1 := s.next.LinkTelegramAccount(codeHash, account)
+	observeCall("LinkTelegramAccount", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetTelegramAccount(userID int) (models.TelegramAccount, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetTelegramAccount(userID)
+	observeCall("GetTelegramAccount", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetTelegramAccountByChat(chatID int64) (models.TelegramAccount, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetTelegramAccountByChat(chatID)
+	observeCall("GetTelegramAccountByChat", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListTelegramAccounts() ([]models.TelegramAccount, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListTelegramAccounts()
+	observeCall("ListTelegramAccounts", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteTelegramAccount(userID int) error {
+	started := time.Now()
+	r0 := s.next.DeleteTelegramAccount(userID)
+	observeCall("DeleteTelegramAccount", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) MarkTelegramDeadlineReminder(userID int, taskID int, dueAt time.Time) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.MarkTelegramDeadlineReminder(userID, taskID, dueAt)
+	observeCall("MarkTelegramDeadlineReminder", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateChatChannel(channel models.ChatChannel) (models.ChatChannel, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateChatChannel(channel)
+	observeCall("CreateChatChannel", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpdateChatChannel(channel models.ChatChannel) (models.ChatChannel, error) {
+	started := time.Now()
+	r0, r1 := s.next.UpdateChatChannel(channel)
+	observeCall("UpdateChatChannel", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteChatChannel(id int) error {
+	started := time.Now()
+	r0 := s.next.DeleteChatChannel(id)
+	observeCall("DeleteChatChannel", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetChatChannel(id int) (models.ChatChannel, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetChatChannel(id)
+	observeCall("GetChatChannel", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListChatChannels() ([]models.ChatChannel, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListChatChannels()
+	observeCall("ListChatChannels", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetChatLeaderboardSent(id int, at time.Time) error {
+	started := time.Now()
+	r0 := s.next.SetChatLeaderboardSent(id, at)
+	observeCall("SetChatLeaderboardSent", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) MarkChatDeadlineReminder(channelID int, taskID int, dueAt time.Time) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.MarkChatDeadlineReminder(channelID, taskID, dueAt)
+	observeCall("MarkChatDeadlineReminder", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SavePushSubscription(sub models.PushSubscription) (models.PushSubscription, error) {
+	started := time.Now()
+	r0, r1 := s.next.SavePushSubscription(sub)
+	observeCall("SavePushSubscription", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListPushSubscriptions(userID int) ([]models.PushSubscription, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListPushSubscriptions(userID)
+	observeCall("ListPushSubscriptions", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListPushSubscriberIDs() ([]int, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListPushSubscriberIDs()
+	observeCall("ListPushSubscriberIDs", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeletePushSubscription(userID int, id int) error {
+	started := time.Now()
+	r0 := s.next.DeletePushSubscription(userID, id)
+	observeCall("DeletePushSubscription", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) DeletePushSubscriptionByEndpoint(endpoint string) error {
+	started := time.Now()
+	r0 := s.next.DeletePushSubscriptionByEndpoint(endpoint)
+	observeCall("DeletePushSubscriptionByEndpoint", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) MarkPushDeadlineReminder(userID int, taskID int, dueAt time.Time) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.MarkPushDeadlineReminder(userID, taskID, dueAt)
+	observeCall("MarkPushDeadlineReminder", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetDigestSettings(userID int) (models.DigestSettings, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetDigestSettings(userID)
+	observeCall("GetDigestSettings", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetDigestEnabled(userID int, enabled bool) error {
+	started := time.Now()
+	r0 := s.next.SetDigestEnabled(userID, enabled)
+	observeCall("SetDigestEnabled", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) UnsubscribeDigest(tokenHash string) (int, error) {
+	started := time.Now()
+	r0, r1 := s.next.UnsubscribeDigest(tokenHash)
+	observeCall("UnsubscribeDigest", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListDigestRecipients(slot time.Time) ([]int, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListDigestRecipients(slot)
+	observeCall("ListDigestRecipients", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) MarkDigestSent(userID int, tokenHash string, at time.Time) error {
+	started := time.Now()
+	r0 := s.next.MarkDigestSent(userID, tokenHash, at)
+	observeCall("MarkDigestSent", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListCoursesCreatedBetween(from time.Time, to time.Time) ([]models.Course, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListCoursesCreatedBetween(from, to)
+	observeCall("ListCoursesCreatedBetween", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CountCompletionsByUser(from time.Time, to time.Time) (map[int]int, error) {
+	started := time.Now()
+	r0, r1 := s.next.CountCompletionsByUser(from, to)
+	observeCall("CountCompletionsByUser", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) RecordUserActivity(userID int, day time.Time) error {
+	started := time.Now()
+	r0 := s.next.RecordUserActivity(userID, day)
+	observeCall("RecordUserActivity", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetPlatformStats(from time.Time, to time.Time) (models.PlatformStats, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetPlatformStats(from, to)
+	observeCall("GetPlatformStats", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SnapshotProgress(day time.Time) (int, error) {
+	started := time.Now()
+	r0, r1 := s.next.SnapshotProgress(day)
+	observeCall("SnapshotProgress", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetProgressHistory(userID int, courseID int, from time.Time) ([]models.CourseProgressHistory, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetProgressHistory(userID, courseID, from)
+	observeCall("GetProgressHistory", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) FetchPendingEvents(limit int) ([]models.OutboxEvent, error) {
+	started := time.Now()
+	r0, r1 := s.next.FetchPendingEvents(limit)
+	observeCall("FetchPendingEvents", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) MarkEventDispatched(eventID int64) error {
+	started := time.Now()
+	r0 := s.next.MarkEventDispatched(eventID)
+	observeCall("MarkEventDispatched", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) MarkEventFailed(eventID int64, reason string, retryAt time.Time) error {
+	started := time.Now()
+	r0 := s.next.MarkEventFailed(eventID, reason, retryAt)
+	observeCall("MarkEventFailed", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) PurgeDispatchedEvents(before time.Time) (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.PurgeDispatchedEvents(before)
+	observeCall("PurgeDispatchedEvents", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetSettings() ([]models.Setting, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetSettings()
+	observeCall("GetSettings", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpsertSetting(setting models.Setting) error {
+	started := time.Now()
+	r0 := s.next.UpsertSetting(setting)
+	observeCall("UpsertSetting", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetUserGroups(userID int) ([]string, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetUserGroups(userID)
+	observeCall("GetUserGroups", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GroupLeaderboard(group string, from time.Time, to time.Time, limit int) ([]models.LeaderboardEntry, error) {
+	started := time.Now()
+	r0, r1 := s.next.GroupLeaderboard(group, from, to, limit)
+	observeCall("GroupLeaderboard", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) IsGroupMember(group string, userID int) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.IsGroupMember(group, userID)
+	observeCall("IsGroupMember", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateTeamChallenge(challenge models.TeamChallenge) (models.TeamChallenge, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateTeamChallenge(challenge)
+	observeCall("CreateTeamChallenge", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpdateTeamChallenge(challenge models.TeamChallenge) (models.TeamChallenge, error) {
+	started := time.Now()
+	r0, r1 := s.next.UpdateTeamChallenge(challenge)
+	observeCall("UpdateTeamChallenge", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteTeamChallenge(id int) error {
+	started := time.Now()
+	r0 := s.next.DeleteTeamChallenge(id)
+	observeCall("DeleteTeamChallenge", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetTeamChallenge(id int) (models.TeamChallenge, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetTeamChallenge(id)
+	observeCall("GetTeamChallenge", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListTeamChallenges() ([]models.TeamChallenge, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListTeamChallenges()
+	observeCall("ListTeamChallenges", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) TeamChallengeCompletions(challenge models.TeamChallenge) (map[string][]int, error) {
+	started := time.Now()
+	r0, r1 := s.next.TeamChallengeCompletions(challenge)
+	observeCall("TeamChallengeCompletions", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCourseRecertification(courseID int) (models.CourseRecertification, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCourseRecertification(courseID)
+	observeCall("GetCourseRecertification", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetCourseRecertification(rec models.CourseRecertification) (models.CourseRecertification, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetCourseRecertification(rec)
+	observeCall("SetCourseRecertification", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) DeleteCourseRecertification(courseID int) error {
+	started := time.Now()
+	r0 := s.next.DeleteCourseRecertification(courseID)
+	observeCall("DeleteCourseRecertification", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) RecordCourseCompletion(userID int, courseID int, at time.Time) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.RecordCourseCompletion(userID, courseID, at)
+	observeCall("RecordCourseCompletion", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListRecertificationReminders(now time.Time) ([]models.CourseCompletion, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListRecertificationReminders(now)
+	observeCall("ListRecertificationReminders", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) MarkRecertificationReminded(userID int, courseID int, at time.Time) error {
+	started := time.Now()
+	r0 := s.next.MarkRecertificationReminded(userID, courseID, at)
+	observeCall("MarkRecertificationReminded", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListExpiredCourseCompletions(now time.Time) ([]models.CourseCompletion, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListExpiredCourseCompletions(now)
+	observeCall("ListExpiredCourseCompletions", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ExpireCourseCompletion(userID int, courseID int, at time.Time) error {
+	started := time.Now()
+	r0 := s.next.ExpireCourseCompletion(userID, courseID, at)
+	observeCall("ExpireCourseCompletion", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ComplianceReport(filter models.ComplianceFilter) ([]models.ComplianceEntry, error) {
+	started := time.Now()
+	r0, r1 := s.next.ComplianceReport(filter)
+	observeCall("ComplianceReport", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) EachComplianceEntry(filter models.ComplianceFilter, fn func(models.ComplianceEntry) error) error {
+	rows := 0
+	counted := func(row models.ComplianceEntry) error {
+		rows++
+		return fn(row)
+	}
+	started := time.Now()
+	r0 := s.next.EachComplianceEntry(filter, counted)
+	observeCall("EachComplianceEntry", started, rows, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) SetUserManager(userID int, managerID *int) error {
+	started := time.Now()
+	r0 := s.next.SetUserManager(userID, managerID)
+	observeCall("SetUserManager", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) IsManagerOf(managerID int, userID int) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.IsManagerOf(managerID, userID)
+	observeCall("IsManagerOf", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListManagerReports(managerID int) ([]models.TeamMember, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListManagerReports(managerID)
+	observeCall("ListManagerReports", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetCourseProgressSummaries(userID int) ([]models.CourseProgressSummary, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetCourseProgressSummaries(userID)
+	observeCall("GetCourseProgressSummaries", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListOutstandingDeadlines(userID int, until time.Time) ([]models.TeamDeadline, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListOutstandingDeadlines(userID, until)
+	observeCall("ListOutstandingDeadlines", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ResetCourseProgress(userID int, courseID int, resetBy *int, reason string) (models.CourseAttempt, error) {
+	started := time.Now()
+	r0, r1 := s.next.ResetCourseProgress(userID, courseID, resetBy, reason)
+	observeCall("ResetCourseProgress", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListCourseAttempts(userID int, courseID int) ([]models.CourseAttempt, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListCourseAttempts(userID, courseID)
+	observeCall("ListCourseAttempts", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListOrganizations() ([]models.Organization, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListOrganizations()
+	observeCall("ListOrganizations", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetOrganization(id int) (models.Organization, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetOrganization(id)
+	observeCall("GetOrganization", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateOrganization(org models.Organization) (models.Organization, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateOrganization(org)
+	observeCall("CreateOrganization", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpdateOrganization(org models.Organization) (models.Organization, error) {
+	started := time.Now()
+	r0, r1 := s.next.UpdateOrganization(org)
+	observeCall("UpdateOrganization", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetUserOrganization(userID int, orgID int) error {
+	started := time.Now()
+	r0 := s.next.SetUserOrganization(userID, orgID)
+	observeCall("SetUserOrganization", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) SetCourseOrganization(courseID int, orgID int) error {
+	started := time.Now()
+	r0 := s.next.SetCourseOrganization(courseID, orgID)
+	observeCall("SetCourseOrganization", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListOrganizationMembers(orgID int) ([]models.OrganizationMember, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListOrganizationMembers(orgID)
+	observeCall("ListOrganizationMembers", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) IsOrganizationMember(orgID int, userID int) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.IsOrganizationMember(orgID, userID)
+	observeCall("IsOrganizationMember", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) IsOrganizationAdmin(orgID int, userID int) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.IsOrganizationAdmin(orgID, userID)
+	observeCall("IsOrganizationAdmin", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetOrganizationAdmin(orgID int, userID int, isAdmin bool) error {
+	started := time.Now()
+	r0 := s.next.SetOrganizationAdmin(orgID, userID, isAdmin)
+	observeCall("SetOrganizationAdmin", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetOrganizationBranding(orgID int) (models.OrganizationBranding, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetOrganizationBranding(orgID)
+	observeCall("GetOrganizationBranding", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetUserBranding(userID int) (models.OrganizationBranding, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetUserBranding(userID)
+	observeCall("GetUserBranding", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetEmailBranding(email string) (models.OrganizationBranding, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetEmailBranding(email)
+	observeCall("GetEmailBranding", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SaveOrganizationBranding(branding models.OrganizationBranding) (models.OrganizationBranding, error) {
+	started := time.Now()
+	r0, r1 := s.next.SaveOrganizationBranding(branding)
+	observeCall("SaveOrganizationBranding", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetOrganizationLogo(orgID int) ([]byte, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetOrganizationLogo(orgID)
+	observeCall("GetOrganizationLogo", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetOrganizationLogo(orgID int, logo []byte) (models.OrganizationBranding, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetOrganizationLogo(orgID, logo)
+	observeCall("SetOrganizationLogo", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListIPAllowlists() ([]models.IPAllowlist, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListIPAllowlists()
+	observeCall("ListIPAllowlists", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetIPAllowlist(orgID int) (models.IPAllowlist, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetIPAllowlist(orgID)
+	observeCall("GetIPAllowlist", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetIPAllowlist(orgID int, cidrs []string, updatedBy int) (models.IPAllowlist, error) {
+	started := time.Now()
+	r0, r1 := s.next.SetIPAllowlist(orgID, cidrs, updatedBy)
+	observeCall("SetIPAllowlist", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ApplyRetention(policy models.RetentionPolicy, cutoff time.Time, dryRun bool) (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.ApplyRetention(policy, cutoff, dryRun)
+	observeCall("ApplyRetention", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) AcquireJobLease(name string, holder string, ttl time.Duration) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.AcquireJobLease(name, holder, ttl)
+	observeCall("AcquireJobLease", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreateInvitation(invitation models.Invitation) (models.Invitation, error) {
+	started := time.Now()
+	r0, r1 := s.next.CreateInvitation(invitation)
+	observeCall("CreateInvitation", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListInvitations(orgID int) ([]models.Invitation, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListInvitations(orgID)
+	observeCall("ListInvitations", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetInvitation(tokenHash string) (models.Invitation, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetInvitation(tokenHash)
+	observeCall("GetInvitation", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) RevokeInvitation(id int, orgID int) error {
+	started := time.Now()
+	r0 := s.next.RevokeInvitation(id, orgID)
+	observeCall("RevokeInvitation", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) RegisterWithInvitation(tokenHash string, user models.User) (int, error) {
+	started := time.Now()
+	r0, r1 := s.next.RegisterWithInvitation(tokenHash, user)
+	observeCall("RegisterWithInvitation", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListCourseAssignments(userID int) ([]models.CourseAssignment, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListCourseAssignments(userID)
+	observeCall("ListCourseAssignments", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) CreatePendingUser(user models.User, requestedIP string) error {
+	started := time.Now()
+	r0 := s.next.CreatePendingUser(user, requestedIP)
+	observeCall("CreatePendingUser", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListPendingRegistrations() ([]models.PendingRegistration, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListPendingRegistrations()
+	observeCall("ListPendingRegistrations", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) IsRegistrationPending(userID int) (bool, error) {
+	started := time.Now()
+	r0, r1 := s.next.IsRegistrationPending(userID)
+	observeCall("IsRegistrationPending", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ApproveRegistration(userID int) (models.PendingRegistration, error) {
+	started := time.Now()
+	r0, r1 := s.next.ApproveRegistration(userID)
+	observeCall("ApproveRegistration", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) RejectRegistration(userID int) (models.PendingRegistration, error) {
+	started := time.Now()
+	r0, r1 := s.next.RejectRegistration(userID)
+	observeCall("RejectRegistration", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) ListAccessExpiries() ([]models.AccessExpiry, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListAccessExpiries()
+	observeCall("ListAccessExpiries", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SetAccessExpiry(userID int, expiresAt *time.Time) error {
+	started := time.Now()
+	r0 := s.next.SetAccessExpiry(userID, expiresAt)
+	observeCall("SetAccessExpiry", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ExtendAccessExpiry(userIDs []int, until *time.Time, days int) ([]models.AccessExpiry, []int, error) {
+	started := time.Now()
+	r0, r1, r2 := s.next.ExtendAccessExpiry(userIDs, until, days)
+	observeCall("ExtendAccessExpiry", started, len(r0), r2)
+	return r0, r1, r2
+}
+
+func (s *InstrumentedStorage) DeactivateExpiredAccounts(now time.Time) ([]models.AccessExpiry, error) {
+	started := time.Now()
+	r0, r1 := s.next.DeactivateExpiredAccounts(now)
+	observeCall("DeactivateExpiredAccounts", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) BulkUpdateUsers(req models.BulkUserRequest, actorID int) ([]models.BulkUserResult, error) {
+	started := time.Now()
+	r0, r1 := s.next.BulkUpdateUsers(req, actorID)
+	observeCall("BulkUpdateUsers", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) RecordAuditEntry(entry models.AuditEntry) error {
+	started := time.Now()
+	r0 := s.next.RecordAuditEntry(entry)
+	observeCall("RecordAuditEntry", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) ListAuditEntries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	started := time.Now()
+	r0, r1 := s.next.ListAuditEntries(filter)
+	observeCall("ListAuditEntries", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) AuditSnapshot(targetType string, targetID int) (map[string]interface{}, error) {
+	started := time.Now()
+	r0, r1 := s.next.AuditSnapshot(targetType, targetID)
+	observeCall("AuditSnapshot", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) GetFeatureFlags() ([]models.FeatureFlag, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetFeatureFlags()
+	observeCall("GetFeatureFlags", started, len(r0), r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) UpsertFeatureFlag(flag models.FeatureFlag) error {
+	started := time.Now()
+	r0 := s.next.UpsertFeatureFlag(flag)
+	observeCall("UpsertFeatureFlag", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) DeleteFeatureFlag(key string) error {
+	started := time.Now()
+	r0 := s.next.DeleteFeatureFlag(key)
+	observeCall("DeleteFeatureFlag", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) GetIdempotentResponse(scope string, key string) (models.IdempotentResponse, error) {
+	started := time.Now()
+	r0, r1 := s.next.GetIdempotentResponse(scope, key)
+	observeCall("GetIdempotentResponse", started, -1, r1)
+	return r0, r1
+}
+
+func (s *InstrumentedStorage) SaveIdempotentResponse(resp models.IdempotentResponse) error {
+	started := time.Now()
+	r0 := s.next.SaveIdempotentResponse(resp)
+	observeCall("SaveIdempotentResponse", started, -1, r0)
+	return r0
+}
+
+func (s *InstrumentedStorage) PurgeIdempotencyKeys(before time.Time) (int64, error) {
+	started := time.Now()
+	r0, r1 := s.next.PurgeIdempotencyKeys(before)
+	observeCall("PurgeIdempotencyKeys", started, -1, r1)
+	return r0, r1
+}
@@ -0,0 +1,272 @@
+// Команда instrumentgen генерирует декораторы хранилища по интерфейсу Storage:
+// InstrumentedStorage (instrumented_gen.go) и FaultyStorage (faults_gen.go). Запускается
+// через go generate в каталоге storage после изменения интерфейса.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/printer"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const source = "storage.go"
+
+// method — разобранный метод интерфейса
+type method struct {
+	name        string
+	params      []string // "имя тип"
+	args        []string // аргументы вызова следующего хранилища
+	results     []string // типы результатов
+	rowCallback string   // параметр func(T) error потокового метода и тип T
+	rowType     string
+	rowsResult  int // индекс результата-среза или отображения; -1 — нет
+	errResult   bool
+}
+
+func main() {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, source, nil, 0)
+	if err != nil {
+		log.Fatalf("parse %s: %v", source, err)
+	}
+	iface := findInterface(file, "Storage")
+	if iface == nil {
+		log.Fatalf("%s: interface Storage not found", source)
+	}
+
+	imports := map[string]string{}
+	for _, spec := range file.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		name := path[strings.LastIndex(path, "/")+1:]
+		if spec.Name != nil {
+			name = spec.Name.Name
+		}
+		imports[name] = spec.Path.Value
+	}
+	used := map[string]bool{"time": true}
+
+	var methods []method
+	for _, field := range iface.Methods.List {
+		fn, ok := field.Type.(*ast.FuncType)
+		if !ok || len(field.Names) == 0 {
+			log.Fatalf("%s: only plain methods are supported in Storage", fset.Position(field.Pos()))
+		}
+		ast.Inspect(fn, func(n ast.Node) bool {
+			if sel, ok := n.(*ast.SelectorExpr); ok {
+				if pkg, ok := sel.X.(*ast.Ident); ok {
+					used[pkg.Name] = true
+				}
+			}
+			return true
+		})
+		methods = append(methods, parseMethod(fset, field.Names[0].Name, fn))
+	}
+
+	var instrumented, faults bytes.Buffer
+	faults.WriteString("\n// storageMethods — имена методов Storage\nvar storageMethods = map[string]bool{\n")
+	for _, m := range methods {
+		fmt.Fprintf(&faults, "\t%q: true,\n", m.name)
+	}
+	faults.WriteString("}\n")
+	for _, m := range methods {
+		writeInstrumented(&instrumented, m)
+		writeFaulty(&faults, m)
+	}
+	write("instrumented_gen.go", imports, used, instrumented.Bytes())
+	write("faults_gen.go", imports, used, faults.Bytes())
+}
+
+// write форматирует и записывает сгенерированный файл; из imports берутся пакеты,
+// которые встречаются в методах (used)
+func write(target string, imports map[string]string, used map[string]bool, body []byte) {
+	var out bytes.Buffer
+	out.WriteString("// Code generated by instrumentgen from storage.go; DO NOT EDIT.\n\npackage storage\n\nimport (\n")
+	var paths []string
+	for name := range used {
+		path, ok := imports[name]
+		if !ok {
+			path = strconv.Quote(name)
+		}
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		out.WriteString("\t" + path + "\n")
+	}
+	out.WriteString(")\n")
+	out.Write(body)
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		log.Fatalf("format generated code: %v", err)
+	}
+	if err := os.WriteFile(target, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func findInterface(file *ast.File, name string) *ast.InterfaceType {
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			ts := spec.(*ast.TypeSpec)
+			if it, ok := ts.Type.(*ast.InterfaceType); ok && ts.Name.Name == name {
+				return it
+			}
+		}
+	}
+	return nil
+}
+
+// parseMethod разбирает сигнатуру метода. Строки считаются по первому результату-срезу
+// или отображению, а у потоковых методов (EachXxx) — по вызовам переданной функции.
+func parseMethod(fset *token.FileSet, name string, fn *ast.FuncType) method {
+	expr := func(e ast.Expr) string {
+		var b bytes.Buffer
+		printer.Fprint(&b, fset, e)
+		return b.String()
+	}
+
+	m := method{name: name, rowsResult: -1}
+	for i, field := range fn.Params.List {
+		names := field.Names
+		if len(names) == 0 {
+			names = []*ast.Ident{ast.NewIdent(fmt.Sprintf("p%d", i))}
+		}
+		for _, ident := range names {
+			m.params = append(m.params, ident.Name+" "+expr(field.Type))
+			arg := ident.Name
+			if _, variadic := field.Type.(*ast.Ellipsis); variadic {
+				arg += "..."
+			}
+			if callback, ok := field.Type.(*ast.FuncType); ok && m.rowCallback == "" && isRowCallback(callback) {
+				m.rowCallback, m.rowType = ident.Name, expr(callback.Params.List[0].Type)
+			}
+			m.args = append(m.args, arg)
+		}
+	}
+	if fn.Results != nil {
+		for _, field := range fn.Results.List {
+			for range max(len(field.Names), 1) {
+				switch t := field.Type.(type) {
+				case *ast.ArrayType, *ast.MapType:
+					if m.rowsResult < 0 {
+						m.rowsResult = len(m.results)
+					}
+				case *ast.Ident:
+					m.errResult = t.Name == "error"
+				}
+				m.results = append(m.results, expr(field.Type))
+			}
+		}
+	}
+	// Ошибкой считается только последний результат
+	if n := len(m.results); n > 0 && m.results[n-1] != "error" {
+		m.errResult = false
+	}
+	return m
+}
+
+// names возвращает имена результатов r0, r1...
+func (m method) names() []string {
+	names := make([]string, len(m.results))
+	for i := range names {
+		names[i] = fmt.Sprintf("r%d", i)
+	}
+	return names
+}
+
+func (m method) signature(receiver string, named bool) string {
+	sig := fmt.Sprintf("func (s *%s) %s(%s)", receiver, m.name, strings.Join(m.params, ", "))
+	if len(m.results) == 0 {
+		return sig
+	}
+	results := m.results
+	if named {
+		results = make([]string, len(m.results))
+		for i, name := range m.names() {
+			results[i] = name + " " + m.results[i]
+		}
+	}
+	return sig + " (" + strings.Join(results, ", ") + ")"
+}
+
+func writeInstrumented(w *bytes.Buffer, m method) {
+	args := m.args
+	rows, errResult := "-1", "nil"
+	if m.rowCallback != "" {
+		fmt.Fprintf(w, "\n%s {\n\trows := 0\n\tcounted := func(row %s) error {\n\t\trows++\n\t\treturn %s(row)\n\t}\n",
+			m.signature("InstrumentedStorage", false), m.rowType, m.rowCallback)
+		args = make([]string, len(m.args))
+		for i, arg := range m.args {
+			if arg == m.rowCallback {
+				arg = "counted"
+			}
+			args[i] = arg
+		}
+		rows = "rows"
+	} else {
+		fmt.Fprintf(w, "\n%s {\n", m.signature("InstrumentedStorage", false))
+	}
+	names := m.names()
+	if m.rowsResult >= 0 && rows == "-1" {
+		rows = "len(" + names[m.rowsResult] + ")"
+	}
+	if m.errResult {
+		errResult = names[len(names)-1]
+	}
+	call := "s.next." + m.name + "(" + strings.Join(args, ", ") + ")"
+
+	w.WriteString("\tstarted := time.Now()\n")
+	if len(names) > 0 {
+		fmt.Fprintf(w, "\t%s := %s\n", strings.Join(names, ", "), call)
+	} else {
+		fmt.Fprintf(w, "\t%s\n", call)
+	}
+	fmt.Fprintf(w, "\tobserveCall(%q, started, %s, %s)\n", m.name, rows, errResult)
+	if len(names) > 0 {
+		fmt.Fprintf(w, "\treturn %s\n", strings.Join(names, ", "))
+	}
+	w.WriteString("}\n")
+}
+
+// writeFaulty пишет метод FaultyStorage: методы без ошибки в результате получают только задержку
+func writeFaulty(w *bytes.Buffer, m method) {
+	call := "s.next." + m.name + "(" + strings.Join(m.args, ", ") + ")"
+	if !m.errResult {
+		fmt.Fprintf(w, "\n%s {\n\ts.delay(%q)\n", m.signature("FaultyStorage", false), m.name)
+		if len(m.results) > 0 {
+			fmt.Fprintf(w, "\treturn %s\n}\n", call)
+		} else {
+			fmt.Fprintf(w, "\t%s\n}\n", call)
+		}
+		return
+	}
+	errName := m.names()[len(m.results)-1]
+	fmt.Fprintf(w, "\n%s {\n\tif %s = s.inject(%q); %s != nil {\n\t\treturn\n\t}\n\treturn %s\n}\n",
+		m.signature("FaultyStorage", true), errName, m.name, errName, call)
+}
+
+// isRowCallback распознает функции вида func(T) error, которым потоковые методы передают строки
+func isRowCallback(fn *ast.FuncType) bool {
+	if fn.Params == nil || len(fn.Params.List) != 1 || len(fn.Params.List[0].Names) > 1 {
+		return false
+	}
+	if fn.Results == nil || len(fn.Results.List) != 1 {
+		return false
+	}
+	ident, ok := fn.Results.List[0].Type.(*ast.Ident)
+	return ok && ident.Name == "error"
+}
@@ -0,0 +1,314 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrInvitationNotFound — приглашения с такой ссылкой или ID нет
+	ErrInvitationNotFound = errors.New("invitation not found")
+	// ErrInvitationExpired — приглашение отозвано, истекло или по нему зарегистрировалось максимальное число пользователей
+	ErrInvitationExpired = errors.New("invitation is no longer valid")
+)
+
+const invitationColumns = "i.id, i.organization_id, o.name, i.group_name, i.max_uses, i.uses, i.token_hash, " +
+	"i.expires_at, i.created_by, i.created_at, i.revoked_at"
+
+const invitationFrom = " FROM invitations i JOIN organizations o ON o.id = i.organization_id"
+
+func scanInvitation(row rowScanner) (models.Invitation, error) {
+	var invitation models.Invitation
+	var createdBy sql.NullInt64
+	var revokedAt sql.NullTime
+	err := row.Scan(&invitation.ID, &invitation.OrganizationID, &invitation.OrganizationName, &invitation.Group,
+		&invitation.MaxUses, &invitation.Uses, &invitation.TokenHash, &invitation.ExpiresAt, &createdBy,
+		&invitation.CreatedAt, &revokedAt)
+	if err != nil {
+		return invitation, err
+	}
+	if createdBy.Valid {
+		id := int(createdBy.Int64)
+		invitation.CreatedBy = &id
+	}
+	if revokedAt.Valid {
+		invitation.RevokedAt = &revokedAt.Time
+	}
+	invitation.CourseIDs = []int{}
+	return invitation, nil
+}
+
+// invitationUsable возвращает ErrInvitationExpired для отозванного, истекшего или исчерпанного приглашения
+func invitationUsable(invitation models.Invitation, now time.Time) error {
+	if invitation.RevokedAt != nil || !invitation.ExpiresAt.After(now) ||
+		(invitation.MaxUses > 0 && invitation.Uses >= invitation.MaxUses) {
+		return ErrInvitationExpired
+	}
+	return nil
+}
+
+// loadInvitationCourses заполняет курсы приглашения
+func loadInvitationCourses(ctx context.Context, q rowsQueryer, invitation *models.Invitation) error {
+	rows, err := q.QueryContext(ctx,
+		"SELECT course_id FROM invitation_courses WHERE invitation_id = ? ORDER BY course_id", invitation.ID)
+	if err != nil {
+		return fmt.Errorf("query invitation courses: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var courseID int
+		if err := rows.Scan(&courseID); err != nil {
+			return fmt.Errorf("scan invitation course: %w", err)
+		}
+		invitation.CourseIDs = append(invitation.CourseIDs, courseID)
+	}
+	return rows.Err()
+}
+
+// CreateInvitation сохраняет приглашение вместе с назначаемыми курсами; CourseIDs не должны повторяться
+func (s *DBStorage) CreateInvitation(invitation models.Invitation) (models.Invitation, error) {
+	ctx, done := s.startQuery("CreateInvitation")
+	defer done()
+
+	var id int64
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if found, err := rowExists(ctx, tx, "organizations", invitation.OrganizationID); err != nil || !found {
+			if err == nil {
+				err = ErrOrganizationNotFound
+			}
+			return err
+		}
+		if invitation.Group != "" {
+			if err := groupExists(ctx, tx, invitation.Group, invitation.OrganizationID); err != nil {
+				return err
+			}
+		}
+		for _, courseID := range invitation.CourseIDs {
+			var archived bool
+			err := tx.QueryRowContext(ctx, "SELECT archived_at IS NOT NULL FROM courses WHERE id = ? AND organization_id = ?",
+				courseID, invitation.OrganizationID).Scan(&archived)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrCourseNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("check course: %w", err)
+			}
+			if archived {
+				return ErrCourseArchived
+			}
+		}
+
+		res, err := tx.ExecContext(ctx, `
+			INSERT INTO invitations (token_hash, organization_id, group_name, max_uses, expires_at, created_by, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			invitation.TokenHash, invitation.OrganizationID, invitation.Group, invitation.MaxUses,
+			invitation.ExpiresAt.UTC(), invitation.CreatedBy, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("insert invitation: %w", err)
+		}
+		if id, err = res.LastInsertId(); err != nil {
+			return err
+		}
+		for _, courseID := range invitation.CourseIDs {
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO invitation_courses (invitation_id, course_id) VALUES (?, ?)",
+				id, courseID); err != nil {
+				return fmt.Errorf("insert invitation course: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return models.Invitation{}, err
+	}
+
+	created, err := scanInvitation(s.DB.QueryRowContext(ctx, "SELECT "+invitationColumns+invitationFrom+" WHERE i.id = ?", id))
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("get invitation: %w", err)
+	}
+	return created, loadInvitationCourses(ctx, s.DB, &created)
+}
+
+// ListInvitations возвращает приглашения организации, новые первыми
+func (s *DBStorage) ListInvitations(orgID int) ([]models.Invitation, error) {
+	ctx, done := s.startQuery("ListInvitations")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT "+invitationColumns+invitationFrom+" WHERE ? = 0 OR i.organization_id = ? ORDER BY i.created_at DESC, i.id DESC",
+		orgID, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("query invitations: %w", err)
+	}
+	invitations := []models.Invitation{}
+	for rows.Next() {
+		invitation, err := scanInvitation(rows)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan invitation: %w", err)
+		}
+		invitations = append(invitations, invitation)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate invitations: %w", err)
+	}
+
+	for i := range invitations {
+		if err := loadInvitationCourses(ctx, s.reader(), &invitations[i]); err != nil {
+			return nil, err
+		}
+	}
+	return invitations, nil
+}
+
+// GetInvitation возвращает действующее приглашение по хешу токена из ссылки
+func (s *DBStorage) GetInvitation(tokenHash string) (models.Invitation, error) {
+	ctx, done := s.startQuery("GetInvitation")
+	defer done()
+
+	invitation, err := scanInvitation(s.DB.QueryRowContext(ctx,
+		"SELECT "+invitationColumns+invitationFrom+" WHERE i.token_hash = ?", tokenHash))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Invitation{}, ErrInvitationNotFound
+	}
+	if err != nil {
+		return models.Invitation{}, fmt.Errorf("get invitation: %w", err)
+	}
+	if err := invitationUsable(invitation, time.Now()); err != nil {
+		return invitation, err
+	}
+	return invitation, loadInvitationCourses(ctx, s.DB, &invitation)
+}
+
+// RevokeInvitation отзывает приглашение; уже зарегистрированные по нему пользователи остаются
+func (s *DBStorage) RevokeInvitation(id, orgID int) error {
+	ctx, done := s.startQuery("RevokeInvitation")
+	defer done()
+
+	// MySQL не считает затронутой строку, которая не изменилась, поэтому повторный отзыв
+	// различается с отсутствием приглашения отдельным запросом
+	var found int
+	err := s.DB.QueryRowContext(ctx, "SELECT 1 FROM invitations WHERE id = ? AND (? = 0 OR organization_id = ?)",
+		id, orgID, orgID).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvitationNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("check invitation: %w", err)
+	}
+	if _, err := s.DB.ExecContext(ctx, "UPDATE invitations SET revoked_at = ? WHERE id = ? AND revoked_at IS NULL",
+		time.Now().UTC(), id); err != nil {
+		return fmt.Errorf("revoke invitation: %w", err)
+	}
+	return nil
+}
+
+// RegisterWithInvitation регистрирует пользователя по приглашению. Использование засчитывается
+// в той же транзакции, что и создание пользователя, поэтому одноразовую ссылку нельзя
+// использовать дважды параллельными запросами.
+func (s *DBStorage) RegisterWithInvitation(tokenHash string, user models.User) (int, error) {
+	ctx, done := s.startQuery("RegisterWithInvitation")
+	defer done()
+
+	var userID int
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		// Условный UPDATE засчитывает использование и одновременно блокирует строку приглашения
+		res, err := tx.ExecContext(ctx, `
+			UPDATE invitations SET uses = uses + 1
+			WHERE token_hash = ? AND revoked_at IS NULL AND expires_at > ? AND (max_uses = 0 OR uses < max_uses)`,
+			tokenHash, now)
+		if err != nil {
+			return fmt.Errorf("use invitation: %w", err)
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			var found int
+			err := tx.QueryRowContext(ctx, "SELECT 1 FROM invitations WHERE token_hash = ?", tokenHash).Scan(&found)
+			if errors.Is(err, sql.ErrNoRows) {
+				return ErrInvitationNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("check invitation: %w", err)
+			}
+			return ErrInvitationExpired
+		}
+
+		invitation, err := scanInvitation(tx.QueryRowContext(ctx,
+			"SELECT "+invitationColumns+invitationFrom+" WHERE i.token_hash = ?", tokenHash))
+		if err != nil {
+			return fmt.Errorf("get invitation: %w", err)
+		}
+		if userID, err = insertUser(ctx, tx, user, invitation.OrganizationID, true); err != nil {
+			return err
+		}
+
+		if invitation.Group != "" {
+			// Группу могли удалить после создания приглашения: регистрация от этого не ломается
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO group_members (group_id, user_id)
+				SELECT id, ? FROM user_groups WHERE name = ? AND organization_id = ?`,
+				userID, invitation.Group, invitation.OrganizationID); err != nil {
+				return fmt.Errorf("add group member: %w", err)
+			}
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO course_assignments (user_id, course_id, invitation_id, assigned_at)
+			SELECT ?, ic.course_id, ic.invitation_id, ?
+			FROM invitation_courses ic
+			JOIN courses c ON c.id = ic.course_id
+			WHERE ic.invitation_id = ? AND c.organization_id = ? AND c.archived_at IS NULL`,
+			userID, now, invitation.ID, invitation.OrganizationID); err != nil {
+			return fmt.Errorf("assign courses: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrInvitationNotFound), errors.Is(err, ErrInvitationExpired),
+			errors.Is(err, ErrDuplicateEmail), errors.Is(err, ErrDuplicateUsername):
+			return 0, err
+		}
+		return 0, fmt.Errorf("register with invitation: %w", err)
+	}
+	return userID, nil
+}
+
+// ListCourseAssignments возвращает курсы, назначенные пользователю, в порядке назначения
+func (s *DBStorage) ListCourseAssignments(userID int) ([]models.CourseAssignment, error) {
+	ctx, done := s.startQuery("ListCourseAssignments")
+	defer done()
+
+	orgSQL, orgArgs := s.orgFilter("AND", "c.organization_id")
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT c.id, c.vulnerability_type, a.assigned_at, a.invitation_id
+		FROM course_assignments a
+		JOIN courses c ON c.id = a.course_id
+		WHERE a.user_id = ? `+orgSQL+`
+		ORDER BY a.assigned_at, c.id`, append([]any{userID}, orgArgs...)...)
+	if err != nil {
+		return nil, fmt.Errorf("query course assignments: %w", err)
+	}
+	defer rows.Close()
+
+	assignments := []models.CourseAssignment{}
+	for rows.Next() {
+		var assignment models.CourseAssignment
+		var invitationID sql.NullInt64
+		if err := rows.Scan(&assignment.CourseID, &assignment.CourseTitle, &assignment.AssignedAt, &invitationID); err != nil {
+			return nil, fmt.Errorf("scan course assignment: %w", err)
+		}
+		if invitationID.Valid {
+			id := int(invitationID.Int64)
+			assignment.InvitationID = &id
+		}
+		assignments = append(assignments, assignment)
+	}
+	return assignments, rows.Err()
+}
@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+	"time"
+)
+
+// scanIPAllowlist читает список разрешенных адресов; cidrs хранятся через запятую
+func scanIPAllowlist(row rowScanner) (models.IPAllowlist, error) {
+	var list models.IPAllowlist
+	var cidrs string
+	var updatedBy sql.NullInt64
+	var updatedAt time.Time
+	if err := row.Scan(&list.OrganizationID, &cidrs, &updatedBy, &updatedAt); err != nil {
+		return list, err
+	}
+	list.CIDRs = strings.Split(cidrs, ",")
+	if updatedBy.Valid {
+		id := int(updatedBy.Int64)
+		list.UpdatedBy = &id
+	}
+	list.UpdatedAt = &updatedAt
+	return list, nil
+}
+
+// ListIPAllowlists возвращает списки разрешенных адресов всех организаций, у которых они заданы
+func (s *DBStorage) ListIPAllowlists() ([]models.IPAllowlist, error) {
+	ctx, done := s.startQuery("ListIPAllowlists")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT organization_id, cidrs, updated_by, updated_at FROM organization_ip_allowlist ORDER BY organization_id")
+	if err != nil {
+		return nil, fmt.Errorf("list ip allowlists: %w", err)
+	}
+	defer rows.Close()
+
+	lists := []models.IPAllowlist{}
+	for rows.Next() {
+		list, err := scanIPAllowlist(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan ip allowlist: %w", err)
+		}
+		lists = append(lists, list)
+	}
+	return lists, rows.Err()
+}
+
+// GetIPAllowlist возвращает список разрешенных адресов организации; пустой, если он не задан
+func (s *DBStorage) GetIPAllowlist(orgID int) (models.IPAllowlist, error) {
+	ctx, done := s.startQuery("GetIPAllowlist")
+	defer done()
+	return getIPAllowlist(ctx, s.DB, orgID)
+}
+
+func getIPAllowlist(ctx context.Context, q queryer, orgID int) (models.IPAllowlist, error) {
+	list, err := scanIPAllowlist(q.QueryRowContext(ctx,
+		"SELECT organization_id, cidrs, updated_by, updated_at FROM organization_ip_allowlist WHERE organization_id = ?", orgID))
+	if errors.Is(err, sql.ErrNoRows) {
+		found, err := rowExists(ctx, q, "organizations", orgID)
+		if err != nil {
+			return list, fmt.Errorf("get ip allowlist: %w", err)
+		}
+		if !found {
+			return list, ErrOrganizationNotFound
+		}
+		return models.IPAllowlist{OrganizationID: orgID, CIDRs: []string{}}, nil
+	}
+	if err != nil {
+		return list, fmt.Errorf("get ip allowlist: %w", err)
+	}
+	return list, nil
+}
+
+// SetIPAllowlist заменяет список разрешенных адресов организации; пустой список снимает ограничение.
+// Записи должны быть уже проверены и приведены к нотации CIDR.
+func (s *DBStorage) SetIPAllowlist(orgID int, cidrs []string, updatedBy int) (models.IPAllowlist, error) {
+	ctx, done := s.startQuery("SetIPAllowlist")
+	defer done()
+
+	var list models.IPAllowlist
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if found, err := rowExists(ctx, tx, "organizations", orgID); err != nil || !found {
+			if err == nil {
+				err = ErrOrganizationNotFound
+			}
+			return err
+		}
+		if len(cidrs) == 0 {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM organization_ip_allowlist WHERE organization_id = ?", orgID); err != nil {
+				return fmt.Errorf("delete ip allowlist: %w", err)
+			}
+		} else {
+			_, err := tx.ExecContext(ctx,
+				"INSERT INTO organization_ip_allowlist (organization_id, cidrs, updated_by, updated_at) VALUES (?, ?, ?, ?)"+
+					s.onConflictUpdate([]string{"organization_id"}, "cidrs", "updated_by", "updated_at"),
+				orgID, strings.Join(cidrs, ","), updatedBy, time.Now().UTC())
+			if err != nil {
+				return fmt.Errorf("save ip allowlist: %w", err)
+			}
+		}
+		var err error
+		list, err = getIPAllowlist(ctx, tx, orgID)
+		return err
+	})
+	return list, err
+}
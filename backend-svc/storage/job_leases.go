@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// AcquireJobLease выдает аренду задачи name держателю holder на ttl. Аренда продлевается, если
+// она истекла или уже принадлежит holder; иначе создается. Одновременную вставку двумя
+// репликами разрешает первичный ключ: проигравшая получает false.
+func (s *DBStorage) AcquireJobLease(name, holder string, ttl time.Duration) (bool, error) {
+	ctx, done := s.startQuery("AcquireJobLease")
+	defer done()
+
+	now := time.Now().UTC()
+	res, err := s.DB.ExecContext(ctx,
+		"UPDATE job_leases SET holder = ?, expires_at = ?, acquired_at = ?, version = version + 1 "+
+			"WHERE name = ? AND (holder = ? OR expires_at <= ?)",
+		holder, now.Add(ttl), now, name, holder, now)
+	if err != nil {
+		return false, fmt.Errorf("renew job lease: %w", err)
+	}
+	if n, err := res.RowsAffected(); err != nil || n > 0 {
+		return n > 0, err
+	}
+
+	_, err = s.DB.ExecContext(ctx,
+		"INSERT INTO job_leases (name, holder, expires_at, acquired_at) VALUES (?, ?, ?, ?)",
+		name, holder, now.Add(ttl), now)
+	if isUniqueViolation(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("insert job lease: %w", err)
+	}
+	return true, nil
+}
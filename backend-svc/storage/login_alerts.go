@@ -0,0 +1,154 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrLockTokenInvalid возвращается для неизвестных, использованных и просроченных токенов блокировки
+var ErrLockTokenInvalid = errors.New("account lock token is invalid or expired")
+
+// RecordLoginContext запоминает контекст входа и сообщает, встречался ли он раньше
+func (s *DBStorage) RecordLoginContext(login models.LoginContext) (bool, error) {
+	ctx, done := s.startQuery("RecordLoginContext")
+	defer done()
+
+	isNew := false
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		res, err := tx.ExecContext(ctx,
+			"UPDATE login_contexts SET ip = ?, user_agent = ?, last_seen_at = ? WHERE user_id = ? AND fingerprint = ?",
+			login.IP, login.UserAgent, now, login.UserID, login.Fingerprint)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return err
+		} else if affected > 0 {
+			return nil
+		}
+
+		var known int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM login_contexts WHERE user_id = ?", login.UserID).Scan(&known); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO login_contexts (user_id, fingerprint, ip, user_agent, location, first_seen_at, last_seen_at) "+
+				"VALUES (?, ?, ?, ?, ?, ?, ?)",
+			login.UserID, login.Fingerprint, login.IP, login.UserAgent, login.Location, now, now); err != nil {
+			return err
+		}
+		isNew = known > 0
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("record login context: %w", err)
+	}
+	return isNew, nil
+}
+
+// ListLoginContexts возвращает известные устройства и местоположения пользователя, последние первыми
+func (s *DBStorage) ListLoginContexts(userID int) ([]models.LoginContext, error) {
+	ctx, done := s.startQuery("ListLoginContexts")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT user_id, fingerprint, ip, user_agent, location, first_seen_at, last_seen_at "+
+			"FROM login_contexts WHERE user_id = ? ORDER BY last_seen_at DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	logins := []models.LoginContext{}
+	for rows.Next() {
+		var login models.LoginContext
+		if err := rows.Scan(&login.UserID, &login.Fingerprint, &login.IP, &login.UserAgent, &login.Location,
+			&login.FirstSeenAt, &login.LastSeenAt); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		logins = append(logins, login)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return logins, nil
+}
+
+// CreateAccountLockToken сохраняет хеш токена для ссылки «это был не я»
+func (s *DBStorage) CreateAccountLockToken(userID int, tokenHash string, expiresAt time.Time) error {
+	ctx, done := s.startQuery("CreateAccountLockToken")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "INSERT INTO account_lock_tokens (token_hash, user_id, expires_at) VALUES (?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	if _, err := stmt.ExecContext(ctx, tokenHash, userID, expiresAt.UTC()); err != nil {
+		return fmt.Errorf("create account lock token: %w", err)
+	}
+	return nil
+}
+
+// LockAccountByToken деактивирует пользователя по токену из письма о новом входе
+func (s *DBStorage) LockAccountByToken(tokenHash string) (int, error) {
+	ctx, done := s.startQuery("LockAccountByToken")
+	defer done()
+
+	var userID int
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now().UTC()
+		err := tx.QueryRowContext(ctx,
+			"SELECT user_id FROM account_lock_tokens WHERE token_hash = ? AND used_at IS NULL AND expires_at > ?",
+			tokenHash, now).Scan(&userID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrLockTokenInvalid
+		}
+		if err != nil {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx,
+			"UPDATE account_lock_tokens SET used_at = ? WHERE token_hash = ? AND used_at IS NULL", now, tokenHash)
+		if err != nil {
+			return err
+		}
+		if affected, err := res.RowsAffected(); err != nil {
+			return err
+		} else if affected == 0 {
+			return ErrLockTokenInvalid
+		}
+
+		if err := bumpUserVersion(ctx, tx, userID, AnyVersion); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET is_active = ? WHERE id = ?", false, userID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE trusted_devices SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL", now, userID); err != nil {
+			return err
+		}
+
+		return insertOutboxEvent(ctx, tx, models.EventUserStatusChanged, userID, map[string]interface{}{
+			"userId":   userID,
+			"isActive": false,
+			"reason":   "locked_by_user",
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrLockTokenInvalid) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("lock account: %w", err)
+	}
+	return userID, nil
+}
@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrManagerCycle — назначение сделало бы пользователя руководителем самого себя
+var ErrManagerCycle = errors.New("manager cycle")
+
+// SetUserManager назначает пользователю руководителя; nil снимает руководителя. Цепочка
+// руководителей не может замыкаться на самого пользователя.
+func (s *DBStorage) SetUserManager(userID int, managerID *int) error {
+	ctx, done := s.startQuery("SetUserManager")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if found, err := rowExists(ctx, tx, "users", userID); err != nil || !found {
+			if err == nil {
+				err = ErrUserNotFound
+			}
+			return err
+		}
+		if managerID == nil {
+			if _, err := tx.ExecContext(ctx, "DELETE FROM user_managers WHERE user_id = ?", userID); err != nil {
+				return fmt.Errorf("delete user manager: %w", err)
+			}
+			return nil
+		}
+		if found, err := rowExists(ctx, tx, "users", *managerID); err != nil || !found {
+			if err == nil {
+				err = ErrUserNotFound
+			}
+			return err
+		}
+
+		// Поднимаемся по цепочке руководителей нового руководителя
+		for current, seen := *managerID, map[int]bool{}; !seen[current]; {
+			if current == userID {
+				return ErrManagerCycle
+			}
+			seen[current] = true
+			err := tx.QueryRowContext(ctx, "SELECT manager_id FROM user_managers WHERE user_id = ?", current).Scan(&current)
+			if errors.Is(err, sql.ErrNoRows) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("check manager chain: %w", err)
+			}
+		}
+
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO user_managers (user_id, manager_id, assigned_at) VALUES (?, ?, ?)"+
+				s.onConflictUpdate([]string{"user_id"}, "manager_id", "assigned_at"),
+			userID, *managerID, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("save user manager: %w", err)
+		}
+		return nil
+	})
+}
+
+// IsManagerOf сообщает, является ли managerID непосредственным руководителем userID
+func (s *DBStorage) IsManagerOf(managerID, userID int) (bool, error) {
+	ctx, done := s.startQuery("IsManagerOf")
+	defer done()
+
+	var found int
+	err := s.DB.QueryRowContext(ctx,
+		"SELECT 1 FROM user_managers WHERE user_id = ? AND manager_id = ?", userID, managerID).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check manager: %w", err)
+	}
+	return true, nil
+}
+
+// ListManagerReports возвращает непосредственных подчиненных руководителя без итогов по курсам
+func (s *DBStorage) ListManagerReports(managerID int) ([]models.TeamMember, error) {
+	ctx, done := s.startQuery("ListManagerReports")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT u.id, u.username, u.full_name, u.email, u.is_active
+		FROM user_managers m JOIN users u ON u.id = m.user_id
+		WHERE m.manager_id = ? ORDER BY u.username`, managerID)
+	if err != nil {
+		return nil, fmt.Errorf("query manager reports: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.TeamMember{}
+	for rows.Next() {
+		var member models.TeamMember
+		if err := rows.Scan(&member.UserID, &member.Username, &member.FullName, &member.Email, &member.IsActive); err != nil {
+			return nil, fmt.Errorf("scan manager report: %w", err)
+		}
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// GetCourseProgressSummaries возвращает прогресс пользователя по начатым курсам
+func (s *DBStorage) GetCourseProgressSummaries(userID int) ([]models.CourseProgressSummary, error) {
+	ctx, done := s.startQuery("GetCourseProgressSummaries")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT c.id, c.vulnerability_type, COUNT(*),
+		       (SELECT COUNT(*) FROM tasks ct WHERE ct.course_id = c.id)
+		FROM user_progress p
+		JOIN tasks t ON t.id = p.task_id
+		JOIN courses c ON c.id = t.course_id
+		WHERE p.user_id = ?
+		GROUP BY c.id, c.vulnerability_type
+		ORDER BY c.id`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query course progress: %w", err)
+	}
+	defer rows.Close()
+
+	summaries := []models.CourseProgressSummary{}
+	for rows.Next() {
+		var summary models.CourseProgressSummary
+		if err := rows.Scan(&summary.CourseID, &summary.CourseTitle, &summary.TasksCompleted, &summary.TasksTotal); err != nil {
+			return nil, fmt.Errorf("scan course progress: %w", err)
+		}
+		summary.Percent = progressPoint("", summary.TasksCompleted, summary.TasksTotal).Percent
+		summaries = append(summaries, summary)
+	}
+	return summaries, rows.Err()
+}
+
+// ListOutstandingDeadlines возвращает невыполненные задания начатых или назначенных пользователю курсов
+// со сроком раньше until, по возрастанию срока
+func (s *DBStorage) ListOutstandingDeadlines(userID int, until time.Time) ([]models.TeamDeadline, error) {
+	ctx, done := s.startQuery("ListOutstandingDeadlines")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT t.id, t.title, c.id, c.vulnerability_type, ts.due_at
+		FROM task_schedules ts
+		JOIN tasks t ON t.id = ts.task_id
+		JOIN courses c ON c.id = t.course_id
+		WHERE ts.due_at IS NOT NULL AND ts.due_at < ?
+		  AND (t.course_id IN (SELECT st.course_id FROM user_progress sp JOIN tasks st ON st.id = sp.task_id WHERE sp.user_id = ?)
+		    OR t.course_id IN (SELECT a.course_id FROM course_assignments a WHERE a.user_id = ?))
+		  AND NOT EXISTS (SELECT 1 FROM user_progress p WHERE p.user_id = ? AND p.task_id = t.id)
+		ORDER BY ts.due_at, t.id`, until.UTC(), userID, userID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query outstanding deadlines: %w", err)
+	}
+	defer rows.Close()
+
+	deadlines := []models.TeamDeadline{}
+	for rows.Next() {
+		var deadline models.TeamDeadline
+		if err := rows.Scan(&deadline.TaskID, &deadline.TaskTitle, &deadline.CourseID, &deadline.CourseTitle, &deadline.DueAt); err != nil {
+			return nil, fmt.Errorf("scan outstanding deadline: %w", err)
+		}
+		deadlines = append(deadlines, deadline)
+	}
+	return deadlines, rows.Err()
+}
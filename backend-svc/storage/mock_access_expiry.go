@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+// mockAccessExpiry — сроки доступа: ID пользователя -> срок (данные пользователя не хранятся)
+var mockAccessExpiry = map[int]models.AccessExpiry{}
+
+// mockAccessExpiryView дополняет срок доступа данными пользователя; mockMu должен быть захвачен
+func mockAccessExpiryView(userID int) models.AccessExpiry {
+	expiry := mockAccessExpiry[userID]
+	user := mockUsers[userID]
+	expiry.UserID, expiry.Username, expiry.Email = user.ID, user.Username, user.Email
+	expiry.FullName, expiry.IsActive = user.FullName, user.IsActive
+	return expiry
+}
+
+// mockSetStatus меняет активность пользователя так же, как UpdateUserStatus; mockMu должен быть захвачен
+func mockSetStatus(userID int, isActive bool) {
+	user := mockUsers[userID]
+	user.IsActive = isActive
+	user.Version++
+	mockUsers[userID] = user
+	appendMockEvent(models.EventUserStatusChanged, userID, map[string]interface{}{
+		"userId":   userID,
+		"isActive": isActive,
+	})
+}
+
+// mockSaveAccessExpiry записывает срок доступа и снова активирует учетную запись, отключенную
+// по сроку, если новый срок еще не наступил; mockMu должен быть захвачен
+func mockSaveAccessExpiry(userID int, expiresAt, now time.Time) {
+	expiry := mockAccessExpiry[userID]
+	expiry.ExpiresAt = expiresAt.UTC()
+	reactivate := expiry.DeactivatedAt != nil && expiresAt.After(now)
+	if reactivate {
+		expiry.DeactivatedAt = nil
+	}
+	mockAccessExpiry[userID] = expiry
+	if reactivate {
+		mockSetStatus(userID, true)
+	}
+}
+
+// ListAccessExpiries возвращает сроки доступа из моковых данных
+func (s *MockStorage) ListAccessExpiries() ([]models.AccessExpiry, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return mockAccessExpiriesWhere(func(models.AccessExpiry) bool { return true }), nil
+}
+
+// mockAccessExpiriesWhere возвращает подходящие сроки доступа, ближайшие первыми; mockMu должен быть захвачен
+func mockAccessExpiriesWhere(match func(models.AccessExpiry) bool) []models.AccessExpiry {
+	expiries := []models.AccessExpiry{}
+	for userID := range mockAccessExpiry {
+		if expiry := mockAccessExpiryView(userID); match(expiry) {
+			expiries = append(expiries, expiry)
+		}
+	}
+	sort.Slice(expiries, func(i, j int) bool {
+		if !expiries[i].ExpiresAt.Equal(expiries[j].ExpiresAt) {
+			return expiries[i].ExpiresAt.Before(expiries[j].ExpiresAt)
+		}
+		return expiries[i].UserID < expiries[j].UserID
+	})
+	return expiries
+}
+
+// SetAccessExpiry задает или снимает срок доступа в моковых данных
+func (s *MockStorage) SetAccessExpiry(userID int, expiresAt *time.Time) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockUsers[userID]; !ok {
+		return ErrUserNotFound
+	}
+	if expiresAt == nil {
+		delete(mockAccessExpiry, userID)
+		return nil
+	}
+	mockSaveAccessExpiry(userID, *expiresAt, time.Now())
+	return nil
+}
+
+// ExtendAccessExpiry продлевает сроки доступа в моковых данных
+func (s *MockStorage) ExtendAccessExpiry(userIDs []int, until *time.Time, days int) ([]models.AccessExpiry, []int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	now := time.Now()
+	extended, skipped := []models.AccessExpiry{}, []int{}
+	seen := make(map[int]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if seen[userID] {
+			continue
+		}
+		seen[userID] = true
+
+		current, ok := mockAccessExpiry[userID]
+		if !ok {
+			skipped = append(skipped, userID)
+			continue
+		}
+		mockSaveAccessExpiry(userID, extendedExpiry(current.ExpiresAt, now, until, days), now)
+		extended = append(extended, mockAccessExpiryView(userID))
+	}
+	return extended, skipped, nil
+}
+
+// DeactivateExpiredAccounts деактивирует пользователей с наступившим сроком доступа в моковых данных
+func (s *MockStorage) DeactivateExpiredAccounts(now time.Time) ([]models.AccessExpiry, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	due := mockAccessExpiriesWhere(func(expiry models.AccessExpiry) bool {
+		return !expiry.ExpiresAt.After(now) && expiry.DeactivatedAt == nil && expiry.IsActive
+	})
+	at := now.UTC()
+	for i, expiry := range due {
+		stored := mockAccessExpiry[expiry.UserID]
+		stored.DeactivatedAt = &at
+		mockAccessExpiry[expiry.UserID] = stored
+		mockSetStatus(expiry.UserID, false)
+		due[i].IsActive, due[i].DeactivatedAt = false, &at
+	}
+	return due, nil
+}
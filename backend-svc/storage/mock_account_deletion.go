@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ScheduleAccountDeletion деактивирует пользователя и планирует удаление в моковых данных
+func (s *MockStorage) ScheduleAccountDeletion(userID int, purgeAt time.Time) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[userID]
+	if !exists {
+		return ErrUserNotFound
+	}
+	purgeAt = purgeAt.UTC()
+	user.Version++
+	user.IsActive = false
+	user.DeletionScheduledAt = &purgeAt
+	mockUsers[userID] = user
+
+	for id, device := range mockTrustedDevices {
+		if device.UserID == userID {
+			delete(mockTrustedDevices, id)
+		}
+	}
+	appendMockEvent(models.EventUserStatusChanged, userID, map[string]interface{}{
+		"userId":   userID,
+		"isActive": false,
+		"reason":   "deletion_scheduled",
+		"purgeAt":  purgeAt,
+	})
+	return nil
+}
+
+// CancelAccountDeletion отменяет запланированное удаление в моковых данных
+func (s *MockStorage) CancelAccountDeletion(userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[userID]
+	if !exists || user.DeletionScheduledAt == nil {
+		return nil
+	}
+	user.Version++
+	user.IsActive = true
+	user.DeletionScheduledAt = nil
+	mockUsers[userID] = user
+	appendMockEvent(models.EventUserStatusChanged, userID, map[string]interface{}{
+		"userId":   userID,
+		"isActive": true,
+		"reason":   "deletion_cancelled",
+	})
+	return nil
+}
+
+// PurgeDeletedAccounts удаляет пользователей с наступившим сроком удаления из моковых данных
+func (s *MockStorage) PurgeDeletedAccounts(now time.Time) (int64, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	var purged int64
+	for id, user := range mockUsers {
+		if user.DeletionScheduledAt == nil || user.DeletionScheduledAt.After(now) {
+			continue
+		}
+		delete(mockUsers, id)
+		delete(mockUsersByUsername, user.Username)
+		delete(mockUserProgress, id)
+		delete(mockOTPCodes, id)
+		delete(mockSessions, id)
+		delete(mockLoginContexts, id)
+		delete(mockPasswordHistory, id)
+		delete(mockProfilePrivacy, id)
+		appendMockEvent(models.EventUserDeleted, id, map[string]interface{}{
+			"userId": id,
+			"reason": "self_service",
+		})
+		purged++
+	}
+	return purged, nil
+}
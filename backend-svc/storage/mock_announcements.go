@@ -0,0 +1,131 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockAnnouncements      = map[int]models.Announcement{}
+	mockNextAnnouncementID = 1
+)
+
+// CreateAnnouncement сохраняет объявление в моковых данных
+func (s *MockStorage) CreateAnnouncement(a models.Announcement) (models.Announcement, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if a.CourseID != nil && mockCourseIndex(*a.CourseID) < 0 {
+		return models.Announcement{}, ErrCourseNotFound
+	}
+	now := time.Now().UTC()
+	a.ID = mockNextAnnouncementID
+	mockNextAnnouncementID++
+	a.CreatedAt, a.UpdatedAt = now, now
+	mockAnnouncements[a.ID] = a
+	return a, nil
+}
+
+// GetAnnouncement возвращает объявление из моковых данных
+func (s *MockStorage) GetAnnouncement(id int) (models.Announcement, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	a, ok := mockAnnouncements[id]
+	if !ok {
+		return models.Announcement{}, ErrAnnouncementNotFound
+	}
+	return a, nil
+}
+
+// UpdateAnnouncement меняет объявление в моковых данных
+func (s *MockStorage) UpdateAnnouncement(a models.Announcement) (models.Announcement, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	existing, ok := mockAnnouncements[a.ID]
+	if !ok {
+		return models.Announcement{}, ErrAnnouncementNotFound
+	}
+	if a.CourseID != nil && mockCourseIndex(*a.CourseID) < 0 {
+		return models.Announcement{}, ErrCourseNotFound
+	}
+	existing.CourseID = a.CourseID
+	existing.Title = a.Title
+	existing.Body = a.Body
+	existing.PublishAt = a.PublishAt
+	existing.ExpiresAt = a.ExpiresAt
+	existing.UpdatedAt = time.Now().UTC()
+	mockAnnouncements[a.ID] = existing
+	return existing, nil
+}
+
+// DeleteAnnouncement удаляет объявление из моковых данных
+func (s *MockStorage) DeleteAnnouncement(id int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockAnnouncements[id]; !ok {
+		return ErrAnnouncementNotFound
+	}
+	delete(mockAnnouncements, id)
+	return nil
+}
+
+// ListAnnouncements возвращает объявления из моковых данных по фильтру
+func (s *MockStorage) ListAnnouncements(filter models.AnnouncementFilter) ([]models.Announcement, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	announcements := []models.Announcement{}
+	for _, a := range mockAnnouncements {
+		if filter.CourseID != nil {
+			matches := a.CourseID != nil && *a.CourseID == *filter.CourseID
+			if !matches && !(filter.IncludeGlobal && a.CourseID == nil) {
+				continue
+			}
+		}
+		if filter.ActiveAt != nil && !mockAnnouncementActive(a, *filter.ActiveAt) {
+			continue
+		}
+		if filter.AuthorID != 0 && (a.AuthorID == nil || *a.AuthorID != filter.AuthorID) {
+			continue
+		}
+		announcements = append(announcements, a)
+	}
+	sort.Slice(announcements, func(i, j int) bool {
+		if !announcements[i].PublishAt.Equal(announcements[j].PublishAt) {
+			return announcements[i].PublishAt.After(announcements[j].PublishAt)
+		}
+		return announcements[i].ID > announcements[j].ID
+	})
+	if filter.Limit > 0 && len(announcements) > filter.Limit {
+		announcements = announcements[:filter.Limit]
+	}
+	return announcements, nil
+}
+
+// ClaimDueAnnouncements отмечает разосланными опубликованные объявления в моковых данных
+func (s *MockStorage) ClaimDueAnnouncements(now time.Time) ([]models.Announcement, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	claimed := []models.Announcement{}
+	for id, a := range mockAnnouncements {
+		if a.NotifiedAt != nil || !mockAnnouncementActive(a, now) {
+			continue
+		}
+		notifiedAt := now.UTC()
+		a.NotifiedAt = &notifiedAt
+		mockAnnouncements[id] = a
+		claimed = append(claimed, a)
+	}
+	sort.Slice(claimed, func(i, j int) bool { return claimed[i].PublishAt.Before(claimed[j].PublishAt) })
+	return claimed, nil
+}
+
+// mockAnnouncementActive сообщает, видно ли объявление в ленте в момент at
+func mockAnnouncementActive(a models.Announcement, at time.Time) bool {
+	return !a.PublishAt.After(at) && (a.ExpiresAt == nil || a.ExpiresAt.After(at))
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	mockAuditLog       []models.AuditEntry
+	mockNextAuditEntry int64 = 1
+)
+
+// RecordAuditEntry добавляет запись в журнал действий в моковых данных
+func (s *MockStorage) RecordAuditEntry(entry models.AuditEntry) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	entry.ID = mockNextAuditEntry
+	mockNextAuditEntry++
+	entry.ActorUsername = ""
+	if entry.ActorID != nil {
+		entry.ActorUsername = mockUsers[*entry.ActorID].Username
+	}
+	entry.CreatedAt = time.Now().UTC()
+	mockAuditLog = append(mockAuditLog, entry)
+	return nil
+}
+
+// ListAuditEntries возвращает записи журнала действий из моковых данных, новые первыми
+func (s *MockStorage) ListAuditEntries(filter models.AuditFilter) ([]models.AuditEntry, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	entries := []models.AuditEntry{}
+	for i := len(mockAuditLog) - 1; i >= 0 && len(entries) < filter.Limit; i-- {
+		entry := mockAuditLog[i]
+		if (filter.ActorID != 0 && (entry.ActorID == nil || *entry.ActorID != filter.ActorID)) ||
+			(filter.Action != "" && entry.Action != filter.Action) ||
+			(filter.TargetType != "" && entry.TargetType != filter.TargetType) ||
+			(filter.TargetID != 0 && (entry.TargetID == nil || *entry.TargetID != filter.TargetID)) ||
+			(!filter.From.IsZero() && entry.CreatedAt.Before(filter.From)) ||
+			(!filter.To.IsZero() && !entry.CreatedAt.Before(filter.To)) ||
+			(filter.Before != 0 && entry.ID >= filter.Before) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// AuditSnapshot возвращает отслеживаемые поля пользователя или курса из моковых данных
+func (s *MockStorage) AuditSnapshot(targetType string, targetID int) (map[string]interface{}, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	switch targetType {
+	case AuditTargetUser:
+		user, ok := mockUsers[targetID]
+		if !ok {
+			return nil, nil
+		}
+		session := mockSessions[targetID]
+		snapshot := map[string]interface{}{
+			"username":           user.Username,
+			"email":              user.Email,
+			"fullName":           user.FullName,
+			"isAdmin":            user.IsAdmin,
+			"isActive":           user.IsActive,
+			"is2faEnabled":       user.Is2FAEnabled,
+			"mustChangePassword": session.MustChangePassword,
+			"singleSession":      session.SingleSession,
+			"organizationId":     mockUserOrganization(targetID),
+			"managerId":          nil,
+			"accessExpiresAt":    nil,
+		}
+		if managerID, ok := mockManagers[targetID]; ok {
+			snapshot["managerId"] = managerID
+		}
+		if expiry, ok := mockAccessExpiry[targetID]; ok {
+			snapshot["accessExpiresAt"] = expiry.ExpiresAt
+		}
+		return snapshot, nil
+	case AuditTargetCourse:
+		i := mockCourseIndex(targetID)
+		if i < 0 {
+			return nil, nil
+		}
+		snapshot := map[string]interface{}{
+			"vulnerabilityType":           mockCourses[i].VulnerabilityType,
+			"description":                 mockCourses[i].Description,
+			"organizationId":              mockCourseOrganization(targetID),
+			"recertificationMonths":       nil,
+			"recertificationReminderDays": nil,
+		}
+		if rec, ok := mockRecertifications[targetID]; ok {
+			snapshot["recertificationMonths"] = rec.PeriodMonths
+			snapshot["recertificationReminderDays"] = rec.ReminderDays
+		}
+		for locale, translation := range mockCourseTranslations[targetID] {
+			snapshot["translations."+locale+".vulnerabilityType"] = translation.VulnerabilityType
+			snapshot["translations."+locale+".description"] = translation.Description
+		}
+		return snapshot, nil
+	}
+	return nil, nil
+}
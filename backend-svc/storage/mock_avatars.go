@@ -0,0 +1,17 @@
+package storage
+
+// SetAvatar запоминает префикс ключей аватара пользователя в моковых данных
+func (s *MockStorage) SetAvatar(userID int, avatarKey string) (string, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[userID]
+	if !exists {
+		return "", ErrUserNotFound
+	}
+	previous := user.AvatarKey
+	user.AvatarKey = avatarKey
+	user.Version++
+	mockUsers[userID] = user
+	return previous, nil
+}
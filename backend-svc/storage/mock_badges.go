@@ -0,0 +1,236 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockBadgeClasses   = map[int]models.BadgeClass{}
+	mockBadgeImages    = map[int][]byte{}
+	mockNextBadgeClass = 1
+	// mockBadgeAssertions — ID удостоверения → выданный значок (без BadgeClass)
+	mockBadgeAssertions = map[string]models.BadgeAssertion{}
+)
+
+// mockBadgeWithClass дополняет выданный значок значком-классом
+func mockBadgeWithClass(assertion models.BadgeAssertion) models.BadgeAssertion {
+	class := mockBadgeClasses[assertion.BadgeClassID]
+	assertion.BadgeClass = &class
+	return assertion
+}
+
+// mockSortBadges упорядочивает значки от новых к старым
+func mockSortBadges(assertions []models.BadgeAssertion) {
+	sort.Slice(assertions, func(i, j int) bool {
+		if !assertions[i].IssuedAt.Equal(assertions[j].IssuedAt) {
+			return assertions[i].IssuedAt.After(assertions[j].IssuedAt)
+		}
+		return assertions[i].ID < assertions[j].ID
+	})
+}
+
+// CreateBadgeClass добавляет значок в моковые данные
+func (s *MockStorage) CreateBadgeClass(class models.BadgeClass) (models.BadgeClass, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if class.CourseID != nil {
+		if mockCourseIndex(*class.CourseID) < 0 {
+			return models.BadgeClass{}, ErrCourseNotFound
+		}
+		for _, existing := range mockBadgeClasses {
+			if existing.CourseID != nil && *existing.CourseID == *class.CourseID {
+				return models.BadgeClass{}, ErrBadgeClassExists
+			}
+		}
+	}
+	class.ID = mockNextBadgeClass
+	mockNextBadgeClass++
+	class.CreatedAt = time.Now().UTC()
+	class.UpdatedAt = class.CreatedAt
+	mockBadgeClasses[class.ID] = class
+	return class, nil
+}
+
+// UpdateBadgeClass меняет описание значка в моковых данных
+func (s *MockStorage) UpdateBadgeClass(class models.BadgeClass) (models.BadgeClass, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	existing, ok := mockBadgeClasses[class.ID]
+	if !ok {
+		return models.BadgeClass{}, ErrBadgeClassNotFound
+	}
+	existing.Name, existing.Description, existing.Criteria = class.Name, class.Description, class.Criteria
+	existing.UpdatedAt = time.Now().UTC()
+	mockBadgeClasses[class.ID] = existing
+	return existing, nil
+}
+
+// DeleteBadgeClass удаляет значок и выданные удостоверения из моковых данных
+func (s *MockStorage) DeleteBadgeClass(id int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockBadgeClasses[id]; !ok {
+		return ErrBadgeClassNotFound
+	}
+	delete(mockBadgeClasses, id)
+	delete(mockBadgeImages, id)
+	for assertionID, assertion := range mockBadgeAssertions {
+		if assertion.BadgeClassID == id {
+			delete(mockBadgeAssertions, assertionID)
+		}
+	}
+	return nil
+}
+
+// GetBadgeClass возвращает значок из моковых данных
+func (s *MockStorage) GetBadgeClass(id int) (models.BadgeClass, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	class, ok := mockBadgeClasses[id]
+	if !ok {
+		return models.BadgeClass{}, ErrBadgeClassNotFound
+	}
+	return class, nil
+}
+
+// GetCourseBadgeClass возвращает значок курса из моковых данных
+func (s *MockStorage) GetCourseBadgeClass(courseID int) (models.BadgeClass, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for _, class := range mockBadgeClasses {
+		if class.CourseID != nil && *class.CourseID == courseID {
+			return class, nil
+		}
+	}
+	return models.BadgeClass{}, ErrBadgeClassNotFound
+}
+
+// ListBadgeClasses возвращает значки из моковых данных
+func (s *MockStorage) ListBadgeClasses() ([]models.BadgeClass, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	classes := make([]models.BadgeClass, 0, len(mockBadgeClasses))
+	for _, class := range mockBadgeClasses {
+		classes = append(classes, class)
+	}
+	sort.Slice(classes, func(i, j int) bool { return classes[i].ID < classes[j].ID })
+	return classes, nil
+}
+
+// GetBadgeClassImage возвращает изображение значка из моковых данных
+func (s *MockStorage) GetBadgeClassImage(id int) ([]byte, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockBadgeClasses[id]; !ok {
+		return nil, ErrBadgeClassNotFound
+	}
+	return mockBadgeImages[id], nil
+}
+
+// SetBadgeClassImage сохраняет изображение значка в моковых данных
+func (s *MockStorage) SetBadgeClassImage(id int, image []byte) (models.BadgeClass, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	class, ok := mockBadgeClasses[id]
+	if !ok {
+		return models.BadgeClass{}, ErrBadgeClassNotFound
+	}
+	if image == nil {
+		delete(mockBadgeImages, id)
+	} else {
+		mockBadgeImages[id] = append([]byte(nil), image...)
+	}
+	class.UpdatedAt = time.Now().UTC()
+	mockBadgeClasses[id] = class
+	return class, nil
+}
+
+// CreateBadgeAssertion сохраняет выданный значок в моковых данных
+func (s *MockStorage) CreateBadgeAssertion(assertion models.BadgeAssertion) (models.BadgeAssertion, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for id, existing := range mockBadgeAssertions {
+		if existing.BadgeClassID != assertion.BadgeClassID || existing.UserID != assertion.UserID {
+			continue
+		}
+		if existing.RevokedAt == nil {
+			return models.BadgeAssertion{}, ErrBadgeAlreadyAwarded
+		}
+		delete(mockBadgeAssertions, id)
+	}
+	assertion.BadgeClass = nil
+	mockBadgeAssertions[assertion.ID] = assertion
+	return assertion, nil
+}
+
+// GetBadgeAssertion возвращает выданный значок из моковых данных
+func (s *MockStorage) GetBadgeAssertion(id string) (models.BadgeAssertion, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	assertion, ok := mockBadgeAssertions[id]
+	if !ok {
+		return models.BadgeAssertion{}, ErrBadgeAssertionNotFound
+	}
+	return mockBadgeWithClass(assertion), nil
+}
+
+// ListUserBadges возвращает значки пользователя из моковых данных
+func (s *MockStorage) ListUserBadges(userID int) ([]models.BadgeAssertion, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	badges := []models.BadgeAssertion{}
+	for _, assertion := range mockBadgeAssertions {
+		if assertion.UserID == userID {
+			badges = append(badges, mockBadgeWithClass(assertion))
+		}
+	}
+	mockSortBadges(badges)
+	return badges, nil
+}
+
+// ListBadgeClassAssertions возвращает выданные значки одного типа из моковых данных
+func (s *MockStorage) ListBadgeClassAssertions(classID int) ([]models.BadgeAssertion, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	assertions := []models.BadgeAssertion{}
+	for _, assertion := range mockBadgeAssertions {
+		if assertion.BadgeClassID == classID {
+			assertion.Username = mockUsers[assertion.UserID].Username
+			assertions = append(assertions, assertion)
+		}
+	}
+	mockSortBadges(assertions)
+	return assertions, nil
+}
+
+// RevokeBadgeAssertion отзывает значок в моковых данных
+func (s *MockStorage) RevokeBadgeAssertion(id, reason string) (models.BadgeAssertion, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	assertion, ok := mockBadgeAssertions[id]
+	if !ok {
+		return models.BadgeAssertion{}, ErrBadgeAssertionNotFound
+	}
+	if assertion.RevokedAt != nil {
+		return models.BadgeAssertion{}, ErrBadgeAlreadyRevoked
+	}
+	now := time.Now().UTC()
+	assertion.RevokedAt, assertion.RevocationReason = &now, reason
+	mockBadgeAssertions[id] = assertion
+	return mockBadgeWithClass(assertion), nil
+}
@@ -0,0 +1,53 @@
+package storage
+
+import "lmsmodule/backend-svc/models"
+
+// GetCoursesByIDs возвращает курсы с заданиями из моковых данных
+func (s *MockStorage) GetCoursesByIDs(ids []int) (map[int]models.Course, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	wanted := make(map[int]bool, len(ids))
+	for _, id := range ids {
+		wanted[id] = true
+	}
+	courses := make(map[int]models.Course, len(ids))
+	for _, course := range mockCourses {
+		if wanted[course.ID] && s.courseVisible(course.ID) {
+			course.Tasks = append([]models.Task(nil), course.Tasks...)
+			fillMockCourseSummary(&course)
+			courses[course.ID] = course
+		}
+	}
+	return courses, nil
+}
+
+// GetUsersByIDs возвращает пользователей из моковых данных
+func (s *MockStorage) GetUsersByIDs(ids []int) (map[int]models.User, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	users := make(map[int]models.User, len(ids))
+	for _, id := range ids {
+		if user, ok := mockUsers[id]; ok {
+			users[id] = user
+		}
+	}
+	return users, nil
+}
+
+// GetProgressByUserIDs возвращает копии прогресса пользователей из моковых данных
+func (s *MockStorage) GetProgressByUserIDs(userIDs []int) (map[int]models.UserProgress, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	progress := make(map[int]models.UserProgress, len(userIDs))
+	for _, userID := range userIDs {
+		completed := make(map[int]bool)
+		for taskID, done := range mockUserProgress[userID].Completed {
+			completed[taskID] = done
+		}
+		progress[userID] = models.UserProgress{UserID: userID, Completed: completed}
+	}
+	return progress, nil
+}
@@ -0,0 +1,82 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+// mockBookmarks — закладки пользователей в порядке добавления
+var mockBookmarks []models.Bookmark
+
+// mockBookmarkIndex возвращает индекс закладки в mockBookmarks или -1; mockMu должен быть захвачен
+func mockBookmarkIndex(userID, courseID int, taskID *int) int {
+	for i, b := range mockBookmarks {
+		if b.UserID == userID && b.CourseID == courseID && bookmarkTaskID(b.TaskID) == bookmarkTaskID(taskID) {
+			return i
+		}
+	}
+	return -1
+}
+
+// SaveBookmark создает закладку или меняет ее notify в моковых данных
+func (s *MockStorage) SaveBookmark(b models.Bookmark) (models.Bookmark, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockCourseIndex(b.CourseID) < 0 {
+		return models.Bookmark{}, ErrCourseNotFound
+	}
+	if b.TaskID != nil {
+		b.Notify = false
+	}
+	if i := mockBookmarkIndex(b.UserID, b.CourseID, b.TaskID); i >= 0 {
+		mockBookmarks[i].Notify = b.Notify
+		return mockBookmarks[i], nil
+	}
+	b.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	mockBookmarks = append(mockBookmarks, b)
+	return b, nil
+}
+
+// DeleteBookmark удаляет закладку из моковых данных
+func (s *MockStorage) DeleteBookmark(userID, courseID int, taskID *int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	i := mockBookmarkIndex(userID, courseID, taskID)
+	if i < 0 {
+		return ErrBookmarkNotFound
+	}
+	mockBookmarks = append(mockBookmarks[:i], mockBookmarks[i+1:]...)
+	return nil
+}
+
+// ListBookmarks возвращает закладки пользователя из моковых данных, новые первыми
+func (s *MockStorage) ListBookmarks(userID int) ([]models.Bookmark, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	bookmarks := []models.Bookmark{}
+	for i := len(mockBookmarks) - 1; i >= 0; i-- {
+		if b := mockBookmarks[i]; b.UserID == userID && mockCourseIndex(b.CourseID) >= 0 {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+	return bookmarks, nil
+}
+
+// ListBookmarkSubscribers возвращает подписчиков курса из моковых данных
+func (s *MockStorage) ListBookmarkSubscribers(courseID int) ([]int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	userIDs := []int{}
+	for _, b := range mockBookmarks {
+		if b.CourseID == courseID && b.TaskID == nil && b.Notify && s.userVisible(b.UserID) {
+			userIDs = append(userIDs, b.UserID)
+		}
+	}
+	sort.Ints(userIDs)
+	return userIDs, nil
+}
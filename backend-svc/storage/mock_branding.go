@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// mockBranding — оформление организаций; логотипы хранятся отдельно в mockLogos
+	mockBranding = map[int]models.OrganizationBranding{}
+	mockLogos    = map[int][]byte{}
+)
+
+// mockOrganizationBranding возвращает оформление организации; mockMu должен быть захвачен
+func mockOrganizationBranding(orgID int) (models.OrganizationBranding, error) {
+	org, ok := mockOrganizations[orgID]
+	if !ok {
+		return models.OrganizationBranding{}, ErrOrganizationNotFound
+	}
+	branding := mockBranding[orgID]
+	branding.OrganizationID, branding.OrganizationName = org.ID, org.Name
+	branding.HasLogo = mockLogos[orgID] != nil
+	return branding, nil
+}
+
+// GetOrganizationBranding возвращает оформление организации из моковых данных
+func (s *MockStorage) GetOrganizationBranding(orgID int) (models.OrganizationBranding, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+	return mockOrganizationBranding(orgID)
+}
+
+// GetUserBranding возвращает оформление организации пользователя из моковых данных
+func (s *MockStorage) GetUserBranding(userID int) (models.OrganizationBranding, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockUsers[userID]; !ok {
+		return models.OrganizationBranding{}, ErrUserNotFound
+	}
+	return mockOrganizationBranding(mockUserOrganization(userID))
+}
+
+// GetEmailBranding возвращает оформление организации владельца адреса из моковых данных
+func (s *MockStorage) GetEmailBranding(email string) (models.OrganizationBranding, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for id, user := range mockUsers {
+		if user.Email == email {
+			return mockOrganizationBranding(mockUserOrganization(id))
+		}
+	}
+	return models.OrganizationBranding{}, ErrUserNotFound
+}
+
+// SaveOrganizationBranding сохраняет оформление организации в моковых данных
+func (s *MockStorage) SaveOrganizationBranding(branding models.OrganizationBranding) (models.OrganizationBranding, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockOrganizations[branding.OrganizationID]; !ok {
+		return branding, ErrOrganizationNotFound
+	}
+	branding.UpdatedAt = time.Now().UTC()
+	mockBranding[branding.OrganizationID] = branding
+	return mockOrganizationBranding(branding.OrganizationID)
+}
+
+// GetOrganizationLogo возвращает логотип организации из моковых данных
+func (s *MockStorage) GetOrganizationLogo(orgID int) ([]byte, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+	return mockLogos[orgID], nil
+}
+
+// SetOrganizationLogo сохраняет или удаляет логотип организации в моковых данных
+func (s *MockStorage) SetOrganizationLogo(orgID int, logo []byte) (models.OrganizationBranding, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockOrganizations[orgID]; !ok {
+		return models.OrganizationBranding{}, ErrOrganizationNotFound
+	}
+	if logo == nil {
+		delete(mockLogos, orgID)
+	} else {
+		mockLogos[orgID] = logo
+	}
+	branding := mockBranding[orgID]
+	branding.UpdatedAt = time.Now().UTC()
+	mockBranding[orgID] = branding
+	return mockOrganizationBranding(orgID)
+}
@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+	"time"
+)
+
+// mockBulkUserIDs возвращает ID выбранных пользователей; mockMu и mockGroupsMu должны быть захвачены
+func (s *MockStorage) mockBulkUserIDs(req models.BulkUserRequest) ([]int, error) {
+	if req.Filter == nil {
+		return uniqueIDs(req.UserIDs), nil
+	}
+
+	filter := req.Filter
+	members, ok := mockGroupMembers[filter.Group]
+	if filter.Group != "" && !ok {
+		return nil, ErrGroupNotFound
+	}
+	query := strings.ToLower(filter.Query)
+	users := mockUsersWhere(func(user models.User) bool {
+		return s.userVisible(user.ID) &&
+			(query == "" || strings.Contains(strings.ToLower(user.Username), query) ||
+				strings.Contains(strings.ToLower(user.Email), query) ||
+				strings.Contains(strings.ToLower(user.FullName), query)) &&
+			(filter.Group == "" || members[user.ID]) &&
+			(filter.IsAdmin == nil || user.IsAdmin == *filter.IsAdmin) &&
+			(filter.IsActive == nil || user.IsActive == *filter.IsActive) &&
+			(filter.OrganizationID == 0 || mockUserOrganization(user.ID) == filter.OrganizationID)
+	})
+	if len(users) > MaxBulkUsers {
+		return nil, ErrTooManyUsers
+	}
+	userIDs := make([]int, len(users))
+	for i, user := range users {
+		userIDs[i] = user.ID
+	}
+	return userIDs, nil
+}
+
+// BulkUpdateUsers применяет массовую операцию к моковым данным. Все проверки выполняются до
+// первого изменения, поэтому операция, как и в базе данных, либо применяется целиком, либо нет.
+// Моковые группы принадлежат организации по умолчанию.
+func (s *MockStorage) BulkUpdateUsers(req models.BulkUserRequest, actorID int) ([]models.BulkUserResult, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+	mockGroupsMu.Lock()
+	defer mockGroupsMu.Unlock()
+
+	for _, group := range append(append([]string{}, req.AddGroups...), req.RemoveGroups...) {
+		if _, ok := mockGroupMembers[group]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrGroupNotFound, group)
+		}
+	}
+	for _, courseID := range append(append([]int{}, req.AssignCourses...), req.UnassignCourses...) {
+		if mockCourseIndex(courseID) < 0 {
+			return nil, fmt.Errorf("%w: %d", ErrCourseNotFound, courseID)
+		}
+	}
+	userIDs, err := s.mockBulkUserIDs(req)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	results := make([]models.BulkUserResult, 0, len(userIDs))
+	for _, userID := range userIDs {
+		result := models.BulkUserResult{UserID: userID}
+		user, ok := mockUsers[userID]
+		if !ok || !s.userVisible(userID) {
+			result.Status = models.BulkUserNotFound
+			results = append(results, result)
+			continue
+		}
+		result.Username = user.Username
+		orgID := mockUserOrganization(userID)
+
+		if req.Role != "" && (req.Role == "admin") != user.IsAdmin {
+			if userID == actorID {
+				result.Errors = append(result.Errors, "cannot change your own role")
+			} else {
+				user.IsAdmin = !user.IsAdmin
+				user.Version++
+				mockUsers[userID] = user
+				appendMockEvent(models.EventUserRoleChanged, userID, map[string]interface{}{
+					"userId":  userID,
+					"isAdmin": user.IsAdmin,
+				})
+				result.RoleChanged = true
+			}
+		}
+
+		for _, group := range req.AddGroups {
+			if orgID != models.DefaultOrganizationID {
+				result.Errors = append(result.Errors, fmt.Sprintf("group %q belongs to another organization", group))
+				continue
+			}
+			if members := mockGroupMembers[group]; !members[userID] {
+				members[userID] = true
+				result.GroupsAdded = append(result.GroupsAdded, group)
+			}
+		}
+		for _, group := range req.RemoveGroups {
+			if members := mockGroupMembers[group]; members[userID] {
+				delete(members, userID)
+				result.GroupsRemoved = append(result.GroupsRemoved, group)
+			}
+		}
+
+		for _, courseID := range uniqueIDs(req.AssignCourses) {
+			if mockCourseOrganization(courseID) != orgID {
+				result.Errors = append(result.Errors, fmt.Sprintf("course %d belongs to another organization", courseID))
+				continue
+			}
+			if mockCourses[mockCourseIndex(courseID)].ArchivedAt != nil {
+				result.Errors = append(result.Errors, fmt.Sprintf("course %d is archived", courseID))
+				continue
+			}
+			if mockAssignmentIndex(userID, courseID) < 0 {
+				mockCourseAssignment[userID] = append(mockCourseAssignment[userID], models.CourseAssignment{
+					CourseID: courseID, AssignedAt: now,
+				})
+				result.CoursesAssigned = append(result.CoursesAssigned, courseID)
+			}
+		}
+		for _, courseID := range uniqueIDs(req.UnassignCourses) {
+			if i := mockAssignmentIndex(userID, courseID); i >= 0 {
+				assignments := mockCourseAssignment[userID]
+				mockCourseAssignment[userID] = append(assignments[:i:i], assignments[i+1:]...)
+				result.CoursesUnassigned = append(result.CoursesUnassigned, courseID)
+			}
+		}
+
+		result.Status = bulkResultStatus(result)
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// mockAssignmentIndex возвращает индекс назначения курса пользователю или -1; mockMu должен быть захвачен
+func mockAssignmentIndex(userID, courseID int) int {
+	for i, assignment := range mockCourseAssignment[userID] {
+		if assignment.CourseID == courseID {
+			return i
+		}
+	}
+	return -1
+}
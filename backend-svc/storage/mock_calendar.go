@@ -0,0 +1,210 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockTaskSchedules     = map[int]models.TaskSchedule{}
+	mockLiveSessions      = map[int]models.LiveSession{}
+	mockNextLiveSessionID = 1
+	// mockCalendarFeeds — хеш токена ссылки на календарь → пользователь
+	mockCalendarFeeds = map[string]int{}
+)
+
+// mockCourseSelected сообщает, входит ли курс в courseIDs (nil — все курсы)
+func mockCourseSelected(courseIDs []int, courseID int) bool {
+	if courseIDs == nil {
+		return true
+	}
+	for _, id := range courseIDs {
+		if id == courseID {
+			return true
+		}
+	}
+	return false
+}
+
+// mockWithin сообщает, попадает ли время в [from, to)
+func mockWithin(t *time.Time, from, to time.Time) bool {
+	return t != nil && !t.Before(from) && t.Before(to)
+}
+
+// SetTaskSchedule сохраняет сроки задания в моковых данных
+func (s *MockStorage) SetTaskSchedule(schedule models.TaskSchedule) (models.TaskSchedule, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for _, task := range mockTasks {
+		if task.ID == schedule.TaskID && s.courseVisible(task.CourseID) {
+			schedule.CourseID = task.CourseID
+			schedule.TaskTitle = task.Title
+			schedule.UpdatedAt = time.Now().UTC()
+			mockTaskSchedules[task.ID] = schedule
+			return schedule, nil
+		}
+	}
+	return models.TaskSchedule{}, ErrTaskNotFound
+}
+
+// ListTaskSchedules возвращает расписания заданий из моковых данных
+func (s *MockStorage) ListTaskSchedules(courseIDs []int, from, to time.Time) ([]models.TaskSchedule, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	schedules := []models.TaskSchedule{}
+	for _, schedule := range mockTaskSchedules {
+		if !mockCourseSelected(courseIDs, schedule.CourseID) {
+			continue
+		}
+		if mockWithin(schedule.ReleaseAt, from, to) || mockWithin(schedule.DueAt, from, to) {
+			schedules = append(schedules, schedule)
+		}
+	}
+	sort.Slice(schedules, func(i, j int) bool { return schedules[i].TaskID < schedules[j].TaskID })
+	return schedules, nil
+}
+
+// CreateLiveSession сохраняет занятие в моковых данных
+func (s *MockStorage) CreateLiveSession(session models.LiveSession) (models.LiveSession, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockCourseIndex(session.CourseID) < 0 {
+		return models.LiveSession{}, ErrCourseNotFound
+	}
+	now := time.Now().UTC()
+	session.ID = mockNextLiveSessionID
+	mockNextLiveSessionID++
+	session.CreatedAt, session.UpdatedAt = now, now
+	mockLiveSessions[session.ID] = session
+	return session, nil
+}
+
+// GetLiveSession возвращает занятие из моковых данных
+func (s *MockStorage) GetLiveSession(id int) (models.LiveSession, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	session, ok := mockLiveSessions[id]
+	if !ok {
+		return models.LiveSession{}, ErrLiveSessionNotFound
+	}
+	return session, nil
+}
+
+// UpdateLiveSession меняет занятие в моковых данных
+func (s *MockStorage) UpdateLiveSession(session models.LiveSession) (models.LiveSession, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	existing, ok := mockLiveSessions[session.ID]
+	if !ok {
+		return models.LiveSession{}, ErrLiveSessionNotFound
+	}
+	if mockCourseIndex(session.CourseID) < 0 {
+		return models.LiveSession{}, ErrCourseNotFound
+	}
+	existing.CourseID = session.CourseID
+	existing.Title = session.Title
+	existing.Description = session.Description
+	existing.StartsAt = session.StartsAt
+	existing.EndsAt = session.EndsAt
+	existing.JoinURL = session.JoinURL
+	existing.UpdatedAt = time.Now().UTC()
+	mockLiveSessions[session.ID] = existing
+	return existing, nil
+}
+
+// DeleteLiveSession удаляет занятие из моковых данных
+func (s *MockStorage) DeleteLiveSession(id int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockLiveSessions[id]; !ok {
+		return ErrLiveSessionNotFound
+	}
+	delete(mockLiveSessions, id)
+	return nil
+}
+
+// ListLiveSessions возвращает занятия из моковых данных
+func (s *MockStorage) ListLiveSessions(courseIDs []int, from, to time.Time) ([]models.LiveSession, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	sessions := []models.LiveSession{}
+	for _, session := range mockLiveSessions {
+		if mockCourseSelected(courseIDs, session.CourseID) && session.StartsAt.Before(to) && session.EndsAt.After(from) {
+			sessions = append(sessions, session)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		if !sessions[i].StartsAt.Equal(sessions[j].StartsAt) {
+			return sessions[i].StartsAt.Before(sessions[j].StartsAt)
+		}
+		return sessions[i].ID < sessions[j].ID
+	})
+	return sessions, nil
+}
+
+// GetStartedCourseIDs возвращает начатые пользователем курсы из моковых данных
+func (s *MockStorage) GetStartedCourseIDs(userID int) ([]int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	started := map[int]bool{}
+	for taskID := range mockUserProgress[userID].Completed {
+		if courseID := mockTaskCourseID(taskID); courseID != 0 {
+			started[courseID] = true
+		}
+	}
+	courseIDs := []int{}
+	for id := range started {
+		courseIDs = append(courseIDs, id)
+	}
+	sort.Ints(courseIDs)
+	return courseIDs, nil
+}
+
+// SetCalendarFeedToken заменяет токен ссылки на календарь в моковых данных
+func (s *MockStorage) SetCalendarFeedToken(userID int, tokenHash string) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for hash, owner := range mockCalendarFeeds {
+		if owner == userID {
+			delete(mockCalendarFeeds, hash)
+		}
+	}
+	mockCalendarFeeds[tokenHash] = userID
+	return nil
+}
+
+// DeleteCalendarFeedToken отзывает ссылку на календарь в моковых данных
+func (s *MockStorage) DeleteCalendarFeedToken(userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for hash, owner := range mockCalendarFeeds {
+		if owner == userID {
+			delete(mockCalendarFeeds, hash)
+			return nil
+		}
+	}
+	return ErrCalendarFeedNotFound
+}
+
+// GetCalendarFeedUser возвращает владельца токена из моковых данных
+func (s *MockStorage) GetCalendarFeedUser(tokenHash string) (int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	userID, ok := mockCalendarFeeds[tokenHash]
+	if !ok {
+		return 0, ErrCalendarFeedNotFound
+	}
+	return userID, nil
+}
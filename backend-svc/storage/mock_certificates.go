@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	// mockCertificates — код проверки → сертификат
+	mockCertificates    = map[string]models.Certificate{}
+	mockNextCertificate = 1
+)
+
+// CreateCertificate добавляет сертификат в моковые данные
+func (s *MockStorage) CreateCertificate(cert models.Certificate) (models.Certificate, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for _, existing := range mockCertificates {
+		if existing.UserID == cert.UserID && existing.CourseID != nil && cert.CourseID != nil && *existing.CourseID == *cert.CourseID {
+			return models.Certificate{}, ErrCertificateExists
+		}
+	}
+	cert.ID = mockNextCertificate
+	mockNextCertificate++
+	mockCertificates[cert.Code] = cert
+	return cert, nil
+}
+
+// GetCertificateByCode возвращает сертификат из моковых данных
+func (s *MockStorage) GetCertificateByCode(code string) (models.Certificate, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	cert, ok := mockCertificates[code]
+	if !ok {
+		return models.Certificate{}, ErrCertificateNotFound
+	}
+	return cert, nil
+}
+
+// ListUserCertificates возвращает сертификаты пользователя из моковых данных
+func (s *MockStorage) ListUserCertificates(userID int) ([]models.Certificate, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	certificates := []models.Certificate{}
+	for _, cert := range mockCertificates {
+		if cert.UserID == userID {
+			certificates = append(certificates, cert)
+		}
+	}
+	sort.Slice(certificates, func(i, j int) bool { return certificates[i].ID > certificates[j].ID })
+	return certificates, nil
+}
+
+// RevokeCertificate отзывает сертификат в моковых данных
+func (s *MockStorage) RevokeCertificate(code string, adminID int, reason string) (models.Certificate, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	cert, ok := mockCertificates[code]
+	if !ok {
+		return models.Certificate{}, ErrCertificateNotFound
+	}
+	if cert.RevokedAt != nil {
+		return models.Certificate{}, ErrCertificateRevoked
+	}
+	now := time.Now().UTC()
+	cert.RevokedAt, cert.RevokedBy, cert.RevocationReason = &now, &adminID, reason
+	mockCertificates[code] = cert
+	return cert, nil
+}
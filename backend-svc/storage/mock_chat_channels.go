@@ -0,0 +1,133 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+type mockChatReminder struct {
+	channelID, taskID int
+	dueAt             time.Time
+}
+
+var (
+	mockChatChannels      = map[int]models.ChatChannel{}
+	mockNextChatChannel   = 1
+	mockChatDeadlineSends = map[mockChatReminder]bool{}
+)
+
+// mockCheckChatChannel проверяет группу и курс канала; вызывается под mockMu
+func mockCheckChatChannel(channel models.ChatChannel) error {
+	if !mockGroupExists(channel.Group) {
+		return ErrGroupNotFound
+	}
+	if channel.CourseID != nil && mockCourseIndex(*channel.CourseID) < 0 {
+		return ErrCourseNotFound
+	}
+	return nil
+}
+
+// CreateChatChannel добавляет канал в моковые данные
+func (s *MockStorage) CreateChatChannel(channel models.ChatChannel) (models.ChatChannel, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if err := mockCheckChatChannel(channel); err != nil {
+		return models.ChatChannel{}, err
+	}
+	channel.ID = mockNextChatChannel
+	mockNextChatChannel++
+	channel.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	channel.UpdatedAt = channel.CreatedAt
+	channel.LastLeaderboardAt = nil
+	mockChatChannels[channel.ID] = channel
+	return channel, nil
+}
+
+// UpdateChatChannel изменяет канал в моковых данных
+func (s *MockStorage) UpdateChatChannel(channel models.ChatChannel) (models.ChatChannel, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	existing, ok := mockChatChannels[channel.ID]
+	if !ok {
+		return models.ChatChannel{}, ErrChatChannelNotFound
+	}
+	if err := mockCheckChatChannel(channel); err != nil {
+		return models.ChatChannel{}, err
+	}
+	channel.CreatedBy, channel.CreatedAt = existing.CreatedBy, existing.CreatedAt
+	channel.LastLeaderboardAt = existing.LastLeaderboardAt
+	channel.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	mockChatChannels[channel.ID] = channel
+	return channel, nil
+}
+
+// DeleteChatChannel удаляет канал из моковых данных
+func (s *MockStorage) DeleteChatChannel(id int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockChatChannels[id]; !ok {
+		return ErrChatChannelNotFound
+	}
+	delete(mockChatChannels, id)
+	return nil
+}
+
+// GetChatChannel возвращает канал из моковых данных
+func (s *MockStorage) GetChatChannel(id int) (models.ChatChannel, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	channel, ok := mockChatChannels[id]
+	if !ok {
+		return models.ChatChannel{}, ErrChatChannelNotFound
+	}
+	return channel, nil
+}
+
+// ListChatChannels возвращает каналы из моковых данных
+func (s *MockStorage) ListChatChannels() ([]models.ChatChannel, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	channels := make([]models.ChatChannel, 0, len(mockChatChannels))
+	for _, channel := range mockChatChannels {
+		channels = append(channels, channel)
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		if channels[i].Group != channels[j].Group {
+			return channels[i].Group < channels[j].Group
+		}
+		return channels[i].ID < channels[j].ID
+	})
+	return channels, nil
+}
+
+// SetChatLeaderboardSent запоминает время отправки таблицы лидеров в моковых данных
+func (s *MockStorage) SetChatLeaderboardSent(id int, at time.Time) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if channel, ok := mockChatChannels[id]; ok {
+		at = at.UTC()
+		channel.LastLeaderboardAt = &at
+		mockChatChannels[id] = channel
+	}
+	return nil
+}
+
+// MarkChatDeadlineReminder отмечает напоминание в моковых данных
+func (s *MockStorage) MarkChatDeadlineReminder(channelID, taskID int, dueAt time.Time) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	key := mockChatReminder{channelID: channelID, taskID: taskID, dueAt: dueAt.UTC()}
+	if mockChatDeadlineSends[key] {
+		return false, nil
+	}
+	mockChatDeadlineSends[key] = true
+	return true, nil
+}
@@ -0,0 +1,165 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockCourseTranslations = map[int]map[string]models.CourseTranslation{}
+	mockTaskTranslations   = map[int]map[string]models.TaskTranslation{}
+)
+
+// GetCourseTranslations возвращает переводы курсов из моковых данных
+func (s *MockStorage) GetCourseTranslations(locale string) (map[int]models.CourseTranslation, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	translations := make(map[int]models.CourseTranslation)
+	for courseID, byLocale := range mockCourseTranslations {
+		if t, ok := byLocale[locale]; ok {
+			translations[courseID] = t
+		}
+	}
+	return translations, nil
+}
+
+// GetTaskTranslations возвращает переводы заданий курса из моковых данных
+func (s *MockStorage) GetTaskTranslations(courseID int, locale string) (map[int]models.TaskTranslation, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	translations := make(map[int]models.TaskTranslation)
+	for _, task := range mockTasks {
+		if task.CourseID != courseID {
+			continue
+		}
+		if t, ok := mockTaskTranslations[task.ID][locale]; ok {
+			translations[task.ID] = t
+		}
+	}
+	return translations, nil
+}
+
+// ListCourseTranslations возвращает все переводы курса и его заданий из моковых данных
+func (s *MockStorage) ListCourseTranslations(courseID int) (models.CourseTranslations, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	result := models.CourseTranslations{
+		CourseID: courseID,
+		Course:   []models.CourseTranslation{},
+		Tasks:    []models.TaskTranslation{},
+	}
+	if mockCourseIndex(courseID) < 0 {
+		return result, ErrCourseNotFound
+	}
+	for _, t := range mockCourseTranslations[courseID] {
+		result.Course = append(result.Course, t)
+	}
+	sort.Slice(result.Course, func(i, j int) bool { return result.Course[i].Locale < result.Course[j].Locale })
+
+	for _, task := range mockTasks {
+		if task.CourseID != courseID {
+			continue
+		}
+		var taskTranslations []models.TaskTranslation
+		for _, t := range mockTaskTranslations[task.ID] {
+			taskTranslations = append(taskTranslations, t)
+		}
+		sort.Slice(taskTranslations, func(i, j int) bool { return taskTranslations[i].Locale < taskTranslations[j].Locale })
+		result.Tasks = append(result.Tasks, taskTranslations...)
+	}
+	return result, nil
+}
+
+// SetCourseTranslation сохраняет перевод курса в моковых данных
+func (s *MockStorage) SetCourseTranslation(t models.CourseTranslation) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	i := mockCourseIndex(t.CourseID)
+	if i < 0 {
+		return ErrCourseNotFound
+	}
+	t.UpdatedAt = time.Now().UTC()
+	if mockCourseTranslations[t.CourseID] == nil {
+		mockCourseTranslations[t.CourseID] = make(map[string]models.CourseTranslation)
+	}
+	mockCourseTranslations[t.CourseID][t.Locale] = t
+	mockCourses[i].UpdatedAt = t.UpdatedAt
+	return nil
+}
+
+// DeleteCourseTranslation удаляет перевод курса из моковых данных
+func (s *MockStorage) DeleteCourseTranslation(courseID int, locale string) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockCourseTranslations[courseID][locale]; !ok {
+		return ErrTranslationNotFound
+	}
+	delete(mockCourseTranslations[courseID], locale)
+	mockCourses[mockCourseIndex(courseID)].UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// SetTaskTranslation сохраняет перевод задания в моковых данных
+func (s *MockStorage) SetTaskTranslation(t models.TaskTranslation) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	courseID := mockTaskCourseID(t.TaskID)
+	if courseID == 0 {
+		return ErrTaskNotFound
+	}
+	t.UpdatedAt = time.Now().UTC()
+	if mockTaskTranslations[t.TaskID] == nil {
+		mockTaskTranslations[t.TaskID] = make(map[string]models.TaskTranslation)
+	}
+	mockTaskTranslations[t.TaskID][t.Locale] = t
+	if i := mockCourseIndex(courseID); i >= 0 {
+		mockCourses[i].UpdatedAt = t.UpdatedAt
+	}
+	return nil
+}
+
+// DeleteTaskTranslation удаляет перевод задания из моковых данных
+func (s *MockStorage) DeleteTaskTranslation(taskID int, locale string) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	courseID := mockTaskCourseID(taskID)
+	if courseID == 0 {
+		return ErrTaskNotFound
+	}
+	if _, ok := mockTaskTranslations[taskID][locale]; !ok {
+		return ErrTranslationNotFound
+	}
+	delete(mockTaskTranslations[taskID], locale)
+	if i := mockCourseIndex(courseID); i >= 0 {
+		mockCourses[i].UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
+
+// mockCourseIndex возвращает индекс курса в mockCourses или -1
+func mockCourseIndex(courseID int) int {
+	for i, course := range mockCourses {
+		if course.ID == courseID {
+			return i
+		}
+	}
+	return -1
+}
+
+// mockTaskCourseID возвращает курс задания или 0, если задания нет
+func mockTaskCourseID(taskID int) int {
+	for _, task := range mockTasks {
+		if task.ID == taskID {
+			return task.CourseID
+		}
+	}
+	return 0
+}
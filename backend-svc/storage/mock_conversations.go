@@ -0,0 +1,191 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockConversations      = map[int]models.Conversation{}
+	mockNextConversationID = 1
+	mockMessages           = map[int]models.ConversationMessage{}
+	mockNextMessageID      = 1
+	// mockConversationReads — последнее прочитанное сообщение по переписке и пользователю
+	mockConversationReads = map[[2]int]int{}
+)
+
+// OpenConversation возвращает переписку из моковых данных, создавая ее при необходимости
+func (s *MockStorage) OpenConversation(courseID, studentID, instructorID int) (models.Conversation, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockCourseIndex(courseID) < 0 {
+		return models.Conversation{}, ErrCourseNotFound
+	}
+	for _, c := range mockConversations {
+		if c.CourseID == courseID && c.StudentID == studentID && c.InstructorID == instructorID {
+			return c, nil
+		}
+	}
+	now := time.Now().UTC()
+	c := models.Conversation{
+		ID:            mockNextConversationID,
+		CourseID:      courseID,
+		StudentID:     studentID,
+		InstructorID:  instructorID,
+		LastMessageAt: now,
+		CreatedAt:     now,
+	}
+	mockNextConversationID++
+	mockConversations[c.ID] = c
+	return c, nil
+}
+
+// GetConversation возвращает переписку из моковых данных
+func (s *MockStorage) GetConversation(id int) (models.Conversation, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	c, ok := mockConversations[id]
+	if !ok {
+		return models.Conversation{}, ErrConversationNotFound
+	}
+	return c, nil
+}
+
+// ListConversations возвращает переписки пользователя из моковых данных
+func (s *MockStorage) ListConversations(userID int) ([]models.Conversation, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	conversations := []models.Conversation{}
+	for _, c := range mockConversations {
+		if c.StudentID != userID && c.InstructorID != userID {
+			continue
+		}
+		lastRead := mockConversationReads[[2]int{c.ID, userID}]
+		for _, m := range mockMessages {
+			if m.ConversationID == c.ID && m.ID > lastRead && m.HiddenAt == nil && (m.SenderID == nil || *m.SenderID != userID) {
+				c.Unread++
+			}
+		}
+		conversations = append(conversations, c)
+	}
+	sort.Slice(conversations, func(i, j int) bool {
+		if !conversations[i].LastMessageAt.Equal(conversations[j].LastMessageAt) {
+			return conversations[i].LastMessageAt.After(conversations[j].LastMessageAt)
+		}
+		return conversations[i].ID > conversations[j].ID
+	})
+	return conversations, nil
+}
+
+// AddConversationMessage сохраняет сообщение в моковых данных
+func (s *MockStorage) AddConversationMessage(m models.ConversationMessage) (models.ConversationMessage, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	c, ok := mockConversations[m.ConversationID]
+	if !ok {
+		return models.ConversationMessage{}, ErrConversationNotFound
+	}
+	m.ID = mockNextMessageID
+	mockNextMessageID++
+	m.CreatedAt = time.Now().UTC()
+	mockMessages[m.ID] = m
+	c.LastMessageAt = m.CreatedAt
+	mockConversations[c.ID] = c
+	return m, nil
+}
+
+// GetConversationMessage возвращает сообщение из моковых данных
+func (s *MockStorage) GetConversationMessage(id int) (models.ConversationMessage, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	m, ok := mockMessages[id]
+	if !ok {
+		return models.ConversationMessage{}, ErrMessageNotFound
+	}
+	return m, nil
+}
+
+// ListConversationMessages возвращает страницу сообщений переписки из моковых данных
+func (s *MockStorage) ListConversationMessages(conversationID, beforeID, limit int) ([]models.ConversationMessage, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	messages := []models.ConversationMessage{}
+	for _, m := range mockMessages {
+		if m.ConversationID == conversationID && (beforeID <= 0 || m.ID < beforeID) {
+			messages = append(messages, m)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ID < messages[j].ID })
+	if limit > 0 && len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+	}
+	return messages, nil
+}
+
+// MarkConversationRead отмечает переписку прочитанной в моковых данных
+func (s *MockStorage) MarkConversationRead(conversationID, userID, messageID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	mockConversationReads[[2]int{conversationID, userID}] = messageID
+	return nil
+}
+
+// ReportConversationMessage отправляет сообщение на модерацию в моковых данных
+func (s *MockStorage) ReportConversationMessage(messageID, reporterID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	m, ok := mockMessages[messageID]
+	if !ok {
+		return ErrMessageNotFound
+	}
+	now := time.Now().UTC()
+	m.ReportedAt, m.ReportedBy = &now, &reporterID
+	mockMessages[messageID] = m
+	return nil
+}
+
+// ModerateConversationMessage скрывает или возвращает сообщение в моковых данных
+func (s *MockStorage) ModerateConversationMessage(messageID, moderatorID int, hidden bool) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	m, ok := mockMessages[messageID]
+	if !ok {
+		return ErrMessageNotFound
+	}
+	m.HiddenAt, m.HiddenBy = nil, nil
+	if hidden {
+		now := time.Now().UTC()
+		m.HiddenAt, m.HiddenBy = &now, &moderatorID
+	}
+	m.ReportedAt, m.ReportedBy = nil, nil
+	mockMessages[messageID] = m
+	return nil
+}
+
+// ListReportedMessages возвращает сообщения с жалобами из моковых данных
+func (s *MockStorage) ListReportedMessages(limit int) ([]models.ConversationMessage, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	messages := []models.ConversationMessage{}
+	for _, m := range mockMessages {
+		if m.ReportedAt != nil {
+			messages = append(messages, m)
+		}
+	}
+	sort.Slice(messages, func(i, j int) bool { return messages[i].ReportedAt.Before(*messages[j].ReportedAt) })
+	if limit > 0 && len(messages) > limit {
+		messages = messages[:limit]
+	}
+	return messages, nil
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+// mockCourseClosed сообщает, что курс задания в архиве, а пользователь его не начинал.
+// Вызывается под mockMu.
+func mockCourseClosed(userID, taskID int) bool {
+	courseID := mockTaskCourseID(taskID)
+	i := mockCourseIndex(courseID)
+	if i < 0 || mockCourses[i].ArchivedAt == nil {
+		return false
+	}
+	for _, task := range mockCourses[i].Tasks {
+		if mockUserProgress[userID].Completed[task.ID] {
+			return false
+		}
+	}
+	return true
+}
+
+// ArchiveCourse переносит курс в архив в моковых данных
+func (s *MockStorage) ArchiveCourse(courseID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	i := mockCourseIndex(courseID)
+	if i < 0 || !s.courseVisible(courseID) {
+		return ErrCourseNotFound
+	}
+	if mockCourses[i].ArchivedAt == nil {
+		now := time.Now().UTC()
+		mockCourses[i].ArchivedAt = &now
+		mockCourses[i].UpdatedAt = now
+	}
+	return nil
+}
+
+// RestoreCourse возвращает курс из архива в моковых данных
+func (s *MockStorage) RestoreCourse(courseID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	i := mockCourseIndex(courseID)
+	if i < 0 || !s.courseVisible(courseID) {
+		return ErrCourseNotFound
+	}
+	if mockCourses[i].ArchivedAt != nil {
+		mockCourses[i].ArchivedAt = nil
+		mockCourses[i].UpdatedAt = time.Now().UTC()
+	}
+	return nil
+}
+
+// PurgeCourse удаляет архивный курс с заданиями и прогрессом из моковых данных
+func (s *MockStorage) PurgeCourse(courseID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	i := mockCourseIndex(courseID)
+	if i < 0 || !s.courseVisible(courseID) {
+		return ErrCourseNotFound
+	}
+	if mockCourses[i].ArchivedAt == nil {
+		return ErrCourseNotArchived
+	}
+	mockCourses = append(mockCourses[:i], mockCourses[i+1:]...)
+	tasks := mockTasks[:0]
+	for _, task := range mockTasks {
+		if task.CourseID != courseID {
+			tasks = append(tasks, task)
+			continue
+		}
+		for _, progress := range mockUserProgress {
+			delete(progress.Completed, task.ID)
+		}
+		for _, notes := range mockTaskNotes {
+			delete(notes, task.ID)
+		}
+	}
+	mockTasks = tasks
+	delete(mockCourseOrgs, courseID)
+	delete(mockCourseCreatedAt, courseID)
+	changes := mockCourseChanges[:0]
+	for _, change := range mockCourseChanges {
+		if change.CourseID != courseID {
+			changes = append(changes, change)
+		}
+	}
+	mockCourseChanges = changes
+	delete(mockCourseVersions, courseID)
+	bookmarks := mockBookmarks[:0]
+	for _, b := range mockBookmarks {
+		if b.CourseID != courseID {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+	mockBookmarks = bookmarks
+	for _, visits := range mockCourseVisits {
+		delete(visits, courseID)
+	}
+	return nil
+}
+
+// ListArchivedCourses возвращает архивные курсы из моковых данных
+func (s *MockStorage) ListArchivedCourses() ([]models.Course, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	courses := []models.Course{}
+	for _, course := range mockCourses {
+		if course.ArchivedAt == nil || !s.courseVisible(course.ID) {
+			continue
+		}
+		course.Tasks = nil
+		fillMockCourseSummary(&course)
+		courses = append(courses, course)
+	}
+	sort.Slice(courses, func(i, j int) bool { return courses[i].ArchivedAt.After(*courses[j].ArchivedAt) })
+	return courses, nil
+}
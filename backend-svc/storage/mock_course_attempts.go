@@ -0,0 +1,95 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	mockCourseAttempts    = []models.CourseAttempt{}
+	mockNextCourseAttempt = 1
+)
+
+// mockArchiveCourseProgress переносит прогресс пользователя по курсу в архив моковых попыток.
+// Время выполнения заданий в моковых данных не хранится.
+func mockArchiveCourseProgress(userID, courseID int, resetBy *int, reason string, at time.Time) (models.CourseAttempt, error) {
+	index := mockCourseIndex(courseID)
+	if index < 0 {
+		return models.CourseAttempt{}, ErrCourseNotFound
+	}
+	attempt := models.CourseAttempt{
+		UserID:   userID,
+		CourseID: courseID,
+		ResetBy:  resetBy,
+		Reason:   reason,
+		ResetAt:  at.UTC().Truncate(time.Second),
+		Tasks:    []models.CourseAttemptTask{},
+	}
+	for _, task := range mockCourses[index].Tasks {
+		attempt.TasksTotal++
+		scorm, started := mockScormAttempts[userID][task.ID]
+		completed := mockUserProgress[userID].Completed[task.ID]
+		if !completed && !started {
+			continue
+		}
+		archived := models.CourseAttemptTask{TaskID: task.ID, ScormStatus: scorm.Status, ScormScore: scorm.Score}
+		if completed {
+			attempt.TasksCompleted++
+			archived.CompletedAt = scorm.CompletedAt
+		}
+		attempt.Tasks = append(attempt.Tasks, archived)
+	}
+	if len(attempt.Tasks) == 0 {
+		return attempt, ErrNoCourseProgress
+	}
+	for _, task := range attempt.Tasks {
+		delete(mockUserProgress[userID].Completed, task.TaskID)
+		delete(mockScormAttempts[userID], task.TaskID)
+	}
+
+	attempt.ID = mockNextCourseAttempt
+	mockNextCourseAttempt++
+	for _, previous := range mockCourseAttempts {
+		if previous.UserID == userID && previous.CourseID == courseID {
+			attempt.Number++
+		}
+	}
+	attempt.Number++
+	mockCourseAttempts = append(mockCourseAttempts, attempt)
+	return attempt, nil
+}
+
+// ResetCourseProgress переносит прогресс курса в архив попыток в моковых данных
+func (s *MockStorage) ResetCourseProgress(userID, courseID int, resetBy *int, reason string) (models.CourseAttempt, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if !s.courseVisible(courseID) {
+		return models.CourseAttempt{}, ErrCourseNotFound
+	}
+	attempt, err := mockArchiveCourseProgress(userID, courseID, resetBy, reason, time.Now())
+	if err != nil {
+		return attempt, err
+	}
+	key := mockCompletionKey{userID, courseID}
+	if completion, ok := mockCourseCompletions[key]; ok && completion.ExpiredAt == nil {
+		resetAt := attempt.ResetAt
+		completion.ExpiredAt = &resetAt
+		mockCourseCompletions[key] = completion
+	}
+	return attempt, nil
+}
+
+// ListCourseAttempts возвращает архивные попытки из моковых данных
+func (s *MockStorage) ListCourseAttempts(userID, courseID int) ([]models.CourseAttempt, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	attempts := []models.CourseAttempt{}
+	for _, attempt := range mockCourseAttempts {
+		if attempt.UserID == userID && attempt.CourseID == courseID {
+			attempts = append(attempts, attempt)
+		}
+	}
+	return attempts, nil
+}
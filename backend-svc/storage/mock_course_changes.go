@@ -0,0 +1,178 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	// mockCourseChanges — история содержимого курсов в порядке добавления
+	mockCourseChanges    []models.CourseChange
+	mockNextCourseChange = 1
+	// mockCourseVersions — текущая версия содержимого по ID курса
+	mockCourseVersions = map[int]int{}
+	// mockCourseVisits — пользователь → курс → последняя просмотренная версия
+	mockCourseVisits = map[int]map[int]int{}
+)
+
+// mockRecordCourseChange добавляет запись в историю курса; mockMu должен быть захвачен
+func mockRecordCourseChange(change models.CourseChange) {
+	mockCourseVersions[change.CourseID]++
+	change.ID = mockNextCourseChange
+	mockNextCourseChange++
+	change.Version = mockCourseVersions[change.CourseID]
+	change.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	mockCourseChanges = append(mockCourseChanges, change)
+	if i := mockCourseIndex(change.CourseID); i >= 0 {
+		mockCourses[i].UpdatedAt = change.CreatedAt
+	}
+	appendMockEvent(models.EventCourseContentChanged, 0, map[string]int{
+		"courseId": change.CourseID, "version": change.Version,
+	})
+}
+
+// mockCourseStarted сообщает, выполнил ли пользователь хотя бы одно задание курса; mockMu должен быть захвачен
+func mockCourseStarted(userID, courseID int) bool {
+	for taskID, done := range mockUserProgress[userID].Completed {
+		if done && mockTaskCourseID(taskID) == courseID {
+			return true
+		}
+	}
+	return false
+}
+
+// mockSeenCourseVersion — версия курса, которую видел пользователь. Время выполнения заданий
+// в моковых данных не хранится, поэтому без отметки о посещении начатого курса новыми
+// считаются все изменения. mockMu должен быть захвачен.
+func mockSeenCourseVersion(userID, courseID int) int {
+	if seen, ok := mockCourseVisits[userID][courseID]; ok {
+		return seen
+	}
+	if mockCourseStarted(userID, courseID) {
+		return 0
+	}
+	return mockCourseVersions[courseID]
+}
+
+// AddTask добавляет задание в курс в моковых данных
+func (s *MockStorage) AddTask(task models.Task) (models.Task, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	i := mockCourseIndex(task.CourseID)
+	if i < 0 || !s.courseVisible(task.CourseID) {
+		return models.Task{}, ErrCourseNotFound
+	}
+	if mockCourses[i].ArchivedAt != nil {
+		return models.Task{}, ErrCourseArchived
+	}
+	task.ID = 1
+	for _, t := range mockTasks {
+		if t.ID >= task.ID {
+			task.ID = t.ID + 1
+		}
+	}
+	if task.Order == 0 {
+		for _, t := range mockCourses[i].Tasks {
+			if t.Order >= task.Order {
+				task.Order = t.Order + 1
+			}
+		}
+	}
+	mockTasks = append(mockTasks, task)
+	mockCourses[i].Tasks = append(mockCourses[i].Tasks, task)
+	mockCourses[i].TasksCount = len(mockCourses[i].Tasks)
+	mockRecordCourseChange(models.CourseChange{
+		CourseID: task.CourseID, Kind: models.CourseChangeTaskAdded, TaskID: &task.ID, Title: task.Title,
+	})
+	return task, nil
+}
+
+// UpdateTask меняет задание курса в моковых данных
+func (s *MockStorage) UpdateTask(task models.Task) (models.Task, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	i := mockCourseIndex(task.CourseID)
+	if i < 0 || !s.courseVisible(task.CourseID) || mockTaskCourseID(task.ID) != task.CourseID {
+		return models.Task{}, ErrTaskNotFound
+	}
+	if mockCourses[i].ArchivedAt != nil {
+		return models.Task{}, ErrCourseArchived
+	}
+	for j, t := range mockCourses[i].Tasks {
+		if t.ID == task.ID {
+			if task.Order == 0 {
+				task.Order = t.Order
+			}
+			mockCourses[i].Tasks[j] = task
+		}
+	}
+	for j, t := range mockTasks {
+		if t.ID == task.ID {
+			mockTasks[j] = task
+		}
+	}
+	mockRecordCourseChange(models.CourseChange{
+		CourseID: task.CourseID, Kind: models.CourseChangeTaskUpdated, TaskID: &task.ID, Title: task.Title,
+	})
+	return task, nil
+}
+
+// GetCourseChangelog возвращает историю курса из моковых данных
+func (s *MockStorage) GetCourseChangelog(courseID, userID, limit int) (models.CourseChangelog, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	log := models.CourseChangelog{CourseID: courseID, Changes: []models.CourseChange{}}
+	if mockCourseIndex(courseID) < 0 {
+		return log, ErrCourseNotFound
+	}
+	log.Version = mockCourseVersions[courseID]
+	log.SeenVersion = mockSeenCourseVersion(userID, courseID)
+	for i := len(mockCourseChanges) - 1; i >= 0 && len(log.Changes) < limit; i-- {
+		if mockCourseChanges[i].CourseID == courseID {
+			log.Changes = append(log.Changes, mockCourseChanges[i])
+		}
+	}
+	return log, nil
+}
+
+// ListUnseenCourseChanges возвращает непросмотренные изменения начатых курсов из моковых данных
+func (s *MockStorage) ListUnseenCourseChanges(userID, limit int) ([]models.CourseChange, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	changes := []models.CourseChange{}
+	for _, change := range mockCourseChanges {
+		if mockCourseStarted(userID, change.CourseID) && change.Version > mockSeenCourseVersion(userID, change.CourseID) {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].CourseID != changes[j].CourseID {
+			return changes[i].CourseID < changes[j].CourseID
+		}
+		return changes[i].Version > changes[j].Version
+	})
+	if len(changes) > limit {
+		changes = changes[:limit]
+	}
+	return changes, nil
+}
+
+// MarkCourseChangesSeen отмечает посещение курса в моковых данных
+func (s *MockStorage) MarkCourseChangesSeen(userID, courseID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockCourseIndex(courseID) < 0 {
+		return ErrCourseNotFound
+	}
+	if mockCourseVisits[userID] == nil {
+		mockCourseVisits[userID] = map[int]int{}
+	}
+	mockCourseVisits[userID][courseID] = mockCourseVersions[courseID]
+	return nil
+}
@@ -0,0 +1,58 @@
+package storage
+
+import "lmsmodule/backend-svc/models"
+
+// fillMockCourseSummary вычисляет показатели сводки курса по моковым данным; вызывается под mockMu
+func fillMockCourseSummary(course *models.Course) {
+	difficulty := map[string]float64{"easy": 1, "medium": 2}
+	taskCourse := make(map[int]int)
+	var total float64
+	var tasks int
+	for _, task := range mockTasks {
+		taskCourse[task.ID] = task.CourseID
+		if task.CourseID != course.ID {
+			continue
+		}
+		score, ok := difficulty[task.Difficulty]
+		if !ok {
+			score = 3
+		}
+		total += score
+		tasks++
+	}
+	course.AverageDifficulty = nil
+	if tasks > 0 {
+		avg := total / float64(tasks)
+		course.AverageDifficulty = &avg
+	}
+
+	course.Enrollments = 0
+	for _, progress := range mockUserProgress {
+		for taskID, done := range progress.Completed {
+			if done && taskCourse[taskID] == course.ID {
+				course.Enrollments++
+				break
+			}
+		}
+	}
+
+	course.Reviews, course.AverageRating = 0, nil
+	var ratings int
+	for _, review := range mockReviews {
+		if review.CourseID == course.ID && review.HiddenAt == nil {
+			course.Reviews++
+			ratings += review.Rating
+		}
+	}
+	if course.Reviews > 0 {
+		avg := float64(ratings) / float64(course.Reviews)
+		course.AverageRating = &avg
+	}
+}
+
+// RefreshCourseSummaries ничего не пересчитывает: мок вычисляет сводку при чтении
+func (s *MockStorage) RefreshCourseSummaries() (int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+	return len(mockCourses), nil
+}
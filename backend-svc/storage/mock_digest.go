@@ -0,0 +1,139 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+type mockDigestToken struct {
+	userID    int
+	createdAt time.Time
+}
+
+var (
+	mockDigestSettings  = map[int]models.DigestSettings{}
+	mockDigestTokens    = map[string]mockDigestToken{}
+	mockCourseCreatedAt = map[int]time.Time{}
+)
+
+// mockDigestSettingsOf возвращает настройки сводки пользователя; вызывается под mockMu
+func mockDigestSettingsOf(userID int) models.DigestSettings {
+	settings, ok := mockDigestSettings[userID]
+	if !ok {
+		return models.DigestSettings{Enabled: true}
+	}
+	return settings
+}
+
+// GetDigestSettings возвращает подписку на сводку из моковых данных
+func (s *MockStorage) GetDigestSettings(userID int) (models.DigestSettings, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return mockDigestSettingsOf(userID), nil
+}
+
+// SetDigestEnabled изменяет подписку на сводку в моковых данных
+func (s *MockStorage) SetDigestEnabled(userID int, enabled bool) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	settings := mockDigestSettingsOf(userID)
+	settings.Enabled = enabled
+	mockDigestSettings[userID] = settings
+	return nil
+}
+
+// UnsubscribeDigest отключает сводку по токену в моковых данных
+func (s *MockStorage) UnsubscribeDigest(tokenHash string) (int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	token, ok := mockDigestTokens[tokenHash]
+	if !ok || token.createdAt.Before(time.Now().UTC().Add(-digestTokenRetention)) {
+		return 0, ErrDigestTokenInvalid
+	}
+	settings := mockDigestSettingsOf(token.userID)
+	settings.Enabled = false
+	mockDigestSettings[token.userID] = settings
+	return token.userID, nil
+}
+
+// ListDigestRecipients возвращает получателей сводки из моковых данных; время регистрации
+// моковых пользователей неизвестно, поэтому оно не учитывается
+func (s *MockStorage) ListDigestRecipients(slot time.Time) ([]int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	ids := []int{}
+	for id, user := range mockUsers {
+		if !user.IsActive || user.DeletionScheduledAt != nil || user.Email == "" {
+			continue
+		}
+		settings := mockDigestSettingsOf(id)
+		if !settings.Enabled || (settings.LastDigestAt != nil && !settings.LastDigestAt.Before(slot)) {
+			continue
+		}
+		ids = append(ids, id)
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// MarkDigestSent запоминает отправку сводки в моковых данных
+func (s *MockStorage) MarkDigestSent(userID int, tokenHash string, at time.Time) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	at = at.UTC()
+	settings := mockDigestSettingsOf(userID)
+	settings.LastDigestAt = &at
+	mockDigestSettings[userID] = settings
+	for hash, token := range mockDigestTokens {
+		if token.userID == userID && token.createdAt.Before(at.Add(-digestTokenRetention)) {
+			delete(mockDigestTokens, hash)
+		}
+	}
+	if tokenHash != "" {
+		mockDigestTokens[tokenHash] = mockDigestToken{userID: userID, createdAt: at}
+	}
+	return nil
+}
+
+// ListCoursesCreatedBetween возвращает курсы, созданные в интервале, из моковых данных
+func (s *MockStorage) ListCoursesCreatedBetween(from, to time.Time) ([]models.Course, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	courses := []models.Course{}
+	for _, course := range mockCourses {
+		createdAt, ok := mockCourseCreatedAt[course.ID]
+		if !ok || createdAt.Before(from) || !createdAt.Before(to) {
+			continue
+		}
+		course.Tasks = nil
+		courses = append(courses, course)
+	}
+	sort.Slice(courses, func(i, j int) bool {
+		return mockCourseCreatedAt[courses[i].ID].Before(mockCourseCreatedAt[courses[j].ID])
+	})
+	return courses, nil
+}
+
+// CountCompletionsByUser считает выполненные задания в моковых данных; время выполнения
+// там не хранится, поэтому учитываются все выполненные задания
+func (s *MockStorage) CountCompletionsByUser(from, to time.Time) (map[int]int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	counts := map[int]int{}
+	for id, progress := range mockUserProgress {
+		for _, done := range progress.Completed {
+			if done {
+				counts[id]++
+			}
+		}
+	}
+	return counts, nil
+}
@@ -0,0 +1,214 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockThreads       = map[int]models.DiscussionThread{}
+	mockNextThreadID  = 1
+	mockComments      = []models.DiscussionComment{}
+	mockNextCommentID = 1
+	mockReviews       = map[int]models.CourseReview{}
+	mockNextReviewID  = 1
+)
+
+// mockThreadWithCount дополняет тему числом сообщений. Вызывается под mockMu.
+func mockThreadWithCount(thread models.DiscussionThread) models.DiscussionThread {
+	thread.CommentCount = 0
+	for _, comment := range mockComments {
+		if comment.ThreadID == thread.ID {
+			thread.CommentCount++
+		}
+	}
+	return thread
+}
+
+// CreateThread создает тему обсуждения в моковых данных
+func (s *MockStorage) CreateThread(thread models.DiscussionThread, body string) (models.DiscussionThread, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockCourseIndex(thread.CourseID) < 0 {
+		return models.DiscussionThread{}, ErrCourseNotFound
+	}
+	now := time.Now().UTC()
+	thread.ID = mockNextThreadID
+	mockNextThreadID++
+	thread.CreatedAt, thread.LastCommentAt = now, now
+	mockThreads[thread.ID] = thread
+
+	mockComments = append(mockComments, models.DiscussionComment{
+		ID: mockNextCommentID, ThreadID: thread.ID, AuthorID: thread.AuthorID, Body: body, CreatedAt: now,
+	})
+	appendMockCommentEvent(mockNextCommentID, thread)
+	mockNextCommentID++
+	return mockThreadWithCount(thread), nil
+}
+
+// ListThreads возвращает темы курса из моковых данных
+func (s *MockStorage) ListThreads(courseID int) ([]models.DiscussionThread, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	threads := []models.DiscussionThread{}
+	for _, thread := range mockThreads {
+		if thread.CourseID == courseID {
+			threads = append(threads, mockThreadWithCount(thread))
+		}
+	}
+	sort.Slice(threads, func(i, j int) bool {
+		if !threads[i].LastCommentAt.Equal(threads[j].LastCommentAt) {
+			return threads[i].LastCommentAt.After(threads[j].LastCommentAt)
+		}
+		return threads[i].ID > threads[j].ID
+	})
+	return threads, nil
+}
+
+// GetThread возвращает тему обсуждения из моковых данных
+func (s *MockStorage) GetThread(id int) (models.DiscussionThread, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	thread, ok := mockThreads[id]
+	if !ok {
+		return models.DiscussionThread{}, ErrThreadNotFound
+	}
+	return mockThreadWithCount(thread), nil
+}
+
+// SetThreadLock закрывает или открывает тему в моковых данных
+func (s *MockStorage) SetThreadLock(threadID, moderatorID int, locked bool) (models.DiscussionThread, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	thread, ok := mockThreads[threadID]
+	if !ok {
+		return models.DiscussionThread{}, ErrThreadNotFound
+	}
+	thread.LockedAt, thread.LockedBy = nil, nil
+	if locked {
+		now := time.Now().UTC()
+		thread.LockedAt, thread.LockedBy = &now, &moderatorID
+	}
+	mockThreads[threadID] = thread
+	return mockThreadWithCount(thread), nil
+}
+
+// ListThreadComments возвращает сообщения темы из моковых данных
+func (s *MockStorage) ListThreadComments(threadID int) ([]models.DiscussionComment, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	comments := []models.DiscussionComment{}
+	for _, comment := range mockComments {
+		if comment.ThreadID == threadID {
+			comments = append(comments, comment)
+		}
+	}
+	return comments, nil
+}
+
+// AddComment сохраняет сообщение в теме в моковых данных
+func (s *MockStorage) AddComment(comment models.DiscussionComment) (models.DiscussionComment, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	thread, ok := mockThreads[comment.ThreadID]
+	if !ok {
+		return models.DiscussionComment{}, ErrThreadNotFound
+	}
+	comment.ID = mockNextCommentID
+	mockNextCommentID++
+	comment.CreatedAt = time.Now().UTC()
+	mockComments = append(mockComments, comment)
+	thread.LastCommentAt = comment.CreatedAt
+	mockThreads[thread.ID] = thread
+	thread.AuthorID = comment.AuthorID
+	appendMockCommentEvent(comment.ID, thread)
+	return comment, nil
+}
+
+// appendMockCommentEvent добавляет в моковый outbox событие о сообщении автора thread.AuthorID
+func appendMockCommentEvent(commentID int, thread models.DiscussionThread) {
+	aggregateID := 0
+	if thread.AuthorID != nil {
+		aggregateID = *thread.AuthorID
+	}
+	appendMockEvent(models.EventCommentCreated, aggregateID, map[string]int{
+		"commentId": commentID,
+		"threadId":  thread.ID,
+		"courseId":  thread.CourseID,
+	})
+}
+
+// GetComment возвращает сообщение обсуждения из моковых данных
+func (s *MockStorage) GetComment(id int) (models.DiscussionComment, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for _, comment := range mockComments {
+		if comment.ID == id {
+			return comment, nil
+		}
+	}
+	return models.DiscussionComment{}, ErrCommentNotFound
+}
+
+// SaveReview создает или заменяет отзыв о курсе в моковых данных
+func (s *MockStorage) SaveReview(review models.CourseReview) (models.CourseReview, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockCourseIndex(review.CourseID) < 0 {
+		return models.CourseReview{}, ErrCourseNotFound
+	}
+	now := time.Now().UTC()
+	for id, existing := range mockReviews {
+		if existing.CourseID == review.CourseID && *existing.UserID == *review.UserID {
+			existing.Rating, existing.Body, existing.UpdatedAt = review.Rating, review.Body, now
+			mockReviews[id] = existing
+			return existing, nil
+		}
+	}
+	review.ID = mockNextReviewID
+	mockNextReviewID++
+	review.CreatedAt, review.UpdatedAt = now, now
+	mockReviews[review.ID] = review
+	return review, nil
+}
+
+// ListCourseReviews возвращает отзывы о курсе из моковых данных
+func (s *MockStorage) ListCourseReviews(courseID int) ([]models.CourseReview, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	reviews := []models.CourseReview{}
+	for _, review := range mockReviews {
+		if review.CourseID == courseID {
+			reviews = append(reviews, review)
+		}
+	}
+	sort.Slice(reviews, func(i, j int) bool {
+		if !reviews[i].UpdatedAt.Equal(reviews[j].UpdatedAt) {
+			return reviews[i].UpdatedAt.After(reviews[j].UpdatedAt)
+		}
+		return reviews[i].ID > reviews[j].ID
+	})
+	return reviews, nil
+}
+
+// GetReview возвращает отзыв из моковых данных
+func (s *MockStorage) GetReview(id int) (models.CourseReview, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	review, ok := mockReviews[id]
+	if !ok {
+		return models.CourseReview{}, ErrReviewNotFound
+	}
+	return review, nil
+}
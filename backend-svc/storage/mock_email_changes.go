@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	mockEmailChanges      = map[int64]models.EmailChange{}
+	mockEmailChangeNextID int64
+)
+
+// CreateEmailChange сохраняет запрос на смену email в моковых данных
+func (s *MockStorage) CreateEmailChange(change models.EmailChange) (int64, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	now := time.Now().UTC()
+	for id, existing := range mockEmailChanges {
+		if existing.UserID == change.UserID && existing.ConfirmedAt == nil && existing.CancelledAt == nil {
+			existing.CancelledAt = &now
+			mockEmailChanges[id] = existing
+		}
+	}
+
+	mockEmailChangeNextID++
+	change.ID = mockEmailChangeNextID
+	change.CreatedAt = now
+	mockEmailChanges[change.ID] = change
+	return change.ID, nil
+}
+
+func findMockEmailChange(match func(models.EmailChange) bool) (models.EmailChange, bool) {
+	for _, change := range mockEmailChanges {
+		if match(change) {
+			return change, true
+		}
+	}
+	return models.EmailChange{}, false
+}
+
+// ConfirmEmailChange применяет новый адрес в моковых данных
+func (s *MockStorage) ConfirmEmailChange(tokenHash string) (models.EmailChange, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	now := time.Now().UTC()
+	change, ok := findMockEmailChange(func(c models.EmailChange) bool { return c.ConfirmTokenHash == tokenHash })
+	if !ok || change.ConfirmedAt != nil || change.CancelledAt != nil || !change.ExpiresAt.After(now) {
+		return models.EmailChange{}, ErrEmailChangeInvalid
+	}
+	user, exists := mockUsers[change.UserID]
+	if !exists {
+		return models.EmailChange{}, ErrUserNotFound
+	}
+	for id, other := range mockUsers {
+		if id != user.ID && strings.EqualFold(other.Email, change.NewEmail) {
+			return models.EmailChange{}, ErrDuplicateEmail
+		}
+	}
+
+	user.Email = change.NewEmail
+	user.Version++
+	mockUsers[user.ID] = user
+	change.ConfirmedAt = &now
+	mockEmailChanges[change.ID] = change
+	appendMockEvent(models.EventUserEmailChanged, user.ID, map[string]interface{}{
+		"userId":   user.ID,
+		"oldEmail": change.OldEmail,
+		"newEmail": change.NewEmail,
+	})
+	return change, nil
+}
+
+// CancelEmailChange отменяет или откатывает смену email в моковых данных
+func (s *MockStorage) CancelEmailChange(tokenHash string) (models.EmailChange, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	now := time.Now().UTC()
+	change, ok := findMockEmailChange(func(c models.EmailChange) bool { return c.CancelTokenHash == tokenHash })
+	if !ok || change.CancelledAt != nil || !change.CancelExpiresAt.After(now) {
+		return models.EmailChange{}, ErrEmailChangeInvalid
+	}
+	change.CancelledAt = &now
+	mockEmailChanges[change.ID] = change
+	if change.ConfirmedAt == nil {
+		return change, nil
+	}
+
+	if user, exists := mockUsers[change.UserID]; exists {
+		user.Email = change.OldEmail
+		user.Version++
+		mockUsers[user.ID] = user
+	}
+	state := mockSessions[change.UserID]
+	state.MustChangePassword = true
+	mockSessions[change.UserID] = state
+	for id, device := range mockTrustedDevices {
+		if device.UserID == change.UserID {
+			delete(mockTrustedDevices, id)
+		}
+	}
+	appendMockEvent(models.EventUserEmailChanged, change.UserID, map[string]interface{}{
+		"userId":   change.UserID,
+		"oldEmail": change.NewEmail,
+		"newEmail": change.OldEmail,
+		"reason":   "reverted_by_owner",
+	})
+	return change, nil
+}
+
+// ListEmailChanges возвращает историю смены email из моковых данных
+func (s *MockStorage) ListEmailChanges(userID int) ([]models.EmailChange, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	changes := []models.EmailChange{}
+	for _, change := range mockEmailChanges {
+		if change.UserID == userID {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID > changes[j].ID })
+	return changes, nil
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"sort"
+
+	"lmsmodule/backend-svc/models"
+)
+
+// EachUserSummary передает fn моковых пользователей в порядке ID. Список копируется под
+// mockMu, чтобы fn могла обращаться к хранилищу.
+func (s *MockStorage) EachUserSummary(fn func(models.UserSummary) error) error {
+	users, err := s.ListUserSummaries()
+	if err != nil {
+		return err
+	}
+	for _, user := range users {
+		if err := fn(user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EachProgressRecord передает fn выполненные задания из моковых данных; время выполнения
+// моковый прогресс не хранит
+func (s *MockStorage) EachProgressRecord(filter models.ProgressExportFilter, fn func(models.ProgressRecord) error) error {
+	mockMu.Lock()
+	var records []models.ProgressRecord
+	for userID, progress := range mockUserProgress {
+		user, ok := mockUsers[userID]
+		if !ok || !s.userVisible(userID) {
+			continue
+		}
+		for _, task := range mockTasks {
+			if !progress.Completed[task.ID] || (filter.CourseID != 0 && filter.CourseID != task.CourseID) {
+				continue
+			}
+			record := models.ProgressRecord{
+				UserID: userID, Username: user.Username,
+				CourseID: task.CourseID, TaskID: task.ID, TaskTitle: task.Title,
+			}
+			for _, course := range mockCourses {
+				if course.ID == task.CourseID {
+					record.CourseTitle = course.VulnerabilityType
+				}
+			}
+			records = append(records, record)
+		}
+	}
+	mockMu.Unlock()
+
+	sort.Slice(records, func(i, j int) bool {
+		if records[i].UserID != records[j].UserID {
+			return records[i].UserID < records[j].UserID
+		}
+		return records[i].TaskID < records[j].TaskID
+	})
+	for _, record := range records {
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EachComplianceEntry передает fn строки отчета по моковым данным
+func (s *MockStorage) EachComplianceEntry(filter models.ComplianceFilter, fn func(models.ComplianceEntry) error) error {
+	entries, err := s.ComplianceReport(filter)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := fn(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sync"
+	"time"
+)
+
+var (
+	mockFlagsMu sync.Mutex
+	mockFlags   = map[string]models.FeatureFlag{}
+)
+
+// GetFeatureFlags возвращает флаги функциональности из моковых данных
+func (s *MockStorage) GetFeatureFlags() ([]models.FeatureFlag, error) {
+	mockFlagsMu.Lock()
+	defer mockFlagsMu.Unlock()
+
+	flags := make([]models.FeatureFlag, 0, len(mockFlags))
+	for _, flag := range mockFlags {
+		flags = append(flags, flag)
+	}
+	return flags, nil
+}
+
+// UpsertFeatureFlag сохраняет флаг функциональности в моковых данных
+func (s *MockStorage) UpsertFeatureFlag(flag models.FeatureFlag) error {
+	mockFlagsMu.Lock()
+	defer mockFlagsMu.Unlock()
+
+	flag.UpdatedAt = time.Now()
+	mockFlags[flag.Key] = flag
+	return nil
+}
+
+// DeleteFeatureFlag удаляет флаг функциональности из моковых данных
+func (s *MockStorage) DeleteFeatureFlag(key string) error {
+	mockFlagsMu.Lock()
+	defer mockFlagsMu.Unlock()
+
+	delete(mockFlags, key)
+	return nil
+}
@@ -0,0 +1,14 @@
+package storage
+
+import "lmsmodule/backend-svc/models"
+
+// ListUserSummaryFields возвращает пользователей из моковых данных; проекция полей
+// для мока не имеет смысла, лишние поля отбрасываются при формировании ответа
+func (s *MockStorage) ListUserSummaryFields(fields []string) ([]models.UserSummary, error) {
+	return s.ListUserSummaries()
+}
+
+// GetCourseFields возвращает каталог курсов из моковых данных
+func (s *MockStorage) GetCourseFields(fields []string) ([]models.Course, error) {
+	return s.GetCourses()
+}
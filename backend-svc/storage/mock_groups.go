@@ -0,0 +1,92 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	mockGroupsMu sync.Mutex
+	// mockGroupMembers — название группы -> множество ID участников
+	mockGroupMembers = map[string]map[int]bool{
+		"beta-testers": {1: true},
+		"instructors":  {2: true},
+	}
+)
+
+// GetUserGroups возвращает группы пользователя из моковых данных
+func (s *MockStorage) GetUserGroups(userID int) ([]string, error) {
+	mockGroupsMu.Lock()
+	defer mockGroupsMu.Unlock()
+
+	var groups []string
+	for name, members := range mockGroupMembers {
+		if members[userID] {
+			groups = append(groups, name)
+		}
+	}
+	sort.Strings(groups)
+	return groups, nil
+}
+
+// mockGroupExists сообщает, есть ли группа в моковых данных
+func mockGroupExists(group string) bool {
+	mockGroupsMu.Lock()
+	defer mockGroupsMu.Unlock()
+	_, ok := mockGroupMembers[group]
+	return ok
+}
+
+// GroupLeaderboard возвращает таблицу лидеров группы из моковых данных. Моковые данные не
+// хранят время выполнения заданий, поэтому учитываются все выполненные задания.
+func (s *MockStorage) GroupLeaderboard(group string, from, to time.Time, limit int) ([]models.LeaderboardEntry, error) {
+	mockGroupsMu.Lock()
+	members, ok := mockGroupMembers[group]
+	userIDs := make([]int, 0, len(members))
+	for userID := range members {
+		userIDs = append(userIDs, userID)
+	}
+	mockGroupsMu.Unlock()
+	if !ok {
+		return nil, ErrGroupNotFound
+	}
+
+	mockMu.Lock()
+	defer mockMu.Unlock()
+	entries := []models.LeaderboardEntry{}
+	for _, userID := range userIDs {
+		completed := 0
+		for _, done := range mockUserProgress[userID].Completed {
+			if done {
+				completed++
+			}
+		}
+		if user, ok := mockUsers[userID]; ok && completed > 0 {
+			entries = append(entries, models.LeaderboardEntry{UserID: userID, Username: user.Username, TasksCompleted: completed})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].TasksCompleted != entries[j].TasksCompleted {
+			return entries[i].TasksCompleted > entries[j].TasksCompleted
+		}
+		return entries[i].Username < entries[j].Username
+	})
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	return entries, nil
+}
+
+// IsGroupMember проверяет участие в группе по моковым данным
+func (s *MockStorage) IsGroupMember(group string, userID int) (bool, error) {
+	mockGroupsMu.Lock()
+	defer mockGroupsMu.Unlock()
+
+	members, ok := mockGroupMembers[group]
+	if !ok {
+		return false, ErrGroupNotFound
+	}
+	return members[userID], nil
+}
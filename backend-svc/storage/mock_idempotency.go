@@ -0,0 +1,49 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sync"
+	"time"
+)
+
+var (
+	mockIdempotencyMu sync.Mutex
+	mockIdempotency   = map[[2]string]models.IdempotentResponse{}
+)
+
+// GetIdempotentResponse возвращает сохраненный ответ из моковых данных
+func (s *MockStorage) GetIdempotentResponse(scope, key string) (models.IdempotentResponse, error) {
+	mockIdempotencyMu.Lock()
+	defer mockIdempotencyMu.Unlock()
+
+	resp, exists := mockIdempotency[[2]string{scope, key}]
+	if !exists {
+		return models.IdempotentResponse{}, ErrIdempotencyKeyNotFound
+	}
+	return resp, nil
+}
+
+// SaveIdempotentResponse сохраняет ответ в моковых данных
+func (s *MockStorage) SaveIdempotentResponse(resp models.IdempotentResponse) error {
+	mockIdempotencyMu.Lock()
+	defer mockIdempotencyMu.Unlock()
+
+	resp.CreatedAt = time.Now()
+	mockIdempotency[[2]string{resp.Scope, resp.Key}] = resp
+	return nil
+}
+
+// PurgeIdempotencyKeys удаляет устаревшие ответы из моковых данных
+func (s *MockStorage) PurgeIdempotencyKeys(before time.Time) (int64, error) {
+	mockIdempotencyMu.Lock()
+	defer mockIdempotencyMu.Unlock()
+
+	var purged int64
+	for k, resp := range mockIdempotency {
+		if resp.CreatedAt.Before(before) {
+			delete(mockIdempotency, k)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,159 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockInvitations      = map[int]models.Invitation{}
+	mockNextInvitation   = 1
+	mockCourseAssignment = map[int][]models.CourseAssignment{}
+)
+
+// mockInvitationView дополняет приглашение названием организации; mockMu должен быть захвачен
+func mockInvitationView(invitation models.Invitation) models.Invitation {
+	invitation.OrganizationName = mockOrganizations[invitation.OrganizationID].Name
+	invitation.CourseIDs = append([]int{}, invitation.CourseIDs...)
+	return invitation
+}
+
+// CreateInvitation сохраняет приглашение в моковых данных
+func (s *MockStorage) CreateInvitation(invitation models.Invitation) (models.Invitation, error) {
+	if invitation.Group != "" && !mockGroupExists(invitation.Group) {
+		return models.Invitation{}, ErrGroupNotFound
+	}
+
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockOrganizations[invitation.OrganizationID]; !ok {
+		return models.Invitation{}, ErrOrganizationNotFound
+	}
+	for _, courseID := range invitation.CourseIDs {
+		i := mockCourseIndex(courseID)
+		if i < 0 || mockCourseOrganization(courseID) != invitation.OrganizationID {
+			return models.Invitation{}, ErrCourseNotFound
+		}
+		if mockCourses[i].ArchivedAt != nil {
+			return models.Invitation{}, ErrCourseArchived
+		}
+	}
+	invitation.ID = mockNextInvitation
+	mockNextInvitation++
+	invitation.Uses = 0
+	invitation.CreatedAt = time.Now().UTC()
+	invitation.RevokedAt = nil
+	invitation.CourseIDs = append([]int{}, invitation.CourseIDs...)
+	sort.Ints(invitation.CourseIDs)
+	mockInvitations[invitation.ID] = invitation
+	return mockInvitationView(invitation), nil
+}
+
+// ListInvitations возвращает приглашения из моковых данных, новые первыми
+func (s *MockStorage) ListInvitations(orgID int) ([]models.Invitation, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	invitations := []models.Invitation{}
+	for _, invitation := range mockInvitations {
+		if orgID == 0 || invitation.OrganizationID == orgID {
+			invitations = append(invitations, mockInvitationView(invitation))
+		}
+	}
+	sort.Slice(invitations, func(i, j int) bool { return invitations[i].ID > invitations[j].ID })
+	return invitations, nil
+}
+
+// mockInvitationByToken возвращает приглашение по хешу токена; mockMu должен быть захвачен
+func mockInvitationByToken(tokenHash string) (models.Invitation, error) {
+	for _, invitation := range mockInvitations {
+		if invitation.TokenHash == tokenHash {
+			return invitation, invitationUsable(invitation, time.Now())
+		}
+	}
+	return models.Invitation{}, ErrInvitationNotFound
+}
+
+// GetInvitation возвращает действующее приглашение из моковых данных
+func (s *MockStorage) GetInvitation(tokenHash string) (models.Invitation, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	invitation, err := mockInvitationByToken(tokenHash)
+	return mockInvitationView(invitation), err
+}
+
+// RevokeInvitation отзывает приглашение в моковых данных
+func (s *MockStorage) RevokeInvitation(id, orgID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	invitation, ok := mockInvitations[id]
+	if !ok || (orgID != 0 && invitation.OrganizationID != orgID) {
+		return ErrInvitationNotFound
+	}
+	if invitation.RevokedAt == nil {
+		now := time.Now().UTC()
+		invitation.RevokedAt = &now
+		mockInvitations[id] = invitation
+	}
+	return nil
+}
+
+// RegisterWithInvitation регистрирует пользователя по приглашению в моковых данных
+func (s *MockStorage) RegisterWithInvitation(tokenHash string, user models.User) (int, error) {
+	mockMu.Lock()
+	invitation, err := mockInvitationByToken(tokenHash)
+	if err != nil {
+		mockMu.Unlock()
+		return 0, err
+	}
+	userID, err := mockInsertUser(user, invitation.OrganizationID, true)
+	if err != nil {
+		mockMu.Unlock()
+		return 0, err
+	}
+	invitation.Uses++
+	mockInvitations[invitation.ID] = invitation
+
+	now := time.Now().UTC()
+	for _, courseID := range invitation.CourseIDs {
+		if i := mockCourseIndex(courseID); i < 0 || mockCourses[i].ArchivedAt != nil {
+			continue
+		}
+		id := invitation.ID
+		mockCourseAssignment[userID] = append(mockCourseAssignment[userID], models.CourseAssignment{
+			CourseID: courseID, AssignedAt: now, InvitationID: &id,
+		})
+	}
+	mockMu.Unlock()
+
+	// Группы хранятся под отдельной блокировкой; группу могли удалить после создания приглашения
+	if invitation.Group != "" {
+		mockGroupsMu.Lock()
+		if members, ok := mockGroupMembers[invitation.Group]; ok {
+			members[userID] = true
+		}
+		mockGroupsMu.Unlock()
+	}
+	return userID, nil
+}
+
+// ListCourseAssignments возвращает назначенные пользователю курсы из моковых данных
+func (s *MockStorage) ListCourseAssignments(userID int) ([]models.CourseAssignment, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	assignments := []models.CourseAssignment{}
+	for _, assignment := range mockCourseAssignment[userID] {
+		i := mockCourseIndex(assignment.CourseID)
+		if i < 0 || !s.courseVisible(assignment.CourseID) {
+			continue
+		}
+		assignment.CourseTitle = mockCourses[i].VulnerabilityType
+		assignments = append(assignments, assignment)
+	}
+	return assignments, nil
+}
@@ -0,0 +1,63 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+// mockIPAllowlists — списки разрешенных адресов организаций
+var mockIPAllowlists = map[int]models.IPAllowlist{}
+
+// ListIPAllowlists возвращает заданные списки разрешенных адресов из моковых данных
+func (s *MockStorage) ListIPAllowlists() ([]models.IPAllowlist, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	lists := make([]models.IPAllowlist, 0, len(mockIPAllowlists))
+	for _, list := range mockIPAllowlists {
+		lists = append(lists, list)
+	}
+	sort.Slice(lists, func(i, j int) bool { return lists[i].OrganizationID < lists[j].OrganizationID })
+	return lists, nil
+}
+
+// mockIPAllowlist возвращает список разрешенных адресов организации; mockMu должен быть захвачен
+func mockIPAllowlist(orgID int) (models.IPAllowlist, error) {
+	if _, ok := mockOrganizations[orgID]; !ok {
+		return models.IPAllowlist{}, ErrOrganizationNotFound
+	}
+	if list, ok := mockIPAllowlists[orgID]; ok {
+		return list, nil
+	}
+	return models.IPAllowlist{OrganizationID: orgID, CIDRs: []string{}}, nil
+}
+
+// GetIPAllowlist возвращает список разрешенных адресов организации из моковых данных
+func (s *MockStorage) GetIPAllowlist(orgID int) (models.IPAllowlist, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+	return mockIPAllowlist(orgID)
+}
+
+// SetIPAllowlist заменяет список разрешенных адресов организации в моковых данных
+func (s *MockStorage) SetIPAllowlist(orgID int, cidrs []string, updatedBy int) (models.IPAllowlist, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockOrganizations[orgID]; !ok {
+		return models.IPAllowlist{}, ErrOrganizationNotFound
+	}
+	if len(cidrs) == 0 {
+		delete(mockIPAllowlists, orgID)
+	} else {
+		now := time.Now().UTC()
+		mockIPAllowlists[orgID] = models.IPAllowlist{
+			OrganizationID: orgID,
+			CIDRs:          append([]string{}, cidrs...),
+			UpdatedBy:      &updatedBy,
+			UpdatedAt:      &now,
+		}
+	}
+	return mockIPAllowlist(orgID)
+}
@@ -0,0 +1,25 @@
+package storage
+
+import "time"
+
+// mockJobLease — аренда периодической задачи
+type mockJobLease struct {
+	holder    string
+	expiresAt time.Time
+}
+
+// mockJobLeases — аренды задач по именам
+var mockJobLeases = map[string]mockJobLease{}
+
+// AcquireJobLease выдает аренду задачи в моковых данных
+func (s *MockStorage) AcquireJobLease(name, holder string, ttl time.Duration) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	now := time.Now()
+	if lease, ok := mockJobLeases[name]; ok && lease.holder != holder && lease.expiresAt.After(now) {
+		return false, nil
+	}
+	mockJobLeases[name] = mockJobLease{holder: holder, expiresAt: now.Add(ttl)}
+	return true, nil
+}
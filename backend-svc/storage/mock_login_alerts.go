@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+// mockLockToken — токен ссылки «это был не я»
+type mockLockToken struct {
+	userID    int
+	expiresAt time.Time
+	used      bool
+}
+
+var (
+	mockLoginContexts = map[int]map[string]models.LoginContext{}
+	mockLockTokens    = map[string]mockLockToken{}
+)
+
+// RecordLoginContext запоминает контекст входа в моковых данных
+func (s *MockStorage) RecordLoginContext(login models.LoginContext) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	now := time.Now().UTC()
+	known := mockLoginContexts[login.UserID]
+	if known == nil {
+		known = map[string]models.LoginContext{}
+		mockLoginContexts[login.UserID] = known
+	}
+	if existing, ok := known[login.Fingerprint]; ok {
+		existing.IP = login.IP
+		existing.UserAgent = login.UserAgent
+		existing.LastSeenAt = now
+		known[login.Fingerprint] = existing
+		return false, nil
+	}
+
+	isNew := len(known) > 0
+	login.FirstSeenAt = now
+	login.LastSeenAt = now
+	known[login.Fingerprint] = login
+	return isNew, nil
+}
+
+// ListLoginContexts возвращает известные контексты входа из моковых данных
+func (s *MockStorage) ListLoginContexts(userID int) ([]models.LoginContext, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	logins := []models.LoginContext{}
+	for _, login := range mockLoginContexts[userID] {
+		logins = append(logins, login)
+	}
+	sort.Slice(logins, func(i, j int) bool { return logins[i].LastSeenAt.After(logins[j].LastSeenAt) })
+	return logins, nil
+}
+
+// CreateAccountLockToken сохраняет токен блокировки в моковых данных
+func (s *MockStorage) CreateAccountLockToken(userID int, tokenHash string, expiresAt time.Time) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	mockLockTokens[tokenHash] = mockLockToken{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+// LockAccountByToken деактивирует пользователя по токену в моковых данных
+func (s *MockStorage) LockAccountByToken(tokenHash string) (int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	token, exists := mockLockTokens[tokenHash]
+	if !exists || token.used || !token.expiresAt.After(time.Now()) {
+		return 0, ErrLockTokenInvalid
+	}
+	user, exists := mockUsers[token.userID]
+	if !exists {
+		return 0, ErrLockTokenInvalid
+	}
+
+	token.used = true
+	mockLockTokens[tokenHash] = token
+	user.IsActive = false
+	user.Version++
+	mockUsers[user.ID] = user
+	for id, device := range mockTrustedDevices {
+		if device.UserID == user.ID {
+			delete(mockTrustedDevices, id)
+		}
+	}
+	appendMockEvent(models.EventUserStatusChanged, user.ID, map[string]interface{}{
+		"userId":   user.ID,
+		"isActive": false,
+		"reason":   "locked_by_user",
+	})
+	return user.ID, nil
+}
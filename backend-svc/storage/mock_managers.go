@@ -0,0 +1,126 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+// mockManagers — пользователь → его руководитель
+var mockManagers = map[int]int{}
+
+// SetUserManager назначает руководителя в моковых данных
+func (s *MockStorage) SetUserManager(userID int, managerID *int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockUsers[userID]; !ok {
+		return ErrUserNotFound
+	}
+	if managerID == nil {
+		delete(mockManagers, userID)
+		return nil
+	}
+	if _, ok := mockUsers[*managerID]; !ok {
+		return ErrUserNotFound
+	}
+	for current, seen := *managerID, map[int]bool{}; !seen[current]; {
+		if current == userID {
+			return ErrManagerCycle
+		}
+		seen[current] = true
+		next, ok := mockManagers[current]
+		if !ok {
+			break
+		}
+		current = next
+	}
+	mockManagers[userID] = *managerID
+	return nil
+}
+
+// IsManagerOf проверяет руководителя по моковым данным
+func (s *MockStorage) IsManagerOf(managerID, userID int) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	current, ok := mockManagers[userID]
+	return ok && current == managerID, nil
+}
+
+// ListManagerReports возвращает подчиненных из моковых данных
+func (s *MockStorage) ListManagerReports(managerID int) ([]models.TeamMember, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	members := []models.TeamMember{}
+	for userID, current := range mockManagers {
+		if current != managerID {
+			continue
+		}
+		user := mockUsers[userID]
+		members = append(members, models.TeamMember{
+			UserID: user.ID, Username: user.Username, FullName: user.FullName, Email: user.Email, IsActive: user.IsActive,
+		})
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Username < members[j].Username })
+	return members, nil
+}
+
+// GetCourseProgressSummaries считает прогресс по начатым курсам в моковых данных
+func (s *MockStorage) GetCourseProgressSummaries(userID int) ([]models.CourseProgressSummary, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	summaries := []models.CourseProgressSummary{}
+	for _, course := range mockCourses {
+		summary := models.CourseProgressSummary{CourseID: course.ID, CourseTitle: course.VulnerabilityType, TasksTotal: len(course.Tasks)}
+		for _, task := range course.Tasks {
+			if mockUserProgress[userID].Completed[task.ID] {
+				summary.TasksCompleted++
+			}
+		}
+		if summary.TasksCompleted > 0 {
+			summary.Percent = progressPoint("", summary.TasksCompleted, summary.TasksTotal).Percent
+			summaries = append(summaries, summary)
+		}
+	}
+	sort.Slice(summaries, func(i, j int) bool { return summaries[i].CourseID < summaries[j].CourseID })
+	return summaries, nil
+}
+
+// ListOutstandingDeadlines возвращает невыполненные задания со сроком из моковых данных
+func (s *MockStorage) ListOutstandingDeadlines(userID int, until time.Time) ([]models.TeamDeadline, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	deadlines := []models.TeamDeadline{}
+	for _, course := range mockCourses {
+		started := false
+		for _, task := range course.Tasks {
+			started = started || mockUserProgress[userID].Completed[task.ID]
+		}
+		for _, assignment := range mockCourseAssignment[userID] {
+			started = started || assignment.CourseID == course.ID
+		}
+		if !started {
+			continue
+		}
+		for _, task := range course.Tasks {
+			schedule, ok := mockTaskSchedules[task.ID]
+			if !ok || schedule.DueAt == nil || !schedule.DueAt.Before(until) || mockUserProgress[userID].Completed[task.ID] {
+				continue
+			}
+			deadlines = append(deadlines, models.TeamDeadline{
+				TaskID: task.ID, TaskTitle: task.Title, CourseID: course.ID, CourseTitle: course.VulnerabilityType, DueAt: *schedule.DueAt,
+			})
+		}
+	}
+	sort.Slice(deadlines, func(i, j int) bool {
+		if !deadlines[i].DueAt.Equal(deadlines[j].DueAt) {
+			return deadlines[i].DueAt.Before(deadlines[j].DueAt)
+		}
+		return deadlines[i].TaskID < deadlines[j].TaskID
+	})
+	return deadlines, nil
+}
@@ -0,0 +1,162 @@
+package storage
+
+import (
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	// mockCourseBans — ID курса → ID пользователя → блокировка
+	mockCourseBans     = map[int]map[int]models.CourseBan{}
+	mockContentReports = map[int]models.ContentReport{}
+	mockNextReportID   = 1
+)
+
+// mockSetContentHidden скрывает или возвращает контент. Вызывается под mockMu.
+func mockSetContentHidden(contentType string, contentID, moderatorID int, hidden bool) error {
+	var hiddenAt *time.Time
+	var hiddenBy *int
+	if hidden {
+		now := time.Now().UTC()
+		hiddenAt, hiddenBy = &now, &moderatorID
+	}
+	switch contentType {
+	case models.ContentComment:
+		for i := range mockComments {
+			if mockComments[i].ID == contentID {
+				mockComments[i].HiddenAt, mockComments[i].HiddenBy = hiddenAt, hiddenBy
+				appendMockEvent(models.EventCommentModerated, 0, map[string]interface{}{
+					"commentId": contentID,
+					"hidden":    hidden,
+				})
+				return nil
+			}
+		}
+		return ErrCommentNotFound
+	case models.ContentReview:
+		review, ok := mockReviews[contentID]
+		if !ok {
+			return ErrReviewNotFound
+		}
+		review.HiddenAt, review.HiddenBy = hiddenAt, hiddenBy
+		mockReviews[contentID] = review
+		return nil
+	}
+	return fmt.Errorf("unknown content type %q", contentType)
+}
+
+// SetContentHidden скрывает или возвращает контент в моковых данных
+func (s *MockStorage) SetContentHidden(contentType string, contentID, moderatorID int, hidden bool) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return mockSetContentHidden(contentType, contentID, moderatorID, hidden)
+}
+
+// SetCourseBan блокирует пользователя в курсе в моковых данных
+func (s *MockStorage) SetCourseBan(ban models.CourseBan) (models.CourseBan, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockCourseIndex(ban.CourseID) < 0 {
+		return models.CourseBan{}, ErrCourseNotFound
+	}
+	ban.CreatedAt = time.Now().UTC()
+	if mockCourseBans[ban.CourseID] == nil {
+		mockCourseBans[ban.CourseID] = map[int]models.CourseBan{}
+	}
+	mockCourseBans[ban.CourseID][ban.UserID] = ban
+	return ban, nil
+}
+
+// DeleteCourseBan снимает блокировку в моковых данных
+func (s *MockStorage) DeleteCourseBan(courseID, userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockCourseBans[courseID][userID]; !ok {
+		return ErrCourseBanNotFound
+	}
+	delete(mockCourseBans[courseID], userID)
+	return nil
+}
+
+// ListCourseBans возвращает блокировки курса из моковых данных
+func (s *MockStorage) ListCourseBans(courseID int) ([]models.CourseBan, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	bans := []models.CourseBan{}
+	for _, ban := range mockCourseBans[courseID] {
+		bans = append(bans, ban)
+	}
+	sort.Slice(bans, func(i, j int) bool { return bans[i].UserID < bans[j].UserID })
+	return bans, nil
+}
+
+// ReportContent сохраняет жалобу в моковых данных
+func (s *MockStorage) ReportContent(report models.ContentReport) (models.ContentReport, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for _, existing := range mockContentReports {
+		if existing.ContentType == report.ContentType && existing.ContentID == report.ContentID &&
+			*existing.ReporterID == *report.ReporterID {
+			return existing, nil
+		}
+	}
+	report.ID = mockNextReportID
+	mockNextReportID++
+	report.Status = models.ReportOpen
+	report.CreatedAt = time.Now().UTC()
+	mockContentReports[report.ID] = report
+	return report, nil
+}
+
+// ListContentReports возвращает жалобы из моковых данных
+func (s *MockStorage) ListContentReports(status string, limit int) ([]models.ContentReport, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	reports := []models.ContentReport{}
+	for _, report := range mockContentReports {
+		if status == "" || report.Status == status {
+			reports = append(reports, report)
+		}
+	}
+	sort.Slice(reports, func(i, j int) bool { return reports[i].ID < reports[j].ID })
+	if len(reports) > limit {
+		reports = reports[:limit]
+	}
+	return reports, nil
+}
+
+// ResolveContentReport принимает решение по жалобе в моковых данных
+func (s *MockStorage) ResolveContentReport(reportID, moderatorID int, status, note string) (models.ContentReport, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	report, ok := mockContentReports[reportID]
+	if !ok {
+		return models.ContentReport{}, ErrReportNotFound
+	}
+	if report.Status != models.ReportOpen {
+		return models.ContentReport{}, ErrReportResolved
+	}
+	if status == models.ReportHidden {
+		if err := mockSetContentHidden(report.ContentType, report.ContentID, moderatorID, true); err != nil {
+			return models.ContentReport{}, err
+		}
+	}
+
+	now := time.Now().UTC()
+	for id, other := range mockContentReports {
+		if other.ContentType == report.ContentType && other.ContentID == report.ContentID && other.Status == models.ReportOpen {
+			other.Status, other.ResolvedBy, other.ResolvedAt, other.Note = status, &moderatorID, &now, note
+			mockContentReports[id] = other
+		}
+	}
+	return mockContentReports[reportID], nil
+}
@@ -0,0 +1,246 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockOrganizations = map[int]models.Organization{
+		models.DefaultOrganizationID: {ID: models.DefaultOrganizationID, Slug: "default", Name: "Default organization", IsActive: true},
+	}
+	mockNextOrganization = models.DefaultOrganizationID + 1
+	// mockUserOrgs и mockCourseOrgs хранят организацию только для перенесенных записей; остальные
+	// принадлежат организации по умолчанию
+	mockUserOrgs   = map[int]int{}
+	mockCourseOrgs = map[int]int{}
+	// mockOrgAdmins — организация → множество ее администраторов
+	mockOrgAdmins = map[int]map[int]bool{}
+)
+
+func mockUserOrganization(userID int) int {
+	if org, ok := mockUserOrgs[userID]; ok {
+		return org
+	}
+	return models.DefaultOrganizationID
+}
+
+func mockCourseOrganization(courseID int) int {
+	if org, ok := mockCourseOrgs[courseID]; ok {
+		return org
+	}
+	return models.DefaultOrganizationID
+}
+
+// userVisible сообщает, виден ли пользователь в представлении организации; mockMu должен быть захвачен
+func (s *MockStorage) userVisible(userID int) bool {
+	return s.OrganizationID == 0 || mockUserOrganization(userID) == s.OrganizationID
+}
+
+// courseVisible сообщает, виден ли курс в представлении организации; mockMu должен быть захвачен
+func (s *MockStorage) courseVisible(courseID int) bool {
+	return s.OrganizationID == 0 || mockCourseOrganization(courseID) == s.OrganizationID
+}
+
+// organization возвращает организацию, в которой создаются новые записи
+func (s *MockStorage) organization() int {
+	if s.OrganizationID == 0 {
+		return models.DefaultOrganizationID
+	}
+	return s.OrganizationID
+}
+
+// mockOrganizationWithCounts дополняет организацию числом пользователей и курсов; mockMu должен быть захвачен
+func mockOrganizationWithCounts(org models.Organization) models.Organization {
+	org.Users, org.Courses = 0, 0
+	for id := range mockUsers {
+		if mockUserOrganization(id) == org.ID {
+			org.Users++
+		}
+	}
+	for _, course := range mockCourses {
+		if mockCourseOrganization(course.ID) == org.ID {
+			org.Courses++
+		}
+	}
+	return org
+}
+
+// ListOrganizations возвращает организации из моковых данных
+func (s *MockStorage) ListOrganizations() ([]models.Organization, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	orgs := []models.Organization{}
+	for _, org := range mockOrganizations {
+		orgs = append(orgs, mockOrganizationWithCounts(org))
+	}
+	sort.Slice(orgs, func(i, j int) bool { return orgs[i].ID < orgs[j].ID })
+	return orgs, nil
+}
+
+// GetOrganization возвращает организацию из моковых данных
+func (s *MockStorage) GetOrganization(id int) (models.Organization, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	org, ok := mockOrganizations[id]
+	if !ok {
+		return org, ErrOrganizationNotFound
+	}
+	return mockOrganizationWithCounts(org), nil
+}
+
+// mockSlugTaken сообщает, занят ли slug другой организацией; mockMu должен быть захвачен
+func mockSlugTaken(slug string, except int) bool {
+	for id, org := range mockOrganizations {
+		if id != except && org.Slug == slug {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateOrganization создает организацию в моковых данных
+func (s *MockStorage) CreateOrganization(org models.Organization) (models.Organization, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockSlugTaken(org.Slug, 0) {
+		return org, ErrOrganizationExists
+	}
+	org.ID = mockNextOrganization
+	mockNextOrganization++
+	org.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	mockOrganizations[org.ID] = org
+	return org, nil
+}
+
+// UpdateOrganization меняет организацию в моковых данных
+func (s *MockStorage) UpdateOrganization(org models.Organization) (models.Organization, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	existing, ok := mockOrganizations[org.ID]
+	if !ok {
+		return org, ErrOrganizationNotFound
+	}
+	if mockSlugTaken(org.Slug, org.ID) {
+		return org, ErrOrganizationExists
+	}
+	existing.Slug, existing.Name, existing.IsActive = org.Slug, org.Name, org.IsActive
+	mockOrganizations[org.ID] = existing
+	return mockOrganizationWithCounts(existing), nil
+}
+
+// SetUserOrganization переносит пользователя в моковых данных. Моковые группы не привязаны
+// к организациям, поэтому членство в них сохраняется.
+func (s *MockStorage) SetUserOrganization(userID, orgID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockOrganizations[orgID]; !ok {
+		return ErrOrganizationNotFound
+	}
+	user, ok := mockUsers[userID]
+	if !ok {
+		return ErrUserNotFound
+	}
+	user.Version++
+	mockUsers[userID] = user
+	if mockUserOrganization(userID) == orgID {
+		return nil
+	}
+	mockUserOrgs[userID] = orgID
+	for org, admins := range mockOrgAdmins {
+		if org != orgID {
+			delete(admins, userID)
+		}
+	}
+	assignments := mockCourseAssignment[userID][:0]
+	for _, assignment := range mockCourseAssignment[userID] {
+		if mockCourseOrganization(assignment.CourseID) == orgID {
+			assignments = append(assignments, assignment)
+		}
+	}
+	mockCourseAssignment[userID] = assignments
+	if manager, ok := mockManagers[userID]; ok && mockUserOrganization(manager) != orgID {
+		delete(mockManagers, userID)
+	}
+	for report, manager := range mockManagers {
+		if manager == userID && mockUserOrganization(report) != orgID {
+			delete(mockManagers, report)
+		}
+	}
+	return nil
+}
+
+// SetCourseOrganization переносит курс в моковых данных
+func (s *MockStorage) SetCourseOrganization(courseID, orgID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockOrganizations[orgID]; !ok {
+		return ErrOrganizationNotFound
+	}
+	if mockCourseIndex(courseID) < 0 {
+		return ErrCourseNotFound
+	}
+	mockCourseOrgs[courseID] = orgID
+	return nil
+}
+
+// ListOrganizationMembers возвращает пользователей организации из моковых данных
+func (s *MockStorage) ListOrganizationMembers(orgID int) ([]models.OrganizationMember, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	members := []models.OrganizationMember{}
+	for id, user := range mockUsers {
+		if mockUserOrganization(id) != orgID {
+			continue
+		}
+		members = append(members, models.OrganizationMember{
+			UserID: id, Username: user.Username, FullName: user.FullName, Email: user.Email,
+			IsActive: user.IsActive, IsOrgAdmin: mockOrgAdmins[orgID][id],
+		})
+	}
+	sort.Slice(members, func(i, j int) bool { return members[i].Username < members[j].Username })
+	return members, nil
+}
+
+// IsOrganizationMember проверяет организацию пользователя по моковым данным
+func (s *MockStorage) IsOrganizationMember(orgID, userID int) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	_, ok := mockUsers[userID]
+	return ok && mockUserOrganization(userID) == orgID, nil
+}
+
+// IsOrganizationAdmin проверяет права администратора организации по моковым данным
+func (s *MockStorage) IsOrganizationAdmin(orgID, userID int) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+	return mockOrgAdmins[orgID][userID], nil
+}
+
+// SetOrganizationAdmin выдает или отзывает права администратора организации в моковых данных
+func (s *MockStorage) SetOrganizationAdmin(orgID, userID int, isAdmin bool) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockUsers[userID]; !ok || mockUserOrganization(userID) != orgID {
+		return ErrUserNotFound
+	}
+	if !isAdmin {
+		delete(mockOrgAdmins[orgID], userID)
+		return nil
+	}
+	if mockOrgAdmins[orgID] == nil {
+		mockOrgAdmins[orgID] = map[int]bool{}
+	}
+	mockOrgAdmins[orgID][userID] = true
+	return nil
+}
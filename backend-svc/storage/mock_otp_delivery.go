@@ -0,0 +1,60 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	mockOTPPreferences = map[int]models.OTPPreference{}
+	mockOTPDeliveries  []models.OTPDelivery
+)
+
+// GetOTPPreference возвращает канал доставки кодов из моковых данных
+func (s *MockStorage) GetOTPPreference(userID int) (models.OTPPreference, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	pref, exists := mockOTPPreferences[userID]
+	if !exists {
+		return models.OTPPreference{UserID: userID, Channel: models.OTPChannelEmail}, nil
+	}
+	return pref, nil
+}
+
+// SetOTPPreference сохраняет канал доставки кодов в моковых данных
+func (s *MockStorage) SetOTPPreference(pref models.OTPPreference) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, exists := mockUsers[pref.UserID]; !exists {
+		return ErrUserNotFound
+	}
+	mockOTPPreferences[pref.UserID] = pref
+	return nil
+}
+
+// RecordOTPDelivery сохраняет результат отправки кода в моковых данных
+func (s *MockStorage) RecordOTPDelivery(delivery models.OTPDelivery) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	delivery.ID = int64(len(mockOTPDeliveries) + 1)
+	delivery.CreatedAt = time.Now().UTC()
+	mockOTPDeliveries = append(mockOTPDeliveries, delivery)
+	return nil
+}
+
+// ListOTPDeliveries возвращает последние попытки доставки кодов из моковых данных
+func (s *MockStorage) ListOTPDeliveries(userID, limit int) ([]models.OTPDelivery, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	deliveries := []models.OTPDelivery{}
+	for i := len(mockOTPDeliveries) - 1; i >= 0 && len(deliveries) < limit; i-- {
+		if mockOTPDeliveries[i].UserID == userID {
+			deliveries = append(deliveries, mockOTPDeliveries[i])
+		}
+	}
+	return deliveries, nil
+}
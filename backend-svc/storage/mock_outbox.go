@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"encoding/json"
+	"lmsmodule/backend-svc/models"
+	"sync"
+	"time"
+)
+
+// mockOutboxEntry хранит событие вместе с состоянием его доставки
+type mockOutboxEntry struct {
+	event        models.OutboxEvent
+	nextAttempt  time.Time
+	dispatchedAt *time.Time
+}
+
+var (
+	mockOutboxMu     sync.Mutex
+	mockOutbox       []*mockOutboxEntry
+	mockOutboxNextID int64
+)
+
+// appendMockEvent добавляет событие в моковый outbox
+func appendMockEvent(eventType string, aggregateID int, payload interface{}) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return
+	}
+
+	mockOutboxMu.Lock()
+	defer mockOutboxMu.Unlock()
+
+	mockOutboxNextID++
+	now := time.Now()
+	mockOutbox = append(mockOutbox, &mockOutboxEntry{
+		event: models.OutboxEvent{
+			ID:          mockOutboxNextID,
+			EventType:   eventType,
+			AggregateID: aggregateID,
+			Payload:     data,
+			CreatedAt:   now,
+		},
+		nextAttempt: now,
+	})
+}
+
+// FetchPendingEvents возвращает неопубликованные события из мокового outbox
+func (s *MockStorage) FetchPendingEvents(limit int) ([]models.OutboxEvent, error) {
+	mockOutboxMu.Lock()
+	defer mockOutboxMu.Unlock()
+
+	now := time.Now()
+	var events []models.OutboxEvent
+	for _, entry := range mockOutbox {
+		if len(events) >= limit {
+			break
+		}
+		if entry.dispatchedAt == nil && !entry.nextAttempt.After(now) {
+			events = append(events, entry.event)
+		}
+	}
+	return events, nil
+}
+
+// MarkEventDispatched отмечает событие как опубликованное в моковом outbox
+func (s *MockStorage) MarkEventDispatched(eventID int64) error {
+	mockOutboxMu.Lock()
+	defer mockOutboxMu.Unlock()
+
+	for _, entry := range mockOutbox {
+		if entry.event.ID == eventID {
+			now := time.Now()
+			entry.dispatchedAt = &now
+			return nil
+		}
+	}
+	return nil
+}
+
+// MarkEventFailed фиксирует неудачную попытку публикации в моковом outbox
+func (s *MockStorage) MarkEventFailed(eventID int64, reason string, retryAt time.Time) error {
+	mockOutboxMu.Lock()
+	defer mockOutboxMu.Unlock()
+
+	for _, entry := range mockOutbox {
+		if entry.event.ID == eventID {
+			entry.event.Attempts++
+			entry.nextAttempt = retryAt
+			return nil
+		}
+	}
+	return nil
+}
+
+// PurgeDispatchedEvents удаляет доставленные события из мокового outbox
+func (s *MockStorage) PurgeDispatchedEvents(before time.Time) (int64, error) {
+	mockOutboxMu.Lock()
+	defer mockOutboxMu.Unlock()
+
+	var purged int64
+	kept := mockOutbox[:0]
+	for _, entry := range mockOutbox {
+		if entry.dispatchedAt != nil && entry.dispatchedAt.Before(before) {
+			purged++
+			continue
+		}
+		kept = append(kept, entry)
+	}
+	mockOutbox = kept
+	return purged, nil
+}
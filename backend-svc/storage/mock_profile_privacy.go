@@ -0,0 +1,42 @@
+package storage
+
+import "lmsmodule/backend-svc/models"
+
+var mockProfilePrivacy = map[int]models.ProfilePrivacy{}
+
+// GetProfilePrivacy возвращает настройки приватности профиля из моковых данных
+func (s *MockStorage) GetProfilePrivacy(userID int) (models.ProfilePrivacy, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if privacy, ok := mockProfilePrivacy[userID]; ok {
+		return privacy, nil
+	}
+	return models.DefaultProfilePrivacy(), nil
+}
+
+// SetProfilePrivacy сохраняет настройки приватности профиля в моковых данных
+func (s *MockStorage) SetProfilePrivacy(userID int, privacy models.ProfilePrivacy) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	mockProfilePrivacy[userID] = privacy
+	return nil
+}
+
+// GetTaskCompletions возвращает выполненные задания из моковых данных; время выполнения
+// в моковом прогрессе не хранится
+func (s *MockStorage) GetTaskCompletions(userID int) ([]models.TaskCompletion, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	completions := []models.TaskCompletion{}
+	for _, course := range mockCourses {
+		for _, task := range course.Tasks {
+			if mockUserProgress[userID].Completed[task.ID] {
+				completions = append(completions, models.TaskCompletion{TaskID: task.ID, CourseID: course.ID})
+			}
+		}
+	}
+	return completions, nil
+}
@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+type mockSnapshotKey struct {
+	userID, courseID int
+	day              string
+}
+
+var mockProgressSnapshots = map[mockSnapshotKey]models.ProgressPoint{}
+
+// SnapshotProgress сохраняет прогресс за день в моковых данных
+func (s *MockStorage) SnapshotProgress(day time.Time) (int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	today := day.UTC().Format(statsDayFormat)
+	since := day.UTC().AddDate(0, 0, -progressSnapshotCarryDays).Format(statsDayFormat)
+	tracked := map[mockCompletionKey]bool{}
+	for key, point := range mockProgressSnapshots {
+		if key.day >= since && point.TasksCompleted > 0 {
+			tracked[mockCompletionKey{key.userID, key.courseID}] = true
+		}
+	}
+
+	snapshots := 0
+	for _, course := range mockCourses {
+		for userID, progress := range mockUserProgress {
+			completed := 0
+			for _, task := range course.Tasks {
+				if progress.Completed[task.ID] {
+					completed++
+				}
+			}
+			if completed == 0 && !tracked[mockCompletionKey{userID, course.ID}] {
+				continue
+			}
+			mockProgressSnapshots[mockSnapshotKey{userID, course.ID, today}] = progressPoint(today, completed, len(course.Tasks))
+			snapshots++
+		}
+	}
+	return snapshots, nil
+}
+
+// GetProgressHistory возвращает снимки прогресса из моковых данных
+func (s *MockStorage) GetProgressHistory(userID, courseID int, from time.Time) ([]models.CourseProgressHistory, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	since := from.UTC().Format(statsDayFormat)
+	history := []models.CourseProgressHistory{}
+	for _, course := range mockCourses {
+		if courseID != 0 && course.ID != courseID {
+			continue
+		}
+		points := []models.ProgressPoint{}
+		for key, point := range mockProgressSnapshots {
+			if key.userID == userID && key.courseID == course.ID && key.day >= since {
+				points = append(points, point)
+			}
+		}
+		if len(points) == 0 {
+			continue
+		}
+		sort.Slice(points, func(i, j int) bool { return points[i].Date < points[j].Date })
+		history = append(history, models.CourseProgressHistory{CourseID: course.ID, CourseTitle: course.VulnerabilityType, Points: points})
+	}
+	sort.Slice(history, func(i, j int) bool { return history[i].CourseID < history[j].CourseID })
+	return history, nil
+}
@@ -0,0 +1,103 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+type mockPushReminder struct {
+	userID, taskID int
+	dueAt          time.Time
+}
+
+var (
+	// mockPushSubscriptions — адрес push-сервиса → подписка
+	mockPushSubscriptions = map[string]models.PushSubscription{}
+	mockNextPushID        = 1
+	mockPushReminders     = map[mockPushReminder]bool{}
+)
+
+// SavePushSubscription сохраняет подписку в моковых данных
+func (s *MockStorage) SavePushSubscription(sub models.PushSubscription) (models.PushSubscription, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if existing, ok := mockPushSubscriptions[sub.Endpoint]; ok {
+		sub.ID = existing.ID
+	} else {
+		sub.ID = mockNextPushID
+		mockNextPushID++
+	}
+	sub.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	mockPushSubscriptions[sub.Endpoint] = sub
+	return sub, nil
+}
+
+// ListPushSubscriptions возвращает подписки пользователя из моковых данных
+func (s *MockStorage) ListPushSubscriptions(userID int) ([]models.PushSubscription, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	subs := []models.PushSubscription{}
+	for _, sub := range mockPushSubscriptions {
+		if sub.UserID == userID {
+			subs = append(subs, sub)
+		}
+	}
+	sort.Slice(subs, func(i, j int) bool { return subs[i].ID < subs[j].ID })
+	return subs, nil
+}
+
+// ListPushSubscriberIDs возвращает пользователей с подписками из моковых данных
+func (s *MockStorage) ListPushSubscriberIDs() ([]int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	seen := map[int]bool{}
+	ids := []int{}
+	for _, sub := range mockPushSubscriptions {
+		if !seen[sub.UserID] {
+			seen[sub.UserID] = true
+			ids = append(ids, sub.UserID)
+		}
+	}
+	sort.Ints(ids)
+	return ids, nil
+}
+
+// DeletePushSubscription удаляет подписку пользователя из моковых данных
+func (s *MockStorage) DeletePushSubscription(userID, id int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for endpoint, sub := range mockPushSubscriptions {
+		if sub.ID == id && sub.UserID == userID {
+			delete(mockPushSubscriptions, endpoint)
+			return nil
+		}
+	}
+	return ErrPushSubscriptionNotFound
+}
+
+// DeletePushSubscriptionByEndpoint удаляет подписку из моковых данных
+func (s *MockStorage) DeletePushSubscriptionByEndpoint(endpoint string) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	delete(mockPushSubscriptions, endpoint)
+	return nil
+}
+
+// MarkPushDeadlineReminder отмечает push-напоминание в моковых данных
+func (s *MockStorage) MarkPushDeadlineReminder(userID, taskID int, dueAt time.Time) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	key := mockPushReminder{userID: userID, taskID: taskID, dueAt: dueAt.UTC()}
+	if mockPushReminders[key] {
+		return false, nil
+	}
+	mockPushReminders[key] = true
+	return true, nil
+}
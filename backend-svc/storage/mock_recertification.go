@@ -0,0 +1,187 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+type mockCompletionKey struct {
+	userID, courseID int
+}
+
+var (
+	mockRecertifications  = map[int]models.CourseRecertification{}
+	mockCourseCompletions = map[mockCompletionKey]models.CourseCompletion{}
+)
+
+// GetCourseRecertification возвращает требование повторного прохождения из моковых данных
+func (s *MockStorage) GetCourseRecertification(courseID int) (models.CourseRecertification, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	rec, ok := mockRecertifications[courseID]
+	if !ok {
+		return models.CourseRecertification{CourseID: courseID}, ErrRecertificationNotFound
+	}
+	return rec, nil
+}
+
+// SetCourseRecertification сохраняет требование в моковых данных. Время выполнения заданий
+// там не хранится, поэтому циклы уже завершивших курс пользователей начинаются сейчас.
+func (s *MockStorage) SetCourseRecertification(rec models.CourseRecertification) (models.CourseRecertification, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	index := mockCourseIndex(rec.CourseID)
+	if index < 0 {
+		return rec, ErrCourseNotFound
+	}
+	rec.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	mockRecertifications[rec.CourseID] = rec
+
+	course := mockCourses[index]
+	for userID, progress := range mockUserProgress {
+		key := mockCompletionKey{userID, rec.CourseID}
+		if current, ok := mockCourseCompletions[key]; ok && current.ExpiredAt == nil {
+			mockCourseCompletions[key] = recertificationCycle(rec, userID, current.CompletedAt)
+			continue
+		}
+		completed := len(course.Tasks) > 0
+		for _, task := range course.Tasks {
+			completed = completed && progress.Completed[task.ID]
+		}
+		if completed {
+			mockCourseCompletions[key] = recertificationCycle(rec, userID, rec.UpdatedAt)
+		}
+	}
+	return rec, nil
+}
+
+// DeleteCourseRecertification отменяет повторное прохождение курса в моковых данных
+func (s *MockStorage) DeleteCourseRecertification(courseID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockRecertifications[courseID]; !ok {
+		return ErrRecertificationNotFound
+	}
+	delete(mockRecertifications, courseID)
+	for key := range mockCourseCompletions {
+		if key.courseID == courseID {
+			delete(mockCourseCompletions, key)
+		}
+	}
+	return nil
+}
+
+// RecordCourseCompletion начинает цикл сертификации в моковых данных
+func (s *MockStorage) RecordCourseCompletion(userID, courseID int, at time.Time) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	rec, ok := mockRecertifications[courseID]
+	if !ok {
+		return false, nil
+	}
+	mockCourseCompletions[mockCompletionKey{userID, courseID}] = recertificationCycle(rec, userID, at)
+	return true, nil
+}
+
+// mockListCourseCompletions возвращает циклы, подходящие под match, по возрастанию срока
+func mockListCourseCompletions(match func(models.CourseCompletion) bool) []models.CourseCompletion {
+	completions := []models.CourseCompletion{}
+	for _, completion := range mockCourseCompletions {
+		if match(completion) {
+			completions = append(completions, completion)
+		}
+	}
+	sort.Slice(completions, func(i, j int) bool { return completions[i].ExpiresAt.Before(completions[j].ExpiresAt) })
+	return completions
+}
+
+// ListRecertificationReminders возвращает циклы для напоминания из моковых данных
+func (s *MockStorage) ListRecertificationReminders(now time.Time) ([]models.CourseCompletion, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return mockListCourseCompletions(func(c models.CourseCompletion) bool {
+		return !c.RemindAt.After(now) && c.ExpiresAt.After(now) && c.RemindedAt == nil && c.ExpiredAt == nil
+	}), nil
+}
+
+// MarkRecertificationReminded отмечает напоминание в моковых данных
+func (s *MockStorage) MarkRecertificationReminded(userID, courseID int, at time.Time) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	key := mockCompletionKey{userID, courseID}
+	if completion, ok := mockCourseCompletions[key]; ok {
+		at = at.UTC()
+		completion.RemindedAt = &at
+		mockCourseCompletions[key] = completion
+	}
+	return nil
+}
+
+// ListExpiredCourseCompletions возвращает истекшие циклы из моковых данных
+func (s *MockStorage) ListExpiredCourseCompletions(now time.Time) ([]models.CourseCompletion, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return mockListCourseCompletions(func(c models.CourseCompletion) bool {
+		return !c.ExpiresAt.After(now) && c.ExpiredAt == nil
+	}), nil
+}
+
+// ExpireCourseCompletion переносит прогресс курса в архив попыток в моковых данных
+func (s *MockStorage) ExpireCourseCompletion(userID, courseID int, at time.Time) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	key := mockCompletionKey{userID, courseID}
+	completion, ok := mockCourseCompletions[key]
+	if !ok || completion.ExpiredAt != nil {
+		return nil
+	}
+	at = at.UTC()
+	completion.ExpiredAt = &at
+	mockCourseCompletions[key] = completion
+	mockArchiveCourseProgress(userID, courseID, nil, recertificationResetReason, at)
+	return nil
+}
+
+// ComplianceReport собирает отчет по моковым данным
+func (s *MockStorage) ComplianceReport(filter models.ComplianceFilter) ([]models.ComplianceEntry, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	entries := []models.ComplianceEntry{}
+	for _, course := range mockCourses {
+		if _, ok := mockRecertifications[course.ID]; !ok || (filter.CourseID != 0 && filter.CourseID != course.ID) {
+			continue
+		}
+		for _, user := range mockUsers {
+			if !user.IsActive || (filter.UserID != 0 && filter.UserID != user.ID) {
+				continue
+			}
+			entry := models.ComplianceEntry{
+				UserID: user.ID, Username: user.Username, Email: user.Email,
+				CourseID: course.ID, CourseTitle: course.VulnerabilityType,
+			}
+			if completion, ok := mockCourseCompletions[mockCompletionKey{user.ID, course.ID}]; ok {
+				completedAt, remindAt, expiresAt := completion.CompletedAt, completion.RemindAt, completion.ExpiresAt
+				entry.CompletedAt, entry.RemindAt, entry.ExpiresAt = &completedAt, &remindAt, &expiresAt
+				entry.ExpiredAt = completion.ExpiredAt
+			}
+			entries = append(entries, entry)
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].CourseID != entries[j].CourseID {
+			return entries[i].CourseID < entries[j].CourseID
+		}
+		return entries[i].Username < entries[j].Username
+	})
+	return entries, nil
+}
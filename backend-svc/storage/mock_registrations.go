@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+// mockPendingRegistrations — очередь регистраций: ID пользователя -> заявка
+var mockPendingRegistrations = map[int]models.PendingRegistration{}
+
+// mockPendingRegistration дополняет заявку данными пользователя; mockMu должен быть захвачен
+func mockPendingRegistration(userID int) (models.PendingRegistration, bool) {
+	registration, ok := mockPendingRegistrations[userID]
+	if !ok {
+		return registration, false
+	}
+	user := mockUsers[userID]
+	registration.UserID, registration.Username, registration.Email = user.ID, user.Username, user.Email
+	registration.FullName, registration.Locale = user.FullName, user.Locale
+	return registration, true
+}
+
+// CreatePendingUser создает неактивного пользователя в моковых данных и ставит его в очередь
+func (s *MockStorage) CreatePendingUser(user models.User, requestedIP string) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	userID, err := mockInsertUser(user, s.organization(), false)
+	if err != nil {
+		return err
+	}
+	mockPendingRegistrations[userID] = models.PendingRegistration{RequestedIP: requestedIP, RequestedAt: time.Now().UTC()}
+	return nil
+}
+
+// ListPendingRegistrations возвращает очередь регистраций из моковых данных
+func (s *MockStorage) ListPendingRegistrations() ([]models.PendingRegistration, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	registrations := []models.PendingRegistration{}
+	for userID := range mockPendingRegistrations {
+		if registration, ok := mockPendingRegistration(userID); ok {
+			registrations = append(registrations, registration)
+		}
+	}
+	sort.Slice(registrations, func(i, j int) bool {
+		if !registrations[i].RequestedAt.Equal(registrations[j].RequestedAt) {
+			return registrations[i].RequestedAt.Before(registrations[j].RequestedAt)
+		}
+		return registrations[i].UserID < registrations[j].UserID
+	})
+	return registrations, nil
+}
+
+// IsRegistrationPending проверяет очередь регистраций в моковых данных
+func (s *MockStorage) IsRegistrationPending(userID int) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+	_, ok := mockPendingRegistrations[userID]
+	return ok, nil
+}
+
+// ApproveRegistration активирует пользователя из очереди в моковых данных
+func (s *MockStorage) ApproveRegistration(userID int) (models.PendingRegistration, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	registration, ok := mockPendingRegistration(userID)
+	if !ok {
+		return registration, ErrRegistrationNotFound
+	}
+	delete(mockPendingRegistrations, userID)
+	mockSetStatus(userID, true)
+	return registration, nil
+}
+
+// RejectRegistration удаляет пользователя из очереди в моковых данных
+func (s *MockStorage) RejectRegistration(userID int) (models.PendingRegistration, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	registration, ok := mockPendingRegistration(userID)
+	if !ok {
+		return registration, ErrRegistrationNotFound
+	}
+	delete(mockPendingRegistrations, userID)
+	delete(mockUsers, userID)
+	delete(mockUsersByUsername, registration.Username)
+	delete(mockUserOrgs, userID)
+	appendMockEvent(models.EventUserDeleted, userID, map[string]interface{}{
+		"userId": userID,
+		"reason": "registration_rejected",
+	})
+	return registration, nil
+}
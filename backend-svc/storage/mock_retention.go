@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ApplyRetention применяет политику хранения к моковым данным. У моковых пользователей нет даты
+// регистрации, поэтому никогда не входившие учетные записи политикой не затрагиваются.
+func (s *MockStorage) ApplyRetention(policy models.RetentionPolicy, cutoff time.Time, dryRun bool) (int64, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	anonymize := policy.Action == models.RetentionAnonymize
+	if !anonymize && policy.Action != models.RetentionPurge {
+		return 0, fmt.Errorf("%w: action %q", ErrUnknownRetentionPolicy, policy.Action)
+	}
+
+	var affected int64
+	switch policy.Data {
+	case models.RetentionAuditLog:
+		kept := mockAuditLog[:0:0]
+		for _, entry := range mockAuditLog {
+			switch {
+			case !entry.CreatedAt.Before(cutoff):
+			case !anonymize:
+				affected++
+				continue
+			case entry.ActorID != nil || entry.ActorUsername != "" || entry.IP != "" || entry.Changes != nil:
+				affected++
+				entry.ActorID, entry.ActorUsername, entry.IP, entry.Changes = nil, "", "", nil
+			}
+			kept = append(kept, entry)
+		}
+		if !dryRun {
+			mockAuditLog = kept
+		}
+
+	case models.RetentionLoginHistory:
+		for userID, contexts := range mockLoginContexts {
+			for fingerprint, login := range contexts {
+				if !login.LastSeenAt.Before(cutoff) || anonymize && login.IP == "" && login.UserAgent == "" && login.Location == "" {
+					continue
+				}
+				affected++
+				if dryRun {
+					continue
+				}
+				if anonymize {
+					login.IP, login.UserAgent, login.Location = "", "", ""
+					contexts[fingerprint] = login
+				} else {
+					delete(contexts, fingerprint)
+				}
+			}
+			if len(contexts) == 0 && !dryRun {
+				delete(mockLoginContexts, userID)
+			}
+		}
+		for hash, token := range mockLockTokens {
+			if token.expiresAt.Before(cutoff) {
+				affected++
+				if !dryRun {
+					delete(mockLockTokens, hash)
+				}
+			}
+		}
+
+	case models.RetentionInactiveAccounts:
+		for id, user := range mockUsers {
+			if user.IsAdmin || user.LastLogin.IsZero() || !user.LastLogin.Before(cutoff) ||
+				anonymize && strings.HasSuffix(user.Email, anonymizedEmailDomain) {
+				continue
+			}
+			affected++
+			if dryRun {
+				continue
+			}
+			mockClearPersonalData(id)
+			if !anonymize {
+				delete(mockUsers, id)
+				delete(mockUsersByUsername, user.Username)
+				delete(mockUserProgress, id)
+				delete(mockPendingRegistrations, id)
+				delete(mockAccessExpiry, id)
+				appendMockEvent(models.EventUserDeleted, id, map[string]interface{}{
+					"userId": id,
+					"reason": "retention",
+				})
+				continue
+			}
+			delete(mockUsersByUsername, user.Username)
+			user.Username = "anonymized-" + strconv.Itoa(id)
+			user.Email = user.Username + anonymizedEmailDomain
+			user.FullName, user.PasswordHash, user.TOTPSecret, user.AvatarKey = "", "", "", ""
+			user.Is2FAEnabled, user.IsActive = false, false
+			user.Version++
+			mockUsers[id] = user
+			mockUsersByUsername[user.Username] = id
+			appendMockEvent(models.EventUserAnonymized, id, map[string]interface{}{
+				"userId": id,
+			})
+		}
+
+	default:
+		return 0, fmt.Errorf("%w: data %q", ErrUnknownRetentionPolicy, policy.Data)
+	}
+	return affected, nil
+}
+
+// mockClearPersonalData удаляет персональные данные пользователя вне его учетной записи;
+// mockMu должен быть захвачен
+func mockClearPersonalData(userID int) {
+	delete(mockLoginContexts, userID)
+	delete(mockPasswordHistory, userID)
+	delete(mockOTPCodes, userID)
+	delete(mockOTPPreferences, userID)
+	delete(mockProfilePrivacy, userID)
+	delete(mockTelegramAccounts, userID)
+	delete(mockTaskNotes, userID)
+	for hash, token := range mockLockTokens {
+		if token.userID == userID {
+			delete(mockLockTokens, hash)
+		}
+	}
+	for id, device := range mockTrustedDevices {
+		if device.UserID == userID {
+			delete(mockTrustedDevices, id)
+		}
+	}
+	for id, change := range mockEmailChanges {
+		if change.UserID == userID {
+			delete(mockEmailChanges, id)
+		}
+	}
+	for endpoint, subscription := range mockPushSubscriptions {
+		if subscription.UserID == userID {
+			delete(mockPushSubscriptions, endpoint)
+		}
+	}
+	for token, owner := range mockCalendarFeeds {
+		if owner == userID {
+			delete(mockCalendarFeeds, token)
+		}
+	}
+}
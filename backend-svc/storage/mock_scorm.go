@@ -0,0 +1,90 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// mockScormPackages — ID курса → пакет с учебными объектами
+	mockScormPackages    = map[int]models.ScormPackage{}
+	mockNextScormPackage = 1
+	// mockScormAttempts — ID пользователя → ID задания → попытка
+	mockScormAttempts = map[int]map[int]models.ScormAttempt{}
+)
+
+// CreateScormCourse добавляет курс из пакета SCORM в моковые данные
+func (s *MockStorage) CreateScormCourse(course models.Course, pkg models.ScormPackage) (models.ScormPackage, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	course = mockInsertCourse(course, s.organization())
+	pkg.ID = mockNextScormPackage
+	mockNextScormPackage++
+	pkg.CourseID = course.ID
+	pkg.CreatedAt = time.Now().UTC()
+	pkg.Items = append([]models.ScormItem(nil), pkg.Items...)
+	for i := range pkg.Items {
+		item := &pkg.Items[i]
+		item.TaskID, item.Title = course.Tasks[i].ID, course.Tasks[i].Title
+		item.CourseID, item.Version, item.AssetKey = course.ID, pkg.Version, pkg.AssetKey
+	}
+	mockScormPackages[course.ID] = pkg
+	return pkg, nil
+}
+
+// GetScormPackage возвращает пакет курса из моковых данных
+func (s *MockStorage) GetScormPackage(courseID int) (models.ScormPackage, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	pkg, ok := mockScormPackages[courseID]
+	if !ok {
+		return models.ScormPackage{}, ErrScormPackageNotFound
+	}
+	pkg.Items = append([]models.ScormItem(nil), pkg.Items...)
+	return pkg, nil
+}
+
+// GetScormItem возвращает учебный объект задания из моковых данных
+func (s *MockStorage) GetScormItem(taskID int) (models.ScormItem, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for _, pkg := range mockScormPackages {
+		for _, item := range pkg.Items {
+			if item.TaskID == taskID {
+				return item, nil
+			}
+		}
+	}
+	return models.ScormItem{}, ErrScormItemNotFound
+}
+
+// GetScormAttempt возвращает данные CMI из моковых данных
+func (s *MockStorage) GetScormAttempt(userID, taskID int) (models.ScormAttempt, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	attempt, ok := mockScormAttempts[userID][taskID]
+	if !ok {
+		return models.ScormAttempt{UserID: userID, TaskID: taskID, Data: map[string]string{}}, nil
+	}
+	return attempt, nil
+}
+
+// SaveScormAttempt сохраняет данные CMI в моковых данных
+func (s *MockStorage) SaveScormAttempt(attempt models.ScormAttempt) (models.ScormAttempt, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if previous, ok := mockScormAttempts[attempt.UserID][attempt.TaskID]; ok && previous.CompletedAt != nil {
+		attempt.CompletedAt = previous.CompletedAt
+	}
+	attempt.UpdatedAt = time.Now().UTC()
+	if mockScormAttempts[attempt.UserID] == nil {
+		mockScormAttempts[attempt.UserID] = map[int]models.ScormAttempt{}
+	}
+	mockScormAttempts[attempt.UserID][attempt.TaskID] = attempt
+	return attempt, nil
+}
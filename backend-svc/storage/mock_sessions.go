@@ -0,0 +1,87 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var mockSessions = map[int]models.SessionState{}
+
+// GetSessionState возвращает состояние сессии пользователя из моковых данных
+func (s *MockStorage) GetSessionState(userID int) (models.SessionState, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[userID]
+	if !exists {
+		return models.SessionState{}, ErrUserNotFound
+	}
+	state := mockSessions[userID]
+	state.IsActive = user.IsActive
+	state.OrganizationID = mockUserOrganization(userID)
+	state.OrganizationActive = mockOrganizations[state.OrganizationID].IsActive
+	return state, nil
+}
+
+// StartSession запоминает текущую сессию пользователя в моковых данных
+func (s *MockStorage) StartSession(userID int, sessionID string) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	state := mockSessions[userID]
+	state.SessionID = sessionID
+	mockSessions[userID] = state
+	return nil
+}
+
+// PurgeStaleSessions забывает устаревшие сессии в моковых данных
+func (s *MockStorage) PurgeStaleSessions(before time.Time) (int64, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	var purged int64
+	for userID, state := range mockSessions {
+		if state.SessionID != "" && mockUsers[userID].LastLogin.Before(before) {
+			state.SessionID = ""
+			mockSessions[userID] = state
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// SetSingleSession включает или выключает режим единственной сессии в моковых данных
+func (s *MockStorage) SetSingleSession(userID int, enabled bool) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[userID]
+	if !exists {
+		return ErrUserNotFound
+	}
+	user.Version++
+	mockUsers[userID] = user
+
+	state := mockSessions[userID]
+	state.SingleSession = enabled
+	mockSessions[userID] = state
+	return nil
+}
+
+// SetMustChangePassword задает требование смены пароля в моковых данных
+func (s *MockStorage) SetMustChangePassword(userID int, required bool) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[userID]
+	if !exists {
+		return ErrUserNotFound
+	}
+	user.Version++
+	mockUsers[userID] = user
+
+	state := mockSessions[userID]
+	state.MustChangePassword = required
+	mockSessions[userID] = state
+	return nil
+}
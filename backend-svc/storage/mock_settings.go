@@ -0,0 +1,34 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sync"
+	"time"
+)
+
+var (
+	mockSettingsMu sync.Mutex
+	mockSettings   = map[string]models.Setting{}
+)
+
+// GetSettings возвращает runtime-параметры из моковых данных
+func (s *MockStorage) GetSettings() ([]models.Setting, error) {
+	mockSettingsMu.Lock()
+	defer mockSettingsMu.Unlock()
+
+	settings := make([]models.Setting, 0, len(mockSettings))
+	for _, setting := range mockSettings {
+		settings = append(settings, setting)
+	}
+	return settings, nil
+}
+
+// UpsertSetting сохраняет runtime-параметр в моковых данных
+func (s *MockStorage) UpsertSetting(setting models.Setting) error {
+	mockSettingsMu.Lock()
+	defer mockSettingsMu.Unlock()
+
+	setting.UpdatedAt = time.Now()
+	mockSettings[setting.Key] = setting
+	return nil
+}
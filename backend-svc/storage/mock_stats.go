@@ -0,0 +1,125 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// mockUserActivity — ID пользователя → дни активности (YYYY-MM-DD)
+var mockUserActivity = map[int]map[string]bool{}
+
+// RecordUserActivity отмечает активность пользователя в моковых данных
+func (s *MockStorage) RecordUserActivity(userID int, day time.Time) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockUserActivity[userID] == nil {
+		mockUserActivity[userID] = map[string]bool{}
+	}
+	mockUserActivity[userID][day.UTC().Format(statsDayFormat)] = true
+	return nil
+}
+
+// GetPlatformStats считает показатели по моковым данным. Время регистрации и выполнения
+// заданий там не хранится, поэтому ряды регистраций и выполнений остаются нулевыми.
+func (s *MockStorage) GetPlatformStats(from, to time.Time) (models.PlatformStats, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	from, to = from.UTC(), to.UTC()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	stats := newPlatformStats(from, to)
+
+	for _, user := range mockUsers {
+		stats.Users.Total++
+		if user.IsActive {
+			stats.Users.Active++
+		}
+	}
+
+	dau, wau, mau := today.Format(statsDayFormat), today.AddDate(0, 0, -6).Format(statsDayFormat), today.AddDate(0, 0, -29).Format(statsDayFormat)
+	for _, days := range mockUserActivity {
+		var lastDay string
+		for day := range days {
+			if day > lastDay {
+				lastDay = day
+			}
+			for i := range stats.DailyActive {
+				if stats.DailyActive[i].Date == day {
+					stats.DailyActive[i].Count++
+				}
+			}
+		}
+		if lastDay >= dau {
+			stats.Users.DAU++
+		}
+		if lastDay >= wau {
+			stats.Users.WAU++
+		}
+		if lastDay >= mau {
+			stats.Users.MAU++
+		}
+	}
+
+	for _, course := range mockCourses {
+		stat := models.CourseStats{CourseID: course.ID, Title: course.VulnerabilityType, Tasks: len(course.Tasks)}
+		for _, progress := range mockUserProgress {
+			done := 0
+			for _, task := range course.Tasks {
+				if progress.Completed[task.ID] {
+					done++
+				}
+			}
+			if done > 0 {
+				stat.Learners++
+			}
+			if done > 0 && done == len(course.Tasks) {
+				stat.Finished++
+			}
+		}
+
+		var scoreSum float64
+		scored := 0
+		for _, attempts := range mockScormAttempts {
+			for _, task := range course.Tasks {
+				if attempt, ok := attempts[task.ID]; ok && attempt.Score != nil {
+					scoreSum += *attempt.Score
+					scored++
+				}
+			}
+		}
+		if scored > 0 {
+			average := scoreSum / float64(scored)
+			stat.AverageScore = &average
+		}
+
+		ratingSum := 0
+		for _, review := range mockReviews {
+			if review.CourseID == course.ID && review.HiddenAt == nil {
+				ratingSum += review.Rating
+				stat.Reviews++
+			}
+		}
+		if stat.Reviews > 0 {
+			average := float64(ratingSum) / float64(stat.Reviews)
+			stat.AverageRating = &average
+		}
+		stats.Courses = append(stats.Courses, stat)
+	}
+
+	var scoreSum float64
+	scored := 0
+	for _, attempts := range mockScormAttempts {
+		for _, attempt := range attempts {
+			if attempt.Score != nil {
+				scoreSum += *attempt.Score
+				scored++
+			}
+		}
+	}
+	if scored > 0 {
+		average := scoreSum / float64(scored)
+		stats.AverageScore = &average
+	}
+	return stats, nil
+}
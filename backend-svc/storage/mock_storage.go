@@ -1,21 +1,42 @@
 package storage
 
 import (
-	"errors"
+	"crypto/subtle"
 	"golang.org/x/crypto/bcrypt"
 	"lmsmodule/backend-svc/models"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
-// Моковые данные для тестирования
+// mockOTP хранит одноразовый код и срок его действия
+type mockOTP struct {
+	code      string
+	expiresAt time.Time
+	attempts  int
+}
+
+// Моковые данные для тестирования. mockMu защищает курсы, пользователей, прогресс и OTP-коды,
+// так как обработчики вызывают хранилище конкурентно.
 var (
+	mockMu sync.Mutex
+
+	mockOTPCodes = map[int]mockOTP{}
+
+	// mockPasswordHistory — хеши предыдущих паролей пользователя, новые первыми
+	mockPasswordHistory = map[int][]string{}
+
+	// mockCatalogLoadedAt — время изменения встроенных курсов для ETag и Last-Modified
+	mockCatalogLoadedAt = time.Now().UTC().Truncate(time.Second)
+
 	mockCourses = []models.Course{
 		{
 			ID:                1,
 			VulnerabilityType: "SQL Injection",
 			TasksCount:        2,
 			Description:       "Learn about SQL injection vulnerabilities",
+			UpdatedAt:         mockCatalogLoadedAt,
 			Tasks: []models.Task{
 				{ID: 1, CourseID: 1, Title: "Basics of SQL Injection", Description: "Understanding the fundamentals", Difficulty: "easy", Order: 1},
 				{ID: 2, CourseID: 1, Title: "Advanced SQL Injection", Description: "More complex techniques", Difficulty: "medium", Order: 2},
@@ -26,6 +47,7 @@ var (
 			VulnerabilityType: "XSS",
 			TasksCount:        1,
 			Description:       "Cross-site scripting attacks and prevention",
+			UpdatedAt:         mockCatalogLoadedAt,
 			Tasks: []models.Task{
 				{ID: 3, CourseID: 2, Title: "XSS in Web Applications", Description: "Exploiting front-end vulnerabilities", Difficulty: "medium", Order: 1},
 			},
@@ -35,6 +57,7 @@ var (
 			VulnerabilityType: "CSRF",
 			TasksCount:        1,
 			Description:       "Cross-site request forgery attacks",
+			UpdatedAt:         mockCatalogLoadedAt,
 			Tasks: []models.Task{
 				{ID: 4, CourseID: 3, Title: "Understanding CSRF", Description: "Forging requests across sites", Difficulty: "hard", Order: 1},
 			},
@@ -68,20 +91,25 @@ var (
 		1: {
 			ID:           1,
 			Username:     "admin",
-			PasswordHash: "$2a$10$XJaM5WKk3xQQbUgRIl9YGuRzJtfuZ/lsGQKJQL9AVG1cAP5DJFuTa", // "admin123"
+			PasswordHash: "$2a$10$tCO.FUM5fTi9C.WT.vvUSObUeXkc.ZoOET4vjGFmuy5nQ/Qu1Szkm", // "admin123"
 			Email:        "eyuborisova@yandex.ru",
 			FullName:     "Admin User",
 			TOTPSecret:   "JBSWY3DPEHPK3PXP",
 			Is2FAEnabled: true,
+			IsAdmin:      true,
+			IsActive:     true,
+			Version:      1,
 		},
 		2: {
 			ID:           2,
 			Username:     "user123",
-			PasswordHash: "$2a$10$qJ7EYH.1r9kQQBqS7iPFm.LoTJHEbzGP4.94a8QCbeHEFjyFsHoKG", // "pass123"
+			PasswordHash: "$2a$10$hY.cQlZ6aVLbhqx/fY0KqeikOL/SGXj.zuj1gIe31R7PyNf1.Xb52", // "pass123"
 			Email:        "user@example.com",
 			FullName:     "Regular User",
 			TOTPSecret:   "JBSWY3DPEHPK3PXP",
 			Is2FAEnabled: false,
+			IsActive:     true,
+			Version:      1,
 		},
 	}
 
@@ -91,24 +119,51 @@ var (
 	}
 )
 
+// mockUsersWhere возвращает отсортированные по ID проекции пользователей, удовлетворяющих match.
+// Вызывается под mockMu.
+func mockUsersWhere(match func(models.User) bool) []models.UserSummary {
+	var users []models.UserSummary
+	for _, user := range mockUsers {
+		if match(user) {
+			users = append(users, user.Summary())
+		}
+	}
+	sort.Slice(users, func(i, j int) bool { return users[i].ID < users[j].ID })
+	return users
+}
+
 func (s *MockStorage) GetCourses() ([]models.Course, error) {
-	coursesWithoutTasks := make([]models.Course, len(mockCourses))
+	mockMu.Lock()
+	defer mockMu.Unlock()
 
-	for i, course := range mockCourses {
-		coursesWithoutTasks[i] = models.Course{
+	coursesWithoutTasks := make([]models.Course, 0, len(mockCourses))
+
+	for _, course := range mockCourses {
+		if !s.courseVisible(course.ID) || course.ArchivedAt != nil {
+			continue
+		}
+		summary := models.Course{
 			ID:                course.ID,
 			VulnerabilityType: course.VulnerabilityType,
 			TasksCount:        course.TasksCount,
 			Description:       course.Description,
+			UpdatedAt:         course.UpdatedAt,
 		}
+		fillMockCourseSummary(&summary)
+		coursesWithoutTasks = append(coursesWithoutTasks, summary)
 	}
 
 	return coursesWithoutTasks, nil
 }
 
 func (s *MockStorage) GetCourseByID(id int) (models.Course, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	for _, course := range mockCourses {
-		if course.ID == id {
+		if course.ID == id && s.courseVisible(id) {
+			course.Tasks = append([]models.Task(nil), course.Tasks...)
+			fillMockCourseSummary(&course)
 			return course, nil
 		}
 	}
@@ -116,20 +171,24 @@ func (s *MockStorage) GetCourseByID(id int) (models.Course, error) {
 }
 
 func (s *MockStorage) GetUserProgress(userID int) (models.UserProgress, error) {
-	progress, exists := mockUserProgress[userID]
-	if !exists {
-		return models.UserProgress{
-			UserID:    userID,
-			Completed: make(map[int]bool),
-		}, nil
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	// Возвращаем копию, чтобы вызывающий код не менял моковые данные без блокировки
+	completed := make(map[int]bool)
+	for taskID, done := range mockUserProgress[userID].Completed {
+		completed[taskID] = done
 	}
-	return progress, nil
+	return models.UserProgress{UserID: userID, Completed: completed}, nil
 }
 
 func (s *MockStorage) CompleteTask(userID, taskID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	var taskExists bool
 	for _, t := range mockTasks {
-		if t.ID == taskID {
+		if t.ID == taskID && s.courseVisible(t.CourseID) {
 			taskExists = true
 			break
 		}
@@ -138,6 +197,9 @@ func (s *MockStorage) CompleteTask(userID, taskID int) error {
 	if !taskExists {
 		return ErrTaskNotFound
 	}
+	if mockCourseClosed(userID, taskID) {
+		return ErrCourseArchived
+	}
 
 	progress, exists := mockUserProgress[userID]
 	if !exists {
@@ -150,37 +212,166 @@ func (s *MockStorage) CompleteTask(userID, taskID int) error {
 	progress.Completed[taskID] = true
 	mockUserProgress[userID] = progress
 
+	appendMockEvent(models.EventTaskCompleted, userID, map[string]int{
+		"userId": userID,
+		"taskId": taskID,
+	})
+
 	return nil
 }
 
+// CompleteTasks отмечает несколько заданий в моковых данных
+func (s *MockStorage) CompleteTasks(userID int, taskIDs []int) ([]models.TaskCompletionResult, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	existing := make(map[int]bool, len(mockTasks))
+	for _, t := range mockTasks {
+		existing[t.ID] = s.courseVisible(t.CourseID)
+	}
+
+	progress, exists := mockUserProgress[userID]
+	if !exists {
+		progress = models.UserProgress{
+			UserID:    userID,
+			Completed: make(map[int]bool),
+		}
+	}
+
+	results := make([]models.TaskCompletionResult, 0, len(taskIDs))
+	for _, taskID := range uniqueInts(taskIDs) {
+		switch {
+		case !existing[taskID]:
+			results = append(results, models.TaskCompletionResult{TaskID: taskID, Status: models.TaskCompletionNotFound})
+		case progress.Completed[taskID]:
+			results = append(results, models.TaskCompletionResult{TaskID: taskID, Status: models.TaskCompletionAlreadyCompleted})
+		case mockCourseClosed(userID, taskID):
+			results = append(results, models.TaskCompletionResult{TaskID: taskID, Status: models.TaskCompletionCourseArchived})
+		default:
+			progress.Completed[taskID] = true
+			appendMockEvent(models.EventTaskCompleted, userID, map[string]int{
+				"userId": userID,
+				"taskId": taskID,
+			})
+			results = append(results, models.TaskCompletionResult{TaskID: taskID, Status: models.TaskCompletionCompleted})
+		}
+	}
+	mockUserProgress[userID] = progress
+
+	return results, nil
+}
+
+// CreateCourse добавляет курс с заданиями в моковые данные
+func (s *MockStorage) CreateCourse(course models.Course) (int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return mockInsertCourse(course, s.organization()).ID, nil
+}
+
+// mockInsertCourse добавляет курс с заданиями в организацию orgID и возвращает его с присвоенными ID.
+// Вызывается под mockMu.
+func mockInsertCourse(course models.Course, orgID int) models.Course {
+	courseID := 1
+	for _, c := range mockCourses {
+		if c.ID >= courseID {
+			courseID = c.ID + 1
+		}
+	}
+	taskID := 1
+	for _, t := range mockTasks {
+		if t.ID >= taskID {
+			taskID = t.ID + 1
+		}
+	}
+
+	course.ID = courseID
+	course.TasksCount = len(course.Tasks)
+	course.UpdatedAt = time.Now().UTC()
+	course.Tasks = append([]models.Task(nil), course.Tasks...)
+
+	for i := range course.Tasks {
+		course.Tasks[i].ID = taskID + i
+		course.Tasks[i].CourseID = courseID
+		if course.Tasks[i].Order == 0 {
+			course.Tasks[i].Order = i + 1
+		}
+		mockTasks = append(mockTasks, course.Tasks[i])
+	}
+
+	mockCourses = append(mockCourses, course)
+	mockCourseCreatedAt[courseID] = course.UpdatedAt
+	appendMockEvent(models.EventCourseCreated, 0, map[string]int{"courseId": courseID})
+	if orgID != models.DefaultOrganizationID {
+		mockCourseOrgs[courseID] = orgID
+	}
+	return course
+}
+
 // CreateUser создает нового пользователя
 func (s *MockStorage) CreateUser(user models.User) error {
-	// Проверяем, что пользователя с таким именем еще нет
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	_, err := mockInsertUser(user, s.organization(), true)
+	return err
+}
+
+// mockInsertUser добавляет пользователя в организацию orgID и возвращает его ID; mockMu должен быть захвачен
+func mockInsertUser(user models.User, orgID int, active bool) (int, error) {
+	// Как и в базе данных, имя пользователя и email должны быть уникальны
 	if _, exists := mockUsersByUsername[user.Username]; exists {
-		return errors.New("username already exists")
+		return 0, ErrDuplicateUsername
+	}
+	if mockUsernameReserved(user.Username, 0) {
+		return 0, ErrDuplicateUsername
+	}
+	for _, existing := range mockUsers {
+		if existing.Email == user.Email {
+			return 0, ErrDuplicateEmail
+		}
 	}
 
-	// Генерируем новый ID
-	newID := len(mockUsers) + 1
+	// Генерируем новый ID (максимальный существующий + 1, чтобы не пересечься после удалений)
+	newID := 1
+	for id := range mockUsers {
+		if id >= newID {
+			newID = id + 1
+		}
+	}
 	user.ID = newID
+	user.IsAdmin = false
+	user.IsActive = active
+	user.Version = 1
 
 	// Сохраняем пользователя
 	mockUsers[newID] = user
 	mockUsersByUsername[user.Username] = newID
+	if orgID != models.DefaultOrganizationID {
+		mockUserOrgs[newID] = orgID
+	}
 
-	return nil
+	appendMockEvent(models.EventUserRegistered, newID, map[string]interface{}{
+		"userId":   newID,
+		"username": user.Username,
+	})
+
+	return newID, nil
 }
 
 // GetUserByUsername возвращает пользователя по имени пользователя
 func (s *MockStorage) GetUserByUsername(username string) (models.User, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	userID, exists := mockUsersByUsername[username]
 	if !exists {
-		return models.User{}, errors.New("user not found")
+		return models.User{}, ErrUserNotFound
 	}
 
 	user, exists := mockUsers[userID]
 	if !exists {
-		return models.User{}, errors.New("user not found")
+		return models.User{}, ErrUserNotFound
 	}
 
 	return user, nil
@@ -188,9 +379,12 @@ func (s *MockStorage) GetUserByUsername(username string) (models.User, error) {
 
 // GetUserByID возвращает пользователя по ID
 func (s *MockStorage) GetUserByID(id int) (models.User, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	user, exists := mockUsers[id]
 	if !exists {
-		return models.User{}, errors.New("user not found")
+		return models.User{}, ErrUserNotFound
 	}
 
 	return user, nil
@@ -198,9 +392,12 @@ func (s *MockStorage) GetUserByID(id int) (models.User, error) {
 
 // UpdateUserLastLogin обновляет время последнего входа пользователя
 func (s *MockStorage) UpdateUserLastLogin(userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	user, exists := mockUsers[userID]
 	if !exists {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 
 	// В моковой реализации мы просто отмечаем, что обновление произошло
@@ -212,9 +409,12 @@ func (s *MockStorage) UpdateUserLastLogin(userID int) error {
 
 // Enable2FA включает двухфакторную аутентификацию для пользователя
 func (s *MockStorage) Enable2FA(userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	user, exists := mockUsers[userID]
 	if !exists {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 
 	user.Is2FAEnabled = true
@@ -225,25 +425,62 @@ func (s *MockStorage) Enable2FA(userID int) error {
 
 // IsAdmin проверяет, является ли пользователь администратором
 func (s *MockStorage) IsAdmin(userID int) (bool, error) {
-	// В моковой реализации только пользователь с ID=1 является администратором
-	return userID == 1, nil
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[userID]
+	if !exists {
+		return false, ErrUserNotFound
+	}
+	return user.IsAdmin, nil
 }
 
-// GetAllUsers возвращает список всех пользователей из моковых данных
-func (s *MockStorage) GetAllUsers() ([]models.User, error) {
-	var users []models.User
-	for _, user := range mockUsers {
-		users = append(users, user)
+// ListUserSummaries возвращает всех пользователей без секретов из моковых данных
+func (s *MockStorage) ListUserSummaries() ([]models.UserSummary, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return mockUsersWhere(func(user models.User) bool { return s.userVisible(user.ID) }), nil
+}
+
+// GetUserPublicByID возвращает пользователя по ID без секретов из моковых данных
+func (s *MockStorage) GetUserPublicByID(id int) (models.UserSummary, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[id]
+	if !exists || !s.userVisible(id) {
+		return models.UserSummary{}, ErrUserNotFound
+	}
+	return user.Summary(), nil
+}
+
+// mockCheckVersion повторяет проверку версии из bumpUserVersion
+func mockCheckVersion(user models.User, expected int) error {
+	switch {
+	case expected == AnyVersion:
+		return nil
+	case expected <= 0:
+		return ErrVersionRequired
+	case expected != user.Version:
+		return ErrVersionConflict
 	}
-	return users, nil
+	return nil
 }
 
 // UpdateUserProfile обновляет профиль пользователя в моковых данных
 func (s *MockStorage) UpdateUserProfile(userID int, data models.UpdateProfileRequest) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	user, exists := mockUsers[userID]
 	if !exists {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
+	if err := mockCheckVersion(user, data.Version); err != nil {
+		return err
+	}
+	user.Version++
 
 	if data.Email != "" {
 		user.Email = data.Email
@@ -253,12 +490,35 @@ func (s *MockStorage) UpdateUserProfile(userID int, data models.UpdateProfileReq
 		user.FullName = data.FullName
 	}
 
+	if data.Locale != "" {
+		user.Locale = data.Locale
+	}
+
+	if data.Timezone != "" {
+		user.Timezone = data.Timezone
+	}
+
 	if data.Password != "" {
+		if s.PasswordHistory > 0 {
+			for _, hash := range append([]string{user.PasswordHash}, mockPasswordHistory[userID]...) {
+				if bcrypt.CompareHashAndPassword([]byte(hash), []byte(data.Password)) == nil {
+					return ErrPasswordReused
+				}
+			}
+		}
 		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(data.Password), bcrypt.DefaultCost)
 		if err != nil {
 			return err
 		}
+		if s.PasswordHistory > 0 {
+			history := append([]string{user.PasswordHash}, mockPasswordHistory[userID]...)
+			mockPasswordHistory[userID] = history[:min(len(history), s.PasswordHistory-1)]
+		}
 		user.PasswordHash = string(hashedPassword)
+		if state, ok := mockSessions[userID]; ok {
+			state.MustChangePassword = false
+			mockSessions[userID] = state
+		}
 	}
 
 	mockUsers[userID] = user
@@ -266,71 +526,167 @@ func (s *MockStorage) UpdateUserProfile(userID int, data models.UpdateProfileReq
 }
 
 // GetUsersByRole возвращает список пользователей с определенной ролью из моковых данных
-func (s *MockStorage) GetUsersByRole(isAdmin bool) ([]models.User, error) {
-	var users []models.User
-	for _, user := range mockUsers {
-		if user.IsAdmin == isAdmin {
-			users = append(users, user)
-		}
-	}
-	return users, nil
+func (s *MockStorage) GetUsersByRole(isAdmin bool) ([]models.UserSummary, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return mockUsersWhere(func(user models.User) bool { return user.IsAdmin == isAdmin }), nil
 }
 
 // SearchUsers ищет пользователей по имени пользователя, email или полному имени в моковых данных
-func (s *MockStorage) SearchUsers(query string) ([]models.User, error) {
+func (s *MockStorage) SearchUsers(query string) ([]models.UserSummary, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	// Поиск без учета регистра, как LIKE с collation по умолчанию в MySQL
 	query = strings.ToLower(query)
-	var users []models.User
-	for _, user := range mockUsers {
-		if strings.Contains(strings.ToLower(user.Username), query) ||
+	return mockUsersWhere(func(user models.User) bool {
+		return s.userVisible(user.ID) && (strings.Contains(strings.ToLower(user.Username), query) ||
 			strings.Contains(strings.ToLower(user.Email), query) ||
-			strings.Contains(strings.ToLower(user.FullName), query) {
-			users = append(users, user)
-		}
-	}
-	return users, nil
+			strings.Contains(strings.ToLower(user.FullName), query))
+	}), nil
 }
 
 // UpdateUserStatus обновляет статус пользователя в моковых данных
-func (s *MockStorage) UpdateUserStatus(userID int, isActive bool) error {
+func (s *MockStorage) UpdateUserStatus(userID int, isActive bool, version int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	user, exists := mockUsers[userID]
 	if !exists {
-		return errors.New("user not found")
+		return ErrUserNotFound
+	}
+	if err := mockCheckVersion(user, version); err != nil {
+		return err
 	}
+	user.Version++
 	user.IsActive = isActive
 	mockUsers[userID] = user
+	appendMockEvent(models.EventUserStatusChanged, userID, map[string]interface{}{
+		"userId":   userID,
+		"isActive": isActive,
+	})
 	return nil
 }
 
 // PromoteToAdmin повышает пользователя до администратора в моковых данных
 func (s *MockStorage) PromoteToAdmin(userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	user, exists := mockUsers[userID]
 	if !exists {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 	user.IsAdmin = true
+	user.Version++
 	mockUsers[userID] = user
+	appendMockEvent(models.EventUserRoleChanged, userID, map[string]interface{}{
+		"userId":  userID,
+		"isAdmin": true,
+	})
 	return nil
 }
 
 // DemoteFromAdmin понижает пользователя с роли администратора в моковых данных
 func (s *MockStorage) DemoteFromAdmin(userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
 	user, exists := mockUsers[userID]
 	if !exists {
-		return errors.New("user not found")
+		return ErrUserNotFound
 	}
 	user.IsAdmin = false
+	user.Version++
 	mockUsers[userID] = user
+	appendMockEvent(models.EventUserRoleChanged, userID, map[string]interface{}{
+		"userId":  userID,
+		"isAdmin": false,
+	})
 	return nil
 }
 
 func (s *MockStorage) ClearOTPCode(userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	delete(mockOTPCodes, userID)
+	return nil
+}
+
+func (s *MockStorage) VerifyOTPCode(userID int, code string, maxAttempts int) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	otp, exists := mockOTPCodes[userID]
+	if !exists || otp.code == "" || otp.attempts >= maxAttempts {
+		return false, nil
+	}
+	otp.attempts++
+	mockOTPCodes[userID] = otp
+
+	if time.Now().After(otp.expiresAt) {
+		return false, nil
+	}
+
+	if subtle.ConstantTimeCompare([]byte(code), []byte(otp.code)) == 1 {
+		return true, nil
+	}
+	if otp.attempts >= maxAttempts {
+		delete(mockOTPCodes, userID)
+		return false, ErrOTPAttemptsExceeded
+	}
+	return false, nil
+}
+
+func (s *MockStorage) SaveOTPCode(userID int, code string, ttl time.Duration) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	mockOTPCodes[userID] = mockOTP{code: code, expiresAt: time.Now().Add(ttl)}
 	return nil
 }
 
-func (s *MockStorage) VerifyOTPCode(userID int, code string) (bool, error) {
-	return true, nil
+// PurgeExpiredOTPCodes удаляет OTP-коды с истекшим сроком действия из моковых данных
+func (s *MockStorage) PurgeExpiredOTPCodes() (int64, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	var purged int64
+	now := time.Now()
+	for userID, otp := range mockOTPCodes {
+		if otp.expiresAt.Before(now) {
+			delete(mockOTPCodes, userID)
+			purged++
+		}
+	}
+	return purged, nil
 }
 
-func (s *MockStorage) SaveOTPCode(userID int, code string) error {
+// DeleteUser удаляет пользователя и его прогресс из моковых данных
+func (s *MockStorage) DeleteUser(userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[userID]
+	if !exists {
+		return ErrUserNotFound
+	}
+	delete(mockUsers, userID)
+	delete(mockUsersByUsername, user.Username)
+	delete(mockUserProgress, userID)
+	delete(mockOTPCodes, userID)
+	delete(mockPendingRegistrations, userID)
+	delete(mockAccessExpiry, userID)
+	delete(mockCourseVisits, userID)
+	delete(mockTaskNotes, userID)
+	bookmarks := mockBookmarks[:0]
+	for _, b := range mockBookmarks {
+		if b.UserID != userID {
+			bookmarks = append(bookmarks, b)
+		}
+	}
+	mockBookmarks = bookmarks
 	return nil
 }
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// mockSurveys — опросы по ID курса
+	mockSurveys              = map[int]models.Survey{}
+	mockNextSurveyID         = 1
+	mockNextSurveyQuestionID = 1
+	mockSurveyResponses      = map[int][]models.SurveyResponse{}
+	mockNextSurveyResponseID = 1
+	// mockSurveyParticipants — ID опроса → пользователи, прошедшие его
+	mockSurveyParticipants = map[int]map[int]bool{}
+)
+
+// mockSurveyCopy возвращает опрос с собственной копией вопросов и числом ответов. Вызывается под mockMu.
+func mockSurveyCopy(survey models.Survey) models.Survey {
+	survey.Questions = append([]models.SurveyQuestion{}, survey.Questions...)
+	survey.ResponseCount = len(mockSurveyResponses[survey.ID])
+	return survey
+}
+
+// SaveCourseSurvey создает или обновляет опрос курса в моковых данных
+func (s *MockStorage) SaveCourseSurvey(survey models.Survey) (models.Survey, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockCourseIndex(survey.CourseID) < 0 || !s.courseVisible(survey.CourseID) {
+		return models.Survey{}, ErrCourseNotFound
+	}
+	now := time.Now().UTC()
+	existing, ok := mockSurveys[survey.CourseID]
+	if !ok {
+		existing = models.Survey{ID: mockNextSurveyID, CourseID: survey.CourseID, CreatedAt: now}
+		mockNextSurveyID++
+	}
+	existing.Title = survey.Title
+	existing.Anonymous = survey.Anonymous
+	existing.Active = survey.Active
+	existing.UpdatedAt = now
+	if survey.Questions != nil || !ok {
+		existing.Questions = []models.SurveyQuestion{}
+		for _, question := range survey.Questions {
+			question.ID = mockNextSurveyQuestionID
+			mockNextSurveyQuestionID++
+			existing.Questions = append(existing.Questions, question)
+		}
+	}
+	mockSurveys[survey.CourseID] = existing
+	if survey.Questions != nil {
+		mockRecordCourseChange(models.CourseChange{
+			CourseID: survey.CourseID, Kind: models.CourseChangeSurveyUpdated, Title: survey.Title,
+		})
+	}
+	return mockSurveyCopy(existing), nil
+}
+
+// GetCourseSurvey возвращает опрос курса из моковых данных
+func (s *MockStorage) GetCourseSurvey(courseID int) (models.Survey, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	survey, ok := mockSurveys[courseID]
+	if !ok || !s.courseVisible(courseID) {
+		return models.Survey{}, ErrSurveyNotFound
+	}
+	return mockSurveyCopy(survey), nil
+}
+
+// DeleteCourseSurvey удаляет опрос курса из моковых данных
+func (s *MockStorage) DeleteCourseSurvey(courseID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	survey, ok := mockSurveys[courseID]
+	if !ok {
+		return ErrSurveyNotFound
+	}
+	delete(mockSurveys, courseID)
+	delete(mockSurveyResponses, survey.ID)
+	delete(mockSurveyParticipants, survey.ID)
+	return nil
+}
+
+// SubmitSurveyResponse сохраняет ответы пользователя в моковых данных
+func (s *MockStorage) SubmitSurveyResponse(survey models.Survey, userID int, answers []models.SurveyAnswer) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if mockSurveyParticipants[survey.ID][userID] {
+		return ErrSurveyAlreadyAnswered
+	}
+	if mockSurveyParticipants[survey.ID] == nil {
+		mockSurveyParticipants[survey.ID] = map[int]bool{}
+	}
+	mockSurveyParticipants[survey.ID][userID] = true
+
+	response := models.SurveyResponse{
+		ID:        mockNextSurveyResponseID,
+		CreatedAt: time.Now().UTC(),
+		Answers:   append([]models.SurveyAnswer{}, answers...),
+	}
+	mockNextSurveyResponseID++
+	if !survey.Anonymous {
+		response.UserID = &userID
+	}
+	mockSurveyResponses[survey.ID] = append(mockSurveyResponses[survey.ID], response)
+	return nil
+}
+
+// HasAnsweredSurvey сообщает, прошел ли пользователь опрос, по моковым данным
+func (s *MockStorage) HasAnsweredSurvey(surveyID, userID int) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return mockSurveyParticipants[surveyID][userID], nil
+}
+
+// ListSurveyResponses возвращает ответы на опрос из моковых данных
+func (s *MockStorage) ListSurveyResponses(surveyID int) ([]models.SurveyResponse, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	return append([]models.SurveyResponse{}, mockSurveyResponses[surveyID]...), nil
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+// mockTaskNotes — пользователь → задание → заметка
+var mockTaskNotes = map[int]map[int]models.TaskNote{}
+
+// mockTaskTitle возвращает название задания; mockMu должен быть захвачен
+func mockTaskTitle(taskID int) string {
+	for _, task := range mockTasks {
+		if task.ID == taskID {
+			return task.Title
+		}
+	}
+	return ""
+}
+
+// GetTaskNote возвращает заметку из моковых данных
+func (s *MockStorage) GetTaskNote(userID, taskID int) (models.TaskNote, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	note, ok := mockTaskNotes[userID][taskID]
+	if !ok {
+		return models.TaskNote{}, ErrNoteNotFound
+	}
+	note.TaskTitle = mockTaskTitle(taskID)
+	return note, nil
+}
+
+// SaveTaskNote сохраняет заметку в моковых данных
+func (s *MockStorage) SaveTaskNote(userID, taskID int, body string, expectedVersion int) (models.TaskNote, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	note, exists := mockTaskNotes[userID][taskID]
+	switch {
+	case expectedVersion != 0 && (!exists || note.Version != expectedVersion):
+		return models.TaskNote{}, ErrNoteConflict
+	case !exists:
+		courseID := mockTaskCourseID(taskID)
+		if courseID == 0 {
+			return models.TaskNote{}, ErrTaskNotFound
+		}
+		note = models.TaskNote{TaskID: taskID, CourseID: courseID, CreatedAt: time.Now().UTC().Truncate(time.Second)}
+		if mockTaskNotes[userID] == nil {
+			mockTaskNotes[userID] = map[int]models.TaskNote{}
+		}
+	}
+	note.Body = body
+	note.Version++
+	note.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	mockTaskNotes[userID][taskID] = note
+	note.TaskTitle = mockTaskTitle(taskID)
+	return note, nil
+}
+
+// DeleteTaskNote удаляет заметку из моковых данных
+func (s *MockStorage) DeleteTaskNote(userID, taskID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockTaskNotes[userID][taskID]; !ok {
+		return ErrNoteNotFound
+	}
+	delete(mockTaskNotes[userID], taskID)
+	return nil
+}
+
+// ListTaskNotes возвращает заметки пользователя из моковых данных
+func (s *MockStorage) ListTaskNotes(userID int) ([]models.TaskNote, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	notes := []models.TaskNote{}
+	for taskID, note := range mockTaskNotes[userID] {
+		note.TaskTitle = mockTaskTitle(taskID)
+		notes = append(notes, note)
+	}
+	sort.Slice(notes, func(i, j int) bool {
+		if !notes[i].UpdatedAt.Equal(notes[j].UpdatedAt) {
+			return notes[i].UpdatedAt.After(notes[j].UpdatedAt)
+		}
+		return notes[i].TaskID < notes[j].TaskID
+	})
+	return notes, nil
+}
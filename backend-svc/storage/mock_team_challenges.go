@@ -0,0 +1,138 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockTeamChallenges    = map[int]models.TeamChallenge{}
+	mockNextTeamChallenge = 1
+)
+
+// mockCheckTeamChallengeGroups проверяет, что все группы соревнования существуют
+func mockCheckTeamChallengeGroups(groups []string) error {
+	for _, group := range groups {
+		if !mockGroupExists(group) {
+			return ErrGroupNotFound
+		}
+	}
+	return nil
+}
+
+// mockTeamChallengeView копирует соревнование с отсортированными группами
+func mockTeamChallengeView(challenge models.TeamChallenge) models.TeamChallenge {
+	challenge.Groups = append([]string{}, challenge.Groups...)
+	sort.Strings(challenge.Groups)
+	return challenge
+}
+
+// CreateTeamChallenge добавляет соревнование в моковые данные
+func (s *MockStorage) CreateTeamChallenge(challenge models.TeamChallenge) (models.TeamChallenge, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if err := mockCheckTeamChallengeGroups(challenge.Groups); err != nil {
+		return models.TeamChallenge{}, err
+	}
+	challenge = mockTeamChallengeView(challenge)
+	challenge.ID = mockNextTeamChallenge
+	mockNextTeamChallenge++
+	challenge.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	challenge.UpdatedAt = challenge.CreatedAt
+	mockTeamChallenges[challenge.ID] = challenge
+	return challenge, nil
+}
+
+// UpdateTeamChallenge изменяет соревнование в моковых данных
+func (s *MockStorage) UpdateTeamChallenge(challenge models.TeamChallenge) (models.TeamChallenge, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	existing, ok := mockTeamChallenges[challenge.ID]
+	if !ok {
+		return models.TeamChallenge{}, ErrTeamChallengeNotFound
+	}
+	if err := mockCheckTeamChallengeGroups(challenge.Groups); err != nil {
+		return models.TeamChallenge{}, err
+	}
+	challenge = mockTeamChallengeView(challenge)
+	challenge.CreatedBy, challenge.CreatedAt = existing.CreatedBy, existing.CreatedAt
+	challenge.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	mockTeamChallenges[challenge.ID] = challenge
+	return challenge, nil
+}
+
+// DeleteTeamChallenge удаляет соревнование из моковых данных
+func (s *MockStorage) DeleteTeamChallenge(id int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockTeamChallenges[id]; !ok {
+		return ErrTeamChallengeNotFound
+	}
+	delete(mockTeamChallenges, id)
+	return nil
+}
+
+// GetTeamChallenge возвращает соревнование из моковых данных
+func (s *MockStorage) GetTeamChallenge(id int) (models.TeamChallenge, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	challenge, ok := mockTeamChallenges[id]
+	if !ok {
+		return models.TeamChallenge{}, ErrTeamChallengeNotFound
+	}
+	return mockTeamChallengeView(challenge), nil
+}
+
+// ListTeamChallenges возвращает соревнования из моковых данных по убыванию даты начала
+func (s *MockStorage) ListTeamChallenges() ([]models.TeamChallenge, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	challenges := []models.TeamChallenge{}
+	for _, challenge := range mockTeamChallenges {
+		challenges = append(challenges, mockTeamChallengeView(challenge))
+	}
+	sort.Slice(challenges, func(i, j int) bool {
+		if !challenges[i].StartsAt.Equal(challenges[j].StartsAt) {
+			return challenges[i].StartsAt.After(challenges[j].StartsAt)
+		}
+		return challenges[i].ID > challenges[j].ID
+	})
+	return challenges, nil
+}
+
+// TeamChallengeCompletions считает задания участников групп по моковым данным. Время выполнения
+// там не хранится, поэтому учитываются все выполненные задания.
+func (s *MockStorage) TeamChallengeCompletions(challenge models.TeamChallenge) (map[string][]int, error) {
+	members := map[string][]int{}
+	mockGroupsMu.Lock()
+	for _, group := range challenge.Groups {
+		members[group] = []int{}
+		for userID := range mockGroupMembers[group] {
+			members[group] = append(members[group], userID)
+		}
+	}
+	mockGroupsMu.Unlock()
+
+	mockMu.Lock()
+	defer mockMu.Unlock()
+	completions := map[string][]int{}
+	for group, userIDs := range members {
+		completions[group] = []int{}
+		for _, userID := range userIDs {
+			completed := 0
+			for _, done := range mockUserProgress[userID].Completed {
+				if done {
+					completed++
+				}
+			}
+			completions[group] = append(completions[group], completed)
+		}
+	}
+	return completions, nil
+}
@@ -0,0 +1,123 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+type mockTelegramLinkCode struct {
+	userID    int
+	expiresAt time.Time
+}
+
+type mockTelegramReminder struct {
+	userID, taskID int
+	dueAt          time.Time
+}
+
+var (
+	// mockTelegramAccounts — пользователь → привязанный чат
+	mockTelegramAccounts = map[int]models.TelegramAccount{}
+	// mockTelegramLinkCodes — хеш кода привязки → владелец
+	mockTelegramLinkCodes = map[string]mockTelegramLinkCode{}
+	mockTelegramReminders = map[mockTelegramReminder]bool{}
+)
+
+// SetTelegramLinkCode заменяет код привязки в моковых данных
+func (s *MockStorage) SetTelegramLinkCode(userID int, codeHash string, expiresAt time.Time) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for hash, code := range mockTelegramLinkCodes {
+		if code.userID == userID {
+			delete(mockTelegramLinkCodes, hash)
+		}
+	}
+	mockTelegramLinkCodes[codeHash] = mockTelegramLinkCode{userID: userID, expiresAt: expiresAt}
+	return nil
+}
+
+// LinkTelegramAccount привязывает чат в моковых данных
+func (s *MockStorage) LinkTelegramAccount(codeHash string, account models.TelegramAccount) (models.TelegramAccount, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	code, ok := mockTelegramLinkCodes[codeHash]
+	if !ok || !time.Now().Before(code.expiresAt) {
+		return models.TelegramAccount{}, ErrTelegramLinkCodeInvalid
+	}
+	delete(mockTelegramLinkCodes, codeHash)
+	for userID, linked := range mockTelegramAccounts {
+		if linked.ChatID == account.ChatID {
+			delete(mockTelegramAccounts, userID)
+		}
+	}
+	account.UserID = code.userID
+	account.LinkedAt = time.Now().UTC().Truncate(time.Second)
+	mockTelegramAccounts[account.UserID] = account
+	return account, nil
+}
+
+// GetTelegramAccount возвращает чат пользователя из моковых данных
+func (s *MockStorage) GetTelegramAccount(userID int) (models.TelegramAccount, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	account, ok := mockTelegramAccounts[userID]
+	if !ok {
+		return models.TelegramAccount{}, ErrTelegramAccountNotFound
+	}
+	return account, nil
+}
+
+// GetTelegramAccountByChat возвращает привязку чата из моковых данных
+func (s *MockStorage) GetTelegramAccountByChat(chatID int64) (models.TelegramAccount, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	for _, account := range mockTelegramAccounts {
+		if account.ChatID == chatID {
+			return account, nil
+		}
+	}
+	return models.TelegramAccount{}, ErrTelegramAccountNotFound
+}
+
+// ListTelegramAccounts возвращает привязанные чаты из моковых данных
+func (s *MockStorage) ListTelegramAccounts() ([]models.TelegramAccount, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	accounts := make([]models.TelegramAccount, 0, len(mockTelegramAccounts))
+	for _, account := range mockTelegramAccounts {
+		accounts = append(accounts, account)
+	}
+	sort.Slice(accounts, func(i, j int) bool { return accounts[i].UserID < accounts[j].UserID })
+	return accounts, nil
+}
+
+// DeleteTelegramAccount отвязывает чат в моковых данных
+func (s *MockStorage) DeleteTelegramAccount(userID int) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if _, ok := mockTelegramAccounts[userID]; !ok {
+		return ErrTelegramAccountNotFound
+	}
+	delete(mockTelegramAccounts, userID)
+	return nil
+}
+
+// MarkTelegramDeadlineReminder отмечает напоминание в моковых данных
+func (s *MockStorage) MarkTelegramDeadlineReminder(userID, taskID int, dueAt time.Time) (bool, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	key := mockTelegramReminder{userID: userID, taskID: taskID, dueAt: dueAt.UTC()}
+	if mockTelegramReminders[key] {
+		return false, nil
+	}
+	mockTelegramReminders[key] = true
+	return true, nil
+}
@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockTickets           = map[int]models.Ticket{}
+	mockNextTicketID      = 1
+	mockTicketReplies     = []models.TicketReply{}
+	mockNextTicketReplyID = 1
+)
+
+// mockSetTicketStatus меняет статус и время закрытия обращения. Вызывается под mockMu.
+func mockSetTicketStatus(ticket *models.Ticket, status string, now time.Time) {
+	ticket.Status = status
+	ticket.ClosedAt = nil
+	if status == models.TicketResolved || status == models.TicketClosed {
+		ticket.ClosedAt = &now
+	}
+}
+
+// CreateTicket сохраняет обращение в моковых данных
+func (s *MockStorage) CreateTicket(ticket models.Ticket, body string) (models.Ticket, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if ticket.TaskID != nil {
+		courseID := mockTaskCourseID(*ticket.TaskID)
+		if courseID == 0 || (ticket.CourseID != nil && *ticket.CourseID != courseID) {
+			return models.Ticket{}, ErrTaskNotFound
+		}
+		ticket.CourseID = &courseID
+	} else if ticket.CourseID != nil && mockCourseIndex(*ticket.CourseID) < 0 {
+		return models.Ticket{}, ErrCourseNotFound
+	}
+
+	now := time.Now().UTC()
+	ticket.ID = mockNextTicketID
+	mockNextTicketID++
+	ticket.Status = models.TicketOpen
+	ticket.CreatedAt, ticket.UpdatedAt = now, now
+	mockTickets[ticket.ID] = ticket
+
+	authorID := ticket.UserID
+	mockTicketReplies = append(mockTicketReplies, models.TicketReply{
+		ID: mockNextTicketReplyID, TicketID: ticket.ID, AuthorID: &authorID, Body: body, CreatedAt: now,
+	})
+	mockNextTicketReplyID++
+	return ticket, nil
+}
+
+// GetTicket возвращает обращение из моковых данных
+func (s *MockStorage) GetTicket(id int) (models.Ticket, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	ticket, ok := mockTickets[id]
+	if !ok {
+		return models.Ticket{}, ErrTicketNotFound
+	}
+	return ticket, nil
+}
+
+// ListTickets возвращает обращения из моковых данных по фильтру
+func (s *MockStorage) ListTickets(filter models.TicketFilter) ([]models.Ticket, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	tickets := []models.Ticket{}
+	for _, ticket := range mockTickets {
+		if filter.UserID != 0 && ticket.UserID != filter.UserID {
+			continue
+		}
+		if filter.AssigneeID != 0 && (ticket.AssigneeID == nil || *ticket.AssigneeID != filter.AssigneeID) {
+			continue
+		}
+		if filter.Status != "" && ticket.Status != filter.Status {
+			continue
+		}
+		tickets = append(tickets, ticket)
+	}
+	sort.Slice(tickets, func(i, j int) bool {
+		if !tickets[i].UpdatedAt.Equal(tickets[j].UpdatedAt) {
+			return tickets[i].UpdatedAt.After(tickets[j].UpdatedAt)
+		}
+		return tickets[i].ID > tickets[j].ID
+	})
+	if filter.Limit > 0 && len(tickets) > filter.Limit {
+		tickets = tickets[:filter.Limit]
+	}
+	return tickets, nil
+}
+
+// AddTicketReply сохраняет сообщение обращения в моковых данных
+func (s *MockStorage) AddTicketReply(reply models.TicketReply, status string) (models.TicketReply, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	ticket, ok := mockTickets[reply.TicketID]
+	if !ok {
+		return models.TicketReply{}, ErrTicketNotFound
+	}
+	now := time.Now().UTC()
+	if status != "" {
+		mockSetTicketStatus(&ticket, status, now)
+	}
+	ticket.UpdatedAt = now
+	mockTickets[ticket.ID] = ticket
+
+	reply.ID = mockNextTicketReplyID
+	mockNextTicketReplyID++
+	reply.CreatedAt = now
+	mockTicketReplies = append(mockTicketReplies, reply)
+	return reply, nil
+}
+
+// ListTicketReplies возвращает сообщения обращения из моковых данных
+func (s *MockStorage) ListTicketReplies(ticketID int, includeInternal bool) ([]models.TicketReply, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	replies := []models.TicketReply{}
+	for _, reply := range mockTicketReplies {
+		if reply.TicketID == ticketID && (includeInternal || !reply.Internal) {
+			replies = append(replies, reply)
+		}
+	}
+	return replies, nil
+}
+
+// SetTicketStatus меняет статус обращения в моковых данных
+func (s *MockStorage) SetTicketStatus(ticketID int, status string) (models.Ticket, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	ticket, ok := mockTickets[ticketID]
+	if !ok {
+		return models.Ticket{}, ErrTicketNotFound
+	}
+	now := time.Now().UTC()
+	mockSetTicketStatus(&ticket, status, now)
+	ticket.UpdatedAt = now
+	mockTickets[ticketID] = ticket
+	return ticket, nil
+}
+
+// SetTicketAssignee назначает обращение преподавателю в моковых данных
+func (s *MockStorage) SetTicketAssignee(ticketID int, assigneeID *int) (models.Ticket, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	ticket, ok := mockTickets[ticketID]
+	if !ok {
+		return models.Ticket{}, ErrTicketNotFound
+	}
+	ticket.AssigneeID = assigneeID
+	ticket.UpdatedAt = time.Now().UTC()
+	mockTickets[ticketID] = ticket
+	return ticket, nil
+}
@@ -0,0 +1,47 @@
+package storage
+
+import "time"
+
+// PurgeExpiredTokens удаляет просроченные токены и ссылки из моковых данных
+func (s *MockStorage) PurgeExpiredTokens(now time.Time) (map[string]int64, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	purged := map[string]int64{}
+	for hash, token := range mockLockTokens {
+		if token.expiresAt.Before(now) {
+			delete(mockLockTokens, hash)
+			purged["account_lock_tokens"]++
+		}
+	}
+	for id, change := range mockEmailChanges {
+		if change.CancelExpiresAt.Before(now) {
+			delete(mockEmailChanges, id)
+			purged["email_changes"]++
+		}
+	}
+	for id, invitation := range mockInvitations {
+		if invitation.ExpiresAt.Before(now) {
+			delete(mockInvitations, id)
+			purged["invitations"]++
+		}
+	}
+	// Назначенные курсы остаются, как при ON DELETE SET NULL
+	for userID, assignments := range mockCourseAssignment {
+		for i, assignment := range assignments {
+			if assignment.InvitationID != nil {
+				if _, ok := mockInvitations[*assignment.InvitationID]; !ok {
+					assignments[i].InvitationID = nil
+				}
+			}
+		}
+		mockCourseAssignment[userID] = assignments
+	}
+	for hash, code := range mockTelegramLinkCodes {
+		if code.expiresAt.Before(now) {
+			delete(mockTelegramLinkCodes, hash)
+			purged["telegram_link_codes"]++
+		}
+	}
+	return purged, nil
+}
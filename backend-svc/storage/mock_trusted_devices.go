@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockTrustedDevices      = map[int64]models.TrustedDevice{}
+	mockTrustedDeviceNextID int64
+)
+
+// CreateTrustedDevice сохраняет доверенное устройство в моковых данных
+func (s *MockStorage) CreateTrustedDevice(device models.TrustedDevice) (int64, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	mockTrustedDeviceNextID++
+	now := time.Now().UTC()
+	device.ID = mockTrustedDeviceNextID
+	device.CreatedAt = now
+	device.LastUsedAt = now
+	mockTrustedDevices[device.ID] = device
+	return device.ID, nil
+}
+
+// GetTrustedDevice возвращает действующее устройство из моковых данных
+func (s *MockStorage) GetTrustedDevice(tokenHash string) (models.TrustedDevice, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	now := time.Now()
+	for _, device := range mockTrustedDevices {
+		if device.TokenHash == tokenHash && device.ExpiresAt.After(now) {
+			return device, nil
+		}
+	}
+	return models.TrustedDevice{}, ErrTrustedDeviceNotFound
+}
+
+// TouchTrustedDevice обновляет время использования устройства в моковых данных
+func (s *MockStorage) TouchTrustedDevice(id int64) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if device, exists := mockTrustedDevices[id]; exists {
+		device.LastUsedAt = time.Now().UTC()
+		mockTrustedDevices[id] = device
+	}
+	return nil
+}
+
+// ListTrustedDevices возвращает действующие устройства пользователя из моковых данных
+func (s *MockStorage) ListTrustedDevices(userID int) ([]models.TrustedDevice, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	now := time.Now()
+	devices := []models.TrustedDevice{}
+	for _, device := range mockTrustedDevices {
+		if device.UserID == userID && device.ExpiresAt.After(now) {
+			devices = append(devices, device)
+		}
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].LastUsedAt.After(devices[j].LastUsedAt) })
+	return devices, nil
+}
+
+// RevokeTrustedDevice удаляет устройство пользователя из моковых данных
+func (s *MockStorage) RevokeTrustedDevice(userID int, id int64) error {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	device, exists := mockTrustedDevices[id]
+	if !exists || device.UserID != userID {
+		return ErrTrustedDeviceNotFound
+	}
+	delete(mockTrustedDevices, id)
+	return nil
+}
+
+// RevokeTrustedDevices удаляет все устройства пользователя из моковых данных
+func (s *MockStorage) RevokeTrustedDevices(userID int) (int64, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	var revoked int64
+	for id, device := range mockTrustedDevices {
+		if device.UserID == userID {
+			delete(mockTrustedDevices, id)
+			revoked++
+		}
+	}
+	return revoked, nil
+}
+
+// PurgeTrustedDevices удаляет просроченные устройства из моковых данных
+func (s *MockStorage) PurgeTrustedDevices() (int64, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	var purged int64
+	now := time.Now()
+	for id, device := range mockTrustedDevices {
+		if !device.ExpiresAt.After(now) {
+			delete(mockTrustedDevices, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,106 @@
+package storage
+
+import (
+	"lmsmodule/backend-svc/models"
+	"sort"
+	"time"
+)
+
+var (
+	mockUsernameHistory       []models.UsernameChange
+	mockUsernameHistoryNextID int64
+)
+
+// mockUsernameReserved сообщает, зарезервировано ли имя за пользователем, отличным от exceptUserID
+func mockUsernameReserved(username string, exceptUserID int) bool {
+	now := time.Now()
+	for _, change := range mockUsernameHistory {
+		if change.OldUsername != username || change.UserID == exceptUserID || !change.ReservedUntil.After(now) {
+			continue
+		}
+		// Как и ON DELETE CASCADE в базе, удаление пользователя снимает резерв его прежних имен
+		if _, exists := mockUsers[change.UserID]; exists {
+			return true
+		}
+	}
+	return false
+}
+
+// ChangeUsername переименовывает пользователя в моковых данных
+func (s *MockStorage) ChangeUsername(change models.UsernameChange, minInterval time.Duration) (models.UsernameChange, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	user, exists := mockUsers[change.UserID]
+	if !exists {
+		return models.UsernameChange{}, ErrUserNotFound
+	}
+
+	now := time.Now().UTC()
+	if minInterval > 0 {
+		for _, previous := range mockUsernameHistory {
+			if previous.UserID == user.ID && now.Before(previous.ChangedAt.Add(minInterval)) {
+				return models.UsernameChange{}, ErrUsernameChangeTooSoon
+			}
+		}
+	}
+	if mockUsernameReserved(change.NewUsername, user.ID) {
+		return models.UsernameChange{}, ErrUsernameReserved
+	}
+	if ownerID, taken := mockUsersByUsername[change.NewUsername]; taken && ownerID != user.ID {
+		return models.UsernameChange{}, ErrDuplicateUsername
+	}
+
+	change.OldUsername = user.Username
+	delete(mockUsersByUsername, user.Username)
+	user.Username = change.NewUsername
+	user.Version++
+	mockUsers[user.ID] = user
+	mockUsersByUsername[user.Username] = user.ID
+
+	mockUsernameHistoryNextID++
+	change.ID = mockUsernameHistoryNextID
+	change.ChangedAt = now
+	change.ReservedUntil = change.ReservedUntil.UTC()
+	mockUsernameHistory = append(mockUsernameHistory, change)
+
+	appendMockEvent(models.EventUserRenamed, user.ID, map[string]interface{}{
+		"userId":      user.ID,
+		"oldUsername": change.OldUsername,
+		"newUsername": change.NewUsername,
+	})
+	return change, nil
+}
+
+// ListUsernameHistory возвращает переименования пользователя из моковых данных, начиная с последнего
+func (s *MockStorage) ListUsernameHistory(userID int) ([]models.UsernameChange, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	changes := []models.UsernameChange{}
+	for _, change := range mockUsernameHistory {
+		if change.UserID == userID {
+			changes = append(changes, change)
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].ID > changes[j].ID })
+	return changes, nil
+}
+
+// ResolveUsername находит пользователя по текущему или прежнему имени в моковых данных
+func (s *MockStorage) ResolveUsername(username string) (int, error) {
+	mockMu.Lock()
+	defer mockMu.Unlock()
+
+	if userID, exists := mockUsersByUsername[username]; exists {
+		return userID, nil
+	}
+	for i := len(mockUsernameHistory) - 1; i >= 0; i-- {
+		if change := mockUsernameHistory[i]; change.OldUsername == username {
+			if _, exists := mockUsers[change.UserID]; exists {
+				return change.UserID, nil
+			}
+		}
+	}
+	return 0, ErrUserNotFound
+}
@@ -0,0 +1,284 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrCourseBanNotFound — пользователь не заблокирован в курсе
+	ErrCourseBanNotFound = errors.New("course ban not found")
+	// ErrReportNotFound — жалобы нет
+	ErrReportNotFound = errors.New("report not found")
+	// ErrReportResolved — по жалобе уже принято решение
+	ErrReportResolved = errors.New("report already resolved")
+)
+
+const reportColumns = "id, content_type, content_id, course_id, reporter_id, reason, status, resolved_by, resolved_at, note, created_at"
+
+// moderatedTables — таблицы контента, который модератор может скрыть
+var moderatedTables = map[string]struct {
+	table    string
+	notFound error
+}{
+	models.ContentComment: {"discussion_comments", ErrCommentNotFound},
+	models.ContentReview:  {"course_reviews", ErrReviewNotFound},
+}
+
+// execQueryer — *sql.DB или *sql.Tx
+type execQueryer interface {
+	queryer
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// rowExists сообщает, есть ли в таблице строка с данным ID. MySQL считает в RowsAffected
+// только измененные строки, поэтому UPDATE, который ничего не поменял, неотличим по нему
+// от UPDATE несуществующей строки.
+func rowExists(ctx context.Context, q queryer, table string, id int) (bool, error) {
+	var found int
+	err := q.QueryRowContext(ctx, "SELECT 1 FROM "+table+" WHERE id = ?", id).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check %s: %w", table, err)
+	}
+	return true, nil
+}
+
+// setContentHidden скрывает или возвращает контент
+func setContentHidden(ctx context.Context, tx *sql.Tx, contentType string, contentID, moderatorID int, hidden bool) error {
+	target, ok := moderatedTables[contentType]
+	if !ok {
+		return fmt.Errorf("unknown content type %q", contentType)
+	}
+	var hiddenAt, hiddenBy interface{}
+	if hidden {
+		hiddenAt, hiddenBy = time.Now().UTC(), moderatorID
+	}
+	res, err := tx.ExecContext(ctx, "UPDATE "+target.table+" SET hidden_at = ?, hidden_by = ? WHERE id = ?", hiddenAt, hiddenBy, contentID)
+	if err != nil {
+		return fmt.Errorf("moderate %s: %w", contentType, err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		if found, err := rowExists(ctx, tx, target.table, contentID); err != nil || !found {
+			if err == nil {
+				err = target.notFound
+			}
+			return err
+		}
+	}
+	// Скрытые отзывы не входят в рейтинг курса, скрытые сообщения — в поисковый индекс
+	if contentType == models.ContentReview {
+		var courseID int
+		if err := tx.QueryRowContext(ctx, "SELECT course_id FROM course_reviews WHERE id = ?", contentID).Scan(&courseID); err != nil {
+			return fmt.Errorf("get review course: %w", err)
+		}
+		return refreshCourseSummary(ctx, tx, courseID)
+	}
+	return insertOutboxEvent(ctx, tx, models.EventCommentModerated, 0, map[string]interface{}{
+		"commentId": contentID,
+		"hidden":    hidden,
+	})
+}
+
+// SetContentHidden скрывает или возвращает сообщение обсуждения или отзыв
+func (s *DBStorage) SetContentHidden(contentType string, contentID, moderatorID int, hidden bool) error {
+	ctx, done := s.startQuery("SetContentHidden")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		return setContentHidden(ctx, tx, contentType, contentID, moderatorID, hidden)
+	})
+}
+
+// SetCourseBan скрыто блокирует пользователя в курсе
+func (s *DBStorage) SetCourseBan(ban models.CourseBan) (models.CourseBan, error) {
+	ctx, done := s.startQuery("SetCourseBan")
+	defer done()
+
+	ban.CreatedAt = time.Now().UTC()
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := courseExists(ctx, tx, ban.CourseID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO course_bans (course_id, user_id, banned_by, reason, created_at) VALUES (?, ?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"course_id", "user_id"}, "banned_by", "reason", "created_at"),
+			ban.CourseID, ban.UserID, ban.BannedBy, ban.Reason, ban.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("save course ban: %w", err)
+		}
+		return nil
+	})
+	return ban, err
+}
+
+// DeleteCourseBan снимает блокировку пользователя в курсе
+func (s *DBStorage) DeleteCourseBan(courseID, userID int) error {
+	ctx, done := s.startQuery("DeleteCourseBan")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM course_bans WHERE course_id = ? AND user_id = ?", courseID, userID)
+	if err != nil {
+		return fmt.Errorf("delete course ban: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrCourseBanNotFound
+	}
+	return nil
+}
+
+// ListCourseBans возвращает заблокированных в курсе пользователей
+func (s *DBStorage) ListCourseBans(courseID int) ([]models.CourseBan, error) {
+	ctx, done := s.startQuery("ListCourseBans")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT course_id, user_id, banned_by, reason, created_at FROM course_bans WHERE course_id = ? ORDER BY created_at, user_id", courseID)
+	if err != nil {
+		return nil, fmt.Errorf("query course bans: %w", err)
+	}
+	defer rows.Close()
+
+	bans := []models.CourseBan{}
+	for rows.Next() {
+		var ban models.CourseBan
+		var bannedBy sql.NullInt64
+		if err := rows.Scan(&ban.CourseID, &ban.UserID, &bannedBy, &ban.Reason, &ban.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan course ban: %w", err)
+		}
+		ban.BannedBy = intOrNil(bannedBy)
+		bans = append(bans, ban)
+	}
+	return bans, rows.Err()
+}
+
+// ReportContent сохраняет жалобу; повторная жалоба того же пользователя возвращает существующую
+func (s *DBStorage) ReportContent(report models.ContentReport) (models.ContentReport, error) {
+	ctx, done := s.startQuery("ReportContent")
+	defer done()
+
+	report.Status = models.ReportOpen
+	report.CreatedAt = time.Now().UTC()
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		existing, err := scanReport(tx.QueryRowContext(ctx,
+			"SELECT "+reportColumns+" FROM content_reports WHERE content_type = ? AND content_id = ? AND reporter_id = ?",
+			report.ContentType, report.ContentID, report.ReporterID))
+		if err == nil {
+			report = existing
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO content_reports (content_type, content_id, course_id, reporter_id, reason, status, created_at) "+
+				"VALUES (?, ?, ?, ?, ?, ?, ?)",
+			report.ContentType, report.ContentID, report.CourseID, report.ReporterID, report.Reason, report.Status, report.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("insert report: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get report id: %w", err)
+		}
+		report.ID = int(id)
+		return nil
+	})
+	return report, err
+}
+
+// ListContentReports возвращает жалобы по статусу, старые первыми
+func (s *DBStorage) ListContentReports(status string, limit int) ([]models.ContentReport, error) {
+	ctx, done := s.startQuery("ListContentReports")
+	defer done()
+
+	query := "SELECT " + reportColumns + " FROM content_reports"
+	var args []interface{}
+	if status != "" {
+		query += " WHERE status = ?"
+		args = append(args, status)
+	}
+	query += " ORDER BY created_at, id LIMIT ?"
+	args = append(args, limit)
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query reports: %w", err)
+	}
+	defer rows.Close()
+
+	reports := []models.ContentReport{}
+	for rows.Next() {
+		report, err := scanReport(rows)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
+
+// ResolveContentReport принимает решение по жалобе и по остальным открытым жалобам на тот же контент
+func (s *DBStorage) ResolveContentReport(reportID, moderatorID int, status, note string) (models.ContentReport, error) {
+	ctx, done := s.startQuery("ResolveContentReport")
+	defer done()
+
+	var report models.ContentReport
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		report, err = scanReport(tx.QueryRowContext(ctx, "SELECT "+reportColumns+" FROM content_reports WHERE id = ?", reportID))
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrReportNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if report.Status != models.ReportOpen {
+			return ErrReportResolved
+		}
+
+		if status == models.ReportHidden {
+			if err := setContentHidden(ctx, tx, report.ContentType, report.ContentID, moderatorID, true); err != nil {
+				return err
+			}
+		}
+		now := time.Now().UTC()
+		_, err = tx.ExecContext(ctx,
+			"UPDATE content_reports SET status = ?, resolved_by = ?, resolved_at = ?, note = ? "+
+				"WHERE content_type = ? AND content_id = ? AND status = ?",
+			status, moderatorID, now, note, report.ContentType, report.ContentID, models.ReportOpen)
+		if err != nil {
+			return fmt.Errorf("resolve reports: %w", err)
+		}
+		report.Status, report.ResolvedBy, report.ResolvedAt, report.Note = status, &moderatorID, &now, note
+		return nil
+	})
+	return report, err
+}
+
+func scanReport(row rowScanner) (models.ContentReport, error) {
+	var report models.ContentReport
+	var reporterID, resolvedBy sql.NullInt64
+	var resolvedAt sql.NullTime
+	err := row.Scan(&report.ID, &report.ContentType, &report.ContentID, &report.CourseID, &reporterID, &report.Reason,
+		&report.Status, &resolvedBy, &resolvedAt, &report.Note, &report.CreatedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return report, err
+		}
+		return report, fmt.Errorf("scan report: %w", err)
+	}
+	report.ReporterID = intOrNil(reporterID)
+	report.ResolvedBy = intOrNil(resolvedBy)
+	if resolvedAt.Valid {
+		report.ResolvedAt = &resolvedAt.Time
+	}
+	return report, nil
+}
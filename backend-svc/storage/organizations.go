@@ -0,0 +1,346 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrOrganizationNotFound — организации нет
+	ErrOrganizationNotFound = errors.New("organization not found")
+	// ErrOrganizationExists — организация с таким slug уже есть
+	ErrOrganizationExists = errors.New("organization already exists")
+)
+
+// InOrganization возвращает представление хранилища, ограниченное одной организацией: курсы,
+// задания, пользователи и группы других организаций в нем не видны, а новые курсы и
+// пользователи создаются в этой организации
+func (s *DBStorage) InOrganization(orgID int) *DBStorage {
+	view := *s
+	view.OrganizationID = orgID
+	return &view
+}
+
+// InOrganization ограничивает хранилище организацией orgID; 0 возвращает хранилище без ограничения
+func InOrganization(store Storage, orgID int) Storage {
+	if orgID == 0 {
+		return store
+	}
+	switch store := store.(type) {
+	case *DBStorage:
+		return store.InOrganization(orgID)
+	case *MockStorage:
+		view := *store
+		view.OrganizationID = orgID
+		return &view
+	case *InstrumentedStorage:
+		return Instrument(InOrganization(store.next, orgID))
+	case *FaultyStorage:
+		view := *store
+		view.next = InOrganization(store.next, orgID)
+		return &view
+	}
+	return store
+}
+
+// organization возвращает организацию, в которой создаются новые записи
+func (s *DBStorage) organization() int {
+	if s.OrganizationID == 0 {
+		return models.DefaultOrganizationID
+	}
+	return s.OrganizationID
+}
+
+// orgFilter возвращает условие «column принадлежит организации представления», начинающееся
+// с keyword (WHERE или AND), и его аргументы; для хранилища без организации — пустое условие
+func (s *DBStorage) orgFilter(keyword, column string) (string, []any) {
+	if s.OrganizationID == 0 {
+		return "", nil
+	}
+	return " " + keyword + " " + column + " = ?", []any{s.OrganizationID}
+}
+
+// courseInOrganization — courseExists с учетом организации представления: курс другой
+// организации считается отсутствующим
+func (s *DBStorage) courseInOrganization(ctx context.Context, q queryer, courseID int) error {
+	orgAnd, orgArgs := s.orgFilter("AND", "organization_id")
+	var id int
+	err := q.QueryRowContext(ctx, "SELECT id FROM courses WHERE id = ?"+orgAnd, append([]any{courseID}, orgArgs...)...).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrCourseNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("check course: %w", err)
+	}
+	return nil
+}
+
+// taskCourseInOrganization — taskCourseID с учетом организации представления: задание курса
+// другой организации считается отсутствующим
+func (s *DBStorage) taskCourseInOrganization(ctx context.Context, q queryer, taskID int) (int, error) {
+	orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+	var courseID int
+	err := q.QueryRowContext(ctx, "SELECT t.course_id FROM tasks t JOIN courses c ON c.id = t.course_id WHERE t.id = ?"+orgAnd,
+		append([]any{taskID}, orgArgs...)...).Scan(&courseID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrTaskNotFound
+	}
+	if err != nil {
+		return 0, fmt.Errorf("get task course: %w", err)
+	}
+	return courseID, nil
+}
+
+const organizationColumns = `o.id, o.slug, o.name, o.is_active, o.created_at,
+	(SELECT COUNT(*) FROM users u WHERE u.organization_id = o.id),
+	(SELECT COUNT(*) FROM courses c WHERE c.organization_id = o.id)`
+
+func scanOrganization(row rowScanner) (models.Organization, error) {
+	var org models.Organization
+	err := row.Scan(&org.ID, &org.Slug, &org.Name, &org.IsActive, &org.CreatedAt, &org.Users, &org.Courses)
+	if errors.Is(err, sql.ErrNoRows) {
+		return org, ErrOrganizationNotFound
+	}
+	if err != nil {
+		return org, fmt.Errorf("scan organization: %w", err)
+	}
+	return org, nil
+}
+
+// ListOrganizations возвращает все организации с числом пользователей и курсов
+func (s *DBStorage) ListOrganizations() ([]models.Organization, error) {
+	ctx, done := s.startQuery("ListOrganizations")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, "SELECT "+organizationColumns+" FROM organizations o ORDER BY o.id")
+	if err != nil {
+		return nil, fmt.Errorf("query organizations: %w", err)
+	}
+	defer rows.Close()
+
+	orgs := []models.Organization{}
+	for rows.Next() {
+		org, err := scanOrganization(rows)
+		if err != nil {
+			return nil, err
+		}
+		orgs = append(orgs, org)
+	}
+	return orgs, rows.Err()
+}
+
+// GetOrganization возвращает организацию по ID
+func (s *DBStorage) GetOrganization(id int) (models.Organization, error) {
+	ctx, done := s.startQuery("GetOrganization")
+	defer done()
+
+	return scanOrganization(s.DB.QueryRowContext(ctx, "SELECT "+organizationColumns+" FROM organizations o WHERE o.id = ?", id))
+}
+
+// CreateOrganization создает организацию; ErrOrganizationExists, если slug занят
+func (s *DBStorage) CreateOrganization(org models.Organization) (models.Organization, error) {
+	ctx, done := s.startQuery("CreateOrganization")
+	defer done()
+
+	org.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	res, err := s.DB.ExecContext(ctx,
+		"INSERT INTO organizations (slug, name, is_active, created_at) VALUES (?, ?, ?, ?)",
+		org.Slug, org.Name, org.IsActive, org.CreatedAt)
+	if isUniqueViolation(err) {
+		return org, ErrOrganizationExists
+	}
+	if err != nil {
+		return org, fmt.Errorf("insert organization: %w", err)
+	}
+	id, err := res.LastInsertId()
+	if err != nil {
+		return org, fmt.Errorf("get organization id: %w", err)
+	}
+	org.ID = int(id)
+	return org, nil
+}
+
+// UpdateOrganization меняет slug, название и активность организации
+func (s *DBStorage) UpdateOrganization(org models.Organization) (models.Organization, error) {
+	ctx, done := s.startQuery("UpdateOrganization")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if found, err := rowExists(ctx, tx, "organizations", org.ID); err != nil || !found {
+			if err == nil {
+				err = ErrOrganizationNotFound
+			}
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE organizations SET slug = ?, name = ?, is_active = ? WHERE id = ?",
+			org.Slug, org.Name, org.IsActive, org.ID)
+		if isUniqueViolation(err) {
+			return ErrOrganizationExists
+		}
+		if err != nil {
+			return fmt.Errorf("update organization: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return org, err
+	}
+	return s.GetOrganization(org.ID)
+}
+
+// SetUserOrganization переносит пользователя в другую организацию. Связи, которые пересекли бы
+// границу организаций, — членство в группах, права администратора организации и руководители —
+// удаляются.
+func (s *DBStorage) SetUserOrganization(userID, orgID int) error {
+	ctx, done := s.startQuery("SetUserOrganization")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if found, err := rowExists(ctx, tx, "organizations", orgID); err != nil || !found {
+			if err == nil {
+				err = ErrOrganizationNotFound
+			}
+			return err
+		}
+		if err := bumpUserVersion(ctx, tx, userID, AnyVersion); err != nil {
+			return err
+		}
+		res, err := tx.ExecContext(ctx, "UPDATE users SET organization_id = ? WHERE id = ? AND organization_id <> ?", orgID, userID, orgID)
+		if err != nil {
+			return fmt.Errorf("move user: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil
+		}
+		cleanup := []struct{ name, query string }{
+			{"group memberships", "DELETE FROM group_members WHERE user_id = ? AND group_id IN (SELECT id FROM user_groups WHERE organization_id <> ?)"},
+			{"course assignments", "DELETE FROM course_assignments WHERE user_id = ? AND course_id IN (SELECT id FROM courses WHERE organization_id <> ?)"},
+			{"organization admin rights", "DELETE FROM organization_admins WHERE user_id = ? AND organization_id <> ?"},
+			{"manager", "DELETE FROM user_managers WHERE user_id = ? AND manager_id IN (SELECT id FROM users WHERE organization_id <> ?)"},
+			{"reports", "DELETE FROM user_managers WHERE manager_id = ? AND user_id IN (SELECT id FROM users WHERE organization_id <> ?)"},
+		}
+		for _, step := range cleanup {
+			if _, err := tx.ExecContext(ctx, step.query, userID, orgID); err != nil {
+				return fmt.Errorf("drop %s: %w", step.name, err)
+			}
+		}
+		return nil
+	})
+}
+
+// SetCourseOrganization переносит курс в другую организацию
+func (s *DBStorage) SetCourseOrganization(courseID, orgID int) error {
+	ctx, done := s.startQuery("SetCourseOrganization")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if found, err := rowExists(ctx, tx, "organizations", orgID); err != nil || !found {
+			if err == nil {
+				err = ErrOrganizationNotFound
+			}
+			return err
+		}
+		if err := courseExists(ctx, tx, courseID); err != nil {
+			return err
+		}
+		// updated_at меняется, чтобы каталоги обеих организаций получили новый ETag
+		if _, err := tx.ExecContext(ctx, "UPDATE courses SET organization_id = ?, updated_at = ? WHERE id = ?",
+			orgID, time.Now().UTC(), courseID); err != nil {
+			return fmt.Errorf("move course: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListOrganizationMembers возвращает пользователей организации с отметкой администраторов
+func (s *DBStorage) ListOrganizationMembers(orgID int) ([]models.OrganizationMember, error) {
+	ctx, done := s.startQuery("ListOrganizationMembers")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT u.id, u.username, u.full_name, u.email, u.is_active,
+		       EXISTS (SELECT 1 FROM organization_admins a WHERE a.organization_id = u.organization_id AND a.user_id = u.id)
+		FROM users u WHERE u.organization_id = ? ORDER BY u.username`, orgID)
+	if err != nil {
+		return nil, fmt.Errorf("query organization members: %w", err)
+	}
+	defer rows.Close()
+
+	members := []models.OrganizationMember{}
+	for rows.Next() {
+		var member models.OrganizationMember
+		var fullName, email sql.NullString
+		if err := rows.Scan(&member.UserID, &member.Username, &fullName, &email, &member.IsActive, &member.IsOrgAdmin); err != nil {
+			return nil, fmt.Errorf("scan organization member: %w", err)
+		}
+		member.FullName, member.Email = fullName.String, email.String
+		members = append(members, member)
+	}
+	return members, rows.Err()
+}
+
+// IsOrganizationMember сообщает, состоит ли пользователь в организации
+func (s *DBStorage) IsOrganizationMember(orgID, userID int) (bool, error) {
+	ctx, done := s.startQuery("IsOrganizationMember")
+	defer done()
+
+	var found int
+	err := s.DB.QueryRowContext(ctx, "SELECT 1 FROM users WHERE id = ? AND organization_id = ?", userID, orgID).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check organization member: %w", err)
+	}
+	return true, nil
+}
+
+// IsOrganizationAdmin сообщает, администрирует ли пользователь организацию
+func (s *DBStorage) IsOrganizationAdmin(orgID, userID int) (bool, error) {
+	ctx, done := s.startQuery("IsOrganizationAdmin")
+	defer done()
+
+	var found int
+	err := s.DB.QueryRowContext(ctx,
+		"SELECT 1 FROM organization_admins WHERE organization_id = ? AND user_id = ?", orgID, userID).Scan(&found)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check organization admin: %w", err)
+	}
+	return true, nil
+}
+
+// SetOrganizationAdmin выдает или отзывает права администратора организации; ErrUserNotFound,
+// если пользователь не состоит в организации
+func (s *DBStorage) SetOrganizationAdmin(orgID, userID int, isAdmin bool) error {
+	ctx, done := s.startQuery("SetOrganizationAdmin")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		var found int
+		err := tx.QueryRowContext(ctx, "SELECT 1 FROM users WHERE id = ? AND organization_id = ?", userID, orgID).Scan(&found)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return fmt.Errorf("check organization member: %w", err)
+		}
+		if !isAdmin {
+			_, err = tx.ExecContext(ctx, "DELETE FROM organization_admins WHERE organization_id = ? AND user_id = ?", orgID, userID)
+		} else {
+			_, err = tx.ExecContext(ctx,
+				"INSERT INTO organization_admins (organization_id, user_id) VALUES (?, ?)"+
+					s.onConflictUpdate([]string{"organization_id", "user_id"}, "user_id"), orgID, userID)
+		}
+		if err != nil {
+			return fmt.Errorf("set organization admin: %w", err)
+		}
+		return nil
+	})
+}
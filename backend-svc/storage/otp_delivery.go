@@ -0,0 +1,111 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// GetOTPPreference возвращает канал доставки одноразовых кодов пользователя
+func (s *DBStorage) GetOTPPreference(userID int) (models.OTPPreference, error) {
+	ctx, done := s.startQuery("GetOTPPreference")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT channel, phone FROM user_otp_preferences WHERE user_id = ?")
+	if err != nil {
+		return models.OTPPreference{}, err
+	}
+
+	pref := models.OTPPreference{UserID: userID}
+	var phone sql.NullString
+	err = stmt.QueryRowContext(ctx, userID).Scan(&pref.Channel, &phone)
+	if errors.Is(err, sql.ErrNoRows) {
+		pref.Channel = models.OTPChannelEmail
+		return pref, nil
+	}
+	if err != nil {
+		return models.OTPPreference{}, fmt.Errorf("get otp preference: %w", err)
+	}
+	pref.Phone = phone.String
+	return pref, nil
+}
+
+// SetOTPPreference сохраняет канал доставки одноразовых кодов
+func (s *DBStorage) SetOTPPreference(pref models.OTPPreference) error {
+	ctx, done := s.startQuery("SetOTPPreference")
+	defer done()
+
+	var phone interface{}
+	if pref.Phone != "" {
+		phone = pref.Phone
+	}
+
+	_, err := s.DB.ExecContext(ctx,
+		"INSERT INTO user_otp_preferences (user_id, channel, phone, updated_at) VALUES (?, ?, ?, ?)"+
+			s.onConflictUpdate([]string{"user_id"}, "channel", "phone", "updated_at"),
+		pref.UserID, pref.Channel, phone, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("set otp preference: %w", err)
+	}
+	return nil
+}
+
+// RecordOTPDelivery сохраняет результат отправки одноразового кода
+func (s *DBStorage) RecordOTPDelivery(delivery models.OTPDelivery) error {
+	ctx, done := s.startQuery("RecordOTPDelivery")
+	defer done()
+
+	// Столбец error ограничен 255 символами; текст ошибки провайдера может быть длиннее
+	var deliveryErr interface{}
+	if delivery.Error != "" {
+		if runes := []rune(delivery.Error); len(runes) > 255 {
+			delivery.Error = string(runes[:255])
+		}
+		deliveryErr = delivery.Error
+	}
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"INSERT INTO otp_deliveries (user_id, channel, status, error, created_at) VALUES (?, ?, ?, ?, ?)")
+	if err != nil {
+		return err
+	}
+	if _, err := stmt.ExecContext(ctx, delivery.UserID, delivery.Channel, delivery.Status, deliveryErr, time.Now().UTC()); err != nil {
+		return fmt.Errorf("record otp delivery: %w", err)
+	}
+	return nil
+}
+
+// ListOTPDeliveries возвращает последние попытки доставки кодов пользователю, новые первыми
+func (s *DBStorage) ListOTPDeliveries(userID, limit int) ([]models.OTPDelivery, error) {
+	ctx, done := s.startQuery("ListOTPDeliveries")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT id, user_id, channel, status, error, created_at FROM otp_deliveries WHERE user_id = ? ORDER BY id DESC LIMIT ?")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := []models.OTPDelivery{}
+	for rows.Next() {
+		var delivery models.OTPDelivery
+		var deliveryErr sql.NullString
+		if err := rows.Scan(&delivery.ID, &delivery.UserID, &delivery.Channel, &delivery.Status, &deliveryErr, &delivery.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		delivery.Error = deliveryErr.String
+		deliveries = append(deliveries, delivery)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return deliveries, nil
+}
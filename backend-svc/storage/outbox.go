@@ -0,0 +1,115 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// insertOutboxEvent записывает доменное событие в outbox в рамках переданной транзакции,
+// чтобы событие и изменение состояния фиксировались атомарно
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, eventType string, aggregateID int, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal event payload: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"INSERT INTO outbox_events (event_type, aggregate_id, payload) VALUES (?, ?, ?)",
+		eventType, aggregateID, data)
+	if err != nil {
+		return fmt.Errorf("insert outbox event: %w", err)
+	}
+
+	return nil
+}
+
+// FetchPendingEvents возвращает неопубликованные события, время повторной попытки которых наступило
+func (s *DBStorage) FetchPendingEvents(limit int) ([]models.OutboxEvent, error) {
+	ctx, done := s.startQuery("FetchPendingEvents")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, `
+		SELECT id, event_type, aggregate_id, payload, attempts, created_at
+		FROM outbox_events
+		WHERE dispatched_at IS NULL AND next_attempt_at <= ?
+		ORDER BY id
+		LIMIT ?
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("prepare statement: %w", err)
+	}
+
+	rows, err := stmt.QueryContext(ctx, time.Now().UTC(), limit)
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var events []models.OutboxEvent
+	for rows.Next() {
+		var event models.OutboxEvent
+		if err := rows.Scan(
+			&event.ID,
+			&event.EventType,
+			&event.AggregateID,
+			&event.Payload,
+			&event.Attempts,
+			&event.CreatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return events, nil
+}
+
+// MarkEventDispatched отмечает событие как успешно опубликованное
+func (s *DBStorage) MarkEventDispatched(eventID int64) error {
+	ctx, done := s.startQuery("MarkEventDispatched")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "UPDATE outbox_events SET dispatched_at = ?, last_error = NULL WHERE id = ?")
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, time.Now().UTC(), eventID)
+	return err
+}
+
+// MarkEventFailed фиксирует неудачную попытку публикации и планирует следующую
+func (s *DBStorage) MarkEventFailed(eventID int64, reason string, retryAt time.Time) error {
+	ctx, done := s.startQuery("MarkEventFailed")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"UPDATE outbox_events SET attempts = attempts + 1, last_error = ?, next_attempt_at = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+
+	_, err = stmt.ExecContext(ctx, reason, retryAt.UTC(), eventID)
+	return err
+}
+
+// PurgeDispatchedEvents удаляет доставленные события, опубликованные раньше before
+func (s *DBStorage) PurgeDispatchedEvents(before time.Time) (int64, error) {
+	ctx, done := s.startQuery("PurgeDispatchedEvents")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx,
+		"DELETE FROM outbox_events WHERE dispatched_at IS NOT NULL AND dispatched_at < ?", before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ErrPasswordReused возвращается при смене пароля на текущий или один из недавних
+var ErrPasswordReused = errors.New("password was used recently")
+
+// checkPasswordHistory сравнивает новый пароль с текущим и PasswordHistory-1 предыдущими
+func (s *DBStorage) checkPasswordHistory(ctx context.Context, userID int, password string) error {
+	var current string
+	err := s.DB.QueryRowContext(ctx, "SELECT password_hash FROM users WHERE id = ?", userID).Scan(&current)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUserNotFound
+	}
+	if err != nil {
+		return fmt.Errorf("load password hash: %w", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT password_hash FROM password_history WHERE user_id = ? ORDER BY id DESC LIMIT ?", userID, s.PasswordHistory-1)
+	if err != nil {
+		return fmt.Errorf("load password history: %w", err)
+	}
+	defer rows.Close()
+
+	hashes := []string{current}
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return fmt.Errorf("scan row: %w", err)
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("iterate rows: %w", err)
+	}
+
+	for _, hash := range hashes {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return ErrPasswordReused
+		}
+	}
+	return nil
+}
+
+// recordPasswordHistory переносит текущий хеш пароля в историю перед его заменой
+// и оставляет только PasswordHistory-1 последних записей
+func (s *DBStorage) recordPasswordHistory(ctx context.Context, tx *sql.Tx, userID int) error {
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO password_history (user_id, password_hash) SELECT id, password_hash FROM users WHERE id = ?", userID); err != nil {
+		return err
+	}
+	// Производная таблица нужна MySQL: LIMIT внутри IN (...) не поддерживается
+	_, err := tx.ExecContext(ctx,
+		"DELETE FROM password_history WHERE user_id = ? AND id NOT IN "+
+			"(SELECT id FROM (SELECT id FROM password_history WHERE user_id = ? ORDER BY id DESC LIMIT ?) AS recent)",
+		userID, userID, s.PasswordHistory-1)
+	return err
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// GetProfilePrivacy возвращает настройки приватности профиля; для пользователей,
+// которые их не меняли, — models.DefaultProfilePrivacy
+func (s *DBStorage) GetProfilePrivacy(userID int) (models.ProfilePrivacy, error) {
+	ctx, done := s.startQuery("GetProfilePrivacy")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT profile_visible, show_full_name, show_avatar, show_badges, show_course_stats, show_skills "+
+			"FROM profile_privacy WHERE user_id = ?")
+	if err != nil {
+		return models.ProfilePrivacy{}, err
+	}
+
+	var p models.ProfilePrivacy
+	err = stmt.QueryRowContext(ctx, userID).Scan(
+		&p.ProfileVisible, &p.ShowFullName, &p.ShowAvatar, &p.ShowBadges, &p.ShowCourseStats, &p.ShowSkills)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.DefaultProfilePrivacy(), nil
+	}
+	if err != nil {
+		return models.ProfilePrivacy{}, fmt.Errorf("get profile privacy: %w", err)
+	}
+	return p, nil
+}
+
+// SetProfilePrivacy сохраняет настройки приватности профиля
+func (s *DBStorage) SetProfilePrivacy(userID int, p models.ProfilePrivacy) error {
+	ctx, done := s.startQuery("SetProfilePrivacy")
+	defer done()
+
+	_, err := s.DB.ExecContext(ctx,
+		"INSERT INTO profile_privacy (user_id, profile_visible, show_full_name, show_avatar, show_badges, "+
+			"show_course_stats, show_skills, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?)"+
+			s.onConflictUpdate([]string{"user_id"},
+				"profile_visible", "show_full_name", "show_avatar", "show_badges", "show_course_stats", "show_skills", "updated_at"),
+		userID, p.ProfileVisible, p.ShowFullName, p.ShowAvatar, p.ShowBadges, p.ShowCourseStats, p.ShowSkills,
+		time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("set profile privacy: %w", err)
+	}
+	return nil
+}
+
+// GetTaskCompletions возвращает выполненные пользователем задания вместе с курсом и временем выполнения
+func (s *DBStorage) GetTaskCompletions(userID int) ([]models.TaskCompletion, error) {
+	ctx, done := s.startQuery("GetTaskCompletions")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT p.task_id, t.course_id, p.completed_at FROM user_progress p "+
+			"JOIN tasks t ON t.id = p.task_id WHERE p.user_id = ? ORDER BY p.completed_at")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	completions := []models.TaskCompletion{}
+	for rows.Next() {
+		var completion models.TaskCompletion
+		var completedAt sql.NullTime
+		if err := rows.Scan(&completion.TaskID, &completion.CourseID, &completedAt); err != nil {
+			return nil, err
+		}
+		if completedAt.Valid {
+			completion.CompletedAt = completedAt.Time
+		}
+		completions = append(completions, completion)
+	}
+	return completions, rows.Err()
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"math"
+	"time"
+)
+
+// progressSnapshotCarryDays — сколько дней после последнего ненулевого снимка пара пользователь-курс
+// еще снимается, чтобы сброс прогресса попал в историю как падение до нуля
+const progressSnapshotCarryDays = 7
+
+// progressPoint возвращает точку истории с процентом выполнения, округленным до десятых
+func progressPoint(day string, completed, total int) models.ProgressPoint {
+	point := models.ProgressPoint{Date: day, TasksCompleted: completed, TasksTotal: total}
+	if total > 0 {
+		point.Percent = math.Round(float64(completed)*1000/float64(total)) / 10
+	}
+	return point
+}
+
+// SnapshotProgress сохраняет прогресс за день day по каждой паре пользователь-курс с выполненными
+// заданиями. Повторный снимок за тот же день перезаписывает значения.
+func (s *DBStorage) SnapshotProgress(day time.Time) (int, error) {
+	ctx, done := s.startQuery("SnapshotProgress")
+	defer done()
+
+	day = day.UTC()
+	// WHERE нужен SQLite: без него ON CONFLICT после SELECT разбирается неоднозначно
+	res, err := s.DB.ExecContext(ctx, `
+		INSERT INTO progress_snapshots (user_id, course_id, snapshot_date, tasks_completed, tasks_total)
+		SELECT p.user_id, p.course_id, ?,
+		       (SELECT COUNT(*) FROM user_progress up JOIN tasks t ON t.id = up.task_id
+		        WHERE up.user_id = p.user_id AND t.course_id = p.course_id),
+		       (SELECT COUNT(*) FROM tasks t WHERE t.course_id = p.course_id)
+		FROM (
+			SELECT DISTINCT up.user_id, t.course_id FROM user_progress up JOIN tasks t ON t.id = up.task_id
+			UNION
+			SELECT user_id, course_id FROM progress_snapshots WHERE snapshot_date >= ? AND tasks_completed > 0
+		) p
+		WHERE p.course_id IS NOT NULL`+
+		s.onConflictUpdate([]string{"user_id", "course_id", "snapshot_date"}, "tasks_completed", "tasks_total"),
+		day.Format(statsDayFormat), day.AddDate(0, 0, -progressSnapshotCarryDays).Format(statsDayFormat))
+	if err != nil {
+		return 0, fmt.Errorf("snapshot progress: %w", err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// GetProgressHistory возвращает снимки прогресса пользователя начиная с дня from; courseID, если
+// не 0, ограничивает историю одним курсом
+func (s *DBStorage) GetProgressHistory(userID, courseID int, from time.Time) ([]models.CourseProgressHistory, error) {
+	ctx, done := s.startQuery("GetProgressHistory")
+	defer done()
+
+	query := `
+		SELECT ps.course_id, c.vulnerability_type, ps.snapshot_date, ps.tasks_completed, ps.tasks_total
+		FROM progress_snapshots ps
+		JOIN courses c ON c.id = ps.course_id
+		WHERE ps.user_id = ? AND ps.snapshot_date >= ?`
+	args := []any{userID, from.UTC().Format(statsDayFormat)}
+	if courseID != 0 {
+		query += " AND ps.course_id = ?"
+		args = append(args, courseID)
+	}
+	rows, err := s.reader().QueryContext(ctx, query+" ORDER BY ps.course_id, ps.snapshot_date", args...)
+	if err != nil {
+		return nil, fmt.Errorf("query progress snapshots: %w", err)
+	}
+	defer rows.Close()
+
+	history := []models.CourseProgressHistory{}
+	for rows.Next() {
+		var id, completed, total int
+		var title string
+		var day any
+		if err := rows.Scan(&id, &title, &day, &completed, &total); err != nil {
+			return nil, fmt.Errorf("scan progress snapshot: %w", err)
+		}
+		if len(history) == 0 || history[len(history)-1].CourseID != id {
+			history = append(history, models.CourseProgressHistory{CourseID: id, CourseTitle: title, Points: []models.ProgressPoint{}})
+		}
+		course := &history[len(history)-1]
+		course.Points = append(course.Points, progressPoint(statsDay(day), completed, total))
+	}
+	return history, rows.Err()
+}
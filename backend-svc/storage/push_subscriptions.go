@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrPushSubscriptionNotFound — подписки Web Push нет
+var ErrPushSubscriptionNotFound = errors.New("push subscription not found")
+
+const pushSubscriptionColumns = "id, user_id, endpoint, p256dh, auth, user_agent, created_at"
+
+// SavePushSubscription сохраняет подписку; браузер, сменивший пользователя, переподписывается
+// с тем же адресом, поэтому подписка переходит к новому владельцу
+func (s *DBStorage) SavePushSubscription(sub models.PushSubscription) (models.PushSubscription, error) {
+	ctx, done := s.startQuery("SavePushSubscription")
+	defer done()
+
+	_, err := s.DB.ExecContext(ctx,
+		"INSERT INTO push_subscriptions (user_id, endpoint, p256dh, auth, user_agent, created_at) VALUES (?, ?, ?, ?, ?, ?)"+
+			s.onConflictUpdate([]string{"endpoint"}, "user_id", "p256dh", "auth", "user_agent", "created_at"),
+		sub.UserID, sub.Endpoint, sub.P256dh, sub.Auth, sub.UserAgent, time.Now().UTC().Truncate(time.Second))
+	if err != nil {
+		return models.PushSubscription{}, fmt.Errorf("save push subscription: %w", err)
+	}
+	return scanPushSubscription(s.DB.QueryRowContext(ctx,
+		"SELECT "+pushSubscriptionColumns+" FROM push_subscriptions WHERE endpoint = ?", sub.Endpoint))
+}
+
+// ListPushSubscriptions возвращает подписки пользователя
+func (s *DBStorage) ListPushSubscriptions(userID int) ([]models.PushSubscription, error) {
+	ctx, done := s.startQuery("ListPushSubscriptions")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT "+pushSubscriptionColumns+" FROM push_subscriptions WHERE user_id = ? ORDER BY id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("query push subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subs := []models.PushSubscription{}
+	for rows.Next() {
+		sub, err := scanPushSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, sub)
+	}
+	return subs, rows.Err()
+}
+
+// ListPushSubscriberIDs возвращает пользователей с подписками
+func (s *DBStorage) ListPushSubscriberIDs() ([]int, error) {
+	ctx, done := s.startQuery("ListPushSubscriberIDs")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx, "SELECT DISTINCT user_id FROM push_subscriptions ORDER BY user_id")
+	if err != nil {
+		return nil, fmt.Errorf("query push subscribers: %w", err)
+	}
+	defer rows.Close()
+
+	ids := []int{}
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan push subscriber: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// DeletePushSubscription удаляет подписку пользователя
+func (s *DBStorage) DeletePushSubscription(userID, id int) error {
+	ctx, done := s.startQuery("DeletePushSubscription")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM push_subscriptions WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return fmt.Errorf("delete push subscription: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrPushSubscriptionNotFound
+	}
+	return nil
+}
+
+// DeletePushSubscriptionByEndpoint удаляет подписку по адресу push-сервиса
+func (s *DBStorage) DeletePushSubscriptionByEndpoint(endpoint string) error {
+	ctx, done := s.startQuery("DeletePushSubscriptionByEndpoint")
+	defer done()
+
+	if _, err := s.DB.ExecContext(ctx, "DELETE FROM push_subscriptions WHERE endpoint = ?", endpoint); err != nil {
+		return fmt.Errorf("delete push subscription: %w", err)
+	}
+	return nil
+}
+
+// MarkPushDeadlineReminder отмечает push-напоминание о сроке задания
+func (s *DBStorage) MarkPushDeadlineReminder(userID, taskID int, dueAt time.Time) (bool, error) {
+	ctx, done := s.startQuery("MarkPushDeadlineReminder")
+	defer done()
+
+	marked := false
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var exists int
+		err := tx.QueryRowContext(ctx,
+			"SELECT 1 FROM push_deadline_reminders WHERE user_id = ? AND task_id = ? AND due_at = ?",
+			userID, taskID, dueAt.UTC()).Scan(&exists)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("get push reminder: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO push_deadline_reminders (user_id, task_id, due_at, sent_at) VALUES (?, ?, ?, ?)",
+			userID, taskID, dueAt.UTC(), time.Now().UTC()); err != nil {
+			return fmt.Errorf("save push reminder: %w", err)
+		}
+		marked = true
+		return nil
+	})
+	return marked, err
+}
+
+func scanPushSubscription(row rowScanner) (models.PushSubscription, error) {
+	var sub models.PushSubscription
+	err := row.Scan(&sub.ID, &sub.UserID, &sub.Endpoint, &sub.P256dh, &sub.Auth, &sub.UserAgent, &sub.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return sub, ErrPushSubscriptionNotFound
+	}
+	if err != nil {
+		return sub, fmt.Errorf("scan push subscription: %w", err)
+	}
+	return sub, nil
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"context"
+	"expvar"
+	"log"
+	"time"
+)
+
+// Метрики запросов DBStorage в разрезе методов хранилища
+var (
+	slowQueries   = expvar.NewMap("db_slow_queries_total")
+	queryTimeouts = expvar.NewMap("db_query_timeouts_total")
+)
+
+// defaultQueryTimeout используется, если QueryTimeout не задан
+const defaultQueryTimeout = 5 * time.Second
+
+// startQuery возвращает контекст с таймаутом запроса для метода method и функцию,
+// которую нужно вызвать по завершении метода: она освобождает контекст и фиксирует
+// запросы дольше SlowQueryThreshold и запросы, прерванные по таймауту
+func (s *DBStorage) startQuery(method string) (context.Context, func()) {
+	timeout := s.QueryTimeout
+	if timeout <= 0 {
+		timeout = defaultQueryTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	started := time.Now()
+
+	return ctx, func() {
+		elapsed := time.Since(started)
+		if ctx.Err() == context.DeadlineExceeded {
+			queryTimeouts.Add(method, 1)
+			log.Printf("Query timeout: %s exceeded %s", method, timeout)
+		} else if s.SlowQueryThreshold > 0 && elapsed > s.SlowQueryThreshold {
+			slowQueries.Add(method, 1)
+			log.Printf("Slow query: %s took %s", method, elapsed)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,359 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+	"time"
+)
+
+// ErrRecertificationNotFound — курс не требует повторного прохождения
+var ErrRecertificationNotFound = errors.New("course recertification not found")
+
+const courseCompletionColumns = "user_id, course_id, completed_at, remind_at, expires_at, reminded_at, expired_at"
+
+// recertificationCycle возвращает цикл сертификации, начатый завершением курса в completedAt
+func recertificationCycle(rec models.CourseRecertification, userID int, completedAt time.Time) models.CourseCompletion {
+	completedAt = completedAt.UTC().Truncate(time.Second)
+	expiresAt := completedAt.AddDate(0, rec.PeriodMonths, 0)
+	remindAt := expiresAt
+	if rec.ReminderDays > 0 {
+		remindAt = expiresAt.AddDate(0, 0, -rec.ReminderDays)
+	}
+	return models.CourseCompletion{
+		UserID:      userID,
+		CourseID:    rec.CourseID,
+		CompletedAt: completedAt,
+		RemindAt:    remindAt,
+		ExpiresAt:   expiresAt,
+	}
+}
+
+// GetCourseRecertification возвращает требование повторного прохождения курса
+func (s *DBStorage) GetCourseRecertification(courseID int) (models.CourseRecertification, error) {
+	ctx, done := s.startQuery("GetCourseRecertification")
+	defer done()
+
+	rec := models.CourseRecertification{CourseID: courseID}
+	err := s.DB.QueryRowContext(ctx,
+		"SELECT period_months, reminder_days, updated_at FROM course_recertification WHERE course_id = ?", courseID).
+		Scan(&rec.PeriodMonths, &rec.ReminderDays, &rec.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return rec, ErrRecertificationNotFound
+	}
+	if err != nil {
+		return rec, fmt.Errorf("get course recertification: %w", err)
+	}
+	return rec, nil
+}
+
+// SetCourseRecertification сохраняет требование и заводит или пересчитывает циклы сертификации
+func (s *DBStorage) SetCourseRecertification(rec models.CourseRecertification) (models.CourseRecertification, error) {
+	ctx, done := s.startQuery("SetCourseRecertification")
+	defer done()
+
+	rec.UpdatedAt = time.Now().UTC().Truncate(time.Second)
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := courseExists(ctx, tx, rec.CourseID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO course_recertification (course_id, period_months, reminder_days, updated_at) VALUES (?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"course_id"}, "period_months", "reminder_days", "updated_at"),
+			rec.CourseID, rec.PeriodMonths, rec.ReminderDays, rec.UpdatedAt); err != nil {
+			return fmt.Errorf("save course recertification: %w", err)
+		}
+
+		// Время завершения курса каждым пользователем: последнее выполненное задание, если выполнены все
+		var tasks int
+		if err := tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM tasks WHERE course_id = ?", rec.CourseID).Scan(&tasks); err != nil {
+			return fmt.Errorf("count course tasks: %w", err)
+		}
+		rows, err := tx.QueryContext(ctx, `
+			SELECT p.user_id, p.completed_at
+			FROM user_progress p
+			JOIN tasks t ON t.id = p.task_id
+			WHERE t.course_id = ?
+		`, rec.CourseID)
+		if err != nil {
+			return fmt.Errorf("query course progress: %w", err)
+		}
+		completed, completedAt := map[int]int{}, map[int]time.Time{}
+		for rows.Next() {
+			var userID int
+			var at sql.NullTime
+			if err := rows.Scan(&userID, &at); err != nil {
+				rows.Close()
+				return fmt.Errorf("scan course progress: %w", err)
+			}
+			completed[userID]++
+			if at.Valid && at.Time.After(completedAt[userID]) {
+				completedAt[userID] = at.Time
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return fmt.Errorf("query course progress: %w", err)
+		}
+
+		// Текущие циклы сохраняют дату завершения, сроки пересчитываются по новому периоду
+		current, err := tx.QueryContext(ctx,
+			"SELECT user_id, completed_at FROM course_completions WHERE course_id = ? AND expired_at IS NULL", rec.CourseID)
+		if err != nil {
+			return fmt.Errorf("query course completions: %w", err)
+		}
+		for current.Next() {
+			var userID int
+			var at time.Time
+			if err := current.Scan(&userID, &at); err != nil {
+				current.Close()
+				return fmt.Errorf("scan course completion: %w", err)
+			}
+			completedAt[userID] = at
+			completed[userID] = tasks
+		}
+		err = current.Err()
+		current.Close()
+		if err != nil {
+			return fmt.Errorf("query course completions: %w", err)
+		}
+
+		for userID, count := range completed {
+			if tasks == 0 || count < tasks || completedAt[userID].IsZero() {
+				continue
+			}
+			cycle := recertificationCycle(rec, userID, completedAt[userID])
+			if _, err := tx.ExecContext(ctx,
+				"INSERT INTO course_completions (user_id, course_id, completed_at, remind_at, expires_at, reminded_at, expired_at) "+
+					"VALUES (?, ?, ?, ?, ?, NULL, NULL)"+
+					s.onConflictUpdate([]string{"user_id", "course_id"}, "completed_at", "remind_at", "expires_at", "reminded_at", "expired_at"),
+				cycle.UserID, cycle.CourseID, cycle.CompletedAt, cycle.RemindAt, cycle.ExpiresAt); err != nil {
+				return fmt.Errorf("save course completion: %w", err)
+			}
+		}
+		return nil
+	})
+	return rec, err
+}
+
+// DeleteCourseRecertification отменяет повторное прохождение курса
+func (s *DBStorage) DeleteCourseRecertification(courseID int) error {
+	ctx, done := s.startQuery("DeleteCourseRecertification")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, "DELETE FROM course_recertification WHERE course_id = ?", courseID)
+		if err != nil {
+			return fmt.Errorf("delete course recertification: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrRecertificationNotFound
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM course_completions WHERE course_id = ?", courseID); err != nil {
+			return fmt.Errorf("delete course completions: %w", err)
+		}
+		return nil
+	})
+}
+
+// RecordCourseCompletion начинает новый цикл сертификации по курсу с повторным прохождением
+func (s *DBStorage) RecordCourseCompletion(userID, courseID int, at time.Time) (bool, error) {
+	rec, err := s.GetCourseRecertification(courseID)
+	if errors.Is(err, ErrRecertificationNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	ctx, done := s.startQuery("RecordCourseCompletion")
+	defer done()
+
+	cycle := recertificationCycle(rec, userID, at)
+	if _, err := s.DB.ExecContext(ctx,
+		"INSERT INTO course_completions (user_id, course_id, completed_at, remind_at, expires_at, reminded_at, expired_at) "+
+			"VALUES (?, ?, ?, ?, ?, NULL, NULL)"+
+			s.onConflictUpdate([]string{"user_id", "course_id"}, "completed_at", "remind_at", "expires_at", "reminded_at", "expired_at"),
+		cycle.UserID, cycle.CourseID, cycle.CompletedAt, cycle.RemindAt, cycle.ExpiresAt); err != nil {
+		return false, fmt.Errorf("save course completion: %w", err)
+	}
+	return true, nil
+}
+
+// ListRecertificationReminders возвращает циклы, по которым наступило время напоминания
+func (s *DBStorage) ListRecertificationReminders(now time.Time) ([]models.CourseCompletion, error) {
+	ctx, done := s.startQuery("ListRecertificationReminders")
+	defer done()
+
+	return s.queryCourseCompletions(ctx,
+		"SELECT "+courseCompletionColumns+" FROM course_completions "+
+			"WHERE remind_at <= ? AND expires_at > ? AND reminded_at IS NULL AND expired_at IS NULL "+
+			"ORDER BY remind_at", now.UTC(), now.UTC())
+}
+
+// MarkRecertificationReminded отмечает отправленное напоминание
+func (s *DBStorage) MarkRecertificationReminded(userID, courseID int, at time.Time) error {
+	ctx, done := s.startQuery("MarkRecertificationReminded")
+	defer done()
+
+	if _, err := s.DB.ExecContext(ctx,
+		"UPDATE course_completions SET reminded_at = ? WHERE user_id = ? AND course_id = ?",
+		at.UTC(), userID, courseID); err != nil {
+		return fmt.Errorf("mark recertification reminder: %w", err)
+	}
+	return nil
+}
+
+// ListExpiredCourseCompletions возвращает истекшие циклы, прогресс по которым еще не сброшен
+func (s *DBStorage) ListExpiredCourseCompletions(now time.Time) ([]models.CourseCompletion, error) {
+	ctx, done := s.startQuery("ListExpiredCourseCompletions")
+	defer done()
+
+	return s.queryCourseCompletions(ctx,
+		"SELECT "+courseCompletionColumns+" FROM course_completions "+
+			"WHERE expires_at <= ? AND expired_at IS NULL ORDER BY expires_at", now.UTC())
+}
+
+// ExpireCourseCompletion переносит прогресс курса в архив попыток: задания снова нужно выполнить
+func (s *DBStorage) ExpireCourseCompletion(userID, courseID int, at time.Time) error {
+	ctx, done := s.startQuery("ExpireCourseCompletion")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			"UPDATE course_completions SET expired_at = ? WHERE user_id = ? AND course_id = ? AND expired_at IS NULL",
+			at.UTC(), userID, courseID)
+		if err != nil {
+			return fmt.Errorf("expire course completion: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return nil
+		}
+		if _, err := archiveCourseProgress(ctx, tx, userID, courseID, nil, recertificationResetReason, at); err != nil &&
+			!errors.Is(err, ErrNoCourseProgress) {
+			return err
+		}
+		return insertOutboxEvent(ctx, tx, models.EventRecertificationDue, userID, map[string]int{
+			"userId":   userID,
+			"courseId": courseID,
+		})
+	})
+}
+
+// ComplianceReport возвращает состояние сертификации пользователей по курсам с повторным прохождением
+func (s *DBStorage) ComplianceReport(filter models.ComplianceFilter) ([]models.ComplianceEntry, error) {
+	entries := []models.ComplianceEntry{}
+	err := s.EachComplianceEntry(filter, func(entry models.ComplianceEntry) error {
+		entries = append(entries, entry)
+		return nil
+	})
+	return entries, err
+}
+
+// EachComplianceEntry читает отчет пачками: следующая пачка продолжается после курса и имени
+// последней строки предыдущей
+func (s *DBStorage) EachComplianceEntry(filter models.ComplianceFilter, fn func(models.ComplianceEntry) error) error {
+	var after *models.ComplianceEntry
+	for {
+		batch, err := s.complianceBatch(filter, after)
+		if err != nil {
+			return err
+		}
+		for _, entry := range batch {
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		if len(batch) < exportBatchSize {
+			return nil
+		}
+		after = &batch[len(batch)-1]
+	}
+}
+
+func (s *DBStorage) complianceBatch(filter models.ComplianceFilter, after *models.ComplianceEntry) ([]models.ComplianceEntry, error) {
+	ctx, done := s.startQuery("ComplianceReport")
+	defer done()
+
+	conditions := []string{"u.is_active = ?"}
+	args := []any{true}
+	if filter.CourseID != 0 {
+		conditions = append(conditions, "r.course_id = ?")
+		args = append(args, filter.CourseID)
+	}
+	if filter.UserID != 0 {
+		conditions = append(conditions, "u.id = ?")
+		args = append(args, filter.UserID)
+	}
+	if after != nil {
+		conditions = append(conditions, "(c.id > ? OR (c.id = ? AND u.username > ?))")
+		args = append(args, after.CourseID, after.CourseID, after.Username)
+	}
+	args = append(args, exportBatchSize)
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT u.id, u.username, u.email, c.id, c.vulnerability_type,
+		       cc.completed_at, cc.remind_at, cc.expires_at, cc.expired_at
+		FROM course_recertification r
+		JOIN courses c ON c.id = r.course_id
+		CROSS JOIN users u
+		LEFT JOIN course_completions cc ON cc.user_id = u.id AND cc.course_id = r.course_id
+		WHERE `+strings.Join(conditions, " AND ")+`
+		ORDER BY c.id, u.username
+		LIMIT ?
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query compliance report: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.ComplianceEntry{}
+	for rows.Next() {
+		var entry models.ComplianceEntry
+		var email sql.NullString
+		var completedAt, remindAt, expiresAt, expiredAt sql.NullTime
+		if err := rows.Scan(&entry.UserID, &entry.Username, &email, &entry.CourseID, &entry.CourseTitle,
+			&completedAt, &remindAt, &expiresAt, &expiredAt); err != nil {
+			return nil, fmt.Errorf("scan compliance entry: %w", err)
+		}
+		entry.Email = email.String
+		entry.CompletedAt = timeOrNil(completedAt)
+		entry.RemindAt = timeOrNil(remindAt)
+		entry.ExpiresAt = timeOrNil(expiresAt)
+		entry.ExpiredAt = timeOrNil(expiredAt)
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (s *DBStorage) queryCourseCompletions(ctx context.Context, query string, args ...any) ([]models.CourseCompletion, error) {
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query course completions: %w", err)
+	}
+	defer rows.Close()
+
+	completions := []models.CourseCompletion{}
+	for rows.Next() {
+		var completion models.CourseCompletion
+		var remindedAt, expiredAt sql.NullTime
+		if err := rows.Scan(&completion.UserID, &completion.CourseID, &completion.CompletedAt, &completion.RemindAt,
+			&completion.ExpiresAt, &remindedAt, &expiredAt); err != nil {
+			return nil, fmt.Errorf("scan course completion: %w", err)
+		}
+		completion.RemindedAt = timeOrNil(remindedAt)
+		completion.ExpiredAt = timeOrNil(expiredAt)
+		completions = append(completions, completion)
+	}
+	return completions, rows.Err()
+}
+
+func timeOrNil(v sql.NullTime) *time.Time {
+	if !v.Valid {
+		return nil
+	}
+	return &v.Time
+}
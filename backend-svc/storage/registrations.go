@@ -0,0 +1,149 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrRegistrationNotFound — регистрация пользователя не ожидает одобрения
+var ErrRegistrationNotFound = errors.New("pending registration not found")
+
+const pendingRegistrationColumns = "u.id, u.username, u.email, u.full_name, COALESCE(u.locale, ''), r.requested_ip, r.requested_at"
+
+const pendingRegistrationFrom = " FROM pending_registrations r JOIN users u ON u.id = r.user_id"
+
+func scanPendingRegistration(row rowScanner) (models.PendingRegistration, error) {
+	var registration models.PendingRegistration
+	err := row.Scan(&registration.UserID, &registration.Username, &registration.Email, &registration.FullName,
+		&registration.Locale, &registration.RequestedIP, &registration.RequestedAt)
+	return registration, err
+}
+
+// CreatePendingUser создает неактивного пользователя и ставит его регистрацию в очередь одобрения
+func (s *DBStorage) CreatePendingUser(user models.User, requestedIP string) error {
+	ctx, done := s.startQuery("CreatePendingUser")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		userID, err := insertUser(ctx, tx, user, s.organization(), false)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO pending_registrations (user_id, requested_ip, requested_at) VALUES (?, ?, ?)",
+			userID, requestedIP, time.Now().UTC())
+		return err
+	})
+}
+
+// ListPendingRegistrations возвращает очередь регистраций, старые первыми
+func (s *DBStorage) ListPendingRegistrations() ([]models.PendingRegistration, error) {
+	ctx, done := s.startQuery("ListPendingRegistrations")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT "+pendingRegistrationColumns+pendingRegistrationFrom+" ORDER BY r.requested_at, u.id")
+	if err != nil {
+		return nil, fmt.Errorf("query pending registrations: %w", err)
+	}
+	defer rows.Close()
+
+	registrations := []models.PendingRegistration{}
+	for rows.Next() {
+		registration, err := scanPendingRegistration(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan pending registration: %w", err)
+		}
+		registrations = append(registrations, registration)
+	}
+	return registrations, rows.Err()
+}
+
+// IsRegistrationPending сообщает, ожидает ли регистрация пользователя одобрения
+func (s *DBStorage) IsRegistrationPending(userID int) (bool, error) {
+	ctx, done := s.startQuery("IsRegistrationPending")
+	defer done()
+
+	var pending int
+	err := s.DB.QueryRowContext(ctx, "SELECT 1 FROM pending_registrations WHERE user_id = ?", userID).Scan(&pending)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check pending registration: %w", err)
+	}
+	return true, nil
+}
+
+// takePendingRegistration удаляет регистрацию из очереди и возвращает ее; ErrRegistrationNotFound,
+// если ее нет (например, ее уже рассмотрел другой администратор)
+func takePendingRegistration(ctx context.Context, tx *sql.Tx, userID int) (models.PendingRegistration, error) {
+	registration, err := scanPendingRegistration(tx.QueryRowContext(ctx,
+		"SELECT "+pendingRegistrationColumns+pendingRegistrationFrom+" WHERE r.user_id = ?", userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return registration, ErrRegistrationNotFound
+	}
+	if err != nil {
+		return registration, err
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM pending_registrations WHERE user_id = ?", userID)
+	if err != nil {
+		return registration, err
+	}
+	if affected, err := res.RowsAffected(); err != nil || affected == 0 {
+		if err == nil {
+			err = ErrRegistrationNotFound
+		}
+		return registration, err
+	}
+	return registration, nil
+}
+
+// ApproveRegistration активирует учетную запись из очереди регистраций
+func (s *DBStorage) ApproveRegistration(userID int) (models.PendingRegistration, error) {
+	ctx, done := s.startQuery("ApproveRegistration")
+	defer done()
+
+	var registration models.PendingRegistration
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if registration, err = takePendingRegistration(ctx, tx, userID); err != nil {
+			return err
+		}
+		return setStatusInTx(ctx, tx, userID, true)
+	})
+	if err != nil && !errors.Is(err, ErrRegistrationNotFound) {
+		err = fmt.Errorf("approve registration: %w", err)
+	}
+	return registration, err
+}
+
+// RejectRegistration удаляет учетную запись из очереди регистраций; имя пользователя и email
+// снова становятся свободны
+func (s *DBStorage) RejectRegistration(userID int) (models.PendingRegistration, error) {
+	ctx, done := s.startQuery("RejectRegistration")
+	defer done()
+
+	var registration models.PendingRegistration
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var err error
+		if registration, err = takePendingRegistration(ctx, tx, userID); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = ?", userID); err != nil {
+			return err
+		}
+		return insertOutboxEvent(ctx, tx, models.EventUserDeleted, userID, map[string]interface{}{
+			"userId": userID,
+			"reason": "registration_rejected",
+		})
+	})
+	if err != nil && !errors.Is(err, ErrRegistrationNotFound) {
+		err = fmt.Errorf("reject registration: %w", err)
+	}
+	return registration, err
+}
@@ -0,0 +1,38 @@
+package storage
+
+import (
+	"database/sql"
+	"math/rand"
+)
+
+// reader возвращает пул для чтения, допускающего отставание: случайную реплику,
+// если они настроены, иначе основной пул
+func (s *DBStorage) reader() *sql.DB {
+	if len(s.Replicas) == 0 {
+		return s.DB
+	}
+	return s.Replicas[rand.Intn(len(s.Replicas))]
+}
+
+// Primary возвращает представление хранилища, которое читает только с основного сервера.
+// Используется, когда чтение должно видеть только что выполненную запись.
+func (s *DBStorage) Primary() *DBStorage {
+	view := *s
+	view.Replicas = nil
+	return &view
+}
+
+// Primary отключает чтение с реплик для хранилищ, которые их поддерживают
+func Primary(store Storage) Storage {
+	switch store := store.(type) {
+	case *DBStorage:
+		return store.Primary()
+	case *InstrumentedStorage:
+		return Instrument(Primary(store.next))
+	case *FaultyStorage:
+		view := *store
+		view.next = Primary(store.next)
+		return &view
+	}
+	return store
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strconv"
+	"time"
+)
+
+// anonymizedEmailDomain — домен адресов обезличенных учетных записей; по нему они
+// не выбираются повторно
+const anonymizedEmailDomain = "@anonymized.invalid"
+
+// personalDataTables — таблицы с персональными данными пользователя, которые удаляются при
+// обезличивании учетной записи; прогресс, попытки и сертификаты остаются для статистики
+var personalDataTables = []string{
+	"login_contexts", "account_lock_tokens", "trusted_devices", "password_history", "email_changes",
+	"username_history", "user_otp_preferences", "otp_deliveries", "telegram_accounts", "push_subscriptions",
+	"calendar_feed_tokens", "digest_unsubscribe_tokens", "profile_privacy", "task_notes",
+}
+
+// ErrUnknownRetentionPolicy — политика хранения для неизвестного вида данных или с неизвестным действием
+var ErrUnknownRetentionPolicy = errors.New("unknown retention policy")
+
+// ApplyRetention удаляет или обезличивает данные вида policy.Data старше cutoff и возвращает
+// число затронутых записей; при dryRun только считает их
+func (s *DBStorage) ApplyRetention(policy models.RetentionPolicy, cutoff time.Time, dryRun bool) (int64, error) {
+	ctx, done := s.startQuery("ApplyRetention")
+	defer done()
+
+	cutoff = cutoff.UTC()
+	anonymize := policy.Action == models.RetentionAnonymize
+	if !anonymize && policy.Action != models.RetentionPurge {
+		return 0, fmt.Errorf("%w: action %q", ErrUnknownRetentionPolicy, policy.Action)
+	}
+
+	switch policy.Data {
+	case models.RetentionAuditLog:
+		if anonymize {
+			return s.retainRows(ctx, dryRun, "audit_log",
+				"created_at < ? AND (actor_id IS NOT NULL OR actor_username <> '' OR ip <> '' OR changes IS NOT NULL)",
+				"UPDATE audit_log SET actor_id = NULL, actor_username = '', ip = '', changes = NULL", cutoff)
+		}
+		return s.retainRows(ctx, dryRun, "audit_log", "created_at < ?", "DELETE FROM audit_log", cutoff)
+
+	case models.RetentionLoginHistory:
+		var contexts int64
+		var err error
+		if anonymize {
+			contexts, err = s.retainRows(ctx, dryRun, "login_contexts",
+				"last_seen_at < ? AND (ip <> '' OR user_agent <> '' OR location <> '')",
+				"UPDATE login_contexts SET ip = '', user_agent = '', location = ''", cutoff)
+		} else {
+			contexts, err = s.retainRows(ctx, dryRun, "login_contexts", "last_seen_at < ?", "DELETE FROM login_contexts", cutoff)
+		}
+		if err != nil {
+			return 0, err
+		}
+		// Просроченные ссылки блокировки бесполезны, поэтому удаляются при любом действии
+		tokens, err := s.retainRows(ctx, dryRun, "account_lock_tokens", "expires_at < ?", "DELETE FROM account_lock_tokens", cutoff)
+		return contexts + tokens, err
+
+	case models.RetentionInactiveAccounts:
+		return s.retainInactiveAccounts(ctx, cutoff, anonymize, dryRun)
+	}
+	return 0, fmt.Errorf("%w: data %q", ErrUnknownRetentionPolicy, policy.Data)
+}
+
+// retainRows считает (dryRun) или изменяет запросом change строки table, подходящие под condition
+func (s *DBStorage) retainRows(ctx context.Context, dryRun bool, table, condition, change string, args ...interface{}) (int64, error) {
+	if dryRun {
+		var count int64
+		if err := s.DB.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+table+" WHERE "+condition, args...).Scan(&count); err != nil {
+			return 0, fmt.Errorf("count %s: %w", table, err)
+		}
+		return count, nil
+	}
+	res, err := s.DB.ExecContext(ctx, change+" WHERE "+condition, args...)
+	if err != nil {
+		return 0, fmt.Errorf("apply retention to %s: %w", table, err)
+	}
+	return res.RowsAffected()
+}
+
+// retainInactiveAccounts удаляет или обезличивает учетные записи без входа с cutoff (никогда не
+// входившие — зарегистрированные до cutoff). Администраторы не затрагиваются. Каждая запись
+// обрабатывается в своей транзакции с повторной проверкой условия: пользователь мог войти
+// после выборки.
+func (s *DBStorage) retainInactiveAccounts(ctx context.Context, cutoff time.Time, anonymize, dryRun bool) (int64, error) {
+	condition := "is_admin = FALSE AND COALESCE(last_login, created_at) < ?"
+	if anonymize {
+		condition += " AND email NOT LIKE '%" + anonymizedEmailDomain + "'"
+	}
+	if dryRun {
+		return s.retainRows(ctx, true, "users", condition, "", cutoff)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, "SELECT id FROM users WHERE "+condition, cutoff)
+	if err != nil {
+		return 0, fmt.Errorf("select inactive accounts: %w", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan row: %w", err)
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	var affected int64
+	for _, id := range ids {
+		changed := false
+		err := s.inTx(ctx, func(tx *sql.Tx) error {
+			var res sql.Result
+			var err error
+			if anonymize {
+				name := "anonymized-" + strconv.Itoa(id)
+				res, err = tx.ExecContext(ctx,
+					"UPDATE users SET username = ?, email = ?, full_name = '', password_hash = '', totp_secret = NULL, "+
+						"is_2fa_enabled = FALSE, is_active = FALSE, otp_code = NULL, otp_expires_at = NULL, avatar_key = '', "+
+						"version = version + 1 WHERE id = ? AND "+condition,
+					name, name+anonymizedEmailDomain, id, cutoff)
+			} else {
+				res, err = tx.ExecContext(ctx, "DELETE FROM users WHERE id = ? AND "+condition, id, cutoff)
+			}
+			if err != nil {
+				return err
+			}
+			if n, err := res.RowsAffected(); err != nil || n == 0 {
+				return err
+			}
+			changed = true
+
+			if !anonymize {
+				return insertOutboxEvent(ctx, tx, models.EventUserDeleted, id, map[string]interface{}{
+					"userId": id,
+					"reason": "retention",
+				})
+			}
+			for _, table := range personalDataTables {
+				if _, err := tx.ExecContext(ctx, "DELETE FROM "+table+" WHERE user_id = ?", id); err != nil {
+					return fmt.Errorf("clear %s: %w", table, err)
+				}
+			}
+			return insertOutboxEvent(ctx, tx, models.EventUserAnonymized, id, map[string]interface{}{
+				"userId": id,
+			})
+		})
+		if err != nil {
+			return affected, fmt.Errorf("apply retention to account %d: %w", id, err)
+		}
+		if changed {
+			affected++
+		}
+	}
+	return affected, nil
+}
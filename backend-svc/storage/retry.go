@@ -0,0 +1,48 @@
+package storage
+
+import (
+	"errors"
+	"expvar"
+	"math/rand"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// maxTxAttempts — сколько раз выполняется транзакция, прерванная блокировкой
+const maxTxAttempts = 3
+
+// Коды ошибок, после которых транзакцию безопасно повторить целиком
+const (
+	mysqlErrLockWaitTimeout = 1205
+	mysqlErrDeadlock        = 1213
+	sqliteBusy              = 5
+	sqliteLocked            = 6
+)
+
+var txRetries = expvar.NewInt("db_tx_retries_total")
+
+// isRetryableTxError сообщает, была ли транзакция откачена из-за взаимной блокировки
+// или таймаута ожидания блокировки
+func isRetryableTxError(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == mysqlErrDeadlock || mysqlErr.Number == mysqlErrLockWaitTimeout
+	}
+
+	// Ошибки modernc.org/sqlite; расширенные коды несут основной код в младшем байте
+	var sqliteErr interface{ Code() int }
+	if errors.As(err, &sqliteErr) {
+		code := sqliteErr.Code() & 0xff
+		return code == sqliteBusy || code == sqliteLocked
+	}
+
+	return false
+}
+
+// txRetryBackoff возвращает задержку перед повтором: экспоненциальную со случайным
+// разбросом, чтобы конкурирующие транзакции не столкнулись снова
+func txRetryBackoff(attempt int) time.Duration {
+	base := 20 * time.Millisecond << (attempt - 1)
+	return base/2 + time.Duration(rand.Int63n(int64(base)))
+}
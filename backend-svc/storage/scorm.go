@@ -0,0 +1,194 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrScormPackageNotFound — курс создан не из пакета SCORM
+	ErrScormPackageNotFound = errors.New("scorm package not found")
+	// ErrScormItemNotFound — задание не запускает учебный объект SCORM
+	ErrScormItemNotFound = errors.New("scorm item not found")
+)
+
+const scormItemColumns = "i.task_id, i.identifier, t.title, i.scorm_type, i.launch, p.course_id, p.version, p.asset_key"
+
+// CreateScormCourse создает курс, его задания, пакет и учебные объекты в одной транзакции
+func (s *DBStorage) CreateScormCourse(course models.Course, pkg models.ScormPackage) (models.ScormPackage, error) {
+	ctx, done := s.startQuery("CreateScormCourse")
+	defer done()
+
+	if len(course.Tasks) != len(pkg.Items) {
+		return models.ScormPackage{}, fmt.Errorf("create scorm course: %d tasks for %d items", len(course.Tasks), len(pkg.Items))
+	}
+	pkg.CreatedAt = time.Now().UTC()
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		courseID, taskIDs, err := insertCourse(ctx, tx, course, s.organization())
+		if err != nil {
+			return err
+		}
+		pkg.CourseID = courseID
+
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO scorm_packages (course_id, identifier, version, title, asset_key, created_by, created_at) VALUES (?, ?, ?, ?, ?, ?, ?)",
+			courseID, pkg.Identifier, pkg.Version, pkg.Title, pkg.AssetKey, pkg.CreatedBy, pkg.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("insert scorm package: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get scorm package id: %w", err)
+		}
+		pkg.ID = int(id)
+
+		stmt, err := tx.PrepareContext(ctx,
+			"INSERT INTO scorm_items (task_id, package_id, identifier, scorm_type, launch) VALUES (?, ?, ?, ?, ?)")
+		if err != nil {
+			return fmt.Errorf("prepare scorm item statement: %w", err)
+		}
+		defer stmt.Close()
+
+		pkg.Items = append([]models.ScormItem(nil), pkg.Items...)
+		for i := range pkg.Items {
+			item := &pkg.Items[i]
+			item.TaskID, item.CourseID, item.Version, item.AssetKey = taskIDs[i], courseID, pkg.Version, pkg.AssetKey
+			if _, err := stmt.ExecContext(ctx, item.TaskID, pkg.ID, item.Identifier, item.ScormType, item.Launch); err != nil {
+				return fmt.Errorf("insert scorm item %q: %w", item.Identifier, err)
+			}
+		}
+		return nil
+	})
+	return pkg, err
+}
+
+// GetScormPackage возвращает пакет курса с учебными объектами в порядке заданий
+func (s *DBStorage) GetScormPackage(courseID int) (models.ScormPackage, error) {
+	ctx, done := s.startQuery("GetScormPackage")
+	defer done()
+
+	var pkg models.ScormPackage
+	var createdBy sql.NullInt64
+	err := s.DB.QueryRowContext(ctx,
+		"SELECT id, course_id, identifier, version, title, asset_key, created_by, created_at FROM scorm_packages WHERE course_id = ?",
+		courseID).Scan(&pkg.ID, &pkg.CourseID, &pkg.Identifier, &pkg.Version, &pkg.Title, &pkg.AssetKey, &createdBy, &pkg.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ScormPackage{}, ErrScormPackageNotFound
+	}
+	if err != nil {
+		return models.ScormPackage{}, fmt.Errorf("get scorm package: %w", err)
+	}
+	pkg.CreatedBy = intOrNil(createdBy)
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT "+scormItemColumns+" FROM scorm_items i JOIN tasks t ON t.id = i.task_id JOIN scorm_packages p ON p.id = i.package_id "+
+			"WHERE i.package_id = ? ORDER BY t.task_order, t.id", pkg.ID)
+	if err != nil {
+		return models.ScormPackage{}, fmt.Errorf("query scorm items: %w", err)
+	}
+	defer rows.Close()
+
+	pkg.Items = []models.ScormItem{}
+	for rows.Next() {
+		item, err := scanScormItem(rows)
+		if err != nil {
+			return models.ScormPackage{}, err
+		}
+		pkg.Items = append(pkg.Items, item)
+	}
+	return pkg, rows.Err()
+}
+
+// GetScormItem возвращает учебный объект, который запускает задание
+func (s *DBStorage) GetScormItem(taskID int) (models.ScormItem, error) {
+	ctx, done := s.startQuery("GetScormItem")
+	defer done()
+
+	item, err := scanScormItem(s.DB.QueryRowContext(ctx,
+		"SELECT "+scormItemColumns+" FROM scorm_items i JOIN tasks t ON t.id = i.task_id JOIN scorm_packages p ON p.id = i.package_id "+
+			"WHERE i.task_id = ?", taskID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ScormItem{}, ErrScormItemNotFound
+	}
+	return item, err
+}
+
+// GetScormAttempt возвращает сохраненные данные CMI учащегося
+func (s *DBStorage) GetScormAttempt(userID, taskID int) (models.ScormAttempt, error) {
+	ctx, done := s.startQuery("GetScormAttempt")
+	defer done()
+
+	return scanScormAttempt(ctx, s.DB, userID, taskID)
+}
+
+// SaveScormAttempt сохраняет данные CMI и возвращает попытку в том виде, в каком она записана
+func (s *DBStorage) SaveScormAttempt(attempt models.ScormAttempt) (models.ScormAttempt, error) {
+	ctx, done := s.startQuery("SaveScormAttempt")
+	defer done()
+
+	data, err := json.Marshal(attempt.Data)
+	if err != nil {
+		return models.ScormAttempt{}, fmt.Errorf("encode cmi data: %w", err)
+	}
+	err = s.inTx(ctx, func(tx *sql.Tx) error {
+		previous, err := scanScormAttempt(ctx, tx, attempt.UserID, attempt.TaskID)
+		if err != nil {
+			return err
+		}
+		if previous.CompletedAt != nil {
+			attempt.CompletedAt = previous.CompletedAt
+		}
+		attempt.UpdatedAt = time.Now().UTC()
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO scorm_attempts (user_id, task_id, cmi_data, status, score, completed_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"user_id", "task_id"}, "cmi_data", "status", "score", "completed_at", "updated_at"),
+			attempt.UserID, attempt.TaskID, string(data), attempt.Status, attempt.Score, attempt.CompletedAt, attempt.UpdatedAt)
+		if err != nil {
+			return fmt.Errorf("save scorm attempt: %w", err)
+		}
+		return nil
+	})
+	return attempt, err
+}
+
+func scanScormItem(row rowScanner) (models.ScormItem, error) {
+	var item models.ScormItem
+	err := row.Scan(&item.TaskID, &item.Identifier, &item.Title, &item.ScormType, &item.Launch,
+		&item.CourseID, &item.Version, &item.AssetKey)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return item, fmt.Errorf("scan scorm item: %w", err)
+	}
+	return item, err
+}
+
+// scanScormAttempt читает попытку; если данных нет, возвращает пустую попытку
+func scanScormAttempt(ctx context.Context, q queryer, userID, taskID int) (models.ScormAttempt, error) {
+	attempt := models.ScormAttempt{UserID: userID, TaskID: taskID, Data: map[string]string{}}
+	var data string
+	var score sql.NullFloat64
+	var completedAt sql.NullTime
+	err := q.QueryRowContext(ctx,
+		"SELECT cmi_data, status, score, completed_at, updated_at FROM scorm_attempts WHERE user_id = ? AND task_id = ?",
+		userID, taskID).Scan(&data, &attempt.Status, &score, &completedAt, &attempt.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return attempt, nil
+	}
+	if err != nil {
+		return models.ScormAttempt{}, fmt.Errorf("get scorm attempt: %w", err)
+	}
+	if err := json.Unmarshal([]byte(data), &attempt.Data); err != nil {
+		return models.ScormAttempt{}, fmt.Errorf("decode cmi data: %w", err)
+	}
+	if score.Valid {
+		attempt.Score = &score.Float64
+	}
+	if completedAt.Valid {
+		attempt.CompletedAt = &completedAt.Time
+	}
+	return attempt, nil
+}
@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// GetSessionState возвращает состояние сессии, требование смены пароля и организацию пользователя
+func (s *DBStorage) GetSessionState(userID int) (models.SessionState, error) {
+	ctx, done := s.startQuery("GetSessionState")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT u.single_session, COALESCE(u.session_id, ''), u.must_change_password, u.is_active, u.organization_id, o.is_active "+
+			"FROM users u JOIN organizations o ON o.id = u.organization_id WHERE u.id = ?")
+	if err != nil {
+		return models.SessionState{}, err
+	}
+
+	var state models.SessionState
+	err = stmt.QueryRowContext(ctx, userID).Scan(&state.SingleSession, &state.SessionID, &state.MustChangePassword, &state.IsActive,
+		&state.OrganizationID, &state.OrganizationActive)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.SessionState{}, ErrUserNotFound
+	}
+	if err != nil {
+		return models.SessionState{}, fmt.Errorf("get session state: %w", err)
+	}
+	return state, nil
+}
+
+// StartSession запоминает идентификатор новой сессии пользователя, вытесняя предыдущую
+func (s *DBStorage) StartSession(userID int, sessionID string) error {
+	ctx, done := s.startQuery("StartSession")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "UPDATE users SET session_id = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	if _, err := stmt.ExecContext(ctx, sessionID, userID); err != nil {
+		return fmt.Errorf("start session: %w", err)
+	}
+	return nil
+}
+
+// PurgeStaleSessions забывает сессии пользователей, последний вход которых был раньше before
+func (s *DBStorage) PurgeStaleSessions(before time.Time) (int64, error) {
+	ctx, done := s.startQuery("PurgeStaleSessions")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx,
+		"UPDATE users SET session_id = NULL WHERE session_id IS NOT NULL AND (last_login IS NULL OR last_login < ?)",
+		before.UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// SetSingleSession включает или выключает режим единственной сессии для пользователя
+func (s *DBStorage) SetSingleSession(userID int, enabled bool) error {
+	ctx, done := s.startQuery("SetSingleSession")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpUserVersion(ctx, tx, userID, AnyVersion); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE users SET single_session = ? WHERE id = ?", enabled, userID)
+		return err
+	})
+}
+
+// SetMustChangePassword требует от пользователя сменить пароль или снимает требование.
+// Смена пароля через UpdateUserProfile снимает его автоматически.
+func (s *DBStorage) SetMustChangePassword(userID int, required bool) error {
+	ctx, done := s.startQuery("SetMustChangePassword")
+	defer done()
+
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := bumpUserVersion(ctx, tx, userID, AnyVersion); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx, "UPDATE users SET must_change_password = ? WHERE id = ?", required, userID)
+		return err
+	})
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"database/sql"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// GetSettings возвращает все сохраненные runtime-параметры
+func (s *DBStorage) GetSettings() ([]models.Setting, error) {
+	ctx, done := s.startQuery("GetSettings")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx, "SELECT setting_key, setting_value, updated_by, updated_at FROM settings")
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	var settings []models.Setting
+	for rows.Next() {
+		var setting models.Setting
+		var updatedBy sql.NullInt64
+		if err := rows.Scan(&setting.Key, &setting.Value, &updatedBy, &setting.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		setting.UpdatedBy = int(updatedBy.Int64)
+		settings = append(settings, setting)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+
+	return settings, nil
+}
+
+// UpsertSetting создает или обновляет runtime-параметр
+func (s *DBStorage) UpsertSetting(setting models.Setting) error {
+	ctx, done := s.startQuery("UpsertSetting")
+	defer done()
+
+	var updatedBy interface{}
+	if setting.UpdatedBy != 0 {
+		updatedBy = setting.UpdatedBy
+	}
+
+	_, err := s.DB.ExecContext(ctx,
+		"INSERT INTO settings (setting_key, setting_value, updated_by, updated_at) VALUES (?, ?, ?, ?)"+
+			s.onConflictUpdate([]string{"setting_key"}, "setting_value", "updated_by", "updated_at"),
+		setting.Key, setting.Value, updatedBy, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("upsert setting %s: %w", setting.Key, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"database/sql"
+	"embed"
+	"fmt"
+	"io/fs"
+	"lmsmodule/backend-svc/migrate"
+	"log"
+	"strings"
+
+	_ "modernc.org/sqlite"
+)
+
+// sqliteMigrations — схема для SQLite, повторяющая MySQL-миграции из каталога migrations
+//
+//go:embed sqlite/*.sql
+var sqliteMigrations embed.FS
+
+// SQLiteMigrations возвращает встроенные миграции SQLite
+func SQLiteMigrations() fs.FS {
+	schema, err := fs.Sub(sqliteMigrations, "sqlite")
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// SchemaVersion — версия схемы, под которую собран код: последняя встроенная миграция.
+// Миграции MySQL нумеруются так же, поэтому версия общая для обоих драйверов.
+func SchemaVersion() int64 {
+	migrations, err := migrate.Load(SQLiteMigrations())
+	if err != nil || len(migrations) == 0 {
+		panic(fmt.Sprintf("storage: embedded migrations: %v", err))
+	}
+	return migrations[len(migrations)-1].Version
+}
+
+// OpenSQLiteDB открывает файл SQLite по пути dsn без применения миграций
+func OpenSQLiteDB(dsn string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", sqliteDSN(dsn))
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	// SQLite допускает только одного писателя; одно соединение исключает SQLITE_BUSY
+	db.SetMaxOpenConns(1)
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("ping sqlite: %w", err)
+	}
+	return db, nil
+}
+
+// OpenSQLite открывает файл SQLite по пути dsn, применяет встроенные миграции
+// и возвращает готовое к работе хранилище. Предназначено для локальной разработки.
+func OpenSQLite(dsn string) (*DBStorage, error) {
+	db, err := OpenSQLiteDB(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	applied, err := migrate.Up(db, SQLiteMigrations())
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("migrate sqlite: %w", err)
+	}
+	for _, m := range applied {
+		log.Printf("SQLite: applied migration %d_%s", m.Version, m.Name)
+	}
+
+	return &DBStorage{DB: db, Dialect: DialectSQLite}, nil
+}
+
+// sqliteDSN добавляет к пути параметры, без которых хранилище работает некорректно:
+// внешние ключи (ON DELETE CASCADE), ожидание блокировки и формат времени
+func sqliteDSN(dsn string) string {
+	params := []string{
+		"_pragma=foreign_keys(1)",
+		"_pragma=busy_timeout(5000)",
+		"_time_format=sqlite",
+	}
+	sep := "?"
+	if strings.Contains(dsn, "?") {
+		sep = "&"
+	}
+	return dsn + sep + strings.Join(params, "&")
+}
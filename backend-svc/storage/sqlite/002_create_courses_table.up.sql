@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS courses (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    vulnerability_type TEXT NOT NULL,
+    description TEXT NOT NULL
+);
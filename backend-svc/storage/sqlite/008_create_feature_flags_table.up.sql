@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS feature_flags (
+    flag_key TEXT PRIMARY KEY,
+    description TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    rollout_percentage INTEGER NOT NULL DEFAULT 0,
+    target_groups TEXT,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- SQLite не допускает CURRENT_TIMESTAMP по умолчанию в ALTER TABLE ADD COLUMN
+ALTER TABLE courses ADD COLUMN updated_at DATETIME NOT NULL DEFAULT '1970-01-01 00:00:00';
+UPDATE courses SET updated_at = CURRENT_TIMESTAMP;
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN otp_attempts INTEGER NOT NULL DEFAULT 0;
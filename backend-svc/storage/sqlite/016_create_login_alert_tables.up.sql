@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS login_contexts (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    fingerprint TEXT NOT NULL,
+    ip TEXT NOT NULL DEFAULT '',
+    user_agent TEXT NOT NULL DEFAULT '',
+    location TEXT NOT NULL DEFAULT '',
+    first_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_seen_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, fingerprint)
+);
+
+CREATE TABLE IF NOT EXISTS account_lock_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_account_lock_tokens_expires_at ON account_lock_tokens (expires_at);
@@ -0,0 +1,2 @@
+ALTER TABLE users ADD COLUMN single_session BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE users ADD COLUMN session_id TEXT;
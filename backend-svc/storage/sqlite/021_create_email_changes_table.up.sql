@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS email_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_email TEXT NOT NULL DEFAULT '',
+    new_email TEXT NOT NULL,
+    confirm_token_hash TEXT NOT NULL UNIQUE,
+    cancel_token_hash TEXT NOT NULL UNIQUE,
+    requested_ip TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    cancel_expires_at DATETIME NOT NULL,
+    confirmed_at DATETIME,
+    cancelled_at DATETIME
+);
+CREATE INDEX IF NOT EXISTS idx_email_changes_user ON email_changes (user_id, created_at);
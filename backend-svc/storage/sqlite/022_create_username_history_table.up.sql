@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS username_history (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_username TEXT NOT NULL,
+    new_username TEXT NOT NULL,
+    changed_by INTEGER,
+    changed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reserved_until DATETIME NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_username_history_old ON username_history (old_username, changed_at);
+CREATE INDEX IF NOT EXISTS idx_username_history_user ON username_history (user_id, changed_at);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS profile_privacy (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    profile_visible BOOLEAN NOT NULL DEFAULT TRUE,
+    show_full_name BOOLEAN NOT NULL DEFAULT TRUE,
+    show_avatar BOOLEAN NOT NULL DEFAULT TRUE,
+    show_badges BOOLEAN NOT NULL DEFAULT TRUE,
+    show_course_stats BOOLEAN NOT NULL DEFAULT TRUE,
+    show_skills BOOLEAN NOT NULL DEFAULT TRUE,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
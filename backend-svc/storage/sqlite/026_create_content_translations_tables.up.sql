@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS course_translations (
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    vulnerability_type TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_id, locale)
+);
+CREATE INDEX IF NOT EXISTS idx_course_translations_locale ON course_translations (locale);
+
+CREATE TABLE IF NOT EXISTS task_translations (
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL,
+    title TEXT NOT NULL DEFAULT '',
+    description TEXT NOT NULL DEFAULT '',
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, locale)
+);
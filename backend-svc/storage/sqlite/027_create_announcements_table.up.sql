@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS announcements (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NULL REFERENCES courses(id) ON DELETE CASCADE,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    author_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    publish_at DATETIME NOT NULL,
+    expires_at DATETIME NULL,
+    notified_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_announcements_publish ON announcements (publish_at);
+CREATE INDEX IF NOT EXISTS idx_announcements_pending ON announcements (notified_at, publish_at);
@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS conversations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    student_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    instructor_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_message_at DATETIME NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, student_id, instructor_id)
+);
+CREATE INDEX IF NOT EXISTS idx_conversations_student ON conversations (student_id, last_message_at);
+CREATE INDEX IF NOT EXISTS idx_conversations_instructor ON conversations (instructor_id, last_message_at);
+
+CREATE TABLE IF NOT EXISTS conversation_messages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    conversation_id INTEGER NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    sender_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    task_id INTEGER NULL REFERENCES tasks(id) ON DELETE SET NULL,
+    reported_at DATETIME NULL,
+    reported_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    hidden_at DATETIME NULL,
+    hidden_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_conversation_messages_conversation ON conversation_messages (conversation_id, id);
+CREATE INDEX IF NOT EXISTS idx_conversation_messages_reported ON conversation_messages (reported_at);
+
+CREATE TABLE IF NOT EXISTS conversation_reads (
+    conversation_id INTEGER NOT NULL REFERENCES conversations(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    last_read_message_id INTEGER NOT NULL,
+    PRIMARY KEY (conversation_id, user_id)
+);
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS support_tickets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    subject TEXT NOT NULL,
+    course_id INTEGER NULL REFERENCES courses(id) ON DELETE SET NULL,
+    task_id INTEGER NULL REFERENCES tasks(id) ON DELETE SET NULL,
+    lab_session_id TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    assignee_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at DATETIME NULL
+);
+CREATE INDEX IF NOT EXISTS idx_support_tickets_user ON support_tickets (user_id, updated_at);
+CREATE INDEX IF NOT EXISTS idx_support_tickets_status ON support_tickets (status, updated_at);
+
+CREATE TABLE IF NOT EXISTS support_ticket_replies (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    ticket_id INTEGER NOT NULL REFERENCES support_tickets(id) ON DELETE CASCADE,
+    author_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    internal BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_support_ticket_replies_ticket ON support_ticket_replies (ticket_id, id);
@@ -0,0 +1,60 @@
+CREATE TABLE IF NOT EXISTS discussion_threads (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    author_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    title TEXT NOT NULL,
+    locked_at DATETIME NULL,
+    locked_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    last_comment_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_discussion_threads_course ON discussion_threads (course_id, last_comment_at);
+
+CREATE TABLE IF NOT EXISTS discussion_comments (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    thread_id INTEGER NOT NULL REFERENCES discussion_threads(id) ON DELETE CASCADE,
+    author_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    body TEXT NOT NULL,
+    hidden_at DATETIME NULL,
+    hidden_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+CREATE INDEX IF NOT EXISTS idx_discussion_comments_thread ON discussion_comments (thread_id, id);
+
+CREATE TABLE IF NOT EXISTS course_reviews (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    rating INTEGER NOT NULL,
+    body TEXT NOT NULL,
+    hidden_at DATETIME NULL,
+    hidden_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS course_bans (
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    banned_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS content_reports (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    content_type TEXT NOT NULL,
+    content_id INTEGER NOT NULL,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    reporter_id INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    status TEXT NOT NULL DEFAULT 'open',
+    resolved_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at DATETIME NULL,
+    note TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (content_type, content_id, reporter_id)
+);
+CREATE INDEX IF NOT EXISTS idx_content_reports_status ON content_reports (status, created_at);
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS scorm_packages (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL UNIQUE REFERENCES courses(id) ON DELETE CASCADE,
+    identifier TEXT NOT NULL,
+    version TEXT NOT NULL,
+    title TEXT NOT NULL,
+    asset_key TEXT NOT NULL,
+    created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS scorm_items (
+    task_id INTEGER PRIMARY KEY REFERENCES tasks(id) ON DELETE CASCADE,
+    package_id INTEGER NOT NULL REFERENCES scorm_packages(id) ON DELETE CASCADE,
+    identifier TEXT NOT NULL,
+    scorm_type TEXT NOT NULL,
+    launch TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS idx_scorm_items_package ON scorm_items (package_id);
+
+CREATE TABLE IF NOT EXISTS scorm_attempts (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL REFERENCES scorm_items(task_id) ON DELETE CASCADE,
+    cmi_data TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT '',
+    score REAL NULL,
+    completed_at DATETIME NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, task_id)
+);
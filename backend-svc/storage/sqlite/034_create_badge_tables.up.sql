@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS badge_classes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NULL UNIQUE REFERENCES courses(id) ON DELETE CASCADE,
+    name TEXT NOT NULL,
+    description TEXT NOT NULL,
+    criteria TEXT NOT NULL,
+    image BLOB NULL,
+    created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS badge_assertions (
+    id TEXT PRIMARY KEY,
+    badge_class_id INTEGER NOT NULL REFERENCES badge_classes(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    issued_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    issued_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    revoked_at DATETIME NULL,
+    revocation_reason TEXT NOT NULL DEFAULT '',
+    credential TEXT NOT NULL,
+    UNIQUE (badge_class_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_badge_assertions_user ON badge_assertions (user_id);
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS certificates (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    code TEXT NOT NULL UNIQUE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id INTEGER NULL REFERENCES courses(id) ON DELETE SET NULL,
+    issued_to TEXT NOT NULL,
+    course_title TEXT NOT NULL,
+    issued_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME NULL,
+    revoked_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    revocation_reason TEXT NOT NULL DEFAULT '',
+    UNIQUE (user_id, course_id)
+);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS telegram_accounts (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    chat_id INTEGER NOT NULL UNIQUE,
+    telegram_username TEXT NOT NULL DEFAULT '',
+    linked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS telegram_link_codes (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    code_hash TEXT NOT NULL UNIQUE,
+    expires_at DATETIME NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS telegram_deadline_reminders (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    due_at DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, task_id, due_at)
+);
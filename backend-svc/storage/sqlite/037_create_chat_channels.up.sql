@@ -0,0 +1,28 @@
+CREATE TABLE IF NOT EXISTS chat_channels (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    group_name TEXT NOT NULL REFERENCES user_groups(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    provider TEXT NOT NULL,
+    webhook_url TEXT NOT NULL DEFAULT '',
+    bot_token TEXT NOT NULL DEFAULT '',
+    server_url TEXT NOT NULL DEFAULT '',
+    channel TEXT NOT NULL DEFAULT '',
+    course_id INTEGER NULL REFERENCES courses(id) ON DELETE CASCADE,
+    locale TEXT NOT NULL DEFAULT '',
+    notify_course_published BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_leaderboard BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_deadlines BOOLEAN NOT NULL DEFAULT FALSE,
+    last_leaderboard_at DATETIME NULL,
+    created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_chat_channels_group ON chat_channels(group_name);
+
+CREATE TABLE IF NOT EXISTS chat_deadline_reminders (
+    channel_id INTEGER NOT NULL REFERENCES chat_channels(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    due_at DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (channel_id, task_id, due_at)
+);
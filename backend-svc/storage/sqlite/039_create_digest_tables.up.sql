@@ -0,0 +1,15 @@
+ALTER TABLE courses ADD COLUMN created_at DATETIME NULL;
+
+CREATE TABLE IF NOT EXISTS digest_settings (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    opted_out BOOLEAN NOT NULL DEFAULT FALSE,
+    last_digest_at DATETIME NULL
+);
+
+CREATE TABLE IF NOT EXISTS digest_unsubscribe_tokens (
+    token_hash TEXT PRIMARY KEY,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_digest_unsubscribe_tokens_user ON digest_unsubscribe_tokens (user_id);
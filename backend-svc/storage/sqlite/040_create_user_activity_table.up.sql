@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_activity (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    activity_date TEXT NOT NULL,
+    PRIMARY KEY (user_id, activity_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_activity_date ON user_activity (activity_date);
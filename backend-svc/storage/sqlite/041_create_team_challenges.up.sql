@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS team_challenges (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    scoring TEXT NOT NULL,
+    best_of INTEGER NOT NULL DEFAULT 0,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    created_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_challenges_starts ON team_challenges(starts_at);
+
+CREATE TABLE IF NOT EXISTS team_challenge_groups (
+    challenge_id INTEGER NOT NULL REFERENCES team_challenges(id) ON DELETE CASCADE,
+    group_name TEXT NOT NULL REFERENCES user_groups(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    PRIMARY KEY (challenge_id, group_name)
+);
+
+CREATE INDEX IF NOT EXISTS idx_team_challenge_groups_group ON team_challenge_groups(group_name);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS course_recertification (
+    course_id INTEGER PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+    period_months INTEGER NOT NULL,
+    reminder_days INTEGER NOT NULL DEFAULT 30,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS course_completions (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    completed_at DATETIME NOT NULL,
+    remind_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    reminded_at DATETIME NULL,
+    expired_at DATETIME NULL,
+    PRIMARY KEY (user_id, course_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_completions_remind ON course_completions(remind_at);
+CREATE INDEX IF NOT EXISTS idx_course_completions_expires ON course_completions(expires_at);
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS course_attempts (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    tasks_completed INTEGER NOT NULL,
+    tasks_total INTEGER NOT NULL,
+    reset_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    reset_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_course_attempts_user_course ON course_attempts(user_id, course_id, reset_at);
+
+CREATE TABLE IF NOT EXISTS course_attempt_tasks (
+    attempt_id INTEGER NOT NULL REFERENCES course_attempts(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+    completed_at DATETIME NULL,
+    scorm_status TEXT NULL,
+    scorm_score REAL NULL,
+    scorm_data TEXT NULL,
+    PRIMARY KEY (attempt_id, task_id)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS progress_snapshots (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    snapshot_date TEXT NOT NULL,
+    tasks_completed INTEGER NOT NULL,
+    tasks_total INTEGER NOT NULL,
+    PRIMARY KEY (user_id, course_id, snapshot_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_progress_snapshots_date ON progress_snapshots (snapshot_date);
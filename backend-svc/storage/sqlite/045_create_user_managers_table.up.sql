@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS user_managers (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    manager_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    assigned_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_managers_manager ON user_managers (manager_id);
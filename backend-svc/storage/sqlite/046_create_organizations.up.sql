@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS organizations (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    slug TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO organizations (id, slug, name) VALUES (1, 'default', 'Default organization');
+
+-- SQLite не допускает REFERENCES с ненулевым значением по умолчанию в ALTER TABLE ADD COLUMN
+ALTER TABLE users ADD COLUMN organization_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE courses ADD COLUMN organization_id INTEGER NOT NULL DEFAULT 1;
+ALTER TABLE user_groups ADD COLUMN organization_id INTEGER NOT NULL DEFAULT 1;
+
+CREATE INDEX IF NOT EXISTS idx_users_organization ON users (organization_id);
+CREATE INDEX IF NOT EXISTS idx_courses_organization ON courses (organization_id);
+CREATE INDEX IF NOT EXISTS idx_user_groups_organization ON user_groups (organization_id);
+
+CREATE TABLE IF NOT EXISTS organization_admins (
+    organization_id INTEGER NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    PRIMARY KEY (organization_id, user_id)
+);
@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS organization_branding (
+    organization_id INTEGER PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    logo BLOB NULL,
+    primary_color TEXT NOT NULL DEFAULT '',
+    accent_color TEXT NOT NULL DEFAULT '',
+    email_footer TEXT NULL,
+    certificate_template TEXT NULL,
+    updated_at DATETIME NOT NULL
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS pending_registrations (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    requested_ip TEXT NOT NULL DEFAULT '',
+    requested_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_pending_registrations_requested ON pending_registrations (requested_at);
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS user_access_expiry (
+    user_id INTEGER PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    expires_at DATETIME NOT NULL,
+    deactivated_at DATETIME NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_access_expiry_expires ON user_access_expiry (expires_at);
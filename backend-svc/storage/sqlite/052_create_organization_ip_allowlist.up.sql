@@ -0,0 +1,6 @@
+CREATE TABLE IF NOT EXISTS organization_ip_allowlist (
+    organization_id INTEGER PRIMARY KEY REFERENCES organizations(id) ON DELETE CASCADE,
+    cidrs TEXT NOT NULL,
+    updated_by INTEGER NULL REFERENCES users(id) ON DELETE SET NULL,
+    updated_at DATETIME NOT NULL
+);
@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS schema_compat (
+    id INTEGER PRIMARY KEY,
+    min_app_version INTEGER NOT NULL
+);
+
+INSERT INTO schema_compat (id, min_app_version)
+SELECT 1, 0 WHERE NOT EXISTS (SELECT 1 FROM schema_compat);
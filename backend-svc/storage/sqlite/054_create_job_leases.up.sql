@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS job_leases (
+    name TEXT PRIMARY KEY,
+    holder TEXT NOT NULL,
+    expires_at DATETIME NOT NULL,
+    acquired_at DATETIME NOT NULL,
+    version INTEGER NOT NULL DEFAULT 1
+);
@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS course_summary (
+    course_id INTEGER PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+    tasks_count INTEGER NOT NULL DEFAULT 0,
+    avg_difficulty REAL NULL,
+    enrollments INTEGER NOT NULL DEFAULT 0,
+    reviews INTEGER NOT NULL DEFAULT 0,
+    avg_rating REAL NULL,
+    updated_at DATETIME NOT NULL
+);
+
+INSERT INTO course_summary (course_id, tasks_count, avg_difficulty, enrollments, reviews, avg_rating, updated_at)
+SELECT c.id,
+       (SELECT COUNT(*) FROM tasks t WHERE t.course_id = c.id),
+       (SELECT AVG(CASE t.difficulty WHEN 'easy' THEN 1 WHEN 'medium' THEN 2 ELSE 3 END) FROM tasks t WHERE t.course_id = c.id),
+       (SELECT COUNT(DISTINCT p.user_id) FROM user_progress p JOIN tasks t ON t.id = p.task_id WHERE t.course_id = c.id),
+       (SELECT COUNT(*) FROM course_reviews r WHERE r.course_id = c.id AND r.hidden_at IS NULL),
+       (SELECT AVG(r.rating) FROM course_reviews r WHERE r.course_id = c.id AND r.hidden_at IS NULL),
+       CURRENT_TIMESTAMP
+FROM courses c;
@@ -0,0 +1,20 @@
+ALTER TABLE courses ADD COLUMN content_version INTEGER NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS course_changes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    version INTEGER NOT NULL,
+    kind TEXT NOT NULL,
+    task_id INTEGER NULL REFERENCES tasks(id) ON DELETE SET NULL,
+    title TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (course_id, version)
+);
+
+CREATE TABLE IF NOT EXISTS course_visits (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    seen_version INTEGER NOT NULL DEFAULT 0,
+    visited_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, course_id)
+);
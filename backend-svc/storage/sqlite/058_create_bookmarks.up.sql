@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS bookmarks (
+    user_id INTEGER NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    course_id INTEGER NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+    task_id INTEGER NOT NULL DEFAULT 0,
+    notify BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, course_id, task_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_bookmarks_notify ON bookmarks (course_id, notify);
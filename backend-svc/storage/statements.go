@@ -0,0 +1,65 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync"
+)
+
+// stmtCaches хранит для каждого пула (*sql.DB) кэш подготовленных выражений по тексту запроса.
+// Кэш привязан к пулу, а не к DBStorage, чтобы его разделяли представления вроде Primary().
+var stmtCaches sync.Map
+
+// prepared возвращает подготовленное выражение для query на пуле db, готовя его
+// при первом обращении. sql.Stmt привязан к пулу, сам переподготавливается на новых
+// соединениях и закрывается только в Close, поэтому вызывающий код его не закрывает.
+func (s *DBStorage) prepared(ctx context.Context, db *sql.DB, query string) (*sql.Stmt, error) {
+	cacheValue, _ := stmtCaches.LoadOrStore(db, &sync.Map{})
+	cache := cacheValue.(*sync.Map)
+
+	if stmt, ok := cache.Load(query); ok {
+		return stmt.(*sql.Stmt), nil
+	}
+
+	stmt, err := db.PrepareContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	// Параллельный вызов мог подготовить то же выражение; оставляем первое
+	if existing, loaded := cache.LoadOrStore(query, stmt); loaded {
+		stmt.Close()
+		return existing.(*sql.Stmt), nil
+	}
+	return stmt, nil
+}
+
+// Close закрывает закэшированные подготовленные выражения и пулы соединений,
+// включая реплики
+func (s *DBStorage) Close() error {
+	var firstErr error
+	for _, db := range append([]*sql.DB{s.DB}, s.Replicas...) {
+		if err := closePool(db); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func closePool(db *sql.DB) error {
+	var firstErr error
+	if cacheValue, ok := stmtCaches.LoadAndDelete(db); ok {
+		cacheValue.(*sync.Map).Range(func(_, value interface{}) bool {
+			if err := value.(*sql.Stmt).Close(); err != nil && firstErr == nil {
+				firstErr = fmt.Errorf("close statement: %w", err)
+			}
+			return true
+		})
+	}
+
+	if err := db.Close(); err != nil && firstErr == nil {
+		firstErr = err
+	}
+	return firstErr
+}
@@ -0,0 +1,265 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// statsDayFormat — формат дней в user_activity и в рядах статистики
+const statsDayFormat = "2006-01-02"
+
+// RecordUserActivity отмечает активность пользователя за день; повторная отметка ничего не меняет
+func (s *DBStorage) RecordUserActivity(userID int, day time.Time) error {
+	ctx, done := s.startQuery("RecordUserActivity")
+	defer done()
+
+	if _, err := s.DB.ExecContext(ctx,
+		"INSERT INTO user_activity (user_id, activity_date) VALUES (?, ?)"+
+			s.onConflictUpdate([]string{"user_id", "activity_date"}, "activity_date"),
+		userID, day.UTC().Format(statsDayFormat)); err != nil {
+		return fmt.Errorf("record user activity: %w", err)
+	}
+	return nil
+}
+
+// GetPlatformStats считает показатели агрегирующими запросами к реплике: каждый запрос
+// возвращает по строке на день или курс, а не по строке на пользователя
+func (s *DBStorage) GetPlatformStats(from, to time.Time) (models.PlatformStats, error) {
+	ctx, done := s.startQuery("GetPlatformStats")
+	defer done()
+
+	db := s.reader()
+	from, to = from.UTC(), to.UTC()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	stats := newPlatformStats(from, to)
+
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+		       COALESCE(SUM(CASE WHEN is_active = ? THEN 1 ELSE 0 END), 0),
+		       COALESCE(SUM(CASE WHEN created_at >= ? AND created_at < ? THEN 1 ELSE 0 END), 0)
+		FROM users
+	`, true, from, to).Scan(&stats.Users.Total, &stats.Users.Active, &stats.Users.NewInPeriod); err != nil {
+		return stats, fmt.Errorf("count users: %w", err)
+	}
+
+	if err := db.QueryRowContext(ctx, `
+		SELECT COUNT(DISTINCT CASE WHEN activity_date >= ? THEN user_id END),
+		       COUNT(DISTINCT CASE WHEN activity_date >= ? THEN user_id END),
+		       COUNT(DISTINCT user_id)
+		FROM user_activity
+		WHERE activity_date >= ?
+	`, today.Format(statsDayFormat), today.AddDate(0, 0, -6).Format(statsDayFormat),
+		today.AddDate(0, 0, -29).Format(statsDayFormat)).Scan(&stats.Users.DAU, &stats.Users.WAU, &stats.Users.MAU); err != nil {
+		return stats, fmt.Errorf("count active users: %w", err)
+	}
+
+	series := []struct {
+		name   string
+		query  string
+		args   []any
+		target []models.DailyCount
+	}{
+		{"daily active users", `
+			SELECT activity_date, COUNT(*) FROM user_activity
+			WHERE activity_date >= ? AND activity_date < ?
+			GROUP BY activity_date
+		`, []any{from.Format(statsDayFormat), to.Format(statsDayFormat)}, stats.DailyActive},
+		{"registrations", `
+			SELECT DATE(created_at), COUNT(*) FROM users
+			WHERE created_at >= ? AND created_at < ?
+			GROUP BY DATE(created_at)
+		`, []any{from, to}, stats.Registrations},
+		{"completions", `
+			SELECT DATE(completed_at), COUNT(*) FROM user_progress
+			WHERE completed_at >= ? AND completed_at < ?
+			GROUP BY DATE(completed_at)
+		`, []any{from, to}, stats.Completions},
+	}
+	for _, serie := range series {
+		if err := fillDailyCounts(ctx, db, serie.query, serie.args, serie.target, from); err != nil {
+			return stats, fmt.Errorf("query %s: %w", serie.name, err)
+		}
+	}
+
+	courses, err := s.courseStats(ctx, db, from, to)
+	if err != nil {
+		return stats, err
+	}
+	stats.Courses = courses
+
+	var average sql.NullFloat64
+	if err := db.QueryRowContext(ctx,
+		"SELECT AVG(score) FROM scorm_attempts WHERE score IS NOT NULL").Scan(&average); err != nil {
+		return stats, fmt.Errorf("average score: %w", err)
+	}
+	if average.Valid {
+		stats.AverageScore = &average.Float64
+	}
+	return stats, nil
+}
+
+// fillDailyCounts записывает результат запроса (день, число) в ряд target, начинающийся с from
+func fillDailyCounts(ctx context.Context, db *sql.DB, query string, args []any, target []models.DailyCount, from time.Time) error {
+	rows, err := db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var day any
+		var count int
+		if err := rows.Scan(&day, &count); err != nil {
+			return err
+		}
+		date, err := time.Parse(statsDayFormat, statsDay(day))
+		if err != nil {
+			return fmt.Errorf("parse day %v: %w", day, err)
+		}
+		if i := int(date.Sub(from) / (24 * time.Hour)); i >= 0 && i < len(target) {
+			target[i].Count += count
+		}
+	}
+	return rows.Err()
+}
+
+// courseStats возвращает показатели всех курсов по порядку ID
+func (s *DBStorage) courseStats(ctx context.Context, db *sql.DB, from, to time.Time) ([]models.CourseStats, error) {
+	courses := []models.CourseStats{}
+	index := map[int]int{}
+
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.id, c.vulnerability_type, COUNT(t.id)
+		FROM courses c
+		LEFT JOIN tasks t ON t.course_id = c.id
+		GROUP BY c.id, c.vulnerability_type
+		ORDER BY c.id
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("query courses: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var course models.CourseStats
+		if err := rows.Scan(&course.CourseID, &course.Title, &course.Tasks); err != nil {
+			return nil, fmt.Errorf("scan course: %w", err)
+		}
+		index[course.CourseID] = len(courses)
+		courses = append(courses, course)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query courses: %w", err)
+	}
+
+	// Прогресс сначала сворачивается до строки на пару (курс, пользователь), затем до строки на курс
+	progress, err := db.QueryContext(ctx, `
+		SELECT l.course_id, COUNT(*),
+		       SUM(CASE WHEN l.done = n.total THEN 1 ELSE 0 END),
+		       SUM(l.in_period)
+		FROM (
+			SELECT t.course_id, p.user_id, COUNT(*) AS done,
+			       SUM(CASE WHEN p.completed_at >= ? AND p.completed_at < ? THEN 1 ELSE 0 END) AS in_period
+			FROM user_progress p
+			JOIN tasks t ON t.id = p.task_id
+			GROUP BY t.course_id, p.user_id
+		) l
+		JOIN (SELECT course_id, COUNT(*) AS total FROM tasks GROUP BY course_id) n ON n.course_id = l.course_id
+		GROUP BY l.course_id
+	`, from, to)
+	if err != nil {
+		return nil, fmt.Errorf("query course progress: %w", err)
+	}
+	defer progress.Close()
+	for progress.Next() {
+		var courseID, learners, finished, inPeriod int
+		if err := progress.Scan(&courseID, &learners, &finished, &inPeriod); err != nil {
+			return nil, fmt.Errorf("scan course progress: %w", err)
+		}
+		if i, ok := index[courseID]; ok {
+			courses[i].Learners, courses[i].Finished, courses[i].CompletionsInPeriod = learners, finished, inPeriod
+		}
+	}
+	if err := progress.Err(); err != nil {
+		return nil, fmt.Errorf("query course progress: %w", err)
+	}
+
+	averages := []struct {
+		name  string
+		query string
+		apply func(course *models.CourseStats, average float64, count int)
+	}{
+		{"scores", `
+			SELECT t.course_id, AVG(a.score), COUNT(*)
+			FROM scorm_attempts a
+			JOIN tasks t ON t.id = a.task_id
+			WHERE a.score IS NOT NULL
+			GROUP BY t.course_id
+		`, func(course *models.CourseStats, average float64, _ int) { course.AverageScore = &average }},
+		{"ratings", `
+			SELECT course_id, AVG(rating), COUNT(*)
+			FROM course_reviews
+			WHERE hidden_at IS NULL
+			GROUP BY course_id
+		`, func(course *models.CourseStats, average float64, count int) {
+			course.AverageRating, course.Reviews = &average, count
+		}},
+	}
+	for _, avg := range averages {
+		rows, err := db.QueryContext(ctx, avg.query)
+		if err != nil {
+			return nil, fmt.Errorf("query course %s: %w", avg.name, err)
+		}
+		for rows.Next() {
+			var courseID, count int
+			var average float64
+			if err := rows.Scan(&courseID, &average, &count); err != nil {
+				rows.Close()
+				return nil, fmt.Errorf("scan course %s: %w", avg.name, err)
+			}
+			if i, ok := index[courseID]; ok {
+				avg.apply(&courses[i], average, count)
+			}
+		}
+		err = rows.Err()
+		rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("query course %s: %w", avg.name, err)
+		}
+	}
+	return courses, nil
+}
+
+// newPlatformStats создает статистику с нулевыми рядами по дням [from, to)
+func newPlatformStats(from, to time.Time) models.PlatformStats {
+	stats := models.PlatformStats{
+		GeneratedAt: time.Now().UTC(),
+		From:        from.Format(statsDayFormat),
+		To:          to.AddDate(0, 0, -1).Format(statsDayFormat),
+	}
+	for day := from; day.Before(to); day = day.AddDate(0, 0, 1) {
+		date := day.Format(statsDayFormat)
+		stats.DailyActive = append(stats.DailyActive, models.DailyCount{Date: date})
+		stats.Registrations = append(stats.Registrations, models.DailyCount{Date: date})
+		stats.Completions = append(stats.Completions, models.DailyCount{Date: date})
+	}
+	return stats
+}
+
+// statsDay приводит день из результата запроса к YYYY-MM-DD: драйверы возвращают DATE
+// как time.Time или как строку, в SQLite — иногда с временем
+func statsDay(value any) string {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(statsDayFormat)
+	case []byte:
+		value = string(v)
+	}
+	day, _ := value.(string)
+	if len(day) > len(statsDayFormat) {
+		day = day[:len(statsDayFormat)]
+	}
+	return day
+}
@@ -3,14 +3,79 @@ package storage
 import (
 	"database/sql"
 	"lmsmodule/backend-svc/models"
+	"time"
 )
 
-// Storage определяет интерфейс для работы с данными
+// Storage определяет интерфейс для работы с данными. После изменения интерфейса нужно
+// перегенерировать декоратор метрик: go generate ./storage
 type Storage interface {
 	GetCourses() ([]models.Course, error)
+	// GetCourseFields выбирает из базы только запрошенные поля курсов (см. CourseFields)
+	GetCourseFields(fields []string) ([]models.Course, error)
 	GetCourseByID(id int) (models.Course, error)
 	GetUserProgress(userID int) (models.UserProgress, error)
+	// GetCoursesByIDs, GetUsersByIDs и GetProgressByUserIDs загружают несколько записей
+	// одним запросом вместо запроса на каждую (вложенные поля GraphQL, рассылки, сводки)
+	GetCoursesByIDs(ids []int) (map[int]models.Course, error)
+	GetUsersByIDs(ids []int) (map[int]models.User, error)
+	GetProgressByUserIDs(userIDs []int) (map[int]models.UserProgress, error)
 	CompleteTask(userID, taskID int) error
+	// CompleteTasks отмечает несколько заданий в одной транзакции и возвращает результат по каждому
+	CompleteTasks(userID int, taskIDs []int) ([]models.TaskCompletionResult, error)
+	CreateCourse(course models.Course) (int, error)
+	// RefreshCourseSummaries пересчитывает сводки всех курсов (course_summary) и возвращает их число
+	RefreshCourseSummaries() (int, error)
+	// ArchiveCourse и RestoreCourse убирают курс из каталога и возвращают обратно. Архивный
+	// курс закрыт для новых слушателей (ErrCourseArchived), а начавшие его сохраняют доступ,
+	// прогресс и сертификаты. ErrCourseNotFound — курса нет или он в другой организации представления.
+	ArchiveCourse(courseID int) error
+	RestoreCourse(courseID int) error
+	// PurgeCourse безвозвратно удаляет курс; ErrCourseNotArchived — курс не в архиве
+	PurgeCourse(courseID int) error
+	ListArchivedCourses() ([]models.Course, error)
+	// AddTask добавляет задание в курс; Order 0 ставит его в конец. UpdateTask меняет задание
+	// курса task.CourseID (ErrTaskNotFound, если задание из другого курса). Курсы архива не
+	// меняются (ErrCourseArchived). Оба метода, как и изменение вопросов опроса, добавляют
+	// запись в историю содержимого курса.
+	AddTask(task models.Task) (models.Task, error)
+	UpdateTask(task models.Task) (models.Task, error)
+	// GetCourseChangelog возвращает не больше limit последних записей истории курса и версию,
+	// которую видел пользователь
+	GetCourseChangelog(courseID, userID, limit int) (models.CourseChangelog, error)
+	// ListUnseenCourseChanges возвращает не больше limit изменений начатых пользователем курсов
+	// с его последнего посещения (без посещения — с начала курса), по курсам, новые первыми
+	ListUnseenCourseChanges(userID, limit int) ([]models.CourseChange, error)
+	// MarkCourseChangesSeen отмечает, что пользователь видел текущую версию курса
+	MarkCourseChangesSeen(userID, courseID int) error
+	// SaveBookmark создает закладку на курс или задание либо меняет ее notify (у закладок на
+	// задания всегда false). DeleteBookmark возвращает ErrBookmarkNotFound, если закладки нет.
+	SaveBookmark(bookmark models.Bookmark) (models.Bookmark, error)
+	DeleteBookmark(userID, courseID int, taskID *int) error
+	// ListBookmarks возвращает закладки пользователя, новые первыми, без названий
+	ListBookmarks(userID int) ([]models.Bookmark, error)
+	// ListBookmarkSubscribers возвращает пользователей, которые просили сообщить о возвращении
+	// курса в каталог; в представлении организации — только ее пользователей
+	ListBookmarkSubscribers(courseID int) ([]int, error)
+	// GetTaskNote и DeleteTaskNote возвращают ErrNoteNotFound, если заметки нет. SaveTaskNote
+	// создает или заменяет заметку; при expectedVersion не 0 — только если версия совпадает
+	// (иначе ErrNoteConflict). ListTaskNotes — все заметки пользователя, недавние первыми.
+	GetTaskNote(userID, taskID int) (models.TaskNote, error)
+	SaveTaskNote(userID, taskID int, body string, expectedVersion int) (models.TaskNote, error)
+	DeleteTaskNote(userID, taskID int) error
+	ListTaskNotes(userID int) ([]models.TaskNote, error)
+
+	// GetCourseTranslations возвращает переводы курсов на язык locale по ID курса
+	GetCourseTranslations(locale string) (map[int]models.CourseTranslation, error)
+	// GetTaskTranslations возвращает переводы заданий курса на язык locale по ID задания
+	GetTaskTranslations(courseID int, locale string) (map[int]models.TaskTranslation, error)
+	// ListCourseTranslations возвращает все переводы курса и его заданий
+	ListCourseTranslations(courseID int) (models.CourseTranslations, error)
+	// SetCourseTranslation и SetTaskTranslation создают или заменяют перевод; изменение
+	// переводов, как и самого курса, сдвигает courses.updated_at, чтобы сбросить кэши клиентов
+	SetCourseTranslation(translation models.CourseTranslation) error
+	DeleteCourseTranslation(courseID int, locale string) error
+	SetTaskTranslation(translation models.TaskTranslation) error
+	DeleteTaskTranslation(taskID int, locale string) error
 
 	CreateUser(user models.User) error
 	GetUserByUsername(username string) (models.User, error)
@@ -19,23 +84,548 @@ type Storage interface {
 	Enable2FA(userID int) error
 	IsAdmin(userID int) (bool, error)
 
-	GetAllUsers() ([]models.User, error)
+	// Методы чтения для отображения возвращают UserSummary без хеша пароля и TOTP-секрета;
+	// полный models.User доступен только через методы, нужные для аутентификации
+	GetUserPublicByID(id int) (models.UserSummary, error)
+	ListUserSummaries() ([]models.UserSummary, error)
+	// ListUserSummaryFields выбирает из базы только запрошенные поля пользователей (см. UserFields)
+	ListUserSummaryFields(fields []string) ([]models.UserSummary, error)
+	// EachUserSummary передает fn пользователей в порядке ID пачками по exportBatchSize;
+	// ошибка fn прерывает выгрузку и возвращается
+	EachUserSummary(fn func(models.UserSummary) error) error
+	// EachProgressRecord передает fn выполненные задания пользователей в порядке пользователя
+	// и задания пачками по exportBatchSize
+	EachProgressRecord(filter models.ProgressExportFilter, fn func(models.ProgressRecord) error) error
+	// UpdateUserProfile и UpdateUserStatus требуют прочитанную клиентом версию пользователя:
+	// без нее — ErrVersionRequired, при расхождении — ErrVersionConflict
 	UpdateUserProfile(userID int, data models.UpdateProfileRequest) error
-	GetUsersByRole(isAdmin bool) ([]models.User, error)
-	SearchUsers(query string) ([]models.User, error)
-	UpdateUserStatus(userID int, isActive bool) error
+	GetUsersByRole(isAdmin bool) ([]models.UserSummary, error)
+	SearchUsers(query string) ([]models.UserSummary, error)
+	UpdateUserStatus(userID int, isActive bool, version int) error
 	PromoteToAdmin(userID int) error
 	DemoteFromAdmin(userID int) error
+	DeleteUser(userID int) error
 
-	SaveOTPCode(userID int, code string) error
-	VerifyOTPCode(userID int, code string) (bool, error)
+	SaveOTPCode(userID int, code string, ttl time.Duration) error
+	// VerifyOTPCode учитывает каждую попытку; после maxAttempts неверных код удаляется (ErrOTPAttemptsExceeded)
+	VerifyOTPCode(userID int, code string, maxAttempts int) (bool, error)
 	ClearOTPCode(userID int) error
+	PurgeExpiredOTPCodes() (int64, error)
+
+	// GetOTPPreference возвращает канал доставки кодов; без сохраненной настройки — email
+	GetOTPPreference(userID int) (models.OTPPreference, error)
+	SetOTPPreference(pref models.OTPPreference) error
+	RecordOTPDelivery(delivery models.OTPDelivery) error
+	ListOTPDeliveries(userID, limit int) ([]models.OTPDelivery, error)
+
+	CreateTrustedDevice(device models.TrustedDevice) (int64, error)
+	// GetTrustedDevice возвращает действующее (не отозванное и не просроченное) устройство по хешу токена
+	GetTrustedDevice(tokenHash string) (models.TrustedDevice, error)
+	TouchTrustedDevice(id int64) error
+	ListTrustedDevices(userID int) ([]models.TrustedDevice, error)
+	RevokeTrustedDevice(userID int, id int64) error
+	RevokeTrustedDevices(userID int) (int64, error)
+	// PurgeTrustedDevices удаляет просроченные и отозванные устройства
+	PurgeTrustedDevices() (int64, error)
+	// PurgeExpiredTokens удаляет просроченные одноразовые токены и ссылки и возвращает число
+	// удаленных строк по таблицам
+	PurgeExpiredTokens(now time.Time) (map[string]int64, error)
+
+	// RecordLoginContext запоминает контекст входа. isNew — контекст раньше не встречался,
+	// а у пользователя уже были другие входы (о первом входе не предупреждаем)
+	RecordLoginContext(login models.LoginContext) (isNew bool, err error)
+	ListLoginContexts(userID int) ([]models.LoginContext, error)
+	CreateAccountLockToken(userID int, tokenHash string, expiresAt time.Time) error
+	// LockAccountByToken одноразово использует токен из письма: деактивирует пользователя
+	// и отзывает его доверенные устройства. Возвращает ErrLockTokenInvalid для чужих,
+	// использованных и просроченных токенов.
+	LockAccountByToken(tokenHash string) (userID int, err error)
+
+	// GetSessionState возвращает режим единственной сессии и текущую сессию пользователя
+	GetSessionState(userID int) (models.SessionState, error)
+	// StartSession запоминает сессию последнего входа; в режиме единственной сессии
+	// токены остальных сессий перестают приниматься
+	StartSession(userID int, sessionID string) error
+	// PurgeStaleSessions забывает сессии пользователей, не входивших с before: выданные при
+	// таких входах токены уже истекли
+	PurgeStaleSessions(before time.Time) (int64, error)
+	SetSingleSession(userID int, enabled bool) error
+	SetMustChangePassword(userID int, required bool) error
+
+	// ScheduleAccountDeletion деактивирует учетную запись, отзывает доверенные устройства
+	// и планирует окончательное удаление на purgeAt
+	ScheduleAccountDeletion(userID int, purgeAt time.Time) error
+	CancelAccountDeletion(userID int) error
+	// PurgeDeletedAccounts удаляет учетные записи, срок удаления которых наступил
+	PurgeDeletedAccounts(now time.Time) (int64, error)
+
+	// CreateEmailChange сохраняет запрос на смену email; предыдущие неподтвержденные запросы отменяются
+	CreateEmailChange(change models.EmailChange) (int64, error)
+	// ConfirmEmailChange применяет новый адрес; ErrEmailChangeInvalid — ссылка неизвестна,
+	// использована или просрочена, ErrDuplicateEmail — адрес занят
+	ConfirmEmailChange(tokenHash string) (models.EmailChange, error)
+	// CancelEmailChange отменяет запрос, а уже подтвержденную смену откатывает
+	CancelEmailChange(tokenHash string) (models.EmailChange, error)
+	ListEmailChanges(userID int) ([]models.EmailChange, error)
+
+	// ChangeUsername переименовывает пользователя и резервирует прежнее имя до change.ReservedUntil.
+	// minInterval ограничивает частоту смены (0 — без ограничения, для администраторов).
+	ChangeUsername(change models.UsernameChange, minInterval time.Duration) (models.UsernameChange, error)
+	ListUsernameHistory(userID int) ([]models.UsernameChange, error)
+	// ResolveUsername находит пользователя по текущему или прежнему имени
+	ResolveUsername(username string) (int, error)
+	GetProfilePrivacy(userID int) (models.ProfilePrivacy, error)
+	SetProfilePrivacy(userID int, privacy models.ProfilePrivacy) error
+	// GetTaskCompletions возвращает выполненные пользователем задания с курсами и временем выполнения
+	GetTaskCompletions(userID int) ([]models.TaskCompletion, error)
+	// SetAvatar сохраняет префикс ключей нового аватара (пусто — удалить) и возвращает прежний
+	SetAvatar(userID int, avatarKey string) (previousKey string, err error)
+
+	// CreateAnnouncement сохраняет объявление; ErrCourseNotFound — курса нет
+	CreateAnnouncement(announcement models.Announcement) (models.Announcement, error)
+	GetAnnouncement(id int) (models.Announcement, error)
+	// UpdateAnnouncement меняет курс, текст и сроки объявления. Если объявление перенесено
+	// на будущее и еще не было разослано, оно будет разослано в новое время.
+	UpdateAnnouncement(announcement models.Announcement) (models.Announcement, error)
+	DeleteAnnouncement(id int) error
+	// ListAnnouncements возвращает объявления, новые первыми
+	ListAnnouncements(filter models.AnnouncementFilter) ([]models.Announcement, error)
+	// ClaimDueAnnouncements отмечает разосланными опубликованные к now объявления и возвращает их.
+	// Каждое объявление возвращается только одному вызову, даже если экземпляров сервиса несколько.
+	ClaimDueAnnouncements(now time.Time) ([]models.Announcement, error)
+
+	// OpenConversation возвращает переписку участников по курсу, создавая ее при необходимости;
+	// ErrCourseNotFound — курса нет
+	OpenConversation(courseID, studentID, instructorID int) (models.Conversation, error)
+	GetConversation(id int) (models.Conversation, error)
+	// ListConversations возвращает переписки пользователя с числом непрочитанных им сообщений,
+	// сначала с самыми свежими сообщениями
+	ListConversations(userID int) ([]models.Conversation, error)
+	// AddConversationMessage сохраняет сообщение и сдвигает время последнего сообщения переписки
+	AddConversationMessage(message models.ConversationMessage) (models.ConversationMessage, error)
+	GetConversationMessage(id int) (models.ConversationMessage, error)
+	// ListConversationMessages возвращает до limit сообщений с ID меньше beforeID (0 — последние)
+	// в хронологическом порядке; limit 0 — все
+	ListConversationMessages(conversationID, beforeID, limit int) ([]models.ConversationMessage, error)
+	// MarkConversationRead отмечает прочитанными сообщения переписки до messageID включительно
+	MarkConversationRead(conversationID, userID, messageID int) error
+	ReportConversationMessage(messageID, reporterID int) error
+	// ModerateConversationMessage скрывает или возвращает сообщение и снимает с него жалобу
+	ModerateConversationMessage(messageID, moderatorID int, hidden bool) error
+	// ListReportedMessages возвращает сообщения с нерассмотренными жалобами, старые первыми
+	ListReportedMessages(limit int) ([]models.ConversationMessage, error)
+
+	// SetTaskSchedule сохраняет сроки задания; ErrTaskNotFound — задания нет
+	SetTaskSchedule(schedule models.TaskSchedule) (models.TaskSchedule, error)
+	// ListTaskSchedules возвращает расписания заданий курсов courseIDs (nil — всех курсов),
+	// у которых открытие или срок сдачи попадает в [from, to)
+	ListTaskSchedules(courseIDs []int, from, to time.Time) ([]models.TaskSchedule, error)
+	// CreateLiveSession сохраняет занятие; ErrCourseNotFound — курса нет
+	CreateLiveSession(session models.LiveSession) (models.LiveSession, error)
+	GetLiveSession(id int) (models.LiveSession, error)
+	UpdateLiveSession(session models.LiveSession) (models.LiveSession, error)
+	DeleteLiveSession(id int) error
+	// ListLiveSessions возвращает занятия курсов courseIDs (nil — всех курсов),
+	// пересекающиеся с [from, to), по времени начала
+	ListLiveSessions(courseIDs []int, from, to time.Time) ([]models.LiveSession, error)
+	// GetStartedCourseIDs возвращает курсы, в которых пользователь выполнил хотя бы одно задание
+	GetStartedCourseIDs(userID int) ([]int, error)
+	// SetCalendarFeedToken заменяет токен ссылки на календарь пользователя
+	SetCalendarFeedToken(userID int, tokenHash string) error
+	DeleteCalendarFeedToken(userID int) error
+	// GetCalendarFeedUser возвращает владельца токена; ErrCalendarFeedNotFound — токена нет
+	GetCalendarFeedUser(tokenHash string) (int, error)
+
+	// CreateTicket сохраняет обращение с первым сообщением; ErrCourseNotFound или ErrTaskNotFound —
+	// указанного курса или задания нет
+	CreateTicket(ticket models.Ticket, body string) (models.Ticket, error)
+	GetTicket(id int) (models.Ticket, error)
+	// ListTickets возвращает обращения, сначала недавно измененные
+	ListTickets(filter models.TicketFilter) ([]models.Ticket, error)
+	// AddTicketReply сохраняет сообщение и переводит обращение в status (пусто — не менять)
+	AddTicketReply(reply models.TicketReply, status string) (models.TicketReply, error)
+	// ListTicketReplies возвращает сообщения обращения по порядку; includeInternal — с заметками преподавателей
+	ListTicketReplies(ticketID int, includeInternal bool) ([]models.TicketReply, error)
+	SetTicketStatus(ticketID int, status string) (models.Ticket, error)
+	SetTicketAssignee(ticketID int, assigneeID *int) (models.Ticket, error)
+
+	// SaveCourseSurvey создает или обновляет опрос курса. Вопросы заменяются, только если
+	// survey.Questions не nil; ErrCourseNotFound — курса нет.
+	SaveCourseSurvey(survey models.Survey) (models.Survey, error)
+	// GetCourseSurvey возвращает опрос курса с вопросами и числом ответов; ErrSurveyNotFound — опроса нет
+	GetCourseSurvey(courseID int) (models.Survey, error)
+	DeleteCourseSurvey(courseID int) error
+	// SubmitSurveyResponse сохраняет ответы пользователя; в анонимном опросе ответы не связываются
+	// с пользователем. ErrSurveyAlreadyAnswered — пользователь уже прошел опрос.
+	SubmitSurveyResponse(survey models.Survey, userID int, answers []models.SurveyAnswer) error
+	HasAnsweredSurvey(surveyID, userID int) (bool, error)
+	// ListSurveyResponses возвращает все ответы на опрос по порядку
+	ListSurveyResponses(surveyID int) ([]models.SurveyResponse, error)
+
+	// CreateThread создает тему обсуждения курса с первым сообщением; ErrCourseNotFound — курса нет
+	CreateThread(thread models.DiscussionThread, body string) (models.DiscussionThread, error)
+	// ListThreads возвращает темы курса, сначала с недавними сообщениями
+	ListThreads(courseID int) ([]models.DiscussionThread, error)
+	GetThread(id int) (models.DiscussionThread, error)
+	// SetThreadLock закрывает или открывает тему; ErrThreadNotFound — темы нет
+	SetThreadLock(threadID, moderatorID int, locked bool) (models.DiscussionThread, error)
+	// ListThreadComments возвращает сообщения темы по порядку
+	ListThreadComments(threadID int) ([]models.DiscussionComment, error)
+	// AddComment сохраняет сообщение в теме; ErrThreadNotFound — темы нет
+	AddComment(comment models.DiscussionComment) (models.DiscussionComment, error)
+	GetComment(id int) (models.DiscussionComment, error)
+	// SaveReview создает или заменяет отзыв пользователя о курсе; ErrCourseNotFound — курса нет
+	SaveReview(review models.CourseReview) (models.CourseReview, error)
+	ListCourseReviews(courseID int) ([]models.CourseReview, error)
+	GetReview(id int) (models.CourseReview, error)
+	// SetContentHidden скрывает или возвращает сообщение (models.ContentComment) или отзыв
+	// (models.ContentReview); ErrCommentNotFound или ErrReviewNotFound — контента нет
+	SetContentHidden(contentType string, contentID, moderatorID int, hidden bool) error
+
+	// SetCourseBan скрыто блокирует пользователя в курсе или меняет причину блокировки
+	SetCourseBan(ban models.CourseBan) (models.CourseBan, error)
+	// DeleteCourseBan снимает блокировку; ErrCourseBanNotFound — блокировки нет
+	DeleteCourseBan(courseID, userID int) error
+	ListCourseBans(courseID int) ([]models.CourseBan, error)
+	// ReportContent сохраняет жалобу; повторная жалоба пользователя на тот же контент
+	// возвращает существующую
+	ReportContent(report models.ContentReport) (models.ContentReport, error)
+	// ListContentReports возвращает жалобы со статусом status (пусто — любые), старые первыми
+	ListContentReports(status string, limit int) ([]models.ContentReport, error)
+	// ResolveContentReport принимает решение по жалобе и по всем открытым жалобам на тот же
+	// контент; при models.ReportHidden контент скрывается. ErrReportNotFound — жалобы нет,
+	// ErrReportResolved — по ней уже принято решение.
+	ResolveContentReport(reportID, moderatorID int, status, note string) (models.ContentReport, error)
+	// CreateScormCourse создает курс с заданиями по элементам пакета и сохраняет пакет;
+	// course.Tasks и pkg.Items соответствуют друг другу по порядку
+	CreateScormCourse(course models.Course, pkg models.ScormPackage) (models.ScormPackage, error)
+	// GetScormPackage возвращает пакет курса; ErrScormPackageNotFound — курс создан не из пакета
+	GetScormPackage(courseID int) (models.ScormPackage, error)
+	// GetScormItem возвращает учебный объект задания; ErrScormItemNotFound — задание не из пакета
+	GetScormItem(taskID int) (models.ScormItem, error)
+	// GetScormAttempt возвращает данные CMI учащегося; без сохраненных данных — пустую попытку
+	GetScormAttempt(userID, taskID int) (models.ScormAttempt, error)
+	// SaveScormAttempt сохраняет данные CMI. Время завершения, однажды записанное, не сбрасывается.
+	SaveScormAttempt(attempt models.ScormAttempt) (models.ScormAttempt, error)
+	// CreateBadgeClass создает значок. ErrCourseNotFound — курса нет, ErrBadgeClassExists —
+	// у курса уже есть значок.
+	CreateBadgeClass(class models.BadgeClass) (models.BadgeClass, error)
+	// UpdateBadgeClass меняет название, описание и критерии значка; ErrBadgeClassNotFound — значка нет
+	UpdateBadgeClass(class models.BadgeClass) (models.BadgeClass, error)
+	// DeleteBadgeClass удаляет значок вместе с выданными удостоверениями
+	DeleteBadgeClass(id int) error
+	GetBadgeClass(id int) (models.BadgeClass, error)
+	// GetCourseBadgeClass возвращает значок за завершение курса; ErrBadgeClassNotFound — его нет
+	GetCourseBadgeClass(courseID int) (models.BadgeClass, error)
+	ListBadgeClasses() ([]models.BadgeClass, error)
+	// GetBadgeClassImage возвращает загруженное изображение значка; nil — изображение не загружено
+	GetBadgeClassImage(id int) ([]byte, error)
+	// SetBadgeClassImage сохраняет изображение значка (nil удаляет его) и обновляет UpdatedAt
+	SetBadgeClassImage(id int, image []byte) (models.BadgeClass, error)
+	// CreateBadgeAssertion сохраняет выданный значок. ErrBadgeAlreadyAwarded — у получателя есть
+	// действующий значок этого типа; отозванный значок заменяется новым.
+	CreateBadgeAssertion(assertion models.BadgeAssertion) (models.BadgeAssertion, error)
+	// GetBadgeAssertion возвращает выданный значок вместе со значком-классом;
+	// ErrBadgeAssertionNotFound — значка нет
+	GetBadgeAssertion(id string) (models.BadgeAssertion, error)
+	// ListUserBadges возвращает значки пользователя, включая отозванные, новые первыми
+	ListUserBadges(userID int) ([]models.BadgeAssertion, error)
+	// ListBadgeClassAssertions возвращает выданные значки одного типа с именами получателей
+	ListBadgeClassAssertions(classID int) ([]models.BadgeAssertion, error)
+	// RevokeBadgeAssertion отзывает значок; ErrBadgeAlreadyRevoked — он уже отозван
+	RevokeBadgeAssertion(id, reason string) (models.BadgeAssertion, error)
+	// CreateCertificate сохраняет сертификат о завершении курса; ErrCertificateExists — у
+	// пользователя уже есть сертификат этого курса, в том числе отозванный
+	CreateCertificate(cert models.Certificate) (models.Certificate, error)
+	// GetCertificateByCode возвращает сертификат по коду проверки; ErrCertificateNotFound — его нет
+	GetCertificateByCode(code string) (models.Certificate, error)
+	// ListUserCertificates возвращает сертификаты пользователя, новые первыми
+	ListUserCertificates(userID int) ([]models.Certificate, error)
+	// RevokeCertificate отзывает сертификат; ErrCertificateRevoked — он уже отозван
+	RevokeCertificate(code string, adminID int, reason string) (models.Certificate, error)
+
+	// SetTelegramLinkCode заменяет одноразовый код привязки Telegram пользователя
+	SetTelegramLinkCode(userID int, codeHash string, expiresAt time.Time) error
+	// LinkTelegramAccount привязывает чат к владельцу кода и удаляет код; ErrTelegramLinkCodeInvalid —
+	// кода нет или он истек. Чат, привязанный к другому пользователю, переходит к владельцу кода.
+	LinkTelegramAccount(codeHash string, account models.TelegramAccount) (models.TelegramAccount, error)
+	// GetTelegramAccount возвращает чат пользователя; ErrTelegramAccountNotFound — чат не привязан
+	GetTelegramAccount(userID int) (models.TelegramAccount, error)
+	// GetTelegramAccountByChat возвращает привязку чата; ErrTelegramAccountNotFound — чат не привязан
+	GetTelegramAccountByChat(chatID int64) (models.TelegramAccount, error)
+	// ListTelegramAccounts возвращает все привязанные чаты
+	ListTelegramAccounts() ([]models.TelegramAccount, error)
+	// DeleteTelegramAccount отвязывает чат пользователя; ErrTelegramAccountNotFound — он не привязан
+	DeleteTelegramAccount(userID int) error
+	// MarkTelegramDeadlineReminder отмечает напоминание о сроке dueAt задания; false — оно уже отправлялось
+	MarkTelegramDeadlineReminder(userID, taskID int, dueAt time.Time) (bool, error)
+
+	// CreateChatChannel сохраняет канал Slack или Mattermost группы; ErrGroupNotFound — группы нет
+	CreateChatChannel(channel models.ChatChannel) (models.ChatChannel, error)
+	// UpdateChatChannel изменяет канал; ErrChatChannelNotFound — его нет
+	UpdateChatChannel(channel models.ChatChannel) (models.ChatChannel, error)
+	// DeleteChatChannel удаляет канал; ErrChatChannelNotFound — его нет
+	DeleteChatChannel(id int) error
+	// GetChatChannel возвращает канал вместе с секретами; ErrChatChannelNotFound — его нет
+	GetChatChannel(id int) (models.ChatChannel, error)
+	// ListChatChannels возвращает все каналы вместе с секретами
+	ListChatChannels() ([]models.ChatChannel, error)
+	// SetChatLeaderboardSent запоминает время отправки еженедельной таблицы лидеров в канал
+	SetChatLeaderboardSent(id int, at time.Time) error
+	// MarkChatDeadlineReminder отмечает напоминание о сроке задания в канале; false — оно уже отправлялось
+	MarkChatDeadlineReminder(channelID, taskID int, dueAt time.Time) (bool, error)
+
+	// SavePushSubscription сохраняет подписку Web Push; подписка с тем же адресом переходит к userID
+	SavePushSubscription(sub models.PushSubscription) (models.PushSubscription, error)
+	// ListPushSubscriptions возвращает подписки пользователя вместе с ключами
+	ListPushSubscriptions(userID int) ([]models.PushSubscription, error)
+	// ListPushSubscriberIDs возвращает пользователей, у которых есть хотя бы одна подписка
+	ListPushSubscriberIDs() ([]int, error)
+	// DeletePushSubscription удаляет подписку пользователя; ErrPushSubscriptionNotFound — ее нет
+	DeletePushSubscription(userID, id int) error
+	// DeletePushSubscriptionByEndpoint удаляет подписку, которую push-сервис больше не принимает
+	DeletePushSubscriptionByEndpoint(endpoint string) error
+	// MarkPushDeadlineReminder отмечает push-напоминание о сроке dueAt задания; false — оно уже отправлялось
+	MarkPushDeadlineReminder(userID, taskID int, dueAt time.Time) (bool, error)
+
+	// GetDigestSettings возвращает подписку пользователя на еженедельную сводку; по умолчанию он подписан
+	GetDigestSettings(userID int) (models.DigestSettings, error)
+	// SetDigestEnabled включает или отключает еженедельную сводку
+	SetDigestEnabled(userID int, enabled bool) error
+	// UnsubscribeDigest отключает сводку владельцу токена из ссылки отписки и возвращает его ID;
+	// ErrDigestTokenInvalid — токен неизвестен или устарел
+	UnsubscribeDigest(tokenHash string) (int, error)
+	// ListDigestRecipients возвращает активных подписанных пользователей с email, зарегистрированных
+	// до slot и еще не получавших сводку начиная с slot
+	ListDigestRecipients(slot time.Time) ([]int, error)
+	// MarkDigestSent запоминает, что сводка собрана в момент at; tokenHash — хэш токена отписки
+	// из отправленного письма, пустой — письмо не отправлялось
+	MarkDigestSent(userID int, tokenHash string, at time.Time) error
+	// ListCoursesCreatedBetween возвращает курсы без заданий, созданные в интервале [from, to)
+	ListCoursesCreatedBetween(from, to time.Time) ([]models.Course, error)
+	// CountCompletionsByUser возвращает, сколько заданий выполнил в интервале [from, to) каждый
+	// пользователь, выполнивший хотя бы одно
+	CountCompletionsByUser(from, to time.Time) (map[int]int, error)
+
+	// RecordUserActivity отмечает, что пользователь был активен в день day (UTC)
+	RecordUserActivity(userID int, day time.Time) error
+	// GetPlatformStats считает показатели платформы за дни [from, to) (полночь UTC)
+	GetPlatformStats(from, to time.Time) (models.PlatformStats, error)
+	// SnapshotProgress сохраняет прогресс пользователей по курсам за день и возвращает число снимков
+	SnapshotProgress(day time.Time) (int, error)
+	// GetProgressHistory возвращает дневные снимки прогресса пользователя начиная с from
+	GetProgressHistory(userID, courseID int, from time.Time) ([]models.CourseProgressHistory, error)
+
+	FetchPendingEvents(limit int) ([]models.OutboxEvent, error)
+	MarkEventDispatched(eventID int64) error
+	MarkEventFailed(eventID int64, reason string, retryAt time.Time) error
+	PurgeDispatchedEvents(before time.Time) (int64, error)
+
+	GetSettings() ([]models.Setting, error)
+	UpsertSetting(setting models.Setting) error
+
+	GetUserGroups(userID int) ([]string, error)
+	// GroupLeaderboard возвращает участников группы, выполнивших больше всего заданий в интервале
+	// [from, to); ErrGroupNotFound — группы нет
+	GroupLeaderboard(group string, from, to time.Time, limit int) ([]models.LeaderboardEntry, error)
+	// IsGroupMember сообщает, состоит ли пользователь в группе; ErrGroupNotFound — группы нет
+	IsGroupMember(group string, userID int) (bool, error)
+
+	// CreateTeamChallenge сохраняет соревнование групп; ErrGroupNotFound — одной из групп нет
+	CreateTeamChallenge(challenge models.TeamChallenge) (models.TeamChallenge, error)
+	// UpdateTeamChallenge изменяет соревнование; ErrTeamChallengeNotFound — его нет
+	UpdateTeamChallenge(challenge models.TeamChallenge) (models.TeamChallenge, error)
+	// DeleteTeamChallenge удаляет соревнование; ErrTeamChallengeNotFound — его нет
+	DeleteTeamChallenge(id int) error
+	// GetTeamChallenge возвращает соревнование; ErrTeamChallengeNotFound — его нет
+	GetTeamChallenge(id int) (models.TeamChallenge, error)
+	// ListTeamChallenges возвращает соревнования, начиная с последних
+	ListTeamChallenges() ([]models.TeamChallenge, error)
+	// TeamChallengeCompletions возвращает для каждой группы соревнования число заданий, выполненных
+	// каждым ее участником в интервале соревнования, включая участников без заданий
+	TeamChallengeCompletions(challenge models.TeamChallenge) (map[string][]int, error)
+
+	// GetCourseRecertification возвращает требование повторного прохождения курса;
+	// ErrRecertificationNotFound — курс не требует повторного прохождения
+	GetCourseRecertification(courseID int) (models.CourseRecertification, error)
+	// SetCourseRecertification сохраняет требование и пересчитывает сроки текущих циклов; пользователи,
+	// уже завершившие курс, получают цикл от последнего выполненного задания. ErrCourseNotFound — курса нет
+	SetCourseRecertification(rec models.CourseRecertification) (models.CourseRecertification, error)
+	// DeleteCourseRecertification отменяет повторное прохождение курса и удаляет циклы;
+	// ErrRecertificationNotFound — требования нет
+	DeleteCourseRecertification(courseID int) error
+	// RecordCourseCompletion начинает новый цикл сертификации, если курс требует повторного прохождения;
+	// false — не требует
+	RecordCourseCompletion(userID, courseID int, at time.Time) (bool, error)
+	// ListRecertificationReminders возвращает циклы, по которым пора напомнить о скором истечении
+	ListRecertificationReminders(now time.Time) ([]models.CourseCompletion, error)
+	// MarkRecertificationReminded отмечает отправленное напоминание
+	MarkRecertificationReminded(userID, courseID int, at time.Time) error
+	// ListExpiredCourseCompletions возвращает циклы, срок которых истек, но прогресс еще не сброшен
+	ListExpiredCourseCompletions(now time.Time) ([]models.CourseCompletion, error)
+	// ExpireCourseCompletion переносит прогресс пользователя по курсу в архив попыток и отмечает
+	// цикл истекшим вместе с событием outbox
+	ExpireCourseCompletion(userID, courseID int, at time.Time) error
+	// ComplianceReport возвращает состояние сертификации активных пользователей по курсам
+	// с повторным прохождением
+	ComplianceReport(filter models.ComplianceFilter) ([]models.ComplianceEntry, error)
+	// EachComplianceEntry передает fn строки отчета ComplianceReport в том же порядке, читая их
+	// пачками по exportBatchSize
+	EachComplianceEntry(filter models.ComplianceFilter, fn func(models.ComplianceEntry) error) error
+
+	// SetUserManager назначает или снимает (nil) руководителя пользователя; ErrManagerCycle, если
+	// пользователь оказался бы руководителем самого себя
+	SetUserManager(userID int, managerID *int) error
+	// IsManagerOf сообщает, является ли managerID непосредственным руководителем userID
+	IsManagerOf(managerID, userID int) (bool, error)
+	// ListManagerReports возвращает непосредственных подчиненных руководителя
+	ListManagerReports(managerID int) ([]models.TeamMember, error)
+	// GetCourseProgressSummaries возвращает прогресс пользователя по начатым курсам
+	GetCourseProgressSummaries(userID int) ([]models.CourseProgressSummary, error)
+	// ListOutstandingDeadlines возвращает невыполненные задания начатых и назначенных курсов со сроком до until
+	ListOutstandingDeadlines(userID int, until time.Time) ([]models.TeamDeadline, error)
+
+	// ResetCourseProgress переносит прогресс пользователя по курсу в архив попыток и завершает
+	// текущий цикл сертификации; ErrNoCourseProgress, если сбрасывать нечего
+	ResetCourseProgress(userID, courseID int, resetBy *int, reason string) (models.CourseAttempt, error)
+	// ListCourseAttempts возвращает архивные попытки пользователя по курсу
+	ListCourseAttempts(userID, courseID int) ([]models.CourseAttempt, error)
+
+	// ListOrganizations возвращает все организации с числом пользователей и курсов
+	ListOrganizations() ([]models.Organization, error)
+	// GetOrganization возвращает организацию; ErrOrganizationNotFound, если ее нет
+	GetOrganization(id int) (models.Organization, error)
+	// CreateOrganization создает организацию; ErrOrganizationExists, если slug занят
+	CreateOrganization(org models.Organization) (models.Organization, error)
+	// UpdateOrganization меняет slug, название и активность организации
+	UpdateOrganization(org models.Organization) (models.Organization, error)
+	// SetUserOrganization переносит пользователя в организацию вместе с удалением связей
+	// с другими организациями
+	SetUserOrganization(userID, orgID int) error
+	// SetCourseOrganization переносит курс в организацию
+	SetCourseOrganization(courseID, orgID int) error
+	// ListOrganizationMembers возвращает пользователей организации
+	ListOrganizationMembers(orgID int) ([]models.OrganizationMember, error)
+	// IsOrganizationMember сообщает, состоит ли пользователь в организации
+	IsOrganizationMember(orgID, userID int) (bool, error)
+	// IsOrganizationAdmin сообщает, администрирует ли пользователь организацию
+	IsOrganizationAdmin(orgID, userID int) (bool, error)
+	// SetOrganizationAdmin выдает или отзывает права администратора организации; ErrUserNotFound,
+	// если пользователь не состоит в организации
+	SetOrganizationAdmin(orgID, userID int, isAdmin bool) error
+
+	// GetOrganizationBranding возвращает оформление организации; ErrOrganizationNotFound, если ее нет
+	GetOrganizationBranding(orgID int) (models.OrganizationBranding, error)
+	// GetUserBranding возвращает оформление организации пользователя
+	GetUserBranding(userID int) (models.OrganizationBranding, error)
+	// GetEmailBranding возвращает оформление организации владельца адреса; ErrUserNotFound, если
+	// адрес никому не принадлежит
+	GetEmailBranding(email string) (models.OrganizationBranding, error)
+	// SaveOrganizationBranding сохраняет цвета, подпись писем и шаблон сертификата
+	SaveOrganizationBranding(branding models.OrganizationBranding) (models.OrganizationBranding, error)
+	// GetOrganizationLogo возвращает логотип организации; nil — логотип не загружен
+	GetOrganizationLogo(orgID int) ([]byte, error)
+	// SetOrganizationLogo сохраняет логотип организации (nil удаляет его) и обновляет UpdatedAt
+	SetOrganizationLogo(orgID int, logo []byte) (models.OrganizationBranding, error)
+	// ListIPAllowlists возвращает списки разрешенных адресов всех организаций, у которых они заданы
+	ListIPAllowlists() ([]models.IPAllowlist, error)
+	// GetIPAllowlist возвращает список разрешенных адресов организации (пустой, если не задан);
+	// ErrOrganizationNotFound, если организации нет
+	GetIPAllowlist(orgID int) (models.IPAllowlist, error)
+	// SetIPAllowlist заменяет список разрешенных адресов организации; пустой список снимает ограничение
+	SetIPAllowlist(orgID int, cidrs []string, updatedBy int) (models.IPAllowlist, error)
+	// ApplyRetention удаляет или обезличивает данные вида policy.Data старше cutoff и возвращает
+	// число затронутых записей; при dryRun только считает их
+	ApplyRetention(policy models.RetentionPolicy, cutoff time.Time, dryRun bool) (int64, error)
+	// AcquireJobLease выдает аренду задачи name держателю holder на ttl, если аренды нет, она
+	// истекла или уже принадлежит holder; false — задачу выполняет другая реплика
+	AcquireJobLease(name, holder string, ttl time.Duration) (bool, error)
+
+	// CreateInvitation сохраняет приглашение; ErrGroupNotFound или ErrCourseNotFound — группы
+	// или курса нет в организации приглашения
+	CreateInvitation(invitation models.Invitation) (models.Invitation, error)
+	// ListInvitations возвращает приглашения организации (orgID = 0 — всех), новые первыми
+	ListInvitations(orgID int) ([]models.Invitation, error)
+	// GetInvitation возвращает приглашение по хешу токена; ErrInvitationNotFound — ссылка неизвестна,
+	// ErrInvitationExpired — она отозвана, истекла или исчерпана
+	GetInvitation(tokenHash string) (models.Invitation, error)
+	// RevokeInvitation отзывает приглашение организации orgID (0 — любой); ErrInvitationNotFound, если его нет
+	RevokeInvitation(id, orgID int) error
+	// RegisterWithInvitation создает пользователя в организации приглашения, добавляет его в группу
+	// и назначает курсы приглашения. Ошибки — как у GetInvitation и CreateUser.
+	RegisterWithInvitation(tokenHash string, user models.User) (userID int, err error)
+	// ListCourseAssignments возвращает курсы, назначенные пользователю
+	ListCourseAssignments(userID int) ([]models.CourseAssignment, error)
+
+	// CreatePendingUser создает неактивного пользователя, ожидающего одобрения регистрации;
+	// ошибки — как у CreateUser
+	CreatePendingUser(user models.User, requestedIP string) error
+	// ListPendingRegistrations возвращает регистрации, ожидающие одобрения, старые первыми
+	ListPendingRegistrations() ([]models.PendingRegistration, error)
+	// IsRegistrationPending сообщает, ожидает ли регистрация пользователя одобрения
+	IsRegistrationPending(userID int) (bool, error)
+	// ApproveRegistration активирует пользователя; ErrRegistrationNotFound — регистрация не ожидает решения
+	ApproveRegistration(userID int) (models.PendingRegistration, error)
+	// RejectRegistration удаляет пользователя, ожидающего одобрения; ErrRegistrationNotFound — как у ApproveRegistration
+	RejectRegistration(userID int) (models.PendingRegistration, error)
+
+	// ListAccessExpiries возвращает пользователей со сроком доступа, ближайшие сроки первыми
+	ListAccessExpiries() ([]models.AccessExpiry, error)
+	// SetAccessExpiry задает срок доступа пользователя (nil снимает ограничение); учетная запись,
+	// отключенная по сроку, снова активируется, если новый срок в будущем; ErrUserNotFound — нет пользователя
+	SetAccessExpiry(userID int, expiresAt *time.Time) error
+	// ExtendAccessExpiry продлевает срок доступа пользователей до until или на days дней;
+	// пользователи без срока доступа возвращаются во втором значении
+	ExtendAccessExpiry(userIDs []int, until *time.Time, days int) ([]models.AccessExpiry, []int, error)
+	// DeactivateExpiredAccounts деактивирует активных пользователей с наступившим сроком доступа
+	// и возвращает их
+	DeactivateExpiredAccounts(now time.Time) ([]models.AccessExpiry, error)
+	// BulkUpdateUsers меняет роль, группы и назначенные курсы пользователей из списка или фильтра
+	// в одной транзакции и возвращает отчет по каждому; ErrGroupNotFound или ErrCourseNotFound —
+	// в запросе есть несуществующая группа или курс, ErrTooManyUsers — фильтр выбрал больше MaxBulkUsers
+	BulkUpdateUsers(req models.BulkUserRequest, actorID int) ([]models.BulkUserResult, error)
+
+	// RecordAuditEntry добавляет запись в журнал действий администраторов и преподавателей
+	RecordAuditEntry(entry models.AuditEntry) error
+	// ListAuditEntries возвращает записи журнала действий по фильтру, новые первыми
+	ListAuditEntries(filter models.AuditFilter) ([]models.AuditEntry, error)
+	// AuditSnapshot возвращает отслеживаемые поля цели (AuditTargetUser, AuditTargetCourse) для
+	// сравнения до и после изменения; nil — цели нет или ее тип не отслеживается
+	AuditSnapshot(targetType string, targetID int) (map[string]interface{}, error)
+
+	GetFeatureFlags() ([]models.FeatureFlag, error)
+	UpsertFeatureFlag(flag models.FeatureFlag) error
+	DeleteFeatureFlag(key string) error
+
+	GetIdempotentResponse(scope, key string) (models.IdempotentResponse, error)
+	SaveIdempotentResponse(resp models.IdempotentResponse) error
+	PurgeIdempotencyKeys(before time.Time) (int64, error)
 }
 
 // DBStorage имплементирует Storage используя реальную базу данных
 type DBStorage struct {
 	DB *sql.DB
+	// Dialect определяет синтаксис диалектно-зависимых запросов; пустое значение — MySQL
+	Dialect Dialect
+	// QueryTimeout ограничивает время одного метода хранилища; 0 — таймаут по умолчанию (5s)
+	QueryTimeout time.Duration
+	// SlowQueryThreshold — порог, после которого метод логируется как медленный; 0 отключает лог
+	SlowQueryThreshold time.Duration
+	// Replicas — пулы реплик только для чтения; на них уходят методы, допускающие
+	// отставание (GetCourses, GetUserProgress, SearchUsers)
+	Replicas []*sql.DB
+	// PasswordHistory — сколько последних паролей, включая текущий, нельзя использовать
+	// повторно при смене пароля; 0 отключает проверку
+	PasswordHistory int
+	// OrganizationID ограничивает курсы, пользователей и группы одной организацией; 0 — без
+	// ограничения. Представление для организации создает InOrganization.
+	OrganizationID int
 }
 
 // MockStorage имплементирует Storage используя моковые данные в памяти
-type MockStorage struct{}
+type MockStorage struct {
+	// PasswordHistory — то же, что DBStorage.PasswordHistory
+	PasswordHistory int
+	// OrganizationID — то же, что DBStorage.OrganizationID
+	OrganizationID int
+}
@@ -0,0 +1,216 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrSurveyNotFound — у курса нет опроса
+	ErrSurveyNotFound = errors.New("survey not found")
+	// ErrSurveyAlreadyAnswered — пользователь уже прошел опрос
+	ErrSurveyAlreadyAnswered = errors.New("survey already answered")
+)
+
+// SaveCourseSurvey создает или обновляет опрос курса
+func (s *DBStorage) SaveCourseSurvey(survey models.Survey) (models.Survey, error) {
+	ctx, done := s.startQuery("SaveCourseSurvey")
+	defer done()
+
+	now := time.Now().UTC()
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if err := s.courseInOrganization(ctx, tx, survey.CourseID); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			"INSERT INTO course_surveys (course_id, title, anonymous, active, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)"+
+				s.onConflictUpdate([]string{"course_id"}, "title", "anonymous", "active", "updated_at"),
+			survey.CourseID, survey.Title, survey.Anonymous, survey.Active, now, now)
+		if err != nil {
+			return fmt.Errorf("save survey: %w", err)
+		}
+		if err := tx.QueryRowContext(ctx, "SELECT id FROM course_surveys WHERE course_id = ?", survey.CourseID).Scan(&survey.ID); err != nil {
+			return fmt.Errorf("get survey id: %w", err)
+		}
+		if survey.Questions == nil {
+			return nil
+		}
+
+		if _, err := tx.ExecContext(ctx, "DELETE FROM survey_questions WHERE survey_id = ?", survey.ID); err != nil {
+			return fmt.Errorf("delete survey questions: %w", err)
+		}
+		for i, question := range survey.Questions {
+			_, err := tx.ExecContext(ctx,
+				"INSERT INTO survey_questions (survey_id, position, kind, prompt, required) VALUES (?, ?, ?, ?, ?)",
+				survey.ID, i, question.Kind, question.Prompt, question.Required)
+			if err != nil {
+				return fmt.Errorf("insert survey question: %w", err)
+			}
+		}
+		return recordCourseChange(ctx, tx, models.CourseChange{
+			CourseID: survey.CourseID, Kind: models.CourseChangeSurveyUpdated, Title: survey.Title,
+		})
+	})
+	if err != nil {
+		return models.Survey{}, err
+	}
+	return s.GetCourseSurvey(survey.CourseID)
+}
+
+// GetCourseSurvey возвращает опрос курса с вопросами и числом ответов
+func (s *DBStorage) GetCourseSurvey(courseID int) (models.Survey, error) {
+	ctx, done := s.startQuery("GetCourseSurvey")
+	defer done()
+
+	survey := models.Survey{Questions: []models.SurveyQuestion{}}
+	orgAnd, orgArgs := s.orgFilter("AND", "c.organization_id")
+	err := s.DB.QueryRowContext(ctx,
+		"SELECT s.id, s.course_id, s.title, s.anonymous, s.active, s.created_at, s.updated_at, "+
+			"(SELECT COUNT(*) FROM survey_responses r WHERE r.survey_id = s.id) "+
+			"FROM course_surveys s JOIN courses c ON c.id = s.course_id WHERE s.course_id = ?"+orgAnd,
+		append([]any{courseID}, orgArgs...)...).
+		Scan(&survey.ID, &survey.CourseID, &survey.Title, &survey.Anonymous, &survey.Active,
+			&survey.CreatedAt, &survey.UpdatedAt, &survey.ResponseCount)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Survey{}, ErrSurveyNotFound
+	}
+	if err != nil {
+		return models.Survey{}, fmt.Errorf("query survey: %w", err)
+	}
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT id, kind, prompt, required FROM survey_questions WHERE survey_id = ? ORDER BY position", survey.ID)
+	if err != nil {
+		return models.Survey{}, fmt.Errorf("query survey questions: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var question models.SurveyQuestion
+		if err := rows.Scan(&question.ID, &question.Kind, &question.Prompt, &question.Required); err != nil {
+			return models.Survey{}, fmt.Errorf("scan survey question: %w", err)
+		}
+		survey.Questions = append(survey.Questions, question)
+	}
+	return survey, rows.Err()
+}
+
+// DeleteCourseSurvey удаляет опрос курса вместе с ответами
+func (s *DBStorage) DeleteCourseSurvey(courseID int) error {
+	ctx, done := s.startQuery("DeleteCourseSurvey")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM course_surveys WHERE course_id = ?", courseID)
+	if err != nil {
+		return fmt.Errorf("delete survey: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrSurveyNotFound
+	}
+	return nil
+}
+
+// SubmitSurveyResponse сохраняет ответы пользователя. Факт прохождения хранится в
+// survey_participants отдельно от ответов, поэтому анонимный ответ нельзя связать с автором.
+func (s *DBStorage) SubmitSurveyResponse(survey models.Survey, userID int, answers []models.SurveyAnswer) error {
+	ctx, done := s.startQuery("SubmitSurveyResponse")
+	defer done()
+
+	var respondent *int
+	if !survey.Anonymous {
+		respondent = &userID
+	}
+	return s.inTx(ctx, func(tx *sql.Tx) error {
+		var answered int
+		err := tx.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM survey_participants WHERE survey_id = ? AND user_id = ?", survey.ID, userID).Scan(&answered)
+		if err != nil {
+			return fmt.Errorf("check survey participant: %w", err)
+		}
+		if answered > 0 {
+			return ErrSurveyAlreadyAnswered
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO survey_participants (survey_id, user_id) VALUES (?, ?)", survey.ID, userID); err != nil {
+			return fmt.Errorf("insert survey participant: %w", err)
+		}
+
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO survey_responses (survey_id, user_id, created_at) VALUES (?, ?, ?)",
+			survey.ID, respondent, time.Now().UTC())
+		if err != nil {
+			return fmt.Errorf("insert survey response: %w", err)
+		}
+		responseID, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get survey response id: %w", err)
+		}
+		for _, answer := range answers {
+			var text interface{}
+			if answer.Rating == nil {
+				text = answer.Text
+			}
+			_, err := tx.ExecContext(ctx,
+				"INSERT INTO survey_answers (response_id, question_id, rating, answer) VALUES (?, ?, ?, ?)",
+				responseID, answer.QuestionID, answer.Rating, text)
+			if err != nil {
+				return fmt.Errorf("insert survey answer: %w", err)
+			}
+		}
+		return nil
+	})
+}
+
+// HasAnsweredSurvey сообщает, прошел ли пользователь опрос
+func (s *DBStorage) HasAnsweredSurvey(surveyID, userID int) (bool, error) {
+	ctx, done := s.startQuery("HasAnsweredSurvey")
+	defer done()
+
+	var answered int
+	err := s.DB.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM survey_participants WHERE survey_id = ? AND user_id = ?", surveyID, userID).Scan(&answered)
+	if err != nil {
+		return false, fmt.Errorf("check survey participant: %w", err)
+	}
+	return answered > 0, nil
+}
+
+// ListSurveyResponses возвращает все ответы на опрос по порядку
+func (s *DBStorage) ListSurveyResponses(surveyID int) ([]models.SurveyResponse, error) {
+	ctx, done := s.startQuery("ListSurveyResponses")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx,
+		"SELECT r.id, r.user_id, r.created_at, a.question_id, a.rating, a.answer "+
+			"FROM survey_responses r LEFT JOIN survey_answers a ON a.response_id = r.id "+
+			"WHERE r.survey_id = ? ORDER BY r.id, a.question_id", surveyID)
+	if err != nil {
+		return nil, fmt.Errorf("query survey responses: %w", err)
+	}
+	defer rows.Close()
+
+	responses := []models.SurveyResponse{}
+	for rows.Next() {
+		var response models.SurveyResponse
+		var userID, questionID, rating sql.NullInt64
+		var text sql.NullString
+		if err := rows.Scan(&response.ID, &userID, &response.CreatedAt, &questionID, &rating, &text); err != nil {
+			return nil, fmt.Errorf("scan survey response: %w", err)
+		}
+		if n := len(responses); n == 0 || responses[n-1].ID != response.ID {
+			response.UserID = intOrNil(userID)
+			response.Answers = []models.SurveyAnswer{}
+			responses = append(responses, response)
+		}
+		// Ответ без единого заполненного необязательного вопроса приходит без строки survey_answers
+		if questionID.Valid {
+			last := &responses[len(responses)-1]
+			last.Answers = append(last.Answers, models.SurveyAnswer{
+				QuestionID: int(questionID.Int64), Rating: intOrNil(rating), Text: text.String,
+			})
+		}
+	}
+	return responses, rows.Err()
+}
@@ -0,0 +1,122 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrNoteNotFound — у пользователя нет заметки к заданию
+	ErrNoteNotFound = errors.New("note not found")
+	// ErrNoteConflict — заметка изменилась (или удалена) после того, как клиент ее прочитал
+	ErrNoteConflict = errors.New("note was modified concurrently")
+)
+
+const taskNoteColumns = "n.task_id, t.course_id, t.title, n.body, n.version, n.created_at, n.updated_at"
+
+// GetTaskNote возвращает заметку пользователя к заданию
+func (s *DBStorage) GetTaskNote(userID, taskID int) (models.TaskNote, error) {
+	ctx, done := s.startQuery("GetTaskNote")
+	defer done()
+
+	note, err := scanTaskNote(s.DB.QueryRowContext(ctx,
+		"SELECT "+taskNoteColumns+" FROM task_notes n JOIN tasks t ON t.id = n.task_id "+
+			"WHERE n.user_id = ? AND n.task_id = ?", userID, taskID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return note, ErrNoteNotFound
+	}
+	return note, err
+}
+
+// SaveTaskNote создает или заменяет текст заметки. Если expectedVersion не 0, заметка
+// сохраняется только при совпадении версии, иначе — ErrNoteConflict.
+func (s *DBStorage) SaveTaskNote(userID, taskID int, body string, expectedVersion int) (models.TaskNote, error) {
+	ctx, done := s.startQuery("SaveTaskNote")
+	defer done()
+
+	now := time.Now().UTC()
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			"UPDATE task_notes SET body = ?, version = version + 1, updated_at = ? "+
+				"WHERE user_id = ? AND task_id = ? AND (? = 0 OR version = ?)",
+			body, now, userID, taskID, expectedVersion, expectedVersion)
+		if err != nil {
+			return fmt.Errorf("update note: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			return nil
+		}
+
+		var exists bool
+		err = tx.QueryRowContext(ctx,
+			"SELECT EXISTS(SELECT 1 FROM task_notes WHERE user_id = ? AND task_id = ?)", userID, taskID).Scan(&exists)
+		if err != nil {
+			return fmt.Errorf("check note: %w", err)
+		}
+		if exists || expectedVersion != 0 {
+			return ErrNoteConflict
+		}
+		if _, err := taskCourseID(ctx, tx, taskID); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO task_notes (user_id, task_id, body, version, created_at, updated_at) VALUES (?, ?, ?, 1, ?, ?)",
+			userID, taskID, body, now, now)
+		if err != nil {
+			return fmt.Errorf("insert note: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return models.TaskNote{}, err
+	}
+	return s.GetTaskNote(userID, taskID)
+}
+
+// DeleteTaskNote удаляет заметку пользователя к заданию
+func (s *DBStorage) DeleteTaskNote(userID, taskID int) error {
+	ctx, done := s.startQuery("DeleteTaskNote")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM task_notes WHERE user_id = ? AND task_id = ?", userID, taskID)
+	if err != nil {
+		return fmt.Errorf("delete note: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrNoteNotFound
+	}
+	return nil
+}
+
+// ListTaskNotes возвращает заметки пользователя, недавно измененные первыми
+func (s *DBStorage) ListTaskNotes(userID int) ([]models.TaskNote, error) {
+	ctx, done := s.startQuery("ListTaskNotes")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx,
+		"SELECT "+taskNoteColumns+" FROM task_notes n JOIN tasks t ON t.id = n.task_id "+
+			"WHERE n.user_id = ? ORDER BY n.updated_at DESC, n.task_id", userID)
+	if err != nil {
+		return nil, fmt.Errorf("query notes: %w", err)
+	}
+	defer rows.Close()
+
+	notes := []models.TaskNote{}
+	for rows.Next() {
+		note, err := scanTaskNote(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan note: %w", err)
+		}
+		notes = append(notes, note)
+	}
+	return notes, rows.Err()
+}
+
+func scanTaskNote(row rowScanner) (models.TaskNote, error) {
+	var note models.TaskNote
+	err := row.Scan(&note.TaskID, &note.CourseID, &note.TaskTitle, &note.Body, &note.Version, &note.CreatedAt, &note.UpdatedAt)
+	return note, err
+}
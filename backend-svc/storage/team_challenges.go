@@ -0,0 +1,234 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrTeamChallengeNotFound — соревнования нет
+var ErrTeamChallengeNotFound = errors.New("team challenge not found")
+
+const teamChallengeColumns = "id, title, description, scoring, best_of, starts_at, ends_at, created_by, created_at, updated_at"
+
+// rowsQueryer — *sql.DB или *sql.Tx для запросов, возвращающих несколько строк
+type rowsQueryer interface {
+	queryer
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}
+
+// CreateTeamChallenge сохраняет соревнование вместе с группами
+func (s *DBStorage) CreateTeamChallenge(challenge models.TeamChallenge) (models.TeamChallenge, error) {
+	ctx, done := s.startQuery("CreateTeamChallenge")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		now := time.Now().UTC().Truncate(time.Second)
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO team_challenges (title, description, scoring, best_of, starts_at, ends_at, created_by, created_at, updated_at) "+
+				"VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+			challenge.Title, challenge.Description, challenge.Scoring, challenge.BestOf,
+			challenge.StartsAt.UTC(), challenge.EndsAt.UTC(), challenge.CreatedBy, now, now)
+		if err != nil {
+			return fmt.Errorf("insert team challenge: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get team challenge id: %w", err)
+		}
+		if err := saveTeamChallengeGroups(ctx, tx, int(id), challenge.Groups); err != nil {
+			return err
+		}
+		challenge, err = getTeamChallenge(ctx, tx, int(id))
+		return err
+	})
+	return challenge, err
+}
+
+// UpdateTeamChallenge изменяет соревнование и заменяет его группы
+func (s *DBStorage) UpdateTeamChallenge(challenge models.TeamChallenge) (models.TeamChallenge, error) {
+	ctx, done := s.startQuery("UpdateTeamChallenge")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx,
+			"UPDATE team_challenges SET title = ?, description = ?, scoring = ?, best_of = ?, starts_at = ?, ends_at = ?, updated_at = ? "+
+				"WHERE id = ?",
+			challenge.Title, challenge.Description, challenge.Scoring, challenge.BestOf,
+			challenge.StartsAt.UTC(), challenge.EndsAt.UTC(), time.Now().UTC().Truncate(time.Second), challenge.ID)
+		if err != nil {
+			return fmt.Errorf("update team challenge: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrTeamChallengeNotFound
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM team_challenge_groups WHERE challenge_id = ?", challenge.ID); err != nil {
+			return fmt.Errorf("delete team challenge groups: %w", err)
+		}
+		if err := saveTeamChallengeGroups(ctx, tx, challenge.ID, challenge.Groups); err != nil {
+			return err
+		}
+		challenge, err = getTeamChallenge(ctx, tx, challenge.ID)
+		return err
+	})
+	return challenge, err
+}
+
+// DeleteTeamChallenge удаляет соревнование
+func (s *DBStorage) DeleteTeamChallenge(id int) error {
+	ctx, done := s.startQuery("DeleteTeamChallenge")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM team_challenges WHERE id = ?", id)
+	if err != nil {
+		return fmt.Errorf("delete team challenge: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTeamChallengeNotFound
+	}
+	return nil
+}
+
+// GetTeamChallenge возвращает соревнование
+func (s *DBStorage) GetTeamChallenge(id int) (models.TeamChallenge, error) {
+	ctx, done := s.startQuery("GetTeamChallenge")
+	defer done()
+
+	return getTeamChallenge(ctx, s.reader(), id)
+}
+
+// ListTeamChallenges возвращает соревнования по убыванию даты начала
+func (s *DBStorage) ListTeamChallenges() ([]models.TeamChallenge, error) {
+	ctx, done := s.startQuery("ListTeamChallenges")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, "SELECT "+teamChallengeColumns+" FROM team_challenges ORDER BY starts_at DESC, id DESC")
+	if err != nil {
+		return nil, fmt.Errorf("query team challenges: %w", err)
+	}
+	defer rows.Close()
+
+	challenges := []models.TeamChallenge{}
+	index := map[int]int{}
+	for rows.Next() {
+		challenge, err := scanTeamChallenge(rows)
+		if err != nil {
+			return nil, err
+		}
+		index[challenge.ID] = len(challenges)
+		challenges = append(challenges, challenge)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query team challenges: %w", err)
+	}
+
+	groups, err := s.reader().QueryContext(ctx, "SELECT challenge_id, group_name FROM team_challenge_groups ORDER BY group_name")
+	if err != nil {
+		return nil, fmt.Errorf("query team challenge groups: %w", err)
+	}
+	defer groups.Close()
+	for groups.Next() {
+		var id int
+		var group string
+		if err := groups.Scan(&id, &group); err != nil {
+			return nil, fmt.Errorf("scan team challenge group: %w", err)
+		}
+		if i, ok := index[id]; ok {
+			challenges[i].Groups = append(challenges[i].Groups, group)
+		}
+	}
+	return challenges, groups.Err()
+}
+
+// TeamChallengeCompletions считает задания участников групп соревнования, выполненные в его интервале
+func (s *DBStorage) TeamChallengeCompletions(challenge models.TeamChallenge) (map[string][]int, error) {
+	ctx, done := s.startQuery("TeamChallengeCompletions")
+	defer done()
+
+	rows, err := s.reader().QueryContext(ctx, `
+		SELECT cg.group_name, m.user_id, COUNT(p.task_id)
+		FROM team_challenge_groups cg
+		JOIN user_groups g ON g.name = cg.group_name
+		JOIN group_members m ON m.group_id = g.id
+		LEFT JOIN user_progress p ON p.user_id = m.user_id AND p.completed_at >= ? AND p.completed_at < ?
+		WHERE cg.challenge_id = ?
+		GROUP BY cg.group_name, m.user_id
+	`, challenge.StartsAt.UTC(), challenge.EndsAt.UTC(), challenge.ID)
+	if err != nil {
+		return nil, fmt.Errorf("query team challenge completions: %w", err)
+	}
+	defer rows.Close()
+
+	completions := map[string][]int{}
+	for _, group := range challenge.Groups {
+		completions[group] = []int{}
+	}
+	for rows.Next() {
+		var group string
+		var userID, completed int
+		if err := rows.Scan(&group, &userID, &completed); err != nil {
+			return nil, fmt.Errorf("scan team challenge completions: %w", err)
+		}
+		completions[group] = append(completions[group], completed)
+	}
+	return completions, rows.Err()
+}
+
+// saveTeamChallengeGroups добавляет группы соревнования; ErrGroupNotFound — одной из групп нет
+func saveTeamChallengeGroups(ctx context.Context, tx *sql.Tx, id int, groups []string) error {
+	for _, group := range groups {
+		if err := groupExists(ctx, tx, group, 0); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO team_challenge_groups (challenge_id, group_name) VALUES (?, ?)", id, group); err != nil {
+			return fmt.Errorf("insert team challenge group: %w", err)
+		}
+	}
+	return nil
+}
+
+func getTeamChallenge(ctx context.Context, q rowsQueryer, id int) (models.TeamChallenge, error) {
+	challenge, err := scanTeamChallenge(q.QueryRowContext(ctx, "SELECT "+teamChallengeColumns+" FROM team_challenges WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return challenge, ErrTeamChallengeNotFound
+	}
+	if err != nil {
+		return challenge, err
+	}
+
+	rows, err := q.QueryContext(ctx, "SELECT group_name FROM team_challenge_groups WHERE challenge_id = ? ORDER BY group_name", id)
+	if err != nil {
+		return challenge, fmt.Errorf("query team challenge groups: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var group string
+		if err := rows.Scan(&group); err != nil {
+			return challenge, fmt.Errorf("scan team challenge group: %w", err)
+		}
+		challenge.Groups = append(challenge.Groups, group)
+	}
+	return challenge, rows.Err()
+}
+
+func scanTeamChallenge(row rowScanner) (models.TeamChallenge, error) {
+	var challenge models.TeamChallenge
+	var createdBy sql.NullInt64
+	err := row.Scan(&challenge.ID, &challenge.Title, &challenge.Description, &challenge.Scoring, &challenge.BestOf,
+		&challenge.StartsAt, &challenge.EndsAt, &createdBy, &challenge.CreatedAt, &challenge.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return challenge, err
+	}
+	if err != nil {
+		return challenge, fmt.Errorf("scan team challenge: %w", err)
+	}
+	if createdBy.Valid {
+		challenge.CreatedBy = int(createdBy.Int64)
+	}
+	challenge.Groups = []string{}
+	return challenge, nil
+}
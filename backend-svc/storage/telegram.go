@@ -0,0 +1,169 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrTelegramAccountNotFound — чат Telegram не привязан
+	ErrTelegramAccountNotFound = errors.New("telegram account not linked")
+	// ErrTelegramLinkCodeInvalid — кода привязки нет или он истек
+	ErrTelegramLinkCodeInvalid = errors.New("telegram link code is invalid or expired")
+)
+
+const telegramAccountColumns = "user_id, chat_id, telegram_username, linked_at"
+
+// SetTelegramLinkCode заменяет одноразовый код привязки Telegram пользователя
+func (s *DBStorage) SetTelegramLinkCode(userID int, codeHash string, expiresAt time.Time) error {
+	ctx, done := s.startQuery("SetTelegramLinkCode")
+	defer done()
+
+	_, err := s.DB.ExecContext(ctx,
+		"INSERT INTO telegram_link_codes (user_id, code_hash, expires_at) VALUES (?, ?, ?)"+
+			s.onConflictUpdate([]string{"user_id"}, "code_hash", "expires_at"),
+		userID, codeHash, expiresAt.UTC())
+	if err != nil {
+		return fmt.Errorf("save telegram link code: %w", err)
+	}
+	return nil
+}
+
+// LinkTelegramAccount привязывает чат к владельцу кода
+func (s *DBStorage) LinkTelegramAccount(codeHash string, account models.TelegramAccount) (models.TelegramAccount, error) {
+	ctx, done := s.startQuery("LinkTelegramAccount")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx,
+			"SELECT user_id FROM telegram_link_codes WHERE code_hash = ? AND expires_at > ?",
+			codeHash, time.Now().UTC()).Scan(&account.UserID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrTelegramLinkCodeInvalid
+		}
+		if err != nil {
+			return fmt.Errorf("get telegram link code: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx, "DELETE FROM telegram_link_codes WHERE user_id = ?", account.UserID); err != nil {
+			return fmt.Errorf("delete telegram link code: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"DELETE FROM telegram_accounts WHERE user_id = ? OR chat_id = ?", account.UserID, account.ChatID); err != nil {
+			return fmt.Errorf("unlink telegram chat: %w", err)
+		}
+		account.LinkedAt = time.Now().UTC().Truncate(time.Second)
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO telegram_accounts ("+telegramAccountColumns+") VALUES (?, ?, ?, ?)",
+			account.UserID, account.ChatID, account.Username, account.LinkedAt); err != nil {
+			return fmt.Errorf("link telegram chat: %w", err)
+		}
+		return nil
+	})
+	return account, err
+}
+
+// GetTelegramAccount возвращает чат пользователя
+func (s *DBStorage) GetTelegramAccount(userID int) (models.TelegramAccount, error) {
+	ctx, done := s.startQuery("GetTelegramAccount")
+	defer done()
+
+	return getTelegramAccount(ctx, s.DB, "user_id = ?", userID)
+}
+
+// GetTelegramAccountByChat возвращает привязку чата
+func (s *DBStorage) GetTelegramAccountByChat(chatID int64) (models.TelegramAccount, error) {
+	ctx, done := s.startQuery("GetTelegramAccountByChat")
+	defer done()
+
+	return getTelegramAccount(ctx, s.DB, "chat_id = ?", chatID)
+}
+
+// ListTelegramAccounts возвращает все привязанные чаты
+func (s *DBStorage) ListTelegramAccounts() ([]models.TelegramAccount, error) {
+	ctx, done := s.startQuery("ListTelegramAccounts")
+	defer done()
+
+	rows, err := s.DB.QueryContext(ctx, "SELECT "+telegramAccountColumns+" FROM telegram_accounts ORDER BY user_id")
+	if err != nil {
+		return nil, fmt.Errorf("query telegram accounts: %w", err)
+	}
+	defer rows.Close()
+
+	accounts := []models.TelegramAccount{}
+	for rows.Next() {
+		account, err := scanTelegramAccount(rows)
+		if err != nil {
+			return nil, err
+		}
+		accounts = append(accounts, account)
+	}
+	return accounts, rows.Err()
+}
+
+// DeleteTelegramAccount отвязывает чат пользователя
+func (s *DBStorage) DeleteTelegramAccount(userID int) error {
+	ctx, done := s.startQuery("DeleteTelegramAccount")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx, "DELETE FROM telegram_accounts WHERE user_id = ?", userID)
+	if err != nil {
+		return fmt.Errorf("delete telegram account: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return ErrTelegramAccountNotFound
+	}
+	return nil
+}
+
+// MarkTelegramDeadlineReminder отмечает напоминание о сроке задания
+func (s *DBStorage) MarkTelegramDeadlineReminder(userID, taskID int, dueAt time.Time) (bool, error) {
+	ctx, done := s.startQuery("MarkTelegramDeadlineReminder")
+	defer done()
+
+	marked := false
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var exists int
+		err := tx.QueryRowContext(ctx,
+			"SELECT 1 FROM telegram_deadline_reminders WHERE user_id = ? AND task_id = ? AND due_at = ?",
+			userID, taskID, dueAt.UTC()).Scan(&exists)
+		if err == nil {
+			return nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("get telegram reminder: %w", err)
+		}
+		if _, err := tx.ExecContext(ctx,
+			"INSERT INTO telegram_deadline_reminders (user_id, task_id, due_at, sent_at) VALUES (?, ?, ?, ?)",
+			userID, taskID, dueAt.UTC(), time.Now().UTC()); err != nil {
+			return fmt.Errorf("save telegram reminder: %w", err)
+		}
+		marked = true
+		return nil
+	})
+	return marked, err
+}
+
+func getTelegramAccount(ctx context.Context, q queryer, condition string, arg interface{}) (models.TelegramAccount, error) {
+	account, err := scanTelegramAccount(q.QueryRowContext(ctx,
+		"SELECT "+telegramAccountColumns+" FROM telegram_accounts WHERE "+condition, arg))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TelegramAccount{}, ErrTelegramAccountNotFound
+	}
+	return account, err
+}
+
+func scanTelegramAccount(row rowScanner) (models.TelegramAccount, error) {
+	var account models.TelegramAccount
+	err := row.Scan(&account.UserID, &account.ChatID, &account.Username, &account.LinkedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return account, err
+	}
+	if err != nil {
+		return account, fmt.Errorf("scan telegram account: %w", err)
+	}
+	return account, nil
+}
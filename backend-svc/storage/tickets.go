@@ -0,0 +1,269 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"strings"
+	"time"
+)
+
+// ErrTicketNotFound — обращения нет
+var ErrTicketNotFound = errors.New("ticket not found")
+
+const ticketColumns = "id, user_id, subject, course_id, task_id, lab_session_id, status, assignee_id, created_at, updated_at, closed_at"
+
+// ticketClosedAt возвращает время закрытия для статуса: решенные и закрытые обращения
+// считаются закрытыми, остальные — открытыми
+func ticketClosedAt(status string, now time.Time) interface{} {
+	if status == models.TicketResolved || status == models.TicketClosed {
+		return now
+	}
+	return nil
+}
+
+// CreateTicket сохраняет обращение с первым сообщением. Курс обращения по заданию берется из задания.
+func (s *DBStorage) CreateTicket(ticket models.Ticket, body string) (models.Ticket, error) {
+	ctx, done := s.startQuery("CreateTicket")
+	defer done()
+
+	now := time.Now().UTC()
+	ticket.Status = models.TicketOpen
+	ticket.CreatedAt, ticket.UpdatedAt = now, now
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		if ticket.TaskID != nil {
+			courseID, err := taskCourseID(ctx, tx, *ticket.TaskID)
+			if err != nil {
+				return err
+			}
+			if ticket.CourseID != nil && *ticket.CourseID != courseID {
+				return ErrTaskNotFound
+			}
+			ticket.CourseID = &courseID
+		} else if ticket.CourseID != nil {
+			if err := courseExists(ctx, tx, *ticket.CourseID); err != nil {
+				return err
+			}
+		}
+
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO support_tickets (user_id, subject, course_id, task_id, lab_session_id, status, created_at, updated_at) "+
+				"VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			ticket.UserID, ticket.Subject, ticket.CourseID, ticket.TaskID, ticket.LabSessionID, ticket.Status, now, now)
+		if err != nil {
+			return fmt.Errorf("insert ticket: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get ticket id: %w", err)
+		}
+		ticket.ID = int(id)
+
+		_, err = tx.ExecContext(ctx,
+			"INSERT INTO support_ticket_replies (ticket_id, author_id, body, internal, created_at) VALUES (?, ?, ?, ?, ?)",
+			ticket.ID, ticket.UserID, body, false, now)
+		if err != nil {
+			return fmt.Errorf("insert ticket message: %w", err)
+		}
+		return nil
+	})
+	return ticket, err
+}
+
+// GetTicket возвращает обращение по ID
+func (s *DBStorage) GetTicket(id int) (models.Ticket, error) {
+	ctx, done := s.startQuery("GetTicket")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "SELECT "+ticketColumns+" FROM support_tickets WHERE id = ?")
+	if err != nil {
+		return models.Ticket{}, err
+	}
+	ticket, err := scanTicket(stmt.QueryRowContext(ctx, id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Ticket{}, ErrTicketNotFound
+	}
+	return ticket, err
+}
+
+// ListTickets возвращает обращения по фильтру
+func (s *DBStorage) ListTickets(filter models.TicketFilter) ([]models.Ticket, error) {
+	ctx, done := s.startQuery("ListTickets")
+	defer done()
+
+	var conditions []string
+	var args []interface{}
+	if filter.UserID != 0 {
+		conditions = append(conditions, "user_id = ?")
+		args = append(args, filter.UserID)
+	}
+	if filter.AssigneeID != 0 {
+		conditions = append(conditions, "assignee_id = ?")
+		args = append(args, filter.AssigneeID)
+	}
+	if filter.Status != "" {
+		conditions = append(conditions, "status = ?")
+		args = append(args, filter.Status)
+	}
+
+	query := "SELECT " + ticketColumns + " FROM support_tickets"
+	if len(conditions) > 0 {
+		query += " WHERE " + strings.Join(conditions, " AND ")
+	}
+	query += " ORDER BY updated_at DESC, id DESC"
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := s.reader().QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query tickets: %w", err)
+	}
+	defer rows.Close()
+
+	tickets := []models.Ticket{}
+	for rows.Next() {
+		ticket, err := scanTicket(rows)
+		if err != nil {
+			return nil, err
+		}
+		tickets = append(tickets, ticket)
+	}
+	return tickets, rows.Err()
+}
+
+// AddTicketReply сохраняет сообщение и при необходимости меняет статус обращения
+func (s *DBStorage) AddTicketReply(reply models.TicketReply, status string) (models.TicketReply, error) {
+	ctx, done := s.startQuery("AddTicketReply")
+	defer done()
+
+	now := time.Now().UTC()
+	reply.CreatedAt = now
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		var res sql.Result
+		var err error
+		if status == "" {
+			res, err = tx.ExecContext(ctx, "UPDATE support_tickets SET updated_at = ? WHERE id = ?", now, reply.TicketID)
+		} else {
+			res, err = tx.ExecContext(ctx,
+				"UPDATE support_tickets SET status = ?, closed_at = ?, updated_at = ? WHERE id = ?",
+				status, ticketClosedAt(status, now), now, reply.TicketID)
+		}
+		if err != nil {
+			return fmt.Errorf("touch ticket: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrTicketNotFound
+		}
+
+		res, err = tx.ExecContext(ctx,
+			"INSERT INTO support_ticket_replies (ticket_id, author_id, body, internal, created_at) VALUES (?, ?, ?, ?, ?)",
+			reply.TicketID, reply.AuthorID, reply.Body, reply.Internal, now)
+		if err != nil {
+			return fmt.Errorf("insert ticket message: %w", err)
+		}
+		id, err := res.LastInsertId()
+		if err != nil {
+			return fmt.Errorf("get ticket message id: %w", err)
+		}
+		reply.ID = int(id)
+		return nil
+	})
+	return reply, err
+}
+
+// ListTicketReplies возвращает сообщения обращения по порядку
+func (s *DBStorage) ListTicketReplies(ticketID int, includeInternal bool) ([]models.TicketReply, error) {
+	ctx, done := s.startQuery("ListTicketReplies")
+	defer done()
+
+	query := "SELECT id, ticket_id, author_id, body, internal, created_at FROM support_ticket_replies WHERE ticket_id = ?"
+	if !includeInternal {
+		query += " AND internal = ?"
+	}
+	query += " ORDER BY id"
+	args := []interface{}{ticketID}
+	if !includeInternal {
+		args = append(args, false)
+	}
+
+	rows, err := s.DB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query ticket messages: %w", err)
+	}
+	defer rows.Close()
+
+	replies := []models.TicketReply{}
+	for rows.Next() {
+		var reply models.TicketReply
+		var authorID sql.NullInt64
+		if err := rows.Scan(&reply.ID, &reply.TicketID, &authorID, &reply.Body, &reply.Internal, &reply.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan ticket message: %w", err)
+		}
+		reply.AuthorID = intOrNil(authorID)
+		replies = append(replies, reply)
+	}
+	return replies, rows.Err()
+}
+
+// SetTicketStatus меняет статус обращения
+func (s *DBStorage) SetTicketStatus(ticketID int, status string) (models.Ticket, error) {
+	ctx, done := s.startQuery("SetTicketStatus")
+	defer done()
+
+	now := time.Now().UTC()
+	return s.updateTicket(ctx, ticketID,
+		"UPDATE support_tickets SET status = ?, closed_at = ?, updated_at = ? WHERE id = ?",
+		status, ticketClosedAt(status, now), now, ticketID)
+}
+
+// SetTicketAssignee назначает обращение преподавателю
+func (s *DBStorage) SetTicketAssignee(ticketID int, assigneeID *int) (models.Ticket, error) {
+	ctx, done := s.startQuery("SetTicketAssignee")
+	defer done()
+
+	return s.updateTicket(ctx, ticketID,
+		"UPDATE support_tickets SET assignee_id = ?, updated_at = ? WHERE id = ?",
+		assigneeID, time.Now().UTC(), ticketID)
+}
+
+// updateTicket выполняет изменение обращения и возвращает его новое состояние
+func (s *DBStorage) updateTicket(ctx context.Context, ticketID int, query string, args ...interface{}) (models.Ticket, error) {
+	var ticket models.Ticket
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		res, err := tx.ExecContext(ctx, query, args...)
+		if err != nil {
+			return fmt.Errorf("update ticket: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n == 0 {
+			return ErrTicketNotFound
+		}
+		ticket, err = scanTicket(tx.QueryRowContext(ctx, "SELECT "+ticketColumns+" FROM support_tickets WHERE id = ?", ticketID))
+		return err
+	})
+	return ticket, err
+}
+
+func scanTicket(row rowScanner) (models.Ticket, error) {
+	var ticket models.Ticket
+	var courseID, taskID, assigneeID sql.NullInt64
+	var closedAt sql.NullTime
+	err := row.Scan(&ticket.ID, &ticket.UserID, &ticket.Subject, &courseID, &taskID, &ticket.LabSessionID, &ticket.Status,
+		&assigneeID, &ticket.CreatedAt, &ticket.UpdatedAt, &closedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ticket, err
+		}
+		return ticket, fmt.Errorf("scan ticket: %w", err)
+	}
+	ticket.CourseID = intOrNil(courseID)
+	ticket.TaskID = intOrNil(taskID)
+	ticket.AssigneeID = intOrNil(assigneeID)
+	if closedAt.Valid {
+		ticket.ClosedAt = &closedAt.Time
+	}
+	return ticket, nil
+}
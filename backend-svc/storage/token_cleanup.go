@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"fmt"
+	"time"
+)
+
+// expiredTokenTables — таблицы одноразовых токенов и ссылок с условием, при котором строка
+// больше не нужна. Смена email удаляется, когда истекла и ссылка отмены, приглашение —
+// по сроку действия, даже если оно отозвано или исчерпано: до этого его видно в списке.
+var expiredTokenTables = []struct{ table, condition string }{
+	{"account_lock_tokens", "expires_at < ?"},
+	{"email_changes", "cancel_expires_at < ?"},
+	{"invitations", "expires_at < ?"},
+	{"telegram_link_codes", "expires_at < ?"},
+}
+
+// PurgeExpiredTokens удаляет просроченные токены блокировки, смены email, приглашения
+// и коды привязки Telegram
+func (s *DBStorage) PurgeExpiredTokens(now time.Time) (map[string]int64, error) {
+	ctx, done := s.startQuery("PurgeExpiredTokens")
+	defer done()
+
+	purged := make(map[string]int64, len(expiredTokenTables))
+	for _, t := range expiredTokenTables {
+		res, err := s.DB.ExecContext(ctx, "DELETE FROM "+t.table+" WHERE "+t.condition, now.UTC())
+		if err != nil {
+			return purged, fmt.Errorf("purge %s: %w", t.table, err)
+		}
+		n, err := res.RowsAffected()
+		if err != nil {
+			return purged, err
+		}
+		purged[t.table] = n
+	}
+	return purged, nil
+}
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+// ErrTrustedDeviceNotFound возвращается, если устройство не найдено, отозвано или просрочено
+var ErrTrustedDeviceNotFound = errors.New("trusted device not found")
+
+const trustedDeviceColumns = "id, user_id, token_hash, user_agent, ip, created_at, last_used_at, expires_at"
+
+func scanTrustedDevice(row rowScanner) (models.TrustedDevice, error) {
+	var device models.TrustedDevice
+	err := row.Scan(&device.ID, &device.UserID, &device.TokenHash, &device.UserAgent, &device.IP,
+		&device.CreatedAt, &device.LastUsedAt, &device.ExpiresAt)
+	return device, err
+}
+
+// CreateTrustedDevice сохраняет доверенное устройство и возвращает его ID
+func (s *DBStorage) CreateTrustedDevice(device models.TrustedDevice) (int64, error) {
+	ctx, done := s.startQuery("CreateTrustedDevice")
+	defer done()
+
+	now := time.Now().UTC()
+	res, err := s.DB.ExecContext(ctx,
+		"INSERT INTO trusted_devices (user_id, token_hash, user_agent, ip, created_at, last_used_at, expires_at) "+
+			"VALUES (?, ?, ?, ?, ?, ?, ?)",
+		device.UserID, device.TokenHash, device.UserAgent, device.IP, now, now, device.ExpiresAt.UTC())
+	if err != nil {
+		return 0, fmt.Errorf("create trusted device: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+// GetTrustedDevice возвращает действующее устройство по хешу токена
+func (s *DBStorage) GetTrustedDevice(tokenHash string) (models.TrustedDevice, error) {
+	ctx, done := s.startQuery("GetTrustedDevice")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT "+trustedDeviceColumns+" FROM trusted_devices WHERE token_hash = ? AND revoked_at IS NULL AND expires_at > ?")
+	if err != nil {
+		return models.TrustedDevice{}, err
+	}
+
+	device, err := scanTrustedDevice(stmt.QueryRowContext(ctx, tokenHash, time.Now().UTC()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.TrustedDevice{}, ErrTrustedDeviceNotFound
+	}
+	if err != nil {
+		return models.TrustedDevice{}, fmt.Errorf("get trusted device: %w", err)
+	}
+	return device, nil
+}
+
+// TouchTrustedDevice обновляет время последнего использования устройства
+func (s *DBStorage) TouchTrustedDevice(id int64) error {
+	ctx, done := s.startQuery("TouchTrustedDevice")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB, "UPDATE trusted_devices SET last_used_at = ? WHERE id = ?")
+	if err != nil {
+		return err
+	}
+	_, err = stmt.ExecContext(ctx, time.Now().UTC(), id)
+	return err
+}
+
+// ListTrustedDevices возвращает действующие устройства пользователя, последние использованные первыми
+func (s *DBStorage) ListTrustedDevices(userID int) ([]models.TrustedDevice, error) {
+	ctx, done := s.startQuery("ListTrustedDevices")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT "+trustedDeviceColumns+" FROM trusted_devices "+
+			"WHERE user_id = ? AND revoked_at IS NULL AND expires_at > ? ORDER BY last_used_at DESC")
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := stmt.QueryContext(ctx, userID, time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("execute query: %w", err)
+	}
+	defer rows.Close()
+
+	devices := []models.TrustedDevice{}
+	for rows.Next() {
+		device, err := scanTrustedDevice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan row: %w", err)
+		}
+		devices = append(devices, device)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate rows: %w", err)
+	}
+	return devices, nil
+}
+
+// RevokeTrustedDevice отзывает одно устройство пользователя
+func (s *DBStorage) RevokeTrustedDevice(userID int, id int64) error {
+	ctx, done := s.startQuery("RevokeTrustedDevice")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"UPDATE trusted_devices SET revoked_at = ? WHERE id = ? AND user_id = ? AND revoked_at IS NULL")
+	if err != nil {
+		return err
+	}
+	res, err := stmt.ExecContext(ctx, time.Now().UTC(), id, userID)
+	if err != nil {
+		return fmt.Errorf("revoke trusted device: %w", err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrTrustedDeviceNotFound
+	}
+	return nil
+}
+
+// RevokeTrustedDevices отзывает все устройства пользователя и возвращает их число
+func (s *DBStorage) RevokeTrustedDevices(userID int) (int64, error) {
+	ctx, done := s.startQuery("RevokeTrustedDevices")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"UPDATE trusted_devices SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL")
+	if err != nil {
+		return 0, err
+	}
+	res, err := stmt.ExecContext(ctx, time.Now().UTC(), userID)
+	if err != nil {
+		return 0, fmt.Errorf("revoke trusted devices: %w", err)
+	}
+	return res.RowsAffected()
+}
+
+// PurgeTrustedDevices удаляет просроченные и отозванные устройства
+func (s *DBStorage) PurgeTrustedDevices() (int64, error) {
+	ctx, done := s.startQuery("PurgeTrustedDevices")
+	defer done()
+
+	res, err := s.DB.ExecContext(ctx,
+		"DELETE FROM trusted_devices WHERE revoked_at IS NOT NULL OR expires_at < ?", time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
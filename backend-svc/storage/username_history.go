@@ -0,0 +1,163 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"lmsmodule/backend-svc/models"
+	"time"
+)
+
+var (
+	// ErrUsernameReserved — имя недавно принадлежало другому пользователю и еще зарезервировано
+	ErrUsernameReserved = errors.New("username is reserved")
+	// ErrUsernameChangeTooSoon — пользователь уже менял имя в пределах минимального интервала
+	ErrUsernameChangeTooSoon = errors.New("username was changed too recently")
+)
+
+const usernameHistoryColumns = "id, user_id, old_username, new_username, changed_by, changed_at, reserved_until"
+
+func scanUsernameChange(row rowScanner) (models.UsernameChange, error) {
+	var change models.UsernameChange
+	var changedBy sql.NullInt64
+	err := row.Scan(&change.ID, &change.UserID, &change.OldUsername, &change.NewUsername, &changedBy,
+		&change.ChangedAt, &change.ReservedUntil)
+	if err != nil {
+		return models.UsernameChange{}, err
+	}
+	if changedBy.Valid {
+		id := int(changedBy.Int64)
+		change.ChangedBy = &id
+	}
+	return change, nil
+}
+
+// ChangeUsername переименовывает пользователя. Прежнее имя попадает в историю и остается
+// зарезервированным за ним до change.ReservedUntil; вернуть себе собственное старое имя можно.
+func (s *DBStorage) ChangeUsername(change models.UsernameChange, minInterval time.Duration) (models.UsernameChange, error) {
+	ctx, done := s.startQuery("ChangeUsername")
+	defer done()
+
+	err := s.inTx(ctx, func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(ctx, "SELECT username FROM users WHERE id = ?", change.UserID).Scan(&change.OldUsername)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if minInterval > 0 {
+			var lastChanged time.Time
+			err := tx.QueryRowContext(ctx,
+				"SELECT changed_at FROM username_history WHERE user_id = ? ORDER BY changed_at DESC LIMIT 1",
+				change.UserID).Scan(&lastChanged)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return err
+			}
+			if err == nil && now.Before(lastChanged.Add(minInterval)) {
+				return ErrUsernameChangeTooSoon
+			}
+		}
+
+		var reserved int
+		if err := tx.QueryRowContext(ctx,
+			"SELECT COUNT(*) FROM username_history WHERE old_username = ? AND reserved_until > ? AND user_id <> ?",
+			change.NewUsername, now, change.UserID).Scan(&reserved); err != nil {
+			return err
+		}
+		if reserved > 0 {
+			return ErrUsernameReserved
+		}
+
+		if err := bumpUserVersion(ctx, tx, change.UserID, AnyVersion); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, "UPDATE users SET username = ? WHERE id = ?", change.NewUsername, change.UserID); err != nil {
+			return translateUserConstraint(err)
+		}
+
+		change.ChangedAt = now
+		change.ReservedUntil = change.ReservedUntil.UTC()
+		res, err := tx.ExecContext(ctx,
+			"INSERT INTO username_history (user_id, old_username, new_username, changed_by, changed_at, reserved_until) "+
+				"VALUES (?, ?, ?, ?, ?, ?)",
+			change.UserID, change.OldUsername, change.NewUsername, change.ChangedBy, change.ChangedAt, change.ReservedUntil)
+		if err != nil {
+			return err
+		}
+		if change.ID, err = res.LastInsertId(); err != nil {
+			return err
+		}
+
+		return insertOutboxEvent(ctx, tx, models.EventUserRenamed, change.UserID, map[string]interface{}{
+			"userId":      change.UserID,
+			"oldUsername": change.OldUsername,
+			"newUsername": change.NewUsername,
+		})
+	})
+	if err != nil {
+		if errors.Is(err, ErrUserNotFound) || errors.Is(err, ErrDuplicateUsername) ||
+			errors.Is(err, ErrUsernameReserved) || errors.Is(err, ErrUsernameChangeTooSoon) {
+			return models.UsernameChange{}, err
+		}
+		return models.UsernameChange{}, fmt.Errorf("change username: %w", err)
+	}
+	return change, nil
+}
+
+// ListUsernameHistory возвращает переименования пользователя, начиная с последнего
+func (s *DBStorage) ListUsernameHistory(userID int) ([]models.UsernameChange, error) {
+	ctx, done := s.startQuery("ListUsernameHistory")
+	defer done()
+
+	stmt, err := s.prepared(ctx, s.DB,
+		"SELECT "+usernameHistoryColumns+" FROM username_history WHERE user_id = ? ORDER BY changed_at DESC, id DESC")
+	if err != nil {
+		return nil, err
+	}
+	rows, err := stmt.QueryContext(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	changes := []models.UsernameChange{}
+	for rows.Next() {
+		change, err := scanUsernameChange(rows)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+	return changes, rows.Err()
+}
+
+// ResolveUsername возвращает ID пользователя с таким именем, а если его нет — ID того,
+// кто последним носил это имя, чтобы старые ссылки вели на переименованную учетную запись
+func (s *DBStorage) ResolveUsername(username string) (int, error) {
+	ctx, done := s.startQuery("ResolveUsername")
+	defer done()
+
+	var userID int
+	stmt, err := s.prepared(ctx, s.DB, "SELECT id FROM users WHERE username = ?")
+	if err != nil {
+		return 0, err
+	}
+	err = stmt.QueryRowContext(ctx, username).Scan(&userID)
+	if !errors.Is(err, sql.ErrNoRows) {
+		return userID, err
+	}
+
+	stmt, err = s.prepared(ctx, s.DB,
+		"SELECT user_id FROM username_history WHERE old_username = ? ORDER BY changed_at DESC, id DESC LIMIT 1")
+	if err != nil {
+		return 0, err
+	}
+	err = stmt.QueryRowContext(ctx, username).Scan(&userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, ErrUserNotFound
+	}
+	return userID, err
+}
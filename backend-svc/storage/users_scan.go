@@ -0,0 +1,109 @@
+package storage
+
+import (
+	"database/sql"
+	"lmsmodule/backend-svc/models"
+)
+
+// userColumns — столбцы таблицы users в порядке, который ожидает scanUser
+const userColumns = "id, username, password_hash, email, full_name, totp_secret, " +
+	"is_2fa_enabled, is_admin, is_active, last_login, version, deletion_scheduled_at, avatar_key, locale, timezone"
+
+// rowScanner — общий интерфейс *sql.Row и *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanUser читает пользователя из строки выборки по userColumns. Email, полное имя
+// и TOTP-секрет в старых записях могут быть NULL и превращаются в пустые строки.
+func scanUser(row rowScanner) (models.User, error) {
+	var user models.User
+	var email, fullName, totpSecret sql.NullString
+	var lastLogin, deletionScheduledAt sql.NullTime
+
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&user.PasswordHash,
+		&email,
+		&fullName,
+		&totpSecret,
+		&user.Is2FAEnabled,
+		&user.IsAdmin,
+		&user.IsActive,
+		&lastLogin,
+		&user.Version,
+		&deletionScheduledAt,
+		&user.AvatarKey,
+		&user.Locale,
+		&user.Timezone,
+	)
+	if err != nil {
+		return models.User{}, err
+	}
+
+	user.Email = email.String
+	user.FullName = fullName.String
+	user.TOTPSecret = totpSecret.String
+	if lastLogin.Valid {
+		user.LastLogin = lastLogin.Time
+	}
+	if deletionScheduledAt.Valid {
+		user.DeletionScheduledAt = &deletionScheduledAt.Time
+	}
+	return user, nil
+}
+
+// userSummaryColumns — столбцы users без секретов в порядке, который ожидает scanUserSummary
+const userSummaryColumns = "id, username, email, full_name, is_2fa_enabled, is_admin, is_active, last_login, version, avatar_key, locale, timezone"
+
+// scanUserSummary читает проекцию пользователя по userSummaryColumns
+func scanUserSummary(row rowScanner) (models.UserSummary, error) {
+	var user models.UserSummary
+	var email, fullName sql.NullString
+	var lastLogin sql.NullTime
+
+	err := row.Scan(
+		&user.ID,
+		&user.Username,
+		&email,
+		&fullName,
+		&user.Is2FAEnabled,
+		&user.IsAdmin,
+		&user.IsActive,
+		&lastLogin,
+		&user.Version,
+		&user.AvatarKey,
+		&user.Locale,
+		&user.Timezone,
+	)
+	if err != nil {
+		return models.UserSummary{}, err
+	}
+
+	user.Email = email.String
+	user.FullName = fullName.String
+	if lastLogin.Valid {
+		user.LastLogin = lastLogin.Time
+	}
+	return user, nil
+}
+
+// scanUserSummaries читает все проекции пользователей из rows и закрывает их
+func scanUserSummaries(rows *sql.Rows) ([]models.UserSummary, error) {
+	defer rows.Close()
+
+	var users []models.UserSummary
+	for rows.Next() {
+		user, err := scanUserSummary(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
@@ -0,0 +1,127 @@
+// Package telegram — минимальный клиент Telegram Bot API: отправка сообщений, регистрация
+// webhook и меню команд.
+package telegram
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// ErrBlocked — пользователь заблокировал бота или удалил чат; сообщения в этот чат больше не доставить
+var ErrBlocked = errors.New("telegram: bot was blocked by the user")
+
+// Update — входящее событие webhook; сервис обрабатывает только сообщения
+type Update struct {
+	UpdateID int      `json:"update_id"`
+	Message  *Message `json:"message"`
+}
+
+type Message struct {
+	MessageID int    `json:"message_id"`
+	From      *User  `json:"from"`
+	Chat      Chat   `json:"chat"`
+	Text      string `json:"text"`
+}
+
+type User struct {
+	ID           int64  `json:"id"`
+	Username     string `json:"username"`
+	LanguageCode string `json:"language_code"`
+}
+
+type Chat struct {
+	ID   int64  `json:"id"`
+	Type string `json:"type"` // private, group, supergroup, channel
+}
+
+// Command — команда в меню бота
+type Command struct {
+	Command     string `json:"command"`
+	Description string `json:"description"`
+}
+
+// Client вызывает методы Bot API от имени бота
+type Client struct {
+	Token  string
+	URL    string
+	Client *http.Client
+}
+
+// New создает клиента бота с токеном от @BotFather для Bot API по адресу apiURL
+func New(apiURL, token string) *Client {
+	return &Client{Token: token, URL: strings.TrimSuffix(apiURL, "/"), Client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+// SendMessage отправляет текстовое сообщение в чат. Текст передается без разметки.
+func (c *Client) SendMessage(ctx context.Context, chatID int64, text string) error {
+	return c.call(ctx, "sendMessage", map[string]interface{}{
+		"chat_id":                  chatID,
+		"text":                     text,
+		"disable_web_page_preview": true,
+	})
+}
+
+// SetWebhook направляет обновления бота на webhookURL; Telegram передает secret в заголовке
+// X-Telegram-Bot-Api-Secret-Token каждого запроса
+func (c *Client) SetWebhook(ctx context.Context, webhookURL, secret string) error {
+	return c.call(ctx, "setWebhook", map[string]interface{}{
+		"url":             webhookURL,
+		"secret_token":    secret,
+		"allowed_updates": []string{"message"},
+	})
+}
+
+// SetCommands задает меню команд бота для пользователей с языком languageCode; пустой — для остальных
+func (c *Client) SetCommands(ctx context.Context, commands []Command, languageCode string) error {
+	params := map[string]interface{}{"commands": commands}
+	if languageCode != "" {
+		params["language_code"] = languageCode
+	}
+	return c.call(ctx, "setMyCommands", params)
+}
+
+func (c *Client) call(ctx context.Context, method string, params interface{}) error {
+	body, err := json.Marshal(params)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL+"/bot"+c.Token+"/"+method, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("telegram: %s: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		// В тексте ошибки net/http есть адрес запроса, а в нем токен бота
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("telegram: %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		ErrorCode   int    `json:"error_code"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram: %s: unexpected status %d", method, resp.StatusCode)
+	}
+	if !result.OK {
+		if result.ErrorCode == http.StatusForbidden {
+			return ErrBlocked
+		}
+		return fmt.Errorf("telegram: %s: %d %s", method, result.ErrorCode, result.Description)
+	}
+	return nil
+}
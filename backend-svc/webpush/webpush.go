@@ -0,0 +1,259 @@
+// Package webpush отправляет уведомления Web Push: шифрует содержимое для подписки браузера
+// (RFC 8291, aes128gcm) и подписывает запрос к push-сервису ключом VAPID (RFC 8292).
+package webpush
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// MaxPayload — наибольший размер открытого содержимого: одна запись aes128gcm длиной recordSize
+// вмещает его вместе с разделителем и тегом GCM
+const MaxPayload = recordSize - 17
+
+const recordSize = 4096
+
+var (
+	// ErrGone — push-сервис больше не принимает сообщения для подписки (404 или 410);
+	// подписку нужно удалить
+	ErrGone = errors.New("webpush: subscription is no longer valid")
+	// ErrPayloadTooLarge — содержимое не помещается в одну запись
+	ErrPayloadTooLarge = errors.New("webpush: payload is too large")
+)
+
+// Subscription — подписка браузера из PushSubscription.toJSON(); ключи в base64url
+type Subscription struct {
+	Endpoint string
+	P256dh   string
+	Auth     string
+}
+
+// Validate проверяет адрес и ключи подписки. Адрес push-сервиса должен быть https.
+func (s Subscription) Validate() error {
+	endpoint, err := url.Parse(s.Endpoint)
+	if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+		return errors.New("webpush: endpoint must be an https URL")
+	}
+	public, err := decodeBase64(s.P256dh)
+	if err == nil {
+		_, err = ecdh.P256().NewPublicKey(public)
+	}
+	if err != nil {
+		return errors.New("webpush: p256dh must be an uncompressed P-256 public key")
+	}
+	if auth, err := decodeBase64(s.Auth); err != nil || len(auth) != 16 {
+		return errors.New("webpush: auth secret must be 16 bytes")
+	}
+	return nil
+}
+
+// Client отправляет уведомления от имени сервера приложения с ключом VAPID
+type Client struct {
+	key       *ecdsa.PrivateKey
+	publicKey string
+	subject   string
+	Client    *http.Client
+}
+
+// New создает клиента. privateKey — закрытый ключ P-256 в base64url (32 байта), subject —
+// контакт администратора сервера приложения: mailto: или https: адрес.
+func New(privateKey, subject string) (*Client, error) {
+	raw, err := decodeBase64(privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: decode private key: %w", err)
+	}
+	ecdhKey, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: invalid private key: %w", err)
+	}
+	public := ecdhKey.PublicKey().Bytes()
+	key := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+	return &Client{
+		key:       key,
+		publicKey: base64.RawURLEncoding.EncodeToString(public),
+		subject:   subject,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+	}, nil
+}
+
+// GenerateKey создает пару ключей VAPID в base64url: закрытый для конфигурации сервера
+// и открытый для applicationServerKey в браузере
+func GenerateKey() (privateKey, publicKey string, err error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(key.Bytes()), base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()), nil
+}
+
+// PublicKey возвращает открытый ключ VAPID для PushManager.subscribe({applicationServerKey})
+func (c *Client) PublicKey() string { return c.publicKey }
+
+// Send шифрует payload для подписки и передает его push-сервису. ttl — сколько сервис хранит
+// сообщение, пока браузер недоступен.
+func (c *Client) Send(ctx context.Context, sub Subscription, payload []byte, ttl time.Duration) error {
+	endpoint, err := url.Parse(sub.Endpoint)
+	if err != nil || endpoint.Host == "" {
+		return errors.New("webpush: invalid endpoint")
+	}
+	body, err := encrypt(sub, payload)
+	if err != nil {
+		return err
+	}
+	authorization, err := c.vapid(endpoint.Scheme + "://" + endpoint.Host)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webpush: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(ttl/time.Second)))
+	req.Header.Set("Urgency", "normal")
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		// Адрес подписки содержит идентификатор браузера; в журнал он не попадает
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("webpush: %w", err)
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrGone
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 256))
+		return fmt.Errorf("webpush: unexpected status %d: %s", resp.StatusCode, strings.TrimSpace(string(detail)))
+	}
+	return nil
+}
+
+// vapid подписывает заголовок Authorization для push-сервиса audience
+func (c *Client) vapid(audience string) (string, error) {
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": audience,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": c.subject,
+	}).SignedString(c.key)
+	if err != nil {
+		return "", fmt.Errorf("webpush: sign VAPID token: %w", err)
+	}
+	return "vapid t=" + token + ", k=" + c.publicKey, nil
+}
+
+// encrypt шифрует содержимое по RFC 8291 одной записью aes128gcm
+func encrypt(sub Subscription, payload []byte) ([]byte, error) {
+	if len(payload) > MaxPayload {
+		return nil, ErrPayloadTooLarge
+	}
+	uaPublic, err := decodeBase64(sub.P256dh)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: decode p256dh: %w", err)
+	}
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: invalid p256dh: %w", err)
+	}
+	authSecret, err := decodeBase64(sub.Auth)
+	if err != nil || len(authSecret) != 16 {
+		return nil, errors.New("webpush: auth secret must be 16 bytes")
+	}
+
+	// Одноразовый ключ сервера приложения для этого сообщения
+	asKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	asPublic := asKey.PublicKey().Bytes()
+	shared, err := asKey.ECDH(uaKey)
+	if err != nil {
+		return nil, fmt.Errorf("webpush: %w", err)
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	prkKey, err := hkdf.Extract(sha256.New, shared, authSecret)
+	if err != nil {
+		return nil, err
+	}
+	ikm, err := hkdf.Expand(sha256.New, prkKey, "WebPush: info\x00"+string(uaPublic)+string(asPublic), 32)
+	if err != nil {
+		return nil, err
+	}
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// Заголовок: salt, размер записи, длина и значение открытого ключа сервера
+	header := make([]byte, 0, 16+4+1+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	// 0x02 отмечает последнюю (единственную) запись
+	plaintext := append(append(make([]byte, 0, len(payload)+1), payload...), 0x02)
+	return gcm.Seal(header, nonce, plaintext, nil), nil
+}
+
+// decodeBase64 принимает base64url без выравнивания, как его отдают браузеры, и обычный base64
+func decodeBase64(s string) ([]byte, error) {
+	s = strings.TrimRight(strings.TrimSpace(s), "=")
+	s = strings.NewReplacer("+", "-", "/", "_").Replace(s)
+	return base64.RawURLEncoding.DecodeString(s)
+}
@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS feature_flags (
+    flag_key VARCHAR(100) PRIMARY KEY,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT FALSE,
+    rollout_percentage INT NOT NULL DEFAULT 0,
+    target_groups TEXT,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    scope VARCHAR(191) NOT NULL,
+    idem_key VARCHAR(191) NOT NULL,
+    request_hash CHAR(64) NOT NULL,
+    status_code INT NOT NULL,
+    response_body MEDIUMBLOB NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (scope, idem_key),
+    INDEX idx_idempotency_keys_created_at (created_at)
+);
@@ -0,0 +1 @@
+ALTER TABLE courses ADD COLUMN updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP;
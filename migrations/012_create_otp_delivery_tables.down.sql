@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS otp_deliveries;
+DROP TABLE IF EXISTS user_otp_preferences;
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS user_otp_preferences (
+    user_id INT PRIMARY KEY,
+    channel VARCHAR(16) NOT NULL DEFAULT 'email',
+    phone VARCHAR(32),
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS otp_deliveries (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    channel VARCHAR(16) NOT NULL,
+    status VARCHAR(16) NOT NULL,
+    error VARCHAR(255),
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_otp_deliveries_user (user_id, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
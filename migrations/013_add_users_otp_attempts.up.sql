@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN otp_attempts INT NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+-- Коды длиннее 6 символов не помещаются в узкий столбец; такой код просто придется запросить заново
+UPDATE users SET otp_code = NULL, otp_expires_at = NULL WHERE CHAR_LENGTH(otp_code) > 6;
+ALTER TABLE users MODIFY COLUMN otp_code VARCHAR(6);
@@ -0,0 +1 @@
+ALTER TABLE users MODIFY COLUMN otp_code VARCHAR(32);
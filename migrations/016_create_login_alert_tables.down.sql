@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS account_lock_tokens;
+DROP TABLE IF EXISTS login_contexts;
@@ -0,0 +1,3 @@
+ALTER TABLE users
+    DROP COLUMN session_id,
+    DROP COLUMN single_session;
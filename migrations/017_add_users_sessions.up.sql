@@ -0,0 +1,3 @@
+ALTER TABLE users
+    ADD COLUMN single_session BOOLEAN NOT NULL DEFAULT FALSE,
+    ADD COLUMN session_id CHAR(32) NULL;
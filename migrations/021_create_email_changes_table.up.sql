@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS email_changes (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    old_email VARCHAR(255) NOT NULL DEFAULT '',
+    new_email VARCHAR(255) NOT NULL,
+    confirm_token_hash CHAR(64) NOT NULL,
+    cancel_token_hash CHAR(64) NOT NULL,
+    requested_ip VARCHAR(64) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    expires_at DATETIME NOT NULL,
+    cancel_expires_at DATETIME NOT NULL,
+    confirmed_at DATETIME,
+    cancelled_at DATETIME,
+    UNIQUE KEY uq_email_changes_confirm (confirm_token_hash),
+    UNIQUE KEY uq_email_changes_cancel (cancel_token_hash),
+    INDEX idx_email_changes_user (user_id, created_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS username_history (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    old_username VARCHAR(64) NOT NULL,
+    new_username VARCHAR(64) NOT NULL,
+    changed_by INT,
+    changed_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    reserved_until DATETIME NOT NULL,
+    INDEX idx_username_history_old (old_username, changed_at),
+    INDEX idx_username_history_user (user_id, changed_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS task_translations;
+DROP TABLE IF EXISTS course_translations;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS course_translations (
+    course_id INT NOT NULL,
+    locale VARCHAR(16) NOT NULL,
+    vulnerability_type VARCHAR(100) NOT NULL DEFAULT '',
+    description TEXT NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (course_id, locale),
+    INDEX idx_course_translations_locale (locale),
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS task_translations (
+    task_id INT NOT NULL,
+    locale VARCHAR(16) NOT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    description TEXT NOT NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (task_id, locale),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
@@ -0,0 +1,16 @@
+CREATE TABLE IF NOT EXISTS announcements (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    course_id INT NULL,
+    title VARCHAR(200) NOT NULL,
+    body TEXT NOT NULL,
+    author_id INT NULL,
+    publish_at DATETIME NOT NULL,
+    expires_at DATETIME NULL,
+    notified_at DATETIME NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_announcements_publish (publish_at),
+    INDEX idx_announcements_pending (notified_at, publish_at),
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE SET NULL
+);
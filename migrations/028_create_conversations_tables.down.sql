@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS conversation_reads;
+DROP TABLE IF EXISTS conversation_messages;
+DROP TABLE IF EXISTS conversations;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS calendar_feed_tokens;
+DROP TABLE IF EXISTS live_sessions;
+DROP TABLE IF EXISTS task_schedules;
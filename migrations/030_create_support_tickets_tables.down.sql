@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS support_ticket_replies;
+DROP TABLE IF EXISTS support_tickets;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS survey_participants;
+DROP TABLE IF EXISTS survey_answers;
+DROP TABLE IF EXISTS survey_responses;
+DROP TABLE IF EXISTS survey_questions;
+DROP TABLE IF EXISTS course_surveys;
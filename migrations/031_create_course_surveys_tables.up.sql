@@ -0,0 +1,51 @@
+CREATE TABLE IF NOT EXISTS course_surveys (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    course_id INT NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    anonymous BOOLEAN NOT NULL DEFAULT FALSE,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_course_surveys_course (course_id),
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS survey_questions (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    survey_id INT NOT NULL,
+    position INT NOT NULL,
+    kind VARCHAR(20) NOT NULL,
+    prompt VARCHAR(500) NOT NULL,
+    required BOOLEAN NOT NULL DEFAULT FALSE,
+    INDEX idx_survey_questions_survey (survey_id, position),
+    FOREIGN KEY (survey_id) REFERENCES course_surveys(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS survey_responses (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    survey_id INT NOT NULL,
+    user_id INT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_survey_responses_survey (survey_id),
+    FOREIGN KEY (survey_id) REFERENCES course_surveys(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS survey_answers (
+    response_id INT NOT NULL,
+    question_id INT NOT NULL,
+    rating INT NULL,
+    answer TEXT NULL,
+    PRIMARY KEY (response_id, question_id),
+    INDEX idx_survey_answers_question (question_id),
+    FOREIGN KEY (response_id) REFERENCES survey_responses(id) ON DELETE CASCADE,
+    FOREIGN KEY (question_id) REFERENCES survey_questions(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS survey_participants (
+    survey_id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (survey_id, user_id),
+    FOREIGN KEY (survey_id) REFERENCES course_surveys(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS content_reports;
+DROP TABLE IF EXISTS course_bans;
+DROP TABLE IF EXISTS course_reviews;
+DROP TABLE IF EXISTS discussion_comments;
+DROP TABLE IF EXISTS discussion_threads;
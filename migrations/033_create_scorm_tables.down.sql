@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS scorm_attempts;
+DROP TABLE IF EXISTS scorm_items;
+DROP TABLE IF EXISTS scorm_packages;
@@ -0,0 +1,37 @@
+CREATE TABLE IF NOT EXISTS scorm_packages (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    course_id INT NOT NULL,
+    identifier VARCHAR(255) NOT NULL,
+    version VARCHAR(10) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    asset_key VARCHAR(255) NOT NULL,
+    created_by INT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_scorm_packages_course (course_id),
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS scorm_items (
+    task_id INT PRIMARY KEY,
+    package_id INT NOT NULL,
+    identifier VARCHAR(255) NOT NULL,
+    scorm_type VARCHAR(10) NOT NULL,
+    launch VARCHAR(2048) NOT NULL,
+    INDEX idx_scorm_items_package (package_id),
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE,
+    FOREIGN KEY (package_id) REFERENCES scorm_packages(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS scorm_attempts (
+    user_id INT NOT NULL,
+    task_id INT NOT NULL,
+    cmi_data MEDIUMTEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT '',
+    score DOUBLE NULL,
+    completed_at DATETIME NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, task_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES scorm_items(task_id) ON DELETE CASCADE
+);
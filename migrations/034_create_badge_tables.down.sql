@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS badge_assertions;
+DROP TABLE IF EXISTS badge_classes;
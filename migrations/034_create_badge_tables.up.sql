@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS badge_classes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    course_id INT NULL,
+    name VARCHAR(200) NOT NULL,
+    description TEXT NOT NULL,
+    criteria TEXT NOT NULL,
+    image MEDIUMBLOB NULL,
+    created_by INT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_badge_classes_course (course_id),
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS badge_assertions (
+    id CHAR(36) PRIMARY KEY,
+    badge_class_id INT NOT NULL,
+    user_id INT NOT NULL,
+    issued_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    issued_by INT NULL,
+    revoked_at DATETIME NULL,
+    revocation_reason VARCHAR(255) NOT NULL DEFAULT '',
+    credential TEXT NOT NULL,
+    UNIQUE KEY uniq_badge_assertions_recipient (badge_class_id, user_id),
+    INDEX idx_badge_assertions_user (user_id),
+    FOREIGN KEY (badge_class_id) REFERENCES badge_classes(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (issued_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,17 @@
+CREATE TABLE IF NOT EXISTS certificates (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    code VARCHAR(14) NOT NULL,
+    user_id INT NOT NULL,
+    course_id INT NULL,
+    issued_to VARCHAR(255) NOT NULL,
+    course_title VARCHAR(255) NOT NULL,
+    issued_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME NULL,
+    revoked_by INT NULL,
+    revocation_reason VARCHAR(255) NOT NULL DEFAULT '',
+    UNIQUE KEY uniq_certificates_code (code),
+    UNIQUE KEY uniq_certificates_user_course (user_id, course_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE SET NULL,
+    FOREIGN KEY (revoked_by) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS telegram_deadline_reminders;
+DROP TABLE IF EXISTS telegram_link_codes;
+DROP TABLE IF EXISTS telegram_accounts;
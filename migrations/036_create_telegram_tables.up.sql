@@ -0,0 +1,27 @@
+CREATE TABLE IF NOT EXISTS telegram_accounts (
+    user_id INT PRIMARY KEY,
+    chat_id BIGINT NOT NULL,
+    telegram_username VARCHAR(64) NOT NULL DEFAULT '',
+    linked_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uniq_telegram_accounts_chat (chat_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+CREATE TABLE IF NOT EXISTS telegram_link_codes (
+    user_id INT PRIMARY KEY,
+    code_hash CHAR(64) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    UNIQUE KEY uniq_telegram_link_codes_hash (code_hash),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Отправленные напоминания о сроках; при переносе срока напоминание отправляется снова
+CREATE TABLE IF NOT EXISTS telegram_deadline_reminders (
+    user_id INT NOT NULL,
+    task_id INT NOT NULL,
+    due_at DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, task_id, due_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS chat_deadline_reminders;
+DROP TABLE IF EXISTS chat_channels;
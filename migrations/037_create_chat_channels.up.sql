@@ -0,0 +1,33 @@
+CREATE TABLE IF NOT EXISTS chat_channels (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    group_name VARCHAR(100) NOT NULL,
+    provider VARCHAR(20) NOT NULL,
+    webhook_url VARCHAR(500) NOT NULL DEFAULT '',
+    bot_token VARCHAR(255) NOT NULL DEFAULT '',
+    server_url VARCHAR(255) NOT NULL DEFAULT '',
+    channel VARCHAR(100) NOT NULL DEFAULT '',
+    course_id INT NULL,
+    locale VARCHAR(10) NOT NULL DEFAULT '',
+    notify_course_published BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_leaderboard BOOLEAN NOT NULL DEFAULT FALSE,
+    notify_deadlines BOOLEAN NOT NULL DEFAULT FALSE,
+    last_leaderboard_at DATETIME NULL,
+    created_by INT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_chat_channels_group (group_name),
+    FOREIGN KEY (group_name) REFERENCES user_groups(name) ON DELETE CASCADE ON UPDATE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Отправленные в канал напоминания о сроках; при переносе срока напоминание отправляется снова
+CREATE TABLE IF NOT EXISTS chat_deadline_reminders (
+    channel_id INT NOT NULL,
+    task_id INT NOT NULL,
+    due_at DATETIME NOT NULL,
+    sent_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (channel_id, task_id, due_at),
+    FOREIGN KEY (channel_id) REFERENCES chat_channels(id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS push_deadline_reminders;
+DROP TABLE IF EXISTS push_subscriptions;
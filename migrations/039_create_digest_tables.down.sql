@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS digest_unsubscribe_tokens;
+DROP TABLE IF EXISTS digest_settings;
+ALTER TABLE courses DROP COLUMN created_at;
@@ -0,0 +1,19 @@
+-- Время создания курса для раздела «новые курсы» еженедельной сводки; у существующих курсов неизвестно
+ALTER TABLE courses ADD COLUMN created_at DATETIME NULL;
+
+-- Подписка на еженедельную сводку; нет строки — пользователь подписан и сводок еще не получал
+CREATE TABLE IF NOT EXISTS digest_settings (
+    user_id INT PRIMARY KEY,
+    opted_out BOOLEAN NOT NULL DEFAULT FALSE,
+    last_digest_at DATETIME NULL,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Хэши токенов ссылок отписки; у каждого письма своя ссылка
+CREATE TABLE IF NOT EXISTS digest_unsubscribe_tokens (
+    token_hash CHAR(64) PRIMARY KEY,
+    user_id INT NOT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_digest_unsubscribe_tokens_user (user_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
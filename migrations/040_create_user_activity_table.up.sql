@@ -0,0 +1,8 @@
+-- Дни (UTC), в которые пользователь обращался к API; основа DAU/WAU/MAU в статистике
+CREATE TABLE IF NOT EXISTS user_activity (
+    user_id INT NOT NULL,
+    activity_date DATE NOT NULL,
+    PRIMARY KEY (user_id, activity_date),
+    KEY idx_user_activity_date (activity_date),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
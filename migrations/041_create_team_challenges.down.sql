@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS team_challenge_groups;
+DROP TABLE IF EXISTS team_challenges;
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS team_challenges (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    title VARCHAR(255) NOT NULL,
+    description TEXT NOT NULL,
+    scoring VARCHAR(16) NOT NULL,
+    best_of INT NOT NULL DEFAULT 0,
+    starts_at DATETIME NOT NULL,
+    ends_at DATETIME NOT NULL,
+    created_by INT NULL,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_team_challenges_starts (starts_at),
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Группы (команды), участвующие в соревновании
+CREATE TABLE IF NOT EXISTS team_challenge_groups (
+    challenge_id INT NOT NULL,
+    group_name VARCHAR(100) NOT NULL,
+    PRIMARY KEY (challenge_id, group_name),
+    INDEX idx_team_challenge_groups_group (group_name),
+    FOREIGN KEY (challenge_id) REFERENCES team_challenges(id) ON DELETE CASCADE,
+    FOREIGN KEY (group_name) REFERENCES user_groups(name) ON DELETE CASCADE ON UPDATE CASCADE
+);
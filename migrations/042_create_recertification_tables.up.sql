@@ -0,0 +1,24 @@
+-- Курсы, которые нужно проходить заново через period_months месяцев после завершения
+CREATE TABLE IF NOT EXISTS course_recertification (
+    course_id INT PRIMARY KEY,
+    period_months INT NOT NULL,
+    reminder_days INT NOT NULL DEFAULT 30,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
+
+-- Текущий цикл сертификации пользователя по курсу: повторное завершение курса начинает новый цикл
+CREATE TABLE IF NOT EXISTS course_completions (
+    user_id INT NOT NULL,
+    course_id INT NOT NULL,
+    completed_at DATETIME NOT NULL,
+    remind_at DATETIME NOT NULL,
+    expires_at DATETIME NOT NULL,
+    reminded_at DATETIME NULL,
+    expired_at DATETIME NULL,
+    PRIMARY KEY (user_id, course_id),
+    INDEX idx_course_completions_remind (remind_at),
+    INDEX idx_course_completions_expires (expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS course_attempt_tasks;
+DROP TABLE IF EXISTS course_attempts;
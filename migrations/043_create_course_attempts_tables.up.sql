@@ -0,0 +1,28 @@
+-- Архив попыток прохождения курса: прогресс переносится сюда при сбросе, а не удаляется
+CREATE TABLE IF NOT EXISTS course_attempts (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    user_id INT NOT NULL,
+    course_id INT NOT NULL,
+    tasks_completed INT NOT NULL,
+    tasks_total INT NOT NULL,
+    reset_by INT NULL,
+    reason VARCHAR(500) NOT NULL DEFAULT '',
+    reset_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    INDEX idx_course_attempts_user_course (user_id, course_id, reset_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+    FOREIGN KEY (reset_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+-- Выполненные задания и состояние SCORM на момент сброса
+CREATE TABLE IF NOT EXISTS course_attempt_tasks (
+    attempt_id INT NOT NULL,
+    task_id INT NOT NULL,
+    completed_at DATETIME NULL,
+    scorm_status VARCHAR(20) NULL,
+    scorm_score DOUBLE NULL,
+    scorm_data MEDIUMTEXT NULL,
+    PRIMARY KEY (attempt_id, task_id),
+    FOREIGN KEY (attempt_id) REFERENCES course_attempts(id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);
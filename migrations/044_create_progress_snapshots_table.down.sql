@@ -0,0 +1 @@
+DROP TABLE IF EXISTS progress_snapshots;
@@ -0,0 +1,12 @@
+-- Ежедневные снимки прогресса пользователей по курсам для графиков прогресса во времени
+CREATE TABLE IF NOT EXISTS progress_snapshots (
+    user_id INT NOT NULL,
+    course_id INT NOT NULL,
+    snapshot_date DATE NOT NULL,
+    tasks_completed INT NOT NULL,
+    tasks_total INT NOT NULL,
+    PRIMARY KEY (user_id, course_id, snapshot_date),
+    KEY idx_progress_snapshots_date (snapshot_date),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
@@ -0,0 +1,9 @@
+-- Руководитель пользователя: видит прогресс и сроки своих подчиненных без прав администратора
+CREATE TABLE IF NOT EXISTS user_managers (
+    user_id INT PRIMARY KEY,
+    manager_id INT NOT NULL,
+    assigned_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_user_managers_manager (manager_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (manager_id) REFERENCES users(id) ON DELETE CASCADE
+);
@@ -0,0 +1,10 @@
+DROP TABLE IF EXISTS organization_admins;
+
+ALTER TABLE user_groups DROP FOREIGN KEY fk_user_groups_organization;
+ALTER TABLE user_groups DROP COLUMN organization_id;
+ALTER TABLE courses DROP FOREIGN KEY fk_courses_organization;
+ALTER TABLE courses DROP COLUMN organization_id;
+ALTER TABLE users DROP FOREIGN KEY fk_users_organization;
+ALTER TABLE users DROP COLUMN organization_id;
+
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,32 @@
+-- Организации (арендаторы): пользователи, курсы и группы принадлежат одной организации.
+-- Существующие данные попадают в организацию по умолчанию.
+CREATE TABLE IF NOT EXISTS organizations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    slug VARCHAR(64) NOT NULL UNIQUE,
+    name VARCHAR(200) NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+INSERT INTO organizations (id, slug, name) VALUES (1, 'default', 'Default organization');
+
+ALTER TABLE users ADD COLUMN organization_id INT NOT NULL DEFAULT 1;
+ALTER TABLE users ADD INDEX idx_users_organization (organization_id);
+ALTER TABLE users ADD CONSTRAINT fk_users_organization FOREIGN KEY (organization_id) REFERENCES organizations(id);
+
+ALTER TABLE courses ADD COLUMN organization_id INT NOT NULL DEFAULT 1;
+ALTER TABLE courses ADD INDEX idx_courses_organization (organization_id);
+ALTER TABLE courses ADD CONSTRAINT fk_courses_organization FOREIGN KEY (organization_id) REFERENCES organizations(id);
+
+ALTER TABLE user_groups ADD COLUMN organization_id INT NOT NULL DEFAULT 1;
+ALTER TABLE user_groups ADD INDEX idx_user_groups_organization (organization_id);
+ALTER TABLE user_groups ADD CONSTRAINT fk_user_groups_organization FOREIGN KEY (organization_id) REFERENCES organizations(id);
+
+-- Администраторы организации управляют ее пользователями, но не получают прав администратора платформы
+CREATE TABLE IF NOT EXISTS organization_admins (
+    organization_id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (organization_id, user_id),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
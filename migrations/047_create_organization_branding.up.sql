@@ -0,0 +1,12 @@
+-- Оформление организации: логотип и цвета писем и сертификатов, подпись писем и шаблон сертификата.
+-- Организации без записи используют оформление по умолчанию.
+CREATE TABLE IF NOT EXISTS organization_branding (
+    organization_id INT PRIMARY KEY,
+    logo MEDIUMBLOB NULL,
+    primary_color VARCHAR(7) NOT NULL DEFAULT '',
+    accent_color VARCHAR(7) NOT NULL DEFAULT '',
+    email_footer TEXT NULL,
+    certificate_template MEDIUMTEXT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE
+);
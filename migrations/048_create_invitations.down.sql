@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS course_assignments;
+DROP TABLE IF EXISTS invitation_courses;
+DROP TABLE IF EXISTS invitations;
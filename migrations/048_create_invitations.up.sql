@@ -0,0 +1,38 @@
+-- Приглашения: ссылка регистрирует пользователя в организации и может сразу добавить его
+-- в группу и назначить курсы. Хранится только хеш токена из ссылки.
+CREATE TABLE IF NOT EXISTS invitations (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    token_hash CHAR(64) NOT NULL,
+    organization_id INT NOT NULL,
+    group_name VARCHAR(100) NOT NULL DEFAULT '',
+    max_uses INT NOT NULL DEFAULT 0,
+    uses INT NOT NULL DEFAULT 0,
+    expires_at DATETIME NOT NULL,
+    created_by INT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    revoked_at DATETIME,
+    UNIQUE KEY uq_invitations_token (token_hash),
+    INDEX idx_invitations_organization (organization_id, created_at),
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (created_by) REFERENCES users(id) ON DELETE SET NULL
+);
+
+CREATE TABLE IF NOT EXISTS invitation_courses (
+    invitation_id INT NOT NULL,
+    course_id INT NOT NULL,
+    PRIMARY KEY (invitation_id, course_id),
+    FOREIGN KEY (invitation_id) REFERENCES invitations(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
+
+-- Курсы, назначенные пользователю (сейчас — при регистрации по приглашению)
+CREATE TABLE IF NOT EXISTS course_assignments (
+    user_id INT NOT NULL,
+    course_id INT NOT NULL,
+    invitation_id INT,
+    assigned_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, course_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+    FOREIGN KEY (invitation_id) REFERENCES invitations(id) ON DELETE SET NULL
+);
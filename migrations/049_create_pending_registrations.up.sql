@@ -0,0 +1,9 @@
+-- Самостоятельные регистрации, ожидающие одобрения администратора. Учетная запись создается
+-- неактивной; одобрение активирует ее, отклонение удаляет.
+CREATE TABLE IF NOT EXISTS pending_registrations (
+    user_id INT PRIMARY KEY,
+    requested_ip VARCHAR(64) NOT NULL DEFAULT '',
+    requested_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_pending_registrations_requested (requested_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
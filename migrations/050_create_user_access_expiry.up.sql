@@ -0,0 +1,11 @@
+-- Срок доступа учетных записей (подрядчики, студенты на семестр). Фоновая задача деактивирует
+-- пользователя, когда expires_at наступает, и отмечает это в deactivated_at; продление срока
+-- снова активирует учетную запись, отключенную по сроку.
+CREATE TABLE IF NOT EXISTS user_access_expiry (
+    user_id INT PRIMARY KEY,
+    expires_at DATETIME NOT NULL,
+    deactivated_at DATETIME NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_user_access_expiry_expires (expires_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+);
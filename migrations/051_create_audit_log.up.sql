@@ -0,0 +1,20 @@
+-- Журнал действий администраторов и преподавателей: кто, каким запросом и над чем что-то изменил.
+-- changes — JSON со списком измененных полей (было/стало) для правок пользователей и курсов;
+-- actor_username сохраняет имя на момент действия, даже если пользователь потом удален.
+CREATE TABLE IF NOT EXISTS audit_log (
+    id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    actor_id INT NULL,
+    actor_username VARCHAR(50) NOT NULL DEFAULT '',
+    action VARCHAR(191) NOT NULL,
+    target_type VARCHAR(32) NOT NULL DEFAULT '',
+    target_id INT NULL,
+    changes TEXT NULL,
+    status INT NOT NULL,
+    ip VARCHAR(64) NOT NULL DEFAULT '',
+    request_id VARCHAR(64) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    KEY idx_audit_log_created (created_at),
+    KEY idx_audit_log_actor (actor_id, id),
+    KEY idx_audit_log_target (target_type, target_id, id),
+    FOREIGN KEY (actor_id) REFERENCES users(id) ON DELETE SET NULL
+);
@@ -0,0 +1,10 @@
+-- Разрешенные адреса организации: пользователи организации могут обращаться к API только с них.
+-- Организации без записи ограничений не имеют. cidrs — подсети в нотации CIDR через запятую.
+CREATE TABLE IF NOT EXISTS organization_ip_allowlist (
+    organization_id INT PRIMARY KEY,
+    cidrs TEXT NOT NULL,
+    updated_by INT NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (organization_id) REFERENCES organizations(id) ON DELETE CASCADE,
+    FOREIGN KEY (updated_by) REFERENCES users(id) ON DELETE SET NULL
+);
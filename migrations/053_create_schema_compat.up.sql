@@ -0,0 +1,13 @@
+-- Совместимость схемы с кодом для выкладки без простоя (expand/contract).
+-- min_app_version — наименьшая версия схемы, под которую может быть собран код, работающий с этой базой.
+-- Расширяющие миграции (новые таблицы и столбцы) ее не меняют. Сужающая миграция, которая удаляет или
+-- переименовывает используемые кодом объекты, поднимает ее до версии схемы первого кода, который эти
+-- объекты уже не использует:
+--   UPDATE schema_compat SET min_app_version = <версия>;
+CREATE TABLE IF NOT EXISTS schema_compat (
+    id INT PRIMARY KEY,
+    min_app_version BIGINT NOT NULL
+);
+
+INSERT INTO schema_compat (id, min_app_version)
+SELECT 1, 0 FROM DUAL WHERE NOT EXISTS (SELECT 1 FROM schema_compat);
@@ -0,0 +1,10 @@
+-- Аренда периодических задач: при нескольких репликах задачу выполняет только держатель
+-- неистекшей аренды, поэтому она запускается один раз за интервал, а не на каждой реплике.
+-- version увеличивается при каждом получении аренды.
+CREATE TABLE IF NOT EXISTS job_leases (
+    name VARCHAR(64) PRIMARY KEY,
+    holder VARCHAR(255) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    acquired_at DATETIME NOT NULL,
+    version BIGINT NOT NULL DEFAULT 1
+);
@@ -0,0 +1,25 @@
+-- Денормализованные показатели курсов для каталога: списки курсов читают одну строку по
+-- первичному ключу вместо агрегатов по заданиям, прогрессу и отзывам. Строка пересчитывается
+-- в транзакциях, меняющих задания, прогресс и отзывы курса, и периодически сверяется целиком.
+-- avg_difficulty — средняя сложность заданий: easy = 1, medium = 2, hard = 3.
+-- enrollments — пользователи, выполнившие хотя бы одно задание курса.
+CREATE TABLE IF NOT EXISTS course_summary (
+    course_id INT PRIMARY KEY,
+    tasks_count INT NOT NULL DEFAULT 0,
+    avg_difficulty DOUBLE NULL,
+    enrollments INT NOT NULL DEFAULT 0,
+    reviews INT NOT NULL DEFAULT 0,
+    avg_rating DOUBLE NULL,
+    updated_at DATETIME NOT NULL,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
+
+INSERT INTO course_summary (course_id, tasks_count, avg_difficulty, enrollments, reviews, avg_rating, updated_at)
+SELECT c.id,
+       (SELECT COUNT(*) FROM tasks t WHERE t.course_id = c.id),
+       (SELECT AVG(CASE t.difficulty WHEN 'easy' THEN 1 WHEN 'medium' THEN 2 ELSE 3 END) FROM tasks t WHERE t.course_id = c.id),
+       (SELECT COUNT(DISTINCT p.user_id) FROM user_progress p JOIN tasks t ON t.id = p.task_id WHERE t.course_id = c.id),
+       (SELECT COUNT(*) FROM course_reviews r WHERE r.course_id = c.id AND r.hidden_at IS NULL),
+       (SELECT AVG(r.rating) FROM course_reviews r WHERE r.course_id = c.id AND r.hidden_at IS NULL),
+       CURRENT_TIMESTAMP
+FROM courses c;
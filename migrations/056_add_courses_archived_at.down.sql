@@ -0,0 +1,2 @@
+DROP INDEX idx_courses_archived_at ON courses;
+ALTER TABLE courses DROP COLUMN archived_at;
@@ -0,0 +1,2 @@
+ALTER TABLE courses ADD COLUMN archived_at DATETIME NULL;
+CREATE INDEX idx_courses_archived_at ON courses (archived_at);
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS course_visits;
+DROP TABLE IF EXISTS course_changes;
+ALTER TABLE courses DROP COLUMN content_version;
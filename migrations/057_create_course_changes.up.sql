@@ -0,0 +1,30 @@
+-- История содержимого курса: каждое изменение (добавлено или изменено задание, обновлены
+-- вопросы опроса) получает следующий номер версии курса. courses.content_version — номер
+-- последней записи; его увеличение в транзакции изменения сериализует параллельные правки.
+-- title сохраняет название задания или опроса на момент изменения.
+ALTER TABLE courses ADD COLUMN content_version INT NOT NULL DEFAULT 0;
+
+CREATE TABLE IF NOT EXISTS course_changes (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    course_id INT NOT NULL,
+    version INT NOT NULL,
+    kind VARCHAR(32) NOT NULL,
+    task_id INT NULL,
+    title VARCHAR(255) NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE KEY uq_course_changes_version (course_id, version),
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE SET NULL
+);
+
+-- Последняя просмотренная пользователем версия курса: лента «что изменилось» показывает
+-- записи истории новее seen_version
+CREATE TABLE IF NOT EXISTS course_visits (
+    user_id INT NOT NULL,
+    course_id INT NOT NULL,
+    seen_version INT NOT NULL DEFAULT 0,
+    visited_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, course_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
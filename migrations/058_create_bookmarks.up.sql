@@ -0,0 +1,14 @@
+-- Закладки пользователей на курсы и задания. task_id = 0 — закладка на курс целиком; задания
+-- удаляются только вместе с курсом, поэтому внешнего ключа на tasks нет. notify — сообщить
+-- пользователю, когда курс из закладки вернется в каталог.
+CREATE TABLE IF NOT EXISTS bookmarks (
+    user_id INT NOT NULL,
+    course_id INT NOT NULL,
+    task_id INT NOT NULL DEFAULT 0,
+    notify BOOLEAN NOT NULL DEFAULT FALSE,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, course_id, task_id),
+    KEY idx_bookmarks_notify (course_id, notify),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (course_id) REFERENCES courses(id) ON DELETE CASCADE
+);
@@ -0,0 +1,15 @@
+-- Личные заметки пользователей к заданиям (Markdown). Видны только автору, выгружаются вместе
+-- с его данными и удаляются при обезличивании учетной записи. version растет с каждым
+-- сохранением: автосохранение из нескольких вкладок не затирает более новый текст.
+CREATE TABLE IF NOT EXISTS task_notes (
+    user_id INT NOT NULL,
+    task_id INT NOT NULL,
+    body MEDIUMTEXT NOT NULL,
+    version INT NOT NULL DEFAULT 1,
+    created_at DATETIME NOT NULL,
+    updated_at DATETIME NOT NULL,
+    PRIMARY KEY (user_id, task_id),
+    KEY idx_task_notes_updated (user_id, updated_at),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (task_id) REFERENCES tasks(id) ON DELETE CASCADE
+);